@@ -16,13 +16,22 @@ func adminCommands() []*cobra.Command {
 		adminDatabase(),
 		adminServices(),
 		adminHooks(),
+		adminElasticsearch(),
+		adminCDN(),
 		adminIntegrationModels(),
 		adminMaintenance(),
 		adminMigrations(),
+		adminSecrets(),
+		adminQueue(),
+		adminLDAP(),
 		adminPlugins(),
 		adminBroadcasts(),
 		adminErrors(),
+		adminEvents(),
 		adminCurl(),
+		adminCost(),
+		adminWorkerModel(),
+		adminWorkerPool(),
 	}
 }
 
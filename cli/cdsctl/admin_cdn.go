@@ -0,0 +1,120 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ovh/cds/cli"
+	"github.com/ovh/cds/sdk"
+)
+
+var adminCDNCmd = cli.Command{
+	Name:  "cdn",
+	Short: "Manage CDS CDN items and storage units",
+}
+
+func adminCDN() *cobra.Command {
+	return cli.NewCommand(adminCDNCmd, nil, []*cobra.Command{
+		cli.NewListCommand(adminCDNUnitListCmd, adminCDNUnitListRun, nil),
+		cli.NewListCommand(adminCDNItemListCmd, adminCDNItemListRun, nil),
+		cli.NewListCommand(adminCDNItemShowCmd, adminCDNItemShowRun, nil),
+		cli.NewListCommand(adminCDNItemCheckCmd, adminCDNItemCheckRun, nil),
+	})
+}
+
+var adminCDNUnitListCmd = cli.Command{
+	Name:  "units",
+	Short: "List CDS CDN storage units with the number of items by status",
+}
+
+func adminCDNUnitListRun(v cli.Values) (cli.ListResult, error) {
+	btes, err := client.ServiceCallGET("cdn", "/unit")
+	if err != nil {
+		return nil, err
+	}
+	units := []sdk.CDNUnit{}
+	if err := json.Unmarshal(btes, &units); err != nil {
+		return nil, err
+	}
+	return cli.AsListResult(units), nil
+}
+
+var adminCDNItemListCmd = cli.Command{
+	Name:  "items",
+	Short: "List CDS CDN items",
+	Flags: []cli.Flag{
+		{
+			Name:  "type",
+			Usage: "Filter items by type: log, artifact, tmpfile",
+		},
+		{
+			Name:  "status",
+			Usage: "Filter items having this status in a storage unit: synced, missing, corrupted",
+		},
+		{
+			Name:  "unit",
+			Usage: "Storage unit of the status filter",
+		},
+	},
+}
+
+func adminCDNItemListRun(v cli.Values) (cli.ListResult, error) {
+	u, _ := url.Parse("/item")
+	q := u.Query()
+	for _, f := range []string{"type", "status", "unit"} {
+		if s := v.GetString(f); s != "" {
+			q.Add(f, s)
+		}
+	}
+	u.RawQuery = q.Encode()
+
+	btes, err := client.ServiceCallGET("cdn", u.String())
+	if err != nil {
+		return nil, err
+	}
+	items := []sdk.CDNItem{}
+	if err := json.Unmarshal(btes, &items); err != nil {
+		return nil, err
+	}
+	return cli.AsListResult(items), nil
+}
+
+var adminCDNItemShowCmd = cli.Command{
+	Name:    "show",
+	Short:   "Show the state of a CDS CDN item in the storage units",
+	Example: "cdsctl admin cdn show 2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae",
+	Args: []cli.Arg{
+		{Name: "hash"},
+	},
+}
+
+func adminCDNItemShowRun(v cli.Values) (cli.ListResult, error) {
+	return adminCDNItemUnits(client.ServiceCallGET("cdn", fmt.Sprintf("/item/%s", v.GetString("hash"))))
+}
+
+var adminCDNItemCheckCmd = cli.Command{
+	Name:    "check",
+	Short:   "Verify the integrity of a CDS CDN item now and replicate it in the storage units where it is not synced",
+	Example: "cdsctl admin cdn check 2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae",
+	Args: []cli.Arg{
+		{Name: "hash"},
+	},
+}
+
+func adminCDNItemCheckRun(v cli.Values) (cli.ListResult, error) {
+	return adminCDNItemUnits(client.ServiceCallPOST("cdn", fmt.Sprintf("/item/%s/check", v.GetString("hash")), nil))
+}
+
+func adminCDNItemUnits(btes []byte, err error) (cli.ListResult, error) {
+	if err != nil {
+		return nil, err
+	}
+	var item sdk.CDNItem
+	if err := json.Unmarshal(btes, &item); err != nil {
+		return nil, err
+	}
+	return cli.AsListResult(item.Units), nil
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ovh/cds/cli"
+)
+
+var adminCostCmd = cli.Command{
+	Name:  "cost",
+	Short: "Show the compute time and the artifact size charged to the projects, applications and groups",
+}
+
+func adminCost() *cobra.Command {
+	return cli.NewCommand(adminCostCmd, nil, []*cobra.Command{
+		cli.NewListCommand(adminCostShowCmd, adminCostShowRun, nil),
+		cli.NewCommand(adminCostExportCmd, adminCostExportRun, nil),
+	})
+}
+
+var adminCostShowCmd = cli.Command{
+	Name:  "show",
+	Short: "Show the costs of all the projects during a period, by group by default",
+	Flags: costFlags,
+}
+
+func adminCostShowRun(v cli.Values) (cli.ListResult, error) {
+	r, err := client.AdminCost(v.GetString("by"), v.GetString("from"), v.GetString("to"))
+	if err != nil {
+		return nil, err
+	}
+	return cli.AsListResult(r.Lines), nil
+}
+
+var adminCostExportCmd = cli.Command{
+	Name:  "export",
+	Short: "Export the costs of all the projects during a period as CSV",
+	Flags: costFlags,
+}
+
+func adminCostExportRun(v cli.Values) error {
+	btes, err := client.AdminCostCSV(v.GetString("by"), v.GetString("from"), v.GetString("to"))
+	if err != nil {
+		return err
+	}
+	fmt.Print(string(btes))
+	return nil
+}
@@ -1,6 +1,8 @@
 package main
 
 import (
+	"fmt"
+
 	"github.com/spf13/cobra"
 
 	"github.com/ovh/cds/cli"
@@ -16,6 +18,7 @@ func adminDatabase() *cobra.Command {
 		cli.NewCommand(adminDatabaseUnlockCmd, adminDatabaseUnlockFunc, nil),
 		cli.NewCommand(adminDatabaseDeleteMigrationCmd, adminDatabaseDeleteFunc, nil),
 		cli.NewListCommand(adminDatabaseMigrationsList, adminDatabaseMigrationsListFunc, nil),
+		cli.NewGetCommand(adminDatabaseMigrateCmd, adminDatabaseMigrateFunc, nil),
 	})
 }
 
@@ -55,3 +58,43 @@ func adminDatabaseMigrationsListFunc(_ cli.Values) (cli.ListResult, error) {
 	}
 	return cli.AsListResult(migrations), nil
 }
+
+var adminDatabaseMigrateCmd = cli.Command{
+	Name:  "migrate",
+	Short: "Show the compatibility of the database schema with the API, or release the lock of the migration scripts",
+	Long: `The API refuses to start if a migration script it needs is not applied, or if a script incompatible with it
+was applied. The other migration scripts can be applied with 'engine database upgrade' before the upgrade of the API.
+
+A migration interrupted while running keeps the migration scripts locked: check that no migration is running
+before releasing the lock with --unlock.`,
+	Flags: []cli.Flag{
+		{
+			Name:  "status",
+			Usage: "Show the status of the database schema (default)",
+			Type:  cli.FlagBool,
+		},
+		{
+			Name:  "unlock",
+			Usage: "Release the lock of the migration scripts (use with caution)",
+			Type:  cli.FlagBool,
+		},
+	},
+}
+
+func adminDatabaseMigrateFunc(v cli.Values) (interface{}, error) {
+	s, err := client.AdminDatabaseMigrationStatus()
+	if err != nil {
+		return nil, err
+	}
+	if !v.GetBool("unlock") {
+		return s, nil
+	}
+
+	if !s.Locked {
+		return nil, fmt.Errorf("migration scripts are not locked")
+	}
+	if err := client.AdminDatabaseMigrationUnlock(s.LockedBy); err != nil {
+		return nil, err
+	}
+	return client.AdminDatabaseMigrationStatus()
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ovh/cds/cli"
+)
+
+var adminElasticsearchCmd = cli.Command{
+	Name:  "elasticsearch",
+	Short: "Manage CDS Elasticsearch indices",
+}
+
+func adminElasticsearch() *cobra.Command {
+	return cli.NewCommand(adminElasticsearchCmd, nil, []*cobra.Command{
+		cli.NewListCommand(adminElasticsearchIndicesCmd, adminElasticsearchIndicesRun, nil),
+		cli.NewCommand(adminElasticsearchRolloverCmd, adminElasticsearchRolloverRun, nil),
+		cli.NewCommand(adminElasticsearchRetentionCmd, adminElasticsearchRetentionRun, nil),
+		cli.NewCommand(adminElasticsearchReindexCmd, adminElasticsearchReindexRun, nil),
+	})
+}
+
+var adminElasticsearchIndicesCmd = cli.Command{
+	Name:  "indices",
+	Short: "List the events and metrics indices",
+}
+
+func adminElasticsearchIndicesRun(v cli.Values) (cli.ListResult, error) {
+	btes, err := client.ServiceCallGET("elasticsearch", "/indices")
+	if err != nil {
+		return nil, err
+	}
+	type IndexDisplay struct {
+		Name    string    `json:"name" cli:"name,key"`
+		Alias   string    `json:"alias" cli:"alias"`
+		Created time.Time `json:"created" cli:"created"`
+		Write   bool      `json:"write" cli:"write"`
+	}
+	indices := []IndexDisplay{}
+	if err := json.Unmarshal(btes, &indices); err != nil {
+		return nil, err
+	}
+	return cli.AsListResult(indices), nil
+}
+
+var adminElasticsearchRolloverCmd = cli.Command{
+	Name:  "rollover",
+	Short: "Create new events and metrics indices now, whatever the rollover conditions",
+}
+
+func adminElasticsearchRolloverRun(v cli.Values) error {
+	btes, err := client.ServiceCallPOST("elasticsearch", "/indices/rollover", nil)
+	if err != nil {
+		return err
+	}
+	var res []struct {
+		OldIndex string `json:"old_index"`
+		NewIndex string `json:"new_index"`
+	}
+	if err := json.Unmarshal(btes, &res); err != nil {
+		return err
+	}
+	for _, r := range res {
+		fmt.Printf("Rolled over from %s to %s\n", r.OldIndex, r.NewIndex)
+	}
+	return nil
+}
+
+var adminElasticsearchRetentionCmd = cli.Command{
+	Name:  "retention",
+	Short: "Delete the events and metrics indices older than the retention now",
+}
+
+func adminElasticsearchRetentionRun(v cli.Values) error {
+	btes, err := client.ServiceCallPOST("elasticsearch", "/indices/retention", nil)
+	if err != nil {
+		return err
+	}
+	var deleted []string
+	if err := json.Unmarshal(btes, &deleted); err != nil {
+		return err
+	}
+	for _, i := range deleted {
+		fmt.Printf("Index %s deleted\n", i)
+	}
+	return nil
+}
+
+var adminElasticsearchReindexCmd = cli.Command{
+	Name:    "reindex",
+	Short:   "Copy all the documents of an index to another one",
+	Example: "cdsctl admin elasticsearch reindex cds-events-old cds-events",
+	Args: []cli.Arg{
+		{Name: "source"},
+		{Name: "destination"},
+	},
+}
+
+func adminElasticsearchReindexRun(v cli.Values) error {
+	body, err := json.Marshal(map[string]string{
+		"source":      v.GetString("source"),
+		"destination": v.GetString("destination"),
+	})
+	if err != nil {
+		return err
+	}
+	btes, err := client.ServiceCallPOST("elasticsearch", "/indices/reindex", body)
+	if err != nil {
+		return err
+	}
+	var res struct {
+		Created int64 `json:"created"`
+	}
+	if err := json.Unmarshal(btes, &res); err != nil {
+		return err
+	}
+	fmt.Printf("%d documents copied to %s\n", res.Created, v.GetString("destination"))
+	return nil
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ovh/cds/cli"
+	"github.com/ovh/cds/sdk"
+)
+
+var adminEventsCmd = cli.Command{
+	Name:  "events",
+	Short: "Manage CDS events outbox",
+	Long: `The events of the workflow runs are saved in an outbox with the runs, and sent to the event brokers
+until they acknowledge them. The events still not delivered after the max number of attempts are dead.`,
+}
+
+func adminEvents() *cobra.Command {
+	return cli.NewCommand(adminEventsCmd, nil, []*cobra.Command{
+		cli.NewListCommand(adminEventsList, adminEventsListFunc, nil),
+		cli.NewCommand(adminEventsRetry, adminEventsRetryFunc, nil),
+		cli.NewCommand(adminEventsDelete, adminEventsDeleteFunc, nil),
+	})
+}
+
+var adminEventsList = cli.Command{
+	Name:  "list",
+	Short: "List the events of the outbox",
+	Flags: []cli.Flag{
+		{
+			Name:    "status",
+			Usage:   "pending, delivered or dead",
+			Default: sdk.EventOutboxStatusDead,
+		},
+	},
+}
+
+func adminEventsListFunc(v cli.Values) (cli.ListResult, error) {
+	entries, err := client.AdminEventOutbox(v.GetString("status"))
+	if err != nil {
+		return nil, err
+	}
+	return cli.AsListResult(entries), nil
+}
+
+var adminEventsRetry = cli.Command{
+	Name:  "retry",
+	Short: "Send again a dead event to the brokers",
+	Args: []cli.Arg{
+		{Name: "id"},
+	},
+}
+
+func adminEventsRetryFunc(v cli.Values) error {
+	id, err := v.GetInt64("id")
+	if err != nil {
+		return sdk.WrapError(err, "Bad id format")
+	}
+	if err := client.AdminEventOutboxRetry(id); err != nil {
+		return err
+	}
+	fmt.Printf("Event %d will be sent again\n", id)
+	return nil
+}
+
+var adminEventsDelete = cli.Command{
+	Name:  "delete",
+	Short: "Delete a dead event",
+	Args: []cli.Arg{
+		{Name: "id"},
+	},
+}
+
+func adminEventsDeleteFunc(v cli.Values) error {
+	id, err := v.GetInt64("id")
+	if err != nil {
+		return sdk.WrapError(err, "Bad id format")
+	}
+	return client.AdminEventOutboxDelete(id)
+}
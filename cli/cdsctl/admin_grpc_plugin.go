@@ -187,6 +187,15 @@ func adminPluginsAddBinaryFunc(v cli.Values) error {
 		return fmt.Errorf("unable to compute sha512sum for file %s: %v", v.GetString("filename"), err)
 	}
 
+	sha256sum, err := sdk.FileSHA256sum(v.GetString("filename"))
+	if err != nil {
+		return fmt.Errorf("unable to compute sha256sum for file %s: %v", v.GetString("filename"), err)
+	}
+	if desc.SHA256sum != "" && desc.SHA256sum != sha256sum {
+		return fmt.Errorf("sha256sum of file %s does not match the descriptor: expected %s, got %s", v.GetString("filename"), desc.SHA256sum, sha256sum)
+	}
+	desc.SHA256sum = sha256sum
+
 	return client.PluginAddBinary(p, &desc)
 }
 
@@ -26,6 +26,8 @@ func adminHooks() *cobra.Command {
 		cli.NewCommand(adminHooksTaskExecutionDeleteAllCmd, adminHooksTaskExecutionDeleteAllRun, nil),
 		cli.NewCommand(adminHooksTaskExecutionStartAllCmd, adminHooksTaskExecutionStartAllRun, nil),
 		cli.NewCommand(adminHooksTaskExecutionStopAllCmd, adminHooksTaskExecutionStopAllRun, nil),
+		cli.NewListCommand(adminHooksWebHookReportListCmd, adminHooksWebHookReportListRun, nil),
+		cli.NewListCommand(adminHooksWebHookRepairCmd, adminHooksWebHookRepairRun, nil),
 	})
 }
 
@@ -194,3 +196,83 @@ func adminHooksTaskExecutionStartAllRun(v cli.Values) error {
 	_, err := client.ServiceCallGET("hooks", "/task/bulk/start")
 	return err
 }
+
+var adminHooksWebHookReportListCmd = cli.Command{
+	Name:    "webhooks",
+	Short:   "List the last verification of the repository webhooks on the VCS servers",
+	Example: "cdsctl admin hooks webhooks --status broken",
+	Flags: []cli.Flag{
+		{
+			Name:    "status",
+			Usage:   "Filter the webhooks by status: ok, repaired or broken",
+			Default: "",
+		},
+	},
+}
+
+func adminHooksWebHookReportListRun(v cli.Values) (cli.ListResult, error) {
+	url, _ := url.Parse("/admin/webhook/repair")
+	if s := v.GetString("status"); s != "" {
+		q := url.Query()
+		q.Add("status", s)
+		url.RawQuery = q.Encode()
+	}
+
+	btes, err := client.ServiceCallGET("hooks", url.String())
+	if err != nil {
+		return nil, err
+	}
+	reports := []sdk.RepositoryWebHookReport{}
+	if err := json.Unmarshal(btes, &reports); err != nil {
+		return nil, err
+	}
+	return cli.AsListResult(adminHooksWebHookReportDisplay(reports)), nil
+}
+
+var adminHooksWebHookRepairCmd = cli.Command{
+	Name:    "repair",
+	Short:   "Verify the repository webhooks on the VCS servers and recreate the missing or disabled ones",
+	Example: "cdsctl admin hooks repair",
+}
+
+func adminHooksWebHookRepairRun(v cli.Values) (cli.ListResult, error) {
+	btes, err := client.ServiceCallPOST("hooks", "/admin/webhook/repair", nil)
+	if err != nil {
+		return nil, err
+	}
+	reports := []sdk.RepositoryWebHookReport{}
+	if err := json.Unmarshal(btes, &reports); err != nil {
+		return nil, err
+	}
+	return cli.AsListResult(adminHooksWebHookReportDisplay(reports)), nil
+}
+
+type webHookReportDisplay struct {
+	UUID       string `cli:"UUID,key"`
+	Project    string `cli:"Project"`
+	Workflow   string `cli:"Workflow"`
+	VCSServer  string `cli:"VCSServer"`
+	Repository string `cli:"Repository"`
+	WebHookID  string `cli:"WebHookID"`
+	Status     string `cli:"Status"`
+	Message    string `cli:"Message"`
+	Date       string `cli:"Date"`
+}
+
+func adminHooksWebHookReportDisplay(reports []sdk.RepositoryWebHookReport) []webHookReportDisplay {
+	res := make([]webHookReportDisplay, len(reports))
+	for i, r := range reports {
+		res[i] = webHookReportDisplay{
+			UUID:       r.UUID,
+			Project:    r.Project,
+			Workflow:   r.Workflow,
+			VCSServer:  r.VCSServer,
+			Repository: r.Repository,
+			WebHookID:  r.WebHookID,
+			Status:     r.Status,
+			Message:    r.Message,
+			Date:       r.Date.Format(time.RFC3339),
+		}
+	}
+	return res
+}
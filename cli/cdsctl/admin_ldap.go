@@ -0,0 +1,56 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ovh/cds/cli"
+)
+
+var adminLDAPCmd = cli.Command{
+	Name:  "ldap",
+	Short: "Manage the LDAP groups synchronization",
+}
+
+func adminLDAP() *cobra.Command {
+	return cli.NewCommand(adminLDAPCmd, nil, []*cobra.Command{
+		cli.NewCommand(adminLDAPSyncStatusCmd, adminLDAPSyncStatusFunc, nil),
+	})
+}
+
+var adminLDAPSyncStatusCmd = cli.Command{
+	Name:  "sync-status",
+	Short: "Show the status and the changes of the last LDAP groups synchronization",
+}
+
+func adminLDAPSyncStatusFunc(v cli.Values) error {
+	s, err := client.AdminLDAPGroupSyncStatus()
+	if err != nil {
+		return err
+	}
+	if !s.Enabled {
+		fmt.Println("LDAP groups synchronization is disabled")
+		return nil
+	}
+	if s.Start.IsZero() {
+		fmt.Println("LDAP groups synchronization has not run yet")
+		return nil
+	}
+	fmt.Printf("Last synchronization: %s (%s)\n", s.Start.Format(time.RFC3339), s.End.Sub(s.Start))
+	if s.DryRun {
+		fmt.Println("Dry run: the changes were not applied")
+	}
+	if s.Error != "" {
+		fmt.Printf("Error: %s\n", s.Error)
+	}
+	for _, c := range s.Changes {
+		if c.User != "" {
+			fmt.Printf("%s %s %s\n", c.Action, c.Group, c.User)
+		} else {
+			fmt.Printf("%s %s\n", c.Action, c.Group)
+		}
+	}
+	return nil
+}
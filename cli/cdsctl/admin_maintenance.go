@@ -15,12 +15,17 @@ func adminMaintenance() *cobra.Command {
 	return cli.NewCommand(adminMaintenancesCmd, nil, []*cobra.Command{
 		cli.NewCommand(adminMaintenanceEnableCmd, adminMaintenanceEnable, nil),
 		cli.NewCommand(adminMaintenanceDisableCmd, adminMaintenanceDisable, nil),
+		cli.NewGetCommand(adminMaintenanceStatusCmd, adminMaintenanceStatus, nil),
 	})
 }
 
 var adminMaintenanceEnableCmd = cli.Command{
 	Name:  "enable",
 	Short: "Enable CDS maintenance",
+	Long: `
+During the maintenance, no new run can be started and the hatcheries stop booking jobs. The building jobs finish: once
+the queue is drained, CDS can be safely upgraded.
+`,
 }
 
 func adminMaintenanceEnable(v cli.Values) error {
@@ -35,3 +40,12 @@ var adminMaintenanceDisableCmd = cli.Command{
 func adminMaintenanceDisable(v cli.Values) error {
 	return client.Maintenance(false)
 }
+
+var adminMaintenanceStatusCmd = cli.Command{
+	Name:  "status",
+	Short: "Show CDS maintenance and the progress of the queue drain",
+}
+
+func adminMaintenanceStatus(v cli.Values) (interface{}, error) {
+	return client.MaintenanceStatus()
+}
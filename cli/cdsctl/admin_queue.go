@@ -0,0 +1,103 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ovh/cds/cli"
+)
+
+var adminQueueCmd = cli.Command{
+	Name:  "queue",
+	Short: "Manage the scheduling quotas of the job queue",
+}
+
+func adminQueue() *cobra.Command {
+	return cli.NewCommand(adminQueueCmd, nil, []*cobra.Command{
+		cli.NewCommand(adminQueueQuotasCmd, adminQueueQuotasFunc, nil),
+		cli.NewCommand(adminQueueQuotaSetCmd, adminQueueQuotaSetFunc, nil),
+		cli.NewCommand(adminQueueQuotaDeleteCmd, adminQueueQuotaDeleteFunc, nil),
+		cli.NewCommand(adminQueueWeightSetCmd, adminQueueWeightSetFunc, nil),
+		cli.NewCommand(adminQueueWeightDeleteCmd, adminQueueWeightDeleteFunc, nil),
+	})
+}
+
+var adminQueueQuotasCmd = cli.Command{
+	Name:  "quotas",
+	Short: "Show the project quotas and the group weights",
+}
+
+func adminQueueQuotasFunc(v cli.Values) error {
+	q, err := client.AdminQueueQuotas()
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Default project quota: %d\n", q.DefaultProjectQuota)
+	for _, p := range q.Projects {
+		fmt.Printf("project %s: %d concurrent jobs\n", p.ProjectKey, p.MaxConcurrentJobs)
+	}
+	for _, g := range q.Groups {
+		fmt.Printf("group %s: weight %d\n", g.GroupName, g.Weight)
+	}
+	return nil
+}
+
+var adminQueueQuotaSetCmd = cli.Command{
+	Name:  "quota-set",
+	Short: "Set the maximum number of jobs of a project building at the same time, 0 for no limit",
+	Args: []cli.Arg{
+		{Name: "project-key"},
+		{Name: "max-concurrent-jobs"},
+	},
+}
+
+func adminQueueQuotaSetFunc(v cli.Values) error {
+	n, err := strconv.Atoi(v.GetString("max-concurrent-jobs"))
+	if err != nil {
+		return fmt.Errorf("invalid max-concurrent-jobs: %v", err)
+	}
+	return client.AdminProjectQueueQuotaSet(v.GetString("project-key"), n)
+}
+
+var adminQueueQuotaDeleteCmd = cli.Command{
+	Name:  "quota-delete",
+	Short: "Remove the quota of a project, the default quota applies again",
+	Args: []cli.Arg{
+		{Name: "project-key"},
+	},
+}
+
+func adminQueueQuotaDeleteFunc(v cli.Values) error {
+	return client.AdminProjectQueueQuotaDelete(v.GetString("project-key"))
+}
+
+var adminQueueWeightSetCmd = cli.Command{
+	Name:  "weight-set",
+	Short: "Set the weight of a group, a group with a weight of 2 gets twice as many workers as a group with a weight of 1",
+	Args: []cli.Arg{
+		{Name: "group-name"},
+		{Name: "weight"},
+	},
+}
+
+func adminQueueWeightSetFunc(v cli.Values) error {
+	n, err := strconv.Atoi(v.GetString("weight"))
+	if err != nil {
+		return fmt.Errorf("invalid weight: %v", err)
+	}
+	return client.AdminGroupQueueWeightSet(v.GetString("group-name"), n)
+}
+
+var adminQueueWeightDeleteCmd = cli.Command{
+	Name:  "weight-delete",
+	Short: "Remove the weight of a group, the default weight applies again",
+	Args: []cli.Arg{
+		{Name: "group-name"},
+	},
+}
+
+func adminQueueWeightDeleteFunc(v cli.Values) error {
+	return client.AdminGroupQueueWeightDelete(v.GetString("group-name"))
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ovh/cds/cli"
+)
+
+var adminSecretsCmd = cli.Command{
+	Name:  "secrets",
+	Short: "Manage CDS secrets encryption",
+}
+
+func adminSecrets() *cobra.Command {
+	return cli.NewCommand(adminSecretsCmd, nil, []*cobra.Command{
+		cli.NewCommand(adminSecretsRotateCmd, adminSecretsRotateFunc, nil),
+	})
+}
+
+var adminSecretsRotateCmd = cli.Command{
+	Name:  "rotate",
+	Short: "Rotate the data key of a project, or of all projects, and re-encrypt their secrets",
+	Long: `Create a new data key, wrapped by the configured KMS, and re-encrypt all the secret variables and keys of the project with it.
+
+Without project key, the data keys of all the projects are rotated. This is also the way to migrate secrets to a new KMS.`,
+	OptionalArgs: []cli.Arg{
+		{Name: "project-key"},
+	},
+}
+
+func adminSecretsRotateFunc(v cli.Values) error {
+	res, err := client.AdminSecretsRotate(v.GetString("project-key"))
+	if err != nil {
+		return err
+	}
+	for key, n := range res {
+		fmt.Printf("%s: %d secrets re-encrypted\n", key, n)
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/ovh/cds/cli"
+)
+
+var adminWorkerModelCmd = cli.Command{
+	Name:  "worker-model",
+	Short: "Manage the worker models of the CDS instance",
+}
+
+func adminWorkerModel() *cobra.Command {
+	return cli.NewCommand(adminWorkerModelCmd, nil, []*cobra.Command{
+		cli.NewListCommand(adminWorkerModelUnusedCmd, adminWorkerModelUnusedRun, nil),
+	})
+}
+
+var adminWorkerModelUnusedCmd = cli.Command{
+	Name:    "unused",
+	Short:   "List the worker models which have run no job for a number of days, the least recently used first",
+	Example: `cdsctl admin worker-model unused --days 90`,
+	Flags: []cli.Flag{
+		{
+			Name:    "days",
+			Usage:   "Number of days without job",
+			Default: "30",
+		},
+	},
+}
+
+func adminWorkerModelUnusedRun(v cli.Values) (cli.ListResult, error) {
+	days, err := v.GetInt64("days")
+	if err != nil {
+		return nil, err
+	}
+	models, err := client.AdminUnusedWorkerModels(int(days))
+	if err != nil {
+		return nil, err
+	}
+	return cli.AsListResult(models), nil
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ovh/cds/cli"
+	"github.com/ovh/cds/sdk"
+)
+
+var adminWorkerPoolCmd = cli.Command{
+	Name:  "worker-pool",
+	Short: "Manage the pools of workers registered by themselves on fixed hosts",
+}
+
+func adminWorkerPool() *cobra.Command {
+	return cli.NewCommand(adminWorkerPoolCmd, nil, []*cobra.Command{
+		cli.NewListCommand(adminWorkerPoolListCmd, adminWorkerPoolListRun, nil),
+		cli.NewListCommand(adminWorkerPoolDrainCmd, adminWorkerPoolDrainRun, nil),
+	})
+}
+
+type poolWorker struct {
+	Pool         string `cli:"pool,key"`
+	Name         string `cli:"name"`
+	Status       string `cli:"status"`
+	Job          int64  `cli:"job"`
+	LastBeat     string `cli:"last_beat"`
+	Draining     bool   `cli:"draining"`
+	Capabilities string `cli:"capabilities"`
+}
+
+func poolWorkers(ws []sdk.Worker) cli.ListResult {
+	res := make([]poolWorker, len(ws))
+	for i, w := range ws {
+		res[i] = poolWorker{
+			Pool:         w.Pool,
+			Name:         w.Name,
+			Status:       w.Status.String(),
+			Job:          w.ActionBuildID,
+			LastBeat:     w.LastBeat.Format("2006-01-02 15:04:05"),
+			Draining:     w.Draining,
+			Capabilities: strings.Join(w.Capabilities, ","),
+		}
+	}
+	return cli.AsListResult(res)
+}
+
+var adminWorkerPoolListCmd = cli.Command{
+	Name:  "list",
+	Short: "List the workers of the pools, with their status, their current job and their capabilities",
+	Flags: []cli.Flag{
+		{
+			Name:  "pool",
+			Usage: "List only the workers of this pool",
+		},
+	},
+}
+
+func adminWorkerPoolListRun(v cli.Values) (cli.ListResult, error) {
+	ws, err := client.AdminWorkerPools(v.GetString("pool"))
+	if err != nil {
+		return nil, err
+	}
+	return poolWorkers(ws), nil
+}
+
+var adminWorkerPoolDrainCmd = cli.Command{
+	Name:  "drain",
+	Short: "Drain the workers of a pool: they finish their current job and exit instead of taking a new one",
+	Example: `cdsctl admin worker-pool drain lab
+cdsctl admin worker-pool drain lab --worker lab-host-1 --worker lab-host-2`,
+	Args: []cli.Arg{
+		{Name: "pool"},
+	},
+	Flags: []cli.Flag{
+		{
+			Name:  "worker",
+			Type:  cli.FlagSlice,
+			Usage: "Drain only this worker of the pool",
+		},
+	},
+}
+
+func adminWorkerPoolDrainRun(v cli.Values) (cli.ListResult, error) {
+	var names []string
+	for _, n := range v.GetStringSlice("worker") {
+		if n != "" {
+			names = append(names, n)
+		}
+	}
+	ws, err := client.AdminWorkerPoolDrain(v.GetString("pool"), names...)
+	if err != nil {
+		return nil, err
+	}
+	return poolWorkers(ws), nil
+}
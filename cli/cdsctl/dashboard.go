@@ -0,0 +1,148 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strconv"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ovh/cds/cli"
+	"github.com/ovh/cds/sdk"
+)
+
+var dashboardCmd = cli.Command{
+	Name:  "dashboard",
+	Short: "Manage CDS dashboards",
+	Long: `A dashboard is a set of widgets showing the run history of a workflow, the queue depth of a project or the
+deployment frequency of a pipeline of a workflow. It can be shared with users and groups.`,
+}
+
+func dashboard() *cobra.Command {
+	return cli.NewCommand(dashboardCmd, nil, []*cobra.Command{
+		cli.NewListCommand(dashboardListCmd, dashboardListRun, nil),
+		cli.NewGetCommand(dashboardShowCmd, dashboardShowRun, nil),
+		cli.NewCommand(dashboardExportCmd, dashboardExportRun, nil),
+		cli.NewCommand(dashboardImportCmd, dashboardImportRun, nil),
+		cli.NewDeleteCommand(dashboardDeleteCmd, dashboardDeleteRun, nil),
+	})
+}
+
+func dashboardID(v cli.Values) (int64, error) {
+	id, err := strconv.ParseInt(v.GetString("dashboard-id"), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid dashboard-id: %v", err)
+	}
+	return id, nil
+}
+
+var dashboardListCmd = cli.Command{
+	Name:  "list",
+	Short: "List your CDS dashboards and the dashboards shared with you",
+}
+
+func dashboardListRun(v cli.Values) (cli.ListResult, error) {
+	ds, err := client.DashboardList()
+	if err != nil {
+		return nil, err
+	}
+	return cli.AsListResult(ds), nil
+}
+
+var dashboardShowCmd = cli.Command{
+	Name:  "show",
+	Short: "Show a CDS dashboard",
+	Args: []cli.Arg{
+		{Name: "dashboard-id"},
+	},
+}
+
+func dashboardShowRun(v cli.Values) (interface{}, error) {
+	id, err := dashboardID(v)
+	if err != nil {
+		return nil, err
+	}
+	d, err := client.DashboardGet(id)
+	if err != nil {
+		return nil, err
+	}
+	return *d, nil
+}
+
+var dashboardExportCmd = cli.Command{
+	Name:  "export",
+	Short: "Export a CDS dashboard as JSON",
+	Args: []cli.Arg{
+		{Name: "dashboard-id"},
+	},
+}
+
+func dashboardExportRun(v cli.Values) error {
+	id, err := dashboardID(v)
+	if err != nil {
+		return err
+	}
+	d, err := client.DashboardGet(id)
+	if err != nil {
+		return err
+	}
+	btes, err := json.MarshalIndent(d, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(btes))
+	return nil
+}
+
+var dashboardImportCmd = cli.Command{
+	Name:  "import",
+	Short: "Import a CDS dashboard from a JSON file",
+	Long: `Import a CDS dashboard from a JSON file, as exported by cdsctl dashboard export. The dashboard is created, or
+updated when the file contains its id.`,
+	Args: []cli.Arg{
+		{Name: "path"},
+	},
+}
+
+func dashboardImportRun(v cli.Values) error {
+	btes, err := ioutil.ReadFile(v.GetString("path"))
+	if err != nil {
+		return fmt.Errorf("Error while reading file: %s", err)
+	}
+	var d sdk.Dashboard
+	if err := json.Unmarshal(btes, &d); err != nil {
+		return fmt.Errorf("invalid dashboard file: %v", err)
+	}
+	if d.ID == 0 {
+		err = client.DashboardCreate(&d)
+	} else {
+		err = client.DashboardUpdate(&d)
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Dashboard %d %s imported\n", d.ID, d.Name)
+	return nil
+}
+
+var dashboardDeleteCmd = cli.Command{
+	Name:  "delete",
+	Short: "Delete one of your CDS dashboards",
+	Args: []cli.Arg{
+		{Name: "dashboard-id"},
+	},
+}
+
+func dashboardDeleteRun(v cli.Values) error {
+	id, err := dashboardID(v)
+	if err != nil {
+		return err
+	}
+	err = client.DashboardDelete(id)
+	if err != nil && v.GetBool("force") && sdk.ErrorIs(err, sdk.ErrNotFound) {
+		fmt.Println(err.Error())
+		return nil
+	}
+	return err
+}
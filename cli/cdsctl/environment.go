@@ -26,6 +26,7 @@ func environment() *cobra.Command {
 		cli.NewDeleteCommand(environmentDeleteCmd, environmentDeleteRun, nil, withAllCommandModifiers()...),
 		environmentKey(),
 		environmentVariable(),
+		environmentFreeze(),
 		cli.NewCommand(environmentExportCmd, environmentExportRun, nil, withAllCommandModifiers()...),
 		cli.NewCommand(environmentImportCmd, environmentImportRun, nil, withAllCommandModifiers()...),
 	})
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ovh/cds/cli"
+	"github.com/ovh/cds/sdk"
+)
+
+var environmentFreezeCmd = cli.Command{
+	Name:  "freeze",
+	Short: "Manage CDS environment locks and freeze windows",
+	Long: `Manage the locks and the freeze windows of a CDS environment. While an environment is frozen, the jobs of the
+deployment nodes targeting it wait in the queue, unless a CDS administrator overrides the freeze for their node run.`,
+}
+
+func environmentFreeze() *cobra.Command {
+	return cli.NewCommand(environmentFreezeCmd, nil, []*cobra.Command{
+		cli.NewListCommand(environmentFreezeListCmd, environmentFreezeListRun, nil, withAllCommandModifiers()...),
+		cli.NewCommand(environmentFreezeAddCmd, environmentFreezeAddRun, nil, withAllCommandModifiers()...),
+		cli.NewCommand(environmentFreezeDeleteCmd, environmentFreezeDeleteRun, nil, withAllCommandModifiers()...),
+		cli.NewCommand(environmentFreezeOverrideCmd, environmentFreezeOverrideRun, nil, withAllCommandModifiers()...),
+	})
+}
+
+var environmentFreezeListCmd = cli.Command{
+	Name:  "list",
+	Short: "List the locks and the freeze windows of a CDS environment",
+	Ctx: []cli.Arg{
+		{Name: _ProjectKey},
+	},
+	Args: []cli.Arg{
+		{Name: "env-name"},
+	},
+}
+
+func environmentFreezeListRun(v cli.Values) (cli.ListResult, error) {
+	fs, err := client.EnvironmentFreezeList(v.GetString(_ProjectKey), v.GetString("env-name"))
+	if err != nil {
+		return nil, err
+	}
+	return cli.AsListResult(fs), nil
+}
+
+var environmentFreezeAddCmd = cli.Command{
+	Name:  "add",
+	Short: "Lock a CDS environment or add a freeze window to it",
+	Long: `Lock a CDS environment until the lock is deleted, or freeze it between two dates with --from and --to, or
+periodically with --cron and --duration.`,
+	Example: `cdsctl environment freeze add MYPROJ production --reason "incident in progress"
+cdsctl environment freeze add MYPROJ production --from 2019-12-20T18:00:00Z --to 2020-01-06T09:00:00Z --reason "end of year"
+cdsctl environment freeze add MYPROJ production --cron "0 18 * * 5" --duration 63h --timezone Europe/Paris --reason weekend`,
+	Ctx: []cli.Arg{
+		{Name: _ProjectKey},
+	},
+	Args: []cli.Arg{
+		{Name: "env-name"},
+	},
+	Flags: []cli.Flag{
+		{Name: "reason", Usage: "Reason of the freeze, shown on the blocked jobs"},
+		{Name: "from", Usage: "Start of the freeze window, formatted as RFC3339"},
+		{Name: "to", Usage: "End of the freeze window, formatted as RFC3339"},
+		{Name: "cron", Usage: "Cron expression of the starts of a periodic freeze"},
+		{Name: "duration", Usage: "Duration of a periodic freeze, for example 63h"},
+		{Name: "timezone", Usage: "Timezone of the cron expression, UTC by default"},
+	},
+}
+
+func environmentFreezeAddRun(v cli.Values) error {
+	f := sdk.EnvironmentFreeze{Type: sdk.EnvironmentFreezeLock, Reason: v.GetString("reason")}
+	switch {
+	case v.GetString("cron") != "":
+		d, err := time.ParseDuration(v.GetString("duration"))
+		if err != nil {
+			return fmt.Errorf("invalid duration %s: %v", v.GetString("duration"), err)
+		}
+		f.Type, f.Cron, f.Duration, f.Timezone = sdk.EnvironmentFreezeCron, v.GetString("cron"), int64(d.Minutes()), v.GetString("timezone")
+	case v.GetString("from") != "" || v.GetString("to") != "":
+		from, err := time.Parse(time.RFC3339, v.GetString("from"))
+		if err != nil {
+			return fmt.Errorf("invalid from date %s: %v", v.GetString("from"), err)
+		}
+		to, err := time.Parse(time.RFC3339, v.GetString("to"))
+		if err != nil {
+			return fmt.Errorf("invalid to date %s: %v", v.GetString("to"), err)
+		}
+		f.Type, f.From, f.To = sdk.EnvironmentFreezeWindow, &from, &to
+	}
+	if err := client.EnvironmentFreezeCreate(v.GetString(_ProjectKey), v.GetString("env-name"), &f); err != nil {
+		return err
+	}
+	fmt.Printf("Environment %s frozen (%s %d)\n", v.GetString("env-name"), f.Type, f.ID)
+	return nil
+}
+
+var environmentFreezeDeleteCmd = cli.Command{
+	Name:  "delete",
+	Short: "Delete a lock or a freeze window of a CDS environment",
+	Ctx: []cli.Arg{
+		{Name: _ProjectKey},
+	},
+	Args: []cli.Arg{
+		{Name: "env-name"},
+		{Name: "id"},
+	},
+}
+
+func environmentFreezeDeleteRun(v cli.Values) error {
+	id, err := strconv.ParseInt(v.GetString("id"), 10, 64)
+	if err != nil {
+		return fmt.Errorf("id parameter have to be an integer")
+	}
+	return client.EnvironmentFreezeDelete(v.GetString(_ProjectKey), v.GetString("env-name"), id)
+}
+
+var environmentFreezeOverrideCmd = cli.Command{
+	Name:    "override",
+	Short:   "Let the jobs of a node run deploy on a frozen environment, for CDS administrators only",
+	Example: `cdsctl environment freeze override MYPROJ my-workflow 42 1234`,
+	Ctx: []cli.Arg{
+		{Name: _ProjectKey},
+		{Name: _WorkflowName},
+	},
+	Args: []cli.Arg{
+		{Name: "run-number"},
+		{Name: "node-run-id"},
+	},
+}
+
+func environmentFreezeOverrideRun(v cli.Values) error {
+	number, err := strconv.ParseInt(v.GetString("run-number"), 10, 64)
+	if err != nil {
+		return fmt.Errorf("run-number parameter have to be an integer")
+	}
+	nodeRunID, err := strconv.ParseInt(v.GetString("node-run-id"), 10, 64)
+	if err != nil {
+		return fmt.Errorf("node-run-id parameter have to be an integer")
+	}
+	return client.WorkflowNodeRunFreezeOverride(v.GetString(_ProjectKey), v.GetString(_WorkflowName), number, nodeRunID)
+}
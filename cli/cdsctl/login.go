@@ -94,6 +94,18 @@ func doLogin(url, username, password string, env bool) error {
 
 	client = cdsclient.New(conf)
 	ok, token, err := client.UserLogin(username, password)
+	if sdk.ErrorIs(err, sdk.ErrMFARequired) {
+		// Two-factor authentication is enabled on the account
+		fmt.Fprintf(os.Stderr, "Authentication code (or recovery code): ")
+		v, errp := sdk.ParseMFAHeader(cli.ReadLine())
+		if errp != nil {
+			return errp
+		}
+		ok, token, err = client.UserLoginWithMFA(username, password, &v)
+		if sdk.ErrorIs(err, sdk.ErrMFARequired) || sdk.ErrorIs(err, sdk.ErrMFAInvalid) {
+			return fmt.Errorf("login failed: invalid authentication code")
+		}
+	}
 	if err != nil {
 		if conf.Verbose {
 			fmt.Fprintf(os.Stderr, "error:%s\n", err)
@@ -31,10 +31,13 @@ func main() {
 		environment(),
 		pipeline(),
 		group(),
+		organization(),
+		dashboard(),
 		health(),
 		project(),
 		worker(),
 		workflow(),
+		search(),
 		update(),
 		usr(),
 		shell(),
@@ -96,6 +99,10 @@ You can bypass keychain tools by using environment variables:
 
 	CDS_API_URL="https://instance.cds.api" CDS_USER="username" CDS_TOKEN="yourtoken" cdsctl [command]
 
+or a personal access token created with ` + "`cdsctl user accesstoken create`" + `:
+
+	CDS_API_URL="https://instance.cds.api" CDS_ACCESS_TOKEN="yourtoken" cdsctl [command]
+
 
 Want to debug something? You can use ` + "`CDS_VERBOSE`" + ` environment variable.
 
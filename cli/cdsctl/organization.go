@@ -0,0 +1,237 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ovh/cds/cli"
+	"github.com/ovh/cds/sdk"
+)
+
+var organizationCmd = cli.Command{
+	Name:    "organization",
+	Short:   "Manage CDS organizations",
+	Aliases: []string{"org"},
+	Long: `An organization groups the projects of a same tenant. The administrators of an organization manage all its
+projects, and its quotas are shared by all its projects.`,
+}
+
+func organization() *cobra.Command {
+	return cli.NewCommand(organizationCmd, nil, []*cobra.Command{
+		cli.NewListCommand(organizationListCmd, organizationListRun, nil),
+		cli.NewGetCommand(organizationShowCmd, organizationShowRun, nil),
+		cli.NewCommand(organizationCreateCmd, organizationCreateRun, nil),
+		cli.NewCommand(organizationQuotaCmd, organizationQuotaRun, nil),
+		cli.NewGetCommand(organizationUsageCmd, organizationUsageRun, nil),
+		cli.NewDeleteCommand(organizationDeleteCmd, organizationDeleteRun, nil),
+		organizationAdmin(),
+		organizationProject(),
+	})
+}
+
+var organizationListCmd = cli.Command{
+	Name:  "list",
+	Short: "List CDS organizations (admin only)",
+}
+
+func organizationListRun(v cli.Values) (cli.ListResult, error) {
+	orgs, err := client.OrganizationList()
+	if err != nil {
+		return nil, err
+	}
+	return cli.AsListResult(orgs), nil
+}
+
+var organizationShowCmd = cli.Command{
+	Name:  "show",
+	Short: "Show a CDS organization with its administrators and its projects",
+	Args: []cli.Arg{
+		{Name: "organization-name"},
+	},
+}
+
+func organizationShowRun(v cli.Values) (interface{}, error) {
+	o, err := client.OrganizationGet(v.GetString("organization-name"))
+	if err != nil {
+		return nil, err
+	}
+	return *o, nil
+}
+
+var organizationCreateCmd = cli.Command{
+	Name:  "create",
+	Short: "Create a CDS organization (admin only)",
+	Args: []cli.Arg{
+		{Name: "organization-name"},
+	},
+	Flags: []cli.Flag{
+		{
+			Name:  "description",
+			Usage: "Description of the organization",
+		},
+	},
+	Aliases: []string{"add"},
+}
+
+func organizationCreateRun(v cli.Values) error {
+	return client.OrganizationCreate(&sdk.Organization{
+		Name:        v.GetString("organization-name"),
+		Description: v.GetString("description"),
+	})
+}
+
+var organizationQuotaCmd = cli.Command{
+	Name:  "quota",
+	Short: "Set the quotas of a CDS organization, 0 for no limit (admin only)",
+	Long: `Set the maximum number of jobs of the projects of the organization building at the same time, which is also the
+maximum number of workers they use, the maximum size in megabytes of their artifacts, and the maximum build minutes
+they use each month.`,
+	Args: []cli.Arg{
+		{Name: "organization-name"},
+		{Name: "max-concurrent-jobs"},
+		{Name: "max-storage"},
+		{Name: "max-build-minutes"},
+	},
+}
+
+func organizationQuotaRun(v cli.Values) error {
+	jobs, err := strconv.Atoi(v.GetString("max-concurrent-jobs"))
+	if err != nil {
+		return fmt.Errorf("invalid max-concurrent-jobs: %v", err)
+	}
+	storage, err := v.GetInt64("max-storage")
+	if err != nil {
+		return fmt.Errorf("invalid max-storage: %v", err)
+	}
+	minutes, err := strconv.Atoi(v.GetString("max-build-minutes"))
+	if err != nil {
+		return fmt.Errorf("invalid max-build-minutes: %v", err)
+	}
+
+	o, err := client.OrganizationGet(v.GetString("organization-name"))
+	if err != nil {
+		return err
+	}
+	o.MaxConcurrentJobs = jobs
+	o.MaxStorage = storage
+	o.MaxBuildMinutes = minutes
+	return client.OrganizationUpdate(o.Name, o)
+}
+
+var organizationUsageCmd = cli.Command{
+	Name:  "usage",
+	Short: "Show the usage of the projects of a CDS organization during a month and its quotas",
+	Args: []cli.Arg{
+		{Name: "organization-name"},
+	},
+	Flags: []cli.Flag{
+		{
+			Name:  "month",
+			Usage: "Month of the usage, formatted as YYYY-MM, the current month by default",
+		},
+	},
+}
+
+func organizationUsageRun(v cli.Values) (interface{}, error) {
+	r, err := client.OrganizationUsage(v.GetString("organization-name"), v.GetString("month"))
+	if err != nil {
+		return nil, err
+	}
+	return *r, nil
+}
+
+var organizationDeleteCmd = cli.Command{
+	Name:  "delete",
+	Short: "Delete a CDS organization without projects (admin only)",
+	Args: []cli.Arg{
+		{Name: "organization-name"},
+	},
+}
+
+func organizationDeleteRun(v cli.Values) error {
+	err := client.OrganizationDelete(v.GetString("organization-name"))
+	if err != nil && v.GetBool("force") && sdk.ErrorIs(err, sdk.ErrNotFound) {
+		fmt.Println(err.Error())
+		return nil
+	}
+	return err
+}
+
+var organizationAdminCmd = cli.Command{
+	Name:  "admin",
+	Short: "Manage the administrators of a CDS organization",
+}
+
+func organizationAdmin() *cobra.Command {
+	return cli.NewCommand(organizationAdminCmd, nil, []*cobra.Command{
+		cli.NewCommand(organizationAdminAddCmd, organizationAdminAddRun, nil),
+		cli.NewCommand(organizationAdminRemoveCmd, organizationAdminRemoveRun, nil),
+	})
+}
+
+var organizationAdminAddCmd = cli.Command{
+	Name:  "add",
+	Short: "Make a user administrator of a CDS organization",
+	Args: []cli.Arg{
+		{Name: "organization-name"},
+		{Name: "username"},
+	},
+}
+
+func organizationAdminAddRun(v cli.Values) error {
+	return client.OrganizationAdminAdd(v.GetString("organization-name"), v.GetString("username"))
+}
+
+var organizationAdminRemoveCmd = cli.Command{
+	Name:  "remove",
+	Short: "Remove a user from the administrators of a CDS organization",
+	Args: []cli.Arg{
+		{Name: "organization-name"},
+		{Name: "username"},
+	},
+}
+
+func organizationAdminRemoveRun(v cli.Values) error {
+	return client.OrganizationAdminRemove(v.GetString("organization-name"), v.GetString("username"))
+}
+
+var organizationProjectCmd = cli.Command{
+	Name:  "project",
+	Short: "Manage the projects of a CDS organization",
+}
+
+func organizationProject() *cobra.Command {
+	return cli.NewCommand(organizationProjectCmd, nil, []*cobra.Command{
+		cli.NewCommand(organizationProjectAddCmd, organizationProjectAddRun, nil),
+		cli.NewCommand(organizationProjectRemoveCmd, organizationProjectRemoveRun, nil),
+	})
+}
+
+var organizationProjectAddCmd = cli.Command{
+	Name:  "add",
+	Short: "Attach a project to a CDS organization",
+	Long:  `Attach a project to a CDS organization, the public integrations of the organization are added to the project.`,
+	Args: []cli.Arg{
+		{Name: "organization-name"},
+		{Name: "project-key"},
+	},
+}
+
+func organizationProjectAddRun(v cli.Values) error {
+	return client.OrganizationProjectAdd(v.GetString("organization-name"), v.GetString("project-key"))
+}
+
+var organizationProjectRemoveCmd = cli.Command{
+	Name:  "remove",
+	Short: "Detach a project from a CDS organization",
+	Args: []cli.Arg{
+		{Name: "organization-name"},
+		{Name: "project-key"},
+	},
+}
+
+func organizationProjectRemoveRun(v cli.Values) error {
+	return client.OrganizationProjectRemove(v.GetString("organization-name"), v.GetString("project-key"))
+}
@@ -24,7 +24,17 @@ func projectCommands() []*cobra.Command {
 		cli.NewCommand(projectCreateCmd, projectCreateRun, nil),
 		cli.NewDeleteCommand(projectDeleteCmd, projectDeleteRun, nil, withAllCommandModifiers()...),
 		cli.NewCommand(projectFavoriteCmd, projectFavoriteRun, nil, withAllCommandModifiers()...),
+		cli.NewCommand(projectArchiveCmd, projectArchiveRun, nil, withAllCommandModifiers()...),
+		cli.NewCommand(projectRestoreCmd, projectRestoreRun, nil, withAllCommandModifiers()...),
+		cli.NewCommand(projectExportCmd, projectExportRun, nil, withAllCommandModifiers()...),
+		cli.NewCommand(projectImportCmd, projectImportRun, nil),
+		cli.NewGetCommand(projectUsageCmd, projectUsageRun, nil, withAllCommandModifiers()...),
+		cli.NewGetCommand(projectDashboardCmd, projectDashboardRun, nil, withAllCommandModifiers()...),
 		projectKey(),
+		projectCost(),
+		projectDeployment(),
+		cli.NewListCommand(projectDORACmd, projectDORARun, nil, withAllCommandModifiers()...),
+		projectTrash(),
 		projectGroup(),
 		projectVariable(),
 		ProjectIntegration(),
@@ -44,10 +54,22 @@ func projectShell() *cobra.Command {
 var projectListCmd = cli.Command{
 	Name:  "list",
 	Short: "List CDS projects",
+	Flags: []cli.Flag{
+		{
+			Type:    cli.FlagBool,
+			Name:    "archived",
+			Usage:   "List the archived projects too",
+			Default: "false",
+		},
+	},
 }
 
 func projectListRun(v cli.Values) (cli.ListResult, error) {
-	projs, err := client.ProjectList(false, false)
+	var filters []cdsclient.Filter
+	if v.GetBool("archived") {
+		filters = append(filters, cdsclient.Filter{Name: "archived", Value: "true"})
+	}
+	projs, err := client.ProjectList(false, false, filters...)
 	if err != nil {
 		return nil, err
 	}
@@ -163,3 +185,44 @@ func projectDeleteRun(v cli.Values) error {
 	}
 	return nil
 }
+
+var projectUsageCmd = cli.Command{
+	Name:  "usage",
+	Short: "Show the build minutes and the size of the artifacts of a CDS project during a month",
+	Ctx: []cli.Arg{
+		{Name: _ProjectKey},
+	},
+	Flags: []cli.Flag{
+		{
+			Name:  "month",
+			Usage: "Month of the usage, formatted as YYYY-MM, the current month by default",
+		},
+	},
+}
+
+func projectUsageRun(v cli.Values) (interface{}, error) {
+	u, err := client.ProjectUsage(v.GetString(_ProjectKey), v.GetString("month"))
+	if err != nil {
+		return nil, err
+	}
+	return *u, nil
+}
+
+var projectDashboardCmd = cli.Command{
+	Name:  "dashboard",
+	Short: "Show the health of the workflows of a CDS project",
+	Long: `Show the status of the last run of each workflow, the success rates over 7 and 30 days, the jobs waiting in the
+queue, the pull requests opened on the repositories of the as code workflows and the workflows failing on the default
+branch of their repository.`,
+	Ctx: []cli.Arg{
+		{Name: _ProjectKey},
+	},
+}
+
+func projectDashboardRun(v cli.Values) (interface{}, error) {
+	d, err := client.ProjectDashboard(v.GetString(_ProjectKey))
+	if err != nil {
+		return nil, err
+	}
+	return *d, nil
+}
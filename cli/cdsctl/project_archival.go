@@ -0,0 +1,45 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/ovh/cds/cli"
+)
+
+var projectArchiveCmd = cli.Command{
+	Name:  "archive",
+	Short: "Archive a CDS project",
+	Long: `Archive a CDS project: its hooks and schedulers are stopped and it becomes read-only.
+
+An archived project is hidden from "cdsctl project list", use the --archived flag to list it.
+Only an administrator can restore it.`,
+	Ctx: []cli.Arg{
+		{Name: _ProjectKey},
+	},
+}
+
+func projectArchiveRun(v cli.Values) error {
+	proj, err := client.ProjectArchive(v.GetString(_ProjectKey))
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Project %s archived\n", proj.Name)
+	return nil
+}
+
+var projectRestoreCmd = cli.Command{
+	Name:  "restore",
+	Short: "Restore an archived CDS project (admin only)",
+	Ctx: []cli.Arg{
+		{Name: _ProjectKey},
+	},
+}
+
+func projectRestoreRun(v cli.Values) error {
+	proj, err := client.ProjectRestore(v.GetString(_ProjectKey))
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Project %s restored\n", proj.Name)
+	return nil
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/ovh/cds/cli"
+)
+
+var projectExportCmd = cli.Command{
+	Name:  "export",
+	Short: "Export a project in a tar archive",
+	Long: `Export a project with its environments, applications, pipelines and workflows in a tar archive, that can be imported on another CDS instance with cdsctl project import.
+
+With --full, the archive also contains the secrets of the project: secret variables, keys and the configuration of the integrations. They are encrypted with the passphrase, that is needed to import the archive. Only an administrator can export a full archive.
+
+	cdsctl project export MYPROJ --full --passphrase "my passphrase" --output myproj.tar
+`,
+	Ctx: []cli.Arg{
+		{Name: _ProjectKey},
+	},
+	Flags: []cli.Flag{
+		{
+			Type:    cli.FlagBool,
+			Name:    "full",
+			Usage:   "Export the secrets, keys and integrations of the project",
+			Default: "false",
+		},
+		{
+			Name:  "passphrase",
+			Usage: "Passphrase used to encrypt the secrets of a full archive",
+		},
+		{
+			Name:      "output",
+			ShortHand: "o",
+			Usage:     "Archive file, default to <project key>.tar",
+		},
+	},
+}
+
+func projectExportRun(v cli.Values) error {
+	key := v.GetString(_ProjectKey)
+	full := v.GetBool("full")
+	if full && v.GetString("passphrase") == "" {
+		return fmt.Errorf("a passphrase is required to export a full archive")
+	}
+
+	btes, err := client.ProjectArchiveExport(key, full, v.GetString("passphrase"))
+	if err != nil {
+		return err
+	}
+
+	output := v.GetString("output")
+	if output == "" {
+		output = key + ".tar"
+	}
+	if err := ioutil.WriteFile(output, btes, 0600); err != nil {
+		return fmt.Errorf("unable to write archive %s: %v", output, err)
+	}
+	fmt.Printf("Project %s exported in %s\n", key, output)
+	return nil
+}
+
+var projectImportCmd = cli.Command{
+	Name:  "import",
+	Short: "Import a project from a tar archive",
+	Long: `Create a project from an archive exported with cdsctl project export. The passphrase is required if the archive contains secrets. Only an administrator can import a project.
+
+	cdsctl project import myproj.tar --passphrase "my passphrase"
+`,
+	Args: []cli.Arg{
+		{Name: "path"},
+	},
+	Flags: []cli.Flag{
+		{
+			Name:  "passphrase",
+			Usage: "Passphrase used to encrypt the secrets of the archive",
+		},
+	},
+}
+
+func projectImportRun(v cli.Values) error {
+	btes, err := ioutil.ReadFile(v.GetString("path"))
+	if err != nil {
+		return err
+	}
+
+	msgs, err := client.ProjectArchiveImport(bytes.NewReader(btes), v.GetString("passphrase"))
+	if err != nil {
+		return err
+	}
+	for _, s := range msgs {
+		fmt.Println(s)
+	}
+	return nil
+}
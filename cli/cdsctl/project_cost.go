@@ -0,0 +1,71 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ovh/cds/cli"
+)
+
+var costFlags = []cli.Flag{
+	{
+		Name:  "by",
+		Usage: "Aggregation of the costs: project|application|group|workflow|run",
+	},
+	{
+		Name:  "from",
+		Usage: "First day of the period, formatted as YYYY-MM-DD, the first day of the current month by default",
+	},
+	{
+		Name:  "to",
+		Usage: "Last day of the period, formatted as YYYY-MM-DD, today by default",
+	},
+}
+
+var projectCostCmd = cli.Command{
+	Name:  "cost",
+	Short: "Show the compute time and the artifact size charged to a CDS project",
+}
+
+func projectCost() *cobra.Command {
+	return cli.NewCommand(projectCostCmd, nil, []*cobra.Command{
+		cli.NewListCommand(projectCostShowCmd, projectCostShowRun, nil, withAllCommandModifiers()...),
+		cli.NewCommand(projectCostExportCmd, projectCostExportRun, nil, withAllCommandModifiers()...),
+	})
+}
+
+var projectCostShowCmd = cli.Command{
+	Name:  "show",
+	Short: "Show the costs of a CDS project during a period, by application by default",
+	Ctx: []cli.Arg{
+		{Name: _ProjectKey},
+	},
+	Flags: costFlags,
+}
+
+func projectCostShowRun(v cli.Values) (cli.ListResult, error) {
+	r, err := client.ProjectCost(v.GetString(_ProjectKey), v.GetString("by"), v.GetString("from"), v.GetString("to"))
+	if err != nil {
+		return nil, err
+	}
+	return cli.AsListResult(r.Lines), nil
+}
+
+var projectCostExportCmd = cli.Command{
+	Name:  "export",
+	Short: "Export the costs of a CDS project during a period as CSV",
+	Ctx: []cli.Arg{
+		{Name: _ProjectKey},
+	},
+	Flags: costFlags,
+}
+
+func projectCostExportRun(v cli.Values) error {
+	btes, err := client.ProjectCostCSV(v.GetString(_ProjectKey), v.GetString("by"), v.GetString("from"), v.GetString("to"))
+	if err != nil {
+		return err
+	}
+	fmt.Print(string(btes))
+	return nil
+}
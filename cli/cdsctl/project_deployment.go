@@ -0,0 +1,122 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ovh/cds/cli"
+	"github.com/ovh/cds/sdk"
+)
+
+var projectDeploymentCmd = cli.Command{
+	Name:  "deployment",
+	Short: "Show what version of the applications of a CDS project runs in which environment",
+}
+
+func projectDeployment() *cobra.Command {
+	return cli.NewCommand(projectDeploymentCmd, nil, []*cobra.Command{
+		cli.NewListCommand(projectDeploymentCurrentCmd, projectDeploymentCurrentRun, nil, withAllCommandModifiers()...),
+		cli.NewListCommand(projectDeploymentHistoryCmd, projectDeploymentHistoryRun, nil, withAllCommandModifiers()...),
+		cli.NewGetCommand(projectDeploymentRollbackCmd, projectDeploymentRollbackRun, nil, withAllCommandModifiers()...),
+	})
+}
+
+var projectDeploymentCurrentCmd = cli.Command{
+	Name:  "current",
+	Short: "Show the version currently deployed of each application on each environment",
+	Ctx: []cli.Arg{
+		{Name: _ProjectKey},
+	},
+	OptionalArgs: []cli.Arg{
+		{Name: "environment"},
+	},
+}
+
+func projectDeploymentCurrentRun(v cli.Values) (cli.ListResult, error) {
+	ds, err := client.ProjectCurrentDeployments(v.GetString(_ProjectKey), v.GetString("environment"))
+	if err != nil {
+		return nil, err
+	}
+	return cli.AsListResult(ds), nil
+}
+
+var projectDeploymentHistoryCmd = cli.Command{
+	Name:  "history",
+	Short: "Show the last deployments of a CDS project, the most recent first",
+	Ctx: []cli.Arg{
+		{Name: _ProjectKey},
+	},
+	Flags: []cli.Flag{
+		{
+			Name:  "application",
+			Usage: "Filter on an application name",
+		},
+		{
+			Name:  "environment",
+			Usage: "Filter on an environment name",
+		},
+		{
+			Name:    "limit",
+			Usage:   "Number of deployments to show, up to 500",
+			Default: "50",
+		},
+	},
+}
+
+func projectDeploymentHistoryRun(v cli.Values) (cli.ListResult, error) {
+	limit, err := v.GetInt64("limit")
+	if err != nil {
+		return nil, err
+	}
+	ds, err := client.ProjectDeployments(v.GetString(_ProjectKey), v.GetString("application"), v.GetString("environment"), int(limit))
+	if err != nil {
+		return nil, err
+	}
+	return cli.AsListResult(ds), nil
+}
+
+var projectDeploymentRollbackCmd = cli.Command{
+	Name:  "rollback",
+	Short: "Redeploy a previous version of an application on an environment, with the artifacts of its workflow run",
+	Long: `Run again the deployment node of a previous successful deployment, in its workflow run, without rebuilding the artifacts.
+
+Without version nor deployment id, the version deployed before the current one is redeployed.`,
+	Example: `cdsctl project deployment rollback MYPROJ my-app production
+cdsctl project deployment rollback MYPROJ my-app production --version 1.2.3
+cdsctl project deployment rollback MYPROJ --deployment-id 1234`,
+	Ctx: []cli.Arg{
+		{Name: _ProjectKey},
+	},
+	OptionalArgs: []cli.Arg{
+		{Name: "application"},
+		{Name: "environment"},
+	},
+	Flags: []cli.Flag{
+		{
+			Name:  "version",
+			Usage: "Version to redeploy",
+		},
+		{
+			Name:  "deployment-id",
+			Usage: "Id of the deployment to redeploy, given by the deployment history",
+		},
+	},
+}
+
+func projectDeploymentRollbackRun(v cli.Values) (interface{}, error) {
+	req := sdk.DeploymentRollbackRequest{
+		ApplicationName: v.GetString("application"),
+		EnvironmentName: v.GetString("environment"),
+		Version:         v.GetString("version"),
+	}
+	if s := v.GetString("deployment-id"); s != "" {
+		id, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("deployment-id parameter have to be an integer")
+		}
+		req.DeploymentID = id
+	}
+	return client.ProjectDeploymentRollback(v.GetString(_ProjectKey), req)
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"github.com/ovh/cds/cli"
+)
+
+var projectDORACmd = cli.Command{
+	Name:  "dora",
+	Short: "Show the deployment frequency, lead time, change failure rate and time to restore of a CDS project",
+	Long: `Show the DORA metrics of the deployments of each application on each environment of a CDS project, over the last 30 days by default.
+
+The deployment frequency is the number of successful deployments per day, the lead time is the median time in seconds between
+the oldest commit of a deployment and its end, and the time to restore is the mean time in seconds between a failed deployment
+and the next successful one. A metric that cannot be computed is -1.`,
+	Ctx: []cli.Arg{
+		{Name: _ProjectKey},
+	},
+	Flags: []cli.Flag{
+		{
+			Name:  "application",
+			Usage: "Filter on an application name",
+		},
+		{
+			Name:  "environment",
+			Usage: "Filter on an environment name",
+		},
+		{
+			Name:  "from",
+			Usage: "First day of the period, formatted as YYYY-MM-DD, 30 days ago by default",
+		},
+		{
+			Name:  "to",
+			Usage: "Last day of the period, formatted as YYYY-MM-DD, today by default",
+		},
+	},
+}
+
+func projectDORARun(v cli.Values) (cli.ListResult, error) {
+	metrics, err := client.ProjectDORAMetrics(v.GetString(_ProjectKey), v.GetString("application"), v.GetString("environment"), v.GetString("from"), v.GetString("to"))
+	if err != nil {
+		return nil, err
+	}
+	return cli.AsListResult(metrics), nil
+}
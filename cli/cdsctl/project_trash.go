@@ -0,0 +1,87 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ovh/cds/cli"
+)
+
+var projectTrashCmd = cli.Command{
+	Name:  "trash",
+	Short: "Manage the deleted workflows and pipelines of a CDS project",
+	Long: `The deleted workflows and pipelines are kept in the trash of their project until they are purged,
+after the retention configured on the API (30 days by default).`,
+}
+
+func projectTrash() *cobra.Command {
+	return cli.NewCommand(projectTrashCmd, nil, []*cobra.Command{
+		cli.NewListCommand(projectTrashListCmd, projectTrashListRun, nil, withAllCommandModifiers()...),
+		cli.NewCommand(projectTrashRestoreCmd, projectTrashRestoreRun, nil, withAllCommandModifiers()...),
+		cli.NewCommand(projectTrashPurgeCmd, projectTrashPurgeRun, nil, withAllCommandModifiers()...),
+	})
+}
+
+var projectTrashListCmd = cli.Command{
+	Name:  "list",
+	Short: "List the workflows and pipelines of the trash",
+	Ctx: []cli.Arg{
+		{Name: _ProjectKey},
+	},
+}
+
+func projectTrashListRun(v cli.Values) (cli.ListResult, error) {
+	items, err := client.ProjectTrash(v.GetString(_ProjectKey))
+	if err != nil {
+		return nil, err
+	}
+	return cli.AsListResult(items), nil
+}
+
+var projectTrashRestoreCmd = cli.Command{
+	Name:  "restore",
+	Short: "Restore a workflow or a pipeline from the trash",
+	Long: `Restore a workflow or a pipeline from the trash, type is workflow or pipeline.
+
+The hooks of a restored workflow are started again, and the pipelines it uses are restored with it.`,
+	Ctx: []cli.Arg{
+		{Name: _ProjectKey},
+	},
+	Args: []cli.Arg{
+		{Name: "type"},
+		{Name: "name"},
+	},
+}
+
+func projectTrashRestoreRun(v cli.Values) error {
+	items, err := client.ProjectTrashRestore(v.GetString(_ProjectKey), v.GetString("type"), v.GetString("name"))
+	if err != nil {
+		return err
+	}
+	for _, it := range items {
+		fmt.Printf("%s %s restored\n", it.Type, it.Name)
+	}
+	return nil
+}
+
+var projectTrashPurgeCmd = cli.Command{
+	Name:  "purge",
+	Short: "Delete permanently a workflow or a pipeline of the trash",
+	Long:  `Delete permanently a workflow or a pipeline of the trash, type is workflow or pipeline. It cannot be restored anymore.`,
+	Ctx: []cli.Arg{
+		{Name: _ProjectKey},
+	},
+	Args: []cli.Arg{
+		{Name: "type"},
+		{Name: "name"},
+	},
+}
+
+func projectTrashPurgeRun(v cli.Values) error {
+	if err := client.ProjectTrashPurge(v.GetString(_ProjectKey), v.GetString("type"), v.GetString("name")); err != nil {
+		return err
+	}
+	fmt.Printf("%s %s will be deleted by the next purge\n", v.GetString("type"), v.GetString("name"))
+	return nil
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"strconv"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ovh/cds/cli"
+)
+
+var searchCmd = cli.Command{
+	Name:  "search",
+	Short: "Search projects, workflows, applications and runs",
+	Long: `Search among the projects you can read. The query is made of free text and of filters:
+
+* kind: project, workflow, application or run, several kinds can be separated by commas
+* project: key of a project
+* repo: repository of the applications, workflows and runs
+* branch: git branch of the runs
+* status: status of the runs`,
+	Example: `cdsctl search "kind:workflow repo:ovh/cds"
+cdsctl search "kind:run branch:master status:Fail"`,
+	Args: []cli.Arg{
+		{Name: "query"},
+	},
+	Flags: []cli.Flag{
+		{Name: "offset", Usage: "Index of the first result", Default: "0"},
+		{Name: "limit", Usage: "Number of results", Default: "20"},
+	},
+}
+
+func search() *cobra.Command {
+	return cli.NewListCommand(searchCmd, searchRun, nil)
+}
+
+func searchRun(v cli.Values) (cli.ListResult, error) {
+	offset, _ := strconv.Atoi(v.GetString("offset"))
+	limit, _ := strconv.Atoi(v.GetString("limit"))
+	res, err := client.Search(v.GetString("query"), offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	return cli.AsListResult(res.Results), nil
+}
@@ -22,6 +22,9 @@ func usr() *cobra.Command {
 		cli.NewCommand(userResetCmd, userResetRun, nil),
 		cli.NewCommand(userConfirmCmd, userConfirmRun, nil),
 		cli.NewCommand(userFavoriteCmd, userFavoriteRun, nil),
+		userAccessToken(),
+		userMFA(),
+		userVCS(),
 	})
 }
 
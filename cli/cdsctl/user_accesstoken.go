@@ -0,0 +1,194 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ovh/cds/cli"
+	"github.com/ovh/cds/sdk"
+)
+
+var userAccessTokenCmd = cli.Command{
+	Name:  "accesstoken",
+	Short: "Manage your personal access tokens",
+	Long: `
+Personal access tokens give access to the API in scripts with a subset of your permissions. Use them by exporting:
+
+	CDS_ACCESS_TOKEN=<token>
+`,
+}
+
+func userAccessToken() *cobra.Command {
+	return cli.NewCommand(userAccessTokenCmd, nil, []*cobra.Command{
+		cli.NewCommand(userAccessTokenCreateCmd, userAccessTokenCreateRun, nil),
+		cli.NewListCommand(userAccessTokenListCmd, userAccessTokenListRun, nil),
+		cli.NewCommand(userAccessTokenRestrictCmd, userAccessTokenRestrictRun, nil),
+		cli.NewDeleteCommand(userAccessTokenRevokeCmd, userAccessTokenRevokeRun, nil),
+	})
+}
+
+var userAccessTokenCreateCmd = cli.Command{
+	Name:  "create",
+	Short: "Create a personal access token",
+	Long: `
+Create a personal access token with the given scopes: ` + strings.Join(sdk.AccessTokenScopesAvailable, ", ") + `.
+
+The token is only displayed once, save it.
+	`,
+	Example: `cdsctl user accesstoken create "my script" read:project,run:workflow --expiration 720h`,
+	Args: []cli.Arg{
+		{Name: "description"},
+		{Name: "scopes"},
+	},
+	Flags: append([]cli.Flag{
+		{
+			Name:  "expiration",
+			Usage: "Duration of validity of the token (e.g. 720h), the token never expires by default",
+			IsValid: func(s string) bool {
+				if s == "" {
+					return true
+				}
+				_, err := time.ParseDuration(s)
+				return err == nil
+			},
+		},
+	}, userAccessTokenRestrictionFlags...),
+}
+
+var userAccessTokenRestrictionFlags = []cli.Flag{
+	{
+		Name:  "allowed-cidrs",
+		Usage: "Comma separated CIDRs of the addresses allowed to use the token",
+	},
+	{
+		Name:  "session-ttl",
+		Usage: "Minutes without request after which the session of an address is closed",
+	},
+	{
+		Name:  "max-sessions",
+		Usage: "Maximum number of addresses using the token at the same time",
+	},
+}
+
+func userAccessTokenRestrictions(v cli.Values) (sdk.AccessTokenRestrictions, error) {
+	var r sdk.AccessTokenRestrictions
+	if c := v.GetString("allowed-cidrs"); c != "" {
+		r.AllowedCIDRs = strings.Split(c, ",")
+	}
+	if ttl := v.GetString("session-ttl"); ttl != "" {
+		var err error
+		r.SessionTTL, err = strconv.ParseInt(ttl, 10, 64)
+		if err != nil {
+			return r, fmt.Errorf("invalid session ttl %s", ttl)
+		}
+	}
+	if max := v.GetString("max-sessions"); max != "" {
+		var err error
+		r.MaxSessions, err = strconv.Atoi(max)
+		if err != nil {
+			return r, fmt.Errorf("invalid max sessions %s", max)
+		}
+	}
+	return r, nil
+}
+
+func userAccessTokenCreateRun(v cli.Values) error {
+	var expiration time.Duration
+	if e := v.GetString("expiration"); e != "" {
+		var err error
+		expiration, err = time.ParseDuration(e)
+		if err != nil {
+			return err
+		}
+	}
+	restrictions, err := userAccessTokenRestrictions(v)
+	if err != nil {
+		return err
+	}
+	token, jwt, err := client.UserAccessTokenCreate(v.GetString("description"), strings.Split(v.GetString("scopes"), ","), expiration, restrictions)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Access token %s created with scopes %s\n", token.ID, strings.Join(token.Scopes, ","))
+	if token.ExpireAt != nil {
+		fmt.Printf("It expires at %s\n", token.ExpireAt.Format(time.RFC3339))
+	}
+	fmt.Println(jwt)
+	return nil
+}
+
+var userAccessTokenListCmd = cli.Command{
+	Name:  "list",
+	Short: "List your access tokens",
+}
+
+func userAccessTokenListRun(v cli.Values) (cli.ListResult, error) {
+	tokens, err := client.UserAccessTokenList()
+	if err != nil {
+		return nil, err
+	}
+	type accessToken struct {
+		ID          string `cli:"id,key"`
+		Description string `cli:"description"`
+		Scopes      string `cli:"scopes"`
+		Created     string `cli:"created"`
+		ExpireAt    string `cli:"expired_at"`
+		LastUsed    string `cli:"last_used"`
+	}
+	res := make([]accessToken, len(tokens))
+	for i, t := range tokens {
+		res[i] = accessToken{
+			ID:          t.ID,
+			Description: t.Description,
+			Scopes:      strings.Join(t.Scopes, ","),
+			Created:     t.Created.Format(time.RFC3339),
+		}
+		if t.ExpireAt != nil {
+			res[i].ExpireAt = t.ExpireAt.Format(time.RFC3339)
+		}
+		if t.LastUsed != nil {
+			res[i].LastUsed = t.LastUsed.Format(time.RFC3339)
+		}
+	}
+	return cli.AsListResult(res), nil
+}
+
+var userAccessTokenRestrictCmd = cli.Command{
+	Name:  "restrict",
+	Short: "Set the restrictions of an access token",
+	Long: `
+Set the restrictions of an access token, the restrictions not given are removed. The requests denied by the
+restrictions are recorded in the audit log.
+	`,
+	Example: `cdsctl user accesstoken restrict <id> --allowed-cidrs 10.0.0.0/8 --max-sessions 2`,
+	Args: []cli.Arg{
+		{Name: "id"},
+	},
+	Flags: userAccessTokenRestrictionFlags,
+}
+
+func userAccessTokenRestrictRun(v cli.Values) error {
+	restrictions, err := userAccessTokenRestrictions(v)
+	if err != nil {
+		return err
+	}
+	_, err = client.UserAccessTokenRestrict(v.GetString("id"), restrictions)
+	return err
+}
+
+var userAccessTokenRevokeCmd = cli.Command{
+	Name:    "revoke",
+	Short:   "Revoke an access token",
+	Aliases: []string{"delete", "remove"},
+	Args: []cli.Arg{
+		{Name: "id"},
+	},
+}
+
+func userAccessTokenRevokeRun(v cli.Values) error {
+	return client.UserAccessTokenRevoke(v.GetString("id"))
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ovh/cds/cli"
+	"github.com/ovh/cds/sdk"
+)
+
+var userMFACmd = cli.Command{
+	Name:  "mfa",
+	Short: "Manage your two-factor authentication",
+}
+
+func userMFA() *cobra.Command {
+	return cli.NewCommand(userMFACmd, nil, []*cobra.Command{
+		cli.NewCommand(userMFAVerifyCmd, userMFAVerifyRun, nil),
+	})
+}
+
+var userMFAVerifyCmd = cli.Command{
+	Name:  "verify",
+	Short: "Give your second factor to run sensitive operations",
+	Long: `
+Sensitive operations like the deletion of keys need a step-up verification when two-factor authentication is enabled
+on your account. Once verified, your session can run them for some minutes.
+`,
+	OptionalArgs: []cli.Arg{
+		{Name: "code"},
+	},
+}
+
+func userMFAVerifyRun(v cli.Values) error {
+	code := v.GetString("code")
+	if code == "" {
+		fmt.Printf("Authentication code (or recovery code): ")
+		code = cli.ReadLine()
+	}
+	mfa, err := sdk.ParseMFAHeader(code)
+	if err != nil {
+		return err
+	}
+	if err := client.UserMFAVerify(mfa); err != nil {
+		return err
+	}
+	fmt.Println("Verified")
+	return nil
+}
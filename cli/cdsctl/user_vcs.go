@@ -0,0 +1,90 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ovh/cds/cli"
+)
+
+var userVCSCmd = cli.Command{
+	Name:  "vcs",
+	Short: "Manage your accounts on the repositories managers",
+	Long: `
+The commands written in the comments of the pull requests, like "/cds run" or "/cds retry", are run with the
+permissions of the CDS user linked to the author of the comment. An account can only be linked to one user.
+`,
+}
+
+func userVCS() *cobra.Command {
+	return cli.NewCommand(userVCSCmd, nil, []*cobra.Command{
+		cli.NewListCommand(userVCSListCmd, userVCSListRun, nil),
+		cli.NewCommand(userVCSLinkCmd, userVCSLinkRun, nil),
+		cli.NewDeleteCommand(userVCSUnlinkCmd, userVCSUnlinkRun, nil),
+	})
+}
+
+var userVCSListCmd = cli.Command{
+	Name:  "list",
+	Short: "List your accounts on the repositories managers",
+}
+
+func userVCSListRun(v cli.Values) (cli.ListResult, error) {
+	as, err := client.UserVCSAccountList(cfg.User)
+	if err != nil {
+		return nil, err
+	}
+	return cli.AsListResult(as), nil
+}
+
+var userVCSLinkCmd = cli.Command{
+	Name:  "link",
+	Short: "Link your account on a repositories manager",
+	Long: `
+Open the given URL to authorize CDS with your account on the repositories manager. If the repositories manager gives
+you a verification code, enter it to end the link.
+`,
+	Example: `cdsctl user vcs link github`,
+	Args: []cli.Arg{
+		{Name: "vcs-server"},
+	},
+}
+
+func userVCSLinkRun(v cli.Values) error {
+	res, err := client.UserVCSAccountAuthorize(cfg.User, v.GetString("vcs-server"))
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Open this URL to authorize CDS with your account on %s:\n%s\n", v.GetString("vcs-server"), res["url"])
+	fmt.Print("Verification code (leave empty if your browser was redirected to CDS): ")
+	verifier := strings.TrimSpace(cli.ReadLine())
+	if verifier == "" {
+		return nil
+	}
+
+	a, err := client.UserVCSAccountAuthorizeCallback(cfg.User, v.GetString("vcs-server"), res["request_token"], verifier)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Account %s of %s linked with id %d\n", a.Username, a.VCSServer, a.ID)
+	return nil
+}
+
+var userVCSUnlinkCmd = cli.Command{
+	Name:  "unlink",
+	Short: "Unlink an account on a repositories manager",
+	Args: []cli.Arg{
+		{Name: "id"},
+	},
+}
+
+func userVCSUnlinkRun(v cli.Values) error {
+	id, err := strconv.ParseInt(v.GetString("id"), 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid id %s", v.GetString("id"))
+	}
+	return client.UserVCSAccountUnlink(cfg.User, id)
+}
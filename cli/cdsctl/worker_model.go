@@ -128,9 +128,19 @@ var workerModelShowCmd = cli.Command{
 	Args: []cli.Arg{
 		{Name: "name"},
 	},
+	Flags: []cli.Flag{
+		{
+			Name:  "resolved",
+			Usage: "Show the model with the fields inherited from its base model, as given to the hatcheries",
+			Type:  cli.FlagBool,
+		},
+	},
 }
 
 func workerModelShowRun(v cli.Values) (interface{}, error) {
+	if v.GetBool("resolved") {
+		return client.WorkerModelResolved(v.GetString("name"))
+	}
 	wm, err := client.WorkerModel(v.GetString("name"))
 	if err != nil {
 		return nil, err
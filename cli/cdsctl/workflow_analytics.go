@@ -0,0 +1,72 @@
+package main
+
+import (
+	"strconv"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ovh/cds/cli"
+	"github.com/ovh/cds/sdk"
+)
+
+var workflowAnalyticsCmd = cli.Command{
+	Name:  "analytics",
+	Short: "Show where the build time of a Workflow goes",
+}
+
+func workflowAnalytics() *cobra.Command {
+	return cli.NewCommand(workflowAnalyticsCmd, nil, []*cobra.Command{
+		cli.NewListCommand(workflowAnalyticsDurationsCmd, workflowAnalyticsDurationsRun, nil, withAllCommandModifiers()...),
+		cli.NewListCommand(workflowAnalyticsStepsCmd, workflowAnalyticsStepsRun, nil, withAllCommandModifiers()...),
+	})
+}
+
+var workflowAnalyticsDurationsCmd = cli.Command{
+	Name:  "durations",
+	Short: "Show the median and 95th percentile durations of the pipelines of a Workflow over time",
+	Ctx: []cli.Arg{
+		{Name: _ProjectKey},
+		{Name: _WorkflowName},
+	},
+	Flags: []cli.Flag{
+		{Name: "pipeline", Usage: "Filter on a pipeline"},
+		{Name: "stages", Usage: "Show the durations of the stages", Type: cli.FlagBool},
+		{Name: "interval", Usage: "Period of aggregation: day, week or month", Default: "day"},
+		{Name: "days", Usage: "Number of days to analyze", Default: "30"},
+	},
+}
+
+func workflowAnalyticsDurationsRun(v cli.Values) (cli.ListResult, error) {
+	days, _ := strconv.Atoi(v.GetString("days"))
+	kind := sdk.DurationKindPipeline
+	if v.GetBool("stages") {
+		kind = sdk.DurationKindStage
+	}
+	stats, err := client.WorkflowDurationsAnalytics(v.GetString(_ProjectKey), v.GetString(_WorkflowName), kind, v.GetString("pipeline"), v.GetString("interval"), days)
+	if err != nil {
+		return nil, err
+	}
+	return cli.AsListResult(stats), nil
+}
+
+var workflowAnalyticsStepsCmd = cli.Command{
+	Name:  "steps",
+	Short: "Show the slowest steps of a Workflow",
+	Ctx: []cli.Arg{
+		{Name: _ProjectKey},
+		{Name: _WorkflowName},
+	},
+	Flags: []cli.Flag{
+		{Name: "pipeline", Usage: "Filter on a pipeline"},
+		{Name: "days", Usage: "Number of days to analyze", Default: "30"},
+	},
+}
+
+func workflowAnalyticsStepsRun(v cli.Values) (cli.ListResult, error) {
+	days, _ := strconv.Atoi(v.GetString("days"))
+	steps, err := client.WorkflowSlowestSteps(v.GetString(_ProjectKey), v.GetString(_WorkflowName), v.GetString("pipeline"), days)
+	if err != nil {
+		return nil, err
+	}
+	return cli.AsListResult(steps), nil
+}
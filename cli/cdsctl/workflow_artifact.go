@@ -1,7 +1,9 @@
 package main
 
 import (
+	"encoding/json"
 	"fmt"
+	"io/ioutil"
 	"os"
 	"regexp"
 	"strconv"
@@ -21,6 +23,8 @@ func workflowArtifact() *cobra.Command {
 	return cli.NewCommand(workflowArtifactCmd, nil, []*cobra.Command{
 		cli.NewListCommand(workflowArtifactListCmd, workflowArtifactListRun, nil, withAllCommandModifiers()...),
 		cli.NewCommand(workflowArtifactDownloadCmd, workflowArtifactDownloadRun, nil, withAllCommandModifiers()...),
+		cli.NewCommand(workflowArtifactVerifyCmd, workflowArtifactVerifyRun, nil, withAllCommandModifiers()...),
+		cli.NewCommand(workflowArtifactProvenanceCmd, workflowArtifactProvenanceRun, nil, withAllCommandModifiers()...),
 	})
 }
 
@@ -139,3 +143,161 @@ func workflowArtifactDownloadRun(v cli.Values) error {
 	}
 	return nil
 }
+
+var workflowArtifactVerifyCmd = cli.Command{
+	Name:  "verify",
+	Short: "Verify the signatures of the artifacts of one Workflow Run",
+	Long: `Verify the signatures of the checksums of the artifacts with the PGP keys of the project.
+If an artifact has been downloaded in the current directory, its checksums are also verified.`,
+	Ctx: []cli.Arg{
+		{Name: _ProjectKey},
+		{Name: _WorkflowName},
+	},
+	Args: []cli.Arg{
+		{Name: "number"},
+	},
+	OptionalArgs: []cli.Arg{
+		{Name: "artefact-name"},
+	},
+	Flags: []cli.Flag{
+		{
+			Name:  "require-signature",
+			Usage: "fail if an artifact is not signed",
+			Type:  cli.FlagBool,
+		},
+	},
+}
+
+func workflowArtifactVerifyRun(v cli.Values) error {
+	number, err := strconv.ParseInt(v.GetString("number"), 10, 64)
+	if err != nil {
+		return fmt.Errorf("number parameter have to be an integer")
+	}
+
+	artifacts, err := client.WorkflowRunArtifacts(v.GetString(_ProjectKey), v.GetString(_WorkflowName), number)
+	if err != nil {
+		return err
+	}
+
+	var nb, nbFailed int
+	for _, a := range artifacts {
+		if v.GetString("artefact-name") != "" && v.GetString("artefact-name") != a.Name {
+			continue
+		}
+		nb++
+
+		verification, err := client.WorkflowNodeRunArtifactVerify(v.GetString(_ProjectKey), v.GetString(_WorkflowName), a.ID)
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case verification.Verified:
+			fmt.Printf("%s: signature OK, signed with key %s (%s)\n", a.Name, verification.SigningKey, verification.KeyID)
+		case verification.Signed:
+			fmt.Printf("%s: signature KO: %s\n", a.Name, verification.Error)
+			nbFailed++
+		case v.GetBool("require-signature"):
+			fmt.Printf("%s: not signed\n", a.Name)
+			nbFailed++
+		default:
+			fmt.Printf("%s: not signed\n", a.Name)
+		}
+
+		if _, err := os.Stat(a.Name); err != nil {
+			continue
+		}
+		if err := verifyArtifactChecksums(a); err != nil {
+			fmt.Printf("%s: %v\n", a.Name, err)
+			nbFailed++
+			continue
+		}
+		fmt.Printf("%s: local file checksum OK\n", a.Name)
+	}
+
+	if nb == 0 {
+		return fmt.Errorf("No artifact found")
+	}
+	if nbFailed > 0 {
+		return fmt.Errorf("%d/%d artifacts are not verified", nbFailed, nb)
+	}
+	return nil
+}
+
+var workflowArtifactProvenanceCmd = cli.Command{
+	Name:  "provenance",
+	Short: "Download the provenance of the artifacts of one Workflow Run",
+	Long: `Download the SLSA provenance of the artifacts, signed by the CDS instance, in the current directory.
+The provenance of an artifact is written in <artifact name>.intoto.jsonl, as a DSSE envelope.`,
+	Ctx: []cli.Arg{
+		{Name: _ProjectKey},
+		{Name: _WorkflowName},
+	},
+	Args: []cli.Arg{
+		{Name: "number"},
+	},
+	OptionalArgs: []cli.Arg{
+		{Name: "artefact-name"},
+	},
+}
+
+func workflowArtifactProvenanceRun(v cli.Values) error {
+	number, err := strconv.ParseInt(v.GetString("number"), 10, 64)
+	if err != nil {
+		return fmt.Errorf("number parameter have to be an integer")
+	}
+
+	artifacts, err := client.WorkflowRunArtifacts(v.GetString(_ProjectKey), v.GetString(_WorkflowName), number)
+	if err != nil {
+		return err
+	}
+
+	var ok bool
+	for _, a := range artifacts {
+		if v.GetString("artefact-name") != "" && v.GetString("artefact-name") != a.Name {
+			continue
+		}
+		env, err := client.WorkflowNodeRunArtifactProvenance(v.GetString(_ProjectKey), v.GetString(_WorkflowName), a.ID)
+		if err != nil {
+			return fmt.Errorf("cannot get provenance of %s: %v", a.Name, err)
+		}
+		btes, err := json.Marshal(env)
+		if err != nil {
+			return err
+		}
+		fileName := a.Name + ".intoto.jsonl"
+		if err := ioutil.WriteFile(fileName, append(btes, '\n'), 0644); err != nil {
+			return err
+		}
+		fmt.Printf("File %s created, %d signature(s)\n", fileName, len(env.Signatures))
+		ok = true
+	}
+
+	if !ok {
+		return fmt.Errorf("No artifact found")
+	}
+	return nil
+}
+
+// verifyArtifactChecksums compares the checksums of a local file with the ones of the artifact
+func verifyArtifactChecksums(a sdk.WorkflowNodeRunArtifact) error {
+	if a.SHA256sum != "" {
+		sha256sum, err := sdk.FileSHA256sum(a.Name)
+		if err != nil {
+			return err
+		}
+		if sha256sum != a.SHA256sum {
+			return fmt.Errorf("invalid sha256sum %s, expected %s", sha256sum, a.SHA256sum)
+		}
+	}
+	if a.SHA512sum != "" {
+		sha512sum, err := sdk.FileSHA512sum(a.Name)
+		if err != nil {
+			return err
+		}
+		if sha512sum != a.SHA512sum {
+			return fmt.Errorf("invalid sha512sum %s, expected %s", sha512sum, a.SHA512sum)
+		}
+	}
+	return nil
+}
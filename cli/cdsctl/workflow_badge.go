@@ -0,0 +1,90 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ovh/cds/cli"
+	"github.com/ovh/cds/sdk"
+)
+
+var workflowBadgeCmd = cli.Command{
+	Name:  "badge",
+	Short: "Manage Workflow badges",
+	Long: `The badges of a workflow are SVG images showing the status of its last run, its code coverage and its last released version, to embed in a README file.
+
+They can be fetched without authentication. Protected badges require the token included in their urls.`,
+}
+
+func workflowBadge() *cobra.Command {
+	return cli.NewCommand(workflowBadgeCmd, nil, []*cobra.Command{
+		cli.NewCommand(workflowBadgeShowCmd, workflowBadgeShowRun, nil, withAllCommandModifiers()...),
+		cli.NewCommand(workflowBadgeEnableCmd, workflowBadgeEnableRun, nil, withAllCommandModifiers()...),
+		cli.NewCommand(workflowBadgeDisableCmd, workflowBadgeDisableRun, nil, withAllCommandModifiers()...),
+	})
+}
+
+var workflowBadgeShowCmd = cli.Command{
+	Name:  "show",
+	Short: "Show the urls of the badges of a Workflow",
+	Ctx: []cli.Arg{
+		{Name: _ProjectKey},
+		{Name: _WorkflowName},
+	},
+}
+
+func workflowBadgeShowRun(v cli.Values) error {
+	badge, err := client.WorkflowBadgeGet(v.GetString(_ProjectKey), v.GetString(_WorkflowName))
+	if err != nil {
+		return err
+	}
+	printBadgeURLs(badge)
+	return nil
+}
+
+var workflowBadgeEnableCmd = cli.Command{
+	Name:  "enable",
+	Short: "Enable the badges of a Workflow",
+	Long:  "Enable the badges of a Workflow. Enabling them again generates a new token for protected badges, the previous urls stop working.",
+	Ctx: []cli.Arg{
+		{Name: _ProjectKey},
+		{Name: _WorkflowName},
+	},
+	Flags: []cli.Flag{
+		{
+			Name:    "protected",
+			Usage:   "Require a token to get the badges",
+			Default: "false",
+			Type:    cli.FlagBool,
+		},
+	},
+}
+
+func workflowBadgeEnableRun(v cli.Values) error {
+	badge, err := client.WorkflowBadgeEnable(v.GetString(_ProjectKey), v.GetString(_WorkflowName), v.GetBool("protected"))
+	if err != nil {
+		return err
+	}
+	printBadgeURLs(badge)
+	return nil
+}
+
+var workflowBadgeDisableCmd = cli.Command{
+	Name:  "disable",
+	Short: "Disable the badges of a Workflow",
+	Ctx: []cli.Arg{
+		{Name: _ProjectKey},
+		{Name: _WorkflowName},
+	},
+}
+
+func workflowBadgeDisableRun(v cli.Values) error {
+	return client.WorkflowBadgeDisable(v.GetString(_ProjectKey), v.GetString(_WorkflowName))
+}
+
+func printBadgeURLs(badge *sdk.WorkflowBadge) {
+	for _, t := range sdk.BadgeTypes {
+		fmt.Printf("%s: %s\n", t, badge.URLs[t])
+	}
+}
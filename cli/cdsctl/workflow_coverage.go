@@ -0,0 +1,90 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ovh/cds/cli"
+)
+
+var workflowCoverageCmd = cli.Command{
+	Name:  "coverage",
+	Short: "Manage Workflow code coverage",
+}
+
+func workflowCoverage() *cobra.Command {
+	return cli.NewCommand(workflowCoverageCmd, nil, []*cobra.Command{
+		cli.NewListCommand(workflowCoverageTrendCmd, workflowCoverageTrendRun, nil, withAllCommandModifiers()...),
+		cli.NewListCommand(workflowCoverageDiffCmd, workflowCoverageDiffRun, nil, withAllCommandModifiers()...),
+	})
+}
+
+var workflowCoverageTrendCmd = cli.Command{
+	Name:  "trend",
+	Short: "Show the code coverage of the last runs of a Workflow",
+	Ctx: []cli.Arg{
+		{Name: _ProjectKey},
+		{Name: _WorkflowName},
+	},
+	Flags: []cli.Flag{
+		{Name: "application", Usage: "Filter on an application"},
+		{Name: "branch", Usage: "Filter on a git branch"},
+	},
+}
+
+func workflowCoverageTrendRun(v cli.Values) (cli.ListResult, error) {
+	trend, err := client.WorkflowCoverageTrend(v.GetString(_ProjectKey), v.GetString(_WorkflowName), v.GetString("application"), v.GetString("branch"))
+	if err != nil {
+		return nil, err
+	}
+	return cli.AsListResult(trend), nil
+}
+
+var workflowCoverageDiffCmd = cli.Command{
+	Name:  "diff",
+	Short: "Compare the code coverage of a Workflow Run with the previous run and the default branch",
+	Ctx: []cli.Arg{
+		{Name: _ProjectKey},
+		{Name: _WorkflowName},
+	},
+	Args: []cli.Arg{
+		{Name: "number"},
+	},
+}
+
+type workflowCoverageDiffDisplay struct {
+	Node              string `cli:"node"`
+	Branch            string `cli:"branch"`
+	Lines             string `cli:"lines"`
+	DiffPrevious      string `cli:"diff_previous"`
+	DiffDefaultBranch string `cli:"diff_default_branch"`
+}
+
+func workflowCoverageDiffRun(v cli.Values) (cli.ListResult, error) {
+	number, err := strconv.ParseInt(v.GetString("number"), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("number parameter have to be an integer")
+	}
+	diffs, err := client.WorkflowRunCoverage(v.GetString(_ProjectKey), v.GetString(_WorkflowName), number)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]workflowCoverageDiffDisplay, len(diffs))
+	for i, d := range diffs {
+		res[i] = workflowCoverageDiffDisplay{
+			Node:   d.WorkflowNodeName,
+			Branch: d.Branch,
+			Lines:  fmt.Sprintf("%.2f%%", d.Current.LinesPercent),
+		}
+		if d.DiffPrevious != nil {
+			res[i].DiffPrevious = fmt.Sprintf("%+.2f%%", *d.DiffPrevious)
+		}
+		if d.DiffDefaultBranch != nil {
+			res[i].DiffDefaultBranch = fmt.Sprintf("%+.2f%%", *d.DiffDefaultBranch)
+		}
+	}
+	return cli.AsListResult(res), nil
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ovh/cds/cli"
+	"github.com/ovh/cds/sdk"
+)
+
+var workflowFilterCmd = cli.Command{
+	Name:  "filter",
+	Short: "Manage your saved filters on the CDS workflows of a project",
+}
+
+func workflowFilter() *cobra.Command {
+	return cli.NewCommand(workflowFilterCmd, nil, []*cobra.Command{
+		cli.NewListCommand(workflowFilterListCmd, workflowFilterListRun, nil, withAllCommandModifiers()...),
+		cli.NewCommand(workflowFilterSaveCmd, workflowFilterSaveRun, nil, withAllCommandModifiers()...),
+		cli.NewDeleteCommand(workflowFilterDeleteCmd, workflowFilterDeleteRun, nil, withAllCommandModifiers()...),
+	})
+}
+
+var workflowFilterListCmd = cli.Command{
+	Name:  "list",
+	Short: "List your saved filters on the CDS workflows of a project",
+	Ctx: []cli.Arg{
+		{Name: _ProjectKey},
+	},
+}
+
+func workflowFilterListRun(v cli.Values) (cli.ListResult, error) {
+	fs, err := client.WorkflowSavedFilterList(v.GetString(_ProjectKey))
+	if err != nil {
+		return nil, err
+	}
+	return cli.AsListResult(fs), nil
+}
+
+var workflowFilterSaveCmd = cli.Command{
+	Name:    "save",
+	Short:   "Save a filter on the CDS workflows of a project, to use it with workflow list --filter",
+	Example: "cdsctl workflow filter save MYPROJ backend-failed --label backend --status Fail",
+	Ctx: []cli.Arg{
+		{Name: _ProjectKey},
+	},
+	Args: []cli.Arg{
+		{Name: "filter-name"},
+	},
+	Flags: workflowFilterFlags,
+}
+
+func workflowFilterSaveRun(v cli.Values) error {
+	return client.WorkflowSavedFilterCreate(v.GetString(_ProjectKey), &sdk.WorkflowSavedFilter{
+		Name:   v.GetString("filter-name"),
+		Filter: workflowFilterFromValues(v),
+	})
+}
+
+var workflowFilterDeleteCmd = cli.Command{
+	Name:  "delete",
+	Short: "Delete one of your saved filters on the CDS workflows of a project",
+	Ctx: []cli.Arg{
+		{Name: _ProjectKey},
+	},
+	Args: []cli.Arg{
+		{Name: "filter-name"},
+	},
+}
+
+func workflowFilterDeleteRun(v cli.Values) error {
+	err := client.WorkflowSavedFilterDelete(v.GetString(_ProjectKey), v.GetString("filter-name"))
+	if err != nil && v.GetBool("force") && sdk.ErrorIs(err, sdk.ErrNotFound) {
+		fmt.Println(err.Error())
+		return nil
+	}
+	return err
+}
+
+var workflowLabelsCmd = cli.Command{
+	Name:    "labels",
+	Short:   "Add and remove labels on several CDS workflows of a project, the missing labels are created",
+	Example: "cdsctl workflow labels MYPROJ my-workflow my-other-workflow --add backend --remove legacy",
+	Ctx: []cli.Arg{
+		{Name: _ProjectKey},
+	},
+	VariadicArgs: cli.Arg{
+		Name: "workflow-name",
+	},
+	Flags: []cli.Flag{
+		{
+			Name:  "add",
+			Usage: "Label to add, can be repeated",
+			Type:  cli.FlagArray,
+		},
+		{
+			Name:  "remove",
+			Usage: "Label to remove, can be repeated",
+			Type:  cli.FlagArray,
+		},
+	},
+}
+
+func workflowLabelsRun(v cli.Values) (cli.ListResult, error) {
+	names, err := client.WorkflowLabels(v.GetString(_ProjectKey), sdk.WorkflowLabelsRequest{
+		Workflows: strings.Split(v.GetString("workflow-name"), ","),
+		Add:       v.GetStringArray("add"),
+		Remove:    v.GetStringArray("remove"),
+	})
+	if err != nil {
+		return nil, err
+	}
+	type workflowLabels struct {
+		Name   string `cli:"name,key"`
+		Labels string `cli:"labels"`
+	}
+	res := make([]workflowLabels, len(names))
+	for i, n := range names {
+		res[i].Name = n.Name
+		for j, l := range n.Labels {
+			if j > 0 {
+				res[i].Labels += ","
+			}
+			res[i].Labels += l.Name
+		}
+	}
+	return cli.AsListResult(res), nil
+}
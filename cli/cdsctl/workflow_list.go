@@ -1,6 +1,44 @@
 package main
 
-import "github.com/ovh/cds/cli"
+import (
+	"github.com/ovh/cds/cli"
+	"github.com/ovh/cds/sdk"
+)
+
+var workflowFilterFlags = []cli.Flag{
+	{
+		Name:  "label",
+		Usage: "Label of the workflows, can be repeated to match all the given labels",
+		Type:  cli.FlagArray,
+	},
+	{
+		Name:  "status",
+		Usage: "Status of the last run of the workflows",
+	},
+	{
+		Name:  "repository",
+		Usage: "Repository of the as code files or of an application of the workflows",
+	},
+	{
+		Name:  "template",
+		Usage: "Template the workflows were generated from, as group-name/template-slug",
+	},
+	{
+		Name:  "favorite",
+		Usage: "Only your favorite workflows",
+		Type:  cli.FlagBool,
+	},
+}
+
+func workflowFilterFromValues(v cli.Values) sdk.WorkflowFilter {
+	return sdk.WorkflowFilter{
+		Labels:     v.GetStringArray("label"),
+		Status:     v.GetString("status"),
+		Repository: v.GetString("repository"),
+		Template:   v.GetString("template"),
+		Favorite:   v.GetBool("favorite"),
+	}
+}
 
 var workflowListCmd = cli.Command{
 	Name:  "list",
@@ -8,10 +46,22 @@ var workflowListCmd = cli.Command{
 	Ctx: []cli.Arg{
 		{Name: _ProjectKey},
 	},
+	Flags: append([]cli.Flag{
+		{
+			Name:  "filter",
+			Usage: "Name of one of your saved filters",
+		},
+	}, workflowFilterFlags...),
 }
 
 func workflowListRun(v cli.Values) (cli.ListResult, error) {
-	w, err := client.WorkflowList(v.GetString(_ProjectKey))
+	var w []sdk.Workflow
+	var err error
+	if name := v.GetString("filter"); name != "" {
+		w, err = client.WorkflowListBySavedFilter(v.GetString(_ProjectKey), name)
+	} else {
+		w, err = client.WorkflowListFiltered(v.GetString(_ProjectKey), workflowFilterFromValues(v))
+	}
 	if err != nil {
 		return nil, err
 	}
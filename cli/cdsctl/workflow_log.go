@@ -1,10 +1,14 @@
 package main
 
 import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
 	"fmt"
-	"io/ioutil"
+	"io"
+	"os"
+	"path/filepath"
 	"regexp"
-	"strings"
 
 	"github.com/spf13/cobra"
 
@@ -70,11 +74,22 @@ var workflowLogListCmd = cli.Command{
 	},
 }
 
+var workflowLogRunFlag = cli.Flag{
+	Name:  "run",
+	Usage: "Run number, the latest run by default",
+}
+
 func workflowLogSearchNumber(v cli.Values) (int64, error) {
-	runNumber, errRunNumber := v.GetInt64("run-number")
+	runNumber, errRunNumber := v.GetInt64("run")
 	if errRunNumber != nil {
 		return 0, errRunNumber
 	}
+	if runNumber == 0 {
+		runNumber, errRunNumber = v.GetInt64("run-number")
+		if errRunNumber != nil {
+			return 0, errRunNumber
+		}
+	}
 	if runNumber == 0 {
 		filters := []cdsclient.Filter{
 			{
@@ -115,29 +130,14 @@ func workflowLogListRun(v cli.Values) error {
 }
 
 type workflowLogDetail struct {
-	workflowName string
-	pipelineName string
-	stageName    string
-	status       string
-	jobName      string
-	runID        int64
-	jobID        int64
-	stepOrder    int
-	number       int64
-	subNumber    int64
+	filename  string
+	runID     int64
+	jobID     int64
+	stepOrder int
 }
 
 func (w workflowLogDetail) getFilename() string {
-	return fmt.Sprintf("%s-%d.%d-pipeline.%s-stage.%s-job.%s-status.%s-step.%d.log",
-		w.workflowName,
-		w.number,
-		w.subNumber,
-		w.pipelineName,
-		strings.Replace(w.stageName, " ", "", -1),
-		strings.Replace(w.jobName, " ", "", -1),
-		w.status,
-		w.stepOrder,
-	)
+	return w.filename
 }
 
 func workflowLogProcess(wr *sdk.WorkflowRun) []workflowLogDetail {
@@ -150,16 +150,10 @@ func workflowLogProcess(wr *sdk.WorkflowRun) []workflowLogDetail {
 					for _, step := range job.Job.StepStatus {
 						logs = append(logs,
 							workflowLogDetail{
-								workflowName: wr.Workflow.Name,
-								pipelineName: node.WorkflowNodeName,
-								stageName:    stage.Name,
-								jobName:      job.Job.Job.Action.Name,
-								jobID:        job.ID,
-								status:       job.Status,
-								stepOrder:    step.StepOrder,
-								runID:        node.ID,
-								number:       wr.Number,
-								subNumber:    wr.LastSubNumber,
+								filename:  sdk.WorkflowRunStepLogFilename(*wr, node, stage.Name, job, step.StepOrder),
+								jobID:     job.ID,
+								stepOrder: step.StepOrder,
+								runID:     node.ID,
 							})
 					}
 				}
@@ -180,6 +174,12 @@ var workflowLogDownloadCmd = cli.Command{
 	# download all logs files on run number 1
 	$ cdsctl workflow logs download KEY WF 1
 
+	# download all logs files on run number 1
+	$ cdsctl workflow logs download KEY WF --run 1
+
+	# download all logs files of run number 1 in an archive WF-1-logs.tar.gz
+	$ cdsctl workflow logs download KEY WF --run 1 --archive
+
 	# download only one file:
 	$ cdsctl workflow logs download KEY WF 1 --pattern="MyStage"
 	# this will download WF-1.0-pipeline.myPipeline-stage.MyStage-job.MyJob-status.Success-step.0.log for example
@@ -204,6 +204,12 @@ var workflowLogDownloadCmd = cli.Command{
 			Name:  "pattern",
 			Usage: "Filter on log filename",
 		},
+		workflowLogRunFlag,
+		{
+			Name:  "archive",
+			Type:  cli.FlagBool,
+			Usage: "Save all the logs files in a tar.gz archive instead of extracting them",
+		},
 	},
 }
 
@@ -215,40 +221,40 @@ func workflowLogDownloadRun(v cli.Values) error {
 
 	fmt.Printf("Downloding logs files from workflow %s run %d\n", v.GetString(_WorkflowName), runNumber)
 
+	if v.GetString("pattern") == "" {
+		return workflowLogDownloadArchive(v, runNumber)
+	}
+	if v.GetBool("archive") {
+		return fmt.Errorf("--archive cannot be used with --pattern")
+	}
+
+	reg, errp := regexp.Compile(v.GetString("pattern"))
+	if errp != nil {
+		return fmt.Errorf("Invalid pattern %s: %v", v.GetString("pattern"), errp)
+	}
+
 	wr, err := client.WorkflowRunGet(v.GetString(_ProjectKey), v.GetString(_WorkflowName), runNumber)
 	if err != nil {
 		return err
 	}
 	logs := workflowLogProcess(wr)
 
-	var reg *regexp.Regexp
-	if v.GetString("pattern") != "" {
-		var errp error
-		reg, errp = regexp.Compile(v.GetString("pattern"))
-		if errp != nil {
-			return fmt.Errorf("Invalid pattern %s: %v", v.GetString("pattern"), errp)
-		}
-	}
-
 	var ok bool
 	for _, log := range logs {
-		if v.GetString("pattern") != "" && !reg.MatchString(log.getFilename()) {
+		if !reg.MatchString(log.getFilename()) {
 			continue
 		}
 
-		buildState, err := client.WorkflowNodeRunJobStep(v.GetString(_ProjectKey),
-			v.GetString(_WorkflowName),
-			runNumber,
-			log.runID,
-			log.jobID,
-			log.stepOrder,
-		)
+		f, err := os.OpenFile(log.getFilename(), os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
 		if err != nil {
 			return err
 		}
-
-		d1 := []byte(buildState.StepLogs.Val)
-		if err := ioutil.WriteFile(log.getFilename(), d1, 0644); err != nil {
+		_, err = client.WorkflowNodeRunJobStepLogDownload(v.GetString(_ProjectKey), v.GetString(_WorkflowName), runNumber,
+			log.runID, log.jobID, log.stepOrder, 0, f)
+		if errC := f.Close(); err == nil {
+			err = errC
+		}
+		if err != nil {
 			return err
 		}
 		fmt.Printf("file %s created\n", log.getFilename())
@@ -260,3 +266,65 @@ func workflowLogDownloadRun(v cli.Values) error {
 	}
 	return nil
 }
+
+// workflowLogDownloadArchive downloads all the logs of a run in one archive, and extracts it unless
+// the archive flag is set
+func workflowLogDownloadArchive(v cli.Values, runNumber int64) error {
+	if v.GetBool("archive") {
+		filename := fmt.Sprintf("%s-%d-logs.tar.gz", v.GetString(_WorkflowName), runNumber)
+		f, err := os.OpenFile(filename, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+		if err != nil {
+			return err
+		}
+		err = client.WorkflowRunLogsDownload(v.GetString(_ProjectKey), v.GetString(_WorkflowName), runNumber, f)
+		if errC := f.Close(); err == nil {
+			err = errC
+		}
+		if err != nil {
+			return err
+		}
+		fmt.Printf("file %s created\n", filename)
+		return nil
+	}
+
+	buf := new(bytes.Buffer)
+	if err := client.WorkflowRunLogsDownload(v.GetString(_ProjectKey), v.GetString(_WorkflowName), runNumber, buf); err != nil {
+		return err
+	}
+	gr, err := gzip.NewReader(buf)
+	if err != nil {
+		return err
+	}
+	tr := tar.NewReader(gr)
+
+	var ok bool
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		filename := filepath.Base(hdr.Name)
+		f, err := os.OpenFile(filename, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+		if err != nil {
+			return err
+		}
+		_, err = io.Copy(f, tr)
+		if errC := f.Close(); err == nil {
+			err = errC
+		}
+		if err != nil {
+			return err
+		}
+		fmt.Printf("file %s created\n", filename)
+		ok = true
+	}
+
+	if !ok {
+		return fmt.Errorf("No log downloaded")
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/ovh/cds/cli"
+	"github.com/ovh/cds/sdk"
+)
+
+var workflowPriorityCmd = cli.Command{
+	Name:  "priority",
+	Short: "Change the priority of a CDS workflow run (admin only)",
+	Long: `Change the priority of a workflow run: low, normal, high or urgent.
+
+The jobs of the runs with the highest priority are started first. The jobs already building are not affected.`,
+	Example: `cdsctl workflow priority MYPROJECT myworkflow 5 urgent`,
+	Ctx: []cli.Arg{
+		{Name: _ProjectKey},
+		{Name: _WorkflowName},
+	},
+	Args: []cli.Arg{
+		{Name: "run-number"},
+		{Name: "priority"},
+	},
+}
+
+func workflowPriorityRun(v cli.Values) error {
+	number, err := strconv.ParseInt(v.GetString("run-number"), 10, 64)
+	if err != nil {
+		return fmt.Errorf("run-number invalid: not a integer")
+	}
+
+	var priority int
+	switch v.GetString("priority") {
+	case "low":
+		priority = sdk.WorkflowRunPriorityLow
+	case "normal":
+		priority = sdk.WorkflowRunPriorityNormal
+	case "high":
+		priority = sdk.WorkflowRunPriorityHigh
+	case "urgent":
+		priority = sdk.WorkflowRunPriorityUrgent
+	default:
+		return fmt.Errorf("invalid priority %s, it should be low, normal, high or urgent", v.GetString("priority"))
+	}
+
+	if _, err := client.WorkflowRunPriority(v.GetString(_ProjectKey), v.GetString(_WorkflowName), number, priority); err != nil {
+		return err
+	}
+	fmt.Printf("Workflow %s #%d priority set to %s\n", v.GetString(_WorkflowName), number, v.GetString("priority"))
+	return nil
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ovh/cds/cli"
+	"github.com/ovh/cds/sdk"
+)
+
+var workflowPromotionCmd = cli.Command{
+	Name:  "promotion",
+	Short: "Manage Workflow promotions between CDS instances",
+	Long: `A workflow and its pipelines, applications and environments can be promoted from a CDS instance to another one, for example from a development instance to the production one. The remote instances are configured on the API by an administrator.
+
+The promotions are recorded on the target instance, with the diff of the workflow and its dependencies.`,
+}
+
+func workflowPromotion() *cobra.Command {
+	return cli.NewCommand(workflowPromotionCmd, nil, []*cobra.Command{
+		cli.NewGetCommand(workflowPromotionPushCmd, workflowPromotionPushRun, nil, withAllCommandModifiers()...),
+		cli.NewListCommand(workflowPromotionListCmd, workflowPromotionListRun, nil, withAllCommandModifiers()...),
+		cli.NewCommand(workflowPromotionDiffCmd, workflowPromotionDiffRun, nil, withAllCommandModifiers()...),
+	})
+}
+
+var workflowPromotionPushCmd = cli.Command{
+	Name:  "push",
+	Short: "Promote a Workflow to a remote CDS instance",
+	Long: `Promote a Workflow to a remote CDS instance, only an administrator can promote a workflow:
+
+	cdsctl workflow promotion push MYPROJ my-workflow prod --comment "release 1.2"
+`,
+	Ctx: []cli.Arg{
+		{Name: _ProjectKey},
+		{Name: _WorkflowName},
+	},
+	Args: []cli.Arg{
+		{Name: "remote"},
+	},
+	Flags: []cli.Flag{
+		{
+			Name:  "target-project",
+			Usage: "Key of the project on the remote instance, default to the key of the project",
+		},
+		{
+			Name:  "comment",
+			Usage: "Comment of the promotion",
+		},
+	},
+}
+
+func workflowPromotionPushRun(v cli.Values) (interface{}, error) {
+	p, err := client.WorkflowPromote(v.GetString(_ProjectKey), v.GetString(_WorkflowName), sdk.WorkflowPromotionRequest{
+		Remote:  v.GetString("remote"),
+		Project: v.GetString("target-project"),
+		Comment: v.GetString("comment"),
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, m := range p.Messages {
+		fmt.Println(m)
+	}
+	return p, nil
+}
+
+var workflowPromotionListCmd = cli.Command{
+	Name:  "list",
+	Short: "List the promotions of a Workflow from other CDS instances",
+	Ctx: []cli.Arg{
+		{Name: _ProjectKey},
+		{Name: _WorkflowName},
+	},
+}
+
+func workflowPromotionListRun(v cli.Values) (cli.ListResult, error) {
+	ps, err := client.WorkflowPromotionList(v.GetString(_ProjectKey), v.GetString(_WorkflowName))
+	if err != nil {
+		return nil, err
+	}
+	return cli.AsListResult(ps), nil
+}
+
+var workflowPromotionDiffCmd = cli.Command{
+	Name:  "diff",
+	Short: "Show the diff of a promotion of a Workflow",
+	Ctx: []cli.Arg{
+		{Name: _ProjectKey},
+		{Name: _WorkflowName},
+	},
+	Args: []cli.Arg{
+		{Name: "id"},
+	},
+}
+
+func workflowPromotionDiffRun(v cli.Values) error {
+	id, err := v.GetInt64("id")
+	if err != nil {
+		return err
+	}
+	p, err := client.WorkflowPromotionGet(v.GetString(_ProjectKey), v.GetString(_WorkflowName), id)
+	if err != nil {
+		return err
+	}
+	if p.Diff == "" {
+		fmt.Println("No change")
+		return nil
+	}
+	fmt.Print(p.Diff)
+	return nil
+}
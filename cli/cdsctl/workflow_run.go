@@ -73,6 +73,11 @@ var workflowRunManualCmd = cli.Command{
 			Usage:     "Follow the workflow run in an interactive terminal user interface",
 			Type:      cli.FlagBool,
 		},
+		{
+			Name:  "follow",
+			Usage: "Follow the workflow run: print the status and logs of its jobs until it ends and exit with an error code if it does not succeed",
+			Type:  cli.FlagBool,
+		},
 		{
 			Name:      "open-web-browser",
 			ShortHand: "o",
@@ -171,6 +176,10 @@ func workflowRunManualRun(v cli.Values) error {
 
 	fmt.Printf("Workflow %s #%d has been launched\n", v.GetString(_WorkflowName), w.Number)
 
+	if v.GetBool("follow") {
+		return workflowRunFollow(v, w.Number)
+	}
+
 	var baseURL string
 	configUser, err := client.ConfigUser()
 	if err != nil {
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ovh/cds/cli"
+	"github.com/ovh/cds/sdk"
+)
+
+// workflowRunFollow prints the status of the nodes, jobs and steps of a workflow run and the logs of its
+// steps as they change, until the run is terminated. Events of the API trigger a refresh of the run, a
+// ticker refreshes it anyway if the events stream is not available.
+// An error with an exit code is returned if the run does not succeed so that it can be used in scripts.
+func workflowRunFollow(v cli.Values, number int64) error {
+	projectKey, workflowName := v.GetString(_ProjectKey), v.GetString(_WorkflowName)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events := make(chan sdk.Event)
+	go client.EventsListen(ctx, events)
+
+	ticker := time.NewTicker(5 * time.Second)
+	defer ticker.Stop()
+
+	f := workflowRunFollower{
+		projectKey:   projectKey,
+		workflowName: workflowName,
+		statuses:     map[string]string{},
+		logs:         map[string]int{},
+		done:         map[string]bool{},
+	}
+	for {
+		run, err := client.WorkflowRunGet(projectKey, workflowName, number)
+		if err != nil {
+			return err
+		}
+		if err := f.print(run); err != nil {
+			return err
+		}
+		if sdk.StatusIsTerminated(run.Status) {
+			return workflowRunFollowResult(run)
+		}
+
+	wait:
+		for {
+			select {
+			case e := <-events:
+				if e.ProjectKey == projectKey && e.WorkflowName == workflowName && e.WorkflowRunNum == number {
+					break wait
+				}
+			case <-ticker.C:
+				break wait
+			}
+		}
+	}
+}
+
+func workflowRunFollowResult(run *sdk.WorkflowRun) error {
+	fmt.Printf("Workflow %s #%d.%d: %s (%s)\n", run.Workflow.Name, run.Number, run.LastSubNumber, run.Status,
+		sdk.Round(run.LastModified.Sub(run.Start), time.Second))
+	switch run.Status {
+	case sdk.StatusSuccess.String():
+		return nil
+	case sdk.StatusStopped.String():
+		return &cli.Error{Code: 2, Err: fmt.Errorf("workflow run %d has been stopped", run.Number)}
+	default:
+		return &cli.Error{Code: 1, Err: fmt.Errorf("workflow run %d ended with status %s", run.Number, run.Status)}
+	}
+}
+
+type workflowRunFollower struct {
+	projectKey, workflowName string
+	// statuses are the last printed statuses of nodes, jobs and steps
+	statuses map[string]string
+	// logs are the lengths of the printed logs of the steps
+	logs map[string]int
+	// done are the steps whose logs have been entirely printed
+	done map[string]bool
+}
+
+func (f *workflowRunFollower) print(run *sdk.WorkflowRun) error {
+	nodeIDs := make([]int64, 0, len(run.WorkflowNodeRuns))
+	for id := range run.WorkflowNodeRuns {
+		nodeIDs = append(nodeIDs, id)
+	}
+	sort.Slice(nodeIDs, func(i, j int) bool { return nodeIDs[i] < nodeIDs[j] })
+
+	for _, id := range nodeIDs {
+		nodeRuns := run.WorkflowNodeRuns[id]
+		sort.Slice(nodeRuns, func(i, j int) bool { return nodeRuns[i].SubNumber < nodeRuns[j].SubNumber })
+		for _, nr := range nodeRuns {
+			f.printStatus(fmt.Sprintf("node/%d", nr.ID), nr.WorkflowNodeName, nr.Status)
+			for _, stage := range nr.Stages {
+				for _, job := range stage.RunJobs {
+					jobName := fmt.Sprintf("%s/%s/%s", nr.WorkflowNodeName, stage.Name, job.Job.Action.Name)
+					f.printStatus(fmt.Sprintf("job/%d", job.ID), jobName, job.Status)
+					for _, step := range job.Job.StepStatus {
+						if err := f.printStep(run.Number, nr.ID, job, jobName, step); err != nil {
+							return err
+						}
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func (f *workflowRunFollower) printStatus(key, name, status string) {
+	if f.statuses[key] == status {
+		return
+	}
+	f.statuses[key] = status
+	fmt.Printf("%s %s\n", name, status)
+}
+
+func (f *workflowRunFollower) printStep(number, nodeRunID int64, job sdk.WorkflowNodeJobRun, jobName string, step sdk.StepStatus) error {
+	key := fmt.Sprintf("step/%d/%d", job.ID, step.StepOrder)
+	stepName := fmt.Sprintf("%s/step %d", jobName, step.StepOrder)
+	if step.StepOrder < len(job.Job.Action.Actions) {
+		if a := job.Job.Action.Actions[step.StepOrder]; a.StepName != "" {
+			stepName = fmt.Sprintf("%s/%s", jobName, a.StepName)
+		} else {
+			stepName = fmt.Sprintf("%s/%s", jobName, a.Name)
+		}
+	}
+
+	f.printStatus(key, stepName, step.Status)
+	if f.done[key] {
+		return nil
+	}
+	switch step.Status {
+	case sdk.StatusSkipped.String(), sdk.StatusDisabled.String(), sdk.StatusNeverBuilt.String():
+		f.done[key] = true
+		return nil
+	}
+
+	buildState, err := client.WorkflowNodeRunJobStep(f.projectKey, f.workflowName, number, nodeRunID, job.ID, step.StepOrder)
+	if err != nil {
+		return err
+	}
+	terminated := sdk.StatusIsTerminated(step.Status)
+
+	logs := buildState.StepLogs.Val
+	if len(logs) <= f.logs[key] {
+		f.done[key] = terminated
+		return nil
+	}
+	logs = logs[f.logs[key]:]
+	// Only complete lines are printed while the step is running
+	if !terminated {
+		i := strings.LastIndex(logs, "\n")
+		if i < 0 {
+			return nil
+		}
+		logs = logs[:i+1]
+	}
+	f.logs[key] += len(logs)
+	f.done[key] = terminated
+
+	for _, line := range strings.Split(strings.TrimSuffix(logs, "\n"), "\n") {
+		fmt.Printf("[%s] %s\n", stepName, line)
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/ovh/cds/cli"
+)
+
+var workflowTestsCmd = cli.Command{
+	Name:  "tests",
+	Short: "Manage Workflow test cases",
+}
+
+func workflowTests() *cobra.Command {
+	return cli.NewCommand(workflowTestsCmd, nil, []*cobra.Command{
+		cli.NewListCommand(workflowTestsFlakyCmd, workflowTestsFlakyRun, nil, withAllCommandModifiers()...),
+		cli.NewListCommand(workflowTestsHistoryCmd, workflowTestsHistoryRun, nil, withAllCommandModifiers()...),
+	})
+}
+
+var workflowTestsFlakyCmd = cli.Command{
+	Name:  "flaky",
+	Short: "List the flaky test cases of a Workflow",
+	Long: `List the test cases that alternate between success and failure on their last executions, the most unstable first.
+
+The score is the rate of status changes between two consecutive executions: 1 for a test case that changes its status at each execution.`,
+	Ctx: []cli.Arg{
+		{Name: _ProjectKey},
+		{Name: _WorkflowName},
+	},
+	Flags: []cli.Flag{
+		{Name: "node", Usage: "Filter on a pipeline of the workflow"},
+		{Name: "branch", Usage: "Filter on a git branch"},
+	},
+}
+
+func workflowTestsFlakyRun(v cli.Values) (cli.ListResult, error) {
+	flakiness, err := client.WorkflowFlakyTests(v.GetString(_ProjectKey), v.GetString(_WorkflowName), v.GetString("node"), v.GetString("branch"))
+	if err != nil {
+		return nil, err
+	}
+	return cli.AsListResult(flakiness), nil
+}
+
+var workflowTestsHistoryCmd = cli.Command{
+	Name:  "history",
+	Short: "Show the last executions of a test case of a Workflow",
+	Ctx: []cli.Arg{
+		{Name: _ProjectKey},
+		{Name: _WorkflowName},
+	},
+	Args: []cli.Arg{
+		{Name: "node"},
+		{Name: "suite"},
+		{Name: "name"},
+	},
+	Flags: []cli.Flag{
+		{Name: "branch", Usage: "Filter on a git branch"},
+	},
+}
+
+func workflowTestsHistoryRun(v cli.Values) (cli.ListResult, error) {
+	history, err := client.WorkflowTestCaseHistory(v.GetString(_ProjectKey), v.GetString(_WorkflowName),
+		v.GetString("node"), v.GetString("suite"), v.GetString("name"), v.GetString("branch"))
+	if err != nil {
+		return nil, err
+	}
+	return cli.AsListResult(history), nil
+}
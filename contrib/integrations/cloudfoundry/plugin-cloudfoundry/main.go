@@ -0,0 +1,268 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+
+	"github.com/golang/protobuf/ptypes/empty"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/grpcplugin/integrationplugin"
+	"github.com/ovh/cds/sdk/interpolate"
+)
+
+/*
+This plugin have to be used as a deployment integration plugin
+
+Cloud Foundry deployment plugin must configured as following:
+	name: cloudfoundry-deployment-plugin
+	type: integration-deploy_application
+	integration: Cloud Foundry
+	author: "OVH SAS"
+	description: "Cloud Foundry Deployment Plugin"
+
+$ cdsctl admin plugins import cloudfoundry-deployment-plugin.yml
+
+Build the present binaries and import in CDS:
+	os: linux
+	arch: amd64
+	cmd: <path-to-binary-file>
+	requirements:
+	- name: cf
+	  type: binary
+	  value: cf
+
+$ cdsctl admin plugins binary-add cloudfoundry-deployment-plugin cloudfoundry-deployment-plugin-bin.yml <path-to-binary-file>
+
+Cloud Foundry integration must configured as following
+	name: Cloud Foundry
+	default_config:
+		api_url:
+			type: string
+		username:
+			type: string
+		password:
+			type: password
+		skip_ssl_validation:
+			type: string
+			value: false
+	deployment: true
+	deployment_default_config:
+		org:
+			type: string
+		space:
+			type: string
+		app_name:
+			type: string
+			value: "{{.cds.application}}"
+		manifest:
+			type: string
+			value: manifest.yml
+		blue_green:
+			type: string
+			value: false
+	plugin: cloudfoundry-deployment-plugin
+
+The plugin needs the cf CLI on the worker. The org, the space, the app_name and the manifest of the application are
+interpolated with the CDS variables of the job before the push, ie. space: "{{.cds.env.cf_space}}". With blue_green,
+the running application is renamed, the new one is pushed and the previous one is deleted only when the new one is
+started.
+*/
+
+// venerableSuffix is added to the name of the running application during a blue-green deployment
+const venerableSuffix = "-venerable"
+
+type cloudfoundryDeploymentPlugin struct {
+	integrationplugin.Common
+}
+
+func (e *cloudfoundryDeploymentPlugin) Manifest(ctx context.Context, _ *empty.Empty) (*integrationplugin.IntegrationPluginManifest, error) {
+	return &integrationplugin.IntegrationPluginManifest{
+		Name:        "Cloud Foundry Deployment Plugin",
+		Author:      "OVH SAS",
+		Description: "Cloud Foundry Deployment Plugin",
+		Version:     sdk.VERSION,
+	}, nil
+}
+
+func (e *cloudfoundryDeploymentPlugin) Deploy(ctx context.Context, q *integrationplugin.DeployQuery) (*integrationplugin.DeployResult, error) {
+	var apiURL = q.GetOptions()["cds.integration.api_url"]
+	var username = q.GetOptions()["cds.integration.username"]
+	var password = q.GetOptions()["cds.integration.password"]
+	var skipSSLValidationStr = q.GetOptions()["cds.integration.skip_ssl_validation"]
+	var manifestPath = q.GetOptions()["cds.integration.manifest"]
+	var blueGreenStr = q.GetOptions()["cds.integration.blue_green"]
+
+	// The org, the space and the name of the application can depend on the variables of the job
+	org, err := interpolate.Do(q.GetOptions()["cds.integration.org"], q.GetOptions())
+	if err != nil {
+		return fail("Error: unable to interpolate org: %v. Please check you integration configuration", err)
+	}
+	space, err := interpolate.Do(q.GetOptions()["cds.integration.space"], q.GetOptions())
+	if err != nil {
+		return fail("Error: unable to interpolate space: %v. Please check you integration configuration", err)
+	}
+	if apiURL == "" || org == "" || space == "" {
+		return fail("Error: api_url, org and space are mandatory. Please check you integration configuration")
+	}
+
+	appName, err := interpolate.Do(q.GetOptions()["cds.integration.app_name"], q.GetOptions())
+	if err != nil {
+		return fail("Error: unable to interpolate app_name: %v. Please check you integration configuration", err)
+	}
+	if appName == "" {
+		appName = q.GetOptions()["cds.application"]
+	}
+	if manifestPath == "" {
+		manifestPath = "manifest.yml"
+	}
+	skipSSLValidation, _ := strconv.ParseBool(skipSSLValidationStr)
+	blueGreen, _ := strconv.ParseBool(blueGreenStr)
+
+	if _, err := exec.LookPath("cf"); err != nil {
+		return fail("Error: cf CLI not found: %v", err)
+	}
+
+	// The manifest is interpolated in a temporary file, next to the original one so that the relative paths it
+	// contains are still valid
+	manifest, err := ioutil.ReadFile(manifestPath)
+	if err != nil {
+		return fail("Error: unable to read manifest %s: %v", manifestPath, err)
+	}
+	manifestContent, err := interpolate.Do(string(manifest), q.GetOptions())
+	if err != nil {
+		return fail("Error: unable to interpolate manifest %s: %v", manifestPath, err)
+	}
+	tmpManifest, err := ioutil.TempFile(filepath.Dir(manifestPath), ".cds-manifest-*.yml")
+	if err != nil {
+		return fail("Error: unable to create manifest: %v", err)
+	}
+	defer os.Remove(tmpManifest.Name()) // nolint
+	if _, err := tmpManifest.WriteString(manifestContent); err != nil {
+		_ = tmpManifest.Close()
+		return fail("Error: unable to write manifest: %v", err)
+	}
+	if err := tmpManifest.Close(); err != nil {
+		return fail("Error: unable to write manifest: %v", err)
+	}
+
+	// The cf CLI stores the session in CF_HOME, a dedicated one is used to not share it with the other jobs
+	cfHome, err := ioutil.TempDir("", "cds-cf-home")
+	if err != nil {
+		return fail("Error: unable to create CF_HOME: %v", err)
+	}
+	defer os.RemoveAll(cfHome) // nolint
+
+	c := cf{env: append(os.Environ(), "CF_HOME="+cfHome, "CF_USERNAME="+username, "CF_PASSWORD="+password)}
+
+	apiArgs := []string{"api", apiURL}
+	if skipSSLValidation {
+		apiArgs = append(apiArgs, "--skip-ssl-validation")
+	}
+	if err := c.run(apiArgs...); err != nil {
+		return fail("Error: %v", err)
+	}
+	// Without arguments, cf auth reads the credentials from CF_USERNAME and CF_PASSWORD
+	if err := c.run("auth"); err != nil {
+		return fail("Error: %v", err)
+	}
+	if err := c.run("target", "-o", org, "-s", space); err != nil {
+		return fail("Error: %v", err)
+	}
+
+	fmt.Printf("Deploying %s on Cloud Foundry %s (org: %s, space: %s)...\n", appName, apiURL, org, space)
+	if blueGreen && c.exists(appName) {
+		err = c.blueGreenPush(appName, tmpManifest.Name())
+	} else {
+		err = c.run("push", appName, "-f", tmpManifest.Name())
+	}
+	if err != nil {
+		return fail("deployment failed: %v", err)
+	}
+
+	return &integrationplugin.DeployResult{
+		Status: sdk.StatusSuccess.String(),
+	}, nil
+}
+
+func (e *cloudfoundryDeploymentPlugin) DeployStatus(ctx context.Context, q *integrationplugin.DeployStatusQuery) (*integrationplugin.DeployResult, error) {
+	return &integrationplugin.DeployResult{
+		Status: sdk.StatusSuccess.String(),
+	}, nil
+}
+
+// cf runs the commands of the cf CLI
+type cf struct {
+	env []string
+}
+
+func (c cf) command(args ...string) *exec.Cmd {
+	cmd := exec.Command("cf", args...)
+	cmd.Env = c.env
+	return cmd
+}
+
+func (c cf) run(args ...string) error {
+	cmd := c.command(args...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("cf %s failed: %v", args[0], err)
+	}
+	return nil
+}
+
+// exists returns true if the application exists in the targeted space
+func (c cf) exists(appName string) bool {
+	return c.command("app", appName).Run() == nil
+}
+
+// blueGreenPush renames the running application, pushes the new one then deletes the previous one. If the push
+// fails, the previous application is restored.
+func (c cf) blueGreenPush(appName, manifest string) error {
+	venerable := appName + venerableSuffix
+	if c.exists(venerable) {
+		fmt.Printf("Deleting %s left by a previous deployment...\n", venerable)
+		if err := c.run("delete", venerable, "-f"); err != nil {
+			return err
+		}
+	}
+
+	if err := c.run("rename", appName, venerable); err != nil {
+		return err
+	}
+
+	if err := c.run("push", appName, "-f", manifest); err != nil {
+		fmt.Printf("Push of %s failed, restoring the previous application...\n", appName)
+		if err := c.run("delete", appName, "-f"); err != nil {
+			fmt.Printf("Unable to delete %s: %v\n", appName, err)
+		}
+		if err := c.run("rename", venerable, appName); err != nil {
+			fmt.Printf("Unable to restore %s: %v\n", appName, err)
+		}
+		return err
+	}
+
+	return c.run("delete", venerable, "-f")
+}
+
+func main() {
+	e := cloudfoundryDeploymentPlugin{}
+	if err := integrationplugin.Start(context.Background(), &e); err != nil {
+		panic(err)
+	}
+}
+
+func fail(format string, args ...interface{}) (*integrationplugin.DeployResult, error) {
+	msg := fmt.Sprintf(format, args...)
+	fmt.Println(msg)
+	return &integrationplugin.DeployResult{
+		Details: msg,
+		Status:  sdk.StatusFail.String(),
+	}, nil
+}
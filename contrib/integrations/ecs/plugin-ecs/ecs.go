@@ -0,0 +1,225 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	v4 "github.com/aws/aws-sdk-go/aws/signer/v4"
+)
+
+// ecsTargetPrefix is the prefix of the operations of the ECS JSON API
+const ecsTargetPrefix = "AmazonEC2ContainerServiceV20141113."
+
+// ecsClient calls the ECS JSON API, requests are signed with the AWS signature v4
+type ecsClient struct {
+	httpClient *http.Client
+	signer     *v4.Signer
+	region     string
+	endpoint   string
+}
+
+type ecsDeployment struct {
+	ID             string `json:"id"`
+	Status         string `json:"status"`
+	TaskDefinition string `json:"taskDefinition"`
+	DesiredCount   int64  `json:"desiredCount"`
+	RunningCount   int64  `json:"runningCount"`
+	PendingCount   int64  `json:"pendingCount"`
+	RolloutState   string `json:"rolloutState"`
+}
+
+type ecsServiceEvent struct {
+	ID      string `json:"id"`
+	Message string `json:"message"`
+}
+
+type ecsService struct {
+	ServiceName    string            `json:"serviceName"`
+	Status         string            `json:"status"`
+	TaskDefinition string            `json:"taskDefinition"`
+	DesiredCount   int64             `json:"desiredCount"`
+	RunningCount   int64             `json:"runningCount"`
+	Deployments    []ecsDeployment   `json:"deployments"`
+	Events         []ecsServiceEvent `json:"events"`
+}
+
+// primaryDeployment returns the deployment of the service using the last task definition
+func (s ecsService) primaryDeployment() *ecsDeployment {
+	for i := range s.Deployments {
+		if s.Deployments[i].Status == "PRIMARY" {
+			return &s.Deployments[i]
+		}
+	}
+	return nil
+}
+
+// steady returns true when the tasks of the service all run the task definition
+func (s ecsService) steady(taskDefinition string) bool {
+	d := s.primaryDeployment()
+	return len(s.Deployments) == 1 && d != nil && d.TaskDefinition == taskDefinition && d.RunningCount == d.DesiredCount
+}
+
+type ecsError struct {
+	Type    string `json:"__type"`
+	Message string `json:"message"`
+}
+
+func newECSClient(region, accessKeyID, secretAccessKey string) (*ecsClient, error) {
+	var creds *credentials.Credentials
+	if accessKeyID != "" {
+		creds = credentials.NewStaticCredentials(accessKeyID, secretAccessKey, "")
+	} else {
+		// Use the default credentials chain: environment variables, shared credentials file, instance role
+		sess, err := session.NewSession(&aws.Config{Region: aws.String(region)})
+		if err != nil {
+			return nil, fmt.Errorf("unable to load AWS credentials: %v", err)
+		}
+		creds = sess.Config.Credentials
+	}
+
+	return &ecsClient{
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+		signer:     v4.NewSigner(creds),
+		region:     region,
+		endpoint:   fmt.Sprintf("https://ecs.%s.amazonaws.com/", region),
+	}, nil
+}
+
+func (c *ecsClient) do(ctx context.Context, operation string, in, out interface{}) error {
+	body, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, c.endpoint, nil)
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", ecsTargetPrefix+operation)
+	if _, err := c.signer.Sign(req, bytes.NewReader(body), "ecs", c.region, time.Now()); err != nil {
+		return fmt.Errorf("unable to sign request %s: %v", operation, err)
+	}
+
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s failed: %v", operation, err)
+	}
+	defer res.Body.Close()
+
+	btes, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return fmt.Errorf("unable to read response of %s: %v", operation, err)
+	}
+	if res.StatusCode >= 400 {
+		var e ecsError
+		if err := json.Unmarshal(btes, &e); err != nil || e.Message == "" {
+			return fmt.Errorf("%s failed (HTTP Status Code: %d): %s", operation, res.StatusCode, string(btes))
+		}
+		return fmt.Errorf("%s failed: %s: %s", operation, e.Type, e.Message)
+	}
+
+	if out == nil {
+		return nil
+	}
+	return json.Unmarshal(btes, out)
+}
+
+func (c *ecsClient) describeService(ctx context.Context, cluster, service string) (*ecsService, error) {
+	in := map[string]interface{}{
+		"cluster":  cluster,
+		"services": []string{service},
+	}
+	var out struct {
+		Services []ecsService `json:"services"`
+		Failures []struct {
+			Arn    string `json:"arn"`
+			Reason string `json:"reason"`
+		} `json:"failures"`
+	}
+	if err := c.do(ctx, "DescribeServices", in, &out); err != nil {
+		return nil, err
+	}
+	if len(out.Failures) > 0 {
+		return nil, fmt.Errorf("unable to describe service %s: %s", service, out.Failures[0].Reason)
+	}
+	if len(out.Services) == 0 {
+		return nil, fmt.Errorf("service %s not found in cluster %s", service, cluster)
+	}
+	return &out.Services[0], nil
+}
+
+func (c *ecsClient) describeTaskDefinition(ctx context.Context, taskDefinition string) (map[string]interface{}, error) {
+	in := map[string]interface{}{
+		"taskDefinition": taskDefinition,
+	}
+	var out struct {
+		TaskDefinition map[string]interface{} `json:"taskDefinition"`
+	}
+	if err := c.do(ctx, "DescribeTaskDefinition", in, &out); err != nil {
+		return nil, err
+	}
+	return out.TaskDefinition, nil
+}
+
+// registerTaskDefinition registers a new revision of a task definition and returns its ARN
+func (c *ecsClient) registerTaskDefinition(ctx context.Context, taskDefinition map[string]interface{}) (string, error) {
+	var out struct {
+		TaskDefinition struct {
+			TaskDefinitionArn string `json:"taskDefinitionArn"`
+		} `json:"taskDefinition"`
+	}
+	if err := c.do(ctx, "RegisterTaskDefinition", taskDefinition, &out); err != nil {
+		return "", err
+	}
+	return out.TaskDefinition.TaskDefinitionArn, nil
+}
+
+func (c *ecsClient) updateService(ctx context.Context, cluster, service, taskDefinition string) error {
+	in := map[string]interface{}{
+		"cluster":        cluster,
+		"service":        service,
+		"taskDefinition": taskDefinition,
+	}
+	return c.do(ctx, "UpdateService", in, nil)
+}
+
+// taskDefinitionReadOnlyFields are returned by DescribeTaskDefinition but are not accepted by RegisterTaskDefinition
+var taskDefinitionReadOnlyFields = []string{"taskDefinitionArn", "revision", "status", "requiresAttributes", "compatibilities", "registeredAt", "registeredBy", "deregisteredAt"}
+
+// newTaskDefinition returns the input to register a new revision of a task definition, with the image of a
+// container replaced. Without container name, the image of the first container is replaced.
+func newTaskDefinition(current map[string]interface{}, container, image string) (map[string]interface{}, error) {
+	def := make(map[string]interface{}, len(current))
+	for k, v := range current {
+		def[k] = v
+	}
+	for _, k := range taskDefinitionReadOnlyFields {
+		delete(def, k)
+	}
+
+	containers, _ := def["containerDefinitions"].([]interface{})
+	for i := range containers {
+		c, ok := containers[i].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if container == "" || c["name"] == container {
+			c["image"] = image
+			return def, nil
+		}
+	}
+	if container == "" {
+		return nil, fmt.Errorf("task definition %v has no container", current["family"])
+	}
+	return nil, fmt.Errorf("container %s not found in task definition %v", container, current["family"])
+}
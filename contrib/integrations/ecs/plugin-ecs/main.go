@@ -0,0 +1,195 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/golang/protobuf/ptypes/empty"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/grpcplugin/integrationplugin"
+	"github.com/ovh/cds/sdk/interpolate"
+)
+
+/*
+This plugin have to be used as a deployment integration plugin
+
+ECS deployment plugin must configured as following:
+	name: ecs-deployment-plugin
+	type: integration-deploy_application
+	integration: AWS ECS
+	author: "OVH SAS"
+	description: "AWS ECS Deployment Plugin"
+
+$ cdsctl admin plugins import ecs-deployment-plugin.yml
+
+Build the present binaries and import in CDS:
+	os: linux
+	arch: amd64
+	cmd: <path-to-binary-file>
+
+$ cdsctl admin plugins binary-add ecs-deployment-plugin ecs-deployment-plugin-bin.yml <path-to-binary-file>
+
+AWS ECS integration must configured as following
+	name: AWS ECS
+	default_config:
+		region:
+			type: string
+		access_key_id:
+			type: string
+		secret_access_key:
+			type: password
+	deployment: true
+	deployment_default_config:
+		cluster:
+			type: string
+		service:
+			type: string
+		container:
+			type: string
+		image:
+			type: string
+		timeout:
+			type: string
+			value: 600
+	plugin: ecs-deployment-plugin
+
+The deployment registers a new revision of the task definition of the service with the new image, updates the
+service to use it and waits for the service to reach a steady state.
+*/
+
+type ecsDeploymentPlugin struct {
+	integrationplugin.Common
+}
+
+func (e *ecsDeploymentPlugin) Manifest(ctx context.Context, _ *empty.Empty) (*integrationplugin.IntegrationPluginManifest, error) {
+	return &integrationplugin.IntegrationPluginManifest{
+		Name:        "AWS ECS Deployment Plugin",
+		Author:      "OVH SAS",
+		Description: "AWS ECS Deployment Plugin",
+		Version:     sdk.VERSION,
+	}, nil
+}
+
+func (e *ecsDeploymentPlugin) Deploy(ctx context.Context, q *integrationplugin.DeployQuery) (*integrationplugin.DeployResult, error) {
+	var application = q.GetOptions()["cds.application"]
+	var region = q.GetOptions()["cds.integration.region"]
+	var accessKeyID = q.GetOptions()["cds.integration.access_key_id"]
+	var secretAccessKey = q.GetOptions()["cds.integration.secret_access_key"]
+	var cluster = q.GetOptions()["cds.integration.cluster"]
+	var service = q.GetOptions()["cds.integration.service"]
+	var container = q.GetOptions()["cds.integration.container"]
+	var timeoutStr = q.GetOptions()["cds.integration.timeout"]
+
+	if region == "" || cluster == "" || service == "" {
+		return fail("Error: region, cluster and service are mandatory. Please check you integration configuration")
+	}
+
+	image, err := interpolate.Do(q.GetOptions()["cds.integration.image"], q.GetOptions())
+	if err != nil {
+		return fail("Error: unable to interpolate image: %v. Please check you integration configuration", err)
+	}
+	if image == "" {
+		return fail("Error: image is mandatory. Please check you integration configuration")
+	}
+
+	timeout, err := strconv.Atoi(timeoutStr)
+	if err != nil {
+		fmt.Printf("Error parsing cds.integration.timeout: %v. Default value will be used\n", err)
+		timeout = 600
+	}
+
+	client, err := newECSClient(region, accessKeyID, secretAccessKey)
+	if err != nil {
+		return fail("Error: %v", err)
+	}
+
+	svc, err := client.describeService(ctx, cluster, service)
+	if err != nil {
+		return fail("Error: %v", err)
+	}
+
+	current, err := client.describeTaskDefinition(ctx, svc.TaskDefinition)
+	if err != nil {
+		return fail("Error: %v", err)
+	}
+	taskDefinition, err := newTaskDefinition(current, container, image)
+	if err != nil {
+		return fail("Error: %v", err)
+	}
+	taskDefinitionArn, err := client.registerTaskDefinition(ctx, taskDefinition)
+	if err != nil {
+		return fail("Error: %v", err)
+	}
+	fmt.Printf("Task definition %s registered with image %s\n", taskDefinitionArn, image)
+
+	fmt.Printf("Deploying %s on ECS service %s of cluster %s...\n", application, service, cluster)
+	if err := client.updateService(ctx, cluster, service, taskDefinitionArn); err != nil {
+		return fail("Error: %v", err)
+	}
+
+	// Only the events that happen during the deployment are displayed
+	seenEvents := make(map[string]struct{}, len(svc.Events))
+	for _, ev := range svc.Events {
+		seenEvents[ev.ID] = struct{}{}
+	}
+
+	deadline := time.Now().Add(time.Duration(timeout) * time.Second)
+	for {
+		svc, err := client.describeService(ctx, cluster, service)
+		if err != nil {
+			return fail("Error: %v", err)
+		}
+
+		// ECS returns the most recent events first
+		for i := len(svc.Events) - 1; i >= 0; i-- {
+			if _, ok := seenEvents[svc.Events[i].ID]; ok {
+				continue
+			}
+			seenEvents[svc.Events[i].ID] = struct{}{}
+			fmt.Println(svc.Events[i].Message)
+		}
+
+		if d := svc.primaryDeployment(); d != nil && d.RolloutState == "FAILED" {
+			return fail("deployment failed")
+		}
+		if svc.steady(taskDefinitionArn) {
+			break
+		}
+
+		if time.Now().After(deadline) {
+			return fail("deployment timeout: service %s did not reach a steady state after %d seconds", service, timeout)
+		}
+		fmt.Println("Not done yet")
+		time.Sleep(10 * time.Second)
+	}
+
+	fmt.Printf("Service %s is running %s\n", service, taskDefinitionArn)
+	return &integrationplugin.DeployResult{
+		Status: sdk.StatusSuccess.String(),
+	}, nil
+}
+
+func (e *ecsDeploymentPlugin) DeployStatus(ctx context.Context, q *integrationplugin.DeployStatusQuery) (*integrationplugin.DeployResult, error) {
+	return &integrationplugin.DeployResult{
+		Status: sdk.StatusSuccess.String(),
+	}, nil
+}
+
+func main() {
+	e := ecsDeploymentPlugin{}
+	if err := integrationplugin.Start(context.Background(), &e); err != nil {
+		panic(err)
+	}
+}
+
+func fail(format string, args ...interface{}) (*integrationplugin.DeployResult, error) {
+	msg := fmt.Sprintf(format, args...)
+	fmt.Println(msg)
+	return &integrationplugin.DeployResult{
+		Details: msg,
+		Status:  sdk.StatusFail.String(),
+	}, nil
+}
@@ -30,6 +30,9 @@ func (api *API) postNewAccessTokenHandler() service.Handler {
 		if len(accessTokenRequest.GroupsIDs) == 0 {
 			return sdk.WithStack(sdk.ErrWrongRequest)
 		}
+		if err := accessTokenRequest.Restrictions.IsValid(); err != nil {
+			return err
+		}
 
 		grantedUser := getGrantedUser(ctx)
 
@@ -73,6 +76,7 @@ func (api *API) postNewAccessTokenHandler() service.Handler {
 		if err != nil {
 			return sdk.WithStack(err)
 		}
+		token.Restrictions = accessTokenRequest.Restrictions
 
 		// Insert the token
 		if err := accesstoken.Insert(tx, &token); err != nil {
@@ -162,3 +166,136 @@ func (api *API) getAccessTokenByGroupHandler() service.Handler {
 		return service.WriteJSON(w, tokens, http.StatusOK)
 	}
 }
+
+// postPersonalAccessTokenHandler creates a personal access token for the current user with the groups of the user,
+// restricted to the requested scopes. The JWT token is send through a header X-CDS-JWT
+func (api *API) postPersonalAccessTokenHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		var accessTokenRequest sdk.AccessTokenRequest
+		if err := service.UnmarshalBody(r, &accessTokenRequest); err != nil {
+			return sdk.WithStack(err)
+		}
+
+		scopes := sdk.AccessTokenScopes(accessTokenRequest.Scopes)
+		if len(scopes) == 0 {
+			return sdk.NewErrorFrom(sdk.ErrWrongRequest, "a personal access token needs at least one scope")
+		}
+		if err := scopes.IsValid(); err != nil {
+			return err
+		}
+		if err := accessTokenRequest.Restrictions.IsValid(); err != nil {
+			return err
+		}
+		if accessTokenRequest.Description == "" {
+			return sdk.NewErrorFrom(sdk.ErrWrongRequest, "a personal access token needs a description")
+		}
+
+		u := deprecatedGetUser(ctx)
+
+		tx, err := api.mustDB().Begin()
+		if err != nil {
+			return sdk.WithStack(err)
+		}
+		defer tx.Rollback() // nolint
+
+		groups, err := group.LoadGroupByUser(tx, u.ID)
+		if err != nil {
+			return sdk.WrapError(err, "cannot load groups of user %s", u.Username)
+		}
+
+		var expiration *time.Time
+		if accessTokenRequest.ExpirationDelaySecond > 0 {
+			t := time.Now().Add(time.Duration(accessTokenRequest.ExpirationDelaySecond) * time.Second)
+			expiration = &t
+		}
+
+		token, jwttoken, err := accesstoken.New(*u, groups, accessTokenRequest.Origin, accessTokenRequest.Description, expiration)
+		if err != nil {
+			return sdk.WithStack(err)
+		}
+		token.Scopes = scopes
+		token.Restrictions = accessTokenRequest.Restrictions
+
+		if err := accesstoken.Insert(tx, &token); err != nil {
+			return sdk.WrapError(err, "cannot insert personal access token")
+		}
+
+		if err := tx.Commit(); err != nil {
+			return sdk.WithStack(err)
+		}
+
+		w.Header().Add("X-CDS-JWT", jwttoken)
+
+		return service.WriteJSON(w, token, http.StatusCreated)
+	}
+}
+
+// getPersonalAccessTokensHandler returns the access tokens of the current user
+func (api *API) getPersonalAccessTokensHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		tokens, err := accesstoken.FindAllByUser(api.mustDB(), deprecatedGetUser(ctx).ID)
+		if err != nil {
+			return sdk.WithStack(err)
+		}
+		return service.WriteJSON(w, tokens, http.StatusOK)
+	}
+}
+
+// deletePersonalAccessTokenHandler revokes an access token of the current user
+func (api *API) deletePersonalAccessTokenHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		id := mux.Vars(r)["id"]
+		u := deprecatedGetUser(ctx)
+
+		token, err := accesstoken.FindByID(api.mustDB(), id)
+		if err != nil {
+			return sdk.NewErrorFrom(sdk.ErrNotFound, "access token %s not found", id)
+		}
+		if token.UserID != u.ID && !u.Admin {
+			return sdk.NewErrorFrom(sdk.ErrNotFound, "access token %s not found", id)
+		}
+
+		if err := accesstoken.Delete(api.mustDB(), &token); err != nil {
+			return sdk.WrapError(err, "cannot revoke access token %s", id)
+		}
+		return service.WriteJSON(w, nil, http.StatusOK)
+	}
+}
+
+// putAccessTokenRestrictionsHandler updates the restrictions of an access token of the current user
+func (api *API) putAccessTokenRestrictionsHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		id := mux.Vars(r)["id"]
+		u := deprecatedGetUser(ctx)
+
+		var restrictions sdk.AccessTokenRestrictions
+		if err := service.UnmarshalBody(r, &restrictions); err != nil {
+			return sdk.WithStack(err)
+		}
+		if err := restrictions.IsValid(); err != nil {
+			return err
+		}
+
+		tx, err := api.mustDB().Begin()
+		if err != nil {
+			return sdk.WithStack(err)
+		}
+		defer tx.Rollback() // nolint
+
+		token, err := accesstoken.FindByID(tx, id)
+		if err != nil || (token.UserID != u.ID && !u.Admin) {
+			return sdk.NewErrorFrom(sdk.ErrNotFound, "access token %s not found", id)
+		}
+
+		token.Restrictions = restrictions
+		if err := accesstoken.Update(tx, &token); err != nil {
+			return sdk.WrapError(err, "cannot update restrictions of access token %s", id)
+		}
+
+		if err := tx.Commit(); err != nil {
+			return sdk.WithStack(err)
+		}
+
+		return service.WriteJSON(w, token, http.StatusOK)
+	}
+}
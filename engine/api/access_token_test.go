@@ -11,6 +11,7 @@ import (
 	"github.com/ovh/cds/engine/api/group"
 	"github.com/ovh/cds/engine/api/test"
 	"github.com/ovh/cds/engine/api/test/assets"
+	"github.com/ovh/cds/engine/service"
 	"github.com/ovh/cds/sdk"
 )
 
@@ -103,3 +104,135 @@ func TestAPI_TokenHandlers(t *testing.T) {
 	t.Logf("getAccessTokenByUserHandler result is : %s", w.Body.String())
 
 }
+
+func TestAPI_PersonalAccessTokenHandlers(t *testing.T) {
+	api, db, router, end := newTestAPI(t)
+	defer end()
+
+	grp := sdk.Group{Name: sdk.RandomString(10)}
+	user, password := assets.InsertLambdaUser(db, &grp)
+
+	// A personal access token needs a valid scope
+	uri := router.GetRoute("POST", api.postPersonalAccessTokenHandler, nil)
+	req := assets.NewAuthentifiedRequest(t, user, password, "POST", uri, sdk.AccessTokenRequest{
+		Description: "test",
+		Scopes:      []string{"write:everything"},
+	})
+	w := httptest.NewRecorder()
+	router.Mux.ServeHTTP(w, req)
+	assert.Equal(t, 400, w.Code)
+
+	req = assets.NewAuthentifiedRequest(t, user, password, "POST", uri, sdk.AccessTokenRequest{
+		Description:           "test",
+		Scopes:                []string{sdk.AccessTokenScopeReadProject},
+		ExpirationDelaySecond: 3600,
+	})
+	w = httptest.NewRecorder()
+	router.Mux.ServeHTTP(w, req)
+	assert.Equal(t, 201, w.Code)
+	jwt := w.Header().Get("X-CDS-JWT")
+
+	var accessToken sdk.AccessToken
+	test.NoError(t, json.Unmarshal(w.Body.Bytes(), &accessToken))
+	assert.Equal(t, sdk.AccessTokenScopes{sdk.AccessTokenScopeReadProject}, accessToken.Scopes)
+	assert.NotNil(t, accessToken.ExpireAt)
+
+	// The token can read but cannot create a worker model
+	uri = router.GetRoute("GET", api.getPersonalAccessTokensHandler, nil)
+	req = assets.NewJWTAuthentifiedRequest(t, jwt, "GET", uri, nil)
+	w = httptest.NewRecorder()
+	router.Mux.ServeHTTP(w, req)
+	assert.Equal(t, 200, w.Code)
+
+	var tokens []sdk.AccessToken
+	test.NoError(t, json.Unmarshal(w.Body.Bytes(), &tokens))
+	assert.Len(t, tokens, 1)
+	assert.NotNil(t, tokens[0].LastUsed)
+
+	uri = router.GetRoute("POST", api.addWorkerModelHandler, nil)
+	req = assets.NewJWTAuthentifiedRequest(t, jwt, "POST", uri, sdk.Model{Name: sdk.RandomString(10)})
+	w = httptest.NewRecorder()
+	router.Mux.ServeHTTP(w, req)
+	assert.Equal(t, 403, w.Code)
+
+	// Revoke the token
+	uri = router.GetRoute("DELETE", api.deletePersonalAccessTokenHandler, map[string]string{"id": accessToken.ID})
+	req = assets.NewAuthentifiedRequest(t, user, password, "DELETE", uri, nil)
+	w = httptest.NewRecorder()
+	router.Mux.ServeHTTP(w, req)
+	assert.Equal(t, 204, w.Code)
+
+	uri = router.GetRoute("GET", api.getPersonalAccessTokensHandler, nil)
+	req = assets.NewJWTAuthentifiedRequest(t, jwt, "GET", uri, nil)
+	w = httptest.NewRecorder()
+	router.Mux.ServeHTTP(w, req)
+	assert.Equal(t, 401, w.Code)
+}
+
+func Test_accessTokenScope(t *testing.T) {
+	r := &Router{}
+	h := func() service.Handler { return nil }
+	assert.Equal(t, sdk.AccessTokenScopeReadProject, accessTokenScope(r.GET(h)))
+	assert.Equal(t, "", accessTokenScope(r.POST(h)))
+	assert.Equal(t, sdk.AccessTokenScopeRunWorkflow, accessTokenScope(r.POSTEXECUTE(h, Scope(sdk.AccessTokenScopeRunWorkflow))))
+	// The admin routes are denied to the scoped tokens
+	assert.Equal(t, "", accessTokenScope(r.GET(h, NeedAdmin(true))))
+	assert.Equal(t, sdk.AccessTokenScopeReadProject, accessTokenScope(r.GET(h, NeedAdmin(false))))
+
+	var scopes sdk.AccessTokenScopes = sdk.AccessTokenScopesAvailable
+	assert.False(t, scopes.Allows(accessTokenScope(r.GET(h, NeedAdmin(true)))))
+}
+
+func TestAPI_AccessTokenRestrictions(t *testing.T) {
+	api, db, router, end := newTestAPI(t)
+	defer end()
+
+	grp := sdk.Group{Name: sdk.RandomString(10)}
+	user, password := assets.InsertLambdaUser(db, &grp)
+
+	uri := router.GetRoute("POST", api.postPersonalAccessTokenHandler, nil)
+	req := assets.NewAuthentifiedRequest(t, user, password, "POST", uri, sdk.AccessTokenRequest{
+		Description:  "restricted",
+		Scopes:       []string{sdk.AccessTokenScopeReadProject},
+		Restrictions: sdk.AccessTokenRestrictions{AllowedCIDRs: []string{"10.0.0.0/8"}, MaxSessions: 1},
+	})
+	w := httptest.NewRecorder()
+	router.Mux.ServeHTTP(w, req)
+	assert.Equal(t, 201, w.Code)
+	jwt := w.Header().Get("X-CDS-JWT")
+
+	var accessToken sdk.AccessToken
+	test.NoError(t, json.Unmarshal(w.Body.Bytes(), &accessToken))
+	assert.Equal(t, 1, accessToken.Restrictions.MaxSessions)
+
+	uri = router.GetRoute("GET", api.getPersonalAccessTokensHandler, nil)
+	for _, c := range []struct {
+		addr string
+		code int
+	}{
+		{"203.0.113.10:4242", 403}, // address not allowed
+		{"10.0.0.1:4242", 200},
+		{"10.0.0.2:4242", 403}, // only one session
+		{"10.0.0.1:4243", 200},
+	} {
+		req = assets.NewJWTAuthentifiedRequest(t, jwt, "GET", uri, nil)
+		req.RemoteAddr = c.addr
+		w = httptest.NewRecorder()
+		router.Mux.ServeHTTP(w, req)
+		assert.Equal(t, c.code, w.Code, c.addr)
+	}
+
+	// Remove the restrictions
+	uri = router.GetRoute("PUT", api.putAccessTokenRestrictionsHandler, map[string]string{"id": accessToken.ID})
+	req = assets.NewAuthentifiedRequest(t, user, password, "PUT", uri, sdk.AccessTokenRestrictions{})
+	w = httptest.NewRecorder()
+	router.Mux.ServeHTTP(w, req)
+	assert.Equal(t, 200, w.Code)
+
+	uri = router.GetRoute("GET", api.getPersonalAccessTokensHandler, nil)
+	req = assets.NewJWTAuthentifiedRequest(t, jwt, "GET", uri, nil)
+	req.RemoteAddr = "203.0.113.10:4242"
+	w = httptest.NewRecorder()
+	router.Mux.ServeHTTP(w, req)
+	assert.Equal(t, 200, w.Code)
+}
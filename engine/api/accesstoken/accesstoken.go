@@ -62,6 +62,10 @@ func New(u sdk.User, groups []sdk.Group, origin, desc string, expiration *time.T
 
 // Regen regenerate the signed token value
 func Regen(token *sdk.AccessToken) (string, error) {
+	if signingKey == nil {
+		return "", sdk.NewErrorFrom(sdk.ErrNotImplemented, "access tokens are disabled on this instance")
+	}
+
 	claims := sdk.AccessTokenJWTClaims{
 		ID:     token.ID,
 		Groups: sdk.GroupsToIDs(token.Groups),
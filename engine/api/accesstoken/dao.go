@@ -2,6 +2,7 @@ package accesstoken
 
 import (
 	"database/sql"
+	"time"
 
 	"github.com/go-gorp/gorp"
 	"github.com/lib/pq"
@@ -103,6 +104,22 @@ func Delete(db gorp.SqlExecutor, token *sdk.AccessToken) error {
 	return nil
 }
 
+// lastUsedPrecision avoids writing in database on each request made with a token
+const lastUsedPrecision = time.Minute
+
+// UpdateLastUsed sets the last time a token was used, at most once per minute
+func UpdateLastUsed(db gorp.SqlExecutor, token *sdk.AccessToken) error {
+	now := time.Now()
+	if token.LastUsed != nil && now.Sub(*token.LastUsed) < lastUsedPrecision {
+		return nil
+	}
+	if _, err := db.Exec("UPDATE access_token SET last_used = $2 WHERE id = $1", token.ID, now); err != nil {
+		return sdk.WrapError(err, "unable to update last use of token %s", token.ID)
+	}
+	token.LastUsed = &now
+	return nil
+}
+
 // PostGet load all the groups for an access token
 func (a *accessToken) PostGet(db gorp.SqlExecutor) error {
 	// Load the user
@@ -0,0 +1,56 @@
+package accesstoken
+
+import (
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/ovh/cds/engine/api/cache"
+	"github.com/ovh/cds/sdk"
+)
+
+// CheckRestrictions returns the reason why the restrictions of the token deny a request from the given address,
+// or an empty string if the request is allowed. The sessions of the tokens limiting their number are kept in cache.
+func CheckRestrictions(store cache.Store, token sdk.AccessToken, ip net.IP) string {
+	r := token.Restrictions
+	if !r.AllowsIP(ip) {
+		return fmt.Sprintf("address %s is not allowed", ip)
+	}
+	if r.MaxSessions == 0 {
+		return ""
+	}
+
+	// The sessions are read and updated under a lock of the token, shared by the API instances, so that concurrent
+	// requests cannot open more sessions than allowed
+	k := cache.Key("accesstoken", "sessions", token.ID)
+	lockKey := cache.Key(k, "lock")
+	if !store.Lock(lockKey, 5*time.Second, 50, 20) {
+		return fmt.Sprintf("cannot check the sessions of the token for %s", ip)
+	}
+	defer store.Unlock(lockKey)
+
+	sessions := map[string]time.Time{}
+	store.Get(k, &sessions)
+	sessions, reason := openSession(sessions, r, ip.String(), time.Now())
+	if reason != "" {
+		return reason
+	}
+	store.SetWithTTL(k, sessions, int(r.SessionDuration().Seconds()))
+	return ""
+}
+
+// openSession refreshes the session of the address, or opens it if the maximum number of sessions is not reached.
+// The sessions without request since the session ttl are closed.
+func openSession(sessions map[string]time.Time, r sdk.AccessTokenRestrictions, addr string, now time.Time) (map[string]time.Time, string) {
+	res := make(map[string]time.Time, len(sessions)+1)
+	for a, last := range sessions {
+		if now.Sub(last) < r.SessionDuration() {
+			res[a] = last
+		}
+	}
+	if _, ok := res[addr]; !ok && len(res) >= r.MaxSessions {
+		return sessions, fmt.Sprintf("maximum number of sessions (%d) reached, cannot open a session for %s", r.MaxSessions, addr)
+	}
+	res[addr] = now
+	return res, ""
+}
@@ -0,0 +1,61 @@
+package accesstoken
+
+import (
+	"fmt"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/engine/api/cache"
+	"github.com/ovh/cds/sdk"
+)
+
+func TestOpenSession(t *testing.T) {
+	r := sdk.AccessTokenRestrictions{MaxSessions: 2, SessionTTL: 10}
+	now := time.Now()
+
+	sessions, reason := openSession(map[string]time.Time{}, r, "10.0.0.1", now)
+	assert.Empty(t, reason)
+	sessions, reason = openSession(sessions, r, "10.0.0.2", now.Add(time.Minute))
+	assert.Empty(t, reason)
+	assert.Len(t, sessions, 2)
+
+	// A third address cannot open a session, the known ones can still use the token
+	_, reason = openSession(sessions, r, "10.0.0.3", now.Add(2*time.Minute))
+	assert.NotEmpty(t, reason)
+	sessions, reason = openSession(sessions, r, "10.0.0.1", now.Add(2*time.Minute))
+	assert.Empty(t, reason)
+
+	// The session of the second address is closed after 10 minutes without request
+	sessions, reason = openSession(sessions, r, "10.0.0.3", now.Add(11*time.Minute+time.Second))
+	assert.Empty(t, reason)
+	assert.Len(t, sessions, 2)
+	assert.Contains(t, sessions, "10.0.0.1")
+	assert.Contains(t, sessions, "10.0.0.3")
+}
+
+func TestCheckRestrictionsConcurrentSessions(t *testing.T) {
+	store := cache.NewLocalStore(60, 100)
+	token := sdk.AccessToken{ID: sdk.UUID(), Restrictions: sdk.AccessTokenRestrictions{MaxSessions: 2, SessionTTL: 10}}
+
+	// Concurrent requests from different addresses cannot open more sessions than allowed
+	var wg sync.WaitGroup
+	var mutex sync.Mutex
+	var allowed int
+	for i := 1; i <= 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if CheckRestrictions(store, token, net.ParseIP(fmt.Sprintf("10.0.0.%d", i))) == "" {
+				mutex.Lock()
+				allowed++
+				mutex.Unlock()
+			}
+		}(i)
+	}
+	wg.Wait()
+	assert.Equal(t, 2, allowed)
+}
@@ -61,7 +61,7 @@ func (api *API) deleteActionHandler() service.Handler {
 		vars := mux.Vars(r)
 		name := vars["permActionName"]
 
-		a, errLoad := action.LoadPublicAction(api.mustDB(), name)
+		a, errLoad := action.LoadPublicActionVersion(api.mustDB(), name, FormString(r, "version"))
 		if errLoad != nil {
 			if !sdk.ErrorIs(errLoad, sdk.ErrNoAction) {
 				log.Warning("deleteAction> Cannot load action %s: %T %s", name, errLoad, errLoad)
@@ -110,7 +110,7 @@ func (api *API) updateActionHandler() service.Handler {
 		}
 
 		// Check that action  already exists
-		actionDB, err := action.LoadPublicAction(api.mustDB(), name)
+		actionDB, err := action.LoadPublicActionByVersion(api.mustDB(), name, a.Version)
 		if err != nil {
 			return sdk.WrapError(err, "Cannot check if action %s exist", a.Name)
 		}
@@ -144,14 +144,17 @@ func (api *API) addActionHandler() service.Handler {
 			return err
 		}
 
-		// Check that action does not already exists
-		conflict, errConflict := action.Exists(api.mustDB(), a.Name)
-		if errConflict != nil {
-			return errConflict
+		if err := sdk.IsValidActionVersion(a.Version); err != nil {
+			return err
 		}
 
-		if conflict {
-			return sdk.WrapError(sdk.ErrConflict, "addAction> Action %s already exists", a.Name)
+		// Check that action does not already exists with the same version
+		_, errConflict := action.LoadPublicActionByVersion(api.mustDB(), a.Name, a.Version)
+		if errConflict == nil {
+			return sdk.WrapError(sdk.ErrConflict, "addAction> Action %s already exists", sdk.ActionReference(a.Name, a.Version))
+		}
+		if !sdk.ErrorIs(errConflict, sdk.ErrNoAction) {
+			return errConflict
 		}
 
 		tx, errDB := api.mustDB().Begin()
@@ -198,7 +201,7 @@ func (api *API) getActionHandler() service.Handler {
 		vars := mux.Vars(r)
 		name := vars["permActionName"]
 
-		a, err := action.LoadPublicAction(api.mustDB(), name)
+		a, err := action.LoadPublicActionVersion(api.mustDB(), name, FormString(r, "version"))
 		if err != nil {
 			return sdk.WrapError(sdk.ErrNotFound, "getActionHandler> Cannot load action: %s", err)
 		}
@@ -206,6 +209,20 @@ func (api *API) getActionHandler() service.Handler {
 	}
 }
 
+// getActionVersionsHandler returns all the versions of an action
+func (api *API) getActionVersionsHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		name := vars["permActionName"]
+
+		acts, err := action.LoadPublicActionVersions(api.mustDB(), name)
+		if err != nil {
+			return sdk.WrapError(err, "Cannot load versions of action %s", name)
+		}
+		return service.WriteJSON(w, acts, http.StatusOK)
+	}
+}
+
 func (api *API) getActionExportHandler() service.Handler {
 	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
 		vars := mux.Vars(r)
@@ -274,9 +291,14 @@ func (api *API) importActionHandler() service.Handler {
 
 		defer tx.Rollback()
 
-		//Check if action exists
+		if err := sdk.IsValidActionVersion(a.Version); err != nil {
+			return err
+		}
+
+		//Check if action exists. A new version of an action is inserted next to the previous ones, so that
+		//the pipelines using them keep running
 		exist := false
-		existingAction, errload := action.LoadPublicAction(tx, a.Name)
+		existingAction, errload := action.LoadPublicActionByVersion(tx, a.Name, a.Version)
 		if errload == nil {
 			exist = true
 			a.ID = existingAction.ID
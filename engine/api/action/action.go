@@ -38,8 +38,8 @@ func InsertAction(tx gorp.SqlExecutor, a *sdk.Action, public bool) error {
 		return sdk.ErrActionLoop
 	}
 
-	query := `INSERT INTO action (name, description, type, enabled, deprecated, public) VALUES($1, $2, $3, $4, $5, $6) RETURNING id`
-	if err := tx.QueryRow(query, a.Name, a.Description, a.Type, a.Enabled, a.Deprecated, public).Scan(&a.ID); err != nil {
+	query := `INSERT INTO action (name, version, description, type, enabled, deprecated, public) VALUES($1, $2, $3, $4, $5, $6, $7) RETURNING id`
+	if err := tx.QueryRow(query, a.Name, a.Version, a.Description, a.Type, a.Enabled, a.Deprecated, public).Scan(&a.ID); err != nil {
 		return err
 	}
 
@@ -51,7 +51,7 @@ func InsertAction(tx gorp.SqlExecutor, a *sdk.Action, public bool) error {
 
 		// if child id is not given, try to load by name
 		if a.Actions[i].ID == 0 {
-			ch, errl := LoadPublicAction(tx, a.Actions[i].Name)
+			ch, errl := LoadPublicActionVersion(tx, a.Actions[i].Name, a.Actions[i].VersionConstraint)
 			if errl != nil {
 				return errl
 			}
@@ -113,10 +113,30 @@ func InsertAction(tx gorp.SqlExecutor, a *sdk.Action, public bool) error {
 	return nil
 }
 
-// LoadPublicAction load an action from database
+// LoadPublicAction load the latest version of an action from database
 func LoadPublicAction(db gorp.SqlExecutor, name string) (*sdk.Action, error) {
-	query := `SELECT id, name, description, type, last_modified, enabled, deprecated FROM action WHERE lower(action.name) = lower($1) AND public = true`
-	a, err := loadActions(db, query, name)
+	return LoadPublicActionVersion(db, name, "")
+}
+
+// LoadPublicActionVersion load the highest version of an action matching the version constraint
+func LoadPublicActionVersion(db gorp.SqlExecutor, name, constraint string) (*sdk.Action, error) {
+	acts, err := LoadPublicActionVersions(db, name)
+	if err != nil {
+		return nil, err
+	}
+	return sdk.LatestActionVersion(acts, constraint)
+}
+
+// LoadPublicActionVersions load all the versions of an action from database
+func LoadPublicActionVersions(db gorp.SqlExecutor, name string) ([]sdk.Action, error) {
+	query := `SELECT id, name, version, description, type, last_modified, enabled, deprecated FROM action WHERE lower(action.name) = lower($1) AND public = true`
+	return loadActions(db, query, name)
+}
+
+// LoadPublicActionByVersion load an action with exactly the given version from database
+func LoadPublicActionByVersion(db gorp.SqlExecutor, name, version string) (*sdk.Action, error) {
+	query := `SELECT id, name, version, description, type, last_modified, enabled, deprecated FROM action WHERE lower(action.name) = lower($1) AND version = $2 AND public = true`
+	a, err := loadActions(db, query, name, version)
 	if err != nil {
 		return nil, err
 	}
@@ -125,7 +145,7 @@ func LoadPublicAction(db gorp.SqlExecutor, name string) (*sdk.Action, error) {
 
 // LoadActionByID retrieves in database the action with given id
 func LoadActionByID(db gorp.SqlExecutor, actionID int64) (*sdk.Action, error) {
-	query := `SELECT id, name, description, type, last_modified, enabled, deprecated FROM action WHERE action.id = $1`
+	query := `SELECT id, name, version, description, type, last_modified, enabled, deprecated FROM action WHERE action.id = $1`
 	a, err := loadActions(db, query, actionID)
 	if err != nil {
 		return nil, err
@@ -135,7 +155,7 @@ func LoadActionByID(db gorp.SqlExecutor, actionID int64) (*sdk.Action, error) {
 
 // LoadActions load all actions from database
 func LoadActions(db gorp.SqlExecutor) ([]sdk.Action, error) {
-	query := `SELECT id, name, description, type, last_modified, enabled, deprecated FROM action WHERE public = true ORDER BY name`
+	query := `SELECT id, name, version, description, type, last_modified, enabled, deprecated FROM action WHERE public = true ORDER BY name, version`
 	return loadActions(db, query)
 }
 
@@ -153,7 +173,7 @@ func loadActions(db gorp.SqlExecutor, query string, args ...interface{}) ([]sdk.
 	for rows.Next() {
 		a := sdk.Action{}
 		var lastModified time.Time
-		if err := rows.Scan(&a.ID, &a.Name, &a.Description, &a.Type, &lastModified, &a.Enabled, &a.Deprecated); err != nil {
+		if err := rows.Scan(&a.ID, &a.Name, &a.Version, &a.Description, &a.Type, &lastModified, &a.Enabled, &a.Deprecated); err != nil {
 			if err == sql.ErrNoRows {
 				return nil, sdk.ErrNoAction
 			}
@@ -225,7 +245,7 @@ func UpdateActionDB(db gorp.SqlExecutor, a *sdk.Action, userID int64) error {
 	for i := range a.Actions {
 		// if child id is not given, try to load by name
 		if a.Actions[i].ID == 0 {
-			ch, errl := LoadPublicAction(db, a.Actions[i].Name)
+			ch, errl := LoadPublicActionVersion(db, a.Actions[i].Name, a.Actions[i].VersionConstraint)
 			if errl != nil {
 				return errl
 			}
@@ -345,7 +365,7 @@ func isTreeLoopFree(db gorp.SqlExecutor, a *sdk.Action, parents []int64) (bool,
 
 		// If child id is not provided, load it properly
 		if cobaye.ID == 0 {
-			cobaye, err = LoadPublicAction(db, cobaye.Name)
+			cobaye, err = LoadPublicActionVersion(db, cobaye.Name, cobaye.VersionConstraint)
 			if err != nil {
 				log.Warning("isTreeLoopFree> error on action %s: %s", child.Name, err)
 				return false, err
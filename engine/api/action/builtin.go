@@ -66,6 +66,31 @@ Parse given file to extract coverage results.`
 		return err
 	}
 
+	// ----------------------------------- Coverage upload --------------------
+	coverUpload := sdk.NewAction(sdk.CoverageUploadAction)
+	coverUpload.Type = sdk.BuiltinAction
+	coverUpload.Description = `CDS Builtin Action.
+Upload a coverage report to follow the coverage of the application across runs and compare it with the default branch.`
+	coverUpload.Parameter(sdk.Parameter{
+		Name:        "path",
+		Description: `Path of the coverage report file.`,
+		Type:        sdk.StringParameter,
+	})
+	coverUpload.Parameter(sdk.Parameter{
+		Name:        "format",
+		Description: `Coverage report format: cobertura, lcov or gocover. Detected from the file if empty.`,
+		Type:        sdk.StringParameter,
+	})
+	coverUpload.Parameter(sdk.Parameter{
+		Name:        "minimum",
+		Description: `Minimum percentage of covered lines required.`,
+		Type:        sdk.NumberParameter,
+		Advanced:    true,
+	})
+	if err := checkBuiltinAction(db, coverUpload); err != nil {
+		return err
+	}
+
 	// ----------------------------------- Git clone    -----------------------
 	gitclone := sdk.NewAction(sdk.GitCloneAction)
 	gitclone.Type = sdk.BuiltinAction
@@ -149,8 +174,8 @@ The public key have to be granted on your repository`,
 Checkout a repository into a new directory.
 
 This action use the configuration from application to git clone the repository.
-The clone will be done with a depth of 50 and with submodules.
-If you want to modify theses options, you have to use gitClone action.
+The clone will be done with a depth of 50 and with submodules by default.
+It can fetch the Git LFS files and checkout only some directories of big repositories.
 `
 
 	checkoutApplication.Parameter(sdk.Parameter{
@@ -159,6 +184,34 @@ If you want to modify theses options, you have to use gitClone action.
 		Value:       "{{.cds.workspace}}",
 		Type:        sdk.StringParameter,
 	})
+	checkoutApplication.Parameter(sdk.Parameter{
+		Name:        "depth",
+		Description: "Depth of the clone, 50 by default. Set 0 to clone the full history.",
+		Value:       "50",
+		Type:        sdk.StringParameter,
+		Advanced:    true,
+	})
+	checkoutApplication.Parameter(sdk.Parameter{
+		Name:        "submodules",
+		Description: "Clone the submodules recursively, with the credentials of the application.",
+		Value:       "true",
+		Type:        sdk.BooleanParameter,
+		Advanced:    true,
+	})
+	checkoutApplication.Parameter(sdk.Parameter{
+		Name:        "lfs",
+		Description: "Fetch the Git LFS files, git-lfs must be installed on the worker.",
+		Value:       "false",
+		Type:        sdk.BooleanParameter,
+		Advanced:    true,
+	})
+	checkoutApplication.Parameter(sdk.Parameter{
+		Name:        "sparse",
+		Description: "Directories to checkout, separated by commas or new lines. Empty: checkout the whole repository.",
+		Value:       "",
+		Type:        sdk.TextParameter,
+		Advanced:    true,
+	})
 	checkoutApplication.Requirement("git", sdk.BinaryRequirement, "git")
 
 	if err := checkBuiltinAction(db, checkoutApplication); err != nil {
@@ -258,6 +311,324 @@ Semver used if fully compatible with https://semver.org/
 		return err
 	}
 
+	// ----------------------------------- Promote -----------------------
+	promote := sdk.NewAction(sdk.PromoteAction)
+	promote.Type = sdk.BuiltinAction
+	promote.Description = `CDS Builtin Action. Promote the build-info of the run with an Artifactory integration.
+The artifacts of the build are moved or copied to the target repository of the status, given by the repository mappings of the integration.`
+	promote.Parameter(sdk.Parameter{
+		Name:        "status",
+		Description: "Promotion status, example: staging or release",
+		Type:        sdk.StringParameter,
+	})
+	promote.Parameter(sdk.Parameter{
+		Name:        "comment",
+		Description: "Comment of the promotion",
+		Type:        sdk.StringParameter,
+	})
+	promote.Parameter(sdk.Parameter{
+		Name:        "copy",
+		Description: "Copy the artifacts instead of moving them",
+		Type:        sdk.BooleanParameter,
+		Value:       "false",
+	})
+	promote.Parameter(sdk.Parameter{
+		Name:        "integration",
+		Description: "Name of the Artifactory integration. Default: the integration of the pipeline context, or the only Artifactory integration of the project",
+		Type:        sdk.StringParameter,
+		Advanced:    true,
+	})
+	promote.Parameter(sdk.Parameter{
+		Name:        "buildName",
+		Description: "Name of the build",
+		Type:        sdk.StringParameter,
+		Value:       "{{.cds.project}}-{{.cds.workflow}}",
+		Advanced:    true,
+	})
+	promote.Parameter(sdk.Parameter{
+		Name:        "buildNumber",
+		Description: "Number of the build",
+		Type:        sdk.StringParameter,
+		Value:       "{{.cds.version}}",
+		Advanced:    true,
+	})
+	if err := checkBuiltinAction(db, promote); err != nil {
+		return err
+	}
+
+	// ----------------------------------- Deploy Helm -----------------------
+	deployHelm := sdk.NewAction(sdk.DeployHelmAction)
+	deployHelm.Type = sdk.BuiltinAction
+	deployHelm.Description = `CDS Builtin Action. Install or upgrade a helm chart on the Kubernetes integration of the pipeline context.
+The release is rolled back if the deployment fails.`
+	deployHelm.Parameter(sdk.Parameter{
+		Name:        "chart",
+		Description: "Chart to deploy: path of a chart directory or archive, or name of a chart in the repository",
+		Type:        sdk.StringParameter,
+	})
+	deployHelm.Parameter(sdk.Parameter{
+		Name:        "releaseName",
+		Description: "Name of the helm release",
+		Type:        sdk.StringParameter,
+		Value:       "{{.cds.application}}",
+	})
+	deployHelm.Parameter(sdk.Parameter{
+		Name:        "namespace",
+		Description: "Kubernetes namespace of the release. Default: the namespace of the integration",
+		Type:        sdk.StringParameter,
+	})
+	deployHelm.Parameter(sdk.Parameter{
+		Name:        "values",
+		Description: "Values of the chart in yaml, you can use the variables of the run. Example: image.tag: {{.cds.version}}",
+		Type:        sdk.TextParameter,
+	})
+	deployHelm.Parameter(sdk.Parameter{
+		Name:        "valuesFiles",
+		Description: "Values files, separated by a comma",
+		Type:        sdk.StringParameter,
+	})
+	deployHelm.Parameter(sdk.Parameter{
+		Name:        "set",
+		Description: "Values of the chart, one key=value per line",
+		Type:        sdk.TextParameter,
+		Advanced:    true,
+	})
+	deployHelm.Parameter(sdk.Parameter{
+		Name:        "repository",
+		Description: "Url of the chart repository",
+		Type:        sdk.StringParameter,
+		Advanced:    true,
+	})
+	deployHelm.Parameter(sdk.Parameter{
+		Name:        "version",
+		Description: "Version of the chart",
+		Type:        sdk.StringParameter,
+		Advanced:    true,
+	})
+	deployHelm.Parameter(sdk.Parameter{
+		Name:        "timeout",
+		Description: "Time to wait for the resources of the release to be ready",
+		Type:        sdk.StringParameter,
+		Value:       "5m",
+		Advanced:    true,
+	})
+	deployHelm.Parameter(sdk.Parameter{
+		Name:        "rollback",
+		Description: "Rollback the release, or uninstall it if it is a first install, when the deployment fails",
+		Type:        sdk.BooleanParameter,
+		Value:       "true",
+		Advanced:    true,
+	})
+	deployHelm.Requirement("helm", sdk.BinaryRequirement, "helm")
+	if err := checkBuiltinAction(db, deployHelm); err != nil {
+		return err
+	}
+
+	// ----------------------------------- Terraform Plan -----------------------
+	terraformPlan := sdk.NewAction(sdk.TerraformPlanAction)
+	terraformPlan.Type = sdk.BuiltinAction
+	terraformPlan.Description = `CDS Builtin Action. Run terraform plan with the Terraform integration of the pipeline context.
+The plan file is uploaded as an artifact of the run, to be applied by the TerraformApply action.`
+	terraformPlan.Parameter(sdk.Parameter{
+		Name:        "directory",
+		Description: "Directory of the terraform configuration",
+		Type:        sdk.StringParameter,
+		Value:       ".",
+	})
+	terraformPlan.Parameter(sdk.Parameter{
+		Name:        "vars",
+		Description: "Variables of the configuration, one key=value per line. Example: image_tag={{.cds.version}}",
+		Type:        sdk.TextParameter,
+	})
+	terraformPlan.Parameter(sdk.Parameter{
+		Name:        "varFiles",
+		Description: "Variables files, separated by a comma",
+		Type:        sdk.StringParameter,
+	})
+	terraformPlan.Parameter(sdk.Parameter{
+		Name:        "workspace",
+		Description: "Terraform workspace, created if it does not exist",
+		Type:        sdk.StringParameter,
+		Advanced:    true,
+	})
+	terraformPlan.Parameter(sdk.Parameter{
+		Name:        "planName",
+		Description: "Name of the plan file, and of its artifact",
+		Type:        sdk.StringParameter,
+		Value:       "terraform.tfplan",
+		Advanced:    true,
+	})
+	terraformPlan.Requirement("terraform", sdk.BinaryRequirement, "terraform")
+	if err := checkBuiltinAction(db, terraformPlan); err != nil {
+		return err
+	}
+
+	// ----------------------------------- Terraform Apply -----------------------
+	terraformApply := sdk.NewAction(sdk.TerraformApplyAction)
+	terraformApply.Type = sdk.BuiltinAction
+	terraformApply.Description = `CDS Builtin Action. Apply the plan file uploaded by the TerraformPlan action of the run.
+By default, the pipeline must be run manually, after a review of the plan summary.`
+	terraformApply.Parameter(sdk.Parameter{
+		Name:        "directory",
+		Description: "Directory of the terraform configuration",
+		Type:        sdk.StringParameter,
+		Value:       ".",
+	})
+	terraformApply.Parameter(sdk.Parameter{
+		Name:        "workspace",
+		Description: "Terraform workspace",
+		Type:        sdk.StringParameter,
+		Advanced:    true,
+	})
+	terraformApply.Parameter(sdk.Parameter{
+		Name:        "planName",
+		Description: "Name of the plan file artifact",
+		Type:        sdk.StringParameter,
+		Value:       "terraform.tfplan",
+		Advanced:    true,
+	})
+	terraformApply.Parameter(sdk.Parameter{
+		Name:        "approval",
+		Description: "Apply the plan only if the pipeline has been run manually",
+		Type:        sdk.BooleanParameter,
+		Value:       "true",
+		Advanced:    true,
+	})
+	terraformApply.Requirement("terraform", sdk.BinaryRequirement, "terraform")
+	if err := checkBuiltinAction(db, terraformApply); err != nil {
+		return err
+	}
+
+	// ----------------------------------- SSH Deploy -----------------------
+	sshDeploy := sdk.NewAction(sdk.SSHDeployAction)
+	sshDeploy.Type = sdk.BuiltinAction
+	sshDeploy.Description = `CDS Builtin Action. Copy files with rsync and run commands on remote hosts with SSH.
+The connection uses a SSH key of the project, the application or the environment, and the host keys must be known.`
+	sshDeploy.Parameter(sdk.Parameter{
+		Name:        "privateKey",
+		Description: "SSH key used to connect to the hosts. Example: app-deploy",
+		Type:        sdk.KeySSHParameter,
+	})
+	sshDeploy.Parameter(sdk.Parameter{
+		Name:        "hosts",
+		Description: "Remote hosts, one per line or separated by a comma: [user@]host[:port]",
+		Type:        sdk.TextParameter,
+		Value:       "{{.cds.env.hosts}}",
+	})
+	sshDeploy.Parameter(sdk.Parameter{
+		Name:        "knownHosts",
+		Description: "Keys of the remote hosts, in the known_hosts format. Example: web1.example.com ssh-ed25519 AAAA...",
+		Type:        sdk.TextParameter,
+		Value:       "{{.cds.env.known_hosts}}",
+	})
+	sshDeploy.Parameter(sdk.Parameter{
+		Name:        "source",
+		Description: "Local files to copy on the hosts with rsync",
+		Type:        sdk.StringParameter,
+	})
+	sshDeploy.Parameter(sdk.Parameter{
+		Name:        "destination",
+		Description: "Remote directory of the copied files",
+		Type:        sdk.StringParameter,
+	})
+	sshDeploy.Parameter(sdk.Parameter{
+		Name:        "command",
+		Description: "Shell commands to run on the hosts, after the copy of the files",
+		Type:        sdk.TextParameter,
+	})
+	sshDeploy.Parameter(sdk.Parameter{
+		Name:        "user",
+		Description: "User of the hosts without user",
+		Type:        sdk.StringParameter,
+		Advanced:    true,
+	})
+	sshDeploy.Parameter(sdk.Parameter{
+		Name:        "rsyncOpts",
+		Description: "Options of rsync",
+		Type:        sdk.StringParameter,
+		Value:       "-az",
+		Advanced:    true,
+	})
+	sshDeploy.Requirement("ssh", sdk.BinaryRequirement, "ssh")
+	if err := checkBuiltinAction(db, sshDeploy); err != nil {
+		return err
+	}
+
+	// ----------------------------------- Generate SBOM -----------------------
+	generateSBOM := sdk.NewAction(sdk.GenerateSBOMAction)
+	generateSBOM.Type = sdk.BuiltinAction
+	generateSBOM.Description = `CDS Builtin Action. Generate the software bill of materials of a directory, an archive or a container image with syft.
+The SBOM is attached to the workflow run.`
+	generateSBOM.Parameter(sdk.Parameter{
+		Name:        "source",
+		Description: "Directory, archive or image to scan. Example: ., ./dist/my-app.tar.gz, registry.example.com/my-app:{{.cds.version}}",
+		Type:        sdk.StringParameter,
+		Value:       ".",
+	})
+	generateSBOM.Parameter(sdk.Parameter{
+		Name:        "name",
+		Description: "Name of the SBOM in the run, the source by default",
+		Type:        sdk.StringParameter,
+	})
+	generateSBOM.Parameter(sdk.Parameter{
+		Name:        "format",
+		Description: "Format of the SBOM: spdx-json or cyclonedx-json",
+		Type:        sdk.StringParameter,
+		Value:       sdk.SBOMFormatSPDX,
+	})
+	generateSBOM.Parameter(sdk.Parameter{
+		Name:        "output",
+		Description: "File where the SBOM is also written, to upload it as an artifact for example",
+		Type:        sdk.StringParameter,
+		Advanced:    true,
+	})
+	generateSBOM.Requirement("syft", sdk.BinaryRequirement, "syft")
+	if err := checkBuiltinAction(db, generateSBOM); err != nil {
+		return err
+	}
+
+	// ----------------------------------- Check Canary Metrics -----------------------
+	checkCanary := sdk.NewAction(sdk.CheckCanaryMetricsAction)
+	checkCanary.Type = sdk.BuiltinAction
+	checkCanary.Description = `CDS Builtin Action. Check the SLO metrics of a canary deployment with the Monitoring integration of the pipeline context.
+The queries are run on Prometheus or Datadog at each interval during the analysis. The step succeeds if all the thresholds
+are respected until the end of the analysis. Otherwise it fails, or if rollback is enabled it succeeds and exports
+cds.build.canary.rollback=true, to be used in the run conditions of the rollback and promotion nodes.`
+	checkCanary.Parameter(sdk.Parameter{
+		Name:        "checks",
+		Description: "Checks of the metrics, one per line: <query> <operator> <threshold>, the operator is <, <=, > or >=. Example: canary:http_errors:ratio_rate5m < 0.01",
+		Type:        sdk.TextParameter,
+	})
+	checkCanary.Parameter(sdk.Parameter{
+		Name:        "duration",
+		Description: "Duration of the analysis",
+		Type:        sdk.StringParameter,
+		Value:       "10m",
+	})
+	checkCanary.Parameter(sdk.Parameter{
+		Name:        "interval",
+		Description: "Interval between two checks",
+		Type:        sdk.StringParameter,
+		Value:       "1m",
+	})
+	checkCanary.Parameter(sdk.Parameter{
+		Name:        "failures",
+		Description: "Number of failed checks of a query before the canary is rejected",
+		Type:        sdk.NumberParameter,
+		Value:       "1",
+		Advanced:    true,
+	})
+	checkCanary.Parameter(sdk.Parameter{
+		Name:        "rollback",
+		Description: "Do not fail the step when the canary is rejected, export cds.build.canary.rollback=true instead",
+		Type:        sdk.BooleanParameter,
+		Value:       "false",
+		Advanced:    true,
+	})
+	if err := checkBuiltinAction(db, checkCanary); err != nil {
+		return err
+	}
+
 	// ----------------------------------- Serve Static Files -----------------------
 	serveStaticAct := sdk.NewAction(sdk.ServeStaticFiles)
 	serveStaticAct.Type = sdk.BuiltinAction
@@ -285,7 +656,43 @@ Semver used if fully compatible with https://semver.org/
 		Value:       "",
 		Advanced:    true})
 
-	return checkBuiltinAction(db, serveStaticAct)
+	if err := checkBuiltinAction(db, serveStaticAct); err != nil {
+		return err
+	}
+
+	// ----------------------------------- Artifact Push -----------------------
+	artifactPush := sdk.NewAction(sdk.ArtifactPush)
+	artifactPush.Type = sdk.BuiltinAction
+	artifactPush.Description = `CDS Builtin Action.
+Push files to an Artifactory or a Nexus repository, with their checksums and the build-info of the run.
+The server is the Artifact Manager integration of the pipeline context.`
+	artifactPush.Parameter(sdk.Parameter{
+		Name:        "path",
+		Description: "Files to push (example: dist/*.tar.gz)",
+		Type:        sdk.StringParameter})
+	artifactPush.Parameter(sdk.Parameter{
+		Name:        "repository",
+		Description: "Name of the repository",
+		Type:        sdk.StringParameter})
+	artifactPush.Parameter(sdk.Parameter{
+		Name:        "targetPath",
+		Description: "Directory of the files in the repository, you can use the variables of the run",
+		Type:        sdk.StringParameter,
+		Value:       "{{.cds.project}}/{{.cds.workflow}}/{{.cds.version}}"})
+	artifactPush.Parameter(sdk.Parameter{
+		Name:        "buildInfo",
+		Description: "Publish the build-info of the run: the pushed files with their checksums, the vcs revision and the CDS run",
+		Type:        sdk.BooleanParameter,
+		Value:       "true",
+		Advanced:    true})
+	artifactPush.Parameter(sdk.Parameter{
+		Name:        "buildName",
+		Description: "Name of the build in the build-info",
+		Type:        sdk.StringParameter,
+		Value:       "{{.cds.project}}-{{.cds.workflow}}",
+		Advanced:    true})
+
+	return checkBuiltinAction(db, artifactPush)
 }
 
 // checkBuiltinAction add builtin actions in database if needed
@@ -363,6 +770,11 @@ func createBuiltinArtifactUploadAction(db *gorp.DbMap) error {
 		Type:        sdk.BooleanParameter,
 		Description: "Enable artifact upload",
 		Value:       "true"})
+	upload.Parameter(sdk.Parameter{
+		Name:        "signingKey",
+		Type:        sdk.KeyPGPParameter,
+		Description: "PGP key of the project used to sign the checksums of the artifacts. Example: proj-signing",
+		Advanced:    true})
 
 	tx, err := db.Begin()
 	if err != nil {
@@ -10,11 +10,11 @@ import (
 	"github.com/ovh/cds/sdk/log"
 )
 
-func insertEdge(db gorp.SqlExecutor, parentID, childID int64, execOrder int, stepName string, optional, alwaysExecuted, enabled bool) (int64, error) {
-	query := `INSERT INTO action_edge (parent_id, child_id, exec_order, step_name, optional, always_executed, enabled) VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id`
+func insertEdge(db gorp.SqlExecutor, parentID, childID int64, execOrder int, stepName, versionConstraint string, optional, alwaysExecuted, enabled bool) (int64, error) {
+	query := `INSERT INTO action_edge (parent_id, child_id, exec_order, step_name, version_constraint, optional, always_executed, enabled) VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id`
 
 	var id int64
-	err := db.QueryRow(query, parentID, childID, execOrder, stepName, optional, alwaysExecuted, enabled).Scan(&id)
+	err := db.QueryRow(query, parentID, childID, execOrder, stepName, versionConstraint, optional, alwaysExecuted, enabled).Scan(&id)
 	if err != nil {
 		return 0, err
 	}
@@ -32,7 +32,7 @@ func insertActionChild(db gorp.SqlExecutor, actionID int64, child sdk.Action, ex
 		child.StepName = ""
 	}
 
-	id, err := insertEdge(db, actionID, child.ID, execOrder, child.StepName, child.Optional, child.AlwaysExecuted, child.Enabled)
+	id, err := insertEdge(db, actionID, child.ID, execOrder, child.StepName, child.VersionConstraint, child.Optional, child.AlwaysExecuted, child.Enabled)
 	if err != nil {
 		return err
 	}
@@ -73,7 +73,7 @@ func loadActionChildren(db gorp.SqlExecutor, actionID int64) ([]sdk.Action, erro
 	var children []sdk.Action
 	var edgeIDs []int64
 	var childrenIDs []int64
-	query := `SELECT id, child_id, exec_order, step_name, optional, always_executed, enabled FROM action_edge WHERE parent_id = $1 ORDER BY exec_order ASC`
+	query := `SELECT id, child_id, exec_order, step_name, version_constraint, optional, always_executed, enabled FROM action_edge WHERE parent_id = $1 ORDER BY exec_order ASC`
 
 	rows, err := db.Query(query, actionID)
 	if err != nil {
@@ -83,21 +83,23 @@ func loadActionChildren(db gorp.SqlExecutor, actionID int64) ([]sdk.Action, erro
 
 	var edgeID, childID int64
 	var execOrder int
-	var stepName string
+	var stepName, versionConstraint string
 	var optional, alwaysExecuted, enabled bool
 	var mapStepName = make(map[int64]string)
+	var mapVersionConstraint = make(map[int64]string)
 	var mapOptional = make(map[int64]bool)
 	var mapAlwaysExecuted = make(map[int64]bool)
 	var mapEnabled = make(map[int64]bool)
 
 	for rows.Next() {
-		err = rows.Scan(&edgeID, &childID, &execOrder, &stepName, &optional, &alwaysExecuted, &enabled)
+		err = rows.Scan(&edgeID, &childID, &execOrder, &stepName, &versionConstraint, &optional, &alwaysExecuted, &enabled)
 		if err != nil {
 			return nil, err
 		}
 		edgeIDs = append(edgeIDs, edgeID)
 		childrenIDs = append(childrenIDs, childID)
 		mapStepName[edgeID] = stepName
+		mapVersionConstraint[edgeID] = versionConstraint
 		mapOptional[edgeID] = optional
 		mapAlwaysExecuted[edgeID] = alwaysExecuted
 		mapEnabled[edgeID] = enabled
@@ -123,6 +125,7 @@ func loadActionChildren(db gorp.SqlExecutor, actionID int64) ([]sdk.Action, erro
 		// and delete one won't be there anymore
 		replaceChildActionParameters(&children[i], params)
 		children[i].StepName = mapStepName[edgeIDs[i]]
+		children[i].VersionConstraint = mapVersionConstraint[edgeIDs[i]]
 		// Get optional & always_executed flags
 		children[i].Optional = mapOptional[edgeIDs[i]]
 		children[i].AlwaysExecuted = mapAlwaysExecuted[edgeIDs[i]]
@@ -0,0 +1,68 @@
+package action
+
+import (
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// LoadRepositoryAction loads an action defined in the .cds/actions directory of a repository at the given commit
+func LoadRepositoryAction(db gorp.SqlExecutor, projectID int64, fromRepository, commit, name string) (*sdk.Action, error) {
+	query := `SELECT id, name, version, description, type, last_modified, enabled, deprecated FROM action
+	WHERE project_id = $1 AND from_repository = $2 AND repository_commit = $3 AND lower(action.name) = lower($4) AND public = false`
+	a, err := loadActions(db, query, projectID, fromRepository, commit, name)
+	if err != nil {
+		return nil, err
+	}
+	return &a[0], nil
+}
+
+// ImportRepositoryAction inserts an action defined in a repository. Actions are versioned by commit: an action
+// already imported from the same commit is reused, so that the pipelines imported from an older commit keep
+// their version of the action.
+func ImportRepositoryAction(db gorp.SqlExecutor, projectID int64, fromRepository, commit string, a *sdk.Action, userID int64) error {
+	a.Type = sdk.DefaultAction
+
+	existing, err := LoadRepositoryAction(db, projectID, fromRepository, commit, a.Name)
+	if err != nil && !sdk.ErrorIs(err, sdk.ErrNoAction) {
+		return sdk.WrapError(err, "unable to load action %s", a.Name)
+	}
+	if existing != nil {
+		a.ID = existing.ID
+		// Without commit, the action is not versioned and is updated in place
+		if commit == "" {
+			return UpdateActionDB(db, a, userID)
+		}
+		return nil
+	}
+
+	if err := InsertAction(db, a, false); err != nil {
+		return sdk.WrapError(err, "unable to insert action %s", a.Name)
+	}
+	query := `UPDATE action SET project_id = $1, from_repository = $2, repository_commit = $3 WHERE id = $4`
+	if _, err := db.Exec(query, projectID, fromRepository, commit, a.ID); err != nil {
+		return sdk.WrapError(err, "unable to update action %s", a.Name)
+	}
+	return nil
+}
+
+// DeleteUnusedRepositoryActions deletes the versions of the actions of a repository which are not used anymore
+// by a pipeline
+func DeleteUnusedRepositoryActions(db gorp.SqlExecutor, projectID int64, fromRepository string, userID int64) error {
+	var ids []int64
+	query := `SELECT id FROM action
+	WHERE project_id = $1 AND from_repository = $2 AND public = false
+	AND id NOT IN (SELECT child_id FROM action_edge)
+	AND id NOT IN (SELECT action_id FROM pipeline_action)`
+	if _, err := db.Select(&ids, query, projectID, fromRepository); err != nil {
+		return sdk.WrapError(err, "unable to load unused actions of repository %s", fromRepository)
+	}
+	for _, id := range ids {
+		log.Debug("DeleteUnusedRepositoryActions> Deleting action %d of repository %s", id, fromRepository)
+		if err := DeleteAction(db, id, userID); err != nil {
+			return sdk.WrapError(err, "unable to delete action %d", id)
+		}
+	}
+	return nil
+}
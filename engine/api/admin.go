@@ -4,11 +4,14 @@ import (
 	"context"
 	"io/ioutil"
 	"net/http"
+	"strconv"
 	"strings"
 
 	"github.com/gorilla/mux"
 
+	"github.com/ovh/cds/engine/api/auth"
 	"github.com/ovh/cds/engine/api/group"
+	"github.com/ovh/cds/engine/api/project"
 	"github.com/ovh/cds/engine/api/services"
 	"github.com/ovh/cds/engine/service"
 	"github.com/ovh/cds/sdk"
@@ -17,11 +20,25 @@ import (
 
 func (api *API) postMaintenanceHandler() service.Handler {
 	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
-		enable := FormString(r, "enable")
-		api.Cache.Publish(sdk.MaintenanceQueueName, enable)
+		enable, err := strconv.ParseBool(FormString(r, "enable"))
+		if err != nil {
+			return sdk.NewErrorFrom(sdk.ErrWrongRequest, "invalid value %s for enable", FormString(r, "enable"))
+		}
+		api.setMaintenance(enable)
 		return nil
 	}
 }
+
+func (api *API) getMaintenanceHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		s, err := api.maintenanceStatus()
+		if err != nil {
+			return err
+		}
+		return service.WriteJSON(w, s, http.StatusOK)
+	}
+}
+
 func (api *API) adminTruncateWarningsHandler() service.Handler {
 	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
 		if _, err := api.mustDB().Exec("delete from warning"); err != nil {
@@ -176,3 +193,61 @@ func putPostAdminServiceCallHandler(api *API, method string) service.Handler {
 		return service.Write(w, btes, code, "application/json")
 	}
 }
+
+// postAdminRotateSecretsHandler creates a new data key for the given project, or for all the projects,
+// and re-encrypts their secrets with it
+func (api *API) postAdminRotateSecretsHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		u := deprecatedGetUser(ctx)
+
+		var projs []sdk.Project
+		if key := FormString(r, "project"); key != "" {
+			p, err := project.Load(api.mustDB(), api.Cache, key, u)
+			if err != nil {
+				return sdk.WrapError(err, "cannot load project %s", key)
+			}
+			projs = append(projs, *p)
+		} else {
+			var err error
+			projs, err = project.LoadAll(ctx, api.mustDB(), api.Cache, u)
+			if err != nil {
+				return sdk.WrapError(err, "cannot load projects")
+			}
+		}
+
+		res := make(map[string]int, len(projs))
+		for _, p := range projs {
+			n, err := api.rotateProjectSecrets(p.ID)
+			if err != nil {
+				return sdk.WrapError(err, "cannot rotate secrets of project %s", p.Key)
+			}
+			log.Info("postAdminRotateSecretsHandler> %d secrets of project %s re-encrypted", n, p.Key)
+			res[p.Key] = n
+		}
+
+		return service.WriteJSON(w, res, http.StatusOK)
+	}
+}
+
+func (api *API) rotateProjectSecrets(projectID int64) (int, error) {
+	tx, err := api.mustDB().Begin()
+	if err != nil {
+		return 0, sdk.WithStack(err)
+	}
+	defer tx.Rollback() // nolint
+
+	n, err := project.RotateEncryptionKey(tx, projectID)
+	if err != nil {
+		return 0, err
+	}
+	return n, sdk.WithStack(tx.Commit())
+}
+
+func (api *API) getAdminLDAPGroupSyncStatusHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		if !api.Config.Auth.LDAP.Enable || !api.Config.Auth.LDAP.GroupSync.Enable {
+			return service.WriteJSON(w, sdk.LDAPGroupSyncStatus{Changes: []sdk.LDAPGroupSyncChange{}}, http.StatusOK)
+		}
+		return service.WriteJSON(w, auth.LoadLDAPGroupSyncStatus(api.Cache), http.StatusOK)
+	}
+}
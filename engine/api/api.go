@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
 	"os"
 	"strings"
@@ -14,21 +15,26 @@ import (
 	"github.com/gorilla/mux"
 	"go.opencensus.io/stats"
 
+	"github.com/ovh/cds/engine/api/accesstoken"
 	"github.com/ovh/cds/engine/api/action"
+	"github.com/ovh/cds/engine/api/auditlog"
 	"github.com/ovh/cds/engine/api/auth"
 	"github.com/ovh/cds/engine/api/bootstrap"
 	"github.com/ovh/cds/engine/api/broadcast"
 	"github.com/ovh/cds/engine/api/cache"
 	"github.com/ovh/cds/engine/api/database"
+	"github.com/ovh/cds/engine/api/database/dbmigrate"
 	"github.com/ovh/cds/engine/api/event"
 	"github.com/ovh/cds/engine/api/feature"
 	"github.com/ovh/cds/engine/api/group"
 	"github.com/ovh/cds/engine/api/integration"
+	"github.com/ovh/cds/engine/api/jobtoken"
 	"github.com/ovh/cds/engine/api/mail"
 	"github.com/ovh/cds/engine/api/metrics"
 	"github.com/ovh/cds/engine/api/migrate"
 	"github.com/ovh/cds/engine/api/notification"
 	"github.com/ovh/cds/engine/api/objectstore"
+	"github.com/ovh/cds/engine/api/provenance"
 	"github.com/ovh/cds/engine/api/purge"
 	"github.com/ovh/cds/engine/api/repositoriesmanager"
 	"github.com/ovh/cds/engine/api/secret"
@@ -51,24 +57,33 @@ type Configuration struct {
 		UI  string `toml:"ui" default:"http://localhost:2015" json:"ui"`
 	} `toml:"url" comment:"#####################\n CDS URLs Settings \n####################" json:"url"`
 	HTTP struct {
-		Addr       string `toml:"addr" default:"" commented:"true" comment:"Listen HTTP address without port, example: 127.0.0.1" json:"addr"`
-		Port       int    `toml:"port" default:"8081" json:"port"`
-		SessionTTL int    `toml:"sessionTTL" default:"60" json:"sessionTTL"`
+		Addr           string `toml:"addr" default:"" commented:"true" comment:"Listen HTTP address without port, example: 127.0.0.1" json:"addr"`
+		Port           int    `toml:"port" default:"8081" json:"port"`
+		SessionTTL     int    `toml:"sessionTTL" default:"60" json:"sessionTTL"`
+		TrustedProxies string `toml:"trustedProxies" default:"" comment:"Comma separated CIDRs of the reverse proxies in front of the API, the client address is read from their X-Forwarded-For header" json:"trustedProxies"`
 	} `toml:"http" json:"http"`
 	GRPC struct {
 		Addr string `toml:"addr" default:"" commented:"true" comment:"Listen GRPC address without port, example: 127.0.0.1" json:"addr"`
 		Port int    `toml:"port" default:"8082" json:"port"`
 	} `toml:"grpc" json:"grpc"`
 	Secrets struct {
-		Key string `toml:"key" json:"-"`
+		Key string                  `toml:"key" json:"-"`
+		KMS secret.KMSConfiguration `toml:"kms" comment:"KMS used to wrap the data key of each project, the data keys encrypt the secrets of the projects" json:"kms"`
 	} `toml:"secrets" json:"secrets"`
 	Database database.DBConfiguration `toml:"database" comment:"################################\n Postgresql Database settings \n###############################" json:"database"`
 	Cache    struct {
-		TTL   int `toml:"ttl" default:"60" json:"ttl"`
-		Redis struct {
-			Host     string `toml:"host" default:"localhost:6379" comment:"If your want to use a redis-sentinel based cluster, follow this syntax! <clustername>@sentinel1:26379,sentinel2:26379,sentinel3:26379" json:"host"`
+		TTL     int    `toml:"ttl" default:"60" json:"ttl"`
+		Backend string `toml:"backend" default:"redis" comment:"Cache backend: redis, memory or memcached\nmemory needs no redis but can only be used with a single API instance\nmemcached stores the cached values in memcached, redis is still used for the queues, pub/sub and locks" json:"backend"`
+		Redis   struct {
+			Host     string `toml:"host" default:"localhost:6379" comment:"If your want to use a redis-sentinel based cluster, follow this syntax! <clustername>@sentinel1:26379,sentinel2:26379,sentinel3:26379\nIf your want to use a redis cluster, list its nodes: node1:7000,node2:7000,node3:7000" json:"host"`
 			Password string `toml:"password" json:"-"`
 		} `toml:"redis" comment:"Connect CDS to a redis cache If you more than one CDS instance and to avoid losing data at startup" json:"redis"`
+		Memory struct {
+			MaxEntries int `toml:"maxEntries" default:"100000" comment:"Max number of cached values, the least recently used are evicted" json:"maxEntries"`
+		} `toml:"memory" comment:"Settings of the memory backend" json:"memory"`
+		Memcached struct {
+			Servers string `toml:"servers" default:"localhost:11211" comment:"Comma separated list of memcached servers: host1:11211,host2:11211" json:"servers"`
+		} `toml:"memcached" comment:"Settings of the memcached backend" json:"memcached"`
 	} `toml:"cache" comment:"######################\n CDS Cache Settings \n#####################\n" json:"cache"`
 	Directories struct {
 		Download string `toml:"download" default:"/tmp/cds/download" json:"download"`
@@ -77,17 +92,37 @@ type Configuration struct {
 	Auth struct {
 		DefaultGroup     string `toml:"defaultGroup" default:"" comment:"The default group is the group in which every new user will be granted at signup" json:"defaultGroup"`
 		SharedInfraToken string `toml:"sharedInfraToken" default:"" comment:"Token for shared.infra group. This value will be used when shared.infra will be created\nat first CDS launch. This token can be used by CDS CLI, Hatchery, etc...\nThis is mandatory." json:"-"`
-		LDAP             struct {
-			Enable   bool   `toml:"enable" default:"false" json:"enable"`
-			Host     string `toml:"host" json:"host"`
-			Port     int    `toml:"port" default:"636" json:"port"`
-			SSL      bool   `toml:"ssl" default:"true" json:"ssl"`
-			Base     string `toml:"base" default:"dc=myorganization,dc=com" json:"base"`
-			DN       string `toml:"dn" default:"uid=%s,ou=people,dc=myorganization,dc=com" json:"dn"`
-			Fullname string `toml:"fullname" default:"{{.givenName}} {{.sn}}" json:"fullname"`
-			BindDN   string `toml:"bindDN" default:"" comment:"Define it if ldapsearch need to be authenticated" json:"bindDN"`
-			BindPwd  string `toml:"bindPwd" default:"" comment:"Define it if ldapsearch need to be authenticated" json:"-"`
+		RSAPrivateKey    string `toml:"rsaPrivateKey" default:"" comment:"PEM encoded RSA private key used to sign the personal access tokens. Generate one with: openssl genrsa 2048" json:"-"`
+		JobToken         struct {
+			RSAPrivateKey string `toml:"rsaPrivateKey" default:"" comment:"PEM encoded RSA private key used to sign the OIDC tokens given to the jobs as CDS_JOB_TOKEN. Generate one with: openssl genrsa 2048" json:"-"`
+			Audience      string `toml:"audience" default:"cds" comment:"Audience of the job tokens, as expected by the cloud providers trusting CDS" json:"audience"`
+			TTL           int    `toml:"ttl" default:"60" comment:"Validity of the job tokens in minutes" json:"ttl"`
+		} `toml:"jobToken" comment:"The public keys verifying the job tokens are served on /.well-known/jwks.json" json:"jobToken"`
+		LDAP struct {
+			Enable    bool   `toml:"enable" default:"false" json:"enable"`
+			Host      string `toml:"host" json:"host"`
+			Port      int    `toml:"port" default:"636" json:"port"`
+			SSL       bool   `toml:"ssl" default:"true" json:"ssl"`
+			Base      string `toml:"base" default:"dc=myorganization,dc=com" json:"base"`
+			DN        string `toml:"dn" default:"uid=%s,ou=people,dc=myorganization,dc=com" json:"dn"`
+			Fullname  string `toml:"fullname" default:"{{.givenName}} {{.sn}}" json:"fullname"`
+			BindDN    string `toml:"bindDN" default:"" comment:"Define it if ldapsearch need to be authenticated" json:"bindDN"`
+			BindPwd   string `toml:"bindPwd" default:"" comment:"Define it if ldapsearch need to be authenticated" json:"-"`
+			GroupSync struct {
+				Enable          bool   `toml:"enable" default:"false" comment:"Synchronize periodically the CDS groups with the LDAP groups" json:"enable"`
+				Interval        int    `toml:"interval" default:"15" comment:"Interval in minutes between two synchronizations" json:"interval"`
+				Base            string `toml:"base" default:"ou=groups,dc=myorganization,dc=com" json:"base"`
+				Filter          string `toml:"filter" default:"(objectClass=posixGroup)" json:"filter"`
+				NameAttribute   string `toml:"nameAttribute" default:"cn" json:"nameAttribute"`
+				MemberAttribute string `toml:"memberAttribute" default:"memberUid" comment:"Attribute giving the uids of the members, or their DNs starting with their uid" json:"memberAttribute"`
+				Prefix          string `toml:"prefix" default:"" comment:"Prefix added to the name of the LDAP groups to get the name of the CDS groups" json:"prefix"`
+				DryRun          bool   `toml:"dryRun" default:"false" comment:"Only compute the changes, see them with cdsctl admin ldap sync-status" json:"dryRun"`
+			} `toml:"groupSync" json:"groupSync"`
 		} `toml:"ldap" json:"ldap"`
+		SCIM struct {
+			Enable bool   `toml:"enable" default:"false" comment:"Enable the SCIM 2.0 API (/scim/v2) to provision users and groups from an identity provider" json:"enable"`
+			Token  string `toml:"token" default:"" comment:"Bearer token used by the identity provider on the SCIM API" json:"-"`
+		} `toml:"scim" json:"scim"`
 		Local struct {
 			SignupAllowedDomains string `toml:"signupAllowedDomains" default:"" comment:"Allow signup from selected domains only - comma separated. Example: your-domain.com,another-domain.com" commented:"true" json:"signupAllowedDomains"`
 		} `toml:"local" json:"local"`
@@ -116,6 +151,9 @@ type Configuration struct {
 			ContainerPrefix string `toml:"containerPrefix" comment:"Use if your want to prefix containers for CDS Artifacts" json:"containerPrefix"`
 			DisableTempURL  bool   `toml:"disableTempURL" default:"false" commented:"true" comment:"True if you want to disable Temporary URL in file upload" json:"disableTempURL"`
 		} `toml:"openstack" json:"openstack"`
+		Provenance struct {
+			SigningKey string `toml:"signingKey" comment:"Base64 encoded ed25519 seed used to sign the provenance of the artifacts. Generate one with: openssl rand -base64 32" json:"-"`
+		} `toml:"provenance" json:"provenance"`
 	} `toml:"artifact" comment:"Either filesystem local storage or Openstack Swift Storage are supported" json:"artifact"`
 	Events struct {
 		Kafka struct {
@@ -126,6 +164,8 @@ type Configuration struct {
 			Password        string `toml:"password" json:"-"`
 			MaxMessageBytes int    `toml:"maxmessagebytes" default:"10000000" json:"maxmessagebytes"`
 		} `toml:"kafka" json:"kafka"`
+		Outbox         event.OutboxConfig `toml:"outbox" comment:"The events of the workflow runs are saved with the runs and sent to the brokers until they acknowledge them" json:"outbox"`
+		SSEHistorySize int                `toml:"sseHistorySize" default:"1000" comment:"Number of events kept to resume the events streams of the clients reconnecting with the Last-Event-ID header" json:"sseHistorySize"`
 	} `toml:"events" comment:"#######################\n CDS Events Settings \n######################" json:"events"`
 	Features struct {
 		Izanami struct {
@@ -140,10 +180,14 @@ type Configuration struct {
 	} `toml:"vault" json:"vault"`
 	Providers []ProviderConfiguration `toml:"providers" comment:"###########################\n CDS Providers Settings \n##########################" json:"providers"`
 	Services  []ServiceConfiguration  `toml:"services" comment:"###########################\n CDS Services Settings \n##########################" json:"services"`
+	Remotes   []RemoteConfiguration   `toml:"remotes" comment:"###########################\n CDS instances the workflows can be promoted to \n##########################" json:"remotes"`
 	Status    struct {
 		API struct {
 			MinInstance int `toml:"minInstance" default:"1" comment:"if less than minInstance of API is running, an alert will on Global/API be created on /mon/status" json:"minInstance"`
 		} `toml:"api" json:"api"`
+		CDN struct {
+			MinInstance int `toml:"minInstance" default:"0" comment:"if less than minInstance of cdn service is running, an alert on Global/cdn will be created on /mon/status" json:"minInstance"`
+		} `toml:"cdn" json:"cdn"`
 		DBMigrate struct {
 			MinInstance int `toml:"minInstance" default:"1" comment:"if less than minInstance of dbmigrate service is running, an alert on Global/dbmigrate will be created on /mon/status" json:"minInstance"`
 		} `toml:"dbmigrate" json:"dbmigrate"`
@@ -174,6 +218,17 @@ type Configuration struct {
 		StepMaxSize    int64 `toml:"stepMaxSize" default:"15728640" comment:"Max step logs size in bytes (default: 15MB)" json:"stepMaxSize"`
 		ServiceMaxSize int64 `toml:"serviceMaxSize" default:"15728640" comment:"Max service logs size in bytes (default: 15MB)" json:"serviceMaxSize"`
 	} `toml:"log" json:"log" comment:"###########################\n Log settings.\n##########################"`
+	Audit struct {
+		Retention int                          `toml:"retention" default:"365" comment:"Number of days the audit logs are kept, 0 to keep them forever" json:"retention"`
+		Export    auditlog.ExportConfiguration `toml:"export" comment:"Stream the audit logs to a SIEM, with syslog (CEF or LEEF format) or a HTTPS webhook" json:"export"`
+	} `toml:"audit" json:"audit" comment:"###########################\n Audit trail settings.\n##########################"`
+	Queue struct {
+		DefaultProjectQuota int  `toml:"defaultProjectQuota" default:"0" comment:"Maximum number of jobs of a project building at the same time, 0 for no limit. An administrator can override it for each project" json:"defaultProjectQuota"`
+		FairScheduling      bool `toml:"fairScheduling" default:"true" comment:"Share the workers between the groups according to their weight. If false, the jobs are taken in the order they were queued" json:"fairScheduling"`
+	} `toml:"queue" json:"queue" comment:"###########################\n Job queue scheduling settings.\n##########################"`
+	Trash struct {
+		Retention int `toml:"retention" default:"30" comment:"Number of days the deleted workflows and pipelines are kept in the trash of their project, they can be restored until they are purged. 0 to purge them immediately" json:"retention"`
+	} `toml:"trash" json:"trash" comment:"###########################\n Trash of the deleted workflows and pipelines.\n##########################"`
 }
 
 // ProviderConfiguration is the piece of configuration for each provider authentication
@@ -182,6 +237,14 @@ type ProviderConfiguration struct {
 	Token string `toml:"token" json:"-"`
 }
 
+// RemoteConfiguration is the configuration of a CDS instance the workflows can be promoted to
+type RemoteConfiguration struct {
+	Name                  string `toml:"name" json:"name"`
+	URL                   string `toml:"url" comment:"URL of the API of the remote instance" json:"url"`
+	Token                 string `toml:"token" comment:"Access token of an administrator of the remote instance" json:"-"`
+	InsecureSkipVerifyTLS bool   `toml:"insecureSkipVerifyTLS" json:"insecureSkipVerifyTLS"`
+}
+
 // ServiceConfiguration is the configuration of external service
 type ServiceConfiguration struct {
 	Name       string `toml:"name" json:"name"`
@@ -231,6 +294,7 @@ type API struct {
 	Maintenance         bool
 	eventsBroker        *eventsBroker
 	warnChan            chan sdk.Event
+	trustedProxies      []*net.IPNet
 	Cache               cache.Store
 	Metrics             struct {
 		WorkflowRunFailed        *stats.Int64Measure
@@ -250,6 +314,12 @@ type API struct {
 		queue                    *stats.Int64Measure
 		WorkflowRunsMarkToDelete *stats.Int64Measure
 		WorkflowRunsDeleted      *stats.Int64Measure
+		jobSchedulingLatency     *stats.Float64Measure
+		dbOpenConnections        *stats.Int64Measure
+		dbInUseConnections       *stats.Int64Measure
+		dbIdleConnections        *stats.Int64Measure
+		dbWaitCount              *stats.Int64Measure
+		dbWaitDuration           *stats.Int64Measure
 	}
 }
 
@@ -340,6 +410,22 @@ func (a *API) CheckConfiguration(config interface{}) error {
 		}
 	}
 
+	if aConfig.Auth.SCIM.Enable && aConfig.Auth.SCIM.Token == "" {
+		return fmt.Errorf("Invalid SCIM token")
+	}
+
+	if aConfig.Auth.LDAP.GroupSync.Enable && aConfig.Auth.LDAP.GroupSync.Interval <= 0 {
+		return fmt.Errorf("Invalid LDAP groups synchronization interval")
+	}
+
+	if aConfig.Auth.JobToken.RSAPrivateKey != "" && aConfig.Auth.JobToken.TTL <= 0 {
+		return fmt.Errorf("Invalid job token ttl")
+	}
+
+	if _, err := ParseCIDRs(aConfig.HTTP.TrustedProxies); err != nil {
+		return fmt.Errorf("Invalid trusted proxies: %v", err)
+	}
+
 	if len(aConfig.Secrets.Key) != 32 {
 		return fmt.Errorf("Invalid secret key. It should be 32 bits (%d)", len(aConfig.Secrets.Key))
 	}
@@ -465,6 +551,18 @@ func (a *API) mustDB() *gorp.DbMap {
 	return db
 }
 
+// mustDBForRead returns a read replica on the routes allowing it, the primary database otherwise
+func (a *API) mustDBForRead(ctx context.Context) *gorp.DbMap {
+	if readReplica, _ := ctx.Value(contextReadReplica).(bool); !readReplica {
+		return a.mustDB()
+	}
+	db := a.DBConnectionFactory.GetReadDBMap()
+	if db == nil {
+		panic(fmt.Errorf("Database unavailable"))
+	}
+	return db
+}
+
 func (a *API) mustDBWithCtx(ctx context.Context) *gorp.DbMap {
 	db := a.DBConnectionFactory.GetDBMap()
 	db = db.WithContext(ctx).(*gorp.DbMap)
@@ -483,6 +581,16 @@ func (a *API) Serve(ctx context.Context) error {
 
 	//Initialize secret driver
 	secret.Init(a.Config.Secrets.Key)
+	kms, err := secret.NewKMS(a.Config.Secrets.KMS)
+	if err != nil {
+		return fmt.Errorf("Unable to initialize kms: %v", err)
+	}
+	secret.InitKMS(kms, func() gorp.SqlExecutor { return a.mustDB() })
+
+	auditExport, err := auditlog.NewExport(a.Config.Audit.Export)
+	if err != nil {
+		return fmt.Errorf("Unable to initialize audit export: %v", err)
+	}
 
 	//Initialize mail package
 	log.Info("Initializing mail driver...")
@@ -539,6 +647,30 @@ func (a *API) Serve(ctx context.Context) error {
 		return fmt.Errorf("cannot initialize storage: %v", err)
 	}
 
+	if err := provenance.Init(a.Config.Artifact.Provenance.SigningKey); err != nil {
+		return fmt.Errorf("cannot initialize provenance: %v", err)
+	}
+	if provenance.Key() == nil {
+		log.Warning("The provenance of the artifacts will not be signed: artifact.provenance.signingKey is not set")
+	}
+
+	if a.Config.Auth.RSAPrivateKey != "" {
+		if err := accesstoken.Init(a.Config.URL.API, []byte(a.Config.Auth.RSAPrivateKey)); err != nil {
+			return fmt.Errorf("cannot initialize access tokens: %v", err)
+		}
+	} else {
+		log.Warning("The personal access tokens are disabled: auth.rsaPrivateKey is not set")
+	}
+
+	a.trustedProxies, err = ParseCIDRs(a.Config.HTTP.TrustedProxies)
+	if err != nil {
+		return fmt.Errorf("invalid trusted proxies: %v", err)
+	}
+
+	if err := jobtoken.Init(a.Config.URL.API, a.Config.Auth.JobToken.Audience, time.Duration(a.Config.Auth.JobToken.TTL)*time.Minute, a.Config.Auth.JobToken.RSAPrivateKey); err != nil {
+		return fmt.Errorf("cannot initialize job tokens: %v", err)
+	}
+
 	log.Info("Initializing database connection...")
 	//Intialize database
 	var errDB error
@@ -557,6 +689,22 @@ func (a *API) Serve(ctx context.Context) error {
 		return fmt.Errorf("cannot connect to database: %v", errDB)
 	}
 
+	// Only a database schema with incompatible migrations stops the API, the backward compatible
+	// migration scripts can be applied before the upgrade
+	if err := dbmigrate.CheckCompatibility(a.DBConnectionFactory.DB()); err != nil {
+		return err
+	}
+
+	if a.Config.Database.Replicas != "" {
+		log.Info("Initializing database replicas...")
+		if err := a.DBConnectionFactory.InitReplicas(a.Config.Database.Replicas, time.Duration(a.Config.Database.ReplicaMaxLag)*time.Second); err != nil {
+			return fmt.Errorf("cannot connect to database replicas: %v", err)
+		}
+		sdk.GoRoutine(ctx, "database.CheckReplicasLag", func(ctx context.Context) {
+			a.DBConnectionFactory.Replicas.CheckLag(ctx, 5*time.Second)
+		}, a.PanicDump())
+	}
+
 	log.Info("Bootstrapping database...")
 	defaultValues := sdk.DefaultValues{
 		DefaultGroupName: a.Config.Auth.DefaultGroup,
@@ -578,13 +726,17 @@ func (a *API) Serve(ctx context.Context) error {
 		return fmt.Errorf("cannot setup integrations: %v", err)
 	}
 
-	log.Info("Initializing redis cache on %s...", a.Config.Cache.Redis.Host)
+	log.Info("Initializing %s cache...", a.Config.Cache.Backend)
 	//Init the cache
 	var errCache error
-	a.Cache, errCache = cache.New(
-		a.Config.Cache.Redis.Host,
-		a.Config.Cache.Redis.Password,
-		a.Config.Cache.TTL)
+	a.Cache, errCache = cache.NewStore(cache.Config{
+		Backend:          a.Config.Cache.Backend,
+		TTL:              a.Config.Cache.TTL,
+		RedisHost:        a.Config.Cache.Redis.Host,
+		RedisPassword:    a.Config.Cache.Redis.Password,
+		MaxEntries:       a.Config.Cache.Memory.MaxEntries,
+		MemcachedServers: a.Config.Cache.Memcached.Servers,
+	})
 	if errCache != nil {
 		return fmt.Errorf("cannot connect to cache store: %v", errCache)
 	}
@@ -652,6 +804,9 @@ func (a *API) Serve(ctx context.Context) error {
 		log.Error("error while initializing event system: %s", err)
 	} else {
 		go event.DequeueEvent(ctx)
+		sdk.GoRoutine(ctx, "event.DispatchOutbox", func(ctx context.Context) {
+			event.DispatchOutbox(ctx, a.DBConnectionFactory.GetDBMap, a.Config.Events.Outbox)
+		})
 	}
 
 	a.warnChan = make(chan sdk.Event)
@@ -673,6 +828,9 @@ func (a *API) Serve(ctx context.Context) error {
 	sdk.GoRoutine(ctx, "workflowtemplate.ComputeAudit", func(ctx context.Context) {
 		workflowtemplate.ComputeAudit(ctx, a.DBConnectionFactory.GetDBMap)
 	}, a.PanicDump())
+	sdk.GoRoutine(ctx, "auditlog.Compute", func(ctx context.Context) {
+		auditlog.Compute(ctx, a.DBConnectionFactory.GetDBMap, time.Duration(a.Config.Audit.Retention)*24*time.Hour, auditExport)
+	}, a.PanicDump())
 	sdk.GoRoutine(ctx, "warning.Start", func(ctx context.Context) {
 		warning.Start(ctx, a.DBConnectionFactory.GetDBMap, a.warnChan)
 	}, a.PanicDump())
@@ -694,6 +852,19 @@ func (a *API) Serve(ctx context.Context) error {
 	sdk.GoRoutine(ctx, "api.serviceAPIHeartbeat", func(ctx context.Context) {
 		a.serviceAPIHeartbeat(ctx)
 	}, a.PanicDump())
+	if a.Config.Auth.LDAP.Enable && a.Config.Auth.LDAP.GroupSync.Enable {
+		sdk.GoRoutine(ctx, "auth.LDAPGroupSync", func(ctx context.Context) {
+			auth.LDAPGroupSync(ctx, a.Router.AuthDriver, a.DBConnectionFactory.GetDBMap, a.Cache, auth.LDAPGroupSyncConfig{
+				Interval:        time.Duration(a.Config.Auth.LDAP.GroupSync.Interval) * time.Minute,
+				Base:            a.Config.Auth.LDAP.GroupSync.Base,
+				Filter:          a.Config.Auth.LDAP.GroupSync.Filter,
+				NameAttribute:   a.Config.Auth.LDAP.GroupSync.NameAttribute,
+				MemberAttribute: a.Config.Auth.LDAP.GroupSync.MemberAttribute,
+				Prefix:          a.Config.Auth.LDAP.GroupSync.Prefix,
+				DryRun:          a.Config.Auth.LDAP.GroupSync.DryRun,
+			})
+		}, a.PanicDump())
+	}
 
 	//Temporary migration code
 	//DEPRECATED Migrations
@@ -795,7 +966,7 @@ func (a *API) Serve(ctx context.Context) error {
 		}, a.PanicDump())
 	sdk.GoRoutine(ctx, "Purge",
 		func(ctx context.Context) {
-			purge.Initialize(ctx, a.Cache, a.DBConnectionFactory.GetDBMap, a.Metrics.WorkflowRunsMarkToDelete, a.Metrics.WorkflowRunsDeleted)
+			purge.Initialize(ctx, a.Cache, a.DBConnectionFactory.GetDBMap, a.trashRetention(), a.Metrics.WorkflowRunsMarkToDelete, a.Metrics.WorkflowRunsDeleted)
 		}, a.PanicDump())
 
 	s := &http.Server{
@@ -821,7 +992,7 @@ func (a *API) Serve(ctx context.Context) error {
 
 	go func() {
 		//TLS is disabled for the moment. We need to serve TLS on HTTP too
-		if err := grpcInit(a.DBConnectionFactory, a.Config.GRPC.Addr, a.Config.GRPC.Port, false, "", "", a.Config.Log.StepMaxSize); err != nil {
+		if err := grpcInit(a.Router.Background, a.DBConnectionFactory, a.Cache, a.Config.GRPC.Addr, a.Config.GRPC.Port, false, "", "", a.Config.Log.StepMaxSize, a.Config.Queue.DefaultProjectQuota, func() bool { return a.Maintenance }, a.PanicDump()); err != nil {
 			log.Error("Cannot start GRPC server: %v", err)
 		}
 	}()
@@ -14,11 +14,16 @@ import (
 func (api *API) InitRouter() {
 	api.Router.URL = api.Config.URL.API
 	api.Router.SetHeaderFunc = DefaultHeaders
-	api.Router.Middlewares = append(api.Router.Middlewares, api.authMiddleware, api.tracingMiddleware, api.maintenanceMiddleware)
+	api.Router.Middlewares = append(api.Router.Middlewares, api.authMiddleware, api.authMFAMiddleware, api.tracingMiddleware, api.maintenanceMiddleware, api.readReplicaMiddleware, api.archivedProjectMiddleware)
 	api.Router.PostMiddlewares = append(api.Router.PostMiddlewares, api.deletePermissionMiddleware, TracingPostMiddleware)
 
 	r := api.Router
 	r.Handle("/login", r.POST(api.loginUserHandler, Auth(false)))
+	r.Handle("/login/webauthn", r.POST(api.postLoginWebAuthnChallengeHandler, Auth(false)))
+
+	// OIDC tokens of the jobs
+	r.Handle("/.well-known/openid-configuration", r.GET(api.getOpenIDConfigurationHandler, Auth(false)))
+	r.Handle("/.well-known/jwks.json", r.GET(api.getJWKSHandler, Auth(false)))
 
 	log.Info("Initializing Events broker")
 	// Initialize event broker
@@ -28,6 +33,7 @@ func (api *API) InitRouter() {
 		clients:  make(map[string]*eventsBrokerSubscribe),
 		dbFunc:   api.DBConnectionFactory.GetDBMap,
 		messages: make(chan sdk.Event),
+		history:  newEventsBrokerHistory(api.Config.Events.SSEHistorySize),
 	}
 	api.eventsBroker.Init(context.Background(), api.PanicDump())
 
@@ -45,10 +51,23 @@ func (api *API) InitRouter() {
 	r.Handle("/action/{permActionName}", r.GET(api.getActionHandler), r.POST(api.addActionHandler), r.PUT(api.updateActionHandler), r.DELETE(api.deleteActionHandler))
 	r.Handle("/action/{actionName}/using", r.GET(api.getPipelinesUsingActionHandler, NeedAdmin(true)))
 	r.Handle("/action/{permActionName}/export", r.GET(api.getActionExportHandler))
+	r.Handle("/action/{permActionName}/version", r.GET(api.getActionVersionsHandler))
 	r.Handle("/action/{actionID}/audit", r.GET(api.getActionAuditHandler, NeedAdmin(true)))
 
 	// Admin
-	r.Handle("/admin/maintenance", r.POST(api.postMaintenanceHandler, NeedAdmin(true)))
+	r.Handle("/admin/maintenance", r.GET(api.getMaintenanceHandler, NeedAdmin(true)), r.POST(api.postMaintenanceHandler, NeedAdmin(true)))
+	r.Handle("/admin/audits", r.GET(api.getAdminAuditsHandler, NeedAdmin(true)))
+	r.Handle("/admin/events/outbox", r.GET(api.getAdminEventOutboxHandler, NeedAdmin(true)))
+	r.Handle("/admin/events/outbox/{id}", r.DELETE(api.deleteAdminEventOutboxHandler, NeedAdmin(true)))
+	r.Handle("/admin/events/outbox/{id}/retry", r.POST(api.postAdminEventOutboxRetryHandler, NeedAdmin(true)))
+	r.Handle("/admin/ldap/groups/sync", r.GET(api.getAdminLDAPGroupSyncStatusHandler, NeedAdmin(true)))
+	r.Handle("/admin/cost", r.GET(api.getAdminCostHandler, NeedAdmin(true)))
+	r.Handle("/admin/organization", r.GET(api.getAdminOrganizationsHandler, NeedAdmin(true)), r.POST(api.postAdminOrganizationHandler, NeedAdmin(true)))
+	r.Handle("/admin/queue/quotas", r.GET(api.getAdminQueueQuotasHandler, NeedAdmin(true)))
+	r.Handle("/admin/worker/model/unused", r.GET(api.getAdminUnusedWorkerModelsHandler, NeedAdmin(true)))
+	r.Handle("/admin/worker/pool", r.GET(api.getAdminWorkerPoolsHandler, NeedAdmin(true)))
+	r.Handle("/admin/worker/pool/{pool}/drain", r.POST(api.postAdminWorkerPoolDrainHandler, NeedAdmin(true)))
+	r.Handle("/admin/secrets/rotate", r.POST(api.postAdminRotateSecretsHandler, NeedAdmin(true)))
 	r.Handle("/admin/warning", r.DELETE(api.adminTruncateWarningsHandler, NeedAdmin(true)))
 	r.Handle("/admin/cds/migration", r.GET(api.getAdminMigrationsHandler, NeedAdmin(true)))
 	r.Handle("/admin/cds/migration/{id}/cancel", r.POST(api.postAdminMigrationCancelHandler, NeedAdmin(true)))
@@ -56,6 +75,7 @@ func (api *API) InitRouter() {
 	r.Handle("/admin/database/migration/delete/{id}", r.DELETE(api.deleteDatabaseMigrationHandler, NeedAdmin(true)))
 	r.Handle("/admin/database/migration/unlock/{id}", r.POST(api.postDatabaseMigrationUnlockedHandler, NeedAdmin(true)))
 	r.Handle("/admin/database/migration", r.GET(api.getDatabaseMigrationHandler, NeedAdmin(true)))
+	r.Handle("/admin/database/migration/status", r.GET(api.getDatabaseMigrationStatusHandler, NeedAdmin(true)))
 	r.Handle("/admin/debug", r.GET(api.getProfileIndexHandler, Auth(false)))
 	r.Handle("/admin/debug/trace", r.POST(api.getTraceHandler, NeedAdmin(true)), r.GET(api.getTraceHandler, NeedAdmin(true)))
 	r.Handle("/admin/debug/cpu", r.POST(api.getCPUProfileHandler, NeedAdmin(true)), r.GET(api.getCPUProfileHandler, NeedAdmin(true)))
@@ -71,25 +91,42 @@ func (api *API) InitRouter() {
 	r.Handle("/admin/services", r.GET(api.getAdminServicesHandler, NeedAdmin(true)))
 	r.Handle("/admin/services/call", r.GET(api.getAdminServiceCallHandler, NeedAdmin(true)), r.POST(api.postAdminServiceCallHandler, NeedAdmin(true)), r.PUT(api.putAdminServiceCallHandler, NeedAdmin(true)), r.DELETE(api.deleteAdminServiceCallHandler, NeedAdmin(true)))
 
+	// SCIM 2.0 provisioning
+	r.Handle("/scim/v2/ServiceProviderConfig", r.GET(api.getSCIMServiceProviderConfigHandler, NeedSCIM()))
+	r.Handle("/scim/v2/Users", r.GET(api.getSCIMUsersHandler, NeedSCIM()), r.POST(api.postSCIMUserHandler, NeedSCIM()))
+	r.Handle("/scim/v2/Users/{id}", r.GET(api.getSCIMUserHandler, NeedSCIM()), r.PUT(api.putSCIMUserHandler, NeedSCIM()), r.PATCH(api.patchSCIMUserHandler, NeedSCIM()), r.DELETE(api.deleteSCIMUserHandler, NeedSCIM()))
+	r.Handle("/scim/v2/Groups", r.GET(api.getSCIMGroupsHandler, NeedSCIM()), r.POST(api.postSCIMGroupHandler, NeedSCIM()))
+	r.Handle("/scim/v2/Groups/{id}", r.GET(api.getSCIMGroupHandler, NeedSCIM()), r.PUT(api.putSCIMGroupHandler, NeedSCIM()), r.PATCH(api.patchSCIMGroupHandler, NeedSCIM()), r.DELETE(api.deleteSCIMGroupHandler, NeedSCIM()))
+
 	// Download file
 	r.Handle("/download", r.GET(api.downloadsHandler))
 	r.Handle("/download/{name}/{os}/{arch}", r.GET(api.downloadHandler, Auth(false)))
+	r.Handle("/download/{name}/{os}/{arch}/checksum", r.GET(api.downloadChecksumHandler, Auth(false)))
+
+	// Organization
+	r.Handle("/organization/{name}", r.GET(api.getOrganizationHandler), r.PUT(api.putOrganizationHandler), r.DELETE(api.deleteOrganizationHandler, NeedAdmin(true)))
+	r.Handle("/organization/{name}/admin/{username}", r.POST(api.postOrganizationAdminHandler), r.DELETE(api.deleteOrganizationAdminHandler))
+	r.Handle("/organization/{name}/usage", r.GET(api.getOrganizationUsageHandler))
+	r.Handle("/organization/{name}/project/{key}", r.POST(api.postOrganizationProjectHandler), r.DELETE(api.deleteOrganizationProjectHandler))
 
 	// Group
 	r.Handle("/group", r.GET(api.getGroupsHandler), r.POST(api.addGroupHandler))
 	r.Handle("/group/public", r.GET(api.getPublicGroupsHandler))
 	r.Handle("/group/{permGroupName}", r.GET(api.getGroupHandler), r.PUT(api.updateGroupHandler), r.DELETE(api.deleteGroupHandler))
+	r.Handle("/group/{permGroupName}/mfa", r.GET(api.getGroupMFAPolicyHandler), r.PUT(api.putGroupMFAPolicyHandler, NeedAdmin(true)))
 	r.Handle("/group/{permGroupName}/user", r.POST(api.addUserInGroupHandler))
 	r.Handle("/group/{permGroupName}/user/{user}", r.DELETE(api.removeUserFromGroupHandler))
 	r.Handle("/group/{permGroupName}/user/{user}/admin", r.POST(api.setUserGroupAdminHandler), r.DELETE(api.removeUserGroupAdminHandler))
 	r.Handle("/group/{permGroupName}/token", r.GET(api.getGroupTokenListHandler), r.POST(api.generateTokenHandler))
 	r.Handle("/group/{permGroupName}/token/{tokenid}", r.DELETE(api.deleteTokenHandler))
+	r.Handle("/group/{permGroupName}/queue/weight", r.GET(api.getGroupQueueWeightHandler), r.PUT(api.putGroupQueueWeightHandler, NeedAdmin(true)), r.DELETE(api.deleteGroupQueueWeightHandler, NeedAdmin(true)))
 
 	// Hatchery
 	r.Handle("/hatchery/count/{workflowNodeRunID}", r.GET(api.hatcheryCountHandler))
 
 	// Hooks
 	r.Handle("/hook/{uuid}/workflow/{workflowID}/vcsevent/{vcsServer}", r.GET(api.getHookPollingVCSEvents))
+	r.Handle("/hook/{uuid}/repair", r.POST(api.postHookRepairHandler, NeedService()))
 
 	// Integration
 	r.Handle("/integration/models", r.GET(api.getIntegrationModelsHandler), r.POST(api.postIntegrationModelHandler, NeedAdmin(true)))
@@ -110,6 +147,7 @@ func (api *API) InitRouter() {
 	r.Handle("/mon/panic/{uuid}", r.GET(api.getPanicDumpHandler, Auth(false)))
 
 	r.Handle("/ui/navbar", r.GET(api.getNavbarHandler))
+	r.Handle("/search", r.GET(api.getSearchHandler, ReadReplica()))
 	r.Handle("/ui/project/{permProjectKey}/application/{applicationName}/overview", r.GET(api.getApplicationOverviewHandler))
 
 	// Import As Code
@@ -120,9 +158,30 @@ func (api *API) InitRouter() {
 	// Bookmarks
 	r.Handle("/bookmarks", r.GET(api.getBookmarksHandler))
 
+	// Dashboards
+	r.Handle("/dashboard", r.GET(api.getDashboardsHandler), r.POST(api.postDashboardHandler))
+	r.Handle("/dashboard/{id}", r.GET(api.getDashboardHandler), r.PUT(api.putDashboardHandler), r.DELETE(api.deleteDashboardHandler))
+	r.Handle("/dashboard/{id}/data", r.GET(api.getDashboardDataHandler))
+
 	// Project
 	r.Handle("/project", r.GET(api.getProjectsHandler, AllowProvider(true), EnableTracing()), r.POST(api.addProjectHandler))
+	r.Handle("/project/archive", r.POST(api.postProjectArchiveHandler, NeedAdmin(true)))
 	r.Handle("/project/{permProjectKey}", r.GET(api.getProjectHandler), r.PUT(api.updateProjectHandler), r.DELETE(api.deleteProjectHandler))
+	r.Handle("/project/{permProjectKey}/archive", r.GET(api.getProjectArchiveHandler))
+	r.Handle("/project/{permProjectKey}/dashboard", r.GET(api.getProjectDashboardHandler))
+	r.Handle("/project/{permProjectKey}/cost", r.GET(api.getProjectCostHandler))
+	r.Handle("/project/{permProjectKey}/deployments", r.GET(api.getProjectDeploymentsHandler))
+	r.Handle("/project/{permProjectKey}/deployments/current", r.GET(api.getProjectCurrentDeploymentsHandler))
+	r.Handle("/project/{permProjectKey}/deployments/rollback", r.POST(api.postProjectDeploymentRollbackHandler))
+	r.Handle("/project/{permProjectKey}/dora", r.GET(api.getProjectDORAMetricsHandler))
+	r.Handle("/project/{permProjectKey}/usage", r.GET(api.getProjectUsageHandler))
+	r.Handle("/project/{permProjectKey}/archival", r.POST(api.postProjectArchivalHandler), r.DELETE(api.deleteProjectArchivalHandler, NeedAdmin(true), AllowArchivedProject()))
+	r.Handle("/project/{permProjectKey}/trash", r.GET(api.getProjectTrashHandler))
+	r.Handle("/project/{permProjectKey}/trash/{type}/{name}", r.DELETE(api.deleteProjectTrashHandler))
+	r.Handle("/project/{permProjectKey}/trash/{type}/{name}/restore", r.POST(api.postProjectTrashRestoreHandler))
+	r.Handle("/project/{permProjectKey}/audits", r.GET(api.getProjectAuditsHandler))
+	r.Handle("/project/{permProjectKey}/queue/quota", r.GET(api.getProjectQueueQuotaHandler), r.PUT(api.putProjectQueueQuotaHandler, NeedAdmin(true)), r.DELETE(api.deleteProjectQueueQuotaHandler, NeedAdmin(true)))
+	r.Handle("/project/{permProjectKey}/sbom/components", r.GET(api.getProjectSBOMComponentRunsHandler))
 	r.Handle("/project/{permProjectKey}/labels", r.PUT(api.putProjectLabelsHandler))
 	r.Handle("/project/{permProjectKey}/group", r.POST(api.addGroupInProjectHandler))
 	r.Handle("/project/{permProjectKey}/group/import", r.POST(api.importGroupsInProjectHandler, DEPRECATED))
@@ -135,10 +194,11 @@ func (api *API) InitRouter() {
 	r.Handle("/project/{permProjectKey}/applications", r.GET(api.getApplicationsHandler, AllowProvider(true)), r.POST(api.addApplicationHandler))
 	r.Handle("/project/{permProjectKey}/integrations", r.GET(api.getProjectIntegrationsHandler), r.POST(api.postProjectIntegrationHandler))
 	r.Handle("/project/{permProjectKey}/integrations/{integrationName}", r.GET(api.getProjectIntegrationHandler, AllowServices(true)), r.PUT(api.putProjectIntegrationHandler), r.DELETE(api.deleteProjectIntegrationHandler))
+	r.Handle("/project/{permProjectKey}/integrations/{integrationName}/credentials", r.PUT(api.putProjectIntegrationCredentialsHandler, AllowProvider(true)))
 	r.Handle("/project/{permProjectKey}/notifications", r.GET(api.getProjectNotificationsHandler, DEPRECATED))
 	r.Handle("/project/{permProjectKey}/all/keys", r.GET(api.getAllKeysProjectHandler))
 	r.Handle("/project/{permProjectKey}/keys", r.GET(api.getKeysInProjectHandler), r.POST(api.addKeyInProjectHandler))
-	r.Handle("/project/{permProjectKey}/keys/{name}", r.DELETE(api.deleteKeyInProjectHandler))
+	r.Handle("/project/{permProjectKey}/keys/{name}", r.DELETE(api.deleteKeyInProjectHandler, NeedMFA()))
 	// Import Application
 	r.Handle("/project/{permProjectKey}/import/application", r.POST(api.postApplicationImportHandler))
 	// Export Application
@@ -151,7 +211,7 @@ func (api *API) InitRouter() {
 	r.Handle("/project/{permProjectKey}/application/{applicationName}", r.GET(api.getApplicationHandler), r.PUT(api.updateApplicationHandler), r.DELETE(api.deleteApplicationHandler))
 	r.Handle("/project/{permProjectKey}/application/{applicationName}/metrics/{metricName}", r.GET(api.getApplicationMetricHandler))
 	r.Handle("/project/{permProjectKey}/application/{applicationName}/keys", r.GET(api.getKeysInApplicationHandler), r.POST(api.addKeyInApplicationHandler))
-	r.Handle("/project/{permProjectKey}/application/{applicationName}/keys/{name}", r.DELETE(api.deleteKeyInApplicationHandler))
+	r.Handle("/project/{permProjectKey}/application/{applicationName}/keys/{name}", r.DELETE(api.deleteKeyInApplicationHandler, NeedMFA()))
 	r.Handle("/project/{permProjectKey}/application/{applicationName}/vcsinfos", r.GET(api.getApplicationVCSInfosHandler))
 	r.Handle("/project/{permProjectKey}/application/{applicationName}/clone", r.POST(api.cloneApplicationHandler))
 	r.Handle("/project/{permProjectKey}/application/{applicationName}/variable", r.GET(api.getVariablesInApplicationHandler))
@@ -190,11 +250,17 @@ func (api *API) InitRouter() {
 	r.Handle("/workflow/artifact/{hash}", r.GET(api.downloadworkflowArtifactDirectHandler, Auth(false)))
 
 	r.Handle("/project/{permProjectKey}/workflows", r.POST(api.postWorkflowHandler, EnableTracing()), r.GET(api.getWorkflowsHandler, AllowProvider(true), EnableTracing()))
+	r.Handle("/project/{key}/workflow/filters", r.GET(api.getWorkflowSavedFiltersHandler), r.POST(api.postWorkflowSavedFilterHandler))
+	r.Handle("/project/{key}/workflow/filters/{name}", r.PUT(api.putWorkflowSavedFilterHandler), r.DELETE(api.deleteWorkflowSavedFilterHandler))
+	r.Handle("/project/{permProjectKey}/workflow/labels", r.POST(api.postWorkflowLabelsHandler))
 	r.Handle("/project/{key}/workflows/{permWorkflowName}", r.GET(api.getWorkflowHandler, AllowProvider(true), EnableTracing()), r.PUT(api.putWorkflowHandler, EnableTracing()), r.DELETE(api.deleteWorkflowHandler))
 	r.Handle("/project/{key}/workflows/{permWorkflowName}/ascode/{uuid}", r.GET(api.getWorkflowAsCodeHandler))
 	r.Handle("/project/{key}/workflows/{permWorkflowName}/ascode", r.POST(api.postWorkflowAsCodeHandler, EnableTracing()))
 	r.Handle("/project/{key}/workflows/{permWorkflowName}/label", r.POST(api.postWorkflowLabelHandler))
 	r.Handle("/project/{key}/workflows/{permWorkflowName}/label/{labelID}", r.DELETE(api.deleteWorkflowLabelHandler))
+	r.Handle("/project/{key}/workflows/{permWorkflowName}/promote", r.POST(api.postWorkflowRemotePromoteHandler, NeedAdmin(true)))
+	r.Handle("/project/{key}/workflows/{permWorkflowName}/promotions", r.GET(api.getWorkflowPromotionsHandler))
+	r.Handle("/project/{key}/workflows/{permWorkflowName}/promotions/{id}", r.GET(api.getWorkflowPromotionHandler))
 	r.Handle("/project/{key}/workflows/{permWorkflowName}/rollback/{auditID}", r.POST(api.postWorkflowRollbackHandler))
 	r.Handle("/project/{key}/workflows/{permWorkflowName}/groups", r.POST(api.postWorkflowGroupHandler))
 	r.Handle("/project/{key}/workflows/{permWorkflowName}/groups/{groupName}", r.PUT(api.putWorkflowGroupHandler), r.DELETE(api.deleteWorkflowGroupHandler))
@@ -217,28 +283,51 @@ func (api *API) InitRouter() {
 	r.Handle("/project/{key}/pull/workflows/{permWorkflowName}", r.GET(api.getWorkflowPullHandler))
 	// Push workflows
 	r.Handle("/project/{permProjectKey}/push/workflows", r.POST(api.postWorkflowPushHandler))
+	r.Handle("/project/{permProjectKey}/promotions", r.POST(api.postWorkflowPromotionHandler, NeedAdmin(true)))
 
 	// Workflows run
-	r.Handle("/project/{permProjectKey}/runs", r.GET(api.getWorkflowAllRunsHandler, EnableTracing()))
-	r.Handle("/project/{key}/workflows/{permWorkflowName}/runs", r.GET(api.getWorkflowRunsHandler, EnableTracing()), r.POSTEXECUTE(api.postWorkflowRunHandler, AllowServices(true), EnableTracing()))
+	r.Handle("/project/{permProjectKey}/runs", r.GET(api.getWorkflowAllRunsHandler, EnableTracing(), ReadReplica()))
+	r.Handle("/project/{key}/workflows/{permWorkflowName}/runs", r.GET(api.getWorkflowRunsHandler, EnableTracing(), ReadReplica()), r.POSTEXECUTE(api.postWorkflowRunHandler, AllowServices(true), EnableTracing(), Scope(sdk.AccessTokenScopeRunWorkflow), MaintenanceLocked()))
 	r.Handle("/project/{key}/workflows/{permWorkflowName}/runs/latest", r.GET(api.getLatestWorkflowRunHandler))
 	r.Handle("/project/{key}/workflows/{permWorkflowName}/runs/tags", r.GET(api.getWorkflowRunTagsHandler))
 	r.Handle("/project/{key}/workflows/{permWorkflowName}/runs/num", r.GET(api.getWorkflowRunNumHandler), r.POST(api.postWorkflowRunNumHandler))
+	r.Handle("/project/{key}/workflows/{permWorkflowName}/runs/compare", r.GET(api.getWorkflowRunsCompareHandler))
 	r.Handle("/project/{key}/workflows/{permWorkflowName}/runs/{number}", r.GET(api.getWorkflowRunHandler, AllowServices(true)))
-	r.Handle("/project/{key}/workflows/{permWorkflowName}/runs/{number}/stop", r.POSTEXECUTE(api.stopWorkflowRunHandler, EnableTracing()))
+	r.Handle("/project/{key}/workflows/{permWorkflowName}/runs/{number}/stop", r.POSTEXECUTE(api.stopWorkflowRunHandler, EnableTracing(), Scope(sdk.AccessTokenScopeRunWorkflow)))
+	r.Handle("/project/{key}/workflows/{permWorkflowName}/runs/{number}/priority", r.PUT(api.putWorkflowRunPriorityHandler, NeedAdmin(true)))
 	r.Handle("/project/{key}/workflows/{permWorkflowName}/runs/{number}/vcs/resync", r.POSTEXECUTE(api.postResyncVCSWorkflowRunHandler))
 	r.Handle("/project/{key}/workflows/{permWorkflowName}/runs/{number}/resync", r.POST(api.resyncWorkflowRunHandler))
 	r.Handle("/project/{key}/workflows/{permWorkflowName}/runs/{number}/artifacts", r.GET(api.getWorkflowRunArtifactsHandler))
+	r.Handle("/project/{key}/workflows/{permWorkflowName}/runs/{number}/results", r.GET(api.getWorkflowRunResultsHandler))
+	r.Handle("/project/{key}/workflows/{permWorkflowName}/runs/{number}/outputs", r.GET(api.getWorkflowRunJobOutputsHandler))
+	r.Handle("/project/{key}/workflows/{permWorkflowName}/runs/{number}/coverage", r.GET(api.getWorkflowRunCoverageHandler))
+	r.Handle("/project/{key}/workflows/{permWorkflowName}/runs/{number}/logs/download", r.GET(api.getWorkflowRunLogsDownloadHandler))
+	r.Handle("/project/{key}/workflows/{permWorkflowName}/runs/{number}/sbom", r.GET(api.getWorkflowRunSBOMsHandler))
+	r.Handle("/project/{key}/workflows/{permWorkflowName}/runs/{number}/sbom/{sbomID}", r.GET(api.getWorkflowRunSBOMHandler))
+	r.Handle("/project/{key}/workflows/{permWorkflowName}/tests/history", r.GET(api.getWorkflowTestCaseHistoryHandler))
+	r.Handle("/project/{key}/workflows/{permWorkflowName}/tests/flaky", r.GET(api.getWorkflowFlakyTestsHandler))
+	r.Handle("/project/{key}/workflows/{permWorkflowName}/coverage", r.GET(api.getWorkflowCoverageTrendHandler))
+	r.Handle("/project/{key}/workflows/{permWorkflowName}/analytics/durations", r.GET(api.getWorkflowDurationsAnalyticsHandler))
+	r.Handle("/project/{key}/workflows/{permWorkflowName}/analytics/steps", r.GET(api.getWorkflowSlowestStepsAnalyticsHandler))
+	r.Handle("/project/{key}/workflows/{permWorkflowName}/badge", r.GET(api.getWorkflowBadgeHandler), r.POST(api.postWorkflowBadgeHandler), r.DELETE(api.deleteWorkflowBadgeHandler))
+	r.Handle("/project/{key}/workflows/{permWorkflowName}/forks/approvals", r.GET(api.getWorkflowForkApprovalsHandler))
+	r.Handle("/project/{key}/workflows/{permWorkflowName}/forks/approvals/{id}", r.POST(api.postWorkflowForkApprovalHandler, MaintenanceLocked()), r.DELETE(api.deleteWorkflowForkApprovalHandler))
 	r.Handle("/project/{key}/workflows/{permWorkflowName}/runs/{number}/nodes/{nodeRunID}", r.GET(api.getWorkflowNodeRunHandler))
-	r.Handle("/project/{key}/workflows/{permWorkflowName}/runs/{number}/nodes/{nodeRunID}/stop", r.POSTEXECUTE(api.stopWorkflowNodeRunHandler))
+	r.Handle("/project/{key}/workflows/{permWorkflowName}/runs/{number}/nodes/{nodeRunID}/stop", r.POSTEXECUTE(api.stopWorkflowNodeRunHandler, Scope(sdk.AccessTokenScopeRunWorkflow)))
+	r.Handle("/project/{key}/workflows/{permWorkflowName}/runs/{number}/nodes/{nodeRunID}/changes", r.GET(api.getWorkflowNodeRunChangedFilesHandler))
+	r.Handle("/project/{key}/workflows/{permWorkflowName}/runs/{number}/nodes/{nodeRunID}/freeze/override", r.POST(api.postWorkflowNodeRunFreezeOverrideHandler, NeedAdmin(true)))
 	r.Handle("/project/{key}/workflows/{permWorkflowName}/runs/{number}/nodes/{nodeID}/history", r.GET(api.getWorkflowNodeRunHistoryHandler))
 	r.Handle("/project/{key}/workflows/{permWorkflowName}/runs/{number}/{nodeName}/commits", r.GET(api.getWorkflowCommitsHandler))
 	r.Handle("/project/{key}/workflows/{permWorkflowName}/runs/{number}/nodes/{nodeRunID}/job/{runJobId}/info", r.GET(api.getWorkflowNodeRunJobSpawnInfosHandler))
 	r.Handle("/project/{key}/workflows/{permWorkflowName}/runs/{number}/nodes/{nodeRunID}/job/{runJobId}/log/service", r.GET(api.getWorkflowNodeRunJobServiceLogsHandler))
 	r.Handle("/project/{key}/workflows/{permWorkflowName}/runs/{number}/nodes/{nodeRunID}/job/{runJobId}/step/{stepOrder}", r.GET(api.getWorkflowNodeRunJobStepHandler))
+	r.Handle("/project/{key}/workflows/{permWorkflowName}/runs/{number}/nodes/{nodeRunID}/job/{runJobId}/step/{stepOrder}/log/download", r.GET(api.getWorkflowNodeRunJobStepLogDownloadHandler))
 	r.Handle("/project/{key}/workflows/{permWorkflowName}/artifact/{artifactId}", r.GET(api.getDownloadArtifactHandler))
+	r.Handle("/project/{key}/workflows/{permWorkflowName}/artifact/{artifactId}/verify", r.GET(api.getVerifyArtifactHandler))
+	r.Handle("/project/{key}/workflows/{permWorkflowName}/artifact/{artifactId}/provenance", r.GET(api.getArtifactProvenanceHandler))
 	r.Handle("/project/{key}/workflows/{permWorkflowName}/node/{nodeID}/triggers/condition", r.GET(api.getWorkflowTriggerConditionHandler))
 	r.Handle("/project/{key}/workflows/{permWorkflowName}/runs/{number}/nodes/{nodeRunID}/release", r.POST(api.releaseApplicationWorkflowHandler))
+	r.Handle("/project/{key}/workflows/{permWorkflowName}/runs/{number}/nodes/{nodeRunID}/promote", r.POST(api.promoteWorkflowNodeRunHandler))
 	r.Handle("/project/{key}/workflows/{permWorkflowName}/runs/{number}/hooks/{hookRunID}/callback", r.POST(api.postWorkflowJobHookCallbackHandler, AllowServices(true)))
 	r.Handle("/project/{key}/workflows/{permWorkflowName}/runs/{number}/hooks/{hookRunID}/details", r.GET(api.getWorkflowJobHookDetailsHandler, NeedService()))
 
@@ -248,8 +337,10 @@ func (api *API) InitRouter() {
 	r.Handle("/project/{permProjectKey}/environment/import/{environmentName}", r.POST(api.importIntoEnvironmentHandler, DEPRECATED))
 	r.Handle("/project/{permProjectKey}/environment/{environmentName}", r.GET(api.getEnvironmentHandler), r.PUT(api.updateEnvironmentHandler), r.DELETE(api.deleteEnvironmentHandler))
 	r.Handle("/project/{permProjectKey}/environment/{environmentName}/usage", r.GET(api.getEnvironmentUsageHandler))
+	r.Handle("/project/{permProjectKey}/environment/{environmentName}/freeze", r.GET(api.getEnvironmentFreezesHandler), r.POST(api.postEnvironmentFreezeHandler))
+	r.Handle("/project/{permProjectKey}/environment/{environmentName}/freeze/{id}", r.DELETE(api.deleteEnvironmentFreezeHandler))
 	r.Handle("/project/{permProjectKey}/environment/{environmentName}/keys", r.GET(api.getKeysInEnvironmentHandler), r.POST(api.addKeyInEnvironmentHandler))
-	r.Handle("/project/{permProjectKey}/environment/{environmentName}/keys/{name}", r.DELETE(api.deleteKeyInEnvironmentHandler))
+	r.Handle("/project/{permProjectKey}/environment/{environmentName}/keys/{name}", r.DELETE(api.deleteKeyInEnvironmentHandler, NeedMFA()))
 	r.Handle("/project/{permProjectKey}/environment/{environmentName}/clone/{cloneName}", r.POST(api.cloneEnvironmentHandler))
 	r.Handle("/project/{permProjectKey}/environment/{environmentName}/variable", r.GET(api.getVariablesInEnvironmentHandler))
 	r.Handle("/project/{permProjectKey}/environment/{environmentName}/variable/{name}", r.GET(api.getVariableInEnvironmentHandler), r.POST(api.addVariableInEnvironmentHandler), r.PUT(api.updateVariableInEnvironmentHandler), r.DELETE(api.deleteVariableFromEnvironmentHandler))
@@ -263,34 +354,45 @@ func (api *API) InitRouter() {
 	// Artifacts
 	r.Handle("/staticfiles/store", r.GET(api.getStaticFilesStoreHandler, Auth(false)))
 	r.Handle("/artifact/store", r.GET(api.getArtifactsStoreHandler, Auth(false)))
+	r.Handle("/artifact/provenance/key", r.GET(api.getArtifactsProvenanceKeyHandler, Auth(false)))
+
+	// Badges
+	r.Handle("/badge/{key}/{workflowName}/{type}.svg", r.GET(api.getBadgeHandler, Auth(false)))
 
 	// Cache
 	r.Handle("/project/{permProjectKey}/cache/{tag}", r.POSTEXECUTE(api.postPushCacheHandler, NeedWorker()), r.GET(api.getPullCacheHandler, NeedWorker()))
 	r.Handle("/project/{permProjectKey}/cache/{tag}/url", r.POSTEXECUTE(api.postPushCacheWithTempURLHandler, NeedWorker()), r.GET(api.getPullCacheWithTempURLHandler, NeedWorker()))
 
 	//Workflow queue
-	r.Handle("/queue/workflows", r.GET(api.getWorkflowJobQueueHandler, EnableTracing(), MaintenanceAware()))
+	r.Handle("/queue/workflows", r.GET(api.getWorkflowJobQueueHandler, EnableTracing(), MaintenanceAware(), ReadReplica()))
 	r.Handle("/queue/workflows/count", r.GET(api.countWorkflowJobQueueHandler, EnableTracing(), MaintenanceAware()))
 	r.Handle("/queue/workflows/{id}/take", r.POST(api.postTakeWorkflowJobHandler, NeedWorker(), EnableTracing(), MaintenanceAware()))
-	r.Handle("/queue/workflows/{id}/book", r.POST(api.postBookWorkflowJobHandler, NeedHatchery(), EnableTracing(), MaintenanceAware()), r.DELETE(api.deleteBookWorkflowJobHandler, NeedHatchery(), EnableTracing(), MaintenanceAware()))
+	r.Handle("/queue/workflows/{id}/book", r.POST(api.postBookWorkflowJobHandler, NeedHatchery(), EnableTracing(), MaintenanceAware()), r.DELETE(api.deleteBookWorkflowJobHandler, NeedHatchery(), EnableTracing()))
+	r.Handle("/queue/workflows/{id}/book/confirm", r.POST(api.postConfirmBookWorkflowJobHandler, NeedHatchery(), EnableTracing(), MaintenanceAware()))
 	r.Handle("/queue/workflows/{id}/attempt", r.POST(api.postIncWorkflowJobAttemptHandler, NeedHatchery(), EnableTracing(), MaintenanceAware()))
 	r.Handle("/queue/workflows/{id}/infos", r.GET(api.getWorkflowJobHandler, NeedWorker(), NeedHatchery(), EnableTracing(), MaintenanceAware()))
-	r.Handle("/queue/workflows/{permID}/vulnerability", r.POSTEXECUTE(api.postVulnerabilityReportHandler, NeedWorker(), EnableTracing(), MaintenanceAware()))
-	r.Handle("/queue/workflows/{id}/spawn/infos", r.POST(r.Asynchronous(api.postSpawnInfosWorkflowJobHandler, 1), NeedHatchery(), EnableTracing(), MaintenanceAware()))
-	r.Handle("/queue/workflows/{permID}/result", r.POSTEXECUTE(api.postWorkflowJobResultHandler, NeedWorker(), EnableTracing(), MaintenanceAware()))
-	r.Handle("/queue/workflows/{permID}/log", r.POSTEXECUTE(r.Asynchronous(api.postWorkflowJobLogsHandler, 1), NeedWorker(), MaintenanceAware()))
-	r.Handle("/queue/workflows/log/service", r.POSTEXECUTE(r.Asynchronous(api.postWorkflowJobServiceLogsHandler, 1), NeedHatchery(), MaintenanceAware()))
-	r.Handle("/queue/workflows/{permID}/coverage", r.POSTEXECUTE(api.postWorkflowJobCoverageResultsHandler, NeedWorker(), EnableTracing(), MaintenanceAware()))
-	r.Handle("/queue/workflows/{permID}/test", r.POSTEXECUTE(api.postWorkflowJobTestsResultsHandler, NeedWorker(), EnableTracing(), MaintenanceAware()))
-	r.Handle("/queue/workflows/{permID}/tag", r.POSTEXECUTE(api.postWorkflowJobTagsHandler, NeedWorker(), EnableTracing(), MaintenanceAware()))
-	r.Handle("/queue/workflows/{permID}/variable", r.POSTEXECUTE(api.postWorkflowJobVariableHandler, NeedWorker(), EnableTracing(), MaintenanceAware()))
-	r.Handle("/queue/workflows/{permID}/step", r.POSTEXECUTE(api.postWorkflowJobStepStatusHandler, NeedWorker(), EnableTracing(), MaintenanceAware()))
-	r.Handle("/queue/workflows/{permID}/artifact/{ref}", r.POSTEXECUTE(api.postWorkflowJobArtifactHandler, NeedWorker(), EnableTracing(), MaintenanceAware()))
-	r.Handle("/queue/workflows/{permID}/artifact/{ref}/url", r.POSTEXECUTE(api.postWorkflowJobArtifacWithTempURLHandler, NeedWorker(), EnableTracing(), MaintenanceAware()))
-	r.Handle("/queue/workflows/{permID}/artifact/{ref}/url/callback", r.POSTEXECUTE(api.postWorkflowJobArtifactWithTempURLCallbackHandler, NeedWorker(), EnableTracing(), MaintenanceAware()))
-	r.Handle("/queue/workflows/{permID}/staticfiles/{name}", r.POSTEXECUTE(api.postWorkflowJobStaticFilesHandler, NeedWorker(), EnableTracing(), MaintenanceAware()))
-	r.Handle("/queue/workflows/{permID}/staticfiles/{name}/url", r.POSTEXECUTE(api.postWorkflowJobStaticFilesWithTempURLHandler, NeedWorker(), EnableTracing(), MaintenanceAware()))
-	r.Handle("/queue/workflows/{permID}/staticfiles/{name}/url/callback", r.POSTEXECUTE(api.postWorkflowJobStaticFilesWithTempURLCallbackHandler, NeedWorker(), EnableTracing(), MaintenanceAware()))
+	r.Handle("/queue/workflows/{permID}/vulnerability", r.POSTEXECUTE(api.postVulnerabilityReportHandler, NeedWorker(), EnableTracing()))
+	r.Handle("/queue/workflows/{id}/spawn/infos", r.POST(r.Asynchronous(api.postSpawnInfosWorkflowJobHandler, 1), NeedHatchery(), EnableTracing()))
+	r.Handle("/queue/workflows/{id}/lost", r.POST(api.postWorkflowJobWorkerLostHandler, NeedHatchery(), EnableTracing()))
+	r.Handle("/queue/workflows/{permID}/result", r.POSTEXECUTE(api.postWorkflowJobResultHandler, NeedWorker(), EnableTracing()))
+	r.Handle("/queue/workflows/{permID}/log", r.POSTEXECUTE(r.Asynchronous(api.postWorkflowJobLogsHandler, 1), NeedWorker()))
+	r.Handle("/queue/workflows/log/service", r.POSTEXECUTE(r.Asynchronous(api.postWorkflowJobServiceLogsHandler, 1), NeedHatchery()))
+	r.Handle("/queue/workflows/{permID}/coverage", r.POSTEXECUTE(api.postWorkflowJobCoverageResultsHandler, NeedWorker(), EnableTracing()))
+	r.Handle("/queue/workflows/{permID}/test", r.POSTEXECUTE(api.postWorkflowJobTestsResultsHandler, NeedWorker(), EnableTracing()))
+	r.Handle("/queue/workflows/{permID}/tag", r.POSTEXECUTE(api.postWorkflowJobTagsHandler, NeedWorker(), EnableTracing()))
+	r.Handle("/queue/workflows/{permID}/sbom", r.POSTEXECUTE(api.postWorkflowJobSBOMHandler, NeedWorker(), EnableTracing()))
+	r.Handle("/queue/workflows/{permID}/run/results", r.POSTEXECUTE(api.postWorkflowJobRunResultHandler, NeedWorker(), EnableTracing()))
+	r.Handle("/queue/workflows/{permID}/variable", r.POSTEXECUTE(api.postWorkflowJobVariableHandler, NeedWorker(), EnableTracing()))
+	r.Handle("/queue/workflows/{permID}/output", r.POSTEXECUTE(api.postWorkflowJobOutputHandler, NeedWorker(), EnableTracing()))
+	r.Handle("/queue/workflows/{permID}/tmpfile/{name}", r.POSTEXECUTE(api.postWorkflowJobTmpFileHandler, NeedWorker(), EnableTracing()), r.GET(api.getWorkflowJobTmpFileHandler, NeedWorker(), EnableTracing()))
+	r.Handle("/queue/workflows/{permID}/mirror", r.GET(api.getWorkflowJobMirrorHandler, NeedWorker(), EnableTracing()))
+	r.Handle("/queue/workflows/{permID}/step", r.POSTEXECUTE(api.postWorkflowJobStepStatusHandler, NeedWorker(), EnableTracing()))
+	r.Handle("/queue/workflows/{permID}/artifact/{ref}", r.POSTEXECUTE(api.postWorkflowJobArtifactHandler, NeedWorker(), EnableTracing()))
+	r.Handle("/queue/workflows/{permID}/artifact/{ref}/url", r.POSTEXECUTE(api.postWorkflowJobArtifacWithTempURLHandler, NeedWorker(), EnableTracing()))
+	r.Handle("/queue/workflows/{permID}/artifact/{ref}/url/callback", r.POSTEXECUTE(api.postWorkflowJobArtifactWithTempURLCallbackHandler, NeedWorker(), EnableTracing()))
+	r.Handle("/queue/workflows/{permID}/staticfiles/{name}", r.POSTEXECUTE(api.postWorkflowJobStaticFilesHandler, NeedWorker(), EnableTracing()))
+	r.Handle("/queue/workflows/{permID}/staticfiles/{name}/url", r.POSTEXECUTE(api.postWorkflowJobStaticFilesWithTempURLHandler, NeedWorker(), EnableTracing()))
+	r.Handle("/queue/workflows/{permID}/staticfiles/{name}/url/callback", r.POSTEXECUTE(api.postWorkflowJobStaticFilesWithTempURLCallbackHandler, NeedWorker(), EnableTracing()))
 
 	r.Handle("/variable/type", r.GET(api.getVariableTypeHandler))
 	r.Handle("/parameter/type", r.GET(api.getParameterTypeHandler))
@@ -324,7 +426,7 @@ func (api *API) InitRouter() {
 	r.Handle("/requirement/types/{type}", r.GET(api.getRequirementTypeValuesHandler))
 
 	// config
-	r.Handle("/config/user", r.GET(api.ConfigUserHandler, Auth(true)))
+	r.Handle("/config/user", r.GET(api.ConfigUserHandler, Auth(true), AllowMFAEnrollment()))
 
 	// Users
 	r.Handle("/user", r.GET(api.getUsersHandler))
@@ -334,11 +436,23 @@ func (api *API) InitRouter() {
 	r.Handle("/user/timeline", r.GET(api.getTimelineHandler))
 	r.Handle("/user/timeline/filter", r.GET(api.getTimelineFilterHandler), r.POST(api.postTimelineFilterHandler))
 	r.Handle("/user/token", r.GET(api.getUserTokenListHandler))
+	r.Handle("/user/accesstoken", r.GET(api.getPersonalAccessTokensHandler), r.POST(api.postPersonalAccessTokenHandler))
+	r.Handle("/user/accesstoken/{id}", r.PUT(api.putAccessTokenRestrictionsHandler), r.DELETE(api.deletePersonalAccessTokenHandler))
 	r.Handle("/user/token/{token}", r.GET(api.getUserTokenHandler))
+	r.Handle("/user/mfa", r.GET(api.getUserMFAHandler, AllowMFAEnrollment()))
+	r.Handle("/user/mfa/totp", r.POST(api.postUserMFATOTPHandler, AllowMFAEnrollment(), NeedMFA()), r.PUT(api.putUserMFATOTPHandler, AllowMFAEnrollment()), r.DELETE(api.deleteUserMFATOTPHandler, NeedMFA()))
+	r.Handle("/user/mfa/recovery", r.POST(api.postUserMFARecoveryCodesHandler, NeedMFA()))
+	r.Handle("/user/mfa/verify", r.POST(api.postUserMFAVerifyHandler))
+	r.Handle("/user/mfa/webauthn", r.GET(api.getUserMFAWebAuthnChallengeHandler, AllowMFAEnrollment()), r.POST(api.postUserMFAWebAuthnHandler, AllowMFAEnrollment(), NeedMFA()))
+	r.Handle("/user/mfa/webauthn/{id}", r.DELETE(api.deleteUserMFAWebAuthnHandler, NeedMFA()))
 	r.Handle("/user/signup", r.POST(api.addUserHandler, Auth(false)))
 	r.Handle("/user/import", r.POST(api.importUsersHandler, NeedAdmin(true)))
 	r.Handle("/user/{username}", r.GET(api.getUserHandler, NeedUsernameOrAdmin(true)), r.PUT(api.updateUserHandler, NeedUsernameOrAdmin(true)), r.DELETE(api.deleteUserHandler, NeedUsernameOrAdmin(true)))
 	r.Handle("/user/{username}/groups", r.GET(api.getUserGroupsHandler, NeedUsernameOrAdmin(true)))
+	r.Handle("/user/{username}/vcs", r.GET(api.getUserVCSAccountsHandler, NeedUsernameOrAdmin(true)))
+	r.Handle("/user/{username}/vcs/{id}", r.DELETE(api.deleteUserVCSAccountHandler, NeedUsernameOrAdmin(true)))
+	r.Handle("/user/{username}/vcs/{vcsServer}/authorize", r.POST(api.postUserVCSAccountAuthorizeHandler, NeedUsernameOrAdmin(true)))
+	r.Handle("/user/{username}/vcs/{vcsServer}/authorize/callback", r.POST(api.postUserVCSAccountAuthorizeCallbackHandler, NeedUsernameOrAdmin(true)))
 	r.Handle("/user/{username}/confirm/{token}", r.GET(api.confirmUserHandler, Auth(false)))
 	r.Handle("/user/{username}/reset", r.POST(api.resetUserHandler, Auth(false)))
 	r.Handle("/auth/mode", r.GET(api.authModeHandler, Auth(false)))
@@ -352,8 +466,8 @@ func (api *API) InitRouter() {
 	r.Handle("/worker/{id}/disable", r.POST(api.disableWorkerHandler))
 
 	// Worker models
-	r.Handle("/worker/model", r.POST(api.addWorkerModelHandler), r.GET(api.getWorkerModelsHandler))
-	r.Handle("/worker/model/import", r.POST(api.postWorkerModelImportHandler))
+	r.Handle("/worker/model", r.POST(api.addWorkerModelHandler, Scope(sdk.AccessTokenScopeAdminWorkerModel)), r.GET(api.getWorkerModelsHandler))
+	r.Handle("/worker/model/import", r.POST(api.postWorkerModelImportHandler, Scope(sdk.AccessTokenScopeAdminWorkerModel)))
 	r.Handle("/worker/model/pattern", r.POST(api.postAddWorkerModelPatternHandler, NeedAdmin(true)), r.GET(api.getWorkerModelPatternsHandler))
 	r.Handle("/worker/model/pattern/{type}/{name}", r.GET(api.getWorkerModelPatternHandler), r.PUT(api.putWorkerModelPatternHandler, NeedAdmin(true)), r.DELETE(api.deleteWorkerModelPatternHandler, NeedAdmin(true)))
 	r.Handle("/worker/model/book/{permModelID}", r.PUT(api.bookWorkerModelHandler, NeedHatchery()))
@@ -361,7 +475,7 @@ func (api *API) InitRouter() {
 	r.Handle("/worker/model/enabled", r.GET(api.getWorkerModelsEnabledHandler, NeedHatchery()))
 	r.Handle("/worker/model/type", r.GET(api.getWorkerModelTypesHandler))
 	r.Handle("/worker/model/communication", r.GET(api.getWorkerModelCommunicationsHandler))
-	r.Handle("/worker/model/{permModelID}", r.PUT(api.updateWorkerModelHandler), r.DELETE(api.deleteWorkerModelHandler))
+	r.Handle("/worker/model/{permModelID}", r.PUT(api.updateWorkerModelHandler, Scope(sdk.AccessTokenScopeAdminWorkerModel)), r.DELETE(api.deleteWorkerModelHandler, Scope(sdk.AccessTokenScopeAdminWorkerModel)))
 	r.Handle("/worker/model/{permModelID}/export", r.GET(api.getWorkerModelExportHandler))
 	r.Handle("/worker/model/{modelID}/usage", r.GET(api.getWorkerModelUsageHandler))
 	r.Handle("/worker/model/capability/type", r.GET(api.getRequirementTypesHandler))
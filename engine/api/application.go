@@ -412,7 +412,7 @@ func (api *API) updateApplicationHandler() service.Handler {
 		}
 
 		if appPost.RepositoryStrategy.Password != sdk.PasswordPlaceholder && appPost.RepositoryStrategy.Password != "" {
-			if errP := application.EncryptVCSStrategyPassword(&appPost); errP != nil {
+			if errP := application.EncryptVCSStrategyPassword(api.mustDB(), app.ProjectID, &appPost); errP != nil {
 				return sdk.WrapError(errP, "updateApplicationHandler> Cannot encrypt password")
 			}
 		}
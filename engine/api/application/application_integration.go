@@ -13,15 +13,16 @@ import (
 
 // LoadDeploymentStrategies loads the deployment strategies for an application
 func LoadDeploymentStrategies(db gorp.SqlExecutor, appID int64, withClearPassword bool) (map[string]sdk.IntegrationConfig, error) {
-	query := `SELECT project_integration.name, application_deployment_strategy.config
+	query := `SELECT project_integration.name, project_integration.project_id, application_deployment_strategy.config
 	FROM application_deployment_strategy
 	JOIN project_integration ON project_integration.id = application_deployment_strategy.project_integration_id
 	JOIN integration_model ON integration_model.id = project_integration.integration_model_id
 	WHERE application_deployment_strategy.application_id = $1`
 
 	res := []struct {
-		Name   string         `db:"name"`
-		Config sql.NullString `db:"config"`
+		Name      string         `db:"name"`
+		ProjectID int64          `db:"project_id"`
+		Config    sql.NullString `db:"config"`
 	}{}
 
 	if _, err := db.Select(&res, query, appID); err != nil {
@@ -49,7 +50,7 @@ func LoadDeploymentStrategies(db gorp.SqlExecutor, appID int64, withClearPasswor
 						return nil, sdk.WrapError(err, "unable to decode encrypted value")
 					}
 
-					decryptedValue, err := secret.Decrypt(s)
+					decryptedValue, err := secret.DecryptForProject(r.ProjectID, s)
 					if err != nil {
 						return nil, sdk.WrapError(err, "unable to decrypt secret value")
 					}
@@ -96,7 +97,7 @@ func SetDeploymentStrategy(db gorp.SqlExecutor, projID, appID, pfID int64, ppfNa
 	newcfg := sdk.IntegrationConfig{}
 	for k, v := range cfg {
 		if v.Type == sdk.IntegrationConfigTypePassword {
-			e, err := secret.Encrypt([]byte(v.Value))
+			e, err := secret.EncryptForProject(db, projID, []byte(v.Value))
 			if err != nil {
 				return sdk.WrapError(err, "unable to encrypt data")
 			}
@@ -161,3 +162,65 @@ func SetDeploymentStrategy(db gorp.SqlExecutor, projID, appID, pfID int64, ppfNa
 	}
 	return nil
 }
+
+// rotateDeploymentStrategiesPasswords re-encrypts the passwords of the deployment strategies of the applications of
+// the project with its active data key. It returns the number of re-encrypted passwords.
+func rotateDeploymentStrategiesPasswords(db gorp.SqlExecutor, projectID int64) (int, error) {
+	query := `SELECT application_deployment_strategy.application_id, application_deployment_strategy.project_integration_id,
+	application_deployment_strategy.config
+	FROM application_deployment_strategy
+	JOIN project_integration ON project_integration.id = application_deployment_strategy.project_integration_id
+	WHERE project_integration.project_id = $1`
+
+	res := []struct {
+		ApplicationID        int64          `db:"application_id"`
+		ProjectIntegrationID int64          `db:"project_integration_id"`
+		Config               sql.NullString `db:"config"`
+	}{}
+	if _, err := db.Select(&res, query, projectID); err != nil {
+		return 0, sdk.WrapError(err, "unable to load deployment strategies of project %d", projectID)
+	}
+
+	var count int
+	for _, r := range res {
+		cfg := sdk.IntegrationConfig{}
+		if err := gorpmapping.JSONNullString(r.Config, &cfg); err != nil {
+			return 0, sdk.WrapError(err, "unable to parse config")
+		}
+		var n int
+		for k, v := range cfg {
+			if v.Type != sdk.IntegrationConfigTypePassword || v.Value == "" {
+				continue
+			}
+			b, err := base64.StdEncoding.DecodeString(v.Value)
+			if err != nil {
+				return 0, sdk.WrapError(err, "unable to decode encrypted value")
+			}
+			clear, err := secret.DecryptForProject(projectID, b)
+			if err != nil {
+				return 0, sdk.WrapError(err, "unable to decrypt %s of deployment strategy of application %d", k, r.ApplicationID)
+			}
+			e, err := secret.EncryptForProject(db, projectID, clear)
+			if err != nil {
+				return 0, sdk.WrapError(err, "unable to encrypt %s of deployment strategy of application %d", k, r.ApplicationID)
+			}
+			v.Value = base64.StdEncoding.EncodeToString(e)
+			cfg[k] = v
+			n++
+		}
+		if n == 0 {
+			continue
+		}
+
+		scfg, err := gorpmapping.JSONToNullString(cfg)
+		if err != nil {
+			return 0, sdk.WrapError(err, "unable to marshal deployment strategy")
+		}
+		if _, err := db.Exec(`UPDATE application_deployment_strategy SET config = $1
+		WHERE application_id = $2 AND project_integration_id = $3`, scfg, r.ApplicationID, r.ProjectIntegrationID); err != nil {
+			return 0, sdk.WrapError(err, "unable to update deployment strategy of application %d", r.ApplicationID)
+		}
+		count += n
+	}
+	return count, nil
+}
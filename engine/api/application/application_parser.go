@@ -123,7 +123,7 @@ func ParseAndImport(db gorp.SqlExecutor, cache cache.Store, proj *sdk.Project, e
 			return app, nil, sdk.WrapError(sdk.NewError(sdk.ErrWrongRequest, err), "unable to decrypt vcs password")
 		}
 		app.RepositoryStrategy.Password = clearPWD
-		if errE := EncryptVCSStrategyPassword(app); errE != nil {
+		if errE := EncryptVCSStrategyPassword(db, proj.ID, app); errE != nil {
 			return app, nil, sdk.WrapError(errE, "cannot encrypt vcs password")
 		}
 	}
@@ -87,7 +87,7 @@ func GetAllVariable(db gorp.SqlExecutor, key, appName string, args ...FuncArg) (
 
 	variables := []sdk.Variable{}
 	query := `SELECT application_variable.id, application_variable.var_name, application_variable.var_value,
-						application_variable.cipher_value, application_variable.var_type
+						application_variable.cipher_value, application_variable.var_type, application.project_id
 	          FROM application_variable
 	          JOIN application ON application.id = application_variable.application_id
 	          JOIN project ON project.id = application.project_id
@@ -103,7 +103,8 @@ func GetAllVariable(db gorp.SqlExecutor, key, appName string, args ...FuncArg) (
 		var typeVar string
 		var clearVal sql.NullString
 		var cipherVal []byte
-		err = rows.Scan(&v.ID, &v.Name, &clearVal, &cipherVal, &typeVar)
+		var projectID int64
+		err = rows.Scan(&v.ID, &v.Name, &clearVal, &cipherVal, &typeVar, &projectID)
 		if err != nil {
 			return nil, err
 		}
@@ -112,7 +113,7 @@ func GetAllVariable(db gorp.SqlExecutor, key, appName string, args ...FuncArg) (
 		if c.encryptsecret && sdk.NeedPlaceholder(v.Type) {
 			v.Value = string(cipherVal)
 		} else {
-			v.Value, err = secret.DecryptS(v.Type, clearVal, cipherVal, c.clearsecret)
+			v.Value, err = secret.DecryptSForProject(projectID, v.Type, clearVal, cipherVal, c.clearsecret)
 		}
 
 		if err != nil {
@@ -131,18 +132,21 @@ func LoadVariableByID(db gorp.SqlExecutor, appID int64, varID int64, fargs ...Fu
 		f(&c)
 	}
 
-	query := `SELECT id, var_name, var_value, var_type, cipher_value FROM application_variable
-			WHERE application_id = $1 AND id = $2`
+	query := `SELECT application_variable.id, var_name, var_value, var_type, cipher_value, application.project_id
+			FROM application_variable
+			JOIN application ON application.id = application_variable.application_id
+			WHERE application_id = $1 AND application_variable.id = $2`
 
 	var v sdk.Variable
 	var value sql.NullString
 	var cipher []byte
-	if err := db.QueryRow(query, appID, varID).Scan(&v.ID, &v.Name, &value, &v.Type, &cipher); err != nil {
+	var projectID int64
+	if err := db.QueryRow(query, appID, varID).Scan(&v.ID, &v.Name, &value, &v.Type, &cipher, &projectID); err != nil {
 		return nil, err
 	}
 
 	var errC error
-	v.Value, errC = secret.DecryptS(v.Type, value, cipher, c.clearsecret)
+	v.Value, errC = secret.DecryptSForProject(projectID, v.Type, value, cipher, c.clearsecret)
 	return &v, errC
 }
 
@@ -153,17 +157,20 @@ func LoadVariable(db gorp.SqlExecutor, appID int64, varName string, fargs ...Fun
 		f(&c)
 	}
 
-	query := `SELECT id, var_name, var_value, var_type, cipher_value FROM application_variable
+	query := `SELECT application_variable.id, var_name, var_value, var_type, cipher_value, application.project_id
+			FROM application_variable
+			JOIN application ON application.id = application_variable.application_id
 			WHERE application_id = $1 AND var_name = $2`
 
 	var v sdk.Variable
 	var value sql.NullString
 	var cipher []byte
-	if err := db.QueryRow(query, appID, varName).Scan(&v.ID, &v.Name, &value, &v.Type, &cipher); err != nil {
+	var projectID int64
+	if err := db.QueryRow(query, appID, varName).Scan(&v.ID, &v.Name, &value, &v.Type, &cipher, &projectID); err != nil {
 		return nil, err
 	}
 	var errC error
-	v.Value, errC = secret.DecryptS(v.Type, value, cipher, c.clearsecret)
+	v.Value, errC = secret.DecryptSForProject(projectID, v.Type, value, cipher, c.clearsecret)
 	return &v, errC
 }
 
@@ -175,8 +182,9 @@ func GetAllVariableByID(db gorp.SqlExecutor, applicationID int64, fargs ...FuncA
 	}
 
 	variables := []sdk.Variable{}
-	query := `SELECT application_variable.id, application_variable.var_name, application_variable.var_value, application_variable.cipher_value, application_variable.var_type
+	query := `SELECT application_variable.id, application_variable.var_name, application_variable.var_value, application_variable.cipher_value, application_variable.var_type, application.project_id
 	          FROM application_variable
+	          JOIN application ON application.id = application_variable.application_id
 	          WHERE application_variable.application_id = $1
 	          ORDER BY var_name`
 	rows, err := db.Query(query, applicationID)
@@ -189,12 +197,13 @@ func GetAllVariableByID(db gorp.SqlExecutor, applicationID int64, fargs ...FuncA
 		var typeVar string
 		var clearVal sql.NullString
 		var cipherVal []byte
-		err = rows.Scan(&v.ID, &v.Name, &clearVal, &cipherVal, &typeVar)
+		var projectID int64
+		err = rows.Scan(&v.ID, &v.Name, &clearVal, &cipherVal, &typeVar, &projectID)
 		if err != nil {
 			return nil, err
 		}
 		v.Type = typeVar
-		v.Value, err = secret.DecryptS(v.Type, clearVal, cipherVal, c.clearsecret)
+		v.Value, err = secret.DecryptSForProject(projectID, v.Type, clearVal, cipherVal, c.clearsecret)
 		if err != nil {
 			return nil, err
 		}
@@ -215,7 +224,7 @@ func InsertVariable(db gorp.SqlExecutor, store cache.Store, app *sdk.Application
 		return fmt.Errorf("You try to insert a placeholder for new variable %s", variable.Name)
 	}
 
-	clear, cipher, err := secret.EncryptS(variable.Type, variable.Value)
+	clear, cipher, err := encryptVariable(db, app, variable.Type, variable.Value)
 	if err != nil {
 		return sdk.WrapError(err, "Cannot encrypt secret")
 	}
@@ -257,7 +266,7 @@ func UpdateVariable(db gorp.SqlExecutor, store cache.Store, app *sdk.Application
 	if sdk.NeedPlaceholder(variable.Type) && variable.Value == sdk.PasswordPlaceholder {
 		variable.Value = variableBefore.Value
 	}
-	clear, cipher, err := secret.EncryptS(variable.Type, variable.Value)
+	clear, cipher, err := encryptVariable(db, app, variable.Type, variable.Value)
 	if err != nil {
 		return sdk.WrapError(err, "Cannot encrypt secret %s", variable.Name)
 	}
@@ -415,3 +424,16 @@ func CountInVarValue(db gorp.SqlExecutor, key string, value string) ([]string, e
 	}
 	return appsName, nil
 }
+
+// encryptVariable encrypts the value of a secret variable with the data key of the project of the application
+func encryptVariable(db gorp.SqlExecutor, app *sdk.Application, ptype, value string) (sql.NullString, []byte, error) {
+	projectID := app.ProjectID
+	if projectID == 0 && sdk.NeedPlaceholder(ptype) {
+		var err error
+		projectID, err = loadProjectID(db, app.ID)
+		if err != nil {
+			return sql.NullString{}, nil, err
+		}
+	}
+	return secret.EncryptSForProject(db, projectID, ptype, value)
+}
@@ -1,17 +1,19 @@
 package application
 
 import (
+	"database/sql"
 	"encoding/base64"
 
 	"github.com/go-gorp/gorp"
 
+	"github.com/ovh/cds/engine/api/database/gorpmapping"
 	"github.com/ovh/cds/engine/api/secret"
 	"github.com/ovh/cds/sdk"
 )
 
-// EncryptVCSStrategyPassword Encrypt vcs password
-func EncryptVCSStrategyPassword(app *sdk.Application) error {
-	encryptedPwd, err := secret.Encrypt([]byte(app.RepositoryStrategy.Password))
+// EncryptVCSStrategyPassword Encrypt vcs password with the data key of the project
+func EncryptVCSStrategyPassword(db gorp.SqlExecutor, projectID int64, app *sdk.Application) error {
+	encryptedPwd, err := secret.EncryptForProject(db, projectID, []byte(app.RepositoryStrategy.Password))
 	if err != nil {
 		return sdk.WrapError(err, "Unable to encrypt password")
 	}
@@ -30,7 +32,7 @@ func DecryptVCSStrategyPassword(app *sdk.Application) error {
 		return sdk.WrapError(err64, "EncryptVCSStrategyPassword> Unable to decoding password")
 	}
 
-	clearPWD, err := secret.Decrypt([]byte(encryptedPassword))
+	clearPWD, err := secret.DecryptForProject(app.ProjectID, encryptedPassword)
 	if err != nil {
 		return sdk.WrapError(err, "Unable to decrypt password")
 	}
@@ -39,6 +41,49 @@ func DecryptVCSStrategyPassword(app *sdk.Application) error {
 	return nil
 }
 
+// RotatePasswords re-encrypts the vcs passwords and the deployment strategy passwords of the applications of the
+// project with its active data key. It returns the number of re-encrypted passwords.
+func RotatePasswords(db gorp.SqlExecutor, projectID int64) (int, error) {
+	apps := []struct {
+		ID          int64          `db:"id"`
+		VCSStrategy sql.NullString `db:"vcs_strategy"`
+	}{}
+	if _, err := db.Select(&apps, "SELECT id, vcs_strategy FROM application WHERE project_id = $1", projectID); err != nil {
+		return 0, sdk.WrapError(err, "cannot load applications of project %d", projectID)
+	}
+
+	var count int
+	for _, a := range apps {
+		app := sdk.Application{ID: a.ID, ProjectID: projectID}
+		if err := gorpmapping.JSONNullString(a.VCSStrategy, &app.RepositoryStrategy); err != nil {
+			return 0, sdk.WrapError(err, "cannot parse vcs strategy of application %d", a.ID)
+		}
+		if app.RepositoryStrategy.Password == "" {
+			continue
+		}
+		if err := DecryptVCSStrategyPassword(&app); err != nil {
+			return 0, sdk.WrapError(err, "cannot decrypt vcs password of application %d", a.ID)
+		}
+		if err := EncryptVCSStrategyPassword(db, projectID, &app); err != nil {
+			return 0, sdk.WrapError(err, "cannot encrypt vcs password of application %d", a.ID)
+		}
+		v, err := gorpmapping.JSONToNullString(app.RepositoryStrategy)
+		if err != nil {
+			return 0, sdk.WrapError(err, "cannot marshal vcs strategy of application %d", a.ID)
+		}
+		if _, err := db.Exec("UPDATE application SET vcs_strategy = $2 WHERE id = $1", a.ID, v); err != nil {
+			return 0, sdk.WrapError(err, "cannot update vcs strategy of application %d", a.ID)
+		}
+		count++
+	}
+
+	n, err := rotateDeploymentStrategiesPasswords(db, projectID)
+	if err != nil {
+		return count, err
+	}
+	return count + n, nil
+}
+
 // CountApplicationByVcsConfigurationKeys counts key use in application vcs configuration for the given project
 func CountApplicationByVcsConfigurationKeys(db gorp.SqlExecutor, projectKey string, vcsName string) ([]string, error) {
 	query := `
@@ -17,7 +17,11 @@ import (
 func InsertKey(db gorp.SqlExecutor, key *sdk.ApplicationKey) error {
 	dbAppKey := dbApplicationKey(*key)
 
-	s, errE := secret.Encrypt([]byte(key.Private))
+	projectID, err := loadProjectID(db, key.ApplicationID)
+	if err != nil {
+		return err
+	}
+	s, errE := secret.EncryptForProject(db, projectID, []byte(key.Private))
 	if errE != nil {
 		return sdk.WrapError(errE, "InsertKey> Cannot encrypt private key")
 	}
@@ -108,11 +112,16 @@ func LoadAllDecryptedKeys(db gorp.SqlExecutor, app *sdk.Application) error {
 		return sdk.WrapError(err, "Cannot load keys")
 	}
 
+	projectID, err := loadProjectID(db, app.ID)
+	if err != nil {
+		return err
+	}
+
 	keys := make([]sdk.ApplicationKey, len(res))
 	for i := range res {
 		p := res[i]
 		keys[i] = sdk.ApplicationKey(p)
-		decrypted, err := secret.Decrypt([]byte(keys[i].Private))
+		decrypted, err := secret.DecryptForProject(projectID, []byte(keys[i].Private))
 		if err != nil {
 			log.Error("LoadAllDecryptedKeys> Unable to decrypt private key %s/%s: %v", app.Name, keys[i].Name, err)
 		}
@@ -127,3 +136,12 @@ func DeleteApplicationKey(db gorp.SqlExecutor, appID int64, keyName string) erro
 	_, err := db.Exec("DELETE FROM application_key WHERE application_id = $1 AND name = $2", appID, keyName)
 	return sdk.WrapError(err, "Cannot delete key %s", keyName)
 }
+
+// loadProjectID returns the id of the project of the application
+func loadProjectID(db gorp.SqlExecutor, appID int64) (int64, error) {
+	id, err := db.SelectInt("SELECT project_id FROM application WHERE id = $1", appID)
+	if err != nil {
+		return 0, sdk.WrapError(err, "cannot load project id of application %d", appID)
+	}
+	return id, nil
+}
@@ -146,9 +146,13 @@ func (ava *dbApplicationVariableAudit) PostInsert(db gorp.SqlExecutor) error {
 
 // PreInsert
 func (ava *dbApplicationVariableAudit) PreInsert(s gorp.SqlExecutor) error {
+	projectID, err := s.SelectInt("SELECT project_id FROM application WHERE id = $1", ava.ApplicationID)
+	if err != nil {
+		return sdk.WrapError(err, "cannot load project of application %d", ava.ApplicationID)
+	}
 	if ava.VariableBefore != nil {
 		if sdk.NeedPlaceholder(ava.VariableBefore.Type) {
-			secret, err := secret.Encrypt([]byte(ava.VariableBefore.Value))
+			secret, err := secret.EncryptForProject(s, projectID, []byte(ava.VariableBefore.Value))
 			if err != nil {
 				return err
 			}
@@ -157,8 +161,7 @@ func (ava *dbApplicationVariableAudit) PreInsert(s gorp.SqlExecutor) error {
 	}
 	if ava.VariableAfter != nil {
 		if sdk.NeedPlaceholder(ava.VariableAfter.Type) {
-			var err error
-			secret, err := secret.Encrypt([]byte(ava.VariableAfter.Value))
+			secret, err := secret.EncryptForProject(s, projectID, []byte(ava.VariableAfter.Value))
 			if err != nil {
 				return err
 			}
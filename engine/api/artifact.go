@@ -7,6 +7,7 @@ import (
 	"net/http"
 
 	"github.com/ovh/cds/engine/api/objectstore"
+	"github.com/ovh/cds/engine/api/provenance"
 	"github.com/ovh/cds/engine/service"
 	"github.com/ovh/cds/sdk"
 	"github.com/ovh/cds/sdk/log"
@@ -18,6 +19,16 @@ func (api *API) getArtifactsStoreHandler() service.Handler {
 	}
 }
 
+func (api *API) getArtifactsProvenanceKeyHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		key := provenance.Key()
+		if key == nil {
+			return sdk.NewErrorFrom(sdk.ErrNotFound, "the provenance of the artifacts is not signed on this instance")
+		}
+		return service.WriteJSON(w, key, http.StatusOK)
+	}
+}
+
 func generateHash() (string, error) {
 	size := 128
 	bs := make([]byte, size)
@@ -0,0 +1,157 @@
+package artifactory
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/ovh/cds/sdk"
+)
+
+var httpClient = &http.Client{Timeout: 5 * time.Minute}
+
+// RepositoryMapping is the source and the target repositories of the promotion of a build to a status
+type RepositoryMapping struct {
+	Status           string
+	SourceRepository string
+	TargetRepository string
+}
+
+// Client calls the Artifactory REST API
+type Client struct {
+	URL      string
+	Token    string
+	Mappings map[string]RepositoryMapping
+}
+
+// Promotion is the body of the build promotion api
+type Promotion struct {
+	Status       string `json:"status"`
+	Comment      string `json:"comment,omitempty"`
+	CIUser       string `json:"ciUser,omitempty"`
+	Timestamp    string `json:"timestamp"`
+	SourceRepo   string `json:"sourceRepo,omitempty"`
+	TargetRepo   string `json:"targetRepo,omitempty"`
+	Copy         bool   `json:"copy"`
+	Artifacts    bool   `json:"artifacts"`
+	Dependencies bool   `json:"dependencies"`
+	FailFast     bool   `json:"failFast"`
+}
+
+// NewClient returns a client from the configuration of an Artifactory integration
+func NewClient(pi sdk.ProjectIntegration) (*Client, error) {
+	c := &Client{
+		URL:   strings.TrimSuffix(pi.Config[sdk.ArtifactoryConfigURL].Value, "/"),
+		Token: pi.Config[sdk.ArtifactoryConfigToken].Value,
+	}
+	if c.URL == "" || c.Token == "" {
+		return nil, sdk.NewErrorFrom(sdk.ErrWrongRequest, "missing url or token on integration %s", pi.Name)
+	}
+	mappings, err := ParseRepositoryMappings(pi.Config[sdk.ArtifactoryConfigRepositoryMappings].Value)
+	if err != nil {
+		return nil, sdk.NewErrorFrom(sdk.ErrWrongRequest, "invalid repository mappings on integration %s: %v", pi.Name, err)
+	}
+	c.Mappings = mappings
+	return c, nil
+}
+
+// ParseRepositoryMappings parses the repository mappings of an integration, one status per line:
+//
+//	staging: libs-snapshot-local -> libs-staging-local
+//	release: libs-staging-local -> libs-release-local
+//
+// The source repository is optional: "release: libs-release-local"
+func ParseRepositoryMappings(s string) (map[string]RepositoryMapping, error) {
+	mappings := map[string]RepositoryMapping{}
+	for _, line := range strings.Split(s, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		statusRepos := strings.SplitN(line, ":", 2)
+		if len(statusRepos) != 2 || strings.TrimSpace(statusRepos[0]) == "" {
+			return nil, fmt.Errorf("invalid mapping %q", line)
+		}
+		m := RepositoryMapping{Status: strings.TrimSpace(statusRepos[0])}
+		repos := strings.SplitN(statusRepos[1], "->", 2)
+		if len(repos) == 2 {
+			m.SourceRepository = strings.TrimSpace(repos[0])
+			m.TargetRepository = strings.TrimSpace(repos[1])
+		} else {
+			m.TargetRepository = strings.TrimSpace(repos[0])
+		}
+		if m.TargetRepository == "" {
+			return nil, fmt.Errorf("invalid mapping %q: missing target repository", line)
+		}
+		if _, has := mappings[m.Status]; has {
+			return nil, fmt.Errorf("duplicated status %s", m.Status)
+		}
+		mappings[m.Status] = m
+	}
+	return mappings, nil
+}
+
+// Promote promotes a build to a status, and moves or copies its artifacts to the target repository of the status
+func (c *Client) Promote(buildName, buildNumber string, p Promotion) error {
+	m, ok := c.Mappings[p.Status]
+	if !ok {
+		return sdk.NewErrorFrom(sdk.ErrWrongRequest, "there is no repository mapping for status %s", p.Status)
+	}
+	p.SourceRepo = m.SourceRepository
+	p.TargetRepo = m.TargetRepository
+	p.Artifacts = true
+	p.FailFast = true
+	if p.Timestamp == "" {
+		p.Timestamp = time.Now().Format("2006-01-02T15:04:05.000-0700")
+	}
+
+	body, err := json.Marshal(p)
+	if err != nil {
+		return sdk.WithStack(err)
+	}
+
+	u := fmt.Sprintf("%s/api/build/promote/%s/%s", c.URL, url.PathEscape(buildName), url.PathEscape(buildNumber))
+	req, err := http.NewRequest(http.MethodPost, u, bytes.NewReader(body))
+	if err != nil {
+		return sdk.WithStack(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+c.Token)
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return sdk.WrapError(err, "unable to promote build %s #%s", buildName, buildNumber)
+	}
+	defer resp.Body.Close() // nolint
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return sdk.WithStack(err)
+	}
+	if resp.StatusCode >= 300 {
+		var artifactoryErr struct {
+			Messages []struct {
+				Level   string `json:"level"`
+				Message string `json:"message"`
+			} `json:"messages"`
+			Errors []struct {
+				Message string `json:"message"`
+			} `json:"errors"`
+		}
+		_ = json.Unmarshal(respBody, &artifactoryErr)
+		var msgs []string
+		for _, m := range artifactoryErr.Messages {
+			msgs = append(msgs, m.Message)
+		}
+		for _, e := range artifactoryErr.Errors {
+			msgs = append(msgs, e.Message)
+		}
+		return sdk.NewErrorFrom(sdk.ErrWrongRequest, "promotion of build %s #%s to %s failed with status %d: %s", buildName, buildNumber, p.Status, resp.StatusCode, strings.Join(msgs, ", "))
+	}
+	return nil
+}
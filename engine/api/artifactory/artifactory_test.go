@@ -0,0 +1,75 @@
+package artifactory
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func TestParseRepositoryMappings(t *testing.T) {
+	mappings, err := ParseRepositoryMappings(`
+# promotion of the builds
+staging: libs-snapshot-local -> libs-staging-local
+release:libs-release-local
+`)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]RepositoryMapping{
+		"staging": {Status: "staging", SourceRepository: "libs-snapshot-local", TargetRepository: "libs-staging-local"},
+		"release": {Status: "release", TargetRepository: "libs-release-local"},
+	}, mappings)
+
+	_, err = ParseRepositoryMappings("libs-release-local")
+	assert.Error(t, err)
+	_, err = ParseRepositoryMappings("release: libs-staging-local -> ")
+	assert.Error(t, err)
+	_, err = ParseRepositoryMappings("release: a\nrelease: b")
+	assert.Error(t, err)
+}
+
+func TestPromote(t *testing.T) {
+	var got Promotion
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/artifactory/api/build/promote/my-build/1.2.3", r.URL.Path)
+		assert.Equal(t, "Bearer my-token", r.Header.Get("Authorization"))
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+		if got.Status == "unknown" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"messages":[{"level":"ERROR","message":"Unable to find build"}]}`)) // nolint
+			return
+		}
+		w.Write([]byte(`{"messages":[]}`)) // nolint
+	}))
+	defer srv.Close()
+
+	c, err := NewClient(sdk.ProjectIntegration{
+		Name: "my-artifactory",
+		Config: sdk.IntegrationConfig{
+			sdk.ArtifactoryConfigURL:                {Value: srv.URL + "/artifactory/"},
+			sdk.ArtifactoryConfigToken:              {Value: "my-token"},
+			sdk.ArtifactoryConfigRepositoryMappings: {Value: "release: libs-staging-local -> libs-release-local\nunknown: foo"},
+		},
+	})
+	assert.NoError(t, err)
+
+	assert.NoError(t, c.Promote("my-build", "1.2.3", Promotion{Status: "release", Comment: "promoted by CDS", Copy: true}))
+	assert.Equal(t, "libs-staging-local", got.SourceRepo)
+	assert.Equal(t, "libs-release-local", got.TargetRepo)
+	assert.Equal(t, "promoted by CDS", got.Comment)
+	assert.True(t, got.Copy)
+	assert.True(t, got.Artifacts)
+
+	err = c.Promote("my-build", "1.2.3", Promotion{Status: "unknown"})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Unable to find build")
+
+	assert.Error(t, c.Promote("my-build", "1.2.3", Promotion{Status: "staging"}))
+
+	_, err = NewClient(sdk.ProjectIntegration{Name: "my-artifactory"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,77 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/ovh/cds/engine/api/auditlog"
+	"github.com/ovh/cds/engine/service"
+	"github.com/ovh/cds/sdk"
+)
+
+// auditLogFilter reads the filters of the audit logs from the query parameters
+func auditLogFilter(r *http.Request) (sdk.AuditLogFilter, error) {
+	f := sdk.AuditLogFilter{
+		Actor:      FormString(r, "actor"),
+		EntityType: FormString(r, "entity"),
+		EntityName: FormString(r, "name"),
+		ProjectKey: FormString(r, "project"),
+	}
+
+	for k, t := range map[string]*time.Time{"since": &f.Since, "until": &f.Until} {
+		if v := FormString(r, k); v != "" {
+			d, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				return f, sdk.NewErrorFrom(sdk.ErrWrongRequest, "invalid %s date, it should be RFC3339", k)
+			}
+			*t = d
+		}
+	}
+
+	var err error
+	if FormString(r, "offset") != "" {
+		if f.Offset, err = FormInt(r, "offset"); err != nil {
+			return f, sdk.NewErrorFrom(sdk.ErrWrongRequest, "invalid offset")
+		}
+	}
+	if FormString(r, "limit") != "" {
+		if f.Limit, err = FormInt(r, "limit"); err != nil {
+			return f, sdk.NewErrorFrom(sdk.ErrWrongRequest, "invalid limit")
+		}
+	}
+	return f, nil
+}
+
+func (api *API) getAdminAuditsHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		f, err := auditLogFilter(r)
+		if err != nil {
+			return err
+		}
+
+		as, err := auditlog.LoadAll(api.mustDB(), f)
+		if err != nil {
+			return err
+		}
+		return service.WriteJSON(w, as, http.StatusOK)
+	}
+}
+
+func (api *API) getProjectAuditsHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		f, err := auditLogFilter(r)
+		if err != nil {
+			return err
+		}
+		f.ProjectKey = mux.Vars(r)["permProjectKey"]
+
+		as, err := auditlog.LoadAll(api.mustDB(), f)
+		if err != nil {
+			return err
+		}
+		return service.WriteJSON(w, as, http.StatusOK)
+	}
+}
@@ -0,0 +1,197 @@
+package auditlog
+
+import (
+	"context"
+	"encoding/json"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/event"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// auditedEntities are the prefixes of the event types recorded in the audit trail
+var auditedEntities = []string{"Project", "Application", "Environment", "Pipeline", "Workflow", "Action", "WorkerModel", "AccessToken"}
+
+var actions = []string{"Add", "Update", "Delete", "Move", "Deny"}
+
+var camelCase = regexp.MustCompile("([a-z0-9])([A-Z])")
+
+// Compute records the events of the API in the audit trail, and purges the audit logs older than the retention.
+// Recorded audit logs are also sent to the sinks of the export, if any.
+func Compute(c context.Context, DBFunc func() *gorp.DbMap, retention time.Duration, export *Export) {
+	chanEvent := make(chan sdk.Event)
+	event.Subscribe(chanEvent)
+	purgeTicker := time.NewTicker(time.Hour)
+	defer purgeTicker.Stop()
+	if export != nil {
+		export.Start(c)
+	}
+
+	for {
+		select {
+		case <-c.Done():
+			if c.Err() != nil {
+				log.Error("auditlog.Compute> Exiting: %v", c.Err())
+			}
+			return
+		case <-purgeTicker.C:
+			if retention <= 0 {
+				continue
+			}
+			n, err := Purge(DBFunc(), retention)
+			if err != nil {
+				log.Error("auditlog.Compute> %v", err)
+				continue
+			}
+			if n > 0 {
+				log.Info("auditlog.Compute> %d audit logs purged", n)
+			}
+		case e := <-chanEvent:
+			a, ok := newAuditLog(e)
+			if !ok {
+				continue
+			}
+			if err := Insert(DBFunc(), a); err != nil {
+				log.Warning("auditlog.Compute> Unable to record event %s: %v", e.EventType, err)
+				continue
+			}
+			if export != nil {
+				export.Push(*a)
+			}
+		}
+	}
+}
+
+// newAuditLog computes the audit log of the event, it returns false if the event is not audited
+func newAuditLog(e sdk.Event) (*sdk.AuditLog, bool) {
+	name := strings.TrimPrefix(e.EventType, "sdk.Event")
+	if name == e.EventType {
+		return nil, false
+	}
+
+	var audited bool
+	for _, p := range auditedEntities {
+		if strings.HasPrefix(name, p) {
+			audited = true
+			break
+		}
+	}
+	if !audited {
+		return nil, false
+	}
+
+	var action string
+	entity := name
+	for _, a := range actions {
+		if strings.HasSuffix(name, a) {
+			action = strings.ToLower(a)
+			entity = strings.TrimSuffix(name, a)
+			break
+		}
+	}
+	if action == "" {
+		return nil, false
+	}
+
+	data, err := scrubbedPayload(e.Payload)
+	if err != nil {
+		log.Warning("newAuditLog> unable to marshal payload of event %s: %v", e.EventType, err)
+		data = []byte("{}")
+	}
+
+	return &sdk.AuditLog{
+		Created:    e.Timestamp,
+		Actor:      e.Username,
+		ActorEmail: e.UserMail,
+		EventType:  name,
+		EntityType: strings.ToLower(camelCase.ReplaceAllString(entity, "${1}_${2}")),
+		EntityName: entityName(e),
+		Action:     action,
+		ProjectKey: e.ProjectKey,
+		Data:       string(data),
+	}, true
+}
+
+// entityName returns the name of the entity changed by the event: the name of the object in the payload,
+// or the name of the most specific entity of the event
+func entityName(e sdk.Event) string {
+	keys := make([]string, 0, len(e.Payload))
+	for k := range e.Payload {
+		if strings.HasPrefix(k, "Old") {
+			continue
+		}
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		m, ok := e.Payload[k].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if n, ok := m["Name"].(string); ok && n != "" {
+			return n
+		}
+		if g, ok := m["Group"].(map[string]interface{}); ok {
+			if n, ok := g["Name"].(string); ok && n != "" {
+				return n
+			}
+		}
+	}
+
+	for _, n := range []string{e.WorkflowName, e.PipelineName, e.ApplicationName, e.EnvironmentName, e.ProjectKey} {
+		if n != "" {
+			return n
+		}
+	}
+	return ""
+}
+
+// scrubbedPayload returns the payload of an event, without the values of the secret variables and integration
+// configurations, and without the private keys. The payload of the event is not modified.
+func scrubbedPayload(payload map[string]interface{}) ([]byte, error) {
+	btes, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	var i interface{}
+	if err := json.Unmarshal(btes, &i); err != nil {
+		return nil, err
+	}
+	scrub(i)
+	return json.Marshal(i)
+}
+
+func scrub(i interface{}) {
+	switch v := i.(type) {
+	case []interface{}:
+		for _, e := range v {
+			scrub(e)
+		}
+	case map[string]interface{}:
+		var secret bool
+		for k, e := range v {
+			if t, ok := e.(string); ok && strings.EqualFold(k, "type") && sdk.NeedPlaceholder(t) {
+				secret = true
+			}
+		}
+		for k, e := range v {
+			switch {
+			case strings.EqualFold(k, "private"):
+				if p, ok := e.(string); ok && p != "" {
+					v[k] = sdk.PasswordPlaceholder
+				}
+			case secret && strings.EqualFold(k, "value"):
+				v[k] = sdk.PasswordPlaceholder
+			default:
+				scrub(e)
+			}
+		}
+	}
+}
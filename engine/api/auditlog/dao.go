@@ -0,0 +1,91 @@
+package auditlog
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/sdk"
+)
+
+const (
+	defaultLimit = 100
+	maxLimit     = 1000
+)
+
+// Insert an audit log in database.
+func Insert(db gorp.SqlExecutor, a *sdk.AuditLog) error {
+	if a.Data == "" {
+		a.Data = "{}"
+	}
+	return sdk.WrapError(db.Insert(a), "cannot insert audit log")
+}
+
+// LoadAll returns the audit logs matching the filter, most recent first.
+func LoadAll(db gorp.SqlExecutor, f sdk.AuditLogFilter) ([]sdk.AuditLog, error) {
+	query, args := filterQuery(f)
+	as := []sdk.AuditLog{}
+	if _, err := db.Select(&as, query, args...); err != nil {
+		return nil, sdk.WrapError(err, "cannot load audit logs")
+	}
+	return as, nil
+}
+
+func filterQuery(f sdk.AuditLogFilter) (string, []interface{}) {
+	var conds []string
+	var args []interface{}
+	add := func(cond string, arg interface{}) {
+		args = append(args, arg)
+		conds = append(conds, fmt.Sprintf(cond, len(args)))
+	}
+
+	if f.Actor != "" {
+		add("actor = $%d", f.Actor)
+	}
+	if f.EntityType != "" {
+		add("entity_type = $%d", f.EntityType)
+	}
+	if f.EntityName != "" {
+		add("entity_name = $%d", f.EntityName)
+	}
+	if f.ProjectKey != "" {
+		add("project_key = $%d", f.ProjectKey)
+	}
+	if !f.Since.IsZero() {
+		add("created >= $%d", f.Since)
+	}
+	if !f.Until.IsZero() {
+		add("created <= $%d", f.Until)
+	}
+
+	query := "SELECT * FROM audit_log"
+	if len(conds) > 0 {
+		query += " WHERE " + strings.Join(conds, " AND ")
+	}
+
+	limit := f.Limit
+	if limit <= 0 {
+		limit = defaultLimit
+	}
+	if limit > maxLimit {
+		limit = maxLimit
+	}
+	offset := f.Offset
+	if offset < 0 {
+		offset = 0
+	}
+	query += fmt.Sprintf(" ORDER BY created DESC, id DESC LIMIT %d OFFSET %d", limit, offset)
+	return query, args
+}
+
+// Purge deletes the audit logs older than the retention.
+func Purge(db gorp.SqlExecutor, retention time.Duration) (int64, error) {
+	res, err := db.Exec("DELETE FROM audit_log WHERE created < $1", time.Now().Add(-retention))
+	if err != nil {
+		return 0, sdk.WrapError(err, "cannot purge audit logs")
+	}
+	n, _ := res.RowsAffected()
+	return n, nil
+}
@@ -0,0 +1,344 @@
+package auditlog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// ExportConfiguration is the configuration of the sinks receiving the audit logs in near real time
+type ExportConfiguration struct {
+	Syslog struct {
+		Addr     string `toml:"addr" comment:"Syslog server address, example: udp://siem.local:514 or tcp://siem.local:601" json:"addr"`
+		Format   string `toml:"format" default:"cef" comment:"cef or leef" json:"format"`
+		Facility int    `toml:"facility" default:"13" comment:"Syslog facility (13: log audit)" json:"facility"`
+	} `toml:"syslog" json:"syslog"`
+	Webhook struct {
+		URL           string `toml:"url" comment:"HTTPS endpoint receiving batches of audit logs as a JSON array" json:"url"`
+		Authorization string `toml:"authorization" comment:"Value of the Authorization header, example: Splunk <token>" json:"-"`
+	} `toml:"webhook" json:"webhook"`
+	BatchSize     int `toml:"batchSize" default:"100" comment:"Max number of audit logs sent at once" json:"batchSize"`
+	FlushInterval int `toml:"flushInterval" default:"5" comment:"Max delay (in seconds) before sending the audit logs" json:"flushInterval"`
+	MaxRetries    int `toml:"maxRetries" default:"5" comment:"Number of retries when a sink is unavailable, the audit logs are dropped after" json:"maxRetries"`
+}
+
+// Exporter sends audit logs to an external system
+type Exporter interface {
+	Name() string
+	Export(as []sdk.AuditLog) error
+}
+
+// Export streams the audit logs to the sinks enabled in the configuration
+type Export struct {
+	queues []*exportQueue
+}
+
+// NewExport returns the export of the audit logs to the sinks enabled in the configuration
+func NewExport(c ExportConfiguration) (*Export, error) {
+	es, err := newExporters(c)
+	if err != nil {
+		return nil, err
+	}
+	e := &Export{}
+	for _, x := range es {
+		e.queues = append(e.queues, newExportQueue(x, c))
+	}
+	return e, nil
+}
+
+// Start sends the audit logs to the sinks until the context is done
+func (e *Export) Start(c context.Context) {
+	for _, q := range e.queues {
+		go q.run(c)
+	}
+}
+
+// Push adds an audit log to the queue of each sink
+func (e *Export) Push(a sdk.AuditLog) {
+	for _, q := range e.queues {
+		q.push(a)
+	}
+}
+
+func newExporters(c ExportConfiguration) ([]Exporter, error) {
+	var es []Exporter
+	if c.Syslog.Addr != "" {
+		e, err := newSyslogExporter(c.Syslog.Addr, c.Syslog.Format, c.Syslog.Facility)
+		if err != nil {
+			return nil, err
+		}
+		es = append(es, e)
+	}
+	if c.Webhook.URL != "" {
+		if !strings.HasPrefix(c.Webhook.URL, "https://") && !strings.HasPrefix(c.Webhook.URL, "http://") {
+			return nil, fmt.Errorf("invalid audit webhook url %s", c.Webhook.URL)
+		}
+		es = append(es, &webhookExporter{
+			url:           c.Webhook.URL,
+			authorization: c.Webhook.Authorization,
+			client:        &http.Client{Timeout: 30 * time.Second},
+		})
+	}
+	return es, nil
+}
+
+// exportQueue batches the audit logs and sends them to an exporter, retrying on errors
+type exportQueue struct {
+	exporter      Exporter
+	ch            chan sdk.AuditLog
+	batchSize     int
+	flushInterval time.Duration
+	maxRetries    int
+}
+
+func newExportQueue(e Exporter, c ExportConfiguration) *exportQueue {
+	q := &exportQueue{
+		exporter:      e,
+		batchSize:     c.BatchSize,
+		flushInterval: time.Duration(c.FlushInterval) * time.Second,
+		maxRetries:    c.MaxRetries,
+	}
+	if q.batchSize <= 0 {
+		q.batchSize = 100
+	}
+	if q.flushInterval <= 0 {
+		q.flushInterval = 5 * time.Second
+	}
+	q.ch = make(chan sdk.AuditLog, 10*q.batchSize)
+	return q
+}
+
+// push adds an audit log to the queue, it never blocks: if the queue is full the audit log is not exported
+func (q *exportQueue) push(a sdk.AuditLog) {
+	select {
+	case q.ch <- a:
+	default:
+		log.Warning("auditlog.export> %s queue is full, audit log %d dropped", q.exporter.Name(), a.ID)
+	}
+}
+
+func (q *exportQueue) run(c context.Context) {
+	tick := time.NewTicker(q.flushInterval)
+	defer tick.Stop()
+
+	batch := make([]sdk.AuditLog, 0, q.batchSize)
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		q.send(c, batch)
+		batch = make([]sdk.AuditLog, 0, q.batchSize)
+	}
+
+	for {
+		select {
+		case <-c.Done():
+			// drain the queue before the last flush
+			for len(q.ch) > 0 {
+				batch = append(batch, <-q.ch)
+			}
+			flush()
+			return
+		case a := <-q.ch:
+			batch = append(batch, a)
+			if len(batch) >= q.batchSize {
+				flush()
+			}
+		case <-tick.C:
+			flush()
+		}
+	}
+}
+
+func (q *exportQueue) send(c context.Context, batch []sdk.AuditLog) {
+	backoff := time.Second
+	for attempt := 0; ; attempt++ {
+		err := q.exporter.Export(batch)
+		if err == nil {
+			return
+		}
+		if attempt >= q.maxRetries {
+			log.Error("auditlog.export> unable to send %d audit logs to %s, they are dropped: %v", len(batch), q.exporter.Name(), err)
+			return
+		}
+		log.Warning("auditlog.export> unable to send audit logs to %s (attempt %d): %v", q.exporter.Name(), attempt+1, err)
+		select {
+		case <-c.Done():
+			return
+		case <-time.After(backoff):
+		}
+		if backoff < time.Minute {
+			backoff *= 2
+		}
+	}
+}
+
+// webhookExporter posts the audit logs as a JSON array
+type webhookExporter struct {
+	url           string
+	authorization string
+	client        *http.Client
+}
+
+func (w *webhookExporter) Name() string { return "webhook" }
+
+func (w *webhookExporter) Export(as []sdk.AuditLog) error {
+	body, err := json.Marshal(as)
+	if err != nil {
+		return sdk.WithStack(err)
+	}
+	req, err := http.NewRequest(http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return sdk.WithStack(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if w.authorization != "" {
+		req.Header.Set("Authorization", w.authorization)
+	}
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return sdk.WithStack(err)
+	}
+	resp.Body.Close() // nolint
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// syslogExporter sends the audit logs to a syslog server, formatted in CEF or LEEF
+type syslogExporter struct {
+	network  string
+	addr     string
+	format   string
+	facility int
+	hostname string
+	conn     net.Conn
+}
+
+func newSyslogExporter(addr, format string, facility int) (*syslogExporter, error) {
+	e := &syslogExporter{network: "udp", addr: addr, format: strings.ToLower(format), facility: facility}
+	if i := strings.Index(addr, "://"); i > 0 {
+		e.network, e.addr = addr[:i], addr[i+3:]
+	}
+	if e.network != "udp" && e.network != "tcp" {
+		return nil, fmt.Errorf("invalid syslog network %s", e.network)
+	}
+	if e.format == "" {
+		e.format = "cef"
+	}
+	if e.format != "cef" && e.format != "leef" {
+		return nil, fmt.Errorf("invalid syslog format %s", format)
+	}
+	e.hostname, _ = os.Hostname()
+	return e, nil
+}
+
+func (e *syslogExporter) Name() string { return "syslog" }
+
+func (e *syslogExporter) Export(as []sdk.AuditLog) error {
+	if e.conn == nil {
+		conn, err := net.DialTimeout(e.network, e.addr, 10*time.Second)
+		if err != nil {
+			return sdk.WithStack(err)
+		}
+		e.conn = conn
+	}
+
+	var buf bytes.Buffer
+	for _, a := range as {
+		var msg string
+		if e.format == "leef" {
+			msg = formatLEEF(a)
+		} else {
+			msg = formatCEF(a)
+		}
+		// RFC 5424 header, severity notice
+		fmt.Fprintf(&buf, "<%d>1 %s %s cds - - - %s", e.facility*8+5, a.Created.UTC().Format(time.RFC3339), e.hostname, msg)
+		// octet counting is not supported by all servers, messages are separated by a new line on tcp
+		buf.WriteByte('\n')
+		if e.network == "udp" {
+			if _, err := e.conn.Write(buf.Bytes()); err != nil {
+				e.conn.Close() // nolint
+				e.conn = nil
+				return sdk.WithStack(err)
+			}
+			buf.Reset()
+		}
+	}
+	if buf.Len() > 0 {
+		if _, err := e.conn.Write(buf.Bytes()); err != nil {
+			e.conn.Close() // nolint
+			e.conn = nil
+			return sdk.WithStack(err)
+		}
+	}
+	return nil
+}
+
+var (
+	cefHeaderEscaper    = strings.NewReplacer(`\`, `\\`, `|`, `\|`)
+	cefExtensionEscaper = strings.NewReplacer(`\`, `\\`, `=`, `\=`, "\n", `\n`, "\r", `\r`)
+	leefEscaper         = strings.NewReplacer("\t", " ", "\n", " ", "\r", " ")
+)
+
+// formatCEF formats the audit log in ArcSight Common Event Format
+func formatCEF(a sdk.AuditLog) string {
+	ext := []string{
+		"rt=" + strconv.FormatInt(a.Created.UnixNano()/int64(time.Millisecond), 10),
+		"suser=" + cefExtensionEscaper.Replace(a.Actor),
+		"act=" + cefExtensionEscaper.Replace(a.Action),
+		"cs1Label=entityType cs1=" + cefExtensionEscaper.Replace(a.EntityType),
+		"cs2Label=entityName cs2=" + cefExtensionEscaper.Replace(a.EntityName),
+		"cs3Label=projectKey cs3=" + cefExtensionEscaper.Replace(a.ProjectKey),
+		"externalId=" + strconv.FormatInt(a.ID, 10),
+	}
+	if a.ActorEmail != "" {
+		ext = append(ext, "suid="+cefExtensionEscaper.Replace(a.ActorEmail))
+	}
+	return fmt.Sprintf("CEF:0|OVH|CDS|%s|%s|%s|%d|%s",
+		cefHeaderEscaper.Replace(sdk.VERSION),
+		cefHeaderEscaper.Replace(a.EventType),
+		cefHeaderEscaper.Replace(a.EntityType+" "+a.Action),
+		cefSeverity(a.Action),
+		strings.Join(ext, " "))
+}
+
+// formatLEEF formats the audit log in IBM QRadar Log Event Extended Format
+func formatLEEF(a sdk.AuditLog) string {
+	attrs := []string{
+		"devTime=" + a.Created.UTC().Format("Jan 02 2006 15:04:05"),
+		"devTimeFormat=MMM dd yyyy HH:mm:ss",
+		"usrName=" + leefEscaper.Replace(a.Actor),
+		"action=" + leefEscaper.Replace(a.Action),
+		"entityType=" + leefEscaper.Replace(a.EntityType),
+		"entityName=" + leefEscaper.Replace(a.EntityName),
+		"projectKey=" + leefEscaper.Replace(a.ProjectKey),
+		"sev=" + strconv.Itoa(cefSeverity(a.Action)),
+	}
+	if a.ActorEmail != "" {
+		attrs = append(attrs, "email="+leefEscaper.Replace(a.ActorEmail))
+	}
+	return fmt.Sprintf("LEEF:1.0|OVH|CDS|%s|%s|%s",
+		leefEscaper.Replace(sdk.VERSION), leefEscaper.Replace(a.EventType), strings.Join(attrs, "\t"))
+}
+
+func cefSeverity(action string) int {
+	switch action {
+	case sdk.AuditDelete:
+		return 6
+	case sdk.AuditUpdate:
+		return 4
+	}
+	return 3
+}
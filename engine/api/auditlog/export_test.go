@@ -0,0 +1,118 @@
+package auditlog
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func TestFormatCEF(t *testing.T) {
+	a := sdk.AuditLog{
+		ID:         42,
+		Created:    time.Unix(1548892800, 0),
+		Actor:      "john",
+		EventType:  "ProjectVariableDelete",
+		EntityType: "project_variable",
+		EntityName: "a=b",
+		Action:     sdk.AuditDelete,
+		ProjectKey: "PROJ",
+	}
+	assert.Equal(t, "CEF:0|OVH|CDS|"+sdk.VERSION+"|ProjectVariableDelete|project_variable delete|6|"+
+		`rt=1548892800000 suser=john act=delete cs1Label=entityType cs1=project_variable cs2Label=entityName cs2=a\=b cs3Label=projectKey cs3=PROJ externalId=42`,
+		formatCEF(a))
+}
+
+func TestFormatLEEF(t *testing.T) {
+	a := sdk.AuditLog{
+		Created:    time.Unix(1548892800, 0),
+		Actor:      "john",
+		ActorEmail: "john@localhost",
+		EventType:  "WorkflowAdd",
+		EntityType: "workflow",
+		EntityName: "my\tworkflow",
+		Action:     sdk.AuditAdd,
+		ProjectKey: "PROJ",
+	}
+	assert.Equal(t, "LEEF:1.0|OVH|CDS|"+sdk.VERSION+"|WorkflowAdd|"+
+		"devTime=Jan 31 2019 00:00:00\tdevTimeFormat=MMM dd yyyy HH:mm:ss\tusrName=john\taction=add\tentityType=workflow\tentityName=my workflow\tprojectKey=PROJ\tsev=3\temail=john@localhost",
+		formatLEEF(a))
+}
+
+func TestExportWebhook(t *testing.T) {
+	var mutex sync.Mutex
+	var batches [][]sdk.AuditLog
+	var calls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		calls++
+		// the first call fails, the batch must be sent again
+		if calls == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		assert.Equal(t, "Splunk token", r.Header.Get("Authorization"))
+		var as []sdk.AuditLog
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&as))
+		batches = append(batches, as)
+	}))
+	defer srv.Close()
+
+	var c ExportConfiguration
+	c.Webhook.URL = srv.URL
+	c.Webhook.Authorization = "Splunk token"
+	c.BatchSize = 2
+	c.FlushInterval = 60
+	c.MaxRetries = 1
+	e, err := NewExport(c)
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	e.Start(ctx)
+	e.Push(sdk.AuditLog{ID: 1})
+	e.Push(sdk.AuditLog{ID: 2})
+	e.Push(sdk.AuditLog{ID: 3})
+
+	waitFor(t, func() bool {
+		mutex.Lock()
+		defer mutex.Unlock()
+		return len(batches) == 1
+	})
+
+	// remaining audit logs are sent when the export stops
+	cancel()
+	waitFor(t, func() bool {
+		mutex.Lock()
+		defer mutex.Unlock()
+		return len(batches) == 2
+	})
+
+	assert.Len(t, batches[0], 2)
+	assert.Equal(t, int64(3), batches[1][0].ID)
+}
+
+func TestNewExportInvalidConfiguration(t *testing.T) {
+	var c ExportConfiguration
+	c.Syslog.Addr = "udp://localhost:514"
+	c.Syslog.Format = "json"
+	_, err := NewExport(c)
+	assert.Error(t, err)
+}
+
+func waitFor(t *testing.T, f func() bool) {
+	for i := 0; i < 100; i++ {
+		if f() {
+			return
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	t.Fatal("timeout")
+}
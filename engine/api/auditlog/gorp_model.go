@@ -0,0 +1,10 @@
+package auditlog
+
+import (
+	"github.com/ovh/cds/engine/api/database/gorpmapping"
+	"github.com/ovh/cds/sdk"
+)
+
+func init() {
+	gorpmapping.Register(gorpmapping.New(sdk.AuditLog{}, "audit_log", true, "id"))
+}
@@ -0,0 +1,333 @@
+package auth
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-gorp/gorp"
+	"gopkg.in/ldap.v2"
+
+	"github.com/ovh/cds/engine/api/cache"
+	"github.com/ovh/cds/engine/api/group"
+	"github.com/ovh/cds/engine/api/user"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+var (
+	ldapGroupSyncStatusKey = cache.Key("ldap", "groups", "sync", "status")
+	ldapGroupSyncLockKey   = cache.Key("ldap", "groups", "sync", "lock")
+)
+
+//LDAPGroupSyncConfig handles the configuration of the LDAP groups synchronization
+type LDAPGroupSyncConfig struct {
+	Interval        time.Duration
+	Base            string
+	Filter          string
+	NameAttribute   string
+	MemberAttribute string
+	Prefix          string
+	DryRun          bool
+}
+
+// ldapGroupEntry is a group read from the LDAP server
+type ldapGroupEntry struct {
+	DN      string
+	Name    string
+	Members []string
+}
+
+// ldapSyncGroup is the current state of a CDS group for the LDAP groups synchronization
+type ldapSyncGroup struct {
+	ID      int64
+	DN      string
+	Managed bool
+	Deleted bool
+	Members []string
+	Admins  []string
+}
+
+// memberUsername returns the username of a group member, given by its uid or by a DN starting with its uid
+func memberUsername(member string) string {
+	if !strings.Contains(member, "=") {
+		return strings.TrimSpace(member)
+	}
+	dn, err := ldap.ParseDN(member)
+	if err != nil || len(dn.RDNs) == 0 {
+		return ""
+	}
+	for _, a := range dn.RDNs[0].Attributes {
+		if strings.EqualFold(a.Type, "uid") {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+func (c *LDAPClient) searchGroups(conf LDAPGroupSyncConfig) ([]ldapGroupEntry, error) {
+	sr, err := c.search(conf.Base, conf.Filter, []string{conf.NameAttribute, conf.MemberAttribute})
+	if err != nil {
+		return nil, sdk.WrapError(err, "cannot search groups in %s", conf.Base)
+	}
+
+	entries := make([]ldapGroupEntry, 0, len(sr))
+	for _, e := range sr {
+		name := conf.Prefix + e.GetAttributeValue(conf.NameAttribute)
+		if !sdk.NamePatternRegex.MatchString(name) {
+			log.Warning("LDAP> group %s ignored: invalid group name %s", e.DN, name)
+			continue
+		}
+		entry := ldapGroupEntry{DN: e.DN, Name: name}
+		for _, m := range e.GetAttributeValues(conf.MemberAttribute) {
+			if u := memberUsername(m); u != "" {
+				entry.Members = append(entry.Members, u)
+			} else {
+				log.Debug("LDAP> member %s of group %s ignored", m, e.DN)
+			}
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// planLDAPGroupSync computes the changes to apply on the CDS groups to match the LDAP groups. Only the members known
+// in CDS are added to the groups, the group admins are never removed. The managed groups which are not in the LDAP
+// anymore are soft deleted: their members are removed but the group and its permissions are kept.
+func planLDAPGroupSync(entries []ldapGroupEntry, groups map[string]ldapSyncGroup, users map[string]int64) []sdk.LDAPGroupSyncChange {
+	changes := []sdk.LDAPGroupSyncChange{}
+	seen := map[string]bool{}
+
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name < entries[j].Name })
+	for _, e := range entries {
+		if seen[e.Name] {
+			log.Warning("LDAP> group %s ignored: another group is named %s", e.DN, e.Name)
+			continue
+		}
+		seen[e.Name] = true
+
+		g, exists := groups[e.Name]
+		switch {
+		case !exists:
+			changes = append(changes, sdk.LDAPGroupSyncChange{Action: sdk.LDAPGroupSyncCreate, Group: e.Name})
+		case g.Deleted:
+			changes = append(changes, sdk.LDAPGroupSyncChange{Action: sdk.LDAPGroupSyncRestore, Group: e.Name})
+		case !g.Managed || g.DN != e.DN:
+			changes = append(changes, sdk.LDAPGroupSyncChange{Action: sdk.LDAPGroupSyncLink, Group: e.Name})
+		}
+
+		current := map[string]bool{}
+		for _, u := range g.Members {
+			current[u] = true
+		}
+		for _, u := range g.Admins {
+			current[u] = true
+		}
+
+		wanted := map[string]bool{}
+		for _, u := range e.Members {
+			if _, known := users[u]; !known || wanted[u] {
+				continue
+			}
+			wanted[u] = true
+			if !current[u] {
+				changes = append(changes, sdk.LDAPGroupSyncChange{Action: sdk.LDAPGroupSyncAddMember, Group: e.Name, User: u})
+			}
+		}
+		for _, u := range g.Members {
+			if !wanted[u] {
+				changes = append(changes, sdk.LDAPGroupSyncChange{Action: sdk.LDAPGroupSyncRemoveMember, Group: e.Name, User: u})
+			}
+		}
+	}
+
+	names := make([]string, 0, len(groups))
+	for name := range groups {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		g := groups[name]
+		if !g.Managed || g.Deleted || seen[name] {
+			continue
+		}
+		for _, u := range g.Members {
+			changes = append(changes, sdk.LDAPGroupSyncChange{Action: sdk.LDAPGroupSyncRemoveMember, Group: name, User: u})
+		}
+		changes = append(changes, sdk.LDAPGroupSyncChange{Action: sdk.LDAPGroupSyncDelete, Group: name})
+	}
+
+	return changes
+}
+
+func applyLDAPGroupSync(db gorp.SqlExecutor, changes []sdk.LDAPGroupSyncChange, entries []ldapGroupEntry, groups map[string]ldapSyncGroup, users map[string]int64) error {
+	dns := map[string]string{}
+	for _, e := range entries {
+		if _, has := dns[e.Name]; !has {
+			dns[e.Name] = e.DN
+		}
+	}
+	ids := map[string]int64{}
+	for name, g := range groups {
+		ids[name] = g.ID
+	}
+
+	for _, c := range changes {
+		switch c.Action {
+		case sdk.LDAPGroupSyncCreate:
+			g := sdk.Group{Name: c.Group}
+			if err := group.InsertGroup(db, &g); err != nil {
+				return sdk.WrapError(err, "cannot create group %s", c.Group)
+			}
+			ids[c.Group] = g.ID
+			if err := group.UpsertLDAPGroup(db, g.ID, dns[c.Group], false); err != nil {
+				return err
+			}
+		case sdk.LDAPGroupSyncLink, sdk.LDAPGroupSyncRestore:
+			if err := group.UpsertLDAPGroup(db, ids[c.Group], dns[c.Group], false); err != nil {
+				return err
+			}
+		case sdk.LDAPGroupSyncDelete:
+			if err := group.UpsertLDAPGroup(db, ids[c.Group], groups[c.Group].DN, true); err != nil {
+				return err
+			}
+		case sdk.LDAPGroupSyncAddMember:
+			if err := group.InsertUserInGroup(db, ids[c.Group], users[c.User], false); err != nil {
+				return sdk.WrapError(err, "cannot add user %s in group %s", c.User, c.Group)
+			}
+		case sdk.LDAPGroupSyncRemoveMember:
+			userID, err := user.FindUserIDByName(db, c.User)
+			if err != nil {
+				return sdk.WrapError(err, "cannot find user %s", c.User)
+			}
+			if err := group.DeleteGroupMember(db, ids[c.Group], userID); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// SyncLDAPGroups synchronizes the CDS groups with the groups of the LDAP server. With dry run, the changes are only
+// computed.
+func (c *LDAPClient) SyncLDAPGroups(db *gorp.DbMap, conf LDAPGroupSyncConfig) ([]sdk.LDAPGroupSyncChange, error) {
+	entries, err := c.searchGroups(conf)
+	if err != nil {
+		return nil, err
+	}
+
+	managed, err := group.LoadLDAPGroups(db)
+	if err != nil {
+		return nil, err
+	}
+	groups := map[string]ldapSyncGroup{}
+	for _, g := range managed {
+		groups[g.Name] = ldapSyncGroup{ID: g.ID, DN: g.DN, Managed: true, Deleted: g.Deleted}
+	}
+	for _, e := range entries {
+		if _, has := groups[e.Name]; has {
+			continue
+		}
+		g, err := group.LoadGroupByName(db, e.Name)
+		if err != nil {
+			if sdk.ErrorIs(err, sdk.ErrGroupNotFound) {
+				continue
+			}
+			return nil, err
+		}
+		groups[e.Name] = ldapSyncGroup{ID: g.ID}
+	}
+	for name, g := range groups {
+		grp := sdk.Group{ID: g.ID, Name: name}
+		if err := group.LoadUserGroup(db, &grp); err != nil {
+			return nil, sdk.WrapError(err, "cannot load users of group %s", name)
+		}
+		for _, u := range grp.Users {
+			g.Members = append(g.Members, u.Username)
+		}
+		for _, u := range grp.Admins {
+			g.Admins = append(g.Admins, u.Username)
+		}
+		groups[name] = g
+	}
+
+	users := map[string]int64{}
+	for _, e := range entries {
+		for _, u := range e.Members {
+			if _, has := users[u]; has {
+				continue
+			}
+			id, err := user.FindUserIDByName(db, u)
+			if err == sql.ErrNoRows {
+				continue
+			}
+			if err != nil {
+				return nil, sdk.WrapError(err, "cannot find user %s", u)
+			}
+			users[u] = id
+		}
+	}
+
+	changes := planLDAPGroupSync(entries, groups, users)
+	if conf.DryRun || len(changes) == 0 {
+		return changes, nil
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return nil, sdk.WithStack(err)
+	}
+	defer tx.Rollback() // nolint
+
+	if err := applyLDAPGroupSync(tx, changes, entries, groups, users); err != nil {
+		return nil, err
+	}
+	return changes, sdk.WithStack(tx.Commit())
+}
+
+// LoadLDAPGroupSyncStatus returns the status of the last LDAP groups synchronization
+func LoadLDAPGroupSyncStatus(store cache.Store) sdk.LDAPGroupSyncStatus {
+	var status sdk.LDAPGroupSyncStatus
+	store.Get(ldapGroupSyncStatusKey, &status)
+	return status
+}
+
+// LDAPGroupSync synchronizes periodically the CDS groups with the LDAP groups. Only one API instance runs each
+// synchronization, the status of the last one is kept in cache.
+func LDAPGroupSync(ctx context.Context, d Driver, dbFunc func() *gorp.DbMap, store cache.Store, conf LDAPGroupSyncConfig) {
+	c, ok := d.(*LDAPClient)
+	if !ok {
+		log.Error("LDAPGroupSync> LDAP groups synchronization needs the LDAP authentication")
+		return
+	}
+
+	tick := time.NewTicker(conf.Interval)
+	defer tick.Stop()
+	for {
+		if store.Lock(ldapGroupSyncLockKey, conf.Interval*9/10, 0, 1) {
+			status := sdk.LDAPGroupSyncStatus{
+				Enabled: true,
+				DryRun:  conf.DryRun,
+				Start:   time.Now(),
+			}
+			changes, err := c.SyncLDAPGroups(dbFunc(), conf)
+			if err != nil {
+				log.Error("LDAPGroupSync> %v", err)
+				status.Error = err.Error()
+			} else {
+				log.Info("LDAPGroupSync> %d changes (dry run: %t)", len(changes), conf.DryRun)
+			}
+			status.Changes = changes
+			status.End = time.Now()
+			store.Set(ldapGroupSyncStatusKey, status)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-tick.C:
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package auth
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func TestMemberUsername(t *testing.T) {
+	assert.Equal(t, "jdoe", memberUsername("jdoe"))
+	assert.Equal(t, "jdoe", memberUsername("uid=jdoe,ou=people,dc=myorganization,dc=com"))
+	assert.Equal(t, "", memberUsername("cn=John Doe,ou=people,dc=myorganization,dc=com"))
+}
+
+func TestPlanLDAPGroupSync(t *testing.T) {
+	entries := []ldapGroupEntry{
+		{DN: "cn=team-b,ou=groups", Name: "team-b", Members: []string{"alice", "bob", "unknown"}},
+		{DN: "cn=team-a,ou=groups", Name: "team-a", Members: []string{"alice", "alice"}},
+		{DN: "cn=team-c,ou=groups", Name: "team-c", Members: []string{"carol"}},
+		{DN: "cn=team-d,ou=groups", Name: "team-d", Members: []string{"alice"}},
+	}
+	groups := map[string]ldapSyncGroup{
+		// Existing group not yet managed, its admin is kept
+		"team-b": {ID: 2, Members: []string{"dave"}, Admins: []string{"erin"}},
+		// Soft deleted group back in the LDAP
+		"team-c": {ID: 3, DN: "cn=team-c,ou=groups", Managed: true, Deleted: true},
+		// Managed group already up to date
+		"team-d": {ID: 4, DN: "cn=team-d,ou=groups", Managed: true, Members: []string{"alice"}},
+		// Managed group removed from the LDAP
+		"team-e": {ID: 5, DN: "cn=team-e,ou=groups", Managed: true, Members: []string{"bob"}, Admins: []string{"erin"}},
+		// Not managed group, never changed
+		"team-f": {ID: 6, Members: []string{"bob"}},
+	}
+	users := map[string]int64{"alice": 1, "bob": 2, "carol": 3, "dave": 4, "erin": 5}
+
+	changes := planLDAPGroupSync(entries, groups, users)
+	assert.Equal(t, []sdk.LDAPGroupSyncChange{
+		{Action: sdk.LDAPGroupSyncCreate, Group: "team-a"},
+		{Action: sdk.LDAPGroupSyncAddMember, Group: "team-a", User: "alice"},
+		{Action: sdk.LDAPGroupSyncLink, Group: "team-b"},
+		{Action: sdk.LDAPGroupSyncAddMember, Group: "team-b", User: "alice"},
+		{Action: sdk.LDAPGroupSyncAddMember, Group: "team-b", User: "bob"},
+		{Action: sdk.LDAPGroupSyncRemoveMember, Group: "team-b", User: "dave"},
+		{Action: sdk.LDAPGroupSyncRestore, Group: "team-c"},
+		{Action: sdk.LDAPGroupSyncAddMember, Group: "team-c", User: "carol"},
+		{Action: sdk.LDAPGroupSyncRemoveMember, Group: "team-e", User: "bob"},
+		{Action: sdk.LDAPGroupSyncDelete, Group: "team-e"},
+	}, changes)
+}
@@ -229,6 +229,32 @@ func (c *LDAPClient) Bind(username, password string) error {
 func (c *LDAPClient) Search(filter string, attributes ...string) ([]Entry, error) {
 	attr := append(attributes, "dn")
 
+	sr, err := c.search(c.conf.Base, filter, attr)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(sr) < 1 {
+		return nil, errors.New(errUserNotFound)
+	}
+
+	entries := []Entry{}
+	for _, e := range sr {
+		entry := Entry{
+			DN:         e.DN,
+			Attributes: make(map[string]string),
+		}
+		for _, a := range attr {
+			entry.Attributes[a] = e.GetAttributeValue(a)
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+// search runs a search request from the given base, with the bind user if any
+func (c *LDAPClient) search(base, filter string, attributes []string) ([]*ldap.Entry, error) {
 	if c.conf.BindDN != "" {
 		log.Debug("LDAP> Bind user %s", c.conf.BindDN)
 		if err := c.conn.Bind(c.conf.BindDN, c.conf.BindPwd); err != nil {
@@ -245,12 +271,11 @@ func (c *LDAPClient) Search(filter string, attributes ...string) ([]Entry, error
 		}
 	}
 
-	// Search for the given username
 	searchRequest := ldap.NewSearchRequest(
-		c.conf.Base,
+		base,
 		ldap.ScopeWholeSubtree, ldap.NeverDerefAliases, 0, 0, false,
 		filter,
-		attr,
+		attributes,
 		nil,
 	)
 
@@ -270,23 +295,7 @@ func (c *LDAPClient) Search(filter string, attributes ...string) ([]Entry, error
 		}
 	}
 
-	if len(sr.Entries) < 1 {
-		return nil, errors.New(errUserNotFound)
-	}
-
-	entries := []Entry{}
-	for _, e := range sr.Entries {
-		entry := Entry{
-			DN:         e.DN,
-			Attributes: make(map[string]string),
-		}
-		for _, a := range attr {
-			entry.Attributes[a] = e.GetAttributeValue(a)
-		}
-		entries = append(entries, entry)
-	}
-
-	return entries, nil
+	return sr.Entries, nil
 }
 
 func (c *LDAPClient) searchAndInsertOrUpdateUser(db gorp.SqlExecutor, username string) (*sdk.User, error) {
@@ -0,0 +1,56 @@
+package aws
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// endpoint returns the url of an AWS service in a region, empty for the default endpoint of the AWS SDK.
+// It can be overridden in tests
+var endpoint = func(service, region string) string {
+	return ""
+}
+
+var httpClient = &http.Client{Timeout: 30 * time.Second}
+
+// Client calls the AWS APIs with static credentials
+type Client struct {
+	Region          string
+	AccessKeyID     string
+	SecretAccessKey string
+}
+
+// NewClient returns a client from the configuration of an AWS Secrets integration
+func NewClient(pi sdk.ProjectIntegration) (*Client, error) {
+	c := &Client{
+		Region:          pi.Config[sdk.AWSSecretsConfigRegion].Value,
+		AccessKeyID:     pi.Config[sdk.AWSSecretsConfigAccessKeyID].Value,
+		SecretAccessKey: pi.Config[sdk.AWSSecretsConfigSecretAccessKey].Value,
+	}
+	if c.AccessKeyID == "" || c.SecretAccessKey == "" {
+		return nil, sdk.NewErrorFrom(sdk.ErrWrongRequest, "missing aws credentials on integration %s", pi.Name)
+	}
+	return c, nil
+}
+
+// session returns a session of the AWS SDK to call a service in a region
+func (c *Client) session(service, region string) (*session.Session, error) {
+	cfg := aws.NewConfig().
+		WithRegion(region).
+		WithCredentials(credentials.NewStaticCredentials(c.AccessKeyID, c.SecretAccessKey, "")).
+		WithHTTPClient(httpClient)
+	if e := endpoint(service, region); e != "" {
+		cfg = cfg.WithEndpoint(e)
+	}
+	sess, err := session.NewSession(cfg)
+	if err != nil {
+		return nil, sdk.WrapError(err, "unable to create aws session")
+	}
+	return sess, nil
+}
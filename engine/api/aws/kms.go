@@ -0,0 +1,40 @@
+package aws
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/kms"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// KMSEncrypt encrypts a small payload (like a data key) with an AWS KMS key
+func (c *Client) KMSEncrypt(keyID string, plain []byte) ([]byte, error) {
+	if c.Region == "" {
+		return nil, sdk.NewErrorFrom(sdk.ErrWrongRequest, "missing aws region")
+	}
+	sess, err := c.session("kms", c.Region)
+	if err != nil {
+		return nil, err
+	}
+	out, err := kms.New(sess).Encrypt(&kms.EncryptInput{KeyId: aws.String(keyID), Plaintext: plain})
+	if err != nil {
+		return nil, sdk.WrapError(err, "unable to encrypt with kms key %s", keyID)
+	}
+	return out.CiphertextBlob, nil
+}
+
+// KMSDecrypt decrypts a payload encrypted by KMSEncrypt, the key is found by AWS from the ciphertext
+func (c *Client) KMSDecrypt(blob []byte) ([]byte, error) {
+	if c.Region == "" {
+		return nil, sdk.NewErrorFrom(sdk.ErrWrongRequest, "missing aws region")
+	}
+	sess, err := c.session("kms", c.Region)
+	if err != nil {
+		return nil, err
+	}
+	out, err := kms.New(sess).Decrypt(&kms.DecryptInput{CiphertextBlob: blob})
+	if err != nil {
+		return nil, sdk.WrapError(err, "unable to decrypt with kms")
+	}
+	return out.Plaintext, nil
+}
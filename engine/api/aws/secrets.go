@@ -0,0 +1,124 @@
+package aws
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/secretsmanager"
+	"github.com/aws/aws-sdk-go/service/ssm"
+
+	"github.com/ovh/cds/sdk"
+)
+
+const (
+	secretsManagerPrefix = "arn:aws:secretsmanager:"
+	ssmARNPrefix         = "arn:aws:ssm:"
+	ssmPrefix            = "ssm:"
+)
+
+// reference is a pointer to a secret stored in Secrets Manager or in SSM Parameter Store
+type reference struct {
+	service string
+	region  string
+	id      string
+	key     string
+}
+
+// IsReference checks if the value of a variable is a reference to an AWS secret:
+//   - arn:aws:secretsmanager:<region>:<account>:secret:<name>, optionally followed by #<json key>
+//   - arn:aws:ssm:<region>:<account>:parameter/<name>
+//   - ssm:/<name>, using the region of the integration
+func IsReference(value string) bool {
+	return strings.HasPrefix(value, secretsManagerPrefix) ||
+		strings.HasPrefix(value, ssmARNPrefix) ||
+		strings.HasPrefix(value, ssmPrefix)
+}
+
+func parseReference(value, defaultRegion string) (reference, error) {
+	var r reference
+	switch {
+	case strings.HasPrefix(value, ssmPrefix):
+		r.service = "ssm"
+		r.region = defaultRegion
+		r.id = strings.TrimPrefix(value, ssmPrefix)
+	case strings.HasPrefix(value, secretsManagerPrefix), strings.HasPrefix(value, ssmARNPrefix):
+		// arn:partition:service:region:account:resource
+		parts := strings.SplitN(value, ":", 6)
+		if len(parts) != 6 {
+			return r, fmt.Errorf("invalid arn %s", value)
+		}
+		r.service = parts[2]
+		r.region = parts[3]
+		if r.service == "ssm" {
+			if !strings.HasPrefix(parts[5], "parameter/") {
+				return r, fmt.Errorf("invalid ssm parameter arn %s", value)
+			}
+			r.id = "/" + strings.TrimPrefix(parts[5], "parameter/")
+		} else {
+			r.id = value
+			if i := strings.LastIndex(value, "#"); i > 0 {
+				r.id, r.key = value[:i], value[i+1:]
+			}
+		}
+	default:
+		return r, fmt.Errorf("invalid aws secret reference %s", value)
+	}
+	if r.region == "" {
+		return r, fmt.Errorf("missing region for aws secret reference %s", value)
+	}
+	if r.id == "" || r.id == "/" {
+		return r, fmt.Errorf("missing secret name in aws secret reference %s", value)
+	}
+	return r, nil
+}
+
+// Resolve returns the current value of the secret referenced by the value, the value is read on each call
+// so rotated secrets are automatically used by the next runs
+func (c *Client) Resolve(value string) (string, error) {
+	r, err := parseReference(value, c.Region)
+	if err != nil {
+		return "", sdk.NewErrorFrom(sdk.ErrWrongRequest, "%v", err)
+	}
+
+	switch r.service {
+	case "ssm":
+		sess, err := c.session("ssm", r.region)
+		if err != nil {
+			return "", err
+		}
+		out, err := ssm.New(sess).GetParameter(&ssm.GetParameterInput{
+			Name:           aws.String(r.id),
+			WithDecryption: aws.Bool(true),
+		})
+		if err != nil {
+			return "", sdk.WrapError(err, "unable to get ssm parameter %s", r.id)
+		}
+		return aws.StringValue(out.Parameter.Value), nil
+	default:
+		sess, err := c.session("secretsmanager", r.region)
+		if err != nil {
+			return "", err
+		}
+		out, err := secretsmanager.New(sess).GetSecretValue(&secretsmanager.GetSecretValueInput{
+			SecretId: aws.String(r.id),
+		})
+		if err != nil {
+			return "", sdk.WrapError(err, "unable to get secret %s", r.id)
+		}
+		secret := aws.StringValue(out.SecretString)
+		if r.key == "" {
+			return secret, nil
+		}
+		values := map[string]interface{}{}
+		if err := json.Unmarshal([]byte(secret), &values); err != nil {
+			return "", sdk.WrapError(err, "secret %s is not a json object", r.id)
+		}
+		v, has := values[r.key]
+		if !has {
+			return "", sdk.WithStack(fmt.Errorf("key %s not found in secret %s", r.key, r.id))
+		}
+		return fmt.Sprintf("%v", v), nil
+	}
+}
@@ -0,0 +1,69 @@
+package aws
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseReference(t *testing.T) {
+	r, err := parseReference("arn:aws:secretsmanager:eu-west-1:123456789012:secret:db-AbCdEf#password", "us-east-1")
+	assert.NoError(t, err)
+	assert.Equal(t, reference{service: "secretsmanager", region: "eu-west-1", id: "arn:aws:secretsmanager:eu-west-1:123456789012:secret:db-AbCdEf", key: "password"}, r)
+
+	r, err = parseReference("arn:aws:ssm:eu-west-3:123456789012:parameter/app/prod/token", "us-east-1")
+	assert.NoError(t, err)
+	assert.Equal(t, reference{service: "ssm", region: "eu-west-3", id: "/app/prod/token"}, r)
+
+	r, err = parseReference("ssm:/app/prod/token", "us-east-1")
+	assert.NoError(t, err)
+	assert.Equal(t, reference{service: "ssm", region: "us-east-1", id: "/app/prod/token"}, r)
+
+	_, err = parseReference("ssm:/app/prod/token", "")
+	assert.Error(t, err)
+
+	_, err = parseReference("arn:aws:ssm:eu-west-3:123456789012:document/foo", "")
+	assert.Error(t, err)
+
+	assert.False(t, IsReference("my-password"))
+	assert.True(t, IsReference("ssm:/foo"))
+}
+
+func TestResolve(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.True(t, strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=AKID/"))
+		var in map[string]interface{}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&in))
+		switch r.Header.Get("X-Amz-Target") {
+		case "AmazonSSM.GetParameter":
+			assert.Equal(t, "/app/token", in["Name"])
+			w.Write([]byte(`{"Parameter":{"Value":"ssm-value"}}`)) // nolint
+		case "secretsmanager.GetSecretValue":
+			w.Write([]byte(`{"SecretString":"{\"password\":\"sm-value\"}"}`)) // nolint
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer srv.Close()
+
+	oldEndpoint := endpoint
+	endpoint = func(service, region string) string { return srv.URL }
+	defer func() { endpoint = oldEndpoint }()
+
+	c := &Client{Region: "eu-west-1", AccessKeyID: "AKID", SecretAccessKey: "secret"}
+
+	v, err := c.Resolve("ssm:/app/token")
+	assert.NoError(t, err)
+	assert.Equal(t, "ssm-value", v)
+
+	v, err = c.Resolve("arn:aws:secretsmanager:eu-west-1:123456789012:secret:db#password")
+	assert.NoError(t, err)
+	assert.Equal(t, "sm-value", v)
+
+	_, err = c.Resolve("arn:aws:secretsmanager:eu-west-1:123456789012:secret:db#user")
+	assert.Error(t, err)
+}
@@ -0,0 +1,79 @@
+package badge
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"unicode/utf8"
+)
+
+// Colors of the badges
+const (
+	ColorGreen     = "#4c1"
+	ColorYellow    = "#dfb317"
+	ColorRed       = "#e05d44"
+	ColorBlue      = "#007ec6"
+	ColorLightGrey = "#9f9f9f"
+)
+
+// charWidth is the average width in pixels of a character of the badge font (Verdana 11px)
+const charWidth = 7
+
+var tmpl = template.Must(template.New("badge").Parse(`<svg xmlns="http://www.w3.org/2000/svg" width="{{.Width}}" height="20" role="img" aria-label="{{.Label}}: {{.Message}}">
+<title>{{.Label}}: {{.Message}}</title>
+<linearGradient id="s" x2="0" y2="100%"><stop offset="0" stop-color="#bbb" stop-opacity=".1"/><stop offset="1" stop-opacity=".1"/></linearGradient>
+<clipPath id="r"><rect width="{{.Width}}" height="20" rx="3" fill="#fff"/></clipPath>
+<g clip-path="url(#r)">
+<rect width="{{.LabelWidth}}" height="20" fill="#555"/>
+<rect x="{{.LabelWidth}}" width="{{.MessageWidth}}" height="20" fill="{{.Color}}"/>
+<rect width="{{.Width}}" height="20" fill="url(#s)"/>
+</g>
+<g fill="#fff" text-anchor="middle" font-family="Verdana,Geneva,DejaVu Sans,sans-serif" font-size="11">
+<text x="{{.LabelX}}" y="15" fill="#010101" fill-opacity=".3">{{.Label}}</text>
+<text x="{{.LabelX}}" y="14">{{.Label}}</text>
+<text x="{{.MessageX}}" y="15" fill="#010101" fill-opacity=".3">{{.Message}}</text>
+<text x="{{.MessageX}}" y="14">{{.Message}}</text>
+</g>
+</svg>
+`))
+
+// Render returns a flat SVG badge with a label on the left and a colored message on the right
+func Render(label, message, color string) ([]byte, error) {
+	labelWidth := textWidth(label)
+	messageWidth := textWidth(message)
+	data := struct {
+		Label, Message, Color           string
+		Width, LabelWidth, MessageWidth int
+		LabelX, MessageX                float64
+	}{
+		Label:        label,
+		Message:      message,
+		Color:        color,
+		Width:        labelWidth + messageWidth,
+		LabelWidth:   labelWidth,
+		MessageWidth: messageWidth,
+		LabelX:       float64(labelWidth) / 2,
+		MessageX:     float64(labelWidth) + float64(messageWidth)/2,
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return nil, fmt.Errorf("unable to render badge: %v", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func textWidth(s string) int {
+	return utf8.RuneCountInString(s)*charWidth + 10
+}
+
+// CoverageColor returns the color of a coverage badge for a percentage of covered lines
+func CoverageColor(percent float64) string {
+	switch {
+	case percent >= 80:
+		return ColorGreen
+	case percent >= 60:
+		return ColorYellow
+	}
+	return ColorRed
+}
@@ -0,0 +1,28 @@
+package badge
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRender(t *testing.T) {
+	svg, err := Render("build", "success", ColorGreen)
+	assert.NoError(t, err)
+	assert.NoError(t, xml.Unmarshal(svg, new(interface{})))
+	assert.True(t, strings.Contains(string(svg), `width="104"`))
+	assert.True(t, strings.Contains(string(svg), `<title>build: success</title>`))
+	assert.True(t, strings.Contains(string(svg), `fill="#4c1"`))
+
+	svg, err = Render("version", "<v1.2.0>", ColorBlue)
+	assert.NoError(t, err)
+	assert.True(t, strings.Contains(string(svg), "&lt;v1.2.0&gt;"))
+}
+
+func TestCoverageColor(t *testing.T) {
+	assert.Equal(t, ColorGreen, CoverageColor(80))
+	assert.Equal(t, ColorYellow, CoverageColor(65.5))
+	assert.Equal(t, ColorRed, CoverageColor(12))
+}
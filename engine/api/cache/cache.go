@@ -3,6 +3,7 @@ package cache
 import (
 	"bytes"
 	"context"
+	"fmt"
 	"io"
 	"strings"
 	"time"
@@ -45,11 +46,49 @@ type Store interface {
 	Unlock(key string)
 }
 
+// Cache backends
+const (
+	BackendRedis     = "redis"
+	BackendMemory    = "memory"
+	BackendMemcached = "memcached"
+)
+
+// Config is the configuration of a cache store
+type Config struct {
+	Backend          string
+	TTL              int
+	RedisHost        string
+	RedisPassword    string
+	MaxEntries       int
+	MemcachedServers string
+}
+
 //New init a cache
 func New(redisHost, redisPassword string, TTL int) (Store, error) {
 	return NewRedisStore(redisHost, redisPassword, TTL)
 }
 
+// NewStore init a cache with the configured backend:
+//   - redis (default) stores everything in redis
+//   - memory stores everything in the process, it can only be used by a single instance
+//   - memcached stores the key/values in memcached, and the queues, pub/sub, sets and locks in redis
+func NewStore(cfg Config) (Store, error) {
+	switch cfg.Backend {
+	case "", BackendRedis:
+		return NewRedisStore(cfg.RedisHost, cfg.RedisPassword, cfg.TTL)
+	case BackendMemory:
+		return NewLocalStore(cfg.TTL, cfg.MaxEntries), nil
+	case BackendMemcached:
+		redisStore, err := NewRedisStore(cfg.RedisHost, cfg.RedisPassword, cfg.TTL)
+		if err != nil {
+			return nil, err
+		}
+		return NewMemcachedStore(cfg.MemcachedServers, redisStore)
+	default:
+		return nil, fmt.Errorf("unknown cache backend %s", cfg.Backend)
+	}
+}
+
 //NewWriteCloser returns a write closer
 func NewWriteCloser(store Store, key string, ttl int) io.WriteCloser {
 	return &writerCloser{
@@ -0,0 +1,422 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// LocalStore is an in-memory store for the installations with a single API instance: the key/values are
+// kept in a LRU, and the pub/sub is made with channels. It cannot be shared between several instances.
+type LocalStore struct {
+	ttl        int
+	maxEntries int
+
+	mutex       sync.Mutex
+	entries     map[string]*list.Element
+	lru         *list.List
+	queues      map[string][]string
+	sets        map[string]map[string]int64
+	subscribers map[string][]*localPubSub
+}
+
+type localEntry struct {
+	key    string
+	value  []byte
+	expire time.Time
+	// pinned entries are the members of the sets and the locks, they are never evicted
+	pinned bool
+}
+
+type localPubSub struct {
+	store    *LocalStore
+	channels []string
+	messages chan string
+}
+
+// Unsubscribe stops the subscription to the channels
+func (p *localPubSub) Unsubscribe(channels ...string) error {
+	p.store.mutex.Lock()
+	defer p.store.mutex.Unlock()
+	if len(channels) == 0 {
+		channels = p.channels
+	}
+	for _, c := range channels {
+		subs := p.store.subscribers[c]
+		for i := range subs {
+			if subs[i] == p {
+				p.store.subscribers[c] = append(subs[:i], subs[i+1:]...)
+				break
+			}
+		}
+	}
+	return nil
+}
+
+// NewLocalStore initiates a new in-memory store, keeping at most maxEntries key/values (0 for no limit)
+func NewLocalStore(ttl, maxEntries int) *LocalStore {
+	return &LocalStore{
+		ttl:         ttl,
+		maxEntries:  maxEntries,
+		entries:     make(map[string]*list.Element),
+		lru:         list.New(),
+		queues:      make(map[string][]string),
+		sets:        make(map[string]map[string]int64),
+		subscribers: make(map[string][]*localPubSub),
+	}
+}
+
+// get returns the entry of the key, it has to be called with the lock
+func (s *LocalStore) get(key string) *localEntry {
+	elem, ok := s.entries[key]
+	if !ok {
+		return nil
+	}
+	e := elem.Value.(*localEntry)
+	if !e.expire.IsZero() && time.Now().After(e.expire) {
+		s.remove(elem)
+		return nil
+	}
+	s.lru.MoveToFront(elem)
+	return e
+}
+
+// set stores the value of the key, it has to be called with the lock
+func (s *LocalStore) set(key string, value []byte, ttl int, pinned bool) {
+	var expire time.Time
+	if ttl > 0 {
+		expire = time.Now().Add(time.Duration(ttl) * time.Second)
+	}
+	if elem, ok := s.entries[key]; ok {
+		e := elem.Value.(*localEntry)
+		e.value, e.expire, e.pinned = value, expire, pinned
+		s.lru.MoveToFront(elem)
+		return
+	}
+	s.entries[key] = s.lru.PushFront(&localEntry{key: key, value: value, expire: expire, pinned: pinned})
+
+	// Evict the least recently used entries
+	for elem := s.lru.Back(); elem != nil && s.maxEntries > 0 && len(s.entries) > s.maxEntries; {
+		prev := elem.Prev()
+		if !elem.Value.(*localEntry).pinned {
+			s.remove(elem)
+		}
+		elem = prev
+	}
+}
+
+func (s *LocalStore) remove(elem *list.Element) {
+	s.lru.Remove(elem)
+	delete(s.entries, elem.Value.(*localEntry).key)
+}
+
+//Get a key from the local store
+func (s *LocalStore) Get(key string, value interface{}) bool {
+	s.mutex.Lock()
+	e := s.get(key)
+	s.mutex.Unlock()
+	if e == nil {
+		return false
+	}
+	if err := json.Unmarshal(e.value, value); err != nil {
+		log.Warning("local> cannot unmarshal %s :%s", key, err)
+		return false
+	}
+	return true
+}
+
+//SetWithTTL a value in local store (0 for eternity)
+func (s *LocalStore) SetWithTTL(key string, value interface{}, ttl int) {
+	b, err := json.Marshal(value)
+	if err != nil {
+		log.Warning("local> error caching %s: %s", key, err)
+		return
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.set(key, b, ttl, false)
+}
+
+//Set a value in local store
+func (s *LocalStore) Set(key string, value interface{}) {
+	s.SetWithTTL(key, value, s.ttl)
+}
+
+//UpdateTTL update the ttl linked to the key, the key is deleted for a ttl lower or equal to 0
+func (s *LocalStore) UpdateTTL(key string, ttl int) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	elem, ok := s.entries[key]
+	if !ok {
+		return
+	}
+	if ttl <= 0 {
+		s.remove(elem)
+		return
+	}
+	elem.Value.(*localEntry).expire = time.Now().Add(time.Duration(ttl) * time.Second)
+}
+
+//Delete a key in local store
+func (s *LocalStore) Delete(key string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if elem, ok := s.entries[key]; ok {
+		s.remove(elem)
+	}
+}
+
+//DeleteAll delete all matching keys in local store
+func (s *LocalStore) DeleteAll(pattern string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for k, elem := range s.entries {
+		if matchPattern(pattern, k) {
+			s.remove(elem)
+		}
+	}
+}
+
+// matchPattern matches the key with a glob-style pattern, with the * and ? wildcards of redis KEYS
+func matchPattern(pattern, key string) bool {
+	for len(pattern) > 0 {
+		switch pattern[0] {
+		case '*':
+			for i := len(key); i >= 0; i-- {
+				if matchPattern(pattern[1:], key[i:]) {
+					return true
+				}
+			}
+			return false
+		case '?':
+			if len(key) == 0 {
+				return false
+			}
+		default:
+			if len(key) == 0 || key[0] != pattern[0] {
+				return false
+			}
+		}
+		pattern, key = pattern[1:], key[1:]
+	}
+	return len(key) == 0
+}
+
+//Enqueue pushes to queue
+func (s *LocalStore) Enqueue(queueName string, value interface{}) {
+	b, err := json.Marshal(value)
+	if err != nil {
+		log.Warning("local> Error queueing %s:%s", queueName, err)
+		return
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.queues[queueName] = append(s.queues[queueName], string(b))
+}
+
+// dequeue pops the oldest element of the queue
+func (s *LocalStore) dequeue(queueName string) (string, bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	q := s.queues[queueName]
+	if len(q) == 0 {
+		return "", false
+	}
+	s.queues[queueName] = q[1:]
+	return q[0], true
+}
+
+//Dequeue gets from queue This is blocking while there is nothing in the queue
+func (s *LocalStore) Dequeue(queueName string, value interface{}) {
+	s.DequeueWithContext(context.Background(), queueName, value)
+}
+
+//DequeueWithContext gets from queue This is blocking while there is nothing in the queue, it can be cancelled with a context.Context
+func (s *LocalStore) DequeueWithContext(c context.Context, queueName string, value interface{}) {
+	elem, ok := s.dequeue(queueName)
+	ticker := time.NewTicker(250 * time.Millisecond)
+	defer ticker.Stop()
+	for !ok {
+		select {
+		case <-ticker.C:
+			elem, ok = s.dequeue(queueName)
+		case <-c.Done():
+			return
+		}
+	}
+	if err := json.Unmarshal([]byte(elem), value); err != nil {
+		log.Error("local.DequeueWithContext> error on unmarshal value on queue:%s err:%v", queueName, err)
+	}
+}
+
+//QueueLen returns the length of a queue
+func (s *LocalStore) QueueLen(queueName string) int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return len(s.queues[queueName])
+}
+
+// RemoveFromQueue removes a member from a list
+func (s *LocalStore) RemoveFromQueue(queueName string, memberKey string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	q := s.queues[queueName][:0]
+	for _, e := range s.queues[queueName] {
+		if e != memberKey {
+			q = append(q, e)
+		}
+	}
+	s.queues[queueName] = q
+}
+
+// Publish a msg in a channel
+func (s *LocalStore) Publish(channel string, value interface{}) {
+	msg, err := json.Marshal(value)
+	if err != nil {
+		log.Warning("local.Publish> Marshall error, cannot push in channel %s: %v, %s", channel, value, err)
+		return
+	}
+	iUnquoted, err := strconv.Unquote(string(msg))
+	if err != nil {
+		log.Warning("local.Publish> Unquote error, cannot push in channel %s: %v, %s", channel, string(msg), err)
+		return
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for _, sub := range s.subscribers[channel] {
+		select {
+		case sub.messages <- iUnquoted:
+		default:
+			log.Warning("local.Publish> subscriber of channel %s is full, message dropped", channel)
+		}
+	}
+}
+
+// Subscribe to a channel
+func (s *LocalStore) Subscribe(channel string) PubSub {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	sub := &localPubSub{store: s, channels: []string{channel}, messages: make(chan string, 1000)}
+	s.subscribers[channel] = append(s.subscribers[channel], sub)
+	return sub
+}
+
+// GetMessageFromSubscription from a local PubSub
+func (s *LocalStore) GetMessageFromSubscription(c context.Context, pb PubSub) (string, error) {
+	sub, ok := pb.(*localPubSub)
+	if !ok {
+		return "", fmt.Errorf("local.GetMessage> PubSub is not a local PubSub. Got %T", pb)
+	}
+	select {
+	case msg := <-sub.messages:
+		return msg, nil
+	case <-c.Done():
+		return "", nil
+	}
+}
+
+// Status returns the status of the local cache
+func (s *LocalStore) Status() sdk.MonitoringStatusLine {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return sdk.MonitoringStatusLine{Component: "Cache Ping", Value: fmt.Sprintf("OK (memory, %d entries)", len(s.entries)), Status: sdk.MonitoringStatusOK}
+}
+
+// SetAdd add a member (identified by a key) in the cached set
+func (s *LocalStore) SetAdd(rootKey string, memberKey string, member interface{}) {
+	b, err := json.Marshal(member)
+	if err != nil {
+		log.Warning("local> error caching %s: %s", Key(rootKey, memberKey), err)
+		return
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.sets[rootKey] == nil {
+		s.sets[rootKey] = make(map[string]int64)
+	}
+	s.sets[rootKey][memberKey] = time.Now().UnixNano()
+	s.set(Key(rootKey, memberKey), b, 0, true)
+}
+
+// SetRemove removes a member from a set
+func (s *LocalStore) SetRemove(rootKey string, memberKey string, member interface{}) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	delete(s.sets[rootKey], memberKey)
+	if elem, ok := s.entries[Key(rootKey, memberKey)]; ok {
+		s.remove(elem)
+	}
+}
+
+// SetCard returns the cardinality of a set
+func (s *LocalStore) SetCard(key string) int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return len(s.sets[key])
+}
+
+// SetScan scans a set, the members are given in the order they were added
+func (s *LocalStore) SetScan(key string, members ...interface{}) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	values := make([]string, 0, len(s.sets[key]))
+	for v := range s.sets[key] {
+		values = append(values, v)
+	}
+	sort.Slice(values, func(i, j int) bool { return s.sets[key][values[i]] < s.sets[key][values[j]] })
+
+	for i := range members {
+		if i >= len(values) {
+			break
+		}
+		e := s.get(Key(key, values[i]))
+		if e == nil {
+			delete(s.sets[key], values[i])
+			return fmt.Errorf("SetScan member %s not found", Key(key, values[i]))
+		}
+		if err := json.Unmarshal(e.value, members[i]); err != nil {
+			log.Warning("local> cannot unmarshal %s :%s", Key(key, values[i]), err)
+			return err
+		}
+	}
+	return nil
+}
+
+// Lock sets the key if it does not exist, it returns false if the key is already locked
+func (s *LocalStore) Lock(key string, expiration time.Duration, retrywdMillisecond int, retryCount int) bool {
+	if retrywdMillisecond == -1 {
+		retrywdMillisecond = retryWait
+	}
+	if retryCount == -1 {
+		retryCount = 3
+	}
+	for i := 0; i < retryCount; i++ {
+		s.mutex.Lock()
+		if s.get(key) == nil {
+			s.set(key, []byte(`"true"`), 0, true)
+			if expiration > 0 {
+				s.entries[key].Value.(*localEntry).expire = time.Now().Add(expiration)
+			}
+			s.mutex.Unlock()
+			return true
+		}
+		s.mutex.Unlock()
+		time.Sleep(time.Duration(retrywdMillisecond) * time.Millisecond)
+	}
+	return false
+}
+
+// Unlock deletes the key of the lock
+func (s *LocalStore) Unlock(key string) {
+	s.Delete(key)
+}
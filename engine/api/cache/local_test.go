@@ -0,0 +1,102 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocalStoreGetSet(t *testing.T) {
+	s := NewLocalStore(60, 2)
+
+	s.Set("key:1", "one")
+	s.Set("key:2", "two")
+	var v string
+	assert.True(t, s.Get("key:1", &v))
+	assert.Equal(t, "one", v)
+
+	// key:2 is the least recently used
+	s.Set("key:3", "three")
+	assert.False(t, s.Get("key:2", &v))
+	assert.True(t, s.Get("key:1", &v))
+	assert.True(t, s.Get("key:3", &v))
+
+	s.SetWithTTL("key:4", "four", 1)
+	time.Sleep(1100 * time.Millisecond)
+	assert.False(t, s.Get("key:4", &v))
+
+	s.Delete("key:1")
+	assert.False(t, s.Get("key:1", &v))
+}
+
+func TestLocalStoreDeleteAll(t *testing.T) {
+	s := NewLocalStore(60, 0)
+	s.Set("project:1:application:1", 1)
+	s.Set("project:1:application:2", 2)
+	s.Set("project:2:application:1", 3)
+
+	s.DeleteAll("project:1:*")
+	var v int
+	assert.False(t, s.Get("project:1:application:1", &v))
+	assert.False(t, s.Get("project:1:application:2", &v))
+	assert.True(t, s.Get("project:2:application:1", &v))
+
+	assert.True(t, matchPattern("a?c*", "abcdef"))
+	assert.False(t, matchPattern("a?c", "abbc"))
+}
+
+func TestLocalStoreQueue(t *testing.T) {
+	s := NewLocalStore(60, 0)
+	s.Enqueue("queue", "a")
+	s.Enqueue("queue", "b")
+	assert.Equal(t, 2, s.QueueLen("queue"))
+
+	var v string
+	s.Dequeue("queue", &v)
+	assert.Equal(t, "a", v)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	s.DequeueWithContext(ctx, "queue", &v)
+	assert.Equal(t, "b", v)
+	assert.Equal(t, 0, s.QueueLen("queue"))
+}
+
+func TestLocalStorePubSub(t *testing.T) {
+	s := NewLocalStore(60, 0)
+	sub := s.Subscribe("events")
+	s.Publish("events", "hello")
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	msg, err := s.GetMessageFromSubscription(ctx, sub)
+	assert.NoError(t, err)
+	assert.Equal(t, "hello", msg)
+
+	assert.NoError(t, sub.Unsubscribe("events"))
+	assert.Empty(t, s.subscribers["events"])
+}
+
+func TestLocalStoreSetAndLock(t *testing.T) {
+	s := NewLocalStore(60, 1)
+	s.SetAdd("tasks", "1", "task 1")
+	s.SetAdd("tasks", "2", "task 2")
+	// The members of the sets are not evicted
+	s.Set("other", "value")
+	assert.Equal(t, 2, s.SetCard("tasks"))
+
+	var t1, t2 string
+	assert.NoError(t, s.SetScan("tasks", &t1, &t2))
+	assert.Equal(t, "task 1", t1)
+	assert.Equal(t, "task 2", t2)
+
+	s.SetRemove("tasks", "1", "task 1")
+	assert.Equal(t, 1, s.SetCard("tasks"))
+
+	assert.True(t, s.Lock("lock", time.Minute, 10, 1))
+	assert.False(t, s.Lock("lock", time.Minute, 10, 1))
+	s.Unlock("lock")
+	assert.True(t, s.Lock("lock", time.Minute, 10, 1))
+}
@@ -0,0 +1,329 @@
+package cache
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"math"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// memcachedKeysIndex is the redis sorted set of the keys stored in memcached, scored by their expiration,
+// memcached cannot list its keys to delete them by pattern
+const memcachedKeysIndex = "cache:memcached:keys"
+
+// memcachedMaxRelativeTTL is the max expiration time given in seconds, memcached reads a greater one as a timestamp
+const memcachedMaxRelativeTTL = 30 * 24 * 3600
+
+// MemcachedStore keeps the key/values in memcached, and the queues, pub/sub, sets and locks in redis
+type MemcachedStore struct {
+	*RedisStore
+	servers []*memcachedServer
+}
+
+type memcachedServer struct {
+	addr  string
+	conns chan *memcachedConn
+}
+
+type memcachedConn struct {
+	net.Conn
+	rw *bufio.ReadWriter
+}
+
+// NewMemcachedStore initiates a new store on the comma separated memcached servers and the redis store
+func NewMemcachedStore(servers string, redisStore *RedisStore) (*MemcachedStore, error) {
+	s := &MemcachedStore{RedisStore: redisStore}
+	for _, addr := range strings.Split(servers, ",") {
+		if addr = strings.TrimSpace(addr); addr != "" {
+			s.servers = append(s.servers, &memcachedServer{addr: addr, conns: make(chan *memcachedConn, 25)})
+		}
+	}
+	if len(s.servers) == 0 {
+		return nil, fmt.Errorf("no memcached server")
+	}
+	for _, srv := range s.servers {
+		if _, err := srv.version(); err != nil {
+			return nil, fmt.Errorf("cannot reach memcached on %s: %v", srv.addr, err)
+		}
+	}
+	return s, nil
+}
+
+// memcachedKey returns a valid memcached key: at most 250 characters, without space nor control character
+func memcachedKey(key string) string {
+	if len(key) <= 250 && strings.IndexFunc(key, func(r rune) bool { return r <= ' ' || r == 0x7f }) == -1 {
+		return key
+	}
+	h := sha1.Sum([]byte(key))
+	return "sha1:" + hex.EncodeToString(h[:])
+}
+
+func (s *MemcachedStore) server(key string) *memcachedServer {
+	return s.servers[crc32.ChecksumIEEE([]byte(key))%uint32(len(s.servers))]
+}
+
+func (srv *memcachedServer) do(f func(c *memcachedConn) error) error {
+	var c *memcachedConn
+	select {
+	case c = <-srv.conns:
+	default:
+		conn, err := net.DialTimeout("tcp", srv.addr, 5*time.Second)
+		if err != nil {
+			return err
+		}
+		c = &memcachedConn{Conn: conn, rw: bufio.NewReadWriter(bufio.NewReader(conn), bufio.NewWriter(conn))}
+	}
+
+	if err := c.SetDeadline(time.Now().Add(5 * time.Second)); err != nil {
+		c.Close() // nolint
+		return err
+	}
+	if err := f(c); err != nil {
+		// The state of the connection is unknown
+		c.Close() // nolint
+		return err
+	}
+
+	select {
+	case srv.conns <- c:
+	default:
+		c.Close() // nolint
+	}
+	return nil
+}
+
+// command sends the command and returns the first line of the response
+func (c *memcachedConn) command(cmd string, data []byte) (string, error) {
+	if _, err := c.rw.WriteString(cmd + "\r\n"); err != nil {
+		return "", err
+	}
+	if data != nil {
+		if _, err := c.rw.Write(append(data, '\r', '\n')); err != nil {
+			return "", err
+		}
+	}
+	if err := c.rw.Flush(); err != nil {
+		return "", err
+	}
+	line, err := c.rw.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	line = strings.TrimSuffix(line, "\r\n")
+	if line == "ERROR" || strings.HasPrefix(line, "CLIENT_ERROR") || strings.HasPrefix(line, "SERVER_ERROR") {
+		return "", fmt.Errorf("memcached %s: %s", strings.Fields(cmd)[0], line)
+	}
+	return line, nil
+}
+
+func (srv *memcachedServer) get(key string) ([]byte, error) {
+	var value []byte
+	err := srv.do(func(c *memcachedConn) error {
+		line, err := c.command("get "+key, nil)
+		if err != nil {
+			return err
+		}
+		if line == "END" {
+			return nil
+		}
+		// VALUE <key> <flags> <bytes>
+		fields := strings.Fields(line)
+		if len(fields) != 4 || fields[0] != "VALUE" {
+			return fmt.Errorf("memcached get: unexpected response %q", line)
+		}
+		size, err := strconv.Atoi(fields[3])
+		if err != nil {
+			return fmt.Errorf("memcached get: unexpected response %q", line)
+		}
+		value = make([]byte, size+2)
+		if _, err := io.ReadFull(c.rw, value); err != nil {
+			return err
+		}
+		value = value[:size]
+		end, err := c.rw.ReadString('\n')
+		if err != nil {
+			return err
+		}
+		if end != "END\r\n" {
+			return fmt.Errorf("memcached get: unexpected response %q", end)
+		}
+		return nil
+	})
+	return value, err
+}
+
+func (srv *memcachedServer) set(key string, value []byte, exptime int64) error {
+	return srv.do(func(c *memcachedConn) error {
+		line, err := c.command(fmt.Sprintf("set %s 0 %d %d", key, exptime, len(value)), value)
+		if err != nil {
+			return err
+		}
+		if line != "STORED" {
+			return fmt.Errorf("memcached set: %s", line)
+		}
+		return nil
+	})
+}
+
+// touch updates the expiration of the key, it returns false if the key does not exist
+func (srv *memcachedServer) touch(key string, exptime int64) (bool, error) {
+	var found bool
+	err := srv.do(func(c *memcachedConn) error {
+		line, err := c.command(fmt.Sprintf("touch %s %d", key, exptime), nil)
+		found = line == "TOUCHED"
+		return err
+	})
+	return found, err
+}
+
+func (srv *memcachedServer) delete(key string) error {
+	return srv.do(func(c *memcachedConn) error {
+		_, err := c.command("delete "+key, nil)
+		return err
+	})
+}
+
+func (srv *memcachedServer) version() (string, error) {
+	var version string
+	err := srv.do(func(c *memcachedConn) error {
+		line, err := c.command("version", nil)
+		version = strings.TrimPrefix(line, "VERSION ")
+		return err
+	})
+	return version, err
+}
+
+// expiration returns the memcached expiration time and the score of the key in the index
+func expiration(ttl int, now time.Time) (int64, float64) {
+	switch {
+	case ttl <= 0:
+		return 0, math.Inf(1)
+	case ttl > memcachedMaxRelativeTTL:
+		t := now.Add(time.Duration(ttl) * time.Second).Unix()
+		return t, float64(t)
+	default:
+		return int64(ttl), float64(now.Add(time.Duration(ttl) * time.Second).Unix())
+	}
+}
+
+//Get a key from memcached
+func (s *MemcachedStore) Get(key string, value interface{}) bool {
+	k := memcachedKey(key)
+	b, err := s.server(k).get(k)
+	if err != nil {
+		log.Error("memcached> get error %s : %v", key, err)
+		return false
+	}
+	if b == nil {
+		return false
+	}
+	if err := json.Unmarshal(b, value); err != nil {
+		log.Warning("memcached> cannot unmarshal %s :%s", key, err)
+		return false
+	}
+	return true
+}
+
+//SetWithTTL a value in memcached (0 for eternity)
+func (s *MemcachedStore) SetWithTTL(key string, value interface{}, ttl int) {
+	b, err := json.Marshal(value)
+	if err != nil {
+		log.Warning("memcached> error caching %s: %s", key, err)
+		return
+	}
+	exptime, score := expiration(ttl, time.Now())
+	k := memcachedKey(key)
+	if err := s.server(k).set(k, b, exptime); err != nil {
+		log.Error("memcached> set error %s: %v", key, err)
+		return
+	}
+	if err := s.Client.ZAdd(memcachedKeysIndex, redis.Z{Member: key, Score: score}).Err(); err != nil {
+		log.Error("memcached> cannot index %s: %v", key, err)
+	}
+}
+
+//Set a value in memcached
+func (s *MemcachedStore) Set(key string, value interface{}) {
+	s.SetWithTTL(key, value, s.ttl)
+}
+
+//UpdateTTL update the ttl linked to the key
+func (s *MemcachedStore) UpdateTTL(key string, ttl int) {
+	if ttl <= 0 {
+		s.Delete(key)
+		return
+	}
+	exptime, score := expiration(ttl, time.Now())
+	k := memcachedKey(key)
+	found, err := s.server(k).touch(k, exptime)
+	if err != nil {
+		log.Error("memcached> update ttl error %s: %v", key, err)
+		return
+	}
+	if found {
+		s.Client.ZAdd(memcachedKeysIndex, redis.Z{Member: key, Score: score})
+	}
+}
+
+//Delete a key in memcached
+func (s *MemcachedStore) Delete(key string) {
+	k := memcachedKey(key)
+	if err := s.server(k).delete(k); err != nil {
+		log.Error("memcached> error deleting %s : %s", key, err)
+	}
+	s.Client.ZRem(memcachedKeysIndex, key)
+}
+
+//DeleteAll delete all matching keys in memcached
+func (s *MemcachedStore) DeleteAll(pattern string) {
+	// Forget the expired keys
+	s.Client.ZRemRangeByScore(memcachedKeysIndex, "-inf", fmt.Sprintf("(%d", time.Now().Unix()))
+
+	keys, err := s.Client.ZRange(memcachedKeysIndex, 0, -1).Result()
+	if err != nil {
+		log.Warning("memcached> Error deleting %s : %s", pattern, err)
+		return
+	}
+	for _, key := range keys {
+		if matchPattern(pattern, key) {
+			s.Delete(key)
+		}
+	}
+}
+
+// Status returns the status of memcached and redis
+func (s *MemcachedStore) Status() sdk.MonitoringStatusLine {
+	line := s.RedisStore.Status()
+	if line.Status == sdk.MonitoringStatusAlert {
+		return line
+	}
+
+	var down []string
+	for _, srv := range s.servers {
+		if _, err := srv.version(); err != nil {
+			down = append(down, srv.addr)
+		}
+	}
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "%s, memcached %d/%d servers", line.Value, len(s.servers)-len(down), len(s.servers))
+	if len(down) > 0 {
+		fmt.Fprintf(&buf, " (%s down)", strings.Join(down, ", "))
+		line.Status = sdk.MonitoringStatusWarn
+	}
+	line.Value = buf.String()
+	return line
+}
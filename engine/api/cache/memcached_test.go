@@ -0,0 +1,38 @@
+package cache
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemcachedKey(t *testing.T) {
+	assert.Equal(t, "project:key", memcachedKey("project:key"))
+
+	k := memcachedKey("project:my key")
+	assert.True(t, strings.HasPrefix(k, "sha1:"))
+	assert.Equal(t, k, memcachedKey("project:my key"))
+
+	long := strings.Repeat("a", 251)
+	assert.Len(t, memcachedKey(long), len("sha1:")+40)
+}
+
+func TestMemcachedExpiration(t *testing.T) {
+	now := time.Unix(1000000, 0)
+
+	exptime, score := expiration(0, now)
+	assert.Equal(t, int64(0), exptime)
+	assert.True(t, math.IsInf(score, 1))
+
+	exptime, score = expiration(60, now)
+	assert.Equal(t, int64(60), exptime)
+	assert.Equal(t, float64(1000060), score)
+
+	// Beyond 30 days memcached expects a timestamp
+	exptime, score = expiration(memcachedMaxRelativeTTL+1, now)
+	assert.Equal(t, int64(1000000+memcachedMaxRelativeTTL+1), exptime)
+	assert.Equal(t, float64(exptime), score)
+}
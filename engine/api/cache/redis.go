@@ -9,6 +9,7 @@ import (
 	stdlog "log"
 	"strconv"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/go-redis/redis"
@@ -17,38 +18,54 @@ import (
 	"github.com/ovh/cds/sdk/log"
 )
 
+// Redis deployment modes
+const (
+	RedisModeSingle   = "single"
+	RedisModeSentinel = "sentinel"
+	RedisModeCluster  = "cluster"
+)
+
 //RedisStore a redis client and a default ttl
 type RedisStore struct {
 	ttl    int
-	Client *redis.Client
+	mode   string
+	Client redis.UniversalClient
 }
 
-//NewRedisStore initiate a new redisStore
-func NewRedisStore(host, password string, ttl int) (*RedisStore, error) {
-	var client *redis.Client
-
-	//if host is line master@localhost:26379,localhost:26380 => it's a redis sentinel cluster
-	if strings.Contains(host, "@") && strings.Contains(host, ",") {
-		masterName := strings.Split(host, "@")[0]
-		sentinelsStr := strings.Split(host, "@")[1]
-		sentinels := strings.Split(sentinelsStr, ",")
-		opts := &redis.FailoverOptions{
-			MasterName:         masterName,
-			SentinelAddrs:      sentinels,
-			Password:           password,
-			IdleCheckFrequency: 10 * time.Second,
-			IdleTimeout:        10 * time.Second,
-			PoolSize:           25,
+// redisOptions returns the options of the redis client and the deployment mode for the given host:
+//   - master@localhost:26379,localhost:26380 is a redis sentinel cluster
+//   - localhost:7000,localhost:7001,localhost:7002 are the seed nodes of a redis cluster
+//   - localhost:6379 is a single redis server
+func redisOptions(host, password string) (*redis.UniversalOptions, string) {
+	opts := &redis.UniversalOptions{
+		Password:           password,
+		IdleCheckFrequency: 30 * time.Second,
+	}
+
+	if strings.Contains(host, "@") {
+		opts.MasterName = strings.Split(host, "@")[0]
+		opts.Addrs = strings.Split(strings.Split(host, "@")[1], ",")
+		opts.IdleCheckFrequency = 10 * time.Second
+		opts.IdleTimeout = 10 * time.Second
+		opts.PoolSize = 25
+		return opts, RedisModeSentinel
+	}
+
+	for _, addr := range strings.Split(host, ",") {
+		if addr = strings.TrimSpace(addr); addr != "" {
+			opts.Addrs = append(opts.Addrs, addr)
 		}
-		client = redis.NewFailoverClient(opts)
-	} else {
-		client = redis.NewClient(&redis.Options{
-			Addr:               host,
-			Password:           password, // no password set
-			DB:                 0,        // use default DB
-			IdleCheckFrequency: 30 * time.Second,
-		})
 	}
+	if len(opts.Addrs) > 1 {
+		return opts, RedisModeCluster
+	}
+	return opts, RedisModeSingle
+}
+
+//NewRedisStore initiate a new redisStore
+func NewRedisStore(host, password string, ttl int) (*RedisStore, error) {
+	opts, mode := redisOptions(host, password)
+	client := redis.NewUniversalClient(opts)
 
 	redis.SetLogger(stdlog.New(ioutil.Discard, "", stdlog.LstdFlags|stdlog.Lshortfile))
 
@@ -61,6 +78,7 @@ func NewRedisStore(host, password string, ttl int) (*RedisStore, error) {
 	}
 	return &RedisStore{
 		ttl:    ttl,
+		mode:   mode,
 		Client: client,
 	}, nil
 }
@@ -172,17 +190,35 @@ func (s *RedisStore) DeleteAll(pattern string) {
 		log.Error("redis> cannot get redis client")
 		return
 	}
-	keys, err := s.Client.Keys(pattern).Result()
-	if err != nil {
-		log.Warning("redis> Error deleting %s : %s", pattern, err)
+	// The keys of a redis cluster are spread on its masters
+	if c, ok := s.Client.(*redis.ClusterClient); ok {
+		if err := c.ForEachMaster(func(client *redis.Client) error {
+			return deleteKeys(client, pattern)
+		}); err != nil {
+			log.Warning("redis> Error deleting %s : %s", pattern, err)
+		}
 		return
 	}
+	if err := deleteKeys(s.Client, pattern); err != nil {
+		log.Warning("redis> Error deleting %s : %s", pattern, err)
+	}
+}
+
+// deleteKeys deletes the matching keys one by one, the keys of a redis cluster can be in different slots
+func deleteKeys(client redis.Cmdable, pattern string) error {
+	keys, err := client.Keys(pattern).Result()
+	if err != nil {
+		return err
+	}
 	if len(keys) == 0 {
-		return
+		return nil
 	}
-	if err := s.Client.Del(keys...).Err(); err != nil {
-		log.Warning("redis> Error deleting %s : %s", pattern, err)
+	pipe := client.Pipeline()
+	for _, k := range keys {
+		pipe.Del(k)
 	}
+	_, err = pipe.Exec()
+	return err
 }
 
 //Enqueue pushes to queue
@@ -363,10 +399,38 @@ func (s *RedisStore) GetMessageFromSubscription(c context.Context, pb PubSub) (s
 
 // Status returns the status of the local cache
 func (s *RedisStore) Status() sdk.MonitoringStatusLine {
-	if s.Client.Ping().Err() == nil {
-		return sdk.MonitoringStatusLine{Component: "Cache Ping", Value: "OK", Status: sdk.MonitoringStatusOK}
+	if err := s.Client.Ping().Err(); err != nil {
+		return sdk.MonitoringStatusLine{Component: "Cache Ping", Value: fmt.Sprintf("KO (%s): %v", s.mode, err), Status: sdk.MonitoringStatusAlert}
+	}
+
+	status := sdk.MonitoringStatusOK
+	value := "OK (" + s.mode
+	var stats *redis.PoolStats
+	switch c := s.Client.(type) {
+	case *redis.ClusterClient:
+		// The cluster answers while a node is down, but some slots can be unavailable
+		var nodes, failures int32
+		_ = c.ForEachNode(func(client *redis.Client) error {
+			atomic.AddInt32(&nodes, 1)
+			if err := client.Ping().Err(); err != nil {
+				atomic.AddInt32(&failures, 1)
+			}
+			return nil
+		})
+		value += fmt.Sprintf(", %d/%d nodes", nodes-failures, nodes)
+		if failures > 0 {
+			status = sdk.MonitoringStatusWarn
+		}
+		stats = c.PoolStats()
+	case *redis.Client:
+		stats = c.PoolStats()
+	}
+	if stats != nil {
+		value += fmt.Sprintf(", %d conns, %d timeouts", stats.TotalConns, stats.Timeouts)
 	}
-	return sdk.MonitoringStatusLine{Component: "Cache Ping", Value: "KO", Status: sdk.MonitoringStatusAlert}
+	value += ")"
+
+	return sdk.MonitoringStatusLine{Component: "Cache Ping", Value: value, Status: status}
 }
 
 // RemoveFromQueue removes a member from a list
@@ -410,9 +474,20 @@ func (s *RedisStore) SetScan(key string, members ...interface{}) error {
 	}
 
 	if len(keys) > 0 {
-		res, err := s.Client.MGet(keys...).Result()
-		if err != nil {
-			return sdk.WrapError(err, "redis mget error")
+		// Get the members one by one in a pipeline instead of MGET, which cannot get keys of different slots in a redis cluster
+		pipe := s.Client.Pipeline()
+		cmds := make([]*redis.StringCmd, len(keys))
+		for i, k := range keys {
+			cmds[i] = pipe.Get(k)
+		}
+		if _, err := pipe.Exec(); err != nil && err != redis.Nil {
+			return sdk.WrapError(err, "redis get error")
+		}
+		res := make([]interface{}, len(keys))
+		for i, cmd := range cmds {
+			if v, err := cmd.Result(); err == nil {
+				res[i] = v
+			}
 		}
 
 		for i := range members {
@@ -0,0 +1,24 @@
+package cache
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedisOptions(t *testing.T) {
+	opts, mode := redisOptions("localhost:6379", "pwd")
+	assert.Equal(t, RedisModeSingle, mode)
+	assert.Equal(t, []string{"localhost:6379"}, opts.Addrs)
+	assert.Equal(t, "pwd", opts.Password)
+
+	opts, mode = redisOptions("mymaster@sentinel1:26379,sentinel2:26379", "")
+	assert.Equal(t, RedisModeSentinel, mode)
+	assert.Equal(t, "mymaster", opts.MasterName)
+	assert.Equal(t, []string{"sentinel1:26379", "sentinel2:26379"}, opts.Addrs)
+
+	opts, mode = redisOptions("node1:7000, node2:7000,node3:7000", "")
+	assert.Equal(t, RedisModeCluster, mode)
+	assert.Empty(t, opts.MasterName)
+	assert.Equal(t, []string{"node1:7000", "node2:7000", "node3:7000"}, opts.Addrs)
+}
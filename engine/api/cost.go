@@ -0,0 +1,90 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/ovh/cds/engine/api/cost"
+	"github.com/ovh/cds/engine/api/project"
+	"github.com/ovh/cds/engine/service"
+	"github.com/ovh/cds/sdk"
+)
+
+const costDateFormat = "2006-01-02"
+
+// parseCostPeriod reads the from and to query params, both days included. The current month is used by default.
+func parseCostPeriod(r *http.Request) (time.Time, time.Time, error) {
+	now := time.Now().UTC()
+	return parsePeriod(r, time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC), now)
+}
+
+// parsePeriod reads the from and to query params, both days included, with the given period by default
+func parsePeriod(r *http.Request, from, to time.Time) (time.Time, time.Time, error) {
+	if s := r.FormValue("from"); s != "" {
+		t, err := time.Parse(costDateFormat, s)
+		if err != nil {
+			return from, to, sdk.NewErrorFrom(sdk.ErrWrongRequest, "invalid from date %s, expected format is YYYY-MM-DD", s)
+		}
+		from = t
+	}
+	if s := r.FormValue("to"); s != "" {
+		t, err := time.Parse(costDateFormat, s)
+		if err != nil {
+			return from, to, sdk.NewErrorFrom(sdk.ErrWrongRequest, "invalid to date %s, expected format is YYYY-MM-DD", s)
+		}
+		to = t.AddDate(0, 0, 1)
+	}
+	if !from.Before(to) {
+		return from, to, sdk.NewErrorFrom(sdk.ErrWrongRequest, "the from date must be before the to date")
+	}
+	return from, to, nil
+}
+
+// writeCostReport writes the cost report of a project, or of all the projects with a zero project id, as JSON or as
+// CSV with the format=csv query param
+func (api *API) writeCostReport(w http.ResponseWriter, r *http.Request, projectID int64, defaultBy string) error {
+	by := r.FormValue("by")
+	if by == "" {
+		by = defaultBy
+	}
+	from, to, err := parseCostPeriod(r)
+	if err != nil {
+		return err
+	}
+
+	report, err := cost.LoadReport(api.mustDB(), projectID, by, from, to)
+	if err != nil {
+		return err
+	}
+
+	if r.FormValue("format") != "csv" {
+		return service.WriteJSON(w, report, http.StatusOK)
+	}
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"cost-%s-%s.csv\"", by, from.Format(costDateFormat)))
+	w.WriteHeader(http.StatusOK)
+	return report.WriteCSV(w)
+}
+
+// getProjectCostHandler returns the compute time and the artifacts of the runs of a project, by application by default
+func (api *API) getProjectCostHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		key := mux.Vars(r)[permProjectKey]
+		p, err := project.Load(api.mustDB(), api.Cache, key, deprecatedGetUser(ctx))
+		if err != nil {
+			return sdk.WrapError(err, "cannot load project %s", key)
+		}
+		return api.writeCostReport(w, r, p.ID, sdk.CostByApplication)
+	}
+}
+
+// getAdminCostHandler returns the compute time and the artifacts of the runs of all the projects, by group by default
+func (api *API) getAdminCostHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		return api.writeCostReport(w, r, 0, sdk.CostByGroup)
+	}
+}
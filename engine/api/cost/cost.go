@@ -0,0 +1,93 @@
+package cost
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/group"
+	"github.com/ovh/cds/sdk"
+)
+
+// ChargedGroupID returns the group which is charged for the jobs of a node run: the executing group with the lowest
+// id, the shared.infra group is ignored as it can run the jobs of every project
+func ChargedGroupID(execGroups []sdk.Group) int64 {
+	var id int64
+	for _, g := range execGroups {
+		if group.SharedInfraGroup != nil && g.ID == group.SharedInfraGroup.ID {
+			continue
+		}
+		if id == 0 || g.ID < id {
+			id = g.ID
+		}
+	}
+	return id
+}
+
+const upsertQuery = `
+	INSERT INTO run_cost (workflow_node_run_id, workflow_run_id, project_id, workflow_id, application_id, group_id, compute_seconds, artifact_size)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	ON CONFLICT (workflow_node_run_id) DO UPDATE SET
+		compute_seconds = run_cost.compute_seconds + $7,
+		artifact_size = run_cost.artifact_size + $8,
+		group_id = CASE WHEN $6 <> 0 THEN $6 ELSE run_cost.group_id END`
+
+// AddCompute charges the duration of a job to its node run
+func AddCompute(db gorp.SqlExecutor, projectID int64, nodeRun *sdk.WorkflowNodeRun, groupID int64, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+	_, err := db.Exec(upsertQuery, nodeRun.ID, nodeRun.WorkflowRunID, projectID, nodeRun.WorkflowID, nodeRun.ApplicationID, groupID, int64(d.Seconds()), 0)
+	return sdk.WrapError(err, "cannot add compute time to node run %d", nodeRun.ID)
+}
+
+// AddArtifact charges the size of an uploaded artifact to its node run
+func AddArtifact(db gorp.SqlExecutor, projectID int64, nodeRun *sdk.WorkflowNodeRun, size int64) error {
+	if size <= 0 {
+		return nil
+	}
+	_, err := db.Exec(upsertQuery, nodeRun.ID, nodeRun.WorkflowRunID, projectID, nodeRun.WorkflowID, nodeRun.ApplicationID, 0, 0, size)
+	return sdk.WrapError(err, "cannot add artifact size to node run %d", nodeRun.ID)
+}
+
+// keys are the labels of the lines of the reports for each aggregation, the items deleted since the run are
+// reported with their id
+var keys = map[string]string{
+	sdk.CostByProject:     `project.projectkey`,
+	sdk.CostByApplication: `project.projectkey || '/' || COALESCE(application.name, CAST(run_cost.application_id AS TEXT))`,
+	sdk.CostByGroup:       `COALESCE("group".name, CAST(run_cost.group_id AS TEXT))`,
+	sdk.CostByWorkflow:    `project.projectkey || '/' || COALESCE(workflow.name, CAST(run_cost.workflow_id AS TEXT))`,
+	sdk.CostByRun:         `project.projectkey || '/' || COALESCE(workflow.name, CAST(run_cost.workflow_id AS TEXT)) || '#' || COALESCE(CAST(workflow_run.num AS TEXT), CAST(run_cost.workflow_run_id AS TEXT))`,
+}
+
+// LoadReport aggregates the costs of the node runs started between two dates, for a project or all the projects
+// with a zero project id
+func LoadReport(db gorp.SqlExecutor, projectID int64, by string, from, to time.Time) (*sdk.CostReport, error) {
+	key, ok := keys[by]
+	if !ok {
+		return nil, sdk.NewErrorFrom(sdk.ErrWrongRequest, "invalid aggregation %s, expected one of %v", by, sdk.CostAggregations)
+	}
+
+	query := fmt.Sprintf(`
+	SELECT %s AS key,
+		COUNT(DISTINCT run_cost.workflow_run_id) AS runs,
+		SUM(run_cost.compute_seconds) AS compute_seconds,
+		SUM(run_cost.artifact_size) AS artifact_size
+	FROM run_cost
+	JOIN project ON project.id = run_cost.project_id
+	LEFT JOIN application ON application.id = run_cost.application_id
+	LEFT JOIN "group" ON "group".id = run_cost.group_id
+	LEFT JOIN workflow ON workflow.id = run_cost.workflow_id
+	LEFT JOIN workflow_run ON workflow_run.id = run_cost.workflow_run_id
+	WHERE run_cost.created >= $1 AND run_cost.created < $2 AND ($3 = 0 OR run_cost.project_id = $3)
+	GROUP BY 1
+	ORDER BY 1`, key)
+
+	r := sdk.CostReport{By: by, From: from, To: to, Lines: []sdk.CostReportLine{}}
+	if _, err := db.Select(&r.Lines, query, from, to, projectID); err != nil {
+		return nil, sdk.WrapError(err, "cannot load cost report")
+	}
+	r.ComputeMinutes()
+	return &r, nil
+}
@@ -0,0 +1,19 @@
+package cost
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/engine/api/group"
+	"github.com/ovh/cds/sdk"
+)
+
+func TestChargedGroupID(t *testing.T) {
+	group.SharedInfraGroup = &sdk.Group{ID: 1, Name: sdk.SharedInfraGroupName}
+	defer func() { group.SharedInfraGroup = nil }()
+
+	assert.Equal(t, int64(5), ChargedGroupID([]sdk.Group{{ID: 1}, {ID: 8}, {ID: 5}}))
+	assert.Equal(t, int64(0), ChargedGroupID([]sdk.Group{{ID: 1}}))
+	assert.Equal(t, int64(0), ChargedGroupID(nil))
+}
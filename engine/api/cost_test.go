@@ -0,0 +1,29 @@
+package api
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func Test_parseCostPeriod(t *testing.T) {
+	from, to, err := parseCostPeriod(httptest.NewRequest("GET", "/project/KEY/cost?from=2019-03-01&to=2019-03-31", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2019, 3, 1, 0, 0, 0, 0, time.UTC), from)
+	// the last day is included
+	assert.Equal(t, time.Date(2019, 4, 1, 0, 0, 0, 0, time.UTC), to)
+
+	from, _, err = parseCostPeriod(httptest.NewRequest("GET", "/project/KEY/cost", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, from.Day())
+
+	_, _, err = parseCostPeriod(httptest.NewRequest("GET", "/project/KEY/cost?from=2019-04-01&to=2019-03-01", nil))
+	assert.True(t, sdk.ErrorIs(err, sdk.ErrWrongRequest))
+
+	_, _, err = parseCostPeriod(httptest.NewRequest("GET", "/project/KEY/cost?from=03/2019", nil))
+	assert.True(t, sdk.ErrorIs(err, sdk.ErrWrongRequest))
+}
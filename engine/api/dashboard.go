@@ -0,0 +1,162 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/ovh/cds/engine/api/dashboard"
+	"github.com/ovh/cds/engine/api/permission"
+	"github.com/ovh/cds/engine/service"
+	"github.com/ovh/cds/sdk"
+)
+
+// loadDashboard returns the dashboard of the request if the user can read it, or can write it when write is true:
+// only its owner and the CDS administrators can change a dashboard
+func (api *API) loadDashboard(ctx context.Context, r *http.Request, write bool) (*sdk.Dashboard, error) {
+	id, err := requestVarInt(r, "id")
+	if err != nil {
+		return nil, err
+	}
+	d, err := dashboard.LoadByID(api.mustDB(), id)
+	if err != nil {
+		return nil, err
+	}
+	u := deprecatedGetUser(ctx)
+	if !d.CanRead(u) {
+		return nil, sdk.NewErrorFrom(sdk.ErrNotFound, "dashboard %d not found", id)
+	}
+	if write && !u.Admin && d.OwnerID != u.ID {
+		return nil, sdk.WithStack(sdk.ErrForbidden)
+	}
+	return d, nil
+}
+
+// getDashboardsHandler returns the dashboards of the user and the dashboards shared with the user
+func (api *API) getDashboardsHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		ds, err := dashboard.LoadAllByUser(api.mustDB(), deprecatedGetUser(ctx))
+		if err != nil {
+			return err
+		}
+		return service.WriteJSON(w, ds, http.StatusOK)
+	}
+}
+
+// postDashboardHandler creates a dashboard owned by the user
+func (api *API) postDashboardHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		var d sdk.Dashboard
+		if err := service.UnmarshalBody(r, &d); err != nil {
+			return sdk.WrapError(err, "cannot read body")
+		}
+		if err := d.IsValid(); err != nil {
+			return err
+		}
+		u := deprecatedGetUser(ctx)
+		d.ID = 0
+		d.OwnerID = u.ID
+
+		tx, err := api.mustDB().Begin()
+		if err != nil {
+			return sdk.WrapError(err, "cannot start transaction")
+		}
+		defer tx.Rollback() // nolint
+
+		if err := dashboard.Insert(tx, &d); err != nil {
+			return err
+		}
+		if err := tx.Commit(); err != nil {
+			return sdk.WrapError(err, "cannot commit transaction")
+		}
+		d.Owner = u.Username
+		return service.WriteJSON(w, d, http.StatusCreated)
+	}
+}
+
+// getDashboardHandler returns a dashboard
+func (api *API) getDashboardHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		d, err := api.loadDashboard(ctx, r, false)
+		if err != nil {
+			return err
+		}
+		return service.WriteJSON(w, d, http.StatusOK)
+	}
+}
+
+// putDashboardHandler updates the widgets of a dashboard and the users and groups it is shared with
+func (api *API) putDashboardHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		old, err := api.loadDashboard(ctx, r, true)
+		if err != nil {
+			return err
+		}
+
+		var d sdk.Dashboard
+		if err := service.UnmarshalBody(r, &d); err != nil {
+			return sdk.WrapError(err, "cannot read body")
+		}
+		if err := d.IsValid(); err != nil {
+			return err
+		}
+		d.ID = old.ID
+		d.OwnerID = old.OwnerID
+		d.Created = old.Created
+
+		tx, err := api.mustDB().Begin()
+		if err != nil {
+			return sdk.WrapError(err, "cannot start transaction")
+		}
+		defer tx.Rollback() // nolint
+
+		if err := dashboard.Update(tx, &d); err != nil {
+			return err
+		}
+		if err := tx.Commit(); err != nil {
+			return sdk.WrapError(err, "cannot commit transaction")
+		}
+		d.Owner = old.Owner
+		return service.WriteJSON(w, d, http.StatusOK)
+	}
+}
+
+// deleteDashboardHandler deletes a dashboard
+func (api *API) deleteDashboardHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		d, err := api.loadDashboard(ctx, r, true)
+		if err != nil {
+			return err
+		}
+		if err := dashboard.Delete(api.mustDB(), d); err != nil {
+			return err
+		}
+		return service.WriteJSON(w, nil, http.StatusOK)
+	}
+}
+
+// getDashboardDataHandler returns the data of each widget of a dashboard, in the order of the widgets. The data of a
+// widget on a project the user cannot read is replaced by an error.
+func (api *API) getDashboardDataHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		d, err := api.loadDashboard(ctx, r, false)
+		if err != nil {
+			return err
+		}
+
+		u := deprecatedGetUser(ctx)
+		now := time.Now()
+		res := make([]sdk.DashboardWidgetData, len(d.Widgets))
+		for i, widget := range d.Widgets {
+			if !u.Admin && u.Permissions.ProjectsPerm[widget.ProjectKey] < permission.PermissionRead {
+				res[i] = sdk.DashboardWidgetData{Type: widget.Type, Points: []sdk.DashboardDataPoint{}, Error: "forbidden"}
+				continue
+			}
+			res[i], err = dashboard.LoadWidgetData(api.mustDB(), widget, now)
+			if err != nil {
+				return err
+			}
+		}
+		return service.WriteJSON(w, res, http.StatusOK)
+	}
+}
@@ -0,0 +1,137 @@
+package dashboard
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/go-gorp/gorp"
+	"github.com/lib/pq"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// Insert creates a dashboard and shares it with its users and groups
+func Insert(db gorp.SqlExecutor, d *sdk.Dashboard) error {
+	d.Created = time.Now()
+	d.LastModified = d.Created
+	if err := db.Insert(d); err != nil {
+		return sdk.WrapError(err, "cannot insert dashboard %s", d.Name)
+	}
+	return share(db, d)
+}
+
+// Update saves a dashboard and shares it with its users and groups
+func Update(db gorp.SqlExecutor, d *sdk.Dashboard) error {
+	d.LastModified = time.Now()
+	if _, err := db.Update(d); err != nil {
+		return sdk.WrapError(err, "cannot update dashboard %s", d.Name)
+	}
+	return share(db, d)
+}
+
+// Delete removes a dashboard
+func Delete(db gorp.SqlExecutor, d *sdk.Dashboard) error {
+	_, err := db.Delete(d)
+	return sdk.WrapError(err, "cannot delete dashboard %s", d.Name)
+}
+
+func share(db gorp.SqlExecutor, d *sdk.Dashboard) error {
+	if _, err := db.Exec("DELETE FROM dashboard_user WHERE dashboard_id = $1", d.ID); err != nil {
+		return sdk.WrapError(err, "cannot unshare dashboard %d", d.ID)
+	}
+	if _, err := db.Exec("DELETE FROM dashboard_group WHERE dashboard_id = $1", d.ID); err != nil {
+		return sdk.WrapError(err, "cannot unshare dashboard %d", d.ID)
+	}
+
+	d.SharedUsers, d.SharedGroups = unique(d.SharedUsers), unique(d.SharedGroups)
+	if len(d.SharedUsers) > 0 {
+		res, err := db.Exec(`INSERT INTO dashboard_user (dashboard_id, user_id)
+			SELECT $1, id FROM "user" WHERE username = ANY($2)`, d.ID, pq.StringArray(d.SharedUsers))
+		if err != nil {
+			return sdk.WrapError(err, "cannot share dashboard %d with users", d.ID)
+		}
+		if n, _ := res.RowsAffected(); int(n) != len(d.SharedUsers) {
+			return sdk.NewErrorFrom(sdk.ErrUserNotFound, "cannot share dashboard with unknown users")
+		}
+	}
+	if len(d.SharedGroups) > 0 {
+		res, err := db.Exec(`INSERT INTO dashboard_group (dashboard_id, group_id)
+			SELECT $1, id FROM "group" WHERE name = ANY($2)`, d.ID, pq.StringArray(d.SharedGroups))
+		if err != nil {
+			return sdk.WrapError(err, "cannot share dashboard %d with groups", d.ID)
+		}
+		if n, _ := res.RowsAffected(); int(n) != len(d.SharedGroups) {
+			return sdk.NewErrorFrom(sdk.ErrGroupNotFound, "cannot share dashboard with unknown groups")
+		}
+	}
+	return nil
+}
+
+func unique(names []string) []string {
+	res := make([]string, 0, len(names))
+	for _, n := range names {
+		if !sdk.IsInArray(n, res) {
+			res = append(res, n)
+		}
+	}
+	return res
+}
+
+// LoadByID returns a dashboard with its owner and the users and groups it is shared with
+func LoadByID(db gorp.SqlExecutor, id int64) (*sdk.Dashboard, error) {
+	var d sdk.Dashboard
+	if err := db.SelectOne(&d, "SELECT * FROM dashboard WHERE id = $1", id); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, sdk.NewErrorFrom(sdk.ErrNotFound, "dashboard %d not found", id)
+		}
+		return nil, sdk.WrapError(err, "cannot load dashboard %d", id)
+	}
+	if err := loadShares(db, &d); err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+// LoadAllByUser returns the dashboards owned by a user or shared with the user or one of the user's groups
+func LoadAllByUser(db gorp.SqlExecutor, u *sdk.User) ([]sdk.Dashboard, error) {
+	groupIDs := make([]int64, len(u.Groups))
+	for i := range u.Groups {
+		groupIDs[i] = u.Groups[i].ID
+	}
+	ds := []sdk.Dashboard{}
+	if _, err := db.Select(&ds, `SELECT * FROM dashboard
+		WHERE owner_id = $1
+		OR id IN (SELECT dashboard_id FROM dashboard_user WHERE user_id = $1)
+		OR id IN (SELECT dashboard_id FROM dashboard_group WHERE group_id = ANY($2))
+		ORDER BY name`, u.ID, pq.Int64Array(groupIDs)); err != nil {
+		return nil, sdk.WrapError(err, "cannot load dashboards of user %s", u.Username)
+	}
+	for i := range ds {
+		if err := loadShares(db, &ds[i]); err != nil {
+			return nil, err
+		}
+	}
+	return ds, nil
+}
+
+func loadShares(db gorp.SqlExecutor, d *sdk.Dashboard) error {
+	owner, err := db.SelectStr(`SELECT username FROM "user" WHERE id = $1`, d.OwnerID)
+	if err != nil {
+		return sdk.WrapError(err, "cannot load owner of dashboard %d", d.ID)
+	}
+	d.Owner = owner
+	d.SharedUsers, d.SharedGroups = []string{}, []string{}
+	if _, err := db.Select(&d.SharedUsers, `SELECT "user".username FROM "user"
+		JOIN dashboard_user ON dashboard_user.user_id = "user".id
+		WHERE dashboard_user.dashboard_id = $1
+		ORDER BY "user".username`, d.ID); err != nil {
+		return sdk.WrapError(err, "cannot load users of dashboard %d", d.ID)
+	}
+	if _, err := db.Select(&d.SharedGroups, `SELECT "group".name FROM "group"
+		JOIN dashboard_group ON dashboard_group.group_id = "group".id
+		WHERE dashboard_group.dashboard_id = $1
+		ORDER BY "group".name`, d.ID); err != nil {
+		return sdk.WrapError(err, "cannot load groups of dashboard %d", d.ID)
+	}
+	return nil
+}
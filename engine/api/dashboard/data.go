@@ -0,0 +1,62 @@
+package dashboard
+
+import (
+	"time"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// LoadWidgetData returns the data shown by a widget
+func LoadWidgetData(db gorp.SqlExecutor, w sdk.DashboardWidget, now time.Time) (sdk.DashboardWidgetData, error) {
+	data := sdk.DashboardWidgetData{Type: w.Type}
+	var err error
+	switch w.Type {
+	case sdk.DashboardWidgetRunHistory:
+		data.Points, err = loadPoints(db, `SELECT date_trunc('day', workflow_run.start), workflow_run.status, count(*)
+			FROM workflow_run
+			JOIN workflow ON workflow.id = workflow_run.workflow_id
+			JOIN project ON project.id = workflow.project_id
+			WHERE project.projectkey = $1 AND workflow.name = $2 AND workflow_run.start >= $3
+			GROUP BY 1, 2
+			ORDER BY 1, 2`, w.ProjectKey, w.WorkflowName, w.Since(now))
+	case sdk.DashboardWidgetQueueDepth:
+		data.Points, err = loadPoints(db, `SELECT $2::timestamp with time zone, workflow_node_run_job.status, count(*)
+			FROM workflow_node_run_job
+			JOIN project ON project.id = workflow_node_run_job.project_id
+			WHERE project.projectkey = $1
+			GROUP BY 1, 2
+			ORDER BY 2`, w.ProjectKey, now)
+	case sdk.DashboardWidgetDeploymentFrequency:
+		data.Points, err = loadPoints(db, `SELECT date_trunc('day', workflow_run_duration.created), workflow_run_duration.pipeline_name, count(*)
+			FROM workflow_run_duration
+			JOIN workflow ON workflow.id = workflow_run_duration.workflow_id
+			JOIN project ON project.id = workflow.project_id
+			WHERE project.projectkey = $1 AND workflow.name = $2 AND workflow_run_duration.pipeline_name = $3
+			AND workflow_run_duration.kind = $4 AND workflow_run_duration.status = $5 AND workflow_run_duration.created >= $6
+			GROUP BY 1, 2
+			ORDER BY 1`, w.ProjectKey, w.WorkflowName, w.PipelineName, sdk.DurationKindPipeline, sdk.StatusSuccess.String(), w.Since(now))
+	default:
+		return data, sdk.NewErrorFrom(sdk.ErrWrongRequest, "unknown widget type %s", w.Type)
+	}
+	return data, err
+}
+
+func loadPoints(db gorp.SqlExecutor, query string, args ...interface{}) ([]sdk.DashboardDataPoint, error) {
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, sdk.WrapError(err, "cannot load widget data")
+	}
+	defer rows.Close()
+
+	points := []sdk.DashboardDataPoint{}
+	for rows.Next() {
+		var p sdk.DashboardDataPoint
+		if err := rows.Scan(&p.Date, &p.Label, &p.Value); err != nil {
+			return nil, sdk.WrapError(err, "cannot scan widget data")
+		}
+		points = append(points, p)
+	}
+	return points, sdk.WithStack(rows.Err())
+}
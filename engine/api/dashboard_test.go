@@ -0,0 +1,83 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/engine/api/test"
+	"github.com/ovh/cds/engine/api/test/assets"
+	"github.com/ovh/cds/sdk"
+)
+
+func Test_shareDashboard(t *testing.T) {
+	api, db, router, end := newTestAPI(t)
+	defer end()
+
+	owner, ownerPass := assets.InsertLambdaUser(api.mustDB())
+	reader, readerPass := assets.InsertLambdaUser(api.mustDB())
+	other, otherPass := assets.InsertLambdaUser(api.mustDB())
+	key := sdk.RandomString(10)
+	assets.InsertTestProject(t, db, api.Cache, key, key, owner)
+
+	d := sdk.Dashboard{
+		Name:        "my dashboard",
+		SharedUsers: []string{reader.Username},
+		Widgets: sdk.DashboardWidgets{
+			{Type: sdk.DashboardWidgetQueueDepth, ProjectKey: key},
+		},
+	}
+	uri := router.GetRoute("POST", api.postDashboardHandler, nil)
+	test.NotEmpty(t, uri)
+	w := httptest.NewRecorder()
+	router.Mux.ServeHTTP(w, assets.NewAuthentifiedRequest(t, owner, ownerPass, "POST", uri, d))
+	assert.Equal(t, 201, w.Code)
+	test.NoError(t, json.Unmarshal(w.Body.Bytes(), &d))
+	assert.Equal(t, owner.Username, d.Owner)
+	vars := map[string]string{"id": strconv.FormatInt(d.ID, 10)}
+
+	// The dashboard is shared with the reader, who cannot change it nor read the data of the project
+	uri = router.GetRoute("GET", api.getDashboardsHandler, nil)
+	test.NotEmpty(t, uri)
+	w = httptest.NewRecorder()
+	router.Mux.ServeHTTP(w, assets.NewAuthentifiedRequest(t, reader, readerPass, "GET", uri, nil))
+	assert.Equal(t, 200, w.Code)
+	var ds []sdk.Dashboard
+	test.NoError(t, json.Unmarshal(w.Body.Bytes(), &ds))
+	assert.Len(t, ds, 1)
+
+	uri = router.GetRoute("PUT", api.putDashboardHandler, vars)
+	test.NotEmpty(t, uri)
+	w = httptest.NewRecorder()
+	router.Mux.ServeHTTP(w, assets.NewAuthentifiedRequest(t, reader, readerPass, "PUT", uri, d))
+	assert.Equal(t, 403, w.Code)
+
+	uri = router.GetRoute("GET", api.getDashboardDataHandler, vars)
+	test.NotEmpty(t, uri)
+	w = httptest.NewRecorder()
+	router.Mux.ServeHTTP(w, assets.NewAuthentifiedRequest(t, reader, readerPass, "GET", uri, nil))
+	assert.Equal(t, 200, w.Code)
+	var data []sdk.DashboardWidgetData
+	test.NoError(t, json.Unmarshal(w.Body.Bytes(), &data))
+	if assert.Len(t, data, 1) {
+		assert.Equal(t, "forbidden", data[0].Error)
+	}
+
+	w = httptest.NewRecorder()
+	router.Mux.ServeHTTP(w, assets.NewAuthentifiedRequest(t, owner, ownerPass, "GET", uri, nil))
+	assert.Equal(t, 200, w.Code)
+	test.NoError(t, json.Unmarshal(w.Body.Bytes(), &data))
+	if assert.Len(t, data, 1) {
+		assert.Equal(t, "", data[0].Error)
+	}
+
+	// The dashboard is not shared with the other user
+	uri = router.GetRoute("GET", api.getDashboardHandler, vars)
+	test.NotEmpty(t, uri)
+	w = httptest.NewRecorder()
+	router.Mux.ServeHTTP(w, assets.NewAuthentifiedRequest(t, other, otherPass, "GET", uri, nil))
+	assert.Equal(t, 404, w.Code)
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/gorilla/mux"
 
 	"github.com/ovh/cds/engine/api/database/dbmigrate"
+	"github.com/ovh/cds/engine/api/migrate"
 	"github.com/ovh/cds/engine/service"
 	"github.com/ovh/cds/sdk"
 )
@@ -46,3 +47,18 @@ func (api *API) getDatabaseMigrationHandler() service.Handler {
 		return service.WriteJSON(w, a, http.StatusOK)
 	}
 }
+
+func (api *API) getDatabaseMigrationStatusHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		s, err := dbmigrate.Status(api.mustDB().Db)
+		if err != nil {
+			return sdk.WrapError(err, "cannot get database schema status")
+		}
+		count, err := migrate.CountInProgress(api.mustDB())
+		if err != nil {
+			return err
+		}
+		s.OnlineMigrations = int(count)
+		return service.WriteJSON(w, s, http.StatusOK)
+	}
+}
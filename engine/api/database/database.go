@@ -27,6 +27,7 @@ type DBConnectionFactory struct {
 	DBConnectTimeout int
 	DBMaxConn        int
 	Database         *sql.DB
+	Replicas         *ReplicaSet
 	mutex            *sync.Mutex
 }
 
@@ -41,6 +42,7 @@ func (f *DBConnectionFactory) DB() *sql.DB {
 			log.Error("Database> cannot init db connection : %s", err)
 			return nil
 		}
+		newF.Replicas = f.Replicas
 		*f = *newF
 	}
 	if err := f.Database.Ping(); err != nil {
@@ -155,6 +157,11 @@ func (f *DBConnectionFactory) Status() sdk.MonitoringStatusLine {
 
 // Close closes the database, releasing any open resources.
 func (f *DBConnectionFactory) Close() error {
+	if f.Replicas != nil {
+		if err := f.Replicas.Close(); err != nil {
+			log.Error("Cannot close connection to DB replicas : %s", err)
+		}
+	}
 	if f.Database != nil {
 		return f.Database.Close()
 	}
@@ -0,0 +1,143 @@
+package dbmigrate
+
+import (
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+
+	gorp "gopkg.in/gorp.v1"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// RequiredSchemaVersion is the number of the last migration script of engine/sql, it has to be updated with each
+// new script. TestRequiredSchemaVersion checks it against the scripts.
+//
+// A script which breaks the API versions before it (drop or rename of a column still used...) has to raise
+// the minimum compatible version to its number in the same script, by updating min_schema_version in table
+// database_compatibility. The other scripts are backward compatible: they can be applied before the upgrade
+// of the API, without downtime.
+const RequiredSchemaVersion = 202
+
+// SchemaVersion returns the number of the last migration script applied
+func SchemaVersion(db *sql.DB) (int, error) {
+	rows, err := db.Query("SELECT id FROM gorp_migrations")
+	if err != nil {
+		return 0, sdk.WithStack(err)
+	}
+	defer rows.Close() // nolint
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return 0, sdk.WithStack(err)
+		}
+		ids = append(ids, id)
+	}
+	return schemaVersion(ids), sdk.WithStack(rows.Err())
+}
+
+func schemaVersion(ids []string) int {
+	var version int
+	for _, id := range ids {
+		n, err := strconv.Atoi(strings.SplitN(id, "_", 2)[0])
+		if err == nil && n > version {
+			version = n
+		}
+	}
+	return version
+}
+
+// MinCompatibleVersion returns the minimum schema version required by the database, 0 if the scripts
+// adding it are not applied
+func MinCompatibleVersion(db *sql.DB) (int, error) {
+	var exists bool
+	if err := db.QueryRow("SELECT to_regclass('database_compatibility') IS NOT NULL").Scan(&exists); err != nil {
+		return 0, sdk.WithStack(err)
+	}
+	if !exists {
+		return 0, nil
+	}
+
+	var version int
+	if err := db.QueryRow("SELECT min_schema_version FROM database_compatibility WHERE id = 1").Scan(&version); err != nil {
+		if err == sql.ErrNoRows {
+			return 0, nil
+		}
+		return 0, sdk.WithStack(err)
+	}
+	return version, nil
+}
+
+// CheckCompatibility returns an error if the database schema cannot be used by this version of the API
+func CheckCompatibility(db *sql.DB) error {
+	version, err := SchemaVersion(db)
+	if err != nil {
+		return err
+	}
+	minVersion, err := MinCompatibleVersion(db)
+	if err != nil {
+		return err
+	}
+	return checkCompatibility(version, minVersion, RequiredSchemaVersion)
+}
+
+func checkCompatibility(version, minVersion, required int) error {
+	if version < required {
+		return fmt.Errorf("database schema version %d is too old, this version of CDS needs at least the migration %d: please run 'engine database upgrade'", version, required)
+	}
+	if minVersion > required {
+		return fmt.Errorf("database schema version %d is not compatible anymore with this version of CDS, which supports the migration %d: please upgrade CDS", minVersion, required)
+	}
+	return nil
+}
+
+// Lock returns the current lock of the migration scripts, nil if they are not locked
+func Lock(db *sql.DB) (*MigrationLock, error) {
+	var exists bool
+	if err := db.QueryRow("SELECT to_regclass('gorp_migrations_lock') IS NOT NULL").Scan(&exists); err != nil {
+		return nil, sdk.WithStack(err)
+	}
+	if !exists {
+		return nil, nil
+	}
+
+	dbmap := &gorp.DbMap{Db: db, Dialect: gorp.PostgresDialect{}}
+	var locks []MigrationLock
+	if _, err := dbmap.Select(&locks, "SELECT * FROM gorp_migrations_lock WHERE unlocked IS NULL ORDER BY locked DESC LIMIT 1"); err != nil {
+		return nil, sdk.WithStack(err)
+	}
+	if len(locks) == 0 {
+		return nil, nil
+	}
+	return &locks[0], nil
+}
+
+// Status returns the compatibility status of the database schema
+func Status(db *sql.DB) (sdk.DatabaseSchemaStatus, error) {
+	s := sdk.DatabaseSchemaStatus{RequiredVersion: RequiredSchemaVersion}
+
+	var err error
+	s.SchemaVersion, err = SchemaVersion(db)
+	if err != nil {
+		return s, err
+	}
+	s.MinCompatibleVersion, err = MinCompatibleVersion(db)
+	if err != nil {
+		return s, err
+	}
+	s.Compatible = checkCompatibility(s.SchemaVersion, s.MinCompatibleVersion, RequiredSchemaVersion) == nil
+
+	lock, err := Lock(db)
+	if err != nil {
+		return s, err
+	}
+	if lock != nil {
+		s.Locked = true
+		s.LockedBy = lock.ID
+		s.LockedSince = lock.Locked
+	}
+	return s, nil
+}
@@ -0,0 +1,35 @@
+package dbmigrate
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSchemaVersion(t *testing.T) {
+	assert.Equal(t, 0, schemaVersion(nil))
+	assert.Equal(t, 181, schemaVersion([]string{"000_create_all.sql", "181_online_migration.sql", "099_foo.sql", "manual.sql"}))
+}
+
+func TestCheckCompatibility(t *testing.T) {
+	assert.NoError(t, checkCompatibility(181, 0, 181))
+	// Backward compatible scripts can be applied before the upgrade of the API
+	assert.NoError(t, checkCompatibility(185, 150, 181))
+	assert.NoError(t, checkCompatibility(185, 181, 181))
+	// Missing script
+	assert.Error(t, checkCompatibility(180, 0, 181))
+	// A breaking script was applied
+	assert.Error(t, checkCompatibility(185, 184, 181))
+}
+
+func TestRequiredSchemaVersion(t *testing.T) {
+	files, err := filepath.Glob(filepath.Join("..", "..", "..", "sql", "*.sql"))
+	assert.NoError(t, err)
+	ids := make([]string, len(files))
+	for i := range files {
+		ids[i] = filepath.Base(files[i])
+	}
+	// RequiredSchemaVersion has to follow the last migration script
+	assert.Equal(t, schemaVersion(ids), RequiredSchemaVersion)
+}
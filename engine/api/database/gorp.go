@@ -29,6 +29,15 @@ func DBMap(db *sql.DB) *gorp.DbMap {
 		return lastDBMap
 	}
 
+	dbmap := newDBMap(db)
+
+	lastDB = db
+	lastDBMap = dbmap
+
+	return dbmap
+}
+
+func newDBMap(db *sql.DB) *gorp.DbMap {
 	dbmap := &gorp.DbMap{Db: db, Dialect: gorp.PostgresDialect{}, TypeConverter: new(TypeConverter)}
 
 	if os.Getenv("gorp_trace") == "true" {
@@ -39,9 +48,6 @@ func DBMap(db *sql.DB) *gorp.DbMap {
 		dbmap.AddTableWithName(m.Target, m.Name).SetKeys(m.AutoIncrement, m.Keys...)
 	}
 
-	lastDB = db
-	lastDBMap = dbmap
-
 	return dbmap
 }
 
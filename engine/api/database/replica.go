@@ -0,0 +1,176 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// replicaLagQuery returns the replication lag in seconds, 0 if the replica replayed all it received
+const replicaLagQuery = `SELECT CASE
+	WHEN pg_last_wal_receive_lsn() = pg_last_wal_replay_lsn() THEN 0
+	ELSE COALESCE(EXTRACT(EPOCH FROM now() - pg_last_xact_replay_timestamp()), 0)
+END`
+
+type replica struct {
+	addr  string
+	db    *sql.DB
+	dbmap *gorp.DbMap
+
+	mutex   sync.RWMutex
+	healthy bool
+	lag     time.Duration
+}
+
+func (r *replica) usable(maxLag time.Duration) bool {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	return r.healthy && r.lag <= maxLag
+}
+
+func (r *replica) checkLag() {
+	var lag float64
+	err := r.db.QueryRow(replicaLagQuery).Scan(&lag)
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if err != nil {
+		if r.healthy {
+			log.Error("Database> replica %s is unavailable: %v", r.addr, err)
+		}
+		r.healthy = false
+		return
+	}
+	r.healthy = true
+	r.lag = time.Duration(lag * float64(time.Second))
+}
+
+// ReplicaSet is the set of the read replicas of the database. A replica is used only while its
+// replication lag is lower than MaxLag.
+type ReplicaSet struct {
+	MaxLag   time.Duration
+	replicas []*replica
+	next     uint32
+}
+
+// InitReplicas connects to the read replicas, given as comma separated host:port, with the credentials of the database
+func (f *DBConnectionFactory) InitReplicas(addrs string, maxLag time.Duration) error {
+	s := &ReplicaSet{MaxLag: maxLag}
+	for _, addr := range strings.Split(addrs, ",") {
+		addr = strings.TrimSpace(addr)
+		if addr == "" {
+			continue
+		}
+		host, port, err := net.SplitHostPort(addr)
+		if err != nil {
+			return sdk.WrapError(err, "invalid replica %s", addr)
+		}
+		p, err := strconv.Atoi(port)
+		if err != nil {
+			return sdk.WrapError(err, "invalid replica port %s", addr)
+		}
+
+		replicaFactory := *f
+		replicaFactory.DBHost = host
+		replicaFactory.DBPort = p
+		db, err := sql.Open(f.DBDriver, replicaFactory.dsn())
+		if err != nil {
+			return sdk.WrapError(err, "cannot open replica %s", addr)
+		}
+		db.SetMaxOpenConns(f.DBMaxConn)
+		db.SetMaxIdleConns(int(f.DBMaxConn / 2))
+
+		r := &replica{addr: addr, db: db, dbmap: newDBMap(db)}
+		r.checkLag()
+		s.replicas = append(s.replicas, r)
+	}
+	f.Replicas = s
+	return nil
+}
+
+// CheckLag checks the replication lag of the replicas at the given interval, until the context is canceled
+func (s *ReplicaSet) CheckLag(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for _, r := range s.replicas {
+				r.checkLag()
+			}
+		}
+	}
+}
+
+// pick returns the next usable replica, round robin, nil if none is usable
+func (s *ReplicaSet) pick() *replica {
+	n := len(s.replicas)
+	if n == 0 {
+		return nil
+	}
+	start := int(atomic.AddUint32(&s.next, 1))
+	for i := 0; i < n; i++ {
+		r := s.replicas[(start+i)%n]
+		if r.usable(s.MaxLag) {
+			return r
+		}
+	}
+	return nil
+}
+
+// Status returns the status of the replicas
+func (s *ReplicaSet) Status() []sdk.MonitoringStatusLine {
+	lines := make([]sdk.MonitoringStatusLine, 0, len(s.replicas))
+	for _, r := range s.replicas {
+		r.mutex.RLock()
+		line := sdk.MonitoringStatusLine{Component: "Database Replica " + r.addr, Status: sdk.MonitoringStatusOK}
+		switch {
+		case !r.healthy:
+			line.Value = "No Ping"
+			line.Status = sdk.MonitoringStatusAlert
+		case r.lag > s.MaxLag:
+			line.Value = fmt.Sprintf("lag %s", r.lag)
+			line.Status = sdk.MonitoringStatusWarn
+		default:
+			line.Value = fmt.Sprintf("lag %s", r.lag)
+		}
+		r.mutex.RUnlock()
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// Close closes the connections to the replicas
+func (s *ReplicaSet) Close() error {
+	var err error
+	for _, r := range s.replicas {
+		if errC := r.db.Close(); errC != nil {
+			err = errC
+		}
+	}
+	return err
+}
+
+// GetReadDBMap returns a gorp.DbMap pointer on a read replica for read-only requests, which can be served
+// a few seconds late. It returns the primary database if no replica is configured or up to date.
+func (f *DBConnectionFactory) GetReadDBMap() *gorp.DbMap {
+	if f.Replicas != nil {
+		if r := f.Replicas.pick(); r != nil {
+			return r.dbmap
+		}
+	}
+	return f.GetDBMap()
+}
@@ -0,0 +1,40 @@
+package database
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func TestReplicaSetPick(t *testing.T) {
+	r1 := &replica{addr: "replica1:5432", healthy: true, lag: 2 * time.Second}
+	r2 := &replica{addr: "replica2:5432", healthy: true}
+	s := &ReplicaSet{MaxLag: 5 * time.Second, replicas: []*replica{r1, r2}}
+
+	// Round robin between the up to date replicas
+	picked := map[string]int{}
+	for i := 0; i < 4; i++ {
+		picked[s.pick().addr]++
+	}
+	assert.Equal(t, map[string]int{"replica1:5432": 2, "replica2:5432": 2}, picked)
+
+	// A lagging or unavailable replica is not used
+	r1.lag = 10 * time.Second
+	for i := 0; i < 4; i++ {
+		assert.Equal(t, r2, s.pick())
+	}
+	r2.healthy = false
+	assert.Nil(t, s.pick())
+
+	status := s.Status()
+	assert.Len(t, status, 2)
+	assert.Equal(t, sdk.MonitoringStatusWarn, status[0].Status)
+	assert.Equal(t, sdk.MonitoringStatusAlert, status[1].Status)
+
+	// Without usable replica, the primary database is used
+	f := &DBConnectionFactory{Replicas: s}
+	assert.Nil(t, f.GetReadDBMap().Db)
+}
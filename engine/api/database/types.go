@@ -17,4 +17,6 @@ type DBConfiguration struct {
 	MaxConn        int    `toml:"maxconn" default:"20" comment:"DB Max connection" json:"maxconn"`
 	ConnectTimeout int    `toml:"connectTimeout" default:"10" comment:"Maximum wait for connection, in seconds" json:"connectTimeout"`
 	Timeout        int    `toml:"timeout" default:"3000" comment:"Statement timeout value in milliseconds" json:"timeout"`
+	Replicas       string `toml:"replicas" default:"" commented:"true" comment:"Comma separated read replicas (host:port) serving the heavy read-only requests (runs history, queue, search).\n They are connected with the user, password and name of the database" json:"replicas"`
+	ReplicaMaxLag  int    `toml:"replicaMaxLag" default:"10" comment:"Maximum replication lag in seconds, a replica lagging more is not used until it catches up" json:"replicaMaxLag"`
 }
@@ -0,0 +1,184 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+
+	"github.com/ovh/cds/engine/api/organization"
+	"github.com/ovh/cds/engine/api/permission"
+	"github.com/ovh/cds/engine/api/project"
+	"github.com/ovh/cds/engine/api/workflow"
+	"github.com/ovh/cds/engine/service"
+	"github.com/ovh/cds/sdk"
+)
+
+// getProjectDeploymentsHandler returns the timeline of the deployments of a project, the most recent first, optionally
+// filtered on an application and an environment
+func (api *API) getProjectDeploymentsHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		key := mux.Vars(r)[permProjectKey]
+		p, err := project.Load(api.mustDB(), api.Cache, key, deprecatedGetUser(ctx))
+		if err != nil {
+			return sdk.WrapError(err, "cannot load project %s", key)
+		}
+
+		limit := 50
+		if s := r.FormValue("limit"); s != "" {
+			limit, err = strconv.Atoi(s)
+			if err != nil || limit <= 0 || limit > 500 {
+				return sdk.NewErrorFrom(sdk.ErrWrongRequest, "invalid limit %s, expected a number between 1 and 500", s)
+			}
+		}
+
+		ds, err := workflow.LoadDeployments(api.mustDB(), p.ID, r.FormValue("application"), r.FormValue("environment"), limit)
+		if err != nil {
+			return err
+		}
+		return service.WriteJSON(w, ds, http.StatusOK)
+	}
+}
+
+// getProjectCurrentDeploymentsHandler returns the version currently deployed of each application on each environment
+// of a project, that is its last successful deployment
+func (api *API) getProjectCurrentDeploymentsHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		key := mux.Vars(r)[permProjectKey]
+		p, err := project.Load(api.mustDB(), api.Cache, key, deprecatedGetUser(ctx))
+		if err != nil {
+			return sdk.WrapError(err, "cannot load project %s", key)
+		}
+
+		ds, err := workflow.LoadCurrentDeployments(api.mustDB(), p.ID, r.FormValue("environment"))
+		if err != nil {
+			return err
+		}
+		return service.WriteJSON(w, ds, http.StatusOK)
+	}
+}
+
+// postProjectDeploymentRollbackHandler redeploys a previous successful deployment: its workflow node is run again in
+// its workflow run, with the artifacts of the run, without rebuilding them. The new deployment is recorded as a
+// rollback in the registry.
+func (api *API) postProjectDeploymentRollbackHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		key := mux.Vars(r)[permProjectKey]
+		u := deprecatedGetUser(ctx)
+
+		var req sdk.DeploymentRollbackRequest
+		if err := service.UnmarshalBody(r, &req); err != nil {
+			return err
+		}
+
+		p, err := project.Load(api.mustDB(), api.Cache, key, u,
+			project.LoadOptions.WithVariables,
+			project.LoadOptions.WithFeatures,
+			project.LoadOptions.WithIntegrations,
+			project.LoadOptions.WithApplicationVariables,
+			project.LoadOptions.WithApplicationWithDeploymentStrategies,
+			project.LoadOptions.WithEnvironments,
+			project.LoadOptions.WithPipelines,
+		)
+		if err != nil {
+			return sdk.WrapError(err, "cannot load project %s", key)
+		}
+		if err := organization.CheckBuildQuota(api.mustDB(), p.OrganizationID); err != nil {
+			return err
+		}
+
+		var d *sdk.Deployment
+		if req.DeploymentID != 0 {
+			d, err = workflow.LoadDeployment(api.mustDB(), p.ID, req.DeploymentID)
+			if err != nil {
+				return err
+			}
+			if d.Status != sdk.StatusSuccess.String() {
+				return sdk.NewErrorFrom(sdk.ErrWrongRequest, "deployment %d is %s, only a successful deployment can be redeployed", d.ID, d.Status)
+			}
+		} else {
+			if req.ApplicationName == "" || req.EnvironmentName == "" {
+				return sdk.NewErrorFrom(sdk.ErrWrongRequest, "a deployment id, or an application and an environment are required")
+			}
+			ds, err := workflow.LoadDeployments(api.mustDB(), p.ID, req.ApplicationName, req.EnvironmentName, 500)
+			if err != nil {
+				return err
+			}
+			if d, err = sdk.RollbackTarget(ds, req.Version); err != nil {
+				return err
+			}
+		}
+
+		wr, err := workflow.LoadRunByIDAndProjectKey(api.mustDB(), key, d.WorkflowRunID, workflow.LoadRunOptions{WithArtifacts: true})
+		if err != nil {
+			return sdk.WrapError(err, "cannot load workflow run %d", d.WorkflowRunID)
+		}
+		if !sdk.StatusIsTerminated(wr.Status) {
+			return sdk.NewErrorFrom(sdk.ErrWrongRequest, "workflow run %s #%d is %s", wr.Workflow.Name, wr.Number, wr.Status)
+		}
+		if err := workflow.MigrateWorkflowRun(ctx, api.mustDB(), wr); err != nil {
+			return sdk.WrapError(err, "unable to migrate workflow run")
+		}
+		wf := &wr.Workflow
+
+		var node *sdk.Node
+		for _, nrs := range wr.WorkflowNodeRuns {
+			for _, nr := range nrs {
+				if nr.ID == d.WorkflowNodeRunID {
+					node = wf.WorkflowData.NodeByID(nr.WorkflowNodeID)
+				}
+			}
+		}
+		if node == nil {
+			return sdk.WrapError(sdk.ErrWorkflowNodeNotFound, "unable to find the node of node run %d", d.WorkflowNodeRunID)
+		}
+		if !permission.AccessToWorkflowNode(wf, node, u, permission.PermissionReadExecute) {
+			return sdk.WrapError(sdk.ErrNoPermExecution, "not enough right on node %d", node.ID)
+		}
+		artifacts := sdk.DeploymentArtifacts(*wr)
+
+		tx, err := api.mustDB().Begin()
+		if err != nil {
+			return sdk.WithStack(err)
+		}
+		defer tx.Rollback() // nolint
+
+		newRun, report, err := workflow.ManualRunFromNode(ctx, tx, api.Cache, p, wf, wr.Number, &sdk.WorkflowNodeRunManual{User: *u}, node.ID)
+		if err != nil {
+			return sdk.WrapError(err, "unable to run node %s of workflow run %s #%d", node.Name, wf.Name, wr.Number)
+		}
+		nrs := newRun.WorkflowNodeRuns[node.ID]
+		if len(nrs) == 0 {
+			return sdk.WrapError(sdk.ErrWorkflowNodeNotFound, "unable to find the run of node %s", node.Name)
+		}
+
+		rb := sdk.DeploymentRollback{
+			ProjectID:         p.ID,
+			DeploymentID:      d.ID,
+			ApplicationName:   d.ApplicationName,
+			EnvironmentName:   d.EnvironmentName,
+			Version:           d.Version,
+			WorkflowName:      wf.Name,
+			Number:            nrs[0].Number,
+			SubNumber:         nrs[0].SubNumber,
+			WorkflowNodeRunID: nrs[0].ID,
+			Artifacts:         artifacts,
+			Author:            u.Username,
+		}
+		if err := workflow.InsertDeploymentRollback(tx, &rb); err != nil {
+			return err
+		}
+		if err := workflow.StoreEvents(tx, p.Key, report); err != nil {
+			return err
+		}
+		if err := tx.Commit(); err != nil {
+			return sdk.WithStack(err)
+		}
+
+		workflow.ResyncNodeRunsWithCommits(api.mustDB(), api.Cache, p, report)
+		go workflow.SendEvent(api.mustDB(), p.Key, report)
+
+		return service.WriteJSON(w, rb, http.StatusAccepted)
+	}
+}
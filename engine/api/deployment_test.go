@@ -0,0 +1,81 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/engine/api/application"
+	"github.com/ovh/cds/engine/api/environment"
+	"github.com/ovh/cds/engine/api/test"
+	"github.com/ovh/cds/engine/api/test/assets"
+	"github.com/ovh/cds/sdk"
+)
+
+func Test_getProjectDeploymentsHandler(t *testing.T) {
+	api, db, router, end := newTestAPI(t)
+	defer end()
+
+	u, pass := assets.InsertAdminUser(api.mustDB())
+	key := sdk.RandomString(10)
+	proj := assets.InsertTestProject(t, db, api.Cache, key, key, u)
+	app := &sdk.Application{Name: "app"}
+	test.NoError(t, application.Insert(api.mustDB(), api.Cache, proj, app, u))
+	env := sdk.Environment{Name: "production", ProjectID: proj.ID}
+	test.NoError(t, environment.InsertEnvironment(api.mustDB(), &env))
+
+	now := time.Now()
+	for i, d := range []struct {
+		version, status string
+		done            time.Time
+	}{
+		{"1.0.0", sdk.StatusSuccess.String(), now.Add(-2 * time.Hour)},
+		{"1.1.0", sdk.StatusSuccess.String(), now.Add(-time.Hour)},
+		{"1.2.0", sdk.StatusFail.String(), now},
+	} {
+		_, err := db.Exec(`INSERT INTO deployment (project_id, application_id, environment_id, workflow_id, workflow_run_id, workflow_node_run_id, status, done, version)
+		VALUES ($1, $2, $3, 0, 0, $4, $5, $6, $7)`, proj.ID, app.ID, env.ID, proj.ID*10+int64(i), d.status, d.done, d.version)
+		test.NoError(t, err)
+	}
+
+	uri := router.GetRoute("GET", api.getProjectDeploymentsHandler, map[string]string{"permProjectKey": proj.Key})
+	test.NotEmpty(t, uri)
+	w := httptest.NewRecorder()
+	router.Mux.ServeHTTP(w, assets.NewAuthentifiedRequest(t, u, pass, "GET", uri+"?environment=production&limit=2", nil))
+	assert.Equal(t, 200, w.Code)
+	var ds []sdk.Deployment
+	test.NoError(t, json.Unmarshal(w.Body.Bytes(), &ds))
+	if assert.Len(t, ds, 2) {
+		assert.Equal(t, "1.2.0", ds[0].Version)
+		assert.Equal(t, "1.1.0", ds[1].Version)
+		assert.Equal(t, "app", ds[0].ApplicationName)
+	}
+
+	uri = router.GetRoute("GET", api.getProjectCurrentDeploymentsHandler, map[string]string{"permProjectKey": proj.Key})
+	test.NotEmpty(t, uri)
+	w = httptest.NewRecorder()
+	router.Mux.ServeHTTP(w, assets.NewAuthentifiedRequest(t, u, pass, "GET", uri, nil))
+	assert.Equal(t, 200, w.Code)
+	ds = nil
+	test.NoError(t, json.Unmarshal(w.Body.Bytes(), &ds))
+	if assert.Len(t, ds, 1) {
+		assert.Equal(t, "1.1.0", ds[0].Version)
+		assert.Equal(t, "production", ds[0].EnvironmentName)
+	}
+
+	// the version 1.2.0 has no successful deployment to redeploy
+	uri = router.GetRoute("POST", api.postProjectDeploymentRollbackHandler, map[string]string{"permProjectKey": proj.Key})
+	test.NotEmpty(t, uri)
+	w = httptest.NewRecorder()
+	router.Mux.ServeHTTP(w, assets.NewAuthentifiedRequest(t, u, pass, "POST", uri, sdk.DeploymentRollbackRequest{
+		ApplicationName: "app", EnvironmentName: "production", Version: "1.2.0",
+	}))
+	assert.Equal(t, 404, w.Code)
+
+	w = httptest.NewRecorder()
+	router.Mux.ServeHTTP(w, assets.NewAuthentifiedRequest(t, u, pass, "POST", uri, sdk.DeploymentRollbackRequest{}))
+	assert.Equal(t, 400, w.Code)
+}
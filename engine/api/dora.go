@@ -0,0 +1,39 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/ovh/cds/engine/api/project"
+	"github.com/ovh/cds/engine/api/workflow"
+	"github.com/ovh/cds/engine/service"
+	"github.com/ovh/cds/sdk"
+)
+
+// getProjectDORAMetricsHandler returns the deployment frequency, the lead time for changes, the change failure rate
+// and the time to restore of each application and environment of a project, over the last 30 days by default
+func (api *API) getProjectDORAMetricsHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		key := mux.Vars(r)[permProjectKey]
+		p, err := project.Load(api.mustDB(), api.Cache, key, deprecatedGetUser(ctx))
+		if err != nil {
+			return sdk.WrapError(err, "cannot load project %s", key)
+		}
+
+		now := time.Now().UTC()
+		today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+		from, to, err := parsePeriod(r, today.AddDate(0, 0, -29), today.AddDate(0, 0, 1))
+		if err != nil {
+			return err
+		}
+
+		metrics, err := workflow.LoadDORAMetrics(api.mustDB(), p.ID, r.FormValue("application"), r.FormValue("environment"), from, to)
+		if err != nil {
+			return err
+		}
+		return service.WriteJSON(w, metrics, http.StatusOK)
+	}
+}
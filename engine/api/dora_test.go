@@ -0,0 +1,36 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/engine/api/test"
+	"github.com/ovh/cds/engine/api/test/assets"
+	"github.com/ovh/cds/sdk"
+)
+
+func Test_getProjectDORAMetricsHandler(t *testing.T) {
+	api, db, router, end := newTestAPI(t)
+	defer end()
+
+	u, pass := assets.InsertAdminUser(api.mustDB())
+	key := sdk.RandomString(10)
+	proj := assets.InsertTestProject(t, db, api.Cache, key, key, u)
+
+	uri := router.GetRoute("GET", api.getProjectDORAMetricsHandler, map[string]string{"permProjectKey": proj.Key})
+	test.NotEmpty(t, uri)
+	w := httptest.NewRecorder()
+	router.Mux.ServeHTTP(w, assets.NewAuthentifiedRequest(t, u, pass, "GET", uri+"?from=2019-01-01&to=2019-01-31", nil))
+	assert.Equal(t, 200, w.Code)
+
+	var metrics []sdk.DORAMetrics
+	test.NoError(t, json.Unmarshal(w.Body.Bytes(), &metrics))
+	assert.Empty(t, metrics)
+
+	w = httptest.NewRecorder()
+	router.Mux.ServeHTTP(w, assets.NewAuthentifiedRequest(t, u, pass, "GET", uri+"?from=2019-02-01&to=2019-01-31", nil))
+	assert.Equal(t, 400, w.Code)
+}
@@ -4,7 +4,10 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"os"
 	"path"
+	"sync"
+	"time"
 
 	"github.com/gorilla/mux"
 
@@ -39,3 +42,61 @@ func (api *API) downloadHandler() service.Handler {
 		return nil
 	}
 }
+
+// downloadChecksumHandler returns the checksum of a binary. It is requested separately from the binary, the clients
+// check the binary against it before installing it.
+func (api *API) downloadChecksumHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		name := vars["name"]
+		os := vars["os"]
+
+		arch, extension, err := sdk.IsBinaryOSArchValid(api.Config.Directories.Download, name, os, vars["arch"])
+		if err != nil {
+			return err
+		}
+
+		path := path.Join(api.Config.Directories.Download, fmt.Sprintf("%s%s-%s-%s%s", sdk.DownloadGetPrefix(name), name, os, arch, extension))
+		checksum, err := downloadChecksums.get(path)
+		if err != nil {
+			return sdk.NewErrorFrom(sdk.ErrNotFound, "cannot compute checksum of %s %s %s", name, os, arch)
+		}
+
+		w.Header().Set("Cache-Control", "no-store")
+		return service.WriteJSON(w, sdk.DownloadChecksum{SHA512: checksum}, http.StatusOK)
+	}
+}
+
+// downloadChecksums keeps the checksums of the binaries of the download directory, they are computed again when a
+// binary is replaced
+var downloadChecksums = checksumCache{checksums: map[string]fileChecksum{}}
+
+type fileChecksum struct {
+	modTime  time.Time
+	size     int64
+	checksum string
+}
+
+type checksumCache struct {
+	mutex     sync.Mutex
+	checksums map[string]fileChecksum
+}
+
+func (c *checksumCache) get(filename string) (string, error) {
+	fi, err := os.Stat(filename)
+	if err != nil {
+		return "", sdk.WithStack(err)
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if f, has := c.checksums[filename]; has && f.modTime.Equal(fi.ModTime()) && f.size == fi.Size() {
+		return f.checksum, nil
+	}
+	checksum, err := sdk.FileSHA512(filename)
+	if err != nil {
+		return "", err
+	}
+	c.checksums[filename] = fileChecksum{modTime: fi.ModTime(), size: fi.Size(), checksum: checksum}
+	return checksum, nil
+}
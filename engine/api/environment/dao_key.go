@@ -17,7 +17,11 @@ import (
 func InsertKey(db gorp.SqlExecutor, key *sdk.EnvironmentKey) error {
 	dbEnvironmentKey := dbEnvironmentKey(*key)
 
-	s, errE := secret.Encrypt([]byte(key.Private))
+	projectID, err := loadProjectID(db, key.EnvironmentID)
+	if err != nil {
+		return err
+	}
+	s, errE := secret.EncryptForProject(db, projectID, []byte(key.Private))
 	if errE != nil {
 		return sdk.WrapError(errE, "InsertKey> Cannot encrypt private key")
 	}
@@ -108,11 +112,16 @@ func LoadAllDecryptedKeys(db gorp.SqlExecutor, env *sdk.Environment) error {
 		return sdk.WrapError(err, "Cannot load keys")
 	}
 
+	projectID, err := loadProjectID(db, env.ID)
+	if err != nil {
+		return err
+	}
+
 	keys := make([]sdk.EnvironmentKey, len(res))
 	for i := range res {
 		p := res[i]
 		keys[i] = sdk.EnvironmentKey(p)
-		decrypted, err := secret.Decrypt([]byte(keys[i].Private))
+		decrypted, err := secret.DecryptForProject(projectID, []byte(keys[i].Private))
 		if err != nil {
 			log.Error("LoadAllDecryptedKeys> Unable to decrypt private key %s/%s: %v", env.Name, keys[i].Name, err)
 		}
@@ -127,3 +136,12 @@ func DeleteEnvironmentKey(db gorp.SqlExecutor, envID int64, keyName string) erro
 	_, err := db.Exec("DELETE FROM environment_key WHERE environment_id = $1 AND name = $2", envID, keyName)
 	return sdk.WrapError(err, "Cannot delete key %s", keyName)
 }
+
+// loadProjectID returns the id of the project of the environment
+func loadProjectID(db gorp.SqlExecutor, envID int64) (int64, error) {
+	id, err := db.SelectInt("SELECT project_id FROM environment WHERE id = $1", envID)
+	if err != nil {
+		return 0, sdk.WrapError(err, "cannot load project id of environment %d", envID)
+	}
+	return id, nil
+}
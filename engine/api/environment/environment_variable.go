@@ -65,12 +65,15 @@ func GetVariableByID(db gorp.SqlExecutor, envID int64, varID int64, args ...GetA
 		f(&c)
 	}
 
+	var projectID int64
+
 	query := `SELECT environment_variable.id, environment_variable.name, environment_variable.value,
-						environment_variable.cipher_value, environment_variable.type
+						environment_variable.cipher_value, environment_variable.type, environment.project_id
 	          FROM environment_variable
-	          WHERE environment_id = $1 AND id = $2
+	          JOIN environment ON environment.id = environment_variable.environment_id
+	          WHERE environment_id = $1 AND environment_variable.id = $2
 	          ORDER BY name`
-	if err := db.QueryRow(query, envID, varID).Scan(&v.ID, &v.Name, &clearVal, &cipherVal, &v.Type); err != nil {
+	if err := db.QueryRow(query, envID, varID).Scan(&v.ID, &v.Name, &clearVal, &cipherVal, &v.Type, &projectID); err != nil {
 		return v, sdk.WrapError(err, "Cannot get variable %d", varID)
 	}
 
@@ -78,7 +81,7 @@ func GetVariableByID(db gorp.SqlExecutor, envID int64, varID int64, args ...GetA
 		v.Value = string(cipherVal)
 	} else {
 		var errDecrypt error
-		v.Value, errDecrypt = secret.DecryptS(v.Type, clearVal, cipherVal, c.clearsecret)
+		v.Value, errDecrypt = secret.DecryptSForProject(projectID, v.Type, clearVal, cipherVal, c.clearsecret)
 		if errDecrypt != nil {
 			return v, sdk.WrapError(errDecrypt, "GetVariableByID> Cannot decrypt secret %s", v.Name)
 		}
@@ -92,6 +95,7 @@ func GetVariable(db gorp.SqlExecutor, key, envName string, varName string, args
 	var clearVal sql.NullString
 	var cipherVal []byte
 	var typeVar string
+	var projectID int64
 
 	c := structarg{}
 	for _, f := range args {
@@ -99,13 +103,13 @@ func GetVariable(db gorp.SqlExecutor, key, envName string, varName string, args
 	}
 
 	query := `SELECT environment_variable.id, environment_variable.name, environment_variable.value,
-						environment_variable.cipher_value, environment_variable.type
+						environment_variable.cipher_value, environment_variable.type, environment.project_id
 	          FROM environment_variable
 	          JOIN environment ON environment.id = environment_variable.environment_id
 	          JOIN project ON project.id = environment.project_id
 	          WHERE environment.name = $1 AND project.projectKey = $2 AND environment_variable.name = $3
 	          ORDER BY name`
-	if err := db.QueryRow(query, envName, key, varName).Scan(&v.ID, &v.Name, &clearVal, &cipherVal, &typeVar); err != nil {
+	if err := db.QueryRow(query, envName, key, varName).Scan(&v.ID, &v.Name, &clearVal, &cipherVal, &typeVar, &projectID); err != nil {
 		return nil, err
 	}
 
@@ -115,7 +119,7 @@ func GetVariable(db gorp.SqlExecutor, key, envName string, varName string, args
 		v.Value = string(cipherVal)
 	} else {
 		var errDecrypt error
-		v.Value, errDecrypt = secret.DecryptS(v.Type, clearVal, cipherVal, c.clearsecret)
+		v.Value, errDecrypt = secret.DecryptSForProject(projectID, v.Type, clearVal, cipherVal, c.clearsecret)
 		if errDecrypt != nil {
 			return nil, errDecrypt
 		}
@@ -132,7 +136,7 @@ func GetAllVariable(db gorp.SqlExecutor, key, envName string, args ...GetAllVari
 
 	variables := []sdk.Variable{}
 	query := `SELECT environment_variable.id, environment_variable.name, environment_variable.value,
-						environment_variable.cipher_value, environment_variable.type
+						environment_variable.cipher_value, environment_variable.type, environment.project_id
 	          FROM environment_variable
 	          JOIN environment ON environment.id = environment_variable.environment_id
 	          JOIN project ON project.id = environment.project_id
@@ -148,7 +152,8 @@ func GetAllVariable(db gorp.SqlExecutor, key, envName string, args ...GetAllVari
 		var typeVar string
 		var clearVal sql.NullString
 		var cipherVal []byte
-		err = rows.Scan(&v.ID, &v.Name, &clearVal, &cipherVal, &typeVar)
+		var projectID int64
+		err = rows.Scan(&v.ID, &v.Name, &clearVal, &cipherVal, &typeVar, &projectID)
 		if err != nil {
 			return nil, err
 		}
@@ -157,7 +162,7 @@ func GetAllVariable(db gorp.SqlExecutor, key, envName string, args ...GetAllVari
 		if c.encryptsecret && sdk.NeedPlaceholder(v.Type) {
 			v.Value = string(cipherVal)
 		} else {
-			v.Value, err = secret.DecryptS(v.Type, clearVal, cipherVal, c.clearsecret)
+			v.Value, err = secret.DecryptSForProject(projectID, v.Type, clearVal, cipherVal, c.clearsecret)
 			if err != nil {
 				return nil, err
 			}
@@ -175,8 +180,9 @@ func GetAllVariableByID(db gorp.SqlExecutor, environmentID int64, args ...GetAll
 	}
 	variables := []sdk.Variable{}
 	query := `SELECT environment_variable.id, environment_variable.name, environment_variable.value,
-						environment_variable.cipher_value, environment_variable.type
+						environment_variable.cipher_value, environment_variable.type, environment.project_id
 	          FROM environment_variable
+	          JOIN environment ON environment.id = environment_variable.environment_id
 	          WHERE environment_variable.environment_id = $1
 	          ORDER BY name`
 	rows, err := db.Query(query, environmentID)
@@ -189,12 +195,13 @@ func GetAllVariableByID(db gorp.SqlExecutor, environmentID int64, args ...GetAll
 		var typeVar string
 		var clearVal sql.NullString
 		var cipherVal []byte
-		err = rows.Scan(&v.ID, &v.Name, &clearVal, &cipherVal, &typeVar)
+		var projectID int64
+		err = rows.Scan(&v.ID, &v.Name, &clearVal, &cipherVal, &typeVar, &projectID)
 		if err != nil {
 			return nil, err
 		}
 		v.Type = typeVar
-		v.Value, err = secret.DecryptS(v.Type, clearVal, cipherVal, c.clearsecret)
+		v.Value, err = secret.DecryptSForProject(projectID, v.Type, clearVal, cipherVal, c.clearsecret)
 		if err != nil {
 			return nil, err
 		}
@@ -214,7 +221,11 @@ func InsertVariable(db gorp.SqlExecutor, environmentID int64, variable *sdk.Vari
 		return sdk.NewError(sdk.ErrInvalidName, fmt.Errorf("Invalid variable name. It should match %s", sdk.NamePattern))
 	}
 
-	clear, cipher, err := secret.EncryptS(variable.Type, variable.Value)
+	projectID, err := loadProjectID(db, environmentID)
+	if err != nil {
+		return err
+	}
+	clear, cipher, err := secret.EncryptSForProject(db, projectID, variable.Type, variable.Value)
 	if err != nil {
 		return sdk.WrapError(err, "Cannot encrypt secret %s", variable.Name)
 	}
@@ -255,7 +266,11 @@ func UpdateVariable(db gorp.SqlExecutor, envID int64, variable *sdk.Variable, va
 		varValue = varBefore.Value
 	}
 
-	clear, cipher, err := secret.EncryptS(variable.Type, varValue)
+	projectID, err := loadProjectID(db, envID)
+	if err != nil {
+		return err
+	}
+	clear, cipher, err := secret.EncryptSForProject(db, projectID, variable.Type, varValue)
 	if err != nil {
 		return sdk.WrapError(err, "Cannot encrypt secret")
 	}
@@ -0,0 +1,93 @@
+package environment
+
+import (
+	"time"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// InsertFreeze freezes an environment
+func InsertFreeze(db gorp.SqlExecutor, f *sdk.EnvironmentFreeze) error {
+	f.Created = time.Now()
+	dbf := dbEnvironmentFreeze(*f)
+	if err := db.Insert(&dbf); err != nil {
+		return sdk.WrapError(err, "cannot insert freeze of environment %d", f.EnvironmentID)
+	}
+	*f = sdk.EnvironmentFreeze(dbf)
+	return nil
+}
+
+// DeleteFreeze removes a freeze of an environment
+func DeleteFreeze(db gorp.SqlExecutor, environmentID, id int64) error {
+	res, err := db.Exec("DELETE FROM environment_freeze WHERE environment_id = $1 AND id = $2", environmentID, id)
+	if err != nil {
+		return sdk.WrapError(err, "cannot delete freeze %d of environment %d", id, environmentID)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return sdk.WithStack(sdk.ErrNotFound)
+	}
+	return nil
+}
+
+func loadFreezes(db gorp.SqlExecutor, query string, args ...interface{}) ([]sdk.EnvironmentFreeze, error) {
+	var res []struct {
+		dbEnvironmentFreeze
+		EnvironmentName string `db:"environment_name"`
+	}
+	if _, err := db.Select(&res, query, args...); err != nil {
+		return nil, sdk.WrapError(err, "cannot load environment freezes")
+	}
+	fs := make([]sdk.EnvironmentFreeze, len(res))
+	for i := range res {
+		fs[i] = sdk.EnvironmentFreeze(res[i].dbEnvironmentFreeze)
+		fs[i].EnvironmentName = res[i].EnvironmentName
+	}
+	return fs, nil
+}
+
+// LoadFreezes returns the freezes of an environment
+func LoadFreezes(db gorp.SqlExecutor, environmentID int64) ([]sdk.EnvironmentFreeze, error) {
+	return loadFreezes(db, `SELECT environment_freeze.*, environment.name AS environment_name
+	FROM environment_freeze
+	JOIN environment ON environment.id = environment_freeze.environment_id
+	WHERE environment_freeze.environment_id = $1
+	ORDER BY environment_freeze.id`, environmentID)
+}
+
+// LoadAllFreezes returns the freezes of all the environments, the expired windows aside
+func LoadAllFreezes(db gorp.SqlExecutor) ([]sdk.EnvironmentFreeze, error) {
+	return loadFreezes(db, `SELECT environment_freeze.*, environment.name AS environment_name
+	FROM environment_freeze
+	JOIN environment ON environment.id = environment_freeze.environment_id
+	WHERE environment_freeze.to_date IS NULL OR environment_freeze.to_date > $1
+	ORDER BY environment_freeze.id`, time.Now())
+}
+
+// ActiveFreeze returns the freeze currently blocking the deployments on an environment, if any
+func ActiveFreeze(db gorp.SqlExecutor, environmentID int64) (*sdk.EnvironmentFreeze, error) {
+	fs, err := LoadFreezes(db, environmentID)
+	if err != nil {
+		return nil, err
+	}
+	return sdk.ActiveEnvironmentFreeze(fs, time.Now()), nil
+}
+
+// InsertFreezeOverride lets the jobs of a node run start although their environment is frozen
+func InsertFreezeOverride(db gorp.SqlExecutor, nodeRunID int64, author string) error {
+	if _, err := db.Exec(`INSERT INTO environment_freeze_override (workflow_node_run_id, author) VALUES ($1, $2)
+	ON CONFLICT (workflow_node_run_id) DO NOTHING`, nodeRunID, author); err != nil {
+		return sdk.WrapError(err, "cannot override environment freeze for node run %d", nodeRunID)
+	}
+	return nil
+}
+
+// IsFreezeOverridden returns whether the jobs of a node run can start although their environment is frozen
+func IsFreezeOverridden(db gorp.SqlExecutor, nodeRunID int64) (bool, error) {
+	n, err := db.SelectInt("SELECT COUNT(1) FROM environment_freeze_override WHERE workflow_node_run_id = $1", nodeRunID)
+	if err != nil {
+		return false, sdk.WrapError(err, "cannot load environment freeze override of node run %d", nodeRunID)
+	}
+	return n > 0, nil
+}
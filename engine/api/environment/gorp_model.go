@@ -14,10 +14,12 @@ import (
 
 type dbEnvironmentVariableAudit sdk.EnvironmentVariableAudit
 type dbEnvironmentKey sdk.EnvironmentKey
+type dbEnvironmentFreeze sdk.EnvironmentFreeze
 
 func init() {
 	gorpmapping.Register(gorpmapping.New(dbEnvironmentVariableAudit{}, "environment_variable_audit", true, "id"))
 	gorpmapping.Register(gorpmapping.New(dbEnvironmentKey{}, "environment_key", false))
+	gorpmapping.Register(gorpmapping.New(dbEnvironmentFreeze{}, "environment_freeze", true, "id"))
 }
 
 // PostGet is a db hook
@@ -90,9 +92,13 @@ func (eva *dbEnvironmentVariableAudit) PostInsert(db gorp.SqlExecutor) error {
 
 // PreInsert
 func (eva *dbEnvironmentVariableAudit) PreInsert(s gorp.SqlExecutor) error {
+	projectID, err := s.SelectInt("SELECT project_id FROM environment WHERE id = $1", eva.EnvironmentID)
+	if err != nil {
+		return sdk.WrapError(err, "cannot load project of environment %d", eva.EnvironmentID)
+	}
 	if eva.VariableBefore != nil {
 		if sdk.NeedPlaceholder(eva.VariableBefore.Type) {
-			secret, err := secret.Encrypt([]byte(eva.VariableBefore.Value))
+			secret, err := secret.EncryptForProject(s, projectID, []byte(eva.VariableBefore.Value))
 			if err != nil {
 				return err
 			}
@@ -101,8 +107,7 @@ func (eva *dbEnvironmentVariableAudit) PreInsert(s gorp.SqlExecutor) error {
 	}
 	if eva.VariableAfter != nil {
 		if sdk.NeedPlaceholder(eva.VariableAfter.Type) {
-			var err error
-			secret, err := secret.Encrypt([]byte(eva.VariableAfter.Value))
+			secret, err := secret.EncryptForProject(s, projectID, []byte(eva.VariableAfter.Value))
 			if err != nil {
 				return err
 			}
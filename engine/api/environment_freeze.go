@@ -0,0 +1,140 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/ovh/cds/engine/api/environment"
+	"github.com/ovh/cds/engine/api/workflow"
+	"github.com/ovh/cds/engine/service"
+	"github.com/ovh/cds/sdk"
+)
+
+func (api *API) getEnvironmentFreezesHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		key := vars[permProjectKey]
+		envName := vars["environmentName"]
+
+		env, err := environment.LoadEnvironmentByName(api.mustDB(), key, envName)
+		if err != nil {
+			return sdk.WrapError(err, "cannot load environment %s", envName)
+		}
+		fs, err := environment.LoadFreezes(api.mustDB(), env.ID)
+		if err != nil {
+			return err
+		}
+		return service.WriteJSON(w, fs, http.StatusOK)
+	}
+}
+
+// postEnvironmentFreezeHandler locks an environment or adds a freeze window to it, the jobs of the deployment nodes
+// targeting it wait in the queue while it is frozen
+func (api *API) postEnvironmentFreezeHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		key := vars[permProjectKey]
+		envName := vars["environmentName"]
+
+		env, err := environment.LoadEnvironmentByName(api.mustDB(), key, envName)
+		if err != nil {
+			return sdk.WrapError(err, "cannot load environment %s", envName)
+		}
+
+		var f sdk.EnvironmentFreeze
+		if err := service.UnmarshalBody(r, &f); err != nil {
+			return err
+		}
+		if err := f.IsValid(); err != nil {
+			return err
+		}
+		f.ID = 0
+		f.ProjectID = env.ProjectID
+		f.EnvironmentID = env.ID
+		f.EnvironmentName = env.Name
+		f.Author = deprecatedGetUser(ctx).Username
+		if err := environment.InsertFreeze(api.mustDB(), &f); err != nil {
+			return err
+		}
+		return service.WriteJSON(w, f, http.StatusCreated)
+	}
+}
+
+func (api *API) deleteEnvironmentFreezeHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		key := vars[permProjectKey]
+		envName := vars["environmentName"]
+		id, err := requestVarInt(r, "id")
+		if err != nil {
+			return err
+		}
+
+		env, err := environment.LoadEnvironmentByName(api.mustDB(), key, envName)
+		if err != nil {
+			return sdk.WrapError(err, "cannot load environment %s", envName)
+		}
+		if err := environment.DeleteFreeze(api.mustDB(), env.ID, id); err != nil {
+			return err
+		}
+		return service.WriteJSON(w, nil, http.StatusOK)
+	}
+}
+
+// postWorkflowNodeRunFreezeOverrideHandler lets the jobs of a node run start although their environment is frozen
+func (api *API) postWorkflowNodeRunFreezeOverrideHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		key := vars["key"]
+		name := vars["permWorkflowName"]
+		number, err := requestVarInt(r, "number")
+		if err != nil {
+			return err
+		}
+		id, err := requestVarInt(r, "nodeRunID")
+		if err != nil {
+			return err
+		}
+
+		nodeRun, err := workflow.LoadNodeRun(api.mustDB(), key, name, number, id, workflow.LoadRunOptions{})
+		if err != nil {
+			return sdk.WrapError(err, "cannot load node run %d", id)
+		}
+		envName := sdk.ParameterValue(nodeRun.BuildParameters, "cds.environment")
+		if envName == "" {
+			return sdk.NewErrorFrom(sdk.ErrWrongRequest, "the node run does not deploy on an environment")
+		}
+
+		tx, err := api.mustDB().Begin()
+		if err != nil {
+			return sdk.WithStack(err)
+		}
+		defer tx.Rollback() // nolint
+
+		u := deprecatedGetUser(ctx)
+		if err := environment.InsertFreezeOverride(tx, nodeRun.ID, u.Username); err != nil {
+			return err
+		}
+		jobIDs, err := workflow.LoadNodeJobRunIDByNodeRunID(tx, nodeRun.ID)
+		if err != nil {
+			return err
+		}
+		for _, jobID := range jobIDs {
+			infos := []sdk.SpawnInfo{{
+				RemoteTime: time.Now(),
+				Message:    sdk.SpawnMsg{ID: sdk.MsgSpawnInfoEnvironmentUnfrozen.ID, Args: []interface{}{envName, u.Username}},
+			}}
+			if err := workflow.AddSpawnInfosNodeJobRun(tx, jobID, infos); err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Commit(); err != nil {
+			return sdk.WithStack(err)
+		}
+		return service.WriteJSON(w, nil, http.StatusOK)
+	}
+}
@@ -0,0 +1,66 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/engine/api/environment"
+	"github.com/ovh/cds/engine/api/test"
+	"github.com/ovh/cds/engine/api/test/assets"
+	"github.com/ovh/cds/sdk"
+)
+
+func Test_environmentFreezeHandlers(t *testing.T) {
+	api, db, router, end := newTestAPI(t)
+	defer end()
+
+	u, pass := assets.InsertAdminUser(api.mustDB())
+	key := sdk.RandomString(10)
+	proj := assets.InsertTestProject(t, db, api.Cache, key, key, u)
+	env := sdk.Environment{Name: "production", ProjectID: proj.ID}
+	test.NoError(t, environment.InsertEnvironment(api.mustDB(), &env))
+	vars := map[string]string{"permProjectKey": proj.Key, "environmentName": env.Name}
+
+	uri := router.GetRoute("POST", api.postEnvironmentFreezeHandler, vars)
+	test.NotEmpty(t, uri)
+	w := httptest.NewRecorder()
+	router.Mux.ServeHTTP(w, assets.NewAuthentifiedRequest(t, u, pass, "POST", uri, sdk.EnvironmentFreeze{Type: "unknown"}))
+	assert.Equal(t, 400, w.Code)
+
+	w = httptest.NewRecorder()
+	router.Mux.ServeHTTP(w, assets.NewAuthentifiedRequest(t, u, pass, "POST", uri, sdk.EnvironmentFreeze{Type: sdk.EnvironmentFreezeLock, Reason: "incident"}))
+	assert.Equal(t, 201, w.Code)
+	var f sdk.EnvironmentFreeze
+	test.NoError(t, json.Unmarshal(w.Body.Bytes(), &f))
+	assert.NotZero(t, f.ID)
+	assert.Equal(t, u.Username, f.Author)
+
+	active, err := environment.ActiveFreeze(db, env.ID)
+	test.NoError(t, err)
+	if assert.NotNil(t, active) {
+		assert.Equal(t, "incident", active.Reason)
+		assert.Equal(t, "production", active.EnvironmentName)
+	}
+
+	uri = router.GetRoute("GET", api.getEnvironmentFreezesHandler, vars)
+	w = httptest.NewRecorder()
+	router.Mux.ServeHTTP(w, assets.NewAuthentifiedRequest(t, u, pass, "GET", uri, nil))
+	assert.Equal(t, 200, w.Code)
+	var fs []sdk.EnvironmentFreeze
+	test.NoError(t, json.Unmarshal(w.Body.Bytes(), &fs))
+	assert.Len(t, fs, 1)
+
+	vars["id"] = fmt.Sprintf("%d", f.ID)
+	uri = router.GetRoute("DELETE", api.deleteEnvironmentFreezeHandler, vars)
+	w = httptest.NewRecorder()
+	router.Mux.ServeHTTP(w, assets.NewAuthentifiedRequest(t, u, pass, "DELETE", uri, nil))
+	assert.Equal(t, 200, w.Code)
+
+	active, err = environment.ActiveFreeze(db, env.ID)
+	test.NoError(t, err)
+	assert.Nil(t, active)
+}
@@ -87,6 +87,11 @@ func DequeueEvent(c context.Context) {
 			s <- e
 		}
 
+		// The events of the workflow runs are sent to the brokers from the outbox
+		if outboxEnabled() && outboxEventTypes[e.EventType] {
+			continue
+		}
+
 		// Send into external brokers
 		for _, b := range brokers {
 			if err := b.sendEvent(&e); err != nil {
@@ -0,0 +1,10 @@
+package event
+
+import (
+	"github.com/ovh/cds/engine/api/database/gorpmapping"
+	"github.com/ovh/cds/sdk"
+)
+
+func init() {
+	gorpmapping.Register(gorpmapping.New(sdk.EventOutboxEntry{}, "event_outbox", true, "id"))
+}
@@ -0,0 +1,242 @@
+package event
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+const (
+	outboxBatchSize      = 100
+	outboxMaxRetryDelay  = time.Hour
+	outboxPurgeFrequency = time.Hour
+)
+
+// OutboxConfig is the configuration of the delivery of the events saved in the outbox
+type OutboxConfig struct {
+	MaxAttempts int `toml:"maxAttempts" default:"10" comment:"Number of attempts to send an event to the brokers, the event is then kept as dead in the outbox" json:"maxAttempts"`
+	Retention   int `toml:"retention" default:"24" comment:"Number of hours the delivered events are kept in the outbox" json:"retention"`
+}
+
+// outboxEventTypes are the types of the events sent to the brokers from the outbox
+var outboxEventTypes = map[string]bool{
+	fmt.Sprintf("%T", sdk.EventRunWorkflow{}):     true,
+	fmt.Sprintf("%T", sdk.EventRunWorkflowNode{}): true,
+	fmt.Sprintf("%T", sdk.EventRunWorkflowJob{}):  true,
+}
+
+// outboxEnabled returns true if the events of the workflow runs are sent to the brokers from the outbox,
+// nothing is saved in the outbox without broker
+func outboxEnabled() bool {
+	return len(brokers) > 0
+}
+
+// Store saves the events in the outbox, it has to be called in the transaction of the changes reported by
+// the events: they are sent to the brokers at least once if the transaction is committed.
+func Store(db gorp.SqlExecutor, es ...sdk.Event) error {
+	if !outboxEnabled() {
+		return nil
+	}
+	now := time.Now()
+	for _, e := range es {
+		entry := sdk.EventOutboxEntry{
+			Created:     now,
+			EventType:   e.EventType,
+			ProjectKey:  e.ProjectKey,
+			Event:       e,
+			Status:      sdk.EventOutboxStatusPending,
+			NextAttempt: now,
+		}
+		if err := db.Insert(&entry); err != nil {
+			return sdk.WrapError(err, "cannot insert event %s in outbox", e.EventType)
+		}
+	}
+	return nil
+}
+
+// DispatchOutbox sends the pending events of the outbox to the brokers until the context is done. Several
+// API instances can dispatch the outbox at the same time, each event is locked by the instance sending it.
+func DispatchOutbox(c context.Context, DBFunc func() *gorp.DbMap, cfg OutboxConfig) {
+	if !outboxEnabled() {
+		return
+	}
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = 10
+	}
+
+	tick := time.NewTicker(time.Second)
+	defer tick.Stop()
+	lastPurge := time.Now()
+	for {
+		select {
+		case <-c.Done():
+			if c.Err() != nil {
+				log.Error("Exiting event.DispatchOutbox: %v", c.Err())
+			}
+			return
+		case <-tick.C:
+			for {
+				n, err := dispatchOutbox(DBFunc(), cfg.MaxAttempts)
+				if err != nil {
+					log.Error("event.DispatchOutbox> %v", err)
+				}
+				if err != nil || n < outboxBatchSize {
+					break
+				}
+			}
+			if cfg.Retention > 0 && time.Since(lastPurge) > outboxPurgeFrequency {
+				lastPurge = time.Now()
+				if err := purgeOutbox(DBFunc(), time.Duration(cfg.Retention)*time.Hour); err != nil {
+					log.Error("event.DispatchOutbox> %v", err)
+				}
+			}
+		}
+	}
+}
+
+// dispatchOutbox sends a batch of pending events, it returns the number of events processed
+func dispatchOutbox(db *gorp.DbMap, maxAttempts int) (int, error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return 0, sdk.WrapError(err, "cannot start transaction")
+	}
+	defer tx.Rollback() // nolint
+
+	var entries []sdk.EventOutboxEntry
+	if _, err := tx.Select(&entries, `SELECT * FROM event_outbox
+		WHERE status = $1 AND next_attempt <= $2
+		ORDER BY id LIMIT $3 FOR UPDATE SKIP LOCKED`, sdk.EventOutboxStatusPending, time.Now(), outboxBatchSize); err != nil {
+		return 0, sdk.WrapError(err, "cannot load pending events")
+	}
+
+	for i := range entries {
+		entry := &entries[i]
+		entry.Attempts++
+		if err := sendToBrokers(&entry.Event); err != nil {
+			entry.LastError = err.Error()
+			if entry.Attempts >= maxAttempts {
+				entry.Status = sdk.EventOutboxStatusDead
+				log.Error("event.DispatchOutbox> event %d (%s) is dead after %d attempts: %v", entry.ID, entry.EventType, entry.Attempts, err)
+			} else {
+				entry.NextAttempt = time.Now().Add(retryDelay(entry.Attempts))
+			}
+		} else {
+			entry.Status = sdk.EventOutboxStatusDelivered
+			entry.LastError = ""
+		}
+		if _, err := tx.Exec("UPDATE event_outbox SET status = $1, attempts = $2, next_attempt = $3, last_error = $4 WHERE id = $5",
+			entry.Status, entry.Attempts, entry.NextAttempt, entry.LastError, entry.ID); err != nil {
+			return 0, sdk.WrapError(err, "cannot update event %d", entry.ID)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, sdk.WrapError(err, "cannot commit transaction")
+	}
+	return len(entries), nil
+}
+
+// sendToBrokers sends the event to all the brokers, an event acknowledged by a broker is sent again to it
+// if another broker failed
+func sendToBrokers(e *sdk.Event) error {
+	var errs []string
+	for _, b := range brokers {
+		if err := b.sendEvent(e); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("%s", strings.Join(errs, ", "))
+	}
+	return nil
+}
+
+// retryDelay returns the delay before the next attempt, doubled at each attempt
+func retryDelay(attempts int) time.Duration {
+	d := time.Second
+	for i := 1; i < attempts && d < outboxMaxRetryDelay; i++ {
+		d *= 2
+	}
+	if d > outboxMaxRetryDelay {
+		d = outboxMaxRetryDelay
+	}
+	return d
+}
+
+func purgeOutbox(db gorp.SqlExecutor, retention time.Duration) error {
+	_, err := db.Exec("DELETE FROM event_outbox WHERE status = $1 AND created < $2", sdk.EventOutboxStatusDelivered, time.Now().Add(-retention))
+	return sdk.WrapError(err, "cannot purge delivered events")
+}
+
+// LoadOutbox returns the events of the outbox with the given status, oldest first
+func LoadOutbox(db gorp.SqlExecutor, status string, limit int) ([]sdk.EventOutboxEntry, error) {
+	entries := []sdk.EventOutboxEntry{}
+	if _, err := db.Select(&entries, "SELECT * FROM event_outbox WHERE status = $1 ORDER BY id LIMIT $2", status, limit); err != nil {
+		return nil, sdk.WrapError(err, "cannot load events")
+	}
+	return entries, nil
+}
+
+// RetryOutboxEntry sends again a dead event to the brokers
+func RetryOutboxEntry(db gorp.SqlExecutor, id int64) error {
+	res, err := db.Exec("UPDATE event_outbox SET status = $1, attempts = 0, next_attempt = $2, last_error = '' WHERE id = $3 AND status = $4",
+		sdk.EventOutboxStatusPending, time.Now(), id, sdk.EventOutboxStatusDead)
+	if err != nil {
+		return sdk.WrapError(err, "cannot retry event %d", id)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return sdk.WithStack(sdk.ErrNotFound)
+	}
+	return nil
+}
+
+// DeleteOutboxEntry deletes a dead event
+func DeleteOutboxEntry(db gorp.SqlExecutor, id int64) error {
+	res, err := db.Exec("DELETE FROM event_outbox WHERE id = $1 AND status = $2", id, sdk.EventOutboxStatusDead)
+	if err != nil {
+		return sdk.WrapError(err, "cannot delete event %d", id)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return sdk.WithStack(sdk.ErrNotFound)
+	}
+	return nil
+}
+
+// OutboxStatus returns the number of pending and dead events of the outbox
+func OutboxStatus(db gorp.SqlExecutor) sdk.MonitoringStatusLine {
+	line := sdk.MonitoringStatusLine{Component: "Event Outbox", Status: sdk.MonitoringStatusOK}
+	if !outboxEnabled() {
+		line.Value = "disabled"
+		return line
+	}
+
+	var counts []struct {
+		Status string `db:"status"`
+		Count  int64  `db:"count"`
+	}
+	if _, err := db.Select(&counts, "SELECT status, COUNT(id) AS count FROM event_outbox WHERE status <> $1 GROUP BY status", sdk.EventOutboxStatusDelivered); err != nil {
+		line.Value = err.Error()
+		line.Status = sdk.MonitoringStatusAlert
+		return line
+	}
+	var pending, dead int64
+	for _, c := range counts {
+		switch c.Status {
+		case sdk.EventOutboxStatusPending:
+			pending = c.Count
+		case sdk.EventOutboxStatusDead:
+			dead = c.Count
+		}
+	}
+	line.Value = fmt.Sprintf("%d pending, %d dead", pending, dead)
+	if dead > 0 {
+		line.Status = sdk.MonitoringStatusWarn
+	}
+	return line
+}
@@ -0,0 +1,52 @@
+package event
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/sdk"
+)
+
+type fakeBroker struct {
+	err    error
+	events []sdk.Event
+}
+
+func (b *fakeBroker) initialize(options interface{}) (Broker, error) { return b, nil }
+func (b *fakeBroker) status() string                                 { return "fake OK" }
+func (b *fakeBroker) close()                                         {}
+func (b *fakeBroker) sendEvent(e *sdk.Event) error {
+	if b.err != nil {
+		return b.err
+	}
+	b.events = append(b.events, *e)
+	return nil
+}
+
+func TestRetryDelay(t *testing.T) {
+	assert.Equal(t, time.Second, retryDelay(1))
+	assert.Equal(t, 2*time.Second, retryDelay(2))
+	assert.Equal(t, 8*time.Second, retryDelay(4))
+	assert.Equal(t, time.Hour, retryDelay(20))
+}
+
+func TestSendToBrokers(t *testing.T) {
+	defer func(bs []Broker) { brokers = bs }(brokers)
+
+	ok, ko := &fakeBroker{}, &fakeBroker{err: fmt.Errorf("kafka is down")}
+	brokers = []Broker{ok, ko}
+	assert.True(t, outboxEnabled())
+
+	e := NewWorkflowRunEvent(sdk.WorkflowRun{Number: 1, Status: sdk.StatusBuilding.String()}, "KEY")
+	assert.True(t, outboxEventTypes[e.EventType])
+	assert.EqualError(t, sendToBrokers(&e), "kafka is down")
+	assert.Len(t, ok.events, 1)
+
+	ko.err = nil
+	assert.NoError(t, sendToBrokers(&e))
+	assert.Len(t, ok.events, 2)
+	assert.Len(t, ko.events, 1)
+}
@@ -0,0 +1,31 @@
+package event
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/fatih/structs"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// PublishAccessTokenDeny publishes an event for a request denied by the restrictions of an access token
+func PublishAccessTokenDeny(token sdk.AccessToken, reason, remoteAddr string) {
+	payload := sdk.EventAccessTokenDeny{
+		AccessToken: sdk.AccessTokenViolation{
+			ID:         token.ID,
+			Name:       token.Description,
+			Reason:     reason,
+			RemoteAddr: remoteAddr,
+		},
+	}
+	publishEvent(sdk.Event{
+		Timestamp: time.Now(),
+		Hostname:  hostname,
+		CDSName:   cdsname,
+		EventType: fmt.Sprintf("%T", payload),
+		Payload:   structs.Map(payload),
+		Username:  token.User.Username,
+		UserMail:  token.User.Email,
+	})
+}
@@ -28,13 +28,29 @@ func PublishProjectEvent(payload interface{}, key string, u *sdk.User) {
 
 // PublishAddProject publishes an event for the creation of the given project
 func PublishAddProject(p *sdk.Project, u *sdk.User) {
+	PublishProjectEvent(newEventProjectAdd(p), p.Key, u)
+}
+
+// newEventProjectAdd returns the event of the creation of the project, without the values of its secret variables
+// and its private keys
+func newEventProjectAdd(p *sdk.Project) sdk.EventProjectAdd {
 	e := sdk.EventProjectAdd{
-		Variables:   p.Variable,
+		Variables:   make([]sdk.Variable, len(p.Variable)),
 		Permissions: p.ProjectGroups,
-		Keys:        p.Keys,
+		Keys:        make([]sdk.ProjectKey, len(p.Keys)),
 		Metadata:    p.Metadata,
 	}
-	PublishProjectEvent(e, p.Key, u)
+	for i, v := range p.Variable {
+		if sdk.NeedPlaceholder(v.Type) {
+			v.Value = sdk.PasswordPlaceholder
+		}
+		e.Variables[i] = v
+	}
+	for i, k := range p.Keys {
+		k.Private = sdk.PasswordPlaceholder
+		e.Keys[i] = k
+	}
+	return e
 }
 
 // PublishUpdateProject publishes an event for the modification of the project
@@ -0,0 +1,41 @@
+package event
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/fatih/structs"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// publishWorkerModelEvent publish worker model event
+func publishWorkerModelEvent(payload interface{}, u *sdk.User) {
+	event := sdk.Event{
+		Timestamp: time.Now(),
+		Hostname:  hostname,
+		CDSName:   cdsname,
+		EventType: fmt.Sprintf("%T", payload),
+		Payload:   structs.Map(payload),
+	}
+	if u != nil {
+		event.Username = u.Username
+		event.UserMail = u.Email
+	}
+	publishEvent(event)
+}
+
+// PublishWorkerModelAdd publishes an event for the creation of the given worker model
+func PublishWorkerModelAdd(m sdk.Model, u *sdk.User) {
+	publishWorkerModelEvent(sdk.EventWorkerModelAdd{Model: m}, u)
+}
+
+// PublishWorkerModelUpdate publishes an event for the update of the given worker model
+func PublishWorkerModelUpdate(oldModel, newModel sdk.Model, u *sdk.User) {
+	publishWorkerModelEvent(sdk.EventWorkerModelUpdate{OldModel: oldModel, NewModel: newModel}, u)
+}
+
+// PublishWorkerModelDelete publishes an event for the deletion of the given worker model
+func PublishWorkerModelDelete(m sdk.Model, u *sdk.User) {
+	publishWorkerModelEvent(sdk.EventWorkerModelDelete{Model: m}, u)
+}
@@ -12,8 +12,8 @@ import (
 	"github.com/ovh/cds/sdk/log"
 )
 
-func publishRunWorkflow(payload interface{}, key, workflowName, appName, pipName, envName string, num int64, sub int64, status string, tags []sdk.WorkflowRunTag) {
-	event := sdk.Event{
+func newRunWorkflowEvent(payload interface{}, key, workflowName, appName, pipName, envName string, num int64, sub int64, status string, tags []sdk.WorkflowRunTag) sdk.Event {
+	return sdk.Event{
 		Timestamp:         time.Now(),
 		Hostname:          hostname,
 		CDSName:           cdsname,
@@ -29,11 +29,10 @@ func publishRunWorkflow(payload interface{}, key, workflowName, appName, pipName
 		Status:            status,
 		Tags:              tags,
 	}
-	publishEvent(event)
 }
 
-// PublishWorkflowRun publish event on a workflow run
-func PublishWorkflowRun(wr sdk.WorkflowRun, projectKey string) {
+// NewWorkflowRunEvent returns the event on a workflow run
+func NewWorkflowRunEvent(wr sdk.WorkflowRun, projectKey string) sdk.Event {
 	e := sdk.EventRunWorkflow{
 		ID:            wr.ID,
 		Number:        wr.Number,
@@ -43,7 +42,12 @@ func PublishWorkflowRun(wr sdk.WorkflowRun, projectKey string) {
 		LastModified:  wr.LastModified.Unix(),
 		Tags:          wr.Tags,
 	}
-	publishRunWorkflow(e, projectKey, wr.Workflow.Name, "", "", "", wr.Number, wr.LastSubNumber, wr.Status, wr.Tags)
+	return newRunWorkflowEvent(e, projectKey, wr.Workflow.Name, "", "", "", wr.Number, wr.LastSubNumber, wr.Status, wr.Tags)
+}
+
+// PublishWorkflowRun publish event on a workflow run
+func PublishWorkflowRun(wr sdk.WorkflowRun, projectKey string) {
+	publishEvent(NewWorkflowRunEvent(wr, projectKey))
 }
 
 // PublishWorkflowNodeRun publish event on a workflow node run
@@ -53,6 +57,13 @@ func PublishWorkflowNodeRun(db gorp.SqlExecutor, nr sdk.WorkflowNodeRun, w sdk.W
 		Publish(event, nil)
 	}
 
+	if e, ok := NewWorkflowNodeRunEvent(nr, w); ok {
+		publishEvent(e)
+	}
+}
+
+// NewWorkflowNodeRunEvent returns the event on a workflow node run, false if the node is not in the workflow
+func NewWorkflowNodeRunEvent(nr sdk.WorkflowNodeRun, w sdk.Workflow) (sdk.Event, bool) {
 	e := sdk.EventRunWorkflowNode{
 		ID:             nr.ID,
 		Number:         nr.Number,
@@ -88,8 +99,8 @@ func PublishWorkflowNodeRun(db gorp.SqlExecutor, nr sdk.WorkflowNodeRun, w sdk.W
 		// check on workflow data
 		wnode := w.WorkflowData.NodeByID(nr.WorkflowNodeID)
 		if wnode == nil {
-			log.Warning("PublishWorkflowNodeRun> Unable to publish event on node %d", nr.WorkflowNodeID)
-			return
+			log.Warning("NewWorkflowNodeRunEvent> Unable to find node %d", nr.WorkflowNodeID)
+			return sdk.Event{}, false
 		}
 		nodeName = wnode.Name
 		if wnode.Context != nil && wnode.Context.PipelineID != 0 {
@@ -114,6 +125,7 @@ func PublishWorkflowNodeRun(db gorp.SqlExecutor, nr sdk.WorkflowNodeRun, w sdk.W
 	}
 
 	e.NodeName = nodeName
+	w.CommitStatus.Apply(w.ProjectKey, w.Name, &e)
 	var envName string
 	var appName string
 	if app.ID != 0 {
@@ -127,11 +139,16 @@ func PublishWorkflowNodeRun(db gorp.SqlExecutor, nr sdk.WorkflowNodeRun, w sdk.W
 	if sdk.StatusIsTerminated(nr.Status) {
 		e.Done = nr.Done.Unix()
 	}
-	publishRunWorkflow(e, w.ProjectKey, w.Name, appName, pipName, envName, nr.Number, nr.SubNumber, nr.Status, nil)
+	return newRunWorkflowEvent(e, w.ProjectKey, w.Name, appName, pipName, envName, nr.Number, nr.SubNumber, nr.Status, nil), true
 }
 
 // PublishWorkflowNodeJobRun publish a WorkflowNodeJobRun
 func PublishWorkflowNodeJobRun(db gorp.SqlExecutor, pkey, wname string, jr sdk.WorkflowNodeJobRun) {
+	publishEvent(NewWorkflowNodeJobRunEvent(pkey, wname, jr))
+}
+
+// NewWorkflowNodeJobRunEvent returns the event on a WorkflowNodeJobRun
+func NewWorkflowNodeJobRunEvent(pkey, wname string, jr sdk.WorkflowNodeJobRun) sdk.Event {
 	e := sdk.EventRunWorkflowJob{
 		ID:     jr.ID,
 		Status: jr.Status,
@@ -141,5 +158,5 @@ func PublishWorkflowNodeJobRun(db gorp.SqlExecutor, pkey, wname string, jr sdk.W
 	if sdk.StatusIsTerminated(jr.Status) {
 		e.Done = jr.Done.Unix()
 	}
-	publishRunWorkflow(e, pkey, wname, "", "", "", 0, 0, jr.Status, nil)
+	return newRunWorkflowEvent(e, pkey, wname, "", "", "", 0, 0, jr.Status, nil)
 }
@@ -0,0 +1,55 @@
+package api
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/ovh/cds/engine/api/event"
+	"github.com/ovh/cds/engine/service"
+	"github.com/ovh/cds/sdk"
+)
+
+func (api *API) getAdminEventOutboxHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		status := FormString(r, "status")
+		if status == "" {
+			status = sdk.EventOutboxStatusDead
+		}
+		if status != sdk.EventOutboxStatusPending && status != sdk.EventOutboxStatusDead && status != sdk.EventOutboxStatusDelivered {
+			return sdk.NewErrorFrom(sdk.ErrWrongRequest, "invalid status %s", status)
+		}
+		limit, err := FormInt(r, "limit")
+		if err != nil {
+			return err
+		}
+		if limit <= 0 || limit > 1000 {
+			limit = 100
+		}
+
+		entries, err := event.LoadOutbox(api.mustDB(), status, limit)
+		if err != nil {
+			return err
+		}
+		return service.WriteJSON(w, entries, http.StatusOK)
+	}
+}
+
+func (api *API) postAdminEventOutboxRetryHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		id, err := requestVarInt(r, "id")
+		if err != nil {
+			return err
+		}
+		return event.RetryOutboxEntry(api.mustDB(), id)
+	}
+}
+
+func (api *API) deleteAdminEventOutboxHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		id, err := requestVarInt(r, "id")
+		if err != nil {
+			return err
+		}
+		return event.DeleteOutboxEntry(api.mustDB(), id)
+	}
+}
@@ -7,6 +7,8 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -42,6 +44,56 @@ type eventsBroker struct {
 	router           *Router
 	chanAddClient    chan (*eventsBrokerSubscribe)
 	chanRemoveClient chan (string)
+	history          *eventsBrokerHistory
+}
+
+// eventsBrokerMessage is an event sent by the broker, with its id in the stream
+type eventsBrokerMessage struct {
+	ID    int64
+	Event sdk.Event
+}
+
+// eventsBrokerHistory keeps the last events sent by the broker, so that a client reconnecting with
+// the Last-Event-ID header receives the events it missed. Ids are only known by the API instance
+// that sent them, events may be sent twice to a client when it resumes the stream.
+type eventsBrokerHistory struct {
+	mutex    sync.RWMutex
+	lastID   int64
+	size     int
+	messages []eventsBrokerMessage
+}
+
+func newEventsBrokerHistory(size int) *eventsBrokerHistory {
+	return &eventsBrokerHistory{size: size}
+}
+
+// add gives an id to the event and keeps it in the history
+func (h *eventsBrokerHistory) add(e sdk.Event) eventsBrokerMessage {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.lastID++
+	m := eventsBrokerMessage{ID: h.lastID, Event: e}
+	if h.size > 0 {
+		h.messages = append(h.messages, m)
+		if len(h.messages) > h.size {
+			h.messages = h.messages[len(h.messages)-h.size:]
+		}
+	}
+	return m
+}
+
+// since returns the events of the history sent after the given id
+func (h *eventsBrokerHistory) since(id int64) []eventsBrokerMessage {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+	// the id has not been sent by this instance of the API
+	if id >= h.lastID {
+		return nil
+	}
+	i := sort.Search(len(h.messages), func(i int) bool { return h.messages[i].ID > id })
+	res := make([]eventsBrokerMessage, len(h.messages)-i)
+	copy(res, h.messages[i:])
+	return res
 }
 
 var handledEventErrors = []string{
@@ -126,6 +178,7 @@ func (b *eventsBroker) Start(ctx context.Context, panicCallback func(s string) (
 			}
 
 		case receivedEvent := <-b.messages:
+			msg := b.history.add(receivedEvent)
 			for i := range b.clients {
 				c := b.clients[i]
 				if c == nil {
@@ -139,7 +192,7 @@ func (b *eventsBroker) Start(ctx context.Context, panicCallback func(s string) (
 					func(ctx context.Context) {
 						if c.isAlive.IsSet() {
 							log.Debug("send data to %s", c.UUID)
-							if err := c.Send(receivedEvent); err != nil {
+							if err := c.Send(msg); err != nil {
 								b.chanRemoveClient <- c.UUID
 								msg := fmt.Sprintf("%v", err)
 								for _, s := range handledEventErrors {
@@ -187,6 +240,20 @@ func (b *eventsBroker) ServeHTTP() service.Handler {
 			return sdk.WrapError(err, "eventsBroker.Serve Cannot load user permission")
 		}
 
+		// Browsers send the Last-Event-ID header when they reconnect, other clients can use the query param
+		lastEventID := r.Header.Get("Last-Event-ID")
+		if lastEventID == "" {
+			lastEventID = FormString(r, "lastEventID")
+		}
+		var missedMessages []eventsBrokerMessage
+		if lastEventID != "" {
+			id, err := strconv.ParseInt(lastEventID, 10, 64)
+			if err != nil {
+				return sdk.NewErrorFrom(sdk.ErrWrongRequest, "invalid last event id %s", lastEventID)
+			}
+			missedMessages = b.history.since(id)
+		}
+
 		uuid := sdk.UUID()
 		client := &eventsBrokerSubscribe{
 			UUID:    uuid,
@@ -209,6 +276,13 @@ func (b *eventsBroker) ServeHTTP() service.Handler {
 		}
 		f.Flush()
 
+		for _, m := range missedMessages {
+			if err := client.Send(m); err != nil {
+				b.chanRemoveClient <- client.UUID
+				return nil
+			}
+		}
+
 		tick := time.NewTicker(time.Second)
 		defer tick.Stop()
 
@@ -224,7 +298,7 @@ func (b *eventsBroker) ServeHTTP() service.Handler {
 				b.chanRemoveClient <- client.UUID
 				break leave
 			case <-tick.C:
-				_ = client.Send(sdk.Event{})
+				_ = client.Send(eventsBrokerMessage{})
 			}
 		}
 
@@ -282,7 +356,7 @@ func (client *eventsBrokerSubscribe) manageEvent(event sdk.Event) bool {
 }
 
 // Send an event to a client
-func (client *eventsBrokerSubscribe) Send(event sdk.Event) (err error) {
+func (client *eventsBrokerSubscribe) Send(m eventsBrokerMessage) (err error) {
 	client.mutex.Lock()
 	defer client.mutex.Unlock()
 
@@ -303,15 +377,16 @@ func (client *eventsBrokerSubscribe) Send(event sdk.Event) (err error) {
 	}
 
 	var buffer bytes.Buffer
-	if event.EventType != "" {
-		if ok := client.manageEvent(event); !ok {
+	if m.Event.EventType != "" {
+		if ok := client.manageEvent(m.Event); !ok {
 			return nil
 		}
 
-		msg, err := json.Marshal(event)
+		msg, err := json.Marshal(m.Event)
 		if err != nil {
 			return sdk.WrapError(err, "Unable to marshall event")
 		}
+		buffer.WriteString(fmt.Sprintf("id: %d\n", m.ID))
 		buffer.WriteString("data: ")
 		buffer.Write(msg)
 		buffer.WriteString("\n\n")
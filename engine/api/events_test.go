@@ -0,0 +1,36 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func TestEventsBrokerHistory(t *testing.T) {
+	h := newEventsBrokerHistory(3)
+	for i := 0; i < 5; i++ {
+		m := h.add(sdk.Event{EventType: "sdk.EventRunWorkflow"})
+		assert.Equal(t, int64(i+1), m.ID)
+	}
+
+	// Only the last 3 events are kept
+	ms := h.since(0)
+	assert.Len(t, ms, 3)
+	assert.Equal(t, int64(3), ms[0].ID)
+
+	ms = h.since(3)
+	assert.Len(t, ms, 2)
+	assert.Equal(t, int64(4), ms[0].ID)
+	assert.Equal(t, int64(5), ms[1].ID)
+
+	assert.Len(t, h.since(5), 0)
+	// Unknown ids, sent by another instance of the API
+	assert.Len(t, h.since(42), 0)
+
+	// Without history, ids are still given to the events
+	h = newEventsBrokerHistory(0)
+	assert.Equal(t, int64(1), h.add(sdk.Event{}).ID)
+	assert.Len(t, h.since(0), 0)
+}
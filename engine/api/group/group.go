@@ -111,7 +111,7 @@ func LoadGroupByName(db gorp.SqlExecutor, name string) (*sdk.Group, error) {
 
 // LoadUserGroup retrieves all group users from database
 func LoadUserGroup(db gorp.SqlExecutor, group *sdk.Group) error {
-	query := `SELECT "user".username, "user".data, "group_user".group_admin FROM "user"
+	query := `SELECT "user".id, "user".username, "user".data, "group_user".group_admin FROM "user"
 	 		  JOIN group_user ON group_user.user_id = "user".id
 	 		  WHERE group_user.group_id = $1 ORDER BY "user".username ASC`
 
@@ -122,10 +122,11 @@ func LoadUserGroup(db gorp.SqlExecutor, group *sdk.Group) error {
 	defer rows.Close()
 
 	for rows.Next() {
+		var id int64
 		var username string
 		var jsonUser []byte
 		var admin bool
-		if err := rows.Scan(&username, &jsonUser, &admin); err != nil {
+		if err := rows.Scan(&id, &username, &jsonUser, &admin); err != nil {
 			return err
 		}
 
@@ -133,6 +134,7 @@ func LoadUserGroup(db gorp.SqlExecutor, group *sdk.Group) error {
 		if err := json.Unmarshal(jsonUser, u); err != nil {
 			return sdk.WrapError(err, "Error while converting jsonUser")
 		}
+		u.ID = id
 
 		if admin {
 			group.Admins = append(group.Admins, *u)
@@ -160,10 +162,29 @@ func IsGroupAdmin(db gorp.SqlExecutor, groupName string, userID int64) (bool, er
 
 // LoadGroups load all groups from database
 func LoadGroups(db gorp.SqlExecutor) ([]sdk.Group, error) {
+	query := `SELECT * FROM "group" ORDER BY name`
+	return loadGroups(db, query)
+}
+
+// LoadGroupsPage loads the groups from database ordered by name, starting at offset, limit 0 means all groups
+func LoadGroupsPage(db gorp.SqlExecutor, offset, limit int) ([]sdk.Group, error) {
+	query := `SELECT * FROM "group" ORDER BY name OFFSET $1 LIMIT NULLIF($2, 0)`
+	return loadGroups(db, query, offset, limit)
+}
+
+// CountGroups returns the number of groups
+func CountGroups(db gorp.SqlExecutor) (int64, error) {
+	var n int64
+	if err := db.QueryRow(`SELECT count(id) FROM "group"`).Scan(&n); err != nil {
+		return 0, sdk.WithStack(err)
+	}
+	return n, nil
+}
+
+func loadGroups(db gorp.SqlExecutor, query string, args ...interface{}) ([]sdk.Group, error) {
 	groups := []sdk.Group{}
 
-	query := `SELECT * FROM "group" ORDER BY name`
-	rows, err := db.Query(query)
+	rows, err := db.Query(query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,52 @@
+package group
+
+import (
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// LDAPGroup is a CDS group managed by the LDAP groups synchronization
+type LDAPGroup struct {
+	sdk.Group
+	DN      string
+	Deleted bool
+}
+
+// LoadLDAPGroups retrieves all groups managed by the LDAP groups synchronization
+func LoadLDAPGroups(db gorp.SqlExecutor) ([]LDAPGroup, error) {
+	query := `SELECT "group".id, "group".name, group_ldap.dn, group_ldap.deleted FROM "group"
+		JOIN group_ldap ON group_ldap.group_id = "group".id
+		ORDER BY "group".name`
+	rows, err := db.Query(query)
+	if err != nil {
+		return nil, sdk.WithStack(err)
+	}
+	defer rows.Close()
+
+	groups := []LDAPGroup{}
+	for rows.Next() {
+		var g LDAPGroup
+		if err := rows.Scan(&g.ID, &g.Name, &g.DN, &g.Deleted); err != nil {
+			return nil, sdk.WithStack(err)
+		}
+		groups = append(groups, g)
+	}
+	return groups, nil
+}
+
+// UpsertLDAPGroup marks a group as managed by the LDAP groups synchronization
+func UpsertLDAPGroup(db gorp.SqlExecutor, groupID int64, dn string, deleted bool) error {
+	query := `INSERT INTO group_ldap (group_id, dn, deleted) VALUES ($1, $2, $3)
+		ON CONFLICT (group_id) DO UPDATE SET dn = $2, deleted = $3`
+	_, err := db.Exec(query, groupID, dn, deleted)
+	return sdk.WrapError(err, "cannot upsert ldap group %d", groupID)
+}
+
+// DeleteGroupMember removes a member of a group without checking the group admins, the members managed by the LDAP
+// groups synchronization are never group admins
+func DeleteGroupMember(db gorp.SqlExecutor, groupID, userID int64) error {
+	query := `DELETE FROM group_user WHERE group_id = $1 AND user_id = $2 AND group_admin = false`
+	_, err := db.Exec(query, groupID, userID)
+	return sdk.WrapError(err, "cannot delete user %d from group %d", userID, groupID)
+}
@@ -0,0 +1,39 @@
+package group
+
+import (
+	"database/sql"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// LoadMFAPolicy retrieves the second factor policy of a group
+func LoadMFAPolicy(db gorp.SqlExecutor, groupID int64) (sdk.MFAPolicy, error) {
+	var p sdk.MFAPolicy
+	query := `SELECT required FROM group_mfa WHERE group_id = $1`
+	if err := db.QueryRow(query, groupID).Scan(&p.Required); err != nil && err != sql.ErrNoRows {
+		return p, sdk.WrapError(err, "cannot load mfa policy of group %d", groupID)
+	}
+	return p, nil
+}
+
+// UpsertMFAPolicy sets the second factor policy of a group
+func UpsertMFAPolicy(db gorp.SqlExecutor, groupID int64, p sdk.MFAPolicy) error {
+	query := `INSERT INTO group_mfa (group_id, required) VALUES ($1, $2)
+		ON CONFLICT (group_id) DO UPDATE SET required = $2`
+	_, err := db.Exec(query, groupID, p.Required)
+	return sdk.WrapError(err, "cannot upsert mfa policy of group %d", groupID)
+}
+
+// IsMFARequiredForUser returns true if one of the groups of the user requires two-factor authentication
+func IsMFARequiredForUser(db gorp.SqlExecutor, userID int64) (bool, error) {
+	query := `SELECT COUNT(group_mfa.group_id) FROM group_mfa
+		JOIN group_user ON group_user.group_id = group_mfa.group_id
+		WHERE group_user.user_id = $1 AND group_mfa.required = true`
+	n, err := db.SelectInt(query, userID)
+	if err != nil {
+		return false, sdk.WrapError(err, "cannot check mfa policy of user %d", userID)
+	}
+	return n > 0, nil
+}
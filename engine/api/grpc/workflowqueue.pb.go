@@ -9,6 +9,7 @@ import fmt "fmt"
 import math "math"
 import github_com_ovh_cds_sdk "github.com/ovh/cds/sdk"
 import github_com_ovh_cds_sdk1 "github.com/ovh/cds/sdk"
+import github_com_ovh_cds_sdk2 "github.com/ovh/cds/sdk"
 import google_protobuf1 "github.com/golang/protobuf/ptypes/empty"
 
 import (
@@ -34,6 +35,10 @@ const _ = grpc1.SupportPackageIsVersion4
 type WorkflowQueueClient interface {
 	SendLog(ctx context.Context, opts ...grpc1.CallOption) (WorkflowQueue_SendLogClient, error)
 	SendResult(ctx context.Context, in *github_com_ovh_cds_sdk1.Result, opts ...grpc1.CallOption) (*google_protobuf1.Empty, error)
+	BookJob(ctx context.Context, in *github_com_ovh_cds_sdk2.JobBookRequest, opts ...grpc1.CallOption) (*google_protobuf1.Empty, error)
+	SendStepStatus(ctx context.Context, in *github_com_ovh_cds_sdk2.StepStatusUpdate, opts ...grpc1.CallOption) (*google_protobuf1.Empty, error)
+	Heartbeat(ctx context.Context, in *google_protobuf1.Empty, opts ...grpc1.CallOption) (*google_protobuf1.Empty, error)
+	StreamLog(ctx context.Context, opts ...grpc1.CallOption) (WorkflowQueue_StreamLogClient, error)
 }
 
 type workflowQueueClient struct {
@@ -87,11 +92,73 @@ func (c *workflowQueueClient) SendResult(ctx context.Context, in *github_com_ovh
 	return out, nil
 }
 
+func (c *workflowQueueClient) BookJob(ctx context.Context, in *github_com_ovh_cds_sdk2.JobBookRequest, opts ...grpc1.CallOption) (*google_protobuf1.Empty, error) {
+	out := new(google_protobuf1.Empty)
+	err := grpc1.Invoke(ctx, "/grpc.WorkflowQueue/BookJob", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workflowQueueClient) SendStepStatus(ctx context.Context, in *github_com_ovh_cds_sdk2.StepStatusUpdate, opts ...grpc1.CallOption) (*google_protobuf1.Empty, error) {
+	out := new(google_protobuf1.Empty)
+	err := grpc1.Invoke(ctx, "/grpc.WorkflowQueue/SendStepStatus", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workflowQueueClient) Heartbeat(ctx context.Context, in *google_protobuf1.Empty, opts ...grpc1.CallOption) (*google_protobuf1.Empty, error) {
+	out := new(google_protobuf1.Empty)
+	err := grpc1.Invoke(ctx, "/grpc.WorkflowQueue/Heartbeat", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workflowQueueClient) StreamLog(ctx context.Context, opts ...grpc1.CallOption) (WorkflowQueue_StreamLogClient, error) {
+	stream, err := grpc1.NewClientStream(ctx, &_WorkflowQueue_serviceDesc.Streams[1], c.cc, "/grpc.WorkflowQueue/StreamLog", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &workflowQueueStreamLogClient{stream}
+	return x, nil
+}
+
+type WorkflowQueue_StreamLogClient interface {
+	Send(*github_com_ovh_cds_sdk2.LogChunk) error
+	Recv() (*github_com_ovh_cds_sdk2.LogAck, error)
+	grpc1.ClientStream
+}
+
+type workflowQueueStreamLogClient struct {
+	grpc1.ClientStream
+}
+
+func (x *workflowQueueStreamLogClient) Send(m *github_com_ovh_cds_sdk2.LogChunk) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *workflowQueueStreamLogClient) Recv() (*github_com_ovh_cds_sdk2.LogAck, error) {
+	m := new(github_com_ovh_cds_sdk2.LogAck)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // Server API for WorkflowQueue service
 
 type WorkflowQueueServer interface {
 	SendLog(WorkflowQueue_SendLogServer) error
 	SendResult(context.Context, *github_com_ovh_cds_sdk1.Result) (*google_protobuf1.Empty, error)
+	BookJob(context.Context, *github_com_ovh_cds_sdk2.JobBookRequest) (*google_protobuf1.Empty, error)
+	SendStepStatus(context.Context, *github_com_ovh_cds_sdk2.StepStatusUpdate) (*google_protobuf1.Empty, error)
+	Heartbeat(context.Context, *google_protobuf1.Empty) (*google_protobuf1.Empty, error)
+	StreamLog(WorkflowQueue_StreamLogServer) error
 }
 
 func RegisterWorkflowQueueServer(s *grpc1.Server, srv WorkflowQueueServer) {
@@ -142,6 +209,86 @@ func _WorkflowQueue_SendResult_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _WorkflowQueue_BookJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc1.UnaryServerInterceptor) (interface{}, error) {
+	in := new(github_com_ovh_cds_sdk2.JobBookRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkflowQueueServer).BookJob(ctx, in)
+	}
+	info := &grpc1.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/grpc.WorkflowQueue/BookJob",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkflowQueueServer).BookJob(ctx, req.(*github_com_ovh_cds_sdk2.JobBookRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WorkflowQueue_SendStepStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc1.UnaryServerInterceptor) (interface{}, error) {
+	in := new(github_com_ovh_cds_sdk2.StepStatusUpdate)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkflowQueueServer).SendStepStatus(ctx, in)
+	}
+	info := &grpc1.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/grpc.WorkflowQueue/SendStepStatus",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkflowQueueServer).SendStepStatus(ctx, req.(*github_com_ovh_cds_sdk2.StepStatusUpdate))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WorkflowQueue_Heartbeat_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc1.UnaryServerInterceptor) (interface{}, error) {
+	in := new(google_protobuf1.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkflowQueueServer).Heartbeat(ctx, in)
+	}
+	info := &grpc1.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/grpc.WorkflowQueue/Heartbeat",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkflowQueueServer).Heartbeat(ctx, req.(*google_protobuf1.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WorkflowQueue_StreamLog_Handler(srv interface{}, stream grpc1.ServerStream) error {
+	return srv.(WorkflowQueueServer).StreamLog(&workflowQueueStreamLogServer{stream})
+}
+
+type WorkflowQueue_StreamLogServer interface {
+	Send(*github_com_ovh_cds_sdk2.LogAck) error
+	Recv() (*github_com_ovh_cds_sdk2.LogChunk, error)
+	grpc1.ServerStream
+}
+
+type workflowQueueStreamLogServer struct {
+	grpc1.ServerStream
+}
+
+func (x *workflowQueueStreamLogServer) Send(m *github_com_ovh_cds_sdk2.LogAck) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *workflowQueueStreamLogServer) Recv() (*github_com_ovh_cds_sdk2.LogChunk, error) {
+	m := new(github_com_ovh_cds_sdk2.LogChunk)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 var _WorkflowQueue_serviceDesc = grpc1.ServiceDesc{
 	ServiceName: "grpc.WorkflowQueue",
 	HandlerType: (*WorkflowQueueServer)(nil),
@@ -150,6 +297,18 @@ var _WorkflowQueue_serviceDesc = grpc1.ServiceDesc{
 			MethodName: "SendResult",
 			Handler:    _WorkflowQueue_SendResult_Handler,
 		},
+		{
+			MethodName: "BookJob",
+			Handler:    _WorkflowQueue_BookJob_Handler,
+		},
+		{
+			MethodName: "SendStepStatus",
+			Handler:    _WorkflowQueue_SendStepStatus_Handler,
+		},
+		{
+			MethodName: "Heartbeat",
+			Handler:    _WorkflowQueue_Heartbeat_Handler,
+		},
 	},
 	Streams: []grpc1.StreamDesc{
 		{
@@ -157,6 +316,12 @@ var _WorkflowQueue_serviceDesc = grpc1.ServiceDesc{
 			Handler:       _WorkflowQueue_SendLog_Handler,
 			ClientStreams: true,
 		},
+		{
+			StreamName:    "StreamLog",
+			Handler:       _WorkflowQueue_StreamLog_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
 	},
 	Metadata: "workflowqueue.proto",
 }
@@ -164,18 +329,23 @@ var _WorkflowQueue_serviceDesc = grpc1.ServiceDesc{
 func init() { proto.RegisterFile("workflowqueue.proto", fileDescriptor1) }
 
 var fileDescriptor1 = []byte{
-	// 196 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x74, 0xce, 0xbf, 0xca, 0xc2, 0x30,
-	0x14, 0x05, 0xf0, 0xaf, 0xf0, 0xa1, 0x10, 0x70, 0x89, 0xe0, 0xd0, 0x82, 0x88, 0x2e, 0x4e, 0x37,
-	0xa0, 0x6f, 0x20, 0xe8, 0xd4, 0x45, 0x1d, 0xdc, 0x9b, 0xa4, 0xa9, 0x34, 0xf5, 0xd6, 0xfc, 0x69,
-	0xf1, 0x59, 0x7c, 0x59, 0x69, 0x53, 0x71, 0xea, 0x78, 0xef, 0x39, 0xfc, 0x38, 0x64, 0xde, 0xa2,
-	0x29, 0x73, 0x8d, 0xed, 0xd3, 0x4b, 0x2f, 0xa1, 0x36, 0xe8, 0x90, 0xfe, 0x2b, 0x53, 0xf3, 0x78,
-	0xa5, 0xee, 0xae, 0xf0, 0x19, 0x70, 0xac, 0x18, 0x36, 0x05, 0xe3, 0xc2, 0x32, 0x2b, 0x4a, 0xa6,
-	0x51, 0x85, 0x5e, 0xbc, 0x19, 0x69, 0x18, 0x69, 0xbd, 0x76, 0x43, 0x29, 0x51, 0x88, 0x4a, 0x4b,
-	0xd6, 0x5f, 0x99, 0xcf, 0x99, 0xac, 0x6a, 0xf7, 0x0a, 0xe1, 0xee, 0x1d, 0x91, 0xd9, 0x6d, 0x58,
-	0x70, 0xee, 0x16, 0xd0, 0x03, 0x99, 0x5e, 0xe5, 0x43, 0xa4, 0xa8, 0x68, 0x02, 0x3f, 0x1f, 0xb0,
-	0x29, 0x80, 0x0b, 0x0b, 0x56, 0x94, 0x90, 0xa2, 0x8a, 0x17, 0x10, 0x5c, 0xf8, 0xba, 0x70, 0xec,
-	0xdc, 0xf5, 0xdf, 0x36, 0xa2, 0x27, 0x42, 0x3a, 0xe3, 0xd2, 0xcf, 0xa0, 0xcb, 0x31, 0x26, 0xe4,
-	0xe3, 0x52, 0x36, 0xe9, 0x3f, 0xfb, 0x4f, 0x00, 0x00, 0x00, 0xff, 0xff, 0xaa, 0x1f, 0xda, 0xec,
-	0x25, 0x01, 0x00, 0x00,
+	// 288 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x7c, 0x90, 0x4d, 0x4b, 0xc3, 0x40,
+	0x10, 0x86, 0x2d, 0x8a, 0xa5, 0x0b, 0x7a, 0x58, 0xc1, 0x43, 0x0a, 0xa5, 0x54, 0x90, 0x9e, 0x76,
+	0x45, 0xcf, 0x1e, 0xac, 0x28, 0x52, 0x7b, 0xb1, 0x51, 0x3c, 0x67, 0xb3, 0xd3, 0x8d, 0x6c, 0xd2,
+	0x49, 0xf7, 0xa3, 0xc5, 0xbf, 0xe9, 0x2f, 0x92, 0x4d, 0x2a, 0xf5, 0xb2, 0x39, 0x0e, 0xf3, 0xcc,
+	0x33, 0x2f, 0x2f, 0xb9, 0xd8, 0xa1, 0xd1, 0xab, 0x12, 0x77, 0x1b, 0x0f, 0x1e, 0x58, 0x6d, 0xd0,
+	0x21, 0x3d, 0x51, 0xa6, 0xce, 0x93, 0xb1, 0xfa, 0x72, 0x85, 0x17, 0x2c, 0xc7, 0x8a, 0xe3, 0xb6,
+	0xe0, 0xb9, 0xb4, 0xdc, 0x4a, 0xcd, 0x4b, 0x54, 0x2d, 0x97, 0x5c, 0x45, 0x08, 0x03, 0xd6, 0x97,
+	0x6e, 0x0f, 0x4d, 0x22, 0xd0, 0xbf, 0x87, 0xc9, 0x50, 0x21, 0xaa, 0x12, 0x78, 0x33, 0x09, 0xbf,
+	0xe2, 0x50, 0xd5, 0xee, 0xbb, 0x5d, 0xde, 0xfe, 0x1c, 0x93, 0xb3, 0xcf, 0x7d, 0xca, 0xb7, 0x70,
+	0x44, 0x67, 0xa4, 0x9f, 0xc2, 0x5a, 0x2e, 0x50, 0xd1, 0x21, 0x3b, 0xe8, 0x19, 0x6e, 0x0b, 0x96,
+	0x4b, 0xcb, 0xac, 0xd4, 0x6c, 0x81, 0x2a, 0xb9, 0x64, 0xad, 0x97, 0xfd, 0x79, 0xd9, 0x53, 0xf0,
+	0x4e, 0x8e, 0xa6, 0x3d, 0xfa, 0x4c, 0x48, 0x70, 0x2c, 0x9b, 0xa8, 0x74, 0x14, 0xd3, 0xb4, 0xfb,
+	0xb8, 0x89, 0xbe, 0x92, 0xfe, 0x0c, 0x51, 0xcf, 0x51, 0xd0, 0xeb, 0x98, 0x64, 0x8e, 0x22, 0x30,
+	0x4b, 0xd8, 0x78, 0xb0, 0x5d, 0xb2, 0x77, 0x72, 0x1e, 0x42, 0xa5, 0x0e, 0xea, 0xd4, 0x65, 0xce,
+	0x5b, 0x3a, 0x8d, 0x39, 0x0f, 0xcc, 0x47, 0x2d, 0x33, 0x07, 0x1d, 0xd6, 0x7b, 0x32, 0x78, 0x81,
+	0xcc, 0x38, 0x01, 0x99, 0xa3, 0x11, 0xac, 0xe3, 0x3c, 0x25, 0x83, 0xd4, 0x19, 0xc8, 0xaa, 0xd0,
+	0xf7, 0xb8, 0xa3, 0xef, 0xc7, 0xc2, 0xaf, 0x75, 0x32, 0xea, 0x20, 0x1e, 0x72, 0x1d, 0xca, 0xbf,
+	0xe9, 0x89, 0xd3, 0xe6, 0xcd, 0xdd, 0xef, 0x00, 0x92, 0x7d, 0xe4, 0x5f, 0x80, 0x02, 0x00, 0x00,
 }
@@ -5,6 +5,7 @@ import (
 
 	"github.com/go-gorp/gorp"
 
+	"github.com/golang/protobuf/ptypes"
 	"github.com/golang/protobuf/ptypes/empty"
 	"golang.org/x/net/context"
 
@@ -12,6 +13,7 @@ import (
 	"github.com/ovh/cds/engine/api/database"
 	"github.com/ovh/cds/engine/api/grpc"
 	"github.com/ovh/cds/engine/api/project"
+	"github.com/ovh/cds/engine/api/services"
 	"github.com/ovh/cds/engine/api/worker"
 	"github.com/ovh/cds/engine/api/workflow"
 	"github.com/ovh/cds/sdk"
@@ -19,9 +21,13 @@ import (
 )
 
 type grpcHandlers struct {
+	background          context.Context
 	dbConnectionFactory *database.DBConnectionFactory
 	store               cache.Store
 	stepMaxLogSize      int64
+	defaultProjectQuota int
+	maintenance         func() bool
+	panicDump           func(s string) (io.WriteCloser, error)
 }
 
 //SendLog is the WorkflowQueueServer implementation
@@ -46,6 +52,47 @@ func (h *grpcHandlers) SendLog(stream grpc.WorkflowQueue_SendLogServer) error {
 	}
 }
 
+//StreamLog is the WorkflowQueueServer implementation. Each chunk of logs is acknowledged once persisted
+func (h *grpcHandlers) StreamLog(stream grpc.WorkflowQueue_StreamLogServer) error {
+	log.Debug("grpc.StreamLog> begin")
+	defer log.Debug("grpc.StreamLog> end")
+
+	workerID, ok := stream.Context().Value(keyWorkerID).(string)
+	if !ok {
+		return sdk.ErrForbidden
+	}
+
+	var wr *sdk.Worker
+	for {
+		in, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		// A worker can only send the logs of the job it has taken, the worker is reloaded when it takes another job
+		if wr == nil || wr.ActionBuildID != in.JobID {
+			wr, err = worker.LoadWorker(h.dbConnectionFactory.GetDBMap(), workerID)
+			if err != nil {
+				return sdk.WrapError(err, "cannot load worker info")
+			}
+			if wr.ActionBuildID != in.JobID {
+				return sdk.ErrForbidden
+			}
+		}
+
+		offset, err := workflow.AddLogChunk(h.dbConnectionFactory.GetDBMap(), in, h.stepMaxLogSize)
+		if err != nil {
+			return sdk.WrapError(err, "Unable to insert log chunk")
+		}
+		if err := stream.Send(&sdk.LogAck{JobID: in.JobID, StepOrder: in.StepOrder, Offset: offset}); err != nil {
+			return err
+		}
+	}
+}
+
 //SendResult is the WorkflowQueueServer implementation
 func (h *grpcHandlers) SendResult(c context.Context, res *sdk.Result) (*empty.Empty, error) {
 	log.Debug("grpc.SendResult> begin")
@@ -87,6 +134,83 @@ func (h *grpcHandlers) SendResult(c context.Context, res *sdk.Result) (*empty.Em
 
 	workflow.ResyncNodeRunsWithCommits(db, h.store, p, report)
 	go workflow.SendEvent(db, p.Key, report)
+	// the upstream workflows are triggered on the api context: the request one is cancelled as soon as the result is sent
+	sdk.GoRoutine(h.background, "triggerUpstreamWorkflows", func(ctx context.Context) {
+		triggerUpstreamWorkflows(ctx, db, h.store, report)
+	}, h.panicDump)
 
 	return new(empty.Empty), nil
 }
+
+//BookJob is the WorkflowQueueServer implementation
+func (h *grpcHandlers) BookJob(c context.Context, req *sdk.JobBookRequest) (*empty.Empty, error) {
+	hatchery, ok := c.Value(keyService).(*sdk.Service)
+	if !ok || hatchery.Type != services.TypeHatchery {
+		return new(empty.Empty), sdk.ErrForbidden
+	}
+	// as the REST route, the jobs can't be booked during CDS maintenance
+	if h.maintenance != nil && h.maintenance() {
+		return new(empty.Empty), sdk.WrapError(sdk.ErrServiceUnavailable, "CDS Maintenance ON")
+	}
+
+	if err := bookJob(h.dbConnectionFactory.GetDBMap(), h.store, req.JobID, hatchery, h.defaultProjectQuota); err != nil {
+		return new(empty.Empty), err
+	}
+	// the grpc hatcheries book the jobs in a single call
+	if err := workflow.ConfirmNodeJobRun(h.store, req.JobID, hatchery); err != nil {
+		return new(empty.Empty), err
+	}
+	return new(empty.Empty), nil
+}
+
+//SendStepStatus is the WorkflowQueueServer implementation
+func (h *grpcHandlers) SendStepStatus(c context.Context, req *sdk.StepStatusUpdate) (*empty.Empty, error) {
+	workerID, ok := c.Value(keyWorkerID).(string)
+	if !ok {
+		return new(empty.Empty), sdk.ErrForbidden
+	}
+
+	db := h.dbConnectionFactory.GetDBMap()
+
+	// A worker can only update the steps of the job it has taken
+	wr, err := worker.LoadWorker(db, workerID)
+	if err != nil {
+		return new(empty.Empty), sdk.WrapError(err, "cannot load worker info")
+	}
+	if wr.ActionBuildID != req.JobID {
+		return new(empty.Empty), sdk.ErrForbidden
+	}
+
+	step := sdk.StepStatus{
+		StepOrder: int(req.StepOrder),
+		Status:    req.Status,
+	}
+	if req.Start != nil {
+		step.Start, _ = ptypes.Timestamp(req.Start)
+	}
+	if req.Done != nil {
+		step.Done, _ = ptypes.Timestamp(req.Done)
+	}
+
+	if err := postJobStepStatus(c, db, h.store, req.JobID, step, ""); err != nil {
+		return new(empty.Empty), err
+	}
+	return new(empty.Empty), nil
+}
+
+//Heartbeat is the WorkflowQueueServer implementation
+func (h *grpcHandlers) Heartbeat(c context.Context, _ *empty.Empty) (*empty.Empty, error) {
+	workerID, ok := c.Value(keyWorkerID).(string)
+	if !ok {
+		return new(empty.Empty), sdk.ErrForbidden
+	}
+
+	w := &sdk.Worker{ID: workerID}
+	if err := worker.RefreshWorker(h.dbConnectionFactory.GetDBMap(), w); err != nil {
+		return new(empty.Empty), sdk.WrapError(err, "cannot refresh last beat of %s", workerID)
+	}
+	if w.Draining {
+		return new(empty.Empty), sdk.WithStack(sdk.ErrWorkerDraining)
+	}
+	return new(empty.Empty), nil
+}
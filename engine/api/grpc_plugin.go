@@ -3,6 +3,8 @@ package api
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -171,6 +173,14 @@ func (api *API) postGRPCluginBinaryHandler() service.Handler {
 			return sdk.WrapError(sdk.ErrWrongRequest, "postGRPCluginBinaryHandler")
 		}
 
+		// A checksum given with the binary pins it: the upload is refused if the content doesn't match
+		sum := sha256.Sum256(b.FileContent)
+		sha256sum := hex.EncodeToString(sum[:])
+		if b.SHA256sum != "" && b.SHA256sum != sha256sum {
+			return sdk.NewErrorFrom(sdk.ErrWrongRequest, "checksum of binary %s does not match: expected %s, got %s", b.Name, b.SHA256sum, sha256sum)
+		}
+		b.SHA256sum = sha256sum
+
 		tx, err := api.mustDB().Begin()
 		if err != nil {
 			return sdk.WrapError(err, "unable to start tx")
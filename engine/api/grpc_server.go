@@ -2,6 +2,7 @@ package api
 
 import (
 	"fmt"
+	"io"
 	"net"
 
 	"golang.org/x/net/context"
@@ -10,6 +11,7 @@ import (
 	"google.golang.org/grpc/metadata"
 
 	"github.com/ovh/cds/engine/api/auth"
+	"github.com/ovh/cds/engine/api/cache"
 	"github.com/ovh/cds/engine/api/database"
 	cdsgrpc "github.com/ovh/cds/engine/api/grpc"
 	"github.com/ovh/cds/sdk"
@@ -17,7 +19,7 @@ import (
 )
 
 // grpcInit initialize all GRPC services
-func grpcInit(dbConnectionFactory *database.DBConnectionFactory, addr string, port int, tls bool, certFile, keyFile string, stepMaxLogSize int64) error {
+func grpcInit(background context.Context, dbConnectionFactory *database.DBConnectionFactory, store cache.Store, addr string, port int, tls bool, certFile, keyFile string, stepMaxLogSize int64, defaultProjectQuota int, maintenance func() bool, panicDump func(s string) (io.WriteCloser, error)) error {
 	lis, err := net.Listen("tcp", fmt.Sprintf("%s:%d", addr, port))
 	if err != nil {
 		return err
@@ -26,8 +28,13 @@ func grpcInit(dbConnectionFactory *database.DBConnectionFactory, addr string, po
 	log.Info("Starting GRPC services on %s:%d", addr, port)
 
 	grpcHandlers := &grpcHandlers{
+		background:          background,
 		dbConnectionFactory: dbConnectionFactory,
+		store:               store,
 		stepMaxLogSize:      stepMaxLogSize,
+		defaultProjectQuota: defaultProjectQuota,
+		maintenance:         maintenance,
+		panicDump:           panicDump,
 	}
 
 	opts := []grpc.ServerOption{
@@ -55,6 +62,7 @@ type key string
 const (
 	keyWorkerID   key = "worker_id"
 	keyWorkerName key = "worker_name"
+	keyService    key = "service"
 )
 
 func (h *grpcHandlers) streamInterceptor(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
@@ -66,10 +74,32 @@ func (h *grpcHandlers) streamInterceptor(srv interface{}, stream grpc.ServerStre
 	m := metadata.Pairs(string(keyWorkerID), w.ID, string(keyWorkerName), w.Name)
 	stream.SendHeader(m)
 
-	return handler(srv, stream)
+	ctx := context.WithValue(c, keyWorkerID, w.ID)
+	ctx = context.WithValue(ctx, keyWorkerName, w.Name)
+	return handler(srv, &workerServerStream{ServerStream: stream, ctx: ctx})
+}
+
+// workerServerStream is a server stream carrying the identity of the authenticated worker in its context
+type workerServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *workerServerStream) Context() context.Context {
+	return s.ctx
 }
 
 func (h *grpcHandlers) unaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+	// Services, as hatcheries, are authenticated with their hash
+	if md, ok := metadata.FromIncomingContext(ctx); ok && len(md["hash"]) > 0 {
+		srv, err := auth.GetService(h.dbConnectionFactory.GetDBMap(), h.store, md["hash"][0])
+		if err != nil {
+			log.Warning("unaryInterceptor> authorize service failed : %s", err)
+			return nil, sdk.ErrForbidden
+		}
+		return handler(context.WithValue(ctx, keyService, srv), req)
+	}
+
 	w, err := h.authorize(ctx)
 	if err != nil {
 		log.Warning("unaryInterceptor> authorize failed : %s", err)
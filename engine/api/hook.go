@@ -11,6 +11,7 @@ import (
 
 	"github.com/ovh/cds/engine/api/project"
 	"github.com/ovh/cds/engine/api/repositoriesmanager"
+	"github.com/ovh/cds/engine/api/services"
 	"github.com/ovh/cds/engine/api/workflow"
 	"github.com/ovh/cds/engine/service"
 	"github.com/ovh/cds/sdk"
@@ -102,3 +103,90 @@ func (api *API) getHookPollingVCSEvents() service.Handler {
 		return service.WriteJSON(w, repoEvents, http.StatusOK)
 	}
 }
+
+func (api *API) postHookRepairHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		uuid := mux.Vars(r)["uuid"]
+
+		h, err := workflow.LoadHookByUUID(api.mustDB(), uuid)
+		if err != nil {
+			return sdk.WrapError(err, "cannot load hook")
+		}
+		if h == nil {
+			return sdk.WithStack(sdk.ErrNotFound)
+		}
+
+		report := sdk.RepositoryWebHookReport{
+			UUID:       h.UUID,
+			Project:    h.Config[sdk.HookConfigProject].Value,
+			Workflow:   h.Config[sdk.HookConfigWorkflow].Value,
+			VCSServer:  h.Config["vcsServer"].Value,
+			Repository: h.Config["repoFullName"].Value,
+			Status:     sdk.RepositoryWebHookStatusOK,
+			Date:       time.Now(),
+		}
+
+		proj, err := project.Load(api.mustDB(), api.Cache, report.Project, nil)
+		if err != nil {
+			return sdk.WrapError(err, "cannot load project")
+		}
+
+		secret := h.Config[sdk.RepositoryWebHookSecret].Value
+		repaired, created, err := workflow.RepairVCSConfiguration(ctx, api.mustDB(), api.Cache, proj, h)
+		if err != nil {
+			log.Warning("postHookRepairHandler> unable to repair webhook of hook %s: %v", uuid, err)
+			report.Status = sdk.RepositoryWebHookStatusBroken
+			report.Message = sdk.Cause(err).Error()
+			report.WebHookID = h.Config["webHookID"].Value
+			return service.WriteJSON(w, report, http.StatusOK)
+		}
+
+		if repaired {
+			if err := api.saveRepairedHook(ctx, proj.ID, h, secret != h.Config[sdk.RepositoryWebHookSecret].Value); err != nil {
+				// the webhook created on the repository is not known by the hook, it would never be cleaned
+				if created {
+					if errD := workflow.DeleteVCSConfiguration(ctx, api.mustDB(), api.Cache, proj, *h); errD != nil {
+						log.Error("postHookRepairHandler> unable to delete webhook %s of hook %s: %v", h.Config["webHookID"].Value, uuid, errD)
+					}
+				}
+				return err
+			}
+			report.Status = sdk.RepositoryWebHookStatusRepaired
+		}
+		report.WebHookID = h.Config["webHookID"].Value
+
+		return service.WriteJSON(w, report, http.StatusOK)
+	}
+}
+
+// saveRepairedHook saves the configuration of a repaired hook, and pushes it to the hooks µService when its secret
+// changed to check the signatures of the payloads
+func (api *API) saveRepairedHook(ctx context.Context, projectID int64, h *sdk.WorkflowNodeHook, secretChanged bool) error {
+	tx, err := api.mustDB().Begin()
+	if err != nil {
+		return sdk.WithStack(err)
+	}
+	defer tx.Rollback() // nolint
+
+	if err := workflow.UpdateHookConfig(tx, h); err != nil {
+		return sdk.WrapError(err, "cannot update hook")
+	}
+
+	if secretChanged {
+		srvs, err := services.FindByType(tx, services.TypeHooks)
+		if err != nil {
+			return sdk.WrapError(err, "unable to get hooks services")
+		}
+		clearHook, err := workflow.WithClearWebHookSecret(projectID, *h)
+		if err != nil {
+			return err
+		}
+		hooks := map[string]sdk.WorkflowNodeHook{h.UUID: clearHook}
+		code, err := services.DoJSONRequest(ctx, srvs, http.MethodPost, "/task/bulk", hooks, &hooks)
+		if err != nil || code >= 400 {
+			return sdk.WrapError(err, "unable to update hook %s [%d]", h.UUID, code)
+		}
+	}
+
+	return sdk.WithStack(tx.Commit())
+}
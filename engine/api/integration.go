@@ -156,7 +156,8 @@ func propagatePublicIntegrationModel(db *gorp.DbMap, store cache.Store, m sdk.In
 }
 
 func propagatePublicIntegrationModelOnProject(db gorp.SqlExecutor, store cache.Store, m sdk.IntegrationModel, p sdk.Project, u *sdk.User) error {
-	if !m.Public {
+	// a model scoped to an organization is only shared with the projects of the organization
+	if !m.Public || (m.OrganizationID != 0 && m.OrganizationID != p.OrganizationID) {
 		return nil
 	}
 
@@ -55,7 +55,7 @@ func LoadIntegrationsByName(db gorp.SqlExecutor, key string, name string, clearP
 	for k, v := range p.Config {
 		if v.Type == sdk.IntegrationConfigTypePassword {
 			if clearPwd {
-				decryptedValue, errD := decryptIntegrationValue(v.Value)
+				decryptedValue, errD := decryptProjectIntegrationValue(p.ProjectID, v.Value)
 				if errD != nil {
 					return p, sdk.WrapError(errD, "LoadIntegrationsByName> Cannot decrypt value")
 				}
@@ -83,7 +83,7 @@ func LoadByID(db gorp.SqlExecutor, id int64, clearPassword bool) (*sdk.ProjectIn
 	for k, v := range pp.Config {
 		if v.Type == sdk.IntegrationConfigTypePassword {
 			if clearPassword {
-				secret, errD := decryptIntegrationValue(v.Value)
+				secret, errD := decryptProjectIntegrationValue(pp.ProjectID, v.Value)
 				if errD != nil {
 					return nil, sdk.WrapError(errD, "LoadIntegrationByID> Cannot decrypt password")
 				}
@@ -121,7 +121,7 @@ func LoadIntegrationsByProjectID(db gorp.SqlExecutor, id int64, clearPassword bo
 		for k, v := range pp.Config {
 			if v.Type == sdk.IntegrationConfigTypePassword {
 				if clearPassword {
-					secret, errD := decryptIntegrationValue(v.Value)
+					secret, errD := decryptProjectIntegrationValue(id, v.Value)
 					if errD != nil {
 						return nil, sdk.WrapError(errD, "LoadIntegrationByID> Cannot decrypt password")
 					}
@@ -138,31 +138,71 @@ func LoadIntegrationsByProjectID(db gorp.SqlExecutor, id int64, clearPassword bo
 	return integrations, nil
 }
 
-func decryptIntegrationValue(v string) (string, error) {
-	b, err64 := base64.StdEncoding.DecodeString(v)
-	if err64 != nil {
-		return "", sdk.WrapError(err64, "decryptIntegrationValue> cannot decode string")
+// decryptProjectIntegrationValue decrypts a password of an integration of the project
+func decryptProjectIntegrationValue(projectID int64, v string) (string, error) {
+	b, err := base64.StdEncoding.DecodeString(v)
+	if err != nil {
+		return "", sdk.WrapError(err, "cannot decode password")
+	}
+	clear, err := secret.DecryptForProject(projectID, b)
+	if err != nil {
+		return "", sdk.WrapError(err, "cannot decrypt password")
 	}
-	secret, errD := secret.Decrypt(b)
-	if errD != nil {
-		return "", sdk.WrapError(errD, "decryptIntegrationValue> Cannot decrypt password")
+	return string(clear), nil
+}
+
+// encryptProjectIntegrationValue encrypts a password of an integration with the data key of the project
+func encryptProjectIntegrationValue(db gorp.SqlExecutor, projectID int64, v string) (string, error) {
+	encrypted, err := secret.EncryptForProject(db, projectID, []byte(v))
+	if err != nil {
+		return "", sdk.WrapError(err, "cannot encrypt password")
 	}
-	return string(secret), nil
+	return base64.StdEncoding.EncodeToString(encrypted), nil
 }
 
-func encryptIntegrationValue(v string) (string, error) {
-	encryptedSecret, errE := secret.Encrypt([]byte(v))
-	if errE != nil {
-		return "", sdk.WrapError(errE, "encryptIntegrationValue> Cannot encrypt password")
+// RotatePasswords re-encrypts the passwords of the integrations of the project with its active data key.
+// It returns the number of re-encrypted passwords.
+func RotatePasswords(db gorp.SqlExecutor, projectID int64) (int, error) {
+	var res []dbProjectIntegration
+	if _, err := db.Select(&res, "SELECT * from project_integration WHERE project_id = $1", projectID); err != nil {
+		return 0, sdk.WrapError(err, "cannot load integrations of project %d", projectID)
+	}
+
+	var count int
+	for i := range res {
+		pp := &res[i]
+		var n int
+		for k, v := range pp.Config {
+			if v.Type != sdk.IntegrationConfigTypePassword || v.Value == "" {
+				continue
+			}
+			clear, err := decryptProjectIntegrationValue(projectID, v.Value)
+			if err != nil {
+				return 0, sdk.WrapError(err, "cannot decrypt %s of integration %s", k, pp.Name)
+			}
+			v.Value, err = encryptProjectIntegrationValue(db, projectID, clear)
+			if err != nil {
+				return 0, sdk.WrapError(err, "cannot encrypt %s of integration %s", k, pp.Name)
+			}
+			pp.Config[k] = v
+			n++
+		}
+		if n == 0 {
+			continue
+		}
+		if err := pp.PostUpdate(db); err != nil {
+			return 0, sdk.WrapError(err, "cannot update integration %s", pp.Name)
+		}
+		count += n
 	}
-	return base64.StdEncoding.EncodeToString(encryptedSecret), nil
+	return count, nil
 }
 
 // InsertIntegration inserts a integration
 func InsertIntegration(db gorp.SqlExecutor, pp *sdk.ProjectIntegration) error {
 	for k, v := range pp.Config {
 		if v.Type == sdk.IntegrationConfigTypePassword {
-			s, errS := encryptIntegrationValue(v.Value)
+			s, errS := encryptProjectIntegrationValue(db, pp.ProjectID, v.Value)
 			if errS != nil {
 				return sdk.WrapError(errS, "InsertIntegration> Cannot encrypt password")
 			}
@@ -182,7 +222,7 @@ func InsertIntegration(db gorp.SqlExecutor, pp *sdk.ProjectIntegration) error {
 func UpdateIntegration(db gorp.SqlExecutor, pp sdk.ProjectIntegration) error {
 	for k, v := range pp.Config {
 		if v.Type == sdk.IntegrationConfigTypePassword {
-			s, errS := encryptIntegrationValue(v.Value)
+			s, errS := encryptProjectIntegrationValue(db, pp.ProjectID, v.Value)
 			if errS != nil {
 				return sdk.WrapError(errS, "UpdateIntegration> Cannot encrypt password")
 			}
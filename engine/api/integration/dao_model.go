@@ -2,10 +2,12 @@ package integration
 
 import (
 	"database/sql"
+	"encoding/base64"
 
 	"github.com/go-gorp/gorp"
 
 	"github.com/ovh/cds/engine/api/database/gorpmapping"
+	"github.com/ovh/cds/engine/api/secret"
 	"github.com/ovh/cds/sdk"
 )
 
@@ -38,7 +40,7 @@ func LoadModel(db gorp.SqlExecutor, modelID int64, clearPassword bool) (sdk.Inte
 	if clearPassword {
 		for pfName, pfCfg := range pm.PublicConfigurations {
 			newCfg := pfCfg.Clone()
-			if err := newCfg.DecryptSecrets(decryptIntegrationValue); err != nil {
+			if err := newCfg.DecryptSecrets(decryptModelValue); err != nil {
 				return sdk.IntegrationModel{}, sdk.WrapError(err, "unable to encrypt config")
 			}
 			pm.PublicConfigurations[pfName] = newCfg
@@ -65,7 +67,7 @@ func LoadModelByName(db gorp.SqlExecutor, name string, clearPassword bool) (sdk.
 	if clearPassword {
 		for pfName, pfCfg := range pm.PublicConfigurations {
 			newCfg := pfCfg.Clone()
-			if err := newCfg.DecryptSecrets(decryptIntegrationValue); err != nil {
+			if err := newCfg.DecryptSecrets(decryptModelValue); err != nil {
 				return sdk.IntegrationModel{}, sdk.WrapError(err, "unable to encrypt config")
 			}
 			pm.PublicConfigurations[pfName] = newCfg
@@ -175,7 +177,7 @@ func (pm *integrationModel) PostUpdate(db gorp.SqlExecutor) error {
 	cfg := make(map[string]sdk.IntegrationConfig, len(pm.PublicConfigurations))
 	for pfName, pfCfg := range pm.PublicConfigurations {
 		newCfg := pfCfg.Clone()
-		if err := newCfg.EncryptSecrets(encryptIntegrationValue); err != nil {
+		if err := newCfg.EncryptSecrets(encryptModelValue); err != nil {
 			return sdk.WrapError(err, "unable to encrypt config")
 		}
 		cfg[pfName] = newCfg
@@ -185,3 +187,26 @@ func (pm *integrationModel) PostUpdate(db gorp.SqlExecutor) error {
 	_, err := db.Exec("update integration_model set default_config = $2, deployment_default_config = $3, public_configurations = $4 where id = $1", pm.ID, defaultConfig, deploymentDefaultConfig, publicConfig)
 	return sdk.WrapError(err, "Unable to update integration_model")
 }
+
+// decryptModelValue decrypts a password of the public configurations of a model. The models are shared by all the
+// projects, their passwords are encrypted with the secrets key of the API.
+func decryptModelValue(v string) (string, error) {
+	b, err := base64.StdEncoding.DecodeString(v)
+	if err != nil {
+		return "", sdk.WrapError(err, "cannot decode password")
+	}
+	clear, err := secret.Decrypt(b)
+	if err != nil {
+		return "", sdk.WrapError(err, "cannot decrypt password")
+	}
+	return string(clear), nil
+}
+
+// encryptModelValue encrypts a password of the public configurations of a model
+func encryptModelValue(v string) (string, error) {
+	encrypted, err := secret.Encrypt([]byte(v))
+	if err != nil {
+		return "", sdk.WrapError(err, "cannot encrypt password")
+	}
+	return base64.StdEncoding.EncodeToString(encrypted), nil
+}
@@ -12,6 +12,15 @@ var (
 	BuiltinModels = []sdk.IntegrationModel{
 		sdk.KafkaIntegration,
 		sdk.RabbitMQIntegration,
+		sdk.VaultIntegration,
+		sdk.AWSSecretsIntegration,
+		sdk.DockerRegistryIntegration,
+		sdk.ArtifactManagerIntegration,
+		sdk.ArtifactoryIntegration,
+		sdk.KubernetesIntegration,
+		sdk.TerraformIntegration,
+		sdk.OAuth2Integration,
+		sdk.MonitoringIntegration,
 	}
 )
 
@@ -0,0 +1,26 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/ovh/cds/engine/api/jobtoken"
+	"github.com/ovh/cds/engine/service"
+)
+
+// getOpenIDConfigurationHandler returns the OpenID Connect discovery document used by the cloud providers to
+// trust the tokens of the jobs
+func (api *API) getOpenIDConfigurationHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		jwksURI := strings.TrimSuffix(api.Config.URL.API, "/") + "/.well-known/jwks.json"
+		return service.WriteJSON(w, jobtoken.OpenIDConfiguration(jwksURI), http.StatusOK)
+	}
+}
+
+// getJWKSHandler returns the public keys verifying the tokens of the jobs
+func (api *API) getJWKSHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		return service.WriteJSON(w, jobtoken.KeySet(), http.StatusOK)
+	}
+}
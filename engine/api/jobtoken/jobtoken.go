@@ -0,0 +1,140 @@
+package jobtoken
+
+import (
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	jwt "github.com/dgrijalva/jwt-go"
+
+	"github.com/ovh/cds/sdk"
+)
+
+var (
+	issuer     string
+	audience   string
+	ttl        time.Duration
+	signingKey *rsa.PrivateKey
+	keyID      string
+)
+
+// Init sets the issuer, the audience, the validity and the PEM encoded RSA key used to sign the OIDC tokens of the
+// jobs. Without key the jobs do not get a token.
+func Init(iss, aud string, validity time.Duration, key string) error {
+	issuer, audience, ttl, signingKey, keyID = iss, aud, validity, nil, ""
+	if key == "" {
+		return nil
+	}
+	k, err := jwt.ParseRSAPrivateKeyFromPEM([]byte(key))
+	if err != nil {
+		return fmt.Errorf("invalid job token signing key: %v", err)
+	}
+	der, err := x509.MarshalPKIXPublicKey(&k.PublicKey)
+	if err != nil {
+		return fmt.Errorf("invalid job token signing key: %v", err)
+	}
+	sum := sha256.Sum256(der)
+	signingKey, keyID = k, base64.RawURLEncoding.EncodeToString(sum[:])
+	return nil
+}
+
+// Enabled returns true if the jobs get an OIDC token
+func Enabled() bool {
+	return signingKey != nil
+}
+
+// Subject returns the subject of the token of a job
+func Subject(c sdk.JobTokenClaims) string {
+	s := fmt.Sprintf("project:%s:workflow:%s:node:%s", c.Project, c.Workflow, c.Node)
+	if c.Ref != "" {
+		s += ":ref:" + c.Ref
+	}
+	return s
+}
+
+// New returns a signed token for a job
+func New(c sdk.JobTokenClaims) (string, error) {
+	if signingKey == nil {
+		return "", sdk.NewErrorFrom(sdk.ErrNotImplemented, "job tokens are disabled on this instance")
+	}
+	now := time.Now()
+	c.StandardClaims = jwt.StandardClaims{
+		Id:        sdk.UUID(),
+		Issuer:    issuer,
+		Subject:   Subject(c),
+		Audience:  audience,
+		IssuedAt:  now.Unix(),
+		NotBefore: now.Unix(),
+		ExpiresAt: now.Add(ttl).Unix(),
+	}
+	t := jwt.NewWithClaims(jwt.SigningMethodRS256, c)
+	t.Header["kid"] = keyID
+	s, err := t.SignedString(signingKey)
+	if err != nil {
+		return "", sdk.WrapError(err, "cannot sign job token")
+	}
+	return s, nil
+}
+
+// Verify checks the signature and the validity of a job token and returns its claims
+func Verify(token string) (sdk.JobTokenClaims, error) {
+	var c sdk.JobTokenClaims
+	if signingKey == nil {
+		return c, sdk.NewErrorFrom(sdk.ErrNotImplemented, "job tokens are disabled on this instance")
+	}
+	_, err := jwt.ParseWithClaims(token, &c, func(t *jwt.Token) (interface{}, error) {
+		if _, ok := t.Method.(*jwt.SigningMethodRSA); !ok {
+			return nil, sdk.NewErrorFrom(sdk.ErrUnauthorized, "unexpected signing method: %v", t.Header["alg"])
+		}
+		return &signingKey.PublicKey, nil
+	})
+	if err != nil {
+		return c, sdk.NewErrorFrom(sdk.ErrUnauthorized, "invalid job token: %v", err)
+	}
+	return c, nil
+}
+
+// KeySet returns the public keys verifying the job tokens, empty if the job tokens are disabled
+func KeySet() sdk.JSONWebKeySet {
+	set := sdk.JSONWebKeySet{Keys: []sdk.JSONWebKey{}}
+	if signingKey == nil {
+		return set
+	}
+	set.Keys = append(set.Keys, sdk.JSONWebKey{
+		Kty: "RSA",
+		Use: "sig",
+		Alg: "RS256",
+		Kid: keyID,
+		N:   base64.RawURLEncoding.EncodeToString(signingKey.PublicKey.N.Bytes()),
+		E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(signingKey.PublicKey.E)).Bytes()),
+	})
+	return set
+}
+
+// OpenIDConfiguration returns the discovery document of the issuer, the keys are served at jwksURI
+func OpenIDConfiguration(jwksURI string) sdk.OpenIDConfiguration {
+	return sdk.OpenIDConfiguration{
+		Issuer:                           issuer,
+		JWKSURI:                          jwksURI,
+		ResponseTypesSupported:           []string{"id_token"},
+		SubjectTypesSupported:            []string{"public"},
+		IDTokenSigningAlgValuesSupported: []string{"RS256"},
+		ClaimsSupported:                  []string{"sub", "aud", "exp", "iat", "iss", "jti", "nbf", "project", "workflow", "run", "node", "job", "ref"},
+	}
+}
+
+// Ref returns the git reference of a node run: refs/tags/<tag> or refs/heads/<branch>
+func Ref(nodeRun sdk.WorkflowNodeRun) string {
+	switch {
+	case nodeRun.VCSTag != "":
+		return "refs/tags/" + nodeRun.VCSTag
+	case nodeRun.VCSBranch != "":
+		return "refs/heads/" + strings.TrimPrefix(nodeRun.VCSBranch, "refs/heads/")
+	}
+	return ""
+}
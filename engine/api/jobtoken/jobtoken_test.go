@@ -0,0 +1,90 @@
+package jobtoken
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func testKey(t *testing.T) (*rsa.PrivateKey, string) {
+	k, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	return k, string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(k)}))
+}
+
+func TestNew(t *testing.T) {
+	assert.NoError(t, Init("https://cds.example.com", "sts.amazonaws.com", time.Hour, ""))
+	assert.False(t, Enabled())
+	assert.Len(t, KeySet().Keys, 0)
+	_, err := New(sdk.JobTokenClaims{})
+	assert.Error(t, err)
+
+	assert.Error(t, Init("https://cds.example.com", "sts.amazonaws.com", time.Hour, "not a key"))
+
+	_, key := testKey(t)
+	assert.NoError(t, Init("https://cds.example.com", "sts.amazonaws.com", time.Hour, key))
+	assert.True(t, Enabled())
+
+	token, err := New(sdk.JobTokenClaims{
+		Project:  "MYPROJ",
+		Workflow: "my-workflow",
+		Run:      42,
+		Node:     "deploy",
+		Job:      "Deploy to production",
+		Ref:      "refs/heads/master",
+	})
+	assert.NoError(t, err)
+
+	c, err := Verify(token)
+	assert.NoError(t, err)
+	assert.Equal(t, "project:MYPROJ:workflow:my-workflow:node:deploy:ref:refs/heads/master", c.Subject)
+	assert.Equal(t, "sts.amazonaws.com", c.Audience)
+	assert.Equal(t, "https://cds.example.com", c.Issuer)
+	assert.Equal(t, int64(42), c.Run)
+	assert.InDelta(t, time.Now().Add(time.Hour).Unix(), c.ExpiresAt, 5)
+
+	// A token signed by another key is rejected
+	_, otherKey := testKey(t)
+	assert.NoError(t, Init("https://cds.example.com", "sts.amazonaws.com", time.Hour, otherKey))
+	_, err = Verify(token)
+	assert.Error(t, err)
+}
+
+func TestKeySet(t *testing.T) {
+	k, key := testKey(t)
+	assert.NoError(t, Init("https://cds.example.com", "cds", time.Hour, key))
+
+	set := KeySet()
+	assert.Len(t, set.Keys, 1)
+	jwk := set.Keys[0]
+	assert.Equal(t, "RSA", jwk.Kty)
+	assert.Equal(t, "RS256", jwk.Alg)
+	assert.Equal(t, keyID, jwk.Kid)
+
+	n, err := base64.RawURLEncoding.DecodeString(jwk.N)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, new(big.Int).SetBytes(n).Cmp(k.PublicKey.N))
+	e, err := base64.RawURLEncoding.DecodeString(jwk.E)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(k.PublicKey.E), new(big.Int).SetBytes(e).Int64())
+
+	conf := OpenIDConfiguration("https://cds.example.com/.well-known/jwks.json")
+	assert.Equal(t, "https://cds.example.com", conf.Issuer)
+	assert.Equal(t, []string{"RS256"}, conf.IDTokenSigningAlgValuesSupported)
+}
+
+func TestRef(t *testing.T) {
+	assert.Equal(t, "refs/tags/v1.0.0", Ref(sdk.WorkflowNodeRun{VCSTag: "v1.0.0", VCSBranch: "master"}))
+	assert.Equal(t, "refs/heads/master", Ref(sdk.WorkflowNodeRun{VCSBranch: "master"}))
+	assert.Equal(t, "refs/heads/feat/a", Ref(sdk.WorkflowNodeRun{VCSBranch: "refs/heads/feat/a"}))
+	assert.Equal(t, "", Ref(sdk.WorkflowNodeRun{}))
+}
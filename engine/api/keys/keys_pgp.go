@@ -123,3 +123,34 @@ func GeneratePGPKeyPair(name string) (sdk.Key, error) {
 	k.Public = string(pub)
 	return k, err
 }
+
+// SignPGP returns the armored detached signature of data with an armored private PGP key
+func SignPGP(privateKey string, data []byte) (string, error) {
+	entity, err := GetOpenPGPEntity(bytes.NewBufferString(privateKey))
+	if err != nil {
+		return "", err
+	}
+	if entity.PrivateKey == nil {
+		return "", sdk.WithStack(errors.New("PGP key has no private key"))
+	}
+
+	buf := new(bytes.Buffer)
+	if err := openpgp.ArmoredDetachSign(buf, entity, bytes.NewReader(data), nil); err != nil {
+		return "", sdk.WrapError(err, "Cannot sign data")
+	}
+	return buf.String(), nil
+}
+
+// VerifyPGP checks the armored detached signature of data with an armored public PGP key, and returns the id of the signing key
+func VerifyPGP(publicKey string, data []byte, signature string) (string, error) {
+	keyring, err := openpgp.ReadArmoredKeyRing(bytes.NewBufferString(publicKey))
+	if err != nil {
+		return "", sdk.WrapError(err, "Unable to read armored key ring")
+	}
+
+	signer, err := openpgp.CheckArmoredDetachedSignature(keyring, bytes.NewReader(data), bytes.NewBufferString(signature))
+	if err != nil {
+		return "", sdk.WrapError(err, "Invalid signature")
+	}
+	return signer.PrimaryKey.KeyIdShortString(), nil
+}
@@ -100,3 +100,25 @@ func TestGenerateGPGKeyPair(t *testing.T) {
 	t.Logf(string(pub2))
 	assert.Equal(t, string([]byte(k.Public)), string(pub2))
 }
+
+func TestSignPGP(t *testing.T) {
+	k, err := GeneratePGPKeyPair("mykey")
+	test.NoError(t, err)
+
+	data := []byte("sha256sum: 2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae\n")
+	signature, err := SignPGP(k.Private, data)
+	test.NoError(t, err)
+	assert.Contains(t, signature, "BEGIN PGP SIGNATURE")
+
+	keyID, err := VerifyPGP(k.Public, data, signature)
+	test.NoError(t, err)
+	assert.Equal(t, k.KeyID, keyID)
+
+	_, err = VerifyPGP(k.Public, []byte("sha256sum: tampered\n"), signature)
+	assert.Error(t, err)
+
+	other, err := GeneratePGPKeyPair("otherkey")
+	test.NoError(t, err)
+	_, err = VerifyPGP(other.Public, data, signature)
+	assert.Error(t, err)
+}
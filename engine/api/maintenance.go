@@ -2,14 +2,62 @@ package api
 
 import (
 	"context"
+	"fmt"
 	"strconv"
 	"time"
 
+	"github.com/ovh/cds/engine/api/cache"
+	"github.com/ovh/cds/engine/api/workflow"
 	"github.com/ovh/cds/sdk"
 	"github.com/ovh/cds/sdk/log"
 )
 
+// maintenanceKey keeps the maintenance mode for the API instances started during the maintenance
+var maintenanceKey = cache.Key("api", "maintenance")
+
+func (a *API) setMaintenance(enable bool) {
+	a.Cache.Set(maintenanceKey, enable)
+	a.Cache.Publish(sdk.MaintenanceQueueName, strconv.FormatBool(enable))
+}
+
+// maintenanceStatus returns the maintenance mode and the progress of the queue drain
+func (a *API) maintenanceStatus() (sdk.MaintenanceStatus, error) {
+	s := sdk.MaintenanceStatus{Enabled: a.Maintenance}
+	counts, err := workflow.CountNodeJobRunsByStatus(a.mustDB())
+	if err != nil {
+		return s, err
+	}
+	s.BuildingJobs = counts[sdk.StatusBuilding.String()]
+	s.WaitingJobs = counts[sdk.StatusWaiting.String()]
+	s.Drained = s.Enabled && s.BuildingJobs == 0
+	return s, nil
+}
+
+// maintenanceStatusLine shows the progress of the queue drain during the maintenance
+func (a *API) maintenanceStatusLine() sdk.MonitoringStatusLine {
+	line := sdk.MonitoringStatusLine{Component: "Maintenance", Value: "OFF", Status: sdk.MonitoringStatusOK}
+	if !a.Maintenance {
+		return line
+	}
+	line.Status = sdk.MonitoringStatusWarn
+	s, err := a.maintenanceStatus()
+	switch {
+	case err != nil:
+		line.Value = fmt.Sprintf("ON - unable to count jobs: %v", err)
+	case s.Drained:
+		line.Value = fmt.Sprintf("ON - drained, %d waiting jobs", s.WaitingJobs)
+	default:
+		line.Value = fmt.Sprintf("ON - draining, %d building jobs", s.BuildingJobs)
+	}
+	return line
+}
+
 func (a *API) listenMaintenance(c context.Context) {
+	a.Cache.Get(maintenanceKey, &a.Maintenance)
+	if a.Maintenance {
+		log.Warning("listenMaintenance> CDS Maintenance ON")
+	}
+
 	pubSub := a.Cache.Subscribe(sdk.MaintenanceQueueName)
 	tick := time.NewTicker(50 * time.Millisecond)
 	defer tick.Stop()
@@ -0,0 +1,48 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/engine/api/test/assets"
+	"github.com/ovh/cds/sdk"
+)
+
+func TestAPI_Maintenance(t *testing.T) {
+	api, db, router, end := newTestAPI(t)
+	defer end()
+	defer api.setMaintenance(false)
+
+	u, pass := assets.InsertAdminUser(db)
+
+	uri := router.GetRoute("POST", api.postMaintenanceHandler, nil)
+	w := httptest.NewRecorder()
+	router.Mux.ServeHTTP(w, assets.NewAuthentifiedRequest(t, u, pass, "POST", uri+"?enable=true", nil))
+	assert.Equal(t, 204, w.Code)
+
+	var enabled bool
+	assert.True(t, api.Cache.Get(maintenanceKey, &enabled))
+	assert.True(t, enabled)
+	api.Maintenance = true
+
+	// New runs are rejected
+	uri = router.GetRoute("POST", api.postWorkflowRunHandler, map[string]string{
+		"key":              sdk.RandomString(10),
+		"permWorkflowName": sdk.RandomString(10),
+	})
+	w = httptest.NewRecorder()
+	router.Mux.ServeHTTP(w, assets.NewAuthentifiedRequest(t, u, pass, "POST", uri, sdk.WorkflowRunPostHandlerOption{}))
+	assert.Equal(t, 423, w.Code)
+
+	uri = router.GetRoute("GET", api.getMaintenanceHandler, nil)
+	w = httptest.NewRecorder()
+	router.Mux.ServeHTTP(w, assets.NewAuthentifiedRequest(t, u, pass, "GET", uri, nil))
+	assert.Equal(t, 200, w.Code)
+	var s sdk.MaintenanceStatus
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &s))
+	assert.True(t, s.Enabled)
+	assert.Equal(t, s.BuildingJobs == 0, s.Drained)
+}
@@ -0,0 +1,82 @@
+package mfa
+
+import (
+	"time"
+
+	"github.com/ovh/cds/engine/api/cache"
+	"github.com/ovh/cds/sdk"
+)
+
+// Verify checks a second factor given by a user: a TOTP code, a recovery code or the response of a security key to
+// the pending challenge of the user. The MFA configuration is updated with the used TOTP counter, the remaining
+// recovery codes or the signature counter of the security key, it has to be stored by the caller.
+func Verify(store cache.Store, username string, m *sdk.MFA, v sdk.MFAVerification, rp RelyingParty, now time.Time) error {
+	if v.IsEmpty() {
+		return sdk.WithStack(sdk.ErrMFARequired)
+	}
+
+	switch {
+	case v.TOTP != "":
+		if !m.TOTPEnabled {
+			return sdk.NewErrorFrom(sdk.ErrMFAInvalid, "TOTP is not enabled")
+		}
+		counter, ok := ValidateTOTP(m.TOTPSecret, v.TOTP, now, m.TOTPLastCounter)
+		if !ok {
+			return sdk.NewErrorFrom(sdk.ErrMFAInvalid, "invalid TOTP code")
+		}
+		m.TOTPLastCounter = counter
+	case v.RecoveryCode != "":
+		if !UseRecoveryCode(m, v.RecoveryCode) {
+			return sdk.NewErrorFrom(sdk.ErrMFAInvalid, "invalid recovery code")
+		}
+	case v.WebAuthn != nil:
+		challenge := consumeChallenge(store, username)
+		for i := range m.WebAuthnCredentials {
+			if m.WebAuthnCredentials[i].ID == v.WebAuthn.CredentialID {
+				return VerifyAssertion(&m.WebAuthnCredentials[i], *v.WebAuthn, challenge, rp)
+			}
+		}
+		return sdk.NewErrorFrom(sdk.ErrMFAInvalid, "unknown security key")
+	}
+	return nil
+}
+
+// Register checks the response of a security key to the pending challenge of the user and adds it to its second
+// factors
+func Register(store cache.Store, username string, m *sdk.MFA, r sdk.WebAuthnRegistration, rp RelyingParty) (sdk.WebAuthnCredential, error) {
+	cred, err := VerifyRegistration(r, consumeChallenge(store, username), rp)
+	if err != nil {
+		return cred, err
+	}
+	for _, c := range m.WebAuthnCredentials {
+		if c.ID == cred.ID {
+			return cred, sdk.NewErrorFrom(sdk.ErrWrongRequest, "security key already registered")
+		}
+	}
+	m.WebAuthnCredentials = append(m.WebAuthnCredentials, cred)
+	return cred, nil
+}
+
+// CredentialIDs returns the ids of the security keys of a user
+func CredentialIDs(m sdk.MFA) []string {
+	ids := make([]string, len(m.WebAuthnCredentials))
+	for i, c := range m.WebAuthnCredentials {
+		ids[i] = c.ID
+	}
+	return ids
+}
+
+// Status returns the second factors of a user, without their secrets
+func Status(m sdk.MFA, required bool) sdk.MFAStatus {
+	s := sdk.MFAStatus{
+		TOTP:                m.TOTPEnabled,
+		WebAuthnCredentials: make([]sdk.WebAuthnCredential, len(m.WebAuthnCredentials)),
+		RecoveryCodes:       len(m.RecoveryCodes),
+		Required:            required,
+	}
+	for i, c := range m.WebAuthnCredentials {
+		c.PublicKey = nil
+		s.WebAuthnCredentials[i] = c
+	}
+	return s
+}
@@ -0,0 +1,141 @@
+package mfa
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func TestTOTP(t *testing.T) {
+	// Test vectors of RFC 6238 truncated to 6 digits, the secret is "12345678901234567890"
+	secret := "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+	for ts, code := range map[int64]string{
+		59:         "287082",
+		1111111109: "081804",
+		1234567890: "005924",
+		2000000000: "279037",
+	} {
+		c, err := TOTPCode(secret, ts/totpPeriod)
+		assert.NoError(t, err)
+		assert.Equal(t, code, c)
+	}
+
+	now := time.Unix(1234567890, 0)
+	counter, ok := ValidateTOTP(secret, "005924", now, 0)
+	assert.True(t, ok)
+	assert.Equal(t, int64(1234567890/totpPeriod), counter)
+
+	// Accepted in the next period, but never twice
+	_, ok = ValidateTOTP(secret, "005924", now.Add(totpPeriod*time.Second), 0)
+	assert.True(t, ok)
+	_, ok = ValidateTOTP(secret, "005924", now, counter)
+	assert.False(t, ok)
+	_, ok = ValidateTOTP(secret, "005924", now.Add(2*totpPeriod*time.Second), 0)
+	assert.False(t, ok)
+	_, ok = ValidateTOTP(secret, "5924", now, 0)
+	assert.False(t, ok)
+
+	s, err := NewTOTPSecret()
+	assert.NoError(t, err)
+	assert.Len(t, s, 32)
+	assert.Equal(t, "otpauth://totp/CDS:john.doe?issuer=CDS&secret="+s, TOTPURL("CDS", "john.doe", s))
+}
+
+func TestRecoveryCodes(t *testing.T) {
+	codes, hashes, err := NewRecoveryCodes()
+	assert.NoError(t, err)
+	assert.Len(t, codes, recoveryCodesCount)
+	assert.Len(t, codes[0], 2*recoveryCodeHalfWidth+1)
+
+	m := sdk.MFA{RecoveryCodes: hashes}
+	assert.False(t, UseRecoveryCode(&m, "wrong-code"))
+	assert.True(t, UseRecoveryCode(&m, " "+codes[3]+" "))
+	assert.Len(t, m.RecoveryCodes, recoveryCodesCount-1)
+	assert.False(t, UseRecoveryCode(&m, codes[3]))
+}
+
+type testSecurityKey struct {
+	key       *ecdsa.PrivateKey
+	id        []byte
+	signCount uint32
+}
+
+func (k *testSecurityKey) ceremony(typ, challenge string, rp RelyingParty) (string, []byte, []byte) {
+	clientData, _ := json.Marshal(clientData{Type: typ, Challenge: challenge, Origin: rp.Origin})
+	rpIDHash := sha256.Sum256([]byte(rp.ID))
+	authData := append(rpIDHash[:], flagUserPresent, 0, 0, 0, 0)
+	binary.BigEndian.PutUint32(authData[33:], k.signCount)
+	return base64.RawURLEncoding.EncodeToString(clientData), clientData, authData
+}
+
+func (k *testSecurityKey) register(challenge string, rp RelyingParty) sdk.WebAuthnRegistration {
+	encodedClientData, _, authData := k.ceremony("webauthn.create", challenge, rp)
+	publicKey, _ := x509.MarshalPKIXPublicKey(&k.key.PublicKey)
+	return sdk.WebAuthnRegistration{
+		Name:              "my key",
+		CredentialID:      base64.RawURLEncoding.EncodeToString(k.id),
+		ClientDataJSON:    encodedClientData,
+		AuthenticatorData: base64.RawURLEncoding.EncodeToString(authData),
+		PublicKey:         base64.RawURLEncoding.EncodeToString(publicKey),
+	}
+}
+
+func (k *testSecurityKey) assert(challenge string, rp RelyingParty) sdk.WebAuthnAssertion {
+	k.signCount++
+	encodedClientData, clientData, authData := k.ceremony("webauthn.get", challenge, rp)
+	clientDataHash := sha256.Sum256(clientData)
+	digest := sha256.Sum256(append(append([]byte{}, authData...), clientDataHash[:]...))
+	r, s, _ := ecdsa.Sign(rand.Reader, k.key, digest[:])
+	signature, _ := asn1.Marshal(struct{ R, S *big.Int }{r, s})
+	return sdk.WebAuthnAssertion{
+		CredentialID:      base64.RawURLEncoding.EncodeToString(k.id),
+		ClientDataJSON:    encodedClientData,
+		AuthenticatorData: base64.RawURLEncoding.EncodeToString(authData),
+		Signature:         base64.RawURLEncoding.EncodeToString(signature),
+	}
+}
+
+func TestWebAuthn(t *testing.T) {
+	rp, err := NewRelyingParty("https://cds.example.com:8443/ui")
+	assert.NoError(t, err)
+	assert.Equal(t, RelyingParty{ID: "cds.example.com", Origin: "https://cds.example.com:8443"}, rp)
+
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	k := &testSecurityKey{key: priv, id: []byte("credential-1")}
+
+	// Registration
+	_, err = VerifyRegistration(k.register("challenge-1", rp), "challenge-2", rp)
+	assert.Error(t, err)
+	_, err = VerifyRegistration(k.register("challenge-1", RelyingParty{ID: "evil.com", Origin: "https://evil.com"}), "challenge-1", rp)
+	assert.Error(t, err)
+	cred, err := VerifyRegistration(k.register("challenge-1", rp), "challenge-1", rp)
+	assert.NoError(t, err)
+	assert.Equal(t, base64.RawURLEncoding.EncodeToString(k.id), cred.ID)
+
+	// Authentication
+	assert.NoError(t, VerifyAssertion(&cred, k.assert("challenge-3", rp), "challenge-3", rp))
+	assert.Equal(t, uint32(1), cred.SignCount)
+	assert.Error(t, VerifyAssertion(&cred, k.assert("challenge-4", rp), "challenge-5", rp))
+
+	a := k.assert("challenge-6", rp)
+	a.Signature = k.assert("challenge-7", rp).Signature
+	assert.Error(t, VerifyAssertion(&cred, a, "challenge-6", rp))
+
+	// A cloned key gives a counter lower than the last one
+	k.signCount = 0
+	assert.Error(t, VerifyAssertion(&cred, k.assert("challenge-8", rp), "challenge-8", rp))
+}
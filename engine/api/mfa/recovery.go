@@ -0,0 +1,52 @@
+package mfa
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"strings"
+
+	"github.com/ovh/cds/sdk"
+)
+
+const (
+	recoveryCodesCount    = 10
+	recoveryCodeAlphabet  = "abcdefghijkmnpqrstuvwxyz23456789"
+	recoveryCodeHalfWidth = 5
+)
+
+// NewRecoveryCodes returns new recovery codes and their hashes, only the hashes are stored
+func NewRecoveryCodes() ([]string, []string, error) {
+	codes := make([]string, recoveryCodesCount)
+	hashes := make([]string, recoveryCodesCount)
+	for i := range codes {
+		btes := make([]byte, 2*recoveryCodeHalfWidth)
+		if _, err := rand.Read(btes); err != nil {
+			return nil, nil, sdk.WithStack(err)
+		}
+		for j := range btes {
+			btes[j] = recoveryCodeAlphabet[int(btes[j])%len(recoveryCodeAlphabet)]
+		}
+		codes[i] = string(btes[:recoveryCodeHalfWidth]) + "-" + string(btes[recoveryCodeHalfWidth:])
+		hashes[i] = hashRecoveryCode(codes[i])
+	}
+	return codes, hashes, nil
+}
+
+func hashRecoveryCode(code string) string {
+	sum := sha256.Sum256([]byte(strings.ToLower(strings.TrimSpace(code))))
+	return hex.EncodeToString(sum[:])
+}
+
+// UseRecoveryCode checks a recovery code and removes it from the codes of the user
+func UseRecoveryCode(m *sdk.MFA, code string) bool {
+	h := hashRecoveryCode(code)
+	for i := range m.RecoveryCodes {
+		if subtle.ConstantTimeCompare([]byte(m.RecoveryCodes[i]), []byte(h)) == 1 {
+			m.RecoveryCodes = append(m.RecoveryCodes[:i], m.RecoveryCodes[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,199 @@
+package mfa
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"math/big"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/ovh/cds/engine/api/cache"
+	"github.com/ovh/cds/sdk"
+)
+
+// WebAuthnTimeout is the time given to a user to answer a challenge with a security key
+const WebAuthnTimeout = 2 * time.Minute
+
+// flags of the authenticator data
+const (
+	flagUserPresent  = 0x01
+	authDataMinWidth = 37
+)
+
+// RelyingParty identifies the CDS UI to the security keys
+type RelyingParty struct {
+	ID     string
+	Origin string
+}
+
+// NewRelyingParty returns the relying party of the given UI URL
+func NewRelyingParty(uiURL string) (RelyingParty, error) {
+	u, err := url.Parse(uiURL)
+	if err != nil || u.Host == "" {
+		return RelyingParty{}, sdk.NewErrorFrom(sdk.ErrWrongRequest, "invalid UI URL %s", uiURL)
+	}
+	return RelyingParty{ID: u.Hostname(), Origin: u.Scheme + "://" + u.Host}, nil
+}
+
+type clientData struct {
+	Type      string `json:"type"`
+	Challenge string `json:"challenge"`
+	Origin    string `json:"origin"`
+}
+
+// NewChallenge returns a random challenge for a user, kept in cache until it is answered
+func NewChallenge(store cache.Store, username string) (string, error) {
+	btes := make([]byte, 32)
+	if _, err := rand.Read(btes); err != nil {
+		return "", sdk.WithStack(err)
+	}
+	challenge := base64.RawURLEncoding.EncodeToString(btes)
+	store.SetWithTTL(cache.Key("mfa", "webauthn", username), challenge, int(WebAuthnTimeout.Seconds()))
+	return challenge, nil
+}
+
+// consumeChallenge returns the pending challenge of a user, a challenge is answered only once
+func consumeChallenge(store cache.Store, username string) string {
+	k := cache.Key("mfa", "webauthn", username)
+	var challenge string
+	store.Get(k, &challenge)
+	store.Delete(k)
+	return challenge
+}
+
+func decode(s string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(strings.TrimRight(s, "="))
+}
+
+// verifyCeremony checks the client data and the authenticator data of a response to a challenge, and returns the
+// decoded client data and authenticator data
+func verifyCeremony(typ, encodedClientData, encodedAuthData, challenge string, rp RelyingParty) ([]byte, []byte, error) {
+	rawClientData, err := decode(encodedClientData)
+	if err != nil {
+		return nil, nil, sdk.NewErrorFrom(sdk.ErrMFAInvalid, "invalid client data")
+	}
+	var c clientData
+	if err := json.Unmarshal(rawClientData, &c); err != nil {
+		return nil, nil, sdk.NewErrorFrom(sdk.ErrMFAInvalid, "invalid client data")
+	}
+	if c.Type != typ {
+		return nil, nil, sdk.NewErrorFrom(sdk.ErrMFAInvalid, "invalid ceremony type %s", c.Type)
+	}
+	if challenge == "" || strings.TrimRight(c.Challenge, "=") != challenge {
+		return nil, nil, sdk.NewErrorFrom(sdk.ErrMFAInvalid, "invalid or expired challenge")
+	}
+	if c.Origin != rp.Origin {
+		return nil, nil, sdk.NewErrorFrom(sdk.ErrMFAInvalid, "invalid origin %s", c.Origin)
+	}
+
+	authData, err := decode(encodedAuthData)
+	if err != nil || len(authData) < authDataMinWidth {
+		return nil, nil, sdk.NewErrorFrom(sdk.ErrMFAInvalid, "invalid authenticator data")
+	}
+	rpIDHash := sha256.Sum256([]byte(rp.ID))
+	if !bytes.Equal(authData[:32], rpIDHash[:]) {
+		return nil, nil, sdk.NewErrorFrom(sdk.ErrMFAInvalid, "invalid relying party")
+	}
+	if authData[32]&flagUserPresent == 0 {
+		return nil, nil, sdk.NewErrorFrom(sdk.ErrMFAInvalid, "user presence is required")
+	}
+	return rawClientData, authData, nil
+}
+
+// VerifyRegistration checks the response of a security key to a registration challenge and returns the new
+// credential. The attestation of the key is not checked: any security key can be registered.
+func VerifyRegistration(r sdk.WebAuthnRegistration, challenge string, rp RelyingParty) (sdk.WebAuthnCredential, error) {
+	var cred sdk.WebAuthnCredential
+	_, authData, err := verifyCeremony("webauthn.create", r.ClientDataJSON, r.AuthenticatorData, challenge, rp)
+	if err != nil {
+		return cred, err
+	}
+	id, err := decode(r.CredentialID)
+	if err != nil || len(id) == 0 {
+		return cred, sdk.NewErrorFrom(sdk.ErrMFAInvalid, "invalid credential id")
+	}
+	publicKey, err := decode(r.PublicKey)
+	if err != nil {
+		return cred, sdk.NewErrorFrom(sdk.ErrMFAInvalid, "invalid public key")
+	}
+	if _, err := parsePublicKey(publicKey); err != nil {
+		return cred, err
+	}
+	return sdk.WebAuthnCredential{
+		ID:        base64.RawURLEncoding.EncodeToString(id),
+		Name:      r.Name,
+		PublicKey: publicKey,
+		SignCount: binary.BigEndian.Uint32(authData[33:37]),
+		Created:   time.Now(),
+	}, nil
+}
+
+// VerifyAssertion checks the response of a security key to an authentication challenge, and updates the signature
+// counter of the credential
+func VerifyAssertion(cred *sdk.WebAuthnCredential, a sdk.WebAuthnAssertion, challenge string, rp RelyingParty) error {
+	rawClientData, authData, err := verifyCeremony("webauthn.get", a.ClientDataJSON, a.AuthenticatorData, challenge, rp)
+	if err != nil {
+		return err
+	}
+	signature, err := decode(a.Signature)
+	if err != nil {
+		return sdk.NewErrorFrom(sdk.ErrMFAInvalid, "invalid signature")
+	}
+	key, err := parsePublicKey(cred.PublicKey)
+	if err != nil {
+		return err
+	}
+
+	clientDataHash := sha256.Sum256(rawClientData)
+	digest := sha256.Sum256(append(append([]byte{}, authData...), clientDataHash[:]...))
+	if !verifySignature(key, digest[:], signature) {
+		return sdk.NewErrorFrom(sdk.ErrMFAInvalid, "invalid signature")
+	}
+
+	// A counter that does not increase reveals a cloned security key, the keys without counter always give 0
+	signCount := binary.BigEndian.Uint32(authData[33:37])
+	if (signCount != 0 || cred.SignCount != 0) && signCount <= cred.SignCount {
+		return sdk.NewErrorFrom(sdk.ErrMFAInvalid, "invalid signature counter, the security key may be cloned")
+	}
+	cred.SignCount = signCount
+	return nil
+}
+
+// parsePublicKey reads a DER encoded public key, security keys use ES256 or RS256
+func parsePublicKey(der []byte) (crypto.PublicKey, error) {
+	key, err := x509.ParsePKIXPublicKey(der)
+	if err != nil {
+		return nil, sdk.NewErrorFrom(sdk.ErrMFAInvalid, "invalid public key")
+	}
+	switch key.(type) {
+	case *ecdsa.PublicKey, *rsa.PublicKey:
+		return key, nil
+	}
+	return nil, sdk.NewErrorFrom(sdk.ErrMFAInvalid, "unsupported public key algorithm")
+}
+
+func verifySignature(key crypto.PublicKey, digest, signature []byte) bool {
+	switch k := key.(type) {
+	case *ecdsa.PublicKey:
+		var sig struct {
+			R, S *big.Int
+		}
+		if _, err := asn1.Unmarshal(signature, &sig); err != nil {
+			return false
+		}
+		return ecdsa.Verify(k, digest, sig.R, sig.S)
+	case *rsa.PublicKey:
+		return rsa.VerifyPKCS1v15(k, crypto.SHA256, digest, signature) == nil
+	}
+	return false
+}
@@ -0,0 +1,93 @@
+package migrate
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// Backfill migrates existing data by small batches, so that the tables are never locked for long
+// while the API is running. The cursor of the last processed batch is saved with the migration:
+// an interrupted backfill is resumed, and several API instances running it share the batches.
+type Backfill struct {
+	// Migration is the name of the migration running the backfill
+	Migration string
+	// Size is the max number of rows given to Process, 100 by default
+	Size int
+	// Pause is the time to wait between two batches, to let the other queries run
+	Pause time.Duration
+	// Next returns the ids, ordered, of the next rows to migrate after the cursor
+	Next func(db gorp.SqlExecutor, cursor int64, size int) ([]int64, error)
+	// Process migrates the given rows, in the same transaction as the save of the cursor
+	Process func(db gorp.SqlExecutor, ids []int64) error
+}
+
+// Run runs the backfill until Next returns no row
+func (b Backfill) Run(ctx context.Context, db *gorp.DbMap) error {
+	if b.Size <= 0 {
+		b.Size = 100
+	}
+
+	var total int
+	for {
+		select {
+		case <-ctx.Done():
+			return sdk.WithStack(ctx.Err())
+		default:
+		}
+
+		n, err := b.batch(db)
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			log.Info("Migration [%s]: backfill done, %d rows migrated", b.Migration, total)
+			return nil
+		}
+		total += n
+
+		if b.Pause > 0 {
+			time.Sleep(b.Pause)
+		}
+	}
+}
+
+// batch processes the next batch and returns its number of rows
+func (b Backfill) batch(db *gorp.DbMap) (int, error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return 0, sdk.WithStack(err)
+	}
+	defer tx.Rollback() // nolint
+
+	// Lock the migration, the other instances wait for this batch to get the next one
+	var mig sdk.Migration
+	if err := tx.SelectOne(&mig, "SELECT * FROM cds_migration WHERE name = $1 FOR UPDATE", b.Migration); err != nil {
+		return 0, sdk.WrapError(err, "cannot lock migration %s", b.Migration)
+	}
+
+	ids, err := b.Next(tx, mig.Cursor, b.Size)
+	if err != nil {
+		return 0, sdk.WrapError(err, "cannot get next rows after %d", mig.Cursor)
+	}
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	if err := b.Process(tx, ids); err != nil {
+		return 0, sdk.WrapError(err, "cannot migrate rows %d to %d", ids[0], ids[len(ids)-1])
+	}
+
+	cursor := ids[len(ids)-1]
+	progress := fmt.Sprintf("Phase %s: backfilled up to %d", sdk.MigrationPhaseExec, cursor)
+	if _, err := tx.Exec("UPDATE cds_migration SET batch_cursor = $1, progress = $2 WHERE id = $3", cursor, progress, mig.ID); err != nil {
+		return 0, sdk.WrapError(err, "cannot save cursor of migration %s", b.Migration)
+	}
+
+	return len(ids), sdk.WithStack(tx.Commit())
+}
@@ -0,0 +1,62 @@
+package migrate_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/go-gorp/gorp"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/engine/api/bootstrap"
+	"github.com/ovh/cds/engine/api/migrate"
+	"github.com/ovh/cds/engine/api/test"
+	"github.com/ovh/cds/sdk"
+)
+
+func TestBackfill(t *testing.T) {
+	db, _, end := test.SetupPG(t, bootstrap.InitiliazeDB)
+	defer end()
+
+	table := "test_backfill_" + sdk.RandomString(8)
+	_, err := db.Exec(fmt.Sprintf("CREATE TABLE %s (id BIGINT PRIMARY KEY, old_value TEXT, new_value TEXT)", table))
+	assert.NoError(t, err)
+	defer db.Exec(fmt.Sprintf("DROP TABLE %s", table)) // nolint
+	for i := 1; i <= 25; i++ {
+		_, err := db.Exec(fmt.Sprintf("INSERT INTO %s (id, old_value) VALUES ($1, $2)", table), i, fmt.Sprintf("value-%d", i))
+		assert.NoError(t, err)
+	}
+
+	mig := sdk.Migration{Name: table, Release: "snapshot", Status: sdk.MigrationStatusInProgress, Phase: sdk.MigrationPhaseExec}
+	assert.NoError(t, migrate.Insert(db, &mig))
+	defer migrate.Delete(db, &mig) // nolint
+
+	var batches int
+	b := migrate.Backfill{
+		Migration: table,
+		Size:      10,
+		Next: func(db gorp.SqlExecutor, cursor int64, size int) ([]int64, error) {
+			var ids []int64
+			_, err := db.Select(&ids, fmt.Sprintf("SELECT id FROM %s WHERE id > $1 ORDER BY id LIMIT $2", table), cursor, size)
+			return ids, err
+		},
+		Process: func(db gorp.SqlExecutor, ids []int64) error {
+			batches++
+			_, err := db.Exec(fmt.Sprintf("UPDATE %s SET new_value = upper(old_value) WHERE id >= $1 AND id <= $2", table), ids[0], ids[len(ids)-1])
+			return err
+		},
+	}
+	assert.NoError(t, b.Run(context.Background(), db))
+	assert.Equal(t, 3, batches)
+
+	count, err := db.SelectInt(fmt.Sprintf("SELECT COUNT(id) FROM %s WHERE new_value IS NULL", table))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), count)
+
+	// The backfill is resumed from the saved cursor
+	m, err := migrate.GetByName(db, table)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(25), m.Cursor)
+	assert.NoError(t, b.Run(context.Background(), db))
+	assert.Equal(t, 3, batches)
+}
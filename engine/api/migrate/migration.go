@@ -34,14 +34,15 @@ func Add(migration sdk.Migration) {
 	migrations = append(migrations, migration)
 }
 
-// Run run all local migrations
+// Run run all local migrations, each one in its own goroutine.
+// A migration is run online: its phases (pre hook, ExecFunc, post hook) are saved in database, so that
+// a migration interrupted by a restart of the API resumes at its last phase. A migration in error stays
+// in progress in the failed phase, it will be retried on the next start unless it is canceled.
 func Run(ctx context.Context, db gorp.SqlExecutor, panicDump func(s string) (io.WriteCloser, error)) {
 	for _, migration := range migrations {
 		func(currentMigration sdk.Migration) {
 			sdk.GoRoutine(ctx, "migrate_"+migration.Name, func(contex context.Context) {
-				var mig *sdk.Migration
-				var errMig error
-				mig, errMig = GetByName(db, currentMigration.Name)
+				mig, errMig := GetByName(db, currentMigration.Name)
 				if errMig != nil {
 					log.Error("Cannot get migration %s : %v", currentMigration.Name, errMig)
 					return
@@ -51,6 +52,20 @@ func Run(ctx context.Context, db gorp.SqlExecutor, panicDump func(s string) (io.
 						log.Info("Migration> %s> Already done (status: %s)", currentMigration.Name, mig.Status)
 						return
 					}
+					currentMigration.ID = mig.ID
+					currentMigration.Created = mig.Created
+					currentMigration.Phase = mig.Phase
+					currentMigration.Cursor = mig.Cursor
+					// A migration reset to "TO DO" is restarted from the beginning
+					if mig.Status == sdk.MigrationStatusTodo {
+						if _, err := db.Exec("UPDATE cds_migration SET phase = '', batch_cursor = 0 WHERE id = $1", mig.ID); err != nil {
+							log.Error("Cannot reset migration %s : %v", currentMigration.Name, err)
+							return
+						}
+						currentMigration.Phase = ""
+						currentMigration.Cursor = 0
+					}
+					currentMigration.Status = sdk.MigrationStatusInProgress
 				} else {
 					currentMigration.Progress = "Begin"
 					currentMigration.Status = sdk.MigrationStatusInProgress
@@ -60,15 +75,20 @@ func Run(ctx context.Context, db gorp.SqlExecutor, panicDump func(s string) (io.
 					}
 				}
 				log.Info("Migration [%s]: begin", currentMigration.Name)
-				if err := currentMigration.ExecFunc(contex); err != nil {
+				if err := runPhases(contex, db, &currentMigration); err != nil {
 					log.Error("migration %s in ERROR : %v", currentMigration.Name, err)
 					currentMigration.Error = err.Error()
+					if err := saveProgress(db, &currentMigration); err != nil {
+						log.Error("Cannot update migration %s : %v", currentMigration.Name, err)
+					}
+					return
 				}
 				currentMigration.Progress = "Migration done"
+				currentMigration.Error = ""
 				currentMigration.Done = time.Now()
 				currentMigration.Status = sdk.MigrationStatusDone
 
-				if err := Update(db, &currentMigration); err != nil {
+				if err := saveProgress(db, &currentMigration); err != nil {
 					log.Error("Cannot update migration %s : %v", currentMigration.Name, err)
 				}
 				log.Info("Migration [%s]: Done", currentMigration.Name)
@@ -77,6 +97,60 @@ func Run(ctx context.Context, db gorp.SqlExecutor, panicDump func(s string) (io.
 	}
 }
 
+// runPhases runs the remaining phases of the migration, and saves each new phase in database
+func runPhases(ctx context.Context, db gorp.SqlExecutor, mig *sdk.Migration) error {
+	phases := []struct {
+		name string
+		f    func(ctx context.Context) error
+	}{
+		{sdk.MigrationPhasePre, mig.PreFunc},
+		{sdk.MigrationPhaseExec, mig.ExecFunc},
+		{sdk.MigrationPhasePost, mig.PostFunc},
+	}
+
+	var started bool
+	for _, p := range phases {
+		if !started && mig.Phase != "" && mig.Phase != p.name {
+			continue
+		}
+		started = true
+		if mig.Phase != p.name {
+			mig.Phase = p.name
+			mig.Progress = fmt.Sprintf("Phase %s", p.name)
+			if err := saveProgress(db, mig); err != nil {
+				return err
+			}
+		}
+		if p.f == nil {
+			continue
+		}
+		if err := p.f(ctx); err != nil {
+			return sdk.WrapError(err, "phase %s", p.name)
+		}
+	}
+	return nil
+}
+
+// saveProgress updates the migration without overriding the cursor saved by a backfill
+func saveProgress(db gorp.SqlExecutor, mig *sdk.Migration) error {
+	cursor, err := db.SelectInt("SELECT batch_cursor FROM cds_migration WHERE id = $1", mig.ID)
+	if err != nil {
+		return sdk.WrapError(err, "cannot get cursor of migration %s", mig.Name)
+	}
+	mig.Cursor = cursor
+	return Update(db, mig)
+}
+
+// IsDone returns true if the migration is done. It allows the code writing both old and new data during an
+// online migration to switch to the new data once the backfill is done.
+func IsDone(db gorp.SqlExecutor, name string) (bool, error) {
+	mig, err := GetByName(db, name)
+	if err != nil {
+		return false, err
+	}
+	return mig != nil && mig.Status == sdk.MigrationStatusDone, nil
+}
+
 // CleanMigrationsList Delete all elements in local migrations
 func CleanMigrationsList() {
 	migrations = []sdk.Migration{}
@@ -84,7 +158,7 @@ func CleanMigrationsList() {
 
 // Status returns monitoring status, if there are cds migration in progress it returns WARN
 func Status(db gorp.SqlExecutor) sdk.MonitoringStatusLine {
-	count, err := db.SelectInt("SELECT COUNT(id) FROM cds_migration WHERE status <> $1 AND status <> $2", sdk.MigrationStatusDone, sdk.MigrationStatusCanceled)
+	count, err := CountInProgress(db)
 	if err != nil {
 		return sdk.MonitoringStatusLine{Component: "CDS Migration", Status: sdk.MonitoringStatusWarn, Value: fmt.Sprintf("KO Cannot request in database : %v", err)}
 	}
@@ -94,3 +168,9 @@ func Status(db gorp.SqlExecutor) sdk.MonitoringStatusLine {
 	}
 	return sdk.MonitoringStatusLine{Component: "Nb of CDS Migrations in progress", Value: fmt.Sprintf("%d", count), Status: status}
 }
+
+// CountInProgress returns the number of cds migrations neither done nor canceled
+func CountInProgress(db gorp.SqlExecutor) (int64, error) {
+	count, err := db.SelectInt("SELECT COUNT(id) FROM cds_migration WHERE status <> $1 AND status <> $2", sdk.MigrationStatusDone, sdk.MigrationStatusCanceled)
+	return count, sdk.WithStack(err)
+}
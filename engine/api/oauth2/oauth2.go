@@ -0,0 +1,173 @@
+package oauth2
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// token is an access token kept in memory by the API
+type token struct {
+	value  string
+	ttl    time.Duration
+	expire time.Time
+}
+
+// renewAt returns the date from which a new token should be requested (at half of its ttl), so that the jobs
+// always get a token valid for a while
+func (t token) renewAt() time.Time {
+	return t.expire.Add(-t.ttl / 2)
+}
+
+var (
+	tokensMutex sync.Mutex
+	tokens      = map[string]token{}
+	httpClient  = &http.Client{Timeout: 30 * time.Second}
+)
+
+type config struct {
+	tokenURL     string
+	clientID     string
+	clientSecret string
+	scopes       string
+	audience     string
+}
+
+func newConfig(pi sdk.ProjectIntegration) (config, error) {
+	c := config{
+		tokenURL:     pi.Config[sdk.OAuth2ConfigTokenURL].Value,
+		clientID:     pi.Config[sdk.OAuth2ConfigClientID].Value,
+		clientSecret: pi.Config[sdk.OAuth2ConfigClientSecret].Value,
+		scopes:       strings.Join(strings.Fields(pi.Config[sdk.OAuth2ConfigScopes].Value), " "),
+		audience:     pi.Config[sdk.OAuth2ConfigAudience].Value,
+	}
+	if c.tokenURL == "" {
+		return c, sdk.NewErrorFrom(sdk.ErrWrongRequest, "missing token url on integration %s", pi.Name)
+	}
+	if c.clientID == "" || c.clientSecret == "" {
+		return c, sdk.NewErrorFrom(sdk.ErrWrongRequest, "missing client id or client secret on integration %s", pi.Name)
+	}
+	return c, nil
+}
+
+// cacheKey identifies a token for a given configuration, so a configuration update invalidates the token
+func (c config) cacheKey(pi sdk.ProjectIntegration) string {
+	return fmt.Sprintf("%d/%s/%s/%s/%s", pi.ID, c.tokenURL, c.clientID, c.scopes, c.audience)
+}
+
+type tokenResponse struct {
+	AccessToken string `json:"access_token"`
+	ExpiresIn   int64  `json:"expires_in"`
+}
+
+type errorResponse struct {
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+// requestToken gets a new access token from the authorization server with the client credentials grant
+func requestToken(pi sdk.ProjectIntegration, c config) (token, error) {
+	form := url.Values{}
+	form.Set("grant_type", "client_credentials")
+	if c.scopes != "" {
+		form.Set("scope", c.scopes)
+	}
+	if c.audience != "" {
+		form.Set("audience", c.audience)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, c.tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return token{}, sdk.NewErrorFrom(sdk.ErrWrongRequest, "invalid token url %s on integration %s", c.tokenURL, pi.Name)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	req.SetBasicAuth(url.QueryEscape(c.clientID), url.QueryEscape(c.clientSecret))
+
+	res, err := httpClient.Do(req)
+	if err != nil {
+		return token{}, sdk.WrapError(err, "unable to request an access token for integration %s", pi.Name)
+	}
+	defer res.Body.Close()
+
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return token{}, sdk.WrapError(err, "unable to read access token response for integration %s", pi.Name)
+	}
+	if res.StatusCode >= 400 {
+		var e errorResponse
+		if err := json.Unmarshal(body, &e); err == nil && e.Error != "" {
+			return token{}, sdk.WithStack(fmt.Errorf("unable to get an access token for integration %s: %s %s", pi.Name, e.Error, e.ErrorDescription))
+		}
+		return token{}, sdk.WithStack(fmt.Errorf("unable to get an access token for integration %s: HTTP %d", pi.Name, res.StatusCode))
+	}
+
+	var r tokenResponse
+	if err := json.Unmarshal(body, &r); err != nil {
+		return token{}, sdk.WrapError(err, "invalid access token response for integration %s", pi.Name)
+	}
+	if r.AccessToken == "" {
+		return token{}, sdk.WithStack(fmt.Errorf("no access token returned for integration %s", pi.Name))
+	}
+
+	ttl := time.Duration(r.ExpiresIn) * time.Second
+	return token{
+		value:  r.AccessToken,
+		ttl:    ttl,
+		expire: time.Now().Add(ttl),
+	}, nil
+}
+
+// getToken returns the access token of the integration. Tokens are kept in memory until they reach half of their
+// ttl, the tokens without expiration are never kept.
+func getToken(pi sdk.ProjectIntegration, c config) (token, error) {
+	key := c.cacheKey(pi)
+	tokensMutex.Lock()
+	defer tokensMutex.Unlock()
+
+	if t, has := tokens[key]; has && time.Now().Before(t.renewAt()) {
+		return t, nil
+	}
+	delete(tokens, key)
+
+	t, err := requestToken(pi, c)
+	if err != nil {
+		return t, err
+	}
+	if t.ttl > 0 {
+		tokens[key] = t
+	}
+	return t, nil
+}
+
+// VariablePrefix returns the prefix of the variables given by an OAuth2 integration
+func VariablePrefix(integrationName string) string {
+	return "cds.oauth2." + integrationName + "."
+}
+
+// LoadToken gets an access token with the client credentials of the integration and returns it as the secret
+// variable cds.oauth2.<integration name>.access_token
+func LoadToken(pi sdk.ProjectIntegration) ([]sdk.Variable, error) {
+	c, err := newConfig(pi)
+	if err != nil {
+		return nil, err
+	}
+
+	t, err := getToken(pi, c)
+	if err != nil {
+		return nil, err
+	}
+
+	return []sdk.Variable{{
+		Name:  VariablePrefix(pi.Name) + "access_token",
+		Type:  sdk.SecretVariable,
+		Value: t.value,
+	}}, nil
+}
@@ -0,0 +1,63 @@
+package oauth2
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func TestLoadToken(t *testing.T) {
+	var calls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		id, secret, _ := r.BasicAuth()
+		if id != "my-client" || secret != "my-secret" || r.FormValue("grant_type") != "client_credentials" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"error":"invalid_client"}`)) // nolint
+			return
+		}
+		assert.Equal(t, "read write", r.FormValue("scope"))
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"access_token":"my-token","token_type":"Bearer","expires_in":3600}`)) // nolint
+	}))
+	defer srv.Close()
+
+	pi := sdk.ProjectIntegration{
+		ID:   1,
+		Name: "my-auth",
+		Config: sdk.IntegrationConfig{
+			sdk.OAuth2ConfigTokenURL:     {Value: srv.URL},
+			sdk.OAuth2ConfigClientID:     {Value: "my-client"},
+			sdk.OAuth2ConfigClientSecret: {Value: "my-secret"},
+			sdk.OAuth2ConfigScopes:       {Value: " read  write"},
+		},
+	}
+
+	vars, err := LoadToken(pi)
+	assert.NoError(t, err)
+	if assert.Len(t, vars, 1) {
+		assert.Equal(t, "cds.oauth2.my-auth.access_token", vars[0].Name)
+		assert.Equal(t, sdk.SecretVariable, vars[0].Type)
+		assert.Equal(t, "my-token", vars[0].Value)
+	}
+
+	// The token is kept until half of its ttl
+	_, err = LoadToken(pi)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, calls)
+
+	pi.Config[sdk.OAuth2ConfigClientSecret] = sdk.IntegrationConfigValue{Value: "wrong-secret"}
+	pi.ID = 2
+	_, err = LoadToken(pi)
+	assert.Error(t, err)
+	assert.Equal(t, 2, calls)
+
+	pi.Config[sdk.OAuth2ConfigClientSecret] = sdk.IntegrationConfigValue{}
+	_, err = LoadToken(pi)
+	assert.Error(t, err)
+	assert.Equal(t, 2, calls)
+}
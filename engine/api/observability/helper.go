@@ -133,6 +133,20 @@ func NewViewLastFloat64(name string, s *stats.Float64Measure, tags []tag.Key) *v
 	}
 }
 
+// LatencyBounds are the bounds of the distributions of durations, in milliseconds
+var LatencyBounds = []float64{100, 250, 500, 1000, 2500, 5000, 10000, 30000, 60000, 120000, 300000, 600000}
+
+// NewViewDistribution creates a new view via aggregation Distribution()
+func NewViewDistribution(name string, s *stats.Float64Measure, tags []tag.Key, bounds []float64) *view.View {
+	return &view.View{
+		Name:        name,
+		Description: s.Description(),
+		Measure:     s,
+		Aggregation: view.Distribution(bounds...),
+		TagKeys:     tags,
+	}
+}
+
 // NewViewCount creates a new view via aggregation Count()
 func NewViewCount(name string, s *stats.Int64Measure, tags []tag.Key) *view.View {
 	return &view.View{
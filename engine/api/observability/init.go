@@ -1,6 +1,7 @@
 package observability
 
 import (
+	"context"
 	"time"
 
 	"go.opencensus.io/exporter/jaeger"
@@ -17,13 +18,30 @@ var (
 	statsExporter *prometheus.Exporter
 )
 
-// Init the opencensus exporter
+// Init the opencensus exporters. The prometheus exporter is always initialized, so /mon/metrics is available
+// on every service, the otlp or jaeger exporter only if tracing is enabled
 func Init(cfg Configuration, serviceName string) error {
+	var err error
+	if statsExporter == nil {
+		statsExporter, err = prometheus.NewExporter(prometheus.Options{})
+		if err != nil {
+			return err
+		}
+		view.RegisterExporter(statsExporter)
+		if cfg.Exporter.Prometheus.ReporteringPeriod == 0 {
+			cfg.Exporter.Prometheus.ReporteringPeriod = 10
+		}
+		view.SetReportingPeriod(time.Duration(cfg.Exporter.Prometheus.ReporteringPeriod) * time.Second)
+	}
+
 	if !cfg.Enable {
 		return nil
 	}
 	traceEnable = true
-	var err error
+	if traceExporter == nil && cfg.Exporter.OTLP.Endpoint != "" {
+		log.Info("observability> initializing otlp exporter")
+		traceExporter = newOTLPExporter(context.Background(), cfg.Exporter.OTLP.Endpoint, cfg.Exporter.OTLP.Headers, serviceName)
+	}
 	if traceExporter == nil {
 		log.Info("observability> initializing jaeger exporter")
 		traceExporter, err = jaeger.NewExporter(jaeger.Options{
@@ -41,15 +59,5 @@ func Init(cfg Configuration, serviceName string) error {
 		},
 	)
 
-	statsExporter, err = prometheus.NewExporter(prometheus.Options{})
-	if err != nil {
-		return err
-	}
-	view.RegisterExporter(statsExporter)
-	if cfg.Exporter.Prometheus.ReporteringPeriod == 0 {
-		cfg.Exporter.Prometheus.ReporteringPeriod = 10
-	}
-	view.SetReportingPeriod(time.Duration(cfg.Exporter.Prometheus.ReporteringPeriod) * time.Second)
-
 	return nil
 }
@@ -0,0 +1,234 @@
+package observability
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.opencensus.io/trace"
+
+	"github.com/ovh/cds/sdk/log"
+)
+
+// otlpExporter sends the spans to an OpenTelemetry collector with the OTLP/HTTP protocol, JSON encoded
+type otlpExporter struct {
+	url         string
+	headers     map[string]string
+	serviceName string
+	client      *http.Client
+	spans       chan *trace.SpanData
+	batchSize   int
+}
+
+func newOTLPExporter(ctx context.Context, endpoint string, headers map[string]string, serviceName string) *otlpExporter {
+	e := &otlpExporter{
+		url:         strings.TrimSuffix(endpoint, "/") + "/v1/traces",
+		headers:     headers,
+		serviceName: serviceName,
+		client:      &http.Client{Timeout: 10 * time.Second},
+		spans:       make(chan *trace.SpanData, 2048),
+		batchSize:   512,
+	}
+	go e.run(ctx)
+	return e
+}
+
+// ExportSpan implements trace.Exporter, it never blocks: spans are dropped if the collector is too slow
+func (e *otlpExporter) ExportSpan(s *trace.SpanData) {
+	select {
+	case e.spans <- s:
+	default:
+	}
+}
+
+func (e *otlpExporter) run(ctx context.Context) {
+	tick := time.NewTicker(5 * time.Second)
+	defer tick.Stop()
+
+	batch := make([]*trace.SpanData, 0, e.batchSize)
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		if err := e.send(batch); err != nil {
+			log.Warning("observability> unable to send %d spans to %s: %v", len(batch), e.url, err)
+		}
+		batch = make([]*trace.SpanData, 0, e.batchSize)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			flush()
+			return
+		case s := <-e.spans:
+			batch = append(batch, s)
+			if len(batch) >= e.batchSize {
+				flush()
+			}
+		case <-tick.C:
+			flush()
+		}
+	}
+}
+
+func (e *otlpExporter) send(spans []*trace.SpanData) error {
+	btes, err := json.Marshal(otlpRequest(e.serviceName, spans))
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, e.url, bytes.NewReader(btes))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range e.headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close() // nolint
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("collector returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// OTLP JSON encoding of the ExportTraceServiceRequest message
+type (
+	otlpKeyValue struct {
+		Key   string    `json:"key"`
+		Value otlpValue `json:"value"`
+	}
+	otlpValue struct {
+		StringValue *string  `json:"stringValue,omitempty"`
+		BoolValue   *bool    `json:"boolValue,omitempty"`
+		IntValue    *string  `json:"intValue,omitempty"`
+		DoubleValue *float64 `json:"doubleValue,omitempty"`
+	}
+	otlpLink struct {
+		TraceID string `json:"traceId"`
+		SpanID  string `json:"spanId"`
+	}
+	otlpEvent struct {
+		TimeUnixNano string         `json:"timeUnixNano"`
+		Name         string         `json:"name"`
+		Attributes   []otlpKeyValue `json:"attributes,omitempty"`
+	}
+	otlpStatus struct {
+		Code    int    `json:"code,omitempty"`
+		Message string `json:"message,omitempty"`
+	}
+	otlpSpan struct {
+		TraceID           string         `json:"traceId"`
+		SpanID            string         `json:"spanId"`
+		ParentSpanID      string         `json:"parentSpanId,omitempty"`
+		Name              string         `json:"name"`
+		Kind              int            `json:"kind"`
+		StartTimeUnixNano string         `json:"startTimeUnixNano"`
+		EndTimeUnixNano   string         `json:"endTimeUnixNano"`
+		Attributes        []otlpKeyValue `json:"attributes,omitempty"`
+		Events            []otlpEvent    `json:"events,omitempty"`
+		Links             []otlpLink     `json:"links,omitempty"`
+		Status            otlpStatus     `json:"status"`
+	}
+	otlpScopeSpans struct {
+		Scope struct {
+			Name string `json:"name"`
+		} `json:"scope"`
+		Spans []otlpSpan `json:"spans"`
+	}
+	otlpResourceSpans struct {
+		Resource struct {
+			Attributes []otlpKeyValue `json:"attributes"`
+		} `json:"resource"`
+		ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+	}
+	otlpTraceRequest struct {
+		ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+	}
+)
+
+// OTLP span kinds and status codes
+const (
+	otlpSpanKindInternal = 1
+	otlpSpanKindServer   = 2
+	otlpSpanKindClient   = 3
+	otlpStatusCodeError  = 2
+)
+
+func otlpRequest(serviceName string, spans []*trace.SpanData) otlpTraceRequest {
+	var rs otlpResourceSpans
+	rs.Resource.Attributes = []otlpKeyValue{otlpAttribute("service.name", serviceName)}
+	var ss otlpScopeSpans
+	ss.Scope.Name = "github.com/ovh/cds"
+	for _, s := range spans {
+		ss.Spans = append(ss.Spans, otlpNewSpan(s))
+	}
+	rs.ScopeSpans = []otlpScopeSpans{ss}
+	return otlpTraceRequest{ResourceSpans: []otlpResourceSpans{rs}}
+}
+
+func otlpNewSpan(s *trace.SpanData) otlpSpan {
+	span := otlpSpan{
+		TraceID:           hex.EncodeToString(s.TraceID[:]),
+		SpanID:            hex.EncodeToString(s.SpanID[:]),
+		Name:              s.Name,
+		Kind:              otlpSpanKindInternal,
+		StartTimeUnixNano: strconv.FormatInt(s.StartTime.UnixNano(), 10),
+		EndTimeUnixNano:   strconv.FormatInt(s.EndTime.UnixNano(), 10),
+	}
+	if s.ParentSpanID != (trace.SpanID{}) {
+		span.ParentSpanID = hex.EncodeToString(s.ParentSpanID[:])
+	}
+	switch s.SpanKind {
+	case trace.SpanKindServer:
+		span.Kind = otlpSpanKindServer
+	case trace.SpanKindClient:
+		span.Kind = otlpSpanKindClient
+	}
+	for k, v := range s.Attributes {
+		span.Attributes = append(span.Attributes, otlpAttribute(k, v))
+	}
+	for _, a := range s.Annotations {
+		ev := otlpEvent{TimeUnixNano: strconv.FormatInt(a.Time.UnixNano(), 10), Name: a.Message}
+		for k, v := range a.Attributes {
+			ev.Attributes = append(ev.Attributes, otlpAttribute(k, v))
+		}
+		span.Events = append(span.Events, ev)
+	}
+	for _, l := range s.Links {
+		span.Links = append(span.Links, otlpLink{TraceID: hex.EncodeToString(l.TraceID[:]), SpanID: hex.EncodeToString(l.SpanID[:])})
+	}
+	if s.Code != trace.StatusCodeOK {
+		span.Status = otlpStatus{Code: otlpStatusCodeError, Message: s.Message}
+	}
+	return span
+}
+
+func otlpAttribute(k string, v interface{}) otlpKeyValue {
+	kv := otlpKeyValue{Key: k}
+	switch x := v.(type) {
+	case bool:
+		kv.Value.BoolValue = &x
+	case int64:
+		i := strconv.FormatInt(x, 10)
+		kv.Value.IntValue = &i
+	case float64:
+		kv.Value.DoubleValue = &x
+	case string:
+		kv.Value.StringValue = &x
+	default:
+		str := fmt.Sprintf("%v", x)
+		kv.Value.StringValue = &str
+	}
+	return kv
+}
@@ -22,6 +22,17 @@ func New(ctx context.Context, serviceName, name string, sampler trace.Sampler, s
 		trace.WithSpanKind(spanKind))
 }
 
+// NewWithRemoteParent may start a tracing span, child of a span started by another service
+func NewWithRemoteParent(ctx context.Context, name string, parent trace.SpanContext, sampler trace.Sampler, spanKind int) (context.Context, *trace.Span) {
+	if !traceEnable {
+		return ctx, nil
+	}
+	ctx, span := trace.StartSpanWithRemoteParent(ctx, name, parent,
+		trace.WithSampler(sampler),
+		trace.WithSpanKind(spanKind))
+	return tracingutils.SpanContextToContext(ctx, span.SpanContext()), span
+}
+
 // Start may start a tracing span
 func Start(ctx context.Context, serviceName string, w http.ResponseWriter, req *http.Request, opt Options, db gorp.SqlExecutor, store cache.Store) (context.Context, error) {
 	if !traceEnable || !opt.Enable {
@@ -24,6 +24,10 @@ type Configuration struct {
 		Prometheus struct {
 			ReporteringPeriod int `toml:"ReporteringPeriod" default:"10" json:"reporteringPeriod"`
 		} `json:"prometheus"`
+		OTLP struct {
+			Endpoint string            `toml:"endpoint" comment:"OpenTelemetry collector OTLP/HTTP endpoint, example: http://localhost:4318. If set, spans are sent to this collector instead of jaeger" json:"endpoint"`
+			Headers  map[string]string `toml:"headers" comment:"Headers added to the requests sent to the collector, example: authentication headers" json:"-"`
+		} `json:"otlp"`
 	} `json:"exporter"`
 	SamplingProbability float64 `json:"samplingProbability"`
 	Name                string  `toml:"name" default:"cdsinstance" comment:"Name of this CDS Instance. This value is added to /mon/metrics as label named 'cds' on each series" json:"name"`
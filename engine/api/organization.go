@@ -0,0 +1,362 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/go-gorp/gorp"
+	"github.com/gorilla/mux"
+
+	"github.com/ovh/cds/engine/api/cache"
+	"github.com/ovh/cds/engine/api/integration"
+	"github.com/ovh/cds/engine/api/organization"
+	"github.com/ovh/cds/engine/api/permission"
+	"github.com/ovh/cds/engine/api/project"
+	"github.com/ovh/cds/engine/api/usage"
+	"github.com/ovh/cds/engine/api/user"
+	"github.com/ovh/cds/engine/service"
+	"github.com/ovh/cds/sdk"
+)
+
+// checkOrganizationAdmin returns an error if the user is neither a CDS administrator nor an administrator of all the
+// given organizations, zero ids are ignored
+func checkOrganizationAdmin(db gorp.SqlExecutor, u *sdk.User, organizationIDs ...int64) error {
+	if u.Admin {
+		return nil
+	}
+	for _, id := range organizationIDs {
+		if id == 0 {
+			continue
+		}
+		ok, err := organization.IsAdmin(db, id, u.ID)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return sdk.WrapError(sdk.ErrForbidden, "user %s is not administrator of organization %d", u.Username, id)
+		}
+	}
+	return nil
+}
+
+// deleteOrganizationPermissionCache drops the permissions of all the users, they are computed again with the
+// administrators and the projects of the organizations
+func (api *API) deleteOrganizationPermissionCache() {
+	api.Cache.DeleteAll(cache.Key("users", "*", "perms"))
+}
+
+func (api *API) getAdminOrganizationsHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		orgs, err := organization.LoadAll(api.mustDB())
+		if err != nil {
+			return err
+		}
+		return service.WriteJSON(w, orgs, http.StatusOK)
+	}
+}
+
+func (api *API) postAdminOrganizationHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		var o sdk.Organization
+		if err := service.UnmarshalBody(r, &o); err != nil {
+			return sdk.WrapError(err, "cannot unmarshal body")
+		}
+		if err := o.IsValid(); err != nil {
+			return err
+		}
+		if _, err := organization.LoadByName(api.mustDB(), o.Name); err == nil {
+			return sdk.NewErrorFrom(sdk.ErrConflict, "organization %s already exists", o.Name)
+		} else if !sdk.ErrorIs(err, sdk.ErrNotFound) {
+			return err
+		}
+
+		o.ID = 0
+		o.Created = time.Now()
+		if err := organization.Insert(api.mustDB(), &o); err != nil {
+			return err
+		}
+		return service.WriteJSON(w, o, http.StatusCreated)
+	}
+}
+
+func (api *API) getOrganizationHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		o, err := organization.LoadByName(api.mustDB(), mux.Vars(r)["name"])
+		if err != nil {
+			return err
+		}
+		if err := checkOrganizationAdmin(api.mustDB(), deprecatedGetUser(ctx), o.ID); err != nil {
+			return err
+		}
+		return service.WriteJSON(w, o, http.StatusOK)
+	}
+}
+
+// putOrganizationHandler updates an organization, its quotas can only be changed by a CDS administrator
+func (api *API) putOrganizationHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		u := deprecatedGetUser(ctx)
+		old, err := organization.LoadByName(api.mustDB(), mux.Vars(r)["name"])
+		if err != nil {
+			return err
+		}
+		if err := checkOrganizationAdmin(api.mustDB(), u, old.ID); err != nil {
+			return err
+		}
+
+		var o sdk.Organization
+		if err := service.UnmarshalBody(r, &o); err != nil {
+			return sdk.WrapError(err, "cannot unmarshal body")
+		}
+		if o.Name == "" {
+			o.Name = old.Name
+		}
+		if !u.Admin {
+			o.MaxConcurrentJobs = old.MaxConcurrentJobs
+			o.MaxStorage = old.MaxStorage
+			o.MaxBuildMinutes = old.MaxBuildMinutes
+		}
+		if err := o.IsValid(); err != nil {
+			return err
+		}
+		if o.Name != old.Name {
+			if _, err := organization.LoadByName(api.mustDB(), o.Name); err == nil {
+				return sdk.NewErrorFrom(sdk.ErrConflict, "organization %s already exists", o.Name)
+			} else if !sdk.ErrorIs(err, sdk.ErrNotFound) {
+				return err
+			}
+		}
+
+		o.ID = old.ID
+		o.Created = old.Created
+		if err := organization.Update(api.mustDB(), &o); err != nil {
+			return err
+		}
+		o.Admins = old.Admins
+		o.Projects = old.Projects
+		return service.WriteJSON(w, o, http.StatusOK)
+	}
+}
+
+// deleteOrganizationHandler deletes an organization without projects
+func (api *API) deleteOrganizationHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		o, err := organization.LoadByName(api.mustDB(), mux.Vars(r)["name"])
+		if err != nil {
+			return err
+		}
+		if len(o.Projects) > 0 {
+			return sdk.NewErrorFrom(sdk.ErrWrongRequest, "organization %s still has %d projects", o.Name, len(o.Projects))
+		}
+
+		tx, err := api.mustDB().Begin()
+		if err != nil {
+			return sdk.WrapError(err, "cannot start transaction")
+		}
+		defer tx.Rollback() // nolint
+
+		if err := organization.Delete(tx, o); err != nil {
+			return err
+		}
+		if err := tx.Commit(); err != nil {
+			return sdk.WrapError(err, "cannot commit transaction")
+		}
+		api.deleteOrganizationPermissionCache()
+
+		return service.WriteJSON(w, nil, http.StatusOK)
+	}
+}
+
+func (api *API) postOrganizationAdminHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		o, err := organization.LoadByName(api.mustDB(), vars["name"])
+		if err != nil {
+			return err
+		}
+		if err := checkOrganizationAdmin(api.mustDB(), deprecatedGetUser(ctx), o.ID); err != nil {
+			return err
+		}
+
+		admin, err := user.LoadUserWithoutAuth(api.mustDB(), vars["username"])
+		if err != nil {
+			return sdk.WrapError(err, "cannot load user %s", vars["username"])
+		}
+		if err := organization.AddAdmin(api.mustDB(), o.ID, admin.ID); err != nil {
+			return err
+		}
+		api.deleteOrganizationPermissionCache()
+
+		o, err = organization.LoadByID(api.mustDB(), o.ID)
+		if err != nil {
+			return err
+		}
+		return service.WriteJSON(w, o, http.StatusOK)
+	}
+}
+
+func (api *API) deleteOrganizationAdminHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		o, err := organization.LoadByName(api.mustDB(), vars["name"])
+		if err != nil {
+			return err
+		}
+		if err := checkOrganizationAdmin(api.mustDB(), deprecatedGetUser(ctx), o.ID); err != nil {
+			return err
+		}
+
+		admin, err := user.LoadUserWithoutAuth(api.mustDB(), vars["username"])
+		if err != nil {
+			return sdk.WrapError(err, "cannot load user %s", vars["username"])
+		}
+		if err := organization.RemoveAdmin(api.mustDB(), o.ID, admin.ID); err != nil {
+			return err
+		}
+		api.deleteOrganizationPermissionCache()
+
+		o, err = organization.LoadByID(api.mustDB(), o.ID)
+		if err != nil {
+			return err
+		}
+		return service.WriteJSON(w, o, http.StatusOK)
+	}
+}
+
+// postOrganizationProjectHandler attaches a project to an organization, the public integration models of the
+// organization are added to the project. An organization administrator can only attach the projects they can
+// already manage which do not belong to another organization.
+func (api *API) postOrganizationProjectHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		u := deprecatedGetUser(ctx)
+		o, err := organization.LoadByName(api.mustDB(), vars["name"])
+		if err != nil {
+			return err
+		}
+		if err := checkOrganizationAdmin(api.mustDB(), u, o.ID); err != nil {
+			return err
+		}
+
+		proj, err := project.Load(api.mustDB(), api.Cache, vars["key"], u)
+		if err != nil {
+			return sdk.WrapError(err, "cannot load project %s", vars["key"])
+		}
+		if !u.Admin && (u.Permissions.ProjectsPerm[proj.Key] < permission.PermissionReadWriteExecute || proj.OrganizationID != 0) {
+			return sdk.WrapError(sdk.ErrForbidden, "user %s cannot attach project %s to organization %s", u.Username, proj.Key, o.Name)
+		}
+
+		tx, err := api.mustDB().Begin()
+		if err != nil {
+			return sdk.WrapError(err, "cannot start transaction")
+		}
+		defer tx.Rollback() // nolint
+
+		if err := organization.SetProject(tx, proj.ID, o.ID); err != nil {
+			return err
+		}
+		proj.OrganizationID = o.ID
+
+		models, err := integration.LoadModels(tx)
+		if err != nil {
+			return err
+		}
+		for _, m := range models {
+			if m.OrganizationID != o.ID {
+				continue
+			}
+			if err := propagatePublicIntegrationModelOnProject(tx, api.Cache, m, *proj, u); err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Commit(); err != nil {
+			return sdk.WrapError(err, "cannot commit transaction")
+		}
+		api.deleteOrganizationPermissionCache()
+
+		o, err = organization.LoadByID(api.mustDB(), o.ID)
+		if err != nil {
+			return err
+		}
+		return service.WriteJSON(w, o, http.StatusOK)
+	}
+}
+
+// deleteOrganizationProjectHandler detaches a project from an organization
+func (api *API) deleteOrganizationProjectHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		o, err := organization.LoadByName(api.mustDB(), vars["name"])
+		if err != nil {
+			return err
+		}
+		if err := checkOrganizationAdmin(api.mustDB(), deprecatedGetUser(ctx), o.ID); err != nil {
+			return err
+		}
+
+		proj, err := project.Load(api.mustDB(), api.Cache, vars["key"], deprecatedGetUser(ctx))
+		if err != nil {
+			return sdk.WrapError(err, "cannot load project %s", vars["key"])
+		}
+		if proj.OrganizationID != o.ID {
+			return sdk.NewErrorFrom(sdk.ErrWrongRequest, "project %s is not in organization %s", proj.Key, o.Name)
+		}
+		if err := organization.SetProject(api.mustDB(), proj.ID, 0); err != nil {
+			return err
+		}
+		api.deleteOrganizationPermissionCache()
+
+		o, err = organization.LoadByID(api.mustDB(), o.ID)
+		if err != nil {
+			return err
+		}
+		return service.WriteJSON(w, o, http.StatusOK)
+	}
+}
+
+// getOrganizationUsageHandler returns the usage of the projects of an organization during a month, the current month
+// by default, with the quotas of the organization
+func (api *API) getOrganizationUsageHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		o, err := organization.LoadByName(api.mustDB(), mux.Vars(r)["name"])
+		if err != nil {
+			return err
+		}
+		if err := checkOrganizationAdmin(api.mustDB(), deprecatedGetUser(ctx), o.ID); err != nil {
+			return err
+		}
+		month, err := sdk.ParseUsageMonth(r.FormValue("month"))
+		if err != nil {
+			return err
+		}
+
+		report := sdk.OrganizationUsageReport{
+			Organization:      o.Name,
+			Month:             month,
+			MaxBuildMinutes:   o.MaxBuildMinutes,
+			MaxStorage:        o.MaxStorage,
+			MaxConcurrentJobs: o.MaxConcurrentJobs,
+		}
+		report.Projects, err = usage.LoadByOrganization(api.mustDB(), o.ID, month)
+		if err != nil {
+			return err
+		}
+		var buildSeconds int64
+		for _, p := range report.Projects {
+			buildSeconds += p.BuildSeconds
+		}
+		report.BuildMinutes = (buildSeconds + 59) / 60
+		report.Storage, err = organization.StorageUsage(api.mustDB(), o.ID)
+		if err != nil {
+			return err
+		}
+		report.BuildingJobs, err = organization.CountBuildingJobs(api.mustDB(), o.ID)
+		if err != nil {
+			return err
+		}
+
+		return service.WriteJSON(w, report, http.StatusOK)
+	}
+}
@@ -0,0 +1,208 @@
+package organization
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/usage"
+	"github.com/ovh/cds/sdk"
+)
+
+// Insert creates an organization
+func Insert(db gorp.SqlExecutor, o *sdk.Organization) error {
+	return sdk.WrapError(db.Insert(o), "cannot insert organization %s", o.Name)
+}
+
+// Update saves the description and the quotas of an organization
+func Update(db gorp.SqlExecutor, o *sdk.Organization) error {
+	_, err := db.Update(o)
+	return sdk.WrapError(err, "cannot update organization %s", o.Name)
+}
+
+// Delete removes an organization, the worker models and the integration models scoped to it become global again
+func Delete(db gorp.SqlExecutor, o *sdk.Organization) error {
+	if _, err := db.Exec("UPDATE worker_model SET organization_id = 0 WHERE organization_id = $1", o.ID); err != nil {
+		return sdk.WrapError(err, "cannot detach worker models from organization %s", o.Name)
+	}
+	if _, err := db.Exec("UPDATE integration_model SET organization_id = 0 WHERE organization_id = $1", o.ID); err != nil {
+		return sdk.WrapError(err, "cannot detach integration models from organization %s", o.Name)
+	}
+	_, err := db.Delete(o)
+	return sdk.WrapError(err, "cannot delete organization %s", o.Name)
+}
+
+// LoadAll returns all the organizations
+func LoadAll(db gorp.SqlExecutor) ([]sdk.Organization, error) {
+	orgs := []sdk.Organization{}
+	if _, err := db.Select(&orgs, "SELECT * FROM organization ORDER BY name"); err != nil {
+		return nil, sdk.WrapError(err, "cannot load organizations")
+	}
+	return orgs, nil
+}
+
+// LoadByName returns an organization with its administrators and its projects
+func LoadByName(db gorp.SqlExecutor, name string) (*sdk.Organization, error) {
+	return load(db, "SELECT * FROM organization WHERE name = $1", name)
+}
+
+// LoadByID returns an organization with its administrators and its projects
+func LoadByID(db gorp.SqlExecutor, id int64) (*sdk.Organization, error) {
+	return load(db, "SELECT * FROM organization WHERE id = $1", id)
+}
+
+func load(db gorp.SqlExecutor, query string, arg interface{}) (*sdk.Organization, error) {
+	var o sdk.Organization
+	if err := db.SelectOne(&o, query, arg); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, sdk.NewErrorFrom(sdk.ErrNotFound, "organization %v not found", arg)
+		}
+		return nil, sdk.WrapError(err, "cannot load organization %v", arg)
+	}
+
+	if _, err := db.Select(&o.Admins, `
+		SELECT "user".username FROM "user"
+		JOIN organization_admin ON organization_admin.user_id = "user".id
+		WHERE organization_admin.organization_id = $1
+		ORDER BY "user".username`, o.ID); err != nil {
+		return nil, sdk.WrapError(err, "cannot load administrators of organization %s", o.Name)
+	}
+	if _, err := db.Select(&o.Projects, "SELECT projectkey FROM project WHERE organization_id = $1 ORDER BY projectkey", o.ID); err != nil {
+		return nil, sdk.WrapError(err, "cannot load projects of organization %s", o.Name)
+	}
+	return &o, nil
+}
+
+// AddAdmin makes a user administrator of an organization
+func AddAdmin(db gorp.SqlExecutor, organizationID, userID int64) error {
+	_, err := db.Exec("INSERT INTO organization_admin (organization_id, user_id) VALUES ($1, $2) ON CONFLICT DO NOTHING", organizationID, userID)
+	return sdk.WrapError(err, "cannot add administrator %d to organization %d", userID, organizationID)
+}
+
+// RemoveAdmin removes a user from the administrators of an organization
+func RemoveAdmin(db gorp.SqlExecutor, organizationID, userID int64) error {
+	_, err := db.Exec("DELETE FROM organization_admin WHERE organization_id = $1 AND user_id = $2", organizationID, userID)
+	return sdk.WrapError(err, "cannot remove administrator %d from organization %d", userID, organizationID)
+}
+
+// IsAdmin checks whether a user is administrator of an organization
+func IsAdmin(db gorp.SqlExecutor, organizationID, userID int64) (bool, error) {
+	n, err := db.SelectInt("SELECT COUNT(*) FROM organization_admin WHERE organization_id = $1 AND user_id = $2", organizationID, userID)
+	if err != nil {
+		return false, sdk.WrapError(err, "cannot check administrators of organization %d", organizationID)
+	}
+	return n > 0, nil
+}
+
+// LoadAdministratedProjectKeys returns the keys of the projects of the organizations administrated by a user
+func LoadAdministratedProjectKeys(db gorp.SqlExecutor, userID int64) ([]string, error) {
+	keys := []string{}
+	if _, err := db.Select(&keys, `
+		SELECT project.projectkey FROM project
+		JOIN organization_admin ON organization_admin.organization_id = project.organization_id
+		WHERE organization_admin.user_id = $1`, userID); err != nil {
+		return nil, sdk.WrapError(err, "cannot load projects administrated by user %d", userID)
+	}
+	return keys, nil
+}
+
+// LoadAdministratedWorkflowKeys returns the permission keys of the workflows of the projects of the organizations
+// administrated by a user
+func LoadAdministratedWorkflowKeys(db gorp.SqlExecutor, userID int64) ([]string, error) {
+	rows, err := db.Query(`
+		SELECT project.projectkey, workflow.name FROM workflow
+		JOIN project ON project.id = workflow.project_id
+		JOIN organization_admin ON organization_admin.organization_id = project.organization_id
+		WHERE organization_admin.user_id = $1`, userID)
+	if err != nil {
+		return nil, sdk.WrapError(err, "cannot load workflows administrated by user %d", userID)
+	}
+	defer rows.Close()
+
+	keys := []string{}
+	for rows.Next() {
+		var projectKey, name string
+		if err := rows.Scan(&projectKey, &name); err != nil {
+			return nil, sdk.WithStack(err)
+		}
+		keys = append(keys, sdk.UserPermissionKey(projectKey, name))
+	}
+	return keys, nil
+}
+
+// SetProject attaches a project to an organization, or detaches it with a zero organization id
+func SetProject(db gorp.SqlExecutor, projectID, organizationID int64) error {
+	_, err := db.Exec("UPDATE project SET organization_id = $1 WHERE id = $2", organizationID, projectID)
+	return sdk.WrapError(err, "cannot set organization of project %d", projectID)
+}
+
+// StorageUsage returns the size in bytes of the artifacts of all the projects of an organization
+func StorageUsage(db gorp.SqlExecutor, organizationID int64) (int64, error) {
+	n, err := db.SelectInt(`
+		SELECT COALESCE(SUM(workflow_node_run_artifacts.size), 0) FROM workflow_node_run_artifacts
+		JOIN workflow_run ON workflow_run.id = workflow_node_run_artifacts.workflow_run_id
+		JOIN project ON project.id = workflow_run.project_id
+		WHERE project.organization_id = $1`, organizationID)
+	return n, sdk.WrapError(err, "cannot compute storage usage of organization %d", organizationID)
+}
+
+// CheckStorageQuota returns an error if the artifacts of the projects of the organization of a project exceed the
+// storage quota of the organization, given in megabytes
+func CheckStorageQuota(db gorp.SqlExecutor, projectID int64) error {
+	organizationID, err := db.SelectInt("SELECT organization_id FROM project WHERE id = $1", projectID)
+	if err != nil {
+		return sdk.WrapError(err, "cannot load organization of project %d", projectID)
+	}
+	if organizationID == 0 {
+		return nil
+	}
+	o, err := LoadByID(db, organizationID)
+	if err != nil {
+		return err
+	}
+	if o.MaxStorage == 0 {
+		return nil
+	}
+	used, err := StorageUsage(db, organizationID)
+	if err != nil {
+		return err
+	}
+	if used >= o.MaxStorage*1024*1024 {
+		return sdk.NewErrorFrom(sdk.ErrOrganizationQuotaExceeded, "organization %s uses %d MB of its %d MB storage quota", o.Name, used/1024/1024, o.MaxStorage)
+	}
+	return nil
+}
+
+// CheckBuildQuota returns an error if the projects of an organization used all the build minutes of the organization
+// for the current month
+func CheckBuildQuota(db gorp.SqlExecutor, organizationID int64) error {
+	if organizationID == 0 {
+		return nil
+	}
+	o, err := LoadByID(db, organizationID)
+	if err != nil {
+		return err
+	}
+	if o.MaxBuildMinutes == 0 {
+		return nil
+	}
+	month := sdk.UsageMonth(time.Now())
+	used, err := usage.OrganizationBuildSeconds(db, organizationID, month)
+	if err != nil {
+		return err
+	}
+	if used >= int64(o.MaxBuildMinutes)*60 {
+		return sdk.NewErrorFrom(sdk.ErrOrganizationQuotaExceeded, "organization %s used its %d build minutes of %s", o.Name, o.MaxBuildMinutes, month)
+	}
+	return nil
+}
+
+// CountBuildingJobs returns the number of jobs of the projects of an organization currently building
+func CountBuildingJobs(db gorp.SqlExecutor, organizationID int64) (int64, error) {
+	n, err := db.SelectInt(`
+		SELECT COUNT(1) FROM workflow_node_run_job
+		JOIN project ON project.id = workflow_node_run_job.project_id
+		WHERE project.organization_id = $1 AND workflow_node_run_job.status = $2`, organizationID, sdk.StatusBuilding.String())
+	return n, sdk.WrapError(err, "cannot count building jobs of organization %d", organizationID)
+}
@@ -0,0 +1,76 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/engine/api/test"
+	"github.com/ovh/cds/engine/api/test/assets"
+	"github.com/ovh/cds/sdk"
+)
+
+func Test_organizationAdminManagesProjects(t *testing.T) {
+	api, db, router, end := newTestAPI(t)
+	defer end()
+
+	admin, adminPass := assets.InsertAdminUser(api.mustDB())
+	u, pass := assets.InsertLambdaUser(api.mustDB())
+	key := sdk.RandomString(10)
+	proj := assets.InsertTestProject(t, db, api.Cache, key, key, admin)
+
+	// Create the organization
+	org := sdk.Organization{Name: sdk.RandomString(10), MaxConcurrentJobs: 5}
+	uri := router.GetRoute("POST", api.postAdminOrganizationHandler, nil)
+	test.NotEmpty(t, uri)
+	w := httptest.NewRecorder()
+	router.Mux.ServeHTTP(w, assets.NewAuthentifiedRequest(t, admin, adminPass, "POST", uri, org))
+	assert.Equal(t, 201, w.Code)
+
+	// The user is not an administrator of the organization yet
+	uri = router.GetRoute("GET", api.getOrganizationHandler, map[string]string{"name": org.Name})
+	test.NotEmpty(t, uri)
+	w = httptest.NewRecorder()
+	router.Mux.ServeHTTP(w, assets.NewAuthentifiedRequest(t, u, pass, "GET", uri, nil))
+	assert.Equal(t, 403, w.Code)
+
+	uri = router.GetRoute("POST", api.postOrganizationAdminHandler, map[string]string{"name": org.Name, "username": u.Username})
+	test.NotEmpty(t, uri)
+	w = httptest.NewRecorder()
+	router.Mux.ServeHTTP(w, assets.NewAuthentifiedRequest(t, admin, adminPass, "POST", uri, nil))
+	assert.Equal(t, 200, w.Code)
+
+	uri = router.GetRoute("POST", api.postOrganizationProjectHandler, map[string]string{"name": org.Name, "key": proj.Key})
+	test.NotEmpty(t, uri)
+	w = httptest.NewRecorder()
+	router.Mux.ServeHTTP(w, assets.NewAuthentifiedRequest(t, admin, adminPass, "POST", uri, nil))
+	assert.Equal(t, 200, w.Code)
+	test.NoError(t, json.Unmarshal(w.Body.Bytes(), &org))
+	assert.Equal(t, []string{u.Username}, org.Admins)
+	assert.Equal(t, []string{proj.Key}, org.Projects)
+
+	// The organization administrator can now manage the project, but not change the quotas
+	uri = router.GetRoute("GET", api.getProjectHandler, map[string]string{"permProjectKey": proj.Key})
+	test.NotEmpty(t, uri)
+	w = httptest.NewRecorder()
+	router.Mux.ServeHTTP(w, assets.NewAuthentifiedRequest(t, u, pass, "GET", uri, nil))
+	assert.Equal(t, 200, w.Code)
+
+	uri = router.GetRoute("PUT", api.putOrganizationHandler, map[string]string{"name": org.Name})
+	test.NotEmpty(t, uri)
+	w = httptest.NewRecorder()
+	router.Mux.ServeHTTP(w, assets.NewAuthentifiedRequest(t, u, pass, "PUT", uri, sdk.Organization{Description: "my org", MaxConcurrentJobs: 100}))
+	assert.Equal(t, 200, w.Code)
+	test.NoError(t, json.Unmarshal(w.Body.Bytes(), &org))
+	assert.Equal(t, "my org", org.Description)
+	assert.Equal(t, 5, org.MaxConcurrentJobs)
+
+	// An organization with projects cannot be deleted
+	uri = router.GetRoute("DELETE", api.deleteOrganizationHandler, map[string]string{"name": org.Name})
+	test.NotEmpty(t, uri)
+	w = httptest.NewRecorder()
+	router.Mux.ServeHTTP(w, assets.NewAuthentifiedRequest(t, admin, adminPass, "DELETE", uri, nil))
+	assert.Equal(t, 400, w.Code)
+}
@@ -293,12 +293,19 @@ func (api *API) deletePipelineHandler() service.Handler {
 		}
 		defer tx.Rollback()
 
-		if err := pipeline.DeleteAudit(tx, p.ID); err != nil {
-			return sdk.WrapError(err, "Cannot delete pipeline audit")
-		}
+		// The pipeline is kept in the trash of the project until the purge
+		if api.Config.Trash.Retention > 0 {
+			if err := putInTrash(tx, proj, sdk.TrashItemPipeline, p.ID, p.Name, deprecatedGetUser(ctx)); err != nil {
+				return sdk.WrapError(err, "Cannot delete pipeline %s", pipelineName)
+			}
+		} else {
+			if err := pipeline.DeleteAudit(tx, p.ID); err != nil {
+				return sdk.WrapError(err, "Cannot delete pipeline audit")
+			}
 
-		if err := pipeline.DeletePipeline(tx, p.ID, deprecatedGetUser(ctx).ID); err != nil {
-			return sdk.WrapError(err, "Cannot delete pipeline %s", pipelineName)
+			if err := pipeline.DeletePipeline(tx, p.ID, deprecatedGetUser(ctx).ID); err != nil {
+				return sdk.WrapError(err, "Cannot delete pipeline %s", pipelineName)
+			}
 		}
 
 		if err := tx.Commit(); err != nil {
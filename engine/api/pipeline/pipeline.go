@@ -29,7 +29,7 @@ func LoadPipeline(db gorp.SqlExecutor, projectKey, name string, deep bool) (*sdk
 	query := `SELECT pipeline.id, pipeline.name, pipeline.description, pipeline.project_id, pipeline.last_modified
 			FROM pipeline
 	 			JOIN project on pipeline.project_id = project.id
-	 		WHERE pipeline.name = $1 AND project.projectKey = $2`
+	 		WHERE pipeline.name = $1 AND project.projectKey = $2 AND pipeline.to_delete = false`
 
 	err := db.QueryRow(query, name, projectKey).Scan(&p.ID, &p.Name, &p.Description, &p.ProjectID, &lastModified)
 	if err != nil {
@@ -211,12 +211,28 @@ func DeletePipeline(db gorp.SqlExecutor, pipelineID int64, userID int64) error {
 	return nil
 }
 
+// MarkAsDelete marks a pipeline to be deleted, it is not loaded by name anymore
+func MarkAsDelete(db gorp.SqlExecutor, pipelineID int64) error {
+	if _, err := db.Exec("UPDATE pipeline SET to_delete = true WHERE id = $1", pipelineID); err != nil {
+		return sdk.WrapError(err, "Unable to mark as delete pipeline id %d", pipelineID)
+	}
+	return nil
+}
+
+// Restore restores a pipeline marked to be deleted
+func Restore(db gorp.SqlExecutor, pipelineID int64) error {
+	if _, err := db.Exec("UPDATE pipeline SET to_delete = false WHERE id = $1", pipelineID); err != nil {
+		return sdk.WrapError(err, "Unable to restore pipeline id %d", pipelineID)
+	}
+	return nil
+}
+
 // LoadPipelines loads all pipelines in a project
 func LoadPipelines(db gorp.SqlExecutor, projectID int64, loadDependencies bool) ([]sdk.Pipeline, error) {
 	var pip []sdk.Pipeline
 	query := `SELECT id, name, description, project_id, last_modified
 			  FROM pipeline
-			  WHERE project_id = $1
+			  WHERE project_id = $1 AND to_delete = false
 			  ORDER BY pipeline.name`
 
 	rows, errquery := db.Query(query, projectID)
@@ -260,7 +276,7 @@ func LoadPipelines(db gorp.SqlExecutor, projectID int64, loadDependencies bool)
 func LoadAllNames(db gorp.SqlExecutor, store cache.Store, projID int64) ([]sdk.IDName, error) {
 	query := `SELECT pipeline.id, pipeline.name, pipeline.description
 			  FROM pipeline
-			  WHERE project_id = $1
+			  WHERE project_id = $1 AND to_delete = false
 			  ORDER BY pipeline.name`
 
 	var res []sdk.IDName
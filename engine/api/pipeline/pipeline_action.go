@@ -153,8 +153,8 @@ func DeletePipelineAction(db gorp.SqlExecutor, pipelineActionID int64) error {
 	return nil
 }
 
-//CheckJob validate a job
-func CheckJob(db gorp.SqlExecutor, job *sdk.Job) error {
+//CheckJob validate a job, a warning is sent on msgChan for each step using a deprecated action
+func CheckJob(db gorp.SqlExecutor, job *sdk.Job, msgChan chan<- sdk.Message) error {
 	t := time.Now()
 	log.Debug("CheckJob> Begin")
 	defer log.Debug("CheckJob> End (%d ns)", time.Since(t).Nanoseconds())
@@ -163,7 +163,14 @@ func CheckJob(db gorp.SqlExecutor, job *sdk.Job) error {
 	for i := range job.Action.Actions {
 		step := &job.Action.Actions[i]
 		log.Debug("CheckJob> Checking step %s", step.Name)
-		a, err := action.LoadPublicAction(db, step.Name)
+		var a *sdk.Action
+		var err error
+		// Steps using an action defined in the repository of a workflow as code are already linked to it
+		if step.ID != 0 {
+			a, err = action.LoadActionByID(db, step.ID)
+		} else {
+			a, err = action.LoadPublicActionVersion(db, step.Name, step.VersionConstraint)
+		}
 		if err != nil {
 			if sdk.ErrorIs(err, sdk.ErrNoAction) {
 				errs = append(errs, sdk.NewMessage(sdk.MsgJobNotValidActionNotFound, job.Action.Name, step.Name, i+1))
@@ -171,6 +178,9 @@ func CheckJob(db gorp.SqlExecutor, job *sdk.Job) error {
 			}
 			return sdk.WrapError(err, "Unable to load public action %s", step.Name)
 		}
+		if a.Deprecated && msgChan != nil {
+			msgChan <- sdk.NewMessage(sdk.MsgJobActionDeprecated, job.Action.Name, sdk.ActionReference(a.Name, a.Version), i+1)
+		}
 
 		a.Parameters, err = action.LoadActionParameters(db, a.ID)
 		if err != nil {
@@ -46,7 +46,7 @@ func ImportUpdate(db gorp.SqlExecutor, proj *sdk.Project, pip *sdk.Pipeline, msg
 			//Insert stage's Jobs
 			for x := range s.Jobs {
 				jobAction := &s.Jobs[x]
-				if errs := CheckJob(db, jobAction); errs != nil {
+				if errs := CheckJob(db, jobAction, msgChan); errs != nil {
 					log.Debug("CheckJob > %s", errs)
 					return errs
 				}
@@ -71,7 +71,7 @@ func ImportUpdate(db gorp.SqlExecutor, proj *sdk.Project, pip *sdk.Pipeline, msg
 			for x := range s.Jobs {
 				jobAction := &s.Jobs[x]
 				//Check the job
-				if errs := CheckJob(db, jobAction); errs != nil {
+				if errs := CheckJob(db, jobAction, msgChan); errs != nil {
 					log.Debug(">> CheckJob > %s", errs)
 					return errs
 				}
@@ -180,7 +180,7 @@ func Import(db gorp.SqlExecutor, store cache.Store, proj *sdk.Project, pip *sdk.
 		return sdk.WrapError(errExist, "Import> Unable to check if pipeline %s %s exists", proj.Name, pip.Name)
 	}
 	if !ok {
-		if err := importNew(db, store, proj, pip, u); err != nil {
+		if err := importNew(db, store, proj, pip, msgChan, u); err != nil {
 			log.Error("pipeline.Import> %s", err)
 			if msgChan != nil {
 				msgChan <- sdk.NewMessage(sdk.MsgPipelineCreationAborted, pip.Name)
@@ -205,7 +205,7 @@ func Import(db gorp.SqlExecutor, store cache.Store, proj *sdk.Project, pip *sdk.
 	return nil
 }
 
-func importNew(db gorp.SqlExecutor, store cache.Store, proj *sdk.Project, pip *sdk.Pipeline, u *sdk.User) error {
+func importNew(db gorp.SqlExecutor, store cache.Store, proj *sdk.Project, pip *sdk.Pipeline, msgChan chan<- sdk.Message, u *sdk.User) error {
 	log.Debug("pipeline.importNew> Creating pipeline %s", pip.Name)
 	//Insert pipeline
 	if err := InsertPipeline(db, store, proj, pip, u); err != nil {
@@ -232,7 +232,7 @@ func importNew(db gorp.SqlExecutor, store cache.Store, proj *sdk.Project, pip *s
 			jobAction := &s.Jobs[i]
 			jobAction.Enabled = true
 			jobAction.Action.Enabled = true
-			if errs := CheckJob(db, jobAction); errs != nil {
+			if errs := CheckJob(db, jobAction, msgChan); errs != nil {
 				log.Warning("pipeline.importNew.CheckJob > %s", errs)
 				return errs
 			}
@@ -1,6 +1,7 @@
 package pipeline
 
 import (
+	"strings"
 	"sync"
 
 	"github.com/go-gorp/gorp"
@@ -18,6 +19,9 @@ type pipeliner interface {
 type ImportOptions struct {
 	Force        bool
 	PipelineName string
+	// RepositoryActions are the actions defined in the repository of a workflow as code, they can be used as
+	// steps of the jobs in addition to the public actions
+	RepositoryActions []sdk.Action
 }
 
 // ParseAndImport parse an exportentities.pipeline and insert or update the pipeline in database
@@ -32,6 +36,8 @@ func ParseAndImport(db gorp.SqlExecutor, cache cache.Store, proj *sdk.Project, e
 		return nil, nil, sdk.ErrPipelineNameImport
 	}
 
+	setRepositoryActions(pip, opts.RepositoryActions)
+
 	// Check if pipeline exists
 	exist, errE := ExistPipeline(db, proj.ID, pip.Name)
 	if errE != nil {
@@ -70,3 +76,24 @@ func ParseAndImport(db gorp.SqlExecutor, cache cache.Store, proj *sdk.Project, e
 
 	return pip, msgList, globalError
 }
+
+// setRepositoryActions links the steps using an action defined in the repository to this action, the other steps
+// are linked to the public actions by their names
+func setRepositoryActions(pip *sdk.Pipeline, actions []sdk.Action) {
+	if len(actions) == 0 {
+		return
+	}
+	for i := range pip.Stages {
+		for j := range pip.Stages[i].Jobs {
+			steps := pip.Stages[i].Jobs[j].Action.Actions
+			for k := range steps {
+				for _, a := range actions {
+					if strings.EqualFold(steps[k].Name, a.Name) {
+						steps[k].ID = a.ID
+						break
+					}
+				}
+			}
+		}
+	}
+}
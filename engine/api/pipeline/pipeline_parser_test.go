@@ -0,0 +1,26 @@
+package pipeline
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func Test_setRepositoryActions(t *testing.T) {
+	pip := &sdk.Pipeline{
+		Stages: []sdk.Stage{{
+			Jobs: []sdk.Job{{
+				Action: sdk.Action{
+					Actions: []sdk.Action{{Name: "Script"}, {Name: "My-Action"}},
+				},
+			}},
+		}},
+	}
+	setRepositoryActions(pip, []sdk.Action{{ID: 42, Name: "my-action"}})
+
+	steps := pip.Stages[0].Jobs[0].Action.Actions
+	assert.Equal(t, int64(0), steps[0].ID)
+	assert.Equal(t, int64(42), steps[1].ID)
+}
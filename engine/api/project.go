@@ -18,6 +18,7 @@ import (
 	"github.com/ovh/cds/engine/api/permission"
 	"github.com/ovh/cds/engine/api/integration"
 	"github.com/ovh/cds/engine/api/project"
+	"github.com/ovh/cds/engine/api/usage"
 	"github.com/ovh/cds/engine/api/user"
 	"github.com/ovh/cds/engine/api/workflow"
 	"github.com/ovh/cds/engine/service"
@@ -32,6 +33,7 @@ func (api *API) getProjectsHandler() service.Handler {
 		filterByRepo := r.FormValue("repo")
 		withPermissions := r.FormValue("permission")
 		withIcon := FormBool(r, "withIcon")
+		withArchived := FormBool(r, "archived")
 
 		var u = deprecatedGetUser(ctx)
 		requestedUserName := r.Header.Get("X-Cds-Username")
@@ -72,15 +74,7 @@ func (api *API) getProjectsHandler() service.Handler {
 				return sdk.WrapError(err, "getProjectsHandler")
 			}
 
-			if strings.ToUpper(withPermissions) == "W" {
-				res := make([]sdk.Project, 0, len(projects))
-				for _, p := range projects {
-					if p.Permission >= permission.PermissionReadWriteExecute {
-						res = append(res, p)
-					}
-				}
-				projects = res
-			}
+			projects = filterProjects(projects, strings.ToUpper(withPermissions) == "W", withArchived)
 
 			return service.WriteJSON(w, projects, http.StatusOK)
 		}
@@ -124,20 +118,27 @@ func (api *API) getProjectsHandler() service.Handler {
 			return sdk.WrapError(err, "getProjectsHandler")
 		}
 
-		if strings.ToUpper(withPermissions) == "W" {
-			res := make([]sdk.Project, 0, len(projects))
-			for _, p := range projects {
-				if p.Permission >= permission.PermissionReadWriteExecute {
-					res = append(res, p)
-				}
-			}
-			projects = res
-		}
+		projects = filterProjects(projects, strings.ToUpper(withPermissions) == "W", withArchived)
 
 		return service.WriteJSON(w, projects, http.StatusOK)
 	}
 }
 
+// filterProjects keeps the projects with the write permission if needed, the archived projects are only kept on demand
+func filterProjects(projects []sdk.Project, writable, withArchived bool) []sdk.Project {
+	res := make([]sdk.Project, 0, len(projects))
+	for _, p := range projects {
+		if writable && p.Permission < permission.PermissionReadWriteExecute {
+			continue
+		}
+		if p.Archived && !withArchived {
+			continue
+		}
+		res = append(res, p)
+	}
+	return res
+}
+
 func (api *API) updateProjectHandler() service.Handler {
 	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
 		// Get project name in URL
@@ -166,6 +167,8 @@ func (api *API) updateProjectHandler() service.Handler {
 		// Update in DB is made given the primary key
 		proj.ID = p.ID
 		proj.VCSServers = p.VCSServers
+		proj.Archived = p.Archived
+		proj.OrganizationID = p.OrganizationID
 		if proj.Icon == "" {
 			p.Icon = proj.Icon
 		}
@@ -410,68 +413,89 @@ func (api *API) addProjectHandler() service.Handler {
 			return sdk.WrapError(errBegin, "Cannot start transaction")
 		}
 
-		if err := project.Insert(tx, api.Cache, p, deprecatedGetUser(ctx)); err != nil {
-			return sdk.WrapError(err, "Cannot insert project")
+		if err := api.createProject(tx, p, deprecatedGetUser(ctx)); err != nil {
+			return err
 		}
 
-		// Add group
-		for i := range p.ProjectGroups {
-			groupPermission := &p.ProjectGroups[i]
+		if err := tx.Commit(); err != nil {
+			return sdk.WrapError(err, "Cannot commit transaction")
+		}
 
-			// Insert group
-			groupID, new, errGroup := group.AddGroup(tx, &groupPermission.Group)
-			if groupID == 0 {
-				return errGroup
-			}
-			groupPermission.Group.ID = groupID
+		event.PublishAddProject(p, deprecatedGetUser(ctx))
 
-			if group.IsDefaultGroupID(groupID) {
-				groupPermission.Permission = permission.PermissionRead
-			}
+		return service.WriteJSON(w, p, http.StatusCreated)
+	}
+}
 
-			// Add group on project
-			if err := group.InsertGroupInProject(tx, p.ID, groupPermission.Group.ID, groupPermission.Permission); err != nil {
-				return sdk.WrapError(err, "Cannot add group %s in project %s", groupPermission.Group.Name, p.Name)
-			}
+// createProject inserts the project with its groups, variables and keys in the transaction, the ssh and pgp keys
+// without private key are generated
+func (api *API) createProject(tx gorp.SqlExecutor, p *sdk.Project, u *sdk.User) error {
+	// a project is attached to an organization by a CDS or an organization administrator
+	p.OrganizationID = 0
+	if err := project.Insert(tx, api.Cache, p, u); err != nil {
+		return sdk.WrapError(err, "Cannot insert project")
+	}
 
-			// Add user in group
-			if new {
-				if err := group.InsertUserInGroup(tx, groupPermission.Group.ID, deprecatedGetUser(ctx).ID, true); err != nil {
-					return sdk.WrapError(err, "Cannot add user %s in group %s", deprecatedGetUser(ctx).Username, groupPermission.Group.Name)
-				}
-			}
+	// Add group
+	for i := range p.ProjectGroups {
+		groupPermission := &p.ProjectGroups[i]
+
+		// Insert group
+		groupID, new, errGroup := group.AddGroup(tx, &groupPermission.Group)
+		if groupID == 0 {
+			return errGroup
 		}
+		groupPermission.Group.ID = groupID
 
-		for _, v := range p.Variable {
-			if errVar := project.InsertVariable(tx, p, &v, deprecatedGetUser(ctx)); errVar != nil {
-				return sdk.WrapError(errVar, "addProjectHandler> Cannot add variable %s in project %s", v.Name, p.Name)
-			}
+		if group.IsDefaultGroupID(groupID) {
+			groupPermission.Permission = permission.PermissionRead
 		}
 
-		var sshExists, gpgExists bool
-		for _, k := range p.Keys {
-			switch k.Type {
-			case sdk.KeyTypeSSH:
-				sshExists = true
-			case sdk.KeyTypePGP:
-				gpgExists = true
+		// Add group on project
+		if err := group.InsertGroupInProject(tx, p.ID, groupPermission.Group.ID, groupPermission.Permission); err != nil {
+			return sdk.WrapError(err, "Cannot add group %s in project %s", groupPermission.Group.Name, p.Name)
+		}
+
+		// Add user in group
+		if new {
+			if err := group.InsertUserInGroup(tx, groupPermission.Group.ID, u.ID, true); err != nil {
+				return sdk.WrapError(err, "Cannot add user %s in group %s", u.Username, groupPermission.Group.Name)
 			}
 		}
+	}
 
-		if !sshExists {
-			p.Keys = append(p.Keys, sdk.ProjectKey{Key: sdk.Key{
-				Type: sdk.KeyTypeSSH,
-				Name: fmt.Sprintf("proj-%s-%s", sdk.KeyTypeSSH, strings.ToLower(p.Key))},
-			})
+	for _, v := range p.Variable {
+		if errVar := project.InsertVariable(tx, p, &v, u); errVar != nil {
+			return sdk.WrapError(errVar, "addProjectHandler> Cannot add variable %s in project %s", v.Name, p.Name)
 		}
-		if !gpgExists {
-			p.Keys = append(p.Keys, sdk.ProjectKey{Key: sdk.Key{
-				Type: sdk.KeyTypePGP,
-				Name: fmt.Sprintf("proj-%s-%s", sdk.KeyTypePGP, strings.ToLower(p.Key))},
-			})
+	}
+
+	var sshExists, gpgExists bool
+	for _, k := range p.Keys {
+		switch k.Type {
+		case sdk.KeyTypeSSH:
+			sshExists = true
+		case sdk.KeyTypePGP:
+			gpgExists = true
 		}
-		for _, k := range p.Keys {
-			k.ProjectID = p.ID
+	}
+
+	if !sshExists {
+		p.Keys = append(p.Keys, sdk.ProjectKey{Key: sdk.Key{
+			Type: sdk.KeyTypeSSH,
+			Name: fmt.Sprintf("proj-%s-%s", sdk.KeyTypeSSH, strings.ToLower(p.Key))},
+		})
+	}
+	if !gpgExists {
+		p.Keys = append(p.Keys, sdk.ProjectKey{Key: sdk.Key{
+			Type: sdk.KeyTypePGP,
+			Name: fmt.Sprintf("proj-%s-%s", sdk.KeyTypePGP, strings.ToLower(p.Key))},
+		})
+	}
+	for _, k := range p.Keys {
+		k.ProjectID = p.ID
+		// The keys of an imported project are given with their private key
+		if k.Private == "" {
 			switch k.Type {
 			case sdk.KeyTypeSSH:
 				keyTemp, errK := keys.GenerateSSHKey(k.Name)
@@ -486,31 +510,24 @@ func (api *API) addProjectHandler() service.Handler {
 				}
 				k.Key = keyTemp
 			}
-			if errK := project.InsertKey(tx, &k); errK != nil {
-				return sdk.WrapError(errK, "addProjectHandler> Cannot add key %s in project %s", k.Name, p.Name)
-			}
 		}
-
-		integrationModels, errP := integration.LoadModels(tx)
-		if errP != nil {
-			return sdk.WrapError(errP, "addProjectHandler> Cannot load integration models")
+		if errK := project.InsertKey(tx, &k); errK != nil {
+			return sdk.WrapError(errK, "addProjectHandler> Cannot add key %s in project %s", k.Name, p.Name)
 		}
+	}
 
-		for i := range integrationModels {
-			pf := &integrationModels[i]
-			if err := propagatePublicIntegrationModelOnProject(tx, api.Cache, *pf, *p, deprecatedGetUser(ctx)); err != nil {
-				return sdk.WrapError(err, "propagatePublicIntegrationModelOnProject error")
-			}
-		}
+	integrationModels, errP := integration.LoadModels(tx)
+	if errP != nil {
+		return sdk.WrapError(errP, "addProjectHandler> Cannot load integration models")
+	}
 
-		if err := tx.Commit(); err != nil {
-			return sdk.WrapError(err, "Cannot commit transaction")
+	for i := range integrationModels {
+		pf := &integrationModels[i]
+		if err := propagatePublicIntegrationModelOnProject(tx, api.Cache, *pf, *p, u); err != nil {
+			return sdk.WrapError(err, "propagatePublicIntegrationModelOnProject error")
 		}
-
-		event.PublishAddProject(p, deprecatedGetUser(ctx))
-
-		return service.WriteJSON(w, p, http.StatusCreated)
 	}
+	return nil
 }
 
 func (api *API) deleteProjectHandler() service.Handler {
@@ -555,3 +572,25 @@ func (api *API) deleteProjectHandler() service.Handler {
 		return nil
 	}
 }
+
+// getProjectUsageHandler returns the build time and the size of the artifacts uploaded by the jobs of a project
+// during a month, the current month by default
+func (api *API) getProjectUsageHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		key := mux.Vars(r)[permProjectKey]
+		month, err := sdk.ParseUsageMonth(r.FormValue("month"))
+		if err != nil {
+			return err
+		}
+
+		p, err := project.Load(api.mustDB(), api.Cache, key, deprecatedGetUser(ctx))
+		if err != nil {
+			return sdk.WrapError(err, "cannot load project %s", key)
+		}
+		res, err := usage.LoadByProject(api.mustDB(), p.ID, month)
+		if err != nil {
+			return err
+		}
+		return service.WriteJSON(w, res, http.StatusOK)
+	}
+}
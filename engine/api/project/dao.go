@@ -129,6 +129,27 @@ func Exist(db gorp.SqlExecutor, projectKey string) (bool, error) {
 	return false, nil
 }
 
+// IsArchived checks whether a project is archived or not
+func IsArchived(db gorp.SqlExecutor, projectKey string) (bool, error) {
+	n, err := db.SelectInt("SELECT COUNT(id) FROM project WHERE projectkey = $1 AND archived = true", projectKey)
+	if err != nil {
+		return false, sdk.WrapError(err, "cannot check if project %s is archived", projectKey)
+	}
+	return n > 0, nil
+}
+
+// Archive archives or restores a project, an archived project is read-only
+func Archive(db gorp.SqlExecutor, projectKey string, archived bool) error {
+	res, err := db.Exec("UPDATE project SET archived = $1, last_modified = $2 WHERE projectkey = $3", archived, time.Now(), projectKey)
+	if err != nil {
+		return sdk.WrapError(err, "cannot update project %s", projectKey)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return sdk.WithStack(sdk.ErrNoProject)
+	}
+	return nil
+}
+
 // Delete delete one or more projects given the key
 func Delete(db gorp.SqlExecutor, store cache.Store, key string) error {
 	proj, err := Load(db, store, key, nil)
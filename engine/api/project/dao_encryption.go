@@ -0,0 +1,228 @@
+package project
+
+import (
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/application"
+	"github.com/ovh/cds/engine/api/integration"
+	"github.com/ovh/cds/engine/api/secret"
+	"github.com/ovh/cds/sdk"
+)
+
+// encryptedColumns lists the columns encrypted with the data key of a project, and how to select them for a project
+var encryptedColumns = []struct {
+	table  string
+	column string
+	query  string
+}{
+	{"project_variable", "cipher_value", `SELECT id, cipher_value FROM project_variable WHERE project_id = $1 AND cipher_value IS NOT NULL`},
+	{"application_variable", "cipher_value", `
+		SELECT application_variable.id, application_variable.cipher_value FROM application_variable
+		JOIN application ON application.id = application_variable.application_id
+		WHERE application.project_id = $1 AND application_variable.cipher_value IS NOT NULL`},
+	{"environment_variable", "cipher_value", `
+		SELECT environment_variable.id, environment_variable.cipher_value FROM environment_variable
+		JOIN environment ON environment.id = environment_variable.environment_id
+		WHERE environment.project_id = $1 AND environment_variable.cipher_value IS NOT NULL`},
+	{"project", "vcs_servers", `SELECT id, vcs_servers FROM project WHERE id = $1 AND vcs_servers IS NOT NULL`},
+	{"project_key", "private", `SELECT id, private FROM project_key WHERE project_id = $1`},
+	{"application_key", "private", `
+		SELECT application_key.id, application_key.private FROM application_key
+		JOIN application ON application.id = application_key.application_id
+		WHERE application.project_id = $1`},
+	{"environment_key", "private", `
+		SELECT environment_key.id, environment_key.private FROM environment_key
+		JOIN environment ON environment.id = environment_key.environment_id
+		WHERE environment.project_id = $1`},
+}
+
+// encryptedVariableAudits lists the audits of the variables of a project, which keep the values of the secret variables
+// encrypted with the data key of the project
+var encryptedVariableAudits = []struct {
+	table string
+	query string
+}{
+	{"project_variable_audit", `SELECT id, variable_before, variable_after FROM project_variable_audit WHERE project_id = $1`},
+	{"application_variable_audit", `
+		SELECT application_variable_audit.id, application_variable_audit.variable_before, application_variable_audit.variable_after
+		FROM application_variable_audit
+		JOIN application ON application.id = application_variable_audit.application_id
+		WHERE application.project_id = $1`},
+	{"environment_variable_audit", `
+		SELECT environment_variable_audit.id, environment_variable_audit.variable_before, environment_variable_audit.variable_after
+		FROM environment_variable_audit
+		JOIN environment ON environment.id = environment_variable_audit.environment_id
+		WHERE environment.project_id = $1`},
+}
+
+// RotateEncryptionKey creates a new data key for the project and re-encrypts all its secret variables, keys and
+// integration, vcs and deployment strategy passwords, repositories managers tokens and variable audits with it. It
+// returns the number of re-encrypted values.
+func RotateEncryptionKey(db gorp.SqlExecutor, projectID int64) (int, error) {
+	if err := secret.RotateProjectKey(db, projectID); err != nil {
+		return 0, sdk.WrapError(err, "cannot rotate data key of project %d", projectID)
+	}
+
+	var count int
+	for _, c := range encryptedColumns {
+		n, err := reencryptColumn(db, projectID, c.table, c.column, c.query)
+		if err != nil {
+			return count, err
+		}
+		count += n
+	}
+
+	for _, a := range encryptedVariableAudits {
+		n, err := reencryptVariableAudits(db, projectID, a.table, a.query)
+		if err != nil {
+			return count, err
+		}
+		count += n
+	}
+
+	n, err := integration.RotatePasswords(db, projectID)
+	if err != nil {
+		return count, sdk.WrapError(err, "cannot rotate integration passwords of project %d", projectID)
+	}
+	count += n
+
+	n, err = application.RotatePasswords(db, projectID)
+	if err != nil {
+		return count, sdk.WrapError(err, "cannot rotate application passwords of project %d", projectID)
+	}
+	return count + n, nil
+}
+
+func reencryptColumn(db gorp.SqlExecutor, projectID int64, table, column, query string) (int, error) {
+	type value struct {
+		id   int64
+		data []byte
+	}
+
+	rows, err := db.Query(query, projectID)
+	if err != nil {
+		return 0, sdk.WrapError(err, "cannot load %s.%s", table, column)
+	}
+	var values []value
+	for rows.Next() {
+		var v value
+		if err := rows.Scan(&v.id, &v.data); err != nil {
+			rows.Close() // nolint
+			return 0, sdk.WithStack(err)
+		}
+		values = append(values, v)
+	}
+	rows.Close() // nolint
+
+	var count int
+	updateQuery := "UPDATE " + table + " SET " + column + " = $1 WHERE id = $2"
+	for _, v := range values {
+		if len(v.data) == 0 {
+			continue
+		}
+		clear, err := secret.DecryptForProject(projectID, v.data)
+		if err != nil {
+			return 0, sdk.WrapError(err, "cannot decrypt %s.%s %d", table, column, v.id)
+		}
+		encrypted, err := secret.EncryptForProject(db, projectID, clear)
+		if err != nil {
+			return 0, sdk.WrapError(err, "cannot encrypt %s.%s %d", table, column, v.id)
+		}
+
+		var arg interface{} = encrypted
+		if column == "private" {
+			arg = string(encrypted)
+		}
+		if _, err := db.Exec(updateQuery, arg, v.id); err != nil {
+			return 0, sdk.WrapError(err, "cannot update %s.%s %d", table, column, v.id)
+		}
+		count++
+	}
+	return count, nil
+}
+
+// reencryptVariableAudits re-encrypts the values of the secret variables kept in the audits, the values are base64
+// encoded in the json of the variables
+func reencryptVariableAudits(db gorp.SqlExecutor, projectID int64, table, query string) (int, error) {
+	type audit struct {
+		id            int64
+		before, after sql.NullString
+	}
+
+	rows, err := db.Query(query, projectID)
+	if err != nil {
+		return 0, sdk.WrapError(err, "cannot load %s", table)
+	}
+	var audits []audit
+	for rows.Next() {
+		var a audit
+		if err := rows.Scan(&a.id, &a.before, &a.after); err != nil {
+			rows.Close() // nolint
+			return 0, sdk.WithStack(err)
+		}
+		audits = append(audits, a)
+	}
+	rows.Close() // nolint
+
+	reencrypt := func(v *sql.NullString) (bool, error) {
+		if !v.Valid {
+			return false, nil
+		}
+		var variable sdk.Variable
+		if err := json.Unmarshal([]byte(v.String), &variable); err != nil {
+			return false, sdk.WithStack(err)
+		}
+		if !sdk.NeedPlaceholder(variable.Type) || variable.Value == "" {
+			return false, nil
+		}
+		data, err := base64.StdEncoding.DecodeString(variable.Value)
+		if err != nil {
+			return false, sdk.WithStack(err)
+		}
+		clear, err := secret.DecryptForProject(projectID, data)
+		if err != nil {
+			return false, err
+		}
+		encrypted, err := secret.EncryptForProject(db, projectID, clear)
+		if err != nil {
+			return false, err
+		}
+		variable.Value = base64.StdEncoding.EncodeToString(encrypted)
+		b, err := json.Marshal(variable)
+		if err != nil {
+			return false, sdk.WithStack(err)
+		}
+		v.String = string(b)
+		return true, nil
+	}
+
+	var count int
+	updateQuery := "UPDATE " + table + " SET variable_before = $1, variable_after = $2 WHERE id = $3"
+	for _, a := range audits {
+		before, err := reencrypt(&a.before)
+		if err != nil {
+			return 0, sdk.WrapError(err, "cannot re-encrypt %s.variable_before %d", table, a.id)
+		}
+		after, err := reencrypt(&a.after)
+		if err != nil {
+			return 0, sdk.WrapError(err, "cannot re-encrypt %s.variable_after %d", table, a.id)
+		}
+		if !before && !after {
+			continue
+		}
+		if _, err := db.Exec(updateQuery, a.before, a.after, a.id); err != nil {
+			return 0, sdk.WrapError(err, "cannot update %s %d", table, a.id)
+		}
+		if before {
+			count++
+		}
+		if after {
+			count++
+		}
+	}
+	return count, nil
+}
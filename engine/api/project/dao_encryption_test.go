@@ -0,0 +1,94 @@
+package project_test
+
+import (
+	"testing"
+
+	"github.com/go-gorp/gorp"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/engine/api/application"
+	"github.com/ovh/cds/engine/api/integration"
+	"github.com/ovh/cds/engine/api/project"
+	"github.com/ovh/cds/engine/api/repositoriesmanager"
+	"github.com/ovh/cds/engine/api/secret"
+	"github.com/ovh/cds/engine/api/test"
+	"github.com/ovh/cds/engine/api/test/assets"
+	"github.com/ovh/cds/sdk"
+)
+
+func TestRotateEncryptionKey(t *testing.T) {
+	db, cache, end := test.SetupPG(t)
+	defer end()
+
+	kms, err := secret.NewKMS(secret.KMSConfiguration{})
+	test.NoError(t, err)
+	secret.InitKMS(kms, func() gorp.SqlExecutor { return db })
+
+	key := sdk.RandomString(10)
+	proj := assets.InsertTestProject(t, db, cache, key, key, nil)
+
+	v := &sdk.Variable{Name: "password", Type: sdk.SecretVariable, Value: "my-password"}
+	test.NoError(t, project.InsertVariable(db, proj, v, &sdk.User{Username: "foo"}))
+
+	models, _ := integration.LoadModels(db)
+	if len(models) == 0 {
+		test.NoError(t, integration.CreateBuiltinModels(db))
+	}
+	model, err := integration.LoadModelByName(db, sdk.KafkaIntegration.Name, false)
+	test.NoError(t, err)
+	cfg := sdk.KafkaIntegration.DefaultConfig.Clone()
+	cfg["password"] = sdk.IntegrationConfigValue{Type: sdk.IntegrationConfigTypePassword, Value: "kafka-password"}
+	pp := sdk.ProjectIntegration{Name: "kafka", ProjectID: proj.ID, IntegrationModelID: model.ID, Config: cfg}
+	test.NoError(t, integration.InsertIntegration(db, &pp))
+
+	app := sdk.Application{Name: "app", RepositoryStrategy: sdk.RepositoryStrategy{Password: "vcs-password"}}
+	test.NoError(t, application.EncryptVCSStrategyPassword(db, proj.ID, &app))
+	test.NoError(t, application.Insert(db, cache, proj, &app, nil))
+	test.NoError(t, application.SetDeploymentStrategy(db, proj.ID, app.ID, model.ID, pp.Name, sdk.IntegrationConfig{
+		"token": sdk.IntegrationConfigValue{Type: sdk.IntegrationConfigTypePassword, Value: "deployment-token"},
+	}))
+
+	test.NoError(t, repositoriesmanager.InsertForProject(db, proj, &sdk.ProjectVCSServer{
+		Name: "github",
+		Data: map[string]string{"token": "vcs-token"},
+	}))
+
+	cipherBefore, err := db.SelectStr("SELECT config FROM project_integration WHERE id = $1", pp.ID)
+	test.NoError(t, err)
+
+	n, err := project.RotateEncryptionKey(db, proj.ID)
+	test.NoError(t, err)
+	// the builtin key, the secret variable and its audit, the repositories managers, the integration password, the vcs
+	// password and the deployment strategy token
+	assert.Equal(t, 7, n)
+
+	cipherAfter, err := db.SelectStr("SELECT config FROM project_integration WHERE id = $1", pp.ID)
+	test.NoError(t, err)
+	assert.NotEqual(t, cipherBefore, cipherAfter)
+
+	v2, err := project.GetVariableInProject(db, proj.ID, "password", project.WithClearPassword())
+	test.NoError(t, err)
+	assert.Equal(t, "my-password", v2.Value)
+
+	pp2, err := integration.LoadByID(db, pp.ID, true)
+	test.NoError(t, err)
+	assert.Equal(t, "kafka-password", pp2.Config["password"].Value)
+
+	app2, err := application.LoadByID(db, cache, app.ID)
+	test.NoError(t, err)
+	test.NoError(t, application.DecryptVCSStrategyPassword(app2))
+	assert.Equal(t, "vcs-password", app2.RepositoryStrategy.Password)
+
+	vcsServer, err := repositoriesmanager.LoadForProject(db, proj.Key, "github")
+	test.NoError(t, err)
+	assert.Equal(t, "vcs-token", vcsServer.Data["token"])
+
+	deps, err := application.LoadDeploymentStrategies(db, app.ID, true)
+	test.NoError(t, err)
+	assert.Equal(t, "deployment-token", deps[pp.Name]["token"].Value)
+
+	// The secrets of a project can not be decrypted as the secrets of another project
+	other := assets.InsertTestProject(t, db, cache, sdk.RandomString(10), sdk.RandomString(10), nil)
+	_, err = project.GetVariableInProject(db, other.ID, "password", project.WithClearPassword())
+	assert.Error(t, err)
+}
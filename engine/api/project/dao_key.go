@@ -16,7 +16,7 @@ import (
 func InsertKey(db gorp.SqlExecutor, key *sdk.ProjectKey) error {
 	dbProjKey := dbProjectKey(*key)
 
-	s, errE := secret.Encrypt([]byte(key.Private))
+	s, errE := secret.EncryptForProject(db, key.ProjectID, []byte(key.Private))
 	if errE != nil {
 		return sdk.WrapError(errE, "InsertKey> Cannot encrypt private key")
 	}
@@ -85,7 +85,7 @@ func LoadAllDecryptedKeys(db gorp.SqlExecutor, proj *sdk.Project) error {
 	for i := range res {
 		p := res[i]
 		keys[i] = sdk.ProjectKey(p)
-		decrypted, err := secret.Decrypt([]byte(keys[i].Private))
+		decrypted, err := secret.DecryptForProject(proj.ID, []byte(keys[i].Private))
 		if err != nil {
 			log.Error("LoadAllDecryptedKeys> Unable to decrypt private key %s/%s: %v", proj.Key, keys[i].Name, err)
 		}
@@ -95,6 +95,38 @@ func LoadAllDecryptedKeys(db gorp.SqlExecutor, proj *sdk.Project) error {
 	return nil
 }
 
+// LoadKey loads a key of the project, without its private key
+func LoadKey(db gorp.SqlExecutor, projectID int64, keyName string) (*sdk.ProjectKey, error) {
+	var res dbProjectKey
+	if err := db.SelectOne(&res, "SELECT * FROM project_key WHERE project_id = $1 and builtin = false and name = $2", projectID, keyName); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, sdk.WithStack(sdk.ErrNotFound)
+		}
+		return nil, sdk.WrapError(err, "Cannot load key %s", keyName)
+	}
+	k := sdk.ProjectKey(res)
+	k.Private = sdk.PasswordPlaceholder
+	return &k, nil
+}
+
+// LoadDecryptedKey loads a key of the project, with its private key
+func LoadDecryptedKey(db gorp.SqlExecutor, projectID int64, keyName string) (*sdk.ProjectKey, error) {
+	var res dbProjectKey
+	if err := db.SelectOne(&res, "SELECT * FROM project_key WHERE project_id = $1 and builtin = false and name = $2", projectID, keyName); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, sdk.WithStack(sdk.ErrNotFound)
+		}
+		return nil, sdk.WrapError(err, "Cannot load key %s", keyName)
+	}
+	k := sdk.ProjectKey(res)
+	decrypted, err := secret.DecryptForProject(projectID, []byte(k.Private))
+	if err != nil {
+		return nil, sdk.WrapError(err, "Unable to decrypt key %s", keyName)
+	}
+	k.Private = string(decrypted)
+	return &k, nil
+}
+
 // DeleteProjectKey Delete the given key from the given project
 func DeleteProjectKey(db gorp.SqlExecutor, projectID int64, keyName string) error {
 	_, err := db.Exec("DELETE FROM project_key WHERE project_id = $1 AND name = $2", projectID, keyName)
@@ -112,7 +144,7 @@ func loadBuildinKey(db gorp.SqlExecutor, projectID int64) (sdk.ProjectKey, error
 	}
 
 	k = sdk.ProjectKey(res)
-	decrypted, err := secret.Decrypt([]byte(k.Private))
+	decrypted, err := secret.DecryptForProject(projectID, []byte(k.Private))
 	if err != nil {
 		return k, sdk.WrapError(err, "Unable to decrypt key")
 	}
@@ -42,7 +42,7 @@ func (p *dbProject) PostGet(db gorp.SqlExecutor) error {
 	}
 
 	if len(fields.VCSServers) > 0 {
-		clearVCSServer, err := secret.Decrypt([]byte(fields.VCSServers))
+		clearVCSServer, err := secret.DecryptForProject(p.ID, fields.VCSServers)
 		if err != nil {
 			return err
 		}
@@ -71,7 +71,7 @@ func (p *dbProject) PostUpdate(db gorp.SqlExecutor) error {
 		if err != nil {
 			return err
 		}
-		encryptedVCSServerStr, err := secret.Encrypt(b1)
+		encryptedVCSServerStr, err := secret.EncryptForProject(db, p.ID, b1)
 		if err != nil {
 			return err
 		}
@@ -160,7 +160,7 @@ func (pva *dbProjectVariableAudit) PostInsert(db gorp.SqlExecutor) error {
 func (pva *dbProjectVariableAudit) PreInsert(s gorp.SqlExecutor) error {
 	if pva.VariableBefore != nil {
 		if sdk.NeedPlaceholder(pva.VariableBefore.Type) {
-			secret, err := secret.Encrypt([]byte(pva.VariableBefore.Value))
+			secret, err := secret.EncryptForProject(s, pva.ProjectID, []byte(pva.VariableBefore.Value))
 			if err != nil {
 				return err
 			}
@@ -170,7 +170,7 @@ func (pva *dbProjectVariableAudit) PreInsert(s gorp.SqlExecutor) error {
 	if pva.VariableAfter != nil {
 		if sdk.NeedPlaceholder(pva.VariableAfter.Type) {
 			var err error
-			secret, err := secret.Encrypt([]byte(pva.VariableAfter.Value))
+			secret, err := secret.EncryptForProject(s, pva.ProjectID, []byte(pva.VariableAfter.Value))
 			if err != nil {
 				return err
 			}
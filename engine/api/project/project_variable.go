@@ -113,7 +113,7 @@ func GetAllVariableInProject(db gorp.SqlExecutor, projectID int64, args ...GetAl
 		if c.encryptsecret && sdk.NeedPlaceholder(v.Type) {
 			v.Value = string(cipherVal)
 		} else {
-			v.Value, err = secret.DecryptS(v.Type, clearVal, cipherVal, c.clearsecret)
+			v.Value, err = secret.DecryptSForProject(projectID, v.Type, clearVal, cipherVal, c.clearsecret)
 		}
 		if err != nil {
 			return nil, err
@@ -165,7 +165,7 @@ func GetVariableByID(db gorp.SqlExecutor, projectID int64, variableID int64, arg
 	}
 
 	var errD error
-	variable.Value, errD = secret.DecryptS(variable.Type, varValue, cipherValue, c.clearsecret)
+	variable.Value, errD = secret.DecryptSForProject(projectID, variable.Type, varValue, cipherValue, c.clearsecret)
 	return variable, errD
 }
 
@@ -186,7 +186,7 @@ func GetVariableInProject(db gorp.SqlExecutor, projectID int64, variableName str
 		return variable, err
 	}
 	var errD error
-	variable.Value, errD = secret.DecryptS(variable.Type, varValue, cipherValue, c.clearsecret)
+	variable.Value, errD = secret.DecryptSForProject(projectID, variable.Type, varValue, cipherValue, c.clearsecret)
 	return variable, errD
 }
 
@@ -201,7 +201,7 @@ func InsertVariable(db gorp.SqlExecutor, proj *sdk.Project, variable *sdk.Variab
 	query := `INSERT INTO project_variable(project_id, var_name, var_value, cipher_value, var_type)
 		  VALUES($1, $2, $3, $4, $5) RETURNING id`
 
-	clear, cipher, err := secret.EncryptS(variable.Type, variable.Value)
+	clear, cipher, err := secret.EncryptSForProject(db, proj.ID, variable.Type, variable.Value)
 	if err != nil {
 		return sdk.WrapError(err, "Cannot encryp secret %s", variable.Name)
 	}
@@ -241,7 +241,7 @@ func UpdateVariable(db gorp.SqlExecutor, proj *sdk.Project, variable *sdk.Variab
 		varValue = previousVar.Value
 	}
 
-	clear, cipher, err := secret.EncryptS(variable.Type, varValue)
+	clear, cipher, err := secret.EncryptSForProject(db, proj.ID, variable.Type, varValue)
 	if err != nil {
 		return sdk.WrapError(err, "Cannot encrypt secret %s", variable.Name)
 	}
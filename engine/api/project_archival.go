@@ -0,0 +1,106 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/ovh/cds/engine/api/event"
+	"github.com/ovh/cds/engine/api/project"
+	"github.com/ovh/cds/engine/api/services"
+	"github.com/ovh/cds/engine/api/workflow"
+	"github.com/ovh/cds/engine/service"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// postProjectArchivalHandler archives a project: its hooks and schedulers are stopped, it is hidden from the
+// default listings and it is read-only until an administrator restores it
+func (api *API) postProjectArchivalHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		key := vars[permProjectKey]
+		u := deprecatedGetUser(ctx)
+
+		proj, err := project.Load(api.mustDB(), api.Cache, key, u)
+		if err != nil {
+			return sdk.WrapError(err, "cannot load project %s", key)
+		}
+
+		n, err := workflow.CountActiveRunsByProjectID(api.mustDB(), proj.ID)
+		if err != nil {
+			return err
+		}
+		if n > 0 {
+			return sdk.NewErrorFrom(sdk.ErrWrongRequest, "project %s has %d workflow runs in progress, they have to be finished or stopped before the archival", key, n)
+		}
+
+		if err := project.Archive(api.mustDB(), key, true); err != nil {
+			return err
+		}
+		api.switchProjectHooks(ctx, proj, "stop")
+
+		archived := *proj
+		archived.Archived = true
+		event.PublishUpdateProject(&archived, proj, u)
+
+		return service.WriteJSON(w, archived, http.StatusOK)
+	}
+}
+
+// deleteProjectArchivalHandler restores an archived project and starts its hooks and schedulers again
+func (api *API) deleteProjectArchivalHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		key := vars[permProjectKey]
+		u := deprecatedGetUser(ctx)
+
+		proj, err := project.Load(api.mustDB(), api.Cache, key, u)
+		if err != nil {
+			return sdk.WrapError(err, "cannot load project %s", key)
+		}
+
+		if err := project.Archive(api.mustDB(), key, false); err != nil {
+			return err
+		}
+		api.switchProjectHooks(ctx, proj, "start")
+
+		restored := *proj
+		restored.Archived = false
+		event.PublishUpdateProject(&restored, proj, u)
+
+		return service.WriteJSON(w, restored, http.StatusOK)
+	}
+}
+
+// switchProjectHooks starts or stops the tasks of the hooks of all the workflows of the project on the hooks µService.
+// The project state is already saved, so a task that cannot be switched is only logged.
+func (api *API) switchProjectHooks(ctx context.Context, proj *sdk.Project, action string) {
+	uuids, err := workflow.LoadHookUUIDsByProjectID(api.mustDB(), proj.ID)
+	if err != nil {
+		log.Warning("switchProjectHooks> %v", err)
+		return
+	}
+	api.switchHooks(ctx, uuids, action)
+}
+
+// switchHooks starts or stops the tasks of the given hooks on the hooks µService
+func (api *API) switchHooks(ctx context.Context, uuids []string, action string) {
+	if len(uuids) == 0 {
+		return
+	}
+
+	srvs, err := services.FindByType(api.mustDB(), services.TypeHooks)
+	if err != nil {
+		log.Warning("switchHooks> unable to load hooks services: %v", err)
+		return
+	}
+	for _, uuid := range uuids {
+		path := fmt.Sprintf("/task/%s/%s", uuid, action)
+		if code, err := services.DoJSONRequest(ctx, srvs, http.MethodGet, path, nil, nil); err != nil || code >= 400 {
+			log.Warning("switchHooks> unable to %s hook %s [%d]: %v", action, uuid, code, err)
+		}
+	}
+}
@@ -0,0 +1,508 @@
+package api
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/go-gorp/gorp"
+	"github.com/gorilla/mux"
+	yaml "gopkg.in/yaml.v2"
+
+	"github.com/ovh/cds/engine/api/application"
+	"github.com/ovh/cds/engine/api/environment"
+	"github.com/ovh/cds/engine/api/event"
+	"github.com/ovh/cds/engine/api/integration"
+	"github.com/ovh/cds/engine/api/keys"
+	"github.com/ovh/cds/engine/api/pipeline"
+	"github.com/ovh/cds/engine/api/project"
+	"github.com/ovh/cds/engine/api/secret"
+	"github.com/ovh/cds/engine/api/workflow"
+	"github.com/ovh/cds/engine/service"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/exportentities"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// archiveEncryptFunc returns the func encrypting the secrets of a project archive with the passphrase, without
+// passphrase the secrets are not exported
+func archiveEncryptFunc(passphrase string) sdk.EncryptFunc {
+	return func(_ gorp.SqlExecutor, _ int64, _ string, content string) (string, error) {
+		if passphrase == "" || content == "" {
+			return "", nil
+		}
+		btes, err := secret.EncryptWithPassphrase(passphrase, []byte(content))
+		if err != nil {
+			return "", err
+		}
+		return base64.StdEncoding.EncodeToString(btes), nil
+	}
+}
+
+// archiveDecryptFunc returns the func decrypting the secrets of a project archive with the passphrase
+func archiveDecryptFunc(passphrase string) keys.DecryptFunc {
+	return func(_ gorp.SqlExecutor, _ int64, content string) (string, error) {
+		if content == "" {
+			return "", nil
+		}
+		if passphrase == "" {
+			return "", sdk.NewErrorFrom(sdk.ErrWrongRequest, "the archive contains secrets, a passphrase is required")
+		}
+		btes, err := base64.StdEncoding.DecodeString(content)
+		if err != nil {
+			return "", sdk.NewError(sdk.ErrWrongRequest, err)
+		}
+		clear, err := secret.DecryptWithPassphrase(passphrase, btes)
+		if err != nil {
+			return "", err
+		}
+		return string(clear), nil
+	}
+}
+
+func (api *API) getProjectArchiveHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		key := vars[permProjectKey]
+		full := FormBool(r, "full")
+		passphrase := r.Header.Get(sdk.ArchivePassphraseHeader)
+		u := deprecatedGetUser(ctx)
+
+		// A full archive contains the secrets of the project
+		if full {
+			if !u.Admin {
+				return sdk.WrapError(sdk.ErrForbidden, "only an administrator can export the secrets of project %s", key)
+			}
+			if passphrase == "" {
+				return sdk.NewErrorFrom(sdk.ErrWrongRequest, "a passphrase is required to export the secrets")
+			}
+		} else {
+			passphrase = ""
+		}
+
+		db := api.mustDB()
+		proj, err := project.Load(db, api.Cache, key, u,
+			project.LoadOptions.WithGroups,
+			project.LoadOptions.WithEnvironments,
+			project.LoadOptions.WithApplicationNames,
+			project.LoadOptions.WithPipelineNames,
+			project.LoadOptions.WithWorkflowNames,
+			project.LoadOptions.WithIntegrations,
+		)
+		if err != nil {
+			return sdk.WrapError(err, "unable to load project %s", key)
+		}
+
+		buf := new(bytes.Buffer)
+		if err := api.exportProjectArchive(ctx, db, proj, u, full, archiveEncryptFunc(passphrase), buf); err != nil {
+			return err
+		}
+
+		w.Header().Add("Content-Type", "application/tar")
+		w.WriteHeader(http.StatusOK)
+		_, err = io.Copy(w, buf)
+		return sdk.WrapError(err, "unable to copy content buffer in the response writer")
+	}
+}
+
+// exportProjectArchive writes the tar archive of the project: the project.yml file with the settings of the
+// project, and one file by environment, application, pipeline and workflow
+func (api *API) exportProjectArchive(ctx context.Context, db gorp.SqlExecutor, proj *sdk.Project, u *sdk.User, full bool, encryptFunc sdk.EncryptFunc, w io.Writer) error {
+	tw := tar.NewWriter(w)
+
+	eproj, err := exportProject(db, proj, full, encryptFunc)
+	if err != nil {
+		return err
+	}
+	btes, err := yaml.Marshal(eproj)
+	if err != nil {
+		return sdk.WithStack(err)
+	}
+	if err := writeArchiveFile(tw, exportentities.ProjectArchiveFile, btes); err != nil {
+		return err
+	}
+
+	for _, env := range proj.Environments {
+		var buf bytes.Buffer
+		if _, err := environment.Export(db, api.Cache, proj.Key, env.Name, exportentities.FormatYAML, u, encryptFunc, &buf); err != nil {
+			return sdk.WrapError(err, "unable to export environment %s", env.Name)
+		}
+		if err := writeArchiveFile(tw, path.Join(exportentities.ProjectArchiveEnvironmentsDir, env.Name+".yml"), buf.Bytes()); err != nil {
+			return err
+		}
+	}
+
+	for _, app := range proj.ApplicationNames {
+		var buf bytes.Buffer
+		if _, err := application.Export(db, api.Cache, proj.Key, app.Name, exportentities.FormatYAML, encryptFunc, &buf); err != nil {
+			return sdk.WrapError(err, "unable to export application %s", app.Name)
+		}
+		if err := writeArchiveFile(tw, path.Join(exportentities.ProjectArchiveApplicationsDir, app.Name+".yml"), buf.Bytes()); err != nil {
+			return err
+		}
+	}
+
+	for _, pip := range proj.PipelineNames {
+		var buf bytes.Buffer
+		if _, err := pipeline.Export(db, api.Cache, proj.Key, pip.Name, exportentities.FormatYAML, &buf); err != nil {
+			return sdk.WrapError(err, "unable to export pipeline %s", pip.Name)
+		}
+		if err := writeArchiveFile(tw, path.Join(exportentities.ProjectArchivePipelinesDir, pip.Name+".yml"), buf.Bytes()); err != nil {
+			return err
+		}
+	}
+
+	for _, wf := range proj.WorkflowNames {
+		var buf bytes.Buffer
+		if _, err := workflow.Export(ctx, db, api.Cache, proj, wf.Name, exportentities.FormatYAML, u, &buf, exportentities.WorkflowWithPermissions); err != nil {
+			return sdk.WrapError(err, "unable to export workflow %s", wf.Name)
+		}
+		if err := writeArchiveFile(tw, path.Join(exportentities.ProjectArchiveWorkflowsDir, wf.Name+".yml"), buf.Bytes()); err != nil {
+			return err
+		}
+	}
+
+	return sdk.WrapError(tw.Close(), "unable to close tar writer")
+}
+
+// exportProject returns the settings of the project, the keys and the integrations are only exported with
+// their secrets in a full archive
+func exportProject(db gorp.SqlExecutor, proj *sdk.Project, full bool, encryptFunc sdk.EncryptFunc) (*exportentities.Project, error) {
+	eproj := &exportentities.Project{
+		Version:     exportentities.ProjectVersion1,
+		Key:         proj.Key,
+		Name:        proj.Name,
+		Description: proj.Description,
+		Metadata:    proj.Metadata,
+	}
+
+	variables, err := project.GetAllVariableInProject(db, proj.ID, project.WithClearPassword())
+	if err != nil {
+		return nil, sdk.WrapError(err, "cannot load variables of project %s", proj.Key)
+	}
+	eproj.Variables = make(map[string]exportentities.VariableValue, len(variables))
+	for _, v := range variables {
+		switch v.Type {
+		case sdk.KeyVariable:
+			return nil, sdk.NewErrorFrom(sdk.ErrUnknownError, "variable %s: variable of type key are deprecated", v.Name)
+		case sdk.SecretVariable:
+			content, err := encryptFunc(db, proj.ID, fmt.Sprintf("projID:%d:%s", proj.ID, v.Name), v.Value)
+			if err != nil {
+				return nil, sdk.WrapError(err, "unable to encrypt variable %s", v.Name)
+			}
+			v.Value = content
+		case sdk.StringVariable:
+			v.Type = ""
+		}
+		eproj.Variables[v.Name] = exportentities.VariableValue{Type: v.Type, Value: v.Value}
+	}
+
+	eproj.Permissions = make(map[string]int, len(proj.ProjectGroups))
+	for _, gp := range proj.ProjectGroups {
+		eproj.Permissions[gp.Group.Name] = gp.Permission
+	}
+
+	if !full {
+		return eproj, nil
+	}
+
+	if err := project.LoadAllDecryptedKeys(db, proj); err != nil {
+		return nil, sdk.WrapError(err, "cannot load keys of project %s", proj.Key)
+	}
+	eproj.Keys = make(map[string]exportentities.KeyValue, len(proj.Keys))
+	for _, k := range proj.Keys {
+		content, err := encryptFunc(db, proj.ID, fmt.Sprintf("projID:%d:%s", proj.ID, k.Name), k.Private)
+		if err != nil {
+			return nil, sdk.WrapError(err, "unable to encrypt key %s", k.Name)
+		}
+		eproj.Keys[k.Name] = exportentities.KeyValue{Type: k.Type, Value: content}
+	}
+
+	// The public integrations are added on every project by the API
+	integrations, err := integration.LoadIntegrationsByProjectID(db, proj.ID, true)
+	if err != nil {
+		return nil, sdk.WrapError(err, "cannot load integrations of project %s", proj.Key)
+	}
+	eproj.Integrations = make(map[string]exportentities.ProjectIntegration, len(integrations))
+	for _, pp := range integrations {
+		if pp.Model.Public {
+			continue
+		}
+		config := pp.Config.Clone()
+		if err := config.EncryptSecrets(func(s string) (string, error) {
+			return encryptFunc(db, proj.ID, fmt.Sprintf("projID:%d:integration:%s", proj.ID, pp.Name), s)
+		}); err != nil {
+			return nil, sdk.WrapError(err, "unable to encrypt integration %s", pp.Name)
+		}
+		ei := exportentities.ProjectIntegration{
+			Model:  pp.Model.Name,
+			Config: make(map[string]exportentities.VariableValue, len(config)),
+		}
+		for k, v := range config {
+			ei.Config[k] = exportentities.VariableValue{Type: v.Type, Value: v.Value}
+		}
+		eproj.Integrations[pp.Name] = ei
+	}
+
+	return eproj, nil
+}
+
+func writeArchiveFile(tw *tar.Writer, name string, btes []byte) error {
+	if err := tw.WriteHeader(&tar.Header{
+		Name: name,
+		Mode: 0644,
+		Size: int64(len(btes)),
+	}); err != nil {
+		return sdk.WrapError(err, "unable to write header for %s", name)
+	}
+	if _, err := tw.Write(btes); err != nil {
+		return sdk.WrapError(err, "unable to write %s", name)
+	}
+	return nil
+}
+
+// readArchive returns the content of the files of a project archive by directory and name
+func readArchive(r io.Reader) (map[string]map[string][]byte, error) {
+	files := map[string]map[string][]byte{}
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, sdk.NewError(sdk.ErrWrongRequest, err)
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		btes, err := ioutil.ReadAll(tr)
+		if err != nil {
+			return nil, sdk.NewError(sdk.ErrWrongRequest, err)
+		}
+		dir, name := path.Split(path.Clean(hdr.Name))
+		dir = strings.TrimSuffix(dir, "/")
+		if files[dir] == nil {
+			files[dir] = map[string][]byte{}
+		}
+		files[dir][name] = btes
+	}
+	if files[""][exportentities.ProjectArchiveFile] == nil {
+		return nil, sdk.NewErrorFrom(sdk.ErrWrongRequest, "invalid archive: missing file %s", exportentities.ProjectArchiveFile)
+	}
+	return files, nil
+}
+
+// sortedArchiveFiles returns the names of the files of a directory of the archive
+func sortedArchiveFiles(files map[string][]byte) []string {
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (api *API) postProjectArchiveHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		passphrase := r.Header.Get(sdk.ArchivePassphraseHeader)
+		u := deprecatedGetUser(ctx)
+
+		files, err := readArchive(r.Body)
+		if err != nil {
+			return err
+		}
+		defer r.Body.Close()
+
+		var eproj exportentities.Project
+		if err := yaml.Unmarshal(files[""][exportentities.ProjectArchiveFile], &eproj); err != nil {
+			return sdk.NewError(sdk.ErrWrongRequest, err)
+		}
+		if eproj.Version != exportentities.ProjectVersion1 {
+			return sdk.NewErrorFrom(sdk.ErrWrongRequest, "unsupported project archive version %s", eproj.Version)
+		}
+		if rgxp := regexp.MustCompile(sdk.ProjectKeyPattern); !rgxp.MatchString(eproj.Key) {
+			return sdk.WrapError(sdk.ErrInvalidProjectKey, "project key %s do not respect pattern %s", eproj.Key, sdk.ProjectKeyPattern)
+		}
+
+		exist, err := project.Exist(api.mustDB(), eproj.Key)
+		if err != nil {
+			return sdk.WrapError(err, "cannot check if project %s exist", eproj.Key)
+		}
+		if exist {
+			return sdk.WrapError(sdk.ErrConflict, "project %s already exists", eproj.Key)
+		}
+
+		tx, err := api.mustDB().Begin()
+		if err != nil {
+			return sdk.WrapError(err, "cannot start transaction")
+		}
+		defer tx.Rollback() // nolint
+
+		proj, msgList, err := api.importProjectArchive(ctx, tx, &eproj, files, archiveDecryptFunc(passphrase), u)
+		if err != nil {
+			return sdk.WrapError(err, "unable to import project %s", eproj.Key)
+		}
+
+		if err := tx.Commit(); err != nil {
+			return sdk.WrapError(err, "cannot commit transaction")
+		}
+
+		event.PublishAddProject(proj, u)
+
+		return service.WriteJSON(w, translate(r, msgList), http.StatusOK)
+	}
+}
+
+// importProjectArchive creates the project of the archive, then its integrations, environments, applications,
+// pipelines and workflows
+func (api *API) importProjectArchive(ctx context.Context, tx gorp.SqlExecutor, eproj *exportentities.Project, files map[string]map[string][]byte, decryptFunc keys.DecryptFunc, u *sdk.User) (*sdk.Project, []sdk.Message, error) {
+	proj := &sdk.Project{
+		Key:         eproj.Key,
+		Name:        eproj.Name,
+		Description: eproj.Description,
+		Metadata:    eproj.Metadata,
+	}
+	if proj.Name == "" {
+		return nil, nil, sdk.WrapError(sdk.ErrInvalidProjectName, "project name must no be empty")
+	}
+
+	for name, perm := range eproj.Permissions {
+		proj.ProjectGroups = append(proj.ProjectGroups, sdk.GroupPermission{Group: sdk.Group{Name: name}, Permission: perm})
+	}
+
+	for name, v := range eproj.Variables {
+		switch v.Type {
+		case "":
+			v.Type = sdk.StringVariable
+		case sdk.SecretVariable:
+			clear, err := decryptFunc(tx, 0, v.Value)
+			if err != nil {
+				return nil, nil, sdk.WrapError(err, "unable to decrypt variable %s", name)
+			}
+			v.Value = clear
+		}
+		proj.Variable = append(proj.Variable, sdk.Variable{Name: name, Type: v.Type, Value: v.Value})
+	}
+
+	for name, kval := range eproj.Keys {
+		k, err := keys.Parse(tx, 0, name, kval, decryptFunc)
+		if err != nil {
+			return nil, nil, sdk.WrapError(err, "unable to parse key %s", name)
+		}
+		proj.Keys = append(proj.Keys, sdk.ProjectKey{Key: *k})
+	}
+
+	if err := api.createProject(tx, proj, u); err != nil {
+		return nil, nil, err
+	}
+
+	for name, ei := range eproj.Integrations {
+		model, err := integration.LoadModelByName(tx, ei.Model, false)
+		if err != nil {
+			return nil, nil, sdk.WrapError(err, "cannot load integration model %s", ei.Model)
+		}
+		pp := sdk.ProjectIntegration{
+			Name:               name,
+			ProjectID:          proj.ID,
+			IntegrationModelID: model.ID,
+			Model:              model,
+			Config:             make(sdk.IntegrationConfig, len(ei.Config)),
+		}
+		for k, v := range ei.Config {
+			pp.Config[k] = sdk.IntegrationConfigValue{Type: v.Type, Value: v.Value}
+		}
+		if err := pp.Config.DecryptSecrets(func(s string) (string, error) {
+			return decryptFunc(tx, proj.ID, s)
+		}); err != nil {
+			return nil, nil, sdk.WrapError(err, "unable to decrypt integration %s", name)
+		}
+		if err := integration.InsertIntegration(tx, &pp); err != nil {
+			return nil, nil, sdk.WrapError(err, "cannot insert integration %s", name)
+		}
+	}
+
+	var msgList []sdk.Message
+
+	proj, err := project.Load(tx, api.Cache, proj.Key, u, project.LoadOptions.WithGroups, project.LoadOptions.WithIntegrations)
+	if err != nil {
+		return nil, nil, sdk.WrapError(err, "unable to load project %s", eproj.Key)
+	}
+
+	envFiles := files[exportentities.ProjectArchiveEnvironmentsDir]
+	for _, name := range sortedArchiveFiles(envFiles) {
+		var eenv exportentities.Environment
+		if err := yaml.Unmarshal(envFiles[name], &eenv); err != nil {
+			return nil, nil, sdk.NewError(sdk.ErrWrongRequest, err)
+		}
+		_, msgs, err := environment.ParseAndImport(tx, api.Cache, proj, &eenv, false, decryptFunc, u)
+		msgList = append(msgList, msgs...)
+		if err != nil {
+			return nil, msgList, sdk.WrapError(err, "unable to import environment %s", eenv.Name)
+		}
+	}
+
+	appFiles := files[exportentities.ProjectArchiveApplicationsDir]
+	for _, name := range sortedArchiveFiles(appFiles) {
+		var eapp exportentities.Application
+		if err := yaml.Unmarshal(appFiles[name], &eapp); err != nil {
+			return nil, nil, sdk.NewError(sdk.ErrWrongRequest, err)
+		}
+		_, msgs, err := application.ParseAndImport(tx, api.Cache, proj, &eapp, false, decryptFunc, u)
+		msgList = append(msgList, msgs...)
+		if err != nil {
+			return nil, msgList, sdk.WrapError(err, "unable to import application %s", eapp.Name)
+		}
+	}
+
+	pipFiles := files[exportentities.ProjectArchivePipelinesDir]
+	for _, name := range sortedArchiveFiles(pipFiles) {
+		var epip exportentities.PipelineV1
+		if err := yaml.Unmarshal(pipFiles[name], &epip); err != nil {
+			return nil, nil, sdk.NewError(sdk.ErrWrongRequest, err)
+		}
+		_, msgs, err := pipeline.ParseAndImport(tx, api.Cache, proj, &epip, u, pipeline.ImportOptions{})
+		msgList = append(msgList, msgs...)
+		if err != nil {
+			return nil, msgList, sdk.WrapError(err, "unable to import pipeline %s", epip.Name)
+		}
+	}
+
+	// The workflows are imported in the project with all its applications, environments and pipelines
+	proj, err = project.Load(tx, api.Cache, proj.Key, u,
+		project.LoadOptions.WithGroups,
+		project.LoadOptions.WithApplications,
+		project.LoadOptions.WithEnvironments,
+		project.LoadOptions.WithPipelines,
+		project.LoadOptions.WithApplicationWithDeploymentStrategies,
+		project.LoadOptions.WithIntegrations,
+	)
+	if err != nil {
+		return nil, nil, sdk.WrapError(err, "unable to load project %s", eproj.Key)
+	}
+
+	wfFiles := files[exportentities.ProjectArchiveWorkflowsDir]
+	for _, name := range sortedArchiveFiles(wfFiles) {
+		var ew exportentities.Workflow
+		if err := yaml.Unmarshal(wfFiles[name], &ew); err != nil {
+			return nil, nil, sdk.NewError(sdk.ErrWrongRequest, err)
+		}
+		_, msgs, err := workflow.ParseAndImport(ctx, tx, api.Cache, proj, nil, &ew, u, workflow.ImportOptions{})
+		msgList = append(msgList, msgs...)
+		if err != nil {
+			return nil, msgList, sdk.WrapError(err, "unable to import workflow %s", ew.Name)
+		}
+	}
+
+	log.Info("importProjectArchive> project %s imported by %s", proj.Key, u.Username)
+	return proj, msgList, nil
+}
@@ -0,0 +1,63 @@
+package api
+
+import (
+	"archive/tar"
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/exportentities"
+)
+
+func TestArchiveSecrets(t *testing.T) {
+	encrypted, err := archiveEncryptFunc("my passphrase")(nil, 1, "projID:1:password", "my secret")
+	assert.NoError(t, err)
+	assert.NotEqual(t, "", encrypted)
+	assert.NotContains(t, encrypted, "my secret")
+
+	clear, err := archiveDecryptFunc("my passphrase")(nil, 2, encrypted)
+	assert.NoError(t, err)
+	assert.Equal(t, "my secret", clear)
+
+	_, err = archiveDecryptFunc("another passphrase")(nil, 2, encrypted)
+	assert.True(t, sdk.ErrorIs(err, sdk.ErrWrongRequest))
+
+	_, err = archiveDecryptFunc("")(nil, 2, encrypted)
+	assert.True(t, sdk.ErrorIs(err, sdk.ErrWrongRequest))
+
+	// Without passphrase the secrets are not exported
+	encrypted, err = archiveEncryptFunc("")(nil, 1, "projID:1:password", "my secret")
+	assert.NoError(t, err)
+	assert.Equal(t, "", encrypted)
+
+	clear, err = archiveDecryptFunc("")(nil, 2, "")
+	assert.NoError(t, err)
+	assert.Equal(t, "", clear)
+}
+
+func TestReadArchive(t *testing.T) {
+	buf := new(bytes.Buffer)
+	tw := tar.NewWriter(buf)
+	assert.NoError(t, writeArchiveFile(tw, exportentities.ProjectArchiveFile, []byte("key: MYPROJ")))
+	assert.NoError(t, writeArchiveFile(tw, "applications/app2.yml", []byte("name: app2")))
+	assert.NoError(t, writeArchiveFile(tw, "applications/app1.yml", []byte("name: app1")))
+	assert.NoError(t, writeArchiveFile(tw, "workflows/wf.yml", []byte("name: wf")))
+	assert.NoError(t, tw.Close())
+
+	files, err := readArchive(buf)
+	assert.NoError(t, err)
+	assert.Equal(t, "key: MYPROJ", string(files[""][exportentities.ProjectArchiveFile]))
+	assert.Equal(t, []string{"app1.yml", "app2.yml"}, sortedArchiveFiles(files[exportentities.ProjectArchiveApplicationsDir]))
+	assert.Equal(t, "name: wf", string(files[exportentities.ProjectArchiveWorkflowsDir]["wf.yml"]))
+	assert.Len(t, files[exportentities.ProjectArchivePipelinesDir], 0)
+
+	// project.yml is required
+	buf.Reset()
+	tw = tar.NewWriter(buf)
+	assert.NoError(t, writeArchiveFile(tw, "workflows/wf.yml", []byte("name: wf")))
+	assert.NoError(t, tw.Close())
+	_, err = readArchive(buf)
+	assert.True(t, sdk.ErrorIs(err, sdk.ErrWrongRequest))
+}
@@ -0,0 +1,114 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/ovh/cds/engine/api/application"
+	"github.com/ovh/cds/engine/api/project"
+	"github.com/ovh/cds/engine/api/repositoriesmanager"
+	"github.com/ovh/cds/engine/api/workflow"
+	"github.com/ovh/cds/engine/service"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// getProjectDashboardHandler summarizes the health of the workflows of a project in a single call
+func (api *API) getProjectDashboardHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		key := mux.Vars(r)[permProjectKey]
+		db := api.mustDB()
+
+		proj, err := project.Load(db, api.Cache, key, deprecatedGetUser(ctx))
+		if err != nil {
+			return sdk.WrapError(err, "unable to load project %s", key)
+		}
+
+		now := time.Now()
+		d := sdk.ProjectDashboard{ProjectKey: proj.Key}
+		d.Workflows, err = workflow.LoadWorkflowsHealth(db, proj.ID, now)
+		if err != nil {
+			return err
+		}
+		d.ComputeSuccessRates()
+
+		d.Queue, err = workflow.LoadQueueHealth(db, proj.ID, now, now.AddDate(0, 0, -7))
+		if err != nil {
+			return err
+		}
+
+		d.AsCodePullRequests, err = workflow.LoadAsCodePullRequests(db, proj.ID)
+		if err != nil {
+			return err
+		}
+
+		d.FailingOnDefaultBranch, err = api.loadFailingOnDefaultBranch(ctx, proj)
+		if err != nil {
+			return err
+		}
+
+		return service.WriteJSON(w, d, http.StatusOK)
+	}
+}
+
+// loadFailingOnDefaultBranch returns the workflows whose last run on the default branch of the repository of their
+// root application failed. The repositories whose default branch cannot be retrieved are ignored.
+func (api *API) loadFailingOnDefaultBranch(ctx context.Context, proj *sdk.Project) ([]sdk.WorkflowDefaultBranchFail, error) {
+	db := api.mustDB()
+	ws, err := workflow.LoadAll(db, proj.Key)
+	if err != nil {
+		return nil, err
+	}
+
+	res := []sdk.WorkflowDefaultBranchFail{}
+	defaultBranches := map[string]string{}
+	for _, wf := range ws {
+		if wf.WorkflowData == nil || wf.WorkflowData.Node.Context == nil || wf.WorkflowData.Node.Context.ApplicationID == 0 {
+			continue
+		}
+		app, err := application.LoadByID(db, api.Cache, wf.WorkflowData.Node.Context.ApplicationID)
+		if err != nil {
+			return nil, sdk.WrapError(err, "unable to load application of workflow %s", wf.Name)
+		}
+		if app.VCSServer == "" || app.RepositoryFullname == "" {
+			continue
+		}
+
+		repo := app.VCSServer + "/" + app.RepositoryFullname
+		branch, ok := defaultBranches[repo]
+		if !ok {
+			if projectVCSServer := repositoriesmanager.GetProjectVCSServer(proj, app.VCSServer); projectVCSServer != nil {
+				client, err := repositoriesmanager.AuthorizedClient(ctx, db, api.Cache, projectVCSServer)
+				if err == nil {
+					branch, err = repositoriesmanager.DefaultBranch(ctx, client, app.RepositoryFullname)
+				}
+				if err != nil {
+					log.Warning("loadFailingOnDefaultBranch> unable to get default branch of %s: %v", repo, err)
+				}
+			}
+			defaultBranches[repo] = branch
+		}
+		if branch == "" {
+			continue
+		}
+
+		wr, err := workflow.LoadLastRunOnBranch(db, wf.ID, branch)
+		if err != nil {
+			return nil, err
+		}
+		if wr == nil || wr.Status != sdk.StatusFail.String() {
+			continue
+		}
+		res = append(res, sdk.WorkflowDefaultBranchFail{
+			WorkflowName: wf.Name,
+			Repository:   app.RepositoryFullname,
+			Branch:       branch,
+			RunNumber:    wr.Number,
+			RunDate:      wr.Start,
+		})
+	}
+	return res, nil
+}
@@ -0,0 +1,59 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/engine/api/pipeline"
+	"github.com/ovh/cds/engine/api/test"
+	"github.com/ovh/cds/engine/api/test/assets"
+	"github.com/ovh/cds/engine/api/workflow"
+	"github.com/ovh/cds/sdk"
+)
+
+func Test_getProjectDashboardHandler(t *testing.T) {
+	api, db, router, end := newTestAPI(t)
+	defer end()
+
+	u, pass := assets.InsertAdminUser(api.mustDB())
+	key := sdk.RandomString(10)
+	proj := assets.InsertTestProject(t, db, api.Cache, key, key, u)
+	pip := sdk.Pipeline{
+		Name:      "pipeline1",
+		ProjectID: proj.ID,
+	}
+	test.NoError(t, pipeline.InsertPipeline(api.mustDB(), api.Cache, proj, &pip, nil))
+	wf := sdk.Workflow{
+		Name:       "Name",
+		ProjectID:  proj.ID,
+		ProjectKey: proj.Key,
+		WorkflowData: &sdk.WorkflowData{
+			Node: sdk.Node{
+				Type: sdk.NodeTypePipeline,
+				Context: &sdk.NodeContext{
+					PipelineID: pip.ID,
+				},
+			},
+		},
+	}
+	test.NoError(t, workflow.Insert(db, api.Cache, &wf, proj, u))
+
+	uri := router.GetRoute("GET", api.getProjectDashboardHandler, map[string]string{"permProjectKey": proj.Key})
+	test.NotEmpty(t, uri)
+	w := httptest.NewRecorder()
+	router.Mux.ServeHTTP(w, assets.NewAuthentifiedRequest(t, u, pass, "GET", uri, nil))
+	assert.Equal(t, 200, w.Code)
+
+	var d sdk.ProjectDashboard
+	test.NoError(t, json.Unmarshal(w.Body.Bytes(), &d))
+	if assert.Len(t, d.Workflows, 1) {
+		assert.Equal(t, "Name", d.Workflows[0].Name)
+		assert.Equal(t, "", d.Workflows[0].LastRunStatus)
+		assert.Equal(t, -1.0, d.Workflows[0].SuccessRate7Days)
+	}
+	assert.Equal(t, int64(0), d.Queue.Waiting)
+	assert.Empty(t, d.FailingOnDefaultBranch)
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/ovh/cds/engine/api/project"
 	"github.com/ovh/cds/engine/service"
 	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
 )
 
 func (api *API) getProjectIntegrationHandler() service.Handler {
@@ -56,6 +57,7 @@ func (api *API) putProjectIntegrationHandler() service.Handler {
 		}
 
 		ppBody.ID = ppDB.ID
+		ppBody.ProjectID = ppDB.ProjectID
 
 		for kkBody := range ppBody.Config {
 			c := ppBody.Config[kkBody]
@@ -90,6 +92,74 @@ func (api *API) putProjectIntegrationHandler() service.Handler {
 	}
 }
 
+// putProjectIntegrationCredentialsHandler updates some values of the configuration of an integration without sending
+// the whole configuration, so that an external job can rotate the credentials of the integration
+func (api *API) putProjectIntegrationCredentialsHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		projectKey := vars[permProjectKey]
+		integrationName := vars["integrationName"]
+
+		var credentials map[string]string
+		if err := service.UnmarshalBody(r, &credentials); err != nil {
+			return sdk.WrapError(err, "Cannot read body")
+		}
+		if len(credentials) == 0 {
+			return sdk.NewErrorFrom(sdk.ErrWrongRequest, "no credentials given")
+		}
+
+		p, err := project.Load(api.mustDB(), api.Cache, projectKey, deprecatedGetUser(ctx))
+		if err != nil {
+			return sdk.WrapError(err, "Cannot load project")
+		}
+
+		tx, err := api.mustDB().Begin()
+		if err != nil {
+			return sdk.WrapError(err, "Cannot start transaction")
+		}
+		defer tx.Rollback() // nolint
+
+		ppDB, err := integration.LoadIntegrationsByName(tx, projectKey, integrationName, true)
+		if err != nil {
+			return sdk.WrapError(err, "Cannot load integration %s for project %s", integrationName, projectKey)
+		}
+		if ppDB.Model.Public {
+			return sdk.ErrForbidden
+		}
+
+		pp := ppDB
+		pp.Config = ppDB.Config.Clone()
+		for k, v := range credentials {
+			c, has := pp.Config[k]
+			if !has {
+				return sdk.NewErrorFrom(sdk.ErrWrongRequest, "unknown configuration key %s for integration %s", k, integrationName)
+			}
+			c.Value = v
+			pp.Config[k] = c
+		}
+
+		if err := integration.UpdateIntegration(tx, pp); err != nil {
+			return sdk.WrapError(err, "Cannot update integration")
+		}
+
+		if err := tx.Commit(); err != nil {
+			return sdk.WrapError(err, "Cannot commit transaction")
+		}
+
+		if provider := getProvider(ctx); provider != nil {
+			log.Info("putProjectIntegrationCredentialsHandler> credentials of integration %s/%s updated by provider %s", projectKey, integrationName, *provider)
+		}
+
+		event.PublishUpdateProjectIntegration(p, pp, ppDB, deprecatedGetUser(ctx))
+
+		res, err := integration.LoadIntegrationsByName(api.mustDB(), projectKey, integrationName, false)
+		if err != nil {
+			return sdk.WrapError(err, "Cannot load integration %s for project %s", integrationName, projectKey)
+		}
+		return service.WriteJSON(w, res, http.StatusOK)
+	}
+}
+
 func (api *API) deleteProjectIntegrationHandler() service.Handler {
 	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
 		vars := mux.Vars(r)
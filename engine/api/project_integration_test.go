@@ -70,6 +70,26 @@ func TestAddUpdateAndDeleteProjectIntegration(t *testing.T) {
 	router.Mux.ServeHTTP(w, req)
 	assert.Equal(t, 200, w.Code)
 
+	// UPDATE integration credentials
+	vars = map[string]string{}
+	vars[permProjectKey] = proj.Key
+	vars["integrationName"] = pp.Name
+	uri = router.GetRoute("PUT", api.putProjectIntegrationCredentialsHandler, vars)
+	req = assets.NewAuthentifiedRequest(t, u, pass, "PUT", uri, map[string]string{"password": "new-password"})
+
+	w = httptest.NewRecorder()
+	router.Mux.ServeHTTP(w, req)
+	assert.Equal(t, 200, w.Code)
+
+	ppDB, err := integration.LoadIntegrationsByName(db, proj.Key, pp.Name, true)
+	test.NoError(t, err)
+	assert.Equal(t, "new-password", ppDB.Config["password"].Value)
+
+	req = assets.NewAuthentifiedRequest(t, u, pass, "PUT", uri, map[string]string{"unknown": "value"})
+	w = httptest.NewRecorder()
+	router.Mux.ServeHTTP(w, req)
+	assert.Equal(t, 400, w.Code)
+
 	// DELETE integration
 	vars = map[string]string{}
 	vars[permProjectKey] = proj.Key
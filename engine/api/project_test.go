@@ -12,6 +12,7 @@ import (
 	"github.com/ovh/cds/engine/api/application"
 	"github.com/ovh/cds/engine/api/bootstrap"
 	"github.com/ovh/cds/engine/api/group"
+	"github.com/ovh/cds/engine/api/permission"
 	"github.com/ovh/cds/engine/api/project"
 	"github.com/ovh/cds/engine/api/test"
 	"github.com/ovh/cds/engine/api/test/assets"
@@ -330,3 +331,24 @@ func Test_putProjectLabelsHandler(t *testing.T) {
 	assert.Equal(t, "this is a test", projReturned.Labels[2].Name)
 	assert.NotZero(t, projReturned.Labels[2].Color)
 }
+
+func Test_filterProjects(t *testing.T) {
+	projects := []sdk.Project{
+		{Key: "RO", Permission: permission.PermissionRead},
+		{Key: "RWX", Permission: permission.PermissionReadWriteExecute},
+		{Key: "ARCHIVED", Permission: permission.PermissionReadWriteExecute, Archived: true},
+	}
+
+	keys := func(ps []sdk.Project) []string {
+		res := make([]string, len(ps))
+		for i := range ps {
+			res[i] = ps[i].Key
+		}
+		return res
+	}
+
+	assert.Equal(t, []string{"RO", "RWX"}, keys(filterProjects(projects, false, false)))
+	assert.Equal(t, []string{"RO", "RWX", "ARCHIVED"}, keys(filterProjects(projects, false, true)))
+	assert.Equal(t, []string{"RWX"}, keys(filterProjects(projects, true, false)))
+	assert.Equal(t, []string{"RWX", "ARCHIVED"}, keys(filterProjects(projects, true, true)))
+}
@@ -0,0 +1,188 @@
+package provenance
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"golang.org/x/crypto/ed25519"
+
+	"github.com/ovh/cds/sdk"
+)
+
+var (
+	signingKey ed25519.PrivateKey
+	keyID      string
+)
+
+// Init sets the key of the instance used to sign the provenance of the artifacts.
+// The key is a base64 encoded ed25519 seed; without key the provenance is not signed.
+func Init(key string) error {
+	signingKey, keyID = nil, ""
+	if key == "" {
+		return nil
+	}
+	seed, err := base64.StdEncoding.DecodeString(key)
+	if err != nil {
+		return fmt.Errorf("invalid provenance signing key: %v", err)
+	}
+	if len(seed) != ed25519.SeedSize {
+		return fmt.Errorf("invalid provenance signing key: expected a seed of %d bytes, got %d", ed25519.SeedSize, len(seed))
+	}
+	signingKey = ed25519.NewKeyFromSeed(seed)
+	sum := sha256.Sum256(signingKey.Public().(ed25519.PublicKey))
+	keyID = hex.EncodeToString(sum[:])
+	return nil
+}
+
+// Key returns the public key of the instance, nil if the provenance is not signed
+func Key() *sdk.ProvenanceKey {
+	if signingKey == nil {
+		return nil
+	}
+	return &sdk.ProvenanceKey{
+		KeyID:     keyID,
+		Algorithm: "ed25519",
+		PublicKey: base64.StdEncoding.EncodeToString(signingKey.Public().(ed25519.PublicKey)),
+	}
+}
+
+// NewStatement returns the provenance of an artifact uploaded by a worker during a node run
+func NewStatement(builderID string, art sdk.WorkflowNodeRunArtifact, nodeRun sdk.WorkflowNodeRun, w *sdk.Worker) sdk.InTotoStatement {
+	digest := map[string]string{}
+	if art.SHA256sum != "" {
+		digest["sha256"] = art.SHA256sum
+	}
+	if art.SHA512sum != "" {
+		digest["sha512"] = art.SHA512sum
+	}
+	if art.MD5sum != "" && len(digest) == 0 {
+		digest["md5"] = art.MD5sum
+	}
+
+	projectKey := sdk.ParameterValue(nodeRun.BuildParameters, "cds.project")
+	workflowName := sdk.ParameterValue(nodeRun.BuildParameters, "cds.workflow")
+
+	parameters := map[string]string{}
+	for _, p := range nodeRun.BuildParameters {
+		if p.Type == sdk.SecretVariable || p.Type == sdk.KeyVariable {
+			continue
+		}
+		parameters[p.Name] = p.Value
+	}
+
+	environment := map[string]string{}
+	if w != nil {
+		environment["worker"] = w.Name
+		environment["hatchery"] = w.HatcheryName
+		if w.Model != nil {
+			environment["model"] = w.Model.Name
+		}
+	}
+
+	source := sdk.SLSAConfigSource{
+		URI:        repositoryURI(nodeRun),
+		EntryPoint: fmt.Sprintf("%s/%s/%s", projectKey, workflowName, nodeRun.WorkflowNodeName),
+	}
+	var materials []sdk.SLSAMaterial
+	if source.URI != "" {
+		if nodeRun.VCSHash != "" {
+			source.Digest = map[string]string{"sha1": nodeRun.VCSHash}
+		}
+		materials = append(materials, sdk.SLSAMaterial{URI: source.URI, Digest: source.Digest})
+	}
+
+	metadata := sdk.SLSAMetadata{
+		BuildInvocationID: fmt.Sprintf("%s/%s/%d.%d/%s", projectKey, workflowName, nodeRun.Number, nodeRun.SubNumber, nodeRun.WorkflowNodeName),
+	}
+	if !nodeRun.Start.IsZero() {
+		start := nodeRun.Start
+		metadata.BuildStartedOn = &start
+	}
+
+	return sdk.InTotoStatement{
+		Type:          sdk.InTotoStatementType,
+		Subject:       []sdk.InTotoSubject{{Name: art.Name, Digest: digest}},
+		PredicateType: sdk.SLSAProvenancePredicateType,
+		Predicate: sdk.SLSAProvenance{
+			Builder:   sdk.SLSABuilder{ID: builderID},
+			BuildType: sdk.CDSBuildType,
+			Invocation: sdk.SLSAInvocation{
+				ConfigSource: source,
+				Parameters:   parameters,
+				Environment:  environment,
+			},
+			Metadata:  metadata,
+			Materials: materials,
+		},
+	}
+}
+
+// repositoryURI returns the url of the repository of a node run, as a git+ url
+func repositoryURI(nodeRun sdk.WorkflowNodeRun) string {
+	for _, name := range []string{"git.http_url", "git.url"} {
+		if u := sdk.ParameterValue(nodeRun.BuildParameters, name); u != "" {
+			return "git+" + u
+		}
+	}
+	if nodeRun.VCSRepository != "" {
+		return fmt.Sprintf("git+%s:%s", nodeRun.VCSServer, nodeRun.VCSRepository)
+	}
+	return ""
+}
+
+// Sign returns the DSSE envelope of a statement, signed with the key of the instance if any
+func Sign(statement sdk.InTotoStatement) (*sdk.DSSEEnvelope, error) {
+	payload, err := json.Marshal(statement)
+	if err != nil {
+		return nil, sdk.WithStack(err)
+	}
+
+	env := &sdk.DSSEEnvelope{
+		PayloadType: sdk.InTotoPayloadType,
+		Payload:     base64.StdEncoding.EncodeToString(payload),
+		Signatures:  []sdk.DSSESignature{},
+	}
+	if signingKey != nil {
+		sig := ed25519.Sign(signingKey, sdk.DSSEPreAuthEncoding(env.PayloadType, payload))
+		env.Signatures = append(env.Signatures, sdk.DSSESignature{
+			KeyID: keyID,
+			Sig:   base64.StdEncoding.EncodeToString(sig),
+		})
+	}
+	return env, nil
+}
+
+// Verify checks the signature of an envelope with the key of the instance and returns its statement
+func Verify(env sdk.DSSEEnvelope) (*sdk.InTotoStatement, error) {
+	if signingKey == nil {
+		return nil, fmt.Errorf("no provenance signing key")
+	}
+	payload, err := base64.StdEncoding.DecodeString(env.Payload)
+	if err != nil {
+		return nil, fmt.Errorf("invalid payload: %v", err)
+	}
+
+	pae := sdk.DSSEPreAuthEncoding(env.PayloadType, payload)
+	publicKey := signingKey.Public().(ed25519.PublicKey)
+	for _, s := range env.Signatures {
+		if s.KeyID != keyID {
+			continue
+		}
+		sig, err := base64.StdEncoding.DecodeString(s.Sig)
+		if err != nil {
+			return nil, fmt.Errorf("invalid signature: %v", err)
+		}
+		if !ed25519.Verify(publicKey, pae, sig) {
+			return nil, fmt.Errorf("invalid signature")
+		}
+		var statement sdk.InTotoStatement
+		if err := json.Unmarshal(payload, &statement); err != nil {
+			return nil, fmt.Errorf("invalid statement: %v", err)
+		}
+		return &statement, nil
+	}
+	return nil, fmt.Errorf("no signature with key %s", keyID)
+}
@@ -0,0 +1,63 @@
+package provenance
+
+import (
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func TestSignAndVerify(t *testing.T) {
+	assert.Error(t, Init("bm90LWEtc2VlZA=="))
+	assert.NoError(t, Init(base64.StdEncoding.EncodeToString([]byte("0123456789abcdef0123456789abcdef"))))
+	defer Init("") // nolint
+
+	key := Key()
+	assert.NotNil(t, key)
+	assert.Equal(t, "ed25519", key.Algorithm)
+
+	start := time.Date(2019, 4, 1, 12, 0, 0, 0, time.UTC)
+	nodeRun := sdk.WorkflowNodeRun{
+		WorkflowNodeName: "build",
+		Number:           12,
+		SubNumber:        1,
+		Start:            start,
+		VCSHash:          "2f4c1a0",
+		BuildParameters: []sdk.Parameter{
+			{Name: "cds.project", Type: sdk.StringParameter, Value: "PROJ"},
+			{Name: "cds.workflow", Type: sdk.StringParameter, Value: "my-workflow"},
+			{Name: "git.http_url", Type: sdk.StringParameter, Value: "https://github.com/ovh/cds.git"},
+			{Name: "cds.proj.password", Type: sdk.SecretVariable, Value: "secret"},
+		},
+	}
+	art := sdk.WorkflowNodeRunArtifact{Name: "my-app.tar.gz", SHA256sum: "abc", SHA512sum: "def"}
+	w := &sdk.Worker{Name: "worker-1", HatcheryName: "swarm", Model: &sdk.Model{Name: "go-official"}}
+
+	statement := NewStatement("https://cds.example.com", art, nodeRun, w)
+	assert.Equal(t, []sdk.InTotoSubject{{Name: "my-app.tar.gz", Digest: map[string]string{"sha256": "abc", "sha512": "def"}}}, statement.Subject)
+	assert.Equal(t, "https://cds.example.com", statement.Predicate.Builder.ID)
+	assert.Equal(t, "git+https://github.com/ovh/cds.git", statement.Predicate.Invocation.ConfigSource.URI)
+	assert.Equal(t, map[string]string{"sha1": "2f4c1a0"}, statement.Predicate.Invocation.ConfigSource.Digest)
+	assert.Equal(t, "PROJ/my-workflow/build", statement.Predicate.Invocation.ConfigSource.EntryPoint)
+	assert.Equal(t, "PROJ/my-workflow/12.1/build", statement.Predicate.Metadata.BuildInvocationID)
+	assert.Equal(t, "go-official", statement.Predicate.Invocation.Environment["model"])
+	_, hasSecret := statement.Predicate.Invocation.Parameters["cds.proj.password"]
+	assert.False(t, hasSecret)
+
+	env, err := Sign(statement)
+	assert.NoError(t, err)
+	assert.Equal(t, sdk.InTotoPayloadType, env.PayloadType)
+	assert.Len(t, env.Signatures, 1)
+	assert.Equal(t, key.KeyID, env.Signatures[0].KeyID)
+
+	verified, err := Verify(*env)
+	assert.NoError(t, err)
+	assert.Equal(t, statement.Subject, verified.Subject)
+
+	env.Payload = base64.StdEncoding.EncodeToString([]byte(`{"_type": "https://in-toto.io/Statement/v0.1"}`))
+	_, err = Verify(*env)
+	assert.Error(t, err)
+}
@@ -10,14 +10,16 @@ import (
 
 	"github.com/ovh/cds/engine/api/cache"
 	"github.com/ovh/cds/engine/api/observability"
+	"github.com/ovh/cds/engine/api/pipeline"
 	"github.com/ovh/cds/engine/api/project"
+	"github.com/ovh/cds/engine/api/trash"
 	"github.com/ovh/cds/engine/api/workflow"
 	"github.com/ovh/cds/sdk"
 	"github.com/ovh/cds/sdk/log"
 )
 
 //Initialize starts goroutines for workflows
-func Initialize(ctx context.Context, store cache.Store, DBFunc func() *gorp.DbMap, workflowRunsMarkToDelete, workflowRunsDeleted *stats.Int64Measure) {
+func Initialize(ctx context.Context, store cache.Store, DBFunc func() *gorp.DbMap, trashRetention time.Duration, workflowRunsMarkToDelete, workflowRunsDeleted *stats.Int64Measure) {
 	tickPurge := time.NewTicker(15 * time.Minute)
 	defer tickPurge.Stop()
 
@@ -35,22 +37,30 @@ func Initialize(ctx context.Context, store cache.Store, DBFunc func() *gorp.DbMa
 			}
 
 			log.Debug("purge> Deleting all workflow marked to delete....")
-			if err := workflows(ctx, DBFunc(), store, workflowRunsMarkToDelete); err != nil {
+			if err := workflows(ctx, DBFunc(), store, trashRetention, workflowRunsMarkToDelete); err != nil {
 				log.Warning("purge> Error on workflows : %v", err)
 			}
+
+			log.Debug("purge> Deleting all pipelines marked to delete....")
+			if err := pipelines(DBFunc(), trashRetention); err != nil {
+				log.Warning("purge> Error on pipelines : %v", err)
+			}
 		}
 	}
 }
 
-// workflows purges all marked workflows
-func workflows(ctx context.Context, db *gorp.DbMap, store cache.Store, workflowRunsMarkToDelete *stats.Int64Measure) error {
-	query := "SELECT id, project_id FROM workflow WHERE to_delete = true ORDER BY id ASC"
+// workflows purges all marked workflows, the ones in the trash of their project are kept during the retention
+func workflows(ctx context.Context, db *gorp.DbMap, store cache.Store, trashRetention time.Duration, workflowRunsMarkToDelete *stats.Int64Measure) error {
+	query := `SELECT workflow.id, workflow.project_id FROM workflow
+		LEFT JOIN trash ON trash.type = $1 AND trash.item_id = workflow.id
+		WHERE workflow.to_delete = true AND (trash.id IS NULL OR trash.deleted < $2)
+		ORDER BY workflow.id ASC`
 	res := []struct {
 		ID        int64 `db:"id"`
 		ProjectID int64 `db:"project_id"`
 	}{}
 
-	if _, err := db.Select(&res, query); err != nil {
+	if _, err := db.Select(&res, query, sdk.TrashItemWorkflow, time.Now().Add(-trashRetention)); err != nil {
 		if err == sql.ErrNoRows {
 			return nil
 		}
@@ -133,6 +143,12 @@ func workflows(ctx context.Context, db *gorp.DbMap, store cache.Store, workflowR
 			continue
 		}
 
+		if err := trash.DeleteByItem(tx, sdk.TrashItemWorkflow, w.ID); err != nil {
+			log.Error("purge.Workflows> %v", err)
+			_ = tx.Rollback()
+			continue
+		}
+
 		if err := tx.Commit(); err != nil {
 			log.Error("purge.Workflows> unable to commit tx: %v", err)
 			_ = tx.Rollback()
@@ -143,6 +159,57 @@ func workflows(ctx context.Context, db *gorp.DbMap, store cache.Store, workflowR
 	return nil
 }
 
+// pipelines purges all marked pipelines, the ones in the trash of their project are kept during the retention
+// and the ones still used by a workflow of the trash are kept until the workflow is purged
+func pipelines(db *gorp.DbMap, trashRetention time.Duration) error {
+	var ids []int64
+	query := `SELECT pipeline.id FROM pipeline
+		LEFT JOIN trash ON trash.type = $1 AND trash.item_id = pipeline.id
+		WHERE pipeline.to_delete = true AND (trash.id IS NULL OR trash.deleted < $2)
+		ORDER BY pipeline.id ASC`
+	if _, err := db.Select(&ids, query, sdk.TrashItemPipeline, time.Now().Add(-trashRetention)); err != nil {
+		return sdk.WrapError(err, "Unable to load pipelines")
+	}
+
+	for _, id := range ids {
+		n, err := workflow.CountNodeContextsByPipelineID(db, id)
+		if err != nil {
+			log.Error("purge.Pipelines> %v", err)
+			continue
+		}
+		if n > 0 {
+			log.Info("skip pipeline %d deletion because it is still used by %d workflow nodes", id, n)
+			continue
+		}
+
+		tx, err := db.Begin()
+		if err != nil {
+			return sdk.WrapError(err, "unable to start tx")
+		}
+		if err := pipeline.DeleteAudit(tx, id); err != nil {
+			log.Error("purge.Pipelines> unable to delete audits of pipeline %d: %v", id, err)
+			_ = tx.Rollback()
+			continue
+		}
+		if err := pipeline.DeletePipeline(tx, id, 0); err != nil {
+			log.Error("purge.Pipelines> unable to delete pipeline %d: %v", id, err)
+			_ = tx.Rollback()
+			continue
+		}
+		if err := trash.DeleteByItem(tx, sdk.TrashItemPipeline, id); err != nil {
+			log.Error("purge.Pipelines> %v", err)
+			_ = tx.Rollback()
+			continue
+		}
+		if err := tx.Commit(); err != nil {
+			log.Error("purge.Pipelines> unable to commit tx: %v", err)
+			_ = tx.Rollback()
+		}
+	}
+
+	return nil
+}
+
 // deleteWorkflowRunsHistory is useful to delete all the workflow run marked with to delete flag in db
 func deleteWorkflowRunsHistory(ctx context.Context, db gorp.SqlExecutor, workflowRunsDeleted *stats.Int64Measure) error {
 	var ids []int64
@@ -151,6 +218,10 @@ func deleteWorkflowRunsHistory(ctx context.Context, db gorp.SqlExecutor, workflo
 	}
 
 	for _, id := range ids {
+		if err := workflow.PurgeRunTmpFiles(ctx, db, id); err != nil {
+			log.Error("deleteWorkflowRunsHistory> unable to delete temporary files of workflow run %d: %v", id, err)
+			continue
+		}
 		res, err := db.Exec("DELETE FROM workflow_run WHERE workflow_run.id = $1", id)
 		if err != nil {
 			log.Error("deleteWorkflowRunsHistory> unable to delete workflow run %d: %v", id, err)
@@ -0,0 +1,151 @@
+package api
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/ovh/cds/engine/api/group"
+	"github.com/ovh/cds/engine/api/project"
+	"github.com/ovh/cds/engine/api/workflow/queue"
+	"github.com/ovh/cds/engine/service"
+	"github.com/ovh/cds/sdk"
+)
+
+func (api *API) getAdminQueueQuotasHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		projects, err := queue.LoadProjectQuotas(api.mustDB())
+		if err != nil {
+			return err
+		}
+		groups, err := queue.LoadGroupWeights(api.mustDB())
+		if err != nil {
+			return err
+		}
+		return service.WriteJSON(w, sdk.QueueQuotas{
+			DefaultProjectQuota: api.Config.Queue.DefaultProjectQuota,
+			Projects:            projects,
+			Groups:              groups,
+		}, http.StatusOK)
+	}
+}
+
+func (api *API) getProjectQueueQuotaHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		key := mux.Vars(r)["permProjectKey"]
+		proj, err := project.Load(api.mustDB(), api.Cache, key, deprecatedGetUser(ctx))
+		if err != nil {
+			return sdk.WrapError(err, "cannot load project %s", key)
+		}
+
+		q, err := queue.LoadProjectQuota(api.mustDB(), proj.ID)
+		if err != nil {
+			return err
+		}
+		if q == nil {
+			q = &sdk.ProjectQueueQuota{ProjectID: proj.ID, ProjectKey: proj.Key, MaxConcurrentJobs: api.Config.Queue.DefaultProjectQuota}
+		}
+		return service.WriteJSON(w, q, http.StatusOK)
+	}
+}
+
+func (api *API) putProjectQueueQuotaHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		key := mux.Vars(r)["permProjectKey"]
+		proj, err := project.Load(api.mustDB(), api.Cache, key, deprecatedGetUser(ctx))
+		if err != nil {
+			return sdk.WrapError(err, "cannot load project %s", key)
+		}
+
+		var q sdk.ProjectQueueQuota
+		if err := service.UnmarshalBody(r, &q); err != nil {
+			return err
+		}
+		if q.MaxConcurrentJobs < 0 {
+			return sdk.NewErrorFrom(sdk.ErrWrongRequest, "max_concurrent_jobs should be positive, 0 for no limit")
+		}
+
+		if err := queue.UpsertProjectQuota(api.mustDB(), proj.ID, q.MaxConcurrentJobs); err != nil {
+			return err
+		}
+		q.ProjectID, q.ProjectKey = proj.ID, proj.Key
+		return service.WriteJSON(w, q, http.StatusOK)
+	}
+}
+
+func (api *API) deleteProjectQueueQuotaHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		key := mux.Vars(r)["permProjectKey"]
+		proj, err := project.Load(api.mustDB(), api.Cache, key, deprecatedGetUser(ctx))
+		if err != nil {
+			return sdk.WrapError(err, "cannot load project %s", key)
+		}
+
+		if err := queue.DeleteProjectQuota(api.mustDB(), proj.ID); err != nil {
+			return err
+		}
+		return service.WriteJSON(w, nil, http.StatusOK)
+	}
+}
+
+func (api *API) getGroupQueueWeightHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		name := mux.Vars(r)["permGroupName"]
+		g, err := group.LoadGroup(api.mustDB(), name)
+		if err != nil {
+			return sdk.WrapError(err, "cannot load group %s", name)
+		}
+
+		ws, err := queue.LoadGroupWeights(api.mustDB())
+		if err != nil {
+			return err
+		}
+		res := sdk.GroupQueueWeight{GroupID: g.ID, GroupName: g.Name, Weight: queue.DefaultGroupWeight}
+		for _, w := range ws {
+			if w.GroupID == g.ID {
+				res.Weight = w.Weight
+			}
+		}
+		return service.WriteJSON(w, res, http.StatusOK)
+	}
+}
+
+func (api *API) putGroupQueueWeightHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		name := mux.Vars(r)["permGroupName"]
+		g, err := group.LoadGroup(api.mustDB(), name)
+		if err != nil {
+			return sdk.WrapError(err, "cannot load group %s", name)
+		}
+
+		var gw sdk.GroupQueueWeight
+		if err := service.UnmarshalBody(r, &gw); err != nil {
+			return err
+		}
+		if gw.Weight < 1 {
+			return sdk.NewErrorFrom(sdk.ErrWrongRequest, "weight should be greater than 0")
+		}
+
+		if err := queue.UpsertGroupWeight(api.mustDB(), g.ID, gw.Weight); err != nil {
+			return err
+		}
+		gw.GroupID, gw.GroupName = g.ID, g.Name
+		return service.WriteJSON(w, gw, http.StatusOK)
+	}
+}
+
+func (api *API) deleteGroupQueueWeightHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		name := mux.Vars(r)["permGroupName"]
+		g, err := group.LoadGroup(api.mustDB(), name)
+		if err != nil {
+			return sdk.WrapError(err, "cannot load group %s", name)
+		}
+
+		if err := queue.DeleteGroupWeight(api.mustDB(), g.ID); err != nil {
+			return err
+		}
+		return service.WriteJSON(w, nil, http.StatusOK)
+	}
+}
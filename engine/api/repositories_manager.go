@@ -108,6 +108,15 @@ func (api *API) repositoriesManagerOAuthCallbackHandler() service.Handler {
 		rmName := data["repositories_manager"]
 		username := data["username"]
 
+		// the flow started without project links the account of the user
+		if projectKey == "" {
+			if _, err := api.linkUserVCSAccount(ctx, data, state, code); err != nil {
+				return err
+			}
+			http.Redirect(w, r, fmt.Sprintf("%s/settings/profile/%s", api.Config.URL.UI, username), http.StatusTemporaryRedirect)
+			return nil
+		}
+
 		u, errU := user.LoadUserWithoutAuth(api.mustDB(), username)
 		if errU != nil {
 			return sdk.WrapError(errU, "repositoriesManagerAuthorizeCallback> Cannot load user %s", username)
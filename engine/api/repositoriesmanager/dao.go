@@ -30,7 +30,7 @@ func InsertForProject(db gorp.SqlExecutor, proj *sdk.Project, vcsServer *sdk.Pro
 
 	log.Debug("repositoriesmanager.InsertForProject> %s %s", proj.Key, string(b1))
 
-	encryptedVCSServerStr, err := secret.Encrypt(b1)
+	encryptedVCSServerStr, err := secret.EncryptForProject(db, proj.ID, b1)
 	if err != nil {
 		return err
 	}
@@ -59,7 +59,7 @@ func DeleteForProject(db gorp.SqlExecutor, proj *sdk.Project, vcsServer *sdk.Pro
 		return err
 	}
 
-	encryptedVCSServerStr, err := secret.Encrypt(b1)
+	encryptedVCSServerStr, err := secret.EncryptForProject(db, proj.ID, b1)
 	if err != nil {
 		return err
 	}
@@ -74,8 +74,9 @@ func DeleteForProject(db gorp.SqlExecutor, proj *sdk.Project, vcsServer *sdk.Pro
 
 //LoadAllForProject loads all repomanager link for a project
 func LoadAllForProject(db gorp.SqlExecutor, projectKey string) ([]sdk.ProjectVCSServer, error) {
+	var projectID int64
 	vcsServerStr := []byte{}
-	if err := db.QueryRow("select vcs_servers from project where projectkey = $1", projectKey).Scan(&vcsServerStr); err != nil {
+	if err := db.QueryRow("select id, vcs_servers from project where projectkey = $1", projectKey).Scan(&projectID, &vcsServerStr); err != nil {
 		return nil, err
 	}
 
@@ -83,7 +84,7 @@ func LoadAllForProject(db gorp.SqlExecutor, projectKey string) ([]sdk.ProjectVCS
 		return []sdk.ProjectVCSServer{}, nil
 	}
 
-	clearVCSServer, err := secret.Decrypt(vcsServerStr)
+	clearVCSServer, err := secret.DecryptForProject(projectID, vcsServerStr)
 	if err != nil {
 		return nil, err
 	}
@@ -98,8 +99,9 @@ func LoadAllForProject(db gorp.SqlExecutor, projectKey string) ([]sdk.ProjectVCS
 
 //LoadForProject loads a repomanager link for a project
 func LoadForProject(db gorp.SqlExecutor, projectKey, rmName string) (*sdk.ProjectVCSServer, error) {
+	var projectID int64
 	vcsServerStr := []byte{}
-	if err := db.QueryRow("select vcs_servers from project where projectkey = $1", projectKey).Scan(&vcsServerStr); err != nil {
+	if err := db.QueryRow("select id, vcs_servers from project where projectkey = $1", projectKey).Scan(&projectID, &vcsServerStr); err != nil {
 		return nil, err
 	}
 
@@ -107,7 +109,7 @@ func LoadForProject(db gorp.SqlExecutor, projectKey, rmName string) (*sdk.Projec
 		return nil, sdk.WithStack(sdk.ErrNotFound)
 	}
 
-	clearVCSServer, err := secret.Decrypt(vcsServerStr)
+	clearVCSServer, err := secret.DecryptForProject(projectID, vcsServerStr)
 	if err != nil {
 		return nil, err
 	}
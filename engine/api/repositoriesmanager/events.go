@@ -3,6 +3,8 @@ package repositoriesmanager
 import (
 	"context"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/go-gorp/gorp"
 	"github.com/mitchellh/mapstructure"
@@ -12,6 +14,9 @@ import (
 	"github.com/ovh/cds/sdk/log"
 )
 
+// statusBatchDelay is the delay during which the events are batched, only the last status of each check of a commit is sent
+const statusBatchDelay = 2 * time.Second
+
 //ReceiveEvents has to be launched as a goroutine.
 func ReceiveEvents(c context.Context, DBFunc func() *gorp.DbMap, store cache.Store) {
 	for {
@@ -22,18 +27,79 @@ func ReceiveEvents(c context.Context, DBFunc func() *gorp.DbMap, store cache.Sto
 			return
 		}
 
-		db := DBFunc()
-		if db != nil {
-			if err := processEvent(c, db, e, store); err != nil {
-				log.Error("ReceiveEvents> err while processing error = %s", err)
-				RetryEvent(&e, err, store)
+		b := newStatusBatch()
+		b.add(e)
+		ctx, cancel := context.WithTimeout(c, statusBatchDelay)
+		for ctx.Err() == nil {
+			next := sdk.Event{}
+			store.DequeueWithContext(ctx, "events_repositoriesmanager", &next)
+			// an event can be dequeued just before the end of the delay
+			if next.EventType != "" {
+				b.add(next)
+			}
+		}
+		cancel()
+
+		for i := range b.events {
+			db := DBFunc()
+			if db != nil {
+				if err := processEvent(c, db, b.events[i], store); err != nil {
+					log.Error("ReceiveEvents> err while processing error = %s", err)
+					RetryEvent(&b.events[i], err, store)
+				}
+				continue
 			}
-			continue
+			RetryEvent(&b.events[i], nil, store)
 		}
-		RetryEvent(&e, nil, store)
 	}
 }
 
+// statusBatch keeps the last event of each check of a commit
+type statusBatch struct {
+	events []sdk.Event
+	index  map[string]int
+}
+
+func newStatusBatch() *statusBatch {
+	return &statusBatch{index: map[string]int{}}
+}
+
+func (b *statusBatch) add(e sdk.Event) {
+	key, ok := statusKey(e)
+	if !ok {
+		b.events = append(b.events, e)
+		return
+	}
+	i, has := b.index[key]
+	if !has {
+		b.index[key] = len(b.events)
+		b.events = append(b.events, e)
+		return
+	}
+	// the retried events are older than the events received since
+	if !e.Timestamp.Before(b.events[i].Timestamp) {
+		b.events[i] = e
+	}
+}
+
+// statusKey returns the check of a commit updated by an event
+func statusKey(e sdk.Event) (string, bool) {
+	if e.EventType != fmt.Sprintf("%T", sdk.EventRunWorkflowNode{}) {
+		return "", false
+	}
+	var eventWNR sdk.EventRunWorkflowNode
+	if err := mapstructure.Decode(e.Payload, &eventWNR); err != nil || eventWNR.RepositoryManagerName == "" {
+		return "", false
+	}
+	return strings.Join([]string{
+		e.ProjectKey,
+		eventWNR.RepositoryManagerName,
+		eventWNR.RepositoryFullName,
+		eventWNR.Hash,
+		sdk.VCSCommitStatusDescription(e.ProjectKey, e.WorkflowName, eventWNR),
+	}, "/"), true
+}
+
 //RetryEvent retries the events
 func RetryEvent(e *sdk.Event, err error, store cache.Store) {
 	e.Attempts++
@@ -66,6 +132,15 @@ func processEvent(ctx context.Context, db *gorp.DbMap, event sdk.Event, store ca
 		if errC != nil {
 			return fmt.Errorf("repositoriesmanager>processEvent> AuthorizedClient (%s, %s) > err:%s", event.ProjectKey, eventWNR.RepositoryManagerName, errC)
 		}
+
+		// the aggregated status of a run is the status of the run
+		if eventWNR.StatusAggregate {
+			status, err := db.SelectStr("SELECT status FROM workflow_run WHERE id = $1", eventWNR.RunID)
+			if err != nil {
+				return fmt.Errorf("repositoriesmanager>processEvent> cannot load status of workflow run %d > err:%s", eventWNR.RunID, err)
+			}
+			event.Payload["Status"] = status
+		}
 	} else {
 		return nil
 	}
@@ -0,0 +1,58 @@
+package repositoriesmanager
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/fatih/structs"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func TestStatusBatch(t *testing.T) {
+	now := time.Now()
+	newEvent := func(node, status string, ts time.Time, aggregate bool) sdk.Event {
+		e := sdk.EventRunWorkflowNode{
+			NodeName:              node,
+			Status:                status,
+			Hash:                  "abcdef",
+			RepositoryManagerName: "github",
+			RepositoryFullName:    "ovh/cds",
+		}
+		sdk.WorkflowCommitStatus{Aggregate: aggregate}.Apply("KEY", "wf", &e)
+		evt := sdk.Event{
+			EventType:    fmt.Sprintf("%T", e),
+			Payload:      structs.Map(e),
+			Timestamp:    ts,
+			ProjectKey:   "KEY",
+			WorkflowName: "wf",
+		}
+		// the events are received from the queue
+		b, _ := json.Marshal(evt)
+		var res sdk.Event
+		assert.NoError(t, json.Unmarshal(b, &res))
+		return res
+	}
+
+	b := newStatusBatch()
+	b.add(newEvent("build", sdk.StatusBuilding.String(), now, false))
+	b.add(newEvent("test", sdk.StatusBuilding.String(), now, false))
+	b.add(newEvent("build", sdk.StatusSuccess.String(), now.Add(time.Second), false))
+	// a retried event does not override a newer status
+	b.add(newEvent("test", sdk.StatusFail.String(), now.Add(-time.Second), false))
+	b.add(sdk.Event{EventType: fmt.Sprintf("%T", sdk.EventRunWorkflowJob{})})
+
+	assert.Len(t, b.events, 3)
+	assert.Equal(t, sdk.StatusSuccess.String(), b.events[0].Payload["Status"])
+	assert.Equal(t, sdk.StatusBuilding.String(), b.events[1].Payload["Status"])
+
+	// the nodes of an aggregated status send a single status
+	b = newStatusBatch()
+	b.add(newEvent("build", sdk.StatusSuccess.String(), now, true))
+	b.add(newEvent("test", sdk.StatusBuilding.String(), now.Add(time.Second), true))
+	assert.Len(t, b.events, 1)
+	assert.Equal(t, "test", b.events[0].Payload["NodeName"])
+}
@@ -339,6 +339,17 @@ func (c *vcsClient) CommitsBetweenRefs(ctx context.Context, fullname, base, head
 	return commits, nil
 }
 
+func (c *vcsClient) ChangedFiles(ctx context.Context, fullname, base, head string) ([]sdk.VCSChangedFile, error) {
+	var files []sdk.VCSChangedFile
+	path := fmt.Sprintf("/vcs/%s/repos/%s/changes?base=%s&head=%s", c.name, fullname, url.QueryEscape(base), url.QueryEscape(head))
+	if code, err := c.doJSONRequest(ctx, "GET", path, nil, &files); err != nil {
+		if code != http.StatusNotFound {
+			return nil, sdk.WrapError(err, "unable to find changed files on repository %s from %s", fullname, c.name)
+		}
+	}
+	return files, nil
+}
+
 func (c *vcsClient) Commit(ctx context.Context, fullname, hash string) (sdk.VCSCommit, error) {
 	commit := sdk.VCSCommit{}
 	path := fmt.Sprintf("/vcs/%s/repos/%s/commits/%s", c.name, fullname, hash)
@@ -531,11 +542,39 @@ func (c *vcsClient) GrantReadPermission(ctx context.Context, repo string) error
 	return nil
 }
 
+func (c *vcsClient) CurrentUser(ctx context.Context) (sdk.VCSAuthor, error) {
+	var u sdk.VCSAuthor
+	path := fmt.Sprintf("/vcs/%s/user", c.name)
+	if _, err := c.doJSONRequest(ctx, "GET", path, nil, &u); err != nil {
+		return u, sdk.WrapError(err, "unable to get current user from %s", c.name)
+	}
+	return u, nil
+}
+
+// CurrentUser returns the account on the repositories manager of an access token given by its OAuth flow
+func CurrentUser(ctx context.Context, db gorp.SqlExecutor, name, token, secret string) (sdk.VCSAuthor, error) {
+	srvs, err := services.FindByType(db, services.TypeVCS)
+	if err != nil {
+		return sdk.VCSAuthor{}, sdk.WithStack(err)
+	}
+	c := &vcsClient{
+		name:   name,
+		token:  token,
+		secret: secret,
+		srvs:   srvs,
+	}
+	return c.CurrentUser(ctx)
+}
+
 // WebhooksInfos is a set of info about webhooks
 type WebhooksInfos struct {
 	WebhooksSupported bool   `json:"webhooks_supported"`
 	WebhooksDisabled  bool   `json:"webhooks_disabled"`
 	Icon              string `json:"webhooks_icon"`
+	// Events are the events of the webhooks created by CDS
+	Events []string `json:"webhooks_events"`
+	// SecretVisible is true if the repository manager tells if a webhook has a secret, GitLab does not
+	SecretVisible bool `json:"webhooks_secret_visible"`
 }
 
 // GetWebhooksInfos returns webhooks_supported, webhooks_disabled, webhooks_creation_supported, webhooks_creation_disabled for a vcs server
@@ -18,6 +18,7 @@ import (
 	"github.com/gorilla/handlers"
 	"github.com/gorilla/mux"
 	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
 	"go.opencensus.io/tag"
 
 	"github.com/ovh/cds/engine/api/auth"
@@ -91,7 +92,15 @@ func (r *Router) pprofLabel(fn http.HandlerFunc) http.HandlerFunc {
 }
 
 func (r *Router) compress(fn http.HandlerFunc) http.HandlerFunc {
-	return handlers.CompressHandlerLevel(fn, gzip.DefaultCompression).ServeHTTP
+	compressed := handlers.CompressHandlerLevel(fn, gzip.DefaultCompression).ServeHTTP
+	return func(w http.ResponseWriter, req *http.Request) {
+		// The content range of a partial response must match its body, so range requests are not compressed
+		if req.Header.Get("Range") != "" {
+			fn(w, req)
+			return
+		}
+		compressed(w, req)
+	}
 }
 
 func (r *Router) recoverWrap(h http.HandlerFunc) http.HandlerFunc {
@@ -157,9 +166,12 @@ var headers = []string{
 	http.CanonicalHeaderKey(tracingutils.TraceIDHeader),
 	http.CanonicalHeaderKey(tracingutils.SpanIDHeader),
 	http.CanonicalHeaderKey(tracingutils.SampledHeader),
+	http.CanonicalHeaderKey(tracingutils.TraceParentHeader),
+	http.CanonicalHeaderKey(sdk.RequestIDHeader),
 	http.CanonicalHeaderKey(sdk.WorkflowAsCodeHeader),
 	http.CanonicalHeaderKey(sdk.ResponseWorkflowIDHeader),
 	http.CanonicalHeaderKey(sdk.ResponseWorkflowNameHeader),
+	http.CanonicalHeaderKey(sdk.MFAHeader),
 }
 
 // DefaultHeaders is a set of default header for the router
@@ -192,6 +204,10 @@ func (r *Router) Handle(uri string, handlers ...*service.HandlerConfig) {
 	}
 
 	f := func(w http.ResponseWriter, req *http.Request) {
+		// Correlation id of the request, given by the caller or generated
+		requestID := RequestID(req)
+		w.Header().Set(sdk.RequestIDHeader, requestID)
+		req = req.WithContext(log.ContextWithFields(req.Context(), log.Fields{log.FieldRequestID: requestID}))
 		ctx := req.Context()
 
 		// Close indicates  to close the connection after replying to this request
@@ -226,9 +242,9 @@ func (r *Router) Handle(uri string, handlers ...*service.HandlerConfig) {
 			end := time.Now()
 			latency := end.Sub(start)
 			if rc.IsDeprecated {
-				log.Error("%-7s | %13v | DEPRECATED ROUTE | %v", req.Method, latency, req.URL)
+				log.ErrorCtx(ctx, "%-7s | %13v | DEPRECATED ROUTE | %v", req.Method, latency, req.URL)
 			} else {
-				log.Debug("%-7s | %13v | %v", req.Method, latency, req.URL)
+				log.DebugCtx(ctx, "%-7s | %13v | %v", req.Method, latency, req.URL)
 			}
 		}()
 
@@ -407,6 +423,21 @@ func (r *Router) PUT(h service.HandlerFunc, cfg ...HandlerConfigParam) *service.
 	return rc
 }
 
+// PATCH will set given handler only for PATCH request
+func (r *Router) PATCH(h service.HandlerFunc, cfg ...HandlerConfigParam) *service.HandlerConfig {
+	rc := NewHandlerConfig()
+	rc.Handler = h()
+	rc.Options["allowServices"] = "false"
+	rc.Options["auth"] = "true"
+	rc.Options["allowProvider"] = "false"
+
+	rc.Method = "PATCH"
+	for _, c := range cfg {
+		c(rc)
+	}
+	return rc
+}
+
 // DELETE will set given handler only for DELETE request
 func (r *Router) DELETE(h service.HandlerFunc, cfg ...HandlerConfigParam) *service.HandlerConfig {
 	rc := NewHandlerConfig()
@@ -438,6 +469,40 @@ func AllowProvider(need bool) HandlerConfigParam {
 	return f
 }
 
+// NeedSCIM set the route for the identity providers authenticated with the SCIM token
+func NeedSCIM() HandlerConfigParam {
+	f := func(rc *service.HandlerConfig) {
+		rc.Options["needSCIM"] = "true"
+	}
+	return f
+}
+
+// Scope set the scope a personal access token needs to access the route, by default the GET routes need the
+// read:project scope and the other routes cannot be accessed with a scoped token
+func Scope(scope string) HandlerConfigParam {
+	f := func(rc *service.HandlerConfig) {
+		rc.Options["scope"] = scope
+	}
+	return f
+}
+
+// NeedMFA set the route as a sensitive operation, the users with two-factor authentication enabled have to give
+// their second factor again
+func NeedMFA() HandlerConfigParam {
+	f := func(rc *service.HandlerConfig) {
+		rc.Options["needMFA"] = "true"
+	}
+	return f
+}
+
+// AllowMFAEnrollment set the route as available to the users that have to enable two-factor authentication
+func AllowMFAEnrollment() HandlerConfigParam {
+	f := func(rc *service.HandlerConfig) {
+		rc.Options["allowMFAEnrollment"] = "true"
+	}
+	return f
+}
+
 // NeedToken set the route for requests that have the given header
 func NeedToken(k, v string) HandlerConfigParam {
 	f := func(rc *service.HandlerConfig) {
@@ -503,6 +568,30 @@ func MaintenanceAware() HandlerConfigParam {
 	return f
 }
 
+// ReadReplica route is read-only and can be served from a read replica of the database
+func ReadReplica() HandlerConfigParam {
+	f := func(rc *service.HandlerConfig) {
+		rc.Options["read_replica"] = "true"
+	}
+	return f
+}
+
+// MaintenanceLocked route starts new runs, it is rejected with 423 during CDS maintenance
+func MaintenanceLocked() HandlerConfigParam {
+	f := func(rc *service.HandlerConfig) {
+		rc.Options["maintenance_locked"] = "true"
+	}
+	return f
+}
+
+// AllowArchivedProject route can update an archived project
+func AllowArchivedProject() HandlerConfigParam {
+	f := func(rc *service.HandlerConfig) {
+		rc.Options["allow_archived"] = "true"
+	}
+	return f
+}
+
 // EnableTracing on a route
 func EnableTracing() HandlerConfigParam {
 	f := func(rc *service.HandlerConfig) {
@@ -527,8 +616,36 @@ func (r *Router) StatusPanic() sdk.MonitoringStatusLine {
 	return sdk.MonitoringStatusLine{Component: "Nb of Panics", Value: fmt.Sprintf("%d", r.nbPanic), Status: statusPanic}
 }
 
-// InitMetrics initialize prometheus metrics
+// InitMetrics initialize prometheus metrics. The views are shared by all the services running in the process,
+// the name of the service is set as tag on the measures of the router.
 func (r *Router) InitMetrics(service, name string) error {
+	tagCDSInstance, _ := tag.NewKey("cds")
+	tagService, _ := tag.NewKey("service")
+	tags := []tag.Key{tagCDSInstance, tagService}
+
+	if r.Background == nil {
+		r.Background = context.Background()
+	}
+	ctx, err := tag.New(r.Background, tag.Upsert(tagService, service))
+	if err != nil {
+		return sdk.WithStack(err)
+	}
+	r.Background = ctx
+
+	if v := view.Find("router_hits"); v != nil {
+		r.Stats.Hits, _ = v.Measure.(*stats.Int64Measure)
+		if v := view.Find("router_errors"); v != nil {
+			r.Stats.Errors, _ = v.Measure.(*stats.Int64Measure)
+		}
+		if v := view.Find("sse_clients"); v != nil {
+			r.Stats.SSEClients, _ = v.Measure.(*stats.Int64Measure)
+		}
+		if v := view.Find("sse_events"); v != nil {
+			r.Stats.SSEEvents, _ = v.Measure.(*stats.Int64Measure)
+		}
+		return nil
+	}
+
 	label := fmt.Sprintf("cds/%s/%s/router_errors", service, name)
 	r.Stats.Errors = stats.Int64(label, "number of errors", stats.UnitDimensionless)
 	label = fmt.Sprintf("cds/%s/%s/router_hits", service, name)
@@ -538,9 +655,6 @@ func (r *Router) InitMetrics(service, name string) error {
 	label = fmt.Sprintf("cds/%s/%s/sse_events", service, name)
 	r.Stats.SSEEvents = stats.Int64(label, "number of sse events", stats.UnitDimensionless)
 
-	tagCDSInstance, _ := tag.NewKey("cds")
-	tags := []tag.Key{tagCDSInstance}
-
 	log.Info("api> Stats initialized")
 
 	return observability.RegisterView(
@@ -2,6 +2,7 @@ package api
 
 import (
 	"context"
+	"crypto/subtle"
 	"net/http"
 	"strings"
 
@@ -9,8 +10,11 @@ import (
 
 	"github.com/ovh/cds/engine/api/accesstoken"
 	"github.com/ovh/cds/engine/api/auth"
+	"github.com/ovh/cds/engine/api/cache"
+	"github.com/ovh/cds/engine/api/event"
 	"github.com/ovh/cds/engine/api/group"
 	"github.com/ovh/cds/engine/api/observability"
+	"github.com/ovh/cds/engine/api/user"
 	"github.com/ovh/cds/engine/api/worker"
 	"github.com/ovh/cds/engine/service"
 	"github.com/ovh/cds/sdk"
@@ -21,6 +25,7 @@ type contextKey int
 
 const (
 	ContextGrantedUser contextKey = iota
+	contextReadReplica
 )
 
 // Check Provider
@@ -44,6 +49,22 @@ func (api *API) authAllowProviderMiddleware(ctx context.Context, w http.Response
 	return ctx, true, nil
 }
 
+// Checks the bearer token of the identity providers on the SCIM API
+func (api *API) authSCIMMiddleware(ctx context.Context, w http.ResponseWriter, req *http.Request, rc *service.HandlerConfig) (context.Context, bool, error) {
+	if rc.Options["needSCIM"] != "true" {
+		return ctx, true, nil
+	}
+	if !api.Config.Auth.SCIM.Enable {
+		return ctx, false, sdk.WrapError(sdk.ErrNotFound, "Router> SCIM API is disabled")
+	}
+	token := strings.TrimPrefix(req.Header.Get("Authorization"), "Bearer ")
+	if subtle.ConstantTimeCompare([]byte(token), []byte(api.Config.Auth.SCIM.Token)) != 1 {
+		return ctx, false, sdk.WrapError(sdk.ErrUnauthorized, "Router> Authorization denied on SCIM API %s %s for %s", req.Method, req.URL, req.RemoteAddr)
+	}
+	ctx = context.WithValue(ctx, auth.ContextUser, &sdk.User{Username: sdk.UserOriginSCIM})
+	return ctx, false, nil
+}
+
 // Checks static tokens
 func (api *API) authStatusTokenMiddleware(ctx context.Context, w http.ResponseWriter, req *http.Request, rc *service.HandlerConfig) (context.Context, bool, error) {
 	if h, ok := rc.Options["token"]; ok {
@@ -129,6 +150,9 @@ func (api *API) authDeprecatedMiddleware(ctx context.Context, w http.ResponseWri
 			}
 		}
 	case deprecatedGetUser(ctx) != nil:
+		if deprecatedGetUser(ctx).Deactivated {
+			return ctx, false, sdk.WrapError(sdk.ErrUnauthorized, "Router> User %s is deactivated", deprecatedGetUser(ctx).Username)
+		}
 		if err := loadUserPermissions(api.mustDB(), api.Cache, deprecatedGetUser(ctx)); err != nil {
 			return ctx, false, sdk.WrapError(sdk.ErrUnauthorized, "Router> Unable to load user %d permission: %v", deprecatedGetUser(ctx).ID, err)
 		}
@@ -237,6 +261,22 @@ func (api *API) authJWTMiddleware(ctx context.Context, w http.ResponseWriter, re
 		}
 	}
 
+	// Checks the scopes of personal access tokens
+	if scope := accessTokenScope(rc); !token.Scopes.Allows(scope) {
+		return ctx, false, sdk.WrapError(sdk.ErrForbidden, "Router> Token %s does not have the scope %s on %s %s", token.ID, scope, req.Method, req.URL)
+	}
+
+	// Checks the restrictions of the token, the violations are recorded in the audit log
+	ip := ClientIP(req, api.trustedProxies)
+	if reason := accesstoken.CheckRestrictions(api.Cache, token, ip); reason != "" {
+		event.PublishAccessTokenDeny(token, reason, ip.String())
+		return ctx, false, sdk.WrapError(sdk.ErrForbidden, "Router> Token %s denied on %s %s: %s", token.ID, req.Method, req.URL, reason)
+	}
+
+	if err := accesstoken.UpdateLastUsed(api.mustDB(), &token); err != nil {
+		log.Error("api.authJWTMiddleware> %v", err)
+	}
+
 	// Put the granted user in the context
 	var grantedUser = sdk.GrantedUser{
 		Fullname:   token.Description,
@@ -251,6 +291,9 @@ func (api *API) authJWTMiddleware(ctx context.Context, w http.ResponseWriter, re
 	if err != nil {
 		return ctx, false, sdk.WithStack(err)
 	}
+	if deprecatedGetUser(ctx).Deactivated {
+		return ctx, false, sdk.WrapError(sdk.ErrUnauthorized, "Router> User %s is deactivated", token.User.Username)
+	}
 
 	for _, g := range grantedUser.Groups {
 		if err := api.deprecatedSetGroupsAndPermissionsFromGroupID(ctx, g.ID); err != nil {
@@ -262,3 +305,71 @@ func (api *API) authJWTMiddleware(ctx context.Context, w http.ResponseWriter, re
 
 	return ctx, false, nil
 }
+
+// accessTokenScope returns the scope needed by a personal access token to access a route, an empty scope
+// is never granted. The admin routes cannot be accessed with a scoped token
+func accessTokenScope(rc *service.HandlerConfig) string {
+	if rc.Options["needAdmin"] == "true" {
+		return ""
+	}
+	if s, ok := rc.Options["scope"]; ok {
+		return s
+	}
+	if rc.Method == http.MethodGet {
+		return sdk.AccessTokenScopeReadProject
+	}
+	return ""
+}
+
+// Checks the second factor of the users with a local account: the members of a group requiring two-factor
+// authentication have to enable it, and the sensitive operations need a step-up verification
+func (api *API) authMFAMiddleware(ctx context.Context, w http.ResponseWriter, req *http.Request, rc *service.HandlerConfig) (context.Context, error) {
+	u := deprecatedGetUser(ctx)
+	if u == nil || u.ID == 0 || !api.isLocalAuth() || getProvider(ctx) != nil || getWorker(ctx) != nil || getHatchery(ctx) != nil || getService(ctx) != nil {
+		return ctx, nil
+	}
+
+	if rc.Options["allowMFAEnrollment"] != "true" {
+		required, err := api.isMFAEnrollmentRequired(u)
+		if err != nil {
+			return ctx, err
+		}
+		if required {
+			return ctx, sdk.WrapError(sdk.ErrMFAEnrollmentRequired, "Router> User %s has to enable two-factor authentication", u.Username)
+		}
+	}
+
+	if rc.Options["needMFA"] != "true" {
+		return ctx, nil
+	}
+
+	// A step-up verification is valid for some minutes on the session
+	session := getUserSession(ctx)
+	if session == "" {
+		session = req.Header.Get(sdk.SessionTokenHeader)
+	}
+	k := cache.Key("mfa", "stepup", session)
+	var verified bool
+	if session != "" && api.Cache.Get(k, &verified) && verified {
+		return ctx, nil
+	}
+
+	usr, err := user.LoadUserAndAuth(api.mustDB(), u.Username)
+	if err != nil {
+		return ctx, sdk.WrapError(err, "cannot load user %s", u.Username)
+	}
+	if !usr.Auth.MFA.Enabled() {
+		return ctx, nil
+	}
+	v, err := sdk.ParseMFAHeader(req.Header.Get(sdk.MFAHeader))
+	if err != nil {
+		return ctx, err
+	}
+	if err := api.verifyMFA(usr, v); err != nil {
+		return ctx, sdk.WrapError(err, "Router> Step-up verification failed for %s on %s %s", u.Username, req.Method, req.URL)
+	}
+	if session != "" {
+		api.Cache.SetWithTTL(k, true, sdk.MFAStepUpTTL)
+	}
+	return ctx, nil
+}
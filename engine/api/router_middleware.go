@@ -8,9 +8,11 @@ import (
 	"strings"
 
 	"github.com/go-gorp/gorp"
+	"github.com/gorilla/mux"
 
 	"github.com/ovh/cds/engine/api/cache"
 	"github.com/ovh/cds/engine/api/observability"
+	"github.com/ovh/cds/engine/api/project"
 	"github.com/ovh/cds/engine/service"
 	"github.com/ovh/cds/sdk"
 	"github.com/ovh/cds/sdk/log"
@@ -27,6 +29,14 @@ func (api *API) authMiddleware(ctx context.Context, w http.ResponseWriter, req *
 	if !shouldContinue {
 		return ctx, nil
 	}
+	// Identity providers on the SCIM API
+	ctx, shouldContinue, err = api.authSCIMMiddleware(ctx, w, req, rc)
+	if err != nil {
+		return ctx, sdk.WithStack(err)
+	}
+	if !shouldContinue {
+		return ctx, nil
+	}
 	// Tokens (like izanamy)
 	ctx, shouldContinue, err = api.authStatusTokenMiddleware(ctx, w, req, rc)
 	if err != nil {
@@ -104,5 +114,38 @@ func (api *API) maintenanceMiddleware(ctx context.Context, w http.ResponseWriter
 	if rc.Options["maintenance_aware"] == "true" && api.Maintenance {
 		return ctx, sdk.WrapError(sdk.ErrServiceUnavailable, "CDS Maintenance ON")
 	}
+	if rc.Options["maintenance_locked"] == "true" && api.Maintenance {
+		return ctx, sdk.WrapError(sdk.ErrMaintenance, "CDS Maintenance ON")
+	}
+	return ctx, nil
+}
+
+func (api *API) readReplicaMiddleware(ctx context.Context, w http.ResponseWriter, req *http.Request, rc *service.HandlerConfig) (context.Context, error) {
+	if rc.Options["read_replica"] == "true" && req.Method == http.MethodGet {
+		ctx = context.WithValue(ctx, contextReadReplica, true)
+	}
+	return ctx, nil
+}
+
+// archivedProjectMiddleware rejects the changes on an archived project, it stays read-only until it is restored
+func (api *API) archivedProjectMiddleware(ctx context.Context, w http.ResponseWriter, req *http.Request, rc *service.HandlerConfig) (context.Context, error) {
+	if req.Method == http.MethodGet || rc.Options["allow_archived"] == "true" {
+		return ctx, nil
+	}
+	vars := mux.Vars(req)
+	key := vars[permProjectKey]
+	if key == "" {
+		key = vars["key"]
+	}
+	if key == "" {
+		return ctx, nil
+	}
+	archived, err := project.IsArchived(api.mustDB(), key)
+	if err != nil {
+		return ctx, err
+	}
+	if archived {
+		return ctx, sdk.WithStack(sdk.ErrProjectArchived)
+	}
 	return ctx, nil
 }
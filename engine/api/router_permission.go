@@ -7,6 +7,8 @@ import (
 	"github.com/go-gorp/gorp"
 
 	"github.com/ovh/cds/engine/api/cache"
+	"github.com/ovh/cds/engine/api/organization"
+	"github.com/ovh/cds/engine/api/permission"
 	"github.com/ovh/cds/engine/api/project"
 	"github.com/ovh/cds/engine/api/workflow"
 	"github.com/ovh/cds/sdk"
@@ -42,6 +44,33 @@ func loadGroupPermissionInUser(db gorp.SqlExecutor, groupID int64, u *sdk.User)
 	return nil
 }
 
+// loadOrganizationPermissionInUser gives to the administrators of an organization all the permissions on its projects
+// and their workflows
+func loadOrganizationPermissionInUser(db gorp.SqlExecutor, u *sdk.User) error {
+	projectKeys, err := organization.LoadAdministratedProjectKeys(db, u.ID)
+	if err != nil {
+		return err
+	}
+	if u.Permissions.ProjectsPerm == nil {
+		u.Permissions.ProjectsPerm = make(map[string]int, len(projectKeys))
+	}
+	for _, k := range projectKeys {
+		u.Permissions.ProjectsPerm[k] = permission.PermissionReadWriteExecute
+	}
+
+	workflowKeys, err := organization.LoadAdministratedWorkflowKeys(db, u.ID)
+	if err != nil {
+		return err
+	}
+	if u.Permissions.WorkflowsPerm == nil {
+		u.Permissions.WorkflowsPerm = make(map[string]int, len(workflowKeys))
+	}
+	for _, k := range workflowKeys {
+		u.Permissions.WorkflowsPerm[k] = permission.PermissionReadWriteExecute
+	}
+	return nil
+}
+
 // loadUserPermissions retrieves all group memberships
 func loadUserPermissions(db gorp.SqlExecutor, store cache.Store, u *sdk.User) error {
 	u.Groups = nil
@@ -81,6 +110,10 @@ func loadUserPermissions(db gorp.SqlExecutor, store cache.Store, u *sdk.User) er
 			u.Groups = append(u.Groups, group)
 		}
 
+		if err := loadOrganizationPermissionInUser(db, u); err != nil {
+			return err
+		}
+
 		store.SetWithTTL(kp, u.Permissions, 120)
 		store.SetWithTTL(kg, u.Groups, 120)
 
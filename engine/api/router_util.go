@@ -3,8 +3,10 @@ package api
 import (
 	"context"
 	"fmt"
+	"net"
 	"net/http"
 	"reflect"
+	"regexp"
 	"strconv"
 	"strings"
 
@@ -187,6 +189,68 @@ func requestVarInt(r *http.Request, s string) (int64, error) {
 	return id, nil
 }
 
+// ParseCIDRs parses a comma separated list of CIDRs
+func ParseCIDRs(s string) ([]*net.IPNet, error) {
+	var res []*net.IPNet
+	for _, c := range strings.Split(s, ",") {
+		c = strings.TrimSpace(c)
+		if c == "" {
+			continue
+		}
+		_, n, err := net.ParseCIDR(c)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CIDR %s", c)
+		}
+		res = append(res, n)
+	}
+	return res, nil
+}
+
+func containsIP(nets []*net.IPNet, ip net.IP) bool {
+	for _, n := range nets {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// ClientIP returns the address of the client of a request. Behind trusted reverse proxies, it is the last address
+// of the X-Forwarded-For header which is not a trusted proxy.
+func ClientIP(r *http.Request, trustedProxies []*net.IPNet) net.IP {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || !containsIP(trustedProxies, ip) {
+		return ip
+	}
+	forwarded := strings.Split(r.Header.Get("X-Forwarded-For"), ",")
+	for i := len(forwarded) - 1; i >= 0; i-- {
+		fip := net.ParseIP(strings.TrimSpace(forwarded[i]))
+		if fip == nil {
+			break
+		}
+		ip = fip
+		if !containsIP(trustedProxies, fip) {
+			break
+		}
+	}
+	return ip
+}
+
+var requestIDRegexp = regexp.MustCompile(`^[A-Za-z0-9-]{1,64}$`)
+
+// RequestID returns the correlation id given by the caller of the request, a new one is generated if it is missing or
+// invalid to keep it out of the logs
+func RequestID(r *http.Request) string {
+	if id := r.Header.Get(sdk.RequestIDHeader); requestIDRegexp.MatchString(id) {
+		return id
+	}
+	return sdk.UUID()
+}
+
 func translate(r *http.Request, msgList []sdk.Message) []string {
 	al := r.Header.Get("Accept-Language")
 	msgListString := []string{}
@@ -1,11 +1,14 @@
 package api_test
 
 import (
+	"net"
 	"net/http"
 	"net/url"
+	"strings"
 	"testing"
 
 	"github.com/ovh/cds/engine/api"
+	"github.com/ovh/cds/sdk"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -28,3 +31,36 @@ func TestQuerySortError(t *testing.T) {
 	_, err = api.QuerySort(&http.Request{URL: url})
 	assert.Error(t, err)
 }
+
+func TestClientIP(t *testing.T) {
+	proxies, err := api.ParseCIDRs("10.0.0.0/8, 192.168.1.1/32")
+	assert.NoError(t, err)
+	assert.Len(t, proxies, 2)
+	_, err = api.ParseCIDRs("10.0.0.0")
+	assert.Error(t, err)
+
+	req := &http.Request{RemoteAddr: "203.0.113.10:4242", Header: http.Header{}}
+	req.Header.Set("X-Forwarded-For", "198.51.100.1")
+	assert.Equal(t, net.ParseIP("203.0.113.10"), api.ClientIP(req, proxies))
+
+	req.RemoteAddr = "10.0.0.2:4242"
+	req.Header.Set("X-Forwarded-For", "198.51.100.1, 203.0.113.7, 192.168.1.1")
+	assert.Equal(t, net.ParseIP("203.0.113.7"), api.ClientIP(req, proxies))
+
+	req.Header.Del("X-Forwarded-For")
+	assert.Equal(t, net.ParseIP("10.0.0.2"), api.ClientIP(req, proxies))
+}
+
+func TestRequestID(t *testing.T) {
+	req := &http.Request{Header: http.Header{}}
+	req.Header.Set(sdk.RequestIDHeader, "5178ce1f-2f76-45c5-a203-58c10c3e2c73")
+	assert.Equal(t, "5178ce1f-2f76-45c5-a203-58c10c3e2c73", api.RequestID(req))
+
+	// the invalid ids are replaced
+	for _, id := range []string{"", "id\nlevel=error msg=forged", strings.Repeat("a", 65)} {
+		req.Header.Set(sdk.RequestIDHeader, id)
+		generated := api.RequestID(req)
+		assert.NotEqual(t, id, generated)
+		assert.Len(t, generated, 36)
+	}
+}
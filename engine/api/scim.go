@@ -0,0 +1,570 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-gorp/gorp"
+	"github.com/gorilla/mux"
+
+	"github.com/ovh/cds/engine/api/event"
+	"github.com/ovh/cds/engine/api/group"
+	"github.com/ovh/cds/engine/api/project"
+	"github.com/ovh/cds/engine/api/scim"
+	"github.com/ovh/cds/engine/api/user"
+	"github.com/ovh/cds/engine/service"
+	"github.com/ovh/cds/sdk"
+)
+
+func (api *API) scimLocation(resourceType string, id int64) string {
+	return api.Config.URL.API + "/scim/v2/" + resourceType + "/" + strconv.FormatInt(id, 10)
+}
+
+func scimID(r *http.Request) (int64, error) {
+	id, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		return 0, sdk.NewErrorFrom(sdk.ErrNotFound, "invalid id %s", mux.Vars(r)["id"])
+	}
+	return id, nil
+}
+
+func scimPage(r *http.Request) (int, int) {
+	startIndex, _ := strconv.Atoi(r.FormValue("startIndex"))
+	if startIndex < 1 {
+		startIndex = 1
+	}
+	count, _ := strconv.Atoi(r.FormValue("count"))
+	if count < 0 {
+		count = 0
+	}
+	return startIndex, count
+}
+
+func (api *API) getSCIMServiceProviderConfigHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		supported := func(b bool) map[string]bool { return map[string]bool{"supported": b} }
+		return service.WriteJSON(w, map[string]interface{}{
+			"schemas":        []string{sdk.SCIMSchemaServiceProviderConfig},
+			"patch":          supported(true),
+			"bulk":           map[string]interface{}{"supported": false, "maxOperations": 0, "maxPayloadSize": 0},
+			"filter":         map[string]interface{}{"supported": true, "maxResults": 0},
+			"changePassword": supported(false),
+			"sort":           supported(false),
+			"etag":           supported(false),
+			"authenticationSchemes": []map[string]string{{
+				"type":        "oauthbearertoken",
+				"name":        "Bearer token",
+				"description": "Token set in the SCIM configuration of the CDS API",
+			}},
+		}, http.StatusOK)
+	}
+}
+
+func (api *API) loadSCIMUser(db gorp.SqlExecutor, id int64) (*sdk.User, error) {
+	u, err := user.LoadUserWithoutAuthByID(db, id)
+	if err != nil {
+		if sdk.Cause(err) == sql.ErrNoRows {
+			return nil, sdk.WithStack(sdk.ErrUserNotFound)
+		}
+		return nil, sdk.WrapError(err, "cannot load user %d", id)
+	}
+	return u, nil
+}
+
+func (api *API) writeSCIMUser(w http.ResponseWriter, u *sdk.User, status int) error {
+	groups, err := group.LoadGroupByUser(api.mustDB(), u.ID)
+	if err != nil {
+		return sdk.WrapError(err, "cannot load groups of user %s", u.Username)
+	}
+	return service.WriteJSON(w, scim.NewUser(*u, groups, api.scimLocation("Users", u.ID)), status)
+}
+
+// saveSCIMUser updates a user from its SCIM resource. A user becoming inactive is deprovisioned.
+func saveSCIMUser(db gorp.SqlExecutor, u *sdk.User, s sdk.SCIMUser) error {
+	if s.UserName != "" && s.UserName != u.Username {
+		if _, err := user.FindUserIDByName(db, s.UserName); err == nil {
+			return sdk.NewErrorFrom(sdk.ErrConflict, "user %s already exists", s.UserName)
+		}
+		u.Username = s.UserName
+	}
+	u.Fullname = scim.Fullname(s)
+	u.Email = scim.Email(s)
+	if err := user.UpdateUser(db, *u); err != nil {
+		return sdk.WrapError(err, "cannot update user %s", u.Username)
+	}
+
+	if s.Active == nil || *s.Active != u.Deactivated {
+		return nil
+	}
+	u.Deactivated = !*s.Active
+	if u.Deactivated {
+		return scim.DeactivateUser(db, u.ID)
+	}
+	return user.SetDeactivated(db, u.ID, false)
+}
+
+func (api *API) getSCIMUsersHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		startIndex, count := scimPage(r)
+
+		var users []*sdk.User
+		var total int
+		if filter := r.FormValue("filter"); filter != "" {
+			attr, value, err := scim.ParseFilter(filter)
+			if err != nil {
+				return err
+			}
+			if !strings.EqualFold(attr, "userName") {
+				return sdk.NewErrorFrom(sdk.ErrWrongRequest, "unsupported filter on %s", attr)
+			}
+			u, err := user.LoadUserWithoutAuth(api.mustDB(), value)
+			if err != nil && sdk.Cause(err) != sql.ErrNoRows {
+				return sdk.WrapError(err, "cannot load user %s", value)
+			}
+			if u != nil {
+				total = 1
+				if startIndex == 1 {
+					users = append(users, u)
+				}
+			}
+		} else {
+			n, err := user.CountUser(api.mustDB())
+			if err != nil {
+				return sdk.WrapError(err, "cannot count users")
+			}
+			total = int(n)
+			users, err = user.LoadUsersPage(api.mustDB(), startIndex-1, count)
+			if err != nil {
+				return sdk.WrapError(err, "cannot load users")
+			}
+		}
+
+		resources := make([]interface{}, len(users))
+		for i, u := range users {
+			resources[i] = scim.NewUser(*u, nil, api.scimLocation("Users", u.ID))
+		}
+		return service.WriteJSON(w, scim.ListResponse(resources, total, startIndex), http.StatusOK)
+	}
+}
+
+func (api *API) postSCIMUserHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		var s sdk.SCIMUser
+		if err := service.UnmarshalBody(r, &s); err != nil {
+			return err
+		}
+		if s.UserName == "" {
+			return sdk.WithStack(sdk.ErrInvalidUsername)
+		}
+
+		tx, err := api.mustDB().Begin()
+		if err != nil {
+			return sdk.WithStack(err)
+		}
+		defer tx.Rollback() // nolint
+
+		if _, err := user.FindUserIDByName(tx, s.UserName); err == nil {
+			return sdk.NewErrorFrom(sdk.ErrConflict, "user %s already exists", s.UserName)
+		}
+
+		u := &sdk.User{
+			Username: s.UserName,
+			Fullname: scim.Fullname(s),
+			Email:    scim.Email(s),
+			Origin:   sdk.UserOriginSCIM,
+		}
+		if err := user.InsertUser(tx, u, &sdk.Auth{EmailVerified: true}); err != nil {
+			return sdk.WrapError(err, "cannot insert user %s", u.Username)
+		}
+		if err := group.CheckUserInDefaultGroup(tx, u.ID); err != nil {
+			return sdk.WrapError(err, "cannot add user %s in default group", u.Username)
+		}
+		if s.Active != nil && !*s.Active {
+			if err := user.SetDeactivated(tx, u.ID, true); err != nil {
+				return err
+			}
+			u.Deactivated = true
+		}
+
+		if err := tx.Commit(); err != nil {
+			return sdk.WithStack(err)
+		}
+		return api.writeSCIMUser(w, u, http.StatusCreated)
+	}
+}
+
+func (api *API) getSCIMUserHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		id, err := scimID(r)
+		if err != nil {
+			return err
+		}
+		u, err := api.loadSCIMUser(api.mustDB(), id)
+		if err != nil {
+			return err
+		}
+		return api.writeSCIMUser(w, u, http.StatusOK)
+	}
+}
+
+func (api *API) putSCIMUserHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		id, err := scimID(r)
+		if err != nil {
+			return err
+		}
+		var s sdk.SCIMUser
+		if err := service.UnmarshalBody(r, &s); err != nil {
+			return err
+		}
+
+		tx, err := api.mustDB().Begin()
+		if err != nil {
+			return sdk.WithStack(err)
+		}
+		defer tx.Rollback() // nolint
+
+		u, err := api.loadSCIMUser(tx, id)
+		if err != nil {
+			return err
+		}
+		if err := saveSCIMUser(tx, u, s); err != nil {
+			return err
+		}
+
+		if err := tx.Commit(); err != nil {
+			return sdk.WithStack(err)
+		}
+		return api.writeSCIMUser(w, u, http.StatusOK)
+	}
+}
+
+func (api *API) patchSCIMUserHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		id, err := scimID(r)
+		if err != nil {
+			return err
+		}
+		var req sdk.SCIMPatchRequest
+		if err := service.UnmarshalBody(r, &req); err != nil {
+			return err
+		}
+
+		tx, err := api.mustDB().Begin()
+		if err != nil {
+			return sdk.WithStack(err)
+		}
+		defer tx.Rollback() // nolint
+
+		u, err := api.loadSCIMUser(tx, id)
+		if err != nil {
+			return err
+		}
+		s := scim.NewUser(*u, nil, "")
+		if err := scim.PatchUser(&s, req.Operations); err != nil {
+			return err
+		}
+		if err := saveSCIMUser(tx, u, s); err != nil {
+			return err
+		}
+
+		if err := tx.Commit(); err != nil {
+			return sdk.WithStack(err)
+		}
+		return api.writeSCIMUser(w, u, http.StatusOK)
+	}
+}
+
+func (api *API) deleteSCIMUserHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		id, err := scimID(r)
+		if err != nil {
+			return err
+		}
+
+		tx, err := api.mustDB().Begin()
+		if err != nil {
+			return sdk.WithStack(err)
+		}
+		defer tx.Rollback() // nolint
+
+		if _, err := api.loadSCIMUser(tx, id); err != nil {
+			return err
+		}
+		if err := scim.DeactivateUser(tx, id); err != nil {
+			return err
+		}
+
+		if err := tx.Commit(); err != nil {
+			return sdk.WithStack(err)
+		}
+		return service.Write(w, nil, http.StatusNoContent, "application/json")
+	}
+}
+
+func (api *API) loadSCIMGroup(db gorp.SqlExecutor, id int64) (*sdk.Group, error) {
+	g, err := group.LoadGroupByID(db, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := group.LoadUserGroup(db, g); err != nil {
+		return nil, sdk.WrapError(err, "cannot load users of group %s", g.Name)
+	}
+	return g, nil
+}
+
+// saveSCIMGroup updates a group from its SCIM resource. The group admins are never removed from the group.
+func saveSCIMGroup(db gorp.SqlExecutor, g *sdk.Group, s sdk.SCIMGroup) error {
+	if s.DisplayName != "" && s.DisplayName != g.Name {
+		if g.Name == sdk.SharedInfraGroupName || group.IsDefaultGroupName(g.Name) {
+			return sdk.NewErrorFrom(sdk.ErrForbidden, "group %s cannot be renamed", g.Name)
+		}
+		oldName := g.Name
+		g.Name = s.DisplayName
+		if err := group.UpdateGroup(db, g, oldName); err != nil {
+			return sdk.WrapError(err, "cannot rename group %s", oldName)
+		}
+	}
+
+	ids, err := scim.MemberIDs(s)
+	if err != nil {
+		return err
+	}
+	wanted := map[int64]bool{}
+	for _, id := range ids {
+		wanted[id] = true
+	}
+	current := map[int64]bool{}
+	for _, u := range append(g.Admins, g.Users...) {
+		current[u.ID] = true
+	}
+
+	for _, id := range ids {
+		if current[id] {
+			continue
+		}
+		if _, err := user.LoadUserWithoutAuthByID(db, id); err != nil {
+			return sdk.NewErrorFrom(sdk.ErrWrongRequest, "unknown member %d", id)
+		}
+		if err := group.InsertUserInGroup(db, g.ID, id, false); err != nil {
+			return sdk.WrapError(err, "cannot add user %d in group %s", id, g.Name)
+		}
+		current[id] = true
+	}
+	for _, u := range g.Users {
+		if !wanted[u.ID] {
+			if err := group.DeleteGroupMember(db, g.ID, u.ID); err != nil {
+				return err
+			}
+		}
+	}
+
+	g.Admins, g.Users = nil, nil
+	return group.LoadUserGroup(db, g)
+}
+
+func (api *API) getSCIMGroupsHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		startIndex, count := scimPage(r)
+
+		var groups []sdk.Group
+		var total int
+		if filter := r.FormValue("filter"); filter != "" {
+			attr, value, err := scim.ParseFilter(filter)
+			if err != nil {
+				return err
+			}
+			if !strings.EqualFold(attr, "displayName") {
+				return sdk.NewErrorFrom(sdk.ErrWrongRequest, "unsupported filter on %s", attr)
+			}
+			g, err := group.LoadGroupByName(api.mustDB(), value)
+			if err != nil && !sdk.ErrorIs(err, sdk.ErrGroupNotFound) {
+				return err
+			}
+			if g != nil {
+				total = 1
+				if startIndex == 1 {
+					if err := group.LoadUserGroup(api.mustDB(), g); err != nil {
+						return sdk.WrapError(err, "cannot load users of group %s", g.Name)
+					}
+					groups = append(groups, *g)
+				}
+			}
+		} else {
+			n, err := group.CountGroups(api.mustDB())
+			if err != nil {
+				return sdk.WrapError(err, "cannot count groups")
+			}
+			total = int(n)
+			groups, err = group.LoadGroupsPage(api.mustDB(), startIndex-1, count)
+			if err != nil {
+				return sdk.WrapError(err, "cannot load groups")
+			}
+		}
+
+		resources := make([]interface{}, len(groups))
+		for i, g := range groups {
+			resources[i] = scim.NewGroup(g, api.scimLocation("Groups", g.ID))
+		}
+		return service.WriteJSON(w, scim.ListResponse(resources, total, startIndex), http.StatusOK)
+	}
+}
+
+func (api *API) postSCIMGroupHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		var s sdk.SCIMGroup
+		if err := service.UnmarshalBody(r, &s); err != nil {
+			return err
+		}
+
+		tx, err := api.mustDB().Begin()
+		if err != nil {
+			return sdk.WithStack(err)
+		}
+		defer tx.Rollback() // nolint
+
+		g := &sdk.Group{Name: s.DisplayName}
+		if _, _, err := group.AddGroup(tx, g); err != nil {
+			if sdk.ErrorIs(err, sdk.ErrGroupExists) {
+				return sdk.NewErrorFrom(sdk.ErrConflict, "group %s already exists", s.DisplayName)
+			}
+			return err
+		}
+		if err := saveSCIMGroup(tx, g, s); err != nil {
+			return err
+		}
+
+		if err := tx.Commit(); err != nil {
+			return sdk.WithStack(err)
+		}
+		return service.WriteJSON(w, scim.NewGroup(*g, api.scimLocation("Groups", g.ID)), http.StatusCreated)
+	}
+}
+
+func (api *API) getSCIMGroupHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		id, err := scimID(r)
+		if err != nil {
+			return err
+		}
+		g, err := api.loadSCIMGroup(api.mustDB(), id)
+		if err != nil {
+			return err
+		}
+		return service.WriteJSON(w, scim.NewGroup(*g, api.scimLocation("Groups", g.ID)), http.StatusOK)
+	}
+}
+
+func (api *API) putSCIMGroupHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		id, err := scimID(r)
+		if err != nil {
+			return err
+		}
+		var s sdk.SCIMGroup
+		if err := service.UnmarshalBody(r, &s); err != nil {
+			return err
+		}
+
+		tx, err := api.mustDB().Begin()
+		if err != nil {
+			return sdk.WithStack(err)
+		}
+		defer tx.Rollback() // nolint
+
+		g, err := api.loadSCIMGroup(tx, id)
+		if err != nil {
+			return err
+		}
+		if err := saveSCIMGroup(tx, g, s); err != nil {
+			return err
+		}
+
+		if err := tx.Commit(); err != nil {
+			return sdk.WithStack(err)
+		}
+		return service.WriteJSON(w, scim.NewGroup(*g, api.scimLocation("Groups", g.ID)), http.StatusOK)
+	}
+}
+
+func (api *API) patchSCIMGroupHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		id, err := scimID(r)
+		if err != nil {
+			return err
+		}
+		var req sdk.SCIMPatchRequest
+		if err := service.UnmarshalBody(r, &req); err != nil {
+			return err
+		}
+
+		tx, err := api.mustDB().Begin()
+		if err != nil {
+			return sdk.WithStack(err)
+		}
+		defer tx.Rollback() // nolint
+
+		g, err := api.loadSCIMGroup(tx, id)
+		if err != nil {
+			return err
+		}
+		s := scim.NewGroup(*g, "")
+		if err := scim.PatchGroup(&s, req.Operations); err != nil {
+			return err
+		}
+		if err := saveSCIMGroup(tx, g, s); err != nil {
+			return err
+		}
+
+		if err := tx.Commit(); err != nil {
+			return sdk.WithStack(err)
+		}
+		return service.WriteJSON(w, scim.NewGroup(*g, api.scimLocation("Groups", g.ID)), http.StatusOK)
+	}
+}
+
+func (api *API) deleteSCIMGroupHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		id, err := scimID(r)
+		if err != nil {
+			return err
+		}
+
+		g, err := group.LoadGroupByID(api.mustDB(), id)
+		if err != nil {
+			return err
+		}
+		if g.Name == sdk.SharedInfraGroupName || group.IsDefaultGroupName(g.Name) {
+			return sdk.NewErrorFrom(sdk.ErrForbidden, "group %s cannot be deleted", g.Name)
+		}
+
+		projPerms, err := project.LoadPermissions(api.mustDB(), g.ID)
+		if err != nil {
+			return sdk.WrapError(err, "cannot load projects of group %s", g.Name)
+		}
+
+		tx, err := api.mustDB().Begin()
+		if err != nil {
+			return sdk.WithStack(err)
+		}
+		defer tx.Rollback() // nolint
+
+		if err := group.DeleteGroupAndDependencies(tx, g); err != nil {
+			return sdk.WrapError(err, "cannot delete group %s", g.Name)
+		}
+
+		if err := tx.Commit(); err != nil {
+			return sdk.WithStack(err)
+		}
+
+		groupPerm := sdk.GroupPermission{Group: *g}
+		for _, pg := range projPerms {
+			event.PublishDeleteProjectPermission(&pg.Project, groupPerm, deprecatedGetUser(ctx))
+		}
+
+		return service.Write(w, nil, http.StatusNoContent, "application/json")
+	}
+}
@@ -0,0 +1,332 @@
+package scim
+
+import (
+	"encoding/json"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/accesstoken"
+	"github.com/ovh/cds/engine/api/user"
+	"github.com/ovh/cds/sdk"
+)
+
+var filterRegex = regexp.MustCompile(`^\s*([a-zA-Z.]+)\s+(?i:eq)\s+"((?:[^"\\]|\\.)*)"\s*$`)
+
+// ParseFilter parses a filter of the form `attribute eq "value"`, the only filters used by the identity providers to
+// look for an existing resource
+func ParseFilter(filter string) (string, string, error) {
+	m := filterRegex.FindStringSubmatch(filter)
+	if m == nil {
+		return "", "", sdk.NewErrorFrom(sdk.ErrWrongRequest, "unsupported filter %s", filter)
+	}
+	value, err := strconv.Unquote(`"` + m[2] + `"`)
+	if err != nil {
+		return "", "", sdk.NewErrorFrom(sdk.ErrWrongRequest, "invalid filter value %s", m[2])
+	}
+	return m[1], value, nil
+}
+
+// ListResponse returns the page of resources starting at the 1-based startIndex, total is the number of resources
+// of all the pages
+func ListResponse(resources []interface{}, total, startIndex int) sdk.SCIMListResponse {
+	return sdk.SCIMListResponse{
+		Schemas:      []string{sdk.SCIMSchemaListResponse},
+		TotalResults: total,
+		StartIndex:   startIndex,
+		ItemsPerPage: len(resources),
+		Resources:    resources,
+	}
+}
+
+// NewUser returns the SCIM resource of a user
+func NewUser(u sdk.User, groups []sdk.Group, location string) sdk.SCIMUser {
+	active := !u.Deactivated
+	s := sdk.SCIMUser{
+		Schemas:     []string{sdk.SCIMSchemaUser},
+		ID:          strconv.FormatInt(u.ID, 10),
+		UserName:    u.Username,
+		DisplayName: u.Fullname,
+		Name:        &sdk.SCIMName{Formatted: u.Fullname},
+		Active:      &active,
+		Meta:        &sdk.SCIMMeta{ResourceType: "User", Location: location},
+	}
+	if u.Email != "" {
+		s.Emails = []sdk.SCIMEmail{{Value: u.Email, Type: "work", Primary: true}}
+	}
+	for _, g := range groups {
+		s.Groups = append(s.Groups, sdk.SCIMMember{Value: strconv.FormatInt(g.ID, 10), Display: g.Name})
+	}
+	return s
+}
+
+// Fullname returns the full name of a SCIM user
+func Fullname(s sdk.SCIMUser) string {
+	if s.DisplayName != "" {
+		return s.DisplayName
+	}
+	if s.Name != nil {
+		if s.Name.Formatted != "" {
+			return s.Name.Formatted
+		}
+		if n := strings.TrimSpace(s.Name.GivenName + " " + s.Name.FamilyName); n != "" {
+			return n
+		}
+	}
+	return s.UserName
+}
+
+// Email returns the primary email of a SCIM user, or its first email
+func Email(s sdk.SCIMUser) string {
+	for _, e := range s.Emails {
+		if e.Primary {
+			return e.Value
+		}
+	}
+	if len(s.Emails) > 0 {
+		return s.Emails[0].Value
+	}
+	return ""
+}
+
+// NewGroup returns the SCIM resource of a group, with all its users and admins as members
+func NewGroup(g sdk.Group, location string) sdk.SCIMGroup {
+	s := sdk.SCIMGroup{
+		Schemas:     []string{sdk.SCIMSchemaGroup},
+		ID:          strconv.FormatInt(g.ID, 10),
+		DisplayName: g.Name,
+		Meta:        &sdk.SCIMMeta{ResourceType: "Group", Location: location},
+	}
+	for _, u := range append(g.Admins, g.Users...) {
+		s.Members = append(s.Members, sdk.SCIMMember{Value: strconv.FormatInt(u.ID, 10), Display: u.Username})
+	}
+	return s
+}
+
+// MemberIDs returns the ids of the users members of a SCIM group
+func MemberIDs(s sdk.SCIMGroup) ([]int64, error) {
+	ids := make([]int64, 0, len(s.Members))
+	for _, m := range s.Members {
+		id, err := strconv.ParseInt(m.Value, 10, 64)
+		if err != nil {
+			return nil, sdk.NewErrorFrom(sdk.ErrWrongRequest, "invalid member %s", m.Value)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// DeactivateUser deprovisions a user: the user is deactivated, and all its persistent sessions and access tokens are
+// revoked. The user, its groups and its history are kept.
+func DeactivateUser(db gorp.SqlExecutor, userID int64) error {
+	if err := user.SetDeactivated(db, userID, true); err != nil {
+		return err
+	}
+	if err := user.DeletePersistentSessionTokensByUser(db, userID); err != nil {
+		return err
+	}
+	tokens, err := accesstoken.FindAllByUser(db, userID)
+	if err != nil {
+		return sdk.WrapError(err, "cannot load access tokens of user %d", userID)
+	}
+	for i := range tokens {
+		if err := accesstoken.Delete(db, &tokens[i]); err != nil {
+			return sdk.WrapError(err, "cannot revoke access token %s", tokens[i].ID)
+		}
+	}
+	return nil
+}
+
+func unmarshalString(value json.RawMessage) (string, error) {
+	var s string
+	if err := json.Unmarshal(value, &s); err != nil {
+		return "", sdk.NewErrorFrom(sdk.ErrWrongRequest, "invalid value %s", string(value))
+	}
+	return s, nil
+}
+
+// unmarshalBool reads a boolean, given as a string by some identity providers
+func unmarshalBool(value json.RawMessage) (bool, error) {
+	var b bool
+	if err := json.Unmarshal(value, &b); err == nil {
+		return b, nil
+	}
+	s, err := unmarshalString(value)
+	if err != nil {
+		return false, err
+	}
+	b, err = strconv.ParseBool(strings.ToLower(s))
+	if err != nil {
+		return false, sdk.NewErrorFrom(sdk.ErrWrongRequest, "invalid boolean %s", s)
+	}
+	return b, nil
+}
+
+// operations returns the operation name and the attributes to change of each operation, the operations without path
+// give the attributes in their value
+func operations(ops []sdk.SCIMPatchOperation, f func(op, path string, value json.RawMessage) error) error {
+	for _, o := range ops {
+		op := strings.ToLower(o.Op)
+		switch op {
+		case "add", "replace", "remove":
+		default:
+			return sdk.NewErrorFrom(sdk.ErrWrongRequest, "invalid patch operation %s", o.Op)
+		}
+		if o.Path != "" {
+			if err := f(op, o.Path, o.Value); err != nil {
+				return err
+			}
+			continue
+		}
+		var attributes map[string]json.RawMessage
+		if err := json.Unmarshal(o.Value, &attributes); err != nil {
+			return sdk.NewErrorFrom(sdk.ErrWrongRequest, "invalid value of patch operation %s without path", o.Op)
+		}
+		for k, v := range attributes {
+			if err := f(op, k, v); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// PatchUser applies the operations of a PATCH request to a SCIM user. The attributes unknown in CDS are ignored.
+func PatchUser(s *sdk.SCIMUser, ops []sdk.SCIMPatchOperation) error {
+	return operations(ops, func(op, path string, value json.RawMessage) error {
+		p := strings.ToLower(path)
+		if op == "remove" {
+			switch {
+			case p == "displayname":
+				s.DisplayName = ""
+			case p == "name" || strings.HasPrefix(p, "name."):
+				s.Name = nil
+			case p == "emails" || strings.HasPrefix(p, "emails["):
+				s.Emails = nil
+			}
+			return nil
+		}
+
+		var err error
+		switch {
+		case p == "active":
+			var b bool
+			b, err = unmarshalBool(value)
+			s.Active = &b
+		case p == "username":
+			s.UserName, err = unmarshalString(value)
+		case p == "displayname":
+			s.DisplayName, err = unmarshalString(value)
+		case p == "name":
+			s.Name = &sdk.SCIMName{}
+			if json.Unmarshal(value, s.Name) != nil {
+				err = sdk.NewErrorFrom(sdk.ErrWrongRequest, "invalid name %s", string(value))
+			}
+		case strings.HasPrefix(p, "name."):
+			if s.Name == nil {
+				s.Name = &sdk.SCIMName{}
+			}
+			switch p {
+			case "name.formatted":
+				s.Name.Formatted, err = unmarshalString(value)
+			case "name.givenname":
+				s.Name.GivenName, err = unmarshalString(value)
+			case "name.familyname":
+				s.Name.FamilyName, err = unmarshalString(value)
+			}
+		case p == "emails":
+			s.Emails = nil
+			if json.Unmarshal(value, &s.Emails) != nil {
+				err = sdk.NewErrorFrom(sdk.ErrWrongRequest, "invalid emails %s", string(value))
+			}
+		case strings.HasPrefix(p, "emails[") && strings.HasSuffix(p, "].value"):
+			var email string
+			email, err = unmarshalString(value)
+			s.Emails = []sdk.SCIMEmail{{Value: email, Type: "work", Primary: true}}
+		}
+		return err
+	})
+}
+
+// PatchGroup applies the operations of a PATCH request to a SCIM group
+func PatchGroup(s *sdk.SCIMGroup, ops []sdk.SCIMPatchOperation) error {
+	return operations(ops, func(op, path string, value json.RawMessage) error {
+		p := strings.ToLower(path)
+		switch {
+		case p == "displayname":
+			if op == "remove" {
+				return sdk.NewErrorFrom(sdk.ErrWrongRequest, "displayName cannot be removed")
+			}
+			name, err := unmarshalString(value)
+			if err != nil {
+				return err
+			}
+			s.DisplayName = name
+		case p == "members":
+			var members []sdk.SCIMMember
+			if len(value) > 0 && json.Unmarshal(value, &members) != nil {
+				return sdk.NewErrorFrom(sdk.ErrWrongRequest, "invalid members %s", string(value))
+			}
+			switch op {
+			case "add":
+				s.Members = addMembers(s.Members, members)
+			case "replace":
+				s.Members = addMembers(nil, members)
+			case "remove":
+				if len(members) == 0 {
+					s.Members = nil
+				} else {
+					s.Members = removeMembers(s.Members, members)
+				}
+			}
+		case strings.HasPrefix(p, "members[") && strings.HasSuffix(p, "]"):
+			if op != "remove" {
+				return sdk.NewErrorFrom(sdk.ErrWrongRequest, "unsupported operation %s on %s", op, path)
+			}
+			attr, v, err := ParseFilter(path[len("members[") : len(path)-1])
+			if err != nil {
+				return err
+			}
+			if !strings.EqualFold(attr, "value") {
+				return sdk.NewErrorFrom(sdk.ErrWrongRequest, "unsupported path %s", path)
+			}
+			s.Members = removeMembers(s.Members, []sdk.SCIMMember{{Value: v}})
+		}
+		return nil
+	})
+}
+
+func addMembers(members, added []sdk.SCIMMember) []sdk.SCIMMember {
+	for _, a := range added {
+		var found bool
+		for _, m := range members {
+			if m.Value == a.Value {
+				found = true
+				break
+			}
+		}
+		if !found {
+			members = append(members, a)
+		}
+	}
+	return members
+}
+
+func removeMembers(members, removed []sdk.SCIMMember) []sdk.SCIMMember {
+	res := make([]sdk.SCIMMember, 0, len(members))
+	for _, m := range members {
+		var found bool
+		for _, r := range removed {
+			if m.Value == r.Value {
+				found = true
+				break
+			}
+		}
+		if !found {
+			res = append(res, m)
+		}
+	}
+	return res
+}
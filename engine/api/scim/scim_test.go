@@ -0,0 +1,75 @@
+package scim
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func TestParseFilter(t *testing.T) {
+	attr, value, err := ParseFilter(`userName eq "john.doe@example.com"`)
+	assert.NoError(t, err)
+	assert.Equal(t, "userName", attr)
+	assert.Equal(t, "john.doe@example.com", value)
+
+	attr, value, err = ParseFilter(`displayName Eq "my \"group\""`)
+	assert.NoError(t, err)
+	assert.Equal(t, "displayName", attr)
+	assert.Equal(t, `my "group"`, value)
+
+	_, _, err = ParseFilter(`userName sw "john"`)
+	assert.Error(t, err)
+}
+
+func TestListResponse(t *testing.T) {
+	res := ListResponse([]interface{}{"b"}, 3, 2)
+	assert.Equal(t, 3, res.TotalResults)
+	assert.Equal(t, 2, res.StartIndex)
+	assert.Equal(t, 1, res.ItemsPerPage)
+	assert.Equal(t, []interface{}{"b"}, res.Resources)
+
+	res = ListResponse([]interface{}{}, 3, 5)
+	assert.Equal(t, 0, res.ItemsPerPage)
+	assert.Len(t, res.Resources, 0)
+}
+
+func TestPatchUser(t *testing.T) {
+	s := NewUser(sdk.User{ID: 1, Username: "jdoe", Fullname: "John Doe", Email: "jdoe@example.com"}, nil, "")
+	assert.True(t, *s.Active)
+
+	var ops []sdk.SCIMPatchOperation
+	assert.NoError(t, json.Unmarshal([]byte(`[
+		{"op": "Replace", "path": "active", "value": "False"},
+		{"op": "replace", "path": "emails[type eq \"work\"].value", "value": "john.doe@example.com"},
+		{"op": "replace", "value": {"displayName": "Johnny Doe", "title": "ignored"}}
+	]`), &ops))
+	assert.NoError(t, PatchUser(&s, ops))
+	assert.False(t, *s.Active)
+	assert.Equal(t, "john.doe@example.com", Email(s))
+	assert.Equal(t, "Johnny Doe", Fullname(s))
+
+	assert.Error(t, PatchUser(&s, []sdk.SCIMPatchOperation{{Op: "move", Path: "active"}}))
+}
+
+func TestPatchGroup(t *testing.T) {
+	s := NewGroup(sdk.Group{ID: 1, Name: "my-group", Admins: []sdk.User{{ID: 1}}, Users: []sdk.User{{ID: 2}}}, "")
+
+	var ops []sdk.SCIMPatchOperation
+	assert.NoError(t, json.Unmarshal([]byte(`[
+		{"op": "add", "path": "members", "value": [{"value": "3"}, {"value": "2"}]},
+		{"op": "remove", "path": "members[value eq \"2\"]"},
+		{"op": "replace", "path": "displayName", "value": "my-new-group"}
+	]`), &ops))
+	assert.NoError(t, PatchGroup(&s, ops))
+	assert.Equal(t, "my-new-group", s.DisplayName)
+
+	ids, err := MemberIDs(s)
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{1, 3}, ids)
+
+	assert.NoError(t, PatchGroup(&s, []sdk.SCIMPatchOperation{{Op: "remove", Path: "members"}}))
+	assert.Len(t, s.Members, 0)
+}
@@ -0,0 +1,137 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/engine/api/bootstrap"
+	"github.com/ovh/cds/engine/api/group"
+	"github.com/ovh/cds/engine/api/user"
+	"github.com/ovh/cds/sdk"
+)
+
+func newSCIMRequest(t *testing.T, token, method, uri string, i interface{}) *http.Request {
+	var btes []byte
+	if i != nil {
+		var err error
+		btes, err = json.Marshal(i)
+		assert.NoError(t, err)
+	}
+	req, err := http.NewRequest(method, uri, bytes.NewBuffer(btes))
+	assert.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/scim+json")
+	return req
+}
+
+func TestSCIMUsersAndGroups(t *testing.T) {
+	api, db, router, end := newTestAPI(t, bootstrap.InitiliazeDB)
+	defer end()
+	api.Config.Auth.SCIM.Enable = true
+	api.Config.Auth.SCIM.Token = sdk.RandomString(20)
+	token := api.Config.Auth.SCIM.Token
+
+	// Wrong token
+	uri := router.GetRoute("GET", api.getSCIMUsersHandler, nil)
+	w := httptest.NewRecorder()
+	router.Mux.ServeHTTP(w, newSCIMRequest(t, "wrong", "GET", uri, nil))
+	assert.Equal(t, 401, w.Code)
+
+	// Provision a user
+	username := sdk.RandomString(10)
+	uri = router.GetRoute("POST", api.postSCIMUserHandler, nil)
+	w = httptest.NewRecorder()
+	router.Mux.ServeHTTP(w, newSCIMRequest(t, token, "POST", uri, sdk.SCIMUser{
+		Schemas:  []string{sdk.SCIMSchemaUser},
+		UserName: username,
+		Name:     &sdk.SCIMName{GivenName: "John", FamilyName: "Doe"},
+		Emails:   []sdk.SCIMEmail{{Value: username + "@example.com", Primary: true}},
+	}))
+	assert.Equal(t, 201, w.Code)
+	var su sdk.SCIMUser
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &su))
+	assert.Equal(t, username, su.UserName)
+	assert.True(t, *su.Active)
+
+	// The identity provider looks for the user
+	uri = router.GetRoute("GET", api.getSCIMUsersHandler, nil)
+	w = httptest.NewRecorder()
+	router.Mux.ServeHTTP(w, newSCIMRequest(t, token, "GET", uri+`?filter=userName+eq+"`+username+`"`, nil))
+	assert.Equal(t, 200, w.Code)
+	var list sdk.SCIMListResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &list))
+	assert.Equal(t, 1, list.TotalResults)
+
+	// The identity provider lists the users page by page
+	w = httptest.NewRecorder()
+	router.Mux.ServeHTTP(w, newSCIMRequest(t, token, "GET", uri+"?startIndex=1&count=1", nil))
+	assert.Equal(t, 200, w.Code)
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &list))
+	assert.Equal(t, 1, list.StartIndex)
+	assert.Equal(t, 1, list.ItemsPerPage)
+	assert.Len(t, list.Resources, 1)
+
+	// Provision a group with the user
+	uri = router.GetRoute("POST", api.postSCIMGroupHandler, nil)
+	w = httptest.NewRecorder()
+	router.Mux.ServeHTTP(w, newSCIMRequest(t, token, "POST", uri, sdk.SCIMGroup{
+		Schemas:     []string{sdk.SCIMSchemaGroup},
+		DisplayName: sdk.RandomString(10),
+		Members:     []sdk.SCIMMember{{Value: su.ID}},
+	}))
+	assert.Equal(t, 201, w.Code)
+	var sg sdk.SCIMGroup
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &sg))
+	assert.Len(t, sg.Members, 1)
+
+	groupID, _ := strconv.ParseInt(sg.ID, 10, 64)
+	g, err := group.LoadGroupByID(db, groupID)
+	assert.NoError(t, err)
+	assert.NoError(t, group.LoadUserGroup(db, g))
+	assert.Len(t, g.Users, 1)
+
+	// Deprovision the user
+	uri = router.GetRoute("DELETE", api.deleteSCIMUserHandler, map[string]string{"id": su.ID})
+	w = httptest.NewRecorder()
+	router.Mux.ServeHTTP(w, newSCIMRequest(t, token, "DELETE", uri, nil))
+	assert.Equal(t, 204, w.Code)
+
+	u, err := user.LoadUserWithoutAuth(db, username)
+	assert.NoError(t, err)
+	assert.True(t, u.Deactivated)
+
+	// Reactivate the user
+	uri = router.GetRoute("PATCH", api.patchSCIMUserHandler, map[string]string{"id": su.ID})
+	w = httptest.NewRecorder()
+	router.Mux.ServeHTTP(w, newSCIMRequest(t, token, "PATCH", uri, sdk.SCIMPatchRequest{
+		Schemas:    []string{sdk.SCIMSchemaPatchOp},
+		Operations: []sdk.SCIMPatchOperation{{Op: "replace", Path: "active", Value: json.RawMessage(`true`)}},
+	}))
+	assert.Equal(t, 200, w.Code)
+
+	u, err = user.LoadUserWithoutAuth(db, username)
+	assert.NoError(t, err)
+	assert.False(t, u.Deactivated)
+
+	// Remove the user from the group, then delete the group
+	uri = router.GetRoute("PATCH", api.patchSCIMGroupHandler, map[string]string{"id": sg.ID})
+	w = httptest.NewRecorder()
+	router.Mux.ServeHTTP(w, newSCIMRequest(t, token, "PATCH", uri, sdk.SCIMPatchRequest{
+		Schemas:    []string{sdk.SCIMSchemaPatchOp},
+		Operations: []sdk.SCIMPatchOperation{{Op: "remove", Path: `members[value eq "` + su.ID + `"]`}},
+	}))
+	assert.Equal(t, 200, w.Code)
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &sg))
+	assert.Len(t, sg.Members, 0)
+
+	uri = router.GetRoute("DELETE", api.deleteSCIMGroupHandler, map[string]string{"id": sg.ID})
+	w = httptest.NewRecorder()
+	router.Mux.ServeHTTP(w, newSCIMRequest(t, token, "DELETE", uri, nil))
+	assert.Equal(t, 204, w.Code)
+}
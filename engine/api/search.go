@@ -0,0 +1,42 @@
+package api
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/ovh/cds/engine/api/search"
+	"github.com/ovh/cds/engine/service"
+	"github.com/ovh/cds/sdk"
+)
+
+// getSearchHandler searches projects, workflows, applications and runs readable by the user.
+// The query accepts free text and the filters kind, project, repo, branch and status, example: "kind:workflow repo:ovh/cds".
+func (api *API) getSearchHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		q, err := sdk.ParseSearchQuery(FormString(r, "q"))
+		if err != nil {
+			return err
+		}
+
+		offset, err := FormInt(r, "offset")
+		if err != nil {
+			return err
+		}
+		if offset < 0 {
+			offset = 0
+		}
+		limit, err := FormInt(r, "limit")
+		if err != nil {
+			return err
+		}
+		if limit <= 0 || limit > 100 {
+			limit = 20
+		}
+
+		res, err := search.Search(ctx, api.mustDBForRead(ctx), deprecatedGetUser(ctx), q, offset, limit)
+		if err != nil {
+			return err
+		}
+		return service.WriteJSON(w, res, http.StatusOK)
+	}
+}
@@ -0,0 +1,204 @@
+package search
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+	"strings"
+
+	"github.com/go-gorp/gorp"
+	"github.com/lib/pq"
+
+	"github.com/ovh/cds/engine/api/services"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// Search returns a page of the projects, workflows, applications and runs matching a query, among the projects readable by the user.
+// Runs are searched in the elasticsearch service if there is one, in the database otherwise.
+func Search(ctx context.Context, db gorp.SqlExecutor, u *sdk.User, q sdk.SearchQuery, offset, limit int) (sdk.SearchResults, error) {
+	var projectKeys []string
+	if u != nil && !u.Admin {
+		projectKeys = make([]string, 0, len(u.Permissions.ProjectsPerm))
+		for k := range u.Permissions.ProjectsPerm {
+			projectKeys = append(projectKeys, k)
+		}
+	}
+
+	// Each kind of results is loaded up to the end of the page, plus one to know if there is a next page
+	size := offset + limit + 1
+	var all []sdk.SearchResult
+	for _, kind := range sdk.SearchKinds {
+		if !q.HasKind(kind) || len(all) >= size {
+			continue
+		}
+		var res []sdk.SearchResult
+		var err error
+		if kind == sdk.SearchKindRun {
+			res, err = searchRuns(ctx, db, q, projectKeys, size-len(all))
+		} else {
+			res, err = searchEntities(db, kind, q, projectKeys, size-len(all))
+		}
+		if err != nil {
+			return sdk.SearchResults{}, err
+		}
+		all = append(all, res...)
+	}
+
+	page := sdk.SearchResults{Results: []sdk.SearchResult{}, Offset: offset, Limit: limit}
+	if offset < len(all) {
+		end := offset + limit
+		if end < len(all) {
+			page.HasMore = true
+		} else {
+			end = len(all)
+		}
+		page.Results = all[offset:end]
+	}
+	return page, nil
+}
+
+// likePattern returns a case insensitive pattern matching the strings containing s
+func likePattern(s string) string {
+	if s == "" {
+		return ""
+	}
+	r := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+	return "%" + r.Replace(s) + "%"
+}
+
+// projectKeysArray returns the keys of the readable projects as a query parameter, nil matches all the projects
+func projectKeysArray(keys []string) interface{} {
+	if keys == nil {
+		return nil
+	}
+	return pq.Array(keys)
+}
+
+func searchEntities(db gorp.SqlExecutor, kind string, q sdk.SearchQuery, projectKeys []string, size int) ([]sdk.SearchResult, error) {
+	var query string
+	switch kind {
+	case sdk.SearchKindProject:
+		query = `SELECT project.projectkey, project.name, coalesce(project.description, ''), '', project.last_modified
+		FROM project
+		WHERE ($1::text[] IS NULL OR project.projectkey = ANY($1))
+		AND ($2 = '' OR project.projectkey ILIKE $2 OR project.name ILIKE $2)
+		AND ($3 = '' OR project.projectkey = $3)
+		AND $4 = ''
+		ORDER BY project.name
+		LIMIT $5`
+	case sdk.SearchKindApplication:
+		query = `SELECT project.projectkey, application.name, coalesce(application.description, ''), coalesce(application.repo_fullname, ''), application.last_modified
+		FROM application
+		JOIN project ON project.id = application.project_id
+		WHERE ($1::text[] IS NULL OR project.projectkey = ANY($1))
+		AND ($2 = '' OR application.name ILIKE $2)
+		AND ($3 = '' OR project.projectkey = $3)
+		AND ($4 = '' OR application.repo_fullname ILIKE $4)
+		ORDER BY project.projectkey, application.name
+		LIMIT $5`
+	case sdk.SearchKindWorkflow:
+		query = `SELECT project.projectkey, workflow.name, coalesce(workflow.description, ''), '', workflow.last_modified
+		FROM workflow
+		JOIN project ON project.id = workflow.project_id
+		WHERE ($1::text[] IS NULL OR project.projectkey = ANY($1))
+		AND ($2 = '' OR workflow.name ILIKE $2)
+		AND ($3 = '' OR project.projectkey = $3)
+		AND ($4 = '' OR EXISTS (
+			SELECT 1 FROM w_node
+			JOIN w_node_context ON w_node_context.node_id = w_node.id
+			JOIN application ON application.id = w_node_context.application_id
+			WHERE w_node.workflow_id = workflow.id AND application.repo_fullname ILIKE $4
+		))
+		ORDER BY project.projectkey, workflow.name
+		LIMIT $5`
+	}
+
+	rows, err := db.Query(query, projectKeysArray(projectKeys), likePattern(q.Text), q.Project, likePattern(q.Repository), size)
+	if err != nil {
+		return nil, sdk.WrapError(err, "unable to search %ss", kind)
+	}
+	defer rows.Close()
+
+	var res []sdk.SearchResult
+	for rows.Next() {
+		r := sdk.SearchResult{Kind: kind}
+		var lastModified pq.NullTime
+		if err := rows.Scan(&r.ProjectKey, &r.Name, &r.Description, &r.Repository, &lastModified); err != nil {
+			return nil, sdk.WrapError(err, "unable to scan %s", kind)
+		}
+		r.LastModified = lastModified.Time
+		if kind == sdk.SearchKindWorkflow {
+			r.WorkflowName = r.Name
+		}
+		res = append(res, r)
+	}
+	return res, nil
+}
+
+func searchRuns(ctx context.Context, db gorp.SqlExecutor, q sdk.SearchQuery, projectKeys []string, size int) ([]sdk.SearchResult, error) {
+	srvs, err := services.FindByType(db, services.TypeElasticsearch)
+	if err != nil {
+		return nil, sdk.WrapError(err, "unable to get elasticsearch service")
+	}
+	if len(srvs) > 0 {
+		var res []sdk.SearchResult
+		req := sdk.SearchRunsRequest{Query: q, ProjectKeys: projectKeys, Size: size}
+		if _, err := services.DoJSONRequest(ctx, srvs, "POST", "/search/runs", req, &res); err == nil {
+			return res, nil
+		}
+		log.Warning("search.searchRuns> unable to search runs in elasticsearch, falling back on database: %v", err)
+	}
+	return searchRunsInDB(db, q, projectKeys, size)
+}
+
+func searchRunsInDB(db gorp.SqlExecutor, q sdk.SearchQuery, projectKeys []string, size int) ([]sdk.SearchResult, error) {
+	// A number in the text matches the number of the runs
+	var number int64
+	if n, err := strconv.ParseInt(q.Text, 10, 64); err == nil {
+		number = n
+	}
+	query := `SELECT project.projectkey, workflow.name, workflow_run.num, workflow_run.status, workflow_run.last_modified,
+		coalesce((SELECT value FROM workflow_run_tag WHERE workflow_run_id = workflow_run.id AND tag = 'git.repository'), ''),
+		coalesce((SELECT value FROM workflow_run_tag WHERE workflow_run_id = workflow_run.id AND tag = 'git.branch'), '')
+	FROM workflow_run
+	JOIN workflow ON workflow.id = workflow_run.workflow_id
+	JOIN project ON project.id = workflow_run.project_id
+	WHERE ($1::text[] IS NULL OR project.projectkey = ANY($1))
+	AND ($2 = '' OR workflow.name ILIKE $2 OR workflow_run.num = $3 OR EXISTS (
+		SELECT 1 FROM workflow_run_tag
+		WHERE workflow_run_tag.workflow_run_id = workflow_run.id AND workflow_run_tag.tag IN ('git.branch', 'git.hash', 'git.tag')
+		AND workflow_run_tag.value ILIKE $2
+	))
+	AND ($4 = '' OR project.projectkey = $4)
+	AND ($5 = '' OR EXISTS (
+		SELECT 1 FROM workflow_run_tag
+		WHERE workflow_run_tag.workflow_run_id = workflow_run.id AND workflow_run_tag.tag = 'git.repository' AND workflow_run_tag.value ILIKE $5
+	))
+	AND ($6 = '' OR EXISTS (
+		SELECT 1 FROM workflow_run_tag
+		WHERE workflow_run_tag.workflow_run_id = workflow_run.id AND workflow_run_tag.tag = 'git.branch' AND workflow_run_tag.value = $6
+	))
+	AND ($7 = '' OR lower(workflow_run.status) = lower($7))
+	ORDER BY workflow_run.start DESC
+	LIMIT $8`
+	rows, err := db.Query(query, projectKeysArray(projectKeys), likePattern(q.Text), number, q.Project, likePattern(q.Repository), q.Branch, q.Status, size)
+	if err != nil {
+		return nil, sdk.WrapError(err, "unable to search runs")
+	}
+	defer rows.Close()
+
+	var res []sdk.SearchResult
+	for rows.Next() {
+		r := sdk.SearchResult{Kind: sdk.SearchKindRun}
+		var lastModified pq.NullTime
+		var status sql.NullString
+		if err := rows.Scan(&r.ProjectKey, &r.WorkflowName, &r.RunNumber, &status, &lastModified, &r.Repository, &r.Branch); err != nil {
+			return nil, sdk.WrapError(err, "unable to scan run")
+		}
+		r.Name = r.WorkflowName + "#" + strconv.FormatInt(r.RunNumber, 10)
+		r.Status, r.LastModified = status.String, lastModified.Time
+		res = append(res, r)
+	}
+	return res, nil
+}
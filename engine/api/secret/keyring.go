@@ -0,0 +1,285 @@
+package secret
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// prefixV2 identifies data encrypted with a data key of a project: prefixV2<key id>:<nonce><data><hmac>
+const (
+	prefixV2    = "3DICCv2k"
+	dataKeySize = ckeySize + macSize
+)
+
+// keyring keeps the unwrapped data keys of the projects in memory
+type keyring struct {
+	kms   KMS
+	kmss  map[string]KMS
+	db    func() gorp.SqlExecutor
+	mutex sync.RWMutex
+	keys  map[int64]dataKey
+}
+
+// dataKey is an unwrapped data key and the project that owns it
+type dataKey struct {
+	projectID int64
+	key       []byte
+}
+
+var ring *keyring
+
+// InitKMS enables the envelope encryption of the secrets of the projects: each project has its own data key,
+// wrapped by the given KMS. The local KMS is always available to unwrap data keys created before a KMS change.
+// Init() must be called before InitKMS()
+func InitKMS(k KMS, db func() gorp.SqlExecutor) {
+	r := &keyring{
+		kms:  k,
+		kmss: map[string]KMS{KMSLocal: localKMS{}},
+		db:   db,
+		keys: map[int64]dataKey{},
+	}
+	r.kmss[k.Name()] = k
+	ring = r
+}
+
+// activeKey returns the active data key of the project, it is created if the project does not have one
+func (r *keyring) activeKey(db gorp.SqlExecutor, projectID int64) (int64, []byte, error) {
+	var id int64
+	var kmsName string
+	var wrapped []byte
+	query := "SELECT id, kms, wrapped_key FROM project_encryption_key WHERE project_id = $1 AND active = true ORDER BY id DESC LIMIT 1"
+	if err := db.QueryRow(query, projectID).Scan(&id, &kmsName, &wrapped); err != nil {
+		if err != sql.ErrNoRows {
+			return 0, nil, sdk.WrapError(err, "unable to load data key of project %d", projectID)
+		}
+		return r.newKey(db, projectID)
+	}
+
+	r.mutex.RLock()
+	dk, has := r.keys[id]
+	r.mutex.RUnlock()
+	if has {
+		return id, dk.key, nil
+	}
+
+	k, err := r.unwrap(kmsName, wrapped)
+	if err != nil {
+		return 0, nil, err
+	}
+	r.cache(id, projectID, k)
+	return id, k, nil
+}
+
+// newKey generates a data key for the project and makes it the only active one
+func (r *keyring) newKey(db gorp.SqlExecutor, projectID int64) (int64, []byte, error) {
+	k := make([]byte, dataKeySize)
+	if _, err := io.ReadFull(rand.Reader, k); err != nil {
+		return 0, nil, sdk.WithStack(err)
+	}
+	wrapped, err := r.kms.Wrap(k)
+	if err != nil {
+		return 0, nil, sdk.WrapError(err, "unable to wrap data key with kms %s", r.kms.Name())
+	}
+
+	if _, err := db.Exec("UPDATE project_encryption_key SET active = false WHERE project_id = $1", projectID); err != nil {
+		return 0, nil, sdk.WrapError(err, "unable to deactivate data keys of project %d", projectID)
+	}
+	var id int64
+	query := "INSERT INTO project_encryption_key (project_id, kms, wrapped_key, active) VALUES ($1, $2, $3, true) RETURNING id"
+	if err := db.QueryRow(query, projectID, r.kms.Name(), wrapped).Scan(&id); err != nil {
+		return 0, nil, sdk.WrapError(err, "unable to insert data key of project %d", projectID)
+	}
+	r.cache(id, projectID, k)
+	return id, k, nil
+}
+
+// key returns the data key with the given id, and the id of the project that owns it
+func (r *keyring) key(id int64) (dataKey, error) {
+	r.mutex.RLock()
+	dk, has := r.keys[id]
+	r.mutex.RUnlock()
+	if has {
+		return dk, nil
+	}
+
+	var projectID int64
+	var kmsName string
+	var wrapped []byte
+	query := "SELECT project_id, kms, wrapped_key FROM project_encryption_key WHERE id = $1"
+	if err := r.db().QueryRow(query, id).Scan(&projectID, &kmsName, &wrapped); err != nil {
+		return dk, sdk.WrapError(err, "unable to load data key %d", id)
+	}
+	k, err := r.unwrap(kmsName, wrapped)
+	if err != nil {
+		return dk, err
+	}
+	r.cache(id, projectID, k)
+	return dataKey{projectID: projectID, key: k}, nil
+}
+
+func (r *keyring) unwrap(kmsName string, wrapped []byte) ([]byte, error) {
+	k, has := r.kmss[kmsName]
+	if !has {
+		return nil, fmt.Errorf("kms %s is not configured", kmsName)
+	}
+	dk, err := k.Unwrap(wrapped)
+	if err != nil {
+		return nil, sdk.WrapError(err, "unable to unwrap data key with kms %s", kmsName)
+	}
+	if len(dk) != dataKeySize {
+		return nil, sdk.ErrSecretKeyFetchFailed
+	}
+	return dk, nil
+}
+
+func (r *keyring) cache(id, projectID int64, k []byte) {
+	r.mutex.Lock()
+	r.keys[id] = dataKey{projectID: projectID, key: k}
+	r.mutex.Unlock()
+}
+
+// EncryptForProject encrypts data with the active data key of the project.
+// Without KMS initialized, data is encrypted with the secrets key of the API.
+func EncryptForProject(db gorp.SqlExecutor, projectID int64, data []byte) ([]byte, error) {
+	if ring == nil || projectID == 0 {
+		return Encrypt(data)
+	}
+	id, k, err := ring.activeKey(db, projectID)
+	if err != nil {
+		return nil, err
+	}
+	ct, err := encryptWithKey(k, data)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(prefixV2+strconv.FormatInt(id, 10)+":"), ct...), nil
+}
+
+// EncryptSForProject is the same as EncryptS, using the data key of the project
+func EncryptSForProject(db gorp.SqlExecutor, projectID int64, ptype string, value string) (sql.NullString, []byte, error) {
+	var n sql.NullString
+
+	if !sdk.NeedPlaceholder(ptype) {
+		n.String = value
+		n.Valid = true
+		return n, nil, nil
+	}
+
+	if value == sdk.PasswordPlaceholder {
+		log.Error("secret.EncryptSForProject> Don't encrypt PasswordPlaceholder !\n")
+		return n, nil, sdk.ErrInvalidSecretValue
+	}
+
+	d, err := EncryptForProject(db, projectID, []byte(value))
+	return n, d, err
+}
+
+// RotateProjectKey creates a new active data key for the project, wrapped by the current KMS.
+// Previous data keys are kept to decrypt the data not yet encrypted with the new key.
+func RotateProjectKey(db gorp.SqlExecutor, projectID int64) error {
+	if ring == nil {
+		return sdk.WithStack(fmt.Errorf("kms is not initialized"))
+	}
+	_, _, err := ring.newKey(db, projectID)
+	return err
+}
+
+// DecryptForProject decrypts data encrypted with EncryptForProject. Data encrypted with a data key
+// is only decrypted if the key belongs to the given project.
+func DecryptForProject(projectID int64, data []byte) ([]byte, error) {
+	if strings.HasPrefix(string(data), prefixV2) {
+		return decryptV2(projectID, data)
+	}
+	return Decrypt(data)
+}
+
+// DecryptSForProject is the same as DecryptS, for data encrypted with EncryptSForProject
+func DecryptSForProject(projectID int64, ptype string, val sql.NullString, data []byte, clear bool) (string, error) {
+	return decryptS(projectID, ptype, val, data, clear)
+}
+
+// DecryptVariableForProject is the same as DecryptVariable, for a variable of the given project
+func DecryptVariableForProject(projectID int64, v *sdk.Variable) error {
+	return decryptVariable(projectID, v)
+}
+
+func decryptV2(projectID int64, data []byte) ([]byte, error) {
+	s := strings.TrimPrefix(string(data), prefixV2)
+	i := strings.Index(s, ":")
+	if i < 0 {
+		return nil, sdk.ErrInvalidSecretFormat
+	}
+	id, err := strconv.ParseInt(s[:i], 10, 64)
+	if err != nil {
+		return nil, sdk.ErrInvalidSecretFormat
+	}
+	if ring == nil {
+		log.Error("Missing kms, init failed?")
+		return nil, sdk.ErrSecretKeyFetchFailed
+	}
+	dk, err := ring.key(id)
+	if err != nil {
+		return nil, err
+	}
+	if dk.projectID != projectID {
+		return nil, sdk.WithStack(fmt.Errorf("data key %d does not belong to project %d", id, projectID))
+	}
+	return decryptWithKey(dk.key, []byte(s[i+1:]))
+}
+
+// encryptWithKey encrypts data with aes-ctr, using the first half of the data key, and authenticates it with
+// a hmac-sha256 using the second half
+func encryptWithKey(k, data []byte) ([]byte, error) {
+	nonce := make([]byte, nonceSize)
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	c, err := aes.NewCipher(k[:ckeySize])
+	if err != nil {
+		return nil, err
+	}
+	ct := make([]byte, len(data))
+	cipher.NewCTR(c, nonce).XORKeyStream(ct, data)
+
+	h := hmac.New(sha256.New, k[ckeySize:])
+	ct = append(nonce, ct...)
+	h.Write(ct) // nolint
+	return h.Sum(ct), nil
+}
+
+func decryptWithKey(k, data []byte) ([]byte, error) {
+	if len(data) < (nonceSize + macSize) {
+		return nil, sdk.ErrInvalidSecretFormat
+	}
+	macStart := len(data) - macSize
+	tag := data[macStart:]
+	data = data[:macStart]
+
+	h := hmac.New(sha256.New, k[ckeySize:])
+	h.Write(data) // nolint
+	if !hmac.Equal(h.Sum(nil), tag) {
+		return nil, fmt.Errorf("invalid hmac")
+	}
+
+	c, err := aes.NewCipher(k[:ckeySize])
+	if err != nil {
+		return nil, err
+	}
+	out := make([]byte, len(data)-nonceSize)
+	cipher.NewCTR(c, data[:nonceSize]).XORKeyStream(out, data[nonceSize:])
+	return out, nil
+}
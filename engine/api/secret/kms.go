@@ -0,0 +1,126 @@
+package secret
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	vault "github.com/hashicorp/vault/api"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// These are the available KMS to wrap the data keys of the projects
+const (
+	KMSLocal        = "local"
+	KMSVaultTransit = "vault-transit"
+	KMSAWS          = "aws-kms"
+	KMSGCP          = "gcp-kms"
+)
+
+// KMS wraps and unwraps the data keys used to encrypt the secrets of the projects.
+// The data keys are stored wrapped in database, only the KMS is able to unwrap them.
+type KMS interface {
+	Name() string
+	Wrap(plain []byte) ([]byte, error)
+	Unwrap(wrapped []byte) ([]byte, error)
+}
+
+// KMSConfiguration is the configuration of the KMS used to wrap the data keys
+type KMSConfiguration struct {
+	Type  string `toml:"type" default:"local" comment:"KMS used to wrap projects data keys: local (use the secrets key), vault-transit, aws-kms or gcp-kms" json:"type"`
+	Vault struct {
+		Addr  string `toml:"addr" json:"addr"`
+		Token string `toml:"token" json:"-"`
+		Mount string `toml:"mount" default:"transit" json:"mount"`
+		Key   string `toml:"key" json:"key"`
+	} `toml:"vault" json:"vault"`
+	AWS struct {
+		Region          string `toml:"region" json:"region"`
+		AccessKeyID     string `toml:"accessKeyID" json:"accessKeyID"`
+		SecretAccessKey string `toml:"secretAccessKey" json:"-"`
+		KeyID           string `toml:"keyID" comment:"ID or ARN of the AWS KMS key" json:"keyID"`
+	} `toml:"aws" json:"aws"`
+	GCP struct {
+		KeyName     string `toml:"keyName" comment:"projects/<project>/locations/<location>/keyRings/<ring>/cryptoKeys/<key>" json:"keyName"`
+		Credentials string `toml:"credentials" comment:"Service account JSON credentials" json:"-"`
+	} `toml:"gcp" json:"gcp"`
+}
+
+// NewKMS returns the KMS described by the configuration
+func NewKMS(c KMSConfiguration) (KMS, error) {
+	switch c.Type {
+	case "", KMSLocal:
+		return localKMS{}, nil
+	case KMSVaultTransit:
+		return newVaultTransitKMS(c.Vault.Addr, c.Vault.Token, c.Vault.Mount, c.Vault.Key)
+	case KMSAWS:
+		return newAWSKMS(c.AWS.Region, c.AWS.AccessKeyID, c.AWS.SecretAccessKey, c.AWS.KeyID)
+	case KMSGCP:
+		return newGCPKMS(c.GCP.KeyName, c.GCP.Credentials)
+	}
+	return nil, fmt.Errorf("unknown kms type %s", c.Type)
+}
+
+// localKMS wraps the data keys with the secrets key of the API
+type localKMS struct{}
+
+func (localKMS) Name() string { return KMSLocal }
+
+func (localKMS) Wrap(plain []byte) ([]byte, error) { return Encrypt(plain) }
+
+func (localKMS) Unwrap(wrapped []byte) ([]byte, error) { return Decrypt(wrapped) }
+
+// vaultTransitKMS wraps the data keys with the transit secret engine of a vault
+type vaultTransitKMS struct {
+	client *vault.Client
+	mount  string
+	key    string
+}
+
+func newVaultTransitKMS(addr, token, mount, key string) (KMS, error) {
+	if addr == "" || token == "" || key == "" {
+		return nil, fmt.Errorf("vault transit kms needs an address, a token and a key")
+	}
+	s, err := New(token, addr)
+	if err != nil {
+		return nil, sdk.WrapError(err, "unable to create vault client")
+	}
+	if mount == "" {
+		mount = "transit"
+	}
+	return &vaultTransitKMS{client: s.Client, mount: strings.Trim(mount, "/"), key: key}, nil
+}
+
+func (k *vaultTransitKMS) Name() string { return KMSVaultTransit }
+
+func (k *vaultTransitKMS) Wrap(plain []byte) ([]byte, error) {
+	s, err := k.client.Logical().Write(k.mount+"/encrypt/"+k.key, map[string]interface{}{
+		"plaintext": base64.StdEncoding.EncodeToString(plain),
+	})
+	if err != nil {
+		return nil, sdk.WrapError(err, "unable to encrypt with vault transit key %s", k.key)
+	}
+	if s == nil {
+		return nil, fmt.Errorf("no ciphertext returned by vault transit")
+	}
+	c, _ := s.Data["ciphertext"].(string)
+	if c == "" {
+		return nil, fmt.Errorf("no ciphertext returned by vault transit")
+	}
+	return []byte(c), nil
+}
+
+func (k *vaultTransitKMS) Unwrap(wrapped []byte) ([]byte, error) {
+	s, err := k.client.Logical().Write(k.mount+"/decrypt/"+k.key, map[string]interface{}{
+		"ciphertext": string(wrapped),
+	})
+	if err != nil {
+		return nil, sdk.WrapError(err, "unable to decrypt with vault transit key %s", k.key)
+	}
+	if s == nil {
+		return nil, fmt.Errorf("no plaintext returned by vault transit")
+	}
+	p, _ := s.Data["plaintext"].(string)
+	return base64.StdEncoding.DecodeString(p)
+}
@@ -0,0 +1,166 @@
+package secret
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	jwt "github.com/dgrijalva/jwt-go"
+
+	"github.com/ovh/cds/engine/api/aws"
+	"github.com/ovh/cds/sdk"
+)
+
+var kmsHTTPClient = &http.Client{Timeout: 30 * time.Second}
+
+// awsKMS wraps the data keys with an AWS KMS key
+type awsKMS struct {
+	client *aws.Client
+	keyID  string
+}
+
+func newAWSKMS(region, accessKeyID, secretAccessKey, keyID string) (KMS, error) {
+	if region == "" || accessKeyID == "" || secretAccessKey == "" || keyID == "" {
+		return nil, fmt.Errorf("aws kms needs a region, credentials and a key id")
+	}
+	return &awsKMS{
+		client: &aws.Client{Region: region, AccessKeyID: accessKeyID, SecretAccessKey: secretAccessKey},
+		keyID:  keyID,
+	}, nil
+}
+
+func (k *awsKMS) Name() string { return KMSAWS }
+
+func (k *awsKMS) Wrap(plain []byte) ([]byte, error) { return k.client.KMSEncrypt(k.keyID, plain) }
+
+func (k *awsKMS) Unwrap(wrapped []byte) ([]byte, error) { return k.client.KMSDecrypt(wrapped) }
+
+// gcpKMS wraps the data keys with a Google Cloud KMS key, authenticated with a service account
+type gcpKMS struct {
+	keyName     string
+	credentials struct {
+		ClientEmail string `json:"client_email"`
+		PrivateKey  string `json:"private_key"`
+		TokenURI    string `json:"token_uri"`
+	}
+	mutex       sync.Mutex
+	token       string
+	tokenExpire time.Time
+}
+
+func newGCPKMS(keyName, credentials string) (KMS, error) {
+	if keyName == "" || credentials == "" {
+		return nil, fmt.Errorf("gcp kms needs a key name and service account credentials")
+	}
+	k := &gcpKMS{keyName: keyName}
+	if err := json.Unmarshal([]byte(credentials), &k.credentials); err != nil {
+		return nil, sdk.WrapError(err, "invalid gcp service account credentials")
+	}
+	if k.credentials.TokenURI == "" {
+		k.credentials.TokenURI = "https://oauth2.googleapis.com/token"
+	}
+	return k, nil
+}
+
+func (k *gcpKMS) Name() string { return KMSGCP }
+
+// accessToken returns an oauth2 access token for the service account, the token is cached until it expires
+func (k *gcpKMS) accessToken() (string, error) {
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+	if k.token != "" && time.Now().Before(k.tokenExpire) {
+		return k.token, nil
+	}
+
+	pk, err := jwt.ParseRSAPrivateKeyFromPEM([]byte(k.credentials.PrivateKey))
+	if err != nil {
+		return "", sdk.WrapError(err, "invalid gcp service account private key")
+	}
+	now := time.Now()
+	assertion, err := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+		"iss":   k.credentials.ClientEmail,
+		"scope": "https://www.googleapis.com/auth/cloudkms",
+		"aud":   k.credentials.TokenURI,
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+	}).SignedString(pk)
+	if err != nil {
+		return "", sdk.WithStack(err)
+	}
+
+	resp, err := kmsHTTPClient.PostForm(k.credentials.TokenURI, url.Values{
+		"grant_type": {"urn:ietf:params:oauth:grant-type:jwt-bearer"},
+		"assertion":  {assertion},
+	})
+	if err != nil {
+		return "", sdk.WrapError(err, "unable to get gcp access token")
+	}
+	defer resp.Body.Close() // nolint
+	var out struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil || out.AccessToken == "" {
+		return "", fmt.Errorf("unable to get gcp access token: status %d", resp.StatusCode)
+	}
+	k.token = out.AccessToken
+	// renew the token one minute before its expiration
+	k.tokenExpire = now.Add(time.Duration(out.ExpiresIn)*time.Second - time.Minute)
+	return k.token, nil
+}
+
+func (k *gcpKMS) call(action string, in, out interface{}) error {
+	token, err := k.accessToken()
+	if err != nil {
+		return err
+	}
+	body, err := json.Marshal(in)
+	if err != nil {
+		return sdk.WithStack(err)
+	}
+	req, err := http.NewRequest(http.MethodPost, "https://cloudkms.googleapis.com/v1/"+k.keyName+":"+action, bytes.NewReader(body))
+	if err != nil {
+		return sdk.WithStack(err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := kmsHTTPClient.Do(req)
+	if err != nil {
+		return sdk.WrapError(err, "unable to %s with gcp kms", action)
+	}
+	defer resp.Body.Close() // nolint
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return sdk.WithStack(err)
+	}
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("unable to %s with gcp kms: status %d: %s", action, resp.StatusCode, string(respBody))
+	}
+	return sdk.WithStack(json.Unmarshal(respBody, out))
+}
+
+func (k *gcpKMS) Wrap(plain []byte) ([]byte, error) {
+	var out struct {
+		Ciphertext []byte `json:"ciphertext"`
+	}
+	if err := k.call("encrypt", map[string]interface{}{"plaintext": plain}, &out); err != nil {
+		return nil, err
+	}
+	return out.Ciphertext, nil
+}
+
+func (k *gcpKMS) Unwrap(wrapped []byte) ([]byte, error) {
+	var out struct {
+		Plaintext []byte `json:"plaintext"`
+	}
+	if err := k.call("decrypt", map[string]interface{}{"ciphertext": wrapped}, &out); err != nil {
+		return nil, err
+	}
+	return out.Plaintext, nil
+}
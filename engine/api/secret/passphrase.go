@@ -0,0 +1,85 @@
+package secret
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"io"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// Data encrypted with a passphrase: <salt><nonce><data><hmac>, the key is derived from the passphrase and the
+// salt with pbkdf2-hmac-sha256
+const (
+	passphraseSaltSize   = 16
+	passphraseIterations = 100000
+)
+
+// EncryptWithPassphrase encrypts data with a key derived from the passphrase, it can be decrypted by any CDS
+// instance knowing the passphrase
+func EncryptWithPassphrase(passphrase string, data []byte) ([]byte, error) {
+	salt := make([]byte, passphraseSaltSize)
+	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+		return nil, sdk.WithStack(err)
+	}
+	ct, err := encryptWithKey(passphraseKey(passphrase, salt), data)
+	if err != nil {
+		return nil, sdk.WithStack(err)
+	}
+	return append(salt, ct...), nil
+}
+
+// DecryptWithPassphrase decrypts data encrypted by EncryptWithPassphrase
+func DecryptWithPassphrase(passphrase string, data []byte) ([]byte, error) {
+	if len(data) < passphraseSaltSize {
+		return nil, sdk.WithStack(sdk.ErrInvalidSecretFormat)
+	}
+	out, err := decryptWithKey(passphraseKey(passphrase, data[:passphraseSaltSize]), data[passphraseSaltSize:])
+	if err != nil {
+		return nil, sdk.NewErrorFrom(sdk.ErrWrongRequest, "cannot decrypt data, check the passphrase")
+	}
+	return out, nil
+}
+
+// passphraseKey derives the cipher and mac keys from the passphrase
+func passphraseKey(passphrase string, salt []byte) []byte {
+	return pbkdf2SHA256([]byte(passphrase), salt, passphraseIterations, ckeySize+macSize)
+}
+
+// pbkdf2SHA256 implements the pbkdf2 key derivation function of RFC 8018 with hmac-sha256
+func pbkdf2SHA256(password, salt []byte, iterations, keyLen int) []byte {
+	prf := hmac.New(sha256.New, password)
+	key := make([]byte, 0, keyLen+prf.Size())
+	for block := uint32(1); len(key) < keyLen; block++ {
+		prf.Reset()
+		prf.Write(salt) // nolint
+		var b [4]byte
+		binary.BigEndian.PutUint32(b[:], block)
+		prf.Write(b[:]) // nolint
+		u := prf.Sum(nil)
+		t := make([]byte, len(u))
+		copy(t, u)
+		for i := 1; i < iterations; i++ {
+			prf.Reset()
+			prf.Write(u) // nolint
+			u = prf.Sum(u[:0])
+			for j := range t {
+				t[j] ^= u[j]
+			}
+		}
+		key = append(key, t...)
+	}
+	return key[:keyLen]
+}
+
+// NewPassphrase returns a random passphrase
+func NewPassphrase() (string, error) {
+	b := make([]byte, 32)
+	if _, err := io.ReadFull(rand.Reader, b); err != nil {
+		return "", sdk.WithStack(err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
@@ -0,0 +1,29 @@
+package secret
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPBKDF2SHA256(t *testing.T) {
+	// Test vector of RFC 7914
+	k := pbkdf2SHA256([]byte("passwd"), []byte("salt"), 1, 64)
+	assert.Equal(t, "55ac046e56e3089fec1691c22544b605f94185216dde0465e68b9d57c20dacbc49ca9cccf179b645991664b39d77ef317c71b845b1e30bd509112041d3a19783", hex.EncodeToString(k))
+}
+
+func TestEncryptWithPassphrase(t *testing.T) {
+	data := []byte("my secret")
+
+	ct, err := EncryptWithPassphrase("my passphrase", data)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(ct), "my secret")
+
+	clear, err := DecryptWithPassphrase("my passphrase", ct)
+	assert.NoError(t, err)
+	assert.Equal(t, data, clear)
+
+	_, err = DecryptWithPassphrase("another passphrase", ct)
+	assert.Error(t, err)
+}
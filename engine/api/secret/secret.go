@@ -108,6 +108,10 @@ func Encrypt(data []byte) ([]byte, error) {
 // Decrypt data using aes+hmac algorithm
 // Init() must be called before any decryption
 func Decrypt(data []byte) ([]byte, error) {
+	if strings.HasPrefix(string(data), prefixV2) {
+		log.Error("cannot decrypt secret encrypted with a data key without its project")
+		return nil, sdk.ErrInvalidSecretFormat
+	}
 
 	if !strings.HasPrefix(string(data), prefix) {
 		return data, nil
@@ -148,6 +152,10 @@ func Decrypt(data []byte) ([]byte, error) {
 
 //DecryptVariable decrypts variable value using aes+hmac algorithm
 func DecryptVariable(v *sdk.Variable) error {
+	return decryptVariable(0, v)
+}
+
+func decryptVariable(projectID int64, v *sdk.Variable) error {
 	if !sdk.NeedPlaceholder(v.Type) {
 		return nil
 	}
@@ -157,7 +165,7 @@ func DecryptVariable(v *sdk.Variable) error {
 		return nil
 	}
 
-	d, err := Decrypt([]byte(v.Value))
+	d, err := DecryptForProject(projectID, []byte(v.Value))
 	if err != nil {
 		return err
 	}
@@ -170,6 +178,10 @@ func DecryptVariable(v *sdk.Variable) error {
 // - return Placeholder instead of value if not needed
 // - cast returned value in string
 func DecryptS(ptype string, val sql.NullString, data []byte, clear bool) (string, error) {
+	return decryptS(0, ptype, val, data, clear)
+}
+
+func decryptS(projectID int64, ptype string, val sql.NullString, data []byte, clear bool) (string, error) {
 	// If not a password, return value
 	if !sdk.NeedPlaceholder(ptype) && val.Valid {
 		return val.String, nil
@@ -189,7 +201,7 @@ func DecryptS(ptype string, val sql.NullString, data []byte, clear bool) (string
 		return val.String, nil
 	}
 
-	d, err := Decrypt(data)
+	d, err := DecryptForProject(projectID, data)
 	if err != nil {
 		return "", err
 	}
@@ -99,3 +99,46 @@ func TestDecryptS(t *testing.T) {
 	}
 
 }
+
+func TestDecryptWithProjectKey(t *testing.T) {
+	key = []byte("78eKVxCGLm6gwoH9LAQ15ZD5AOABo1Xf")
+	InitKMS(localKMS{}, nil)
+	defer func() { ring = nil }()
+
+	dk := bytes.Repeat([]byte{42}, dataKeySize)
+	wrapped, err := ring.kms.Wrap(dk)
+	if err != nil {
+		t.Fatalf("Wrap failed: %s", err)
+	}
+	unwrapped, err := ring.unwrap(KMSLocal, wrapped)
+	if err != nil {
+		t.Fatalf("Unwrap failed: %s", err)
+	}
+	ring.cache(12, 7, unwrapped)
+
+	data := []byte("Hello world !")
+	ct, err := encryptWithKey(dk, data)
+	if err != nil {
+		t.Fatalf("Encrypt failed: %s", err)
+	}
+
+	clear, err := DecryptForProject(7, append([]byte(prefixV2+"12:"), ct...))
+	if err != nil {
+		t.Fatalf("Decrypt failed: %s", err)
+	}
+	if bytes.Compare(clear, data) != 0 {
+		t.Fatalf("Fail: Expected '%s', got '%s'", data, clear)
+	}
+
+	if _, err := DecryptForProject(8, append([]byte(prefixV2+"12:"), ct...)); err == nil {
+		t.Fatalf("Decrypt should have failed with the data key of another project")
+	}
+	if _, err := Decrypt(append([]byte(prefixV2+"12:"), ct...)); err == nil {
+		t.Fatalf("Decrypt should have failed without the project of the data key")
+	}
+
+	ct[len(ct)-1]++
+	if _, err := DecryptForProject(7, append([]byte(prefixV2+"12:"), ct...)); err == nil {
+		t.Fatalf("Decrypt should have failed with an invalid hmac")
+	}
+}
@@ -9,4 +9,5 @@ const (
 	TypeAPI           = "api"
 	TypeHatchery      = "hatchery"
 	TypeDBMigrate     = "dbmigrate"
+	TypeCDN           = "cdn"
 )
@@ -237,6 +237,10 @@ func doRequest(ctx context.Context, httpURL string, hash string, method, path st
 		tracingutils.DefaultFormat.SpanContextToRequest(spanCtx, req)
 	}
 
+	if id, ok := log.FieldsFromContext(ctx)[log.FieldRequestID].(string); ok {
+		req.Header.Set(sdk.RequestIDHeader, id)
+	}
+
 	req.Header.Set("Connection", "close")
 	req.Header.Add(sdk.RequestedWithHeader, sdk.RequestedWithValue)
 	for i := range mods {
@@ -280,3 +284,71 @@ func doRequest(ctx context.Context, httpURL string, hash string, method, path st
 
 	return nil, resp.StatusCode, fmt.Errorf("Request Failed")
 }
+
+// StreamHTTPClient is used for the requests whose response is streamed, it has no timeout
+var StreamHTTPClient sdk.HTTPClient = &http.Client{}
+
+// StreamRequest performs a GET http request on a service and returns the body of the response without reading it,
+// it is used for the large contents. The caller has to close the body.
+func StreamRequest(ctx context.Context, srvs []sdk.Service, path string, mods ...sdk.RequestModifier) (io.ReadCloser, http.Header, int, error) {
+	var lastErr error
+	var lastCode int
+	for i := range srvs {
+		body, header, code, err := streamRequest(ctx, srvs[i].HTTPURL, srvs[i].Hash, path, mods...)
+		if err == nil {
+			return body, header, code, nil
+		}
+		// do not try another service if the content was not found
+		if code == http.StatusNotFound {
+			return nil, nil, code, err
+		}
+		lastErr = err
+		lastCode = code
+	}
+	return nil, nil, lastCode, lastErr
+}
+
+func streamRequest(ctx context.Context, httpURL string, hash string, path string, mods ...sdk.RequestModifier) (io.ReadCloser, http.Header, int, error) {
+	callURL, err := url.ParseRequestURI(httpURL + path)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+
+	req, err := http.NewRequest(http.MethodGet, callURL.String(), nil)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	req = req.WithContext(ctx)
+
+	if id, ok := log.FieldsFromContext(ctx)[log.FieldRequestID].(string); ok {
+		req.Header.Set(sdk.RequestIDHeader, id)
+	}
+	req.Header.Add(sdk.RequestedWithHeader, sdk.RequestedWithValue)
+	for i := range mods {
+		if mods[i] != nil {
+			mods[i](req)
+		}
+	}
+	if hash != "" {
+		req.Header.Set(sdk.AuthHeader, base64.StdEncoding.EncodeToString([]byte(hash)))
+	}
+
+	resp, err := StreamHTTPClient.Do(req)
+	if err != nil {
+		return nil, nil, 0, sdk.WrapError(err, "services.StreamRequest> Request failed")
+	}
+
+	if resp.StatusCode < 400 {
+		return resp.Body, resp.Header, resp.StatusCode, nil
+	}
+
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, nil, resp.StatusCode, sdk.WrapError(err, "services.StreamRequest> Unable to read body")
+	}
+	if cdserr := sdk.DecodeError(body); cdserr != nil {
+		return nil, nil, resp.StatusCode, cdserr
+	}
+	return nil, nil, resp.StatusCode, fmt.Errorf("Request Failed")
+}
@@ -41,13 +41,18 @@ func (api *API) Status() sdk.MonitoringStatus {
 	m.Lines = append(m.Lines, getStatusLine(sdk.MonitoringStatusLine{Component: "CDSName", Value: event.GetCDSName(), Status: sdk.MonitoringStatusOK}))
 	m.Lines = append(m.Lines, getStatusLine(api.Router.StatusPanic()))
 	m.Lines = append(m.Lines, getStatusLine(event.Status()))
+	m.Lines = append(m.Lines, getStatusLine(event.OutboxStatus(api.mustDB())))
 	m.Lines = append(m.Lines, getStatusLine(api.Cache.Status()))
 	m.Lines = append(m.Lines, getStatusLine(sessionstore.Status))
 	m.Lines = append(m.Lines, getStatusLine(objectstore.Status()))
 	m.Lines = append(m.Lines, getStatusLine(mail.Status()))
 	m.Lines = append(m.Lines, getStatusLine(api.DBConnectionFactory.Status()))
+	if api.DBConnectionFactory.Replicas != nil {
+		m.Lines = append(m.Lines, api.DBConnectionFactory.Replicas.Status()...)
+	}
 	m.Lines = append(m.Lines, getStatusLine(worker.Status(api.mustDB())))
 	m.Lines = append(m.Lines, getStatusLine(migrate.Status(api.mustDB())))
+	m.Lines = append(m.Lines, getStatusLine(api.maintenanceStatusLine()))
 
 	return m
 }
@@ -121,6 +126,7 @@ func (api *API) computeGlobalStatus(srvs []sdk.Service) sdk.MonitoringStatus {
 		services.TypeHatchery:      {minInstance: api.Config.Status.Hatchery.MinInstance},
 		services.TypeDBMigrate:     {minInstance: api.Config.Status.DBMigrate.MinInstance},
 		services.TypeElasticsearch: {minInstance: api.Config.Status.ElasticSearch.MinInstance},
+		services.TypeCDN:           {minInstance: api.Config.Status.CDN.MinInstance},
 	}
 	var nbg computeGlobalNumbers
 	for _, s := range srvs {
@@ -259,6 +265,13 @@ func (api *API) initMetrics(ctx context.Context) error {
 	label = fmt.Sprintf("cds/cds-api/%s/workflow_runs_deleted", api.Name)
 	api.Metrics.WorkflowRunsDeleted = stats.Int64(label, "number of workflow runs deleted", stats.UnitDimensionless)
 
+	api.Metrics.jobSchedulingLatency = stats.Float64("cds/cds-api/job_scheduling_latency", "time between the queuing of a job and its take by a worker", stats.UnitMilliseconds)
+	api.Metrics.dbOpenConnections = stats.Int64("cds/cds-api/db_open_connections", "number of established connections to the database", stats.UnitDimensionless)
+	api.Metrics.dbInUseConnections = stats.Int64("cds/cds-api/db_in_use_connections", "number of connections to the database currently in use", stats.UnitDimensionless)
+	api.Metrics.dbIdleConnections = stats.Int64("cds/cds-api/db_idle_connections", "number of idle connections to the database", stats.UnitDimensionless)
+	api.Metrics.dbWaitCount = stats.Int64("cds/cds-api/db_wait_count", "total number of connections waited for", stats.UnitDimensionless)
+	api.Metrics.dbWaitDuration = stats.Int64("cds/cds-api/db_wait_duration", "total time blocked waiting for a new connection", stats.UnitMilliseconds)
+
 	tagRange, _ = tag.NewKey("range")
 	tagStatus, _ = tag.NewKey("status")
 	tagServiceName, _ = tag.NewKey("name")
@@ -287,6 +300,12 @@ func (api *API) initMetrics(ctx context.Context) error {
 		observability.NewViewCount("workflow_runs_failed", api.Metrics.WorkflowRunFailed, tags),
 		observability.NewViewCount("workflow_runs_mark_to_delete", api.Metrics.WorkflowRunsMarkToDelete, tags),
 		observability.NewViewCount("workflow_runs_deleted", api.Metrics.WorkflowRunsDeleted, tags),
+		observability.NewViewDistribution("job_scheduling_latency", api.Metrics.jobSchedulingLatency, tags, observability.LatencyBounds),
+		observability.NewViewLast("db_open_connections", api.Metrics.dbOpenConnections, tags),
+		observability.NewViewLast("db_in_use_connections", api.Metrics.dbInUseConnections, tags),
+		observability.NewViewLast("db_idle_connections", api.Metrics.dbIdleConnections, tags),
+		observability.NewViewLast("db_wait_count", api.Metrics.dbWaitCount, tags),
+		observability.NewViewLast("db_wait_duration", api.Metrics.dbWaitDuration, tags),
 	)
 
 	return err
@@ -337,6 +356,7 @@ func (api *API) computeMetrics(ctx context.Context) {
 				api.countMetricRange(ctx, "waiting", "70_more_10min", api.Metrics.queue, queryOld, now10min)
 
 				api.processStatusMetrics(ctx)
+				api.processDBMetrics(ctx)
 			}
 		}
 	})
@@ -367,6 +387,19 @@ func (api *API) countMetricRange(ctx context.Context, status string, timerange s
 	observability.Record(ctx, v, n)
 }
 
+func (api *API) processDBMetrics(ctx context.Context) {
+	db := api.DBConnectionFactory.DB()
+	if db == nil {
+		return
+	}
+	s := db.Stats()
+	observability.Record(ctx, api.Metrics.dbOpenConnections, int64(s.OpenConnections))
+	observability.Record(ctx, api.Metrics.dbInUseConnections, int64(s.InUse))
+	observability.Record(ctx, api.Metrics.dbIdleConnections, int64(s.Idle))
+	observability.Record(ctx, api.Metrics.dbWaitCount, s.WaitCount)
+	observability.Record(ctx, api.Metrics.dbWaitDuration, int64(s.WaitDuration/time.Millisecond))
+}
+
 func (api *API) processStatusMetrics(ctx context.Context) {
 	srvs, err := services.All(api.mustDB())
 	if err != nil {
@@ -0,0 +1,13 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/ovh/cds/engine/api/test/assets"
+)
+
+func Test_getMetricsHandler(t *testing.T) {
+	api, _, _, end := newTestAPI(t)
+	defer end()
+	assets.CheckMetricsHandler(t, api.Router, api.Router.Mux, "cds-api")
+}
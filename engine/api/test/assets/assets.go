@@ -8,6 +8,7 @@ import (
 	"io"
 	"mime/multipart"
 	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"testing"
@@ -18,9 +19,11 @@ import (
 	"github.com/ovh/cds/engine/api/accesstoken"
 	"github.com/ovh/cds/engine/api/cache"
 	"github.com/ovh/cds/engine/api/group"
+	"github.com/ovh/cds/engine/api/observability"
 	"github.com/ovh/cds/engine/api/permission"
 	"github.com/ovh/cds/engine/api/project"
 	"github.com/ovh/cds/engine/api/user"
+	"github.com/ovh/cds/engine/service"
 	"github.com/ovh/cds/sdk"
 )
 
@@ -357,3 +360,26 @@ func NewJWTTokenWithXSRF(t *testing.T, db gorp.SqlExecutor, store cache.Store, u
 	xsrf := accesstoken.StoreXSRFToken(store, token)
 	return jwt, xsrf, err
 }
+
+// Router is the part of the router of a service needed to find one of its routes
+type Router interface {
+	GetRoute(method string, handler service.HandlerFunc, vars map[string]string) string
+}
+
+// CheckMetricsHandler checks that the metrics of a service are served without authentication by its router
+func CheckMetricsHandler(t *testing.T, router Router, mux http.Handler, serviceName string) {
+	if err := observability.Init(observability.Configuration{}, serviceName); err != nil {
+		t.Fatalf("Cannot init observability : %s", err)
+	}
+
+	uri := router.GetRoute("GET", observability.StatsHandler, nil)
+	if uri == "" {
+		t.Fatalf("Cannot find the route of the metrics")
+	}
+	req := httptest.NewRequest("GET", uri, nil)
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Metrics handler returned %d", rec.Code)
+	}
+}
@@ -0,0 +1,171 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/go-gorp/gorp"
+	"github.com/gorilla/mux"
+
+	"github.com/ovh/cds/engine/api/event"
+	"github.com/ovh/cds/engine/api/pipeline"
+	"github.com/ovh/cds/engine/api/project"
+	"github.com/ovh/cds/engine/api/trash"
+	"github.com/ovh/cds/engine/api/workflow"
+	"github.com/ovh/cds/engine/service"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// trashRetention returns how long the deleted workflows and pipelines are kept in the trash
+func (api *API) trashRetention() time.Duration {
+	return time.Duration(api.Config.Trash.Retention) * 24 * time.Hour
+}
+
+// putInTrash marks a workflow or a pipeline to be deleted and keeps it in the trash of its project until the purge
+func putInTrash(db gorp.SqlExecutor, proj *sdk.Project, itemType string, itemID int64, name string, u *sdk.User) error {
+	switch itemType {
+	case sdk.TrashItemWorkflow:
+		if err := workflow.MarkAsDelete(db, &sdk.Workflow{ID: itemID}); err != nil {
+			return err
+		}
+	case sdk.TrashItemPipeline:
+		if err := pipeline.MarkAsDelete(db, itemID); err != nil {
+			return err
+		}
+	}
+	return trash.Insert(db, &sdk.TrashItem{
+		ProjectID: proj.ID,
+		Type:      itemType,
+		ItemID:    itemID,
+		Name:      name,
+		DeletedBy: u.Username,
+		Deleted:   time.Now(),
+	})
+}
+
+func (api *API) getProjectTrashHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		key := vars[permProjectKey]
+
+		proj, err := project.Load(api.mustDB(), api.Cache, key, deprecatedGetUser(ctx))
+		if err != nil {
+			return sdk.WrapError(err, "cannot load project %s", key)
+		}
+
+		items, err := trash.LoadAll(api.mustDB(), proj.ID)
+		if err != nil {
+			return err
+		}
+		for i := range items {
+			items[i].Purge = items[i].Deleted.Add(api.trashRetention())
+		}
+
+		return service.WriteJSON(w, items, http.StatusOK)
+	}
+}
+
+// postProjectTrashRestoreHandler restores a workflow or a pipeline from the trash. The hooks of a restored workflow
+// are started again, and the pipelines it uses are restored with it.
+func (api *API) postProjectTrashRestoreHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		key := vars[permProjectKey]
+		u := deprecatedGetUser(ctx)
+
+		proj, err := project.Load(api.mustDB(), api.Cache, key, u, project.LoadOptions.WithIntegrations)
+		if err != nil {
+			return sdk.WrapError(err, "cannot load project %s", key)
+		}
+
+		item, err := trash.Load(api.mustDB(), proj.ID, vars["type"], vars["name"])
+		if err != nil {
+			return err
+		}
+
+		tx, err := api.mustDB().Begin()
+		if err != nil {
+			return sdk.WrapError(err, "cannot start transaction")
+		}
+		defer tx.Rollback() // nolint
+
+		restored := []sdk.TrashItem{*item}
+		switch item.Type {
+		case sdk.TrashItemWorkflow:
+			if err := workflow.Restore(tx, item.ItemID); err != nil {
+				return err
+			}
+			pipelineIDs, err := workflow.LoadPipelineIDsByWorkflowID(tx, item.ItemID)
+			if err != nil {
+				return err
+			}
+			items, err := trash.LoadAll(tx, proj.ID)
+			if err != nil {
+				return err
+			}
+			for _, it := range items {
+				if it.Type != sdk.TrashItemPipeline || !sdk.IsInInt64Array(it.ItemID, pipelineIDs) {
+					continue
+				}
+				if err := pipeline.Restore(tx, it.ItemID); err != nil {
+					return err
+				}
+				restored = append(restored, it)
+			}
+		case sdk.TrashItemPipeline:
+			if err := pipeline.Restore(tx, item.ItemID); err != nil {
+				return err
+			}
+		}
+		for i := range restored {
+			if err := trash.Delete(tx, &restored[i]); err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Commit(); err != nil {
+			return sdk.WrapError(err, "cannot commit transaction")
+		}
+
+		for _, it := range restored {
+			switch it.Type {
+			case sdk.TrashItemWorkflow:
+				uuids, err := workflow.LoadHookUUIDsByWorkflowID(api.mustDB(), it.ItemID)
+				if err != nil {
+					log.Warning("postProjectTrashRestoreHandler> %v", err)
+				}
+				api.switchHooks(ctx, uuids, "start")
+				event.PublishWorkflowAdd(key, sdk.Workflow{ID: it.ItemID, Name: it.Name, ProjectID: proj.ID, ProjectKey: key}, u)
+			case sdk.TrashItemPipeline:
+				event.PublishPipelineAdd(key, sdk.Pipeline{ID: it.ItemID, Name: it.Name, ProjectID: proj.ID, ProjectKey: key}, u)
+			}
+		}
+
+		return service.WriteJSON(w, restored, http.StatusOK)
+	}
+}
+
+// deleteProjectTrashHandler removes a workflow or a pipeline from the trash, it is deleted by the next purge
+func (api *API) deleteProjectTrashHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		key := vars[permProjectKey]
+
+		proj, err := project.Load(api.mustDB(), api.Cache, key, deprecatedGetUser(ctx))
+		if err != nil {
+			return sdk.WrapError(err, "cannot load project %s", key)
+		}
+
+		item, err := trash.Load(api.mustDB(), proj.ID, vars["type"], vars["name"])
+		if err != nil {
+			return err
+		}
+		if err := trash.Delete(api.mustDB(), item); err != nil {
+			return err
+		}
+
+		return service.WriteJSON(w, nil, http.StatusOK)
+	}
+}
@@ -0,0 +1,10 @@
+package trash
+
+import (
+	"github.com/ovh/cds/engine/api/database/gorpmapping"
+	"github.com/ovh/cds/sdk"
+)
+
+func init() {
+	gorpmapping.Register(gorpmapping.New(sdk.TrashItem{}, "trash", true, "id"))
+}
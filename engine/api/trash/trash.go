@@ -0,0 +1,47 @@
+package trash
+
+import (
+	"database/sql"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// Insert puts a deleted workflow or pipeline in the trash of its project
+func Insert(db gorp.SqlExecutor, item *sdk.TrashItem) error {
+	return sdk.WrapError(db.Insert(item), "cannot insert %s %s in trash", item.Type, item.Name)
+}
+
+// LoadAll returns the items of the trash of a project, the last deleted first
+func LoadAll(db gorp.SqlExecutor, projectID int64) ([]sdk.TrashItem, error) {
+	items := []sdk.TrashItem{}
+	if _, err := db.Select(&items, "SELECT * FROM trash WHERE project_id = $1 ORDER BY deleted DESC", projectID); err != nil {
+		return nil, sdk.WrapError(err, "cannot load trash of project %d", projectID)
+	}
+	return items, nil
+}
+
+// Load returns the last deleted item of the trash of a project with the given type and name
+func Load(db gorp.SqlExecutor, projectID int64, itemType, name string) (*sdk.TrashItem, error) {
+	var item sdk.TrashItem
+	if err := db.SelectOne(&item, "SELECT * FROM trash WHERE project_id = $1 AND type = $2 AND name = $3 ORDER BY deleted DESC LIMIT 1", projectID, itemType, name); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, sdk.NewErrorFrom(sdk.ErrNotFound, "there is no %s %s in the trash", itemType, name)
+		}
+		return nil, sdk.WrapError(err, "cannot load %s %s from trash", itemType, name)
+	}
+	return &item, nil
+}
+
+// Delete removes an item from the trash, the workflow or pipeline is either restored or purged
+func Delete(db gorp.SqlExecutor, item *sdk.TrashItem) error {
+	_, err := db.Delete(item)
+	return sdk.WrapError(err, "cannot delete %s %s from trash", item.Type, item.Name)
+}
+
+// DeleteByItem removes a purged workflow or pipeline from the trash
+func DeleteByItem(db gorp.SqlExecutor, itemType string, itemID int64) error {
+	_, err := db.Exec("DELETE FROM trash WHERE type = $1 AND item_id = $2", itemType, itemID)
+	return sdk.WrapError(err, "cannot delete %s %d from trash", itemType, itemID)
+}
@@ -0,0 +1,80 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/engine/api/pipeline"
+	"github.com/ovh/cds/engine/api/test"
+	"github.com/ovh/cds/engine/api/test/assets"
+	"github.com/ovh/cds/engine/api/workflow"
+	"github.com/ovh/cds/sdk"
+)
+
+func Test_trashWorkflow(t *testing.T) {
+	api, db, router, end := newTestAPI(t)
+	defer end()
+	api.Config.Trash.Retention = 30
+	test.NoError(t, workflow.CreateBuiltinWorkflowHookModels(db))
+
+	u, pass := assets.InsertAdminUser(api.mustDB())
+	key := sdk.RandomString(10)
+	proj := assets.InsertTestProject(t, db, api.Cache, key, key, u)
+	pip := sdk.Pipeline{
+		Name:      "pipeline1",
+		ProjectID: proj.ID,
+	}
+	test.NoError(t, pipeline.InsertPipeline(api.mustDB(), api.Cache, proj, &pip, nil))
+	wf := sdk.Workflow{
+		Name:       "Name",
+		ProjectID:  proj.ID,
+		ProjectKey: proj.Key,
+		WorkflowData: &sdk.WorkflowData{
+			Node: sdk.Node{
+				Type: sdk.NodeTypePipeline,
+				Context: &sdk.NodeContext{
+					PipelineID: pip.ID,
+				},
+			},
+		},
+	}
+	test.NoError(t, workflow.Insert(db, api.Cache, &wf, proj, u))
+
+	// Delete the workflow, it goes in the trash
+	uri := router.GetRoute("DELETE", api.deleteWorkflowHandler, map[string]string{"key": proj.Key, "permWorkflowName": wf.Name})
+	test.NotEmpty(t, uri)
+	w := httptest.NewRecorder()
+	router.Mux.ServeHTTP(w, assets.NewAuthentifiedRequest(t, u, pass, "DELETE", uri, nil))
+	assert.Equal(t, 200, w.Code)
+
+	_, err := workflow.Load(context.TODO(), db, api.Cache, proj, wf.Name, u, workflow.LoadOptions{})
+	assert.Error(t, err)
+
+	uri = router.GetRoute("GET", api.getProjectTrashHandler, map[string]string{"permProjectKey": proj.Key})
+	test.NotEmpty(t, uri)
+	w = httptest.NewRecorder()
+	router.Mux.ServeHTTP(w, assets.NewAuthentifiedRequest(t, u, pass, "GET", uri, nil))
+	assert.Equal(t, 200, w.Code)
+	var items []sdk.TrashItem
+	test.NoError(t, json.Unmarshal(w.Body.Bytes(), &items))
+	if assert.Len(t, items, 1) {
+		assert.Equal(t, sdk.TrashItemWorkflow, items[0].Type)
+		assert.Equal(t, wf.Name, items[0].Name)
+		assert.Equal(t, u.Username, items[0].DeletedBy)
+		assert.Equal(t, items[0].Deleted.Add(api.trashRetention()).Unix(), items[0].Purge.Unix())
+	}
+
+	// Restore it
+	uri = router.GetRoute("POST", api.postProjectTrashRestoreHandler, map[string]string{"permProjectKey": proj.Key, "type": sdk.TrashItemWorkflow, "name": wf.Name})
+	test.NotEmpty(t, uri)
+	w = httptest.NewRecorder()
+	router.Mux.ServeHTTP(w, assets.NewAuthentifiedRequest(t, u, pass, "POST", uri, nil))
+	assert.Equal(t, 200, w.Code)
+
+	_, err = workflow.Load(context.TODO(), db, api.Cache, proj, wf.Name, u, workflow.LoadOptions{})
+	assert.NoError(t, err)
+}
@@ -0,0 +1,81 @@
+package usage
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// AddBuildTime counts the duration of a job in the usage of its project for the month the job finished
+func AddBuildTime(db gorp.SqlExecutor, projectID int64, done time.Time, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+	query := `
+	INSERT INTO resource_usage (project_id, month, build_seconds) VALUES ($1, $2, $3)
+	ON CONFLICT (project_id, month) DO UPDATE SET build_seconds = resource_usage.build_seconds + $3`
+	_, err := db.Exec(query, projectID, sdk.UsageMonth(done), int64(d.Seconds()))
+	return sdk.WrapError(err, "cannot add build time of project %d", projectID)
+}
+
+// AddArtifactSize counts an uploaded artifact in the usage of its project for the current month
+func AddArtifactSize(db gorp.SqlExecutor, projectID int64, size int64) error {
+	if size <= 0 {
+		return nil
+	}
+	query := `
+	INSERT INTO resource_usage (project_id, month, artifact_size) VALUES ($1, $2, $3)
+	ON CONFLICT (project_id, month) DO UPDATE SET artifact_size = resource_usage.artifact_size + $3`
+	_, err := db.Exec(query, projectID, sdk.UsageMonth(time.Now()), size)
+	return sdk.WrapError(err, "cannot add artifact size of project %d", projectID)
+}
+
+// LoadByProject returns the usage of a project during a month, a zero usage if nothing was counted
+func LoadByProject(db gorp.SqlExecutor, projectID int64, month string) (*sdk.ResourceUsage, error) {
+	var r sdk.ResourceUsage
+	query := `
+	SELECT project.id AS project_id, project.projectkey AS project_key, $2 AS month,
+		COALESCE(resource_usage.build_seconds, 0) AS build_seconds, COALESCE(resource_usage.artifact_size, 0) AS artifact_size
+	FROM project
+	LEFT JOIN resource_usage ON resource_usage.project_id = project.id AND resource_usage.month = $2
+	WHERE project.id = $1`
+	if err := db.SelectOne(&r, query, projectID, month); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, sdk.WithStack(sdk.ErrNoProject)
+		}
+		return nil, sdk.WrapError(err, "cannot load usage of project %d", projectID)
+	}
+	r.ComputeBuildMinutes()
+	return &r, nil
+}
+
+// LoadByOrganization returns the usage of all the projects of an organization during a month
+func LoadByOrganization(db gorp.SqlExecutor, organizationID int64, month string) ([]sdk.ResourceUsage, error) {
+	rs := []sdk.ResourceUsage{}
+	query := `
+	SELECT project.id AS project_id, project.projectkey AS project_key, $2 AS month,
+		COALESCE(resource_usage.build_seconds, 0) AS build_seconds, COALESCE(resource_usage.artifact_size, 0) AS artifact_size
+	FROM project
+	LEFT JOIN resource_usage ON resource_usage.project_id = project.id AND resource_usage.month = $2
+	WHERE project.organization_id = $1
+	ORDER BY project.projectkey`
+	if _, err := db.Select(&rs, query, organizationID, month); err != nil {
+		return nil, sdk.WrapError(err, "cannot load usage of organization %d", organizationID)
+	}
+	for i := range rs {
+		rs[i].ComputeBuildMinutes()
+	}
+	return rs, nil
+}
+
+// OrganizationBuildSeconds returns the build time of all the projects of an organization during a month
+func OrganizationBuildSeconds(db gorp.SqlExecutor, organizationID int64, month string) (int64, error) {
+	n, err := db.SelectInt(`
+	SELECT COALESCE(SUM(resource_usage.build_seconds), 0) FROM resource_usage
+	JOIN project ON project.id = resource_usage.project_id
+	WHERE project.organization_id = $1 AND resource_usage.month = $2`, organizationID, month)
+	return n, sdk.WrapError(err, "cannot load build time of organization %d", organizationID)
+}
@@ -425,6 +425,13 @@ func (api *API) confirmUserHandler() service.Handler {
 			User: *u,
 		}
 
+		// The users with a second factor only get their new password, they have to log in with it and their second factor
+		if u.Auth.MFA.Enabled() {
+			response.Password = password
+			response.User.Auth = sdk.Auth{}
+			return service.WriteJSON(w, response, http.StatusOK)
+		}
+
 		var logFromCLI bool
 		if r.Header.Get(sdk.RequestedWithHeader) == sdk.RequestedWithValue {
 			log.Info("LoginUser> login from CLI")
@@ -484,12 +491,38 @@ func (api *API) loginUserHandler() service.Handler {
 		if errl != nil {
 			return sdk.WrapError(sdk.ErrWrongRequest, "Auth> Login error %s: %s", loginUserRequest.Username, errl)
 		}
+		if u.Deactivated {
+			return sdk.WrapError(sdk.ErrInvalidUser, "Auth> Login error %s: user is deactivated", loginUserRequest.Username)
+		}
 
 		// Prepare response
 		response := sdk.UserAPIResponse{
 			User: *u,
 		}
 
+		// Check the second factor of the local accounts
+		if api.isLocalAuth() {
+			usr, err := user.LoadUserAndAuth(api.mustDB(), loginUserRequest.Username)
+			if err != nil {
+				return sdk.WrapError(sdk.ErrInvalidUser, "Auth> Login error %s: %s", loginUserRequest.Username, err)
+			}
+			if usr.Auth.MFA.Enabled() {
+				var v sdk.MFAVerification
+				if loginUserRequest.MFA != nil {
+					v = *loginUserRequest.MFA
+				}
+				if err := api.verifyMFA(usr, v); err != nil {
+					return sdk.WrapError(err, "Auth> Login failed: %s", loginUserRequest.Username)
+				}
+			} else {
+				required, err := api.isMFAEnrollmentRequired(u)
+				if err != nil {
+					return err
+				}
+				response.MFAEnrollmentRequired = required
+			}
+		}
+
 		if err := group.CheckUserInDefaultGroup(api.mustDB(), u.ID); err != nil {
 			log.Warning("Auth> Error while check user in default group:%s\n", err)
 		}
@@ -10,4 +10,5 @@ type persistentSessionToken sdk.UserToken
 
 func init() {
 	gorpmapping.Register(gorpmapping.New(persistentSessionToken{}, "user_persistent_session", false, "token"))
+	gorpmapping.Register(gorpmapping.New(sdk.UserVCSAccount{}, "user_vcs_account", true, "id"))
 }
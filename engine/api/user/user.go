@@ -71,13 +71,13 @@ func IsAllowedDomain(allowedDomains string, email string) bool {
 
 // LoadUserWithoutAuthByID load user information without secret
 func LoadUserWithoutAuthByID(db gorp.SqlExecutor, userID int64) (*sdk.User, error) {
-	query := `SELECT username, admin, data, origin FROM "user" WHERE id = $1`
+	query := `SELECT username, admin, data, origin, deactivated FROM "user" WHERE id = $1`
 
 	var jsonUser []byte
 	var username, origin string
-	var admin bool
+	var admin, deactivated bool
 
-	if err := db.QueryRow(query, userID).Scan(&username, &admin, &jsonUser, &origin); err != nil {
+	if err := db.QueryRow(query, userID).Scan(&username, &admin, &jsonUser, &origin, &deactivated); err != nil {
 		return nil, err
 	}
 
@@ -90,19 +90,20 @@ func LoadUserWithoutAuthByID(db gorp.SqlExecutor, userID int64) (*sdk.User, erro
 	u.Admin = admin
 	u.ID = userID
 	u.Origin = origin
+	u.Deactivated = deactivated
 	return u, nil
 }
 
 // LoadUserWithoutAuth load user without auth information
 func LoadUserWithoutAuth(db gorp.SqlExecutor, name string) (*sdk.User, error) {
-	query := `SELECT id, admin, data, origin FROM "user" WHERE username = $1`
+	query := `SELECT id, admin, data, origin, deactivated FROM "user" WHERE username = $1`
 
 	var jsonUser []byte
 	var id int64
-	var admin bool
+	var admin, deactivated bool
 	var origin string
 
-	if err := db.QueryRow(query, name).Scan(&id, &admin, &jsonUser, &origin); err != nil {
+	if err := db.QueryRow(query, name).Scan(&id, &admin, &jsonUser, &origin, &deactivated); err != nil {
 		return nil, err
 	}
 
@@ -115,20 +116,21 @@ func LoadUserWithoutAuth(db gorp.SqlExecutor, name string) (*sdk.User, error) {
 	u.Admin = admin
 	u.ID = id
 	u.Origin = origin
+	u.Deactivated = deactivated
 	return u, nil
 }
 
 // LoadUserAndAuth Load user with auth information
 func LoadUserAndAuth(db gorp.SqlExecutor, name string) (*sdk.User, error) {
-	query := `SELECT id, admin, data, auth, origin FROM "user" WHERE username = $1`
+	query := `SELECT id, admin, data, auth, origin, deactivated FROM "user" WHERE username = $1`
 
 	var jsonUser []byte
 	var jsonAuth []byte
 	var id int64
-	var admin bool
+	var admin, deactivated bool
 	var origin string
 
-	if err := db.QueryRow(query, name).Scan(&id, &admin, &jsonUser, &jsonAuth, &origin); err != nil {
+	if err := db.QueryRow(query, name).Scan(&id, &admin, &jsonUser, &jsonAuth, &origin, &deactivated); err != nil {
 		return nil, err
 	}
 
@@ -148,6 +150,7 @@ func LoadUserAndAuth(db gorp.SqlExecutor, name string) (*sdk.User, error) {
 	u.Auth = *a
 	u.ID = id
 	u.Origin = origin
+	u.Deactivated = deactivated
 	return u, nil
 }
 
@@ -164,19 +167,31 @@ func FindUserIDByName(db gorp.SqlExecutor, name string) (int64, error) {
 
 // LoadUsers load all users from database
 func LoadUsers(db gorp.SqlExecutor) ([]*sdk.User, error) {
+	query := `SELECT "user".id, "user".username, "user".data, origin, admin, deactivated FROM "user" ORDER BY "user".username`
+	return loadUsers(db, query)
+}
+
+// LoadUsersPage loads the users from database ordered by username, starting at offset, limit 0 means all users
+func LoadUsersPage(db gorp.SqlExecutor, offset, limit int) ([]*sdk.User, error) {
+	query := `SELECT "user".id, "user".username, "user".data, origin, admin, deactivated FROM "user" ORDER BY "user".username OFFSET $1 LIMIT NULLIF($2, 0)`
+	return loadUsers(db, query, offset, limit)
+}
+
+func loadUsers(db gorp.SqlExecutor, query string, args ...interface{}) ([]*sdk.User, error) {
 	users := []*sdk.User{}
 
-	query := `SELECT "user".username, "user".data, origin, admin FROM "user" ORDER BY "user".username`
-	rows, err := db.Query(query)
+	rows, err := db.Query(query, args...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
 	for rows.Next() {
+		var id int64
 		var username, data, origin string
 		var adminSQL sql.NullBool
-		err := rows.Scan(&username, &data, &origin, &adminSQL)
+		var deactivated bool
+		err := rows.Scan(&id, &username, &data, &origin, &adminSQL, &deactivated)
 		if err != nil {
 			return nil, err
 		}
@@ -193,11 +208,13 @@ func LoadUsers(db gorp.SqlExecutor) ([]*sdk.User, error) {
 		}
 
 		u := &sdk.User{
-			Username: username,
-			Fullname: uTemp.Fullname,
-			Origin:   origin,
-			Email:    uTemp.Email,
-			Admin:    admin,
+			ID:          id,
+			Username:    username,
+			Fullname:    uTemp.Fullname,
+			Origin:      origin,
+			Email:       uTemp.Email,
+			Admin:       admin,
+			Deactivated: deactivated,
 		}
 
 		users = append(users, u)
@@ -244,6 +261,24 @@ func UpdateUserAndAuth(db gorp.SqlExecutor, u sdk.User) error {
 	return err
 }
 
+// UpdateAuth updates only the authentication data of a user
+func UpdateAuth(db gorp.SqlExecutor, userID int64, a sdk.Auth) error {
+	sa, err := json.Marshal(a)
+	if err != nil {
+		return sdk.WithStack(err)
+	}
+	query := `UPDATE "user" SET auth = $1 WHERE id = $2`
+	_, err = db.Exec(query, sa, userID)
+	return sdk.WrapError(err, "cannot update auth of user %d", userID)
+}
+
+// SetDeactivated deactivates or reactivates a user, a deactivated user cannot log in anymore
+func SetDeactivated(db gorp.SqlExecutor, userID int64, deactivated bool) error {
+	query := `UPDATE "user" SET deactivated = $1 WHERE id = $2`
+	_, err := db.Exec(query, deactivated, userID)
+	return sdk.WrapError(err, "cannot update user %d", userID)
+}
+
 // DeleteUserWithDependenciesByName Delete user and all his dependencies
 func DeleteUserWithDependenciesByName(db gorp.SqlExecutor, s string) error {
 	u, err := LoadUserWithoutAuth(db, s)
@@ -338,3 +373,9 @@ func UpdatePersistentSessionToken(db gorp.SqlExecutor, t sdk.UserToken) error {
 	}
 	return nil
 }
+
+// DeletePersistentSessionTokensByUser revokes all the persistent sessions of a user
+func DeletePersistentSessionTokensByUser(db gorp.SqlExecutor, userID int64) error {
+	_, err := db.Exec("delete from user_persistent_session where user_id = $1", userID)
+	return sdk.WrapError(err, "Unable to delete persistent session tokens for user %d", userID)
+}
@@ -0,0 +1,67 @@
+package user
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/go-gorp/gorp"
+	"github.com/lib/pq"
+
+	"github.com/ovh/cds/engine/api/database/gorpmapping"
+	"github.com/ovh/cds/sdk"
+)
+
+// LoadVCSAccounts returns the accounts on the repositories managers linked to a user
+func LoadVCSAccounts(db gorp.SqlExecutor, userID int64) ([]sdk.UserVCSAccount, error) {
+	as := []sdk.UserVCSAccount{}
+	if _, err := db.Select(&as, "SELECT * FROM user_vcs_account WHERE user_id = $1 ORDER BY vcs_server, username", userID); err != nil {
+		return nil, sdk.WrapError(err, "cannot load vcs accounts of user %d", userID)
+	}
+	return as, nil
+}
+
+// LoadVCSAccountByID returns an account on a repositories manager linked to a user
+func LoadVCSAccountByID(db gorp.SqlExecutor, userID, id int64) (*sdk.UserVCSAccount, error) {
+	var a sdk.UserVCSAccount
+	if err := db.SelectOne(&a, "SELECT * FROM user_vcs_account WHERE user_id = $1 AND id = $2", userID, id); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, sdk.NewErrorFrom(sdk.ErrNotFound, "vcs account %d not found", id)
+		}
+		return nil, sdk.WrapError(err, "cannot load vcs account %d", id)
+	}
+	return &a, nil
+}
+
+// LoadUserByVCSAccount returns the user linked to an account on a repositories manager
+func LoadUserByVCSAccount(db gorp.SqlExecutor, vcsServer, username string) (*sdk.User, error) {
+	var a sdk.UserVCSAccount
+	if err := db.SelectOne(&a, "SELECT * FROM user_vcs_account WHERE vcs_server = $1 AND username = $2", vcsServer, username); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, sdk.NewErrorFrom(sdk.ErrNotFound, "the account %s of %s is not linked to a user", username, vcsServer)
+		}
+		return nil, sdk.WrapError(err, "cannot load vcs account %s of %s", username, vcsServer)
+	}
+	u, err := LoadUserWithoutAuthByID(db, a.UserID)
+	if err != nil {
+		return nil, sdk.WrapError(err, "cannot load user %d", a.UserID)
+	}
+	return u, nil
+}
+
+// InsertVCSAccount links an account on a repositories manager to a user, an account can only be linked to one user
+func InsertVCSAccount(db gorp.SqlExecutor, a *sdk.UserVCSAccount) error {
+	a.Created = time.Now()
+	if err := db.Insert(a); err != nil {
+		if errPG, ok := err.(*pq.Error); ok && errPG.Code == gorpmapping.ViolateUniqueKeyPGCode {
+			return sdk.NewErrorFrom(sdk.ErrConflict, "the account %s of %s is already linked to a user", a.Username, a.VCSServer)
+		}
+		return sdk.WrapError(err, "cannot insert vcs account %s of %s", a.Username, a.VCSServer)
+	}
+	return nil
+}
+
+// DeleteVCSAccount unlinks an account on a repositories manager
+func DeleteVCSAccount(db gorp.SqlExecutor, a *sdk.UserVCSAccount) error {
+	_, err := db.Delete(a)
+	return sdk.WrapError(err, "cannot delete vcs account %s of %s", a.Username, a.VCSServer)
+}
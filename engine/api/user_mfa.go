@@ -0,0 +1,393 @@
+package api
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/ovh/cds/engine/api/auth"
+	"github.com/ovh/cds/engine/api/cache"
+	"github.com/ovh/cds/engine/api/group"
+	"github.com/ovh/cds/engine/api/mfa"
+	"github.com/ovh/cds/engine/api/user"
+	"github.com/ovh/cds/engine/service"
+	"github.com/ovh/cds/sdk"
+)
+
+// totpIssuer is the name of CDS displayed by the authenticator applications
+const totpIssuer = "CDS"
+
+// isLocalAuth returns true if the users are authenticated by the local driver, the second factors of the other
+// drivers are managed by their identity provider
+func (api *API) isLocalAuth() bool {
+	_, ok := api.Router.AuthDriver.(*auth.LocalClient)
+	return ok
+}
+
+// isMFAEnrollmentRequired returns true if a user without second factor is member of a group requiring two-factor
+// authentication
+func (api *API) isMFAEnrollmentRequired(u *sdk.User) (bool, error) {
+	k := cache.Key("users", u.Username, "mfa")
+	var required bool
+	if api.Cache.Get(k, &required) {
+		return required, nil
+	}
+
+	usr, err := user.LoadUserAndAuth(api.mustDB(), u.Username)
+	if err != nil {
+		return false, sdk.WrapError(err, "cannot load user %s", u.Username)
+	}
+	if !usr.Auth.MFA.Enabled() {
+		required, err = group.IsMFARequiredForUser(api.mustDB(), u.ID)
+		if err != nil {
+			return false, err
+		}
+	}
+	api.Cache.SetWithTTL(k, required, 120)
+	return required, nil
+}
+
+// verifyMFA checks a second factor of a user loaded with its authentication data, and stores its updated MFA
+// configuration
+func (api *API) verifyMFA(u *sdk.User, v sdk.MFAVerification) error {
+	rp, err := mfa.NewRelyingParty(api.Config.URL.UI)
+	if err != nil {
+		return err
+	}
+	if err := mfa.Verify(api.Cache, u.Username, &u.Auth.MFA, v, rp, time.Now()); err != nil {
+		return err
+	}
+	return user.UpdateAuth(api.mustDB(), u.ID, u.Auth)
+}
+
+// loadMFAUser loads the current user with its authentication data
+func (api *API) loadMFAUser(ctx context.Context) (*sdk.User, error) {
+	if !api.isLocalAuth() {
+		return nil, sdk.NewErrorFrom(sdk.ErrNotImplemented, "two-factor authentication is only available for local accounts")
+	}
+	u, err := user.LoadUserAndAuth(api.mustDB(), deprecatedGetUser(ctx).Username)
+	if err != nil {
+		return nil, sdk.WrapError(err, "cannot load user %s", deprecatedGetUser(ctx).Username)
+	}
+	return u, nil
+}
+
+// saveMFAUser stores the MFA configuration of the user, and generates its recovery codes when it enables its first
+// second factor
+func (api *API) saveMFAUser(u *sdk.User) (sdk.MFARecoveryCodes, error) {
+	res := sdk.MFARecoveryCodes{Codes: []string{}}
+	if u.Auth.MFA.Enabled() && len(u.Auth.MFA.RecoveryCodes) == 0 {
+		codes, hashes, err := mfa.NewRecoveryCodes()
+		if err != nil {
+			return res, err
+		}
+		u.Auth.MFA.RecoveryCodes = hashes
+		res.Codes = codes
+	}
+	if !u.Auth.MFA.Enabled() {
+		u.Auth.MFA.RecoveryCodes = nil
+	}
+	if err := user.UpdateAuth(api.mustDB(), u.ID, u.Auth); err != nil {
+		return res, err
+	}
+	api.Cache.Delete(cache.Key("users", u.Username, "mfa"))
+	return res, nil
+}
+
+func (api *API) getUserMFAHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		u, err := api.loadMFAUser(ctx)
+		if err != nil {
+			return err
+		}
+		required, err := group.IsMFARequiredForUser(api.mustDB(), u.ID)
+		if err != nil {
+			return err
+		}
+		return service.WriteJSON(w, mfa.Status(u.Auth.MFA, required), http.StatusOK)
+	}
+}
+
+// postUserMFATOTPHandler starts the enrollment of an authenticator application, it is enabled once a first code
+// is checked
+func (api *API) postUserMFATOTPHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		u, err := api.loadMFAUser(ctx)
+		if err != nil {
+			return err
+		}
+		if u.Auth.MFA.TOTPEnabled {
+			return sdk.NewErrorFrom(sdk.ErrWrongRequest, "an authenticator application is already enabled")
+		}
+
+		secret, err := mfa.NewTOTPSecret()
+		if err != nil {
+			return err
+		}
+		u.Auth.MFA.TOTPSecret = secret
+		if err := user.UpdateAuth(api.mustDB(), u.ID, u.Auth); err != nil {
+			return err
+		}
+
+		return service.WriteJSON(w, sdk.TOTPEnrollment{
+			Secret: secret,
+			URL:    mfa.TOTPURL(totpIssuer, u.Username, secret),
+		}, http.StatusOK)
+	}
+}
+
+// putUserMFATOTPHandler enables the authenticator application with a first code
+func (api *API) putUserMFATOTPHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		var v sdk.MFAVerification
+		if err := service.UnmarshalBody(r, &v); err != nil {
+			return err
+		}
+
+		u, err := api.loadMFAUser(ctx)
+		if err != nil {
+			return err
+		}
+		if u.Auth.MFA.TOTPEnabled {
+			return sdk.NewErrorFrom(sdk.ErrWrongRequest, "an authenticator application is already enabled")
+		}
+		if u.Auth.MFA.TOTPSecret == "" {
+			return sdk.NewErrorFrom(sdk.ErrWrongRequest, "no pending enrollment of an authenticator application")
+		}
+
+		counter, ok := mfa.ValidateTOTP(u.Auth.MFA.TOTPSecret, v.TOTP, time.Now(), 0)
+		if !ok {
+			return sdk.NewErrorFrom(sdk.ErrMFAInvalid, "invalid TOTP code")
+		}
+		u.Auth.MFA.TOTPEnabled = true
+		u.Auth.MFA.TOTPLastCounter = counter
+
+		codes, err := api.saveMFAUser(u)
+		if err != nil {
+			return err
+		}
+		return service.WriteJSON(w, codes, http.StatusOK)
+	}
+}
+
+func (api *API) deleteUserMFATOTPHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		u, err := api.loadMFAUser(ctx)
+		if err != nil {
+			return err
+		}
+		u.Auth.MFA.TOTPEnabled = false
+		u.Auth.MFA.TOTPSecret = ""
+		u.Auth.MFA.TOTPLastCounter = 0
+		_, err = api.saveMFAUser(u)
+		return err
+	}
+}
+
+// postUserMFARecoveryCodesHandler replaces the recovery codes of the user
+func (api *API) postUserMFARecoveryCodesHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		u, err := api.loadMFAUser(ctx)
+		if err != nil {
+			return err
+		}
+		if !u.Auth.MFA.Enabled() {
+			return sdk.NewErrorFrom(sdk.ErrWrongRequest, "two-factor authentication is not enabled")
+		}
+		u.Auth.MFA.RecoveryCodes = nil
+		codes, err := api.saveMFAUser(u)
+		if err != nil {
+			return err
+		}
+		return service.WriteJSON(w, codes, http.StatusOK)
+	}
+}
+
+// webAuthnChallenge returns a new challenge to register or use a security key
+func (api *API) webAuthnChallenge(u *sdk.User) (sdk.WebAuthnChallenge, error) {
+	rp, err := mfa.NewRelyingParty(api.Config.URL.UI)
+	if err != nil {
+		return sdk.WebAuthnChallenge{}, err
+	}
+	challenge, err := mfa.NewChallenge(api.Cache, u.Username)
+	if err != nil {
+		return sdk.WebAuthnChallenge{}, err
+	}
+	return sdk.WebAuthnChallenge{
+		Challenge:     challenge,
+		RPID:          rp.ID,
+		UserID:        base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatInt(u.ID, 10))),
+		UserName:      u.Username,
+		CredentialIDs: mfa.CredentialIDs(u.Auth.MFA),
+		Timeout:       int64(mfa.WebAuthnTimeout / time.Millisecond),
+	}, nil
+}
+
+func (api *API) getUserMFAWebAuthnChallengeHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		u, err := api.loadMFAUser(ctx)
+		if err != nil {
+			return err
+		}
+		c, err := api.webAuthnChallenge(u)
+		if err != nil {
+			return err
+		}
+		return service.WriteJSON(w, c, http.StatusOK)
+	}
+}
+
+// postUserMFAWebAuthnHandler registers a security key with the response to the pending challenge
+func (api *API) postUserMFAWebAuthnHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		var reg sdk.WebAuthnRegistration
+		if err := service.UnmarshalBody(r, &reg); err != nil {
+			return err
+		}
+		if reg.Name == "" {
+			return sdk.NewErrorFrom(sdk.ErrWrongRequest, "name of the security key is mandatory")
+		}
+
+		u, err := api.loadMFAUser(ctx)
+		if err != nil {
+			return err
+		}
+		rp, err := mfa.NewRelyingParty(api.Config.URL.UI)
+		if err != nil {
+			return err
+		}
+		if _, err := mfa.Register(api.Cache, u.Username, &u.Auth.MFA, reg, rp); err != nil {
+			return err
+		}
+
+		codes, err := api.saveMFAUser(u)
+		if err != nil {
+			return err
+		}
+		return service.WriteJSON(w, codes, http.StatusOK)
+	}
+}
+
+func (api *API) deleteUserMFAWebAuthnHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		id := mux.Vars(r)["id"]
+
+		u, err := api.loadMFAUser(ctx)
+		if err != nil {
+			return err
+		}
+		creds := u.Auth.MFA.WebAuthnCredentials[:0]
+		for _, c := range u.Auth.MFA.WebAuthnCredentials {
+			if c.ID != id {
+				creds = append(creds, c)
+			}
+		}
+		if len(creds) == len(u.Auth.MFA.WebAuthnCredentials) {
+			return sdk.WithStack(sdk.ErrNotFound)
+		}
+		u.Auth.MFA.WebAuthnCredentials = creds
+		_, err = api.saveMFAUser(u)
+		return err
+	}
+}
+
+// postUserMFAVerifyHandler checks a second factor of the user, the session is then allowed to run sensitive
+// operations for some minutes
+func (api *API) postUserMFAVerifyHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		var v sdk.MFAVerification
+		if err := service.UnmarshalBody(r, &v); err != nil {
+			return err
+		}
+
+		u, err := api.loadMFAUser(ctx)
+		if err != nil {
+			return err
+		}
+		if !u.Auth.MFA.Enabled() {
+			return sdk.NewErrorFrom(sdk.ErrWrongRequest, "two-factor authentication is not enabled")
+		}
+		if err := api.verifyMFA(u, v); err != nil {
+			return err
+		}
+
+		session := getUserSession(ctx)
+		if session == "" {
+			session = r.Header.Get(sdk.SessionTokenHeader)
+		}
+		if session != "" {
+			api.Cache.SetWithTTL(cache.Key("mfa", "stepup", session), true, sdk.MFAStepUpTTL)
+		}
+		return nil
+	}
+}
+
+// postLoginWebAuthnChallengeHandler returns the challenge to answer with a security key to log in
+func (api *API) postLoginWebAuthnChallengeHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		var req sdk.UserLoginRequest
+		if err := service.UnmarshalBody(r, &req); err != nil {
+			return err
+		}
+		if !api.isLocalAuth() {
+			return sdk.NewErrorFrom(sdk.ErrNotImplemented, "two-factor authentication is only available for local accounts")
+		}
+
+		ok, err := api.Router.AuthDriver.Authentify(req.Username, req.Password)
+		if err != nil || !ok {
+			return sdk.WrapError(sdk.ErrInvalidUser, "Auth> Login failed: %s", req.Username)
+		}
+		u, err := user.LoadUserAndAuth(api.mustDB(), req.Username)
+		if err != nil {
+			return sdk.WrapError(sdk.ErrInvalidUser, "Auth> Login error %s: %s", req.Username, err)
+		}
+		if len(u.Auth.MFA.WebAuthnCredentials) == 0 {
+			return sdk.NewErrorFrom(sdk.ErrWrongRequest, "no security key registered")
+		}
+
+		c, err := api.webAuthnChallenge(u)
+		if err != nil {
+			return err
+		}
+		return service.WriteJSON(w, c, http.StatusOK)
+	}
+}
+
+func (api *API) getGroupMFAPolicyHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		g, err := group.LoadGroup(api.mustDB(), mux.Vars(r)["permGroupName"])
+		if err != nil {
+			return sdk.WrapError(err, "cannot load group %s", mux.Vars(r)["permGroupName"])
+		}
+		p, err := group.LoadMFAPolicy(api.mustDB(), g.ID)
+		if err != nil {
+			return err
+		}
+		return service.WriteJSON(w, p, http.StatusOK)
+	}
+}
+
+// putGroupMFAPolicyHandler sets the second factor policy of a group, the members without second factor have to
+// enable it before using CDS again
+func (api *API) putGroupMFAPolicyHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		var p sdk.MFAPolicy
+		if err := service.UnmarshalBody(r, &p); err != nil {
+			return err
+		}
+
+		g, err := group.LoadGroup(api.mustDB(), mux.Vars(r)["permGroupName"])
+		if err != nil {
+			return sdk.WrapError(err, "cannot load group %s", mux.Vars(r)["permGroupName"])
+		}
+		if err := group.UpsertMFAPolicy(api.mustDB(), g.ID, p); err != nil {
+			return err
+		}
+		api.Cache.DeleteAll(cache.Key("users", "*", "mfa"))
+		return service.WriteJSON(w, p, http.StatusOK)
+	}
+}
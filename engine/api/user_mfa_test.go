@@ -0,0 +1,97 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/engine/api/mfa"
+	"github.com/ovh/cds/engine/api/test/assets"
+	"github.com/ovh/cds/sdk"
+)
+
+func TestAPI_UserMFA(t *testing.T) {
+	api, db, router, end := newTestAPI(t)
+	defer end()
+
+	grp := sdk.Group{Name: sdk.RandomString(10)}
+	u, pass := assets.InsertLambdaUser(db, &grp)
+
+	// Enroll an authenticator application
+	uri := router.GetRoute("POST", api.postUserMFATOTPHandler, nil)
+	w := httptest.NewRecorder()
+	router.Mux.ServeHTTP(w, assets.NewAuthentifiedRequest(t, u, pass, "POST", uri, nil))
+	assert.Equal(t, 200, w.Code)
+	var enrollment sdk.TOTPEnrollment
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &enrollment))
+	assert.NotEmpty(t, enrollment.Secret)
+
+	code, err := mfa.TOTPCode(enrollment.Secret, time.Now().Unix()/30)
+	assert.NoError(t, err)
+	uri = router.GetRoute("PUT", api.putUserMFATOTPHandler, nil)
+	w = httptest.NewRecorder()
+	router.Mux.ServeHTTP(w, assets.NewAuthentifiedRequest(t, u, pass, "PUT", uri, sdk.MFAVerification{TOTP: code}))
+	assert.Equal(t, 200, w.Code)
+	var codes sdk.MFARecoveryCodes
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &codes))
+	assert.Len(t, codes.Codes, 10)
+
+	uri = router.GetRoute("GET", api.getUserMFAHandler, nil)
+	w = httptest.NewRecorder()
+	router.Mux.ServeHTTP(w, assets.NewAuthentifiedRequest(t, u, pass, "GET", uri, nil))
+	assert.Equal(t, 200, w.Code)
+	var status sdk.MFAStatus
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &status))
+	assert.True(t, status.TOTP)
+	assert.Equal(t, 10, status.RecoveryCodes)
+
+	// Sensitive operations need a step-up verification
+	uri = router.GetRoute("POST", api.postUserMFARecoveryCodesHandler, nil)
+	w = httptest.NewRecorder()
+	router.Mux.ServeHTTP(w, assets.NewAuthentifiedRequest(t, u, pass, "POST", uri, nil))
+	assert.Equal(t, 401, w.Code)
+
+	req := assets.NewAuthentifiedRequest(t, u, pass, "POST", uri, nil)
+	req.Header.Set(sdk.MFAHeader, "wrong-code")
+	w = httptest.NewRecorder()
+	router.Mux.ServeHTTP(w, req)
+	assert.Equal(t, 401, w.Code)
+
+	req = assets.NewAuthentifiedRequest(t, u, pass, "POST", uri, nil)
+	req.Header.Set(sdk.MFAHeader, codes.Codes[0])
+	w = httptest.NewRecorder()
+	router.Mux.ServeHTTP(w, req)
+	assert.Equal(t, 200, w.Code)
+
+	// The step-up verification is kept on the session
+	uri = router.GetRoute("DELETE", api.deleteUserMFATOTPHandler, nil)
+	w = httptest.NewRecorder()
+	router.Mux.ServeHTTP(w, assets.NewAuthentifiedRequest(t, u, pass, "DELETE", uri, nil))
+	assert.Equal(t, 204, w.Code)
+
+	// A group requires two-factor authentication for its members
+	admin, adminPass := assets.InsertAdminUser(db)
+	uri = router.GetRoute("PUT", api.putGroupMFAPolicyHandler, map[string]string{"permGroupName": grp.Name})
+	w = httptest.NewRecorder()
+	router.Mux.ServeHTTP(w, assets.NewAuthentifiedRequest(t, u, pass, "PUT", uri, sdk.MFAPolicy{Required: true}))
+	assert.Equal(t, 403, w.Code)
+	w = httptest.NewRecorder()
+	router.Mux.ServeHTTP(w, assets.NewAuthentifiedRequest(t, admin, adminPass, "PUT", uri, sdk.MFAPolicy{Required: true}))
+	assert.Equal(t, 200, w.Code)
+
+	uri = router.GetRoute("GET", api.getUsersHandler, nil)
+	w = httptest.NewRecorder()
+	router.Mux.ServeHTTP(w, assets.NewAuthentifiedRequest(t, u, pass, "GET", uri, nil))
+	assert.Equal(t, 403, w.Code)
+
+	uri = router.GetRoute("GET", api.getUserMFAHandler, nil)
+	w = httptest.NewRecorder()
+	router.Mux.ServeHTTP(w, assets.NewAuthentifiedRequest(t, u, pass, "GET", uri, nil))
+	assert.Equal(t, 200, w.Code)
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &status))
+	assert.False(t, status.TOTP)
+	assert.True(t, status.Required)
+}
@@ -0,0 +1,148 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/ovh/cds/engine/api/cache"
+	"github.com/ovh/cds/engine/api/repositoriesmanager"
+	"github.com/ovh/cds/engine/api/user"
+	"github.com/ovh/cds/engine/service"
+	"github.com/ovh/cds/sdk"
+)
+
+func (api *API) getUserVCSAccountsHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		u, err := user.LoadUserWithoutAuth(api.mustDB(), mux.Vars(r)["username"])
+		if err != nil {
+			return sdk.WrapError(sdk.ErrUserNotFound, "cannot load user: %v", err)
+		}
+
+		as, err := user.LoadVCSAccounts(api.mustDB(), u.ID)
+		if err != nil {
+			return err
+		}
+		return service.WriteJSON(w, as, http.StatusOK)
+	}
+}
+
+// postUserVCSAccountAuthorizeHandler starts the OAuth flow of a repositories manager to link an account to a user.
+// The account is read from the access token given at the end of the flow, so a user can only link its own accounts.
+// The commands written by this account in the comments of the pull requests are run with the permissions of the user.
+func (api *API) postUserVCSAccountAuthorizeHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		rmName := vars["vcsServer"]
+
+		u, err := user.LoadUserWithoutAuth(api.mustDB(), vars["username"])
+		if err != nil {
+			return sdk.WrapError(sdk.ErrUserNotFound, "cannot load user: %v", err)
+		}
+
+		vcsServer, err := repositoriesmanager.NewVCSServerConsumer(api.mustDB, api.Cache, rmName)
+		if err != nil {
+			return err
+		}
+		token, url, err := vcsServer.AuthorizeRedirect(ctx)
+		if err != nil {
+			return sdk.WrapError(sdk.ErrNoReposManagerAuth, "cannot start authorization on %s: %v", rmName, err)
+		}
+
+		// without project key, the callback of the flow links the account to the user
+		data := map[string]string{
+			"last_modified":        strconv.FormatInt(time.Now().Unix(), 10),
+			"repositories_manager": rmName,
+			"url":                  url,
+			"request_token":        token,
+			"username":             u.Username,
+		}
+		api.Cache.Set(cache.Key("reposmanager", "oauth", token), data)
+		return service.WriteJSON(w, data, http.StatusOK)
+	}
+}
+
+// postUserVCSAccountAuthorizeCallbackHandler ends the OAuth flow of the repositories managers which give a verifier to
+// the user instead of calling back the API
+func (api *API) postUserVCSAccountAuthorizeCallbackHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+
+		var tv map[string]string
+		if err := service.UnmarshalBody(r, &tv); err != nil {
+			return err
+		}
+		if tv["request_token"] == "" || tv["verifier"] == "" {
+			return sdk.NewErrorFrom(sdk.ErrWrongRequest, "request token and verifier are mandatory")
+		}
+
+		data := map[string]string{}
+		if !api.Cache.Get(cache.Key("reposmanager", "oauth", tv["request_token"]), &data) ||
+			data["project_key"] != "" || data["username"] != vars["username"] || data["repositories_manager"] != vars["vcsServer"] {
+			return sdk.WithStack(sdk.ErrForbidden)
+		}
+
+		a, err := api.linkUserVCSAccount(ctx, data, tv["request_token"], tv["verifier"])
+		if err != nil {
+			return err
+		}
+		return service.WriteJSON(w, a, http.StatusCreated)
+	}
+}
+
+// linkUserVCSAccount links the account of the access token given at the end of an OAuth flow to the user who started
+// the flow. The flow can only be used once.
+func (api *API) linkUserVCSAccount(ctx context.Context, data map[string]string, token, verifier string) (*sdk.UserVCSAccount, error) {
+	api.Cache.Delete(cache.Key("reposmanager", "oauth", token))
+	rmName := data["repositories_manager"]
+
+	u, err := user.LoadUserWithoutAuth(api.mustDB(), data["username"])
+	if err != nil {
+		return nil, sdk.WrapError(sdk.ErrUserNotFound, "cannot load user: %v", err)
+	}
+
+	vcsServer, err := repositoriesmanager.NewVCSServerConsumer(api.mustDB, api.Cache, rmName)
+	if err != nil {
+		return nil, err
+	}
+	accessToken, secret, err := vcsServer.AuthorizeToken(ctx, token, verifier)
+	if err != nil {
+		return nil, sdk.WrapError(sdk.ErrNoReposManagerClientAuth, "cannot get access token on %s: %v", rmName, err)
+	}
+	vcsUser, err := repositoriesmanager.CurrentUser(ctx, api.mustDB(), rmName, accessToken, secret)
+	if err != nil {
+		return nil, err
+	}
+
+	a := sdk.UserVCSAccount{UserID: u.ID, VCSServer: rmName, Username: vcsUser.Name}
+	if err := a.IsValid(); err != nil {
+		return nil, err
+	}
+	if err := user.InsertVCSAccount(api.mustDB(), &a); err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+func (api *API) deleteUserVCSAccountHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		id, err := requestVarInt(r, "id")
+		if err != nil {
+			return err
+		}
+
+		u, err := user.LoadUserWithoutAuth(api.mustDB(), mux.Vars(r)["username"])
+		if err != nil {
+			return sdk.WrapError(sdk.ErrUserNotFound, "cannot load user: %v", err)
+		}
+
+		a, err := user.LoadVCSAccountByID(api.mustDB(), u.ID, id)
+		if err != nil {
+			return err
+		}
+		return user.DeleteVCSAccount(api.mustDB(), a)
+	}
+}
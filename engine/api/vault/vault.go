@@ -0,0 +1,224 @@
+package vault
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-cleanhttp"
+	vault "github.com/hashicorp/vault/api"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// token is an authenticated vault token kept in memory by the API
+type token struct {
+	value     string
+	renewable bool
+	ttl       time.Duration
+	expire    time.Time
+}
+
+// renewAt returns the date from which the token should be renewed (at half of its ttl)
+func (t token) renewAt() time.Time {
+	return t.expire.Add(-t.ttl / 2)
+}
+
+var (
+	tokensMutex sync.Mutex
+	tokens      = map[string]token{}
+)
+
+type config struct {
+	url        string
+	authMethod string
+	token      string
+	roleID     string
+	secretID   string
+	mount      string
+	paths      []string
+}
+
+func newConfig(pi sdk.ProjectIntegration) (config, error) {
+	c := config{
+		url:        pi.Config[sdk.VaultConfigURL].Value,
+		authMethod: pi.Config[sdk.VaultConfigAuthMethod].Value,
+		token:      pi.Config[sdk.VaultConfigToken].Value,
+		roleID:     pi.Config[sdk.VaultConfigRoleID].Value,
+		secretID:   pi.Config[sdk.VaultConfigSecretID].Value,
+		mount:      strings.Trim(pi.Config[sdk.VaultConfigMount].Value, "/"),
+	}
+	if c.authMethod == "" {
+		c.authMethod = sdk.VaultAuthMethodToken
+	}
+	if c.mount == "" {
+		c.mount = "secret"
+	}
+	for _, p := range strings.FieldsFunc(pi.Config[sdk.VaultConfigPaths].Value, func(r rune) bool { return r == '\n' || r == ',' }) {
+		if p = strings.Trim(strings.TrimSpace(p), "/"); p != "" {
+			c.paths = append(c.paths, p)
+		}
+	}
+
+	if c.url == "" {
+		return c, sdk.NewErrorFrom(sdk.ErrWrongRequest, "missing vault url on integration %s", pi.Name)
+	}
+	switch c.authMethod {
+	case sdk.VaultAuthMethodToken:
+		if c.token == "" {
+			return c, sdk.NewErrorFrom(sdk.ErrWrongRequest, "missing vault token on integration %s", pi.Name)
+		}
+	case sdk.VaultAuthMethodAppRole:
+		if c.roleID == "" || c.secretID == "" {
+			return c, sdk.NewErrorFrom(sdk.ErrWrongRequest, "missing vault role id or secret id on integration %s", pi.Name)
+		}
+	default:
+		return c, sdk.NewErrorFrom(sdk.ErrWrongRequest, "unknown vault auth method %s on integration %s", c.authMethod, pi.Name)
+	}
+	return c, nil
+}
+
+// cacheKey identifies a token for a given configuration, so a configuration update invalidates the token
+func (c config) cacheKey(pi sdk.ProjectIntegration) string {
+	return fmt.Sprintf("%d/%s/%s/%s", pi.ID, c.url, c.authMethod, c.roleID)
+}
+
+// newClient returns a vault client authenticated with the token of the integration.
+// AppRole tokens are kept in memory and renewed when they reach half of their ttl, a new login is performed when they can't be renewed.
+func newClient(pi sdk.ProjectIntegration, c config) (*vault.Client, error) {
+	client, err := vault.NewClient(newVaultConfig(c.url))
+	if err != nil {
+		return nil, sdk.WrapError(err, "invalid vault url %s", c.url)
+	}
+	// the client must not use the VAULT_TOKEN of the API
+	client.ClearToken()
+
+	if c.authMethod == sdk.VaultAuthMethodToken {
+		client.SetToken(c.token)
+		return client, nil
+	}
+
+	// the calls to vault are done without the lock, only the tokens are read and stored under it
+	key := c.cacheKey(pi)
+	tokensMutex.Lock()
+	t, has := tokens[key]
+	tokensMutex.Unlock()
+
+	now := time.Now()
+	if has && now.Before(t.renewAt()) {
+		client.SetToken(t.value)
+		return client, nil
+	}
+
+	if has && t.renewable && now.Before(t.expire) {
+		client.SetToken(t.value)
+		s, err := client.Auth().Token().RenewSelf(int(t.ttl.Seconds()))
+		if err == nil && s != nil && s.Auth != nil {
+			setToken(key, newToken(s.Auth))
+			return client, nil
+		}
+		log.Warning("vault> unable to renew token of integration %s: %v", pi.Name, err)
+	}
+
+	s, err := client.Logical().Write("auth/approle/login", map[string]interface{}{
+		"role_id":   c.roleID,
+		"secret_id": c.secretID,
+	})
+	if err != nil {
+		deleteToken(key)
+		return nil, sdk.WrapError(err, "unable to login on vault with approle for integration %s", pi.Name)
+	}
+	if s == nil || s.Auth == nil {
+		deleteToken(key)
+		return nil, sdk.WithStack(fmt.Errorf("no auth information returned by vault for integration %s", pi.Name))
+	}
+	setToken(key, newToken(s.Auth))
+	client.SetToken(s.Auth.ClientToken)
+	return client, nil
+}
+
+// newVaultConfig returns the configuration of a client of the vault of an integration. Unlike vault.DefaultConfig,
+// the VAULT_* environment variables of the API are ignored: they configure the vault of the API.
+func newVaultConfig(url string) *vault.Config {
+	httpClient := cleanhttp.DefaultClient()
+	httpClient.Timeout = 60 * time.Second
+	transport := httpClient.Transport.(*http.Transport)
+	transport.TLSHandshakeTimeout = 10 * time.Second
+	transport.TLSClientConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	return &vault.Config{Address: url, HttpClient: httpClient}
+}
+
+func setToken(key string, t token) {
+	tokensMutex.Lock()
+	tokens[key] = t
+	tokensMutex.Unlock()
+}
+
+func deleteToken(key string) {
+	tokensMutex.Lock()
+	delete(tokens, key)
+	tokensMutex.Unlock()
+}
+
+func newToken(auth *vault.SecretAuth) token {
+	ttl := time.Duration(auth.LeaseDuration) * time.Second
+	return token{
+		value:     auth.ClientToken,
+		renewable: auth.Renewable,
+		ttl:       ttl,
+		expire:    time.Now().Add(ttl),
+	}
+}
+
+// VariablePrefix returns the prefix of the variables resolved from a vault integration
+func VariablePrefix(integrationName string) string {
+	return "cds.vault." + integrationName + "."
+}
+
+// LoadSecrets reads all the KV v2 paths configured on the integration and returns their values as secret variables
+// named cds.vault.<integration name>.<key>. If a key is defined on several paths, the last path wins.
+func LoadSecrets(pi sdk.ProjectIntegration) ([]sdk.Variable, error) {
+	c, err := newConfig(pi)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := newClient(pi, c)
+	if err != nil {
+		return nil, err
+	}
+
+	values := map[string]string{}
+	for _, p := range c.paths {
+		s, err := client.Logical().Read(c.mount + "/data/" + p)
+		if err != nil {
+			return nil, sdk.WrapError(err, "unable to read %s on vault for integration %s", p, pi.Name)
+		}
+		if s == nil {
+			log.Warning("vault> no value found at %s for integration %s", p, pi.Name)
+			continue
+		}
+		data, ok := s.Data["data"].(map[string]interface{})
+		if !ok {
+			log.Warning("vault> no kv v2 data found at %s for integration %s", p, pi.Name)
+			continue
+		}
+		for k, v := range data {
+			values[k] = fmt.Sprintf("%v", v)
+		}
+	}
+
+	vars := make([]sdk.Variable, 0, len(values))
+	for k, v := range values {
+		vars = append(vars, sdk.Variable{
+			Name:  VariablePrefix(pi.Name) + k,
+			Type:  sdk.SecretVariable,
+			Value: v,
+		})
+	}
+	return vars, nil
+}
@@ -0,0 +1,72 @@
+package vault
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func TestNewConfig(t *testing.T) {
+	pi := sdk.ProjectIntegration{
+		Name: "my-vault",
+		Config: sdk.IntegrationConfig{
+			sdk.VaultConfigURL:        {Value: "https://vault.local:8200"},
+			sdk.VaultConfigAuthMethod: {Value: sdk.VaultAuthMethodAppRole},
+			sdk.VaultConfigRoleID:     {Value: "role"},
+			sdk.VaultConfigSecretID:   {Value: "secret"},
+			sdk.VaultConfigMount:      {Value: "/kv/"},
+			sdk.VaultConfigPaths:      {Value: "app/prod\n /app/common/ ,,\n"},
+		},
+	}
+
+	c, err := newConfig(pi)
+	assert.NoError(t, err)
+	assert.Equal(t, "kv", c.mount)
+	assert.Equal(t, []string{"app/prod", "app/common"}, c.paths)
+
+	pi.Config[sdk.VaultConfigSecretID] = sdk.IntegrationConfigValue{}
+	_, err = newConfig(pi)
+	assert.Error(t, err)
+
+	pi.Config[sdk.VaultConfigAuthMethod] = sdk.IntegrationConfigValue{}
+	pi.Config[sdk.VaultConfigMount] = sdk.IntegrationConfigValue{}
+	_, err = newConfig(pi)
+	assert.Error(t, err)
+
+	pi.Config[sdk.VaultConfigToken] = sdk.IntegrationConfigValue{Value: "s.token"}
+	c, err = newConfig(pi)
+	assert.NoError(t, err)
+	assert.Equal(t, sdk.VaultAuthMethodToken, c.authMethod)
+	assert.Equal(t, "secret", c.mount)
+}
+
+func TestNewClientIgnoresEnvironment(t *testing.T) {
+	os.Setenv("VAULT_ADDR", "https://api-vault.local:8200")
+	os.Setenv("VAULT_TOKEN", "s.api-token")
+	defer os.Unsetenv("VAULT_ADDR")
+	defer os.Unsetenv("VAULT_TOKEN")
+
+	var loginToken string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		loginToken = r.Header.Get("X-Vault-Token")
+		w.Write([]byte(`{"auth":{"client_token":"s.approle","lease_duration":3600,"renewable":true}}`))
+	}))
+	defer srv.Close()
+
+	pi := sdk.ProjectIntegration{ID: 1, Name: "my-vault"}
+	client, err := newClient(pi, config{url: "https://vault.local:8200", authMethod: sdk.VaultAuthMethodToken, token: "s.token"})
+	assert.NoError(t, err)
+	assert.Equal(t, "https://vault.local:8200", client.Address())
+	assert.Equal(t, "s.token", client.Token())
+
+	// the approle login is not sent with the token of the api
+	client, err = newClient(pi, config{url: srv.URL, authMethod: sdk.VaultAuthMethodAppRole, roleID: "role", secretID: "secret"})
+	assert.NoError(t, err)
+	assert.Equal(t, "", loginToken)
+	assert.Equal(t, "s.approle", client.Token())
+}
@@ -25,6 +25,10 @@ func (api *API) registerWorkerHandler() service.Handler {
 			return sdk.WrapError(err, "Unable to parse registration form")
 		}
 
+		if params.Pool != "" && params.HatcheryName != "" {
+			return sdk.NewErrorFrom(sdk.ErrWrongRequest, "a worker spawned by a hatchery cannot join a pool")
+		}
+
 		// Check that hatchery exists
 		var hatch *sdk.Service
 		if params.HatcheryName != "" {
@@ -36,18 +40,24 @@ func (api *API) registerWorkerHandler() service.Handler {
 		}
 
 		// Try to register worker
-		worker, err := worker.RegisterWorker(api.mustDB(), api.Cache, params.Name, params.Token, params.ModelID, hatch, params.BinaryCapabilities, params.OS, params.Arch)
+		wk, err := worker.RegisterWorker(api.mustDB(), api.Cache, params.Name, params.Token, params.ModelID, hatch, params.BinaryCapabilities, params.OS, params.Arch)
 		if err != nil {
 			err = sdk.NewError(sdk.ErrUnauthorized, err)
 			return sdk.WrapError(err, "[%s] Registering failed", params.Name)
 		}
 
-		worker.Uptodate = params.Version == sdk.VERSION
+		if params.Pool != "" {
+			if err := worker.SetPool(api.mustDB(), wk, params.Pool, params.BinaryCapabilities); err != nil {
+				return err
+			}
+		}
+
+		wk.Uptodate = params.Version == sdk.VERSION
 
-		log.Debug("New worker: [%s] - %s", worker.ID, worker.Name)
+		log.Debug("New worker: [%s] - %s", wk.ID, wk.Name)
 
 		// Return worker info to worker itself
-		return service.WriteJSON(w, worker, http.StatusOK)
+		return service.WriteJSON(w, wk, http.StatusOK)
 	}
 }
 
@@ -110,6 +120,10 @@ func (api *API) refreshWorkerHandler() service.Handler {
 		if err := worker.RefreshWorker(api.mustDB(), getWorker(ctx)); err != nil && (sdk.Cause(err) != sql.ErrNoRows || sdk.Cause(err) != worker.ErrNoWorker) {
 			return sdk.WrapError(err, "cannot refresh last beat of %s", getWorker(ctx).ID)
 		}
+		// a draining worker finishes its job and exits
+		if getWorker(ctx).Draining {
+			return sdk.WithStack(sdk.ErrWorkerDraining)
+		}
 		return nil
 	}
 }
@@ -218,3 +232,32 @@ func DisableWorker(db *gorp.DbMap, id string) error {
 
 	return tx.Commit()
 }
+
+func (api *API) getAdminWorkerPoolsHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		workers, err := worker.LoadPoolWorkers(api.mustDB(), r.FormValue("pool"))
+		if err != nil {
+			return err
+		}
+		return service.WriteJSON(w, workers, http.StatusOK)
+	}
+}
+
+func (api *API) postAdminWorkerPoolDrainHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		pool := mux.Vars(r)["pool"]
+		if err := r.ParseForm(); err != nil {
+			return sdk.WrapError(sdk.ErrWrongRequest, "cannot parse form: %v", err)
+		}
+
+		workers, err := worker.DrainPoolWorkers(api.mustDB(), pool, r.Form["worker"])
+		if err != nil {
+			return err
+		}
+		// the workers are reloaded with their draining status
+		for _, wk := range workers {
+			api.Cache.Delete(cache.Key("worker", wk.ID))
+		}
+		return service.WriteJSON(w, workers, http.StatusOK)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 
 	"github.com/go-gorp/gorp"
+	"github.com/lib/pq"
 
 	"github.com/ovh/cds/engine/api/database/gorpmapping"
 	"github.com/ovh/cds/engine/api/group"
@@ -30,9 +31,15 @@ func (m *WorkerModel) PostInsert(s gorp.SqlExecutor) error {
 	var modelBtes []byte
 	switch m.Type {
 	case sdk.Docker:
-		m.ModelDocker.Envs = mergeWithDefaultEnvs(m.ModelDocker.Envs)
+		// the default envs of a model extending a base model are inherited from it
+		if m.ParentID == 0 {
+			m.ModelDocker.Envs = mergeWithDefaultEnvs(m.ModelDocker.Envs)
+		}
+		// The credentials of the registry are resolved when the models are given to the hatcheries, never stored
+		modelDocker := m.ModelDocker
+		modelDocker.RegistryAuth = nil
 		var err error
-		if modelBtes, err = json.Marshal(m.ModelDocker); err != nil {
+		if modelBtes, err = json.Marshal(modelDocker); err != nil {
 			return err
 		}
 	default:
@@ -100,12 +107,29 @@ func (m *WorkerModel) PostSelect(s gorp.SqlExecutor) error {
 
 	//Load created_by
 	m.CreatedBy = sdk.User{}
-	var createdBy, model, registeredOS, registeredArch, lastSpawnErr, lastSpawnErrLogs sql.NullString
-	err := s.QueryRow("select created_by, model, registered_os, registered_arch, last_spawn_err, last_spawn_err_log from worker_model where id = $1", m.ID).Scan(&createdBy, &model, &registeredOS, &registeredArch, &lastSpawnErr, &lastSpawnErrLogs)
+	var createdBy, model, registeredOS, registeredArch, lastSpawnErr, lastSpawnErrLogs, parent sql.NullString
+	var nbJobs sql.NullInt64
+	var lastUsed pq.NullTime
+	err := s.QueryRow(`select created_by, model, registered_os, registered_arch, last_spawn_err, last_spawn_err_log,
+		(select name from worker_model parent where parent.id = worker_model.parent_id),
+		worker_model_usage.jobs, worker_model_usage.last_used
+		from worker_model
+		left join worker_model_usage on worker_model_usage.worker_model_id = worker_model.id
+		where worker_model.id = $1`, m.ID).Scan(&createdBy, &model, &registeredOS, &registeredArch, &lastSpawnErr, &lastSpawnErrLogs, &parent, &nbJobs, &lastUsed)
 	if err != nil {
 		return sdk.WrapError(err, "unable to load created_by, model, registered_os, registered_arch")
 	}
 
+	if parent.Valid {
+		m.Parent = parent.String
+	}
+
+	m.NbJobs = nbJobs.Int64
+	m.LastUsed = nil
+	if lastUsed.Valid {
+		m.LastUsed = &lastUsed.Time
+	}
+
 	if registeredOS.Valid {
 		m.RegisteredOS = registeredOS.String
 	}
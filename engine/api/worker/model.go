@@ -38,6 +38,8 @@ const modelColumns = `
 	worker_model.nb_spawn_err,
 	worker_model.date_last_spawn_err,
 	worker_model.is_deprecated,
+	worker_model.organization_id,
+	worker_model.parent_id,
 	"group".name as groupname`
 
 const (
@@ -185,6 +187,8 @@ func LoadAndLockWorkerModelByID(db gorp.SqlExecutor, ID int64) (*sdk.Model, erro
 		worker_model.nb_spawn_err,
 		worker_model.date_last_spawn_err,
 		worker_model.is_deprecated,
+		worker_model.organization_id,
+		worker_model.parent_id,
 		"group".name as groupname
 	FROM worker_model
 		JOIN "group" on worker_model.group_id = "group".id and worker_model.id = $1 FOR UPDATE NOWAIT`
@@ -272,6 +276,9 @@ func LoadWorkerModelsUsableOnGroup(db gorp.SqlExecutor, store cache.Store, group
 		}
 		models = append(models, sdk.Model(ms[i]))
 	}
+	if err := ResolveModelsParent(db, models); err != nil {
+		return nil, err
+	}
 
 	store.SetWithTTL(key, models, modelsCacheTTLInSeconds)
 
@@ -55,9 +55,17 @@ currentUGroup:
 		return nil, sdk.ErrWorkerModelNoAdmin
 	}
 
+	if existingWm, err := LoadWorkerModelByName(db, sdkWm.Name); err == nil && force {
+		sdkWm.ID = existingWm.ID
+	}
+	base, err := LoadModelParent(db, u, &sdkWm)
+	if err != nil {
+		return nil, err
+	}
+
 	switch sdkWm.Type {
 	case sdk.Docker:
-		if sdkWm.ModelDocker.Image == "" {
+		if sdkWm.Inherit(base).ModelDocker.Image == "" {
 			return nil, sdk.NewErrorFrom(sdk.ErrWrongRequest, "Invalid worker image")
 		}
 		if !u.Admin && !sdkWm.Restricted {
@@ -70,11 +78,14 @@ currentUGroup:
 			sdkWm.ModelDocker.Shell = modelPattern.Model.Shell
 			sdkWm.ModelDocker.Envs = modelPattern.Model.Envs
 		}
-		if sdkWm.ModelDocker.Cmd == "" || sdkWm.ModelDocker.Shell == "" {
+		if resolved := sdkWm.Inherit(base); resolved.ModelDocker.Cmd == "" || resolved.ModelDocker.Shell == "" {
 			return nil, sdk.NewErrorFrom(sdk.ErrWrongRequest, "Invalid worker command or invalid shell command")
 		}
+		if err := CheckModelRegistry(db, sdkWm.ModelDocker.Registry); err != nil {
+			return nil, err
+		}
 	default:
-		if sdkWm.ModelVirtualMachine.Image == "" {
+		if sdkWm.Inherit(base).ModelVirtualMachine.Image == "" {
 			return nil, sdk.NewErrorFrom(sdk.ErrWrongRequest, "Invalid worker image: cannot be empty")
 		}
 		if !u.Admin && !sdkWm.Restricted {
@@ -88,7 +99,7 @@ currentUGroup:
 			sdkWm.ModelVirtualMachine.PostCmd = modelPattern.Model.PostCmd
 		}
 
-		if sdkWm.ModelVirtualMachine.Cmd == "" {
+		if sdkWm.Inherit(base).ModelVirtualMachine.Cmd == "" {
 			return nil, sdk.NewErrorFrom(sdk.ErrWrongRequest, "Invalid worker command: Cannot be empty")
 		}
 	}
@@ -0,0 +1,102 @@
+package worker
+
+import (
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/group"
+	"github.com/ovh/cds/sdk"
+)
+
+// LoadModelParent loads the base model extended by a worker model and sets its parent id. The base model must have
+// the same type, must not extend another model and must be official or owned by a group of the user. It returns nil
+// if the worker model does not extend a base model.
+func LoadModelParent(db gorp.SqlExecutor, u *sdk.User, m *sdk.Model) (*sdk.Model, error) {
+	m.ParentID = 0
+	if m.Parent == "" {
+		return nil, nil
+	}
+
+	base, err := LoadWorkerModelByName(db, m.Parent)
+	if err != nil {
+		if sdk.ErrorIs(err, sdk.ErrNoWorkerModel) {
+			return nil, sdk.NewErrorFrom(sdk.ErrWrongRequest, "unknown base worker model %s", m.Parent)
+		}
+		return nil, sdk.WrapError(err, "cannot load worker model %s", m.Parent)
+	}
+	if base.ID == m.ID || base.Name == m.Name {
+		return nil, sdk.NewErrorFrom(sdk.ErrWrongRequest, "a worker model cannot extend itself")
+	}
+	if base.ParentID != 0 {
+		return nil, sdk.NewErrorFrom(sdk.ErrWrongRequest, "worker model %s extends %s, it cannot be a base model", base.Name, base.Parent)
+	}
+	if base.Type != m.Type {
+		return nil, sdk.NewErrorFrom(sdk.ErrWrongRequest, "worker model %s is a %s model, not a %s model", base.Name, base.Type, m.Type)
+	}
+	if !u.Admin && base.GroupID != group.SharedInfraGroup.ID {
+		var member bool
+		for _, g := range u.Groups {
+			if g.ID == base.GroupID {
+				member = true
+				break
+			}
+		}
+		if !member {
+			return nil, sdk.NewErrorFrom(sdk.ErrForbidden, "worker model %s is not official and not owned by your groups", base.Name)
+		}
+	}
+	if m.ID != 0 {
+		n, err := countModelVariants(db, m.ID)
+		if err != nil {
+			return nil, err
+		}
+		if n > 0 {
+			return nil, sdk.NewErrorFrom(sdk.ErrWrongRequest, "worker model %s is the base of %d worker models, it cannot extend another one", m.Name, n)
+		}
+	}
+
+	m.ParentID = base.ID
+	return base, nil
+}
+
+// CheckModelVariants returns an error if worker models extend the given one
+func CheckModelVariants(db gorp.SqlExecutor, m *sdk.Model) error {
+	n, err := countModelVariants(db, m.ID)
+	if err != nil {
+		return err
+	}
+	if n > 0 {
+		return sdk.NewErrorFrom(sdk.ErrForbidden, "worker model %s is the base of %d worker models", m.Name, n)
+	}
+	return nil
+}
+
+func countModelVariants(db gorp.SqlExecutor, id int64) (int64, error) {
+	n, err := db.SelectInt("SELECT COUNT(id) FROM worker_model WHERE parent_id = $1", id)
+	if err != nil {
+		return 0, sdk.WrapError(err, "cannot count the worker models extending worker model %d", id)
+	}
+	return n, nil
+}
+
+// ResolveModelsParent gives to the worker models extending a base model the fields they do not override. It is done
+// when the models are given to the hatcheries, so the variants follow the updates of their base.
+func ResolveModelsParent(db gorp.SqlExecutor, models []sdk.Model) error {
+	bases := map[int64]*sdk.Model{}
+	for i := range models {
+		m := &models[i]
+		if m.ParentID == 0 {
+			continue
+		}
+		base, has := bases[m.ParentID]
+		if !has {
+			var err error
+			base, err = LoadWorkerModelByID(db, m.ParentID)
+			if err != nil {
+				return sdk.WrapError(err, "cannot load base model of worker model %s", m.Name)
+			}
+			bases[m.ParentID] = base
+		}
+		*m = m.Inherit(base)
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package worker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/engine/api/bootstrap"
+	"github.com/ovh/cds/engine/api/test"
+	"github.com/ovh/cds/sdk"
+)
+
+func TestLoadModelParent(t *testing.T) {
+	db, _, end := test.SetupPG(t, bootstrap.InitiliazeDB)
+	defer end()
+	deleteAllWorkerModel(t, db)
+
+	g := insertGroup(t, db)
+	base := insertWorkerModel(t, db, "golden", g.ID)
+	u := &sdk.User{Admin: true}
+
+	variant := sdk.Model{Name: "golden-go", Type: sdk.Docker, Parent: "golden", GroupID: g.ID,
+		ModelDocker: sdk.ModelDocker{Envs: map[string]string{"GOPATH": "/go"}}}
+	b, err := LoadModelParent(db, u, &variant)
+	test.NoError(t, err)
+	assert.Equal(t, base.ID, b.ID)
+	assert.Equal(t, base.ID, variant.ParentID)
+	test.NoError(t, InsertWorkerModel(db, &variant))
+
+	loaded, err := LoadWorkerModelByName(db, "golden-go")
+	test.NoError(t, err)
+	assert.Equal(t, "golden", loaded.Parent)
+	assert.Equal(t, "", loaded.ModelDocker.Image)
+	ms := []sdk.Model{*loaded}
+	test.NoError(t, ResolveModelsParent(db, ms))
+	assert.Equal(t, "foo/bar:3.4", ms[0].ModelDocker.Image)
+	assert.Equal(t, "/go", ms[0].ModelDocker.Envs["GOPATH"])
+
+	// a variant cannot be a base, a base cannot extend another model
+	m := sdk.Model{Name: "golden-go-1.12", Type: sdk.Docker, Parent: "golden-go"}
+	_, err = LoadModelParent(db, u, &m)
+	assert.Error(t, err)
+	_, err = LoadModelParent(db, u, &sdk.Model{ID: base.ID, Name: "golden", Type: sdk.Docker, Parent: "golden-go"})
+	assert.Error(t, err)
+	_, err = LoadModelParent(db, u, &sdk.Model{Name: "vm", Type: sdk.Openstack, Parent: "golden"})
+	assert.Error(t, err)
+	_, err = LoadModelParent(db, &sdk.User{}, &sdk.Model{Name: "other", Type: sdk.Docker, Parent: "golden"})
+	assert.Error(t, err)
+	assert.Error(t, CheckModelVariants(db, base))
+}
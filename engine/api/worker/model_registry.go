@@ -0,0 +1,55 @@
+package worker
+
+import (
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/integration"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// CheckModelRegistry checks that the registry of a docker worker model is a public configuration of the
+// Docker Registry integration model
+func CheckModelRegistry(db gorp.SqlExecutor, registry string) error {
+	if registry == "" {
+		return nil
+	}
+	m, err := integration.LoadModelByName(db, sdk.DockerRegistryIntegrationModel, false)
+	if err != nil {
+		return err
+	}
+	if _, has := m.PublicConfigurations[registry]; !has {
+		return sdk.NewErrorFrom(sdk.ErrWrongRequest, "unknown registry %s, it should be a public configuration of the %s integration", registry, sdk.DockerRegistryIntegrationModel)
+	}
+	return nil
+}
+
+// LoadModelsRegistryAuth gives to the docker worker models the credentials of their registry, read from the public
+// configurations of the Docker Registry integration model. The credentials are only given to the hatcheries.
+func LoadModelsRegistryAuth(db gorp.SqlExecutor, models []sdk.Model) error {
+	var registryModel *sdk.IntegrationModel
+	for i := range models {
+		m := &models[i]
+		if m.Type != sdk.Docker || m.ModelDocker.Registry == "" {
+			continue
+		}
+		if registryModel == nil {
+			rm, err := integration.LoadModelByName(db, sdk.DockerRegistryIntegrationModel, true)
+			if err != nil {
+				return err
+			}
+			registryModel = &rm
+		}
+		cfg, has := registryModel.PublicConfigurations[m.ModelDocker.Registry]
+		if !has {
+			log.Warning("LoadModelsRegistryAuth> registry %s of worker model %s not found", m.ModelDocker.Registry, m.Name)
+			continue
+		}
+		m.ModelDocker.RegistryAuth = &sdk.ModelDockerRegistryAuth{
+			URL:      cfg[sdk.DockerRegistryConfigURL].Value,
+			Username: cfg[sdk.DockerRegistryConfigUsername].Value,
+			Password: cfg[sdk.DockerRegistryConfigPassword].Value,
+		}
+	}
+	return nil
+}
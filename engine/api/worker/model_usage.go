@@ -0,0 +1,47 @@
+package worker
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// UpdateModelUsage counts a job taken by a worker of a worker model and updates its last use
+func UpdateModelUsage(db gorp.SqlExecutor, modelID int64) error {
+	query := `INSERT INTO worker_model_usage (worker_model_id, jobs, last_used) VALUES ($1, 1, $2)
+	ON CONFLICT (worker_model_id) DO UPDATE SET jobs = worker_model_usage.jobs + 1, last_used = $2`
+	if _, err := db.Exec(query, modelID, time.Now()); err != nil {
+		return sdk.WrapError(err, "unable to update usage of worker model %d", modelID)
+	}
+	return nil
+}
+
+// LoadUnusedWorkerModels returns the worker models which have run no job since the given date, and have not been
+// modified since then, the least recently used first
+func LoadUnusedWorkerModels(db gorp.SqlExecutor, since time.Time) ([]sdk.Model, error) {
+	wms := []dbResultWMS{}
+	query := fmt.Sprintf(`SELECT %s
+	FROM worker_model
+	JOIN "group" ON worker_model.group_id = "group".id
+	LEFT JOIN worker_model_usage ON worker_model_usage.worker_model_id = worker_model.id
+	WHERE (worker_model_usage.last_used IS NULL OR worker_model_usage.last_used < $1)
+	AND worker_model.user_last_modified < $1`, modelColumns)
+	if _, err := db.Select(&wms, query, since); err != nil {
+		return nil, sdk.WrapError(err, "unable to load worker models unused since %s", since)
+	}
+	models, err := scanWorkerModels(db, wms)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(models, func(i, j int) bool {
+		if models[i].LastUsed == nil || models[j].LastUsed == nil {
+			return models[i].LastUsed == nil && models[j].LastUsed != nil
+		}
+		return models[i].LastUsed.Before(*models[j].LastUsed)
+	})
+	return models, nil
+}
@@ -0,0 +1,41 @@
+package worker
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/engine/api/bootstrap"
+	"github.com/ovh/cds/engine/api/test"
+)
+
+func TestLoadUnusedWorkerModels(t *testing.T) {
+	db, _, end := test.SetupPG(t, bootstrap.InitiliazeDB)
+	defer end()
+	deleteAllWorkerModel(t, db)
+
+	g := insertGroup(t, db)
+	used := insertWorkerModel(t, db, "used", g.ID)
+	insertWorkerModel(t, db, "never-used", g.ID)
+
+	test.NoError(t, UpdateModelUsage(db, used.ID))
+	test.NoError(t, UpdateModelUsage(db, used.ID))
+
+	loaded, err := LoadWorkerModelByID(db, used.ID)
+	test.NoError(t, err)
+	assert.Equal(t, int64(2), loaded.NbJobs)
+	assert.NotNil(t, loaded.LastUsed)
+
+	models, err := LoadUnusedWorkerModels(db, time.Now().Add(time.Hour))
+	test.NoError(t, err)
+	if assert.Len(t, models, 2) {
+		assert.Equal(t, "never-used", models[0].Name)
+		assert.Equal(t, "used", models[1].Name)
+	}
+
+	// the models have been used or modified in the last hour
+	models, err = LoadUnusedWorkerModels(db, time.Now().Add(-time.Hour))
+	test.NoError(t, err)
+	assert.Len(t, models, 0)
+}
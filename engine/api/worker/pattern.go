@@ -155,6 +155,18 @@ chmod +x worker
 				},
 			},
 		},
+		{
+			patternType: sdk.AWS,
+			patternModel: sdk.ModelPattern{
+				Type: sdk.AWS,
+				Name: "basic_debian",
+				Model: sdk.ModelCmds{
+					PreCmd:  preCmdOs,
+					Cmd:     "./worker",
+					PostCmd: "sudo shutdown -h now",
+				},
+			},
+		},
 		{
 			patternType: sdk.VSphere,
 			patternModel: sdk.ModelPattern{
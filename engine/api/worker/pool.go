@@ -0,0 +1,89 @@
+package worker
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// SetPool records the pool of a worker registered by itself on a host, with the capabilities it declared
+func SetPool(db gorp.SqlExecutor, w *sdk.Worker, pool string, capabilities []string) error {
+	capas, err := json.Marshal(capabilities)
+	if err != nil {
+		return sdk.WithStack(err)
+	}
+	if _, err := db.Exec("UPDATE worker SET pool = $1, capabilities = $2 WHERE id = $3", pool, capas, w.ID); err != nil {
+		return sdk.WrapError(err, "cannot set pool of worker %s", w.Name)
+	}
+	w.Pool = pool
+	w.Capabilities = capabilities
+	return nil
+}
+
+// LoadPoolWorkers loads the workers of the pools, or of the given pool, sorted by pool and name
+func LoadPoolWorkers(db gorp.SqlExecutor, pool string) ([]sdk.Worker, error) {
+	query := `SELECT id, name, last_beat, group_id, status, action_build_id, pool, capabilities, draining FROM worker
+	WHERE pool <> '' AND ($1 = '' OR pool = $1)
+	ORDER BY pool, name`
+	rows, err := db.Query(query, pool)
+	if err != nil {
+		return nil, sdk.WrapError(err, "cannot load workers of pool %s", pool)
+	}
+	defer rows.Close()
+
+	ws := []sdk.Worker{}
+	for rows.Next() {
+		var w sdk.Worker
+		var status string
+		var jobID *int64
+		var capas []byte
+		if err := rows.Scan(&w.ID, &w.Name, &w.LastBeat, &w.GroupID, &status, &jobID, &w.Pool, &capas, &w.Draining); err != nil {
+			return nil, sdk.WrapError(err, "cannot scan worker")
+		}
+		w.Status = sdk.StatusFromString(status)
+		if jobID != nil {
+			w.ActionBuildID = *jobID
+		}
+		if len(capas) > 0 {
+			if err := json.Unmarshal(capas, &w.Capabilities); err != nil {
+				return nil, sdk.WrapError(err, "cannot unmarshal capabilities of worker %s", w.Name)
+			}
+		}
+		ws = append(ws, w)
+	}
+	return ws, nil
+}
+
+// DrainPoolWorkers drains the workers of a pool, or only the given ones: they finish their current job, and exit instead
+// of taking a new one. It returns the drained workers.
+func DrainPoolWorkers(db gorp.SqlExecutor, pool string, names []string) ([]sdk.Worker, error) {
+	ws, err := LoadPoolWorkers(db, pool)
+	if err != nil {
+		return nil, err
+	}
+	if len(ws) == 0 {
+		return nil, sdk.NewErrorFrom(sdk.ErrNotFound, "no worker in pool %s", pool)
+	}
+
+	drained := []sdk.Worker{}
+	for _, w := range ws {
+		if len(names) > 0 && !sdk.IsInArray(w.Name, names) {
+			continue
+		}
+		drained = append(drained, w)
+	}
+	if len(drained) == 0 {
+		return nil, sdk.NewErrorFrom(sdk.ErrNotFound, "no worker %s in pool %s", strings.Join(names, ", "), pool)
+	}
+
+	for i := range drained {
+		if _, err := db.Exec("UPDATE worker SET draining = true WHERE id = $1", drained[i].ID); err != nil {
+			return nil, sdk.WrapError(err, "cannot drain worker %s", drained[i].Name)
+		}
+		drained[i].Draining = true
+	}
+	return drained, nil
+}
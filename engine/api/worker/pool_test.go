@@ -0,0 +1,67 @@
+package worker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/engine/api/bootstrap"
+	"github.com/ovh/cds/engine/api/test"
+	"github.com/ovh/cds/sdk"
+)
+
+func TestDrainPoolWorkers(t *testing.T) {
+	db, _, end := test.SetupPG(t, bootstrap.InitiliazeDB)
+	defer end()
+
+	workers, err := LoadWorkers(db, "")
+	test.NoError(t, err)
+	for _, w := range workers {
+		DeleteWorker(db, w.ID)
+	}
+
+	for _, w := range []*sdk.Worker{
+		{ID: "lab-1", Name: "lab-host-1", Status: sdk.StatusWaiting},
+		{ID: "lab-2", Name: "lab-host-2", Status: sdk.StatusWaiting},
+		{ID: "spawned", Name: "spawned", Status: sdk.StatusWaiting},
+	} {
+		test.NoError(t, InsertWorker(db, w, 0))
+		if w.ID != "spawned" {
+			test.NoError(t, SetPool(db, w, "lab", []string{"bash", "docker"}))
+		}
+	}
+
+	ws, err := LoadPoolWorkers(db, "")
+	test.NoError(t, err)
+	if assert.Len(t, ws, 2) {
+		assert.Equal(t, "lab", ws[0].Pool)
+		assert.Equal(t, "lab-host-1", ws[0].Name)
+		assert.Equal(t, []string{"bash", "docker"}, ws[0].Capabilities)
+		assert.False(t, ws[0].Draining)
+	}
+
+	_, err = DrainPoolWorkers(db, "unknown", nil)
+	assert.Error(t, err)
+	_, err = DrainPoolWorkers(db, "lab", []string{"spawned"})
+	assert.Error(t, err)
+
+	drained, err := DrainPoolWorkers(db, "lab", []string{"lab-host-2"})
+	test.NoError(t, err)
+	if assert.Len(t, drained, 1) {
+		assert.Equal(t, "lab-host-2", drained[0].Name)
+	}
+
+	// the heartbeat tells the worker it is draining
+	w := &sdk.Worker{ID: "lab-1"}
+	test.NoError(t, RefreshWorker(db, w))
+	assert.False(t, w.Draining)
+	w = &sdk.Worker{ID: "lab-2"}
+	test.NoError(t, RefreshWorker(db, w))
+	assert.True(t, w.Draining)
+	assert.Error(t, RefreshWorker(db, &sdk.Worker{ID: "unknown"}))
+
+	loaded, err := LoadWorker(db, "lab-2")
+	test.NoError(t, err)
+	assert.Equal(t, "lab", loaded.Pool)
+	assert.True(t, loaded.Draining)
+}
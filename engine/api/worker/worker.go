@@ -44,9 +44,9 @@ func LoadWorker(db gorp.SqlExecutor, id string) (*sdk.Worker, error) {
 	var statusS string
 	var pbJobID sql.NullInt64
 	var jobType sql.NullString
-	query := `SELECT id, action_build_id, job_type, name, last_beat, group_id, model, status, hatchery_name, group_id FROM worker WHERE worker.id = $1 FOR UPDATE`
+	query := `SELECT id, action_build_id, job_type, name, last_beat, group_id, model, status, hatchery_name, group_id, pool, draining FROM worker WHERE worker.id = $1 FOR UPDATE`
 
-	if err := db.QueryRow(query, id).Scan(&w.ID, &pbJobID, &jobType, &w.Name, &w.LastBeat, &w.GroupID, &w.ModelID, &statusS, &w.HatcheryName, &w.GroupID); err != nil {
+	if err := db.QueryRow(query, id).Scan(&w.ID, &pbJobID, &jobType, &w.Name, &w.LastBeat, &w.GroupID, &w.ModelID, &statusS, &w.HatcheryName, &w.GroupID, &w.Pool, &w.Draining); err != nil {
 		return nil, err
 	}
 	w.Status = sdk.StatusFromString(statusS)
@@ -66,12 +66,12 @@ func LoadWorker(db gorp.SqlExecutor, id string) (*sdk.Worker, error) {
 func LoadWorkers(db gorp.SqlExecutor, hatcheryName string) ([]sdk.Worker, error) {
 	w := []sdk.Worker{}
 	var statusS string
-	query := `SELECT id, name, last_beat, group_id, model, status, hatchery_name FROM worker ORDER BY name ASC`
+	query := `SELECT id, name, last_beat, group_id, model, status, hatchery_name, pool, draining FROM worker ORDER BY name ASC`
 	args := []interface{}{}
 
 	if hatcheryName != "" {
 		// TODO: remove the hatchery name from worker worker table !
-		query = `SELECT id, name, last_beat, group_id, model, status, hatchery_name FROM worker WHERE hatchery_name = $1 ORDER BY name ASC`
+		query = `SELECT id, name, last_beat, group_id, model, status, hatchery_name, pool, draining FROM worker WHERE hatchery_name = $1 ORDER BY name ASC`
 		args = []interface{}{hatcheryName}
 	}
 
@@ -83,7 +83,7 @@ func LoadWorkers(db gorp.SqlExecutor, hatcheryName string) ([]sdk.Worker, error)
 
 	for rows.Next() {
 		var worker sdk.Worker
-		err = rows.Scan(&worker.ID, &worker.Name, &worker.LastBeat, &worker.GroupID, &worker.ModelID, &statusS, &worker.HatcheryName)
+		err = rows.Scan(&worker.ID, &worker.Name, &worker.LastBeat, &worker.GroupID, &worker.ModelID, &statusS, &worker.HatcheryName, &worker.Pool, &worker.Draining)
 		if err != nil {
 			return nil, err
 		}
@@ -133,30 +133,24 @@ func LoadDeadWorkers(db gorp.SqlExecutor, timeout float64, status []string) ([]s
 	return w, nil
 }
 
-// RefreshWorker Update worker last_beat
+// RefreshWorker Update worker last_beat, and tells if the worker is draining
 func RefreshWorker(db gorp.SqlExecutor, w *sdk.Worker) error {
 	if w == nil {
 		return sdk.WrapError(sdk.ErrUnknownError, "RefreshWorker> Invalid worker")
 	}
-	query := `UPDATE worker SET last_beat = now() WHERE id = $1`
-	res, err := db.Exec(query, w.ID)
-	if err != nil {
-		return sdk.WrapError(err, "Unable to update worker: %s", w.ID)
-	}
-
-	n, err := res.RowsAffected()
-	if err != nil {
-		return sdk.WrapError(err, "Unable to refresh worker: %s", w.ID)
-	}
-
-	var mname string
-	if w.Model != nil {
-		mname = w.Model.Name
-	}
-	if n != 1 {
+	query := `UPDATE worker SET last_beat = now() WHERE id = $1 RETURNING draining`
+	err := db.QueryRow(query, w.ID).Scan(&w.Draining)
+	if err == sql.ErrNoRows {
+		var mname string
+		if w.Model != nil {
+			mname = w.Model.Name
+		}
 		return sdk.NewError(sdk.ErrForbidden, fmt.Errorf("unknown worker '%s' Name:%s GroupID:%d ModelID:%d Model:%s HatcheryName:%s",
 			w.ID, w.Name, w.GroupID, w.ModelID, mname, w.HatcheryName))
 	}
+	if err != nil {
+		return sdk.WrapError(err, "Unable to update worker: %s", w.ID)
+	}
 
 	return nil
 }
@@ -4,11 +4,14 @@ import (
 	"context"
 	"database/sql"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/gorilla/mux"
 
 	"github.com/ovh/cds/engine/api/action"
 	"github.com/ovh/cds/engine/api/cache"
+	"github.com/ovh/cds/engine/api/event"
 	"github.com/ovh/cds/engine/api/group"
 	"github.com/ovh/cds/engine/api/pipeline"
 	"github.com/ovh/cds/engine/api/worker"
@@ -58,9 +61,21 @@ func (api *API) addWorkerModelHandler() service.Handler {
 			return sdk.ErrWorkerModelNoAdmin
 		}
 
+		// only the administrators of an organization can scope a worker model to it
+		if model.OrganizationID != 0 {
+			if err := checkOrganizationAdmin(api.mustDB(), currentUser, model.OrganizationID); err != nil {
+				return err
+			}
+		}
+
+		base, err := worker.LoadModelParent(api.mustDB(), currentUser, &model)
+		if err != nil {
+			return err
+		}
+
 		switch model.Type {
 		case sdk.Docker:
-			if model.ModelDocker.Image == "" {
+			if model.Inherit(base).ModelDocker.Image == "" {
 				return sdk.WrapError(sdk.ErrWrongRequest, "addWorkerModel> Invalid worker image")
 			}
 			if !currentUser.Admin && !model.Restricted {
@@ -71,11 +86,14 @@ func (api *API) addWorkerModelHandler() service.Handler {
 				model.ModelDocker.Shell = modelPattern.Model.Shell
 				model.ModelDocker.Envs = modelPattern.Model.Envs
 			}
-			if model.ModelDocker.Cmd == "" || model.ModelDocker.Shell == "" {
+			if resolved := model.Inherit(base); resolved.ModelDocker.Cmd == "" || resolved.ModelDocker.Shell == "" {
 				return sdk.WrapError(sdk.ErrWrongRequest, "updateWorkerModel> Invalid worker command or invalid shell command")
 			}
+			if err := worker.CheckModelRegistry(api.mustDB(), model.ModelDocker.Registry); err != nil {
+				return err
+			}
 		default:
-			if model.ModelVirtualMachine.Image == "" {
+			if model.Inherit(base).ModelVirtualMachine.Image == "" {
 				return sdk.WrapError(sdk.ErrWrongRequest, "addWorkerModel> Invalid worker command or invalid image")
 			}
 			if !currentUser.Admin && !model.Restricted {
@@ -128,6 +146,8 @@ func (api *API) addWorkerModelHandler() service.Handler {
 		key := cache.Key("api:workermodels:*")
 		api.Cache.DeleteAll(key)
 
+		event.PublishWorkerModelAdd(model, currentUser)
+
 		return service.WriteJSON(w, model, http.StatusOK)
 	}
 }
@@ -275,9 +295,26 @@ func (api *API) updateWorkerModelHandler() service.Handler {
 			return sdk.ErrWorkerModelNoAdmin
 		}
 
+		// only the administrators of the organizations can move a worker model between them
+		if model.OrganizationID != old.OrganizationID {
+			if err := checkOrganizationAdmin(api.mustDB(), user, model.OrganizationID, old.OrganizationID); err != nil {
+				return err
+			}
+		}
+
+		//If the model modelID has not been set, keep the old modelID
+		if model.ID == 0 {
+			model.ID = old.ID
+		}
+
+		base, err := worker.LoadModelParent(api.mustDB(), user, &model)
+		if err != nil {
+			return err
+		}
+
 		switch model.Type {
 		case sdk.Docker:
-			if model.ModelDocker.Image == "" {
+			if model.Inherit(base).ModelDocker.Image == "" {
 				return sdk.WrapError(sdk.ErrWrongRequest, "updateWorkerModel> Invalid worker image")
 			}
 			if !user.Admin && !model.Restricted {
@@ -294,11 +331,14 @@ func (api *API) updateWorkerModelHandler() service.Handler {
 					model.ModelDocker.Envs = modelPattern.Model.Envs
 				}
 			}
-			if model.ModelDocker.Cmd == "" || model.ModelDocker.Shell == "" {
+			if resolved := model.Inherit(base); resolved.ModelDocker.Cmd == "" || resolved.ModelDocker.Shell == "" {
 				return sdk.WrapError(sdk.ErrWrongRequest, "updateWorkerModel> Invalid worker command or invalid shell command")
 			}
+			if err := worker.CheckModelRegistry(api.mustDB(), model.ModelDocker.Registry); err != nil {
+				return err
+			}
 		default:
-			if model.ModelVirtualMachine.Image == "" {
+			if model.Inherit(base).ModelVirtualMachine.Image == "" {
 				return sdk.WrapError(sdk.ErrWrongRequest, "updateWorkerModel> Invalid worker command or invalid image")
 			}
 			if !user.Admin && !model.Restricted {
@@ -317,11 +357,6 @@ func (api *API) updateWorkerModelHandler() service.Handler {
 			}
 		}
 
-		//If the model modelID has not been set, keep the old modelID
-		if model.ID == 0 {
-			model.ID = old.ID
-		}
-
 		// provision is allowed only for CDS Admin
 		// or by user with a restricted model
 		if !deprecatedGetUser(ctx).Admin && !model.Restricted {
@@ -378,6 +413,8 @@ func (api *API) updateWorkerModelHandler() service.Handler {
 		key := cache.Key("api:workermodels:*")
 		api.Cache.DeleteAll(key)
 
+		event.PublishWorkerModelUpdate(*old, model, deprecatedGetUser(ctx))
+
 		return service.WriteJSON(w, model, http.StatusOK)
 	}
 }
@@ -389,6 +426,14 @@ func (api *API) deleteWorkerModelHandler() service.Handler {
 			return sdk.WrapError(errr, "deleteWorkerModel> Invalid permModelID")
 		}
 
+		old, err := worker.LoadWorkerModelByID(api.mustDB(), workerModelID)
+		if err != nil {
+			return sdk.WrapError(err, "cannot load worker model by id")
+		}
+		if err := worker.CheckModelVariants(api.mustDB(), old); err != nil {
+			return err
+		}
+
 		tx, err := api.mustDB().Begin()
 		if err != nil {
 			return sdk.WrapError(err, "Cannot start transaction")
@@ -405,15 +450,26 @@ func (api *API) deleteWorkerModelHandler() service.Handler {
 		key := cache.Key("api:workermodels:*")
 		api.Cache.DeleteAll(key)
 
+		event.PublishWorkerModelDelete(*old, deprecatedGetUser(ctx))
+
 		return nil
 	}
 }
 
+// getWorkerModel returns a worker model, with resolved=true the fields it inherits from its base model are resolved
+// as they are given to the hatcheries
 func (api *API) getWorkerModel(w http.ResponseWriter, r *http.Request, name string) error {
 	m, err := worker.LoadWorkerModelByName(api.mustDB(), name)
 	if err != nil {
 		return sdk.WrapError(err, "cannot load worker model")
 	}
+	if FormBool(r, "resolved") {
+		ms := []sdk.Model{*m}
+		if err := worker.ResolveModelsParent(api.mustDB(), ms); err != nil {
+			return err
+		}
+		m = &ms[0]
+	}
 	return service.WriteJSON(w, m, http.StatusOK)
 }
 
@@ -449,6 +505,9 @@ func (api *API) getWorkerModelsEnabledHandler() service.Handler {
 		if errgroup != nil {
 			return sdk.WrapError(errgroup, "getWorkerModelsEnabled> cannot load worker models for hatchery %d with group %d", h.ID, *h.GroupID)
 		}
+		if err := worker.LoadModelsRegistryAuth(api.mustDB(), models); err != nil {
+			return err
+		}
 		return service.WriteJSON(w, models, http.StatusOK)
 	}
 }
@@ -668,3 +727,23 @@ func (api *API) getWorkerModelCommunicationsHandler() service.Handler {
 		return service.WriteJSON(w, sdk.AvailableWorkerModelCommunication, http.StatusOK)
 	}
 }
+
+// getAdminUnusedWorkerModelsHandler returns the worker models which have run no job during the given number of days,
+// 30 by default, to clean up the models and their images
+func (api *API) getAdminUnusedWorkerModelsHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		days := 30
+		if s := r.FormValue("days"); s != "" {
+			var err error
+			days, err = strconv.Atoi(s)
+			if err != nil || days <= 0 {
+				return sdk.NewErrorFrom(sdk.ErrWrongRequest, "invalid days %s, expected a positive number", s)
+			}
+		}
+		models, err := worker.LoadUnusedWorkerModels(api.mustDB(), time.Now().AddDate(0, 0, -days))
+		if err != nil {
+			return err
+		}
+		return service.WriteJSON(w, models, http.StatusOK)
+	}
+}
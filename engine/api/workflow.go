@@ -32,12 +32,36 @@ func (api *API) getWorkflowsHandler() service.Handler {
 	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
 		vars := mux.Vars(r)
 		key := vars[permProjectKey]
+		u := deprecatedGetUser(ctx)
 
-		ws, err := workflow.LoadAll(api.mustDB(), key)
-		if err != nil {
+		f := sdk.WorkflowFilterFromValues(r.URL.Query())
+		if name := r.FormValue("filter"); name != "" {
+			saved, err := api.loadWorkflowSavedFilter(ctx, key, name)
+			if err != nil {
+				return err
+			}
+			f = saved.Filter
+		}
+		if err := f.IsValid(); err != nil {
 			return err
 		}
+		// Providers do not have favorites
+		if u == nil {
+			f.Favorite = false
+		}
+
+		if f.IsEmpty() {
+			ws, err := workflow.LoadAll(api.mustDB(), key)
+			if err != nil {
+				return err
+			}
+			return service.WriteJSON(w, ws, http.StatusOK)
+		}
 
+		ws, err := workflow.LoadAllFiltered(api.mustDB(), key, f, u)
+		if err != nil {
+			return err
+		}
 		return service.WriteJSON(w, ws, http.StatusOK)
 	}
 }
@@ -124,6 +148,7 @@ func (api *API) getWorkflowHandler() service.Handler {
 
 		//We filter project and workflow configurtaion key, because they are always set on insertHooks
 		w1.FilterHooksConfig(sdk.HookConfigProject, sdk.HookConfigWorkflow)
+		w1.MaskHooksSecrets()
 		return service.WriteJSON(w, w1, http.StatusOK)
 	}
 }
@@ -378,6 +403,7 @@ func (api *API) postWorkflowHandler() service.Handler {
 
 		//We filter project and workflow configurtaion key, because they are always set on insertHooks
 		wf1.FilterHooksConfig(sdk.HookConfigProject, sdk.HookConfigWorkflow)
+		wf1.MaskHooksSecrets()
 
 		// TODO REMOVE WHEN WE WILL DELETE OLD NODE STRUCT
 		wf1.Root = nil
@@ -478,6 +504,7 @@ func (api *API) putWorkflowHandler() service.Handler {
 
 		//We filter project and workflow configuration key, because they are always set on insertHooks
 		wf1.FilterHooksConfig(sdk.HookConfigProject, sdk.HookConfigWorkflow)
+		wf1.MaskHooksSecrets()
 		// TODO REMOVE
 		wf1.Root = nil
 		wf1.Joins = nil
@@ -508,6 +535,23 @@ func (api *API) deleteWorkflowHandler() service.Handler {
 		}
 		defer tx.Rollback() // nolint
 
+		// The workflow is kept in the trash of the project until the purge, its hooks are stopped
+		if api.Config.Trash.Retention > 0 {
+			if err := putInTrash(tx, p, sdk.TrashItemWorkflow, oldW.ID, oldW.Name, deprecatedGetUser(ctx)); err != nil {
+				return sdk.WrapError(err, "Cannot delete workflow")
+			}
+			if err := tx.Commit(); err != nil {
+				return sdk.WrapError(err, "Cannot commit transaction")
+			}
+			uuids, err := workflow.LoadHookUUIDsByWorkflowID(api.mustDB(), oldW.ID)
+			if err != nil {
+				log.Warning("deleteWorkflowHandler> %v", err)
+			}
+			api.switchHooks(ctx, uuids, "stop")
+			event.PublishWorkflowDelete(key, *oldW, deprecatedGetUser(ctx))
+			return service.WriteJSON(w, nil, http.StatusOK)
+		}
+
 		if err := workflow.MarkAsDelete(tx, oldW); err != nil {
 			return sdk.WrapError(err, "Cannot delete workflow")
 		}
@@ -61,6 +61,7 @@ func UpdateAsCode(ctx context.Context, db *gorp.DbMap, store cache.Store, proj *
 		}
 		vcsStrategy.SSHKeyContent = key.Private
 	} else {
+		app.ProjectID = proj.ID
 		if err := application.DecryptVCSStrategyPassword(&app); err != nil {
 			return nil, sdk.WrapError(err, "unable to decrypt vcs strategy")
 		}
@@ -14,8 +14,10 @@ import (
 	"time"
 
 	"github.com/go-gorp/gorp"
+	"github.com/lib/pq"
 	"gopkg.in/yaml.v2"
 
+	"github.com/ovh/cds/engine/api/action"
 	"github.com/ovh/cds/engine/api/application"
 	"github.com/ovh/cds/engine/api/cache"
 	"github.com/ovh/cds/engine/api/database/gorpmapping"
@@ -128,10 +130,13 @@ func (w *Workflow) PostGet(db gorp.SqlExecutor) error {
 	var res = struct {
 		Metadata     sql.NullString `db:"metadata"`
 		PurgeTags    sql.NullString `db:"purge_tags"`
+		ForkPolicy   sql.NullString `db:"fork_policy"`
+		AutoCancel   bool           `db:"auto_cancel"`
+		CommitStatus sql.NullString `db:"commit_status"`
 		WorkflowData sql.NullString `db:"workflow_data"`
 	}{}
 
-	if err := db.SelectOne(&res, "SELECT metadata, purge_tags, workflow_data FROM workflow WHERE id = $1", w.ID); err != nil {
+	if err := db.SelectOne(&res, "SELECT metadata, purge_tags, fork_policy, auto_cancel, commit_status, workflow_data FROM workflow WHERE id = $1", w.ID); err != nil {
 		return sdk.WrapError(err, "PostGet> Unable to load marshalled workflow")
 	}
 
@@ -147,6 +152,19 @@ func (w *Workflow) PostGet(db gorp.SqlExecutor) error {
 	}
 	w.PurgeTags = purgeTags
 
+	var forkPolicy sdk.WorkflowForkPolicy
+	if err := gorpmapping.JSONNullString(res.ForkPolicy, &forkPolicy); err != nil {
+		return err
+	}
+	w.ForkPolicy = forkPolicy
+	w.AutoCancel = res.AutoCancel
+
+	var commitStatus sdk.WorkflowCommitStatus
+	if err := gorpmapping.JSONNullString(res.CommitStatus, &commitStatus); err != nil {
+		return err
+	}
+	w.CommitStatus = commitStatus
+
 	data := &sdk.WorkflowData{}
 	if err := gorpmapping.JSONNullString(res.WorkflowData, data); err != nil {
 		return sdk.WrapError(err, "Unable to unmarshall workflow data")
@@ -183,11 +201,21 @@ func (w *Workflow) PostUpdate(db gorp.SqlExecutor) error {
 		return errPt
 	}
 
+	fp, errFp := json.Marshal(w.ForkPolicy)
+	if errFp != nil {
+		return errFp
+	}
+
+	cs, errCs := json.Marshal(w.CommitStatus)
+	if errCs != nil {
+		return errCs
+	}
+
 	data, errD := gorpmapping.JSONToNullString(w.WorkflowData)
 	if errD != nil {
 		return sdk.WrapError(errD, "Workflow.PostUpdate> Unable to marshall workflow data")
 	}
-	if _, err := db.Exec("update workflow set purge_tags = $1, workflow_data = $3 where id = $2", pt, w.ID, data); err != nil {
+	if _, err := db.Exec("update workflow set purge_tags = $1, workflow_data = $3, fork_policy = $4, auto_cancel = $5, commit_status = $6 where id = $2", pt, w.ID, data, fp, w.AutoCancel, cs); err != nil {
 		return err
 	}
 
@@ -196,9 +224,6 @@ func (w *Workflow) PostUpdate(db gorp.SqlExecutor) error {
 
 // LoadAll loads all workflows for a project. All users in a project can list all workflows in a project
 func LoadAll(db gorp.SqlExecutor, projectKey string) ([]sdk.Workflow, error) {
-	res := []sdk.Workflow{}
-	dbRes := []Workflow{}
-
 	query := `
 		select workflow.*
 		from workflow
@@ -207,7 +232,96 @@ func LoadAll(db gorp.SqlExecutor, projectKey string) ([]sdk.Workflow, error) {
 		and workflow.to_delete = false
 		order by workflow.name asc`
 
-	if _, err := db.Select(&dbRes, query, projectKey); err != nil {
+	return loadAll(db, projectKey, query, projectKey)
+}
+
+// LoadAllFiltered loads all workflows for a project matching the given filter, the favorite criteria applies to
+// the given user
+func LoadAllFiltered(db gorp.SqlExecutor, projectKey string, f sdk.WorkflowFilter, u *sdk.User) ([]sdk.Workflow, error) {
+	query := `
+		select workflow.*
+		from workflow
+		join project on project.id = workflow.project_id
+		where project.projectkey = $1
+		and workflow.to_delete = false`
+	args := []interface{}{projectKey}
+
+	if len(f.Labels) > 0 {
+		labels := make([]string, 0, len(f.Labels))
+		for _, l := range f.Labels {
+			if !sdk.IsInArray(l, labels) {
+				labels = append(labels, l)
+			}
+		}
+		args = append(args, pq.StringArray(labels))
+		query += fmt.Sprintf(`
+		and (
+			select count(distinct project_label.name)
+			from project_label_workflow
+			join project_label on project_label.id = project_label_workflow.label_id
+			where project_label_workflow.workflow_id = workflow.id
+			and project_label.name = any($%d)
+		) = %d`, len(args), len(labels))
+	}
+	if f.Status != "" {
+		args = append(args, f.Status)
+		query += fmt.Sprintf(`
+		and (
+			select workflow_run.status
+			from workflow_run
+			where workflow_run.workflow_id = workflow.id
+			order by workflow_run.num desc
+			limit 1
+		) = $%d`, len(args))
+	}
+	if f.Repository != "" {
+		args = append(args, f.Repository)
+		query += fmt.Sprintf(`
+		and (
+			workflow.from_repository = $%d
+			or exists (
+				select 1
+				from w_node
+				join w_node_context on w_node_context.node_id = w_node.id
+				join application on application.id = w_node_context.application_id
+				where w_node.workflow_id = workflow.id
+				and application.repo_fullname = $%d
+			)
+		)`, len(args), len(args))
+	}
+	if f.Template != "" {
+		args = append(args, f.Template)
+		query += fmt.Sprintf(`
+		and exists (
+			select 1
+			from workflow_template_instance
+			join workflow_template on workflow_template.id = workflow_template_instance.workflow_template_id
+			join "group" on "group".id = workflow_template.group_id
+			where workflow_template_instance.workflow_id = workflow.id
+			and "group".name || '/' || workflow_template.slug = $%d
+		)`, len(args))
+	}
+	if f.Favorite {
+		args = append(args, u.ID)
+		query += fmt.Sprintf(`
+		and exists (
+			select 1
+			from workflow_favorite
+			where workflow_favorite.workflow_id = workflow.id
+			and workflow_favorite.user_id = $%d
+		)`, len(args))
+	}
+	query += `
+		order by workflow.name asc`
+
+	return loadAll(db, projectKey, query, args...)
+}
+
+func loadAll(db gorp.SqlExecutor, projectKey string, query string, args ...interface{}) ([]sdk.Workflow, error) {
+	res := []sdk.Workflow{}
+	dbRes := []Workflow{}
+
+	if _, err := db.Select(&dbRes, query, args...); err != nil {
 		if err == sql.ErrNoRows {
 			return nil, sdk.ErrWorkflowNotFound
 		}
@@ -287,7 +401,8 @@ func Load(ctx context.Context, db gorp.SqlExecutor, store cache.Store, proj *sdk
 		from workflow
 		join project on project.id = workflow.project_id
 		where project.projectkey = $1
-		and workflow.name = $2`, icon)
+		and workflow.name = $2
+		and workflow.to_delete = false`, icon)
 	res, err := load(ctx, db, store, proj, opts, u, query, proj.Key, name)
 	if err != nil {
 		return nil, sdk.WrapError(err, "Unable to load workflow %s in project %s", name, proj.Key)
@@ -883,6 +998,9 @@ func RenameNode(db gorp.SqlExecutor, w *sdk.Workflow) error {
 
 // Update updates a workflow
 func Update(ctx context.Context, db gorp.SqlExecutor, store cache.Store, w *sdk.Workflow, oldWorkflow *sdk.Workflow, p *sdk.Project, u *sdk.User) error {
+	// the secrets of the hooks are masked in the workflows returned by the API
+	restoreHooksSecrets(w, oldWorkflow)
+
 	if err := IsValid(ctx, store, db, w, p, u); err != nil {
 		return err
 	}
@@ -986,6 +1104,14 @@ func MarkAsDelete(db gorp.SqlExecutor, w *sdk.Workflow) error {
 	return nil
 }
 
+// Restore restores a workflow marked to be deleted
+func Restore(db gorp.SqlExecutor, id int64) error {
+	if _, err := db.Exec("update workflow set to_delete = false where id = $1", id); err != nil {
+		return sdk.WrapError(err, "Unable to restore workflow id %d", id)
+	}
+	return nil
+}
+
 // Delete workflow
 func Delete(ctx context.Context, db gorp.SqlExecutor, store cache.Store, p *sdk.Project, w *sdk.Workflow) error {
 	log.Debug("Delete> deleting workflow %d", w.ID)
@@ -1048,6 +1174,14 @@ func IsValid(ctx context.Context, store cache.Store, db gorp.SqlExecutor, w *sdk
 		return sdk.NewError(sdk.ErrWorkflowInvalid, fmt.Errorf("Invalid workflow name. It should match %s", sdk.NamePattern))
 	}
 
+	if err := w.ForkPolicy.IsValid(); err != nil {
+		return err
+	}
+
+	if err := w.CommitStatus.IsValid(); err != nil {
+		return err
+	}
+
 	//Check duplicate refs
 	refs := w.References()
 	for i, ref1 := range refs {
@@ -1164,6 +1298,11 @@ func IsValid(ctx context.Context, store cache.Store, db gorp.SqlExecutor, w *sdk
 	nodesArray := w.WorkflowData.Array()
 	for i := range nodesArray {
 		n := nodesArray[i]
+		if n.Type == sdk.NodeTypeJoin {
+			if err := n.JoinStrategy.IsValid(len(n.JoinContext)); err != nil {
+				return err
+			}
+		}
 		if n.Context == nil {
 			continue
 		}
@@ -1180,12 +1319,15 @@ func IsValid(ctx context.Context, store cache.Store, db gorp.SqlExecutor, w *sdk
 		if err := checkProjectIntegration(proj, w, n); err != nil {
 			return err
 		}
-		if err := checkHooks(db, w, n); err != nil {
+		if err := checkHooks(db, proj, w, n); err != nil {
 			return err
 		}
 		if err := checkOutGoingHook(db, w, n); err != nil {
 			return err
 		}
+		if p := n.Context.Conditions.Priority; p != nil && (!sdk.WorkflowRunPriorityValidate(*p) || *p == sdk.WorkflowRunPriorityUrgent) {
+			return sdk.NewError(sdk.ErrWorkflowInvalid, fmt.Errorf("Invalid priority %d on node %s, the urgent priority can only be set by an administrator", *p, n.Name))
+		}
 
 		if n.Context.ApplicationID != 0 && n.Context.ProjectIntegrationID != 0 {
 			if err := n.CheckApplicationDeploymentStrategies(proj, w); err != nil {
@@ -1228,7 +1370,7 @@ func checkOutGoingHook(db gorp.SqlExecutor, w *sdk.Workflow, n *sdk.Node) error
 	return nil
 }
 
-func checkHooks(db gorp.SqlExecutor, w *sdk.Workflow, n *sdk.Node) error {
+func checkHooks(db gorp.SqlExecutor, proj *sdk.Project, w *sdk.Workflow, n *sdk.Node) error {
 	for i := range n.Hooks {
 		h := &n.Hooks[i]
 		if h.HookModelID != 0 {
@@ -1250,6 +1392,11 @@ func checkHooks(db gorp.SqlExecutor, w *sdk.Workflow, n *sdk.Node) error {
 			w.HookModels[hm.ID] = *hm
 			h.HookModelID = hm.ID
 		}
+		if h.HookModelName == sdk.UpstreamWorkflowModelName {
+			if err := checkUpstreamHook(db, proj, h); err != nil {
+				return err
+			}
+		}
 	}
 	return nil
 }
@@ -1403,6 +1550,7 @@ func Push(ctx context.Context, db *gorp.DbMap, store cache.Store, proj *sdk.Proj
 	apps := make(map[string]exportentities.Application)
 	pips := make(map[string]exportentities.PipelineV1)
 	envs := make(map[string]exportentities.Environment)
+	acts := make(map[string]exportentities.Action)
 	var wrkflw exportentities.Workflow
 
 	mError := new(sdk.MultiError)
@@ -1427,6 +1575,14 @@ func Push(ctx context.Context, db *gorp.DbMap, store cache.Store, proj *sdk.Proj
 		var workflowFileName string
 		b := buff.Bytes()
 		switch {
+		case strings.HasPrefix(hdr.Name, repositoryActionsDirectory+"/"):
+			var act exportentities.Action
+			if err := yaml.Unmarshal(b, &act); err != nil {
+				log.Error("Push> Unable to unmarshal action %s: %v", hdr.Name, err)
+				mError.Append(fmt.Errorf("Unable to unmarshal action %s: %v", hdr.Name, err))
+				continue
+			}
+			acts[hdr.Name] = act
 		case strings.Contains(hdr.Name, ".app."):
 			var app exportentities.Application
 			if err := yaml.Unmarshal(b, &app); err != nil {
@@ -1544,9 +1700,27 @@ func Push(ctx context.Context, db *gorp.DbMap, store cache.Store, proj *sdk.Proj
 		log.Debug("Push> -- %s OK", filename)
 	}
 
+	repositoryActions := make([]sdk.Action, 0, len(acts))
+	for filename, act := range acts {
+		log.Debug("Push> Parsing %s", filename)
+		if opts == nil || opts.FromRepository == "" {
+			return nil, nil, sdk.NewErrorFrom(sdk.ErrWrongRequest, "action %s can only be defined in the repository of a workflow as code", act.Name)
+		}
+		a, err := act.Action()
+		if err != nil {
+			return nil, nil, sdk.NewError(sdk.ErrWrongRequest, fmt.Errorf("unable to parse action %s: %v", act.Name, err))
+		}
+		if err := action.ImportRepositoryAction(tx, proj.ID, opts.FromRepository, opts.Commit, a, u.ID); err != nil {
+			err = fmt.Errorf("unable to import action %s: %v", act.Name, err)
+			return nil, nil, sdk.NewError(sdk.ErrWrongRequest, err)
+		}
+		repositoryActions = append(repositoryActions, *a)
+		log.Debug("Push> -- %s OK", filename)
+	}
+
 	for filename, pip := range pips {
 		log.Debug("Push> Parsing %s", filename)
-		pipDB, msgList, err := pipeline.ParseAndImport(tx, store, proj, &pip, u, pipeline.ImportOptions{Force: true})
+		pipDB, msgList, err := pipeline.ParseAndImport(tx, store, proj, &pip, u, pipeline.ImportOptions{Force: true, RepositoryActions: repositoryActions})
 		if err != nil {
 			err = fmt.Errorf("unable to import pipeline %s: %v", pip.Name, err)
 			return nil, nil, sdk.NewError(sdk.ErrWrongRequest, err)
@@ -1569,6 +1743,12 @@ func Push(ctx context.Context, db *gorp.DbMap, store cache.Store, proj *sdk.Proj
 		log.Debug("Push> -- %s OK", filename)
 	}
 
+	if opts != nil && opts.FromRepository != "" {
+		if err := action.DeleteUnusedRepositoryActions(tx, proj.ID, opts.FromRepository, u.ID); err != nil {
+			return nil, nil, err
+		}
+	}
+
 	var dryRun bool
 	if opts != nil {
 		dryRun = opts.DryRun
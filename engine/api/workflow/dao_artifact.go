@@ -1,8 +1,11 @@
 package workflow
 
 import (
+	"database/sql"
+
 	"github.com/go-gorp/gorp"
 
+	"github.com/ovh/cds/engine/api/database/gorpmapping"
 	"github.com/ovh/cds/sdk"
 )
 
@@ -22,7 +25,10 @@ func LoadWorkfowArtifactByHash(db gorp.SqlExecutor, hash string) (*sdk.WorkflowN
 				object_path,
 				created,
 				workflow_run_id,
-				coalesce(sha512sum, '') AS sha512sum
+				coalesce(sha512sum, '') AS sha512sum,
+				coalesce(sha256sum, '') AS sha256sum,
+				coalesce(signing_key, '') AS signing_key,
+				coalesce(signature, '') AS signature
 		  FROM workflow_node_run_artifacts
 		  WHERE workflow_node_run_artifacts.download_hash = $1`
 	if err := db.SelectOne(&artGorp, query, hash); err != nil {
@@ -50,7 +56,10 @@ func LoadArtifactByIDs(db gorp.SqlExecutor, workflowID, artifactID int64) (*sdk.
 			workflow_node_run_artifacts.object_path,
 			workflow_node_run_artifacts.created,
 			workflow_node_run_artifacts.workflow_run_id,
-			coalesce(workflow_node_run_artifacts.sha512sum, '') AS sha512sum
+			coalesce(workflow_node_run_artifacts.sha512sum, '') AS sha512sum,
+			coalesce(workflow_node_run_artifacts.sha256sum, '') AS sha256sum,
+			coalesce(workflow_node_run_artifacts.signing_key, '') AS signing_key,
+			coalesce(workflow_node_run_artifacts.signature, '') AS signature
 		FROM workflow_node_run_artifacts
 		JOIN workflow_run ON workflow_run.id = workflow_node_run_artifacts.workflow_run_id
 		WHERE workflow_run.workflow_id = $1 AND workflow_node_run_artifacts.id = $2
@@ -78,7 +87,10 @@ func loadArtifactByNodeRunID(db gorp.SqlExecutor, nodeRunID int64) ([]sdk.Workfl
 			object_path,
 			created,
 			workflow_run_id,
-			coalesce(sha512sum, '') AS sha512sum
+			coalesce(sha512sum, '') AS sha512sum,
+			coalesce(sha256sum, '') AS sha256sum,
+			coalesce(signing_key, '') AS signing_key,
+			coalesce(signature, '') AS signature
 		FROM workflow_node_run_artifacts WHERE workflow_node_run_id = $1`, nodeRunID); err != nil {
 		return nil, err
 	}
@@ -99,3 +111,35 @@ func InsertArtifact(db gorp.SqlExecutor, a *sdk.WorkflowNodeRunArtifact) error {
 	a.ID = wArtifactDB.ID
 	return nil
 }
+
+// UpdateArtifactProvenance stores the signed provenance of an artifact
+func UpdateArtifactProvenance(db gorp.SqlExecutor, artifactID int64, env sdk.DSSEEnvelope) error {
+	s, err := gorpmapping.JSONToNullString(env)
+	if err != nil {
+		return sdk.WrapError(err, "unable to marshal provenance")
+	}
+	if _, err := db.Exec("UPDATE workflow_node_run_artifacts SET provenance = $1 WHERE id = $2", s, artifactID); err != nil {
+		return sdk.WrapError(err, "unable to update provenance of artifact %d", artifactID)
+	}
+	return nil
+}
+
+// LoadArtifactProvenance loads the signed provenance of an artifact
+func LoadArtifactProvenance(db gorp.SqlExecutor, artifactID int64) (*sdk.DSSEEnvelope, error) {
+	var s sql.NullString
+	if err := db.QueryRow("SELECT provenance FROM workflow_node_run_artifacts WHERE id = $1", artifactID).Scan(&s); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, sdk.WithStack(sdk.ErrNotFound)
+		}
+		return nil, sdk.WrapError(err, "unable to load provenance of artifact %d", artifactID)
+	}
+	if !s.Valid {
+		return nil, sdk.WithStack(sdk.ErrNotFound)
+	}
+
+	var env sdk.DSSEEnvelope
+	if err := gorpmapping.JSONNullString(s, &env); err != nil {
+		return nil, sdk.WrapError(err, "unable to unmarshal provenance of artifact %d", artifactID)
+	}
+	return &env, nil
+}
@@ -0,0 +1,83 @@
+package workflow
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// InsertBadge enables the badges of a workflow, or replaces their token if they are already enabled
+func InsertBadge(db gorp.SqlExecutor, badge *sdk.WorkflowBadge) error {
+	if err := DeleteBadge(db, badge.WorkflowID); err != nil {
+		return err
+	}
+	badge.Created = time.Now()
+	dbBadge := dbWorkflowBadge(*badge)
+	if err := db.Insert(&dbBadge); err != nil {
+		return sdk.WrapError(err, "unable to insert badge of workflow %d", badge.WorkflowID)
+	}
+	return nil
+}
+
+// DeleteBadge disables the badges of a workflow
+func DeleteBadge(db gorp.SqlExecutor, workflowID int64) error {
+	if _, err := db.Exec("DELETE FROM workflow_badge WHERE workflow_id = $1", workflowID); err != nil {
+		return sdk.WrapError(err, "unable to delete badge of workflow %d", workflowID)
+	}
+	return nil
+}
+
+// LoadBadge loads the badge configuration of a workflow
+func LoadBadge(db gorp.SqlExecutor, projectKey, workflowName string) (*sdk.WorkflowBadge, error) {
+	var dbBadge dbWorkflowBadge
+	query := `SELECT workflow_badge.workflow_id, coalesce(workflow_badge.token, '') AS token, workflow_badge.created
+	FROM workflow_badge
+	JOIN workflow ON workflow.id = workflow_badge.workflow_id
+	JOIN project ON project.id = workflow.project_id
+	WHERE project.projectkey = $1 AND workflow.name = $2`
+	if err := db.SelectOne(&dbBadge, query, projectKey, workflowName); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, sdk.WithStack(sdk.ErrNotFound)
+		}
+		return nil, sdk.WrapError(err, "unable to load badge of workflow %s", workflowName)
+	}
+	badge := sdk.WorkflowBadge(dbBadge)
+	return &badge, nil
+}
+
+// LoadLastRunStatus returns the status of the last run of a workflow, on a branch if given.
+// It returns an empty status if the workflow has no run.
+func LoadLastRunStatus(db gorp.SqlExecutor, workflowID int64, branch string) (string, error) {
+	query := `SELECT workflow_run.status FROM workflow_run
+	WHERE workflow_run.workflow_id = $1
+	AND ($2 = '' OR EXISTS (
+		SELECT 1 FROM workflow_run_tag
+		WHERE workflow_run_tag.workflow_run_id = workflow_run.id AND workflow_run_tag.tag = 'git.branch' AND workflow_run_tag.value = $2
+	))
+	ORDER BY workflow_run.num DESC
+	LIMIT 1`
+	status, err := db.SelectNullStr(query, workflowID, branch)
+	if err != nil {
+		return "", sdk.WrapError(err, "unable to load status of the last run of workflow %d", workflowID)
+	}
+	return status.String, nil
+}
+
+// LoadLastReleasedVersion returns the git tag of the last successful run of a workflow built from a tag.
+// It returns an empty version if the workflow has no such run.
+func LoadLastReleasedVersion(db gorp.SqlExecutor, workflowID int64) (string, error) {
+	query := `SELECT workflow_run_tag.value FROM workflow_run_tag
+	JOIN workflow_run ON workflow_run.id = workflow_run_tag.workflow_run_id
+	WHERE workflow_run.workflow_id = $1 AND workflow_run.status = $2
+	AND workflow_run_tag.tag = 'git.tag' AND workflow_run_tag.value <> ''
+	ORDER BY workflow_run.num DESC
+	LIMIT 1`
+	version, err := db.SelectNullStr(query, workflowID, sdk.StatusSuccess.String())
+	if err != nil {
+		return "", sdk.WrapError(err, "unable to load last released version of workflow %d", workflowID)
+	}
+	return version.String, nil
+}
@@ -3,6 +3,7 @@ package workflow
 import (
 	"context"
 	"database/sql"
+	"time"
 
 	"github.com/go-gorp/gorp"
 	"github.com/sguiheux/go-coverage"
@@ -67,8 +68,65 @@ func LoadCoverageReport(db gorp.SqlExecutor, workflowNodeRunID int64) (sdk.Workf
 	return sdk.WorkflowNodeRunCoverage(cov), nil
 }
 
+// LoadCoverageReportsByRunID loads the coverage reports of the node runs of a workflow run
+func LoadCoverageReportsByRunID(db gorp.SqlExecutor, workflowRunID int64) ([]sdk.WorkflowNodeRunCoverage, error) {
+	query := `
+    SELECT * from workflow_node_run_coverage
+    WHERE workflow_run_id = $1
+    ORDER BY workflow_node_run_id
+  `
+	var covs []Coverage
+	if _, err := db.Select(&covs, query, workflowRunID); err != nil {
+		return nil, sdk.WrapError(err, "Unable to load coverage reports of workflow run %d", workflowRunID)
+	}
+
+	res := make([]sdk.WorkflowNodeRunCoverage, len(covs))
+	for i := range covs {
+		res[i] = sdk.WorkflowNodeRunCoverage(covs[i])
+	}
+	return res, nil
+}
+
+// LoadCoverageTrend loads the coverage percentages of the last runs of a workflow, from the most recent.
+// Empty application name and branch match all the runs.
+func LoadCoverageTrend(db gorp.SqlExecutor, projectKey, workflowName, applicationName, branch string, limit int) ([]sdk.WorkflowCoverageTrend, error) {
+	query := `
+    SELECT workflow_node_run_coverage.run_number, workflow_node_run_coverage.workflow_node_run_id,
+      coalesce(application.name, ''), coalesce(workflow_node_run_coverage.branch, ''),
+      coalesce(workflow_node_run_coverage.lines_percent, 0), coalesce(workflow_node_run_coverage.functions_percent, 0),
+      coalesce(workflow_node_run_coverage.branches_percent, 0), coalesce(workflow_node_run_coverage.created, to_timestamp(0))
+    FROM workflow_node_run_coverage
+    JOIN workflow ON workflow.id = workflow_node_run_coverage.workflow_id
+    JOIN project ON project.id = workflow.project_id
+    LEFT JOIN application ON application.id = workflow_node_run_coverage.application_id
+    WHERE project.projectkey = $1 AND workflow.name = $2
+    AND ($3 = '' OR application.name = $3)
+    AND ($4 = '' OR workflow_node_run_coverage.branch = $4)
+    ORDER BY workflow_node_run_coverage.run_number DESC, workflow_node_run_coverage.workflow_node_run_id DESC
+    LIMIT $5
+  `
+	rows, err := db.Query(query, projectKey, workflowName, applicationName, branch, limit)
+	if err != nil {
+		return nil, sdk.WrapError(err, "Unable to load coverage trend of workflow %s", workflowName)
+	}
+	defer rows.Close()
+
+	res := []sdk.WorkflowCoverageTrend{}
+	for rows.Next() {
+		var t sdk.WorkflowCoverageTrend
+		if err := rows.Scan(&t.RunNumber, &t.WorkflowNodeRunID, &t.ApplicationName, &t.Branch,
+			&t.LinesPercent, &t.FunctionsPercent, &t.BranchesPercent, &t.Created); err != nil {
+			return nil, sdk.WrapError(err, "Unable to scan coverage trend")
+		}
+		res = append(res, t)
+	}
+	return res, nil
+}
+
 // InsertCoverage insert a coverage report for a workflow run
 func InsertCoverage(db gorp.SqlExecutor, cov sdk.WorkflowNodeRunCoverage) error {
+	cov.ComputePercents()
+	cov.Created = time.Now()
 	c := Coverage(cov)
 	if err := db.Insert(&c); err != nil {
 		return sdk.WrapError(err, "Unable to insert code coverage report")
@@ -78,6 +136,7 @@ func InsertCoverage(db gorp.SqlExecutor, cov sdk.WorkflowNodeRunCoverage) error
 
 // UpdateCoverage update a coverage report for a workflow run
 func UpdateCoverage(db gorp.SqlExecutor, cov sdk.WorkflowNodeRunCoverage) error {
+	cov.ComputePercents()
 	c := Coverage(cov)
 	if _, err := db.Update(&c); err != nil {
 		return sdk.WrapError(err, "Unable to update code coverage report")
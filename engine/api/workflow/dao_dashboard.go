@@ -0,0 +1,127 @@
+package workflow
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/go-gorp/gorp"
+	"github.com/lib/pq"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// LoadWorkflowsHealth returns the last run of each workflow of a project and the number of its successful and failed
+// runs started during the last 7 and 30 days
+func LoadWorkflowsHealth(db gorp.SqlExecutor, projectID int64, now time.Time) ([]sdk.WorkflowHealth, error) {
+	query := `
+	SELECT workflow.name, coalesce(last_run.num, 0), coalesce(last_run.status, ''), last_run.start,
+		coalesce(stats.runs_7, 0), coalesce(stats.success_7, 0), coalesce(stats.runs_30, 0), coalesce(stats.success_30, 0)
+	FROM workflow
+	LEFT JOIN (
+		SELECT DISTINCT ON (workflow_id) workflow_id, num, status, start
+		FROM workflow_run
+		WHERE project_id = $1
+		ORDER BY workflow_id, num DESC
+	) last_run ON last_run.workflow_id = workflow.id
+	LEFT JOIN (
+		SELECT workflow_id,
+			sum(CASE WHEN start >= $2 THEN 1 ELSE 0 END) AS runs_7,
+			sum(CASE WHEN start >= $2 AND status = $4 THEN 1 ELSE 0 END) AS success_7,
+			count(*) AS runs_30,
+			sum(CASE WHEN status = $4 THEN 1 ELSE 0 END) AS success_30
+		FROM workflow_run
+		WHERE project_id = $1 AND start >= $3 AND status IN ($4, $5)
+		GROUP BY workflow_id
+	) stats ON stats.workflow_id = workflow.id
+	WHERE workflow.project_id = $1 AND workflow.to_delete = false
+	ORDER BY workflow.name`
+	rows, err := db.Query(query, projectID, now.AddDate(0, 0, -7), now.AddDate(0, 0, -30), sdk.StatusSuccess.String(), sdk.StatusFail.String())
+	if err != nil {
+		return nil, sdk.WrapError(err, "unable to load health of the workflows of project %d", projectID)
+	}
+	defer rows.Close()
+
+	res := []sdk.WorkflowHealth{}
+	for rows.Next() {
+		var h sdk.WorkflowHealth
+		var lastRunDate pq.NullTime
+		if err := rows.Scan(&h.Name, &h.LastRunNumber, &h.LastRunStatus, &lastRunDate,
+			&h.Runs7Days, &h.Success7Days, &h.Runs30Days, &h.Success30Days); err != nil {
+			return nil, sdk.WrapError(err, "unable to scan health of workflow")
+		}
+		if lastRunDate.Valid {
+			h.LastRunDate = &lastRunDate.Time
+		}
+		res = append(res, h)
+	}
+	return res, sdk.WithStack(rows.Err())
+}
+
+// LoadQueueHealth returns the jobs of a project waiting in the queue and the time its jobs waited in the queue
+// since the given date
+func LoadQueueHealth(db gorp.SqlExecutor, projectID int64, now, since time.Time) (sdk.ProjectQueueHealth, error) {
+	var q sdk.ProjectQueueHealth
+	if err := db.QueryRow(`SELECT count(*), coalesce(extract(epoch FROM $3::timestamp with time zone - min(queued)), 0)
+		FROM workflow_node_run_job
+		WHERE project_id = $1 AND status = $2`, projectID, sdk.StatusWaiting.String(), now).Scan(&q.Waiting, &q.OldestWaiting); err != nil {
+		return q, sdk.WrapError(err, "unable to load waiting jobs of project %d", projectID)
+	}
+
+	// the jobs of the terminated node runs are kept in their stages
+	if err := db.QueryRow(`SELECT count(*), coalesce(avg(wait), 0), coalesce(percentile_cont(0.95) WITHIN GROUP (ORDER BY wait), 0)
+		FROM (
+			SELECT extract(epoch FROM (job->>'start')::timestamp with time zone - (job->>'queued')::timestamp with time zone) AS wait
+			FROM workflow_node_run
+			JOIN workflow_run ON workflow_run.id = workflow_node_run.workflow_run_id
+			CROSS JOIN LATERAL jsonb_array_elements(CASE WHEN jsonb_typeof(workflow_node_run.stages) = 'array' THEN workflow_node_run.stages ELSE '[]' END) AS stage
+			CROSS JOIN LATERAL jsonb_array_elements(CASE WHEN jsonb_typeof(stage->'run_jobs') = 'array' THEN stage->'run_jobs' ELSE '[]' END) AS job
+			WHERE workflow_run.project_id = $1 AND workflow_node_run.start >= $2
+			AND (job->>'queued')::timestamp with time zone >= $2
+			AND (job->>'start')::timestamp with time zone >= (job->>'queued')::timestamp with time zone
+		) waits`, projectID, since).Scan(&q.Jobs7Days, &q.AverageWait7Days, &q.P95Wait7Days); err != nil {
+		return q, sdk.WrapError(err, "unable to load queue durations of project %d", projectID)
+	}
+	return q, nil
+}
+
+// LoadAsCodePullRequests returns the pull requests opened from CDS on the repositories of the as code workflows of
+// a project
+func LoadAsCodePullRequests(db gorp.SqlExecutor, projectID int64) ([]sdk.ProjectAsCodePullRequest, error) {
+	prs := []sdk.ProjectAsCodePullRequest{}
+	rows, err := db.Query(`SELECT workflow.name, workflow_as_code_events.pullrequest_id, workflow_as_code_events.pullrequest_url,
+		workflow_as_code_events.username, workflow_as_code_events.creation_date
+		FROM workflow_as_code_events
+		JOIN workflow ON workflow.id = workflow_as_code_events.workflow_id
+		WHERE workflow.project_id = $1
+		ORDER BY workflow_as_code_events.creation_date DESC`, projectID)
+	if err != nil {
+		return nil, sdk.WrapError(err, "unable to load as code pull requests of project %d", projectID)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var pr sdk.ProjectAsCodePullRequest
+		if err := rows.Scan(&pr.WorkflowName, &pr.PullRequestID, &pr.PullRequestURL, &pr.Username, &pr.CreationDate); err != nil {
+			return nil, sdk.WrapError(err, "unable to scan as code pull request")
+		}
+		prs = append(prs, pr)
+	}
+	return prs, sdk.WithStack(rows.Err())
+}
+
+// LoadLastRunOnBranch returns the last run of a workflow tagged with the given branch, nil if there is none
+func LoadLastRunOnBranch(db gorp.SqlExecutor, workflowID int64, branch string) (*sdk.WorkflowRun, error) {
+	var wr sdk.WorkflowRun
+	if err := db.QueryRow(`SELECT workflow_run.id, workflow_run.num, workflow_run.status, workflow_run.start
+		FROM workflow_run
+		JOIN workflow_run_tag ON workflow_run_tag.workflow_run_id = workflow_run.id
+		WHERE workflow_run.workflow_id = $1 AND workflow_run_tag.tag = 'git.branch' AND workflow_run_tag.value = $2
+		ORDER BY workflow_run.num DESC
+		LIMIT 1`, workflowID, branch).Scan(&wr.ID, &wr.Number, &wr.Status, &wr.Start); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, sdk.WrapError(err, "unable to load last run of workflow %d on branch %s", workflowID, branch)
+	}
+	wr.WorkflowID = workflowID
+	return &wr, nil
+}
@@ -94,3 +94,26 @@ func insertNodeContextData(db gorp.SqlExecutor, w *sdk.Workflow, n *sdk.Node) er
 	n.Context.NodeID = n.ID
 	return nil
 }
+
+// LoadPipelineIDsByWorkflowID returns the ids of the pipelines used by the nodes of a workflow
+func LoadPipelineIDsByWorkflowID(db gorp.SqlExecutor, workflowID int64) ([]int64, error) {
+	var ids []int64
+	query := `SELECT DISTINCT w_node_context.pipeline_id
+		FROM w_node_context
+		JOIN w_node ON w_node.id = w_node_context.node_id
+		WHERE w_node.workflow_id = $1 AND w_node_context.pipeline_id IS NOT NULL`
+	if _, err := db.Select(&ids, query, workflowID); err != nil {
+		return nil, sdk.WrapError(err, "cannot load pipelines of workflow %d", workflowID)
+	}
+	return ids, nil
+}
+
+// CountNodeContextsByPipelineID returns the number of nodes using a pipeline, in all the workflows
+// including the ones marked to be deleted
+func CountNodeContextsByPipelineID(db gorp.SqlExecutor, pipelineID int64) (int64, error) {
+	n, err := db.SelectInt("SELECT COUNT(1) FROM w_node_context WHERE pipeline_id = $1", pipelineID)
+	if err != nil {
+		return 0, sdk.WrapError(err, "cannot count nodes of pipeline %d", pipelineID)
+	}
+	return n, nil
+}
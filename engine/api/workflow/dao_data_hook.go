@@ -84,3 +84,30 @@ func (h *dbNodeHookData) PostUpdate(db gorp.SqlExecutor) error {
 	}
 	return nil
 }
+
+// LoadHookUUIDsByProjectID returns the uuid of the hooks of all the workflows of a project
+func LoadHookUUIDsByProjectID(db gorp.SqlExecutor, projectID int64) ([]string, error) {
+	var uuids []string
+	query := `SELECT w_node_hook.uuid
+		FROM w_node_hook
+		JOIN w_node ON w_node.id = w_node_hook.node_id
+		JOIN workflow ON workflow.id = w_node.workflow_id
+		WHERE workflow.project_id = $1`
+	if _, err := db.Select(&uuids, query, projectID); err != nil {
+		return nil, sdk.WrapError(err, "cannot load hooks of project %d", projectID)
+	}
+	return uuids, nil
+}
+
+// LoadHookUUIDsByWorkflowID returns the uuid of the hooks of a workflow
+func LoadHookUUIDsByWorkflowID(db gorp.SqlExecutor, workflowID int64) ([]string, error) {
+	var uuids []string
+	query := `SELECT w_node_hook.uuid
+		FROM w_node_hook
+		JOIN w_node ON w_node.id = w_node_hook.node_id
+		WHERE w_node.workflow_id = $1`
+	if _, err := db.Select(&uuids, query, workflowID); err != nil {
+		return nil, sdk.WrapError(err, "cannot load hooks of workflow %d", workflowID)
+	}
+	return uuids, nil
+}
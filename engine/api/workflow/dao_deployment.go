@@ -0,0 +1,182 @@
+package workflow
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/go-gorp/gorp"
+	"github.com/lib/pq"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// insertDeployment stores a terminated node run as a deployment when its node has an application and an environment
+func insertDeployment(db gorp.SqlExecutor, wr *sdk.WorkflowRun, nr *sdk.WorkflowNodeRun) error {
+	if wr.Workflow.WorkflowData == nil {
+		return nil
+	}
+	n := wr.Workflow.WorkflowData.NodeByID(nr.WorkflowNodeID)
+	if n == nil || n.Context == nil || n.Context.ApplicationID == 0 || n.Context.EnvironmentID == 0 {
+		return nil
+	}
+	d := sdk.NewDeployment(*nr, wr.ProjectID, n.Context.ApplicationID, n.Context.EnvironmentID)
+	d.WorkflowName = wr.Workflow.Name
+	d.URL = fmt.Sprintf("%s/project/%s/workflow/%s/run/%d/node/%d?name=%s", baseUIURL, wr.Workflow.ProjectKey, wr.Workflow.Name, nr.Number, nr.ID, wr.Workflow.Name)
+	query := `INSERT INTO deployment (project_id, application_id, environment_id, workflow_id, workflow_run_id, workflow_node_run_id,
+		status, vcs_hash, started, done, lead_time, version, vcs_branch, workflow_name, workflow_node_name, num, url, rollback_of)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17,
+		COALESCE((SELECT deployment_id FROM deployment_rollback WHERE workflow_node_run_id = $6), 0))
+	ON CONFLICT (workflow_node_run_id) DO NOTHING`
+	if _, err := db.Exec(query, d.ProjectID, d.ApplicationID, d.EnvironmentID, d.WorkflowID, d.WorkflowRunID, d.WorkflowNodeRunID,
+		d.Status, d.VCSHash, d.Start, d.Done, d.LeadTime, d.Version, d.VCSBranch, d.WorkflowName, d.WorkflowNodeName, d.Number, d.URL); err != nil {
+		return sdk.WrapError(err, "unable to insert deployment of node run %d", nr.ID)
+	}
+	return nil
+}
+
+const deploymentColumns = `deployment.id, deployment.project_id, deployment.application_id, application.name, deployment.environment_id, environment.name,
+	deployment.version, deployment.status, deployment.vcs_branch, deployment.vcs_hash, deployment.workflow_id, deployment.workflow_name,
+	deployment.workflow_node_name, deployment.workflow_run_id, deployment.num, deployment.workflow_node_run_id, deployment.url,
+	deployment.started, deployment.done, deployment.lead_time, deployment.rollback_of`
+
+func loadDeployments(db gorp.SqlExecutor, query string, args ...interface{}) ([]sdk.Deployment, error) {
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, sdk.WithStack(err)
+	}
+	defer rows.Close()
+
+	ds := []sdk.Deployment{}
+	for rows.Next() {
+		var d sdk.Deployment
+		var start pq.NullTime
+		if err := rows.Scan(&d.ID, &d.ProjectID, &d.ApplicationID, &d.ApplicationName, &d.EnvironmentID, &d.EnvironmentName,
+			&d.Version, &d.Status, &d.VCSBranch, &d.VCSHash, &d.WorkflowID, &d.WorkflowName,
+			&d.WorkflowNodeName, &d.WorkflowRunID, &d.Number, &d.WorkflowNodeRunID, &d.URL,
+			&start, &d.Done, &d.LeadTime, &d.RollbackOf); err != nil {
+			return nil, sdk.WithStack(err)
+		}
+		d.Start = start.Time
+		ds = append(ds, d)
+	}
+	return ds, sdk.WithStack(rows.Err())
+}
+
+// LoadDeployments returns the last deployments of a project, the most recent first. Empty application or environment
+// name matches all of them.
+func LoadDeployments(db gorp.SqlExecutor, projectID int64, applicationName, environmentName string, limit int) ([]sdk.Deployment, error) {
+	query := `SELECT ` + deploymentColumns + `
+	FROM deployment
+	JOIN application ON application.id = deployment.application_id
+	JOIN environment ON environment.id = deployment.environment_id
+	WHERE deployment.project_id = $1
+	AND ($2 = '' OR application.name = $2) AND ($3 = '' OR environment.name = $3)
+	ORDER BY deployment.done DESC
+	LIMIT $4`
+	ds, err := loadDeployments(db, query, projectID, applicationName, environmentName, limit)
+	if err != nil {
+		return nil, sdk.WrapError(err, "unable to load deployments of project %d", projectID)
+	}
+	return ds, nil
+}
+
+// LoadDeployment returns a deployment of a project
+func LoadDeployment(db gorp.SqlExecutor, projectID, id int64) (*sdk.Deployment, error) {
+	query := `SELECT ` + deploymentColumns + `
+	FROM deployment
+	JOIN application ON application.id = deployment.application_id
+	JOIN environment ON environment.id = deployment.environment_id
+	WHERE deployment.project_id = $1 AND deployment.id = $2`
+	ds, err := loadDeployments(db, query, projectID, id)
+	if err != nil {
+		return nil, sdk.WrapError(err, "unable to load deployment %d", id)
+	}
+	if len(ds) == 0 {
+		return nil, sdk.WithStack(sdk.ErrNotFound)
+	}
+	return &ds[0], nil
+}
+
+// InsertDeploymentRollback stores a rollback, the deployment of its node run will be recorded as a rollback
+func InsertDeploymentRollback(db gorp.SqlExecutor, rb *sdk.DeploymentRollback) error {
+	artifacts, err := json.Marshal(rb.Artifacts)
+	if err != nil {
+		return sdk.WithStack(err)
+	}
+	rb.Created = time.Now()
+	query := `INSERT INTO deployment_rollback (project_id, deployment_id, workflow_node_run_id, artifacts, author, created)
+	VALUES ($1, $2, $3, $4, $5, $6) RETURNING id`
+	if err := db.QueryRow(query, rb.ProjectID, rb.DeploymentID, rb.WorkflowNodeRunID, artifacts, rb.Author, rb.Created).Scan(&rb.ID); err != nil {
+		return sdk.WrapError(err, "unable to insert rollback of deployment %d", rb.DeploymentID)
+	}
+	return nil
+}
+
+// LoadCurrentDeployments returns the last successful deployment of each application on each environment of a project.
+// Empty environment name matches all of them.
+func LoadCurrentDeployments(db gorp.SqlExecutor, projectID int64, environmentName string) ([]sdk.Deployment, error) {
+	query := `SELECT DISTINCT ON (deployment.application_id, deployment.environment_id) ` + deploymentColumns + `
+	FROM deployment
+	JOIN application ON application.id = deployment.application_id
+	JOIN environment ON environment.id = deployment.environment_id
+	WHERE deployment.project_id = $1 AND deployment.status = $2
+	AND ($3 = '' OR environment.name = $3)
+	ORDER BY deployment.application_id, deployment.environment_id, deployment.done DESC`
+	ds, err := loadDeployments(db, query, projectID, sdk.StatusSuccess.String(), environmentName)
+	if err != nil {
+		return nil, sdk.WrapError(err, "unable to load current deployments of project %d", projectID)
+	}
+	sort.Slice(ds, func(i, j int) bool {
+		if ds[i].EnvironmentName != ds[j].EnvironmentName {
+			return ds[i].EnvironmentName < ds[j].EnvironmentName
+		}
+		return ds[i].ApplicationName < ds[j].ApplicationName
+	})
+	return ds, nil
+}
+
+// LoadDORAMetrics computes the metrics of the deployments of a project ended between the from and to dates, for each
+// application and environment. Empty application or environment name matches all of them.
+func LoadDORAMetrics(db gorp.SqlExecutor, projectID int64, applicationName, environmentName string, from, to time.Time) ([]sdk.DORAMetrics, error) {
+	query := `SELECT application.name, environment.name, deployment.status, deployment.done, deployment.lead_time
+	FROM deployment
+	JOIN application ON application.id = deployment.application_id
+	JOIN environment ON environment.id = deployment.environment_id
+	WHERE deployment.project_id = $1 AND deployment.done >= $2 AND deployment.done < $3
+	AND ($4 = '' OR application.name = $4) AND ($5 = '' OR environment.name = $5)`
+	rows, err := db.Query(query, projectID, from, to, applicationName, environmentName)
+	if err != nil {
+		return nil, sdk.WrapError(err, "unable to load deployments of project %d", projectID)
+	}
+	defer rows.Close()
+
+	type key struct{ application, environment string }
+	deployments := map[key][]sdk.Deployment{}
+	for rows.Next() {
+		var k key
+		var d sdk.Deployment
+		if err := rows.Scan(&k.application, &k.environment, &d.Status, &d.Done, &d.LeadTime); err != nil {
+			return nil, sdk.WithStack(err)
+		}
+		deployments[k] = append(deployments[k], d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, sdk.WithStack(err)
+	}
+
+	metrics := make([]sdk.DORAMetrics, 0, len(deployments))
+	for k, ds := range deployments {
+		m := sdk.ComputeDORAMetrics(ds, from, to)
+		m.ApplicationName, m.EnvironmentName = k.application, k.environment
+		metrics = append(metrics, m)
+	}
+	sort.Slice(metrics, func(i, j int) bool {
+		if metrics[i].ApplicationName != metrics[j].ApplicationName {
+			return metrics[i].ApplicationName < metrics[j].ApplicationName
+		}
+		return metrics[i].EnvironmentName < metrics[j].EnvironmentName
+	})
+	return metrics, nil
+}
@@ -0,0 +1,112 @@
+package workflow
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/go-gorp/gorp"
+	"github.com/lib/pq"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// InsertDurations inserts the durations of a node run, of its stages and of its steps
+func InsertDurations(db gorp.SqlExecutor, durations []sdk.WorkflowRunDuration) error {
+	if len(durations) == 0 {
+		return nil
+	}
+	nodeRun := durations[0]
+	kinds := make([]string, len(durations))
+	stages := make([]string, len(durations))
+	jobs := make([]string, len(durations))
+	orders := make([]int64, len(durations))
+	steps := make([]string, len(durations))
+	statuses := make([]string, len(durations))
+	values := make([]float64, len(durations))
+	for i, d := range durations {
+		kinds[i], stages[i], jobs[i], orders[i], steps[i], statuses[i], values[i] = d.Kind, d.StageName, d.JobName, int64(d.StepOrder), d.StepName, d.Status, d.Duration
+	}
+	query := `INSERT INTO workflow_run_duration (workflow_id, workflow_run_id, workflow_node_run_id, workflow_node_name, pipeline_name, num,
+		kind, stage_name, job_name, step_order, step_name, status, duration)
+	SELECT $1, $2, $3, $4, $5, $6, unnest($7::text[]), unnest($8::text[]), unnest($9::text[]), unnest($10::int[]), unnest($11::text[]), unnest($12::text[]), unnest($13::float8[])`
+	if _, err := db.Exec(query, nodeRun.WorkflowID, nodeRun.WorkflowRunID, nodeRun.WorkflowNodeRunID, nodeRun.WorkflowNodeName, nodeRun.PipelineName, nodeRun.Number,
+		pq.Array(kinds), pq.Array(stages), pq.Array(jobs), pq.Array(orders), pq.Array(steps), pq.Array(statuses), pq.Array(values)); err != nil {
+		return sdk.WrapError(err, "unable to insert durations of node run %d", nodeRun.WorkflowNodeRunID)
+	}
+	return nil
+}
+
+// insertNodeRunDurations stores the durations of a terminated node run
+func insertNodeRunDurations(db gorp.SqlExecutor, wr *sdk.WorkflowRun, nr *sdk.WorkflowNodeRun) error {
+	var pipelineName string
+	if wr.Workflow.WorkflowData != nil {
+		if n := wr.Workflow.WorkflowData.NodeByID(nr.WorkflowNodeID); n != nil && n.Context != nil {
+			pipelineName = wr.Workflow.Pipelines[n.Context.PipelineID].Name
+		}
+	}
+	if pipelineName == "" {
+		return nil
+	}
+	return InsertDurations(db, sdk.NewWorkflowRunDurations(*nr, pipelineName))
+}
+
+// LoadDurationStats computes the median and the 95th percentile of the durations of the pipelines of a workflow, or of their stages,
+// for each period since the given date. Empty pipeline name matches all the pipelines.
+func LoadDurationStats(db gorp.SqlExecutor, workflowID int64, kind, pipelineName, interval string, since time.Time) ([]sdk.WorkflowDurationStats, error) {
+	if !sdk.IsInArray(interval, sdk.DurationIntervals) {
+		return nil, sdk.NewErrorFrom(sdk.ErrWrongRequest, "invalid interval %s", interval)
+	}
+	if kind != sdk.DurationKindPipeline && kind != sdk.DurationKindStage {
+		return nil, sdk.NewErrorFrom(sdk.ErrWrongRequest, "invalid kind %s", kind)
+	}
+	// interval is checked above, it can be safely used in the query
+	query := fmt.Sprintf(`SELECT date_trunc('%s', created) AS period, pipeline_name, coalesce(stage_name, '') AS stage_name, count(*),
+		percentile_cont(0.5) WITHIN GROUP (ORDER BY duration), percentile_cont(0.95) WITHIN GROUP (ORDER BY duration)
+	FROM workflow_run_duration
+	WHERE workflow_id = $1 AND kind = $2 AND created >= $3
+	AND ($4 = '' OR pipeline_name = $4)
+	GROUP BY period, pipeline_name, stage_name
+	ORDER BY period, pipeline_name, stage_name`, interval)
+	rows, err := db.Query(query, workflowID, kind, since, pipelineName)
+	if err != nil {
+		return nil, sdk.WrapError(err, "unable to load durations of workflow %d", workflowID)
+	}
+	defer rows.Close()
+
+	res := []sdk.WorkflowDurationStats{}
+	for rows.Next() {
+		var s sdk.WorkflowDurationStats
+		if err := rows.Scan(&s.Period, &s.PipelineName, &s.StageName, &s.Runs, &s.P50, &s.P95); err != nil {
+			return nil, sdk.WrapError(err, "unable to scan durations")
+		}
+		res = append(res, s)
+	}
+	return res, nil
+}
+
+// LoadSlowestSteps loads the steps of a workflow with the highest average duration since the given date
+func LoadSlowestSteps(db gorp.SqlExecutor, workflowID int64, pipelineName string, since time.Time, limit int) ([]sdk.WorkflowSlowStep, error) {
+	query := `SELECT pipeline_name, coalesce(stage_name, ''), coalesce(job_name, ''), coalesce(step_order, 0), coalesce(step_name, ''), count(*),
+		avg(duration), percentile_cont(0.95) WITHIN GROUP (ORDER BY duration)
+	FROM workflow_run_duration
+	WHERE workflow_id = $1 AND kind = $2 AND created >= $3
+	AND ($4 = '' OR pipeline_name = $4)
+	GROUP BY pipeline_name, stage_name, job_name, step_order, step_name
+	ORDER BY avg(duration) DESC
+	LIMIT $5`
+	rows, err := db.Query(query, workflowID, sdk.DurationKindStep, since, pipelineName, limit)
+	if err != nil {
+		return nil, sdk.WrapError(err, "unable to load slowest steps of workflow %d", workflowID)
+	}
+	defer rows.Close()
+
+	res := []sdk.WorkflowSlowStep{}
+	for rows.Next() {
+		var s sdk.WorkflowSlowStep
+		if err := rows.Scan(&s.PipelineName, &s.StageName, &s.JobName, &s.StepOrder, &s.StepName, &s.Runs, &s.Average, &s.P95); err != nil {
+			return nil, sdk.WrapError(err, "unable to scan slowest steps")
+		}
+		res = append(res, s)
+	}
+	return res, nil
+}
@@ -0,0 +1,55 @@
+package workflow
+
+import (
+	"database/sql"
+
+	"github.com/go-gorp/gorp"
+	"github.com/lib/pq"
+
+	"github.com/ovh/cds/engine/api/database/gorpmapping"
+	"github.com/ovh/cds/sdk"
+)
+
+// InsertSavedFilter saves a filter on the workflows of a project for a user
+func InsertSavedFilter(db gorp.SqlExecutor, f *sdk.WorkflowSavedFilter) error {
+	if err := db.Insert(f); err != nil {
+		if errPG, ok := err.(*pq.Error); ok && errPG.Code == gorpmapping.ViolateUniqueKeyPGCode {
+			return sdk.NewErrorFrom(sdk.ErrConflict, "filter %s already exists", f.Name)
+		}
+		return sdk.WrapError(err, "cannot insert filter %s", f.Name)
+	}
+	return nil
+}
+
+// UpdateSavedFilter updates a saved filter
+func UpdateSavedFilter(db gorp.SqlExecutor, f *sdk.WorkflowSavedFilter) error {
+	_, err := db.Update(f)
+	return sdk.WrapError(err, "cannot update filter %s", f.Name)
+}
+
+// DeleteSavedFilter deletes a saved filter
+func DeleteSavedFilter(db gorp.SqlExecutor, f *sdk.WorkflowSavedFilter) error {
+	_, err := db.Delete(f)
+	return sdk.WrapError(err, "cannot delete filter %s", f.Name)
+}
+
+// LoadSavedFilters returns the filters saved by a user on the workflows of a project
+func LoadSavedFilters(db gorp.SqlExecutor, userID, projectID int64) ([]sdk.WorkflowSavedFilter, error) {
+	fs := []sdk.WorkflowSavedFilter{}
+	if _, err := db.Select(&fs, "SELECT * FROM workflow_saved_filter WHERE user_id = $1 AND project_id = $2 ORDER BY name", userID, projectID); err != nil {
+		return nil, sdk.WrapError(err, "cannot load filters of user %d", userID)
+	}
+	return fs, nil
+}
+
+// LoadSavedFilter returns a filter saved by a user on the workflows of a project
+func LoadSavedFilter(db gorp.SqlExecutor, userID, projectID int64, name string) (*sdk.WorkflowSavedFilter, error) {
+	var f sdk.WorkflowSavedFilter
+	if err := db.SelectOne(&f, "SELECT * FROM workflow_saved_filter WHERE user_id = $1 AND project_id = $2 AND name = $3", userID, projectID, name); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, sdk.NewErrorFrom(sdk.ErrNotFound, "filter %s not found", name)
+		}
+		return nil, sdk.WrapError(err, "cannot load filter %s", name)
+	}
+	return &f, nil
+}
@@ -0,0 +1,119 @@
+package workflow
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// LoadForkApprovals returns the approvals of the forks of a workflow, the pending ones first
+func LoadForkApprovals(db gorp.SqlExecutor, workflowID int64) ([]sdk.WorkflowForkApproval, error) {
+	as := []sdk.WorkflowForkApproval{}
+	if _, err := db.Select(&as, "SELECT * FROM workflow_fork_approval WHERE workflow_id = $1 ORDER BY approved, created DESC", workflowID); err != nil {
+		return nil, sdk.WrapError(err, "cannot load fork approvals of workflow %d", workflowID)
+	}
+	return as, nil
+}
+
+// LoadForkApprovalByID returns an approval of a fork of a workflow
+func LoadForkApprovalByID(db gorp.SqlExecutor, workflowID, id int64) (*sdk.WorkflowForkApproval, error) {
+	var a sdk.WorkflowForkApproval
+	if err := db.SelectOne(&a, "SELECT * FROM workflow_fork_approval WHERE workflow_id = $1 AND id = $2", workflowID, id); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, sdk.NewErrorFrom(sdk.ErrNotFound, "fork approval %d not found", id)
+		}
+		return nil, sdk.WrapError(err, "cannot load fork approval %d", id)
+	}
+	return &a, nil
+}
+
+// LoadForkApproval returns the approval of a branch of a fork, nil if the fork never triggered the workflow
+func LoadForkApproval(db gorp.SqlExecutor, workflowID int64, repository, branch string) (*sdk.WorkflowForkApproval, error) {
+	var a sdk.WorkflowForkApproval
+	if err := db.SelectOne(&a, "SELECT * FROM workflow_fork_approval WHERE workflow_id = $1 AND repository = $2 AND branch = $3", workflowID, repository, branch); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, sdk.WrapError(err, "cannot load fork approval of %s:%s", repository, branch)
+	}
+	return &a, nil
+}
+
+// CheckForkApproval returns an error if the run of a pull request from a fork has to be approved by a maintainer.
+// The hook event of the first run waiting for the approval is kept, it is used to start the run once approved.
+func CheckForkApproval(db gorp.SqlExecutor, w *sdk.Workflow, hook *sdk.WorkflowNodeRunHookEvent) error {
+	if hook == nil || !sdk.IsForkPullRequest(hook.Payload) || !w.ForkPolicy.RequireApproval() {
+		return nil
+	}
+
+	a := sdk.WorkflowForkApproval{
+		WorkflowID: w.ID,
+		Repository: hook.Payload[tagGitRepository],
+		Branch:     hook.Payload[tagGitBranch],
+		Author:     hook.Payload[tagGitAuthor],
+		Created:    time.Now(),
+		HookEvent:  *hook,
+	}
+
+	existing, err := LoadForkApproval(db, w.ID, a.Repository, a.Branch)
+	if err != nil {
+		return err
+	}
+	if existing != nil && existing.Approved {
+		return nil
+	}
+	if existing == nil {
+		if err := db.Insert(&a); err != nil {
+			return sdk.WrapError(err, "cannot insert fork approval of %s", a)
+		}
+	} else {
+		// only the latest event of a fork waiting for the approval is run
+		existing.HookEvent = *hook
+		if _, err := db.Update(existing); err != nil {
+			return sdk.WrapError(err, "cannot update fork approval of %s", a)
+		}
+	}
+	return sdk.NewErrorFrom(sdk.ErrForkApprovalRequired, "the run of %s is waiting for the approval of a maintainer", a)
+}
+
+// ApproveFork approves the runs of a branch of a fork
+func ApproveFork(db gorp.SqlExecutor, a *sdk.WorkflowForkApproval, u *sdk.User) error {
+	now := time.Now()
+	a.Approved = true
+	a.Approver = u.Username
+	a.ApprovalDate = &now
+	if _, err := db.Update(a); err != nil {
+		return sdk.WrapError(err, "cannot approve fork %s", a)
+	}
+	return nil
+}
+
+// DeleteForkApproval revokes the approval of a branch of a fork
+func DeleteForkApproval(db gorp.SqlExecutor, a *sdk.WorkflowForkApproval) error {
+	_, err := db.Delete(a)
+	return sdk.WrapError(err, "cannot delete fork approval %s", a)
+}
+
+// ForkPolicy returns the fork policy applied to a run triggered by a pull request from a fork, nil for the other runs
+func ForkPolicy(wr *sdk.WorkflowRun) *sdk.WorkflowForkPolicy {
+	if wr.Workflow.WorkflowData == nil {
+		return nil
+	}
+	root := wr.RootRun()
+	if root == nil || root.HookEvent == nil || !sdk.IsForkPullRequest(root.HookEvent.Payload) {
+		return nil
+	}
+	return &wr.Workflow.ForkPolicy
+}
+
+// ForkSecrets filters the secrets of a job of a run triggered by a pull request from a fork
+func ForkSecrets(wr *sdk.WorkflowRun, secrets []sdk.Variable) []sdk.Variable {
+	policy := ForkPolicy(wr)
+	if policy == nil {
+		return secrets
+	}
+	return policy.FilterSecrets(secrets)
+}
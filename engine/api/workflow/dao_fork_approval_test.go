@@ -0,0 +1,35 @@
+package workflow
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func TestForkSecrets(t *testing.T) {
+	secrets := []sdk.Variable{
+		{Name: "cds.proj.password", Value: "p"},
+		{Name: "cds.app.sonar_token", Value: "s"},
+	}
+
+	wr := &sdk.WorkflowRun{
+		Workflow: sdk.Workflow{WorkflowData: &sdk.WorkflowData{Node: sdk.Node{ID: 1}}},
+		WorkflowNodeRuns: map[int64][]sdk.WorkflowNodeRun{
+			1: {{ID: 1, HookEvent: &sdk.WorkflowNodeRunHookEvent{Payload: map[string]string{"git.branch": "master"}}}},
+		},
+	}
+	assert.Equal(t, secrets, ForkSecrets(wr, secrets), "a run which is not triggered by a fork gets all the secrets")
+	assert.Nil(t, ForkPolicy(wr))
+
+	wr.WorkflowNodeRuns[1][0].HookEvent.Payload[sdk.PayloadPullRequestFork] = "true"
+	assert.Empty(t, ForkSecrets(wr, secrets), "the secrets are withheld by default")
+	assert.NotNil(t, ForkPolicy(wr))
+
+	wr.Workflow.ForkPolicy = sdk.WorkflowForkPolicy{Secrets: sdk.ForkSecretsRestricted, AllowedSecrets: []string{"cds.app.sonar_token"}}
+	assert.Equal(t, secrets[1:], ForkSecrets(wr, secrets))
+
+	wr.Workflow.ForkPolicy = sdk.WorkflowForkPolicy{Secrets: sdk.ForkSecretsAll}
+	assert.Equal(t, secrets, ForkSecrets(wr, secrets))
+}
@@ -26,6 +26,21 @@ func UpdateHook(db gorp.SqlExecutor, h *sdk.WorkflowNodeHook) error {
 	return nil
 }
 
+// UpdateHookConfig updates the configuration of a hook, on the workflow node hook and on the node hook of the workflow data
+func UpdateHookConfig(db gorp.SqlExecutor, h *sdk.WorkflowNodeHook) error {
+	if err := UpdateHook(db, h); err != nil {
+		return err
+	}
+	config, err := gorpmapping.JSONToNullString(h.Config)
+	if err != nil {
+		return sdk.WrapError(err, "unable to marshal config")
+	}
+	if _, err := db.Exec("UPDATE w_node_hook SET config = $1 WHERE uuid = $2", config, h.UUID); err != nil {
+		return sdk.WrapError(err, "unable to update config")
+	}
+	return nil
+}
+
 // DeleteHook Delete a workflow node hook
 func DeleteHook(db gorp.SqlExecutor, h *sdk.WorkflowNodeHook) error {
 	dbhook := NodeHook(*h)
@@ -132,7 +147,7 @@ func (r *NodeHook) PostGet(db gorp.SqlExecutor) error {
 	return nil
 }
 
-// LoadAllHooks returns all hooks
+// LoadAllHooks returns all hooks, with the clear secrets of the repository webhooks for the hooks µService
 func LoadAllHooks(db gorp.SqlExecutor) ([]sdk.WorkflowNodeHook, error) {
 	res := []NodeHook{}
 	if _, err := db.Select(&res, "select id, uuid, ref, workflow_hook_model_id, workflow_node_id from workflow_node_hook"); err != nil {
@@ -143,11 +158,30 @@ func LoadAllHooks(db gorp.SqlExecutor) ([]sdk.WorkflowNodeHook, error) {
 	}
 
 	nodes := []sdk.WorkflowNodeHook{}
+	projectIDs := map[string]int64{}
 	for i := range res {
 		if err := res[i].PostGet(db); err != nil {
 			return nil, sdk.WrapError(err, "LoadAllHooks")
 		}
-		nodes = append(nodes, sdk.WorkflowNodeHook(res[i]))
+		h := sdk.WorkflowNodeHook(res[i])
+		if _, ok := h.Config[sdk.RepositoryWebHookSecret]; ok {
+			key := h.Config[sdk.HookConfigProject].Value
+			projectID, ok := projectIDs[key]
+			if !ok {
+				id, err := db.SelectInt("SELECT id FROM project WHERE projectkey = $1", key)
+				if err != nil {
+					return nil, sdk.WrapError(err, "cannot load project %s", key)
+				}
+				projectID = id
+				projectIDs[key] = id
+			}
+			var err error
+			h, err = WithClearWebHookSecret(projectID, h)
+			if err != nil {
+				return nil, sdk.WrapError(err, "cannot decrypt secret of hook %s", h.UUID)
+			}
+		}
+		nodes = append(nodes, h)
 	}
 
 	return nodes, nil
@@ -71,3 +71,12 @@ func LabelsByProjectID(db gorp.SqlExecutor, projectID int64) ([]sdk.Label, error
 
 	return labels, nil
 }
+
+// IsLabeled returns true if a label is linked to a workflow
+func IsLabeled(db gorp.SqlExecutor, labelID, workflowID int64) (bool, error) {
+	count, err := db.SelectInt("SELECT COUNT(1) FROM project_label_workflow WHERE label_id = $1 AND workflow_id = $2", labelID, workflowID)
+	if err != nil {
+		return false, sdk.WrapError(err, "cannot check label %d on workflow %d", labelID, workflowID)
+	}
+	return count > 0, nil
+}
@@ -57,6 +57,26 @@ func CountNodeJobRunQueue(ctx context.Context, db gorp.SqlExecutor, store cache.
 	return c, nil
 }
 
+// CountNodeJobRunsByStatus counts all the jobs in the queue by status
+func CountNodeJobRunsByStatus(db gorp.SqlExecutor) (map[string]int64, error) {
+	rows, err := db.Query(`SELECT status, COUNT(id) FROM workflow_node_run_job GROUP BY status`)
+	if err != nil {
+		return nil, sdk.WrapError(err, "cannot count jobs")
+	}
+	defer rows.Close()
+
+	res := map[string]int64{}
+	for rows.Next() {
+		var status string
+		var n int64
+		if err := rows.Scan(&status, &n); err != nil {
+			return nil, sdk.WithStack(err)
+		}
+		res[status] = n
+	}
+	return res, nil
+}
+
 // LoadNodeJobRunQueue load all workflow_node_run_job accessible
 func LoadNodeJobRunQueue(ctx context.Context, db gorp.SqlExecutor, store cache.Store, filter QueueFilter) ([]sdk.WorkflowNodeJobRun, error) {
 	ctx, end := observability.Span(ctx, "LoadNodeJobRunQueue")
@@ -104,7 +124,7 @@ func LoadNodeJobRunQueue(ctx context.Context, db gorp.SqlExecutor, store cache.S
 	and workflow_node_run_job.status = ANY(string_to_array($3, ','))
 	AND contains_service IN ($4, $5)
 	AND (model_type is NULL OR model_type = '' OR model_type = ANY(string_to_array($6, ',')))
-	ORDER BY workflow_node_run_job.queued ASC
+	ORDER BY workflow_node_run_job.priority DESC, workflow_node_run_job.queued ASC
 	`
 
 	if filter.User != nil && !filter.User.Admin {
@@ -128,7 +148,7 @@ func LoadNodeJobRunQueue(ctx context.Context, db gorp.SqlExecutor, store cache.S
 		AND workflow_node_run_job.status = ANY(string_to_array($3, ','))
 		AND contains_service IN ($4, $5)
 		AND (model_type is NULL OR model_type = '' OR model_type = ANY(string_to_array($6, ',')))
-		ORDER BY workflow_node_run_job.queued ASC
+		ORDER BY workflow_node_run_job.priority DESC, workflow_node_run_job.queued ASC
 		`
 
 		var groupID string
@@ -28,7 +28,8 @@ workflow_run.last_modified,
 workflow_run.status,
 workflow_run.last_sub_num,
 workflow_run.last_execution,
-workflow_run.to_delete
+workflow_run.to_delete,
+workflow_run.priority
 `
 
 // LoadRunOptions are options for loading a run (node or workflow)
@@ -91,6 +92,21 @@ func UpdateWorkflowRunStatus(db gorp.SqlExecutor, wr *sdk.WorkflowRun) error {
 	return nil
 }
 
+// UpdateWorkflowRunPriority sets the priority of a workflow run and of its jobs which are not started yet
+func UpdateWorkflowRunPriority(db gorp.SqlExecutor, wr *sdk.WorkflowRun, priority int) error {
+	if _, err := db.Exec("UPDATE workflow_run SET priority = $1 WHERE id = $2", priority, wr.ID); err != nil {
+		return sdk.WrapError(err, "Unable to set priority of workflow_run id %d", wr.ID)
+	}
+	query := `UPDATE workflow_node_run_job SET priority = $1
+	WHERE status = $2
+	AND workflow_node_run_id IN (SELECT id FROM workflow_node_run WHERE workflow_run_id = $3)`
+	if _, err := db.Exec(query, priority, sdk.StatusWaiting.String(), wr.ID); err != nil {
+		return sdk.WrapError(err, "Unable to set priority of the jobs of workflow_run id %d", wr.ID)
+	}
+	wr.Priority = priority
+	return nil
+}
+
 // LoadWorkflowFromWorkflowRunID loads the workflow for the given workfloxw run id
 func LoadWorkflowFromWorkflowRunID(db gorp.SqlExecutor, wrID int64) (sdk.Workflow, error) {
 	var workflow sdk.Workflow
@@ -553,6 +569,50 @@ ORDER BY tags.tag;
 	return rmap, nil
 }
 
+// CountActiveRunsByProjectID returns the number of runs of the project which are not finished
+func CountActiveRunsByProjectID(db gorp.SqlExecutor, projectID int64) (int64, error) {
+	n, err := db.SelectInt("SELECT COUNT(id) FROM workflow_run WHERE project_id = $1 AND status = ANY(string_to_array($2, ','))",
+		projectID, strings.Join([]string{sdk.StatusBuilding.String(), sdk.StatusWaiting.String(), sdk.StatusChecking.String()}, ","))
+	if err != nil {
+		return 0, sdk.WrapError(err, "cannot count runs of project %d", projectID)
+	}
+	return n, nil
+}
+
+// LoadSupersededRunNumbers returns the numbers of the runs of a workflow which are not finished, older than the given run
+// and built from another commit of the same branch of the same repository
+func LoadSupersededRunNumbers(db gorp.SqlExecutor, wr *sdk.WorkflowRun) ([]int64, error) {
+	var repository, branch, hash string
+	for _, t := range wr.Tags {
+		switch t.Tag {
+		case tagGitRepository:
+			repository = t.Value
+		case tagGitBranch:
+			branch = t.Value
+		case tagGitHash:
+			hash = t.Value
+		}
+	}
+	if repository == "" || branch == "" || hash == "" {
+		return nil, nil
+	}
+
+	query := `SELECT workflow_run.num FROM workflow_run
+	JOIN workflow_run_tag repository ON repository.workflow_run_id = workflow_run.id AND repository.tag = $3 AND repository.value = $4
+	JOIN workflow_run_tag branch ON branch.workflow_run_id = workflow_run.id AND branch.tag = $5 AND branch.value = $6
+	JOIN workflow_run_tag hash ON hash.workflow_run_id = workflow_run.id AND hash.tag = $7 AND hash.value <> $8
+	WHERE workflow_run.workflow_id = $1 AND workflow_run.num < $2
+	AND workflow_run.status = ANY(string_to_array($9, ','))
+	ORDER BY workflow_run.num`
+	var nums []int64
+	if _, err := db.Select(&nums, query, wr.WorkflowID, wr.Number,
+		tagGitRepository, repository, tagGitBranch, branch, tagGitHash, hash,
+		strings.Join([]string{sdk.StatusBuilding.String(), sdk.StatusWaiting.String(), sdk.StatusChecking.String()}, ",")); err != nil {
+		return nil, sdk.WrapError(err, "cannot load the runs superseded by run %d", wr.Number)
+	}
+	return nums, nil
+}
+
 // LoadCurrentRunNum load the current num from workflow_sequences table
 func LoadCurrentRunNum(db gorp.SqlExecutor, projectkey, workflowname string) (int64, error) {
 	query := `SELECT COALESCE(workflow_sequences.current_val, 0) as run_num
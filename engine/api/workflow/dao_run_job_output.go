@@ -0,0 +1,93 @@
+package workflow
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// SaveRunJobOutput inserts an output of a job, or updates its value if the job has already set it
+func SaveRunJobOutput(db gorp.SqlExecutor, o *sdk.WorkflowRunJobOutput) error {
+	o.Created = time.Now()
+	query := `INSERT INTO workflow_run_job_output (workflow_run_id, workflow_node_run_id, workflow_run_job_id, workflow_node_name, sub_num, job_name, key, value, created)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	ON CONFLICT (workflow_node_run_id, job_name, key) DO UPDATE SET workflow_run_job_id = $3, value = $8, created = $9
+	RETURNING id`
+	id, err := db.SelectInt(query, o.WorkflowRunID, o.WorkflowNodeRunID, o.WorkflowRunJobID, o.WorkflowNodeName, o.SubNumber, o.JobName, o.Key, o.Value, o.Created)
+	if err != nil {
+		return sdk.WrapError(err, "unable to save output %s of job %s", o.Key, o.JobName)
+	}
+	o.ID = id
+	return nil
+}
+
+// LoadRunJobOutputs loads the outputs of the jobs of a workflow run
+func LoadRunJobOutputs(db gorp.SqlExecutor, workflowRunID int64) ([]sdk.WorkflowRunJobOutput, error) {
+	query := `SELECT id, workflow_run_id, workflow_node_run_id, workflow_run_job_id, workflow_node_name, sub_num, job_name, key, coalesce(value, ''), created
+	FROM workflow_run_job_output
+	WHERE workflow_run_id = $1
+	ORDER BY id`
+	rows, err := db.Query(query, workflowRunID)
+	if err != nil {
+		return nil, sdk.WrapError(err, "unable to load outputs of workflow run %d", workflowRunID)
+	}
+	defer rows.Close()
+
+	outputs := []sdk.WorkflowRunJobOutput{}
+	for rows.Next() {
+		var o sdk.WorkflowRunJobOutput
+		if err := rows.Scan(&o.ID, &o.WorkflowRunID, &o.WorkflowNodeRunID, &o.WorkflowRunJobID, &o.WorkflowNodeName, &o.SubNumber, &o.JobName, &o.Key, &o.Value, &o.Created); err != nil {
+			return nil, sdk.WrapError(err, "unable to scan output")
+		}
+		outputs = append(outputs, o)
+	}
+	return outputs, nil
+}
+
+// AddRunJobOutput records an output set by a job and adds it to the build parameters of its node run, so that
+// it is given to the jobs of the next stages and inherited by the next nodes of the workflow. The node run is
+// locked as the jobs of a stage can set their outputs at the same time.
+func AddRunJobOutput(ctx context.Context, db gorp.SqlExecutor, job *sdk.WorkflowNodeJobRun, key, value string) (*sdk.WorkflowRunJobOutput, error) {
+	nodeRun, err := LoadAndLockNodeRunByID(ctx, db, job.WorkflowNodeRunID, true)
+	if err != nil {
+		return nil, sdk.WrapError(err, "unable to load node run %d", job.WorkflowNodeRunID)
+	}
+
+	o := sdk.WorkflowRunJobOutput{
+		WorkflowRunID:     nodeRun.WorkflowRunID,
+		WorkflowNodeRunID: nodeRun.ID,
+		WorkflowRunJobID:  job.ID,
+		WorkflowNodeName:  nodeRun.WorkflowNodeName,
+		SubNumber:         nodeRun.SubNumber,
+		JobName:           job.Job.Action.Name,
+		Key:               key,
+		Value:             value,
+	}
+	if err := o.IsValid(); err != nil {
+		return nil, err
+	}
+	if err := SaveRunJobOutput(db, &o); err != nil {
+		return nil, err
+	}
+
+	name := o.ParameterName()
+	var found bool
+	for i := range nodeRun.BuildParameters {
+		if nodeRun.BuildParameters[i].Name == name {
+			nodeRun.BuildParameters[i].Value = value
+			found = true
+			break
+		}
+	}
+	if !found {
+		sdk.AddParameter(&nodeRun.BuildParameters, name, sdk.StringParameter, value)
+	}
+	if err := UpdateNodeRunBuildParameters(db, nodeRun.ID, nodeRun.BuildParameters); err != nil {
+		return nil, sdk.WrapError(err, "unable to update node run %d", nodeRun.ID)
+	}
+
+	return &o, nil
+}
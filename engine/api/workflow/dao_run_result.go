@@ -0,0 +1,71 @@
+package workflow
+
+import (
+	"time"
+
+	"github.com/go-gorp/gorp"
+	"github.com/lib/pq"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// InsertRunResult inserts a result of a workflow run
+func InsertRunResult(db gorp.SqlExecutor, r *sdk.WorkflowRunResult) error {
+	r.Created = time.Now()
+	dbResult := dbWorkflowRunResult(*r)
+	if err := db.Insert(&dbResult); err != nil {
+		return sdk.WrapError(err, "unable to insert %s result", r.Type)
+	}
+	r.ID = dbResult.ID
+	return nil
+}
+
+// PostInsert is a db hook
+func (r *dbWorkflowRunResult) PostInsert(db gorp.SqlExecutor) error {
+	if _, err := db.Exec("UPDATE workflow_run_result SET data = $1 WHERE id = $2", string(r.Data), r.ID); err != nil {
+		return sdk.WrapError(err, "unable to insert data of result %d", r.ID)
+	}
+	return nil
+}
+
+// LoadRunResults loads the results of a workflow run, of the given types or all of them
+func LoadRunResults(db gorp.SqlExecutor, workflowRunID int64, types ...string) ([]sdk.WorkflowRunResult, error) {
+	query := `SELECT id, workflow_run_id, workflow_node_run_id, workflow_run_job_id, workflow_node_name, sub_num, type, coalesce(name, ''), created, coalesce(data::text, '{}')
+	FROM workflow_run_result
+	WHERE workflow_run_id = $1 AND (cardinality($2::text[]) = 0 OR type = ANY($2::text[]))
+	ORDER BY id`
+	rows, err := db.Query(query, workflowRunID, pq.StringArray(types))
+	if err != nil {
+		return nil, sdk.WrapError(err, "unable to load results of workflow run %d", workflowRunID)
+	}
+	defer rows.Close()
+
+	results := []sdk.WorkflowRunResult{}
+	for rows.Next() {
+		var r sdk.WorkflowRunResult
+		var data string
+		if err := rows.Scan(&r.ID, &r.WorkflowRunID, &r.WorkflowNodeRunID, &r.WorkflowRunJobID, &r.WorkflowNodeName, &r.SubNumber, &r.Type, &r.Name, &r.Created, &data); err != nil {
+			return nil, sdk.WrapError(err, "unable to scan result")
+		}
+		r.Data = []byte(data)
+		results = append(results, r)
+	}
+	return results, nil
+}
+
+// AddRunResult records a result produced by a job of a node run
+func AddRunResult(db gorp.SqlExecutor, nodeRun sdk.WorkflowNodeRun, jobID int64, resultType string, data interface{}) (*sdk.WorkflowRunResult, error) {
+	r, err := sdk.NewWorkflowRunResult(resultType, data)
+	if err != nil {
+		return nil, err
+	}
+	r.WorkflowRunID = nodeRun.WorkflowRunID
+	r.WorkflowNodeRunID = nodeRun.ID
+	r.WorkflowNodeName = nodeRun.WorkflowNodeName
+	r.SubNumber = nodeRun.SubNumber
+	r.WorkflowRunJobID = jobID
+	if err := InsertRunResult(db, &r); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
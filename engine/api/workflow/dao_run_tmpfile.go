@@ -0,0 +1,81 @@
+package workflow
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/services"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// SaveRunTmpFile inserts a temporary file of a workflow run, or replaces it if a file with the same name has
+// already been pushed in the run
+func SaveRunTmpFile(db gorp.SqlExecutor, f *sdk.WorkflowRunTmpFile) error {
+	f.Created = time.Now()
+	query := `INSERT INTO workflow_run_tmpfile (workflow_run_id, workflow_run_job_id, name, hash, size, created)
+	VALUES ($1, $2, $3, $4, $5, $6)
+	ON CONFLICT (workflow_run_id, name) DO UPDATE SET workflow_run_job_id = $2, hash = $4, size = $5, created = $6
+	RETURNING id`
+	id, err := db.SelectInt(query, f.WorkflowRunID, f.WorkflowRunJobID, f.Name, f.Hash, f.Size, f.Created)
+	if err != nil {
+		return sdk.WrapError(err, "unable to save temporary file %s", f.Name)
+	}
+	f.ID = id
+	return nil
+}
+
+// LoadRunTmpFile loads a temporary file of a workflow run by its name
+func LoadRunTmpFile(db gorp.SqlExecutor, workflowRunID int64, name string) (*sdk.WorkflowRunTmpFile, error) {
+	var f sdk.WorkflowRunTmpFile
+	query := `SELECT id, workflow_run_id, workflow_run_job_id, name, hash, size, created
+	FROM workflow_run_tmpfile
+	WHERE workflow_run_id = $1 AND name = $2`
+	if err := db.QueryRow(query, workflowRunID, name).Scan(&f.ID, &f.WorkflowRunID, &f.WorkflowRunJobID, &f.Name, &f.Hash, &f.Size, &f.Created); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, sdk.NewErrorFrom(sdk.ErrNotFound, "temporary file %s not found", name)
+		}
+		return nil, sdk.WrapError(err, "unable to load temporary file %s", name)
+	}
+	return &f, nil
+}
+
+// PurgeRunTmpFiles deletes the temporary files of a workflow run, and their content from the CDN service when
+// no other run references it
+func PurgeRunTmpFiles(ctx context.Context, db gorp.SqlExecutor, workflowRunID int64) error {
+	var hashes []string
+	if _, err := db.Select(&hashes, "DELETE FROM workflow_run_tmpfile WHERE workflow_run_id = $1 RETURNING hash", workflowRunID); err != nil {
+		return sdk.WrapError(err, "unable to delete temporary files of workflow run %d", workflowRunID)
+	}
+	if len(hashes) == 0 {
+		return nil
+	}
+
+	srvs, err := services.FindByType(db, services.TypeCDN)
+	if err != nil {
+		return sdk.WrapError(err, "unable to load cdn services")
+	}
+	if len(srvs) == 0 {
+		return nil
+	}
+
+	for _, h := range hashes {
+		n, err := db.SelectInt("SELECT COUNT(1) FROM workflow_run_tmpfile WHERE hash = $1", h)
+		if err != nil {
+			return sdk.WrapError(err, "unable to count references to temporary file %s", h)
+		}
+		if n > 0 {
+			continue
+		}
+		path := fmt.Sprintf("/item/%s?type=%s", h, sdk.CDNItemTypeTmpFile)
+		if _, code, err := services.DoRequest(ctx, srvs, "DELETE", path, nil); err != nil && code != http.StatusNotFound {
+			log.Warning("PurgeRunTmpFiles> unable to delete temporary file %s of workflow run %d: %v", h, workflowRunID, err)
+		}
+	}
+	return nil
+}
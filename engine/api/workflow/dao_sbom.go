@@ -0,0 +1,117 @@
+package workflow
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/go-gorp/gorp"
+	"github.com/lib/pq"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// InsertSBOM inserts a SBOM document and its components
+func InsertSBOM(db gorp.SqlExecutor, sbom *sdk.WorkflowRunSBOM) error {
+	sbom.Created = time.Now()
+	dbSBOM := dbWorkflowRunSBOM(*sbom)
+	if err := db.Insert(&dbSBOM); err != nil {
+		return sdk.WrapError(err, "unable to insert sbom")
+	}
+	sbom.ID = dbSBOM.ID
+	return nil
+}
+
+// PostInsert is a db hook
+func (s *dbWorkflowRunSBOM) PostInsert(db gorp.SqlExecutor) error {
+	if _, err := db.Exec("UPDATE workflow_run_sbom SET document = $1 WHERE id = $2", string(s.Document), s.ID); err != nil {
+		return sdk.WrapError(err, "unable to insert sbom document")
+	}
+
+	if len(s.Components) == 0 {
+		return nil
+	}
+	names := make([]string, len(s.Components))
+	versions := make([]string, len(s.Components))
+	types := make([]string, len(s.Components))
+	purls := make([]string, len(s.Components))
+	for i, c := range s.Components {
+		names[i], versions[i], types[i], purls[i] = c.Name, c.Version, c.Type, c.PURL
+	}
+	query := `INSERT INTO workflow_run_sbom_component (sbom_id, name, version, type, purl)
+	SELECT $1, unnest($2::text[]), unnest($3::text[]), unnest($4::text[]), unnest($5::text[])`
+	if _, err := db.Exec(query, s.ID, pq.Array(names), pq.Array(versions), pq.Array(types), pq.Array(purls)); err != nil {
+		return sdk.WrapError(err, "unable to insert sbom components")
+	}
+	return nil
+}
+
+// LoadSBOMsByRunID loads the SBOM documents of a workflow run, without their content
+func LoadSBOMsByRunID(db gorp.SqlExecutor, workflowRunID int64) ([]sdk.WorkflowRunSBOM, error) {
+	var dbSBOMs []dbWorkflowRunSBOM
+	query := `SELECT id, project_id, workflow_id, workflow_run_id, workflow_node_run_id, name, format, created
+	FROM workflow_run_sbom WHERE workflow_run_id = $1 ORDER BY id`
+	if _, err := db.Select(&dbSBOMs, query, workflowRunID); err != nil {
+		return nil, sdk.WrapError(err, "unable to load sbom for workflow run %d", workflowRunID)
+	}
+
+	sboms := make([]sdk.WorkflowRunSBOM, len(dbSBOMs))
+	for i := range dbSBOMs {
+		sboms[i] = sdk.WorkflowRunSBOM(dbSBOMs[i])
+	}
+	return sboms, nil
+}
+
+// LoadSBOM loads a SBOM document of a workflow run with its content
+func LoadSBOM(db gorp.SqlExecutor, workflowRunID, id int64) (*sdk.WorkflowRunSBOM, error) {
+	var dbSBOM dbWorkflowRunSBOM
+	query := `SELECT id, project_id, workflow_id, workflow_run_id, workflow_node_run_id, name, format, created
+	FROM workflow_run_sbom WHERE workflow_run_id = $1 AND id = $2`
+	if err := db.SelectOne(&dbSBOM, query, workflowRunID, id); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, sdk.WithStack(sdk.ErrNotFound)
+		}
+		return nil, sdk.WrapError(err, "unable to load sbom %d", id)
+	}
+
+	var document sql.NullString
+	if err := db.QueryRow("SELECT document FROM workflow_run_sbom WHERE id = $1", id).Scan(&document); err != nil {
+		return nil, sdk.WrapError(err, "unable to load sbom document %d", id)
+	}
+
+	sbom := sdk.WorkflowRunSBOM(dbSBOM)
+	sbom.Document = []byte(document.String)
+	return &sbom, nil
+}
+
+// LoadSBOMComponentRuns loads the workflow runs of a project with a component in their SBOM.
+// An empty version matches all the versions of the component.
+func LoadSBOMComponentRuns(db gorp.SqlExecutor, projectID int64, name, version string, limit int) ([]sdk.WorkflowRunSBOMComponent, error) {
+	query := `SELECT workflow.name, workflow_run.id, workflow_run.num, workflow_run_sbom.id, workflow_run_sbom.name, workflow_run_sbom.created,
+		workflow_run_sbom_component.name, workflow_run_sbom_component.version,
+		coalesce(workflow_run_sbom_component.type, ''), coalesce(workflow_run_sbom_component.purl, '')
+	FROM workflow_run_sbom_component
+	JOIN workflow_run_sbom ON workflow_run_sbom.id = workflow_run_sbom_component.sbom_id
+	JOIN workflow_run ON workflow_run.id = workflow_run_sbom.workflow_run_id
+	JOIN workflow ON workflow.id = workflow_run_sbom.workflow_id
+	WHERE workflow_run_sbom.project_id = $1
+	AND workflow_run_sbom_component.name = $2
+	AND ($3 = '' OR workflow_run_sbom_component.version = $3)
+	ORDER BY workflow_run_sbom.created DESC
+	LIMIT $4`
+	rows, err := db.Query(query, projectID, name, version, limit)
+	if err != nil {
+		return nil, sdk.WrapError(err, "unable to load runs with component %s", name)
+	}
+	defer rows.Close()
+
+	res := []sdk.WorkflowRunSBOMComponent{}
+	for rows.Next() {
+		var c sdk.WorkflowRunSBOMComponent
+		if err := rows.Scan(&c.WorkflowName, &c.WorkflowRunID, &c.WorkflowRunNumber, &c.SBOMID, &c.SBOMName, &c.Created,
+			&c.Name, &c.Version, &c.Type, &c.PURL); err != nil {
+			return nil, sdk.WrapError(err, "unable to scan component")
+		}
+		res = append(res, c)
+	}
+	return res, nil
+}
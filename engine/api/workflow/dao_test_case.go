@@ -0,0 +1,133 @@
+package workflow
+
+import (
+	"sort"
+
+	"github.com/go-gorp/gorp"
+	"github.com/lib/pq"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// InsertTestCases inserts the results of the test cases of a node run
+func InsertTestCases(db gorp.SqlExecutor, cases []sdk.WorkflowRunTestCase) error {
+	if len(cases) == 0 {
+		return nil
+	}
+	nodeRun := cases[0]
+	suites := make([]string, len(cases))
+	classnames := make([]string, len(cases))
+	names := make([]string, len(cases))
+	statuses := make([]string, len(cases))
+	durations := make([]float64, len(cases))
+	for i, c := range cases {
+		suites[i], classnames[i], names[i], statuses[i], durations[i] = c.TestSuite, c.Classname, c.Name, c.Status, c.Duration
+	}
+	query := `INSERT INTO workflow_run_test_case (workflow_id, workflow_run_id, workflow_node_run_id, workflow_node_name, num, sub_num, vcs_branch,
+		test_suite, classname, name, status, duration)
+	SELECT $1, $2, $3, $4, $5, $6, $7, unnest($8::text[]), unnest($9::text[]), unnest($10::text[]), unnest($11::text[]), unnest($12::float8[])`
+	if _, err := db.Exec(query, nodeRun.WorkflowID, nodeRun.WorkflowRunID, nodeRun.WorkflowNodeRunID, nodeRun.WorkflowNodeName,
+		nodeRun.Number, nodeRun.SubNumber, nodeRun.VCSBranch,
+		pq.Array(suites), pq.Array(classnames), pq.Array(names), pq.Array(statuses), pq.Array(durations)); err != nil {
+		return sdk.WrapError(err, "unable to insert test cases of node run %d", nodeRun.WorkflowNodeRunID)
+	}
+	return nil
+}
+
+// LoadTestCaseHistory loads the last executions of a test case of a workflow node, from the most recent.
+// Empty branch and status match all the executions.
+func LoadTestCaseHistory(db gorp.SqlExecutor, workflowID int64, nodeName, testSuite, name, branch, status string, limit int) ([]sdk.WorkflowRunTestCase, error) {
+	var dbCases []dbWorkflowRunTestCase
+	query := `SELECT id, workflow_id, workflow_run_id, workflow_node_run_id, workflow_node_name, num, sub_num, coalesce(vcs_branch, '') AS vcs_branch,
+		test_suite, coalesce(classname, '') AS classname, name, status, coalesce(duration, 0) AS duration, created
+	FROM workflow_run_test_case
+	WHERE workflow_id = $1 AND workflow_node_name = $2 AND test_suite = $3 AND name = $4
+	AND ($5 = '' OR vcs_branch = $5)
+	AND ($6 = '' OR status = $6)
+	ORDER BY id DESC
+	LIMIT $7`
+	if _, err := db.Select(&dbCases, query, workflowID, nodeName, testSuite, name, branch, status, limit); err != nil {
+		return nil, sdk.WrapError(err, "unable to load history of test case %s/%s", testSuite, name)
+	}
+
+	cases := make([]sdk.WorkflowRunTestCase, len(dbCases))
+	for i := range dbCases {
+		cases[i] = sdk.WorkflowRunTestCase(dbCases[i])
+	}
+	return cases, nil
+}
+
+// LoadTestCasesFlakiness computes the flakiness of the test cases of a workflow on their last executions.
+// Empty node name and branch match all the executions. Only the flaky test cases are returned, the most unstable first.
+func LoadTestCasesFlakiness(db gorp.SqlExecutor, workflowID int64, nodeName, branch string, window int) ([]sdk.WorkflowTestCaseFlakiness, error) {
+	query := `SELECT workflow_node_name, test_suite, name, status FROM (
+		SELECT workflow_node_name, test_suite, name, status, id,
+			row_number() OVER (PARTITION BY workflow_node_name, test_suite, name ORDER BY id DESC) AS rank
+		FROM workflow_run_test_case
+		WHERE workflow_id = $1
+		AND ($2 = '' OR workflow_node_name = $2)
+		AND ($3 = '' OR vcs_branch = $3)
+	) AS executions
+	WHERE rank <= $4
+	ORDER BY workflow_node_name, test_suite, name, id DESC`
+	rows, err := db.Query(query, workflowID, nodeName, branch, window)
+	if err != nil {
+		return nil, sdk.WrapError(err, "unable to load executions of test cases of workflow %d", workflowID)
+	}
+	defer rows.Close()
+
+	res := []sdk.WorkflowTestCaseFlakiness{}
+	var current sdk.WorkflowTestCaseFlakiness
+	var statuses []string
+	flush := func() {
+		if len(statuses) == 0 {
+			return
+		}
+		current.Executions, current.Failures, current.Score = sdk.ComputeFlakiness(statuses)
+		if current.Score > 0 {
+			res = append(res, current)
+		}
+	}
+	for rows.Next() {
+		var node, suite, name, status string
+		if err := rows.Scan(&node, &suite, &name, &status); err != nil {
+			return nil, sdk.WrapError(err, "unable to scan test case")
+		}
+		if node != current.WorkflowNodeName || suite != current.TestSuite || name != current.Name {
+			flush()
+			current = sdk.WorkflowTestCaseFlakiness{WorkflowNodeName: node, TestSuite: suite, Name: name}
+			statuses = nil
+		}
+		statuses = append(statuses, status)
+	}
+	flush()
+
+	sort.SliceStable(res, func(i, j int) bool { return res[i].Score > res[j].Score })
+	return res, nil
+}
+
+// LoadNodeRunFlakyTests returns the flaky test cases among the tests of a node run,
+// computed on the executions of the same node on the same branch
+func LoadNodeRunFlakyTests(db gorp.SqlExecutor, nodeRun sdk.WorkflowNodeRun) ([]sdk.WorkflowTestCaseFlakiness, error) {
+	if nodeRun.Tests == nil || len(nodeRun.Tests.TestSuites) == 0 {
+		return nil, nil
+	}
+	flakiness, err := LoadTestCasesFlakiness(db, nodeRun.WorkflowID, nodeRun.WorkflowNodeName, nodeRun.VCSBranch, sdk.FlakinessWindow)
+	if err != nil {
+		return nil, err
+	}
+
+	inRun := map[string]struct{}{}
+	for _, ts := range nodeRun.Tests.TestSuites {
+		for _, tc := range ts.TestCases {
+			inRun[ts.Name+"/"+tc.Name] = struct{}{}
+		}
+	}
+	var res []sdk.WorkflowTestCaseFlakiness
+	for _, f := range flakiness {
+		if _, ok := inRun[f.TestSuite+"/"+f.Name]; ok {
+			res = append(res, f)
+		}
+	}
+	return res, nil
+}
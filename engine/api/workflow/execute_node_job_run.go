@@ -5,7 +5,9 @@ import (
 	"context"
 	"database/sql"
 	"encoding/base64"
+	"encoding/json"
 	"fmt"
+	"strings"
 	"sync"
 	"time"
 
@@ -13,11 +15,16 @@ import (
 	"github.com/lib/pq"
 
 	"github.com/ovh/cds/engine/api/application"
+	"github.com/ovh/cds/engine/api/aws"
 	"github.com/ovh/cds/engine/api/cache"
+	"github.com/ovh/cds/engine/api/cost"
 	"github.com/ovh/cds/engine/api/environment"
-	"github.com/ovh/cds/engine/api/observability"
 	"github.com/ovh/cds/engine/api/integration"
+	"github.com/ovh/cds/engine/api/oauth2"
+	"github.com/ovh/cds/engine/api/observability"
 	"github.com/ovh/cds/engine/api/secret"
+	"github.com/ovh/cds/engine/api/usage"
+	"github.com/ovh/cds/engine/api/vault"
 	"github.com/ovh/cds/sdk"
 	"github.com/ovh/cds/sdk/log"
 )
@@ -66,7 +73,7 @@ func (r *ProcessorReport) Add(i ...interface{}) {
 	}
 }
 
-//All returns all the objects in the reports
+// All returns all the objects in the reports
 func (r *ProcessorReport) All() []interface{} {
 	r.mutex.Lock()
 	defer r.mutex.Unlock()
@@ -146,6 +153,14 @@ func UpdateNodeJobRunStatus(ctx context.Context, dbFunc func() *gorp.DbMap, db g
 		}
 		job.Done = time.Now()
 		job.Status = status.String()
+		if currentStatus == sdk.StatusBuilding.String() && !job.Start.IsZero() {
+			if err := usage.AddBuildTime(db, job.ProjectID, job.Done, job.Done.Sub(job.Start)); err != nil {
+				return nil, err
+			}
+			if err := cost.AddCompute(db, job.ProjectID, nodeRun, cost.ChargedGroupID(job.ExecGroups), job.Done.Sub(job.Start)); err != nil {
+				return nil, err
+			}
+		}
 
 		_, next := observability.Span(ctx, "workflow.LoadRunByID")
 		wf, errLoadWf := LoadRunByID(db, nodeRun.WorkflowRunID, LoadRunOptions{})
@@ -384,7 +399,7 @@ func LoadNodeJobRunKeys(p *sdk.Project, wr *sdk.WorkflowRun, nodeRun *sdk.Workfl
 			if err64 != nil {
 				return nil, nil, sdk.WrapError(err64, "LoadNodeJobRunKeys> Cannot app decode key %s", k.Name)
 			}
-			decrypted, errD := secret.Decrypt([]byte(unBase64))
+			decrypted, errD := secret.DecryptForProject(wr.Workflow.ProjectID, []byte(unBase64))
 			if errD != nil {
 				log.Error("LoadNodeJobRunKeys> Unable to decrypt app private key %s/%s: %v", app.Name, k.Name, errD)
 			}
@@ -413,7 +428,7 @@ func LoadNodeJobRunKeys(p *sdk.Project, wr *sdk.WorkflowRun, nodeRun *sdk.Workfl
 			if err64 != nil {
 				return nil, nil, sdk.WrapError(err64, "LoadNodeJobRunKeys> Cannot decode env key %s", k.Name)
 			}
-			decrypted, errD := secret.Decrypt([]byte(unBase64))
+			decrypted, errD := secret.DecryptForProject(wr.Workflow.ProjectID, []byte(unBase64))
 			if errD != nil {
 				log.Error("LoadNodeJobRunKeys> Unable to decrypt env private key %s/%s: %v", env.Name, k.Name, errD)
 			}
@@ -446,6 +461,8 @@ func LoadSecrets(db gorp.SqlExecutor, store cache.Store, nodeRun *sdk.WorkflowNo
 		if node != nil && node.Context != nil {
 			if node.Context.ApplicationID != 0 {
 				a := w.Workflow.Applications[node.Context.ApplicationID]
+				// the vcs password is encrypted with the data key of the project
+				a.ProjectID = w.Workflow.ProjectID
 				app = &a
 			}
 			if node.Context.EnvironmentID != 0 {
@@ -509,6 +526,16 @@ func LoadSecrets(db gorp.SqlExecutor, store cache.Store, nodeRun *sdk.WorkflowNo
 			pfv = sdk.VariablesPrefix(pfv, "cds.integration.")
 			pfv = sdk.VariablesFilter(pfv, sdk.SecretVariable)
 
+			// The password of a Docker Registry integration can be overridden by a secret variable of the environment
+			if pf.Model.Name == sdk.DockerRegistryIntegrationModel {
+				for i := range pfv {
+					name := sdk.DockerRegistryEnvironmentVariablePrefix + strings.TrimPrefix(pfv[i].Name, "cds.integration.")
+					if v := sdk.VariableFind(ev, "cds.env."+name); v != nil {
+						pfv[i].Value = v.Value
+					}
+				}
+			}
+
 			if app != nil && app.DeploymentStrategies != nil {
 				strats, err := application.LoadDeploymentStrategies(db, app.ID, true)
 				if err != nil {
@@ -538,41 +565,223 @@ func LoadSecrets(db gorp.SqlExecutor, store cache.Store, nodeRun *sdk.WorkflowNo
 	//Decrypt secrets
 	for i := range secrets {
 		s := &secrets[i]
-		if err := secret.DecryptVariable(s); err != nil {
+		if err := secret.DecryptVariableForProject(w.Workflow.ProjectID, s); err != nil {
 			return nil, sdk.WrapError(err, "Unable to decrypt variables")
 		}
 	}
+
 	return secrets, nil
 }
 
-//BookNodeJobRun  Book a job for a hatchery
+// secretReferences is the definition of a job or an outgoing hook, in which the secrets are referenced by their name
+// or by the name of their environment variable
+type secretReferences struct {
+	definition      string
+	upperDefinition string
+}
+
+func newSecretReferences(definition interface{}) (secretReferences, error) {
+	b, err := json.Marshal(definition)
+	if err != nil {
+		return secretReferences{}, sdk.WithStack(err)
+	}
+	return secretReferences{definition: string(b), upperDefinition: strings.ToUpper(string(b))}, nil
+}
+
+// has returns true if the variable, or a variable with the given prefix, is referenced
+func (r secretReferences) has(name string) bool {
+	if strings.Contains(r.definition, name) {
+		return true
+	}
+	envName := strings.ToUpper(strings.NewReplacer(".", "_", "-", "_").Replace(name))
+	return strings.Contains(r.upperDefinition, envName)
+}
+
+// LoadIntegrationSecrets resolves the secrets of the secret manager integrations of the project referenced by the
+// definition of a job or an outgoing hook: secret variables referencing an AWS secret are replaced by its value, Vault
+// secrets and OAuth2 access tokens are appended. The secrets referencing an AWS secret which are not used are removed.
+// For a run of a pull request from a fork, the integrations are only called for the secrets allowed by the fork policy.
+// The integrations are called over the network, it must not be called in a transaction. The secrets resolved from
+// external secret managers are never stored in CDS database.
+func LoadIntegrationSecrets(db gorp.SqlExecutor, wr *sdk.WorkflowRun, definition interface{}, secrets []sdk.Variable) ([]sdk.Variable, error) {
+	projectID := wr.Workflow.ProjectID
+	references, err := newSecretReferences(definition)
+	if err != nil {
+		return nil, err
+	}
+
+	// the secrets withheld from a fork are not resolved
+	forkPolicy := ForkPolicy(wr)
+	allowed := func(name string) bool {
+		return references.has(name) && (forkPolicy == nil || forkPolicy.AllowSecret(name))
+	}
+	allowedPrefix := func(prefix string) bool {
+		return references.has(prefix) && (forkPolicy == nil || forkPolicy.AllowSecretPrefix(prefix))
+	}
+
+	var awsReferences bool
+	for _, s := range secrets {
+		if s.Type == sdk.SecretVariable && aws.IsReference(s.Value) && allowed(s.Name) {
+			awsReferences = true
+			break
+		}
+	}
+
+	pis, err := integration.LoadIntegrationsByProjectID(db, projectID, true)
+	if err != nil {
+		return nil, sdk.WrapError(err, "cannot load integrations of project %d", projectID)
+	}
+
+	var awsClient *aws.Client
+	var integrationSecrets []sdk.Variable
+	for _, pi := range pis {
+		switch pi.Model.Name {
+		case sdk.VaultIntegrationModel:
+			if !allowedPrefix(vault.VariablePrefix(pi.Name)) {
+				continue
+			}
+			vs, err := vault.LoadSecrets(pi)
+			if err != nil {
+				return nil, sdk.WrapError(err, "cannot load secrets from vault integration %s", pi.Name)
+			}
+			integrationSecrets = append(integrationSecrets, vs...)
+		case sdk.OAuth2IntegrationModel:
+			if !allowedPrefix(oauth2.VariablePrefix(pi.Name)) {
+				continue
+			}
+			ts, err := oauth2.LoadToken(pi)
+			if err != nil {
+				return nil, sdk.WrapError(err, "cannot get access token from oauth2 integration %s", pi.Name)
+			}
+			integrationSecrets = append(integrationSecrets, ts...)
+		case sdk.AWSSecretsIntegrationModel:
+			if awsClient != nil || !awsReferences {
+				continue
+			}
+			awsClient, err = aws.NewClient(pi)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	resolved := make([]sdk.Variable, 0, len(secrets)+len(integrationSecrets))
+	for _, s := range secrets {
+		if s.Type != sdk.SecretVariable || !aws.IsReference(s.Value) {
+			resolved = append(resolved, s)
+			continue
+		}
+		if !allowed(s.Name) {
+			continue
+		}
+		if awsClient == nil {
+			return nil, sdk.NewErrorFrom(sdk.ErrWrongRequest, "variable %s references an aws secret but there is no %s integration on the project", s.Name, sdk.AWSSecretsIntegrationModel)
+		}
+		v, err := awsClient.Resolve(s.Value)
+		if err != nil {
+			return nil, sdk.WrapError(err, "cannot resolve variable %s", s.Name)
+		}
+		s.Value = v
+		resolved = append(resolved, s)
+	}
+
+	return append(resolved, integrationSecrets...), nil
+}
+
+// Leases of the job bookings, in seconds. A job reserved by a hatchery is released if the hatchery does not confirm
+// the booking in time, a confirmed booking is released if no worker took the job in time.
+const (
+	JobReservationLease  = 30
+	JobConfirmationLease = 120
+)
+
+// jobBooking is the booking of a job stored in the cache. It is decoded as the hatchery which booked the job by the
+// readers of the booking.
+type jobBooking struct {
+	sdk.Service
+	Confirmed bool `json:"confirmed"`
+}
+
+// lockJobBooking serializes the changes of the booking of a job between the API instances
+func lockJobBooking(store cache.Store, id int64) (func(), error) {
+	k := cache.Key(keyBookJob(id), "lock")
+	if !store.Lock(k, 5*time.Second, 50, 20) {
+		return nil, sdk.WrapError(sdk.ErrJobLocked, "cannot lock booking of job %d", id)
+	}
+	return func() { store.Unlock(k) }, nil
+}
+
+// BookNodeJobRun reserves a job for a hatchery. The reservation must be confirmed by the hatchery with
+// ConfirmNodeJobRun before spawning a worker, it is released if it is not confirmed in time.
 func BookNodeJobRun(store cache.Store, id int64, hatchery *sdk.Service) (*sdk.Service, error) {
+	unlock, err := lockJobBooking(store, id)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
 	k := keyBookJob(id)
-	h := sdk.Service{}
-	if !store.Get(k, &h) {
-		// job not already booked, book it for 2 min
-		store.SetWithTTL(k, hatchery, 120)
+	b := jobBooking{}
+	if !store.Get(k, &b) {
+		store.SetWithTTL(k, jobBooking{Service: *hatchery}, JobReservationLease)
 		return nil, nil
 	}
-	if h.ID == hatchery.ID {
+	if b.ID == hatchery.ID {
 		return nil, nil
 	}
-	return &h, sdk.WrapError(sdk.ErrJobAlreadyBooked, "BookNodeJobRun> job %d already booked by %s (%d)", id, h.Name, h.ID)
+	return &b.Service, sdk.WrapError(sdk.ErrJobAlreadyBooked, "BookNodeJobRun> job %d already booked by %s (%d)", id, b.Name, b.ID)
+}
+
+// IsNodeJobRunBooked returns true if the job is booked by a hatchery.
+func IsNodeJobRunBooked(store cache.Store, id int64) bool {
+	var b jobBooking
+	return store.Get(keyBookJob(id), &b)
 }
 
-//FreeNodeJobRun  Free a job for a hatchery
-func FreeNodeJobRun(store cache.Store, id int64) error {
+// ConfirmNodeJobRun confirms the reservation of a job by a hatchery, which can then spawn a worker for it. It fails if
+// the reservation of the hatchery expired.
+func ConfirmNodeJobRun(store cache.Store, id int64, hatchery *sdk.Service) error {
+	unlock, err := lockJobBooking(store, id)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	k := keyBookJob(id)
-	h := sdk.Service{}
-	if store.Get(k, &h) {
-		// job not already booked, book it for 2 min
-		store.Delete(k)
-		return nil
+	b := jobBooking{}
+	if !store.Get(k, &b) {
+		return sdk.WrapError(sdk.ErrJobNotBooked, "ConfirmNodeJobRun> reservation of job %d expired", id)
+	}
+	if b.ID != hatchery.ID {
+		return sdk.WrapError(sdk.ErrJobAlreadyBooked, "ConfirmNodeJobRun> job %d already booked by %s (%d)", id, b.Name, b.ID)
 	}
-	return sdk.WrapError(sdk.ErrJobNotBooked, "BookNodeJobRun> job %d already released", id)
+	b.Confirmed = true
+	store.SetWithTTL(k, b, JobConfirmationLease)
+	return nil
 }
 
-//AddNodeJobAttempt add an hatchery attempt to spawn a job
+// FreeNodeJobRun releases the booking of a job by a hatchery. Without hatchery, as for the worker spawned for the job,
+// the booking is released whoever booked the job.
+func FreeNodeJobRun(store cache.Store, id int64, hatchery *sdk.Service) error {
+	unlock, err := lockJobBooking(store, id)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	k := keyBookJob(id)
+	b := jobBooking{}
+	if !store.Get(k, &b) {
+		return sdk.WrapError(sdk.ErrJobNotBooked, "FreeNodeJobRun> job %d already released", id)
+	}
+	if hatchery != nil && b.ID != hatchery.ID {
+		return sdk.WrapError(sdk.ErrJobAlreadyBooked, "FreeNodeJobRun> job %d booked by %s (%d)", id, b.Name, b.ID)
+	}
+	store.Delete(k)
+	return nil
+}
+
+// AddNodeJobAttempt add an hatchery attempt to spawn a job
 func AddNodeJobAttempt(db gorp.SqlExecutor, id, hatcheryID int64) ([]int64, error) {
 	var ids []int64
 	query := "UPDATE workflow_node_run_job SET spawn_attempts = array_append(spawn_attempts, $1) WHERE id = $2"
@@ -593,7 +802,7 @@ func AddNodeJobAttempt(db gorp.SqlExecutor, id, hatcheryID int64) ([]int64, erro
 	return ids, err
 }
 
-//AddLog adds a build log
+// AddLog adds a build log
 func AddLog(db gorp.SqlExecutor, job *sdk.WorkflowNodeJobRun, logs *sdk.Log, maxLogSize int64) error {
 	if job != nil {
 		logs.PipelineBuildJobID = job.ID
@@ -615,21 +824,47 @@ func AddLog(db gorp.SqlExecutor, job *sdk.WorkflowNodeJobRun, logs *sdk.Log, max
 		return sdk.WrapError(insertLog(db, logs), "cannot insert log")
 	}
 
-	existingLogs, err := LoadStepLogs(db, logs.PipelineBuildJobID, logs.StepOrder)
+	return sdk.WrapError(appendLog(db, logs), "cannot update log")
+}
+
+// AddLogChunk adds a chunk of the logs of a step streamed by a worker. The parts of the chunk already
+// persisted, sent again when a worker resumes its stream, are ignored. It returns the offset of the
+// end of the chunk, until which the logs of the step are persisted.
+func AddLogChunk(db gorp.SqlExecutor, chunk *sdk.LogChunk, maxLogSize int64) (int64, error) {
+	end := chunk.Offset + int64(len(chunk.Val))
+
+	_, size, err := ExistsStepLog(db, chunk.JobID, chunk.StepOrder)
 	if err != nil {
-		return sdk.WrapError(err, "cannot load existing logs")
+		return 0, sdk.WrapError(err, "cannot check if log exists")
 	}
 
-	logbuf := bytes.NewBufferString(existingLogs.Val)
-	logbuf.WriteString(logs.Val)
-	existingLogs.Val = logbuf.String()
-	existingLogs.LastModified = logs.LastModified
-	existingLogs.Done = logs.Done
+	// the logs of the step have been truncated, next chunks are ignored
+	if maxLogSize == 0 {
+		maxLogSize = DefaultMaxLogSize
+	}
+	if size >= maxLogSize {
+		return end, nil
+	}
+
+	val := chunk.Val
+	switch {
+	case end <= size:
+		return end, nil
+	case chunk.Offset < size:
+		val = val[size-chunk.Offset:]
+	case chunk.Offset > size:
+		log.Warning("AddLogChunk> logs of step %d of job %d are missing between %d and %d", chunk.StepOrder, chunk.JobID, size, chunk.Offset)
+	}
 
-	return sdk.WrapError(updateLog(db, existingLogs), "cannot update log")
+	logs := sdk.NewLog(chunk.JobID, val, chunk.NodeRunID, int(chunk.StepOrder))
+	logs.Done = chunk.Done
+	if err := AddLog(db, nil, logs, maxLogSize); err != nil {
+		return 0, err
+	}
+	return end, nil
 }
 
-//AddServiceLog adds a service log
+// AddServiceLog adds a service log
 func AddServiceLog(db gorp.SqlExecutor, job *sdk.WorkflowNodeJobRun, logs *sdk.ServiceLog, maxLogSize int64) error {
 	if job != nil {
 		logs.WorkflowNodeJobRunID = job.ID
@@ -666,6 +901,22 @@ func AddServiceLog(db gorp.SqlExecutor, job *sdk.WorkflowNodeJobRun, logs *sdk.S
 
 // RestartWorkflowNodeJob restart all workflow node job and update logs to indicate restart
 func RestartWorkflowNodeJob(ctx context.Context, db gorp.SqlExecutor, wNodeJob sdk.WorkflowNodeJobRun) error {
+	return restartWorkflowNodeJob(ctx, db, wNodeJob, "Timeout", "Worker timeout")
+}
+
+// RequeueLostWorkflowNodeJob replaces in queue a job whose worker was lost, for instance a preempted spot instance
+func RequeueLostWorkflowNodeJob(ctx context.Context, db gorp.SqlExecutor, wNodeJob sdk.WorkflowNodeJobRun, workerName string) error {
+	if err := restartWorkflowNodeJob(ctx, db, wNodeJob, "Worker lost", "Worker "+workerName+" lost"); err != nil {
+		return err
+	}
+	return AddSpawnInfosNodeJobRun(db, wNodeJob.ID, []sdk.SpawnInfo{{
+		APITime:    time.Now(),
+		RemoteTime: time.Now(),
+		Message:    sdk.SpawnMsg{ID: sdk.MsgSpawnInfoWorkerLost.ID, Args: []interface{}{workerName}},
+	}})
+}
+
+func restartWorkflowNodeJob(ctx context.Context, db gorp.SqlExecutor, wNodeJob sdk.WorkflowNodeJobRun, reason, logMessage string) error {
 	var end func()
 	ctx, end = observability.Span(ctx, "workflow.RestartWorkflowNodeJob")
 	defer end()
@@ -679,13 +930,14 @@ func RestartWorkflowNodeJob(ctx context.Context, db gorp.SqlExecutor, wNodeJob s
 		if errL != nil {
 			return sdk.WrapError(errL, "RestartWorkflowNodeJob> error while load step logs")
 		}
-		wNodeJob.Job.Reason = "Killed (Reason: Timeout)\n"
+		wNodeJob.Job.Reason = "Killed (Reason: " + reason + ")\n"
 		step.Status = sdk.StatusWaiting.String()
 		step.Done = time.Time{}
+		step.Usage = nil
 		if l != nil { // log could be nil here
 			l.Done = nil
 			logbuf := bytes.NewBufferString(l.Val)
-			logbuf.WriteString("\n\n\n-=-=-=-=-=- Worker timeout: job replaced in queue -=-=-=-=-=-\n\n\n")
+			logbuf.WriteString("\n\n\n-=-=-=-=-=- " + logMessage + ": job replaced in queue -=-=-=-=-=-\n\n\n")
 			l.Val = logbuf.String()
 			if err := updateLog(db, l); err != nil {
 				return sdk.WrapError(errL, "RestartWorkflowNodeJob> error while update step log")
@@ -168,3 +168,34 @@ func updateLog(db gorp.SqlExecutor, logs *sdk.Log) error {
 	}
 	return nil
 }
+
+// appendLog appends the value of logs to the existing logs of the step, without loading them
+func appendLog(db gorp.SqlExecutor, logs *sdk.Log) error {
+	if logs.LastModified == nil {
+		logs.LastModified, _ = ptypes.TimestampProto(time.Now())
+	}
+	if logs.Done == nil {
+		logs.Done, _ = ptypes.TimestampProto(time.Now())
+	}
+
+	query := `
+		UPDATE workflow_node_run_job_logs set
+			last_modified = $3,
+			done = $4,
+			value = value || $5
+		where workflow_node_run_job_id = $1 AND step_order = $2`
+
+	m, errm := ptypes.Timestamp(logs.LastModified)
+	if errm != nil {
+		return errm
+	}
+	d, errd := ptypes.Timestamp(logs.Done)
+	if errd != nil {
+		return errd
+	}
+
+	if _, err := db.Exec(query, logs.PipelineBuildJobID, logs.StepOrder, m, d, logs.Val); err != nil {
+		return sdk.WithStack(err)
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package workflow
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/engine/api/cache"
+	"github.com/ovh/cds/sdk"
+)
+
+func TestBookNodeJobRun(t *testing.T) {
+	store := cache.NewLocalStore(60, 0)
+	h1 := &sdk.Service{ID: 1, Name: "hatchery-1"}
+	h2 := &sdk.Service{ID: 2, Name: "hatchery-2"}
+
+	// a single hatchery reserves a job booked concurrently
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var reserved []int64
+	for _, h := range []*sdk.Service{h1, h2, h1, h2} {
+		wg.Add(1)
+		go func(h *sdk.Service) {
+			defer wg.Done()
+			if _, err := BookNodeJobRun(store, 42, h); err == nil {
+				mu.Lock()
+				reserved = append(reserved, h.ID)
+				mu.Unlock()
+			}
+		}(h)
+	}
+	wg.Wait()
+	if assert.NotEmpty(t, reserved) {
+		for _, id := range reserved {
+			assert.Equal(t, reserved[0], id)
+		}
+	}
+
+	owner, other := h1, h2
+	if reserved[0] == h2.ID {
+		owner, other = h2, h1
+	}
+	assert.Error(t, ConfirmNodeJobRun(store, 42, other))
+	assert.Error(t, FreeNodeJobRun(store, 42, other))
+	assert.NoError(t, ConfirmNodeJobRun(store, 42, owner))
+
+	var b jobBooking
+	assert.True(t, store.Get(keyBookJob(42), &b))
+	assert.True(t, b.Confirmed)
+	assert.Equal(t, owner.Name, b.Name)
+	assert.True(t, IsNodeJobRunBooked(store, 42))
+	assert.False(t, IsNodeJobRunBooked(store, 43))
+
+	booker, err := BookNodeJobRun(store, 42, other)
+	assert.Error(t, err)
+	assert.Equal(t, owner.ID, booker.ID)
+
+	// a released job can be reserved again, an expired reservation cannot be confirmed
+	assert.NoError(t, FreeNodeJobRun(store, 42, owner))
+	_, err = BookNodeJobRun(store, 42, other)
+	assert.NoError(t, err)
+	store.Delete(keyBookJob(42))
+	assert.False(t, IsNodeJobRunBooked(store, 42))
+	assert.Error(t, ConfirmNodeJobRun(store, 42, other))
+}
+
+func TestSecretReferences(t *testing.T) {
+	job := sdk.WorkflowNodeJobRun{Job: sdk.ExecutedJob{Job: sdk.Job{Action: sdk.Action{
+		Actions: []sdk.Action{
+			{Name: "Script", Parameters: []sdk.Parameter{{Name: "script", Value: "deploy --token {{.cds.vault.my-vault.token}} --key $CDS_PROJ_AWS_KEY"}}},
+		},
+	}}}}
+
+	r, err := newSecretReferences(job)
+	assert.NoError(t, err)
+	assert.True(t, r.has("cds.vault.my-vault."))
+	assert.True(t, r.has("cds.proj.aws_key"), "the secrets are referenced by their environment variable")
+	assert.False(t, r.has("cds.vault.other-vault."))
+	assert.False(t, r.has("cds.oauth2.my-oauth2."))
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/go-gorp/gorp"
 
 	"github.com/ovh/cds/engine/api/cache"
+	"github.com/ovh/cds/engine/api/environment"
 	"github.com/ovh/cds/engine/api/group"
 	"github.com/ovh/cds/engine/api/observability"
 	"github.com/ovh/cds/engine/api/permission"
@@ -218,6 +219,15 @@ func execute(ctx context.Context, db gorp.SqlExecutor, store cache.Store, proj *
 		return nil, sdk.WrapError(fmt.Errorf("Unable to update node id=%d at status %s. err:%s", nr.ID, nr.Status, err), "workflow.execute> Unable to execute node")
 	}
 
+	if sdk.StatusIsTerminated(nr.Status) && nr.Status != sdk.StatusNeverBuilt.String() {
+		if err := insertNodeRunDurations(db, wr, nr); err != nil {
+			log.Error("workflow.execute> Unable to store durations of node run %d: %v", nr.ID, err)
+		}
+		if err := insertDeployment(db, wr, nr); err != nil {
+			log.Error("workflow.execute> Unable to store deployment of node run %d: %v", nr.ID, err)
+		}
+	}
+
 	//Reload the workflow
 	updatedWorkflowRun, err := LoadRunByID(db, nr.WorkflowRunID, LoadRunOptions{})
 	if err != nil {
@@ -346,6 +356,16 @@ func addJobsToQueue(ctx context.Context, db gorp.SqlExecutor, stage *sdk.Stage,
 	}
 	next()
 
+	// The jobs deploying on a frozen environment wait in the queue, tell why
+	var freeze *sdk.EnvironmentFreeze
+	if runContext.Environment.ID != 0 {
+		var err error
+		freeze, err = environment.ActiveFreeze(db, runContext.Environment.ID)
+		if err != nil {
+			return report, err
+		}
+	}
+
 	skippedOrDisabledJobs := 0
 	//Browse the jobs
 	for j := range stage.Jobs {
@@ -393,6 +413,7 @@ func addJobsToQueue(ctx context.Context, db gorp.SqlExecutor, stage *sdk.Stage,
 			Header:          run.Header,
 			ContainsService: containsService,
 			ModelType:       modelType,
+			Priority:        wr.Priority,
 		}
 		wjob.Job.Job.Action.Requirements = jobRequirements // Set the interpolated requirements on the job run only
 
@@ -425,6 +446,13 @@ func addJobsToQueue(ctx context.Context, db gorp.SqlExecutor, stage *sdk.Stage,
 				Message:    sdk.SpawnMsg{ID: sdk.MsgSpawnInfoJobInQueue.ID},
 				RemoteTime: time.Now(),
 			}}
+				if freeze != nil && wjob.Status == sdk.StatusWaiting.String() {
+				wjob.SpawnInfos = append(wjob.SpawnInfos, sdk.SpawnInfo{
+					APITime:    time.Now(),
+					Message:    sdk.SpawnMsg{ID: sdk.MsgSpawnInfoEnvironmentFrozen.ID, Args: []interface{}{freeze.Message(time.Now())}},
+					RemoteTime: time.Now(),
+				})
+			}
 		}
 
 		//Insert in database
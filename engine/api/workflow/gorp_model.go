@@ -99,11 +99,13 @@ type JobRun struct {
 	ContainsService           bool           `db:"contains_service"`
 	ModelType                 sql.NullString `db:"model_type"`
 	Header                    sql.NullString `db:"header"`
+	Priority                  int            `db:"priority"`
 }
 
 // ToJobRun transform the JobRun with data of the provided sdk.WorkflowNodeJobRun
 func (j *JobRun) ToJobRun(jr *sdk.WorkflowNodeJobRun) (err error) {
 	j.ProjectID = jr.ProjectID
+	j.Priority = jr.Priority
 	j.ID = jr.ID
 	j.WorkflowNodeRunID = jr.WorkflowNodeRunID
 	j.Job, err = gorpmapping.JSONToNullString(jr.Job)
@@ -153,6 +155,7 @@ func (j JobRun) WorkflowNodeRunJob() (sdk.WorkflowNodeJobRun, error) {
 		Done:              j.Done,
 		BookedBy:          j.BookedBy,
 		ContainsService:   j.ContainsService,
+		Priority:          j.Priority,
 	}
 	if j.SpawnAttempts != nil {
 		jr.SpawnAttempts = *j.SpawnAttempts
@@ -203,6 +206,18 @@ type NodeRunArtifact sdk.WorkflowNodeRunArtifact
 // dbStaticFiles is a gorp wrapper around sdk.StaticFiles
 type dbStaticFiles sdk.StaticFiles
 
+// dbWorkflowRunSBOM is a gorp wrapper around sdk.WorkflowRunSBOM
+type dbWorkflowRunSBOM sdk.WorkflowRunSBOM
+
+// dbWorkflowRunResult is a gorp wrapper around sdk.WorkflowRunResult
+type dbWorkflowRunResult sdk.WorkflowRunResult
+
+// dbWorkflowRunTestCase is a gorp wrapper around sdk.WorkflowRunTestCase
+type dbWorkflowRunTestCase sdk.WorkflowRunTestCase
+
+// dbWorkflowBadge is a gorp wrapper around sdk.WorkflowBadge
+type dbWorkflowBadge sdk.WorkflowBadge
+
 // RunTag is a gorp wrapper around sdk.WorkflowRunTag
 type RunTag sdk.WorkflowRunTag
 
@@ -255,6 +270,10 @@ func init() {
 	gorpmapping.Register(gorpmapping.New(Coverage{}, "workflow_node_run_coverage", false, "workflow_id", "workflow_run_id", "workflow_node_run_id", "repository", "branch"))
 	gorpmapping.Register(gorpmapping.New(dbStaticFiles{}, "workflow_node_run_static_files", true, "id"))
 	gorpmapping.Register(gorpmapping.New(dbNodeRunVulenrabilitiesReport{}, "workflow_node_run_vulnerability", true, "id"))
+	gorpmapping.Register(gorpmapping.New(dbWorkflowRunSBOM{}, "workflow_run_sbom", true, "id"))
+	gorpmapping.Register(gorpmapping.New(dbWorkflowRunResult{}, "workflow_run_result", true, "id"))
+	gorpmapping.Register(gorpmapping.New(dbWorkflowRunTestCase{}, "workflow_run_test_case", true, "id"))
+	gorpmapping.Register(gorpmapping.New(dbWorkflowBadge{}, "workflow_badge", false, "workflow_id"))
 	gorpmapping.Register(gorpmapping.New(dbNodeFork{}, "workflow_node_fork", true, "id"))
 	gorpmapping.Register(gorpmapping.New(dbNodeForkTrigger{}, "workflow_node_fork_trigger", true, "id"))
 	gorpmapping.Register(gorpmapping.New(dbNodeData{}, "w_node", true, "id"))
@@ -264,4 +283,7 @@ func init() {
 	gorpmapping.Register(gorpmapping.New(dbNodeOutGoingHookData{}, "w_node_outgoing_hook", true, "id"))
 	gorpmapping.Register(gorpmapping.New(dbNodeJoinData{}, "w_node_join", true, "id"))
 	gorpmapping.Register(gorpmapping.New(dbAsCodeEvents{}, "workflow_as_code_events", true, "id"))
+	gorpmapping.Register(gorpmapping.New(sdk.WorkflowPromotion{}, "workflow_promotion", true, "id"))
+	gorpmapping.Register(gorpmapping.New(sdk.WorkflowSavedFilter{}, "workflow_saved_filter", true, "id"))
+	gorpmapping.Register(gorpmapping.New(sdk.WorkflowForkApproval{}, "workflow_fork_approval", true, "id"))
 }
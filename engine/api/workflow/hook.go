@@ -3,6 +3,9 @@ package workflow
 import (
 	"bytes"
 	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -13,6 +16,7 @@ import (
 	"github.com/ovh/cds/engine/api/application"
 	"github.com/ovh/cds/engine/api/cache"
 	"github.com/ovh/cds/engine/api/repositoriesmanager"
+	"github.com/ovh/cds/engine/api/secret"
 	"github.com/ovh/cds/engine/api/services"
 	"github.com/ovh/cds/sdk"
 	"github.com/ovh/cds/sdk/log"
@@ -45,6 +49,15 @@ func HookRegistration(ctx context.Context, db gorp.SqlExecutor, store cache.Stor
 				h.Config[sdk.HookConfigWorkflow] = configValue
 				hookToUpdate[i] = h
 			}
+			// the payloads of the new repository webhooks are signed with a secret shared with the hooks µService
+			if h.WorkflowHookModel.Name == sdk.RepositoryWebHookModelName && h.Config["vcsServer"].Value != "" && h.Config[sdk.RepositoryWebHookSecret].Value == "" {
+				value, err := newWebHookSecret(db, p.ID)
+				if err != nil {
+					return err
+				}
+				h.Config[sdk.RepositoryWebHookSecret] = value
+				hookToUpdate[i] = h
+			}
 		}
 
 		//Perform the request on one off the hooks service
@@ -133,11 +146,27 @@ func HookRegistration(ctx context.Context, db gorp.SqlExecutor, store cache.Stor
 			}
 		}
 
-		// Create hook on µservice
-		code, errHooks := services.DoJSONRequest(ctx, srvs, http.MethodPost, "/task/bulk", hookToUpdate, &hookToUpdate)
+		// Create hook on µservice, with the clear secrets of the repository webhooks to check the signatures of the payloads
+		hookToSend := make(map[string]sdk.WorkflowNodeHook, len(hookToUpdate))
+		for i := range hookToUpdate {
+			h, err := WithClearWebHookSecret(p.ID, hookToUpdate[i])
+			if err != nil {
+				return err
+			}
+			hookToSend[i] = h
+		}
+		code, errHooks := services.DoJSONRequest(ctx, srvs, http.MethodPost, "/task/bulk", hookToSend, &hookToSend)
 		if errHooks != nil || code >= 400 {
 			return sdk.WrapError(errHooks, "HookRegistration> Unable to create hooks [%d]", code)
 		}
+		// the secrets are saved encrypted
+		for i := range hookToSend {
+			h := hookToSend[i]
+			if encrypted, ok := hookToUpdate[i].Config[sdk.RepositoryWebHookSecret]; ok {
+				h.Config[sdk.RepositoryWebHookSecret] = encrypted
+			}
+			hookToUpdate[i] = h
+		}
 
 		// Create vcs configuration ( always after hook creation to have webhook URL) + update hook in DB
 		for i := range hookToUpdate {
@@ -224,10 +253,15 @@ func createVCSConfiguration(ctx context.Context, db gorp.SqlExecutor, store cach
 	if !webHookInfo.WebhooksSupported || webHookInfo.WebhooksDisabled {
 		return sdk.WrapError(sdk.ErrForbidden, "createVCSConfiguration> hook creation are forbidden")
 	}
+	clearSecret, err := webHookSecret(p.ID, h.Config[sdk.RepositoryWebHookSecret])
+	if err != nil {
+		return err
+	}
 	vcsHook := sdk.VCSHook{
 		Method:   "POST",
 		URL:      h.Config["webHookURL"].Value,
 		Workflow: true,
+		Secret:   clearSecret,
 	}
 	if err := client.CreateHook(ctx, h.Config["repoFullName"].Value, &vcsHook); err != nil {
 		return sdk.WrapError(err, "Cannot create hook on repository: %+v", vcsHook)
@@ -250,6 +284,205 @@ func createVCSConfiguration(ctx context.Context, db gorp.SqlExecutor, store cach
 	return nil
 }
 
+// RepairVCSConfiguration checks that the webhook of a repository webhook still exists on the repository with the url,
+// the events and the secret of the hook. A missing webhook is created, a webhook which has drifted is recreated. It
+// returns true if the configuration of the hook was updated, and true as second value if a webhook was created on the
+// repository: it must be deleted with DeleteVCSConfiguration if the configuration of the hook can not be saved.
+func RepairVCSConfiguration(ctx context.Context, db gorp.SqlExecutor, store cache.Store, p *sdk.Project, h *sdk.WorkflowNodeHook) (bool, bool, error) {
+	if h.WorkflowHookModel.Name != sdk.RepositoryWebHookModelName {
+		return false, false, sdk.NewErrorFrom(sdk.ErrWrongRequest, "hook %s is not a repository webhook", h.UUID)
+	}
+	projectVCSServer := repositoriesmanager.GetProjectVCSServer(p, h.Config["vcsServer"].Value)
+	if projectVCSServer == nil {
+		return false, false, sdk.NewErrorFrom(sdk.ErrNoReposManager, "repository manager %s is not linked to project %s", h.Config["vcsServer"].Value, p.Key)
+	}
+	if h.Config["webHookURL"].Value == "" {
+		return false, false, sdk.NewErrorFrom(sdk.ErrHookNotFound, "webhook of hook %s is not configured", h.UUID)
+	}
+
+	client, err := repositoriesmanager.AuthorizedClient(ctx, db, store, projectVCSServer)
+	if err != nil {
+		return false, false, sdk.WrapError(err, "cannot get vcs client")
+	}
+	webHookInfo, err := repositoriesmanager.GetWebhooksInfos(ctx, client)
+	if err != nil {
+		return false, false, sdk.WrapError(err, "cannot get vcs web hook info")
+	}
+
+	repo := h.Config["repoFullName"].Value
+	vcsHook, err := client.GetHook(ctx, repo, h.Config["webHookURL"].Value)
+	if err != nil && !sdk.ErrorIs(err, sdk.ErrNotFound) && !sdk.ErrorIs(err, sdk.ErrHookNotFound) {
+		return false, false, sdk.WrapError(err, "cannot get webhook on repository %s", repo)
+	}
+
+	if err == nil {
+		if !webHookDrifted(*h, vcsHook, webHookInfo) {
+			if vcsHook.ID == h.Config["webHookID"].Value {
+				return false, false, nil
+			}
+			// the webhook was recreated on the repository
+			h.Config["webHookID"] = sdk.WorkflowNodeHookConfigValue{
+				Value:        vcsHook.ID,
+				Configurable: false,
+			}
+			return true, false, nil
+		}
+		log.Info("RepairVCSConfiguration> webhook %s on repository %s has drifted, it is recreated", vcsHook.ID, repo)
+		if err := client.DeleteHook(ctx, repo, vcsHook); err != nil {
+			return false, false, sdk.WrapError(err, "cannot delete webhook on repository %s", repo)
+		}
+	} else if id := h.Config["webHookID"].Value; id != "" {
+		// the url of the webhook was changed on the repository, the webhook is replaced
+		if err := client.DeleteHook(ctx, repo, sdk.VCSHook{ID: id, URL: h.Config["webHookURL"].Value, Workflow: true}); err != nil {
+			log.Warning("RepairVCSConfiguration> cannot delete webhook %s on repository %s: %v", id, repo, err)
+		}
+	}
+
+	// the webhooks created without secret are recreated with one
+	if h.Config[sdk.RepositoryWebHookSecret].Value == "" {
+		value, err := newWebHookSecret(db, p.ID)
+		if err != nil {
+			return false, false, err
+		}
+		h.Config[sdk.RepositoryWebHookSecret] = value
+	}
+
+	if err := createVCSConfiguration(ctx, db, store, p, h); err != nil {
+		return false, false, err
+	}
+	return true, true, nil
+}
+
+// webHookDrifted returns true if the webhook on the repository is disabled, or does not match the url, the events or
+// the secret of the hook
+func webHookDrifted(h sdk.WorkflowNodeHook, vcsHook sdk.VCSHook, webHookInfo repositoriesmanager.WebhooksInfos) bool {
+	if vcsHook.Disable || vcsHook.URL != h.Config["webHookURL"].Value {
+		return true
+	}
+	for _, e := range webHookInfo.Events {
+		if !sdk.IsInArray(e, vcsHook.Events) {
+			return true
+		}
+	}
+	// the secrets are not readable, only their presence can be checked
+	if h.Config[sdk.RepositoryWebHookSecret].Value == "" {
+		return true
+	}
+	return webHookInfo.SecretVisible && vcsHook.Secret == ""
+}
+
+// DeleteVCSConfiguration deletes the webhook of a repository webhook on its repository
+func DeleteVCSConfiguration(ctx context.Context, db gorp.SqlExecutor, store cache.Store, p *sdk.Project, h sdk.WorkflowNodeHook) error {
+	projectVCSServer := repositoriesmanager.GetProjectVCSServer(p, h.Config["vcsServer"].Value)
+	if projectVCSServer == nil {
+		return nil
+	}
+	client, err := repositoriesmanager.AuthorizedClient(ctx, db, store, projectVCSServer)
+	if err != nil {
+		return sdk.WrapError(err, "cannot get vcs client")
+	}
+	vcsHook := sdk.VCSHook{
+		Method:   "POST",
+		URL:      h.Config["webHookURL"].Value,
+		Workflow: true,
+		ID:       h.Config["webHookID"].Value,
+	}
+	return client.DeleteHook(ctx, h.Config["repoFullName"].Value, vcsHook)
+}
+
+// newWebHookSecret returns a random secret shared with the repository manager to sign the payloads of a webhook. The
+// secret is encrypted with the key of the project.
+func newWebHookSecret(db gorp.SqlExecutor, projectID int64) (sdk.WorkflowNodeHookConfigValue, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return sdk.WorkflowNodeHookConfigValue{}, sdk.WrapError(err, "cannot generate webhook secret")
+	}
+	encrypted, err := secret.EncryptForProject(db, projectID, []byte(hex.EncodeToString(b)))
+	if err != nil {
+		return sdk.WorkflowNodeHookConfigValue{}, sdk.WrapError(err, "cannot encrypt webhook secret")
+	}
+	return sdk.WorkflowNodeHookConfigValue{
+		Value:        base64.StdEncoding.EncodeToString(encrypted),
+		Configurable: false,
+		Type:         sdk.HookConfigTypeSecret,
+	}, nil
+}
+
+// webHookSecret returns the clear secret of a repository webhook. The secrets saved before their encryption are
+// returned as is.
+func webHookSecret(projectID int64, v sdk.WorkflowNodeHookConfigValue) (string, error) {
+	if v.Type != sdk.HookConfigTypeSecret || v.Value == "" {
+		return v.Value, nil
+	}
+	encrypted, err := base64.StdEncoding.DecodeString(v.Value)
+	if err != nil {
+		return "", sdk.WrapError(err, "cannot decode webhook secret")
+	}
+	clear, err := secret.DecryptForProject(projectID, encrypted)
+	if err != nil {
+		return "", sdk.WrapError(err, "cannot decrypt webhook secret")
+	}
+	return string(clear), nil
+}
+
+// WithClearWebHookSecret returns a copy of the hook with the clear secret of its repository webhook, for the hooks
+// µService which checks the signatures of the payloads
+func WithClearWebHookSecret(projectID int64, h sdk.WorkflowNodeHook) (sdk.WorkflowNodeHook, error) {
+	v, ok := h.Config[sdk.RepositoryWebHookSecret]
+	if !ok {
+		return h, nil
+	}
+	clear, err := webHookSecret(projectID, v)
+	if err != nil {
+		return h, err
+	}
+	h.Config = h.Config.Clone()
+	h.Config[sdk.RepositoryWebHookSecret] = sdk.WorkflowNodeHookConfigValue{
+		Value:        clear,
+		Configurable: false,
+	}
+	return h, nil
+}
+
+// restoreHooksSecrets replaces the secrets masked in the hooks of a workflow by the secrets of the hooks of its previous
+// version. The masked secrets of the new hooks are removed to be generated.
+func restoreHooksSecrets(w, oldW *sdk.Workflow) {
+	secrets := map[string]sdk.WorkflowNodeHookConfigValue{}
+	if oldW != nil {
+		for _, h := range oldW.GetHooks() {
+			if v, ok := h.Config[sdk.RepositoryWebHookSecret]; ok && v.Value != sdk.PasswordPlaceholder {
+				secrets[h.Ref] = v
+			}
+		}
+		for _, h := range oldW.WorkflowData.GetHooksMapRef() {
+			if v, ok := h.Config[sdk.RepositoryWebHookSecret]; ok && v.Value != sdk.PasswordPlaceholder {
+				secrets[h.Ref] = v
+			}
+		}
+	}
+
+	restore := func(ref string, cfg sdk.WorkflowNodeHookConfig) {
+		if v, ok := cfg[sdk.RepositoryWebHookSecret]; !ok || v.Value != sdk.PasswordPlaceholder {
+			return
+		}
+		if v, ok := secrets[ref]; ok {
+			cfg[sdk.RepositoryWebHookSecret] = v
+			return
+		}
+		delete(cfg, sdk.RepositoryWebHookSecret)
+	}
+	for _, h := range w.GetHooks() {
+		restore(h.Ref, h.Config)
+	}
+	if w.WorkflowData != nil {
+		for _, n := range w.WorkflowData.Array() {
+			for _, h := range n.Hooks {
+				restore(h.Ref, h.Config)
+			}
+		}
+	}
+}
+
 func mergeAndDiffHook(oldHooks map[string]sdk.WorkflowNodeHook, newHooks map[string]sdk.WorkflowNodeHook) (hookToUpdate map[string]sdk.WorkflowNodeHook, hookToDelete map[string]sdk.WorkflowNodeHook) {
 	hookToUpdate = make(map[string]sdk.WorkflowNodeHook)
 	hookToDelete = make(map[string]sdk.WorkflowNodeHook)
@@ -266,6 +499,9 @@ func mergeAndDiffHook(oldHooks map[string]sdk.WorkflowNodeHook, newHooks map[str
 				if webhookID, ok := oldHooks[o].Config["webHookID"]; ok {
 					nh.Config["webHookID"] = webhookID
 				}
+				if secret, ok := oldHooks[o].Config[sdk.RepositoryWebHookSecret]; ok {
+					nh.Config[sdk.RepositoryWebHookSecret] = secret
+				}
 				if oldIcon, ok := oldHooks[o].Config["hookIcon"]; oldHooks[o].WorkflowHookModelID == newHooks[n].WorkflowHookModelID && ok {
 					nh.Config["hookIcon"] = oldIcon
 				}
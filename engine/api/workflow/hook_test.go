@@ -4,6 +4,8 @@ import (
 	"reflect"
 	"testing"
 
+	"github.com/ovh/cds/engine/api/repositoriesmanager"
+	"github.com/ovh/cds/engine/api/secret"
 	"github.com/ovh/cds/sdk"
 )
 
@@ -60,3 +62,97 @@ func Test_mergeAndDiffHook(t *testing.T) {
 		})
 	}
 }
+
+func Test_webHookDrifted(t *testing.T) {
+	h := sdk.WorkflowNodeHook{
+		Config: sdk.WorkflowNodeHookConfig{
+			"webHookURL":                {Value: "https://hooks/webhook/uuid"},
+			sdk.RepositoryWebHookSecret: {Value: "secret"},
+		},
+	}
+	legacy := sdk.WorkflowNodeHook{
+		Config: sdk.WorkflowNodeHookConfig{
+			"webHookURL": {Value: "https://hooks/webhook/uuid"},
+		},
+	}
+	github := repositoriesmanager.WebhooksInfos{Events: sdk.GitHubWebHookEvents, SecretVisible: true}
+	gitlab := repositoriesmanager.WebhooksInfos{Events: sdk.GitlabWebHookEvents}
+
+	tests := []struct {
+		name    string
+		hook    sdk.WorkflowNodeHook
+		vcsHook sdk.VCSHook
+		info    repositoriesmanager.WebhooksInfos
+		want    bool
+	}{
+		{"up to date", h, sdk.VCSHook{URL: "https://hooks/webhook/uuid", Events: []string{"push", "issue_comment"}, Secret: "********"}, github, false},
+		{"disabled", h, sdk.VCSHook{URL: "https://hooks/webhook/uuid", Events: []string{"push", "issue_comment"}, Secret: "********", Disable: true}, github, true},
+		{"other url", h, sdk.VCSHook{URL: "https://other/webhook/uuid", Events: []string{"push", "issue_comment"}, Secret: "********"}, github, true},
+		{"missing event", h, sdk.VCSHook{URL: "https://hooks/webhook/uuid", Events: []string{"push"}, Secret: "********"}, github, true},
+		{"secret removed", h, sdk.VCSHook{URL: "https://hooks/webhook/uuid", Events: []string{"push", "issue_comment"}}, github, true},
+		{"secret not visible", h, sdk.VCSHook{URL: "https://hooks/webhook/uuid", Events: []string{"push", "note"}}, gitlab, false},
+		{"created without secret", legacy, sdk.VCSHook{URL: "https://hooks/webhook/uuid", Events: []string{"push", "note"}}, gitlab, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := webHookDrifted(tt.hook, tt.vcsHook, tt.info); got != tt.want {
+				t.Errorf("webHookDrifted() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_webHookSecret(t *testing.T) {
+	secret.Init("78eKVxCGLm6gwoH9LAQ15ZD5AOABo1Xf")
+
+	v, err := newWebHookSecret(nil, 0)
+	if err != nil {
+		t.Fatalf("newWebHookSecret() error = %v", err)
+	}
+	if v.Type != sdk.HookConfigTypeSecret || v.Configurable {
+		t.Errorf("newWebHookSecret() = %+v, want a secret not configurable", v)
+	}
+
+	h := sdk.WorkflowNodeHook{Config: sdk.WorkflowNodeHookConfig{sdk.RepositoryWebHookSecret: v}}
+	clear, err := WithClearWebHookSecret(0, h)
+	if err != nil {
+		t.Fatalf("WithClearWebHookSecret() error = %v", err)
+	}
+	if s := clear.Config[sdk.RepositoryWebHookSecret].Value; len(s) != 64 || s == v.Value {
+		t.Errorf("WithClearWebHookSecret() secret = %s, want the clear secret", s)
+	}
+	if h.Config[sdk.RepositoryWebHookSecret] != v {
+		t.Errorf("WithClearWebHookSecret() must not update the configuration of the hook")
+	}
+
+	// the secrets saved before their encryption are clear
+	legacy := sdk.WorkflowNodeHookConfigValue{Value: "legacy"}
+	if s, err := webHookSecret(0, legacy); err != nil || s != "legacy" {
+		t.Errorf("webHookSecret() = %s, %v, want legacy", s, err)
+	}
+}
+
+func Test_restoreHooksSecrets(t *testing.T) {
+	encrypted := sdk.WorkflowNodeHookConfigValue{Value: "encrypted", Type: sdk.HookConfigTypeSecret}
+	oldW := &sdk.Workflow{
+		WorkflowData: &sdk.WorkflowData{Node: sdk.Node{Hooks: []sdk.NodeHook{
+			{Ref: "AAA", Config: sdk.WorkflowNodeHookConfig{sdk.RepositoryWebHookSecret: encrypted}},
+		}}},
+	}
+	masked := sdk.WorkflowNodeHookConfigValue{Value: sdk.PasswordPlaceholder, Type: sdk.HookConfigTypeSecret}
+	w := &sdk.Workflow{
+		WorkflowData: &sdk.WorkflowData{Node: sdk.Node{Hooks: []sdk.NodeHook{
+			{Ref: "AAA", Config: sdk.WorkflowNodeHookConfig{sdk.RepositoryWebHookSecret: masked}},
+			{Ref: "BBB", Config: sdk.WorkflowNodeHookConfig{sdk.RepositoryWebHookSecret: masked}},
+		}}},
+	}
+
+	restoreHooksSecrets(w, oldW)
+
+	if v := w.WorkflowData.Node.Hooks[0].Config[sdk.RepositoryWebHookSecret]; v != encrypted {
+		t.Errorf("restoreHooksSecrets() secret of AAA = %+v, want %+v", v, encrypted)
+	}
+	if _, ok := w.WorkflowData.Node.Hooks[1].Config[sdk.RepositoryWebHookSecret]; ok {
+		t.Errorf("restoreHooksSecrets() secret of BBB must be removed")
+	}
+}
@@ -0,0 +1,122 @@
+package workflow
+
+import (
+	"context"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/cache"
+	"github.com/ovh/cds/engine/api/observability"
+	"github.com/ovh/cds/engine/api/repositoriesmanager"
+	"github.com/ovh/cds/sdk"
+)
+
+const (
+	tagGitHashBefore   = "git.hash.before"
+	tagGitChangedFiles = "git.changed_files"
+)
+
+// emptyGitHash is sent by repositories managers as previous hash on branch creation
+const emptyGitHash = "0000000000000000000000000000000000000000"
+
+// hookPathFilters returns the include and exclude path patterns configured on a repository webhook
+func hookPathFilters(h sdk.NodeHook) ([]string, []string) {
+	return sdk.PathFilters(h.Config[sdk.RepositoryWebHookIncludePaths].Value), sdk.PathFilters(h.Config[sdk.RepositoryWebHookExcludePaths].Value)
+}
+
+// getHookChangedFiles returns the list of files modified by the commits of a hook event.
+// The list is taken from the payload if the hooks service was able to compute it, else it is resolved through the VCS driver.
+// It returns nil if the list cannot be computed, and an empty list if the commits did not modify any file.
+func getHookChangedFiles(ctx context.Context, db gorp.SqlExecutor, store cache.Store, vcsServer *sdk.ProjectVCSServer, repository string, payload map[string]string) ([]string, error) {
+	if files, has := payload[tagGitChangedFiles]; has {
+		return sdk.SplitChangedFiles(files), nil
+	}
+
+	changes, err := getChangedFiles(ctx, db, store, vcsServer, repository, payload)
+	if err != nil || changes == nil {
+		return nil, err
+	}
+
+	files := make([]string, len(changes))
+	for i := range changes {
+		files[i] = changes[i].Filename
+	}
+	return files, nil
+}
+
+// getChangedFiles resolves through the VCS driver the list of files modified between git.hash.before and git.hash.
+// It returns nil if the payload does not contain enough git information to compute the diff.
+func getChangedFiles(ctx context.Context, db gorp.SqlExecutor, store cache.Store, vcsServer *sdk.ProjectVCSServer, repository string, payload map[string]string) ([]sdk.VCSChangedFile, error) {
+	before, after := payload[tagGitHashBefore], payload[tagGitHash]
+	if vcsServer == nil || repository == "" || before == "" || before == emptyGitHash || after == "" {
+		return nil, nil
+	}
+
+	ctx, end := observability.Span(ctx, "workflow.getChangedFiles",
+		observability.Tag("vcs_server", vcsServer.Name),
+		observability.Tag("vcs_repo", repository),
+	)
+	defer end()
+
+	client, err := repositoriesmanager.AuthorizedClient(ctx, db, store, vcsServer)
+	if err != nil {
+		return nil, sdk.WrapError(err, "cannot get client")
+	}
+
+	files, err := client.ChangedFiles(ctx, repository, before, after)
+	if err != nil {
+		return nil, sdk.WrapError(err, "cannot get changed files between %s and %s on %s", before, after, repository)
+	}
+	return files, nil
+}
+
+// inheritChangedFiles copies the changed files variable of the parent node runs built on the same commit
+func inheritChangedFiles(run *sdk.WorkflowNodeRun, parents []*sdk.WorkflowNodeRun) {
+	if sdk.ParameterFind(&run.BuildParameters, tagGitChangedFiles) != nil {
+		return
+	}
+	for _, p := range parents {
+		if p.VCSHash != run.VCSHash || p.VCSRepository != run.VCSRepository {
+			continue
+		}
+		if param := sdk.ParameterFind(&p.BuildParameters, tagGitChangedFiles); param != nil {
+			sdk.AddParameter(&run.BuildParameters, tagGitChangedFiles, sdk.StringParameter, param.Value)
+			return
+		}
+	}
+}
+
+// NodeRunChangedFiles returns the manifest of the files modified by the commits which triggered the workflow run
+func NodeRunChangedFiles(ctx context.Context, db gorp.SqlExecutor, store cache.Store, proj *sdk.Project, wr *sdk.WorkflowRun, nodeRun *sdk.WorkflowNodeRun) ([]sdk.VCSChangedFile, error) {
+	rootRun := wr.RootRun()
+	if rootRun == nil || rootRun.HookEvent == nil {
+		return []sdk.VCSChangedFile{}, nil
+	}
+
+	payload := rootRun.HookEvent.Payload
+	node := wr.Workflow.WorkflowData.NodeByID(nodeRun.WorkflowNodeID)
+	if node == nil {
+		return nil, sdk.WithStack(sdk.ErrWorkflowNodeNotFound)
+	}
+
+	if node.Context != nil && node.Context.ApplicationID != 0 && nodeRun.VCSHash == rootRun.VCSHash {
+		app := wr.Workflow.Applications[node.Context.ApplicationID]
+		vcsServer := repositoriesmanager.GetProjectVCSServer(proj, app.VCSServer)
+		files, err := getChangedFiles(ctx, db, store, vcsServer, nodeRun.VCSRepository, payload)
+		if err != nil {
+			return nil, err
+		}
+		if files != nil {
+			return files, nil
+		}
+	}
+
+	// Fallback on the variable computed when the node was processed
+	files := []sdk.VCSChangedFile{}
+	if param := sdk.ParameterFind(&nodeRun.BuildParameters, tagGitChangedFiles); param != nil {
+		for _, f := range sdk.SplitChangedFiles(param.Value) {
+			files = append(files, sdk.VCSChangedFile{Filename: f})
+		}
+	}
+	return files, nil
+}
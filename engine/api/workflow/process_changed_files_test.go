@@ -0,0 +1,31 @@
+package workflow
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func Test_getHookChangedFiles(t *testing.T) {
+	tests := []struct {
+		name    string
+		payload map[string]string
+		want    []string
+	}{
+		{"files", map[string]string{tagGitChangedFiles: "README.md\nsrc/main.go"}, []string{"README.md", "src/main.go"}},
+		{"comma", map[string]string{tagGitChangedFiles: "docs/a,b.md\nsrc/main.go"}, []string{"docs/a,b.md", "src/main.go"}},
+		{"no file", map[string]string{tagGitChangedFiles: ""}, []string{}},
+		{"unknown", map[string]string{tagGitHash: "abcdef"}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := getHookChangedFiles(context.TODO(), nil, nil, nil, "", tt.payload)
+			if err != nil {
+				t.Fatalf("getHookChangedFiles() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("getHookChangedFiles() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
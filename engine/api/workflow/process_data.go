@@ -41,6 +41,7 @@ func processWorkflowDataRun(ctx context.Context, db gorp.SqlExecutor, store cach
 	if observability.Current(ctx).SpanContext().IsSampled() {
 		wr.Header.Set(tracingutils.SampledHeader, "1")
 		wr.Header.Set(tracingutils.TraceIDHeader, fmt.Sprintf("%v", observability.Current(ctx).SpanContext().TraceID))
+		wr.Header.Set(tracingutils.TraceParentHeader, tracingutils.FormatTraceParent(observability.Current(ctx).SpanContext()))
 	}
 	//////
 
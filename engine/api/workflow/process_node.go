@@ -407,6 +407,53 @@ func processNode(ctx context.Context, db gorp.SqlExecutor, store cache.Store, pr
 			return nil, false, sdk.WrapError(sdk.ErrWorkflowNodeNotFound, "Unable to find node %d", hook.NodeID)
 		}
 
+		isRepositoryWebHook := wr.Workflow.HookModels[hook.HookModelID].Name == sdk.RepositoryWebHookModelName
+
+		// Refresh the mirror of the repository used by the workers to clone it, on each push whether the node is
+		// triggered or not
+		if isRepositoryWebHook {
+			mirrorURL := vcsInfos.HTTPUrl
+			if app.RepositoryStrategy.ConnectionType == "ssh" {
+				mirrorURL = vcsInfos.URL
+			}
+			if err := postRepositoryMirror(ctx, db, *proj, app, mirrorURL); err != nil {
+				log.Warning("processNode> unable to refresh the mirror of %s: %v", app.RepositoryFullname, err)
+			}
+		}
+
+		// Check path filters on repository webhooks
+		if isRepositoryWebHook {
+			files, errF := getHookChangedFiles(ctx, db, store, vcsServer, vcsInfos.Repository, hookEvent.Payload)
+			if errF != nil {
+				log.Warning("processNode> unable to get changed files for hook %s: %v", hook.UUID, errF)
+			}
+			if files != nil {
+				sdk.ParameterAddOrSetValue(&run.BuildParameters, tagGitChangedFiles, sdk.StringParameter, sdk.JoinChangedFiles(files))
+				params = run.BuildParameters
+			}
+
+			includes, excludes := hookPathFilters(hook)
+			if len(includes) > 0 || len(excludes) > 0 {
+				// without the changed files the path filters cannot be checked, the node is not triggered
+				if files == nil {
+					log.Debug("Avoid trigger workflow from hook %s: changed files cannot be resolved", hook.UUID)
+					AddWorkflowRunInfo(wr, false, sdk.SpawnMsg{
+						ID:   sdk.MsgWorkflowRunChangedFilesUnknown.ID,
+						Args: []interface{}{hook.Ref},
+					})
+					return nil, false, nil
+				}
+				if len(sdk.FilterPaths(files, includes, excludes)) == 0 {
+					log.Debug("Avoid trigger workflow from hook %s: no changed file matches path filters", hook.UUID)
+					AddWorkflowRunInfo(wr, false, sdk.SpawnMsg{
+						ID:   sdk.MsgWorkflowRunNoChangedFileMatch.ID,
+						Args: []interface{}{hook.Ref},
+					})
+					return nil, false, nil
+				}
+			}
+		}
+
 		if !checkNodeRunCondition(wr, dest.Context.Conditions, params) {
 			log.Debug("Avoid trigger workflow from hook %s", hook.UUID)
 			return nil, false, nil
@@ -418,8 +465,16 @@ func processNode(ctx context.Context, db gorp.SqlExecutor, store cache.Store, pr
 		}
 	}
 
+	// the priority of the node is set on the run when it starts, the next nodes can only raise it
+	if p := n.Context.Conditions.Priority; p != nil && *p != wr.Priority && (isRoot || *p > wr.Priority) {
+		if err := UpdateWorkflowRunPriority(db, wr, *p); err != nil {
+			return nil, false, err
+		}
+	}
+
 	if !isRoot {
 		setValuesGitInBuildParameters(run, vcsInfos)
+		inheritChangedFiles(run, parents)
 	}
 
 	// Tag VCS infos : add in tag only if it does not exist
@@ -71,6 +71,15 @@ func GetNodeBuildParameters(proj *sdk.Project, w *sdk.Workflow, runContext nodeR
 			vars[k] = v
 		}
 
+		// The configuration of a Docker Registry integration can be overridden by the variables of the environment
+		if runContext.ProjectIntegration.Model.Name == sdk.DockerRegistryIntegrationModel && runContext.Environment.ID != 0 {
+			for _, k := range sdk.DockerRegistryConfigKeys {
+				if v, has := vars["cds.env."+sdk.DockerRegistryEnvironmentVariablePrefix+k]; has {
+					vars["cds.integration."+k] = v
+				}
+			}
+		}
+
 		// Process deployment strategy of the chosen integration
 		if runContext.Application.ID != 0 {
 			for pfName, pfConfig := range runContext.Application.DeploymentStrategies {
@@ -109,37 +109,10 @@ func processAllJoins(ctx context.Context, db gorp.SqlExecutor, store cache.Store
 			}
 		}
 
-		//now checks if all sources have been completed
-		var ok = true
-		nodeRunIDs := []int64{}
-		sourcesParams := map[string]string{}
-		sourcesFail := 0
-		for _, nodeRun := range sources {
-			if nodeRun == nil {
-				ok = false
-				break
-			}
-
-			if !sdk.StatusIsTerminated(nodeRun.Status) || nodeRun.Status == sdk.StatusNeverBuilt.String() || nodeRun.Status == sdk.StatusFail.String() || nodeRun.Status == sdk.StatusStopped.String() || nodeRun.SubNumber < wr.LastSubNumber {
-				//One of the sources have not been completed
-				ok = false
-				break
-			}
-
-			if nodeRun.Status == sdk.StatusFail.String() {
-				sourcesFail++
-			}
-
-			nodeRunIDs = append(nodeRunIDs, nodeRun.ID)
-			//Merge build parameters from all sources
-			sourcesParams = sdk.ParametersMapMerge(sourcesParams, sdk.ParametersToMap(nodeRun.BuildParameters))
-		}
-
-		if len(sources) != len(j.JoinContext) {
-			ok = false
-		}
+		//now checks if the sources fulfill the join strategy
+		sources, ok := joinSources(j, sources, wr.LastSubNumber)
 
-		//All the sources are completed
+		//The join can be triggered
 		if ok {
 			r1, _, err := processNodeRun(ctx, db, store, proj, wr, mapNodes, j, int(wr.LastSubNumber), sources, nil, nil)
 			if err != nil {
@@ -150,3 +123,30 @@ func processAllJoins(ctx context.Context, db gorp.SqlExecutor, store cache.Store
 	}
 	return report, nil
 }
+
+// joinSources returns the parent node runs to use to trigger the join node, and if the join strategy is fulfilled
+func joinSources(j *sdk.Node, sources []*sdk.WorkflowNodeRun, lastSubNumber int64) ([]*sdk.WorkflowNodeRun, bool) {
+	finished := make([]*sdk.WorkflowNodeRun, 0, len(sources))
+	succeeded := make([]*sdk.WorkflowNodeRun, 0, len(sources))
+	for _, nodeRun := range sources {
+		if nodeRun == nil || nodeRun.SubNumber < lastSubNumber || !sdk.StatusIsTerminated(nodeRun.Status) {
+			continue
+		}
+		finished = append(finished, nodeRun)
+		if nodeRun.Status != sdk.StatusNeverBuilt.String() && nodeRun.Status != sdk.StatusFail.String() && nodeRun.Status != sdk.StatusStopped.String() {
+			succeeded = append(succeeded, nodeRun)
+		}
+	}
+
+	nbParents := len(j.JoinContext)
+	switch j.JoinStrategy.GetType() {
+	case sdk.JoinStrategyAnySuccess:
+		return succeeded, len(succeeded) > 0
+	case sdk.JoinStrategyAtLeast:
+		return succeeded, len(succeeded) >= j.JoinStrategy.Min
+	case sdk.JoinStrategyAllFinished:
+		return finished, len(finished) == nbParents
+	default:
+		return succeeded, len(succeeded) == nbParents
+	}
+}
@@ -55,3 +55,37 @@ func TestGetWorkflowRunStatus(t *testing.T) {
 		assert.Equal(t, tc.status, status)
 	}
 }
+
+func TestJoinSources(t *testing.T) {
+	success := &sdk.WorkflowNodeRun{ID: 1, SubNumber: 0, Status: sdk.StatusSuccess.String()}
+	fail := &sdk.WorkflowNodeRun{ID: 2, SubNumber: 0, Status: sdk.StatusFail.String()}
+	building := &sdk.WorkflowNodeRun{ID: 3, SubNumber: 0, Status: sdk.StatusBuilding.String()}
+
+	j := &sdk.Node{
+		Type:        sdk.NodeTypeJoin,
+		JoinContext: []sdk.NodeJoin{{ParentID: 1}, {ParentID: 2}},
+	}
+
+	_, ok := joinSources(j, []*sdk.WorkflowNodeRun{success, fail}, 0)
+	assert.False(t, ok)
+
+	j.JoinStrategy = &sdk.NodeJoinStrategy{Type: sdk.JoinStrategyAnySuccess}
+	sources, ok := joinSources(j, []*sdk.WorkflowNodeRun{success, building}, 0)
+	assert.True(t, ok)
+	assert.Equal(t, []*sdk.WorkflowNodeRun{success}, sources)
+
+	j.JoinStrategy = &sdk.NodeJoinStrategy{Type: sdk.JoinStrategyAtLeast, Min: 2}
+	_, ok = joinSources(j, []*sdk.WorkflowNodeRun{success, fail}, 0)
+	assert.False(t, ok)
+
+	j.JoinStrategy = &sdk.NodeJoinStrategy{Type: sdk.JoinStrategyAllFinished}
+	_, ok = joinSources(j, []*sdk.WorkflowNodeRun{success, building}, 0)
+	assert.False(t, ok)
+	sources, ok = joinSources(j, []*sdk.WorkflowNodeRun{success, fail}, 0)
+	assert.True(t, ok)
+	assert.Len(t, sources, 2)
+
+	// Parents runs of a previous sub number are ignored
+	_, ok = joinSources(j, []*sdk.WorkflowNodeRun{success, fail}, 1)
+	assert.False(t, ok)
+}
@@ -0,0 +1,246 @@
+package workflow
+
+import (
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/cache"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/exportentities"
+)
+
+// PromotionSnapshot returns the files of the workflow and its dependencies as pulled, by file name. The secrets
+// are replaced by a placeholder: they are not shown in the diff of a promotion.
+func PromotionSnapshot(ctx context.Context, db gorp.SqlExecutor, store cache.Store, proj *sdk.Project, name string, u *sdk.User) (map[string]string, error) {
+	files := map[string]string{}
+	exist, err := Exists(db, proj.Key, name)
+	if err != nil {
+		return nil, sdk.WrapError(err, "cannot check if workflow %s exists", name)
+	}
+	if !exist {
+		return files, nil
+	}
+
+	hideSecret := func(_ gorp.SqlExecutor, _ int64, _ string, content string) (string, error) {
+		if content == "" {
+			return "", nil
+		}
+		return sdk.PasswordPlaceholder, nil
+	}
+	pull, err := Pull(ctx, db, store, proj, name, exportentities.FormatYAML, hideSecret, u)
+	if err != nil {
+		return nil, err
+	}
+
+	add := func(format string, item exportentities.WorkflowPulledItem) error {
+		btes, err := base64.StdEncoding.DecodeString(item.Value)
+		if err != nil {
+			return sdk.WithStack(err)
+		}
+		files[fmt.Sprintf(format, item.Name)] = string(btes)
+		return nil
+	}
+	if err := add(exportentities.PullWorkflowName, pull.Workflow); err != nil {
+		return nil, err
+	}
+	for _, item := range pull.Pipelines {
+		if err := add(exportentities.PullPipelineName, item); err != nil {
+			return nil, err
+		}
+	}
+	for _, item := range pull.Applications {
+		if err := add(exportentities.PullApplicationName, item); err != nil {
+			return nil, err
+		}
+	}
+	for _, item := range pull.Environments {
+		if err := add(exportentities.PullEnvironmentName, item); err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
+
+// PromotionDiff returns the unified diff between two snapshots of a workflow
+func PromotionDiff(before, after map[string]string) string {
+	names := make([]string, 0, len(before)+len(after))
+	for name := range before {
+		names = append(names, name)
+	}
+	for name := range after {
+		if _, ok := before[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var diff strings.Builder
+	for _, name := range names {
+		if before[name] == after[name] {
+			continue
+		}
+		fromFile, toFile := "a/"+name, "b/"+name
+		if _, ok := before[name]; !ok {
+			fromFile = "/dev/null"
+		}
+		if _, ok := after[name]; !ok {
+			toFile = "/dev/null"
+		}
+		diff.WriteString("--- " + fromFile + "\n")
+		diff.WriteString("+++ " + toFile + "\n")
+		diff.WriteString(unifiedDiff(splitLines(before[name]), splitLines(after[name]), 3))
+	}
+	return diff.String()
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	lines := strings.SplitAfter(s, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// unifiedDiff returns the hunks of the diff between the lines, with the given number of lines of context
+func unifiedDiff(a, b []string, context int) string {
+	// lcs[i][j] is the length of the longest common subsequence of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			switch {
+			case a[i] == b[j]:
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			case lcs[i+1][j] >= lcs[i][j+1]:
+				lcs[i][j] = lcs[i+1][j]
+			default:
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	type line struct {
+		op   byte
+		text string
+	}
+	var lines []line
+	for i, j := 0, 0; i < len(a) || j < len(b); {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			lines = append(lines, line{' ', a[i]})
+			i++
+			j++
+		case j == len(b) || (i < len(a) && lcs[i+1][j] >= lcs[i][j+1]):
+			lines = append(lines, line{'-', a[i]})
+			i++
+		default:
+			lines = append(lines, line{'+', b[j]})
+			j++
+		}
+	}
+
+	var out strings.Builder
+	// aLine and bLine are the numbers of the lines before lines[k] in a and b
+	var aLine, bLine int
+	for k := 0; k < len(lines); {
+		if lines[k].op == ' ' {
+			aLine++
+			bLine++
+			k++
+			continue
+		}
+		// A hunk ends with the context after its last change, the changes separated by at most 2*context
+		// unchanged lines are in the same hunk
+		start := k - context
+		if start < 0 {
+			start = 0
+		}
+		last := k
+		for j := k + 1; j < len(lines); j++ {
+			if lines[j].op == ' ' {
+				if j-last > 2*context {
+					break
+				}
+				continue
+			}
+			last = j
+		}
+		end := last + 1 + context
+		if end > len(lines) {
+			end = len(lines)
+		}
+		aStart, bStart := aLine-(k-start), bLine-(k-start)
+		var aLen, bLen int
+		var hunk strings.Builder
+		for _, l := range lines[start:end] {
+			if l.op != '+' {
+				aLen++
+			}
+			if l.op != '-' {
+				bLen++
+			}
+			hunk.WriteByte(l.op)
+			hunk.WriteString(l.text)
+			if !strings.HasSuffix(l.text, "\n") {
+				hunk.WriteString("\n\\ No newline at end of file\n")
+			}
+		}
+		fmt.Fprintf(&out, "@@ -%s +%s @@\n", hunkRange(aStart, aLen), hunkRange(bStart, bLen))
+		out.WriteString(hunk.String())
+		for _, l := range lines[k:end] {
+			if l.op != '+' {
+				aLine++
+			}
+			if l.op != '-' {
+				bLine++
+			}
+		}
+		k = end
+	}
+	return out.String()
+}
+
+func hunkRange(start, n int) string {
+	if n == 0 {
+		return fmt.Sprintf("%d,0", start)
+	}
+	return fmt.Sprintf("%d,%d", start+1, n)
+}
+
+// InsertPromotion records the promotion of a workflow
+func InsertPromotion(db gorp.SqlExecutor, p *sdk.WorkflowPromotion) error {
+	return sdk.WrapError(db.Insert(p), "cannot insert promotion of workflow %s", p.WorkflowName)
+}
+
+// LoadPromotions returns the promotions of a workflow, the last one first, without their diff
+func LoadPromotions(db gorp.SqlExecutor, key, name string) ([]sdk.WorkflowPromotion, error) {
+	ps := []sdk.WorkflowPromotion{}
+	if _, err := db.Select(&ps, `SELECT id, project_key, workflow_name, source, username, comment, created, '' AS diff
+		FROM workflow_promotion WHERE project_key = $1 AND workflow_name = $2 ORDER BY id DESC`, key, name); err != nil {
+		return nil, sdk.WrapError(err, "cannot load promotions of workflow %s", name)
+	}
+	return ps, nil
+}
+
+// LoadPromotion returns a promotion of a workflow with its diff
+func LoadPromotion(db gorp.SqlExecutor, key, name string, id int64) (*sdk.WorkflowPromotion, error) {
+	var p sdk.WorkflowPromotion
+	if err := db.SelectOne(&p, "SELECT * FROM workflow_promotion WHERE project_key = $1 AND workflow_name = $2 AND id = $3", key, name, id); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, sdk.WithStack(sdk.ErrNotFound)
+		}
+		return nil, sdk.WrapError(err, "cannot load promotion %d", id)
+	}
+	return &p, nil
+}
@@ -0,0 +1,78 @@
+package workflow
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPromotionDiff(t *testing.T) {
+	before := map[string]string{
+		"wf.yml":        "name: wf\nversion: v1.0\npipeline: build\n",
+		"build.pip.yml": "version: v1.0\nname: build\nsteps:\n- script: make\n",
+		"old.app.yml":   "version: v1.0\nname: old\n",
+	}
+	after := map[string]string{
+		"wf.yml":        "name: wf\nversion: v1.0\npipeline: build\n",
+		"build.pip.yml": "version: v1.0\nname: build\nsteps:\n- script: make test\n",
+		"new.env.yml":   "name: new",
+	}
+
+	assert.Equal(t, `--- a/build.pip.yml
++++ b/build.pip.yml
+@@ -1,4 +1,4 @@
+ version: v1.0
+ name: build
+ steps:
+-- script: make
++- script: make test
+--- /dev/null
++++ b/new.env.yml
+@@ -0,0 +1,1 @@
++name: new
+\ No newline at end of file
+--- a/old.app.yml
++++ /dev/null
+@@ -1,2 +0,0 @@
+-version: v1.0
+-name: old
+`, PromotionDiff(before, after))
+
+	assert.Equal(t, "", PromotionDiff(before, before))
+}
+
+func TestUnifiedDiffHunks(t *testing.T) {
+	a := []string{"1\n", "2\n", "3\n", "4\n", "5\n", "6\n", "7\n", "8\n", "9\n", "10\n", "11\n", "12\n"}
+	b := []string{"0\n", "1\n", "2\n", "3\n", "4\n", "5\n", "6\n", "7\n", "8\n", "9\n", "10\n", "11\n", "twelve\n"}
+
+	assert.Equal(t, `@@ -1,3 +1,4 @@
++0
+ 1
+ 2
+ 3
+@@ -9,4 +10,4 @@
+ 9
+ 10
+ 11
+-12
++twelve
+`, unifiedDiff(a, b, 3))
+
+	// The changes separated by at most 2*context lines are in the same hunk
+	assert.Equal(t, `@@ -1,12 +1,13 @@
++0
+ 1
+ 2
+ 3
+ 4
+ 5
+ 6
+ 7
+ 8
+ 9
+ 10
+ 11
+-12
++twelve
+`, unifiedDiff(a, b, 6))
+}
@@ -0,0 +1,290 @@
+package queue
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/cache"
+	"github.com/ovh/cds/engine/api/database/gorpmapping"
+	"github.com/ovh/cds/engine/api/environment"
+	"github.com/ovh/cds/engine/api/organization"
+	"github.com/ovh/cds/engine/api/workflow"
+	"github.com/ovh/cds/sdk"
+)
+
+// LoadProjectQuotas returns the quotas set on the projects.
+func LoadProjectQuotas(db gorp.SqlExecutor) ([]sdk.ProjectQueueQuota, error) {
+	qs := []sdk.ProjectQueueQuota{}
+	query := `
+	SELECT project_queue_quota.project_id, project.projectkey AS project_key, project_queue_quota.max_concurrent_jobs
+	FROM project_queue_quota
+	JOIN project ON project.id = project_queue_quota.project_id
+	ORDER BY project.projectkey`
+	if _, err := db.Select(&qs, query); err != nil {
+		return nil, sdk.WrapError(err, "cannot load project queue quotas")
+	}
+	return qs, nil
+}
+
+// LoadProjectQuota returns the quota set on a project, nil if the project uses the default quota.
+func LoadProjectQuota(db gorp.SqlExecutor, projectID int64) (*sdk.ProjectQueueQuota, error) {
+	var q sdk.ProjectQueueQuota
+	query := `
+	SELECT project_queue_quota.project_id, project.projectkey AS project_key, project_queue_quota.max_concurrent_jobs
+	FROM project_queue_quota
+	JOIN project ON project.id = project_queue_quota.project_id
+	WHERE project_queue_quota.project_id = $1`
+	if err := db.SelectOne(&q, query, projectID); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, sdk.WrapError(err, "cannot load queue quota of project %d", projectID)
+	}
+	return &q, nil
+}
+
+// UpsertProjectQuota sets the quota of a project.
+func UpsertProjectQuota(db gorp.SqlExecutor, projectID int64, maxConcurrentJobs int) error {
+	query := `
+	INSERT INTO project_queue_quota (project_id, max_concurrent_jobs) VALUES ($1, $2)
+	ON CONFLICT (project_id) DO UPDATE SET max_concurrent_jobs = $2`
+	if _, err := db.Exec(query, projectID, maxConcurrentJobs); err != nil {
+		return sdk.WrapError(err, "cannot set queue quota of project %d", projectID)
+	}
+	return nil
+}
+
+// DeleteProjectQuota removes the quota of a project, the default quota applies again.
+func DeleteProjectQuota(db gorp.SqlExecutor, projectID int64) error {
+	if _, err := db.Exec("DELETE FROM project_queue_quota WHERE project_id = $1", projectID); err != nil {
+		return sdk.WrapError(err, "cannot delete queue quota of project %d", projectID)
+	}
+	return nil
+}
+
+// LoadGroupWeights returns the weights set on the groups.
+func LoadGroupWeights(db gorp.SqlExecutor) ([]sdk.GroupQueueWeight, error) {
+	ws := []sdk.GroupQueueWeight{}
+	query := `
+	SELECT group_queue_weight.group_id, "group".name AS group_name, group_queue_weight.weight
+	FROM group_queue_weight
+	JOIN "group" ON "group".id = group_queue_weight.group_id
+	ORDER BY "group".name`
+	if _, err := db.Select(&ws, query); err != nil {
+		return nil, sdk.WrapError(err, "cannot load group queue weights")
+	}
+	return ws, nil
+}
+
+// UpsertGroupWeight sets the weight of a group.
+func UpsertGroupWeight(db gorp.SqlExecutor, groupID int64, weight int) error {
+	query := `
+	INSERT INTO group_queue_weight (group_id, weight) VALUES ($1, $2)
+	ON CONFLICT (group_id) DO UPDATE SET weight = $2`
+	if _, err := db.Exec(query, groupID, weight); err != nil {
+		return sdk.WrapError(err, "cannot set queue weight of group %d", groupID)
+	}
+	return nil
+}
+
+// DeleteGroupWeight removes the weight of a group, the default weight applies again.
+func DeleteGroupWeight(db gorp.SqlExecutor, groupID int64) error {
+	if _, err := db.Exec("DELETE FROM group_queue_weight WHERE group_id = $1", groupID); err != nil {
+		return sdk.WrapError(err, "cannot delete queue weight of group %d", groupID)
+	}
+	return nil
+}
+
+type buildingJob struct {
+	ProjectID  int64          `db:"project_id"`
+	ExecGroups sql.NullString `db:"exec_groups"`
+}
+
+// loadBuildingJobs returns the project and the groups of the jobs currently building.
+func loadBuildingJobs(db gorp.SqlExecutor) ([]sdk.WorkflowNodeJobRun, error) {
+	var rows []buildingJob
+	query := "SELECT project_id, exec_groups FROM workflow_node_run_job WHERE status = $1"
+	if _, err := db.Select(&rows, query, sdk.StatusBuilding.String()); err != nil {
+		return nil, sdk.WrapError(err, "cannot load building jobs")
+	}
+	jobs := make([]sdk.WorkflowNodeJobRun, len(rows))
+	for i := range rows {
+		jobs[i].ProjectID = rows[i].ProjectID
+		if err := gorpmapping.JSONNullString(rows[i].ExecGroups, &jobs[i].ExecGroups); err != nil {
+			return nil, sdk.WrapError(err, "column exec_groups")
+		}
+	}
+	return jobs, nil
+}
+
+// lockProject locks the project until the end of the transaction, to serialize the admission of its jobs between the
+// API instances.
+func lockProject(db gorp.SqlExecutor, projectID int64) error {
+	if _, err := db.Exec("SELECT id FROM project WHERE id = $1 FOR UPDATE", projectID); err != nil {
+		return sdk.WrapError(err, "cannot lock project %d", projectID)
+	}
+	return nil
+}
+
+// countBookedJobs returns the number of waiting jobs of the project, other than the given job, booked by a hatchery.
+func countBookedJobs(db gorp.SqlExecutor, store cache.Store, projectID, jobID int64) (int, error) {
+	var ids []int64
+	query := "SELECT id FROM workflow_node_run_job WHERE project_id = $1 AND status = $2 AND id <> $3"
+	if _, err := db.Select(&ids, query, projectID, sdk.StatusWaiting.String(), jobID); err != nil {
+		return 0, sdk.WrapError(err, "cannot load waiting jobs of project %d", projectID)
+	}
+	var count int
+	for _, id := range ids {
+		if workflow.IsNodeJobRunBooked(store, id) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// CheckProjectQuota returns an error if the project has as many building and booked jobs, other than the given job, as
+// its quota. The project is locked until the end of the transaction so that concurrent calls can't exceed the quota.
+func CheckProjectQuota(db gorp.SqlExecutor, store cache.Store, projectID, jobID int64, defaultProjectQuota int) error {
+	quota := defaultProjectQuota
+	q, err := LoadProjectQuota(db, projectID)
+	if err != nil {
+		return err
+	}
+	if q != nil {
+		quota = q.MaxConcurrentJobs
+	}
+	if quota <= 0 {
+		return nil
+	}
+	if err := lockProject(db, projectID); err != nil {
+		return err
+	}
+	building, err := db.SelectInt("SELECT COUNT(1) FROM workflow_node_run_job WHERE project_id = $1 AND status = $2 AND id <> $3", projectID, sdk.StatusBuilding.String(), jobID)
+	if err != nil {
+		return sdk.WrapError(err, "cannot count building jobs of project %d", projectID)
+	}
+	booked, err := countBookedJobs(db, store, projectID, jobID)
+	if err != nil {
+		return err
+	}
+	if int(building)+booked >= quota {
+		return sdk.NewErrorFrom(sdk.ErrQueueQuotaExceeded, "%d jobs are building and %d are booked, the quota is %d", building, booked, quota)
+	}
+	return nil
+}
+
+type organizationQuota struct {
+	ProjectID         int64 `db:"project_id"`
+	OrganizationID    int64 `db:"organization_id"`
+	MaxConcurrentJobs int   `db:"max_concurrent_jobs"`
+}
+
+// loadOrganizationQuotas returns the organization and its quota of the projects which belong to an organization.
+func loadOrganizationQuotas(db gorp.SqlExecutor) ([]organizationQuota, error) {
+	var qs []organizationQuota
+	query := `
+	SELECT project.id AS project_id, organization.id AS organization_id, organization.max_concurrent_jobs
+	FROM project
+	JOIN organization ON organization.id = project.organization_id`
+	if _, err := db.Select(&qs, query); err != nil {
+		return nil, sdk.WrapError(err, "cannot load organization queue quotas")
+	}
+	return qs, nil
+}
+
+// loadOrganizationsOverBuildQuota returns the organizations which used all their build minutes of the month.
+func loadOrganizationsOverBuildQuota(db gorp.SqlExecutor) ([]int64, error) {
+	var ids []int64
+	query := `
+	SELECT organization.id
+	FROM organization
+	JOIN project ON project.organization_id = organization.id
+	JOIN resource_usage ON resource_usage.project_id = project.id AND resource_usage.month = $1
+	WHERE organization.max_build_minutes > 0
+	GROUP BY organization.id, organization.max_build_minutes
+	HAVING SUM(resource_usage.build_seconds) >= organization.max_build_minutes * 60`
+	if _, err := db.Select(&ids, query, sdk.UsageMonth(time.Now())); err != nil {
+		return nil, sdk.WrapError(err, "cannot load organizations over their build quota")
+	}
+	return ids, nil
+}
+
+// loadWaitingFreezeOverrides returns the node runs with waiting jobs which can deploy on a frozen environment.
+func loadWaitingFreezeOverrides(db gorp.SqlExecutor) ([]int64, error) {
+	var ids []int64
+	query := `
+	SELECT DISTINCT environment_freeze_override.workflow_node_run_id
+	FROM environment_freeze_override
+	JOIN workflow_node_run_job ON workflow_node_run_job.workflow_node_run_id = environment_freeze_override.workflow_node_run_id
+	WHERE workflow_node_run_job.status = $1`
+	if _, err := db.Select(&ids, query, sdk.StatusWaiting.String()); err != nil {
+		return nil, sdk.WrapError(err, "cannot load environment freeze overrides")
+	}
+	return ids, nil
+}
+
+// CheckEnvironmentFreeze returns an error if the job deploys on a frozen environment and the freeze was not overridden
+// for its node run.
+func CheckEnvironmentFreeze(db gorp.SqlExecutor, job *sdk.WorkflowNodeJobRun) error {
+	envName := sdk.ParameterValue(job.Parameters, "cds.environment")
+	if envName == "" {
+		return nil
+	}
+	overridden, err := environment.IsFreezeOverridden(db, job.WorkflowNodeRunID)
+	if err != nil || overridden {
+		return err
+	}
+	var envID int64
+	if err := db.SelectOne(&envID, "SELECT id FROM environment WHERE project_id = $1 AND name = $2", job.ProjectID, envName); err != nil {
+		if err == sql.ErrNoRows {
+			return nil
+		}
+		return sdk.WrapError(err, "cannot load environment %s", envName)
+	}
+	f, err := environment.ActiveFreeze(db, envID)
+	if err != nil {
+		return err
+	}
+	if f != nil {
+		return sdk.NewErrorFrom(sdk.ErrEnvironmentFrozen, "%s", f.Message(time.Now()))
+	}
+	return nil
+}
+
+// CheckOrganizationQuota returns an error if the projects of the organization of the project have as many building
+// jobs as the quota of the organization, or used all its build minutes of the month.
+func CheckOrganizationQuota(db gorp.SqlExecutor, projectID int64) error {
+	var q organizationQuota
+	query := `
+	SELECT project.id AS project_id, organization.id AS organization_id, organization.max_concurrent_jobs
+	FROM project
+	JOIN organization ON organization.id = project.organization_id
+	WHERE project.id = $1`
+	if err := db.SelectOne(&q, query, projectID); err != nil {
+		if err == sql.ErrNoRows {
+			return nil
+		}
+		return sdk.WrapError(err, "cannot load organization queue quota of project %d", projectID)
+	}
+	if err := organization.CheckBuildQuota(db, q.OrganizationID); err != nil {
+		return err
+	}
+	if q.MaxConcurrentJobs <= 0 {
+		return nil
+	}
+
+	// the organization is locked until the end of the transaction so that concurrent calls can't exceed the quota
+	if _, err := db.Exec("SELECT id FROM organization WHERE id = $1 FOR UPDATE", q.OrganizationID); err != nil {
+		return sdk.WrapError(err, "cannot lock organization %d", q.OrganizationID)
+	}
+	count, err := organization.CountBuildingJobs(db, q.OrganizationID)
+	if err != nil {
+		return err
+	}
+	if int(count) >= q.MaxConcurrentJobs {
+		return sdk.NewErrorFrom(sdk.ErrOrganizationQuotaExceeded, "%d jobs of the organization are building, the quota is %d", count, q.MaxConcurrentJobs)
+	}
+	return nil
+}
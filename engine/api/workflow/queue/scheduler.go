@@ -0,0 +1,263 @@
+package queue
+
+import (
+	"time"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/environment"
+	"github.com/ovh/cds/engine/api/group"
+	"github.com/ovh/cds/sdk"
+)
+
+// DefaultGroupWeight is the weight of the groups without a weight set by an administrator
+const DefaultGroupWeight = 1
+
+// Scheduler orders the waiting jobs so that the workers are shared between the groups
+// according to their weight, and filters the jobs of the projects which reached their quota or the quota
+// of their organization, and the jobs deploying on a frozen environment.
+type Scheduler struct {
+	// Fair is false to keep the jobs in the order they were queued, only the quotas are enforced
+	Fair                  bool
+	DefaultProjectQuota   int
+	ProjectQuotas         map[int64]int
+	ProjectOrganizations  map[int64]int64
+	OrganizationQuotas    map[int64]int
+	BuildQuotaExceeded    map[int64]bool // organizations which used all their build minutes of the month
+	GroupWeights          map[int64]int
+	Freezes               map[int64]map[string][]sdk.EnvironmentFreeze // freezes by project and environment name
+	FreezeOverrides       map[int64]bool                               // node runs which can deploy on a frozen environment
+	runningByProject      map[int64]int
+	runningByOrganization map[int64]int
+	runningByGroup        map[int64]int
+}
+
+// NewScheduler loads the quotas, the weights and the jobs currently building.
+// A default project quota of 0 means no limit.
+func NewScheduler(db gorp.SqlExecutor, fair bool, defaultProjectQuota int) (*Scheduler, error) {
+	s := &Scheduler{
+		Fair:                  fair,
+		DefaultProjectQuota:   defaultProjectQuota,
+		ProjectQuotas:         map[int64]int{},
+		ProjectOrganizations:  map[int64]int64{},
+		OrganizationQuotas:    map[int64]int{},
+		BuildQuotaExceeded:    map[int64]bool{},
+		GroupWeights:          map[int64]int{},
+		Freezes:               map[int64]map[string][]sdk.EnvironmentFreeze{},
+		FreezeOverrides:       map[int64]bool{},
+		runningByProject:      map[int64]int{},
+		runningByOrganization: map[int64]int{},
+		runningByGroup:        map[int64]int{},
+	}
+
+	quotas, err := LoadProjectQuotas(db)
+	if err != nil {
+		return nil, err
+	}
+	for _, q := range quotas {
+		s.ProjectQuotas[q.ProjectID] = q.MaxConcurrentJobs
+	}
+
+	orgQuotas, err := loadOrganizationQuotas(db)
+	if err != nil {
+		return nil, err
+	}
+	for _, q := range orgQuotas {
+		s.ProjectOrganizations[q.ProjectID] = q.OrganizationID
+		s.OrganizationQuotas[q.OrganizationID] = q.MaxConcurrentJobs
+	}
+
+	overBuildQuota, err := loadOrganizationsOverBuildQuota(db)
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range overBuildQuota {
+		s.BuildQuotaExceeded[id] = true
+	}
+
+	weights, err := LoadGroupWeights(db)
+	if err != nil {
+		return nil, err
+	}
+	for _, w := range weights {
+		s.GroupWeights[w.GroupID] = w.Weight
+	}
+
+	freezes, err := environment.LoadAllFreezes(db)
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range freezes {
+		if _, ok := s.Freezes[f.ProjectID]; !ok {
+			s.Freezes[f.ProjectID] = map[string][]sdk.EnvironmentFreeze{}
+		}
+		s.Freezes[f.ProjectID][f.EnvironmentName] = append(s.Freezes[f.ProjectID][f.EnvironmentName], f)
+	}
+
+	overrides, err := loadWaitingFreezeOverrides(db)
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range overrides {
+		s.FreezeOverrides[id] = true
+	}
+
+	building, err := loadBuildingJobs(db)
+	if err != nil {
+		return nil, err
+	}
+	for _, j := range building {
+		s.AddRunning(j)
+	}
+	return s, nil
+}
+
+// AddRunning counts a building job in the usage of its project, of its organization and of its group.
+func (s *Scheduler) AddRunning(j sdk.WorkflowNodeJobRun) {
+	s.runningByProject[j.ProjectID]++
+	if o, ok := s.ProjectOrganizations[j.ProjectID]; ok {
+		s.runningByOrganization[o]++
+	}
+	if s.Fair {
+		s.runningByGroup[s.jobGroup(j)]++
+	}
+}
+
+// quota returns the maximum number of building jobs of a project, 0 means no limit.
+func (s *Scheduler) quota(projectID int64) int {
+	if q, ok := s.ProjectQuotas[projectID]; ok {
+		return q
+	}
+	return s.DefaultProjectQuota
+}
+
+func (s *Scheduler) weight(groupID int64) int {
+	if w, ok := s.GroupWeights[groupID]; ok && w > 0 {
+		return w
+	}
+	return DefaultGroupWeight
+}
+
+// jobGroup returns the group which is charged for a job: the executing group with the highest weight.
+// The shared.infra group is ignored as it can run the jobs of every project.
+func (s *Scheduler) jobGroup(j sdk.WorkflowNodeJobRun) int64 {
+	var groupID int64
+	var weight int
+	for _, g := range j.ExecGroups {
+		if group.SharedInfraGroup != nil && g.ID == group.SharedInfraGroup.ID {
+			continue
+		}
+		w := s.weight(g.ID)
+		if groupID == 0 || w > weight || (w == weight && g.ID < groupID) {
+			groupID, weight = g.ID, w
+		}
+	}
+	return groupID
+}
+
+// organizationQuota returns the organization of a project and its maximum number of building jobs, 0 means no limit.
+func (s *Scheduler) organizationQuota(projectID int64) (int64, int) {
+	o, ok := s.ProjectOrganizations[projectID]
+	if !ok {
+		return 0, 0
+	}
+	return o, s.OrganizationQuotas[o]
+}
+
+// QuotaExceeded returns true if the project can not start another job.
+func (s *Scheduler) QuotaExceeded(projectID int64) bool {
+	if q := s.quota(projectID); q > 0 && s.runningByProject[projectID] >= q {
+		return true
+	}
+	o, q := s.organizationQuota(projectID)
+	return s.BuildQuotaExceeded[o] || (q > 0 && s.runningByOrganization[o] >= q)
+}
+
+// Frozen returns the freeze blocking a job, if its node deploys on a frozen environment and the freeze was not
+// overridden for its node run.
+func (s *Scheduler) Frozen(j sdk.WorkflowNodeJobRun) *sdk.EnvironmentFreeze {
+	if s.FreezeOverrides[j.WorkflowNodeRunID] {
+		return nil
+	}
+	env := sdk.ParameterValue(j.Parameters, "cds.environment")
+	if env == "" {
+		return nil
+	}
+	return sdk.ActiveEnvironmentFreeze(s.Freezes[j.ProjectID][env], time.Now())
+}
+
+// Schedule returns the jobs in the order they should be started. The waiting jobs of the projects
+// which reached their quota or a quota of their organization, and the waiting jobs deploying on a frozen environment, are removed. Between groups, the next job is taken from the group with the
+// lowest number of building and already scheduled jobs relatively to its weight, unless another group has
+// a job with a higher priority; inside a group the jobs keep the order of the queue.
+func (s *Scheduler) Schedule(jobs []sdk.WorkflowNodeJobRun) []sdk.WorkflowNodeJobRun {
+	res := make([]sdk.WorkflowNodeJobRun, 0, len(jobs))
+
+	var groups []int64
+	pending := map[int64][]sdk.WorkflowNodeJobRun{}
+	for _, j := range jobs {
+		if j.Status != sdk.StatusWaiting.String() {
+			res = append(res, j)
+			continue
+		}
+		if s.Frozen(j) != nil {
+			continue
+		}
+		var g int64
+		if s.Fair {
+			g = s.jobGroup(j)
+		}
+		if _, ok := pending[g]; !ok {
+			groups = append(groups, g)
+		}
+		pending[g] = append(pending[g], j)
+	}
+
+	scheduledByProject := map[int64]int{}
+	scheduledByOrganization := map[int64]int{}
+	scheduledByGroup := map[int64]int{}
+	for {
+		// Find the group with the lowest usage, groups are compared with cross products to avoid float divisions
+		var next int64
+		var found bool
+		for _, g := range groups {
+			if len(pending[g]) == 0 {
+				continue
+			}
+			if !found {
+				next, found = g, true
+				continue
+			}
+			// The jobs of the runs with the highest priority are started first, whatever the usage of their group
+			if p, nextP := pending[g][0].Priority, pending[next][0].Priority; p != nextP {
+				if p > nextP {
+					next = g
+				}
+				continue
+			}
+			usage := (s.runningByGroup[g] + scheduledByGroup[g]) * s.weight(next)
+			nextUsage := (s.runningByGroup[next] + scheduledByGroup[next]) * s.weight(g)
+			if usage < nextUsage || (usage == nextUsage && pending[g][0].Queued.Before(pending[next][0].Queued)) {
+				next = g
+			}
+		}
+		if !found {
+			break
+		}
+
+		j := pending[next][0]
+		pending[next] = pending[next][1:]
+		if q := s.quota(j.ProjectID); q > 0 && s.runningByProject[j.ProjectID]+scheduledByProject[j.ProjectID] >= q {
+			continue
+		}
+		o, oq := s.organizationQuota(j.ProjectID)
+		if s.BuildQuotaExceeded[o] || (oq > 0 && s.runningByOrganization[o]+scheduledByOrganization[o] >= oq) {
+			continue
+		}
+		scheduledByProject[j.ProjectID]++
+		scheduledByOrganization[o]++
+		scheduledByGroup[next]++
+		res = append(res, j)
+	}
+	return res
+}
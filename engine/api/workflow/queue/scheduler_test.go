@@ -0,0 +1,189 @@
+package queue
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func newTestScheduler() *Scheduler {
+	return &Scheduler{
+		Fair:                  true,
+		ProjectQuotas:         map[int64]int{},
+		ProjectOrganizations:  map[int64]int64{},
+		OrganizationQuotas:    map[int64]int{},
+		BuildQuotaExceeded:    map[int64]bool{},
+		GroupWeights:          map[int64]int{},
+		Freezes:               map[int64]map[string][]sdk.EnvironmentFreeze{},
+		FreezeOverrides:       map[int64]bool{},
+		runningByProject:      map[int64]int{},
+		runningByOrganization: map[int64]int{},
+		runningByGroup:        map[int64]int{},
+	}
+}
+
+func testJob(id, projectID, groupID int64, queued time.Time) sdk.WorkflowNodeJobRun {
+	return sdk.WorkflowNodeJobRun{
+		ID:         id,
+		ProjectID:  projectID,
+		Status:     sdk.StatusWaiting.String(),
+		Queued:     queued,
+		ExecGroups: []sdk.Group{{ID: groupID}},
+	}
+}
+
+func jobIDs(jobs []sdk.WorkflowNodeJobRun) []int64 {
+	ids := make([]int64, len(jobs))
+	for i := range jobs {
+		ids[i] = jobs[i].ID
+	}
+	return ids
+}
+
+func TestScheduleFairShare(t *testing.T) {
+	now := time.Now()
+	s := newTestScheduler()
+
+	// The noisy group queued its jobs first, the other group must not wait for all of them
+	jobs := []sdk.WorkflowNodeJobRun{
+		testJob(1, 1, 10, now),
+		testJob(2, 1, 10, now.Add(time.Second)),
+		testJob(3, 1, 10, now.Add(2*time.Second)),
+		testJob(4, 2, 20, now.Add(3*time.Second)),
+		testJob(5, 2, 20, now.Add(4*time.Second)),
+	}
+	assert.Equal(t, []int64{1, 4, 2, 5, 3}, jobIDs(s.Schedule(jobs)))
+}
+
+func TestScheduleWeightsAndRunningJobs(t *testing.T) {
+	now := time.Now()
+	s := newTestScheduler()
+	s.GroupWeights[10] = 2
+	s.AddRunning(testJob(0, 2, 20, now))
+
+	jobs := []sdk.WorkflowNodeJobRun{
+		testJob(1, 1, 10, now),
+		testJob(2, 1, 10, now.Add(time.Second)),
+		testJob(3, 1, 10, now.Add(2*time.Second)),
+		testJob(4, 2, 20, now.Add(3*time.Second)),
+	}
+	assert.Equal(t, []int64{1, 2, 3, 4}, jobIDs(s.Schedule(jobs)))
+}
+
+func TestScheduleProjectQuota(t *testing.T) {
+	now := time.Now()
+	s := newTestScheduler()
+	s.DefaultProjectQuota = 2
+	s.ProjectQuotas[2] = 1
+	s.AddRunning(testJob(0, 1, 10, now))
+
+	jobs := []sdk.WorkflowNodeJobRun{
+		testJob(1, 1, 10, now),
+		testJob(2, 1, 10, now.Add(time.Second)),
+		testJob(3, 2, 20, now.Add(2*time.Second)),
+		testJob(4, 2, 20, now.Add(3*time.Second)),
+	}
+	assert.Equal(t, []int64{3, 1}, jobIDs(s.Schedule(jobs)))
+	assert.False(t, s.QuotaExceeded(1))
+	s.AddRunning(testJob(5, 2, 20, now))
+	assert.True(t, s.QuotaExceeded(2))
+}
+
+func TestScheduleOrganizationQuota(t *testing.T) {
+	now := time.Now()
+	s := newTestScheduler()
+	// Projects 1 and 2 share the quota of their organization, project 3 is not in an organization
+	s.ProjectOrganizations[1] = 100
+	s.ProjectOrganizations[2] = 100
+	s.OrganizationQuotas[100] = 2
+	s.AddRunning(testJob(0, 1, 10, now))
+
+	jobs := []sdk.WorkflowNodeJobRun{
+		testJob(1, 1, 10, now),
+		testJob(2, 2, 20, now.Add(time.Second)),
+		testJob(3, 3, 30, now.Add(2*time.Second)),
+		testJob(4, 3, 30, now.Add(3*time.Second)),
+	}
+	// Only one more job of the organization can start, the group of project 2 has no building job
+	assert.Equal(t, []int64{2, 3, 4}, jobIDs(s.Schedule(jobs)))
+	assert.False(t, s.QuotaExceeded(2))
+	s.AddRunning(testJob(5, 2, 20, now))
+	assert.True(t, s.QuotaExceeded(1))
+	assert.False(t, s.QuotaExceeded(3))
+}
+
+func TestScheduleOrganizationBuildQuota(t *testing.T) {
+	now := time.Now()
+	s := newTestScheduler()
+	s.ProjectOrganizations[1] = 100
+	s.BuildQuotaExceeded[100] = true
+
+	jobs := []sdk.WorkflowNodeJobRun{
+		testJob(1, 1, 10, now),
+		testJob(2, 2, 20, now.Add(time.Second)),
+	}
+	assert.Equal(t, []int64{2}, jobIDs(s.Schedule(jobs)))
+	assert.True(t, s.QuotaExceeded(1))
+	assert.False(t, s.QuotaExceeded(2))
+}
+
+func TestScheduleFIFO(t *testing.T) {
+	now := time.Now()
+	s := newTestScheduler()
+	s.Fair = false
+	s.ProjectQuotas[1] = 2
+
+	jobs := []sdk.WorkflowNodeJobRun{
+		testJob(1, 1, 10, now),
+		testJob(2, 1, 10, now.Add(time.Second)),
+		testJob(3, 1, 10, now.Add(2*time.Second)),
+		testJob(4, 2, 20, now.Add(3*time.Second)),
+	}
+	assert.Equal(t, []int64{1, 2, 4}, jobIDs(s.Schedule(jobs)))
+}
+
+func TestSchedulePriority(t *testing.T) {
+	now := time.Now()
+	s := newTestScheduler()
+
+	urgent := testJob(4, 2, 20, now.Add(3*time.Second))
+	urgent.Priority = sdk.WorkflowRunPriorityUrgent
+	jobs := []sdk.WorkflowNodeJobRun{
+		testJob(1, 1, 10, now),
+		testJob(2, 1, 10, now.Add(time.Second)),
+		testJob(3, 2, 20, now.Add(2*time.Second)),
+		urgent,
+	}
+	// The queue is loaded ordered by priority then by date
+	jobs[2], jobs[3] = jobs[3], jobs[2]
+	assert.Equal(t, []int64{4, 1, 2, 3}, jobIDs(s.Schedule(jobs)))
+}
+
+func TestScheduleFrozenEnvironment(t *testing.T) {
+	now := time.Now()
+	s := newTestScheduler()
+	s.Freezes[1] = map[string][]sdk.EnvironmentFreeze{
+		"production": {{Type: sdk.EnvironmentFreezeLock, EnvironmentName: "production"}},
+	}
+
+	deploy := func(id, projectID, nodeRunID int64, env string) sdk.WorkflowNodeJobRun {
+		j := testJob(id, projectID, 10, now.Add(time.Duration(id)*time.Second))
+		j.WorkflowNodeRunID = nodeRunID
+		j.Parameters = []sdk.Parameter{{Name: "cds.environment", Value: env}}
+		return j
+	}
+	jobs := []sdk.WorkflowNodeJobRun{
+		deploy(1, 1, 100, "production"),
+		deploy(2, 1, 101, "staging"),
+		deploy(3, 2, 102, "production"),
+		deploy(4, 1, 103, "production"),
+		testJob(5, 1, 10, now.Add(5*time.Second)),
+	}
+	s.FreezeOverrides[103] = true
+	assert.NotNil(t, s.Frozen(jobs[0]))
+	assert.Nil(t, s.Frozen(jobs[3]))
+	assert.Equal(t, []int64{2, 3, 4, 5}, jobIDs(s.Schedule(jobs)))
+}
@@ -6,6 +6,7 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"path"
 	"path/filepath"
 	"time"
 
@@ -23,11 +24,15 @@ import (
 // WorkflowAsCodePattern is the default code pattern to find cds files
 const WorkflowAsCodePattern = ".cds/**/*.yml"
 
+// repositoryActionsDirectory is the directory of the actions defined in the repository, in the .cds directory
+const repositoryActionsDirectory = "actions"
+
 // PushOption is the set of options for workflow push
 type PushOption struct {
 	VCSServer          string
 	FromRepository     string
 	Branch             string
+	Commit             string
 	IsDefaultBranch    bool
 	RepositoryName     string
 	RepositoryStrategy sdk.RepositoryStrategy
@@ -91,6 +96,7 @@ func extractWorkflow(ctx context.Context, db *gorp.DbMap, store cache.Store, p *
 		RepositoryName:     ope.RepoFullName,
 		RepositoryStrategy: ope.RepositoryStrategy,
 		Branch:             ope.Setup.Checkout.Branch,
+		Commit:             ope.Setup.Checkout.Commit,
 		FromRepository:     ope.RepositoryInfo.FetchURL,
 		IsDefaultBranch:    ope.Setup.Checkout.Branch == ope.RepositoryInfo.DefaultBranch,
 		DryRun:             true,
@@ -105,7 +111,8 @@ func extractWorkflow(ctx context.Context, db *gorp.DbMap, store cache.Store, p *
 	return allMsg, nil
 }
 
-// ReadCDSFiles reads CDS files
+// ReadCDSFiles reads CDS files. The files of the actions directory keep it as prefix, the other files are read by
+// their base name.
 func ReadCDSFiles(files map[string][]byte) (*tar.Reader, error) {
 	// Create a buffer to write our archive to.
 	buf := new(bytes.Buffer)
@@ -114,8 +121,12 @@ func ReadCDSFiles(files map[string][]byte) (*tar.Reader, error) {
 	// Add some files to the archive.
 	for fname, fcontent := range files {
 		log.Debug("ReadCDSFiles> Reading %s", fname)
+		name := filepath.Base(fname)
+		if filepath.Base(filepath.Dir(fname)) == repositoryActionsDirectory {
+			name = path.Join(repositoryActionsDirectory, name)
+		}
 		hdr := &tar.Header{
-			Name: filepath.Base(fname),
+			Name: name,
 			Mode: 0600,
 			Size: int64(len(fcontent)),
 		}
@@ -245,6 +256,48 @@ func PostRepositoryOperation(ctx context.Context, db gorp.SqlExecutor, prj sdk.P
 	return nil
 }
 
+// mirrorTimeout bounds the request sent to the repositories service to refresh a mirror
+const mirrorTimeout = 30 * time.Second
+
+// postRepositoryMirror asks the repositories service to refresh the mirror of the repository of an application,
+// the url is the one used by the workers to clone the repository. The request is sent in background so that the
+// processing of the workflow run does not wait for the repositories service.
+func postRepositoryMirror(ctx context.Context, db gorp.SqlExecutor, prj sdk.Project, app sdk.Application, url string) error {
+	srvs, err := services.FindByType(db, services.TypeRepositories)
+	if err != nil {
+		return sdk.WrapError(err, "Unable to found repositories service")
+	}
+	if len(srvs) == 0 || url == "" {
+		return nil
+	}
+
+	ope := sdk.Operation{
+		ProjectKey:         prj.Key,
+		VCSServer:          app.VCSServer,
+		RepoFullName:       app.RepositoryFullname,
+		URL:                url,
+		RepositoryStrategy: app.RepositoryStrategy,
+		Setup:              sdk.OperationSetup{Mirror: true},
+	}
+	if ope.RepositoryStrategy.ConnectionType == "ssh" {
+		for _, k := range prj.Keys {
+			if k.Name == ope.RepositoryStrategy.SSHKey {
+				ope.RepositoryStrategy.SSHKeyContent = k.Private
+				break
+			}
+		}
+	}
+
+	sdk.GoRoutine(context.Background(), "postRepositoryMirror-"+app.RepositoryFullname, func(ctx context.Context) {
+		ctx, cancel := context.WithTimeout(ctx, mirrorTimeout)
+		defer cancel()
+		if _, err := services.DoJSONRequest(ctx, srvs, http.MethodPost, "/operations", ope, &ope); err != nil {
+			log.Warning("postRepositoryMirror> unable to refresh the mirror of %s: %v", app.RepositoryFullname, err)
+		}
+	})
+	return nil
+}
+
 // GetRepositoryOperation get repository operation status
 func GetRepositoryOperation(ctx context.Context, db gorp.SqlExecutor, ope *sdk.Operation) error {
 	srvs, err := services.FindByType(db, services.TypeRepositories)
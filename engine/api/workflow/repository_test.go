@@ -0,0 +1,28 @@
+package workflow
+
+import (
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadCDSFiles(t *testing.T) {
+	tr, err := ReadCDSFiles(map[string][]byte{
+		".cds/my-workflow.yml":          []byte("name: my-workflow"),
+		".cds/actions/my-action.yml":    []byte("name: my-action"),
+		".cds/pipelines/my-pip.pip.yml": []byte("name: my-pip"),
+	})
+	assert.NoError(t, err)
+
+	var names []string
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		assert.NoError(t, err)
+		names = append(names, hdr.Name)
+	}
+	assert.ElementsMatch(t, []string{"my-workflow.yml", "actions/my-action.yml", "my-pip.pip.yml"}, names)
+}
@@ -0,0 +1,253 @@
+package workflow
+
+import (
+	"bytes"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	dump "github.com/fsamin/go-dump"
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/group"
+	"github.com/ovh/cds/engine/api/permission"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// UpstreamHook is an incoming hook of a workflow waiting for the success of a node of an upstream workflow
+type UpstreamHook struct {
+	sdk.WorkflowNodeHook
+	ProjectKey   string
+	WorkflowName string
+}
+
+// UpstreamHookEvent is a hook event to send to a downstream workflow
+type UpstreamHookEvent struct {
+	ProjectKey   string
+	WorkflowName string
+	Event        sdk.WorkflowNodeRunHookEvent
+}
+
+// LoadUpstreamHooks loads all the upstream workflow hooks listening to the given workflow
+func LoadUpstreamHooks(db gorp.SqlExecutor, projectKey, workflowName string) ([]UpstreamHook, error) {
+	query := `
+		SELECT workflow_node_hook.id, workflow_node_hook.uuid, workflow_node_hook.ref,
+			workflow_node_hook.workflow_hook_model_id, workflow_node_hook.workflow_node_id,
+			project.projectkey, workflow.name
+		FROM workflow_node_hook
+		JOIN workflow_hook_model ON workflow_hook_model.id = workflow_node_hook.workflow_hook_model_id
+		JOIN workflow_node ON workflow_node.id = workflow_node_hook.workflow_node_id
+		JOIN workflow ON workflow.id = workflow_node.workflow_id
+		JOIN project ON project.id = workflow.project_id
+		WHERE workflow_hook_model.name = $1
+		AND workflow_node_hook.config->'` + sdk.UpstreamWorkflowProject + `'->>'value' = $2
+		AND workflow_node_hook.config->'` + sdk.UpstreamWorkflowWorkflow + `'->>'value' = $3
+		AND workflow.to_delete = false`
+
+	rows, err := db.Query(query, sdk.UpstreamWorkflowModelName, projectKey, workflowName)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, sdk.WrapError(err, "unable to load upstream hooks of %s/%s", projectKey, workflowName)
+	}
+
+	hooks := []UpstreamHook{}
+	for rows.Next() {
+		var h UpstreamHook
+		var ref sql.NullString
+		if err := rows.Scan(&h.ID, &h.UUID, &ref, &h.WorkflowHookModelID, &h.WorkflowNodeID, &h.ProjectKey, &h.WorkflowName); err != nil {
+			rows.Close() // nolint
+			return nil, sdk.WithStack(err)
+		}
+		h.Ref = ref.String
+		hooks = append(hooks, h)
+	}
+	rows.Close() // nolint
+
+	for i := range hooks {
+		nh := NodeHook(hooks[i].WorkflowNodeHook)
+		if err := nh.PostGet(db); err != nil {
+			return nil, sdk.WrapError(err, "cannot load postget")
+		}
+		hooks[i].WorkflowNodeHook = sdk.WorkflowNodeHook(nh)
+	}
+
+	return hooks, nil
+}
+
+// checkUpstreamHook checks that at least one group of the project of the workflow can read the upstream project
+func checkUpstreamHook(db gorp.SqlExecutor, proj *sdk.Project, h *sdk.NodeHook) error {
+	upstreamKey := h.Config[sdk.UpstreamWorkflowProject].Value
+	if upstreamKey == "" || upstreamKey == proj.Key {
+		return nil
+	}
+
+	downstreamProj := sdk.Project{ID: proj.ID, Key: proj.Key}
+	if err := group.LoadGroupByProject(db, &downstreamProj); err != nil {
+		return sdk.WrapError(err, "cannot load groups of project %s", proj.Key)
+	}
+
+	return CheckUpstreamProjectReadable(db, proj.Key, upstreamKey, downstreamProj.ProjectGroups)
+}
+
+// CheckUpstreamProjectReadable checks that at least one of the given groups of the downstream project can read the upstream project
+func CheckUpstreamProjectReadable(db gorp.SqlExecutor, projectKey, upstreamKey string, groups []sdk.GroupPermission) error {
+	if upstreamKey == "" || upstreamKey == projectKey {
+		return nil
+	}
+
+	upstreamProj := sdk.Project{Key: upstreamKey}
+	if err := db.QueryRow("SELECT id FROM project WHERE projectkey = $1", upstreamKey).Scan(&upstreamProj.ID); err != nil {
+		if err == sql.ErrNoRows {
+			return sdk.WithStack(sdk.ErrNoProject)
+		}
+		return sdk.WithStack(err)
+	}
+	if err := group.LoadGroupByProject(db, &upstreamProj); err != nil {
+		return sdk.WrapError(err, "cannot load groups of project %s", upstreamKey)
+	}
+
+	if !upstreamProjectReadable(upstreamProj.ProjectGroups, groups) {
+		return sdk.NewError(sdk.ErrForbidden, fmt.Errorf("no group of project %s can read project %s", projectKey, upstreamKey))
+	}
+	return nil
+}
+
+// upstreamProjectReadable checks that at least one group of the downstream project can read the upstream project
+func upstreamProjectReadable(upstreamGroups, downstreamGroups []sdk.GroupPermission) bool {
+	for _, ug := range upstreamGroups {
+		if ug.Permission < permission.PermissionRead {
+			continue
+		}
+		for _, dg := range downstreamGroups {
+			if dg.Group.ID == ug.Group.ID {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// upstreamHookMatch checks if the node run has to trigger the upstream hook
+func upstreamHookMatch(h sdk.WorkflowNodeHook, nodeRun sdk.WorkflowNodeRun) bool {
+	if nodeRun.Status != sdk.StatusSuccess.String() || nodeRun.OutgoingHook != nil {
+		return false
+	}
+
+	node := h.Config[sdk.UpstreamWorkflowNode].Value
+	if node == "" || node != nodeRun.WorkflowNodeName {
+		return false
+	}
+
+	pattern := strings.TrimSpace(h.Config[sdk.UpstreamWorkflowBranch].Value)
+	if pattern == "" {
+		return true
+	}
+	branch := nodeRun.VCSBranch
+	if branch == "" {
+		if p := sdk.ParameterFind(&nodeRun.BuildParameters, tagGitBranch); p != nil {
+			branch = p.Value
+		}
+	}
+	return sdk.GlobMatch(pattern, branch)
+}
+
+// upstreamHookPayload forwards the payload of the upstream node run to the downstream workflow.
+// Git values are not forwarded as is since they are related to the upstream repository, they are prefixed by "parent."
+func upstreamHookPayload(nodeRun sdk.WorkflowNodeRun) map[string]string {
+	payload := map[string]string{}
+
+	if nodeRun.Payload != nil {
+		e := dump.NewDefaultEncoder(new(bytes.Buffer))
+		e.Formatters = []dump.KeyFormatterFunc{dump.WithDefaultLowerCaseFormatter()}
+		e.ExtraFields.DetailedMap = false
+		e.ExtraFields.DetailedStruct = false
+		e.ExtraFields.Len = false
+		e.ExtraFields.Type = false
+		m, err := e.ToStringMap(nodeRun.Payload)
+		if err != nil {
+			log.Error("upstreamHookPayload> unable to dump payload of node run %d: %v", nodeRun.ID, err)
+		}
+		for k, v := range m {
+			if strings.HasPrefix(k, "git.") {
+				continue
+			}
+			payload[k] = v
+		}
+	}
+
+	params := sdk.ParametersToMap(nodeRun.BuildParameters)
+	for _, k := range []string{tagGitRepository, tagGitBranch, tagGitHash, tagGitAuthor, tagGitMessage} {
+		if v, ok := params[k]; ok {
+			payload["parent."+k] = v
+		}
+	}
+	for _, k := range []string{"cds.triggered_by.username", "cds.triggered_by.fullname", "cds.triggered_by.email"} {
+		if v, ok := params[k]; ok {
+			payload[k] = v
+		}
+	}
+	payload["parent.node"] = nodeRun.WorkflowNodeName
+	return payload
+}
+
+// UpstreamHookEvents computes the events to send to the downstream workflows listening to the node runs of the report
+func UpstreamHookEvents(db gorp.SqlExecutor, report *ProcessorReport) ([]UpstreamHookEvent, error) {
+	if report == nil {
+		return nil, nil
+	}
+
+	runs := map[int64]sdk.WorkflowRun{}
+	for _, wr := range report.WorkflowRuns() {
+		runs[wr.ID] = wr
+	}
+
+	events := []UpstreamHookEvent{}
+	done := map[int64]struct{}{}
+	hooksCache := map[int64][]UpstreamHook{}
+	for _, nodeRun := range report.nodes {
+		if nodeRun.Status != sdk.StatusSuccess.String() {
+			continue
+		}
+		if _, has := done[nodeRun.ID]; has {
+			continue
+		}
+		done[nodeRun.ID] = struct{}{}
+
+		wr, has := runs[nodeRun.WorkflowRunID]
+		if !has {
+			continue
+		}
+
+		hooks, has := hooksCache[wr.WorkflowID]
+		if !has {
+			var err error
+			hooks, err = LoadUpstreamHooks(db, wr.Workflow.ProjectKey, wr.Workflow.Name)
+			if err != nil {
+				return nil, err
+			}
+			hooksCache[wr.WorkflowID] = hooks
+		}
+
+		for _, h := range hooks {
+			if !upstreamHookMatch(h.WorkflowNodeHook, nodeRun) {
+				continue
+			}
+			evt := sdk.WorkflowNodeRunHookEvent{
+				WorkflowNodeHookUUID: h.UUID,
+				Payload:              upstreamHookPayload(nodeRun),
+			}
+			evt.ParentWorkflow.Key = wr.Workflow.ProjectKey
+			evt.ParentWorkflow.Name = wr.Workflow.Name
+			evt.ParentWorkflow.Run = wr.Number
+			events = append(events, UpstreamHookEvent{
+				ProjectKey:   h.ProjectKey,
+				WorkflowName: h.WorkflowName,
+				Event:        evt,
+			})
+		}
+	}
+	return events, nil
+}
@@ -0,0 +1,66 @@
+package workflow
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func TestUpstreamHookMatch(t *testing.T) {
+	h := sdk.WorkflowNodeHook{
+		Config: sdk.WorkflowNodeHookConfig{
+			sdk.UpstreamWorkflowNode:   {Value: "deploy"},
+			sdk.UpstreamWorkflowBranch: {Value: "master,release/*"},
+		},
+	}
+
+	nodeRun := sdk.WorkflowNodeRun{
+		WorkflowNodeName: "deploy",
+		Status:           sdk.StatusSuccess.String(),
+		VCSBranch:        "master",
+	}
+	assert.True(t, upstreamHookMatch(h, nodeRun))
+
+	nodeRun.VCSBranch = "release/1.0"
+	assert.True(t, upstreamHookMatch(h, nodeRun))
+
+	nodeRun.VCSBranch = "feat/foo"
+	assert.False(t, upstreamHookMatch(h, nodeRun))
+
+	nodeRun.VCSBranch = ""
+	nodeRun.BuildParameters = []sdk.Parameter{{Name: "git.branch", Type: sdk.StringParameter, Value: "master"}}
+	assert.True(t, upstreamHookMatch(h, nodeRun))
+
+	nodeRun.Status = sdk.StatusFail.String()
+	assert.False(t, upstreamHookMatch(h, nodeRun))
+
+	nodeRun.Status = sdk.StatusSuccess.String()
+	nodeRun.WorkflowNodeName = "build"
+	assert.False(t, upstreamHookMatch(h, nodeRun))
+}
+
+func TestUpstreamHookPayload(t *testing.T) {
+	nodeRun := sdk.WorkflowNodeRun{
+		WorkflowNodeName: "deploy",
+		Payload: map[string]string{
+			"version":    "1.0.0",
+			"git.branch": "master",
+		},
+		BuildParameters: []sdk.Parameter{
+			{Name: "git.branch", Type: sdk.StringParameter, Value: "master"},
+			{Name: "git.hash", Type: sdk.StringParameter, Value: "abcdef"},
+			{Name: "cds.triggered_by.username", Type: sdk.StringParameter, Value: "john"},
+		},
+	}
+
+	payload := upstreamHookPayload(nodeRun)
+	assert.Equal(t, "1.0.0", payload["version"])
+	assert.Equal(t, "master", payload["parent.git.branch"])
+	assert.Equal(t, "abcdef", payload["parent.git.hash"])
+	assert.Equal(t, "deploy", payload["parent.node"])
+	assert.Equal(t, "john", payload["cds.triggered_by.username"])
+	_, has := payload["git.branch"]
+	assert.False(t, has)
+}
@@ -65,6 +65,64 @@ func SendEvent(db gorp.SqlExecutor, key string, report *ProcessorReport) {
 	}
 }
 
+// StoreEvents saves the events of the report in the outbox, it has to be called in the transaction updating the runs
+func StoreEvents(db gorp.SqlExecutor, key string, report *ProcessorReport) error {
+	if report == nil {
+		return nil
+	}
+
+	var es []sdk.Event
+	for _, wr := range report.workflows {
+		es = append(es, event.NewWorkflowRunEvent(wr, key))
+	}
+
+	runs := make(map[int64]*sdk.WorkflowRun)
+	loadRun := func(id int64) (*sdk.WorkflowRun, error) {
+		if wr, ok := runs[id]; ok {
+			return wr, nil
+		}
+		wr, err := LoadRunByID(db, id, LoadRunOptions{DisableDetailledNodeRun: true})
+		if err != nil {
+			return nil, sdk.WrapError(err, "cannot load workflow run %d", id)
+		}
+		runs[id] = wr
+		return wr, nil
+	}
+
+	for _, wnr := range report.nodes {
+		wr, err := loadRun(wnr.WorkflowRunID)
+		if err != nil {
+			return err
+		}
+		if e, ok := event.NewWorkflowNodeRunEvent(wnr, wr.Workflow); ok {
+			es = append(es, e)
+		}
+	}
+
+	for _, jobrun := range report.jobs {
+		noderun, err := LoadNodeRunByID(db, jobrun.WorkflowNodeRunID, LoadRunOptions{DisableDetailledNodeRun: true})
+		if err != nil {
+			return sdk.WrapError(err, "cannot load workflow node run %d", jobrun.WorkflowNodeRunID)
+		}
+		wr, err := loadRun(noderun.WorkflowRunID)
+		if err != nil {
+			return err
+		}
+		es = append(es, event.NewWorkflowNodeJobRunEvent(key, wr.Workflow.Name, jobrun))
+	}
+
+	return event.Store(db, es...)
+}
+
+// commitStatuses are the node runs of a workflow run sending their statuses on the same commit,
+// they share the client, the statuses and the pull requests of the commit
+type commitStatuses struct {
+	vcsServer *sdk.ProjectVCSServer
+	repo      string
+	ref       string
+	nodeRuns  []sdk.WorkflowNodeRun
+}
+
 // ResyncCommitStatus resync commit status for a workflow run
 func ResyncCommitStatus(ctx context.Context, db gorp.SqlExecutor, store cache.Store, proj *sdk.Project, wr *sdk.WorkflowRun) error {
 
@@ -74,6 +132,7 @@ func ResyncCommitStatus(ctx context.Context, db gorp.SqlExecutor, store cache.St
 	)
 	defer end()
 
+	var batches []*commitStatuses
 	for nodeID, nodeRuns := range wr.WorkflowNodeRuns {
 
 		sort.Slice(nodeRuns, func(i, j int) bool {
@@ -86,27 +145,15 @@ func ResyncCommitStatus(ctx context.Context, db gorp.SqlExecutor, store cache.St
 			continue
 		}
 
-		var vcsServerName string
-		var repoFullName string
-
 		node := wr.Workflow.WorkflowData.NodeByID(nodeID)
-		if !node.IsLinkedToRepo(&wr.Workflow) {
-			return nil
+		if node == nil || !node.IsLinkedToRepo(&wr.Workflow) {
+			continue
 		}
-		vcsServerName = wr.Workflow.Applications[node.Context.ApplicationID].VCSServer
-		repoFullName = wr.Workflow.Applications[node.Context.ApplicationID].RepositoryFullname
+		app := wr.Workflow.Applications[node.Context.ApplicationID]
 
-		vcsServer := repositoriesmanager.GetProjectVCSServer(proj, vcsServerName)
+		vcsServer := repositoriesmanager.GetProjectVCSServer(proj, app.VCSServer)
 		if vcsServer == nil {
-			return nil
-		}
-
-		details := fmt.Sprintf("on project:%s workflow:%s node:%s num:%d sub:%d vcs:%s", proj.Name, wr.Workflow.Name, nodeRun.WorkflowNodeName, nodeRun.Number, nodeRun.SubNumber, vcsServer.Name)
-
-		//Get the RepositoriesManager Client
-		client, errClient := repositoriesmanager.AuthorizedClient(ctx, db, store, vcsServer)
-		if errClient != nil {
-			return sdk.WrapError(errClient, "resyncCommitStatus> Cannot get client %s", details)
+			continue
 		}
 
 		ref := nodeRun.VCSHash
@@ -114,82 +161,113 @@ func ResyncCommitStatus(ctx context.Context, db gorp.SqlExecutor, store cache.St
 			ref = nodeRun.VCSTag
 		}
 
-		statuses, errStatuses := client.ListStatuses(ctx, repoFullName, ref)
-		if errStatuses != nil {
-			return sdk.WrapError(errStatuses, "resyncCommitStatus> Cannot get statuses %s", details)
+		var batch *commitStatuses
+		for _, b := range batches {
+			if b.vcsServer.Name == vcsServer.Name && b.repo == app.RepositoryFullname && b.ref == ref {
+				batch = b
+				break
+			}
+		}
+		if batch == nil {
+			batch = &commitStatuses{vcsServer: vcsServer, repo: app.RepositoryFullname, ref: ref}
+			batches = append(batches, batch)
 		}
+		batch.nodeRuns = append(batch.nodeRuns, nodeRun)
+	}
 
-		var statusFound *sdk.VCSCommitStatus
-		expected := sdk.VCSCommitStatusDescription(proj.Key, wr.Workflow.Name, sdk.EventRunWorkflowNode{
-			NodeName: nodeRun.WorkflowNodeName,
-		})
+	for _, b := range batches {
+		if err := resyncCommitStatuses(ctx, db, store, proj, wr, b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// resyncCommitStatuses sends the statuses of a commit which are not up to date, with a single call to list them
+func resyncCommitStatuses(ctx context.Context, db gorp.SqlExecutor, store cache.Store, proj *sdk.Project, wr *sdk.WorkflowRun, b *commitStatuses) error {
+	details := fmt.Sprintf("on project:%s workflow:%s num:%d vcs:%s repo:%s ref:%s", proj.Name, wr.Workflow.Name, wr.Number, b.vcsServer.Name, b.repo, b.ref)
+
+	//Get the RepositoriesManager Client
+	client, errClient := repositoriesmanager.AuthorizedClient(ctx, db, store, b.vcsServer)
+	if errClient != nil {
+		return sdk.WrapError(errClient, "resyncCommitStatus> Cannot get client %s", details)
+	}
+
+	statuses, errStatuses := client.ListStatuses(ctx, b.repo, b.ref)
+	if errStatuses != nil {
+		return sdk.WrapError(errStatuses, "resyncCommitStatus> Cannot get statuses %s", details)
+	}
+
+	nodeRuns := b.nodeRuns
+	// a single status is sent for the whole run
+	if wr.Workflow.CommitStatus.Aggregate {
+		sort.Slice(nodeRuns, func(i, j int) bool { return nodeRuns[i].ID < nodeRuns[j].ID })
+		nodeRuns = nodeRuns[:1]
+	}
+
+	// the failures are reported on the pull requests when their statuses are sent
+	var sent []sdk.WorkflowNodeRun
+	for i := range nodeRuns {
+		nodeRun := &nodeRuns[i]
+		evt := sdk.EventRunWorkflowNode{NodeName: nodeRun.WorkflowNodeName}
+		wr.Workflow.CommitStatus.Apply(proj.Key, wr.Workflow.Name, &evt)
+		expected := sdk.VCSCommitStatusDescription(proj.Key, wr.Workflow.Name, evt)
+
+		status := nodeRun.Status
+		if wr.Workflow.CommitStatus.Aggregate {
+			status = wr.Status
+		}
 
-		for i, status := range statuses {
-			if status.Decription == expected {
+		var statusFound *sdk.VCSCommitStatus
+		for i, s := range statuses {
+			if s.Decription == expected {
 				statusFound = &statuses[i]
 				break
 			}
 		}
-
-		if statusFound == nil || statusFound.State == "" {
-			if err := sendVCSEventStatus(ctx, db, store, proj, wr, &nodeRun); err != nil {
-				log.Error("resyncCommitStatus> Error sending status %s err: %v", details, err)
-			}
+		if statusFound != nil && commitStatusIsUpToDate(statusFound.State, status) {
 			continue
 		}
 
-		if statusFound.State == sdk.StatusBuilding.String() {
-			if err := sendVCSEventStatus(ctx, db, store, proj, wr, &nodeRun); err != nil {
-				log.Error("resyncCommitStatus> Error sending status %s err: %v", details, err)
-			}
+		if err := sendVCSEventStatus(ctx, client, store, proj, wr, nodeRun); err != nil {
+			log.Error("resyncCommitStatus> Error sending status %s node:%s err:%v", details, nodeRun.WorkflowNodeName, err)
 			continue
 		}
+		sent = append(sent, *nodeRun)
+	}
+	if wr.Workflow.CommitStatus.Aggregate && len(sent) > 0 {
+		sent = b.nodeRuns
+	}
 
-		switch statusFound.State {
-		case sdk.StatusBuilding.String():
-			if err := sendVCSEventStatus(ctx, db, store, proj, wr, &nodeRun); err != nil {
-				log.Error("resyncCommitStatus> Error sending status %s %s err:%v", statusFound.State, details, err)
-			}
-			continue
-
-		case sdk.StatusSuccess.String():
-			switch nodeRun.Status {
-			case sdk.StatusSuccess.String():
-				continue
-			default:
-				if err := sendVCSEventStatus(ctx, db, store, proj, wr, &nodeRun); err != nil {
-					log.Error("resyncCommitStatus> Error sending status %s %s err:%v", statusFound.State, details, err)
-				}
-				continue
-			}
-		case sdk.StatusFail.String():
-			switch nodeRun.Status {
-			case sdk.StatusFail.String():
-				continue
-			default:
-				if err := sendVCSEventStatus(ctx, db, store, proj, wr, &nodeRun); err != nil {
-					log.Error("resyncCommitStatus> Error sending status %s %s err:%v", statusFound.State, details, err)
-				}
-				continue
-			}
-
-		case sdk.StatusSkipped.String():
-			switch nodeRun.Status {
-			case sdk.StatusDisabled.String(), sdk.StatusNeverBuilt.String(), sdk.StatusSkipped.String():
-				continue
-			default:
-				if err := sendVCSEventStatus(ctx, db, store, proj, wr, &nodeRun); err != nil {
-					log.Error("resyncCommitStatus> Error sending status %s %s err:%v", statusFound.State, details, err)
-				}
-				continue
-			}
+	var failedNodeRuns []sdk.WorkflowNodeRun
+	for _, nodeRun := range sent {
+		if nodeRun.Status == sdk.StatusFail.String() || nodeRun.Status == sdk.StatusStopped.String() {
+			failedNodeRuns = append(failedNodeRuns, nodeRun)
 		}
 	}
+	if len(failedNodeRuns) > 0 {
+		sendPullRequestReports(ctx, client, b.repo, failedNodeRuns)
+	}
 	return nil
 }
 
+// commitStatusIsUpToDate returns true if the state of a commit status on the repositories manager matches the status of a node run
+func commitStatusIsUpToDate(state, status string) bool {
+	switch state {
+	case "", sdk.StatusBuilding.String():
+		return false
+	case sdk.StatusSuccess.String():
+		return status == sdk.StatusSuccess.String()
+	case sdk.StatusFail.String():
+		return status == sdk.StatusFail.String()
+	case sdk.StatusSkipped.String():
+		return status == sdk.StatusDisabled.String() || status == sdk.StatusNeverBuilt.String() || status == sdk.StatusSkipped.String()
+	}
+	return true
+}
+
 // sendVCSEventStatus send status
-func sendVCSEventStatus(ctx context.Context, db gorp.SqlExecutor, store cache.Store, proj *sdk.Project, wr *sdk.WorkflowRun, nodeRun *sdk.WorkflowNodeRun) error {
+func sendVCSEventStatus(ctx context.Context, client sdk.VCSAuthorizedClient, store cache.Store, proj *sdk.Project, wr *sdk.WorkflowRun, nodeRun *sdk.WorkflowNodeRun) error {
 	log.Debug("Send status for node run %d", nodeRun.ID)
 
 	var app sdk.Application
@@ -207,16 +285,6 @@ func sendVCSEventStatus(ctx context.Context, db gorp.SqlExecutor, store cache.St
 		env = wr.Workflow.Environments[node.Context.EnvironmentID]
 	}
 
-	vcsServer := repositoriesmanager.GetProjectVCSServer(proj, app.VCSServer)
-	if vcsServer == nil {
-		return nil
-	}
-	//Get the RepositoriesManager Client
-	client, errClient := repositoriesmanager.AuthorizedClient(ctx, db, store, vcsServer)
-	if errClient != nil {
-		return sdk.WrapError(errClient, "sendVCSEventStatus> Cannot get client")
-	}
-
 	var eventWNR = sdk.EventRunWorkflowNode{
 		ID:             nodeRun.ID,
 		Number:         nodeRun.Number,
@@ -241,6 +309,11 @@ func sendVCSEventStatus(ctx context.Context, db gorp.SqlExecutor, store cache.St
 		eventWNR.StagesSummary[i] = nodeRun.Stages[i].ToSummary()
 	}
 
+	wr.Workflow.CommitStatus.Apply(proj.Key, wr.Workflow.Name, &eventWNR)
+	if eventWNR.StatusAggregate {
+		eventWNR.Status = wr.Status
+	}
+
 	var pipName, appName, envName string
 
 	pipName = pip.Name
@@ -267,30 +340,33 @@ func sendVCSEventStatus(ctx context.Context, db gorp.SqlExecutor, store cache.St
 		return fmt.Errorf("sendEvent> err:%s", err)
 	}
 
+	return nil
+}
+
+// sendPullRequestReports comments the pull requests of the commit with the reports of the failed node runs
+func sendPullRequestReports(ctx context.Context, client sdk.VCSAuthorizedClient, repo string, nodeRuns []sdk.WorkflowNodeRun) {
 	//Check if this branch and this commit is a pullrequest
-	prs, err := client.PullRequests(ctx, app.RepositoryFullname)
+	prs, err := client.PullRequests(ctx, repo)
 	if err != nil {
-		log.Error("sendVCSEventStatus> unable to get pull requests on repo %s: %v", app.RepositoryFullname, err)
-		return nil
+		log.Error("sendPullRequestReports> unable to get pull requests on repo %s: %v", repo, err)
+		return
 	}
 
 	//Send comment on pull request
-	for _, pr := range prs {
-		if pr.Head.Branch.DisplayID == nodeRun.VCSBranch && pr.Head.Branch.LatestCommit == nodeRun.VCSHash {
-			if nodeRun.Status != sdk.StatusFail.String() && nodeRun.Status != sdk.StatusStopped.String() {
+	for _, nodeRun := range nodeRuns {
+		for _, pr := range prs {
+			if pr.Head.Branch.DisplayID != nodeRun.VCSBranch || pr.Head.Branch.LatestCommit != nodeRun.VCSHash {
 				continue
 			}
 			report, err := nodeRun.Report()
 			if err != nil {
-				log.Error("sendVCSEventStatus> unable to compute node run report%v", err)
-				return nil
+				log.Error("sendPullRequestReports> unable to compute node run report%v", err)
+				return
 			}
-			if err := client.PullRequestComment(ctx, app.RepositoryFullname, pr.ID, report); err != nil {
-				log.Error("sendVCSEventStatus> unable to send PR report%v", err)
-				return nil
+			if err := client.PullRequestComment(ctx, repo, pr.ID, report); err != nil {
+				log.Error("sendPullRequestReports> unable to send PR report%v", err)
+				return
 			}
 		}
 	}
-
-	return nil
 }
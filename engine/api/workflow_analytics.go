@@ -0,0 +1,88 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/ovh/cds/engine/api/workflow"
+	"github.com/ovh/cds/engine/service"
+	"github.com/ovh/cds/sdk"
+)
+
+// analyticsSince returns the start date of the analytics from the number of days given in the request, 30 days by default
+func analyticsSince(r *http.Request) (time.Time, error) {
+	days, err := FormInt(r, "days")
+	if err != nil {
+		return time.Time{}, err
+	}
+	if days <= 0 || days > 365 {
+		days = 30
+	}
+	return time.Now().AddDate(0, 0, -days), nil
+}
+
+func (api *API) getWorkflowDurationsAnalyticsHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		key := vars["key"]
+		name := vars["permWorkflowName"]
+
+		since, err := analyticsSince(r)
+		if err != nil {
+			return err
+		}
+		kind := FormString(r, "kind")
+		if kind == "" {
+			kind = sdk.DurationKindPipeline
+		}
+		interval := FormString(r, "interval")
+		if interval == "" {
+			interval = sdk.DurationIntervalDay
+		}
+
+		wf, err := api.loadWorkflowForTestCases(ctx, key, name)
+		if err != nil {
+			return err
+		}
+
+		stats, err := workflow.LoadDurationStats(api.mustDB(), wf.ID, kind, FormString(r, "pipeline"), interval, since)
+		if err != nil {
+			return err
+		}
+		return service.WriteJSON(w, stats, http.StatusOK)
+	}
+}
+
+func (api *API) getWorkflowSlowestStepsAnalyticsHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		key := vars["key"]
+		name := vars["permWorkflowName"]
+
+		since, err := analyticsSince(r)
+		if err != nil {
+			return err
+		}
+		limit, err := FormInt(r, "limit")
+		if err != nil {
+			return err
+		}
+		if limit <= 0 || limit > 100 {
+			limit = 20
+		}
+
+		wf, err := api.loadWorkflowForTestCases(ctx, key, name)
+		if err != nil {
+			return err
+		}
+
+		steps, err := workflow.LoadSlowestSteps(api.mustDB(), wf.ID, FormString(r, "pipeline"), since, limit)
+		if err != nil {
+			return err
+		}
+		return service.WriteJSON(w, steps, http.StatusOK)
+	}
+}
@@ -0,0 +1,171 @@
+package api
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/gorilla/mux"
+
+	"github.com/ovh/cds/engine/api/badge"
+	"github.com/ovh/cds/engine/api/token"
+	"github.com/ovh/cds/engine/api/workflow"
+	"github.com/ovh/cds/engine/service"
+	"github.com/ovh/cds/sdk"
+)
+
+func (api *API) getWorkflowBadgeHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		key := vars["key"]
+		name := vars["permWorkflowName"]
+
+		b, err := workflow.LoadBadge(api.mustDB(), key, name)
+		if err != nil {
+			return err
+		}
+		b.URLs = api.badgeURLs(key, name, b.Token)
+		return service.WriteJSON(w, b, http.StatusOK)
+	}
+}
+
+func (api *API) postWorkflowBadgeHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		key := vars["key"]
+		name := vars["permWorkflowName"]
+
+		var req struct {
+			Protected bool `json:"protected"`
+		}
+		if err := service.UnmarshalBody(r, &req); err != nil {
+			return sdk.WrapError(err, "cannot unmarshal request")
+		}
+
+		wf, err := api.loadWorkflowForTestCases(ctx, key, name)
+		if err != nil {
+			return err
+		}
+
+		b := sdk.WorkflowBadge{WorkflowID: wf.ID}
+		if req.Protected {
+			b.Token, err = token.GenerateToken()
+			if err != nil {
+				return sdk.WrapError(err, "cannot generate badge token")
+			}
+		}
+		if err := workflow.InsertBadge(api.mustDB(), &b); err != nil {
+			return err
+		}
+		b.URLs = api.badgeURLs(key, name, b.Token)
+		return service.WriteJSON(w, b, http.StatusOK)
+	}
+}
+
+func (api *API) deleteWorkflowBadgeHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		key := vars["key"]
+		name := vars["permWorkflowName"]
+
+		b, err := workflow.LoadBadge(api.mustDB(), key, name)
+		if err != nil {
+			return err
+		}
+		if err := workflow.DeleteBadge(api.mustDB(), b.WorkflowID); err != nil {
+			return err
+		}
+		return service.WriteJSON(w, nil, http.StatusOK)
+	}
+}
+
+// getBadgeHandler returns a badge of a workflow as an SVG image. It does not need authentication,
+// only the token of the badges if they are protected.
+func (api *API) getBadgeHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		key := vars["key"]
+		name := vars["workflowName"]
+		badgeType := vars["type"]
+
+		if !sdk.IsInArray(badgeType, sdk.BadgeTypes) {
+			return sdk.NewErrorFrom(sdk.ErrNotFound, "unknown badge %s", badgeType)
+		}
+
+		b, err := workflow.LoadBadge(api.mustDB(), key, name)
+		if err != nil {
+			return err
+		}
+		if b.Token != "" && subtle.ConstantTimeCompare([]byte(FormString(r, "token")), []byte(b.Token)) != 1 {
+			return sdk.WithStack(sdk.ErrForbidden)
+		}
+
+		branch := FormString(r, "branch")
+		var label, message, color string
+		switch badgeType {
+		case sdk.BadgeTypeStatus:
+			status, err := workflow.LoadLastRunStatus(api.mustDB(), b.WorkflowID, branch)
+			if err != nil {
+				return err
+			}
+			label, message, color = "build", strings.ToLower(status), badgeStatusColor(status)
+			if status == "" {
+				message = "unknown"
+			}
+		case sdk.BadgeTypeCoverage:
+			trend, err := workflow.LoadCoverageTrend(api.mustDB(), key, name, FormString(r, "application"), branch, 1)
+			if err != nil {
+				return err
+			}
+			label, message, color = "coverage", "unknown", badge.ColorLightGrey
+			if len(trend) > 0 {
+				message, color = fmt.Sprintf("%.1f%%", trend[0].LinesPercent), badge.CoverageColor(trend[0].LinesPercent)
+			}
+		case sdk.BadgeTypeVersion:
+			version, err := workflow.LoadLastReleasedVersion(api.mustDB(), b.WorkflowID)
+			if err != nil {
+				return err
+			}
+			label, message, color = "version", version, badge.ColorBlue
+			if version == "" {
+				message, color = "none", badge.ColorLightGrey
+			}
+		}
+
+		svg, err := badge.Render(label, message, color)
+		if err != nil {
+			return sdk.WithStack(err)
+		}
+		// Badges are embedded in pages cached by proxies, they should always be fetched again
+		w.Header().Set("Cache-Control", "no-cache, no-store, must-revalidate")
+		return service.Write(w, svg, http.StatusOK, "image/svg+xml")
+	}
+}
+
+func badgeStatusColor(status string) string {
+	switch status {
+	case sdk.StatusSuccess.String():
+		return badge.ColorGreen
+	case sdk.StatusFail.String():
+		return badge.ColorRed
+	case sdk.StatusBuilding.String(), sdk.StatusWaiting.String(), sdk.StatusChecking.String():
+		return badge.ColorBlue
+	}
+	return badge.ColorLightGrey
+}
+
+// badgeURLs returns the urls of the badges of a workflow
+func (api *API) badgeURLs(key, name, token string) map[string]string {
+	urls := make(map[string]string, len(sdk.BadgeTypes))
+	for _, t := range sdk.BadgeTypes {
+		u := fmt.Sprintf("%s/badge/%s/%s/%s.svg", api.Config.URL.API, url.PathEscape(key), url.PathEscape(name), t)
+		if token != "" {
+			u += "?token=" + url.QueryEscape(token)
+		}
+		urls[t] = u
+	}
+	return urls
+}
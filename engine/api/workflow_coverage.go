@@ -0,0 +1,71 @@
+package api
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/ovh/cds/engine/api/workflow"
+	"github.com/ovh/cds/engine/service"
+	"github.com/ovh/cds/sdk"
+)
+
+func (api *API) getWorkflowCoverageTrendHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		key := vars["key"]
+		name := vars["permWorkflowName"]
+
+		limit, err := FormInt(r, "limit")
+		if err != nil {
+			return err
+		}
+		if limit <= 0 || limit > 200 {
+			limit = 50
+		}
+
+		trend, err := workflow.LoadCoverageTrend(api.mustDB(), key, name, FormString(r, "application"), FormString(r, "branch"), limit)
+		if err != nil {
+			return err
+		}
+		return service.WriteJSON(w, trend, http.StatusOK)
+	}
+}
+
+func (api *API) getWorkflowRunCoverageHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		key := vars["key"]
+		name := vars["permWorkflowName"]
+
+		number, err := requestVarInt(r, "number")
+		if err != nil {
+			return err
+		}
+
+		wr, err := workflow.LoadRun(api.mustDB(), key, name, number, workflow.LoadRunOptions{DisableDetailledNodeRun: true})
+		if err != nil {
+			return sdk.WrapError(err, "cannot load workflow run")
+		}
+
+		covs, err := workflow.LoadCoverageReportsByRunID(api.mustDB(), wr.ID)
+		if err != nil {
+			return err
+		}
+
+		nodeNames := map[int64]string{}
+		for _, nrs := range wr.WorkflowNodeRuns {
+			for _, nr := range nrs {
+				nodeNames[nr.ID] = nr.WorkflowNodeName
+			}
+		}
+
+		diffs := make([]sdk.WorkflowNodeRunCoverageDiff, len(covs))
+		for i := range covs {
+			diffs[i] = sdk.NewWorkflowNodeRunCoverageDiff(covs[i])
+			diffs[i].WorkflowNodeName = nodeNames[covs[i].WorkflowNodeRunID]
+		}
+		return service.WriteJSON(w, diffs, http.StatusOK)
+	}
+}
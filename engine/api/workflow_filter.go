@@ -0,0 +1,227 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/ovh/cds/engine/api/project"
+	"github.com/ovh/cds/engine/api/workflow"
+	"github.com/ovh/cds/engine/service"
+	"github.com/ovh/cds/sdk"
+)
+
+// checkWorkflowSavedFilterProject returns the project of the saved filters of the user, the filters are personal so
+// a read permission on the project is enough to manage them
+func (api *API) checkWorkflowSavedFilterProject(ctx context.Context, key string) (*sdk.Project, error) {
+	u := deprecatedGetUser(ctx)
+	if u == nil {
+		return nil, sdk.WithStack(sdk.ErrForbidden)
+	}
+	if !u.Admin && !checkProjectReadPermission(ctx, key) {
+		return nil, sdk.WithStack(sdk.ErrForbidden)
+	}
+	return project.Load(api.mustDB(), api.Cache, key, u)
+}
+
+func (api *API) loadWorkflowSavedFilter(ctx context.Context, key, name string) (*sdk.WorkflowSavedFilter, error) {
+	proj, err := api.checkWorkflowSavedFilterProject(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	return workflow.LoadSavedFilter(api.mustDB(), deprecatedGetUser(ctx).ID, proj.ID, name)
+}
+
+// getWorkflowSavedFiltersHandler returns the filters saved by the user on the workflows of a project
+func (api *API) getWorkflowSavedFiltersHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		key := mux.Vars(r)["key"]
+
+		proj, err := api.checkWorkflowSavedFilterProject(ctx, key)
+		if err != nil {
+			return err
+		}
+
+		fs, err := workflow.LoadSavedFilters(api.mustDB(), deprecatedGetUser(ctx).ID, proj.ID)
+		if err != nil {
+			return err
+		}
+		return service.WriteJSON(w, fs, http.StatusOK)
+	}
+}
+
+// postWorkflowSavedFilterHandler saves a filter on the workflows of a project for the user
+func (api *API) postWorkflowSavedFilterHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		key := mux.Vars(r)["key"]
+
+		proj, err := api.checkWorkflowSavedFilterProject(ctx, key)
+		if err != nil {
+			return err
+		}
+
+		var f sdk.WorkflowSavedFilter
+		if err := service.UnmarshalBody(r, &f); err != nil {
+			return sdk.WrapError(err, "cannot read body")
+		}
+		if err := f.IsValid(); err != nil {
+			return err
+		}
+		f.ID = 0
+		f.UserID = deprecatedGetUser(ctx).ID
+		f.ProjectID = proj.ID
+
+		if err := workflow.InsertSavedFilter(api.mustDB(), &f); err != nil {
+			return err
+		}
+		return service.WriteJSON(w, f, http.StatusCreated)
+	}
+}
+
+// putWorkflowSavedFilterHandler updates a filter saved by the user
+func (api *API) putWorkflowSavedFilterHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+
+		old, err := api.loadWorkflowSavedFilter(ctx, vars["key"], vars["name"])
+		if err != nil {
+			return err
+		}
+
+		var f sdk.WorkflowSavedFilter
+		if err := service.UnmarshalBody(r, &f); err != nil {
+			return sdk.WrapError(err, "cannot read body")
+		}
+		if f.Name == "" {
+			f.Name = old.Name
+		}
+		if err := f.IsValid(); err != nil {
+			return err
+		}
+		f.ID = old.ID
+		f.UserID = old.UserID
+		f.ProjectID = old.ProjectID
+
+		if err := workflow.UpdateSavedFilter(api.mustDB(), &f); err != nil {
+			return err
+		}
+		return service.WriteJSON(w, f, http.StatusOK)
+	}
+}
+
+// deleteWorkflowSavedFilterHandler deletes a filter saved by the user
+func (api *API) deleteWorkflowSavedFilterHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+
+		f, err := api.loadWorkflowSavedFilter(ctx, vars["key"], vars["name"])
+		if err != nil {
+			return err
+		}
+
+		if err := workflow.DeleteSavedFilter(api.mustDB(), f); err != nil {
+			return err
+		}
+		return service.WriteJSON(w, nil, http.StatusOK)
+	}
+}
+
+// postWorkflowLabelsHandler adds and removes labels on several workflows of a project at once
+func (api *API) postWorkflowLabelsHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		key := mux.Vars(r)[permProjectKey]
+		u := deprecatedGetUser(ctx)
+
+		var req sdk.WorkflowLabelsRequest
+		if err := service.UnmarshalBody(r, &req); err != nil {
+			return sdk.WrapError(err, "cannot read body")
+		}
+		if len(req.Workflows) == 0 || len(req.Add)+len(req.Remove) == 0 {
+			return sdk.NewErrorFrom(sdk.ErrWrongRequest, "workflows and labels to add or to remove should not be empty")
+		}
+
+		db := api.mustDB()
+		proj, err := project.Load(db, api.Cache, key, u)
+		if err != nil {
+			return sdk.WrapError(err, "cannot load project %s", key)
+		}
+
+		names, err := workflow.LoadAllNames(db, proj.ID, u)
+		if err != nil {
+			return err
+		}
+		ids := make(map[string]int64, len(names))
+		for _, n := range names {
+			ids[n.Name] = n.ID
+		}
+		for _, name := range req.Workflows {
+			if _, ok := ids[name]; !ok {
+				return sdk.NewErrorFrom(sdk.ErrWorkflowNotFound, "workflow %s not found", name)
+			}
+		}
+
+		tx, err := db.Begin()
+		if err != nil {
+			return sdk.WrapError(err, "cannot start transaction")
+		}
+		defer tx.Rollback() // nolint
+
+		for _, labelName := range req.Add {
+			label, err := project.LabelByName(tx, proj.ID, labelName)
+			if err != nil {
+				if sdk.Cause(err) != sql.ErrNoRows {
+					return sdk.WrapError(err, "cannot load label %s", labelName)
+				}
+				label = sdk.Label{Name: labelName, ProjectID: proj.ID}
+				if err := project.InsertLabel(tx, &label); err != nil {
+					return sdk.WrapError(err, "cannot create label %s", labelName)
+				}
+			}
+			for _, name := range req.Workflows {
+				labeled, err := workflow.IsLabeled(tx, label.ID, ids[name])
+				if err != nil {
+					return err
+				}
+				if labeled {
+					continue
+				}
+				if err := workflow.LabelWorkflow(tx, label.ID, ids[name]); err != nil {
+					return err
+				}
+			}
+		}
+
+		for _, labelName := range req.Remove {
+			label, err := project.LabelByName(tx, proj.ID, labelName)
+			if err != nil {
+				if sdk.Cause(err) == sql.ErrNoRows {
+					continue
+				}
+				return sdk.WrapError(err, "cannot load label %s", labelName)
+			}
+			for _, name := range req.Workflows {
+				if err := workflow.UnLabelWorkflow(tx, label.ID, ids[name]); err != nil {
+					return err
+				}
+			}
+		}
+
+		if err := tx.Commit(); err != nil {
+			return sdk.WrapError(err, "cannot commit transaction")
+		}
+
+		names, err = workflow.LoadAllNames(db, proj.ID, u)
+		if err != nil {
+			return err
+		}
+		updated := make([]sdk.IDName, 0, len(req.Workflows))
+		for _, n := range names {
+			if sdk.IsInArray(n.Name, req.Workflows) {
+				updated = append(updated, n)
+			}
+		}
+		return service.WriteJSON(w, updated, http.StatusOK)
+	}
+}
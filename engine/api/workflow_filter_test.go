@@ -0,0 +1,101 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/engine/api/pipeline"
+	"github.com/ovh/cds/engine/api/test"
+	"github.com/ovh/cds/engine/api/test/assets"
+	"github.com/ovh/cds/engine/api/workflow"
+	"github.com/ovh/cds/sdk"
+)
+
+func Test_filterWorkflowsByLabels(t *testing.T) {
+	api, db, router, end := newTestAPI(t)
+	defer end()
+
+	u, pass := assets.InsertAdminUser(api.mustDB())
+	key := sdk.RandomString(10)
+	proj := assets.InsertTestProject(t, db, api.Cache, key, key, u)
+	pip := sdk.Pipeline{
+		Name:      "pipeline1",
+		ProjectID: proj.ID,
+	}
+	test.NoError(t, pipeline.InsertPipeline(api.mustDB(), api.Cache, proj, &pip, nil))
+	for _, name := range []string{"wf1", "wf2"} {
+		wf := sdk.Workflow{
+			Name:       name,
+			ProjectID:  proj.ID,
+			ProjectKey: proj.Key,
+			WorkflowData: &sdk.WorkflowData{
+				Node: sdk.Node{
+					Type: sdk.NodeTypePipeline,
+					Context: &sdk.NodeContext{
+						PipelineID: pip.ID,
+					},
+				},
+			},
+		}
+		test.NoError(t, workflow.Insert(db, api.Cache, &wf, proj, u))
+	}
+
+	// Label both workflows, then remove the label from the second one
+	uri := router.GetRoute("POST", api.postWorkflowLabelsHandler, map[string]string{"permProjectKey": proj.Key})
+	test.NotEmpty(t, uri)
+	w := httptest.NewRecorder()
+	router.Mux.ServeHTTP(w, assets.NewAuthentifiedRequest(t, u, pass, "POST", uri, sdk.WorkflowLabelsRequest{
+		Workflows: []string{"wf1", "wf2"},
+		Add:       []string{"team-a", "backend"},
+	}))
+	assert.Equal(t, 200, w.Code)
+	var names []sdk.IDName
+	test.NoError(t, json.Unmarshal(w.Body.Bytes(), &names))
+	assert.Len(t, names, 2)
+	for _, n := range names {
+		assert.Len(t, n.Labels, 2)
+	}
+
+	w = httptest.NewRecorder()
+	router.Mux.ServeHTTP(w, assets.NewAuthentifiedRequest(t, u, pass, "POST", uri, sdk.WorkflowLabelsRequest{
+		Workflows: []string{"wf2"},
+		Remove:    []string{"team-a"},
+	}))
+	assert.Equal(t, 200, w.Code)
+
+	// Only the first workflow has both labels
+	uri = router.GetRoute("GET", api.getWorkflowsHandler, map[string]string{"permProjectKey": proj.Key})
+	test.NotEmpty(t, uri)
+	w = httptest.NewRecorder()
+	router.Mux.ServeHTTP(w, assets.NewAuthentifiedRequest(t, u, pass, "GET", uri+"?label=team-a&label=backend", nil))
+	assert.Equal(t, 200, w.Code)
+	var wfs []sdk.Workflow
+	test.NoError(t, json.Unmarshal(w.Body.Bytes(), &wfs))
+	if assert.Len(t, wfs, 1) {
+		assert.Equal(t, "wf1", wfs[0].Name)
+	}
+
+	w = httptest.NewRecorder()
+	router.Mux.ServeHTTP(w, assets.NewAuthentifiedRequest(t, u, pass, "GET", uri+"?status=Unknown", nil))
+	assert.Equal(t, 400, w.Code)
+
+	// Save the filter and use it
+	uri = router.GetRoute("POST", api.postWorkflowSavedFilterHandler, map[string]string{"key": proj.Key})
+	test.NotEmpty(t, uri)
+	w = httptest.NewRecorder()
+	router.Mux.ServeHTTP(w, assets.NewAuthentifiedRequest(t, u, pass, "POST", uri, sdk.WorkflowSavedFilter{
+		Name:   "backend",
+		Filter: sdk.WorkflowFilter{Labels: []string{"backend"}},
+	}))
+	assert.Equal(t, 201, w.Code)
+
+	uri = router.GetRoute("GET", api.getWorkflowsHandler, map[string]string{"permProjectKey": proj.Key})
+	w = httptest.NewRecorder()
+	router.Mux.ServeHTTP(w, assets.NewAuthentifiedRequest(t, u, pass, "GET", uri+"?filter=backend", nil))
+	assert.Equal(t, 200, w.Code)
+	test.NoError(t, json.Unmarshal(w.Body.Bytes(), &wfs))
+	assert.Len(t, wfs, 2)
+}
@@ -0,0 +1,108 @@
+package api
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/ovh/cds/engine/api/project"
+	"github.com/ovh/cds/engine/api/workflow"
+	"github.com/ovh/cds/engine/service"
+	"github.com/ovh/cds/sdk"
+)
+
+func (api *API) getWorkflowForkApprovalsHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		key := vars["key"]
+		name := vars["permWorkflowName"]
+
+		proj, err := project.Load(api.mustDB(), api.Cache, key, deprecatedGetUser(ctx))
+		if err != nil {
+			return sdk.WrapError(err, "unable to load project %s", key)
+		}
+		wf, err := workflow.Load(ctx, api.mustDB(), api.Cache, proj, name, deprecatedGetUser(ctx), workflow.LoadOptions{WithoutNode: true})
+		if err != nil {
+			return sdk.WrapError(err, "unable to load workflow %s/%s", key, name)
+		}
+
+		as, err := workflow.LoadForkApprovals(api.mustDB(), wf.ID)
+		if err != nil {
+			return err
+		}
+		return service.WriteJSON(w, as, http.StatusOK)
+	}
+}
+
+// postWorkflowForkApprovalHandler approves the runs of a branch of a fork, and starts the run waiting for the approval
+func (api *API) postWorkflowForkApprovalHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		key := vars["key"]
+		name := vars["permWorkflowName"]
+		id, err := requestVarInt(r, "id")
+		if err != nil {
+			return err
+		}
+
+		proj, err := project.Load(api.mustDB(), api.Cache, key, deprecatedGetUser(ctx))
+		if err != nil {
+			return sdk.WrapError(err, "unable to load project %s", key)
+		}
+		wf, err := workflow.Load(ctx, api.mustDB(), api.Cache, proj, name, deprecatedGetUser(ctx), workflow.LoadOptions{WithoutNode: true})
+		if err != nil {
+			return sdk.WrapError(err, "unable to load workflow %s/%s", key, name)
+		}
+
+		a, err := workflow.LoadForkApprovalByID(api.mustDB(), wf.ID, id)
+		if err != nil {
+			return err
+		}
+		if a.Approved {
+			return sdk.NewErrorFrom(sdk.ErrConflict, "the runs of %s are already approved", a)
+		}
+
+		if err := workflow.ApproveFork(api.mustDB(), a, deprecatedGetUser(ctx)); err != nil {
+			return err
+		}
+
+		hook := a.HookEvent
+		wr, err := api.postWorkflowRun(ctx, r, key, name, deprecatedGetUser(ctx), &sdk.WorkflowRunPostHandlerOption{Hook: &hook})
+		if err != nil {
+			return err
+		}
+		return service.WriteJSON(w, wr, http.StatusAccepted)
+	}
+}
+
+// deleteWorkflowForkApprovalHandler revokes the approval of a branch of a fork, its next run waits for a new approval
+func (api *API) deleteWorkflowForkApprovalHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		key := vars["key"]
+		name := vars["permWorkflowName"]
+		id, err := requestVarInt(r, "id")
+		if err != nil {
+			return err
+		}
+
+		proj, err := project.Load(api.mustDB(), api.Cache, key, deprecatedGetUser(ctx))
+		if err != nil {
+			return sdk.WrapError(err, "unable to load project %s", key)
+		}
+		wf, err := workflow.Load(ctx, api.mustDB(), api.Cache, proj, name, deprecatedGetUser(ctx), workflow.LoadOptions{WithoutNode: true})
+		if err != nil {
+			return sdk.WrapError(err, "unable to load workflow %s/%s", key, name)
+		}
+
+		a, err := workflow.LoadForkApprovalByID(api.mustDB(), wf.ID, id)
+		if err != nil {
+			return err
+		}
+		if err := workflow.DeleteForkApproval(api.mustDB(), a); err != nil {
+			return err
+		}
+		return nil
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"strings"
 
+	"github.com/go-gorp/gorp"
 	"github.com/gorilla/mux"
 
 	"github.com/ovh/cds/engine/api/observability"
@@ -208,6 +209,22 @@ func (api *API) postWorkflowJobHookCallbackHandler() service.Handler {
 			return sdk.WrapError(err, "Unable to unmarshal body")
 		}
 
+		// The secrets are loaded before the transaction, the secret manager integrations are called over the network
+		run, err := workflow.LoadRun(api.mustDB(), key, workflowName, number, workflow.LoadRunOptions{
+			DisableDetailledNodeRun: true,
+		})
+		if err != nil {
+			return sdk.WrapError(err, "postWorkflowJobHookCallbackHandler> Cannot load workflow run")
+		}
+		hr := run.GetOutgoingHookRun(hookRunID)
+		if hr == nil {
+			return sdk.WithStack(sdk.ErrNotFound)
+		}
+		secrets, err := api.loadOutgoingHookSecrets(api.mustDB(), run, hr)
+		if err != nil {
+			return sdk.WrapError(err, "postWorkflowJobHookCallbackHandler> Cannot load secrets")
+		}
+
 		tx, err := api.mustDB().Begin()
 		if err != nil {
 			return err
@@ -234,16 +251,6 @@ func (api *API) postWorkflowJobHookCallbackHandler() service.Handler {
 			return sdk.WrapError(err, "postWorkflowJobHookCallbackHandler> Cannot load workflow run")
 		}
 
-		pv, err := project.GetAllVariableInProject(tx, wr.Workflow.ProjectID, project.WithClearPassword())
-		if err != nil {
-			return sdk.WrapError(err, "Cannot load project variable")
-		}
-
-		secrets, errSecret := workflow.LoadSecrets(tx, api.Cache, nil, wr, pv)
-		if errSecret != nil {
-			return sdk.WrapError(errSecret, "postWorkflowJobHookCallbackHandler> Cannot load secrets")
-		}
-
 		// Hide secrets in payload
 		for _, s := range secrets {
 			callback.Log = strings.Replace(callback.Log, s.Value, "**"+s.Name+"**", -1)
@@ -254,6 +261,10 @@ func (api *API) postWorkflowJobHookCallbackHandler() service.Handler {
 			return sdk.WrapError(err, "Unable to update outgoing hook run status")
 		}
 
+		if err := workflow.StoreEvents(tx, key, report); err != nil {
+			return err
+		}
+
 		if err := tx.Commit(); err != nil {
 			return err
 		}
@@ -293,16 +304,26 @@ func (api *API) getWorkflowJobHookDetailsHandler() service.Handler {
 			return sdk.ErrNotFound
 		}
 
-		pv, err := project.GetAllVariableInProject(db, wr.Workflow.ProjectID, project.WithClearPassword())
+		secrets, err := api.loadOutgoingHookSecrets(db, wr, hr)
 		if err != nil {
-			return sdk.WrapError(err, "Cannot load project variable")
-		}
-
-		secrets, errSecret := workflow.LoadSecrets(db, api.Cache, nil, wr, pv)
-		if errSecret != nil {
-			return sdk.WrapError(errSecret, "getWorkflowJobHookDetailsHandler> Cannot load secrets")
+			return sdk.WrapError(err, "getWorkflowJobHookDetailsHandler> Cannot load secrets")
 		}
+		secrets = workflow.ForkSecrets(wr, secrets)
 		hr.BuildParameters = append(hr.BuildParameters, sdk.VariablesToParameters("", secrets)...)
 		return service.WriteJSON(w, hr, http.StatusOK)
 	}
 }
+
+// loadOutgoingHookSecrets loads the secrets of an outgoing hook run, and resolves the secrets of the secret manager
+// integrations referenced by the outgoing hook
+func (api *API) loadOutgoingHookSecrets(db gorp.SqlExecutor, wr *sdk.WorkflowRun, hr *sdk.WorkflowNodeRun) ([]sdk.Variable, error) {
+	pv, err := project.GetAllVariableInProject(db, wr.Workflow.ProjectID, project.WithClearPassword())
+	if err != nil {
+		return nil, sdk.WrapError(err, "cannot load project variable")
+	}
+	secrets, err := workflow.LoadSecrets(db, api.Cache, nil, wr, pv)
+	if err != nil {
+		return nil, err
+	}
+	return workflow.LoadIntegrationSecrets(db, wr, hr.OutgoingHook, secrets)
+}
@@ -0,0 +1,121 @@
+package api
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/ovh/cds/engine/api/artifactory"
+	"github.com/ovh/cds/engine/api/integration"
+	"github.com/ovh/cds/engine/api/workflow"
+	"github.com/ovh/cds/engine/service"
+	"github.com/ovh/cds/sdk"
+)
+
+// promoteWorkflowNodeRunHandler promotes the build-info of a workflow node run with an Artifactory integration of the project
+func (api *API) promoteWorkflowNodeRunHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		key := vars["key"]
+		name := vars["permWorkflowName"]
+		nodeRunID, errN := requestVarInt(r, "nodeRunID")
+		if errN != nil {
+			return errN
+		}
+
+		number, errNRI := requestVarInt(r, "number")
+		if errNRI != nil {
+			return errNRI
+		}
+
+		var req sdk.WorkflowNodeRunPromote
+		if err := service.UnmarshalBody(r, &req); err != nil {
+			return err
+		}
+		if req.Status == "" {
+			return sdk.NewErrorFrom(sdk.ErrWrongRequest, "missing promotion status")
+		}
+
+		wNodeRun, err := workflow.LoadNodeRun(api.mustDB(), key, name, number, nodeRunID, workflow.LoadRunOptions{})
+		if err != nil {
+			return sdk.WrapError(err, "cannot load node run %d", nodeRunID)
+		}
+
+		workflowRun, err := workflow.LoadRunByIDAndProjectKey(api.mustDB(), key, wNodeRun.WorkflowRunID, workflow.LoadRunOptions{})
+		if err != nil {
+			return sdk.WrapError(err, "cannot load workflow run %d", wNodeRun.WorkflowRunID)
+		}
+
+		var contextIntegrationID int64
+		if node := workflowRun.Workflow.WorkflowData.NodeByID(wNodeRun.WorkflowNodeID); node != nil && node.Context != nil {
+			contextIntegrationID = node.Context.ProjectIntegrationID
+		}
+
+		pis, err := integration.LoadIntegrationsByProjectID(api.mustDB(), workflowRun.ProjectID, true)
+		if err != nil {
+			return sdk.WrapError(err, "cannot load integrations of project %s", key)
+		}
+		pi, err := artifactoryIntegration(pis, req.Integration, contextIntegrationID)
+		if err != nil {
+			return err
+		}
+
+		client, err := artifactory.NewClient(pi)
+		if err != nil {
+			return err
+		}
+
+		// by default, the build is the one published by the Artifact Push action
+		if req.BuildName == "" {
+			req.BuildName = sdk.ParameterValue(wNodeRun.BuildParameters, "cds.project") + "-" + sdk.ParameterValue(wNodeRun.BuildParameters, "cds.workflow")
+		}
+		if req.BuildNumber == "" {
+			req.BuildNumber = sdk.ParameterValue(wNodeRun.BuildParameters, "cds.version")
+		}
+
+		var ciUser string
+		if u := deprecatedGetUser(ctx); u != nil {
+			ciUser = u.Username
+		}
+		if err := client.Promote(req.BuildName, req.BuildNumber, artifactory.Promotion{
+			Status:  req.Status,
+			Comment: req.Comment,
+			CIUser:  ciUser,
+			Copy:    req.Copy,
+		}); err != nil {
+			return err
+		}
+
+		req.Integration = pi.Name
+		req.SourceRepository = client.Mappings[req.Status].SourceRepository
+		req.TargetRepository = client.Mappings[req.Status].TargetRepository
+		return service.WriteJSON(w, req, http.StatusOK)
+	}
+}
+
+// artifactoryIntegration returns the Artifactory integration to use: the given one,
+// the one of the pipeline context, or the only Artifactory integration of the project
+func artifactoryIntegration(pis []sdk.ProjectIntegration, name string, contextIntegrationID int64) (sdk.ProjectIntegration, error) {
+	var candidates []sdk.ProjectIntegration
+	for _, pi := range pis {
+		if pi.Model.Name != sdk.ArtifactoryIntegrationModel {
+			continue
+		}
+		if (name != "" && pi.Name == name) || (name == "" && pi.ID == contextIntegrationID) {
+			return pi, nil
+		}
+		candidates = append(candidates, pi)
+	}
+	if name != "" {
+		return sdk.ProjectIntegration{}, sdk.NewErrorFrom(sdk.ErrNotFound, "there is no %s integration %s on the project", sdk.ArtifactoryIntegrationModel, name)
+	}
+	switch len(candidates) {
+	case 0:
+		return sdk.ProjectIntegration{}, sdk.NewErrorFrom(sdk.ErrNotFound, "there is no %s integration on the project", sdk.ArtifactoryIntegrationModel)
+	case 1:
+		return candidates[0], nil
+	default:
+		return sdk.ProjectIntegration{}, sdk.NewErrorFrom(sdk.ErrWrongRequest, "there are many %s integrations on the project, select one", sdk.ArtifactoryIntegrationModel)
+	}
+}
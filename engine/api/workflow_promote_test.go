@@ -0,0 +1,38 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func Test_artifactoryIntegration(t *testing.T) {
+	pis := []sdk.ProjectIntegration{
+		{ID: 1, Name: "my-kafka", Model: sdk.KafkaIntegration},
+		{ID: 2, Name: "artifactory-dev", Model: sdk.ArtifactoryIntegration},
+	}
+
+	pi, err := artifactoryIntegration(pis, "", 0)
+	assert.NoError(t, err)
+	assert.Equal(t, "artifactory-dev", pi.Name)
+
+	_, err = artifactoryIntegration(pis, "my-kafka", 0)
+	assert.Error(t, err)
+
+	pis = append(pis, sdk.ProjectIntegration{ID: 3, Name: "artifactory-prod", Model: sdk.ArtifactoryIntegration})
+	_, err = artifactoryIntegration(pis, "", 1)
+	assert.Error(t, err)
+
+	pi, err = artifactoryIntegration(pis, "", 3)
+	assert.NoError(t, err)
+	assert.Equal(t, "artifactory-prod", pi.Name)
+
+	pi, err = artifactoryIntegration(pis, "artifactory-dev", 3)
+	assert.NoError(t, err)
+	assert.Equal(t, "artifactory-dev", pi.Name)
+
+	_, err = artifactoryIntegration(nil, "", 0)
+	assert.Error(t, err)
+}
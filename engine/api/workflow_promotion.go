@@ -0,0 +1,204 @@
+package api
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	yaml "gopkg.in/yaml.v2"
+
+	"github.com/ovh/cds/engine/api/project"
+	"github.com/ovh/cds/engine/api/secret"
+	"github.com/ovh/cds/engine/api/workflow"
+	"github.com/ovh/cds/engine/service"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/cdsclient"
+	"github.com/ovh/cds/sdk/exportentities"
+)
+
+// postWorkflowRemotePromoteHandler pushes the workflow and its dependencies to a remote CDS instance, the secrets
+// are encrypted with a passphrase generated for the promotion
+func (api *API) postWorkflowRemotePromoteHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		key := vars["key"]
+		name := vars["permWorkflowName"]
+
+		var req sdk.WorkflowPromotionRequest
+		if err := service.UnmarshalBody(r, &req); err != nil {
+			return err
+		}
+		if req.Project == "" {
+			req.Project = key
+		}
+
+		var remote *RemoteConfiguration
+		for i := range api.Config.Remotes {
+			if api.Config.Remotes[i].Name == req.Remote {
+				remote = &api.Config.Remotes[i]
+				break
+			}
+		}
+		if remote == nil {
+			return sdk.NewErrorFrom(sdk.ErrNotFound, "remote CDS instance %s is not configured", req.Remote)
+		}
+
+		proj, err := project.Load(api.mustDB(), api.Cache, key, deprecatedGetUser(ctx), project.LoadOptions.WithIntegrations)
+		if err != nil {
+			return sdk.WrapError(err, "unable to load project %s", key)
+		}
+
+		passphrase, err := secret.NewPassphrase()
+		if err != nil {
+			return err
+		}
+		pull, err := workflow.Pull(ctx, api.mustDB(), api.Cache, proj, name, exportentities.FormatYAML, archiveEncryptFunc(passphrase), deprecatedGetUser(ctx))
+		if err != nil {
+			return err
+		}
+		buf := new(bytes.Buffer)
+		if err := pull.Tar(buf); err != nil {
+			return err
+		}
+
+		client := cdsclient.New(cdsclient.Config{
+			Host:                  remote.URL,
+			AccessToken:           remote.Token,
+			InsecureSkipVerifyTLS: remote.InsecureSkipVerifyTLS,
+		})
+		source := fmt.Sprintf("%s/project/%s/workflow/%s", api.Config.URL.UI, key, name)
+		promotion, err := client.WorkflowPromotionReceive(req.Project, buf, passphrase, source, req.Comment)
+		if err != nil {
+			return sdk.WrapError(err, "cannot promote workflow %s to %s", name, remote.Name)
+		}
+
+		return service.WriteJSON(w, promotion, http.StatusOK)
+	}
+}
+
+// postWorkflowPromotionHandler imports a workflow promoted from another CDS instance, and records the promotion
+// with the diff of the workflow and its dependencies
+func (api *API) postWorkflowPromotionHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		key := vars[permProjectKey]
+		passphrase := r.Header.Get(sdk.ArchivePassphraseHeader)
+		u := deprecatedGetUser(ctx)
+
+		if r.Body == nil {
+			return sdk.ErrWrongRequest
+		}
+		btes, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			return sdk.NewError(sdk.ErrWrongRequest, err)
+		}
+		defer r.Body.Close()
+
+		name, err := promotedWorkflowName(btes)
+		if err != nil {
+			return err
+		}
+
+		db := api.mustDB()
+		proj, err := project.Load(db, api.Cache, key, u,
+			project.LoadOptions.WithGroups,
+			project.LoadOptions.WithApplications,
+			project.LoadOptions.WithEnvironments,
+			project.LoadOptions.WithPipelines,
+			project.LoadOptions.WithApplicationWithDeploymentStrategies,
+			project.LoadOptions.WithIntegrations)
+		if err != nil {
+			return sdk.WrapError(err, "cannot load project %s", key)
+		}
+
+		before, err := workflow.PromotionSnapshot(ctx, db, api.Cache, proj, name, u)
+		if err != nil {
+			return err
+		}
+
+		allMsg, wf, err := workflow.Push(ctx, db, api.Cache, proj, tar.NewReader(bytes.NewReader(btes)), nil, u, archiveDecryptFunc(passphrase))
+		if err != nil {
+			return sdk.WrapError(err, "cannot push workflow %s", name)
+		}
+
+		after, err := workflow.PromotionSnapshot(ctx, db, api.Cache, proj, wf.Name, u)
+		if err != nil {
+			return err
+		}
+
+		promotion := sdk.WorkflowPromotion{
+			ProjectKey:   key,
+			WorkflowName: wf.Name,
+			Source:       FormString(r, "source"),
+			Username:     u.Username,
+			Comment:      FormString(r, "comment"),
+			Created:      time.Now(),
+			Diff:         workflow.PromotionDiff(before, after),
+		}
+		if err := workflow.InsertPromotion(db, &promotion); err != nil {
+			return err
+		}
+		promotion.Messages = translate(r, allMsg)
+
+		return service.WriteJSON(w, promotion, http.StatusOK)
+	}
+}
+
+// promotedWorkflowName returns the name of the workflow of a pulled tar, the other files are its dependencies
+func promotedWorkflowName(btes []byte) (string, error) {
+	tr := tar.NewReader(bytes.NewReader(btes))
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return "", sdk.NewErrorFrom(sdk.ErrWrongRequest, "there is no workflow in the archive")
+		}
+		if err != nil {
+			return "", sdk.NewError(sdk.ErrWrongRequest, err)
+		}
+		if strings.Contains(hdr.Name, ".app.") || strings.Contains(hdr.Name, ".pip.") || strings.Contains(hdr.Name, ".env.") {
+			continue
+		}
+		content, err := ioutil.ReadAll(tr)
+		if err != nil {
+			return "", sdk.NewError(sdk.ErrWrongRequest, err)
+		}
+		var ew exportentities.Workflow
+		if err := yaml.Unmarshal(content, &ew); err != nil {
+			return "", sdk.NewError(sdk.ErrWrongRequest, err)
+		}
+		return ew.Name, nil
+	}
+}
+
+func (api *API) getWorkflowPromotionsHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		ps, err := workflow.LoadPromotions(api.mustDB(), vars["key"], vars["permWorkflowName"])
+		if err != nil {
+			return err
+		}
+		return service.WriteJSON(w, ps, http.StatusOK)
+	}
+}
+
+func (api *API) getWorkflowPromotionHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		id, err := requestVarInt(r, "id")
+		if err != nil {
+			return err
+		}
+		p, err := workflow.LoadPromotion(api.mustDB(), vars["key"], vars["permWorkflowName"], id)
+		if err != nil {
+			return err
+		}
+		return service.WriteJSON(w, p, http.StatusOK)
+	}
+}
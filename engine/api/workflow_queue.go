@@ -2,6 +2,7 @@ package api
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 	"net/http"
 	"strconv"
@@ -15,6 +16,7 @@ import (
 	"github.com/ovh/cds/engine/api/cache"
 	"github.com/ovh/cds/engine/api/event"
 	"github.com/ovh/cds/engine/api/group"
+	"github.com/ovh/cds/engine/api/jobtoken"
 	"github.com/ovh/cds/engine/api/metrics"
 	"github.com/ovh/cds/engine/api/observability"
 	"github.com/ovh/cds/engine/api/permission"
@@ -23,6 +25,7 @@ import (
 	"github.com/ovh/cds/engine/api/services"
 	"github.com/ovh/cds/engine/api/worker"
 	"github.com/ovh/cds/engine/api/workflow"
+	"github.com/ovh/cds/engine/api/workflow/queue"
 	"github.com/ovh/cds/engine/service"
 	"github.com/ovh/cds/sdk"
 	"github.com/ovh/cds/sdk/cdsclient"
@@ -41,6 +44,10 @@ func (api *API) postTakeWorkflowJobHandler() service.Handler {
 			return sdk.WrapError(err, "Cannot unmarshal request")
 		}
 
+		if getWorker(ctx).Draining {
+			return sdk.WrapError(sdk.ErrWorkerDraining, "worker %s cannot take job %d", getWorker(ctx).Name, id)
+		}
+
 		user := deprecatedGetUser(ctx)
 		p, errP := project.LoadProjectByNodeJobRunID(ctx, api.mustDB(), api.Cache, id, user, project.LoadOptions.WithVariables, project.LoadOptions.WithClearKeys)
 		if errP != nil {
@@ -58,6 +65,10 @@ func (api *API) postTakeWorkflowJobHandler() service.Handler {
 			if errModel != nil {
 				return sdk.ErrNoWorkerModel
 			}
+			// a worker model scoped to an organization only runs the jobs of its projects
+			if wm.OrganizationID != 0 && wm.OrganizationID != p.OrganizationID {
+				return sdk.WrapError(sdk.ErrForbidden, "worker model %s cannot run jobs of project %s", wm.Name, p.Key)
+			}
 			workerModel = wm.Name
 		}
 
@@ -65,6 +76,7 @@ func (api *API) postTakeWorkflowJobHandler() service.Handler {
 		if errl != nil {
 			return sdk.WrapError(errl, "Cannot load job nodeJobRunID:%d", id)
 		}
+		ctx = log.ContextWithFields(ctx, pbj.LogFields())
 
 		observability.Current(ctx,
 			observability.Tag(observability.TagWorkflowNodeJobRun, id),
@@ -94,10 +106,20 @@ func (api *API) postTakeWorkflowJobHandler() service.Handler {
 		}
 
 		pbji := &sdk.WorkflowNodeJobRunData{}
-		report, errT := takeJob(ctx, api.mustDB, api.Cache, p, id, takeForm, workerModel, pbji)
+		report, errT := takeJob(ctx, api.mustDB, api.Cache, p, id, takeForm, workerModel, pbji, api.Config.Queue.DefaultProjectQuota)
 		if errT != nil {
 			return sdk.WrapError(errT, "Cannot takeJob nodeJobRunID:%d", id)
 		}
+		observability.RecordFloat64(api.Router.Background, api.Metrics.jobSchedulingLatency, float64(time.Since(pbj.Queued))/float64(time.Millisecond))
+		if modelID := getWorker(ctx).ModelID; modelID != 0 {
+			if err := worker.UpdateModelUsage(api.mustDB(), modelID); err != nil {
+				log.Warning("postTakeWorkflowJobHandler> %v", err)
+			}
+		}
+		if wk := getWorker(ctx); wk != nil {
+			ctx = log.ContextWithFields(ctx, log.Fields{log.FieldWorker: wk.Name})
+		}
+		log.InfoCtx(ctx, "postTakeWorkflowJobHandler> job %d taken", id)
 
 		workflow.ResyncNodeRunsWithCommits(api.mustDB(), api.Cache, p, report)
 		go workflow.SendEvent(api.mustDB(), p.Key, report)
@@ -106,7 +128,14 @@ func (api *API) postTakeWorkflowJobHandler() service.Handler {
 	}
 }
 
-func takeJob(ctx context.Context, dbFunc func() *gorp.DbMap, store cache.Store, p *sdk.Project, id int64, takeForm *sdk.WorkerTakeForm, workerModel string, wnjri *sdk.WorkflowNodeJobRunData) (*workflow.ProcessorReport, error) {
+func takeJob(ctx context.Context, dbFunc func() *gorp.DbMap, store cache.Store, p *sdk.Project, id int64, takeForm *sdk.WorkerTakeForm, workerModel string, wnjri *sdk.WorkflowNodeJobRunData, defaultProjectQuota int) (*workflow.ProcessorReport, error) {
+	// The secrets are loaded before the transaction, the secret manager integrations are not called under the lock
+	// of the project taken by the quota check
+	secrets, err := loadJobSecrets(dbFunc(), store, p, id)
+	if err != nil {
+		return nil, sdk.WrapError(err, "Cannot load secrets")
+	}
+
 	// Start a tx
 	tx, errBegin := dbFunc().Begin()
 	if errBegin != nil {
@@ -114,6 +143,22 @@ func takeJob(ctx context.Context, dbFunc func() *gorp.DbMap, store cache.Store,
 	}
 	defer tx.Rollback()
 
+	// The quotas and the freeze of the environment are checked again in the transaction, a worker can take a job
+	// which was not booked
+	pbj, err := workflow.LoadNodeJobRun(tx, store, id)
+	if err != nil {
+		return nil, sdk.WrapError(err, "Cannot load job %d", id)
+	}
+	if err := queue.CheckProjectQuota(tx, store, pbj.ProjectID, id, defaultProjectQuota); err != nil {
+		return nil, err
+	}
+	if err := queue.CheckOrganizationQuota(tx, pbj.ProjectID); err != nil {
+		return nil, err
+	}
+	if err := queue.CheckEnvironmentFreeze(tx, pbj); err != nil {
+		return nil, err
+	}
+
 	//Prepare spawn infos
 	infos := []sdk.SpawnInfo{
 		{
@@ -163,17 +208,6 @@ func takeJob(ctx context.Context, dbFunc func() *gorp.DbMap, store cache.Store,
 		return nil, sdk.WrapError(err, "Unable to load workflow run")
 	}
 
-	//Load the secrets
-	pv, err := project.GetAllVariableInProject(tx, p.ID, project.WithClearPassword())
-	if err != nil {
-		return nil, sdk.WrapError(err, "Cannot load project variable")
-	}
-
-	secrets, errSecret := workflow.LoadSecrets(tx, store, noderun, workflowRun, pv)
-	if errSecret != nil {
-		return nil, sdk.WrapError(errSecret, "Cannot load secrets")
-	}
-
 	//Feed the worker
 	wnjri.NodeJobRun = *job
 	wnjri.Number = noderun.Number
@@ -187,6 +221,29 @@ func takeJob(ctx context.Context, dbFunc func() *gorp.DbMap, store cache.Store,
 	wnjri.Secrets = append(wnjri.Secrets, secretsKeys...)
 	wnjri.NodeJobRun.Parameters = append(wnjri.NodeJobRun.Parameters, params...)
 
+	// Give its OIDC token to the job, to federate with the cloud providers
+	if jobtoken.Enabled() {
+		token, err := jobtoken.New(sdk.JobTokenClaims{
+			Project:  p.Key,
+			Workflow: workflowRun.Workflow.Name,
+			Run:      workflowRun.Number,
+			Node:     noderun.WorkflowNodeName,
+			Job:      job.Job.Action.Name,
+			Ref:      jobtoken.Ref(*noderun),
+		})
+		if err != nil {
+			return nil, sdk.WrapError(err, "Cannot create job token")
+		}
+		wnjri.Secrets = append(wnjri.Secrets, sdk.Variable{Name: sdk.JobTokenVariable, Type: sdk.SecretVariable, Value: token})
+	}
+
+	// The runs of the pull requests from forks only get the secrets allowed by the fork policy of the workflow
+	wnjri.Secrets = workflow.ForkSecrets(workflowRun, wnjri.Secrets)
+
+	if err := workflow.StoreEvents(tx, p.Key, report); err != nil {
+		return nil, err
+	}
+
 	if err := tx.Commit(); err != nil {
 		return nil, sdk.WrapError(err, "Cannot commit transaction")
 	}
@@ -194,6 +251,32 @@ func takeJob(ctx context.Context, dbFunc func() *gorp.DbMap, store cache.Store,
 	return report, nil
 }
 
+// loadJobSecrets loads the secrets of a job, and resolves the secrets of the secret manager integrations it references
+func loadJobSecrets(db gorp.SqlExecutor, store cache.Store, p *sdk.Project, id int64) ([]sdk.Variable, error) {
+	job, err := workflow.LoadNodeJobRun(db, store, id)
+	if err != nil {
+		return nil, sdk.WrapError(err, "cannot load job %d", id)
+	}
+	noderun, err := workflow.LoadNodeRunByID(db, job.WorkflowNodeRunID, workflow.LoadRunOptions{})
+	if err != nil {
+		return nil, sdk.WrapError(err, "cannot get node run")
+	}
+	workflowRun, err := workflow.LoadRunByID(db, noderun.WorkflowRunID, workflow.LoadRunOptions{})
+	if err != nil {
+		return nil, sdk.WrapError(err, "unable to load workflow run")
+	}
+
+	pv, err := project.GetAllVariableInProject(db, p.ID, project.WithClearPassword())
+	if err != nil {
+		return nil, sdk.WrapError(err, "cannot load project variable")
+	}
+	secrets, err := workflow.LoadSecrets(db, store, noderun, workflowRun, pv)
+	if err != nil {
+		return nil, err
+	}
+	return workflow.LoadIntegrationSecrets(db, workflowRun, job, secrets)
+}
+
 func (api *API) postBookWorkflowJobHandler() service.Handler {
 	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
 		id, errc := requestVarInt(r, "id")
@@ -201,8 +284,55 @@ func (api *API) postBookWorkflowJobHandler() service.Handler {
 			return sdk.WrapError(errc, "Invalid id")
 		}
 
-		if _, err := workflow.BookNodeJobRun(api.Cache, id, getHatchery(ctx)); err != nil {
-			return sdk.WrapError(err, "Job already booked")
+		if err := bookJob(api.mustDB(), api.Cache, id, getHatchery(ctx), api.Config.Queue.DefaultProjectQuota); err != nil {
+			return err
+		}
+		return service.WriteJSON(w, nil, http.StatusOK)
+	}
+}
+
+// bookJob reserves a job of the queue for an hatchery, unless the quota of the project of the job or of its organization is reached
+// or the job deploys on a frozen environment
+func bookJob(db *gorp.DbMap, store cache.Store, id int64, hatchery *sdk.Service, defaultProjectQuota int) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return sdk.WrapError(err, "cannot start transaction")
+	}
+	defer tx.Rollback() // nolint
+
+	job, err := workflow.LoadNodeJobRun(tx, store, id)
+	if err != nil {
+		return sdk.WrapError(err, "cannot load job %d", id)
+	}
+	if err := queue.CheckProjectQuota(tx, store, job.ProjectID, id, defaultProjectQuota); err != nil {
+		return err
+	}
+	if err := queue.CheckOrganizationQuota(tx, job.ProjectID); err != nil {
+		return err
+	}
+	if err := queue.CheckEnvironmentFreeze(tx, job); err != nil {
+		return err
+	}
+
+	if _, err := workflow.BookNodeJobRun(store, id, hatchery); err != nil {
+		return sdk.WrapError(err, "Job already booked")
+	}
+	return sdk.WithStack(tx.Commit())
+}
+
+func (api *API) postConfirmBookWorkflowJobHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		id, errc := requestVarInt(r, "id")
+		if errc != nil {
+			return sdk.WrapError(errc, "Invalid id")
+		}
+
+		h := getHatchery(ctx)
+		if h == nil {
+			return sdk.WithStack(sdk.ErrForbidden)
+		}
+		if err := workflow.ConfirmNodeJobRun(api.Cache, id, h); err != nil {
+			return sdk.WrapError(err, "cannot confirm booking of job %d", id)
 		}
 		return service.WriteJSON(w, nil, http.StatusOK)
 	}
@@ -215,7 +345,7 @@ func (api *API) deleteBookWorkflowJobHandler() service.Handler {
 			return sdk.WrapError(errc, "Invalid id")
 		}
 
-		if err := workflow.FreeNodeJobRun(api.Cache, id); err != nil {
+		if err := workflow.FreeNodeJobRun(api.Cache, id, getHatchery(ctx)); err != nil {
 			return sdk.WrapError(err, "job not booked")
 		}
 		return service.WriteJSON(w, nil, http.StatusOK)
@@ -375,6 +505,61 @@ func (api *API) postSpawnInfosWorkflowJobHandler() service.AsynchronousHandler {
 	}
 }
 
+func (api *API) postWorkflowJobWorkerLostHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		id, errc := requestVarInt(r, "id")
+		if errc != nil {
+			return sdk.WrapError(errc, "invalid id")
+		}
+		h := getHatchery(ctx)
+		if h == nil {
+			return service.WriteJSON(w, nil, http.StatusUnauthorized)
+		}
+
+		var f sdk.WorkerLostForm
+		if err := service.UnmarshalBody(r, &f); err != nil {
+			return sdk.WrapError(err, "cannot unmarshal request")
+		}
+
+		tx, errBegin := api.mustDB().Begin()
+		if errBegin != nil {
+			return sdk.WrapError(errBegin, "cannot start transaction")
+		}
+		defer tx.Rollback() // nolint
+
+		job, err := workflow.LoadAndLockNodeJobRunWait(tx, api.Cache, id)
+		if err != nil {
+			return sdk.WrapError(err, "cannot load node job run %d", id)
+		}
+
+		// The job could have been restarted or ended by the worker before the preemption
+		if job.Status != sdk.StatusBuilding.String() {
+			log.Info("postWorkflowJobWorkerLostHandler> job %d is %s, ignoring lost worker %s", id, job.Status, f.WorkerName)
+			return service.WriteJSON(w, nil, http.StatusOK)
+		}
+
+		// Only the hatchery which spawned the worker of the job can replace it in queue
+		wk, err := worker.LoadWorker(tx, job.Job.WorkerID)
+		if err != nil && err != sql.ErrNoRows {
+			return sdk.WrapError(err, "cannot load worker %s", job.Job.WorkerID)
+		}
+		if wk == nil || wk.HatcheryName != h.Name || wk.ActionBuildID != id {
+			return sdk.NewErrorFrom(sdk.ErrForbidden, "job %d was not taken by a worker of hatchery %s", id, h.Name)
+		}
+
+		if err := workflow.RequeueLostWorkflowNodeJob(ctx, tx, *job, f.WorkerName); err != nil {
+			return sdk.WrapError(err, "cannot replace node job run %d in queue", id)
+		}
+
+		if err := tx.Commit(); err != nil {
+			return sdk.WrapError(err, "cannot commit tx")
+		}
+
+		log.Info("postWorkflowJobWorkerLostHandler> worker %s of hatchery %s lost (%s), job %d replaced in queue", f.WorkerName, h.Name, f.Reason, id)
+		return service.WriteJSON(w, nil, http.StatusOK)
+	}
+}
+
 func (api *API) postWorkflowJobResultHandler() service.Handler {
 	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
 		id, errc := requestVarInt(r, "permID")
@@ -387,6 +572,7 @@ func (api *API) postWorkflowJobResultHandler() service.Handler {
 		if err := service.UnmarshalBody(r, &res); err != nil {
 			return sdk.WrapError(err, "Cannot unmarshal request")
 		}
+		ctx = log.ContextWithFields(ctx, log.Fields{log.FieldJobID: id})
 		customCtx, cancel := context.WithTimeout(ctx, 180*time.Second)
 		defer cancel()
 		dbWithCtx := api.mustDBWithCtx(customCtx)
@@ -401,7 +587,7 @@ func (api *API) postWorkflowJobResultHandler() service.Handler {
 					// job result already send as job is no more in database
 					// this log is here to stats it and we returns nil for unlock the worker
 					// and avoid a "worker timeout"
-					log.Warning("NodeJobRun not found: %d err:%v", id, errLn)
+					log.WarningCtx(ctx, "NodeJobRun not found: %d err:%v", id, errLn)
 					return nil
 				}
 				return sdk.WrapError(errLn, "Cannot load NodeJobRun %d", id)
@@ -417,6 +603,7 @@ func (api *API) postWorkflowJobResultHandler() service.Handler {
 		if err != nil {
 			return sdk.WrapError(err, "unable to post job result")
 		}
+		log.InfoCtx(ctx, "postWorkflowJobResultHandler> job %d ended with status %s", id, res.Status)
 
 		workflowRuns := report.WorkflowRuns()
 		if len(workflowRuns) > 0 {
@@ -434,6 +621,10 @@ func (api *API) postWorkflowJobResultHandler() service.Handler {
 
 		go workflow.SendEvent(api.mustDB(), proj.Key, report)
 
+		sdk.GoRoutine(api.Router.Background, "triggerUpstreamWorkflows", func(ctx context.Context) {
+			triggerUpstreamWorkflows(ctx, api.mustDB(), api.Cache, report)
+		}, api.PanicDump())
+
 		return nil
 	}
 }
@@ -490,6 +681,10 @@ func postJobResult(ctx context.Context, dbFunc func(context.Context) *gorp.DbMap
 		return nil, sdk.WrapError(err, "Cannot update worker %s status", wr.ID)
 	}
 
+	if err := workflow.StoreEvents(tx, proj.Key, report); err != nil {
+		return nil, err
+	}
+
 	//Commit the transaction
 	if err := tx.Commit(); err != nil {
 		return nil, sdk.WrapError(err, "Cannot commit tx")
@@ -640,87 +835,96 @@ func (api *API) postWorkflowJobStepStatusHandler() service.Handler {
 		if errr != nil {
 			return sdk.WrapError(errr, "Invalid id")
 		}
-		dbWithCtx := api.mustDBWithCtx(ctx)
-
-		nodeJobRun, errJob := workflow.LoadNodeJobRun(dbWithCtx, api.Cache, id)
-		if errJob != nil {
-			return sdk.WrapError(errJob, "Cannot get job run %d", id)
-		}
-
 		var step sdk.StepStatus
 		if err := service.UnmarshalBody(r, &step); err != nil {
 			return sdk.WrapError(err, "Error while unmarshal job")
 		}
 
-		found := false
-		for i := range nodeJobRun.Job.StepStatus {
-			jobStep := &nodeJobRun.Job.StepStatus[i]
-			if step.StepOrder == jobStep.StepOrder {
-				jobStep.Status = step.Status
-				if sdk.StatusIsTerminated(step.Status) {
-					jobStep.Done = step.Done
-				}
-				found = true
-				break
+		return postJobStepStatus(ctx, api.mustDBWithCtx(ctx), api.Cache, id, step, r.Header.Get("Accept-Language"))
+	}
+}
+
+// postJobStepStatus updates the status of a step of a job and publishes the new status of its node run
+func postJobStepStatus(ctx context.Context, db *gorp.DbMap, store cache.Store, id int64, step sdk.StepStatus, lang string) error {
+	nodeJobRun, errJob := workflow.LoadNodeJobRun(db, store, id)
+	if errJob != nil {
+		return sdk.WrapError(errJob, "Cannot get job run %d", id)
+	}
+
+	found := false
+	for i := range nodeJobRun.Job.StepStatus {
+		jobStep := &nodeJobRun.Job.StepStatus[i]
+		if step.StepOrder == jobStep.StepOrder {
+			jobStep.Status = step.Status
+			if sdk.StatusIsTerminated(step.Status) {
+				jobStep.Done = step.Done
+				jobStep.Usage = step.Usage
 			}
+			found = true
+			break
 		}
-		if !found {
-			step.Done = time.Time{}
-			nodeJobRun.Job.StepStatus = append(nodeJobRun.Job.StepStatus, step)
-		}
+	}
+	if !found {
+		step.Done = time.Time{}
+		nodeJobRun.Job.StepStatus = append(nodeJobRun.Job.StepStatus, step)
+	}
 
-		tx, errB := dbWithCtx.Begin()
-		if errB != nil {
-			return sdk.WrapError(errB, "Cannot start transaction")
-		}
-		defer tx.Rollback()
+	tx, errB := db.Begin()
+	if errB != nil {
+		return sdk.WrapError(errB, "Cannot start transaction")
+	}
+	defer tx.Rollback()
 
-		if err := workflow.UpdateNodeJobRun(ctx, tx, nodeJobRun); err != nil {
-			return sdk.WrapError(err, "Error while update job run. JobID on handler: %d", id)
-		}
+	if err := workflow.UpdateNodeJobRun(ctx, tx, nodeJobRun); err != nil {
+		return sdk.WrapError(err, "Error while update job run. JobID on handler: %d", id)
+	}
 
-		var nodeRun sdk.WorkflowNodeRun
-		if !found {
-			nodeRun, errNR := workflow.LoadAndLockNodeRunByID(ctx, tx, nodeJobRun.WorkflowNodeRunID, false)
-			if errNR != nil {
-				return sdk.WrapError(errNR, "postWorkflowJobStepStatusHandler> Cannot load node run")
-			}
-			sync, errS := workflow.SyncNodeRunRunJob(ctx, tx, nodeRun, *nodeJobRun)
-			if errS != nil {
-				return sdk.WrapError(errS, "postWorkflowJobStepStatusHandler> unable to sync nodeJobRun. JobID on handler: %d", id)
-			}
-			if !sync {
-				log.Warning("postWorkflowJobStepStatusHandler> sync doesn't find a nodeJobRun. JobID on handler: %d", id)
-			}
-			if errU := workflow.UpdateNodeRun(tx, nodeRun); errU != nil {
-				return sdk.WrapError(errU, "postWorkflowJobStepStatusHandler> Cannot update node run. JobID on handler: %d", id)
-			}
+	var nodeRun sdk.WorkflowNodeRun
+	if !found {
+		nodeRun, errNR := workflow.LoadAndLockNodeRunByID(ctx, tx, nodeJobRun.WorkflowNodeRunID, false)
+		if errNR != nil {
+			return sdk.WrapError(errNR, "postWorkflowJobStepStatusHandler> Cannot load node run")
 		}
-
-		if err := tx.Commit(); err != nil {
-			return sdk.WrapError(err, "Cannot commit transaction")
+		sync, errS := workflow.SyncNodeRunRunJob(ctx, tx, nodeRun, *nodeJobRun)
+		if errS != nil {
+			return sdk.WrapError(errS, "postWorkflowJobStepStatusHandler> unable to sync nodeJobRun. JobID on handler: %d", id)
 		}
-
-		if nodeRun.ID == 0 {
-			nodeRunP, errN := workflow.LoadNodeRunByID(api.mustDB(), nodeJobRun.WorkflowNodeRunID, workflow.LoadRunOptions{
-				DisableDetailledNodeRun: true,
-			})
-			if errN != nil {
-				log.Warning("postWorkflowJobStepStatusHandler> Unable to load node run for event: %v", errN)
-				return nil
-			}
-			nodeRun = *nodeRunP
+		if !sync {
+			log.Warning("postWorkflowJobStepStatusHandler> sync doesn't find a nodeJobRun. JobID on handler: %d", id)
+		}
+		if errU := workflow.UpdateNodeRun(tx, nodeRun); errU != nil {
+			return sdk.WrapError(errU, "postWorkflowJobStepStatusHandler> Cannot update node run. JobID on handler: %d", id)
 		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return sdk.WrapError(err, "Cannot commit transaction")
+	}
 
-		work, errW := workflow.LoadWorkflowFromWorkflowRunID(api.mustDB(), nodeRun.WorkflowRunID)
-		if errW != nil {
-			log.Warning("postWorkflowJobStepStatusHandler> Unable to load workflow for event: %v", errW)
+	if nodeRun.ID == 0 {
+		nodeRunP, errN := workflow.LoadNodeRunByID(db, nodeJobRun.WorkflowNodeRunID, workflow.LoadRunOptions{
+			DisableDetailledNodeRun: true,
+		})
+		if errN != nil {
+			log.Warning("postWorkflowJobStepStatusHandler> Unable to load node run for event: %v", errN)
 			return nil
 		}
-		nodeRun.Translate(r.Header.Get("Accept-Language"))
-		event.PublishWorkflowNodeRun(api.mustDB(), nodeRun, work, nil)
+		nodeRun = *nodeRunP
+	}
+
+	work, errW := workflow.LoadWorkflowFromWorkflowRunID(db, nodeRun.WorkflowRunID)
+	if errW != nil {
+		log.Warning("postWorkflowJobStepStatusHandler> Unable to load workflow for event: %v", errW)
 		return nil
 	}
+	nodeRun.Translate(lang)
+	if e, ok := event.NewWorkflowNodeRunEvent(nodeRun, work); ok {
+		if err := event.Store(db, e); err != nil {
+			log.Warning("postWorkflowJobStepStatusHandler> Unable to store event: %v", err)
+		}
+	}
+	event.PublishWorkflowNodeRun(db, nodeRun, work, nil)
+	return nil
 }
 
 func (api *API) countWorkflowJobQueueHandler() service.Handler {
@@ -798,11 +1002,25 @@ func (api *API) getWorkflowJobQueueHandler() service.Handler {
 			Limit:        &limit,
 			Statuses:     status,
 		}
-		jobs, err := workflow.LoadNodeJobRunQueue(ctx, api.mustDB(), api.Cache, filter)
+		// Hatcheries and workers book the jobs they get, they need an up to date queue
+		db := api.mustDB()
+		if permissions == permission.PermissionRead {
+			db = api.mustDBForRead(ctx)
+		}
+		jobs, err := workflow.LoadNodeJobRunQueue(ctx, db, api.Cache, filter)
 		if err != nil {
 			return sdk.WrapError(err, "Unable to load queue")
 		}
 
+		// Hatcheries and workers get the jobs in the order they should be started
+		if permissions == permission.PermissionReadExecute {
+			scheduler, err := queue.NewScheduler(api.mustDB(), api.Config.Queue.FairScheduling, api.Config.Queue.DefaultProjectQuota)
+			if err != nil {
+				return sdk.WrapError(err, "unable to load queue quotas")
+			}
+			jobs = scheduler.Schedule(jobs)
+		}
+
 		return service.WriteJSON(w, jobs, http.StatusOK)
 	}
 }
@@ -893,7 +1111,30 @@ func (api *API) postWorkflowJobCoverageResultsHandler() service.Handler {
 			}
 		}
 
-		return nil
+		var name string
+		if nodeRunJob, err := workflow.LoadNodeJobRun(api.mustDB(), api.Cache, id); err == nil {
+			name = nodeRunJob.Job.Action.Name
+		}
+		result := sdk.WorkflowRunResultCoverage{
+			Name:             name,
+			TotalLines:       report.TotalLines,
+			CoveredLines:     report.CoveredLines,
+			TotalFunctions:   report.TotalFunctions,
+			CoveredFunctions: report.CoveredFunctions,
+			TotalBranches:    report.TotalBranches,
+			CoveredBranches:  report.CoveredBranches,
+		}
+		if _, err := workflow.AddRunResult(api.mustDB(), *wnr, id, sdk.WorkflowRunResultTypeCoverage, result); err != nil {
+			log.Error("postWorkflowJobCoverageResultsHandler> Cannot record coverage as a result of node run %d: %v", wnr.ID, err)
+		}
+
+		cov, err := workflow.LoadCoverageReport(api.mustDB(), wnr.ID)
+		if err != nil {
+			return sdk.WrapError(err, "Unable to load code coverage")
+		}
+		diff := sdk.NewWorkflowNodeRunCoverageDiff(cov)
+		diff.WorkflowNodeName = wnr.WorkflowNodeName
+		return service.WriteJSON(w, diff, http.StatusOK)
 	}
 }
 
@@ -931,6 +1172,9 @@ func (api *API) postWorkflowJobTestsResultsHandler() service.Handler {
 			nr.Tests = &venom.Tests{}
 		}
 
+		// Test cases are stored with the name of their testsuite before deduplication, to follow them across runs
+		testCases := sdk.NewWorkflowRunTestCases(*nr, new)
+
 		for k := range new.TestSuites {
 			for i := range nr.Tests.TestSuites {
 				if nr.Tests.TestSuites[i].Name == new.TestSuites[k].Name {
@@ -963,6 +1207,21 @@ func (api *API) postWorkflowJobTestsResultsHandler() service.Handler {
 			return sdk.WrapError(err, "Cannot update node run")
 		}
 
+		if err := workflow.InsertTestCases(api.mustDB(), testCases); err != nil {
+			log.Error("postWorkflowJobTestsResultsHandler> Cannot store test cases of node run %d: %v", nr.ID, err)
+		}
+
+		result := sdk.WorkflowRunResultTests{Name: nodeRunJob.Job.Action.Name}
+		for _, ts := range new.TestSuites {
+			result.Total += ts.Total
+			result.TotalKO += ts.Failures + ts.Errors
+			result.TotalOK += ts.Total - ts.Skipped - ts.Failures - ts.Errors
+			result.TotalSkipped += ts.Skipped
+		}
+		if _, err := workflow.AddRunResult(api.mustDB(), *nr, id, sdk.WorkflowRunResultTypeTests, result); err != nil {
+			log.Error("postWorkflowJobTestsResultsHandler> Cannot record tests as a result of node run %d: %v", nr.ID, err)
+		}
+
 		// If we are on default branch, push metrics
 		if nr.VCSServer != "" && nr.VCSBranch != "" {
 			p, errP := project.LoadProjectByNodeJobRunID(ctx, api.mustDB(), api.Cache, id, deprecatedGetUser(ctx))
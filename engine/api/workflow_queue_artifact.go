@@ -3,16 +3,24 @@ package api
 import (
 	"context"
 	"encoding/base64"
+	"fmt"
 	"mime"
 	"net/http"
 	"strconv"
 	"time"
 
+	"github.com/go-gorp/gorp"
 	"github.com/gorilla/mux"
 
 	"github.com/ovh/cds/engine/api/artifact"
 	"github.com/ovh/cds/engine/api/cache"
+	"github.com/ovh/cds/engine/api/cost"
+	"github.com/ovh/cds/engine/api/keys"
 	"github.com/ovh/cds/engine/api/objectstore"
+	"github.com/ovh/cds/engine/api/organization"
+	"github.com/ovh/cds/engine/api/project"
+	"github.com/ovh/cds/engine/api/provenance"
+	"github.com/ovh/cds/engine/api/usage"
 	"github.com/ovh/cds/engine/api/workflow"
 	"github.com/ovh/cds/engine/service"
 	"github.com/ovh/cds/sdk"
@@ -45,7 +53,7 @@ func (api *API) postWorkflowJobArtifactHandler() service.Handler {
 		//get a ref to the parsed multipart form
 		m := r.MultipartForm
 
-		var sizeStr, permStr, md5sum, sha512sum string
+		var sizeStr, permStr, md5sum, sha512sum, sha256sum, signingKey string
 		if len(m.Value["size"]) > 0 {
 			sizeStr = m.Value["size"][0]
 		}
@@ -58,6 +66,12 @@ func (api *API) postWorkflowJobArtifactHandler() service.Handler {
 		if len(m.Value["sha512sum"]) > 0 {
 			sha512sum = m.Value["sha512sum"][0]
 		}
+		if len(m.Value["sha256sum"]) > 0 {
+			sha256sum = m.Value["sha256sum"][0]
+		}
+		if len(m.Value["signing_key"]) > 0 {
+			signingKey = m.Value["signing_key"][0]
+		}
 
 		if fileName == "" {
 			log.Warning("uploadArtifactHandler> %s header is not set", "Content-Disposition")
@@ -68,6 +82,9 @@ func (api *API) postWorkflowJobArtifactHandler() service.Handler {
 		if errJ != nil {
 			return sdk.WrapError(errJ, "Cannot load node job run")
 		}
+		if err := organization.CheckStorageQuota(api.mustDB(), nodeJobRun.ProjectID); err != nil {
+			return err
+		}
 
 		nodeRun, errR := workflow.LoadNodeRunByID(api.mustDB(), nodeJobRun.WorkflowNodeRunID, workflow.LoadRunOptions{WithArtifacts: true, DisableDetailledNodeRun: true})
 		if errR != nil {
@@ -103,11 +120,22 @@ func (api *API) postWorkflowJobArtifactHandler() service.Handler {
 			Perm:              uint32(perm),
 			MD5sum:            md5sum,
 			SHA512sum:         sha512sum,
+			SHA256sum:         sha256sum,
+			SigningKey:        signingKey,
 			WorkflowNodeRunID: nodeRun.ID,
 			WorkflowID:        nodeRun.WorkflowRunID,
 			Created:           time.Now(),
 		}
 
+		if err := signArtifact(api.mustDB(), nodeJobRun.ProjectID, &art); err != nil {
+			return err
+		}
+
+		env, err := api.attestArtifact(ctx, art)
+		if err != nil {
+			return err
+		}
+
 		files := m.File[fileName]
 		if len(files) == 1 {
 			file, err := files[0].Open()
@@ -129,6 +157,16 @@ func (api *API) postWorkflowJobArtifactHandler() service.Handler {
 			_ = objectstore.Delete(&art)
 			return sdk.WrapError(err, "Cannot update workflow node run")
 		}
+		if err := workflow.UpdateArtifactProvenance(api.mustDB(), art.ID, *env); err != nil {
+			return err
+		}
+		if err := usage.AddArtifactSize(api.mustDB(), nodeJobRun.ProjectID, art.Size); err != nil {
+			log.Warning("postWorkflowJobArtifactHandler> %v", err)
+		}
+		if err := cost.AddArtifact(api.mustDB(), nodeJobRun.ProjectID, nodeRun, art.Size); err != nil {
+			log.Warning("postWorkflowJobArtifactHandler> %v", err)
+		}
+		addArtifactRunResult(api.mustDB(), *nodeRun, id, art)
 		return nil
 	}
 }
@@ -167,6 +205,9 @@ func (api *API) postWorkflowJobArtifacWithTempURLHandler() service.Handler {
 		if errJ != nil {
 			return sdk.WrapError(errJ, "postWorkflowJobArtifacWithTempURLHandler> Cannot load node job run")
 		}
+		if err := organization.CheckStorageQuota(api.mustDB(), nodeJobRun.ProjectID); err != nil {
+			return err
+		}
 
 		nodeRun, errR := workflow.LoadNodeRunByID(api.mustDB(), nodeJobRun.WorkflowNodeRunID, workflow.LoadRunOptions{WithArtifacts: true, DisableDetailledNodeRun: true})
 		if errR != nil {
@@ -184,6 +225,10 @@ func (api *API) postWorkflowJobArtifacWithTempURLHandler() service.Handler {
 		art.Tag = string(tag)
 		art.Ref = ref
 
+		if err := signArtifact(api.mustDB(), nodeJobRun.ProjectID, &art); err != nil {
+			return err
+		}
+
 		var retryURL = 10
 		var url, key string
 		var errorStoreURL error
@@ -233,18 +278,113 @@ func (api *API) postWorkflowJobArtifactWithTempURLCallbackHandler() service.Hand
 		if !art.Equal(cachedArt) {
 			return sdk.WrapError(sdk.ErrForbidden, "postWorkflowJobArtifactWithTempURLCallbackHandler> Submitted artifact doesn't match, key:%s art:%v cachedArt:%v", cacheKey, art, cachedArt)
 		}
+		// the signature is computed by the api, not submitted by the worker
+		art.Signature = cachedArt.Signature
 
 		nodeRun, errR := workflow.LoadNodeRunByID(api.mustDB(), art.WorkflowNodeRunID, workflow.LoadRunOptions{WithArtifacts: true, DisableDetailledNodeRun: true})
 		if errR != nil {
 			return sdk.WrapError(errR, "Cannot load node run")
 		}
 
+		env, err := api.attestArtifact(ctx, art)
+		if err != nil {
+			_ = objectstore.Delete(&art)
+			return err
+		}
+
 		nodeRun.Artifacts = append(nodeRun.Artifacts, art)
 		if err := workflow.InsertArtifact(api.mustDB(), &art); err != nil {
 			_ = objectstore.Delete(&art)
 			return sdk.WrapError(err, "Cannot update workflow node run")
 		}
+		if err := workflow.UpdateArtifactProvenance(api.mustDB(), art.ID, *env); err != nil {
+			return err
+		}
+		jobID, _ := requestVarInt(r, "permID")
+		if nodeJobRun, err := workflow.LoadNodeJobRun(api.mustDB(), api.Cache, jobID); err != nil {
+			log.Warning("postWorkflowJobArtifactWithTempURLCallbackHandler> %v", err)
+		} else {
+			if err := usage.AddArtifactSize(api.mustDB(), nodeJobRun.ProjectID, art.Size); err != nil {
+				log.Warning("postWorkflowJobArtifactWithTempURLCallbackHandler> %v", err)
+			}
+			if err := cost.AddArtifact(api.mustDB(), nodeJobRun.ProjectID, nodeRun, art.Size); err != nil {
+				log.Warning("postWorkflowJobArtifactWithTempURLCallbackHandler> %v", err)
+			}
+		}
+		addArtifactRunResult(api.mustDB(), *nodeRun, jobID, art)
+
+		return nil
+	}
+}
 
+// signArtifact signs the attestation of the checksums of an artifact with its signing key, a PGP key of the project
+func signArtifact(db gorp.SqlExecutor, projectID int64, art *sdk.WorkflowNodeRunArtifact) error {
+	art.Signature = ""
+	if art.SigningKey == "" {
 		return nil
 	}
+	if art.SHA256sum == "" || art.SHA512sum == "" {
+		return sdk.NewErrorFrom(sdk.ErrWrongRequest, "cannot sign artifact %s without its checksums", art.Name)
+	}
+
+	k, err := project.LoadDecryptedKey(db, projectID, art.SigningKey)
+	if err != nil {
+		if sdk.ErrorIs(err, sdk.ErrNotFound) {
+			return sdk.NewErrorFrom(sdk.ErrWrongRequest, "signing key %s not found in the project", art.SigningKey)
+		}
+		return err
+	}
+	if k.Type != sdk.KeyTypePGP {
+		return sdk.NewErrorFrom(sdk.ErrWrongRequest, "signing key %s is not a PGP key", art.SigningKey)
+	}
+
+	signature, err := keys.SignPGP(k.Private, art.Attestation())
+	if err != nil {
+		return sdk.WrapError(err, "cannot sign artifact %s", art.Name)
+	}
+	art.Signature = signature
+	return nil
+}
+
+// attestArtifact returns the provenance of an artifact uploaded by a worker, signed with the key of the instance
+func (api *API) attestArtifact(ctx context.Context, art sdk.WorkflowNodeRunArtifact) (*sdk.DSSEEnvelope, error) {
+	nodeRun, err := workflow.LoadNodeRunByID(api.mustDB(), art.WorkflowNodeRunID, workflow.LoadRunOptions{})
+	if err != nil {
+		return nil, sdk.WrapError(err, "cannot load node run %d", art.WorkflowNodeRunID)
+	}
+	statement := provenance.NewStatement(api.Config.URL.API, art, *nodeRun, getWorker(ctx))
+	env, err := provenance.Sign(statement)
+	if err != nil {
+		return nil, sdk.WrapError(err, "cannot sign provenance of artifact %s", art.Name)
+	}
+	return env, nil
+}
+
+// verifyArtifact checks the signature of an artifact with the public key of its signing key
+func verifyArtifact(db gorp.SqlExecutor, projectID int64, art sdk.WorkflowNodeRunArtifact) sdk.WorkflowNodeRunArtifactVerification {
+	v := sdk.WorkflowNodeRunArtifactVerification{
+		ID:         art.ID,
+		Name:       art.Name,
+		SHA256sum:  art.SHA256sum,
+		SHA512sum:  art.SHA512sum,
+		Signed:     art.Signature != "",
+		SigningKey: art.SigningKey,
+	}
+	if !v.Signed {
+		return v
+	}
+
+	k, err := project.LoadKey(db, projectID, art.SigningKey)
+	if err != nil {
+		v.Error = fmt.Sprintf("signing key %s not found in the project", art.SigningKey)
+		return v
+	}
+	keyID, err := keys.VerifyPGP(k.Public, art.Attestation(), art.Signature)
+	if err != nil {
+		v.Error = fmt.Sprintf("invalid signature with key %s", art.SigningKey)
+		return v
+	}
+	v.KeyID = keyID
+	v.Verified = true
+	return v
 }
@@ -0,0 +1,84 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/ovh/cds/engine/api/application"
+	"github.com/ovh/cds/engine/api/project"
+	"github.com/ovh/cds/engine/api/services"
+	"github.com/ovh/cds/engine/api/workflow"
+	"github.com/ovh/cds/engine/service"
+	"github.com/ovh/cds/sdk"
+)
+
+// getWorkflowJobMirrorHandler streams to a worker the bundle of the mirror of a repository maintained by the
+// repositories service. The worker can only download the mirror of the repository of its job, in the project of its job.
+func (api *API) getWorkflowJobMirrorHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		id, err := requestVarInt(r, "permID")
+		if err != nil {
+			return sdk.WrapError(sdk.ErrInvalidID, "getWorkflowJobMirrorHandler> Invalid node job run ID")
+		}
+
+		if wk := getWorker(ctx); wk == nil || wk.ActionBuildID != id {
+			return sdk.WithStack(sdk.ErrForbidden)
+		}
+
+		job, err := workflow.LoadNodeJobRun(api.mustDB(), api.Cache, id)
+		if err != nil {
+			return sdk.WrapError(err, "cannot load job")
+		}
+
+		url := r.FormValue("url")
+		if url == "" || (url != sdk.ParameterValue(job.Parameters, "git.url") && url != sdk.ParameterValue(job.Parameters, "git.http_url")) {
+			return sdk.NewErrorFrom(sdk.ErrForbidden, "the repository is not the repository of the job")
+		}
+
+		// the mirrors are scoped by project and vcs server, the application of the job must belong to its project
+		proj, err := project.LoadProjectByNodeJobRunID(ctx, api.mustDB(), api.Cache, id, deprecatedGetUser(ctx))
+		if err != nil {
+			return sdk.WrapError(err, "cannot load project by nodeJobRunID:%d", id)
+		}
+		if proj.ID != job.ProjectID {
+			return sdk.WithStack(sdk.ErrForbidden)
+		}
+		nr, err := workflow.LoadNodeRunByNodeJobID(api.mustDB(), id, workflow.LoadRunOptions{DisableDetailledNodeRun: true})
+		if err != nil {
+			return sdk.WrapError(err, "cannot load node run")
+		}
+		if nr.ApplicationID == 0 {
+			return sdk.WrapError(sdk.ErrApplicationNotFound, "there is no application linked")
+		}
+		app, err := application.LoadByID(api.mustDB(), api.Cache, nr.ApplicationID)
+		if err != nil {
+			return sdk.WrapError(err, "cannot load application")
+		}
+		if app.ProjectID != job.ProjectID || app.VCSServer == "" {
+			return sdk.NewErrorFrom(sdk.ErrForbidden, "the repository is not the repository of the job")
+		}
+
+		srvs, err := services.FindByType(api.mustDB(), services.TypeRepositories)
+		if err != nil {
+			return sdk.WrapError(err, "unable to load repositories services")
+		}
+		if len(srvs) == 0 {
+			return sdk.NewErrorFrom(sdk.ErrNotFound, "no repositories service available")
+		}
+
+		body, header, _, err := services.StreamRequest(ctx, srvs, fmt.Sprintf("/mirrors/%s/bundle", sdk.MirrorID(proj.Key, app.VCSServer, url)))
+		if err != nil {
+			return err
+		}
+		defer body.Close()
+
+		w.Header().Set("Content-Type", "application/octet-stream")
+		if l := header.Get("Content-Length"); l != "" {
+			w.Header().Set("Content-Length", l)
+		}
+		_, err = io.Copy(w, body)
+		return sdk.WithStack(err)
+	}
+}
@@ -389,6 +389,14 @@ func Test_postBookWorkflowJobHandler(t *testing.T) {
 	router.Mux.ServeHTTP(rec, req)
 	assert.Equal(t, 200, rec.Code)
 
+	//Confirm the reservation
+	uri = router.GetRoute("POST", api.postConfirmBookWorkflowJobHandler, vars)
+	test.NotEmpty(t, uri)
+
+	req = assets.NewAuthentifiedRequestFromHatchery(t, ctx.hatchery, "POST", uri, nil)
+	rec = httptest.NewRecorder()
+	router.Mux.ServeHTTP(rec, req)
+	assert.Equal(t, 200, rec.Code)
 }
 
 func Test_postWorkflowJobResultHandler(t *testing.T) {
@@ -553,6 +561,7 @@ func Test_postWorkflowJobTestsResultsHandler(t *testing.T) {
 	step := sdk.StepStatus{
 		Status:    sdk.StatusSuccess.String(),
 		StepOrder: 0,
+		Usage:     &sdk.StepResourceUsage{CPUTime: 1.5, MaxMemory: 1 << 20, MaxDisk: 1 << 10},
 	}
 
 	uri = router.GetRoute("POST", api.postWorkflowJobStepStatusHandler, vars)
@@ -565,6 +574,11 @@ func Test_postWorkflowJobTestsResultsHandler(t *testing.T) {
 
 	wNodeJobRun, errJ := workflow.LoadNodeJobRun(api.mustDB(), api.Cache, ctx.job.ID)
 	test.NoError(t, errJ)
+	for _, s := range wNodeJobRun.Job.StepStatus {
+		if s.StepOrder == 0 {
+			assert.Equal(t, step.Usage, s.Usage)
+		}
+	}
 	nodeRun, errN := workflow.LoadNodeRunByID(api.mustDB(), wNodeJobRun.WorkflowNodeRunID, workflow.LoadRunOptions{WithArtifacts: true, WithTests: true})
 	test.NoError(t, errN)
 
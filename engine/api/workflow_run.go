@@ -18,6 +18,7 @@ import (
 	"github.com/ovh/cds/engine/api/feature"
 	"github.com/ovh/cds/engine/api/objectstore"
 	"github.com/ovh/cds/engine/api/observability"
+	"github.com/ovh/cds/engine/api/organization"
 	"github.com/ovh/cds/engine/api/permission"
 	"github.com/ovh/cds/engine/api/project"
 	"github.com/ovh/cds/engine/api/workflow"
@@ -68,7 +69,7 @@ func (api *API) searchWorkflowRun(ctx context.Context, w http.ResponseWriter, r
 
 	//Maximim range is set to 50
 	w.Header().Add("Accept-Range", "run 50")
-	runs, offset, limit, count, err := workflow.LoadRuns(api.mustDB(), key, name, offset, limit, mapFilters)
+	runs, offset, limit, count, err := workflow.LoadRuns(api.mustDBForRead(ctx), key, name, offset, limit, mapFilters)
 	if err != nil {
 		return sdk.WrapError(err, "Unable to load workflow runs")
 	}
@@ -303,6 +304,7 @@ func (api *API) getWorkflowRunHandler() service.Handler {
 			return sdk.WrapError(err, "Unable to load workflow %s run number %d", name, number)
 		}
 		run.Translate(r.Header.Get("Accept-Language"))
+		run.Workflow.MaskHooksSecrets()
 
 		return service.WriteJSON(w, run, http.StatusOK)
 	}
@@ -367,6 +369,13 @@ func (api *API) stopWorkflowRunHandler() service.Handler {
 }
 
 func stopWorkflowRun(ctx context.Context, dbFunc func() *gorp.DbMap, store cache.Store, p *sdk.Project, run *sdk.WorkflowRun, u *sdk.User, parentWorkflowRunID int64) (*workflow.ProcessorReport, error) {
+	spwnMsg := sdk.SpawnMsg{ID: sdk.MsgWorkflowNodeStop.ID, Args: []interface{}{u.Username}}
+	return stopWorkflowRunWithMessage(ctx, dbFunc, store, p, run, u, parentWorkflowRunID, spwnMsg)
+}
+
+// stopWorkflowRunWithMessage stops a run and the runs of the workflows it triggered, the message is added to the infos
+// of the stopped runs. The user is nil when the run is stopped by CDS.
+func stopWorkflowRunWithMessage(ctx context.Context, dbFunc func() *gorp.DbMap, store cache.Store, p *sdk.Project, run *sdk.WorkflowRun, u *sdk.User, parentWorkflowRunID int64, spwnMsg sdk.SpawnMsg) (*workflow.ProcessorReport, error) {
 	report := new(workflow.ProcessorReport)
 
 	tx, errTx := dbFunc().Begin()
@@ -375,8 +384,6 @@ func stopWorkflowRun(ctx context.Context, dbFunc func() *gorp.DbMap, store cache
 	}
 	defer tx.Rollback() //nolint
 
-	spwnMsg := sdk.SpawnMsg{ID: sdk.MsgWorkflowNodeStop.ID, Args: []interface{}{u.Username}}
-
 	stopInfos := sdk.SpawnInfo{
 		APITime:    time.Now(),
 		RemoteTime: time.Now(),
@@ -432,7 +439,7 @@ func stopWorkflowRun(ctx context.Context, dbFunc func() *gorp.DbMap, store cache
 						continue
 					}
 
-					r2, err := stopWorkflowRun(ctx, dbFunc, store, targetProj, targetRun, u, run.ID)
+					r2, err := stopWorkflowRunWithMessage(ctx, dbFunc, store, targetProj, targetRun, u, run.ID, spwnMsg)
 					if err != nil {
 						log.Error("stopWorkflowRun> Unable to stop workflow %v", err)
 						continue
@@ -450,6 +457,10 @@ func stopWorkflowRun(ctx context.Context, dbFunc func() *gorp.DbMap, store cache
 	}
 	report.Add(*run)
 
+	if err := workflow.StoreEvents(tx, p.Key, report); err != nil {
+		return nil, err
+	}
+
 	if err := tx.Commit(); err != nil {
 		return nil, sdk.WrapError(err, "Cannot commit transaction")
 	}
@@ -692,6 +703,10 @@ func (api *API) stopWorkflowNodeRun(ctx context.Context, dbFunc func() *gorp.DbM
 		observability.Record(api.Router.Background, api.Metrics.WorkflowRunFailed, 1)
 	}
 
+	if err := workflow.StoreEvents(tx, p.Key, report); err != nil {
+		return nil, err
+	}
+
 	if errC := tx.Commit(); errC != nil {
 		return nil, sdk.WrapError(errC, "stopWorkflowNodeRunHandler> Unable to commit")
 	}
@@ -738,162 +753,247 @@ func (api *API) getWorkflowNodeRunHandler() service.Handler {
 			return sdk.WrapError(err, "Unable to load last workflow run")
 		}
 
+		run.FlakyTests, err = workflow.LoadNodeRunFlakyTests(api.mustDB(), *run)
+		if err != nil {
+			return err
+		}
+
 		run.Translate(r.Header.Get("Accept-Language"))
 		return service.WriteJSON(w, run, http.StatusOK)
 	}
 }
 
-func (api *API) postWorkflowRunHandler() service.Handler {
+func (api *API) getWorkflowNodeRunChangedFilesHandler() service.Handler {
 	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
 		vars := mux.Vars(r)
 		key := vars["key"]
 		name := vars["permWorkflowName"]
-		u := deprecatedGetUser(ctx)
+		number, err := requestVarInt(r, "number")
+		if err != nil {
+			return err
+		}
+		id, err := requestVarInt(r, "nodeRunID")
+		if err != nil {
+			return err
+		}
 
-		observability.Current(ctx,
-			observability.Tag(observability.TagProjectKey, key),
-			observability.Tag(observability.TagWorkflow, name),
-		)
-		observability.Record(api.Router.Background, api.Metrics.WorkflowRunStarted, 1)
-
-		_, next := observability.Span(ctx, "project.Load")
-		p, errP := project.Load(api.mustDB(), api.Cache, key, u,
-			project.LoadOptions.WithVariables,
-			project.LoadOptions.WithFeatures,
-			project.LoadOptions.WithIntegrations,
-			project.LoadOptions.WithApplicationVariables,
-			project.LoadOptions.WithApplicationWithDeploymentStrategies,
-			project.LoadOptions.WithEnvironments,
-			project.LoadOptions.WithPipelines,
-		)
-		next()
-		if errP != nil {
-			return sdk.WrapError(errP, "postWorkflowRunHandler> Cannot load project")
+		proj, err := project.Load(api.mustDB(), api.Cache, key, deprecatedGetUser(ctx), project.LoadOptions.WithIntegrations)
+		if err != nil {
+			return sdk.WrapError(err, "unable to load project %s", key)
+		}
+
+		wr, err := workflow.LoadRun(api.mustDB(), key, name, number, workflow.LoadRunOptions{})
+		if err != nil {
+			return sdk.WrapError(err, "unable to load workflow run")
+		}
+
+		nodeRun, err := workflow.LoadNodeRun(api.mustDB(), key, name, number, id, workflow.LoadRunOptions{})
+		if err != nil {
+			return sdk.WrapError(err, "unable to load node run")
+		}
+
+		files, err := workflow.NodeRunChangedFiles(ctx, api.mustDB(), api.Cache, proj, wr, nodeRun)
+		if err != nil {
+			return sdk.WrapError(err, "unable to get changed files")
 		}
 
+		return service.WriteJSON(w, files, http.StatusOK)
+	}
+}
+
+func (api *API) postWorkflowRunHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		key := vars["key"]
+		name := vars["permWorkflowName"]
+		u := deprecatedGetUser(ctx)
 		opts := &sdk.WorkflowRunPostHandlerOption{}
 		if err := service.UnmarshalBody(r, opts); err != nil {
 			return err
 		}
 
-		var lastRun *sdk.WorkflowRun
-		var asCodeInfosMsg []sdk.Message
-		if opts.Number != nil {
-			var errlr error
-			_, next := observability.Span(ctx, "workflow.LoadRun")
-			lastRun, errlr = workflow.LoadRun(api.mustDB(), key, name, *opts.Number, workflow.LoadRunOptions{})
-			next()
-			if errlr != nil {
-				return sdk.WrapError(errlr, "postWorkflowRunHandler> Unable to load workflow run")
-			}
-			if err := workflow.MigrateWorkflowRun(ctx, api.mustDB(), lastRun); err != nil {
-				return sdk.WrapError(err, "unable to migrate workflow run")
-			}
+		var wr *sdk.WorkflowRun
+		var err error
+		if opts.Hook != nil && opts.Hook.Payload[sdk.PayloadCommentCommand] != "" {
+			wr, err = api.postWorkflowRunFromComment(ctx, r, key, name, opts.Hook)
+		} else {
+			wr, err = api.postWorkflowRun(ctx, r, key, name, u, opts)
+		}
+		if err != nil {
+			return err
+		}
+		return service.WriteJSON(w, wr, http.StatusAccepted)
+	}
+}
+
+// postWorkflowRun starts a run of a workflow, from a hook event or manually
+func (api *API) postWorkflowRun(ctx context.Context, r *http.Request, key, name string, u *sdk.User, opts *sdk.WorkflowRunPostHandlerOption) (*sdk.WorkflowRun, error) {
+	observability.Current(ctx,
+		observability.Tag(observability.TagProjectKey, key),
+		observability.Tag(observability.TagWorkflow, name),
+	)
+	observability.Record(api.Router.Background, api.Metrics.WorkflowRunStarted, 1)
+
+	_, next := observability.Span(ctx, "project.Load")
+	p, errP := project.Load(api.mustDB(), api.Cache, key, u,
+		project.LoadOptions.WithVariables,
+		project.LoadOptions.WithFeatures,
+		project.LoadOptions.WithIntegrations,
+		project.LoadOptions.WithApplicationVariables,
+		project.LoadOptions.WithApplicationWithDeploymentStrategies,
+		project.LoadOptions.WithEnvironments,
+		project.LoadOptions.WithPipelines,
+	)
+	next()
+	if errP != nil {
+		return nil, sdk.WrapError(errP, "postWorkflowRunHandler> Cannot load project")
+	}
+	if err := organization.CheckBuildQuota(api.mustDB(), p.OrganizationID); err != nil {
+		return nil, err
+	}
+
+	if opts.Priority != nil {
+		if !sdk.WorkflowRunPriorityValidate(*opts.Priority) {
+			return nil, sdk.NewErrorFrom(sdk.ErrWrongRequest, "invalid priority %d", *opts.Priority)
 		}
+		if *opts.Priority == sdk.WorkflowRunPriorityUrgent && !deprecatedGetUser(ctx).Admin {
+			return nil, sdk.NewErrorFrom(sdk.ErrForbidden, "only an administrator can start a run with urgent priority")
+		}
+	}
 
-		var wf *sdk.Workflow
-		if lastRun != nil {
-			wf = &lastRun.Workflow
-			// Check workflow name in case of rename
-			if wf.Name != name {
-				wf.Name = name
+	var lastRun *sdk.WorkflowRun
+	var asCodeInfosMsg []sdk.Message
+	if opts.Number != nil {
+		var errlr error
+		_, next := observability.Span(ctx, "workflow.LoadRun")
+		lastRun, errlr = workflow.LoadRun(api.mustDB(), key, name, *opts.Number, workflow.LoadRunOptions{})
+		next()
+		if errlr != nil {
+			return nil, sdk.WrapError(errlr, "postWorkflowRunHandler> Unable to load workflow run")
+		}
+		if err := workflow.MigrateWorkflowRun(ctx, api.mustDB(), lastRun); err != nil {
+			return nil, sdk.WrapError(err, "unable to migrate workflow run")
+		}
+	}
+
+	var wf *sdk.Workflow
+	if lastRun != nil {
+		wf = &lastRun.Workflow
+		// Check workflow name in case of rename
+		if wf.Name != name {
+			wf.Name = name
+		}
+	} else {
+		// Test workflow as code or not
+		options := workflow.LoadOptions{
+			OnlyRootNode:          true,
+			DeepPipeline:          false,
+			Base64Keys:            true,
+			WithAsCodeUpdateEvent: true,
+		}
+		var errW error
+		wf, errW = workflow.Load(ctx, api.mustDB(), api.Cache, p, name, u, options)
+		if errW != nil {
+			return nil, sdk.WrapError(errW, "postWorkflowRunHandler> Unable to load workflow %s", name)
+		}
+
+		// The runs of the pull requests from forks follow the fork policy of the stored workflow,
+		// a pull request can not change it in the workflow as code
+		forkPolicy := wf.ForkPolicy
+		if err := workflow.CheckForkApproval(api.mustDB(), wf, opts.Hook); err != nil {
+			return nil, err
+		}
+
+		enabled, has := p.Features[feature.FeatWorkflowAsCode]
+
+		// Check if workflow has to become as code
+		if wf.FromRepository == "" && len(wf.AsCodeEvent) > 0 {
+			tx, err := api.mustDB().Begin()
+			if err != nil {
+				return nil, sdk.WrapError(err, "unable to start transaction")
 			}
-		} else {
-			// Test workflow as code or not
-			options := workflow.LoadOptions{
-				OnlyRootNode:          true,
-				DeepPipeline:          false,
-				Base64Keys:            true,
-				WithAsCodeUpdateEvent: true,
+			if err := workflow.SyncAsCodeEvent(ctx, tx, api.Cache, p, wf); err != nil {
+				tx.Rollback() // nolint
+				return nil, err
 			}
-			var errW error
-			wf, errW = workflow.Load(ctx, api.mustDB(), api.Cache, p, name, u, options)
-			if errW != nil {
-				return sdk.WrapError(errW, "postWorkflowRunHandler> Unable to load workflow %s", name)
+			if err := tx.Commit(); err != nil {
+				return nil, sdk.WrapError(err, "unable to commit transaction")
 			}
+		}
 
-			enabled, has := p.Features[feature.FeatWorkflowAsCode]
-
-			// Check if workflow has to become as code
-			if wf.FromRepository == "" && len(wf.AsCodeEvent) > 0 {
-				tx, err := api.mustDB().Begin()
-				if err != nil {
-					return sdk.WrapError(err, "unable to start transaction")
-				}
-				if err := workflow.SyncAsCodeEvent(ctx, tx, api.Cache, p, wf); err != nil {
-					tx.Rollback() // nolint
-					return err
-				}
-				if err := tx.Commit(); err != nil {
-					return sdk.WrapError(err, "unable to commit transaction")
-				}
+		if wf.FromRepository != "" {
+			if has && !enabled {
+				return nil, sdk.WrapError(sdk.ErrForbidden, "postWorkflowRunHandler> %s not allowed for project %s", feature.FeatWorkflowAsCode, p.Key)
 			}
+			proj, errp := project.Load(api.mustDB(), api.Cache, key, u,
+				project.LoadOptions.WithGroups,
+				project.LoadOptions.WithApplicationVariables,
+				project.LoadOptions.WithApplicationWithDeploymentStrategies,
+				project.LoadOptions.WithEnvironments,
+				project.LoadOptions.WithPipelines,
+				project.LoadOptions.WithClearKeys,
+				project.LoadOptions.WithClearIntegrations,
+			)
 
-			if wf.FromRepository != "" {
-				if has && !enabled {
-					return sdk.WrapError(sdk.ErrForbidden, "postWorkflowRunHandler> %s not allowed for project %s", feature.FeatWorkflowAsCode, p.Key)
-				}
-				proj, errp := project.Load(api.mustDB(), api.Cache, key, u,
-					project.LoadOptions.WithGroups,
-					project.LoadOptions.WithApplicationVariables,
-					project.LoadOptions.WithApplicationWithDeploymentStrategies,
-					project.LoadOptions.WithEnvironments,
-					project.LoadOptions.WithPipelines,
-					project.LoadOptions.WithClearKeys,
-					project.LoadOptions.WithClearIntegrations,
-				)
-
-				if errp != nil {
-					return sdk.WrapError(errp, "postWorkflowRunHandler> Cannot load project %s", key)
-				}
-				// Get workflow from repository
-				var errCreate error
-				asCodeInfosMsg, errCreate = workflow.CreateFromRepository(ctx, api.mustDB(), api.Cache, proj, wf, *opts, u, project.DecryptWithBuiltinKey)
-				if errCreate != nil {
-					var msgListString string
-					if len(asCodeInfosMsg) > 0 {
-						msgListString = strings.Join(translate(r, asCodeInfosMsg), " ")
-					}
-					return sdk.WrapError(errCreate, "postWorkflowRunHandler> Unable to get workflow from repository.%s", msgListString)
-				}
-			} else {
-				var errl error
-				options := workflow.LoadOptions{
-					DeepPipeline: true,
-					Base64Keys:   true,
-				}
-				wf, errl = workflow.Load(ctx, api.mustDB(), api.Cache, p, name, u, options)
-				if errl != nil {
-					return sdk.WrapError(errl, "postWorkflowRunHandler> Unable to load workflow %s/%s", key, name)
+			if errp != nil {
+				return nil, sdk.WrapError(errp, "postWorkflowRunHandler> Cannot load project %s", key)
+			}
+			// Get workflow from repository
+			var errCreate error
+			asCodeInfosMsg, errCreate = workflow.CreateFromRepository(ctx, api.mustDB(), api.Cache, proj, wf, *opts, u, project.DecryptWithBuiltinKey)
+			if errCreate != nil {
+				var msgListString string
+				if len(asCodeInfosMsg) > 0 {
+					msgListString = strings.Join(translate(r, asCodeInfosMsg), " ")
 				}
+				return nil, sdk.WrapError(errCreate, "postWorkflowRunHandler> Unable to get workflow from repository.%s", msgListString)
+			}
+			wf.ForkPolicy = forkPolicy
+		} else {
+			var errl error
+			options := workflow.LoadOptions{
+				DeepPipeline: true,
+				Base64Keys:   true,
+			}
+			wf, errl = workflow.Load(ctx, api.mustDB(), api.Cache, p, name, u, options)
+			if errl != nil {
+				return nil, sdk.WrapError(errl, "postWorkflowRunHandler> Unable to load workflow %s/%s", key, name)
 			}
 		}
-		if name != wf.Name {
-			return sdk.WrapError(sdk.ErrWorkflowInvalid, "workflow %s asked, but workflow %s found", name, wf.Name)
-		}
+	}
+	if name != wf.Name {
+		return nil, sdk.WrapError(sdk.ErrWorkflowInvalid, "workflow %s asked, but workflow %s found", name, wf.Name)
+	}
 
-		report, errS := startWorkflowRun(ctx, api.mustDB(), api.Cache, p, wf, lastRun, opts, u, asCodeInfosMsg)
+	report, errS := startWorkflowRun(ctx, api.mustDB(), api.Cache, p, wf, lastRun, opts, u, asCodeInfosMsg)
 
-		if errS != nil {
-			return sdk.WrapError(errS, "postWorkflowRunHandler> Unable to start workflow %s/%s", key, name)
+	if errS != nil {
+		return nil, sdk.WrapError(errS, "postWorkflowRunHandler> Unable to start workflow %s/%s", key, name)
+	}
+	workflow.ResyncNodeRunsWithCommits(api.mustDB(), api.Cache, p, report)
+	go workflow.SendEvent(api.mustDB(), p.Key, report)
+
+	// Purge workflow run
+	sdk.GoRoutine(ctx, "workflow.PurgeWorkflowRun", func(ctx context.Context) {
+		if err := workflow.PurgeWorkflowRun(ctx, api.mustDB(), *wf, api.Metrics.WorkflowRunsMarkToDelete); err != nil {
+			log.Error("workflow.PurgeWorkflowRun> error %v", err)
 		}
-		workflow.ResyncNodeRunsWithCommits(api.mustDB(), api.Cache, p, report)
-		go workflow.SendEvent(api.mustDB(), p.Key, report)
+	}, api.PanicDump())
 
-		// Purge workflow run
-		sdk.GoRoutine(ctx, "workflow.PurgeWorkflowRun", func(ctx context.Context) {
-			if err := workflow.PurgeWorkflowRun(ctx, api.mustDB(), *wf, api.Metrics.WorkflowRunsMarkToDelete); err != nil {
-				log.Error("workflow.PurgeWorkflowRun> error %v", err)
-			}
-		}, api.PanicDump())
+	var wr *sdk.WorkflowRun
+	if len(report.WorkflowRuns()) > 0 {
+		wr = &report.WorkflowRuns()[0]
+		wr.Translate(r.Header.Get("Accept-Language"))
+	}
 
-		var wr *sdk.WorkflowRun
-		if len(report.WorkflowRuns()) > 0 {
-			wr = &report.WorkflowRuns()[0]
-			wr.Translate(r.Header.Get("Accept-Language"))
-		}
-		return service.WriteJSON(w, wr, http.StatusAccepted)
+	// A new commit on the branch of a pull request supersedes the runs of the previous commits
+	if wr != nil && opts.Hook != nil && wf.AutoCancel && sdk.IsPullRequest(opts.Hook.Payload) {
+		superseding := *wr
+		sdk.GoRoutine(api.Router.Background, "cancelSupersededRuns", func(ctx context.Context) {
+			api.cancelSupersededRuns(ctx, p, superseding)
+		}, api.PanicDump())
 	}
+	return wr, nil
 }
 
 func startWorkflowRun(ctx context.Context, db *gorp.DbMap, store cache.Store, p *sdk.Project, wf *sdk.Workflow, lastRun *sdk.WorkflowRun, opts *sdk.WorkflowRunPostHandlerOption, u *sdk.User, asCodeInfos []sdk.Message) (*workflow.ProcessorReport, error) {
@@ -910,10 +1010,16 @@ func startWorkflowRun(ctx context.Context, db *gorp.DbMap, store cache.Store, p
 
 	// Run from HOOK
 	if opts.Hook != nil {
-		_, r1, err := workflow.RunFromHook(ctx, tx, store, p, wf, opts.Hook, asCodeInfos)
+		wr, r1, err := workflow.RunFromHook(ctx, tx, store, p, wf, opts.Hook, asCodeInfos)
 		if err != nil {
 			return nil, sdk.WrapError(err, "Unable to run workflow from hook")
 		}
+		if err := setWorkflowRunPriority(tx, wr, opts.Priority); err != nil {
+			return nil, err
+		}
+		if err := workflow.StoreEvents(tx, p.Key, r1); err != nil {
+			return nil, err
+		}
 
 		//Commit and return success
 		if err := tx.Commit(); err != nil {
@@ -940,10 +1046,13 @@ func startWorkflowRun(ctx context.Context, db *gorp.DbMap, store cache.Store, p
 		}
 
 		// Continue  the current workflow run
-		_, r1, errmr := workflow.ManualRunFromNode(ctx, tx, store, p, wf, lastRun.Number, opts.Manual, fromNode.ID)
+		wr, r1, errmr := workflow.ManualRunFromNode(ctx, tx, store, p, wf, lastRun.Number, opts.Manual, fromNode.ID)
 		if errmr != nil {
 			return nil, sdk.WrapError(errmr, "Unable to run workflow")
 		}
+		if err := setWorkflowRunPriority(tx, wr, opts.Priority); err != nil {
+			return nil, err
+		}
 		_, _ = report.Merge(r1, nil)
 
 	} else {
@@ -952,13 +1061,20 @@ func startWorkflowRun(ctx context.Context, db *gorp.DbMap, store cache.Store, p
 		}
 
 		// Start new workflow
-		_, r1, errmr := workflow.ManualRun(ctx, tx, store, p, wf, opts.Manual, asCodeInfos)
+		wr, r1, errmr := workflow.ManualRun(ctx, tx, store, p, wf, opts.Manual, asCodeInfos)
 		if errmr != nil {
 			return nil, sdk.WrapError(errmr, "Unable to run workflow")
 		}
+		if err := setWorkflowRunPriority(tx, wr, opts.Priority); err != nil {
+			return nil, err
+		}
 		_, _ = report.Merge(r1, nil)
 	}
 
+	if err := workflow.StoreEvents(tx, p.Key, report); err != nil {
+		return nil, err
+	}
+
 	//Commit and return success
 	if err := tx.Commit(); err != nil {
 		return nil, sdk.WrapError(err, "Unable to commit transaction")
@@ -966,6 +1082,48 @@ func startWorkflowRun(ctx context.Context, db *gorp.DbMap, store cache.Store, p
 	return report, nil
 }
 
+// setWorkflowRunPriority sets the priority asked when starting the run, the jobs are already queued in the transaction
+func setWorkflowRunPriority(db gorp.SqlExecutor, wr *sdk.WorkflowRun, priority *int) error {
+	if priority == nil || wr == nil || wr.Priority == *priority {
+		return nil
+	}
+	return workflow.UpdateWorkflowRunPriority(db, wr, *priority)
+}
+
+func (api *API) putWorkflowRunPriorityHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		key := vars["key"]
+		name := vars["permWorkflowName"]
+		number, err := requestVarInt(r, "number")
+		if err != nil {
+			return err
+		}
+
+		var p sdk.WorkflowRunPriority
+		if err := service.UnmarshalBody(r, &p); err != nil {
+			return err
+		}
+		if !sdk.WorkflowRunPriorityValidate(p.Priority) {
+			return sdk.NewErrorFrom(sdk.ErrWrongRequest, "invalid priority %d", p.Priority)
+		}
+
+		wr, err := workflow.LoadRun(api.mustDB(), key, name, number, workflow.LoadRunOptions{DisableDetailledNodeRun: true})
+		if err != nil {
+			return sdk.WrapError(err, "unable to load workflow run %s/%s #%d", key, name, number)
+		}
+		if sdk.StatusIsTerminated(wr.Status) {
+			return sdk.NewErrorFrom(sdk.ErrWrongRequest, "workflow run #%d is %s", number, wr.Status)
+		}
+
+		if err := workflow.UpdateWorkflowRunPriority(api.mustDB(), wr, p.Priority); err != nil {
+			return err
+		}
+		log.Info("putWorkflowRunPriorityHandler> priority of %s/%s #%d set to %s by %s", key, name, number, sdk.WorkflowRunPriorityName(p.Priority), deprecatedGetUser(ctx).Username)
+		return service.WriteJSON(w, wr, http.StatusOK)
+	}
+}
+
 func (api *API) downloadworkflowArtifactDirectHandler() service.Handler {
 	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
 		vars := mux.Vars(r)
@@ -978,6 +1136,15 @@ func (api *API) downloadworkflowArtifactDirectHandler() service.Handler {
 
 		w.Header().Add("Content-Type", "application/octet-stream")
 		w.Header().Add("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", art.Name))
+		if art.SHA256sum != "" {
+			w.Header().Add("X-Artifact-Sha256sum", art.SHA256sum)
+		}
+		if art.SHA512sum != "" {
+			w.Header().Add("X-Artifact-Sha512sum", art.SHA512sum)
+		}
+		if art.SigningKey != "" {
+			w.Header().Add("X-Artifact-Signing-Key", art.SigningKey)
+		}
 
 		f, err := objectstore.Fetch(art)
 		if err != nil {
@@ -1027,6 +1194,15 @@ func (api *API) getDownloadArtifactHandler() service.Handler {
 
 		w.Header().Add("Content-Type", "application/octet-stream")
 		w.Header().Add("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", art.Name))
+		if art.SHA256sum != "" {
+			w.Header().Add("X-Artifact-Sha256sum", art.SHA256sum)
+		}
+		if art.SHA512sum != "" {
+			w.Header().Add("X-Artifact-Sha512sum", art.SHA512sum)
+		}
+		if art.SigningKey != "" {
+			w.Header().Add("X-Artifact-Signing-Key", art.SigningKey)
+		}
 
 		f, err := objectstore.Fetch(art)
 		if err != nil {
@@ -1046,6 +1222,72 @@ func (api *API) getDownloadArtifactHandler() service.Handler {
 	}
 }
 
+func (api *API) getVerifyArtifactHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		key := vars["key"]
+		name := vars["permWorkflowName"]
+
+		id, errI := requestVarInt(r, "artifactId")
+		if errI != nil {
+			return sdk.WrapError(sdk.ErrInvalidID, "getVerifyArtifactHandler> Invalid artifact ID")
+		}
+
+		proj, err := project.Load(api.mustDB(), api.Cache, key, deprecatedGetUser(ctx))
+		if err != nil {
+			return sdk.WrapError(err, "unable to load projet")
+		}
+
+		work, err := workflow.Load(ctx, api.mustDB(), api.Cache, proj, name, deprecatedGetUser(ctx), workflow.LoadOptions{WithoutNode: true})
+		if err != nil {
+			return sdk.WrapError(err, "cannot load workflow")
+		}
+
+		art, err := workflow.LoadArtifactByIDs(api.mustDB(), work.ID, id)
+		if err != nil {
+			return sdk.WrapError(err, "cannot load artifact")
+		}
+
+		return service.WriteJSON(w, verifyArtifact(api.mustDB(), proj.ID, *art), http.StatusOK)
+	}
+}
+
+func (api *API) getArtifactProvenanceHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		key := vars["key"]
+		name := vars["permWorkflowName"]
+
+		id, errI := requestVarInt(r, "artifactId")
+		if errI != nil {
+			return sdk.WrapError(sdk.ErrInvalidID, "getArtifactProvenanceHandler> Invalid artifact ID")
+		}
+
+		proj, err := project.Load(api.mustDB(), api.Cache, key, deprecatedGetUser(ctx))
+		if err != nil {
+			return sdk.WrapError(err, "unable to load projet")
+		}
+
+		work, err := workflow.Load(ctx, api.mustDB(), api.Cache, proj, name, deprecatedGetUser(ctx), workflow.LoadOptions{WithoutNode: true})
+		if err != nil {
+			return sdk.WrapError(err, "cannot load workflow")
+		}
+
+		art, err := workflow.LoadArtifactByIDs(api.mustDB(), work.ID, id)
+		if err != nil {
+			return sdk.WrapError(err, "cannot load artifact")
+		}
+
+		env, err := workflow.LoadArtifactProvenance(api.mustDB(), art.ID)
+		if err != nil {
+			return sdk.WrapError(err, "cannot load provenance of artifact %s", art.Name)
+		}
+
+		w.Header().Add("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s.intoto.jsonl\"", art.Name))
+		return service.WriteJSON(w, env, http.StatusOK)
+	}
+}
+
 func (api *API) getWorkflowRunArtifactsHandler() service.Handler {
 	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
 		vars := mux.Vars(r)
@@ -1158,25 +1400,7 @@ func (api *API) getWorkflowNodeRunJobStepHandler() service.Handler {
 			return sdk.WrapError(errNR, "getWorkflowNodeRunJobBuildLogsHandler> Cannot find nodeRun %d/%d for workflow %s in project %s", nodeRunID, number, workflowName, projectKey)
 		}
 
-		var stepStatus string
-		// Find job/step in nodeRun
-	stageLoop:
-		for _, s := range nodeRun.Stages {
-			for _, rj := range s.RunJobs {
-				if rj.ID != runJobID {
-					continue
-				}
-				ss := rj.Job.StepStatus
-				for _, sss := range ss {
-					if int64(sss.StepOrder) == stepOrder {
-						stepStatus = sss.Status
-						break
-					}
-				}
-				break stageLoop
-			}
-		}
-
+		stepStatus := nodeRunStepStatus(nodeRun, runJobID, stepOrder)
 		if stepStatus == "" {
 			return sdk.WrapError(sdk.ErrStepNotFound, "getWorkflowNodeRunJobStepHandler> Cannot find step %d on job %d in nodeRun %d/%d for workflow %s in project %s",
 				stepOrder, runJobID, nodeRunID, number, workflowName, projectKey)
@@ -1200,6 +1424,24 @@ func (api *API) getWorkflowNodeRunJobStepHandler() service.Handler {
 	}
 }
 
+// nodeRunStepStatus returns the status of a step of a job of the node run, or an empty string if the step is not found
+func nodeRunStepStatus(nodeRun *sdk.WorkflowNodeRun, runJobID, stepOrder int64) string {
+	for _, s := range nodeRun.Stages {
+		for _, rj := range s.RunJobs {
+			if rj.ID != runJobID {
+				continue
+			}
+			for _, ss := range rj.Job.StepStatus {
+				if int64(ss.StepOrder) == stepOrder {
+					return ss.Status
+				}
+			}
+			return ""
+		}
+	}
+	return ""
+}
+
 func (api *API) getWorkflowRunTagsHandler() service.Handler {
 	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
 		vars := mux.Vars(r)
@@ -0,0 +1,55 @@
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/ovh/cds/engine/api/workflow"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// cancelSupersededRuns stops the runs of a pull request which are still running on the previous commits of its branch.
+// It is called for the runs of the pull requests of the workflows with the auto cancel enabled.
+func (api *API) cancelSupersededRuns(ctx context.Context, p *sdk.Project, wr sdk.WorkflowRun) {
+	nums, err := workflow.LoadSupersededRunNumbers(api.mustDB(), &wr)
+	if err != nil {
+		log.Error("cancelSupersededRuns> %v", err)
+		return
+	}
+
+	var hash string
+	for _, t := range wr.Tags {
+		if t.Tag == "git.hash" {
+			hash = t.Value
+		}
+	}
+	msg := sdk.SpawnMsg{ID: sdk.MsgWorkflowRunSuperseded.ID, Args: []interface{}{wr.Number, hash}}
+
+	for _, n := range nums {
+		run, err := workflow.LoadRun(api.mustDB(), p.Key, wr.Workflow.Name, n, workflow.LoadRunOptions{})
+		if err != nil {
+			log.Error("cancelSupersededRuns> unable to load run %s/%s#%d: %v", p.Key, wr.Workflow.Name, n, err)
+			continue
+		}
+
+		report, err := stopWorkflowRunWithMessage(ctx, api.mustDB, api.Cache, p, run, nil, 0, msg)
+		if err != nil {
+			log.Error("cancelSupersededRuns> unable to stop run %s/%s#%d: %v", p.Key, wr.Workflow.Name, n, err)
+			continue
+		}
+		log.Info("cancelSupersededRuns> run %s/%s#%d superseded by run #%d", p.Key, wr.Workflow.Name, n, wr.Number)
+		go workflow.SendEvent(api.mustDB(), p.Key, report)
+
+		// the status of the previous commit is updated on the repositories manager
+		stopped, err := workflow.LoadRunByID(api.mustDB(), run.ID, workflow.LoadRunOptions{DisableDetailledNodeRun: true})
+		if err != nil {
+			log.Error("cancelSupersededRuns> unable to load run %d for resync commit status: %v", run.ID, err)
+			continue
+		}
+		stopped.LastExecution = time.Now()
+		if err := workflow.ResyncCommitStatus(ctx, api.mustDB(), api.Cache, p, stopped); err != nil {
+			log.Error("cancelSupersededRuns> %v", err)
+		}
+	}
+}
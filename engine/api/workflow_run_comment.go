@@ -0,0 +1,146 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/ovh/cds/engine/api/permission"
+	"github.com/ovh/cds/engine/api/project"
+	"github.com/ovh/cds/engine/api/repositoriesmanager"
+	"github.com/ovh/cds/engine/api/user"
+	"github.com/ovh/cds/engine/api/workflow"
+	"github.com/ovh/cds/sdk"
+)
+
+// postWorkflowRunFromComment runs the command written in a comment of a pull request and received by a repository webhook.
+// The command is run on behalf of the user linked to the author of the comment, who must be allowed to run the workflow.
+func (api *API) postWorkflowRunFromComment(ctx context.Context, r *http.Request, key, name string, hook *sdk.WorkflowNodeRunHookEvent) (*sdk.WorkflowRun, error) {
+	if getService(ctx) == nil {
+		return nil, sdk.NewErrorFrom(sdk.ErrForbidden, "the commands of the comments are only sent by the hooks service")
+	}
+
+	h, err := workflow.LoadHookByUUID(api.mustDB(), hook.WorkflowNodeHookUUID)
+	if err != nil {
+		return nil, sdk.WrapError(err, "cannot load hook")
+	}
+	if h == nil {
+		return nil, sdk.WithStack(sdk.ErrNotFound)
+	}
+	vcsServer := h.Config["vcsServer"].Value
+
+	u, err := user.LoadUserByVCSAccount(api.mustDB(), vcsServer, hook.Payload[sdk.PayloadCommentAuthor])
+	if err != nil {
+		return nil, err
+	}
+	if u.Deactivated {
+		return nil, sdk.NewErrorFrom(sdk.ErrForbidden, "the user %s is deactivated", u.Username)
+	}
+	if err := loadUserPermissions(api.mustDB(), api.Cache, u); err != nil {
+		return nil, err
+	}
+	if !permission.AccessToWorkflow(key, name, u, permission.PermissionReadExecute) {
+		return nil, sdk.NewErrorFrom(sdk.ErrNoPermExecution, "%s is not allowed to run the workflow %s/%s", u.Username, key, name)
+	}
+
+	// Github does not send the branch of the pull request with the comments
+	if hook.Payload["git.branch"] == "" {
+		if err := api.resolveCommentPullRequest(ctx, key, vcsServer, h.Config["repoFullName"].Value, hook.Payload); err != nil {
+			return nil, err
+		}
+	}
+	hook.Payload["cds.triggered_by.username"] = u.Username
+	hook.Payload["cds.triggered_by.fullname"] = u.Fullname
+	hook.Payload["cds.triggered_by.email"] = u.Email
+
+	switch hook.Payload[sdk.PayloadCommentCommand] {
+	case sdk.CommentCommandRun:
+		return api.postWorkflowRun(ctx, r, key, name, u, &sdk.WorkflowRunPostHandlerOption{Hook: hook})
+	case sdk.CommentCommandRetry:
+		branch := hook.Payload["git.branch"]
+		runs, _, _, _, err := workflow.LoadRuns(api.mustDB(), key, name, 0, 1, map[string]string{"git.branch": branch})
+		if err != nil {
+			return nil, sdk.WrapError(err, "cannot load the last run of branch %s", branch)
+		}
+		if len(runs) == 0 {
+			return nil, sdk.NewErrorFrom(sdk.ErrNotFound, "no run of the workflow %s/%s for the branch %s", key, name, branch)
+		}
+		wr, err := workflow.LoadRun(api.mustDB(), key, name, runs[0].Number, workflow.LoadRunOptions{})
+		if err != nil {
+			return nil, sdk.WrapError(err, "cannot load run %d", runs[0].Number)
+		}
+		if err := workflow.MigrateWorkflowRun(ctx, api.mustDB(), wr); err != nil {
+			return nil, sdk.WrapError(err, "unable to migrate workflow run")
+		}
+		nodeID, err := retryNodeID(wr, hook.Payload[sdk.PayloadCommentNode])
+		if err != nil {
+			return nil, err
+		}
+		return api.postWorkflowRun(ctx, r, key, name, u, &sdk.WorkflowRunPostHandlerOption{
+			Number:      &wr.Number,
+			FromNodeIDs: []int64{nodeID},
+			Manual:      &sdk.WorkflowNodeRunManual{},
+		})
+	}
+	return nil, sdk.NewErrorFrom(sdk.ErrWrongRequest, "unknown command %s", hook.Payload[sdk.PayloadCommentCommand])
+}
+
+// resolveCommentPullRequest adds to the payload of a comment the branch and the commit of its pull request
+func (api *API) resolveCommentPullRequest(ctx context.Context, key, vcsServerName, repository string, payload map[string]string) error {
+	id, err := strconv.Atoi(payload[sdk.PayloadPullRequestID])
+	if err != nil {
+		return sdk.NewErrorFrom(sdk.ErrWrongRequest, "invalid pull request %s", payload[sdk.PayloadPullRequestID])
+	}
+
+	proj, err := project.Load(api.mustDB(), api.Cache, key, nil)
+	if err != nil {
+		return sdk.WrapError(err, "cannot load project %s", key)
+	}
+	vcsServer := repositoriesmanager.GetProjectVCSServer(proj, vcsServerName)
+	if vcsServer == nil {
+		return sdk.NewErrorFrom(sdk.ErrNoReposManager, "repositories manager %s not found on project %s", vcsServerName, key)
+	}
+	client, err := repositoriesmanager.AuthorizedClient(ctx, api.mustDB(), api.Cache, vcsServer)
+	if err != nil {
+		return sdk.WrapError(err, "cannot get client for %s", vcsServerName)
+	}
+
+	pr, err := client.PullRequest(ctx, repository, id)
+	if err != nil {
+		return sdk.WrapError(err, "cannot load pull request %d of %s", id, repository)
+	}
+	payload["git.branch"] = pr.Head.Branch.DisplayID
+	payload["git.hash"] = pr.Head.Commit.Hash
+	payload["git.repository"] = pr.Head.Repo
+	payload[sdk.PayloadPullRequestBaseRepository] = pr.Base.Repo
+	payload[sdk.PayloadPullRequestFork] = strconv.FormatBool(pr.Head.Repo != "" && pr.Base.Repo != "" && pr.Head.Repo != pr.Base.Repo)
+	return nil
+}
+
+// retryNodeID returns the node to restart in a run: the given node, or the first failed node
+func retryNodeID(wr *sdk.WorkflowRun, nodeName string) (int64, error) {
+	if nodeName != "" {
+		n := wr.Workflow.WorkflowData.NodeByName(nodeName)
+		if n == nil {
+			return 0, sdk.NewErrorFrom(sdk.ErrWorkflowNodeNotFound, "node %s not found in run %d", nodeName, wr.Number)
+		}
+		return n.ID, nil
+	}
+
+	var failed *sdk.WorkflowNodeRun
+	for _, nrs := range wr.WorkflowNodeRuns {
+		if len(nrs) == 0 {
+			continue
+		}
+		// the node runs are sorted by descending subnumber
+		if nr := nrs[0]; nr.Status == sdk.StatusFail.String() || nr.Status == sdk.StatusStopped.String() {
+			if failed == nil || nr.ID < failed.ID {
+				failed = &nrs[0]
+			}
+		}
+	}
+	if failed == nil {
+		return 0, sdk.NewErrorFrom(sdk.ErrNotFound, "no failed node in run %d", wr.Number)
+	}
+	return failed.WorkflowNodeID, nil
+}
@@ -0,0 +1,43 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func Test_retryNodeID(t *testing.T) {
+	wr := &sdk.WorkflowRun{
+		Number: 3,
+		Workflow: sdk.Workflow{
+			WorkflowData: &sdk.WorkflowData{
+				Node: sdk.Node{ID: 1, Name: "build", Triggers: []sdk.NodeTrigger{
+					{ChildNode: sdk.Node{ID: 2, Name: "test"}},
+					{ChildNode: sdk.Node{ID: 3, Name: "lint"}},
+				}},
+			},
+		},
+		WorkflowNodeRuns: map[int64][]sdk.WorkflowNodeRun{
+			1: {{ID: 10, WorkflowNodeID: 1, Status: sdk.StatusSuccess.String()}},
+			2: {{ID: 14, WorkflowNodeID: 2, SubNumber: 1, Status: sdk.StatusSuccess.String()}, {ID: 11, WorkflowNodeID: 2, Status: sdk.StatusFail.String()}},
+			3: {{ID: 12, WorkflowNodeID: 3, Status: sdk.StatusFail.String()}},
+		},
+	}
+
+	id, err := retryNodeID(wr, "")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), id, "the node test succeeded on its retry")
+
+	id, err = retryNodeID(wr, "test")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), id)
+
+	_, err = retryNodeID(wr, "deploy")
+	assert.Error(t, err)
+
+	wr.WorkflowNodeRuns[3][0].Status = sdk.StatusSuccess.String()
+	_, err = retryNodeID(wr, "")
+	assert.Error(t, err)
+}
@@ -0,0 +1,45 @@
+package api
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/ovh/cds/engine/api/workflow"
+	"github.com/ovh/cds/engine/service"
+	"github.com/ovh/cds/sdk"
+)
+
+// getWorkflowRunsCompareHandler returns the differences between two runs of a workflow
+func (api *API) getWorkflowRunsCompareHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		key := vars["key"]
+		name := vars["permWorkflowName"]
+
+		from, err := FormInt(r, "from")
+		if err != nil {
+			return err
+		}
+		to, err := FormInt(r, "to")
+		if err != nil {
+			return err
+		}
+		if from <= 0 || to <= 0 {
+			return sdk.NewErrorFrom(sdk.ErrWrongRequest, "from and to run numbers are mandatory")
+		}
+
+		opts := workflow.LoadRunOptions{WithArtifacts: true}
+		fromRun, err := workflow.LoadRun(api.mustDB(), key, name, int64(from), opts)
+		if err != nil {
+			return sdk.WrapError(err, "cannot load workflow run %d", from)
+		}
+		toRun, err := workflow.LoadRun(api.mustDB(), key, name, int64(to), opts)
+		if err != nil {
+			return sdk.WrapError(err, "cannot load workflow run %d", to)
+		}
+
+		return service.WriteJSON(w, sdk.CompareWorkflowRuns(*fromRun, *toRun), http.StatusOK)
+	}
+}
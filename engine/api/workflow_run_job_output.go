@@ -0,0 +1,77 @@
+package api
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/ovh/cds/engine/api/workflow"
+	"github.com/ovh/cds/engine/service"
+	"github.com/ovh/cds/sdk"
+)
+
+// postWorkflowJobOutputHandler records an output set by a job with `worker output set`
+func (api *API) postWorkflowJobOutputHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		id, err := requestVarInt(r, "permID")
+		if err != nil {
+			return sdk.WrapError(sdk.ErrInvalidID, "postWorkflowJobOutputHandler> Invalid node job run ID")
+		}
+
+		var output sdk.WorkflowRunJobOutput
+		if err := service.UnmarshalBody(r, &output); err != nil {
+			return sdk.WrapError(err, "cannot unmarshal request")
+		}
+		if err := output.IsValid(); err != nil {
+			return err
+		}
+
+		tx, err := api.mustDB().Begin()
+		if err != nil {
+			return sdk.WrapError(err, "unable to start transaction")
+		}
+		defer tx.Rollback() // nolint
+
+		job, err := workflow.LoadNodeJobRun(tx, api.Cache, id)
+		if err != nil {
+			return sdk.WrapError(err, "unable to load job %d", id)
+		}
+
+		o, err := workflow.AddRunJobOutput(ctx, tx, job, output.Key, output.Value)
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Commit(); err != nil {
+			return sdk.WrapError(err, "unable to commit transaction")
+		}
+
+		return service.WriteJSON(w, o, http.StatusOK)
+	}
+}
+
+func (api *API) getWorkflowRunJobOutputsHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		key := vars["key"]
+		name := vars["permWorkflowName"]
+
+		number, err := requestVarInt(r, "number")
+		if err != nil {
+			return sdk.WrapError(err, "getWorkflowRunJobOutputsHandler> Invalid run number")
+		}
+
+		wr, err := workflow.LoadRun(api.mustDB(), key, name, number, workflow.LoadRunOptions{DisableDetailledNodeRun: true})
+		if err != nil {
+			return sdk.WrapError(err, "cannot load workflow run")
+		}
+
+		outputs, err := workflow.LoadRunJobOutputs(api.mustDB(), wr.ID)
+		if err != nil {
+			return err
+		}
+
+		return service.WriteJSON(w, outputs, http.StatusOK)
+	}
+}
@@ -0,0 +1,173 @@
+package api
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-gorp/gorp"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"github.com/gorilla/mux"
+
+	"github.com/ovh/cds/engine/api/workflow"
+	"github.com/ovh/cds/engine/service"
+	"github.com/ovh/cds/sdk"
+)
+
+// getWorkflowNodeRunJobStepLogDownloadHandler returns the raw logs of a step. Byte ranges are supported so that
+// clients can download only the end of the logs they don't have yet.
+func (api *API) getWorkflowNodeRunJobStepLogDownloadHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		projectKey := vars["key"]
+		workflowName := vars["permWorkflowName"]
+		number, err := requestVarInt(r, "number")
+		if err != nil {
+			return err
+		}
+		nodeRunID, err := requestVarInt(r, "nodeRunID")
+		if err != nil {
+			return err
+		}
+		runJobID, err := requestVarInt(r, "runJobId")
+		if err != nil {
+			return err
+		}
+		stepOrder, err := requestVarInt(r, "stepOrder")
+		if err != nil {
+			return err
+		}
+
+		nodeRun, err := workflow.LoadNodeRun(api.mustDB(), projectKey, workflowName, number, nodeRunID, workflow.LoadRunOptions{DisableDetailledNodeRun: true})
+		if err != nil {
+			return sdk.WrapError(err, "Cannot find nodeRun %d/%d for workflow %s in project %s", nodeRunID, number, workflowName, projectKey)
+		}
+		if nodeRunStepStatus(nodeRun, runJobID, stepOrder) == "" {
+			return sdk.WrapError(sdk.ErrStepNotFound, "Cannot find step %d on job %d in nodeRun %d/%d for workflow %s in project %s",
+				stepOrder, runJobID, nodeRunID, number, workflowName, projectKey)
+		}
+
+		logs, err := workflow.LoadStepLogs(api.mustDB(), runJobID, stepOrder)
+		if err != nil {
+			return sdk.WrapError(err, "Cannot load log for runJob %d on step %d", runJobID, stepOrder)
+		}
+		var value string
+		var modtime time.Time
+		if logs != nil {
+			value = logs.Val
+			modtime = logTimestamp(logs.LastModified)
+		}
+
+		filename := fmt.Sprintf("%s-%d-job.%d-step.%d.log", workflowName, number, runJobID, stepOrder)
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+		http.ServeContent(w, r, filename, modtime, strings.NewReader(value))
+		return nil
+	}
+}
+
+// getWorkflowRunLogsDownloadHandler returns a gzipped tar archive with the logs of all the steps and services
+// of all the jobs of a workflow run
+func (api *API) getWorkflowRunLogsDownloadHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		projectKey := vars["key"]
+		workflowName := vars["permWorkflowName"]
+		number, err := requestVarInt(r, "number")
+		if err != nil {
+			return err
+		}
+
+		db := api.mustDB()
+		wr, err := workflow.LoadRun(db, projectKey, workflowName, number, workflow.LoadRunOptions{})
+		if err != nil {
+			return sdk.WrapError(err, "Unable to load workflow run %s/%s #%d", projectKey, workflowName, number)
+		}
+
+		w.Header().Set("Content-Type", "application/gzip")
+		w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s-%d-logs.tar.gz"`, workflowName, number))
+
+		gw := gzip.NewWriter(w)
+		tw := tar.NewWriter(gw)
+		if err := writeWorkflowRunLogs(db, tw, wr); err != nil {
+			return err
+		}
+		if err := tw.Close(); err != nil {
+			return sdk.WithStack(err)
+		}
+		return sdk.WithStack(gw.Close())
+	}
+}
+
+func writeWorkflowRunLogs(db gorp.SqlExecutor, tw *tar.Writer, wr *sdk.WorkflowRun) error {
+	nodeIDs := make([]int64, 0, len(wr.WorkflowNodeRuns))
+	for id := range wr.WorkflowNodeRuns {
+		nodeIDs = append(nodeIDs, id)
+	}
+	sort.Slice(nodeIDs, func(i, j int) bool { return nodeIDs[i] < nodeIDs[j] })
+
+	for _, id := range nodeIDs {
+		for _, nr := range wr.WorkflowNodeRuns[id] {
+			for _, stage := range nr.Stages {
+				for _, job := range stage.RunJobs {
+					logs, err := workflow.LoadLogs(db, job.ID)
+					if err != nil {
+						return sdk.WrapError(err, "Unable to load logs of job %d", job.ID)
+					}
+					for _, l := range logs {
+						name := sdk.WorkflowRunStepLogFilename(*wr, nr, stage.Name, job, int(l.StepOrder))
+						if err := writeLogFile(tw, name, l.Val, logTimestamp(l.LastModified)); err != nil {
+							return err
+						}
+					}
+
+					servicesLogs, err := workflow.LoadServicesLogsByJob(db, job.ID)
+					if err != nil {
+						return sdk.WrapError(err, "Unable to load services logs of job %d", job.ID)
+					}
+					for _, l := range servicesLogs {
+						name := sdk.WorkflowRunServiceLogFilename(*wr, nr, stage.Name, job, l.ServiceRequirementName)
+						if err := writeLogFile(tw, name, l.Val, logTimestamp(l.LastModified)); err != nil {
+							return err
+						}
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func writeLogFile(tw *tar.Writer, name, value string, modtime time.Time) error {
+	hdr := &tar.Header{
+		Name:    name,
+		Mode:    0644,
+		Size:    int64(len(value)),
+		ModTime: modtime,
+	}
+	if err := tw.WriteHeader(hdr); err != nil {
+		return sdk.WrapError(err, "Unable to write header of %s", name)
+	}
+	if _, err := io.WriteString(tw, value); err != nil {
+		return sdk.WrapError(err, "Unable to write %s", name)
+	}
+	return nil
+}
+
+func logTimestamp(t *timestamp.Timestamp) time.Time {
+	if t == nil {
+		return time.Time{}
+	}
+	tt, err := ptypes.Timestamp(t)
+	if err != nil {
+		return time.Time{}
+	}
+	return tt
+}
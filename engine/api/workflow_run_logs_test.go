@@ -0,0 +1,64 @@
+package api
+
+import (
+	"archive/tar"
+	"bytes"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteLogFile(t *testing.T) {
+	buf := new(bytes.Buffer)
+	tw := tar.NewWriter(buf)
+	assert.NoError(t, writeLogFile(tw, "step.0.log", "my logs\n", time.Now()))
+	assert.NoError(t, writeLogFile(tw, "service.pg.log", "", time.Now()))
+	assert.NoError(t, tw.Close())
+
+	tr := tar.NewReader(buf)
+	hdr, err := tr.Next()
+	assert.NoError(t, err)
+	assert.Equal(t, "step.0.log", hdr.Name)
+	btes, err := ioutil.ReadAll(tr)
+	assert.NoError(t, err)
+	assert.Equal(t, "my logs\n", string(btes))
+
+	hdr, err = tr.Next()
+	assert.NoError(t, err)
+	assert.Equal(t, "service.pg.log", hdr.Name)
+	assert.Equal(t, int64(0), hdr.Size)
+
+	_, err = tr.Next()
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestRouterCompressRange(t *testing.T) {
+	r := &Router{}
+	h := r.compress(func(w http.ResponseWriter, req *http.Request) {
+		http.ServeContent(w, req, "step.log", time.Time{}, strings.NewReader("0123456789"))
+	})
+
+	// Range requests are not compressed
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	req.Header.Set("Range", "bytes=4-")
+	rec := httptest.NewRecorder()
+	h(rec, req)
+	assert.Equal(t, http.StatusPartialContent, rec.Code)
+	assert.Equal(t, "", rec.Header().Get("Content-Encoding"))
+	assert.Equal(t, "bytes 4-9/10", rec.Header().Get("Content-Range"))
+	assert.Equal(t, "456789", rec.Body.String())
+
+	req = httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	rec = httptest.NewRecorder()
+	h(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "gzip", rec.Header().Get("Content-Encoding"))
+}
@@ -0,0 +1,95 @@
+package api
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/go-gorp/gorp"
+	"github.com/gorilla/mux"
+
+	"github.com/ovh/cds/engine/api/workflow"
+	"github.com/ovh/cds/engine/service"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+func (api *API) postWorkflowJobRunResultHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		id, errI := requestVarInt(r, "permID")
+		if errI != nil {
+			return sdk.WrapError(sdk.ErrInvalidID, "postWorkflowJobRunResultHandler> Invalid node job run ID")
+		}
+
+		var result sdk.WorkflowRunResult
+		if err := service.UnmarshalBody(r, &result); err != nil {
+			return sdk.WrapError(err, "cannot unmarshal request")
+		}
+		if err := result.IsValid(); err != nil {
+			return err
+		}
+
+		nodeRun, err := workflow.LoadNodeRunByNodeJobID(api.mustDB(), id, workflow.LoadRunOptions{DisableDetailledNodeRun: true})
+		if err != nil {
+			return sdk.WrapError(err, "cannot load node run")
+		}
+
+		res, err := workflow.AddRunResult(api.mustDB(), *nodeRun, id, result.Type, result.Data)
+		if err != nil {
+			return err
+		}
+
+		return service.WriteJSON(w, res, http.StatusOK)
+	}
+}
+
+func (api *API) getWorkflowRunResultsHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		key := vars["key"]
+		name := vars["permWorkflowName"]
+
+		number, errN := requestVarInt(r, "number")
+		if errN != nil {
+			return sdk.WrapError(errN, "getWorkflowRunResultsHandler> Invalid run number")
+		}
+
+		types, err := QueryStrings(r, "type")
+		if err != nil {
+			return sdk.WrapError(sdk.ErrWrongRequest, "getWorkflowRunResultsHandler> Invalid type: %v", err)
+		}
+		for _, t := range types {
+			if !sdk.IsInArray(t, sdk.WorkflowRunResultTypes) {
+				return sdk.NewErrorFrom(sdk.ErrWrongRequest, "invalid result type %s", t)
+			}
+		}
+
+		wr, err := workflow.LoadRun(api.mustDB(), key, name, number, workflow.LoadRunOptions{DisableDetailledNodeRun: true})
+		if err != nil {
+			return sdk.WrapError(err, "cannot load workflow run")
+		}
+
+		results, err := workflow.LoadRunResults(api.mustDB(), wr.ID, types...)
+		if err != nil {
+			return err
+		}
+
+		return service.WriteJSON(w, results, http.StatusOK)
+	}
+}
+
+// addArtifactRunResult records an uploaded artifact as a result of the run
+func addArtifactRunResult(db gorp.SqlExecutor, nodeRun sdk.WorkflowNodeRun, jobID int64, art sdk.WorkflowNodeRunArtifact) {
+	data := sdk.WorkflowRunResultArtifact{
+		Name:       art.Name,
+		Tag:        art.Tag,
+		ArtifactID: art.ID,
+		Size:       art.Size,
+		Perm:       art.Perm,
+		MD5sum:     art.MD5sum,
+		SHA256sum:  art.SHA256sum,
+		SHA512sum:  art.SHA512sum,
+	}
+	if _, err := workflow.AddRunResult(db, nodeRun, jobID, sdk.WorkflowRunResultTypeArtifact, data); err != nil {
+		log.Error("addArtifactRunResult> Cannot record artifact %s as a result of node run %d: %v", art.Name, nodeRun.ID, err)
+	}
+}
@@ -0,0 +1,89 @@
+package api
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/ovh/cds/engine/api/project"
+	"github.com/ovh/cds/engine/api/workflow"
+	"github.com/ovh/cds/engine/service"
+	"github.com/ovh/cds/sdk"
+)
+
+func (api *API) getWorkflowTestCaseHistoryHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		key := vars["key"]
+		name := vars["permWorkflowName"]
+
+		nodeName := FormString(r, "node")
+		testSuite := FormString(r, "suite")
+		testName := FormString(r, "name")
+		if nodeName == "" || testSuite == "" || testName == "" {
+			return sdk.NewErrorFrom(sdk.ErrWrongRequest, "node, suite and name are mandatory")
+		}
+		status := FormString(r, "status")
+		if status != "" && status != sdk.TestCaseStatusSuccess && status != sdk.TestCaseStatusFailure && status != sdk.TestCaseStatusSkipped {
+			return sdk.NewErrorFrom(sdk.ErrWrongRequest, "invalid status %s", status)
+		}
+		limit, err := FormInt(r, "limit")
+		if err != nil {
+			return err
+		}
+		if limit <= 0 || limit > 100 {
+			limit = 50
+		}
+
+		wf, err := api.loadWorkflowForTestCases(ctx, key, name)
+		if err != nil {
+			return err
+		}
+
+		history, err := workflow.LoadTestCaseHistory(api.mustDB(), wf.ID, nodeName, testSuite, testName, FormString(r, "branch"), status, limit)
+		if err != nil {
+			return err
+		}
+		return service.WriteJSON(w, history, http.StatusOK)
+	}
+}
+
+func (api *API) getWorkflowFlakyTestsHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		key := vars["key"]
+		name := vars["permWorkflowName"]
+
+		window, err := FormInt(r, "window")
+		if err != nil {
+			return err
+		}
+		if window <= 1 || window > 100 {
+			window = sdk.FlakinessWindow
+		}
+
+		wf, err := api.loadWorkflowForTestCases(ctx, key, name)
+		if err != nil {
+			return err
+		}
+
+		flakiness, err := workflow.LoadTestCasesFlakiness(api.mustDB(), wf.ID, FormString(r, "node"), FormString(r, "branch"), window)
+		if err != nil {
+			return err
+		}
+		return service.WriteJSON(w, flakiness, http.StatusOK)
+	}
+}
+
+func (api *API) loadWorkflowForTestCases(ctx context.Context, key, name string) (*sdk.Workflow, error) {
+	proj, err := project.Load(api.mustDB(), api.Cache, key, deprecatedGetUser(ctx))
+	if err != nil {
+		return nil, sdk.WrapError(err, "unable to load project %s", key)
+	}
+	wf, err := workflow.Load(ctx, api.mustDB(), api.Cache, proj, name, deprecatedGetUser(ctx), workflow.LoadOptions{WithoutNode: true})
+	if err != nil {
+		return nil, sdk.WrapError(err, "unable to load workflow %s", name)
+	}
+	return wf, nil
+}
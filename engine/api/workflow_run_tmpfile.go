@@ -0,0 +1,115 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/ovh/cds/engine/api/services"
+	"github.com/ovh/cds/engine/api/workflow"
+	"github.com/ovh/cds/engine/service"
+	"github.com/ovh/cds/sdk"
+)
+
+// postWorkflowJobTmpFileHandler stores a temporary file pushed by a job in the CDN service. The file can be pulled
+// by the next jobs of the workflow run until the run is deleted.
+func (api *API) postWorkflowJobTmpFileHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		id, err := requestVarInt(r, "permID")
+		if err != nil {
+			return sdk.WrapError(sdk.ErrInvalidID, "postWorkflowJobTmpFileHandler> Invalid node job run ID")
+		}
+
+		f := sdk.WorkflowRunTmpFile{Name: mux.Vars(r)["name"], WorkflowRunJobID: id}
+		if err := f.IsValid(); err != nil {
+			return err
+		}
+
+		nodeRun, err := workflow.LoadNodeRunByNodeJobID(api.mustDB(), id, workflow.LoadRunOptions{DisableDetailledNodeRun: true})
+		if err != nil {
+			return sdk.WrapError(err, "cannot load node run")
+		}
+		f.WorkflowRunID = nodeRun.WorkflowRunID
+
+		content, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			return sdk.WrapError(err, "unable to read temporary file %s", f.Name)
+		}
+		defer r.Body.Close() // nolint
+
+		srvs, err := api.cdnServices()
+		if err != nil {
+			return err
+		}
+		btes, _, err := services.DoRequest(ctx, srvs, "POST", "/item?type="+sdk.CDNItemTypeTmpFile, content)
+		if err != nil {
+			return sdk.WrapError(err, "unable to store temporary file %s in cdn", f.Name)
+		}
+		var item sdk.CDNItem
+		if err := json.Unmarshal(btes, &item); err != nil {
+			return sdk.WrapError(err, "unable to read cdn item")
+		}
+		f.Hash, f.Size = item.Hash, item.Size
+
+		if err := workflow.SaveRunTmpFile(api.mustDB(), &f); err != nil {
+			return err
+		}
+
+		return service.WriteJSON(w, f, http.StatusOK)
+	}
+}
+
+// getWorkflowJobTmpFileHandler returns the content of a temporary file pushed by a job of the same workflow run
+func (api *API) getWorkflowJobTmpFileHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		id, err := requestVarInt(r, "permID")
+		if err != nil {
+			return sdk.WrapError(sdk.ErrInvalidID, "getWorkflowJobTmpFileHandler> Invalid node job run ID")
+		}
+		name := mux.Vars(r)["name"]
+
+		// Only the worker which has taken the job can read the temporary files of its run
+		if wk := getWorker(ctx); wk == nil || wk.ActionBuildID != id {
+			return sdk.WithStack(sdk.ErrForbidden)
+		}
+
+		nodeRun, err := workflow.LoadNodeRunByNodeJobID(api.mustDB(), id, workflow.LoadRunOptions{DisableDetailledNodeRun: true})
+		if err != nil {
+			return sdk.WrapError(err, "cannot load node run")
+		}
+
+		f, err := workflow.LoadRunTmpFile(api.mustDB(), nodeRun.WorkflowRunID, name)
+		if err != nil {
+			return err
+		}
+
+		srvs, err := api.cdnServices()
+		if err != nil {
+			return err
+		}
+		content, _, err := services.DoRequest(ctx, srvs, "GET", fmt.Sprintf("/item/%s/download", f.Hash), nil)
+		if err != nil {
+			return sdk.WrapError(err, "unable to download temporary file %s from cdn", f.Name)
+		}
+
+		w.Header().Set("Content-Type", "application/octet-stream")
+		w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, f.Name))
+		_, err = w.Write(content)
+		return sdk.WithStack(err)
+	}
+}
+
+func (api *API) cdnServices() ([]sdk.Service, error) {
+	srvs, err := services.FindByType(api.mustDB(), services.TypeCDN)
+	if err != nil {
+		return nil, sdk.WrapError(err, "unable to load cdn services")
+	}
+	if len(srvs) == 0 {
+		return nil, sdk.NewErrorFrom(sdk.ErrServiceUnavailable, "no cdn service available")
+	}
+	return srvs, nil
+}
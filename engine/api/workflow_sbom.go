@@ -0,0 +1,168 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/ovh/cds/engine/api/project"
+	"github.com/ovh/cds/engine/api/workflow"
+	"github.com/ovh/cds/engine/service"
+	"github.com/ovh/cds/sdk"
+)
+
+func (api *API) postWorkflowJobSBOMHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		id, errI := requestVarInt(r, "permID")
+		if errI != nil {
+			return sdk.WrapError(sdk.ErrInvalidID, "postWorkflowJobSBOMHandler> Invalid node job run ID")
+		}
+
+		name := FormString(r, "name")
+		if name == "" {
+			return sdk.NewErrorFrom(sdk.ErrWrongRequest, "the name of the SBOM is mandatory")
+		}
+
+		document, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			return sdk.WrapError(sdk.ErrWrongRequest, "postWorkflowJobSBOMHandler> Unable to read body: %v", err)
+		}
+		defer r.Body.Close() // nolint
+
+		format, components, err := sdk.ParseSBOM(document)
+		if err != nil {
+			return err
+		}
+
+		nodeJobRun, err := workflow.LoadNodeJobRun(api.mustDB(), api.Cache, id)
+		if err != nil {
+			return sdk.WrapError(err, "cannot load node job run %d", id)
+		}
+
+		nodeRun, err := workflow.LoadNodeRunByNodeJobID(api.mustDB(), id, workflow.LoadRunOptions{DisableDetailledNodeRun: true})
+		if err != nil {
+			return sdk.WrapError(err, "cannot load node run")
+		}
+
+		sbom := sdk.WorkflowRunSBOM{
+			ProjectID:         nodeJobRun.ProjectID,
+			WorkflowID:        nodeRun.WorkflowID,
+			WorkflowRunID:     nodeRun.WorkflowRunID,
+			WorkflowNodeRunID: nodeRun.ID,
+			Name:              name,
+			Format:            format,
+			Components:        components,
+			Document:          document,
+		}
+
+		tx, err := api.mustDB().Begin()
+		if err != nil {
+			return sdk.WrapError(err, "cannot start transaction")
+		}
+		defer tx.Rollback() // nolint
+
+		if err := workflow.InsertSBOM(tx, &sbom); err != nil {
+			return err
+		}
+
+		if err := tx.Commit(); err != nil {
+			return sdk.WrapError(err, "cannot commit transaction")
+		}
+
+		sbom.Components = nil
+		sbom.Document = nil
+		return service.WriteJSON(w, sbom, http.StatusOK)
+	}
+}
+
+func (api *API) getWorkflowRunSBOMsHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		key := vars["key"]
+		name := vars["permWorkflowName"]
+
+		number, errN := requestVarInt(r, "number")
+		if errN != nil {
+			return sdk.WrapError(errN, "getWorkflowRunSBOMsHandler> Invalid run number")
+		}
+
+		wr, err := workflow.LoadRun(api.mustDB(), key, name, number, workflow.LoadRunOptions{DisableDetailledNodeRun: true})
+		if err != nil {
+			return sdk.WrapError(err, "cannot load workflow run")
+		}
+
+		sboms, err := workflow.LoadSBOMsByRunID(api.mustDB(), wr.ID)
+		if err != nil {
+			return err
+		}
+
+		return service.WriteJSON(w, sboms, http.StatusOK)
+	}
+}
+
+func (api *API) getWorkflowRunSBOMHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		key := vars["key"]
+		name := vars["permWorkflowName"]
+
+		number, errN := requestVarInt(r, "number")
+		if errN != nil {
+			return sdk.WrapError(errN, "getWorkflowRunSBOMHandler> Invalid run number")
+		}
+
+		id, errI := requestVarInt(r, "sbomID")
+		if errI != nil {
+			return sdk.WrapError(sdk.ErrInvalidID, "getWorkflowRunSBOMHandler> Invalid SBOM ID")
+		}
+
+		wr, err := workflow.LoadRun(api.mustDB(), key, name, number, workflow.LoadRunOptions{DisableDetailledNodeRun: true})
+		if err != nil {
+			return sdk.WrapError(err, "cannot load workflow run")
+		}
+
+		sbom, err := workflow.LoadSBOM(api.mustDB(), wr.ID, id)
+		if err != nil {
+			return err
+		}
+
+		w.Header().Add("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s.%s.json\"", sbom.Name, sbom.Format))
+		return service.Write(w, sbom.Document, http.StatusOK, "application/json")
+	}
+}
+
+func (api *API) getProjectSBOMComponentRunsHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		key := vars[permProjectKey]
+
+		name := FormString(r, "name")
+		if name == "" {
+			return sdk.NewErrorFrom(sdk.ErrWrongRequest, "the name of the component is mandatory")
+		}
+		version := FormString(r, "version")
+
+		limit, err := FormInt(r, "limit")
+		if err != nil {
+			return err
+		}
+		if limit <= 0 || limit > 1000 {
+			limit = 100
+		}
+
+		proj, err := project.Load(api.mustDB(), api.Cache, key, deprecatedGetUser(ctx))
+		if err != nil {
+			return sdk.WrapError(err, "unable to load project %s", key)
+		}
+
+		runs, err := workflow.LoadSBOMComponentRuns(api.mustDB(), proj.ID, name, version, limit)
+		if err != nil {
+			return err
+		}
+
+		return service.WriteJSON(w, runs, http.StatusOK)
+	}
+}
@@ -0,0 +1,130 @@
+package api
+
+import (
+	"context"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/cache"
+	"github.com/ovh/cds/engine/api/permission"
+	"github.com/ovh/cds/engine/api/project"
+	"github.com/ovh/cds/engine/api/workflow"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// triggerUpstreamWorkflows starts the downstream workflows listening to the successful node runs of the report
+func triggerUpstreamWorkflows(ctx context.Context, db *gorp.DbMap, store cache.Store, report *workflow.ProcessorReport) {
+	events, err := workflow.UpstreamHookEvents(db, report)
+	if err != nil {
+		log.Error("triggerUpstreamWorkflows> unable to compute upstream hook events: %v", err)
+		return
+	}
+
+	for _, e := range events {
+		if err := triggerUpstreamWorkflow(ctx, db, store, e); err != nil {
+			log.Error("triggerUpstreamWorkflows> unable to start workflow %s/%s from %s/%s #%d: %v",
+				e.ProjectKey, e.WorkflowName, e.Event.ParentWorkflow.Key, e.Event.ParentWorkflow.Name, e.Event.ParentWorkflow.Run, err)
+		}
+	}
+}
+
+func triggerUpstreamWorkflow(ctx context.Context, db *gorp.DbMap, store cache.Store, e workflow.UpstreamHookEvent) error {
+	projGroups, err := project.Load(db, store, e.ProjectKey, nil, project.LoadOptions.WithGroups)
+	if err != nil {
+		return sdk.WrapError(err, "cannot load project %s", e.ProjectKey)
+	}
+
+	// The downstream workflow is started with the groups of its project allowed to execute it, the read access
+	// on the upstream project is checked again for these groups as it may have been revoked since the hook was saved
+	u, err := upstreamTriggerUser(db, store, projGroups.ProjectGroups)
+	if err != nil {
+		return err
+	}
+
+	if err := workflow.CheckUpstreamProjectReadable(db, e.ProjectKey, e.Event.ParentWorkflow.Key, upstreamTriggerGroups(projGroups.ProjectGroups)); err != nil {
+		if sdk.ErrorIs(err, sdk.ErrForbidden) || sdk.ErrorIs(err, sdk.ErrNoProject) {
+			log.Warning("triggerUpstreamWorkflow> skip workflow %s/%s from %s/%s #%d: %v",
+				e.ProjectKey, e.WorkflowName, e.Event.ParentWorkflow.Key, e.Event.ParentWorkflow.Name, e.Event.ParentWorkflow.Run, err)
+			return nil
+		}
+		return err
+	}
+
+	p, err := project.Load(db, store, e.ProjectKey, u,
+		project.LoadOptions.WithGroups,
+		project.LoadOptions.WithVariables,
+		project.LoadOptions.WithFeatures,
+		project.LoadOptions.WithIntegrations,
+		project.LoadOptions.WithApplicationVariables,
+		project.LoadOptions.WithApplicationWithDeploymentStrategies,
+		project.LoadOptions.WithEnvironments,
+		project.LoadOptions.WithPipelines,
+	)
+	if err != nil {
+		return sdk.WrapError(err, "cannot load project %s", e.ProjectKey)
+	}
+
+	wf, err := workflow.Load(ctx, db, store, p, e.WorkflowName, u, workflow.LoadOptions{
+		DeepPipeline: true,
+		Base64Keys:   true,
+	})
+	if err != nil {
+		return sdk.WrapError(err, "unable to load workflow %s/%s", e.ProjectKey, e.WorkflowName)
+	}
+
+	evt := e.Event
+	report, err := startWorkflowRun(ctx, db, store, p, wf, nil, &sdk.WorkflowRunPostHandlerOption{Hook: &evt}, u, nil)
+	if err != nil {
+		return err
+	}
+
+	workflow.ResyncNodeRunsWithCommits(db, store, p, report)
+	go workflow.SendEvent(db, p.Key, report)
+
+	return nil
+}
+
+// upstreamTriggerGroups returns the groups of the project allowed to execute the downstream workflows
+func upstreamTriggerGroups(projGroups []sdk.GroupPermission) []sdk.GroupPermission {
+	groups := make([]sdk.GroupPermission, 0, len(projGroups))
+	for _, gp := range projGroups {
+		if gp.Permission >= permission.PermissionReadExecute {
+			groups = append(groups, gp)
+		}
+	}
+	return groups
+}
+
+// upstreamTriggerUser returns the user starting the downstream workflows, with the permissions of the groups
+// of the project allowed to execute it
+func upstreamTriggerUser(db gorp.SqlExecutor, store cache.Store, projGroups []sdk.GroupPermission) (*sdk.User, error) {
+	u := &sdk.User{Username: "cds.upstream"}
+	for _, gp := range upstreamTriggerGroups(projGroups) {
+		g, perm, err := loadPermissionsByGroupID(db, store, gp.Group.ID)
+		if err != nil {
+			return nil, sdk.WrapError(err, "cannot load permissions of group %d", gp.Group.ID)
+		}
+		u.Groups = append(u.Groups, g)
+		u.Permissions.Groups = append(u.Permissions.Groups, perm.Groups...)
+		u.Permissions.ProjectsPerm = mergePermissions(u.Permissions.ProjectsPerm, perm.ProjectsPerm)
+		u.Permissions.WorkflowsPerm = mergePermissions(u.Permissions.WorkflowsPerm, perm.WorkflowsPerm)
+	}
+	if len(u.Groups) == 0 {
+		return nil, sdk.WrapError(sdk.ErrForbidden, "no group can execute the workflows of the project")
+	}
+	return u, nil
+}
+
+// mergePermissions keeps the highest permission level of each key
+func mergePermissions(dst, src map[string]int) map[string]int {
+	if dst == nil {
+		dst = make(map[string]int, len(src))
+	}
+	for k, v := range src {
+		if v > dst[k] {
+			dst[k] = v
+		}
+	}
+	return dst
+}
@@ -0,0 +1,143 @@
+package cdn
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/ovh/cds/engine/api"
+	"github.com/ovh/cds/engine/api/cache"
+	"github.com/ovh/cds/engine/api/services"
+	"github.com/ovh/cds/sdk/cdsclient"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// New returns a new service
+func New() *Service {
+	s := new(Service)
+	s.Router = &api.Router{
+		Mux: mux.NewRouter(),
+	}
+	return s
+}
+
+// ApplyConfiguration apply an object of type cdn.Configuration after checking it
+func (s *Service) ApplyConfiguration(config interface{}) error {
+	if err := s.CheckConfiguration(config); err != nil {
+		return err
+	}
+	var ok bool
+	s.Cfg, ok = config.(Configuration)
+	if !ok {
+		return fmt.Errorf("Invalid CDN configuration")
+	}
+
+	s.Client = cdsclient.NewService(s.Cfg.API.HTTP.URL, 60*time.Second, s.Cfg.API.HTTP.Insecure)
+	s.API = s.Cfg.API.HTTP.URL
+	s.Name = s.Cfg.Name
+	s.HTTPURL = s.Cfg.URL
+	s.Token = s.Cfg.API.Token
+	s.Type = services.TypeCDN
+	s.MaxHeartbeatFailures = s.Cfg.API.MaxHeartbeatFailures
+	s.ServiceName = "cds-cdn"
+
+	return nil
+}
+
+// CheckConfiguration checks the validity of the configuration object
+func (s *Service) CheckConfiguration(config interface{}) error {
+	sConfig, ok := config.(Configuration)
+	if !ok {
+		return fmt.Errorf("Invalid CDN configuration")
+	}
+
+	if sConfig.URL == "" {
+		return fmt.Errorf("your CDS configuration seems to be empty. Please use environment variables, file or Consul to set your configuration")
+	}
+	if sConfig.Name == "" {
+		return fmt.Errorf("please enter a name in your CDN configuration")
+	}
+	if sConfig.Storage.Buffer.Basedir == "" {
+		return fmt.Errorf("please enter a buffer directory in your CDN configuration")
+	}
+	if sConfig.Storage.Filesystem.Basedir == "" && sConfig.Storage.Swift.URL == "" {
+		return fmt.Errorf("please enable at least one storage unit besides the buffer in your CDN configuration")
+	}
+	if sConfig.Storage.Filesystem.Basedir == sConfig.Storage.Buffer.Basedir {
+		return fmt.Errorf("the filesystem storage unit of your CDN configuration must not use the buffer directory")
+	}
+	if sConfig.SyncInterval <= 0 {
+		return fmt.Errorf("invalid sync interval %d in your CDN configuration", sConfig.SyncInterval)
+	}
+
+	return nil
+}
+
+// Serve will start the http api server
+func (s *Service) Serve(c context.Context) error {
+	ctx, cancel := context.WithCancel(c)
+	defer cancel()
+
+	//Init the cache
+	var errCache error
+	s.Cache, errCache = cache.New(s.Cfg.Cache.Redis.Host, s.Cfg.Cache.Redis.Password, s.Cfg.Cache.TTL)
+	if errCache != nil {
+		return fmt.Errorf("Cannot connect to redis instance : %v", errCache)
+	}
+
+	//Init the DAO
+	s.Dao = dao{s.Cache}
+
+	//Init the storage units
+	if err := os.MkdirAll(s.Cfg.Storage.Buffer.Basedir, 0755); err != nil {
+		return fmt.Errorf("Cannot create buffer directory: %v", err)
+	}
+	if err := s.initUnits(); err != nil {
+		return err
+	}
+
+	//Start the replication and the integrity verification of the items
+	go func() {
+		if err := s.syncItems(ctx); err != nil {
+			log.Info("CDN> Shutdown items synchronization")
+		}
+	}()
+	if s.Cfg.CheckInterval > 0 {
+		go func() {
+			if err := s.checkItems(ctx); err != nil {
+				log.Info("CDN> Shutdown items verification")
+			}
+		}()
+	}
+
+	//Init the http server
+	s.initRouter(ctx)
+	server := &http.Server{
+		Addr:           fmt.Sprintf("%s:%d", s.Cfg.HTTP.Addr, s.Cfg.HTTP.Port),
+		Handler:        s.Router.Mux,
+		ReadTimeout:    10 * time.Minute,
+		WriteTimeout:   10 * time.Minute,
+		MaxHeaderBytes: 1 << 20,
+	}
+
+	//Gracefully shutdown the http server
+	go func() {
+		select {
+		case <-ctx.Done():
+			log.Info("CDN> Shutdown HTTP Server")
+			_ = server.Shutdown(ctx)
+		}
+	}()
+
+	//Start the http server
+	log.Info("CDN> Starting HTTP Server on port %d", s.Cfg.HTTP.Port)
+	if err := server.ListenAndServe(); err != nil {
+		log.Error("CDN> Listen and serve failed: %v", err)
+	}
+
+	return ctx.Err()
+}
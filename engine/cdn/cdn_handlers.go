@@ -0,0 +1,274 @@
+package cdn
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/ovh/cds/engine/service"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+func (s *Service) postItemHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		itemType := r.FormValue("type")
+		switch itemType {
+		case sdk.CDNItemTypeLog, sdk.CDNItemTypeArtifact, sdk.CDNItemTypeTmpFile:
+		default:
+			return sdk.NewErrorFrom(sdk.ErrWrongRequest, "invalid item type %s", itemType)
+		}
+
+		// The content is written in a temporary file to compute its hash before storing it in the buffer
+		tmp, err := ioutil.TempFile(s.Cfg.Storage.Buffer.Basedir, "upload-")
+		if err != nil {
+			return sdk.WrapError(err, "Unable to create temporary file")
+		}
+		defer os.Remove(tmp.Name()) // nolint
+		defer tmp.Close()           // nolint
+
+		hash, size, err := hashContent(io.TeeReader(r.Body, tmp))
+		if err != nil {
+			return sdk.WrapError(err, "Unable to read item")
+		}
+
+		if item := s.Dao.FindItem(hash); item != nil {
+			if item.Type == sdk.CDNItemTypeTmpFile && itemType != sdk.CDNItemTypeTmpFile {
+				promoted, err := s.updateItem(hash, func(i *sdk.CDNItem) { promoteItem(i, itemType) })
+				if err != nil {
+					return err
+				}
+				item = promoted
+			}
+			return service.WriteJSON(w, item, http.StatusOK)
+		}
+
+		if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+			return sdk.WrapError(err, "Unable to read temporary file")
+		}
+		if err := s.unit(unitBuffer).store(hash, ioutil.NopCloser(tmp)); err != nil {
+			return sdk.WrapError(err, "Unable to store item %s in the buffer", hash)
+		}
+
+		item := newItem(hash, itemType, size, s.Units)
+		s.Dao.SaveItem(&item)
+		log.Debug("CDN> Item %s (%s, %d bytes) stored in the buffer", hash, itemType, size)
+
+		return service.WriteJSON(w, item, http.StatusCreated)
+	}
+}
+
+// promoteItem changes the type of a temporary file item when the same content is stored with another type, so that
+// it is not deleted with the workflow run which pushed it
+func promoteItem(item *sdk.CDNItem, itemType string) {
+	if item.Type == sdk.CDNItemTypeTmpFile {
+		item.Type = itemType
+	}
+}
+
+// newItem returns an item stored in the buffer and missing in the other storage units
+func newItem(hash, itemType string, size int64, units []*storageUnit) sdk.CDNItem {
+	now := time.Now()
+	item := sdk.CDNItem{
+		Hash:         hash,
+		Type:         itemType,
+		Size:         size,
+		Created:      now,
+		LastModified: now,
+	}
+	for _, u := range units {
+		iu := sdk.CDNItemUnit{Unit: u.name, Status: sdk.CDNItemUnitStatusMissing}
+		if u.buffer {
+			iu.Status = sdk.CDNItemUnitStatusSynced
+			iu.LastCheck = now
+		}
+		item.Units = append(item.Units, iu)
+	}
+	return item
+}
+
+func (s *Service) getItemsHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		items, err := s.Dao.FindAllItems()
+		if err != nil {
+			return err
+		}
+
+		itemType, status, unit := r.FormValue("type"), r.FormValue("status"), r.FormValue("unit")
+		filtered := make([]sdk.CDNItem, 0, len(items))
+		for _, i := range items {
+			if itemType != "" && i.Type != itemType {
+				continue
+			}
+			if status != "" && !itemHasStatus(i, unit, status) {
+				continue
+			}
+			filtered = append(filtered, i)
+		}
+		sort.Slice(filtered, func(i, j int) bool { return filtered[i].Created.After(filtered[j].Created) })
+
+		return service.WriteJSON(w, filtered, http.StatusOK)
+	}
+}
+
+// itemHasStatus returns true if the item has the given status in the given unit, or in any unit if
+// no unit is given
+func itemHasStatus(i sdk.CDNItem, unit, status string) bool {
+	for _, iu := range i.Units {
+		if (unit == "" || iu.Unit == unit) && iu.Status == status {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Service) getItemHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		hash := mux.Vars(r)["hash"]
+		item := s.Dao.FindItem(hash)
+		if item == nil {
+			return sdk.WithStack(sdk.ErrNotFound)
+		}
+		return service.WriteJSON(w, item, http.StatusOK)
+	}
+}
+
+func (s *Service) deleteItemHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		hash := mux.Vars(r)["hash"]
+		if !s.Dao.LockItem(hash, itemLockExpiration) {
+			return sdk.NewErrorFrom(sdk.ErrConflict, "item %s is locked", hash)
+		}
+		defer s.Dao.UnlockItem(hash)
+
+		item := s.Dao.FindItem(hash)
+		if item == nil {
+			return sdk.WithStack(sdk.ErrNotFound)
+		}
+		// When a type is given, the item is kept if it has been promoted to another type
+		if itemType := r.FormValue("type"); itemType != "" && item.Type != itemType {
+			return nil
+		}
+		for _, u := range s.Units {
+			if err := u.driver.Delete(itemObject{hash: hash}); err != nil {
+				return sdk.WrapError(err, "Unable to delete item %s from %s", hash, u.name)
+			}
+		}
+		s.Dao.DeleteItem(item)
+		return nil
+	}
+}
+
+func (s *Service) getItemDownloadHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		hash := mux.Vars(r)["hash"]
+		item := s.Dao.FindItem(hash)
+		if item == nil {
+			return sdk.WithStack(sdk.ErrNotFound)
+		}
+
+		// The units are ordered so that the item is read from the buffer if it is still there
+		for _, u := range s.Units {
+			if iu := item.Unit(u.name); iu == nil || iu.Status != sdk.CDNItemUnitStatusSynced {
+				continue
+			}
+			f, err := u.fetch(hash)
+			if err != nil {
+				log.Warning("CDN> Unable to fetch item %s from %s: %v", hash, u.name, err)
+				continue
+			}
+			defer f.Close() // nolint
+
+			w.Header().Add("Content-Type", "application/octet-stream")
+			w.Header().Add("Content-Length", strconv.FormatInt(item.Size, 10))
+			if _, err := io.Copy(w, f); err != nil {
+				return sdk.WrapError(err, "Unable to download item %s", hash)
+			}
+			return nil
+		}
+
+		return sdk.NewErrorFrom(sdk.ErrNotFound, "item %s is not available in any storage unit", hash)
+	}
+}
+
+func (s *Service) postItemCheckHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		hash := mux.Vars(r)["hash"]
+		item, err := s.updateItem(hash, func(i *sdk.CDNItem) {
+			s.checkItem(i)
+			s.syncItem(i)
+		})
+		if err != nil {
+			return err
+		}
+		return service.WriteJSON(w, item, http.StatusOK)
+	}
+}
+
+func (s *Service) getUnitsHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		items, err := s.Dao.FindAllItems()
+		if err != nil {
+			return err
+		}
+		return service.WriteJSON(w, s.unitsState(items), http.StatusOK)
+	}
+}
+
+// unitsState returns the storage units with the number of items by status
+func (s *Service) unitsState(items []sdk.CDNItem) []sdk.CDNUnit {
+	units := make([]sdk.CDNUnit, len(s.Units))
+	for i, u := range s.Units {
+		status := u.driver.Status()
+		units[i] = sdk.CDNUnit{
+			Name:   u.name,
+			Buffer: u.buffer,
+			Status: status.Status,
+			Value:  status.Value,
+		}
+		for _, item := range items {
+			iu := item.Unit(u.name)
+			if iu == nil {
+				units[i].NbMissing++
+				continue
+			}
+			switch iu.Status {
+			case sdk.CDNItemUnitStatusSynced:
+				units[i].NbSynced++
+			case sdk.CDNItemUnitStatusCorrupted:
+				units[i].NbCorrupted++
+			default:
+				units[i].NbMissing++
+			}
+		}
+		units[i].NbItems = len(items)
+	}
+	return units
+}
+
+func (s *Service) statusHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		return service.WriteJSON(w, s.Status(), http.StatusOK)
+	}
+}
+
+// Status returns sdk.MonitoringStatus, implements interface service.Service
+func (s *Service) Status() sdk.MonitoringStatus {
+	m := s.CommonMonitoring()
+	if s.Cache != nil {
+		m.Lines = append(m.Lines, s.Cache.Status())
+	}
+	for _, u := range s.Units {
+		status := u.driver.Status()
+		status.Component = "Unit " + u.name
+		m.Lines = append(m.Lines, status)
+	}
+	return m
+}
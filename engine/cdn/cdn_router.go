@@ -0,0 +1,31 @@
+package cdn
+
+import (
+	"context"
+
+	"github.com/ovh/cds/engine/api"
+	"github.com/ovh/cds/engine/api/observability"
+	"github.com/ovh/cds/sdk/log"
+)
+
+func (s *Service) initRouter(ctx context.Context) {
+	r := s.Router
+	r.Background = ctx
+	r.URL = s.Cfg.URL
+	r.SetHeaderFunc = api.DefaultHeaders
+	r.Middlewares = append(r.Middlewares, s.authMiddleware)
+
+	r.Handle("/mon/version", r.GET(api.VersionHandler, api.Auth(false)))
+	r.Handle("/mon/status", r.GET(s.statusHandler, api.Auth(false)))
+	r.Handle("/mon/metrics", r.GET(observability.StatsHandler, api.Auth(false)))
+
+	r.Handle("/item", r.POST(s.postItemHandler), r.GET(s.getItemsHandler))
+	r.Handle("/item/{hash}", r.GET(s.getItemHandler), r.DELETE(s.deleteItemHandler))
+	r.Handle("/item/{hash}/download", r.GET(s.getItemDownloadHandler))
+	r.Handle("/item/{hash}/check", r.POST(s.postItemCheckHandler))
+	r.Handle("/unit", r.GET(s.getUnitsHandler))
+
+	if err := r.InitMetrics("cds-cdn", s.Cfg.Name); err != nil {
+		log.Error("unable to init router metrics: %v", err)
+	}
+}
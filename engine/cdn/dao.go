@@ -0,0 +1,62 @@
+package cdn
+
+import (
+	"time"
+
+	"github.com/ovh/cds/engine/api/cache"
+	"github.com/ovh/cds/sdk"
+)
+
+var (
+	rootKey = cache.Key("cdn", "items")
+	lockKey = cache.Key("cdn", "lock")
+)
+
+type dao struct {
+	store cache.Store
+}
+
+func (d *dao) FindAllItems() ([]sdk.CDNItem, error) {
+	nbItems := d.store.SetCard(rootKey)
+	items := make([]*sdk.CDNItem, nbItems, nbItems)
+	for i := 0; i < nbItems; i++ {
+		items[i] = &sdk.CDNItem{}
+	}
+	if err := d.store.SetScan(rootKey, sdk.InterfaceSlice(items)...); err != nil {
+		return nil, sdk.WrapError(err, "Unable to scan %s", rootKey)
+	}
+
+	allitems := make([]sdk.CDNItem, 0, nbItems)
+	for _, i := range items {
+		// items deleted during the scan are empty
+		if i.Hash != "" {
+			allitems = append(allitems, *i)
+		}
+	}
+	return allitems, nil
+}
+
+func (d *dao) FindItem(hash string) *sdk.CDNItem {
+	i := &sdk.CDNItem{}
+	if d.store.Get(cache.Key(rootKey, hash), i) {
+		return i
+	}
+	return nil
+}
+
+func (d *dao) SaveItem(i *sdk.CDNItem) {
+	d.store.SetAdd(rootKey, i.Hash, i)
+}
+
+func (d *dao) DeleteItem(i *sdk.CDNItem) {
+	d.store.SetRemove(rootKey, i.Hash, i)
+}
+
+// LockItem locks an item so that only one CDN service replicates or verifies it at a time
+func (d *dao) LockItem(hash string, expiration time.Duration) bool {
+	return d.store.Lock(cache.Key(lockKey, hash), expiration, 0, 1)
+}
+
+func (d *dao) UnlockItem(hash string) {
+	d.store.Unlock(cache.Key(lockKey, hash))
+}
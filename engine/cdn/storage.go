@@ -0,0 +1,113 @@
+package cdn
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+
+	"github.com/ovh/cds/engine/api/objectstore"
+	"github.com/ovh/cds/sdk"
+)
+
+// Names of the storage units
+const (
+	unitBuffer     = "buffer"
+	unitFilesystem = "filesystem"
+	unitSwift      = "swift"
+)
+
+const itemsPath = "items"
+
+// itemObject is the object of an item in the objectstore drivers
+type itemObject struct {
+	hash string
+}
+
+func (o itemObject) GetName() string { return o.hash }
+func (o itemObject) GetPath() string { return itemsPath }
+
+// storageUnit stores the items in an objectstore driver
+type storageUnit struct {
+	name   string
+	buffer bool
+	driver objectstore.Driver
+}
+
+func (s *Service) initUnits() error {
+	buffer, err := objectstore.NewFilesystemStore(s.Cfg.Storage.Buffer.Basedir)
+	if err != nil {
+		return sdk.WrapError(err, "Unable to initialize the buffer")
+	}
+	s.Units = []*storageUnit{{name: unitBuffer, buffer: true, driver: buffer}}
+
+	if s.Cfg.Storage.Filesystem.Basedir != "" {
+		fs, err := objectstore.NewFilesystemStore(s.Cfg.Storage.Filesystem.Basedir)
+		if err != nil {
+			return sdk.WrapError(err, "Unable to initialize the filesystem storage unit")
+		}
+		s.Units = append(s.Units, &storageUnit{name: unitFilesystem, driver: fs})
+	}
+
+	if s.Cfg.Storage.Swift.URL != "" {
+		cfg := s.Cfg.Storage.Swift
+		swift, err := objectstore.NewSwiftStore(cfg.URL, cfg.Username, cfg.Password, cfg.Region, cfg.Tenant, cfg.Domain, cfg.ContainerPrefix)
+		if err != nil {
+			return sdk.WrapError(err, "Unable to initialize the swift storage unit")
+		}
+		s.Units = append(s.Units, &storageUnit{name: unitSwift, driver: swift})
+	}
+
+	return nil
+}
+
+func (s *Service) unit(name string) *storageUnit {
+	for _, u := range s.Units {
+		if u.name == name {
+			return u
+		}
+	}
+	return nil
+}
+
+func (u *storageUnit) store(hash string, r io.ReadCloser) error {
+	_, err := u.driver.Store(itemObject{hash: hash}, r)
+	return err
+}
+
+func (u *storageUnit) fetch(hash string) (io.ReadCloser, error) {
+	return u.driver.Fetch(itemObject{hash: hash})
+}
+
+// verify reads an item from the storage unit and checks its hash and its size, it returns the status of
+// the item in the unit. Swift returns an empty content for unknown objects, so an empty content is
+// considered as missing.
+func (u *storageUnit) verify(item sdk.CDNItem) (string, error) {
+	r, err := u.fetch(item.Hash)
+	if err != nil {
+		return sdk.CDNItemUnitStatusMissing, err
+	}
+	defer r.Close()
+
+	hash, size, err := hashContent(r)
+	if err != nil {
+		return sdk.CDNItemUnitStatusMissing, err
+	}
+	if size == 0 && item.Size > 0 {
+		return sdk.CDNItemUnitStatusMissing, fmt.Errorf("item not found")
+	}
+	if hash != item.Hash || size != item.Size {
+		return sdk.CDNItemUnitStatusCorrupted, fmt.Errorf("invalid content: sha256 %s for %d bytes", hash, size)
+	}
+	return sdk.CDNItemUnitStatusSynced, nil
+}
+
+// hashContent returns the sha256 and the size of a content
+func hashContent(r io.Reader) (string, int64, error) {
+	h := sha256.New()
+	size, err := io.Copy(h, r)
+	if err != nil {
+		return "", 0, err
+	}
+	return hex.EncodeToString(h.Sum(nil)), size, nil
+}
@@ -0,0 +1,147 @@
+package cdn
+
+import (
+	"context"
+	"time"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+const itemLockExpiration = 10 * time.Minute
+
+// syncItems replicates periodically the items in the storage units where they are missing or corrupted
+func (s *Service) syncItems(ctx context.Context) error {
+	tick := time.NewTicker(time.Duration(s.Cfg.SyncInterval) * time.Second)
+	defer tick.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-tick.C:
+			items, err := s.Dao.FindAllItems()
+			if err != nil {
+				log.Error("CDN> Unable to load items: %v", err)
+				continue
+			}
+			for _, i := range items {
+				if ctx.Err() != nil {
+					return ctx.Err()
+				}
+				if i.Synced() && len(i.Units) == len(s.Units) {
+					continue
+				}
+				if _, err := s.updateItem(i.Hash, s.syncItem); err != nil && !sdk.ErrorIs(err, sdk.ErrConflict) {
+					log.Error("CDN> Unable to sync item %s: %v", i.Hash, err)
+				}
+			}
+		}
+	}
+}
+
+// checkItems verifies periodically the integrity of the items in all the storage units
+func (s *Service) checkItems(ctx context.Context) error {
+	tick := time.NewTicker(time.Duration(s.Cfg.CheckInterval) * time.Minute)
+	defer tick.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-tick.C:
+			items, err := s.Dao.FindAllItems()
+			if err != nil {
+				log.Error("CDN> Unable to load items: %v", err)
+				continue
+			}
+			for _, i := range items {
+				if ctx.Err() != nil {
+					return ctx.Err()
+				}
+				if _, err := s.updateItem(i.Hash, s.checkItem); err != nil && !sdk.ErrorIs(err, sdk.ErrConflict) {
+					log.Error("CDN> Unable to check item %s: %v", i.Hash, err)
+				}
+			}
+		}
+	}
+}
+
+// updateItem locks an item, reloads it and saves it after applying the given function
+func (s *Service) updateItem(hash string, f func(*sdk.CDNItem)) (*sdk.CDNItem, error) {
+	if !s.Dao.LockItem(hash, itemLockExpiration) {
+		return nil, sdk.NewErrorFrom(sdk.ErrConflict, "item %s is locked", hash)
+	}
+	defer s.Dao.UnlockItem(hash)
+
+	item := s.Dao.FindItem(hash)
+	if item == nil {
+		return nil, sdk.WithStack(sdk.ErrNotFound)
+	}
+	f(item)
+	item.LastModified = time.Now()
+	s.Dao.SaveItem(item)
+	return item, nil
+}
+
+// syncItem copies an item from a storage unit where it is synced to the units where it is not
+func (s *Service) syncItem(item *sdk.CDNItem) {
+	var source *storageUnit
+	for _, u := range s.Units {
+		if iu := item.Unit(u.name); iu != nil && iu.Status == sdk.CDNItemUnitStatusSynced {
+			source = u
+			break
+		}
+	}
+	if source == nil {
+		log.Error("CDN> Item %s is not synced in any storage unit", item.Hash)
+		return
+	}
+
+	for _, u := range s.Units {
+		iu := item.Unit(u.name)
+		if iu == nil {
+			item.Units = append(item.Units, sdk.CDNItemUnit{Unit: u.name, Status: sdk.CDNItemUnitStatusMissing})
+			iu = &item.Units[len(item.Units)-1]
+		}
+		if iu.Status == sdk.CDNItemUnitStatusSynced {
+			continue
+		}
+
+		iu.LastCheck = time.Now()
+		r, err := source.fetch(item.Hash)
+		if err != nil {
+			iu.LastError = err.Error()
+			continue
+		}
+		if err := u.store(item.Hash, r); err != nil {
+			iu.LastError = err.Error()
+			continue
+		}
+		iu.Status, err = u.verify(*item)
+		if err != nil {
+			iu.LastError = err.Error()
+			continue
+		}
+		iu.LastError = ""
+		log.Debug("CDN> Item %s synced from %s to %s", item.Hash, source.name, u.name)
+	}
+}
+
+// checkItem verifies the content of an item in the storage units where it is synced
+func (s *Service) checkItem(item *sdk.CDNItem) {
+	for _, u := range s.Units {
+		iu := item.Unit(u.name)
+		if iu == nil || iu.Status != sdk.CDNItemUnitStatusSynced {
+			continue
+		}
+
+		var err error
+		iu.LastCheck = time.Now()
+		iu.Status, err = u.verify(*item)
+		if err != nil {
+			iu.LastError = err.Error()
+			log.Warning("CDN> Item %s is %s in %s: %v", item.Hash, iu.Status, u.name, err)
+			continue
+		}
+		iu.LastError = ""
+	}
+}
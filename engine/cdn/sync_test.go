@@ -0,0 +1,98 @@
+package cdn
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/engine/api/objectstore"
+	"github.com/ovh/cds/sdk"
+)
+
+func newTestService(t *testing.T) (*Service, func()) {
+	dir, err := ioutil.TempDir("", "cdn")
+	assert.NoError(t, err)
+
+	s := New()
+	s.Cfg.Storage.Buffer.Basedir = path.Join(dir, "buffer")
+	s.Cfg.Storage.Filesystem.Basedir = path.Join(dir, "filesystem")
+	assert.NoError(t, s.initUnits())
+	return s, func() { os.RemoveAll(dir) }
+}
+
+func TestSyncItem(t *testing.T) {
+	s, cleanup := newTestService(t)
+	defer cleanup()
+
+	content := []byte("my logs")
+	hash, size, err := hashContent(bytes.NewReader(content))
+	assert.NoError(t, err)
+	assert.NoError(t, s.unit(unitBuffer).store(hash, ioutil.NopCloser(bytes.NewReader(content))))
+
+	item := newItem(hash, sdk.CDNItemTypeLog, size, s.Units)
+	assert.Equal(t, int64(len(content)), item.Size)
+	assert.False(t, item.Synced())
+	assert.Equal(t, sdk.CDNItemUnitStatusSynced, item.Unit(unitBuffer).Status)
+	assert.Equal(t, sdk.CDNItemUnitStatusMissing, item.Unit(unitFilesystem).Status)
+
+	s.syncItem(&item)
+	assert.True(t, item.Synced())
+	assert.Empty(t, item.Unit(unitFilesystem).LastError)
+
+	r, err := s.unit(unitFilesystem).fetch(hash)
+	assert.NoError(t, err)
+	btes, err := ioutil.ReadAll(r)
+	r.Close()
+	assert.NoError(t, err)
+	assert.Equal(t, content, btes)
+}
+
+func TestCheckItem(t *testing.T) {
+	s, cleanup := newTestService(t)
+	defer cleanup()
+
+	content := []byte("my artifact")
+	hash, size, err := hashContent(bytes.NewReader(content))
+	assert.NoError(t, err)
+	assert.NoError(t, s.unit(unitBuffer).store(hash, ioutil.NopCloser(bytes.NewReader(content))))
+	item := newItem(hash, sdk.CDNItemTypeArtifact, size, s.Units)
+	s.syncItem(&item)
+	assert.True(t, item.Synced())
+
+	// Corrupt the item in the filesystem unit and remove it from the buffer
+	fs, err := objectstore.NewFilesystemStore(s.Cfg.Storage.Filesystem.Basedir)
+	assert.NoError(t, err)
+	_, err = fs.Store(itemObject{hash: hash}, ioutil.NopCloser(bytes.NewReader([]byte("something else"))))
+	assert.NoError(t, err)
+	assert.NoError(t, s.unit(unitBuffer).driver.Delete(itemObject{hash: hash}))
+
+	s.checkItem(&item)
+	assert.Equal(t, sdk.CDNItemUnitStatusMissing, item.Unit(unitBuffer).Status)
+	assert.Equal(t, sdk.CDNItemUnitStatusCorrupted, item.Unit(unitFilesystem).Status)
+	assert.NotEmpty(t, item.Unit(unitFilesystem).LastError)
+	assert.True(t, itemHasStatus(item, unitFilesystem, sdk.CDNItemUnitStatusCorrupted))
+	assert.False(t, itemHasStatus(item, unitBuffer, sdk.CDNItemUnitStatusCorrupted))
+
+	units := s.unitsState([]sdk.CDNItem{item})
+	assert.Len(t, units, 2)
+	assert.Equal(t, 1, units[0].NbMissing)
+	assert.Equal(t, 1, units[1].NbCorrupted)
+
+	// No unit has a valid copy of the item anymore, it cannot be repaired
+	s.syncItem(&item)
+	assert.False(t, item.Synced())
+}
+
+func TestPromoteItem(t *testing.T) {
+	item := sdk.CDNItem{Type: sdk.CDNItemTypeTmpFile}
+	promoteItem(&item, sdk.CDNItemTypeArtifact)
+	assert.Equal(t, sdk.CDNItemTypeArtifact, item.Type)
+
+	// Only temporary files are promoted
+	promoteItem(&item, sdk.CDNItemTypeLog)
+	assert.Equal(t, sdk.CDNItemTypeArtifact, item.Type)
+}
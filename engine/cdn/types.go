@@ -0,0 +1,54 @@
+package cdn
+
+import (
+	"github.com/ovh/cds/engine/api"
+	"github.com/ovh/cds/engine/api/cache"
+	"github.com/ovh/cds/engine/service"
+)
+
+// Service is the CDN service
+type Service struct {
+	service.Common
+	Cfg    Configuration
+	Router *api.Router
+	Cache  cache.Store
+	Dao    dao
+	Units  []*storageUnit
+}
+
+// Configuration is the CDN configuration structure
+type Configuration struct {
+	Name string `toml:"name" comment:"Name of this CDS CDN Service\n Enter a name to enable this service" json:"name"`
+	HTTP struct {
+		Addr string `toml:"addr" default:"" commented:"true" comment:"Listen address without port, example: 127.0.0.1" json:"addr"`
+		Port int    `toml:"port" default:"8089" json:"port"`
+	} `toml:"http" comment:"######################\n CDS CDN HTTP Configuration \n######################" json:"http"`
+	URL     string `default:"http://localhost:8089" json:"url"`
+	Storage struct {
+		Buffer struct {
+			Basedir string `toml:"basedir" default:"/tmp/cds/cdn/buffer" comment:"Items are written in this directory before being replicated in the other storage units. It must be shared if you run several CDN services" json:"basedir"`
+		} `toml:"buffer" json:"buffer"`
+		Filesystem struct {
+			Basedir string `toml:"basedir" default:"" commented:"true" comment:"Replicate the items in this directory, leave it empty to disable this storage unit" json:"basedir"`
+		} `toml:"filesystem" json:"filesystem"`
+		Swift struct {
+			URL             string `toml:"url" default:"" commented:"true" comment:"Authentication Endpoint, leave it empty to disable this storage unit" json:"url"`
+			Username        string `toml:"username" default:"" commented:"true" json:"username"`
+			Password        string `toml:"password" default:"" commented:"true" json:"-"`
+			Tenant          string `toml:"tenant" default:"" commented:"true" comment:"Openstack Tenant, generally value of $OS_TENANT_NAME, v2 auth only" json:"tenant"`
+			Domain          string `toml:"domain" default:"" commented:"true" comment:"User domain name, generally value of $OS_DOMAIN_NAME, v3 auth only" json:"domain"`
+			Region          string `toml:"region" default:"" commented:"true" comment:"Region, generally value of $OS_REGION_NAME" json:"region"`
+			ContainerPrefix string `toml:"containerPrefix" default:"" commented:"true" comment:"Use if your want to prefix containers for CDS CDN items" json:"containerPrefix"`
+		} `toml:"swift" json:"swift"`
+	} `toml:"storage" comment:"######################\n CDS CDN Storage Units \n Items are stored in the buffer and replicated in all the other enabled units, at least one is required\n######################" json:"storage"`
+	SyncInterval  int                             `toml:"syncInterval" default:"10" comment:"Interval in seconds between two replications of the items in the storage units" json:"syncInterval"`
+	CheckInterval int                             `toml:"checkInterval" default:"60" comment:"Interval in minutes between two integrity verifications of the items in the storage units, 0 to disable them" json:"checkInterval"`
+	API           service.APIServiceConfiguration `toml:"api" comment:"######################\n CDS API Settings \n######################" json:"api"`
+	Cache         struct {
+		TTL   int `toml:"ttl" default:"60" json:"ttl"`
+		Redis struct {
+			Host     string `toml:"host" default:"localhost:6379" comment:"If your want to use a redis-sentinel based cluster, follow this syntax! <clustername>@sentinel1:26379,sentinel2:26379,sentinel3:26379\nIf your want to use a redis cluster, list its nodes: node1:7000,node2:7000,node3:7000" json:"host"`
+			Password string `toml:"password" json:"-"`
+		} `toml:"redis" comment:"Connect CDS to a redis cache to store the state of the items" json:"redis"`
+	} `toml:"cache" comment:"######################\n CDS CDN Cache Settings \n######################" json:"cache"`
+}
@@ -12,10 +12,13 @@ import (
 	"github.com/ovh/cds/engine/api"
 	"github.com/ovh/cds/engine/api/observability"
 	"github.com/ovh/cds/engine/api/secret"
+	"github.com/ovh/cds/engine/cdn"
 	"github.com/ovh/cds/engine/elasticsearch"
+	"github.com/ovh/cds/engine/hatchery/aws"
 	"github.com/ovh/cds/engine/hatchery/kubernetes"
 	"github.com/ovh/cds/engine/hatchery/local"
 	"github.com/ovh/cds/engine/hatchery/marathon"
+	"github.com/ovh/cds/engine/hatchery/nomad"
 	"github.com/ovh/cds/engine/hatchery/openstack"
 	"github.com/ovh/cds/engine/hatchery/swarm"
 	"github.com/ovh/cds/engine/hatchery/vsphere"
@@ -48,6 +51,12 @@ func configSetDefaults() {
 	if conf.Hatchery != nil && conf.Hatchery.Marathon != nil {
 		defaults.SetDefaults(conf.Hatchery.Marathon)
 	}
+	if conf.Hatchery != nil && conf.Hatchery.AWS != nil {
+		defaults.SetDefaults(conf.Hatchery.AWS)
+	}
+	if conf.Hatchery != nil && conf.Hatchery.Nomad != nil {
+		defaults.SetDefaults(conf.Hatchery.Nomad)
+	}
 	if conf.Hatchery != nil && conf.Hatchery.Openstack != nil {
 		defaults.SetDefaults(conf.Hatchery.Openstack)
 	}
@@ -69,6 +78,9 @@ func configSetDefaults() {
 	if conf.ElasticSearch != nil {
 		defaults.SetDefaults(conf.ElasticSearch)
 	}
+	if conf.CDN != nil {
+		defaults.SetDefaults(conf.CDN)
+	}
 }
 
 // config reads in config file and ENV variables if set.
@@ -99,10 +111,18 @@ func configBootstrap(args []string) {
 			if conf.Hatchery.Kubernetes == nil {
 				conf.Hatchery.Kubernetes = &kubernetes.HatcheryConfiguration{}
 			}
+		case "hatchery:aws":
+			if conf.Hatchery.AWS == nil {
+				conf.Hatchery.AWS = &aws.HatcheryConfiguration{}
+			}
 		case "hatchery:marathon":
 			if conf.Hatchery.Marathon == nil {
 				conf.Hatchery.Marathon = &marathon.HatcheryConfiguration{}
 			}
+		case "hatchery:nomad":
+			if conf.Hatchery.Nomad == nil {
+				conf.Hatchery.Nomad = &nomad.HatcheryConfiguration{}
+			}
 		case "hatchery:openstack":
 			if conf.Hatchery.Openstack == nil {
 				conf.Hatchery.Openstack = &openstack.HatcheryConfiguration{}
@@ -131,6 +151,10 @@ func configBootstrap(args []string) {
 			if conf.ElasticSearch == nil {
 				conf.ElasticSearch = &elasticsearch.Configuration{}
 			}
+		case "cdn":
+			if conf.CDN == nil {
+				conf.CDN = &cdn.Configuration{}
+			}
 		default:
 			fmt.Printf("Error: service '%s' unknown\n", a)
 			os.Exit(1)
@@ -146,6 +170,8 @@ func configBootstrap(args []string) {
 		conf.Hatchery.Local = &local.HatcheryConfiguration{}
 		conf.Hatchery.Kubernetes = &kubernetes.HatcheryConfiguration{}
 		conf.Hatchery.Marathon = &marathon.HatcheryConfiguration{}
+		conf.Hatchery.AWS = &aws.HatcheryConfiguration{}
+		conf.Hatchery.Nomad = &nomad.HatcheryConfiguration{}
 		conf.Hatchery.Openstack = &openstack.HatcheryConfiguration{}
 		conf.Hatchery.Swarm = &swarm.HatcheryConfiguration{}
 		conf.Hatchery.VSphere = &vsphere.HatcheryConfiguration{}
@@ -153,6 +179,7 @@ func configBootstrap(args []string) {
 		conf.VCS = &vcs.Configuration{}
 		conf.Repositories = &repositories.Configuration{}
 		conf.ElasticSearch = &elasticsearch.Configuration{}
+		conf.CDN = &cdn.Configuration{}
 	}
 }
 
@@ -0,0 +1,131 @@
+package elasticsearch
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"gopkg.in/olivere/elastic.v5"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// Drivers of the search server
+const (
+	DriverAuto          = "auto"
+	DriverElasticsearch = "elasticsearch"
+	DriverOpenSearch    = "opensearch"
+)
+
+// typelessDocumentType is the only document type accepted by the servers without mapping types
+const typelessDocumentType = "_doc"
+
+// serverInfo describes the search server the service is connected to
+type serverInfo struct {
+	Distribution string
+	Version      string
+	Major        int
+}
+
+// typeless returns true if the server does not support custom mapping types: elasticsearch 7+ and opensearch
+func (i serverInfo) typeless() bool {
+	return i.Distribution == DriverOpenSearch || i.Major >= 7
+}
+
+// indexPatterns returns true if the index templates use index_patterns instead of template: elasticsearch 6+ and opensearch
+func (i serverInfo) indexPatterns() bool {
+	return i.Distribution == DriverOpenSearch || i.Major >= 6
+}
+
+// parseServerInfo reads the distribution and the version of the server from the response of its root endpoint
+func parseServerInfo(body []byte) (serverInfo, error) {
+	var root struct {
+		Version struct {
+			Number       string `json:"number"`
+			Distribution string `json:"distribution"`
+		} `json:"version"`
+	}
+	if err := json.Unmarshal(body, &root); err != nil {
+		return serverInfo{}, sdk.WrapError(err, "unable to read server version")
+	}
+	i := serverInfo{Distribution: root.Version.Distribution, Version: root.Version.Number}
+	if i.Distribution == "" {
+		i.Distribution = DriverElasticsearch
+	}
+	major, err := strconv.Atoi(strings.SplitN(i.Version, ".", 2)[0])
+	if err != nil {
+		return serverInfo{}, fmt.Errorf("invalid server version %q", i.Version)
+	}
+	i.Major = major
+	return i, nil
+}
+
+// negotiateVersion detects the distribution and the version of the server, and checks them against the configured driver
+func (s *Service) negotiateVersion(ctx context.Context) error {
+	res, err := esClient.PerformRequest(ctx, http.MethodGet, "/", nil, nil)
+	if err != nil {
+		return sdk.WrapError(err, "unable to get server version")
+	}
+	info, err := parseServerInfo(res.Body)
+	if err != nil {
+		return err
+	}
+
+	switch s.Cfg.ElasticSearch.Driver {
+	case "", DriverAuto:
+	case DriverOpenSearch:
+		// Some managed offers, like AWS OpenSearch in compatibility mode, report themselves as elasticsearch 7.10
+		if info.Distribution != DriverOpenSearch {
+			log.Warning("ElasticSearch> Driver %s is configured but server reports %s %s", DriverOpenSearch, info.Distribution, info.Version)
+			info.Distribution = DriverOpenSearch
+		}
+	case DriverElasticsearch:
+		if info.Distribution != DriverElasticsearch {
+			return fmt.Errorf("driver %s is configured but server is %s %s", DriverElasticsearch, info.Distribution, info.Version)
+		}
+	default:
+		return fmt.Errorf("unknown driver %s", s.Cfg.ElasticSearch.Driver)
+	}
+
+	s.server = info
+	log.Info("ElasticSearch> Connected to %s %s", info.Distribution, info.Version)
+	return nil
+}
+
+// documentType returns the mapping type to use to store a document of the given type
+func (s *Service) documentType(t string) string {
+	if s.server.typeless() {
+		return typelessDocumentType
+	}
+	return t
+}
+
+// search runs a search on the server. On servers without mapping types, the documents are not filtered on their type,
+// the caller has to filter them on a field of the documents.
+func (s *Service) search(ctx context.Context, index, typ string, source *elastic.SearchSource) (*elastic.SearchResult, error) {
+	if !s.server.typeless() {
+		return esClient.Search().Index(index).Type(typ).SearchSource(source).Do(ctx)
+	}
+
+	body, err := source.Source()
+	if err != nil {
+		return nil, sdk.WithStack(err)
+	}
+	// The total of the hits is an object since elasticsearch 7, the client expects an integer
+	params := url.Values{}
+	params.Set("rest_total_hits_as_int", "true")
+	res, err := esClient.PerformRequest(ctx, http.MethodPost, "/"+url.PathEscape(index)+"/_search", params, body)
+	if err != nil {
+		return nil, err
+	}
+	var result elastic.SearchResult
+	if err := json.Unmarshal(res.Body, &result); err != nil {
+		return nil, sdk.WrapError(err, "unable to read search result")
+	}
+	return &result, nil
+}
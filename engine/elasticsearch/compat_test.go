@@ -0,0 +1,30 @@
+package elasticsearch
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseServerInfo(t *testing.T) {
+	i, err := parseServerInfo([]byte(`{"name":"node","version":{"number":"5.6.16","lucene_version":"6.6.1"}}`))
+	assert.NoError(t, err)
+	assert.Equal(t, DriverElasticsearch, i.Distribution)
+	assert.Equal(t, 5, i.Major)
+	assert.False(t, i.typeless())
+	assert.False(t, i.indexPatterns())
+
+	i, err = parseServerInfo([]byte(`{"name":"node","version":{"distribution":"opensearch","number":"2.11.0"}}`))
+	assert.NoError(t, err)
+	assert.Equal(t, DriverOpenSearch, i.Distribution)
+	assert.Equal(t, 2, i.Major)
+	assert.True(t, i.typeless())
+	assert.True(t, i.indexPatterns())
+
+	i, err = parseServerInfo([]byte(`{"name":"node","version":{"number":"7.10.2"}}`))
+	assert.NoError(t, err)
+	assert.True(t, i.typeless())
+
+	_, err = parseServerInfo([]byte(`{"version":{"number":""}}`))
+	assert.Error(t, err)
+}
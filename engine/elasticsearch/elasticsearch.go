@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/gorilla/mux"
@@ -63,6 +64,21 @@ func (s *Service) CheckConfiguration(config interface{}) error {
 	if sConfig.Name == "" {
 		return fmt.Errorf("please enter a name in your Elasticsearch configuration")
 	}
+	switch sConfig.ElasticSearch.Driver {
+	case "", DriverAuto, DriverElasticsearch, DriverOpenSearch:
+	default:
+		return fmt.Errorf("invalid driver %s in your Elasticsearch configuration", sConfig.ElasticSearch.Driver)
+	}
+	if sConfig.ElasticSearch.Lifecycle.Enabled {
+		events, metrics := sConfig.ElasticSearch.IndexEvents, sConfig.ElasticSearch.IndexMetrics
+		if events == "" && metrics == "" {
+			return fmt.Errorf("please enter an events or metrics index in your Elasticsearch configuration to enable its lifecycle")
+		}
+		// the indices of an alias are matched with the pattern <alias>-*
+		if events != "" && metrics != "" && (events == metrics || strings.HasPrefix(metrics, events+"-") || strings.HasPrefix(events, metrics+"-")) {
+			return fmt.Errorf("the events index %s and the metrics index %s of your Elasticsearch configuration overlap", events, metrics)
+		}
+	}
 
 	return nil
 }
@@ -78,6 +94,20 @@ func (s *Service) Serve(c context.Context) error {
 	if errClient != nil {
 		return sdk.WrapError(errClient, "Unable to create elasticsearchclient")
 	}
+	if err := s.negotiateVersion(ctx); err != nil {
+		return err
+	}
+
+	if s.Cfg.ElasticSearch.Lifecycle.Enabled {
+		if err := s.initLifecycle(ctx); err != nil {
+			return err
+		}
+		go func() {
+			if err := s.lifecycle(ctx); err != nil {
+				log.Info("ElasticSearch> Shutdown lifecycle")
+			}
+		}()
+	}
 
 	//Init the http server
 	s.initRouter(ctx)
@@ -32,7 +32,13 @@ func (s *Service) getEventsHandler() service.Handler {
 
 		}
 
-		result, errR := esClient.Search().Index(s.Cfg.ElasticSearch.IndexEvents).Type("sdk.EventRunWorkflow").Query(boolQuery).Sort("timestamp", false).From(filters.CurrentItem).Size(15).Do(context.Background())
+		eventType := fmt.Sprintf("%T", sdk.EventRunWorkflow{})
+		if s.server.typeless() {
+			boolQuery.Filter(elastic.NewTermQuery("type_event", eventType))
+		}
+
+		source := elastic.NewSearchSource().Query(boolQuery).Sort("timestamp", false).From(filters.CurrentItem).Size(15)
+		result, errR := s.search(ctx, s.eventsReadIndex(), eventType, source)
 		if errR != nil {
 			return sdk.WrapError(errR, "Cannot get result on index: %s", s.Cfg.ElasticSearch.IndexEvents)
 		}
@@ -51,7 +57,7 @@ func (s *Service) postEventHandler() service.Handler {
 			return sdk.WrapError(err, "Unable to read body")
 		}
 
-		_, errI := esClient.Index().Index(s.Cfg.ElasticSearch.IndexEvents).Type(e.EventType).BodyJson(e).Do(context.Background())
+		_, errI := esClient.Index().Index(s.Cfg.ElasticSearch.IndexEvents).Type(s.documentType(e.EventType)).BodyJson(e).Do(context.Background())
 		if errI != nil {
 			return sdk.WrapError(errI, "Unable to insert event")
 		}
@@ -78,13 +84,11 @@ func (s *Service) getMetricsHandler() service.Handler {
 			stringQuery = fmt.Sprintf("%s AND workflow_id:%d", stringQuery, request.WorkflowID)
 		}
 
-		results, errR := esClient.Search().
-			Index(s.Cfg.ElasticSearch.IndexMetrics).
-			Type(fmt.Sprintf("%T", sdk.Metric{})).
+		source := elastic.NewSearchSource().
 			Query(elastic.NewBoolQuery().Must(elastic.NewQueryStringQuery(stringQuery))).
 			Sort("run", false).
-			Size(10).
-			Do(context.Background())
+			Size(10)
+		results, errR := s.search(ctx, s.metricsReadIndex(), fmt.Sprintf("%T", sdk.Metric{}), source)
 		if errR != nil {
 			return sdk.WrapError(errR, "Unable to get result")
 		}
@@ -107,15 +111,24 @@ func (s *Service) postMetricsHandler() service.Handler {
 		id := fmt.Sprintf("%s-%d-%d-%d-%s", metric.ProjectKey, metric.WorkflowID, metric.ApplicationID, metric.Num, metric.Key)
 
 		// Get metrics if already exists
-		existingMetric, err := s.loadMetric(id)
+		existingMetric, index, err := s.loadMetric(id)
 		if err != nil {
 			return sdk.WrapError(err, "unable to load metric")
 		}
 		if existingMetric.Value != nil {
 			s.mergeMetric(&metric, existingMetric.Value)
 		}
+		// An existing metric is updated in its index, that may have been rolled over, to keep a single document by id
+		if index == "" {
+			index = s.Cfg.ElasticSearch.IndexMetrics
+		}
 
-		_, errI := esClient.Index().Index(s.Cfg.ElasticSearch.IndexMetrics).Id(id).Type(fmt.Sprintf("%T", sdk.Metric{})).Timestamp(strconv.Itoa(int(metric.Date.Unix()))).BodyJson(metric).Do(context.Background())
+		req := esClient.Index().Index(index).Id(id).Type(s.documentType(fmt.Sprintf("%T", sdk.Metric{}))).BodyJson(metric)
+		// The _timestamp field has been removed in elasticsearch 6
+		if !s.server.indexPatterns() {
+			req = req.Timestamp(strconv.Itoa(int(metric.Date.Unix())))
+		}
+		_, errI := req.Do(context.Background())
 		if errI != nil {
 			return sdk.WrapError(errI, "Unable to insert event")
 		}
@@ -123,6 +136,59 @@ func (s *Service) postMetricsHandler() service.Handler {
 	}
 }
 
+func (s *Service) getIndicesHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		if !s.Cfg.ElasticSearch.Lifecycle.Enabled {
+			return sdk.NewErrorFrom(sdk.ErrNotFound, "lifecycle of the indices is not enabled")
+		}
+		indices, err := s.listIndices(ctx)
+		if err != nil {
+			return err
+		}
+		return service.WriteJSON(w, indices, http.StatusOK)
+	}
+}
+
+func (s *Service) postRolloverHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		if !s.Cfg.ElasticSearch.Lifecycle.Enabled {
+			return sdk.NewErrorFrom(sdk.ErrNotFound, "lifecycle of the indices is not enabled")
+		}
+		res, err := s.rollover(ctx, true)
+		if err != nil {
+			return err
+		}
+		return service.WriteJSON(w, res, http.StatusOK)
+	}
+}
+
+func (s *Service) postRetentionHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		if !s.Cfg.ElasticSearch.Lifecycle.Enabled {
+			return sdk.NewErrorFrom(sdk.ErrNotFound, "lifecycle of the indices is not enabled")
+		}
+		deleted, err := s.applyRetention(ctx)
+		if err != nil {
+			return err
+		}
+		return service.WriteJSON(w, deleted, http.StatusOK)
+	}
+}
+
+func (s *Service) postReindexHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		var req ReindexRequest
+		if err := service.UnmarshalBody(r, &req); err != nil {
+			return sdk.WrapError(err, "Unable to read body")
+		}
+		res, err := s.reindex(ctx, req)
+		if err != nil {
+			return err
+		}
+		return service.WriteJSON(w, res, http.StatusOK)
+	}
+}
+
 func (s *Service) getStatusHandler() service.Handler {
 	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
 		var status = http.StatusOK
@@ -130,28 +196,27 @@ func (s *Service) getStatusHandler() service.Handler {
 	}
 }
 
-func (s *Service) loadMetric(ID string) (sdk.Metric, error) {
+// loadMetric returns the metric with the given id and the index it is stored in
+func (s *Service) loadMetric(ID string) (sdk.Metric, string, error) {
 	var m sdk.Metric
-	results, errR := esClient.Search().
-		Index(s.Cfg.ElasticSearch.IndexMetrics).
-		Type(fmt.Sprintf("%T", sdk.Metric{})).
+	source := elastic.NewSearchSource().
 		Query(elastic.NewBoolQuery().Must(elastic.NewQueryStringQuery(fmt.Sprintf("_id:%s", ID)))).
 		Sort("_score", false).
 		Sort("run", false).
-		Size(10).
-		Do(context.Background())
+		Size(10)
+	results, errR := s.search(context.Background(), s.metricsReadIndex(), fmt.Sprintf("%T", sdk.Metric{}), source)
 	if errR != nil {
-		return m, sdk.WrapError(errR, "unable to get result")
+		return m, "", sdk.WrapError(errR, "unable to get result")
 	}
 
 	if len(results.Hits.Hits) == 0 {
-		return m, nil
+		return m, "", nil
 	}
 
 	if err := json.Unmarshal(*results.Hits.Hits[0].Source, &m); err != nil {
-		return m, err
+		return m, "", err
 	}
-	return m, nil
+	return m, results.Hits.Hits[0].Index, nil
 }
 
 func (s *Service) mergeMetric(newMetric *sdk.Metric, oldMetricValue map[string]float64) {
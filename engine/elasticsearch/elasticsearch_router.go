@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/ovh/cds/engine/api"
+	"github.com/ovh/cds/engine/api/observability"
 	"github.com/ovh/cds/sdk/log"
 )
 
@@ -16,6 +17,16 @@ func (s *Service) initRouter(ctx context.Context) {
 
 	r.Handle("/mon/version", r.GET(api.VersionHandler, api.Auth(false)))
 	r.Handle("/mon/status", r.GET(s.getStatusHandler))
+	r.Handle("/mon/metrics", r.GET(observability.StatsHandler, api.Auth(false)))
 	r.Handle("/events", r.GET(s.getEventsHandler), r.POST(s.postEventHandler))
 	r.Handle("/metrics", r.GET(s.getMetricsHandler), r.POST(s.postMetricsHandler))
+	r.Handle("/search/runs", r.POST(s.postSearchRunsHandler))
+	r.Handle("/indices", r.GET(s.getIndicesHandler))
+	r.Handle("/indices/rollover", r.POST(s.postRolloverHandler))
+	r.Handle("/indices/retention", r.POST(s.postRetentionHandler))
+	r.Handle("/indices/reindex", r.POST(s.postReindexHandler))
+
+	if err := r.InitMetrics("cds-elasticsearch", s.Cfg.Name); err != nil {
+		log.Error("unable to init router metrics: %v", err)
+	}
 }
@@ -0,0 +1,25 @@
+package elasticsearch
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gorilla/mux"
+
+	"github.com/ovh/cds/engine/api"
+	"github.com/ovh/cds/engine/api/test"
+	"github.com/ovh/cds/engine/api/test/assets"
+)
+
+func Test_getMetricsHandler(t *testing.T) {
+	ctx := context.Background()
+	s := new(Service)
+	s.Hash = "hash"
+	s.Router = &api.Router{
+		Mux:        mux.NewRouter(),
+		Prefix:     "/" + test.GetTestName(t),
+		Background: ctx,
+	}
+	s.initRouter(ctx)
+	assets.CheckMetricsHandler(t, s.Router, s.Router.Mux, "cds-elasticsearch")
+}
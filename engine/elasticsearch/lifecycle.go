@@ -0,0 +1,262 @@
+package elasticsearch
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"gopkg.in/olivere/elastic.v5"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// IndexInfo is an index managed by the lifecycle of the service
+type IndexInfo struct {
+	Name    string    `json:"name" cli:"name,key"`
+	Alias   string    `json:"alias" cli:"alias"`
+	Created time.Time `json:"created" cli:"created"`
+	Write   bool      `json:"write" cli:"write"`
+}
+
+// ReindexRequest copies the documents of an index to another one
+type ReindexRequest struct {
+	Source      string `json:"source"`
+	Destination string `json:"destination"`
+}
+
+// lifecycleAliases returns the aliases of the events and metrics indices managed by the lifecycle
+func (s *Service) lifecycleAliases() []string {
+	var aliases []string
+	for _, alias := range []string{s.Cfg.ElasticSearch.IndexEvents, s.Cfg.ElasticSearch.IndexMetrics} {
+		if alias != "" {
+			aliases = append(aliases, alias)
+		}
+	}
+	return aliases
+}
+
+// readIndex returns the indices to search the documents written through the alias in
+func (s *Service) readIndex(alias string) string {
+	if s.Cfg.ElasticSearch.Lifecycle.Enabled {
+		return alias + "-*"
+	}
+	return alias
+}
+
+// eventsReadIndex returns the indices to search events in
+func (s *Service) eventsReadIndex() string {
+	return s.readIndex(s.Cfg.ElasticSearch.IndexEvents)
+}
+
+// metricsReadIndex returns the indices to search metrics in
+func (s *Service) metricsReadIndex() string {
+	return s.readIndex(s.Cfg.ElasticSearch.IndexMetrics)
+}
+
+// initLifecycle creates the templates of the events and metrics indices, and the first index behind each alias
+// if it does not exist
+func (s *Service) initLifecycle(ctx context.Context) error {
+	for _, alias := range s.lifecycleAliases() {
+		if err := s.initAlias(ctx, alias); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// initAlias creates the template of the indices of the alias, and its first index if it does not exist
+func (s *Service) initAlias(ctx context.Context, alias string) error {
+	cfg := s.Cfg.ElasticSearch.Lifecycle
+
+	settings := map[string]interface{}{}
+	if cfg.Shards > 0 {
+		settings["number_of_shards"] = cfg.Shards
+	}
+	if cfg.Replicas >= 0 {
+		settings["number_of_replicas"] = cfg.Replicas
+	}
+	template := map[string]interface{}{
+		"settings": settings,
+	}
+	if s.server.indexPatterns() {
+		template["index_patterns"] = []string{alias + "-*"}
+	} else {
+		template["template"] = alias + "-*"
+	}
+	if _, err := esClient.IndexPutTemplate(alias).BodyJson(template).Do(ctx); err != nil {
+		return sdk.WrapError(err, "unable to put template %s", alias)
+	}
+
+	exists, err := esClient.IndexExists(alias).Do(ctx)
+	if err != nil {
+		return sdk.WrapError(err, "unable to check alias %s", alias)
+	}
+	if exists {
+		return nil
+	}
+
+	// The name of the indices must end with a number to be incremented by the rollover
+	body := map[string]interface{}{
+		"aliases": map[string]interface{}{alias: map[string]interface{}{}},
+	}
+	if _, err := esClient.CreateIndex(alias + "-000001").BodyJson(body).Do(ctx); err != nil {
+		return sdk.WrapError(err, "unable to create first index of alias %s", alias)
+	}
+	log.Info("ElasticSearch> Index %s-000001 created for alias %s", alias, alias)
+	return nil
+}
+
+// lifecycle applies periodically the rollover and the retention policies on the events and metrics indices
+func (s *Service) lifecycle(ctx context.Context) error {
+	interval := s.Cfg.ElasticSearch.Lifecycle.CheckInterval
+	if interval <= 0 {
+		interval = 10
+	}
+	tick := time.NewTicker(time.Duration(interval) * time.Minute)
+	defer tick.Stop()
+	for {
+		select {
+		case <-tick.C:
+			if _, err := s.rollover(ctx, false); err != nil {
+				log.Error("ElasticSearch.lifecycle> %v", err)
+			}
+			if _, err := s.applyRetention(ctx); err != nil {
+				log.Error("ElasticSearch.lifecycle> %v", err)
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// rollover creates a new index behind each alias if its current index matches the rollover conditions,
+// or in any case if force is true
+func (s *Service) rollover(ctx context.Context, force bool) ([]*elastic.IndicesRolloverResponse, error) {
+	var responses []*elastic.IndicesRolloverResponse
+	for _, alias := range s.lifecycleAliases() {
+		res, err := s.rolloverAlias(ctx, alias, force)
+		if err != nil {
+			return responses, err
+		}
+		if res != nil {
+			responses = append(responses, res)
+		}
+	}
+	return responses, nil
+}
+
+// rolloverAlias creates a new index behind the alias if the current one matches the rollover conditions,
+// or in any case if force is true
+func (s *Service) rolloverAlias(ctx context.Context, alias string, force bool) (*elastic.IndicesRolloverResponse, error) {
+	cfg := s.Cfg.ElasticSearch.Lifecycle
+
+	req := esClient.RolloverIndex(alias)
+	if !force {
+		if cfg.RolloverMaxAge == "" && cfg.RolloverMaxDocs <= 0 {
+			return nil, nil
+		}
+		if cfg.RolloverMaxAge != "" {
+			req = req.AddMaxIndexAgeCondition(cfg.RolloverMaxAge)
+		}
+		if cfg.RolloverMaxDocs > 0 {
+			req = req.AddMaxIndexDocsCondition(cfg.RolloverMaxDocs)
+		}
+	}
+	res, err := req.Do(ctx)
+	if err != nil {
+		return nil, sdk.WrapError(err, "unable to roll over alias %s", alias)
+	}
+	if res.RolledOver {
+		log.Info("ElasticSearch> Alias %s rolled over from %s to %s", alias, res.OldIndex, res.NewIndex)
+	}
+	return res, nil
+}
+
+// listIndices returns the indices of the events and metrics aliases, from the oldest
+func (s *Service) listIndices(ctx context.Context) ([]IndexInfo, error) {
+	var indices []IndexInfo
+	for _, alias := range s.lifecycleAliases() {
+		is, err := s.listAliasIndices(ctx, alias)
+		if err != nil {
+			return nil, err
+		}
+		indices = append(indices, is...)
+	}
+	return indices, nil
+}
+
+// listAliasIndices returns the indices of the alias, from the oldest
+func (s *Service) listAliasIndices(ctx context.Context, alias string) ([]IndexInfo, error) {
+	settings, err := esClient.IndexGetSettings(alias + "-*").FlatSettings(true).Do(ctx)
+	if err != nil {
+		return nil, sdk.WrapError(err, "unable to get indices of alias %s", alias)
+	}
+	aliases, err := esClient.Aliases().Index(alias + "-*").Do(ctx)
+	if err != nil {
+		return nil, sdk.WrapError(err, "unable to get alias %s", alias)
+	}
+	writeIndices := aliases.IndicesByAlias(alias)
+
+	indices := make([]IndexInfo, 0, len(settings))
+	for name, res := range settings {
+		i := IndexInfo{Name: name, Alias: alias, Write: sdk.IsInArray(name, writeIndices)}
+		if creation, ok := res.Settings["index.creation_date"].(string); ok {
+			ms, err := strconv.ParseInt(creation, 10, 64)
+			if err != nil {
+				return nil, sdk.WrapError(err, "invalid creation date of index %s", name)
+			}
+			i.Created = time.Unix(0, ms*int64(time.Millisecond))
+		}
+		indices = append(indices, i)
+	}
+	sort.Slice(indices, func(i, j int) bool { return indices[i].Name < indices[j].Name })
+	return indices, nil
+}
+
+// applyRetention deletes the events and metrics indices older than the retention. The index behind each alias
+// is never deleted.
+func (s *Service) applyRetention(ctx context.Context) ([]string, error) {
+	days := s.Cfg.ElasticSearch.Lifecycle.RetentionDays
+	if days <= 0 {
+		return nil, nil
+	}
+	indices, err := s.listIndices(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := time.Now().AddDate(0, 0, -days)
+	var deleted []string
+	for _, i := range indices {
+		if i.Write || i.Created.IsZero() || i.Created.After(limit) {
+			continue
+		}
+		if _, err := esClient.DeleteIndex(i.Name).Do(ctx); err != nil {
+			return deleted, sdk.WrapError(err, "unable to delete index %s", i.Name)
+		}
+		log.Info("ElasticSearch> Index %s deleted, created on %s", i.Name, i.Created)
+		deleted = append(deleted, i.Name)
+	}
+	return deleted, nil
+}
+
+// reindex copies all the documents of an index to another one
+func (s *Service) reindex(ctx context.Context, req ReindexRequest) (*elastic.BulkIndexByScrollResponse, error) {
+	if req.Source == "" || req.Destination == "" {
+		return nil, sdk.NewErrorFrom(sdk.ErrWrongRequest, "source and destination indices are mandatory")
+	}
+	if req.Source == req.Destination {
+		return nil, sdk.NewErrorFrom(sdk.ErrWrongRequest, "source and destination indices must be different")
+	}
+	res, err := esClient.Reindex().SourceIndex(req.Source).DestinationIndex(req.Destination).WaitForCompletion(true).Do(ctx)
+	if err != nil {
+		return nil, sdk.WrapError(err, "unable to reindex %s to %s", req.Source, req.Destination)
+	}
+	if len(res.Failures) > 0 {
+		return res, fmt.Errorf("%d documents were not copied from %s to %s", len(res.Failures), req.Source, req.Destination)
+	}
+	return res, nil
+}
@@ -0,0 +1,20 @@
+package elasticsearch
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLifecycleAliases(t *testing.T) {
+	s := new(Service)
+	s.Cfg.ElasticSearch.IndexEvents = "cds-events"
+	assert.Equal(t, []string{"cds-events"}, s.lifecycleAliases())
+	assert.Equal(t, "cds-events", s.eventsReadIndex())
+
+	s.Cfg.ElasticSearch.IndexMetrics = "cds-metrics"
+	s.Cfg.ElasticSearch.Lifecycle.Enabled = true
+	assert.Equal(t, []string{"cds-events", "cds-metrics"}, s.lifecycleAliases())
+	assert.Equal(t, "cds-events-*", s.eventsReadIndex())
+	assert.Equal(t, "cds-metrics-*", s.metricsReadIndex())
+}
@@ -0,0 +1,120 @@
+package elasticsearch
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"gopkg.in/olivere/elastic.v5"
+
+	"github.com/ovh/cds/engine/service"
+	"github.com/ovh/cds/sdk"
+)
+
+// maxSearchEvents is the maximum number of events read to find the runs matching a search
+const maxSearchEvents = 500
+
+func (s *Service) postSearchRunsHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		if s.Cfg.ElasticSearch.IndexEvents == "" {
+			return sdk.WrapError(sdk.ErrNotFound, "No events index found")
+		}
+
+		var req sdk.SearchRunsRequest
+		if err := service.UnmarshalBody(r, &req); err != nil {
+			return sdk.WrapError(err, "Unable to read body")
+		}
+
+		res, err := s.searchRuns(ctx, req)
+		if err != nil {
+			return err
+		}
+		return service.WriteJSON(w, res, http.StatusOK)
+	}
+}
+
+// searchRuns searches the runs matching a query in the events of the runs. As a run has many events,
+// the latest events are read and only the last one of each run is kept.
+func (s *Service) searchRuns(ctx context.Context, req sdk.SearchRunsRequest) ([]sdk.SearchResult, error) {
+	eventType := fmt.Sprintf("%T", sdk.EventRunWorkflow{})
+	q := req.Query
+
+	boolQuery := elastic.NewBoolQuery()
+	if s.server.typeless() {
+		boolQuery.Filter(elastic.NewTermQuery("type_event", eventType))
+	}
+	if req.ProjectKeys != nil {
+		keys := make([]interface{}, len(req.ProjectKeys))
+		for i := range req.ProjectKeys {
+			keys[i] = req.ProjectKeys[i]
+		}
+		boolQuery.Filter(elastic.NewTermsQuery("project_key.keyword", keys...))
+	}
+	if q.Project != "" {
+		boolQuery.Filter(elastic.NewTermQuery("project_key.keyword", q.Project))
+	}
+	if q.Status != "" {
+		boolQuery.Filter(elastic.NewMatchQuery("status", q.Status))
+	}
+	if q.Branch != "" {
+		boolQuery.Filter(elastic.NewMatchPhraseQuery("tag.value", q.Branch))
+	}
+	if q.Repository != "" {
+		boolQuery.Filter(elastic.NewMatchPhraseQuery("tag.value", q.Repository))
+	}
+	if q.Text != "" {
+		boolQuery.Must(elastic.NewMultiMatchQuery(q.Text, "workflow_name", "tag.value").Lenient(true))
+	}
+
+	size := req.Size * 5
+	if size <= 0 || size > maxSearchEvents {
+		size = maxSearchEvents
+	}
+	source := elastic.NewSearchSource().Query(boolQuery).Sort("timestamp", false).Size(size)
+	result, err := s.search(ctx, s.eventsReadIndex(), eventType, source)
+	if err != nil {
+		return nil, sdk.WrapError(err, "Cannot search runs on index: %s", s.Cfg.ElasticSearch.IndexEvents)
+	}
+
+	res := []sdk.SearchResult{}
+	seen := map[string]struct{}{}
+	for _, h := range result.Hits.Hits {
+		if len(res) >= req.Size {
+			break
+		}
+		var e sdk.Event
+		if h.Source == nil {
+			continue
+		}
+		if err := json.Unmarshal(*h.Source, &e); err != nil {
+			return nil, sdk.WrapError(err, "Unable to read event")
+		}
+		id := fmt.Sprintf("%s/%s/%d", e.ProjectKey, e.WorkflowName, e.WorkflowRunNum)
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+
+		r := sdk.SearchResult{
+			Kind:         sdk.SearchKindRun,
+			ProjectKey:   e.ProjectKey,
+			Name:         e.WorkflowName + "#" + strconv.FormatInt(e.WorkflowRunNum, 10),
+			WorkflowName: e.WorkflowName,
+			RunNumber:    e.WorkflowRunNum,
+			Status:       e.Status,
+			LastModified: e.Timestamp,
+		}
+		for _, t := range e.Tags {
+			switch t.Tag {
+			case "git.repository":
+				r.Repository = t.Value
+			case "git.branch":
+				r.Branch = t.Value
+			}
+		}
+		res = append(res, r)
+	}
+	return res, nil
+}
@@ -10,6 +10,7 @@ type Service struct {
 	service.Common
 	Cfg    Configuration
 	Router *api.Router
+	server serverInfo
 }
 
 // Configuration is the vcs configuration structure
@@ -21,11 +22,24 @@ type Configuration struct {
 	} `toml:"http" comment:"######################\n CDS Elasticsearch HTTP Configuration \n######################" json:"http"`
 	URL           string `default:"http://localhost:8088" json:"url"`
 	ElasticSearch struct {
-		URL          string `toml:"url" json:"url"`
-		Username     string `toml:"username" json:"username"`
-		Password     string `toml:"password" json:"-"`
-		IndexEvents  string `toml:"indexEvents" commented:"true" comment:"index to store CDS events" json:"indexEvents"`
-		IndexMetrics string `toml:"indexMetrics" commented:"true" comment:"index to store CDS metrics" json:"indexMetrics"`
-	} `toml:"elasticsearch" comment:"######################\n CDS ElasticSearch Settings \nSupport for elasticsearch 5.6\n######################" json:"elasticsearch"`
+		URL          string                 `toml:"url" json:"url"`
+		Driver       string                 `toml:"driver" default:"auto" comment:"Search server: elasticsearch, opensearch, or auto to detect it from the server" json:"driver"`
+		Username     string                 `toml:"username" json:"username"`
+		Password     string                 `toml:"password" json:"-"`
+		IndexEvents  string                 `toml:"indexEvents" commented:"true" comment:"index to store CDS events" json:"indexEvents"`
+		IndexMetrics string                 `toml:"indexMetrics" commented:"true" comment:"index to store CDS metrics" json:"indexMetrics"`
+		Lifecycle    LifecycleConfiguration `toml:"lifecycle" comment:"Time-based indices for CDS events and metrics: events and metrics are written through the aliases indexEvents and indexMetrics, which are rolled over to new indices" json:"lifecycle"`
+	} `toml:"elasticsearch" comment:"######################\n CDS ElasticSearch Settings \nSupport for elasticsearch 5.6+ and opensearch\n######################" json:"elasticsearch"`
 	API service.APIServiceConfiguration `toml:"api" comment:"######################\n CDS Indexes Settings \n######################" json:"api"`
 }
+
+// LifecycleConfiguration is the configuration of the time-based indices
+type LifecycleConfiguration struct {
+	Enabled         bool   `toml:"enabled" default:"false" comment:"Enable the rollover of the events and metrics indices. indexEvents and indexMetrics must not be existing indices, use 'cdsctl admin elasticsearch reindex' to copy an existing index" json:"enabled"`
+	RolloverMaxAge  string `toml:"rolloverMaxAge" default:"1d" comment:"Roll over to a new index when the current one is older than this age (example: 1d, 12h)" json:"rolloverMaxAge"`
+	RolloverMaxDocs int64  `toml:"rolloverMaxDocs" default:"0" comment:"Roll over to a new index when the current one contains more documents, 0 to disable" json:"rolloverMaxDocs"`
+	RetentionDays   int    `toml:"retentionDays" default:"30" comment:"Delete the indices older than this number of days, 0 to keep them forever" json:"retentionDays"`
+	Shards          int    `toml:"shards" default:"0" comment:"Number of shards of the new indices, 0 to use the elasticsearch default" json:"shards"`
+	Replicas        int    `toml:"replicas" default:"-1" comment:"Number of replicas of the new indices, -1 to use the elasticsearch default" json:"replicas"`
+	CheckInterval   int    `toml:"checkInterval" default:"10" comment:"Interval in minutes between two checks of the rollover and retention policies" json:"checkInterval"`
+}
@@ -0,0 +1,353 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/gorilla/mux"
+
+	"github.com/ovh/cds/engine/api"
+	"github.com/ovh/cds/engine/api/services"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/cdsclient"
+	"github.com/ovh/cds/sdk/hatchery"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// New instanciates a new Hatchery AWS
+func New() *HatcheryAWS {
+	s := new(HatcheryAWS)
+	s.Router = &api.Router{
+		Mux: mux.NewRouter(),
+	}
+	return s
+}
+
+// ApplyConfiguration apply an object of type HatcheryConfiguration after checking it
+func (h *HatcheryAWS) ApplyConfiguration(cfg interface{}) error {
+	if err := h.CheckConfiguration(cfg); err != nil {
+		return err
+	}
+
+	var ok bool
+	h.Config, ok = cfg.(HatcheryConfiguration)
+	if !ok {
+		return fmt.Errorf("Invalid configuration")
+	}
+
+	h.hatch = &sdk.Hatchery{}
+	h.Client = cdsclient.NewService(h.Config.API.HTTP.URL, 60*time.Second, h.Config.API.HTTP.Insecure)
+	h.API = h.Config.API.HTTP.URL
+	h.Name = h.Config.Name
+	h.HTTPURL = h.Config.URL
+	h.Token = h.Config.API.Token
+	h.Type = services.TypeHatchery
+	h.MaxHeartbeatFailures = h.Config.API.MaxHeartbeatFailures
+	h.Common.Common.ServiceName = "cds-hatchery-aws"
+
+	h.subnets = splitList(h.Config.Subnets)
+	h.securityGroups = splitList(h.Config.SecurityGroups)
+
+	return nil
+}
+
+// Status returns sdk.MonitoringStatus, implements interface service.Service
+func (h *HatcheryAWS) Status() sdk.MonitoringStatus {
+	m := h.CommonMonitoring()
+	if h.IsInitialized() {
+		m.Lines = append(m.Lines, sdk.MonitoringStatusLine{Component: "Workers", Value: fmt.Sprintf("%d/%d", len(h.WorkersStarted()), h.Config.Provision.MaxWorker), Status: sdk.MonitoringStatusOK})
+	}
+	return m
+}
+
+// CheckConfiguration checks the validity of the configuration object
+func (h *HatcheryAWS) CheckConfiguration(cfg interface{}) error {
+	hconfig, ok := cfg.(HatcheryConfiguration)
+	if !ok {
+		return fmt.Errorf("Invalid configuration")
+	}
+
+	if hconfig.API.HTTP.URL == "" {
+		return fmt.Errorf("API HTTP(s) URL is mandatory")
+	}
+
+	if hconfig.API.Token == "" {
+		return fmt.Errorf("API Token URL is mandatory")
+	}
+
+	if hconfig.Region == "" {
+		return fmt.Errorf("AWS region is mandatory")
+	}
+
+	if (hconfig.AccessKeyID == "") != (hconfig.SecretAccessKey == "") {
+		return fmt.Errorf("AWS access key ID and secret access key should be both set or both empty")
+	}
+
+	if hconfig.IdleTimeout < 0 {
+		return fmt.Errorf("idleTimeout should be positive")
+	}
+
+	if hconfig.Name == "" {
+		return fmt.Errorf("please enter a name in your aws hatchery configuration")
+	}
+
+	return nil
+}
+
+// ID returns hatchery id
+func (h *HatcheryAWS) ID() int64 {
+	if h.CDSClient().GetService() == nil {
+		return 0
+	}
+	return h.CDSClient().GetService().ID
+}
+
+// Service returns service instance
+func (h *HatcheryAWS) Service() *sdk.Service {
+	return h.CDSClient().GetService()
+}
+
+// Hatchery returns hatchery instance
+func (h *HatcheryAWS) Hatchery() *sdk.Hatchery {
+	return h.hatch
+}
+
+// Serve start the hatchery server
+func (h *HatcheryAWS) Serve(ctx context.Context) error {
+	return h.CommonServe(ctx, h)
+}
+
+// Configuration returns Hatchery CommonConfiguration
+func (h *HatcheryAWS) Configuration() hatchery.CommonConfiguration {
+	return h.Config.CommonConfiguration
+}
+
+// ModelType returns type of hatchery
+func (*HatcheryAWS) ModelType() string {
+	return sdk.AWS
+}
+
+// WorkerModelsEnabled returns Worker model enabled
+func (h *HatcheryAWS) WorkerModelsEnabled() ([]sdk.Model, error) {
+	return h.CDSClient().WorkerModelsEnabled()
+}
+
+// CanSpawn return wether or not hatchery can spawn model
+// requirements services and memory are not supported
+func (h *HatcheryAWS) CanSpawn(model *sdk.Model, jobID int64, requirements []sdk.Requirement) bool {
+	for _, r := range requirements {
+		if r.Type == sdk.ServiceRequirement || r.Type == sdk.MemoryRequirement {
+			return false
+		}
+	}
+	return true
+}
+
+// NeedRegistration return true if worker model need regsitration
+func (h *HatcheryAWS) NeedRegistration(m *sdk.Model) bool {
+	return m.NeedRegistration
+}
+
+// WorkersStarted returns the number of instances started but
+// not necessarily register on CDS yet
+func (h *HatcheryAWS) WorkersStarted() []string {
+	instances := h.getInstances()
+	res := make([]string, len(instances))
+	for i, inst := range instances {
+		res[i] = instanceTag(inst, "worker")
+	}
+	return res
+}
+
+// WorkersStartedByModel returns the number of instances of given model started but
+// not necessarily register on CDS yet
+func (h *HatcheryAWS) WorkersStartedByModel(model *sdk.Model) int {
+	var x int
+	for _, inst := range h.getInstances() {
+		if instanceTag(inst, "worker_model_name") == model.Name {
+			x++
+		}
+	}
+	log.Debug("WorkersStartedByModel> %s : %d", model.Name, x)
+	return x
+}
+
+func (h *HatcheryAWS) main() {
+	instanceListTick := time.NewTicker(10 * time.Second).C
+	killAwolInstancesTick := time.NewTicker(30 * time.Second).C
+	killIdleInstancesTick := time.NewTicker(60 * time.Second).C
+	killDisabledWorkersTick := time.NewTicker(60 * time.Second).C
+
+	for {
+		select {
+		case <-instanceListTick:
+			h.updateInstanceList()
+		case <-killAwolInstancesTick:
+			h.killAwolInstances()
+		case <-killIdleInstancesTick:
+			h.killIdleInstances()
+		case <-killDisabledWorkersTick:
+			h.killDisabledWorkers()
+		}
+	}
+}
+
+func (h *HatcheryAWS) updateInstanceList() {
+	var out string
+	var total int
+	status := map[string]int{}
+
+	for _, inst := range h.getInstances() {
+		state := instanceState(inst)
+		out += fmt.Sprintf("- [%s] %s:%s ", aws.TimeValue(inst.LaunchTime), state, instanceTag(inst, "worker"))
+		status[state]++
+		total++
+	}
+	var st string
+	for k, s := range status {
+		st += fmt.Sprintf("%d %s ", s, k)
+	}
+	log.Debug("Got %d instances %s", total, st)
+	if total > 0 {
+		log.Debug(out)
+	}
+}
+
+// killAwolInstances terminates the instances whose worker is not known by the CDS API,
+// and replaces in queue the jobs of the spot instances interrupted by AWS
+func (h *HatcheryAWS) killAwolInstances() {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	workers, err := h.CDSClient().WorkerList(ctx)
+	if err != nil {
+		log.Warning("killAwolInstances> Cannot fetch worker list: %s", err)
+		return
+	}
+
+	for _, inst := range h.getInstances() {
+		workerName := instanceTag(inst, "worker")
+		state := instanceState(inst)
+
+		var worker *sdk.Worker
+		for i := range workers {
+			if workers[i].Name == workerName {
+				worker = &workers[i]
+				break
+			}
+		}
+
+		if worker != nil && spotInterrupted(inst) && worker.Status == sdk.StatusBuilding && worker.ActionBuildID != 0 {
+			hatchery.WorkerLost(ctx, h, worker.ActionBuildID, workerName, "spot instance "+aws.StringValue(inst.InstanceId)+" interrupted")
+		}
+
+		// Instances shutting down were stopped by the worker itself or by AWS
+		if state == ec2.InstanceStateNameShuttingDown {
+			continue
+		}
+
+		// Wait for 6 minutes, to avoid killing worker babies
+		launched := time.Since(aws.TimeValue(inst.LaunchTime))
+		if state == ec2.InstanceStateNameStopped || (worker == nil && launched > 6*time.Minute) {
+			log.Debug("killAwolInstances> terminating instance %s of worker %s state:%s launched:%s inWorkersList:%t", aws.StringValue(inst.InstanceId), workerName, state, launched, worker != nil)
+			_ = h.terminateInstance(inst)
+		}
+	}
+}
+
+// killIdleInstances terminates the instances of the workers waiting for a job since more than the idle timeout
+func (h *HatcheryAWS) killIdleInstances() {
+	if h.Config.IdleTimeout == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	workers, err := h.CDSClient().WorkerList(ctx)
+	if err != nil {
+		log.Warning("killIdleInstances> Cannot fetch worker list: %s", err)
+		return
+	}
+
+	idles := h.idleWorkers.update(workers, time.Now(), time.Duration(h.Config.IdleTimeout)*time.Minute)
+	if len(idles) == 0 {
+		return
+	}
+
+	for _, inst := range h.getInstances() {
+		workerName := instanceTag(inst, "worker")
+		for _, name := range idles {
+			if name == workerName {
+				log.Info("killIdleInstances> worker %s idle since more than %d minutes", workerName, h.Config.IdleTimeout)
+				_ = h.terminateInstance(inst)
+			}
+		}
+	}
+}
+
+// killDisabledWorkers terminates the instances of the disabled workers
+func (h *HatcheryAWS) killDisabledWorkers() {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	workers, err := h.CDSClient().WorkerList(ctx)
+	if err != nil {
+		log.Warning("killDisabledWorkers> Cannot fetch worker list: %s", err)
+		return
+	}
+
+	instances := h.getInstances()
+	for _, w := range workers {
+		if w.Status != sdk.StatusDisabled {
+			continue
+		}
+
+		for _, inst := range instances {
+			if instanceTag(inst, "worker") == w.Name {
+				_ = h.terminateInstance(inst)
+			}
+		}
+	}
+}
+
+func (h *HatcheryAWS) terminateInstance(inst *ec2.Instance) error {
+	workerName := instanceTag(inst, "worker")
+	log.Info("Terminating worker %s instance %s", workerName, aws.StringValue(inst.InstanceId))
+
+	// If its a worker "register", check registration before terminating it
+	if instanceTag(inst, "register_only") == "true" {
+		modelID, err := strconv.ParseInt(instanceTag(inst, "worker_model_id"), 10, 64)
+		if err != nil {
+			log.Error("terminateInstance> unable to get model from registering instance %s", workerName)
+		} else if err := hatchery.CheckWorkerModelRegister(h, modelID); err != nil {
+			spawnErr := sdk.SpawnErrorForm{Error: err.Error()}
+			if err := h.CDSClient().WorkerModelSpawnError(modelID, spawnErr); err != nil {
+				log.Error("terminateInstance> error on call client.WorkerModelSpawnError on worker model %d for register: %s", modelID, err)
+			}
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	if _, err := h.ec2Client.TerminateInstancesWithContext(ctx, &ec2.TerminateInstancesInput{
+		InstanceIds: []*string{inst.InstanceId},
+	}); err != nil {
+		log.Warning("terminateInstance> Cannot terminate worker %s: %s", workerName, err)
+		return err
+	}
+	h.resetInstancesCache()
+	return nil
+}
+
+func splitList(s string) []string {
+	var res []string
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			res = append(res, v)
+		}
+	}
+	return res
+}
@@ -0,0 +1,62 @@
+package aws
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func TestNextSubnet(t *testing.T) {
+	h := &HatcheryAWS{}
+	assert.Equal(t, "", h.nextSubnet())
+
+	h.subnets = splitList("subnet-a, subnet-b,,")
+	assert.Equal(t, []string{"subnet-a", "subnet-b"}, h.subnets)
+	assert.Equal(t, "subnet-a", h.nextSubnet())
+	assert.Equal(t, "subnet-b", h.nextSubnet())
+	assert.Equal(t, "subnet-a", h.nextSubnet())
+}
+
+func TestSpotInterrupted(t *testing.T) {
+	inst := &ec2.Instance{
+		Tags:              []*ec2.Tag{{Key: aws.String("worker"), Value: aws.String("my-worker")}},
+		InstanceLifecycle: aws.String(ec2.InstanceLifecycleTypeSpot),
+		StateReason:       &ec2.StateReason{Code: aws.String(spotInterruptionCode)},
+	}
+	assert.Equal(t, "my-worker", instanceTag(inst, "worker"))
+	assert.True(t, spotInterrupted(inst))
+
+	inst.StateReason.Code = aws.String("Client.InstanceInitiatedShutdown")
+	assert.False(t, spotInterrupted(inst))
+
+	inst.InstanceLifecycle = nil
+	inst.StateReason.Code = aws.String(spotInterruptionCode)
+	assert.False(t, spotInterrupted(inst))
+}
+
+func TestIdleWorkers(t *testing.T) {
+	var i idleWorkers
+	now := time.Now()
+	timeout := 10 * time.Minute
+
+	workers := []sdk.Worker{
+		{Name: "w1", Status: sdk.StatusWaiting},
+		{Name: "w2", Status: sdk.StatusBuilding},
+	}
+	assert.Empty(t, i.update(workers, now, timeout))
+	assert.Empty(t, i.update(workers, now.Add(5*time.Minute), timeout))
+	assert.Equal(t, []string{"w1"}, i.update(workers, now.Add(11*time.Minute), timeout))
+
+	// A worker which took a job is no more idle
+	workers[0].Status = sdk.StatusBuilding
+	workers[1].Status = sdk.StatusWaiting
+	assert.Empty(t, i.update(workers, now.Add(12*time.Minute), timeout))
+	workers[0].Status = sdk.StatusWaiting
+	assert.Empty(t, i.update(workers, now.Add(20*time.Minute), timeout))
+	assert.Equal(t, []string{"w2"}, i.update(workers, now.Add(23*time.Minute), timeout))
+}
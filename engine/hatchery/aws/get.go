@@ -0,0 +1,153 @@
+package aws
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// spotInterruptionCode is the state reason of the spot instances interrupted by AWS
+const spotInterruptionCode = "Server.SpotInstanceTermination"
+
+// This a embeded cache for instances list
+var linstances = struct {
+	mu   sync.RWMutex
+	list []*ec2.Instance
+}{
+	mu:   sync.RWMutex{},
+	list: []*ec2.Instance{},
+}
+
+// getInstances returns the instances spawned by this hatchery, terminated instances excepted
+func (h *HatcheryAWS) getInstances() []*ec2.Instance {
+	t := time.Now()
+	defer func() { log.Debug("getInstances() : %fs", time.Since(t).Seconds()) }()
+
+	linstances.mu.RLock()
+	nbInstances := len(linstances.list)
+	linstances.mu.RUnlock()
+
+	if nbInstances == 0 {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		input := &ec2.DescribeInstancesInput{
+			Filters: []*ec2.Filter{
+				{Name: aws.String("tag:hatchery_name"), Values: []*string{aws.String(h.Service().Name)}},
+				{Name: aws.String("instance-state-name"), Values: aws.StringSlice([]string{
+					ec2.InstanceStateNamePending,
+					ec2.InstanceStateNameRunning,
+					ec2.InstanceStateNameShuttingDown,
+					ec2.InstanceStateNameStopping,
+					ec2.InstanceStateNameStopped,
+				})},
+			},
+		}
+
+		instances := []*ec2.Instance{}
+		if err := h.ec2Client.DescribeInstancesPagesWithContext(ctx, input, func(out *ec2.DescribeInstancesOutput, last bool) bool {
+			for _, r := range out.Reservations {
+				for _, inst := range r.Instances {
+					if instanceTag(inst, "worker") != "" {
+						instances = append(instances, inst)
+					}
+				}
+			}
+			return true
+		}); err != nil {
+			log.Error("getInstances> error on DescribeInstances: %s", err)
+			return linstances.list
+		}
+
+		linstances.mu.Lock()
+		linstances.list = instances
+		linstances.mu.Unlock()
+		//Remove data from the cache after 2 seconds
+		go func() {
+			time.Sleep(2 * time.Second)
+			h.resetInstancesCache()
+		}()
+	}
+
+	return linstances.list
+}
+
+func (h *HatcheryAWS) resetInstancesCache() {
+	linstances.mu.Lock()
+	linstances.list = []*ec2.Instance{}
+	linstances.mu.Unlock()
+}
+
+// nextSubnet returns the subnet of the next worker, the configured subnets are used in turn
+func (h *HatcheryAWS) nextSubnet() string {
+	if len(h.subnets) == 0 {
+		return ""
+	}
+	i := atomic.AddUint32(&h.subnetIndex, 1) - 1
+	return h.subnets[int(i)%len(h.subnets)]
+}
+
+func instanceTag(inst *ec2.Instance, key string) string {
+	for _, t := range inst.Tags {
+		if aws.StringValue(t.Key) == key {
+			return aws.StringValue(t.Value)
+		}
+	}
+	return ""
+}
+
+func instanceState(inst *ec2.Instance) string {
+	if inst.State == nil {
+		return ""
+	}
+	return aws.StringValue(inst.State.Name)
+}
+
+// spotInterrupted returns true if the instance is a spot instance stopped or terminated by AWS
+func spotInterrupted(inst *ec2.Instance) bool {
+	if aws.StringValue(inst.InstanceLifecycle) != ec2.InstanceLifecycleTypeSpot || inst.StateReason == nil {
+		return false
+	}
+	return aws.StringValue(inst.StateReason.Code) == spotInterruptionCode
+}
+
+// update records the workers waiting for a job and returns the name of the workers idle since more than the timeout
+func (i *idleWorkers) update(workers []sdk.Worker, now time.Time, timeout time.Duration) []string {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if i.since == nil {
+		i.since = map[string]time.Time{}
+	}
+
+	waiting := map[string]bool{}
+	var res []string
+	for _, w := range workers {
+		if w.Status != sdk.StatusWaiting {
+			continue
+		}
+		waiting[w.Name] = true
+		since, ok := i.since[w.Name]
+		if !ok {
+			i.since[w.Name] = now
+			continue
+		}
+		if now.Sub(since) > timeout {
+			res = append(res, w.Name)
+		}
+	}
+
+	// forget the workers which took a job or are gone
+	for name := range i.since {
+		if !waiting[name] {
+			delete(i.since, name)
+		}
+	}
+	return res
+}
@@ -0,0 +1,32 @@
+package aws
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/ec2"
+)
+
+// Init creates the EC2 client then starts the loop
+// terminating the instances no more used
+func (h *HatcheryAWS) Init() error {
+	h.idleWorkers = idleWorkers{since: map[string]time.Time{}}
+
+	cfg := aws.NewConfig().WithRegion(h.Config.Region)
+	if h.Config.AccessKeyID != "" {
+		cfg = cfg.WithCredentials(credentials.NewStaticCredentials(h.Config.AccessKeyID, h.Config.SecretAccessKey, ""))
+	}
+
+	sess, err := session.NewSession(cfg)
+	if err != nil {
+		return fmt.Errorf("Unable to create AWS session: %v", err)
+	}
+	h.ec2Client = ec2.New(sess)
+
+	go h.main()
+
+	return nil
+}
@@ -0,0 +1,157 @@
+package aws
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/hatchery"
+	"github.com/ovh/cds/sdk/log"
+	"github.com/ovh/cds/sdk/namesgenerator"
+)
+
+// SpawnWorker starts a new EC2 instance
+// requirements are not supported
+func (h *HatcheryAWS) SpawnWorker(ctx context.Context, spawnArgs hatchery.SpawnArguments) (string, error) {
+	//generate a pretty cool name
+	name := spawnArgs.Model.Name + "-" + strings.Replace(namesgenerator.GetRandomNameCDS(0), "_", "-", -1)
+	if spawnArgs.RegisterOnly {
+		name = "register-" + name
+	}
+
+	if spawnArgs.JobID > 0 {
+		log.Debug("spawnWorker> spawning worker %s model:%s for job %d - %s", name, spawnArgs.Model.Name, spawnArgs.JobID, spawnArgs.LogInfo)
+	} else {
+		log.Debug("spawnWorker> spawning worker %s model:%s - %s", name, spawnArgs.Model.Name, spawnArgs.LogInfo)
+	}
+
+	if h.hatch == nil {
+		return "", fmt.Errorf("hatchery disconnected from engine")
+	}
+
+	if len(h.getInstances()) >= h.Configuration().Provision.MaxWorker {
+		log.Debug("MaxWorker limit (%d) reached", h.Configuration().Provision.MaxWorker)
+		return "", nil
+	}
+
+	if spawnArgs.Model.ModelVirtualMachine.Image == "" {
+		return "", fmt.Errorf("spawnWorker> no AMI on worker model %s", spawnArgs.Model.Name)
+	}
+
+	if spawnArgs.RegisterOnly {
+		spawnArgs.Model.ModelVirtualMachine.Cmd += " register"
+	}
+
+	udata := spawnArgs.Model.ModelVirtualMachine.PreCmd + "\n" + spawnArgs.Model.ModelVirtualMachine.Cmd + "\n" + spawnArgs.Model.ModelVirtualMachine.PostCmd
+
+	tmpl, errt := template.New("udata").Parse(udata)
+	if errt != nil {
+		return "", errt
+	}
+	udataParam := sdk.WorkerArgs{
+		API:               h.Configuration().API.HTTP.URL,
+		Name:              name,
+		Token:             h.Configuration().API.Token,
+		Model:             spawnArgs.Model.ID,
+		HatcheryName:      h.Service().Name,
+		TTL:               h.Config.WorkerTTL,
+		GraylogHost:       h.Configuration().Provision.WorkerLogsOptions.Graylog.Host,
+		GraylogPort:       h.Configuration().Provision.WorkerLogsOptions.Graylog.Port,
+		GraylogExtraKey:   h.Configuration().Provision.WorkerLogsOptions.Graylog.ExtraKey,
+		GraylogExtraValue: h.Configuration().Provision.WorkerLogsOptions.Graylog.ExtraValue,
+		GrpcAPI:           h.Configuration().API.GRPC.URL,
+		GrpcInsecure:      h.Configuration().API.GRPC.Insecure,
+	}
+
+	udataParam.WorkflowJobID = spawnArgs.JobID
+
+	var buffer bytes.Buffer
+	if err := tmpl.Execute(&buffer, udataParam); err != nil {
+		return "", err
+	}
+
+	input := h.runInstancesInput(name, spawnArgs, base64.StdEncoding.EncodeToString(buffer.Bytes()))
+	r, err := h.ec2Client.RunInstancesWithContext(ctx, input)
+	if err != nil {
+		return "", fmt.Errorf("SpawnWorker> Unable to run instance: name:%s type:%s ami:%s subnet:%s err:%s", name, aws.StringValue(input.InstanceType), aws.StringValue(input.ImageId), aws.StringValue(input.SubnetId), err)
+	}
+	h.resetInstancesCache()
+
+	for _, inst := range r.Instances {
+		log.Debug("SpawnWorker> Created instance ID: %s", aws.StringValue(inst.InstanceId))
+	}
+	return name, nil
+}
+
+// runInstancesInput computes the parameters of the instance of a worker
+func (h *HatcheryAWS) runInstancesInput(name string, spawnArgs hatchery.SpawnArguments, udata64 string) *ec2.RunInstancesInput {
+	tags := map[string]string{
+		"Name":                       name,
+		"worker":                     name,
+		"hatchery_name":              h.Service().Name,
+		"register_only":              fmt.Sprintf("%t", spawnArgs.RegisterOnly),
+		"worker_model_id":            strconv.FormatInt(spawnArgs.Model.ID, 10),
+		"worker_model_name":          spawnArgs.Model.Name,
+		"worker_model_last_modified": fmt.Sprintf("%d", spawnArgs.Model.UserLastModified.Unix()),
+	}
+	// The jobs of the spot instances interrupted by AWS are replaced in queue
+	preemptible := spawnArgs.Model.ModelVirtualMachine.Preemptible && !spawnArgs.RegisterOnly
+	if preemptible {
+		tags["preemptible"] = "true"
+	}
+
+	ec2Tags := make([]*ec2.Tag, 0, len(tags))
+	for k, v := range tags {
+		ec2Tags = append(ec2Tags, &ec2.Tag{Key: aws.String(k), Value: aws.String(v)})
+	}
+
+	input := &ec2.RunInstancesInput{
+		ImageId:  aws.String(spawnArgs.Model.ModelVirtualMachine.Image),
+		MinCount: aws.Int64(1),
+		MaxCount: aws.Int64(1),
+		UserData: aws.String(udata64),
+		// the post command of the worker model shutdowns the instance at the end of the worker
+		InstanceInitiatedShutdownBehavior: aws.String(ec2.ShutdownBehaviorTerminate),
+		TagSpecifications: []*ec2.TagSpecification{{
+			ResourceType: aws.String(ec2.ResourceTypeInstance),
+			Tags:         ec2Tags,
+		}},
+	}
+	if spawnArgs.Model.ModelVirtualMachine.Flavor != "" {
+		input.InstanceType = aws.String(spawnArgs.Model.ModelVirtualMachine.Flavor)
+	}
+	if subnet := h.nextSubnet(); subnet != "" {
+		input.SubnetId = aws.String(subnet)
+	}
+	if len(h.securityGroups) > 0 {
+		input.SecurityGroupIds = aws.StringSlice(h.securityGroups)
+	}
+	if h.Config.KeyName != "" {
+		input.KeyName = aws.String(h.Config.KeyName)
+	}
+	if h.Config.InstanceProfile != "" {
+		if strings.HasPrefix(h.Config.InstanceProfile, "arn:") {
+			input.IamInstanceProfile = &ec2.IamInstanceProfileSpecification{Arn: aws.String(h.Config.InstanceProfile)}
+		} else {
+			input.IamInstanceProfile = &ec2.IamInstanceProfileSpecification{Name: aws.String(h.Config.InstanceProfile)}
+		}
+	}
+	if preemptible {
+		input.InstanceMarketOptions = &ec2.InstanceMarketOptionsRequest{
+			MarketType: aws.String(ec2.MarketTypeSpot),
+			SpotOptions: &ec2.SpotMarketOptions{
+				SpotInstanceType:             aws.String(ec2.SpotInstanceTypeOneTime),
+				InstanceInterruptionBehavior: aws.String(ec2.InstanceInterruptionBehaviorTerminate),
+			},
+		}
+	}
+	return input
+}
@@ -0,0 +1,64 @@
+package aws
+
+import (
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/service/ec2"
+
+	hatcheryCommon "github.com/ovh/cds/engine/hatchery"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/hatchery"
+)
+
+// HatcheryConfiguration is the configuration for hatchery
+type HatcheryConfiguration struct {
+	hatchery.CommonConfiguration `mapstructure:"commonConfiguration" toml:"commonConfiguration" json:"commonConfiguration"`
+
+	// Region AWS region
+	Region string `mapstructure:"region" toml:"region" default:"" commented:"false" comment:"AWS Region, example: eu-west-1" json:"region"`
+
+	// AccessKeyID AWS access key
+	AccessKeyID string `mapstructure:"accessKeyID" toml:"accessKeyID" default:"" commented:"true" comment:"AWS Access Key ID. If empty, the credentials are taken from the environment, the shared credentials file or the instance profile of the hatchery" json:"accessKeyID,omitempty"`
+
+	// SecretAccessKey AWS secret key
+	SecretAccessKey string `mapstructure:"secretAccessKey" toml:"secretAccessKey" default:"" commented:"true" comment:"AWS Secret Access Key" json:"-"`
+
+	// InstanceProfile IAM instance profile of the workers
+	InstanceProfile string `mapstructure:"instanceProfile" toml:"instanceProfile" default:"" commented:"true" comment:"Name or ARN of the IAM instance profile attached to the workers" json:"instanceProfile,omitempty"`
+
+	// Subnets used to spawn the workers
+	Subnets string `mapstructure:"subnets" toml:"subnets" default:"" commented:"true" comment:"Subnets IDs used to spawn the workers, the hatchery uses them in turn. \n Format: subnet-aaaa,subnet-bbbb \n If empty, the default subnet of the default VPC is used" json:"subnets,omitempty"`
+
+	// SecurityGroups of the workers
+	SecurityGroups string `mapstructure:"securityGroups" toml:"securityGroups" default:"" commented:"true" comment:"Security groups IDs of the workers. \n Format: sg-aaaa,sg-bbbb" json:"securityGroups,omitempty"`
+
+	// KeyName EC2 key pair
+	KeyName string `mapstructure:"keyName" toml:"keyName" default:"" commented:"true" comment:"Name of the EC2 key pair installed on the workers" json:"keyName,omitempty"`
+
+	// WorkerTTL Worker TTL (minutes)
+	WorkerTTL int `mapstructure:"workerTTL" toml:"workerTTL" default:"30" commented:"false" comment:"Worker TTL (minutes)" json:"workerTTL"`
+
+	// IdleTimeout delay before terminating an idle instance (minutes)
+	IdleTimeout int `mapstructure:"idleTimeout" toml:"idleTimeout" default:"10" commented:"false" comment:"Terminate the instances of the workers waiting for a job since this delay (minutes). 0 to disable" json:"idleTimeout"`
+}
+
+// HatcheryAWS spawns instances of worker model with type 'aws'
+// by starting EC2 instances from AMIs
+type HatcheryAWS struct {
+	hatcheryCommon.Common
+	Config    HatcheryConfiguration
+	hatch     *sdk.Hatchery
+	ec2Client *ec2.EC2
+
+	subnets        []string // computed from subnets
+	securityGroups []string // computed from securityGroups
+	subnetIndex    uint32
+	idleWorkers    idleWorkers
+}
+
+// idleWorkers keeps the date since when the workers are waiting for a job
+type idleWorkers struct {
+	mu    sync.Mutex
+	since map[string]time.Time
+}
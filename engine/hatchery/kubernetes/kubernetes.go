@@ -370,7 +370,7 @@ func (h *HatcheryKubernetes) SpawnWorker(ctx context.Context, spawnArgs hatchery
 	for i, serv := range services {
 		//name= <alias> => the name of the host put in /etc/hosts of the worker
 		//value= "postgres:latest env_1=blabla env_2=blabla"" => we can add env variables in requirement name
-		tuple := strings.Split(serv.Value, " ")
+		tuple := sdk.SplitServiceRequirementValue(serv.Value)
 		img := tuple[0]
 
 		servContainer := apiv1.Container{
@@ -279,6 +279,13 @@ func (h *HatcheryLocal) SpawnWorker(ctx context.Context, spawnArgs hatchery.Spaw
 	}
 
 	binCmd := cmdSplitted[0]
+	if binCmd == "worker" {
+		h.Lock()
+		if h.workerBinary != "" {
+			binCmd = h.workerBinary
+		}
+		h.Unlock()
+	}
 	log.Debug("Command exec: %v", cmdSplitted)
 	var cmd *exec.Cmd
 	if spawnArgs.RegisterOnly {
@@ -358,6 +365,10 @@ func (h *HatcheryLocal) WorkersStartedByModel(model *sdk.Model) int {
 func (h *HatcheryLocal) Init() error {
 	h.workers = make(map[string]workerCmd)
 	sdk.GoRoutine(context.Background(), "startKillAwolWorkerRoutine", h.startKillAwolWorkerRoutine)
+	if h.Config.WorkerAutoUpdate {
+		h.updateWorkerBinary()
+		sdk.GoRoutine(context.Background(), "startWorkerBinaryRoutine", h.startWorkerBinaryRoutine)
+	}
 	return nil
 }
 
@@ -15,6 +15,7 @@ type HatcheryConfiguration struct {
 	hatchery.CommonConfiguration `mapstructure:"commonConfiguration" toml:"commonConfiguration" json:"commonConfiguration"`
 	Basedir                      string `mapstructure:"basedir" toml:"basedir" default:"/tmp" comment:"BaseDir for worker workspace" json:"basedir"`
 	NbProvision                  int    `mapstructure:"nbProvision" toml:"nbProvision" default:"1" comment:"Nb Workers to provision" json:"nbProvision"`
+	WorkerAutoUpdate             bool   `mapstructure:"workerAutoUpdate" toml:"workerAutoUpdate" default:"true" comment:"Pull the worker binary from the CDS API in the basedir each time the API is upgraded, instead of using the worker binary of the PATH" json:"workerAutoUpdate"`
 }
 
 // HatcheryLocal implements HatcheryMode interface for local usage
@@ -25,6 +26,9 @@ type HatcheryLocal struct {
 	hatch      *sdk.Hatchery
 	workers    map[string]workerCmd
 	ModelLocal sdk.Model
+	// worker binary pulled from the CDS API and the version of the API it was pulled from
+	workerBinary  string
+	workerVersion string
 }
 
 type workerCmd struct {
@@ -0,0 +1,62 @@
+package local
+
+import (
+	"context"
+	"path"
+	"time"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/cdsclient"
+	"github.com/ovh/cds/sdk/log"
+)
+
+func (h *HatcheryLocal) startWorkerBinaryRoutine(ctx context.Context) {
+	t := time.NewTicker(time.Minute)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			h.updateWorkerBinary()
+		}
+	}
+}
+
+// updateWorkerBinary pulls the worker binary from the CDS API when the API has been upgraded. The workers already
+// started keep their binary, the next ones are spawned with the new one. If the binary cannot be pulled, the workers
+// are spawned with the previous one.
+func (h *HatcheryLocal) updateWorkerBinary() {
+	version, err := h.CDSClient().Version()
+	if err != nil {
+		log.Warning("hatchery> local> cannot get version of CDS API: %v", err)
+		return
+	}
+
+	h.Lock()
+	current := h.workerVersion
+	h.Unlock()
+	if version.Version == current {
+		return
+	}
+
+	checksum, err := h.CDSClient().DownloadChecksumFromAPI("worker", sdk.GOOS, sdk.GOARCH)
+	if err != nil {
+		log.Warning("hatchery> local> cannot get checksum of worker binary %s: %v", version.Version, err)
+		return
+	}
+
+	url := h.CDSClient().DownloadURLFromAPI("worker", sdk.GOOS, sdk.GOARCH)
+	target := path.Join(h.Config.Basedir, "worker")
+	httpClient := cdsclient.NewHTTPClient(5*time.Minute, h.Config.API.HTTP.Insecure)
+	if err := sdk.DownloadVerifiedBinary(httpClient, url, checksum, target); err != nil {
+		log.Warning("hatchery> local> cannot pull worker binary %s: %v", version.Version, err)
+		return
+	}
+	log.Info("hatchery> local> worker binary %s pulled from %s", version.Version, url)
+
+	h.Lock()
+	h.workerBinary = target
+	h.workerVersion = version.Version
+	h.Unlock()
+}
@@ -0,0 +1,199 @@
+package nomad
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// nomadClient is a light client of the Nomad HTTP API, only the endpoints used by the hatchery are implemented
+type nomadClient struct {
+	address   string
+	token     string
+	region    string
+	namespace string
+	http      *http.Client
+}
+
+type nomadJob struct {
+	ID          string
+	Name        string
+	Type        string
+	Region      string `json:",omitempty"`
+	Namespace   string `json:",omitempty"`
+	Datacenters []string
+	Meta        map[string]string `json:",omitempty"`
+	Constraints []nomadConstraint `json:",omitempty"`
+	TaskGroups  []nomadTaskGroup
+}
+
+type nomadConstraint struct {
+	LTarget string
+	RTarget string
+	Operand string
+}
+
+type nomadTaskGroup struct {
+	Name             string
+	Count            int
+	RestartPolicy    nomadRestartPolicy
+	ReschedulePolicy nomadReschedulePolicy
+	Tasks            []nomadTask
+}
+
+type nomadRestartPolicy struct {
+	Attempts int
+	Interval time.Duration
+	Delay    time.Duration
+	Mode     string
+}
+
+type nomadReschedulePolicy struct {
+	Attempts  int
+	Unlimited bool
+}
+
+type nomadTask struct {
+	Name      string
+	Driver    string
+	Config    map[string]interface{}
+	Env       map[string]string `json:",omitempty"`
+	Meta      map[string]string `json:",omitempty"`
+	Resources nomadResources
+}
+
+type nomadResources struct {
+	CPU      int
+	MemoryMB int
+}
+
+// nomadJobStub is an item of the jobs list
+type nomadJobStub struct {
+	ID         string
+	Status     string
+	SubmitTime int64 // nanoseconds
+}
+
+type nomadAllocation struct {
+	ID           string
+	ClientStatus string
+}
+
+func (c *nomadClient) do(ctx context.Context, method, path string, query url.Values, in, out interface{}) error {
+	if query == nil {
+		query = url.Values{}
+	}
+	if c.region != "" {
+		query.Set("region", c.region)
+	}
+	if c.namespace != "" {
+		query.Set("namespace", c.namespace)
+	}
+
+	var body *bytes.Buffer
+	if in != nil {
+		btes, err := json.Marshal(in)
+		if err != nil {
+			return sdk.WithStack(err)
+		}
+		body = bytes.NewBuffer(btes)
+	} else {
+		body = bytes.NewBuffer(nil)
+	}
+
+	u := strings.TrimSuffix(c.address, "/") + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	req, err := http.NewRequest(method, u, body)
+	if err != nil {
+		return sdk.WithStack(err)
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/json")
+	if c.token != "" {
+		req.Header.Set("X-Nomad-Token", c.token)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return sdk.WrapError(err, "cannot call nomad %s %s", method, path)
+	}
+	defer resp.Body.Close()
+
+	btes, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return sdk.WithStack(err)
+	}
+	if resp.StatusCode >= 400 {
+		return fmt.Errorf("nomad %s %s: %d %s", method, path, resp.StatusCode, string(btes))
+	}
+	if out == nil {
+		return nil
+	}
+	if s, ok := out.(*string); ok {
+		*s = string(btes)
+		return nil
+	}
+	return sdk.WithStack(json.Unmarshal(btes, out))
+}
+
+func (c *nomadClient) registerJob(ctx context.Context, job nomadJob) error {
+	return c.do(ctx, http.MethodPut, "/v1/jobs", nil, map[string]interface{}{"Job": job}, nil)
+}
+
+func (c *nomadClient) listJobs(ctx context.Context, prefix string) ([]nomadJobStub, error) {
+	var jobs []nomadJobStub
+	if err := c.do(ctx, http.MethodGet, "/v1/jobs", url.Values{"prefix": []string{prefix}}, nil, &jobs); err != nil {
+		return nil, err
+	}
+	return jobs, nil
+}
+
+func (c *nomadClient) getJob(ctx context.Context, id string) (nomadJob, error) {
+	var job nomadJob
+	err := c.do(ctx, http.MethodGet, "/v1/job/"+url.PathEscape(id), nil, nil, &job)
+	return job, err
+}
+
+// deregisterJob stops the job and purges it from Nomad
+func (c *nomadClient) deregisterJob(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, "/v1/job/"+url.PathEscape(id), url.Values{"purge": []string{"true"}}, nil, nil)
+}
+
+func (c *nomadClient) jobAllocations(ctx context.Context, id string) ([]nomadAllocation, error) {
+	var allocs []nomadAllocation
+	if err := c.do(ctx, http.MethodGet, "/v1/job/"+url.PathEscape(id)+"/allocations", nil, nil, &allocs); err != nil {
+		return nil, err
+	}
+	return allocs, nil
+}
+
+// allocationLogs returns the stdout or stderr of a task
+func (c *nomadClient) allocationLogs(ctx context.Context, allocID, task, logType string) (string, error) {
+	var logs string
+	err := c.do(ctx, http.MethodGet, "/v1/client/fs/logs/"+url.PathEscape(allocID), url.Values{
+		"task":  []string{task},
+		"type":  []string{logType},
+		"plain": []string{"true"},
+	}, nil, &logs)
+	return logs, err
+}
+
+func newNomadClient(cfg HatcheryConfiguration) *nomadClient {
+	return &nomadClient{
+		address:   cfg.Address,
+		token:     cfg.Token,
+		region:    cfg.Region,
+		namespace: cfg.Namespace,
+		http:      &http.Client{Timeout: 30 * time.Second},
+	}
+}
@@ -0,0 +1,100 @@
+package nomad
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/hatchery"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// killAwolWorkers purges the dead jobs, the jobs of the disabled workers
+// and the jobs unknown by the api
+func (h *HatcheryNomad) killAwolWorkers(ctx context.Context) error {
+	jobs, err := h.nomadClient.listJobs(ctx, h.jobIDPrefix())
+	if err != nil {
+		return err
+	}
+
+	workers, err := h.CDSClient().WorkerList(ctx)
+	if err != nil {
+		return err
+	}
+	workersByName := make(map[string]sdk.Worker, len(workers))
+	for _, w := range workers {
+		workersByName[w.Name] = w
+	}
+
+	var globalErr error
+	for _, j := range jobs {
+		name := h.workerName(j.ID)
+		w, known := workersByName[name]
+
+		var toDelete bool
+		switch {
+		case j.Status == "dead":
+			toDelete = true
+			if strings.HasPrefix(name, "register-") {
+				h.checkRegistration(ctx, j.ID)
+			}
+		case known && w.Status == sdk.StatusDisabled:
+			toDelete = true
+		case !known && time.Since(time.Unix(0, j.SubmitTime)) > 6*time.Minute:
+			// the worker never registered, or was already removed from the api
+			toDelete = true
+		}
+
+		if !toDelete {
+			continue
+		}
+		log.Debug("hatchery> nomad> killAwolWorkers> deregister job %s (status:%s)", j.ID, j.Status)
+		if err := h.nomadClient.deregisterJob(ctx, j.ID); err != nil {
+			globalErr = err
+			log.Error("hatchery> nomad> killAwolWorkers> Cannot deregister job %s: %v", j.ID, err)
+		}
+	}
+	return globalErr
+}
+
+// checkRegistration sends the logs of the register job to the api if the
+// registration of its model failed
+func (h *HatcheryNomad) checkRegistration(ctx context.Context, jobID string) {
+	job, err := h.nomadClient.getJob(ctx, jobID)
+	if err != nil {
+		log.Error("hatchery> nomad> checkRegistration> unable to get job %s: %v", jobID, err)
+		return
+	}
+	modelID, err := strconv.ParseInt(job.Meta["worker_model_id"], 10, 64)
+	if err != nil {
+		log.Error("hatchery> nomad> checkRegistration> unable to get model from register job %s", jobID)
+		return
+	}
+
+	errRegister := hatchery.CheckWorkerModelRegister(h, modelID)
+	if errRegister == nil {
+		return
+	}
+
+	spawnErr := sdk.SpawnErrorForm{Error: errRegister.Error()}
+	allocs, err := h.nomadClient.jobAllocations(ctx, jobID)
+	if err != nil {
+		log.Warning("hatchery> nomad> checkRegistration> unable to get allocations of job %s: %v", jobID, err)
+	}
+	for _, a := range allocs {
+		for _, logType := range []string{"stderr", "stdout"} {
+			logs, err := h.nomadClient.allocationLogs(ctx, a.ID, taskName, logType)
+			if err != nil {
+				log.Warning("hatchery> nomad> checkRegistration> unable to get %s of allocation %s: %v", logType, a.ID, err)
+				continue
+			}
+			spawnErr.Logs = append(spawnErr.Logs, []byte(fmt.Sprintf("%s:\n%s\n", logType, logs))...)
+		}
+	}
+	if err := h.CDSClient().WorkerModelSpawnError(modelID, spawnErr); err != nil {
+		log.Error("hatchery> nomad> checkRegistration> error on call client.WorkerModelSpawnError on worker model %d for register: %s", modelID, err)
+	}
+}
@@ -0,0 +1,222 @@
+package nomad
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/ovh/cds/engine/api"
+	"github.com/ovh/cds/engine/api/services"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/cdsclient"
+	"github.com/ovh/cds/sdk/hatchery"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// New instanciates a new hatchery nomad
+func New() *HatcheryNomad {
+	s := new(HatcheryNomad)
+	s.Router = &api.Router{
+		Mux: mux.NewRouter(),
+	}
+	return s
+}
+
+// Init starts the routines removing the jobs of the workers no more used
+func (h *HatcheryNomad) Init() error {
+	sdk.GoRoutine(context.Background(), "hatchery nomad routines", func(ctx context.Context) {
+		h.routines(ctx)
+	})
+	return nil
+}
+
+// ApplyConfiguration apply an object of type HatcheryConfiguration after checking it
+func (h *HatcheryNomad) ApplyConfiguration(cfg interface{}) error {
+	if err := h.CheckConfiguration(cfg); err != nil {
+		return err
+	}
+
+	var ok bool
+	h.Config, ok = cfg.(HatcheryConfiguration)
+	if !ok {
+		return fmt.Errorf("Invalid configuration")
+	}
+
+	h.nomadClient = newNomadClient(h.Config)
+	h.datacenters = nil
+	for _, dc := range strings.Split(h.Config.Datacenters, ",") {
+		if dc = strings.TrimSpace(dc); dc != "" {
+			h.datacenters = append(h.datacenters, dc)
+		}
+	}
+
+	h.hatch = &sdk.Hatchery{}
+	h.Client = cdsclient.NewService(h.Config.API.HTTP.URL, 60*time.Second, h.Config.API.HTTP.Insecure)
+	h.API = h.Config.API.HTTP.URL
+	h.Name = h.Config.Name
+	h.HTTPURL = h.Config.URL
+	h.Token = h.Config.API.Token
+	h.Type = services.TypeHatchery
+	h.MaxHeartbeatFailures = h.Config.API.MaxHeartbeatFailures
+	h.Common.Common.ServiceName = "cds-hatchery-nomad"
+
+	return nil
+}
+
+// Status returns sdk.MonitoringStatus, implements interface service.Service
+func (h *HatcheryNomad) Status() sdk.MonitoringStatus {
+	m := h.CommonMonitoring()
+	if h.IsInitialized() {
+		m.Lines = append(m.Lines, sdk.MonitoringStatusLine{Component: "Workers", Value: fmt.Sprintf("%d/%d", len(h.WorkersStarted()), h.Config.Provision.MaxWorker), Status: sdk.MonitoringStatusOK})
+	}
+	return m
+}
+
+// CheckConfiguration checks the validity of the configuration object
+func (h *HatcheryNomad) CheckConfiguration(cfg interface{}) error {
+	hconfig, ok := cfg.(HatcheryConfiguration)
+	if !ok {
+		return fmt.Errorf("Invalid configuration")
+	}
+
+	if hconfig.API.HTTP.URL == "" {
+		return fmt.Errorf("API HTTP(s) URL is mandatory")
+	}
+
+	if hconfig.API.Token == "" {
+		return fmt.Errorf("API Token URL is mandatory")
+	}
+
+	if hconfig.Name == "" {
+		return fmt.Errorf("please enter a name in your nomad hatchery configuration")
+	}
+
+	if hconfig.Address == "" {
+		return fmt.Errorf("please enter a valid nomad address")
+	}
+
+	if strings.TrimSpace(hconfig.Datacenters) == "" {
+		return fmt.Errorf("please enter at least one nomad datacenter")
+	}
+
+	if hconfig.Driver != DriverDocker && hconfig.Driver != DriverExec {
+		return fmt.Errorf("invalid nomad driver %s, should be %s or %s", hconfig.Driver, DriverDocker, DriverExec)
+	}
+
+	return nil
+}
+
+// ID must returns hatchery id
+func (h *HatcheryNomad) ID() int64 {
+	if h.CDSClient().GetService() == nil {
+		return 0
+	}
+	return h.CDSClient().GetService().ID
+}
+
+// Service returns service instance
+func (h *HatcheryNomad) Service() *sdk.Service {
+	return h.CDSClient().GetService()
+}
+
+// Hatchery returns hatchery instance
+func (h *HatcheryNomad) Hatchery() *sdk.Hatchery {
+	return h.hatch
+}
+
+// Serve start the hatchery server
+func (h *HatcheryNomad) Serve(ctx context.Context) error {
+	return h.CommonServe(ctx, h)
+}
+
+// Configuration returns Hatchery CommonConfiguration
+func (h *HatcheryNomad) Configuration() hatchery.CommonConfiguration {
+	return h.Config.CommonConfiguration
+}
+
+// ModelType returns type of hatchery, it depends on the nomad driver
+func (h *HatcheryNomad) ModelType() string {
+	if h.Config.Driver == DriverExec {
+		return sdk.HostProcess
+	}
+	return sdk.Docker
+}
+
+// WorkerModelsEnabled returns Worker model enabled
+func (h *HatcheryNomad) WorkerModelsEnabled() ([]sdk.Model, error) {
+	return h.CDSClient().WorkerModelsEnabled()
+}
+
+// CanSpawn return wether or not hatchery can spawn model.
+// services and volumes requirements are not supported
+func (h *HatcheryNomad) CanSpawn(model *sdk.Model, jobID int64, requirements []sdk.Requirement) bool {
+	for _, r := range requirements {
+		if r.Type == sdk.ServiceRequirement || r.Type == sdk.VolumeRequirement {
+			return false
+		}
+	}
+	return true
+}
+
+// WorkersStarted returns the number of instances started but
+// not necessarily register on CDS yet
+func (h *HatcheryNomad) WorkersStarted() []string {
+	jobs, err := h.listWorkerJobs()
+	if err != nil {
+		log.Warning("WorkersStarted> unable to list nomad jobs: %v", err)
+		return nil
+	}
+	workerNames := make([]string, 0, len(jobs))
+	for _, j := range jobs {
+		if j.Status != "dead" {
+			workerNames = append(workerNames, h.workerName(j.ID))
+		}
+	}
+	return workerNames
+}
+
+// WorkersStartedByModel returns the number of instances of given model started but
+// not necessarily register on CDS yet
+func (h *HatcheryNomad) WorkersStartedByModel(model *sdk.Model) int {
+	prefix := workerNamePrefix(model.Name)
+	var x int
+	for _, name := range h.WorkersStarted() {
+		if strings.HasPrefix(strings.TrimPrefix(name, "register-"), prefix) {
+			x++
+		}
+	}
+	log.Debug("WorkersStartedByModel> %s : %d", model.Name, x)
+	return x
+}
+
+// NeedRegistration return true if worker model need regsitration
+func (h *HatcheryNomad) NeedRegistration(m *sdk.Model) bool {
+	if m.NeedRegistration || m.LastRegistration.Unix() < m.UserLastModified.Unix() {
+		return true
+	}
+	return false
+}
+
+func (h *HatcheryNomad) routines(ctx context.Context) {
+	ticker := time.NewTicker(10 * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			sdk.GoRoutine(ctx, "killAwolWorkers", func(ctx context.Context) {
+				if err := h.killAwolWorkers(ctx); err != nil {
+					log.Warning("Hatchery> Nomad> Cannot kill awol workers: %v", err)
+				}
+			})
+		case <-ctx.Done():
+			if ctx.Err() != nil {
+				log.Error("Hatchery> Nomad> Exiting routines")
+			}
+			return
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package nomad
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/hatchery"
+)
+
+func TestRequirementsConstraints(t *testing.T) {
+	constraints := requirementsConstraints([]sdk.Requirement{
+		{Type: sdk.OSArchRequirement, Value: "linux/amd64"},
+		{Type: sdk.HostnameRequirement, Value: "node-1"},
+		{Type: sdk.BinaryRequirement, Value: "git"},
+		{Type: sdk.OSArchRequirement, Value: "invalid"},
+	})
+	assert.Equal(t, []nomadConstraint{
+		{LTarget: "${attr.kernel.name}", RTarget: "linux", Operand: "="},
+		{LTarget: "${attr.cpu.arch}", RTarget: "amd64", Operand: "="},
+		{LTarget: "${attr.unique.hostname}", RTarget: "node-1", Operand: "="},
+	}, constraints)
+}
+
+func TestWorkerJob(t *testing.T) {
+	h := &HatcheryNomad{
+		Config: HatcheryConfiguration{
+			Driver:        DriverDocker,
+			DefaultMemory: 1024,
+			DefaultCPU:    500,
+		},
+		datacenters: []string{"dc1", "dc2"},
+	}
+	h.Config.Name = "my-hatchery"
+
+	args := hatchery.SpawnArguments{
+		Model: sdk.Model{
+			ID:   42,
+			Name: "my-model",
+			ModelDocker: sdk.ModelDocker{
+				Image: "debian:9",
+				Shell: "sh -c",
+				Cmd:   "worker --name={{.Name}}",
+				Envs:  map[string]string{"MY_VAR": "{{.Name}}"},
+			},
+		},
+		JobID:        666,
+		Requirements: []sdk.Requirement{{Type: sdk.MemoryRequirement, Value: "2048"}},
+	}
+
+	job, err := h.workerJob("nomad-my-model-foo", args, sdk.WorkerArgs{Name: "nomad-my-model-foo", Model: 42})
+	assert.NoError(t, err)
+	assert.Equal(t, "my-hatchery.nomad-my-model-foo", job.ID)
+	assert.Equal(t, "nomad-my-model-foo", h.workerName(job.ID))
+	assert.Equal(t, "batch", job.Type)
+	assert.Equal(t, []string{"dc1", "dc2"}, job.Datacenters)
+	assert.Equal(t, "42", job.Meta["worker_model_id"])
+	assert.Len(t, job.TaskGroups, 1)
+	assert.Len(t, job.TaskGroups[0].Tasks, 1)
+
+	task := job.TaskGroups[0].Tasks[0]
+	assert.Equal(t, DriverDocker, task.Driver)
+	assert.Equal(t, "debian:9", task.Config["image"])
+	assert.Equal(t, "sh", task.Config["command"])
+	assert.Equal(t, []string{"-c", "worker --name=nomad-my-model-foo"}, task.Config["args"])
+	assert.Equal(t, "nomad-my-model-foo", task.Env["MY_VAR"])
+	assert.Equal(t, "666", task.Env["CDS_BOOKED_WORKFLOW_JOB_ID"])
+	assert.Equal(t, 2048, task.Resources.MemoryMB)
+
+	// register jobs use the exec driver command of the model
+	h.Config.Driver = DriverExec
+	args.RegisterOnly = true
+	args.Model.ModelVirtualMachine.Cmd = "./worker --model={{.Model}}"
+	job, err = h.workerJob("register-nomad-my-model-foo", args, sdk.WorkerArgs{Name: "register-nomad-my-model-foo", Model: 42})
+	assert.NoError(t, err)
+	task = job.TaskGroups[0].Tasks[0]
+	assert.Equal(t, DriverExec, task.Driver)
+	assert.Equal(t, []string{"-c", "./worker --model=42 register"}, task.Config["args"])
+	assert.Equal(t, int(hatchery.MemoryRegisterContainer), task.Resources.MemoryMB)
+}
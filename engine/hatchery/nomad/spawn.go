@@ -0,0 +1,227 @@
+package nomad
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/hatchery"
+	"github.com/ovh/cds/sdk/log"
+	"github.com/ovh/cds/sdk/namesgenerator"
+)
+
+const taskName = "worker"
+
+// workerNamePrefix returns the prefix of the names of the workers of a model
+func workerNamePrefix(modelName string) string {
+	return "nomad-" + strings.Replace(strings.ToLower(modelName), ".", "-", -1) + "-"
+}
+
+// jobIDPrefix is the prefix of the nomad jobs of the hatchery, the id of
+// a job is the prefix followed by the name of its worker
+func (h *HatcheryNomad) jobIDPrefix() string {
+	return h.Config.Name + "."
+}
+
+func (h *HatcheryNomad) workerName(jobID string) string {
+	return strings.TrimPrefix(jobID, h.jobIDPrefix())
+}
+
+func (h *HatcheryNomad) listWorkerJobs() ([]nomadJobStub, error) {
+	return h.nomadClient.listJobs(context.Background(), h.jobIDPrefix())
+}
+
+// SpawnWorker submits a nomad batch job running a worker
+func (h *HatcheryNomad) SpawnWorker(ctx context.Context, spawnArgs hatchery.SpawnArguments) (string, error) {
+	name := workerNamePrefix(spawnArgs.Model.Name) + strings.Replace(namesgenerator.GetRandomNameCDS(0), "_", "-", -1)
+	if spawnArgs.RegisterOnly {
+		name = "register-" + name
+	}
+
+	log.Debug("hatchery> nomad> SpawnWorker> %s", name)
+
+	if h.hatch == nil {
+		return "", fmt.Errorf("hatchery disconnected from engine")
+	}
+
+	udataParam := sdk.WorkerArgs{
+		API:               h.Configuration().API.HTTP.URL,
+		Token:             h.Configuration().API.Token,
+		HTTPInsecure:      h.Config.API.HTTP.Insecure,
+		Name:              name,
+		Model:             spawnArgs.Model.ID,
+		HatcheryName:      h.Service().Name,
+		TTL:               h.Config.WorkerTTL,
+		GraylogHost:       h.Configuration().Provision.WorkerLogsOptions.Graylog.Host,
+		GraylogPort:       h.Configuration().Provision.WorkerLogsOptions.Graylog.Port,
+		GraylogExtraKey:   h.Configuration().Provision.WorkerLogsOptions.Graylog.ExtraKey,
+		GraylogExtraValue: h.Configuration().Provision.WorkerLogsOptions.Graylog.ExtraValue,
+		GrpcAPI:           h.Configuration().API.GRPC.URL,
+		GrpcInsecure:      h.Configuration().API.GRPC.Insecure,
+	}
+	udataParam.WorkflowJobID = spawnArgs.JobID
+
+	job, err := h.workerJob(name, spawnArgs, udataParam)
+	if err != nil {
+		return "", err
+	}
+
+	if err := h.nomadClient.registerJob(ctx, job); err != nil {
+		return "", sdk.WrapError(err, "unable to submit nomad job %s", job.ID)
+	}
+
+	log.Debug("hatchery> nomad> SpawnWorker> %s > Job submitted", name)
+
+	return name, nil
+}
+
+// workerJob computes the nomad job of a worker
+func (h *HatcheryNomad) workerJob(name string, spawnArgs hatchery.SpawnArguments, udataParam sdk.WorkerArgs) (nomadJob, error) {
+	memory := h.Config.DefaultMemory
+	for _, r := range spawnArgs.Requirements {
+		if r.Type == sdk.MemoryRequirement {
+			m, err := strconv.Atoi(r.Value)
+			if err != nil {
+				return nomadJob{}, sdk.WrapError(err, "unable to parse memory requirement %s", r.Value)
+			}
+			memory = m
+		}
+	}
+	if spawnArgs.RegisterOnly {
+		memory = int(hatchery.MemoryRegisterContainer)
+	}
+
+	env := map[string]string{}
+	env["CDS_FORCE_EXIT"] = "1"
+	env["CDS_API"] = udataParam.API
+	env["CDS_TOKEN"] = udataParam.Token
+	env["CDS_NAME"] = udataParam.Name
+	env["CDS_MODEL"] = fmt.Sprintf("%d", udataParam.Model)
+	env["CDS_HATCHERY_NAME"] = udataParam.HatcheryName
+	env["CDS_FROM_WORKER_IMAGE"] = fmt.Sprintf("%v", udataParam.FromWorkerImage)
+	env["CDS_INSECURE"] = fmt.Sprintf("%v", udataParam.HTTPInsecure)
+	env["CDS_TTL"] = fmt.Sprintf("%d", udataParam.TTL)
+	if spawnArgs.JobID > 0 {
+		env["CDS_BOOKED_WORKFLOW_JOB_ID"] = fmt.Sprintf("%d", spawnArgs.JobID)
+	}
+	if udataParam.GrpcAPI != "" && spawnArgs.Model.Communication == sdk.GRPC {
+		env["CDS_GRPC_API"] = udataParam.GrpcAPI
+		env["CDS_GRPC_INSECURE"] = fmt.Sprintf("%v", udataParam.GrpcInsecure)
+	}
+
+	task := nomadTask{
+		Name:      taskName,
+		Driver:    h.Config.Driver,
+		Resources: nomadResources{CPU: h.Config.DefaultCPU, MemoryMB: memory},
+	}
+
+	switch h.Config.Driver {
+	case DriverExec:
+		cmd, err := templateCmd(spawnArgs.Model.ModelVirtualMachine.Cmd, udataParam)
+		if err != nil {
+			return nomadJob{}, err
+		}
+		if spawnArgs.RegisterOnly {
+			cmd += " register"
+		}
+		task.Config = map[string]interface{}{
+			"command": "/bin/sh",
+			"args":    []string{"-c", cmd},
+		}
+	default:
+		cmd, err := templateCmd(spawnArgs.Model.ModelDocker.Cmd, udataParam)
+		if err != nil {
+			return nomadJob{}, err
+		}
+		if spawnArgs.RegisterOnly {
+			cmd += " register"
+		}
+		shell := strings.Fields(spawnArgs.Model.ModelDocker.Shell)
+		if len(shell) == 0 {
+			shell = []string{"sh", "-c"}
+		}
+		task.Config = map[string]interface{}{
+			"image":   spawnArgs.Model.ModelDocker.Image,
+			"command": shell[0],
+			"args":    append(shell[1:], cmd),
+		}
+
+		envTemplated, err := sdk.TemplateEnvs(udataParam, spawnArgs.Model.ModelDocker.Envs)
+		if err != nil {
+			return nomadJob{}, err
+		}
+		for k, v := range envTemplated {
+			env[k] = v
+		}
+	}
+	task.Env = env
+
+	return nomadJob{
+		ID:          h.jobIDPrefix() + name,
+		Name:        h.jobIDPrefix() + name,
+		Type:        "batch",
+		Region:      h.Config.Region,
+		Namespace:   h.Config.Namespace,
+		Datacenters: h.datacenters,
+		Meta: map[string]string{
+			"worker":            name,
+			"hatchery_name":     h.Configuration().Name,
+			"register_only":     fmt.Sprintf("%t", spawnArgs.RegisterOnly),
+			"worker_model_id":   strconv.FormatInt(spawnArgs.Model.ID, 10),
+			"worker_model_name": spawnArgs.Model.Name,
+		},
+		Constraints: requirementsConstraints(spawnArgs.Requirements),
+		TaskGroups: []nomadTaskGroup{{
+			Name:  taskName,
+			Count: 1,
+			// a worker is never restarted nor rescheduled, the job is requeued by CDS
+			RestartPolicy: nomadRestartPolicy{
+				Attempts: 0,
+				Interval: 5 * time.Minute,
+				Delay:    15 * time.Second,
+				Mode:     "fail",
+			},
+			ReschedulePolicy: nomadReschedulePolicy{Attempts: 0, Unlimited: false},
+			Tasks:            []nomadTask{task},
+		}},
+	}, nil
+}
+
+// requirementsConstraints maps the requirements of a job to the constraints
+// of the nomad job, only the requirements checked on the node are mapped
+func requirementsConstraints(requirements []sdk.Requirement) []nomadConstraint {
+	var constraints []nomadConstraint
+	for _, r := range requirements {
+		switch r.Type {
+		case sdk.OSArchRequirement:
+			osArch := strings.SplitN(r.Value, "/", 2)
+			if len(osArch) != 2 {
+				continue
+			}
+			constraints = append(constraints,
+				nomadConstraint{LTarget: "${attr.kernel.name}", RTarget: osArch[0], Operand: "="},
+				nomadConstraint{LTarget: "${attr.cpu.arch}", RTarget: osArch[1], Operand: "="},
+			)
+		case sdk.HostnameRequirement:
+			constraints = append(constraints, nomadConstraint{LTarget: "${attr.unique.hostname}", RTarget: r.Value, Operand: "="})
+		}
+	}
+	return constraints
+}
+
+func templateCmd(cmd string, udataParam sdk.WorkerArgs) (string, error) {
+	tmpl, err := template.New("cmd").Parse(cmd)
+	if err != nil {
+		return "", err
+	}
+	var buffer bytes.Buffer
+	if err := tmpl.Execute(&buffer, udataParam); err != nil {
+		return "", err
+	}
+	return buffer.String(), nil
+}
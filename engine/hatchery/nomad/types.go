@@ -0,0 +1,54 @@
+package nomad
+
+import (
+	hatcheryCommon "github.com/ovh/cds/engine/hatchery"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/hatchery"
+)
+
+// Nomad drivers supported by the hatchery
+const (
+	DriverDocker = "docker"
+	DriverExec   = "exec"
+)
+
+// HatcheryConfiguration is the configuration for nomad hatchery
+type HatcheryConfiguration struct {
+	hatchery.CommonConfiguration `mapstructure:"commonConfiguration" toml:"commonConfiguration" json:"commonConfiguration"`
+
+	// Address of the Nomad API
+	Address string `mapstructure:"address" toml:"address" default:"http://127.0.0.1:4646" commented:"false" comment:"Address of the Nomad HTTP API" json:"address"`
+
+	// Token ACL token of Nomad
+	Token string `mapstructure:"token" toml:"token" default:"" commented:"true" comment:"Nomad ACL token, needs the submit-job capability" json:"-"`
+
+	// Region of the jobs
+	Region string `mapstructure:"region" toml:"region" default:"" commented:"true" comment:"Nomad region of the jobs, the region of the agent if empty" json:"region,omitempty"`
+
+	// Namespace of the jobs
+	Namespace string `mapstructure:"namespace" toml:"namespace" default:"" commented:"true" comment:"Nomad namespace of the jobs (Nomad Enterprise)" json:"namespace,omitempty"`
+
+	// Datacenters of the jobs
+	Datacenters string `mapstructure:"datacenters" toml:"datacenters" default:"dc1" commented:"false" comment:"Datacenters where the workers can run. \n Format: dc1,dc2" json:"datacenters"`
+
+	// Driver of the tasks
+	Driver string `mapstructure:"driver" toml:"driver" default:"docker" commented:"false" comment:"Nomad driver of the workers: docker to run the worker models of type docker, exec to run the worker models of type host" json:"driver"`
+
+	// WorkerTTL Worker TTL (minutes)
+	WorkerTTL int `mapstructure:"workerTTL" toml:"workerTTL" default:"10" commented:"false" comment:"Worker TTL (minutes)" json:"workerTTL"`
+
+	// DefaultMemory Worker default memory
+	DefaultMemory int `mapstructure:"defaultMemory" toml:"defaultMemory" default:"1024" commented:"false" comment:"Worker default memory in Mo" json:"defaultMemory"`
+
+	// DefaultCPU Worker default CPU
+	DefaultCPU int `mapstructure:"defaultCPU" toml:"defaultCPU" default:"500" commented:"false" comment:"Worker default CPU in MHz" json:"defaultCPU"`
+}
+
+// HatcheryNomad spawns the workers as Nomad batch jobs
+type HatcheryNomad struct {
+	hatcheryCommon.Common
+	Config      HatcheryConfiguration
+	hatch       *sdk.Hatchery
+	nomadClient *nomadClient
+	datacenters []string // computed from datacenters
+}
@@ -239,6 +239,7 @@ func (h *HatcheryOpenstack) killAwolServers() {
 		}
 
 		var inWorkersList bool
+		var worker sdk.Worker
 		for _, w := range workers {
 			if _, ok := workersAlive[w.Name]; !ok {
 				log.Debug("killAwolServers> add %s to map workersAlive", w.Name)
@@ -247,6 +248,7 @@ func (h *HatcheryOpenstack) killAwolServers() {
 
 			if w.Name == s.Name {
 				inWorkersList = true
+				worker = w
 				workersAlive[w.Name] = now
 				break
 			}
@@ -259,6 +261,7 @@ func (h *HatcheryOpenstack) killAwolServers() {
 		workerModelNameLastModified, _ := s.Metadata["worker_model_last_modified"]
 		model, _ := s.Metadata["model"]
 		flavor, _ := s.Metadata["flavor"]
+		preemptible, _ := s.Metadata["preemptible"]
 
 		var toDeleteKilled bool
 		if isWorker {
@@ -284,6 +287,12 @@ func (h *HatcheryOpenstack) killAwolServers() {
 				h.killAwolServersComputeImage(workerModelName, workerModelNameLastModified, s.ID, model, flavor)
 			}
 
+			// a preemptible server stopped while its worker was building has been preempted
+			if preemptible == "true" && s.Status == "SHUTOFF" && inWorkersList &&
+				worker.Status == sdk.StatusBuilding && worker.ActionBuildID != 0 {
+				hatchery.WorkerLost(ctx, h, worker.ActionBuildID, workerName, "server "+s.ID+" preempted")
+			}
+
 			log.Debug("killAwolServers> Deleting server %s status: %s last update: %s registerOnly:%s toDeleteKilled:%t inWorkersList:%t", s.Name, s.Status, time.Since(s.Updated), registerOnly, toDeleteKilled, inWorkersList)
 			_ = h.deleteServer(s)
 		}
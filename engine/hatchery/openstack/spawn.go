@@ -121,6 +121,10 @@ func (h *HatcheryOpenstack) SpawnWorker(ctx context.Context, spawnArgs hatchery.
 		"worker_model_name":          spawnArgs.Model.Name,
 		"worker_model_last_modified": fmt.Sprintf("%d", spawnArgs.Model.UserLastModified.Unix()),
 	}
+	// A preempted server is stopped by the cloud provider, its job will be replaced in queue
+	if spawnArgs.Model.ModelVirtualMachine.Preemptible && !spawnArgs.RegisterOnly {
+		meta["preemptible"] = "true"
+	}
 
 	// Ip len(ipsInfos.ips) > 0, specify one of those
 	var ip string
@@ -0,0 +1,34 @@
+package hatchery
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gorilla/mux"
+
+	"github.com/ovh/cds/engine/api"
+	"github.com/ovh/cds/engine/api/test"
+	"github.com/ovh/cds/engine/api/test/assets"
+	"github.com/ovh/cds/sdk/hatchery"
+)
+
+type testHatchery struct {
+	hatchery.Interface
+}
+
+func (h testHatchery) Configuration() hatchery.CommonConfiguration {
+	return hatchery.CommonConfiguration{}
+}
+
+func Test_getMetricsHandler(t *testing.T) {
+	ctx := context.Background()
+	c := new(Common)
+	c.Hash = "hash"
+	c.Router = &api.Router{
+		Mux:        mux.NewRouter(),
+		Prefix:     "/" + test.GetTestName(t),
+		Background: ctx,
+	}
+	c.initRouter(ctx, testHatchery{})
+	assets.CheckMetricsHandler(t, c.Router, c.Router.Mux, "cds-hatchery")
+}
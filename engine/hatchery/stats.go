@@ -44,6 +44,9 @@ func (c *Common) initMetrics(hatcheryName string) error {
 	label = fmt.Sprintf("cds/%s/%s/disabled_workers", c.ServiceName(), hatcheryName)
 	c.metrics.DisabledWorkers = stats.Int64(label, "number of disabled workers", stats.UnitDimensionless)
 
+	label = fmt.Sprintf("cds/%s/%s/spawn_duration", c.ServiceName(), hatcheryName)
+	c.metrics.SpawnDuration = stats.Float64(label, "duration of the spawn of the workers", stats.UnitMilliseconds)
+
 	log.Info("hatchery> Stats initialized on %s", c.ServiceName())
 
 	tagCDSInstance, _ := tag.NewKey("cds")
@@ -59,5 +62,6 @@ func (c *Common) initMetrics(hatcheryName string) error {
 		observability.NewViewLast("checking_workers", c.metrics.CheckingWorkers, tags),
 		observability.NewViewLast("building_workers", c.metrics.BuildingWorkers, tags),
 		observability.NewViewLast("disabled_workers", c.metrics.DisabledWorkers, tags),
+		observability.NewViewDistribution("spawn_duration", c.metrics.SpawnDuration, tags, observability.LatencyBounds),
 	)
 }
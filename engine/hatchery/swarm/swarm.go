@@ -16,6 +16,7 @@ import (
 
 	types "github.com/docker/docker/api/types"
 	docker "github.com/docker/docker/client"
+	"github.com/docker/go-connections/sockets"
 	"github.com/docker/go-connections/tlsconfig"
 	"github.com/gorilla/mux"
 	context "golang.org/x/net/context"
@@ -59,6 +60,7 @@ func (h *HatcherySwarm) Init() error {
 			MaxContainers: h.Config.MaxContainers,
 			name:          "default",
 		}
+		h.detectEngine(h.dockerClients["default"])
 		log.Info("hatchery> swarm> connected to default docker engine")
 
 	} else {
@@ -113,7 +115,15 @@ func (h *HatcherySwarm) Init() error {
 				}
 			}
 
-			if tlsc != nil {
+			if strings.HasPrefix(cfg.Host, "unix://") {
+				// local socket, as the socket of a rootless podman
+				transport := &http.Transport{}
+				if err := sockets.ConfigureTransport(transport, "unix", strings.TrimPrefix(cfg.Host, "unix://")); err != nil {
+					log.Error("hatchery> swarm> docker client error: unable to use socket %s: %v", cfg.Host, err)
+					continue
+				}
+				httpClient.Transport = transport
+			} else if tlsc != nil {
 				httpClient.Transport = &http.Transport{
 					DialContext: (&net.Dialer{
 						Timeout:   30 * time.Second,
@@ -160,6 +170,7 @@ func (h *HatcherySwarm) Init() error {
 				MaxContainers: cfg.MaxContainers,
 				name:          hostName,
 			}
+			h.detectEngine(h.dockerClients[hostName])
 		}
 		if len(h.dockerClients) == 0 {
 			log.Error("hatchery> swarm> no docker host available. Please check errors")
@@ -240,6 +251,8 @@ func (h *HatcherySwarm) SpawnWorker(ctx context.Context, spawnArgs hatchery.Spaw
 
 	var network, networkAlias string
 	services := []string{}
+	// alias -> container name of the services
+	servicesAliases := map[string]string{}
 
 	if spawnArgs.JobID > 0 {
 		for _, r := range spawnArgs.Requirements {
@@ -263,13 +276,16 @@ func (h *HatcherySwarm) SpawnWorker(ctx context.Context, spawnArgs hatchery.Spaw
 				}
 				//name= <alias> => the name of the host put in /etc/hosts of the worker
 				//value= "postgres:latest env_1=blabla env_2=blabla" => we can add env variables in requirement name
-				tuple := strings.Split(r.Value, " ")
+				tuple := sdk.SplitServiceRequirementValue(r.Value)
 				img := tuple[0]
 				env := []string{}
 				serviceMemory := int64(1024)
 				if len(tuple) > 1 {
 					for i := 1; i < len(tuple); i++ {
 						splittedTuple := strings.SplitN(tuple[i], "=", 2)
+						if len(splittedTuple) < 2 {
+							continue
+						}
 						name := splittedTuple[0]
 						val := strings.TrimLeft(splittedTuple[1], "\"")
 						val = strings.TrimRight(val, "\"")
@@ -321,6 +337,7 @@ func (h *HatcherySwarm) SpawnWorker(ctx context.Context, spawnArgs hatchery.Spaw
 					return "", err
 				}
 				services = append(services, serviceName)
+				servicesAliases[r.Name] = serviceName
 			}
 		}
 	}
@@ -343,6 +360,25 @@ func (h *HatcherySwarm) SpawnWorker(ctx context.Context, spawnArgs hatchery.Spaw
 		return name, errDockerOpts
 	}
 
+	if dockerClient.podman && len(servicesAliases) > 0 {
+		extraHosts, err := h.servicesExtraHosts(ctx, dockerClient, network, servicesAliases)
+		if err != nil {
+			log.Warning("hatchery> swarm> SpawnWorker> Unable to link services on %s: %v", dockerClient.name, err)
+			return "", err
+		}
+		dockerOpts.extraHosts = append(dockerOpts.extraHosts, extraHosts...)
+	}
+
+	if !spawnArgs.RegisterOnly && h.Config.CacheVolumes.cacheVolumeEnabled(spawnArgs.Model.Name) && !dockerOpts.hasMountTarget(h.Config.CacheVolumes.Destination) {
+		m, err := h.cacheVolumeMount(ctx, dockerClient, spawnArgs.Model.Name)
+		if err != nil {
+			// the worker can run without its cache
+			log.Warning("hatchery> swarm> SpawnWorker> Unable to get a cache volume on %s: %v", dockerClient.name, err)
+		} else if m != nil {
+			dockerOpts.mounts = append(dockerOpts.mounts, *m)
+		}
+	}
+
 	udataParam := sdk.WorkerArgs{
 		API:               h.Configuration().API.HTTP.URL,
 		Token:             h.Configuration().API.Token,
@@ -609,8 +645,17 @@ func (h *HatcherySwarm) routines(ctx context.Context) {
 	ticker := time.NewTicker(10 * time.Second)
 	defer ticker.Stop()
 
+	cacheTicker := time.NewTicker(10 * time.Minute)
+	defer cacheTicker.Stop()
+
 	for {
 		select {
+		case <-cacheTicker.C:
+			if h.Config.CacheVolumes.Enabled {
+				sdk.GoRoutine(ctx, "cleanCacheVolumes", func(ctx context.Context) {
+					h.cleanCacheVolumes()
+				})
+			}
 		case <-ticker.C:
 			sdk.GoRoutine(ctx, "getServicesLogs", func(ctx context.Context) {
 				if err := h.getServicesLogs(); err != nil {
@@ -98,5 +98,23 @@ func (h *HatcherySwarm) CheckConfiguration(cfg interface{}) error {
 		return fmt.Errorf("please enter a name in your swarm hatchery configuration")
 	}
 
+	if hconfig.CacheVolumes.Enabled {
+		switch hconfig.CacheVolumes.Type {
+		case cacheVolumeTypeVolume:
+		case cacheVolumeTypeBind:
+			if hconfig.CacheVolumes.HostPath == "" {
+				return fmt.Errorf("cache volumes of type bind needs a hostPath")
+			}
+		default:
+			return fmt.Errorf("invalid cache volumes type %s, should be %s or %s", hconfig.CacheVolumes.Type, cacheVolumeTypeVolume, cacheVolumeTypeBind)
+		}
+		if hconfig.CacheVolumes.Destination == "" {
+			return fmt.Errorf("cache volumes needs a destination")
+		}
+		if hconfig.CacheVolumes.MaxVolumesPerModel <= 0 {
+			return fmt.Errorf("cache volumes maxVolumesPerModel must be > 0")
+		}
+	}
+
 	return nil
 }
@@ -0,0 +1,294 @@
+package swarm
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	types "github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/mount"
+	volumetypes "github.com/docker/docker/api/types/volume"
+	context "golang.org/x/net/context"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+const (
+	cacheVolumeTypeVolume = "volume"
+	cacheVolumeTypeBind   = "bind"
+
+	labelCacheHatchery = "cds_cache_hatchery"
+	labelCacheModel    = "cds_cache_model"
+
+	// a cache volume given to a spawning worker is not given to another one before its container starts
+	cacheVolumeReservation = 2 * time.Minute
+)
+
+var regexCacheVolumeName = regexp.MustCompile("[^a-z0-9_.-]+")
+
+// cacheVolumes tracks the usage of the cache volumes, the keys are <docker engine>/<volume>
+type cacheVolumes struct {
+	mu       sync.Mutex
+	reserved map[string]time.Time
+	lastUsed map[string]time.Time
+}
+
+// cacheVolume is a cache volume seen by the janitor
+type cacheVolume struct {
+	name     string
+	size     int64 // bytes
+	inUse    bool
+	lastUsed time.Time
+}
+
+func cacheVolumeName(hatcheryName, modelName string, index int) string {
+	return fmt.Sprintf("cds-cache-%s-%s-%d",
+		regexCacheVolumeName.ReplaceAllString(strings.ToLower(hatcheryName), "-"),
+		regexCacheVolumeName.ReplaceAllString(strings.ToLower(modelName), "-"),
+		index)
+}
+
+// cacheVolumeEnabled returns true if the workers of the model use a cache volume
+func (c CacheVolumesConfiguration) cacheVolumeEnabled(modelName string) bool {
+	if !c.Enabled {
+		return false
+	}
+	if strings.TrimSpace(c.Models) == "" {
+		return true
+	}
+	for _, m := range strings.Split(c.Models, ",") {
+		if strings.TrimSpace(m) == modelName {
+			return true
+		}
+	}
+	return false
+}
+
+// usedCacheVolumes returns the volumes and the host paths mounted by the running containers
+func (h *HatcherySwarm) usedCacheVolumes(dockerClient *dockerClient) (map[string]bool, error) {
+	containers, err := h.getContainers(dockerClient, types.ContainerListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	used := map[string]bool{}
+	for _, c := range containers {
+		for _, m := range c.Mounts {
+			if m.Name != "" {
+				used[m.Name] = true
+			}
+			if m.Source != "" {
+				used[m.Source] = true
+			}
+		}
+	}
+	return used, nil
+}
+
+// cacheVolumeMount returns a free cache volume of the model, nil if all the volumes of the model are used
+func (h *HatcherySwarm) cacheVolumeMount(ctx context.Context, dockerClient *dockerClient, modelName string) (*mount.Mount, error) {
+	cfg := h.Config.CacheVolumes
+
+	h.cache.mu.Lock()
+	defer h.cache.mu.Unlock()
+	if h.cache.reserved == nil {
+		h.cache.reserved = map[string]time.Time{}
+		h.cache.lastUsed = map[string]time.Time{}
+	}
+
+	used, err := h.usedCacheVolumes(dockerClient)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := 0; i < cfg.MaxVolumesPerModel; i++ {
+		name := cacheVolumeName(h.Config.Name, modelName, i)
+		source := name
+		if cfg.Type == cacheVolumeTypeBind {
+			source = filepath.Join(cfg.HostPath, name)
+		}
+		key := dockerClient.name + "/" + name
+		if used[source] || time.Since(h.cache.reserved[key]) < cacheVolumeReservation {
+			continue
+		}
+
+		if cfg.Type == cacheVolumeTypeBind {
+			if err := os.MkdirAll(source, 0755); err != nil {
+				return nil, sdk.WrapError(err, "unable to create cache directory %s", source)
+			}
+		} else {
+			// the creation of an existing volume does nothing
+			if _, err := dockerClient.VolumeCreate(ctx, volumetypes.VolumeCreateBody{
+				Name:   name,
+				Driver: "local",
+				Labels: map[string]string{
+					labelCacheHatchery: h.Config.Name,
+					labelCacheModel:    modelName,
+				},
+			}); err != nil {
+				return nil, sdk.WrapError(err, "unable to create cache volume %s on %s", name, dockerClient.name)
+			}
+		}
+
+		h.cache.reserved[key] = time.Now()
+		h.cache.lastUsed[key] = time.Now()
+		log.Debug("hatchery> swarm> cacheVolumeMount> use cache volume %s on %s", name, dockerClient.name)
+		return &mount.Mount{
+			Type:   mount.Type(cfg.Type),
+			Source: source,
+			Target: cfg.Destination,
+		}, nil
+	}
+
+	log.Debug("hatchery> swarm> cacheVolumeMount> all cache volumes of model %s are used on %s", modelName, dockerClient.name)
+	return nil, nil
+}
+
+// cleanCacheVolumes removes the cache volumes unused for too long, too big, or above the total size
+func (h *HatcherySwarm) cleanCacheVolumes() {
+	cfg := h.Config.CacheVolumes
+	for _, dockerClient := range h.dockerClients {
+		volumes, err := h.listCacheVolumes(dockerClient)
+		if err != nil {
+			log.Warning("hatchery> swarm> cleanCacheVolumes> unable to list cache volumes on %s: %v", dockerClient.name, err)
+			continue
+		}
+
+		toRemove := cacheVolumesToEvict(volumes, cfg.MaxSize*1024*1024, cfg.MaxTotalSize*1024*1024, time.Duration(cfg.MaxUnusedTime)*time.Hour, time.Now())
+		for _, v := range toRemove {
+			log.Info("hatchery> swarm> cleanCacheVolumes> remove cache volume %s on %s (size:%dMo, last used:%v)", v.name, dockerClient.name, v.size/1024/1024, v.lastUsed)
+			if err := h.removeCacheVolume(dockerClient, v.name); err != nil {
+				log.Warning("hatchery> swarm> cleanCacheVolumes> unable to remove cache volume %s on %s: %v", v.name, dockerClient.name, err)
+				continue
+			}
+			h.cache.mu.Lock()
+			delete(h.cache.lastUsed, dockerClient.name+"/"+v.name)
+			h.cache.mu.Unlock()
+		}
+	}
+}
+
+// listCacheVolumes returns the cache volumes of the hatchery on a docker engine, with their size and usage
+func (h *HatcherySwarm) listCacheVolumes(dockerClient *dockerClient) ([]cacheVolume, error) {
+	cfg := h.Config.CacheVolumes
+	used, err := h.usedCacheVolumes(dockerClient)
+	if err != nil {
+		return nil, err
+	}
+	prefix := strings.TrimSuffix(cacheVolumeName(h.Config.Name, "", 0), "-0")
+
+	var volumes []cacheVolume
+	if cfg.Type == cacheVolumeTypeBind {
+		files, err := ioutil.ReadDir(cfg.HostPath)
+		if err != nil {
+			return nil, sdk.WithStack(err)
+		}
+		for _, f := range files {
+			if !f.IsDir() || !strings.HasPrefix(f.Name(), prefix) {
+				continue
+			}
+			source := filepath.Join(cfg.HostPath, f.Name())
+			volumes = append(volumes, cacheVolume{name: f.Name(), size: dirSize(source), inUse: used[source], lastUsed: f.ModTime()})
+		}
+	} else {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+		// the size of the volumes is only computed by the disk usage
+		du, err := dockerClient.DiskUsage(ctx)
+		if err != nil {
+			return nil, sdk.WrapError(err, "unable to get disk usage")
+		}
+		for _, v := range du.Volumes {
+			if v == nil || v.Labels[labelCacheHatchery] != h.Config.Name {
+				continue
+			}
+			cv := cacheVolume{name: v.Name, inUse: used[v.Name]}
+			if v.UsageData != nil {
+				cv.size = v.UsageData.Size
+				cv.inUse = cv.inUse || v.UsageData.RefCount > 0
+			}
+			cv.lastUsed, _ = time.Parse(time.RFC3339, v.CreatedAt)
+			volumes = append(volumes, cv)
+		}
+	}
+
+	// the last usage known by the hatchery is more accurate
+	h.cache.mu.Lock()
+	defer h.cache.mu.Unlock()
+	if h.cache.lastUsed == nil {
+		h.cache.reserved = map[string]time.Time{}
+		h.cache.lastUsed = map[string]time.Time{}
+	}
+	for i := range volumes {
+		key := dockerClient.name + "/" + volumes[i].name
+		if volumes[i].inUse {
+			h.cache.lastUsed[key] = time.Now()
+		}
+		if t, ok := h.cache.lastUsed[key]; ok && t.After(volumes[i].lastUsed) {
+			volumes[i].lastUsed = t
+		}
+		// a volume reserved by a spawning worker is in use
+		if time.Since(h.cache.reserved[key]) < cacheVolumeReservation {
+			volumes[i].inUse = true
+		}
+	}
+	return volumes, nil
+}
+
+func (h *HatcherySwarm) removeCacheVolume(dockerClient *dockerClient, name string) error {
+	if h.Config.CacheVolumes.Type == cacheVolumeTypeBind {
+		return sdk.WithStack(os.RemoveAll(filepath.Join(h.Config.CacheVolumes.HostPath, name)))
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	return dockerClient.VolumeRemove(ctx, name, false)
+}
+
+// cacheVolumesToEvict returns the unused volumes unused since more than maxUnused, bigger than maxSize,
+// and the least recently used ones while the total size is above maxTotalSize
+func cacheVolumesToEvict(volumes []cacheVolume, maxSize, maxTotalSize int64, maxUnused time.Duration, now time.Time) []cacheVolume {
+	var toRemove, candidates []cacheVolume
+	var total int64
+	for _, v := range volumes {
+		switch {
+		case v.inUse:
+			total += v.size
+		case (maxUnused > 0 && now.Sub(v.lastUsed) > maxUnused) || (maxSize > 0 && v.size > maxSize):
+			toRemove = append(toRemove, v)
+		default:
+			total += v.size
+			candidates = append(candidates, v)
+		}
+	}
+
+	if maxTotalSize <= 0 || total <= maxTotalSize {
+		return toRemove
+	}
+
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i].lastUsed.Before(candidates[j].lastUsed) })
+	for _, v := range candidates {
+		if total <= maxTotalSize {
+			break
+		}
+		toRemove = append(toRemove, v)
+		total -= v.size
+	}
+	return toRemove
+}
+
+func dirSize(path string) int64 {
+	var size int64
+	_ = filepath.Walk(path, func(_ string, info os.FileInfo, err error) error {
+		if err == nil && !info.IsDir() {
+			size += info.Size()
+		}
+		return nil
+	})
+	return size
+}
@@ -0,0 +1,45 @@
+package swarm
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCacheVolumeName(t *testing.T) {
+	assert.Equal(t, "cds-cache-my-hatchery-go-official-1.11-0", cacheVolumeName("My Hatchery", "go-official-1.11", 0))
+
+	cfg := CacheVolumesConfiguration{Enabled: true}
+	assert.True(t, cfg.cacheVolumeEnabled("go-official"))
+	cfg.Models = "docker-in-docker, go-official"
+	assert.True(t, cfg.cacheVolumeEnabled("go-official"))
+	assert.False(t, cfg.cacheVolumeEnabled("debian"))
+	cfg.Enabled = false
+	assert.False(t, cfg.cacheVolumeEnabled("go-official"))
+}
+
+func TestCacheVolumesToEvict(t *testing.T) {
+	now := time.Now()
+	volumes := []cacheVolume{
+		{name: "in-use", size: 50, inUse: true, lastUsed: now.Add(-100 * time.Hour)},
+		{name: "unused-for-too-long", size: 1, lastUsed: now.Add(-73 * time.Hour)},
+		{name: "too-big", size: 200, lastUsed: now},
+		{name: "old", size: 30, lastUsed: now.Add(-2 * time.Hour)},
+		{name: "recent", size: 30, lastUsed: now.Add(-time.Hour)},
+	}
+
+	names := func(vs []cacheVolume) []string {
+		res := []string{}
+		for _, v := range vs {
+			res = append(res, v.name)
+		}
+		return res
+	}
+
+	assert.Equal(t, []string{"unused-for-too-long", "too-big"}, names(cacheVolumesToEvict(volumes, 100, 200, 72*time.Hour, now)))
+	// 50+30+30 > 90: the least recently used volume is removed
+	assert.Equal(t, []string{"unused-for-too-long", "too-big", "old"}, names(cacheVolumesToEvict(volumes, 100, 90, 72*time.Hour, now)))
+	// the volumes in use are never removed
+	assert.Equal(t, []string{"unused-for-too-long", "too-big", "old", "recent"}, names(cacheVolumesToEvict(volumes, 100, 10, 72*time.Hour, now)))
+}
@@ -24,19 +24,31 @@ func (h *HatcherySwarm) createNetwork(ctx context.Context, dockerClient *dockerC
 	ctx, end := observability.Span(ctx, "swarm.createNetwork", observability.Tag("network", name))
 	defer end()
 	log.Debug("hatchery> swarm> createNetwork> Create network %s", name)
-	_, err := dockerClient.NetworkCreate(ctx, name, types.NetworkCreate{
+	_, err := dockerClient.NetworkCreate(ctx, name, networkCreateOptions(dockerClient, name, h.Config.NetworkEnableIPv6))
+	return err
+}
+
+func networkCreateOptions(dockerClient *dockerClient, name string, enableIPv6 bool) types.NetworkCreate {
+	opts := types.NetworkCreate{
 		Driver:         "bridge",
 		Internal:       false,
 		CheckDuplicate: true,
-		EnableIPv6:     h.Config.NetworkEnableIPv6,
+		EnableIPv6:     enableIPv6,
 		IPAM: &network.IPAM{
 			Driver: "default",
 		},
 		Labels: map[string]string{
 			"worker_net": name,
 		},
-	})
-	return err
+	}
+	if dockerClient.podman {
+		// podman only knows its own ipam drivers, and rootless networks have no ipv6
+		opts.IPAM = nil
+		if dockerClient.rootless {
+			opts.EnableIPv6 = false
+		}
+	}
+	return opts
 }
 
 type containerArgs struct {
@@ -80,9 +92,11 @@ func (h *HatcherySwarm) createAndStartContainer(ctx context.Context, dockerClien
 		Mounts:       cArgs.dockerOpts.mounts,
 		ExtraHosts:   cArgs.dockerOpts.extraHosts,
 	}
-	hostConfig.Resources = container.Resources{
-		Memory:     cArgs.memory * 1024 * 1024, //from MB to B
-		MemorySwap: -1,
+	if !dockerClient.noResourceLimits {
+		hostConfig.Resources = container.Resources{
+			Memory:     cArgs.memory * 1024 * 1024, //from MB to B
+			MemorySwap: -1,
+		}
 	}
 
 	networkingConfig := &network.NetworkingConfig{
@@ -125,7 +139,7 @@ checkImage:
 		})
 
 		_, next := observability.Span(ctx, "swarm.dockerClient.pullImage", observability.Tag("image", cArgs.image))
-		if err := h.pullImage(dockerClient, cArgs.image, timeoutPullImage); err != nil {
+		if err := h.pullImage(dockerClient, cArgs.image, spawnArgs.Model.ModelDocker.RegistryAuth, timeoutPullImage); err != nil {
 			next()
 			hatchery.SendSpawnInfo(ctx, h, spawnArgs.JobID, sdk.SpawnMsg{
 				ID:   sdk.MsgSpawnInfoHatcheryEndDockerPullErr.ID,
@@ -290,6 +304,15 @@ func (d *dockerOpts) computeDockerOptsOnVolumeMountRequirement(opt string) error
 	return nil
 }
 
+func (d *dockerOpts) hasMountTarget(target string) bool {
+	for _, m := range d.mounts {
+		if m.Target == target {
+			return true
+		}
+	}
+	return false
+}
+
 func (d *dockerOpts) computeDockerOptsExtraHosts(arg string) error {
 	value := strings.TrimPrefix(strings.TrimSpace(arg), "--add-host=")
 	d.extraHosts = append(d.extraHosts, value)
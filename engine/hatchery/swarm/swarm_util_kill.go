@@ -97,7 +97,7 @@ func (h *HatcherySwarm) killAndRemove(dockerClient *dockerClient, ID string) err
 		}
 
 		//If it's the default docker bridge... skip
-		if network.Driver != bridge || network.Name == docker0 || network.Name == bridge {
+		if isDefaultNetwork(network) {
 			continue
 		}
 
@@ -164,7 +164,7 @@ func (h *HatcherySwarm) killAwolNetworks() error {
 				continue
 			}
 
-			if n.Driver != bridge || n.Name == docker0 || n.Name == bridge {
+			if isDefaultNetwork(n) {
 				continue
 			}
 
@@ -0,0 +1,93 @@
+package swarm
+
+import (
+	"strings"
+	"time"
+
+	types "github.com/docker/docker/api/types"
+	context "golang.org/x/net/context"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// podmanNetwork is the default network of a podman engine
+const podmanNetwork = "podman"
+
+// detectEngine checks if the docker api is served by podman, and if the engine runs rootless
+func (h *HatcherySwarm) detectEngine(dockerClient *dockerClient) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	version, err := dockerClient.ServerVersion(ctx)
+	if err != nil {
+		log.Warning("hatchery> swarm> detectEngine> unable to get version of %s: %v", dockerClient.name, err)
+		return
+	}
+	dockerClient.podman = isPodman(version)
+	if !dockerClient.podman {
+		return
+	}
+
+	info, err := dockerClient.Info(ctx)
+	if err != nil {
+		log.Warning("hatchery> swarm> detectEngine> unable to get info of %s: %v", dockerClient.name, err)
+		return
+	}
+	dockerClient.rootless = isRootless(info)
+	// a rootless engine can only limit the resources of the containers with the systemd cgroup manager
+	dockerClient.noResourceLimits = dockerClient.rootless && info.CgroupDriver == "cgroupfs"
+
+	log.Info("hatchery> swarm> %s is a podman engine (rootless:%t)", dockerClient.name, dockerClient.rootless)
+}
+
+func isPodman(version types.Version) bool {
+	for _, c := range version.Components {
+		if strings.Contains(strings.ToLower(c.Name), "podman") {
+			return true
+		}
+	}
+	return false
+}
+
+func isRootless(info types.Info) bool {
+	for _, opt := range info.SecurityOptions {
+		if opt == "rootless" || strings.Contains(opt, "name=rootless") {
+			return true
+		}
+	}
+	return false
+}
+
+// isDefaultNetwork returns true for the networks not created by the hatchery
+func isDefaultNetwork(n types.NetworkResource) bool {
+	return n.Driver != bridge || n.Name == docker0 || n.Name == bridge || n.Name == podmanNetwork
+}
+
+// servicesExtraHosts returns the host entries of the services started on a network.
+// The network aliases are not resolved by all the podman network backends,
+// so the worker reaches its services with its /etc/hosts.
+func (h *HatcherySwarm) servicesExtraHosts(ctx context.Context, dockerClient *dockerClient, network string, services map[string]string) ([]string, error) {
+	extraHosts := make([]string, 0, len(services))
+	for alias, containerName := range services {
+		c, err := dockerClient.ContainerInspect(ctx, containerName)
+		if err != nil {
+			return nil, sdk.WrapError(err, "unable to inspect service %s on %s", containerName, dockerClient.name)
+		}
+		if host := serviceExtraHost(c, network, alias); host != "" {
+			extraHosts = append(extraHosts, host)
+		}
+	}
+	return extraHosts, nil
+}
+
+func serviceExtraHost(c types.ContainerJSON, network, alias string) string {
+	if c.NetworkSettings == nil {
+		return ""
+	}
+	settings, ok := c.NetworkSettings.Networks[network]
+	if !ok || settings == nil || settings.IPAddress == "" {
+		return ""
+	}
+	return alias + ":" + settings.IPAddress
+}
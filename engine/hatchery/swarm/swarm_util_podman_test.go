@@ -0,0 +1,45 @@
+package swarm
+
+import (
+	"testing"
+
+	types "github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/network"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsPodman(t *testing.T) {
+	assert.False(t, isPodman(types.Version{Components: []types.ComponentVersion{{Name: "Engine", Version: "18.09.0"}}}))
+	assert.True(t, isPodman(types.Version{Components: []types.ComponentVersion{{Name: "Podman Engine", Version: "4.3.1"}}}))
+
+	assert.False(t, isRootless(types.Info{SecurityOptions: []string{"name=seccomp,profile=default"}}))
+	assert.True(t, isRootless(types.Info{SecurityOptions: []string{"name=seccomp,profile=default", "name=rootless"}}))
+}
+
+func TestNetworkCreateOptions(t *testing.T) {
+	opts := networkCreateOptions(&dockerClient{}, "my-net", true)
+	assert.NotNil(t, opts.IPAM)
+	assert.True(t, opts.EnableIPv6)
+	assert.Equal(t, "my-net", opts.Labels["worker_net"])
+
+	opts = networkCreateOptions(&dockerClient{podman: true, rootless: true}, "my-net", true)
+	assert.Nil(t, opts.IPAM)
+	assert.False(t, opts.EnableIPv6)
+	assert.Equal(t, "my-net", opts.Labels["worker_net"])
+
+	assert.True(t, isDefaultNetwork(types.NetworkResource{Name: podmanNetwork, Driver: bridge}))
+	assert.False(t, isDefaultNetwork(types.NetworkResource{Name: "my-net", Driver: bridge}))
+}
+
+func TestServiceExtraHost(t *testing.T) {
+	c := types.ContainerJSON{
+		NetworkSettings: &types.NetworkSettings{
+			Networks: map[string]*network.EndpointSettings{
+				"my-net": {IPAddress: "10.88.0.3"},
+			},
+		},
+	}
+	assert.Equal(t, "pg:10.88.0.3", serviceExtraHost(c, "my-net", "pg"))
+	assert.Equal(t, "", serviceExtraHost(c, "other-net", "pg"))
+	assert.Equal(t, "", serviceExtraHost(types.ContainerJSON{}, "my-net", "pg"))
+}
@@ -2,7 +2,10 @@ package swarm
 
 import (
 	"bytes"
+	"encoding/base64"
+	"encoding/json"
 	"io"
+	"strings"
 	"time"
 
 	"github.com/ovh/cds/sdk"
@@ -12,7 +15,39 @@ import (
 	context "golang.org/x/net/context"
 )
 
-func (h *HatcherySwarm) pullImage(dockerClient *dockerClient, img string, timeout time.Duration) error {
+// registryHost returns the host of the url of a registry
+func registryHost(url string) string {
+	url = strings.TrimPrefix(strings.TrimPrefix(url, "https://"), "http://")
+	return strings.Split(url, "/")[0]
+}
+
+// imageRegistryHost returns the host of the registry of an image, an image without host is on the docker hub
+func imageRegistryHost(img string) string {
+	parts := strings.Split(img, "/")
+	if len(parts) == 1 || (!strings.ContainsAny(parts[0], ".:") && parts[0] != "localhost") {
+		return "docker.io"
+	}
+	return parts[0]
+}
+
+// registryAuth returns the encoded credentials to pull an image, the credentials of a registry are only sent
+// for the images of this registry
+func registryAuth(img string, auth *sdk.ModelDockerRegistryAuth) (string, error) {
+	if auth == nil || auth.Username == "" || registryHost(auth.URL) != imageRegistryHost(img) {
+		return "", nil
+	}
+	btes, err := json.Marshal(types.AuthConfig{
+		Username:      auth.Username,
+		Password:      auth.Password,
+		ServerAddress: registryHost(auth.URL),
+	})
+	if err != nil {
+		return "", sdk.WithStack(err)
+	}
+	return base64.URLEncoding.EncodeToString(btes), nil
+}
+
+func (h *HatcherySwarm) pullImage(dockerClient *dockerClient, img string, auth *sdk.ModelDockerRegistryAuth, timeout time.Duration) error {
 	t0 := time.Now()
 	log.Debug("hatchery> swarm> pullImage> pulling image %s on %s", img, dockerClient.name)
 
@@ -21,6 +56,11 @@ func (h *HatcherySwarm) pullImage(dockerClient *dockerClient, img string, timeou
 
 	//Pull the worker image
 	opts := types.ImageCreateOptions{}
+	encodedAuth, err := registryAuth(img, auth)
+	if err != nil {
+		return err
+	}
+	opts.RegistryAuth = encodedAuth
 	res, err := dockerClient.ImageCreate(ctx, img, opts)
 	if err != nil {
 		log.Warning("hatchery> swarm> pullImage> Unable to pull image %s on %s: %s", img, dockerClient.name, err)
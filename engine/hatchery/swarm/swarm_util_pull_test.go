@@ -0,0 +1,40 @@
+package swarm
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+
+	types "github.com/docker/docker/api/types"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func TestRegistryAuth(t *testing.T) {
+	assert.Equal(t, "registry.example.com", registryHost("https://registry.example.com/my-namespace"))
+	assert.Equal(t, "docker.io", imageRegistryHost("golang:1.11"))
+	assert.Equal(t, "docker.io", imageRegistryHost("library/golang:1.11"))
+	assert.Equal(t, "localhost:5000", imageRegistryHost("localhost:5000/worker"))
+
+	auth := &sdk.ModelDockerRegistryAuth{URL: "registry.example.com/my-namespace", Username: "user", Password: "pass"}
+
+	s, err := registryAuth("registry.example.com/my-namespace/worker:latest", auth)
+	assert.NoError(t, err)
+	btes, err := base64.URLEncoding.DecodeString(s)
+	assert.NoError(t, err)
+	var config types.AuthConfig
+	assert.NoError(t, json.Unmarshal(btes, &config))
+	assert.Equal(t, "user", config.Username)
+	assert.Equal(t, "pass", config.Password)
+	assert.Equal(t, "registry.example.com", config.ServerAddress)
+
+	// The credentials are never sent to another registry
+	s, err = registryAuth("golang:1.11", auth)
+	assert.NoError(t, err)
+	assert.Equal(t, "", s)
+
+	s, err = registryAuth("registry.example.com/worker", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "", s)
+}
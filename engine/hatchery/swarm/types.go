@@ -31,6 +31,22 @@ type HatcheryConfiguration struct {
 	NetworkEnableIPv6 bool `mapstructure:"networkEnableIPv6" toml:"networkEnableIPv6" default:"false" commented:"false" comment:"if true: hatchery creates private network between services with ipv6 enabled" json:"networkEnableIPv6"`
 
 	DockerEngines map[string]DockerEngineConfiguration `mapstructure:"dockerEngines" toml:"dockerEngines" comment:"List of Docker Engines" json:"dockerEngines,omitempty"`
+
+	// CacheVolumes persistent volumes attached to the workers for the docker layers cache
+	CacheVolumes CacheVolumesConfiguration `mapstructure:"cacheVolumes" toml:"cacheVolumes" comment:"Persistent cache volumes attached to the workers, for the docker layers cache" json:"cacheVolumes"`
+}
+
+// CacheVolumesConfiguration is the configuration of the cache volumes of the workers
+type CacheVolumesConfiguration struct {
+	Enabled            bool   `mapstructure:"enabled" toml:"enabled" default:"false" commented:"false" comment:"Attach a cache volume to the workers" json:"enabled"`
+	Models             string `mapstructure:"models" toml:"models" default:"" commented:"true" comment:"Worker models using a cache volume, all if empty. \n Format: model1,model2" json:"models,omitempty"`
+	Type               string `mapstructure:"type" toml:"type" default:"volume" commented:"false" comment:"volume: docker named volumes, bind: directories of hostPath, the hatchery has to run on the docker host" json:"type"`
+	HostPath           string `mapstructure:"hostPath" toml:"hostPath" default:"" commented:"true" comment:"Directory of the cache volumes of type bind" json:"hostPath,omitempty"`
+	Destination        string `mapstructure:"destination" toml:"destination" default:"/var/lib/docker" commented:"false" comment:"Mount point of the cache volume in the worker" json:"destination"`
+	MaxVolumesPerModel int    `mapstructure:"maxVolumesPerModel" toml:"maxVolumesPerModel" default:"2" commented:"false" comment:"Max cache volumes of a worker model, a volume is used by one worker at a time" json:"maxVolumesPerModel"`
+	MaxSize            int64  `mapstructure:"maxSize" toml:"maxSize" default:"20480" commented:"false" comment:"Max size of a cache volume in Mo, a bigger volume is removed when unused" json:"maxSize"`
+	MaxTotalSize       int64  `mapstructure:"maxTotalSize" toml:"maxTotalSize" default:"102400" commented:"false" comment:"Max size of all the cache volumes of a docker engine in Mo, the least recently used volumes are removed above" json:"maxTotalSize"`
+	MaxUnusedTime      int    `mapstructure:"maxUnusedTime" toml:"maxUnusedTime" default:"72" commented:"false" comment:"Cache volumes unused since more than maxUnusedTime hours are removed" json:"maxUnusedTime"`
 }
 
 // HatcherySwarm is a hatchery which can be connected to a remote to a docker remote api
@@ -39,19 +55,24 @@ type HatcherySwarm struct {
 	Config        HatcheryConfiguration
 	hatch         *sdk.Hatchery
 	dockerClients map[string]*dockerClient
+	cache         cacheVolumes
 }
 
 type dockerClient struct {
 	docker.Client
 	MaxContainers int
 	name          string
+	// podman is true if the docker api is served by podman
+	podman           bool
+	rootless         bool
+	noResourceLimits bool
 }
 
 // DockerEngineConfiguration is a configuration to be able to connect to a docker engine
 type DockerEngineConfiguration struct {
-	Host                  string `mapstructure:"host" toml:"host" comment:"DOCKER_HOST" json:"host"`                                                                        // DOCKER_HOST
-	CertPath              string `mapstructure:"certPath" toml:"certPath" comment:"DOCKER_CERT_PATH" json:"-"`                                                              // DOCKER_CERT_PATH
-	InsecureSkipTLSVerify bool   `mapstructure:"insecureSkipTLSVerify" toml:"insecureSkipTLSVerify" comment:"DOCKER_INSECURE_SKIP_TLS_VERIFY" json:"insecureSkipTLSVerify"` // !DOCKER_TLS_VERIFY
+	Host                  string `mapstructure:"host" toml:"host" comment:"DOCKER_HOST, can be the socket of a rootless podman: unix:///run/user/1000/podman/podman.sock" json:"host"` // DOCKER_HOST
+	CertPath              string `mapstructure:"certPath" toml:"certPath" comment:"DOCKER_CERT_PATH" json:"-"`                                                                         // DOCKER_CERT_PATH
+	InsecureSkipTLSVerify bool   `mapstructure:"insecureSkipTLSVerify" toml:"insecureSkipTLSVerify" comment:"DOCKER_INSECURE_SKIP_TLS_VERIFY" json:"insecureSkipTLSVerify"`            // !DOCKER_TLS_VERIFY
 	TLSCAPEM              string `mapstructure:"TLSCAPEM" toml:"TLSCAPEM" comment:"content of your ca.pem" json:"-"`
 	TLSCERTPEM            string `mapstructure:"TLSCERTPEM" toml:"TLSCERTPEM" comment:"content of your cert.pem" json:"-"`
 	TLSKEYPEM             string `mapstructure:"TLSKEYPEM" toml:"TLSKEYPEM" comment:"content of your key.pem" json:"-"`
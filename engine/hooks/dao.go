@@ -2,6 +2,7 @@ package hooks
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/ovh/cds/engine/api/cache"
 	"github.com/ovh/cds/sdk"
@@ -103,3 +104,18 @@ func (d *dao) FindAllTaskExecutionsForTasks(ts ...sdk.Task) ([]sdk.TaskExecution
 
 	return tes, nil
 }
+
+func (d *dao) SaveWebHookReports(reports []sdk.RepositoryWebHookReport) {
+	d.store.Set(webHookReportsKey, reports)
+}
+
+func (d *dao) FindWebHookReports() []sdk.RepositoryWebHookReport {
+	var reports []sdk.RepositoryWebHookReport
+	d.store.Get(webHookReportsKey, &reports)
+	return reports
+}
+
+// LockWebHookRepair returns true if no other instance of the service verified the webhooks during the given delay
+func (d *dao) LockWebHookRepair(delay time.Duration) bool {
+	return d.store.Lock(webHookRepairKey, delay, 0, 1)
+}
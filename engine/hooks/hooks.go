@@ -95,6 +95,15 @@ func (s *Service) Serve(c context.Context) error {
 				cancel()
 			}
 		}()
+
+		//Verify the repository webhooks on the VCS servers
+		if s.Cfg.RepairDelay > 0 {
+			go func() {
+				if err := s.repairWebHooksRoutine(ctx); err != nil && err != context.Canceled {
+					log.Error("%v", err)
+				}
+			}()
+		}
 	}
 
 	//Init the http server
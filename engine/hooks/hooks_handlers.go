@@ -12,9 +12,11 @@ import (
 	"github.com/gorilla/mux"
 
 	"github.com/ovh/cds/engine/api"
+	"github.com/ovh/cds/engine/api/observability"
 	"github.com/ovh/cds/engine/service"
 	"github.com/ovh/cds/sdk"
 	"github.com/ovh/cds/sdk/log"
+	"github.com/ovh/cds/sdk/tracingutils"
 )
 
 func (s *Service) webhookHandler() service.Handler {
@@ -45,6 +47,13 @@ func (s *Service) webhookHandler() service.Handler {
 			return sdk.WrapError(err, "Unable to read request")
 		}
 
+		//Check the signature of the repository webhooks
+		if webHook.Type == TypeRepoManagerWebHook {
+			if err := checkRepositoryWebHookSignature(webHook.Config[sdk.RepositoryWebHookSecret].Value, r.Header, req); err != nil {
+				return sdk.WrapError(err, "webhook %s", uuid)
+			}
+		}
+
 		//Prepare a web hook execution
 		exec := &sdk.TaskExecution{
 			Timestamp: time.Now().UnixNano(),
@@ -57,6 +66,9 @@ func (s *Service) webhookHandler() service.Handler {
 				RequestURL:    r.URL.RawQuery,
 			},
 		}
+		if span := observability.Current(ctx); span != nil {
+			exec.TraceParent = tracingutils.FormatTraceParent(span.SpanContext())
+		}
 
 		//Save the web hook execution
 		s.Dao.SaveTaskExecution(exec)
@@ -87,6 +99,29 @@ func (s *Service) stopTasksHandler() service.Handler {
 	}
 }
 
+func (s *Service) getWebHookReportsHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		status := r.FormValue("status")
+		reports := []sdk.RepositoryWebHookReport{}
+		for _, report := range s.Dao.FindWebHookReports() {
+			if status == "" || report.Status == status {
+				reports = append(reports, report)
+			}
+		}
+		return service.WriteJSON(w, reports, http.StatusOK)
+	}
+}
+
+func (s *Service) postWebHookRepairHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		reports, err := s.repairWebHooks(ctx)
+		if err != nil {
+			return err
+		}
+		return service.WriteJSON(w, reports, http.StatusOK)
+	}
+}
+
 func (s *Service) startTaskHandler() service.Handler {
 	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
 		//Get the UUID of the task from the URL
@@ -478,6 +513,18 @@ func (s *Service) Status() sdk.MonitoringStatus {
 	}
 	m.Lines = append(m.Lines, sdk.MonitoringStatusLine{Component: "Queue", Value: fmt.Sprintf("%d", size), Status: status})
 
+	var nbWebHooksBroken int
+	for _, report := range s.Dao.FindWebHookReports() {
+		if report.Status == sdk.RepositoryWebHookStatusBroken {
+			nbWebHooksBroken++
+		}
+	}
+	statusWebHooks := sdk.MonitoringStatusOK
+	if nbWebHooksBroken > 0 {
+		statusWebHooks = sdk.MonitoringStatusWarn
+	}
+	m.Lines = append(m.Lines, sdk.MonitoringStatusLine{Component: "WebHooks Broken", Value: fmt.Sprintf("%d", nbWebHooksBroken), Status: statusWebHooks})
+
 	var nbHooksKafkaTotal int64
 
 	tasks, err := s.Dao.FindAllTasks()
@@ -4,6 +4,8 @@ import (
 	"context"
 
 	"github.com/ovh/cds/engine/api"
+	"github.com/ovh/cds/engine/api/observability"
+	"github.com/ovh/cds/sdk/log"
 )
 
 func (s *Service) initRouter(ctx context.Context) {
@@ -15,8 +17,9 @@ func (s *Service) initRouter(ctx context.Context) {
 
 	r.Handle("/mon/version", r.GET(api.VersionHandler, api.Auth(false)))
 	r.Handle("/mon/status", r.GET(s.statusHandler, api.Auth(false)))
+	r.Handle("/mon/metrics", r.GET(observability.StatsHandler, api.Auth(false)))
 
-	r.Handle("/webhook/{uuid}", r.POST(s.webhookHandler, api.Auth(false)), r.GET(s.webhookHandler, api.Auth(false)), r.DELETE(s.webhookHandler, api.Auth(false)), r.PUT(s.webhookHandler, api.Auth(false)))
+	r.Handle("/webhook/{uuid}", r.POST(s.webhookHandler, api.Auth(false), api.EnableTracing()), r.GET(s.webhookHandler, api.Auth(false), api.EnableTracing()), r.DELETE(s.webhookHandler, api.Auth(false), api.EnableTracing()), r.PUT(s.webhookHandler, api.Auth(false), api.EnableTracing()))
 	r.Handle("/task", r.POST(s.postTaskHandler), r.GET(s.getTasksHandler))
 	r.Handle("/task/bulk/start", r.GET(s.startTasksHandler))
 	r.Handle("/task/bulk/stop", r.GET(s.stopTasksHandler))
@@ -28,5 +31,9 @@ func (s *Service) initRouter(ctx context.Context) {
 	r.Handle("/task/{uuid}/execution", r.GET(s.getTaskExecutionsHandler), r.DELETE(s.deleteAllTaskExecutionsHandler))
 	r.Handle("/task/{uuid}/execution/{timestamp}", r.GET(s.getTaskExecutionHandler))
 	r.Handle("/task/{uuid}/execution/{timestamp}/stop", r.POST(s.postStopTaskExecutionHandler))
+	r.Handle("/admin/webhook/repair", r.GET(s.getWebHookReportsHandler), r.POST(s.postWebHookRepairHandler))
 
+	if err := r.InitMetrics("cds-hooks", s.Cfg.Name); err != nil {
+		log.Error("unable to init router metrics: %v", err)
+	}
 }
@@ -224,7 +224,7 @@ func (s *Service) doOutgoingWorkflowExecution(t *sdk.TaskExecution) error {
 	evt.ParentWorkflow.Run = runNumber
 	evt.ParentWorkflow.HookRunID = hookRunID
 
-	targetRun, err := s.Client.WorkflowRunFromHook(targetProject, targetWorkflow, evt)
+	targetRun, err := s.Client.WorkflowRunFromHook(context.Background(), targetProject, targetWorkflow, evt)
 	if err != nil {
 		return sdk.WrapError(handleError(err), "Unable to run workflow from hook")
 	}
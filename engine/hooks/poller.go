@@ -13,6 +13,11 @@ import (
 	"github.com/ovh/cds/sdk/log"
 )
 
+// isForkPullRequest returns true if the head of a pull request is on another repository than its base
+func isForkPullRequest(e sdk.VCSPullRequestEvent) bool {
+	return e.Head.Repo != "" && e.Base.Repo != "" && e.Head.Repo != e.Base.Repo
+}
+
 func fillPayload(pushEvent sdk.VCSPushEvent) map[string]string {
 	payload := make(map[string]string)
 	payload["git.author"] = pushEvent.Commit.Author.Name
@@ -102,6 +107,8 @@ func (s *Service) doPollerTaskExecution(task *sdk.Task, taskExec *sdk.TaskExecut
 
 		for _, pullRequestEvent := range events.PullRequestEvents {
 			payload := fillPayload(pullRequestEvent.Head)
+			payload[sdk.PayloadPullRequestBaseRepository] = pullRequestEvent.Base.Repo
+			payload[sdk.PayloadPullRequestFork] = strconv.FormatBool(isForkPullRequest(pullRequestEvent))
 			hookEvents[i] = sdk.WorkflowNodeRunHookEvent{
 				WorkflowNodeHookUUID: task.UUID,
 				Payload:              sdk.ParametersMapMerge(payloadValues, payload),
@@ -11,6 +11,7 @@ import (
 	"github.com/ovh/cds/engine/api/cache"
 	"github.com/ovh/cds/sdk"
 	"github.com/ovh/cds/sdk/log"
+	"github.com/ovh/cds/sdk/tracingutils"
 )
 
 //This are all the types
@@ -39,6 +40,8 @@ var (
 	rootKey           = cache.Key("hooks", "tasks")
 	executionRootKey  = cache.Key("hooks", "tasks", "executions")
 	schedulerQueueKey = cache.Key("hooks", "scheduler", "queue")
+	webHookReportsKey = cache.Key("hooks", "webhooks", "reports")
+	webHookRepairKey  = cache.Key("hooks", "webhooks", "repair")
 )
 
 // runTasks should run as a long-running goroutine
@@ -163,6 +166,13 @@ func (s *Service) hookToTask(h *sdk.WorkflowNodeHook) (*sdk.Task, error) {
 			UUID: h.UUID,
 			Type: TypeWorkflowHook,
 		}, nil
+	case sdk.UpstreamWorkflowModelName:
+		// Upstream workflow hooks are triggered by the API on node run success, the task is only kept for display
+		return &sdk.Task{
+			UUID:   h.UUID,
+			Type:   TypeWorkflowHook,
+			Config: h.Config,
+		}, nil
 	}
 
 	return nil, fmt.Errorf("Unsupported hook: %s", h.WorkflowHookModel.Name)
@@ -362,8 +372,12 @@ func (s *Service) doTask(ctx context.Context, t *sdk.Task, e *sdk.TaskExecution)
 	confProj := t.Config[sdk.HookConfigProject]
 	confWorkflow := t.Config[sdk.HookConfigWorkflow]
 	var globalErr error
+	// Continue the trace of the webhook, if any
+	if sc, ok := tracingutils.ParseTraceParent(e.TraceParent); ok {
+		ctx = tracingutils.SpanContextToContext(ctx, sc)
+	}
 	for _, hEvent := range hs {
-		run, err := s.Client.WorkflowRunFromHook(confProj.Value, confWorkflow.Value, hEvent)
+		run, err := s.Client.WorkflowRunFromHook(ctx, confProj.Value, confWorkflow.Value, hEvent)
 		if err != nil {
 			globalErr = err
 			log.Error("Hooks> Unable to run workflow %s", err)
@@ -56,6 +56,7 @@ func Test_doWebHookExecutionGithub(t *testing.T) {
 	assert.Equal(t, "baxterthehacker", hs[0].Payload["git.author"])
 	assert.Equal(t, "Update README.md", hs[0].Payload["git.message"])
 	assert.Equal(t, "0d1a26e67d8f5eaf1f6ba5c57fc3c7d91ac0fd1c", hs[0].Payload["git.hash"])
+	assert.Equal(t, "README.md", hs[0].Payload["git.changed_files"])
 }
 
 func Test_doWebHookExecutionTagGithub(t *testing.T) {
@@ -105,6 +106,9 @@ func Test_doWebHookExecutionGitlab(t *testing.T) {
 	assert.Equal(t, "jsmith", hs[0].Payload["git.author"])
 	assert.Equal(t, "Update Catalan translation to e38cb41.", hs[0].Payload["git.message"])
 	assert.Equal(t, "da1560886d4f094c3e6c9ef40349f7d38b5d27d7", hs[0].Payload["git.hash"])
+	// commits list is truncated by gitlab, changed files have to be resolved by the API
+	_, has := hs[0].Payload["git.changed_files"]
+	assert.False(t, has)
 }
 
 func Test_doWebHookExecutionBitbucket(t *testing.T) {
@@ -156,6 +160,103 @@ func Test_doWebHookExecutionBitbucketMultiple(t *testing.T) {
 	assert.Equal(t, "9f4fac7ec5642099982a86f584f2c4a362adb670", hs[0].Payload["git.hash"])
 }
 
+func Test_doWebHookExecutionGithubComment(t *testing.T) {
+	log.SetLogger(t)
+	s := Service{}
+	task := &sdk.TaskExecution{
+		UUID: sdk.RandomString(10),
+		Type: TypeRepoManagerWebHook,
+		Config: sdk.WorkflowNodeHookConfig{
+			sdk.RepositoryWebHookSecret: {Value: "secret"},
+		},
+		WebHook: &sdk.WebHookExecution{
+			RequestBody: []byte(githubIssueCommentEvent),
+			RequestHeader: map[string][]string{
+				GithubHeader: {"issue_comment"},
+			},
+		},
+	}
+	hs, err := s.doWebHookExecution(task)
+	test.NoError(t, err)
+
+	assert.Equal(t, 1, len(hs))
+	assert.Equal(t, sdk.CommentCommandRun, hs[0].Payload[sdk.PayloadCommentCommand])
+	assert.Equal(t, "baxterthehacker", hs[0].Payload[sdk.PayloadCommentAuthor])
+	assert.Equal(t, "2", hs[0].Payload[sdk.PayloadPullRequestID])
+	assert.Equal(t, "staging", hs[0].Payload["env"])
+	assert.Equal(t, "", hs[0].Payload["git.branch"])
+}
+
+func Test_doWebHookExecutionGitlabComment(t *testing.T) {
+	log.SetLogger(t)
+	s := Service{}
+	task := &sdk.TaskExecution{
+		UUID: sdk.RandomString(10),
+		Type: TypeRepoManagerWebHook,
+		Config: sdk.WorkflowNodeHookConfig{
+			sdk.RepositoryWebHookSecret: {Value: "secret"},
+		},
+		WebHook: &sdk.WebHookExecution{
+			RequestBody: []byte(gitlabNoteEvent),
+			RequestHeader: map[string][]string{
+				GitlabHeader: {"Note Hook"},
+			},
+		},
+	}
+	hs, err := s.doWebHookExecution(task)
+	test.NoError(t, err)
+
+	assert.Equal(t, 1, len(hs))
+	assert.Equal(t, sdk.CommentCommandRetry, hs[0].Payload[sdk.PayloadCommentCommand])
+	assert.Equal(t, "build", hs[0].Payload[sdk.PayloadCommentNode])
+	assert.Equal(t, "ms-viewport", hs[0].Payload["git.branch"])
+	assert.Equal(t, "jsmith/gitlab-test", hs[0].Payload["git.repository"])
+	assert.Equal(t, "true", hs[0].Payload[sdk.PayloadPullRequestFork])
+}
+
+func Test_doWebHookExecutionBitbucketComment(t *testing.T) {
+	log.SetLogger(t)
+	s := Service{}
+	task := &sdk.TaskExecution{
+		UUID: sdk.RandomString(10),
+		Type: TypeRepoManagerWebHook,
+		Config: sdk.WorkflowNodeHookConfig{
+			sdk.RepositoryWebHookSecret: {Value: "secret"},
+		},
+		WebHook: &sdk.WebHookExecution{
+			RequestBody: []byte(bitbucketPullRequestCommentEvent),
+			RequestHeader: map[string][]string{
+				BitbucketHeader: {"pr:comment:added"},
+			},
+		},
+	}
+
+	// a comment without command is ignored
+	hs, err := s.doWebHookExecution(task)
+	test.NoError(t, err)
+	assert.Equal(t, 0, len(hs))
+}
+
+func Test_doWebHookExecutionUnsignedComment(t *testing.T) {
+	log.SetLogger(t)
+	s := Service{}
+	task := &sdk.TaskExecution{
+		UUID: sdk.RandomString(10),
+		Type: TypeRepoManagerWebHook,
+		WebHook: &sdk.WebHookExecution{
+			RequestBody: []byte(githubIssueCommentEvent),
+			RequestHeader: map[string][]string{
+				GithubHeader: {"issue_comment"},
+			},
+		},
+	}
+
+	// the comments of the webhooks created without secret are rejected
+	hs, err := s.doWebHookExecution(task)
+	assert.Error(t, err)
+	assert.Equal(t, 0, len(hs))
+}
+
 var bitbucketPushEvent = `
 	{
     "eventKey": "repo:refs_changed",
@@ -677,3 +778,81 @@ var githubTagEvent = `
   }
 }
 `
+
+var githubIssueCommentEvent = `
+{
+  "action": "created",
+  "issue": {
+    "number": 2,
+    "pull_request": {
+      "url": "https://api.github.com/repos/baxterthehacker/public-repo/pulls/2"
+    }
+  },
+  "comment": {
+    "body": "Tests are fixed\r\n/cds run env=staging",
+    "user": {
+      "login": "baxterthehacker"
+    }
+  },
+  "repository": {
+    "full_name": "baxterthehacker/public-repo"
+  }
+}
+`
+
+var gitlabNoteEvent = `
+{
+  "object_kind": "note",
+  "user": {
+    "name": "Administrator",
+    "username": "root",
+    "email": "admin@example.com"
+  },
+  "object_attributes": {
+    "note": "/cds retry build",
+    "noteable_type": "MergeRequest"
+  },
+  "merge_request": {
+    "iid": 1,
+    "source_branch": "ms-viewport",
+    "source": {
+      "path_with_namespace": "jsmith/gitlab-test"
+    },
+    "target": {
+      "path_with_namespace": "gitlabhq/gitlab-test"
+    },
+    "last_commit": {
+      "id": "562e173be03b8ff2efb05345d12df18815438a4b"
+    }
+  }
+}
+`
+
+var bitbucketPullRequestCommentEvent = `
+{
+  "eventKey": "pr:comment:added",
+  "actor": {
+    "name": "admin",
+    "emailAddress": "admin@example.com",
+    "displayName": "Administrator"
+  },
+  "pullRequest": {
+    "id": 1,
+    "fromRef": {
+      "id": "refs/heads/feature",
+      "displayId": "feature",
+      "latestCommit": "178864a7d521b6f5e720b386b2c2b0ef8563e0dc",
+      "repository": {"slug": "repository", "project": {"key": "PROJ"}}
+    },
+    "toRef": {
+      "id": "refs/heads/master",
+      "displayId": "master",
+      "latestCommit": "7549846524f8aed2bd1c0249993ae1bf9d3c9998",
+      "repository": {"slug": "repository", "project": {"key": "PROJ"}}
+    }
+  },
+  "comment": {
+    "text": "I am a PR comment"
+  }
+}
+`
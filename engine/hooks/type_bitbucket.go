@@ -42,3 +42,39 @@ type BitbucketPushEvent struct {
 		Type     string `json:"type"`
 	} `json:"changes"`
 }
+
+// BitbucketRef represents a reference of a pull request in a payload send by bitbucket
+type BitbucketRef struct {
+	ID           string `json:"id"`
+	DisplayID    string `json:"displayId"`
+	LatestCommit string `json:"latestCommit"`
+	Repository   struct {
+		Slug    string `json:"slug"`
+		Project struct {
+			Key string `json:"key"`
+		} `json:"project"`
+	} `json:"repository"`
+}
+
+// FullName returns the name of the repository of the reference
+func (r BitbucketRef) FullName() string {
+	return r.Repository.Project.Key + "/" + r.Repository.Slug
+}
+
+// BitbucketPullRequestCommentEvent represents payload send by bitbucket on a comment of a pull request
+type BitbucketPullRequestCommentEvent struct {
+	EventKey string `json:"eventKey"`
+	Actor    struct {
+		Name         string `json:"name"`
+		EmailAddress string `json:"emailAddress"`
+		DisplayName  string `json:"displayName"`
+	} `json:"actor"`
+	PullRequest struct {
+		ID      int          `json:"id"`
+		FromRef BitbucketRef `json:"fromRef"`
+		ToRef   BitbucketRef `json:"toRef"`
+	} `json:"pullRequest"`
+	Comment struct {
+		Text string `json:"text"`
+	} `json:"comment"`
+}
@@ -32,9 +32,9 @@ type GithubPushEvent struct {
 			Email    string `json:"email"`
 			Username string `json:"username"`
 		} `json:"committer"`
-		Added    []interface{} `json:"added"`
-		Removed  []interface{} `json:"removed"`
-		Modified []string      `json:"modified"`
+		Added    []string `json:"added"`
+		Removed  []string `json:"removed"`
+		Modified []string `json:"modified"`
 	} `json:"commits"`
 	HeadCommit struct {
 		ID        string `json:"id"`
@@ -53,9 +53,9 @@ type GithubPushEvent struct {
 			Email    string `json:"email"`
 			Username string `json:"username"`
 		} `json:"committer"`
-		Added    []interface{} `json:"added"`
-		Removed  []interface{} `json:"removed"`
-		Modified []string      `json:"modified"`
+		Added    []string `json:"added"`
+		Removed  []string `json:"removed"`
+		Modified []string `json:"modified"`
 	} `json:"head_commit"`
 	Repository struct {
 		ID       int    `json:"id"`
@@ -174,3 +174,39 @@ func (g *GithubPushEvent) GetCommits() []sdk.VCSCommit {
 	}
 	return commits
 }
+
+// githubMaxPushCommits is the maximum number of commits sent by Github in a push event
+const githubMaxPushCommits = 20
+
+// GetChangedFiles returns the files modified by the commits of the push event.
+// The second returned value is false if Github truncated the list of commits.
+func (g *GithubPushEvent) GetChangedFiles() ([]string, bool) {
+	if len(g.Commits) >= githubMaxPushCommits {
+		return nil, false
+	}
+	var files [][]string
+	for _, c := range g.Commits {
+		files = append(files, c.Added, c.Removed, c.Modified)
+	}
+	return mergeChangedFiles(files...), true
+}
+
+// GithubIssueCommentEvent represents payload send by github on a comment of an issue or a pull request
+type GithubIssueCommentEvent struct {
+	Action string `json:"action"`
+	Issue  struct {
+		Number      int `json:"number"`
+		PullRequest *struct {
+			URL string `json:"url"`
+		} `json:"pull_request"`
+	} `json:"issue"`
+	Comment struct {
+		Body string `json:"body"`
+		User struct {
+			Login string `json:"login"`
+		} `json:"user"`
+	} `json:"comment"`
+	Repository struct {
+		FullName string `json:"full_name"`
+	} `json:"repository"`
+}
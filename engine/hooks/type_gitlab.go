@@ -53,9 +53,9 @@ type GitlabPushEvent struct {
 			Name  string `json:"name"`
 			Email string `json:"email"`
 		} `json:"author"`
-		Added    []string      `json:"added"`
-		Modified []string      `json:"modified"`
-		Removed  []interface{} `json:"removed"`
+		Added    []string `json:"added"`
+		Modified []string `json:"modified"`
+		Removed  []string `json:"removed"`
 	} `json:"commits"`
 	TotalCommitsCount int `json:"total_commits_count"`
 }
@@ -78,3 +78,43 @@ func (g *GitlabPushEvent) GetCommits() []sdk.VCSCommit {
 	}
 	return commits
 }
+
+// GetChangedFiles returns the files modified by the commits of the push event.
+// The second returned value is false if Gitlab truncated the list of commits.
+func (g *GitlabPushEvent) GetChangedFiles() ([]string, bool) {
+	if g.TotalCommitsCount > len(g.Commits) {
+		return nil, false
+	}
+	var files [][]string
+	for _, c := range g.Commits {
+		files = append(files, c.Added, c.Removed, c.Modified)
+	}
+	return mergeChangedFiles(files...), true
+}
+
+// GitlabNoteEvent represents payload send by gitlab on a comment
+type GitlabNoteEvent struct {
+	ObjectKind string `json:"object_kind"`
+	User       struct {
+		Name     string `json:"name"`
+		Username string `json:"username"`
+		Email    string `json:"email"`
+	} `json:"user"`
+	ObjectAttributes struct {
+		Note         string `json:"note"`
+		NoteableType string `json:"noteable_type"`
+	} `json:"object_attributes"`
+	MergeRequest *struct {
+		IID          int    `json:"iid"`
+		SourceBranch string `json:"source_branch"`
+		Source       struct {
+			PathWithNamespace string `json:"path_with_namespace"`
+		} `json:"source"`
+		Target struct {
+			PathWithNamespace string `json:"path_with_namespace"`
+		} `json:"target"`
+		LastCommit struct {
+			ID string `json:"id"`
+		} `json:"last_commit"`
+	} `json:"merge_request"`
+}
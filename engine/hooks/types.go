@@ -35,11 +35,12 @@ type Configuration struct {
 	RetryError       int64                           `toml:"retryError" default:"3" comment:"Retry execution while this number of error is not reached" json:"retryError"`
 	ExecutionHistory int                             `toml:"executionHistory" default:"10" comment:"Number of execution to keep" json:"executionHistory"`
 	Disable          bool                            `toml:"disable" default:"false" comment:"Disable all hooks executions" json:"disable"`
+	RepairDelay      int64                           `toml:"repairDelay" default:"3600" comment:"Delay in seconds between two verifications of the repository webhooks on the VCS servers, the missing or disabled webhooks are recreated. 0 to disable" json:"repairDelay"`
 	API              service.APIServiceConfiguration `toml:"api" comment:"######################\n CDS API Settings \n######################" json:"api"`
 	Cache            struct {
 		TTL   int `toml:"ttl" default:"60" json:"ttl"`
 		Redis struct {
-			Host     string `toml:"host" default:"localhost:6379" comment:"If your want to use a redis-sentinel based cluster, follow this syntax! <clustername>@sentinel1:26379,sentinel2:26379,sentinel3:26379" json:"host"`
+			Host     string `toml:"host" default:"localhost:6379" comment:"If your want to use a redis-sentinel based cluster, follow this syntax! <clustername>@sentinel1:26379,sentinel2:26379,sentinel3:26379\nIf your want to use a redis cluster, list its nodes: node1:7000,node2:7000,node3:7000" json:"host"`
 			Password string `toml:"password" json:"-"`
 		} `toml:"redis" comment:"Connect CDS to a redis cache If you more than one CDS instance and to avoid losing data at startup" json:"redis"`
 	} `toml:"cache" comment:"######################\n CDS Hooks Cache Settings \n######################" json:"cache"`
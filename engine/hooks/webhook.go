@@ -7,6 +7,7 @@ import (
 	"mime"
 	"net/http"
 	"net/url"
+	"sort"
 	"strings"
 
 	dump "github.com/fsamin/go-dump"
@@ -18,6 +19,13 @@ func (s *Service) doWebHookExecution(e *sdk.TaskExecution) ([]sdk.WorkflowNodeRu
 	log.Debug("Hooks> Processing webhook %s %s", e.UUID, e.Type)
 
 	if e.Type == TypeRepoManagerWebHook {
+		if header := getRepositoryCommentHeader(e.WebHook); header != "" {
+			// the signature of the comments was checked on reception, the webhooks without secret are not signed
+			if e.Config[sdk.RepositoryWebHookSecret].Value == "" {
+				return nil, sdk.NewErrorFrom(sdk.ErrUnauthorized, "the comments are only accepted from signed webhooks")
+			}
+			return executeRepositoryCommentWebHook(e, header)
+		}
 		return executeRepositoryWebHook(e)
 	}
 	event, err := executeWebHook(e)
@@ -70,6 +78,9 @@ func executeRepositoryWebHook(t *sdk.TaskExecution) ([]sdk.WorkflowNodeRunHookEv
 		if len(pushEvent.Commits) > 0 {
 			payload["git.message"] = pushEvent.Commits[0].Message
 		}
+		if files, ok := pushEvent.GetChangedFiles(); ok {
+			payload["git.changed_files"] = sdk.JoinChangedFiles(files)
+		}
 		payloadStr, err := json.Marshal(pushEvent)
 		if err != nil {
 			log.Error("Unable to marshal payload: %v", err)
@@ -104,6 +115,9 @@ func executeRepositoryWebHook(t *sdk.TaskExecution) ([]sdk.WorkflowNodeRunHookEv
 		if len(pushEvent.Commits) > 0 {
 			payload["git.message"] = pushEvent.Commits[0].Message
 		}
+		if files, ok := pushEvent.GetChangedFiles(); ok {
+			payload["git.changed_files"] = sdk.JoinChangedFiles(files)
+		}
 		payloadStr, err := json.Marshal(pushEvent)
 		if err != nil {
 			log.Error("Unable to marshal payload: %v", err)
@@ -263,6 +277,22 @@ func executeWebHook(t *sdk.TaskExecution) (*sdk.WorkflowNodeRunHookEvent, error)
 	return &h, nil
 }
 
+// mergeChangedFiles returns the sorted list of distinct files
+func mergeChangedFiles(lists ...[]string) []string {
+	m := map[string]struct{}{}
+	for _, l := range lists {
+		for _, f := range l {
+			m[f] = struct{}{}
+		}
+	}
+	files := make([]string, 0, len(m))
+	for f := range m {
+		files = append(files, f)
+	}
+	sort.Strings(files)
+	return files
+}
+
 func copyValues(dst, src url.Values) {
 	for k, vs := range src {
 		for _, value := range vs {
@@ -0,0 +1,99 @@
+package hooks
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func getRepositoryCommentHeader(whe *sdk.WebHookExecution) string {
+	if v, ok := whe.RequestHeader[GithubHeader]; ok && v[0] == "issue_comment" {
+		return GithubHeader
+	} else if v, ok := whe.RequestHeader[GitlabHeader]; ok && v[0] == "Note Hook" {
+		return GitlabHeader
+	} else if v, ok := whe.RequestHeader[BitbucketHeader]; ok && v[0] == "pr:comment:added" {
+		return BitbucketHeader
+	}
+	return ""
+}
+
+// executeRepositoryCommentWebHook reads the command written in a comment of a pull request.
+// The comments without command and the comments of the issues are ignored. The author of the comment is
+// sent to the API, which runs the command with the permissions of the CDS user linked to this author.
+func executeRepositoryCommentWebHook(t *sdk.TaskExecution, header string) ([]sdk.WorkflowNodeRunHookEvent, error) {
+	payload := map[string]string{}
+	var comment, headRepository string
+
+	switch header {
+	case GithubHeader:
+		var e GithubIssueCommentEvent
+		if err := json.Unmarshal(t.WebHook.RequestBody, &e); err != nil {
+			return nil, sdk.WrapError(err, "unable ro read github request: %s", string(t.WebHook.RequestBody))
+		}
+		if e.Action != "created" || e.Issue.PullRequest == nil {
+			return nil, nil
+		}
+		comment = e.Comment.Body
+		payload[sdk.PayloadCommentAuthor] = e.Comment.User.Login
+		payload[sdk.PayloadPullRequestID] = strconv.Itoa(e.Issue.Number)
+		payload[sdk.PayloadPullRequestBaseRepository] = e.Repository.FullName
+		// the branch of the pull request is not sent by github, it is resolved by the API
+	case GitlabHeader:
+		var e GitlabNoteEvent
+		if err := json.Unmarshal(t.WebHook.RequestBody, &e); err != nil {
+			return nil, sdk.WrapError(err, "unable ro read gitlab request: %s", string(t.WebHook.RequestBody))
+		}
+		if e.ObjectAttributes.NoteableType != "MergeRequest" || e.MergeRequest == nil {
+			return nil, nil
+		}
+		comment = e.ObjectAttributes.Note
+		headRepository = e.MergeRequest.Source.PathWithNamespace
+		payload[sdk.PayloadCommentAuthor] = e.User.Username
+		payload[sdk.PayloadPullRequestID] = strconv.Itoa(e.MergeRequest.IID)
+		payload[sdk.PayloadPullRequestBaseRepository] = e.MergeRequest.Target.PathWithNamespace
+		payload["git.branch"] = e.MergeRequest.SourceBranch
+		payload["git.hash"] = e.MergeRequest.LastCommit.ID
+	case BitbucketHeader:
+		var e BitbucketPullRequestCommentEvent
+		if err := json.Unmarshal(t.WebHook.RequestBody, &e); err != nil {
+			return nil, sdk.WrapError(err, "unable ro read bitbucket request: %s", string(t.WebHook.RequestBody))
+		}
+		comment = e.Comment.Text
+		headRepository = e.PullRequest.FromRef.FullName()
+		payload[sdk.PayloadCommentAuthor] = e.Actor.Name
+		payload[sdk.PayloadPullRequestID] = strconv.Itoa(e.PullRequest.ID)
+		payload[sdk.PayloadPullRequestBaseRepository] = e.PullRequest.ToRef.FullName()
+		payload["git.branch"] = e.PullRequest.FromRef.DisplayID
+		payload["git.hash"] = e.PullRequest.FromRef.LatestCommit
+	default:
+		return nil, fmt.Errorf("Repository manager not found. Cannot read request body")
+	}
+
+	cmd, err := sdk.ParseCommentCommand(comment)
+	if err != nil {
+		return nil, fmt.Errorf("invalid command in the comment of %s on pull request %s: %v", payload[sdk.PayloadCommentAuthor], payload[sdk.PayloadPullRequestID], err)
+	}
+	if cmd == nil {
+		return nil, nil
+	}
+
+	for k, v := range cmd.Payload {
+		payload[k] = v
+	}
+	payload[sdk.PayloadCommentCommand] = cmd.Name
+	if cmd.Node != "" {
+		payload[sdk.PayloadCommentNode] = cmd.Node
+	}
+	if headRepository != "" {
+		payload["git.repository"] = headRepository
+		payload[sdk.PayloadPullRequestFork] = strconv.FormatBool(headRepository != payload[sdk.PayloadPullRequestBaseRepository])
+	}
+	payload["cds.triggered_by.username"] = payload[sdk.PayloadCommentAuthor]
+
+	return []sdk.WorkflowNodeRunHookEvent{{
+		WorkflowNodeHookUUID: t.UUID,
+		Payload:              payload,
+	}}, nil
+}
@@ -0,0 +1,75 @@
+package hooks
+
+import (
+	"context"
+	"time"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// Every RepairDelay seconds, the webhooks of the repository webhook tasks are verified on their VCS server
+func (s *Service) repairWebHooksRoutine(c context.Context) error {
+	delay := time.Duration(s.Cfg.RepairDelay) * time.Second
+	tick := time.NewTicker(delay)
+	defer tick.Stop()
+	for {
+		select {
+		case <-c.Done():
+			return c.Err()
+		case <-tick.C:
+			// only one instance of the service verifies the webhooks on each period
+			if !s.Dao.LockWebHookRepair(delay) {
+				continue
+			}
+			if _, err := s.repairWebHooks(c); err != nil {
+				log.Error("Hooks> repairWebHooksRoutine> %v", err)
+			}
+		}
+	}
+}
+
+// repairWebHooks asks the API to verify the webhook of each repository webhook task, the API recreates the missing or
+// disabled webhooks. The reports are saved for the admin route.
+func (s *Service) repairWebHooks(ctx context.Context) ([]sdk.RepositoryWebHookReport, error) {
+	tasks, err := s.Dao.FindAllTasks()
+	if err != nil {
+		return nil, sdk.WrapError(err, "unable to find all tasks")
+	}
+
+	reports := []sdk.RepositoryWebHookReport{}
+	for _, t := range tasks {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		if t.Type != TypeRepoManagerWebHook || t.Stopped {
+			continue
+		}
+
+		report, err := s.Client.HookRepair(t.UUID)
+		if err != nil {
+			report = sdk.RepositoryWebHookReport{
+				UUID:       t.UUID,
+				Project:    t.Config[sdk.HookConfigProject].Value,
+				Workflow:   t.Config[sdk.HookConfigWorkflow].Value,
+				VCSServer:  t.Config["vcsServer"].Value,
+				Repository: t.Config["repoFullName"].Value,
+				WebHookID:  t.Config["webHookID"].Value,
+				Status:     sdk.RepositoryWebHookStatusBroken,
+				Message:    err.Error(),
+				Date:       time.Now(),
+			}
+		}
+
+		switch report.Status {
+		case sdk.RepositoryWebHookStatusRepaired:
+			log.Info("Hooks> repairWebHooks> webhook of %s/%s on %s repaired", report.Project, report.Workflow, report.Repository)
+		case sdk.RepositoryWebHookStatusBroken:
+			log.Warning("Hooks> repairWebHooks> webhook of %s/%s on %s is broken: %s", report.Project, report.Workflow, report.Repository, report.Message)
+		}
+		reports = append(reports, report)
+	}
+
+	s.Dao.SaveWebHookReports(reports)
+	return reports, nil
+}
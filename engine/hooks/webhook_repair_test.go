@@ -0,0 +1,76 @@
+package hooks
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/engine/api/cache"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/cdsclient"
+	"github.com/ovh/cds/sdk/log"
+)
+
+type repairClient struct {
+	cdsclient.Interface
+	reports map[string]sdk.RepositoryWebHookReport
+	calls   []string
+}
+
+func (c *repairClient) HookRepair(uuid string) (sdk.RepositoryWebHookReport, error) {
+	c.calls = append(c.calls, uuid)
+	report, ok := c.reports[uuid]
+	if !ok {
+		return report, fmt.Errorf("api unavailable")
+	}
+	return report, nil
+}
+
+func Test_repairWebHooks(t *testing.T) {
+	log.SetLogger(t)
+	store := cache.NewLocalStore(60, 100)
+	client := &repairClient{reports: map[string]sdk.RepositoryWebHookReport{
+		"ok":       {UUID: "ok", Status: sdk.RepositoryWebHookStatusOK},
+		"repaired": {UUID: "repaired", Status: sdk.RepositoryWebHookStatusRepaired, WebHookID: "42"},
+	}}
+	s := Service{Dao: dao{store}}
+	s.Client = client
+
+	for _, task := range []sdk.Task{
+		{UUID: "ok", Type: TypeRepoManagerWebHook},
+		{UUID: "repaired", Type: TypeRepoManagerWebHook},
+		{UUID: "unavailable", Type: TypeRepoManagerWebHook, Config: sdk.WorkflowNodeHookConfig{
+			sdk.HookConfigProject: {Value: "PROJ"},
+			"repoFullName":        {Value: "ovh/cds"},
+		}},
+		{UUID: "stopped", Type: TypeRepoManagerWebHook, Stopped: true},
+		{UUID: "scheduler", Type: TypeScheduler},
+	} {
+		task := task
+		s.Dao.SaveTask(&task)
+	}
+
+	reports, err := s.repairWebHooks(context.Background())
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"ok", "repaired", "unavailable"}, client.calls)
+	assert.Len(t, reports, 3)
+
+	// the webhooks which could not be verified are reported as broken
+	saved := map[string]sdk.RepositoryWebHookReport{}
+	for _, r := range s.Dao.FindWebHookReports() {
+		saved[r.UUID] = r
+	}
+	assert.Equal(t, sdk.RepositoryWebHookStatusOK, saved["ok"].Status)
+	assert.Equal(t, "42", saved["repaired"].WebHookID)
+	assert.Equal(t, sdk.RepositoryWebHookStatusBroken, saved["unavailable"].Status)
+	assert.Equal(t, "PROJ", saved["unavailable"].Project)
+	assert.Equal(t, "ovh/cds", saved["unavailable"].Repository)
+	assert.Equal(t, "api unavailable", saved["unavailable"].Message)
+
+	// only one instance verifies the webhooks on each period
+	assert.True(t, s.Dao.LockWebHookRepair(time.Minute))
+	assert.False(t, s.Dao.LockWebHookRepair(time.Minute))
+}
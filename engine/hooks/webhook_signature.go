@@ -0,0 +1,71 @@
+package hooks
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"hash"
+	"net/http"
+	"strings"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// HTTP headers of the signatures of the repository webhooks
+const (
+	GithubSignatureHeader    = "X-Hub-Signature"
+	GithubSignature256Header = "X-Hub-Signature-256"
+	GitlabTokenHeader        = "X-Gitlab-Token"
+	BitbucketSignatureHeader = "X-Hub-Signature"
+)
+
+// checkRepositoryWebHookSignature verifies that the payload of a repository webhook was signed with the secret of the
+// task. The webhooks created without secret are still accepted, except for the comments of the pull requests which run
+// commands on behalf of their author.
+func checkRepositoryWebHookSignature(secret string, header http.Header, body []byte) error {
+	if secret == "" {
+		if isRepositoryCommentEvent(header) {
+			return sdk.NewErrorFrom(sdk.ErrUnauthorized, "the comments are only accepted from signed webhooks")
+		}
+		return nil
+	}
+
+	switch {
+	case header.Get(GithubHeader) != "":
+		if s := header.Get(GithubSignature256Header); s != "" {
+			return checkHMAC(sha256.New, "sha256=", secret, s, body)
+		}
+		return checkHMAC(sha1.New, "sha1=", secret, header.Get(GithubSignatureHeader), body)
+	case header.Get(GitlabHeader) != "":
+		if subtle.ConstantTimeCompare([]byte(header.Get(GitlabTokenHeader)), []byte(secret)) != 1 {
+			return sdk.NewErrorFrom(sdk.ErrUnauthorized, "invalid webhook token")
+		}
+		return nil
+	case header.Get(BitbucketHeader) != "":
+		return checkHMAC(sha256.New, "sha256=", secret, header.Get(BitbucketSignatureHeader), body)
+	}
+	return sdk.NewErrorFrom(sdk.ErrUnauthorized, "unsigned webhook")
+}
+
+func isRepositoryCommentEvent(header http.Header) bool {
+	return header.Get(GithubHeader) == "issue_comment" || header.Get(GitlabHeader) == "Note Hook" || header.Get(BitbucketHeader) == "pr:comment:added"
+}
+
+// checkHMAC verifies a signature formatted as the prefix followed by the hexadecimal HMAC of the body
+func checkHMAC(h func() hash.Hash, prefix, secret, signature string, body []byte) error {
+	if !strings.HasPrefix(signature, prefix) {
+		return sdk.NewErrorFrom(sdk.ErrUnauthorized, "unsigned webhook")
+	}
+	sig, err := hex.DecodeString(strings.TrimPrefix(signature, prefix))
+	if err != nil {
+		return sdk.NewErrorFrom(sdk.ErrUnauthorized, "invalid webhook signature")
+	}
+	mac := hmac.New(h, []byte(secret))
+	mac.Write(body) // nolint
+	if !hmac.Equal(sig, mac.Sum(nil)) {
+		return sdk.NewErrorFrom(sdk.ErrUnauthorized, "invalid webhook signature")
+	}
+	return nil
+}
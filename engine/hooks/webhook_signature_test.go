@@ -0,0 +1,54 @@
+package hooks
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"hash"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func sign(h func() hash.Hash, secret string, body []byte) string {
+	mac := hmac.New(h, []byte(secret))
+	mac.Write(body) // nolint
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func Test_checkRepositoryWebHookSignature(t *testing.T) {
+	body := []byte(`{"ref":"refs/heads/master"}`)
+
+	tests := []struct {
+		name    string
+		secret  string
+		header  http.Header
+		wantErr bool
+	}{
+		{"github sha256", "secret", http.Header{GithubHeader: {"push"}, GithubSignature256Header: {"sha256=" + sign(sha256.New, "secret", body)}}, false},
+		{"github sha1", "secret", http.Header{GithubHeader: {"push"}, GithubSignatureHeader: {"sha1=" + sign(sha1.New, "secret", body)}}, false},
+		{"github wrong secret", "secret", http.Header{GithubHeader: {"push"}, GithubSignature256Header: {"sha256=" + sign(sha256.New, "other", body)}}, true},
+		{"github unsigned", "secret", http.Header{GithubHeader: {"push"}}, true},
+		{"gitlab token", "secret", http.Header{GitlabHeader: {"Push Hook"}, GitlabTokenHeader: {"secret"}}, false},
+		{"gitlab wrong token", "secret", http.Header{GitlabHeader: {"Push Hook"}, GitlabTokenHeader: {"other"}}, true},
+		{"bitbucket sha256", "secret", http.Header{BitbucketHeader: {"repo:refs_changed"}, BitbucketSignatureHeader: {"sha256=" + sign(sha256.New, "secret", body)}}, false},
+		{"bitbucket unsigned", "secret", http.Header{BitbucketHeader: {"repo:refs_changed"}}, true},
+		{"unknown sender", "secret", http.Header{}, true},
+		{"legacy push without secret", "", http.Header{GithubHeader: {"push"}}, false},
+		{"legacy github comment without secret", "", http.Header{GithubHeader: {"issue_comment"}}, true},
+		{"legacy gitlab comment without secret", "", http.Header{GitlabHeader: {"Note Hook"}}, true},
+		{"legacy bitbucket comment without secret", "", http.Header{BitbucketHeader: {"pr:comment:added"}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkRepositoryWebHookSignature(tt.secret, tt.header, body)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
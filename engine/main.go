@@ -18,10 +18,13 @@ import (
 
 	"github.com/ovh/cds/engine/api"
 	"github.com/ovh/cds/engine/api/observability"
+	"github.com/ovh/cds/engine/cdn"
 	"github.com/ovh/cds/engine/elasticsearch"
+	"github.com/ovh/cds/engine/hatchery/aws"
 	"github.com/ovh/cds/engine/hatchery/kubernetes"
 	"github.com/ovh/cds/engine/hatchery/local"
 	"github.com/ovh/cds/engine/hatchery/marathon"
+	"github.com/ovh/cds/engine/hatchery/nomad"
 	"github.com/ovh/cds/engine/hatchery/openstack"
 	"github.com/ovh/cds/engine/hatchery/swarm"
 	"github.com/ovh/cds/engine/hatchery/vsphere"
@@ -145,7 +148,7 @@ var configNewCmd = &cobra.Command{
 	$ engine config new debug tracing [µService(s)...]
 
 # All options
-	$ engine config new [debug] [tracing] [api] [hatchery:local] [hatchery:marathon] [hatchery:openstack] [hatchery:swarm] [hatchery:vsphere] [elasticsearch] [hooks] [vcs] [repositories] [migrate]
+	$ engine config new [debug] [tracing] [api] [hatchery:local] [hatchery:marathon] [hatchery:aws] [hatchery:nomad] [hatchery:openstack] [hatchery:swarm] [hatchery:vsphere] [elasticsearch] [hooks] [vcs] [repositories] [cdn] [migrate]
 
 `,
 	Run: func(cmd *cobra.Command, args []string) {
@@ -206,6 +209,10 @@ var configNewCmd = &cobra.Command{
 			conf.Repositories.API.Token = sharedInfraToken
 		}
 
+		if conf.CDN != nil {
+			conf.CDN.API.Token = sharedInfraToken
+		}
+
 		if conf.DatabaseMigrate != nil {
 			conf.DatabaseMigrate.API.Token = sharedInfraToken
 		}
@@ -306,6 +313,22 @@ var configCheckCmd = &cobra.Command{
 			}
 		}
 
+		if conf.Hatchery != nil && conf.Hatchery.AWS != nil && conf.Hatchery.AWS.API.HTTP.URL != "" {
+			fmt.Printf("checking hatchery:aws configuration...\n")
+			if err := aws.New().CheckConfiguration(*conf.Hatchery.AWS); err != nil {
+				fmt.Printf("hatchery:aws Configuration: %v\n", err)
+				hasError = true
+			}
+		}
+
+		if conf.Hatchery != nil && conf.Hatchery.Nomad != nil && conf.Hatchery.Nomad.API.HTTP.URL != "" {
+			fmt.Printf("checking hatchery:nomad configuration...\n")
+			if err := nomad.New().CheckConfiguration(*conf.Hatchery.Nomad); err != nil {
+				fmt.Printf("hatchery:nomad Configuration: %v\n", err)
+				hasError = true
+			}
+		}
+
 		if conf.Hatchery != nil && conf.Hatchery.Openstack != nil && conf.Hatchery.Openstack.API.HTTP.URL != "" {
 			fmt.Printf("checking hatchery:openstack configuration...\n")
 			if err := openstack.New().CheckConfiguration(*conf.Hatchery.Openstack); err != nil {
@@ -354,6 +377,14 @@ var configCheckCmd = &cobra.Command{
 			}
 		}
 
+		if conf.CDN != nil && conf.CDN.API.HTTP.URL != "" {
+			fmt.Printf("checking cdn configuration...\n")
+			if err := cdn.New().CheckConfiguration(*conf.CDN); err != nil {
+				fmt.Printf("cdn Configuration: %v\n", err)
+				hasError = true
+			}
+		}
+
 		if !hasError {
 			fmt.Println("Configuration file OK")
 		}
@@ -390,9 +421,12 @@ This component operates CDS workflow repositories
 #### VCS
 This component operates CDS VCS connectivity
 
+#### CDN
+This component stores and replicates CDS logs and artifacts
+
 Start all of this with a single command:
 
-	$ engine start [api] [hatchery:local] [hatchery:marathon] [hatchery:openstack] [hatchery:swarm] [hatchery:vsphere] [elasticsearch] [hooks] [vcs] [repositories] [migrate]
+	$ engine start [api] [hatchery:local] [hatchery:marathon] [hatchery:aws] [hatchery:nomad] [hatchery:openstack] [hatchery:swarm] [hatchery:vsphere] [elasticsearch] [hooks] [vcs] [repositories] [cdn] [migrate]
 
 All the services are using the same configuration file format.
 
@@ -476,6 +510,12 @@ See $ engine config command for more details.
 			case "hatchery:marathon":
 				services = append(services, serviceConf{arg: a, service: marathon.New(), cfg: *conf.Hatchery.Marathon})
 				names = append(names, conf.Hatchery.Marathon.Name)
+			case "hatchery:aws":
+				services = append(services, serviceConf{arg: a, service: aws.New(), cfg: *conf.Hatchery.AWS})
+				names = append(names, conf.Hatchery.AWS.Name)
+			case "hatchery:nomad":
+				services = append(services, serviceConf{arg: a, service: nomad.New(), cfg: *conf.Hatchery.Nomad})
+				names = append(names, conf.Hatchery.Nomad.Name)
 			case "hatchery:openstack":
 				services = append(services, serviceConf{arg: a, service: openstack.New(), cfg: *conf.Hatchery.Openstack})
 				names = append(names, conf.Hatchery.Openstack.Name)
@@ -497,6 +537,9 @@ See $ engine config command for more details.
 			case "elasticsearch":
 				services = append(services, serviceConf{arg: a, service: elasticsearch.New(), cfg: *conf.ElasticSearch})
 				names = append(names, conf.ElasticSearch.Name)
+			case "cdn":
+				services = append(services, serviceConf{arg: a, service: cdn.New(), cfg: *conf.CDN})
+				names = append(names, conf.CDN.Name)
 			default:
 				fmt.Printf("Error: service '%s' unknown\n", a)
 				os.Exit(1)
@@ -506,6 +549,7 @@ See $ engine config command for more details.
 		//Initialize logs
 		log.Initialize(&log.Conf{
 			Level:                  conf.Log.Level,
+			Format:                 conf.Log.Format,
 			GraylogProtocol:        conf.Log.Graylog.Protocol,
 			GraylogHost:            conf.Log.Graylog.Host,
 			GraylogPort:            fmt.Sprintf("%d", conf.Log.Graylog.Port),
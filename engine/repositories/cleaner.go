@@ -5,6 +5,7 @@ import (
 	"os"
 	"path/filepath"
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/ovh/cds/sdk"
@@ -29,6 +30,9 @@ func (s *Service) vacuumCleaner(ctx context.Context) error {
 			if err := s.vacuumStoreCleanerRun(); err != nil {
 				log.Error("vacuumCleaner> Error cleaning the store: %v", err)
 			}
+			if err := s.vacuumMirrorsCleanerRun(); err != nil {
+				log.Error("vacuumCleaner> Error cleaning the mirrors: %v", err)
+			}
 		case <-ctx.Done():
 			return ctx.Err()
 		}
@@ -68,6 +72,10 @@ func (s *Service) vacuumFilesystemCleanerRun() error {
 	sort.Strings(names)
 
 	for _, n := range names {
+		// the mirrors have their own retention
+		if n == mirrorsDirectory {
+			continue
+		}
 		if err := s.vacuumFileSystemCleanerFunc(n); err != nil {
 			log.Error("vacuumFilesystemCleanerRun> %v ", err)
 		}
@@ -101,3 +109,48 @@ func (s *Service) vacuumFileSystemCleanerFunc(repoUUID string) error {
 
 	return nil
 }
+
+func (s *Service) vacuumMirrorsCleanerRun() error {
+	dir := filepath.Join(s.Cfg.Basedir, mirrorsDirectory)
+	fi, err := os.Open(dir)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer fi.Close()
+
+	names, err := fi.Readdirnames(-1)
+	if err != nil {
+		return err
+	}
+
+	for _, n := range names {
+		if !strings.HasSuffix(n, ".used") {
+			continue
+		}
+		id := strings.TrimSuffix(n, ".used")
+		used, err := os.Stat(s.mirrorUsed(id))
+		if err != nil {
+			log.Error("vacuumMirrorsCleanerRun> %v", err)
+			continue
+		}
+		if time.Since(used.ModTime()) < 24*time.Hour*time.Duration(s.Cfg.RepositoriesRentention) {
+			continue
+		}
+
+		log.Debug("vacuumMirrorsCleanerRun> Removing mirror %s", id)
+		if err := os.RemoveAll(s.mirrorPath(id)); err != nil {
+			log.Error("vacuumMirrorsCleanerRun> %v", err)
+			continue
+		}
+		for _, f := range []string{s.mirrorBundle(id), s.mirrorUsed(id)} {
+			if err := os.Remove(f); err != nil && !os.IsNotExist(err) {
+				log.Error("vacuumMirrorsCleanerRun> %v", err)
+			}
+		}
+	}
+
+	return nil
+}
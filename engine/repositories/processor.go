@@ -70,6 +70,15 @@ func (s *Service) do(op sdk.Operation) error {
 			op.Error = ""
 			op.Status = sdk.OperationStatusDone
 		}
+	// Refresh the mirror of the repository
+	case op.Setup.Mirror:
+		if err := s.processMirror(&op); err != nil {
+			op.Error = err.Error()
+			op.Status = sdk.OperationStatusError
+		} else {
+			op.Error = ""
+			op.Status = sdk.OperationStatusDone
+		}
 	default:
 		op.Error = "unrecognized setup"
 		op.Status = sdk.OperationStatusError
@@ -0,0 +1,105 @@
+package repositories
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+	"github.com/ovh/cds/sdk/vcs"
+	"github.com/ovh/cds/sdk/vcs/git"
+)
+
+// mirrorsDirectory is the directory of the mirrors in the basedir
+const mirrorsDirectory = "mirrors"
+
+// mirrorPath returns the path of the bare mirror of a repository
+func (s *Service) mirrorPath(id string) string {
+	return filepath.Join(s.Cfg.Basedir, mirrorsDirectory, id+".git")
+}
+
+// mirrorBundle returns the path of the bundle served to the workers for a mirror
+func (s *Service) mirrorBundle(id string) string {
+	return filepath.Join(s.Cfg.Basedir, mirrorsDirectory, id+".bundle")
+}
+
+// mirrorUsed returns the path of the file touched each time a mirror is served, it is used to expire the mirrors
+func (s *Service) mirrorUsed(id string) string {
+	return filepath.Join(s.Cfg.Basedir, mirrorsDirectory, id+".used")
+}
+
+func (s *Service) processMirror(op *sdk.Operation) error {
+	if !s.Cfg.Mirrors {
+		return fmt.Errorf("mirrors are disabled on this repositories service")
+	}
+
+	if op.ProjectKey == "" || op.VCSServer == "" {
+		return fmt.Errorf("the project and the vcs server of the mirror are mandatory")
+	}
+
+	id := sdk.MirrorID(op.ProjectKey, op.VCSServer, op.URL)
+	if err := os.MkdirAll(filepath.Join(s.Cfg.Basedir, mirrorsDirectory), 0700); err != nil {
+		return sdk.WithStack(err)
+	}
+
+	var auth *git.AuthOpts
+	if op.RepositoryStrategy.ConnectionType == "ssh" {
+		keyDir, err := ioutil.TempDir("", "mirror")
+		if err != nil {
+			return sdk.WithStack(err)
+		}
+		defer os.RemoveAll(keyDir) // nolint
+
+		keyFile := filepath.Join(keyDir, "key")
+		if err := ioutil.WriteFile(keyFile, []byte(op.RepositoryStrategy.SSHKeyContent), 0600); err != nil {
+			return sdk.WithStack(err)
+		}
+		auth = &git.AuthOpts{
+			PrivateKey: vcs.SSHKey{Filename: keyFile, Content: []byte(op.RepositoryStrategy.SSHKeyContent)},
+		}
+	} else if op.RepositoryStrategy.User != "" && op.RepositoryStrategy.Password != "" {
+		auth = &git.AuthOpts{
+			Username: op.RepositoryStrategy.User,
+			Password: op.RepositoryStrategy.Password,
+		}
+	}
+
+	log.Info("Repositories> processMirror> [%s] refreshing mirror %s of %s", op.UUID, id, op.URL)
+	stdErr := new(bytes.Buffer)
+	if err := git.UpdateReference(op.URL, s.mirrorPath(id), auth, &git.OutputOpts{Stderr: stdErr}); err != nil {
+		log.Error("Repositories> processMirror> [%s] unable to fetch %s: %v", op.UUID, op.URL, err)
+		return fmt.Errorf("unable to fetch the repository")
+	}
+
+	// the bundle is written next to the current one, so that the workers never download a partial bundle
+	tmp := s.mirrorBundle(id) + ".tmp"
+	if err := git.CreateBundle(s.mirrorPath(id), tmp, &git.OutputOpts{Stderr: stdErr}); err != nil {
+		log.Error("Repositories> processMirror> [%s] unable to create bundle of %s: %v: %s", op.UUID, id, err, stdErr.String())
+		_ = os.Remove(tmp)
+		return fmt.Errorf("unable to create the bundle of the repository")
+	}
+	if err := os.Rename(tmp, s.mirrorBundle(id)); err != nil {
+		return sdk.WithStack(err)
+	}
+
+	// a new mirror is kept for the retention even if it is never served
+	if _, err := os.Stat(s.mirrorUsed(id)); os.IsNotExist(err) {
+		return s.touchMirror(id)
+	}
+	return nil
+}
+
+// touchMirror marks a mirror as used
+func (s *Service) touchMirror(id string) error {
+	f, err := os.OpenFile(s.mirrorUsed(id), os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return sdk.WithStack(err)
+	}
+	f.Close() // nolint
+	now := time.Now()
+	return sdk.WithStack(os.Chtimes(s.mirrorUsed(id), now, now))
+}
@@ -4,9 +4,11 @@ import (
 	"archive/tar"
 	"bytes"
 	"context"
+	"encoding/hex"
 	"encoding/json"
 	"io"
 	"net/http"
+	"os"
 	"strings"
 	"time"
 
@@ -14,6 +16,7 @@ import (
 
 	"github.com/ovh/cds/engine/service"
 	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
 )
 
 func muxVar(r *http.Request, s string) string {
@@ -115,6 +118,37 @@ func (s *Service) getOperationsHandler() service.Handler {
 	}
 }
 
+func (s *Service) getMirrorBundleHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		id := muxVar(r, "id")
+		if _, err := hex.DecodeString(id); err != nil || id == "" {
+			return sdk.WithStack(sdk.ErrWrongRequest)
+		}
+
+		f, err := os.Open(s.mirrorBundle(id))
+		if os.IsNotExist(err) {
+			return sdk.WithStack(sdk.ErrNotFound)
+		}
+		if err != nil {
+			return sdk.WithStack(err)
+		}
+		defer f.Close()
+
+		fi, err := f.Stat()
+		if err != nil {
+			return sdk.WithStack(err)
+		}
+
+		if err := s.touchMirror(id); err != nil {
+			log.Warning("getMirrorBundleHandler> unable to mark mirror %s as used: %v", id, err)
+		}
+
+		w.Header().Set("Content-Type", "application/octet-stream")
+		http.ServeContent(w, r, id+".bundle", fi.ModTime(), f)
+		return nil
+	}
+}
+
 // Status returns sdk.MonitoringStatus, implements interface service.Service
 func (s *Service) Status() sdk.MonitoringStatus {
 	m := s.CommonMonitoring()
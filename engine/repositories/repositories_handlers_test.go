@@ -3,12 +3,21 @@ package repositories
 import (
 	"archive/tar"
 	"bytes"
+	"context"
 	"encoding/json"
+	"io/ioutil"
 	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
 	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
 
 	"github.com/ovh/cds/sdk"
 
+	"github.com/ovh/cds/engine/api"
 	"github.com/ovh/cds/engine/api/test"
 	"github.com/stretchr/testify/assert"
 )
@@ -104,6 +113,86 @@ func Test_postOperationMultiPartHandler(t *testing.T) {
 	t.Logf(rec.Body.String())
 }
 
+func Test_getMirrorBundleHandler(t *testing.T) {
+	basedir, err := ioutil.TempDir("", "repositories")
+	test.NoError(t, err)
+	defer os.RemoveAll(basedir)
+
+	// a local repository with one commit
+	origin := filepath.Join(basedir, "origin")
+	for _, args := range [][]string{
+		{"init", "--quiet", origin},
+		{"-C", origin, "-c", "user.name=cds", "-c", "user.email=cds@localhost", "commit", "--quiet", "--allow-empty", "-m", "initial"},
+	} {
+		out, err := exec.Command("git", args...).CombinedOutput()
+		test.NoError(t, err, string(out))
+	}
+
+	ctx := context.Background()
+	s := new(Service)
+	s.Hash = "hash"
+	s.Cfg.Basedir = basedir
+	s.Cfg.RepositoriesRentention = 1
+	s.Router = &api.Router{
+		Mux:        mux.NewRouter(),
+		Prefix:     "/" + test.GetTestName(t),
+		Background: ctx,
+	}
+	s.initRouter(ctx)
+
+	op := sdk.Operation{
+		UUID:       sdk.UUID(),
+		ProjectKey: "PROJ",
+		VCSServer:  "github",
+		URL:        origin,
+		RepositoryStrategy: sdk.RepositoryStrategy{
+			ConnectionType: "ssh",
+			SSHKeyContent:  "key",
+		},
+		Setup: sdk.OperationSetup{Mirror: true},
+	}
+	assert.Error(t, s.processMirror(&op), "mirrors are disabled by default")
+
+	s.Cfg.Mirrors = true
+	test.NoError(t, s.processMirror(&op))
+
+	id := sdk.MirrorID("PROJ", "github", origin)
+	uri := s.Router.GetRoute("GET", s.getMirrorBundleHandler, map[string]string{"id": id})
+	test.NotEmpty(t, uri)
+
+	// the bundle is only served to the api
+	req := httptest.NewRequest("GET", uri, nil)
+	rec := httptest.NewRecorder()
+	s.Router.Mux.ServeHTTP(rec, req)
+	assert.Equal(t, 401, rec.Code)
+
+	req = newRequest(t, s, "GET", uri, nil)
+	rec = httptest.NewRecorder()
+	s.Router.Mux.ServeHTTP(rec, req)
+	assert.Equal(t, 200, rec.Code)
+	assert.True(t, bytes.HasPrefix(rec.Body.Bytes(), []byte("# v2 git bundle")))
+
+	req = newRequest(t, s, "GET", s.Router.GetRoute("GET", s.getMirrorBundleHandler, map[string]string{"id": sdk.MirrorID("PROJ", "github", "unknown")}), nil)
+	rec = httptest.NewRecorder()
+	s.Router.Mux.ServeHTTP(rec, req)
+	assert.Equal(t, 404, rec.Code)
+
+	// the mirror of another project is not shared
+	req = newRequest(t, s, "GET", s.Router.GetRoute("GET", s.getMirrorBundleHandler, map[string]string{"id": sdk.MirrorID("OTHER", "github", origin)}), nil)
+	rec = httptest.NewRecorder()
+	s.Router.Mux.ServeHTTP(rec, req)
+	assert.Equal(t, 404, rec.Code)
+
+	// the mirrors which are not used are removed after the retention
+	old := time.Now().Add(-48 * time.Hour)
+	test.NoError(t, os.Chtimes(s.mirrorUsed(id), old, old))
+	test.NoError(t, s.vacuumMirrorsCleanerRun())
+	_, err = os.Stat(s.mirrorPath(id))
+	assert.True(t, os.IsNotExist(err))
+	_, err = os.Stat(s.mirrorBundle(id))
+	assert.True(t, os.IsNotExist(err))
+}
+
 func getWorkflowTarFile(t *testing.T, buf *bytes.Buffer) {
 	tw := tar.NewWriter(buf)
 	defer func() {
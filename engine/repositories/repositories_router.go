@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/ovh/cds/engine/api"
+	"github.com/ovh/cds/engine/api/observability"
 	"github.com/ovh/cds/sdk/log"
 )
 
@@ -13,10 +14,16 @@ func (s *Service) initRouter(ctx context.Context) {
 	r.Background = ctx
 	r.URL = s.Cfg.URL
 	r.SetHeaderFunc = api.DefaultHeaders
-	//	r.Middlewares = append(r.Middlewares, s.authMiddleware)
+	r.Middlewares = append(r.Middlewares, s.authMiddleware)
 
 	r.Handle("/mon/version", r.GET(api.VersionHandler, api.Auth(false)))
 	r.Handle("/mon/status", r.GET(s.getStatusHandler))
+	r.Handle("/mon/metrics", r.GET(observability.StatsHandler, api.Auth(false)))
 	r.Handle("/operations", r.POST(s.postOperationHandler))
 	r.Handle("/operations/{uuid}", r.GET(s.getOperationsHandler))
+	r.Handle("/mirrors/{id}/bundle", r.GET(s.getMirrorBundleHandler))
+
+	if err := r.InitMetrics("cds-repositories", s.Cfg.Name); err != nil {
+		log.Error("unable to init router metrics: %v", err)
+	}
 }
@@ -25,6 +25,7 @@ type Configuration struct {
 	Basedir                string `toml:"basedir" comment:"Root directory where the service will store all checked-out repositories" json:"basedir"`
 	OperationRetention     int    `toml:"operation_retention" comment:"Operation retention in redis store (in days)" default:"5" json:"operation_retention"`
 	RepositoriesRentention int    `toml:"repositories_retention" comment:"Re retention on the filesystem (in days)" default:"10" json:"repositories_retention"`
+	Mirrors                bool   `toml:"mirrors" comment:"Maintain bare mirrors of the repositories, refreshed by the webhooks and served to the workers through the API.\n The mirrors which are not used are removed after the repositories retention" default:"false" json:"mirrors"`
 	HTTP                   struct {
 		Addr string `toml:"addr" default:"" commented:"true" comment:"Listen address without port, example: 127.0.0.1" json:"addr"`
 		Port int    `toml:"port" default:"8085" json:"port"`
@@ -34,7 +35,7 @@ type Configuration struct {
 	Cache struct {
 		TTL   int `toml:"ttl" default:"60" json:"ttl"`
 		Redis struct {
-			Host     string `toml:"host" default:"localhost:6379" comment:"If your want to use a redis-sentinel based cluster, follow this syntax! <clustername>@sentinel1:26379,sentinel2:26379,sentinel3:26379" json:"host"`
+			Host     string `toml:"host" default:"localhost:6379" comment:"If your want to use a redis-sentinel based cluster, follow this syntax! <clustername>@sentinel1:26379,sentinel2:26379,sentinel3:26379\nIf your want to use a redis cluster, list its nodes: node1:7000,node2:7000,node3:7000" json:"host"`
 			Password string `toml:"password" json:"-"`
 		} `toml:"redis" json:"redis"`
 	} `toml:"cache" comment:"######################\n CDS Repositories Cache Settings \n######################" json:"cache"`
@@ -96,7 +96,8 @@ func WriteError(w http.ResponseWriter, r *http.Request, err error) {
 	httpErr.UUID = uuid.NewUUID().String()
 	isErrWithStack := sdk.IsErrorWithStack(err)
 
-	entry := logrus.WithField("method", r.Method).
+	entry := logrus.WithFields(logrus.Fields(log.FieldsFromContext(r.Context()))).
+		WithField("method", r.Method).
 		WithField("request_uri", r.RequestURI).
 		WithField("status", httpErr.Status).
 		WithField("error_uuid", httpErr.UUID)
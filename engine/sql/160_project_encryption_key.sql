@@ -0,0 +1,15 @@
+-- +migrate Up
+CREATE TABLE project_encryption_key (
+  id BIGSERIAL PRIMARY KEY,
+  project_id BIGINT NOT NULL,
+  kms TEXT NOT NULL,
+  wrapped_key BYTEA NOT NULL,
+  active BOOLEAN NOT NULL DEFAULT true,
+  created TIMESTAMP WITH TIME ZONE DEFAULT LOCALTIMESTAMP
+);
+
+SELECT create_foreign_key_idx_cascade('FK_PROJECT_ENCRYPTION_KEY', 'project_encryption_key', 'project', 'project_id', 'id');
+SELECT create_index('project_encryption_key', 'IDX_PROJECT_ENCRYPTION_KEY_ACTIVE', 'project_id,active');
+
+-- +migrate Down
+DROP TABLE project_encryption_key;
@@ -0,0 +1,21 @@
+-- +migrate Up
+CREATE TABLE audit_log (
+  id BIGSERIAL PRIMARY KEY,
+  created TIMESTAMP WITH TIME ZONE DEFAULT LOCALTIMESTAMP,
+  actor TEXT,
+  actor_email TEXT,
+  event_type TEXT NOT NULL,
+  entity_type TEXT NOT NULL,
+  entity_name TEXT,
+  action TEXT NOT NULL,
+  project_key TEXT,
+  data JSONB
+);
+
+SELECT create_index('audit_log', 'IDX_AUDIT_LOG_CREATED', 'created');
+SELECT create_index('audit_log', 'IDX_AUDIT_LOG_PROJECT_KEY', 'project_key,created');
+SELECT create_index('audit_log', 'IDX_AUDIT_LOG_ACTOR', 'actor,created');
+SELECT create_index('audit_log', 'IDX_AUDIT_LOG_ENTITY', 'entity_type,entity_name');
+
+-- +migrate Down
+DROP TABLE audit_log;
@@ -0,0 +1,16 @@
+-- +migrate Up
+CREATE TABLE project_queue_quota (
+  project_id BIGINT PRIMARY KEY,
+  max_concurrent_jobs INT NOT NULL
+);
+SELECT create_foreign_key_idx_cascade('FK_PROJECT_QUEUE_QUOTA_PROJECT', 'project_queue_quota', 'project', 'project_id', 'id');
+
+CREATE TABLE group_queue_weight (
+  group_id BIGINT PRIMARY KEY,
+  weight INT NOT NULL
+);
+SELECT create_foreign_key_idx_cascade('FK_GROUP_QUEUE_WEIGHT_GROUP', 'group_queue_weight', 'group', 'group_id', 'id');
+
+-- +migrate Down
+DROP TABLE project_queue_quota;
+DROP TABLE group_queue_weight;
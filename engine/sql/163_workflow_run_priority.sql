@@ -0,0 +1,7 @@
+-- +migrate Up
+ALTER TABLE workflow_run ADD COLUMN priority INT NOT NULL DEFAULT 0;
+ALTER TABLE workflow_node_run_job ADD COLUMN priority INT NOT NULL DEFAULT 0;
+
+-- +migrate Down
+ALTER TABLE workflow_run DROP COLUMN priority;
+ALTER TABLE workflow_node_run_job DROP COLUMN priority;
@@ -0,0 +1,13 @@
+-- +migrate Up
+ALTER TABLE workflow_node_run_artifacts ADD COLUMN sha256sum TEXT;
+ALTER TABLE workflow_node_run_artifacts ADD COLUMN signing_key TEXT;
+ALTER TABLE workflow_node_run_artifacts ADD COLUMN signature TEXT;
+
+INSERT into action_parameter (action_id, name, description, type, value, advanced) values((select id from action where name = 'Artifact Upload' and type = 'Builtin'), 'signingKey', 'PGP key of the project used to sign the checksums of the artifacts. Example: proj-signing', 'pgp-key', '', true);
+
+-- +migrate Down
+ALTER TABLE workflow_node_run_artifacts DROP COLUMN sha256sum;
+ALTER TABLE workflow_node_run_artifacts DROP COLUMN signing_key;
+ALTER TABLE workflow_node_run_artifacts DROP COLUMN signature;
+
+DELETE from action_parameter where name = 'signingKey' and action_id = (select id from action where name = 'Artifact Upload' and type = 'Builtin');
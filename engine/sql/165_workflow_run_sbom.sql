@@ -0,0 +1,31 @@
+-- +migrate Up
+CREATE TABLE workflow_run_sbom (
+  id BIGSERIAL PRIMARY KEY,
+  project_id BIGINT NOT NULL,
+  workflow_id BIGINT NOT NULL,
+  workflow_run_id BIGINT NOT NULL,
+  workflow_node_run_id BIGINT NOT NULL,
+  name TEXT NOT NULL,
+  format TEXT NOT NULL,
+  document JSONB,
+  created TIMESTAMP WITH TIME ZONE DEFAULT LOCALTIMESTAMP
+);
+
+SELECT create_foreign_key_idx_cascade('FK_WORKFLOW_RUN_SBOM_PROJECT', 'workflow_run_sbom', 'project', 'project_id', 'id');
+SELECT create_foreign_key_idx_cascade('FK_WORKFLOW_RUN_SBOM_WORKFLOW_RUN', 'workflow_run_sbom', 'workflow_run', 'workflow_run_id', 'id');
+
+CREATE TABLE workflow_run_sbom_component (
+  sbom_id BIGINT NOT NULL,
+  name TEXT NOT NULL,
+  version TEXT NOT NULL,
+  type TEXT,
+  purl TEXT
+);
+
+SELECT create_foreign_key_idx_cascade('FK_WORKFLOW_RUN_SBOM_COMPONENT_SBOM', 'workflow_run_sbom_component', 'workflow_run_sbom', 'sbom_id', 'id');
+SELECT create_index('workflow_run_sbom_component', 'IDX_WORKFLOW_RUN_SBOM_COMPONENT_NAME', 'name,version');
+SELECT create_index('workflow_run_sbom_component', 'IDX_WORKFLOW_RUN_SBOM_COMPONENT_PURL', 'purl');
+
+-- +migrate Down
+DROP TABLE workflow_run_sbom_component;
+DROP TABLE workflow_run_sbom;
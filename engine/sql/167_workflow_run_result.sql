@@ -0,0 +1,19 @@
+-- +migrate Up
+CREATE TABLE workflow_run_result (
+  id BIGSERIAL PRIMARY KEY,
+  workflow_run_id BIGINT NOT NULL,
+  workflow_node_run_id BIGINT NOT NULL,
+  workflow_run_job_id BIGINT NOT NULL,
+  workflow_node_name TEXT NOT NULL,
+  sub_num BIGINT NOT NULL DEFAULT 0,
+  type TEXT NOT NULL,
+  name TEXT,
+  data JSONB,
+  created TIMESTAMP WITH TIME ZONE DEFAULT LOCALTIMESTAMP
+);
+
+SELECT create_foreign_key_idx_cascade('FK_WORKFLOW_RUN_RESULT_WORKFLOW_RUN', 'workflow_run_result', 'workflow_run', 'workflow_run_id', 'id');
+SELECT create_index('workflow_run_result', 'IDX_WORKFLOW_RUN_RESULT_TYPE', 'workflow_run_id,type');
+
+-- +migrate Down
+DROP TABLE workflow_run_result;
@@ -0,0 +1,24 @@
+-- +migrate Up
+CREATE TABLE workflow_run_test_case (
+  id BIGSERIAL PRIMARY KEY,
+  workflow_id BIGINT NOT NULL,
+  workflow_run_id BIGINT NOT NULL,
+  workflow_node_run_id BIGINT NOT NULL,
+  workflow_node_name TEXT NOT NULL,
+  num BIGINT NOT NULL,
+  sub_num BIGINT NOT NULL,
+  vcs_branch TEXT,
+  test_suite TEXT NOT NULL,
+  classname TEXT,
+  name TEXT NOT NULL,
+  status VARCHAR(20) NOT NULL,
+  duration DOUBLE PRECISION,
+  created TIMESTAMP WITH TIME ZONE DEFAULT LOCALTIMESTAMP
+);
+
+SELECT create_foreign_key_idx_cascade('FK_WORKFLOW_RUN_TEST_CASE_WORKFLOW_RUN', 'workflow_run_test_case', 'workflow_run', 'workflow_run_id', 'id');
+SELECT create_index('workflow_run_test_case', 'IDX_WORKFLOW_RUN_TEST_CASE_NODE_RUN', 'workflow_node_run_id');
+SELECT create_index('workflow_run_test_case', 'IDX_WORKFLOW_RUN_TEST_CASE_NAME', 'workflow_id,workflow_node_name,test_suite,name');
+
+-- +migrate Down
+DROP TABLE workflow_run_test_case;
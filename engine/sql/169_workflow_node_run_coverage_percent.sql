@@ -0,0 +1,16 @@
+-- +migrate Up
+ALTER TABLE workflow_node_run_coverage ADD COLUMN lines_percent DOUBLE PRECISION DEFAULT 0;
+ALTER TABLE workflow_node_run_coverage ADD COLUMN functions_percent DOUBLE PRECISION DEFAULT 0;
+ALTER TABLE workflow_node_run_coverage ADD COLUMN branches_percent DOUBLE PRECISION DEFAULT 0;
+ALTER TABLE workflow_node_run_coverage ADD COLUMN created TIMESTAMP WITH TIME ZONE DEFAULT LOCALTIMESTAMP;
+
+UPDATE workflow_node_run_coverage SET
+  lines_percent = CASE WHEN (report->>'total_lines')::float > 0 THEN round(((report->>'covered_lines')::numeric * 100 / (report->>'total_lines')::numeric), 2) ELSE 0 END,
+  functions_percent = CASE WHEN (report->>'total_functions')::float > 0 THEN round(((report->>'covered_functions')::numeric * 100 / (report->>'total_functions')::numeric), 2) ELSE 0 END,
+  branches_percent = CASE WHEN (report->>'total_branches')::float > 0 THEN round(((report->>'covered_branches')::numeric * 100 / (report->>'total_branches')::numeric), 2) ELSE 0 END;
+
+-- +migrate Down
+ALTER TABLE workflow_node_run_coverage DROP COLUMN lines_percent;
+ALTER TABLE workflow_node_run_coverage DROP COLUMN functions_percent;
+ALTER TABLE workflow_node_run_coverage DROP COLUMN branches_percent;
+ALTER TABLE workflow_node_run_coverage DROP COLUMN created;
@@ -0,0 +1,11 @@
+-- +migrate Up
+CREATE TABLE workflow_badge (
+  workflow_id BIGINT PRIMARY KEY,
+  token TEXT,
+  created TIMESTAMP WITH TIME ZONE DEFAULT LOCALTIMESTAMP
+);
+
+SELECT create_foreign_key_idx_cascade('FK_WORKFLOW_BADGE_WORKFLOW', 'workflow_badge', 'workflow', 'workflow_id', 'id');
+
+-- +migrate Down
+DROP TABLE workflow_badge;
@@ -0,0 +1,24 @@
+-- +migrate Up
+CREATE TABLE workflow_run_duration (
+  id BIGSERIAL PRIMARY KEY,
+  workflow_id BIGINT NOT NULL,
+  workflow_run_id BIGINT NOT NULL,
+  workflow_node_run_id BIGINT NOT NULL,
+  workflow_node_name TEXT NOT NULL,
+  pipeline_name TEXT NOT NULL,
+  num BIGINT NOT NULL,
+  kind VARCHAR(20) NOT NULL,
+  stage_name TEXT,
+  job_name TEXT,
+  step_order INT,
+  step_name TEXT,
+  status VARCHAR(20) NOT NULL,
+  duration DOUBLE PRECISION NOT NULL,
+  created TIMESTAMP WITH TIME ZONE DEFAULT LOCALTIMESTAMP
+);
+
+SELECT create_foreign_key_idx_cascade('FK_WORKFLOW_RUN_DURATION_WORKFLOW_RUN', 'workflow_run_duration', 'workflow_run', 'workflow_run_id', 'id');
+SELECT create_index('workflow_run_duration', 'IDX_WORKFLOW_RUN_DURATION_KIND', 'workflow_id,kind,created');
+
+-- +migrate Down
+DROP TABLE workflow_run_duration;
@@ -0,0 +1,19 @@
+-- +migrate Up
+CREATE TABLE workflow_run_job_output (
+  id BIGSERIAL PRIMARY KEY,
+  workflow_run_id BIGINT NOT NULL,
+  workflow_node_run_id BIGINT NOT NULL,
+  workflow_run_job_id BIGINT NOT NULL,
+  workflow_node_name TEXT NOT NULL,
+  sub_num BIGINT NOT NULL,
+  job_name TEXT NOT NULL,
+  key TEXT NOT NULL,
+  value TEXT,
+  created TIMESTAMP WITH TIME ZONE DEFAULT LOCALTIMESTAMP
+);
+
+SELECT create_foreign_key_idx_cascade('FK_WORKFLOW_RUN_JOB_OUTPUT_WORKFLOW_RUN', 'workflow_run_job_output', 'workflow_run', 'workflow_run_id', 'id');
+SELECT create_unique_index('workflow_run_job_output', 'IDX_WORKFLOW_RUN_JOB_OUTPUT_KEY', 'workflow_node_run_id,job_name,key');
+
+-- +migrate Down
+DROP TABLE workflow_run_job_output;
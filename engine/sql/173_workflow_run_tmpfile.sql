@@ -0,0 +1,17 @@
+-- +migrate Up
+CREATE TABLE workflow_run_tmpfile (
+  id BIGSERIAL PRIMARY KEY,
+  workflow_run_id BIGINT NOT NULL,
+  workflow_run_job_id BIGINT NOT NULL,
+  name TEXT NOT NULL,
+  hash TEXT NOT NULL,
+  size BIGINT NOT NULL,
+  created TIMESTAMP WITH TIME ZONE DEFAULT LOCALTIMESTAMP
+);
+
+SELECT create_foreign_key_idx_cascade('FK_WORKFLOW_RUN_TMPFILE_WORKFLOW_RUN', 'workflow_run_tmpfile', 'workflow_run', 'workflow_run_id', 'id');
+SELECT create_unique_index('workflow_run_tmpfile', 'IDX_WORKFLOW_RUN_TMPFILE_NAME', 'workflow_run_id,name');
+SELECT create_index('workflow_run_tmpfile', 'IDX_WORKFLOW_RUN_TMPFILE_HASH', 'hash');
+
+-- +migrate Down
+DROP TABLE workflow_run_tmpfile;
@@ -0,0 +1,10 @@
+-- +migrate Up
+ALTER TABLE action ADD COLUMN project_id BIGINT;
+ALTER TABLE action ADD COLUMN from_repository TEXT;
+ALTER TABLE action ADD COLUMN repository_commit TEXT;
+SELECT create_index('action', 'IDX_ACTION_PROJECT_ID', 'project_id');
+
+-- +migrate Down
+ALTER TABLE action DROP COLUMN project_id;
+ALTER TABLE action DROP COLUMN from_repository;
+ALTER TABLE action DROP COLUMN repository_commit;
@@ -0,0 +1,7 @@
+-- +migrate Up
+ALTER TABLE action ADD COLUMN version TEXT NOT NULL DEFAULT '';
+ALTER TABLE action_edge ADD COLUMN version_constraint TEXT NOT NULL DEFAULT '';
+
+-- +migrate Down
+ALTER TABLE action DROP COLUMN version;
+ALTER TABLE action_edge DROP COLUMN version_constraint;
@@ -0,0 +1,7 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS "group_ldap" (group_id BIGINT PRIMARY KEY, dn TEXT NOT NULL, deleted BOOLEAN NOT NULL DEFAULT false);
+SELECT create_foreign_key_idx_cascade('FK_GROUP_LDAP_GROUP', 'group_ldap', 'group', 'group_id', 'id');
+SELECT create_unique_index('group_ldap', 'IDX_GROUP_LDAP_DN', 'dn');
+
+-- +migrate Down
+DROP TABLE IF EXISTS "group_ldap";
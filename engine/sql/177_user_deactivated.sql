@@ -0,0 +1,5 @@
+-- +migrate Up
+ALTER TABLE "user" ADD COLUMN deactivated BOOLEAN NOT NULL DEFAULT false;
+
+-- +migrate Down
+ALTER TABLE "user" DROP COLUMN deactivated;
@@ -0,0 +1,7 @@
+-- +migrate Up
+ALTER TABLE access_token ADD COLUMN scopes JSONB;
+ALTER TABLE access_token ADD COLUMN last_used TIMESTAMP WITH TIME ZONE;
+
+-- +migrate Down
+ALTER TABLE access_token DROP COLUMN scopes;
+ALTER TABLE access_token DROP COLUMN last_used;
@@ -0,0 +1,5 @@
+-- +migrate Up
+ALTER TABLE access_token ADD COLUMN restrictions JSONB;
+
+-- +migrate Down
+ALTER TABLE access_token DROP COLUMN restrictions;
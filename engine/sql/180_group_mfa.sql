@@ -0,0 +1,6 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS "group_mfa" (group_id BIGINT PRIMARY KEY, required BOOLEAN NOT NULL DEFAULT false);
+SELECT create_foreign_key_idx_cascade('FK_GROUP_MFA_GROUP', 'group_mfa', 'group', 'group_id', 'id');
+
+-- +migrate Down
+DROP TABLE IF EXISTS "group_mfa";
@@ -0,0 +1,10 @@
+-- +migrate Up
+ALTER TABLE "cds_migration" ADD COLUMN IF NOT EXISTS phase VARCHAR(50) NOT NULL DEFAULT '';
+ALTER TABLE "cds_migration" ADD COLUMN IF NOT EXISTS batch_cursor BIGINT NOT NULL DEFAULT 0;
+CREATE TABLE IF NOT EXISTS "database_compatibility" (id INT PRIMARY KEY DEFAULT 1 CHECK (id = 1), min_schema_version INT NOT NULL DEFAULT 0);
+INSERT INTO "database_compatibility" (id, min_schema_version) VALUES (1, 0) ON CONFLICT DO NOTHING;
+
+-- +migrate Down
+DROP TABLE IF EXISTS "database_compatibility";
+ALTER TABLE "cds_migration" DROP COLUMN IF EXISTS phase;
+ALTER TABLE "cds_migration" DROP COLUMN IF EXISTS batch_cursor;
@@ -0,0 +1,16 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS "event_outbox" (
+    id BIGSERIAL PRIMARY KEY,
+    created TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT LOCALTIMESTAMP,
+    event_type VARCHAR(256) NOT NULL,
+    project_key VARCHAR(256) NOT NULL DEFAULT '',
+    event JSONB NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    attempts INT NOT NULL DEFAULT 0,
+    next_attempt TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT LOCALTIMESTAMP,
+    last_error TEXT NOT NULL DEFAULT ''
+);
+SELECT create_index('event_outbox', 'IDX_EVENT_OUTBOX_STATUS', 'status,next_attempt');
+
+-- +migrate Down
+DROP TABLE IF EXISTS "event_outbox";
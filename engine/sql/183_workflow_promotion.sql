@@ -0,0 +1,15 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS "workflow_promotion" (
+    id BIGSERIAL PRIMARY KEY,
+    project_key VARCHAR(256) NOT NULL,
+    workflow_name VARCHAR(256) NOT NULL,
+    source TEXT NOT NULL DEFAULT '',
+    username VARCHAR(256) NOT NULL DEFAULT '',
+    comment TEXT NOT NULL DEFAULT '',
+    created TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT LOCALTIMESTAMP,
+    diff TEXT NOT NULL DEFAULT ''
+);
+SELECT create_index('workflow_promotion', 'IDX_WORKFLOW_PROMOTION_WORKFLOW', 'project_key,workflow_name');
+
+-- +migrate Down
+DROP TABLE IF EXISTS "workflow_promotion";
@@ -0,0 +1,5 @@
+-- +migrate Up
+ALTER TABLE "project" ADD COLUMN IF NOT EXISTS archived BOOLEAN NOT NULL DEFAULT false;
+
+-- +migrate Down
+ALTER TABLE "project" DROP COLUMN IF EXISTS archived;
@@ -0,0 +1,18 @@
+-- +migrate Up
+ALTER TABLE "pipeline" ADD COLUMN IF NOT EXISTS to_delete BOOLEAN NOT NULL DEFAULT false;
+
+CREATE TABLE IF NOT EXISTS "trash" (
+    id BIGSERIAL PRIMARY KEY,
+    project_id BIGINT NOT NULL,
+    type VARCHAR(64) NOT NULL,
+    item_id BIGINT NOT NULL,
+    name VARCHAR(256) NOT NULL,
+    deleted_by VARCHAR(256) NOT NULL DEFAULT '',
+    deleted TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT LOCALTIMESTAMP
+);
+SELECT create_unique_index('trash', 'IDX_TRASH_ITEM', 'type,item_id');
+SELECT create_foreign_key_idx_cascade('FK_TRASH_PROJECT', 'trash', 'project', 'project_id', 'id');
+
+-- +migrate Down
+DROP TABLE IF EXISTS "trash";
+ALTER TABLE "pipeline" DROP COLUMN IF EXISTS to_delete;
@@ -0,0 +1,30 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS "organization" (
+    id BIGSERIAL PRIMARY KEY,
+    name VARCHAR(256) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    max_concurrent_jobs INT NOT NULL DEFAULT 0,
+    max_storage BIGINT NOT NULL DEFAULT 0,
+    created TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT LOCALTIMESTAMP
+);
+SELECT create_unique_index('organization', 'IDX_ORGANIZATION_NAME', 'name');
+
+CREATE TABLE IF NOT EXISTS "organization_admin" (
+    organization_id BIGINT NOT NULL,
+    user_id BIGINT NOT NULL,
+    PRIMARY KEY (organization_id, user_id)
+);
+SELECT create_foreign_key_idx_cascade('FK_ORGANIZATION_ADMIN_ORGANIZATION', 'organization_admin', 'organization', 'organization_id', 'id');
+SELECT create_foreign_key_idx_cascade('FK_ORGANIZATION_ADMIN_USER', 'organization_admin', 'user', 'user_id', 'id');
+
+ALTER TABLE "project" ADD COLUMN IF NOT EXISTS organization_id BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE "worker_model" ADD COLUMN IF NOT EXISTS organization_id BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE "integration_model" ADD COLUMN IF NOT EXISTS organization_id BIGINT NOT NULL DEFAULT 0;
+SELECT create_index('project', 'IDX_PROJECT_ORGANIZATION', 'organization_id');
+
+-- +migrate Down
+ALTER TABLE "integration_model" DROP COLUMN IF EXISTS organization_id;
+ALTER TABLE "worker_model" DROP COLUMN IF EXISTS organization_id;
+ALTER TABLE "project" DROP COLUMN IF EXISTS organization_id;
+DROP TABLE IF EXISTS "organization_admin";
+DROP TABLE IF EXISTS "organization";
@@ -0,0 +1,15 @@
+-- +migrate Up
+ALTER TABLE "organization" ADD COLUMN IF NOT EXISTS max_build_minutes INT NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS "resource_usage" (
+    project_id BIGINT NOT NULL,
+    month VARCHAR(7) NOT NULL,
+    build_seconds BIGINT NOT NULL DEFAULT 0,
+    artifact_size BIGINT NOT NULL DEFAULT 0,
+    PRIMARY KEY (project_id, month)
+);
+SELECT create_foreign_key_idx_cascade('FK_RESOURCE_USAGE_PROJECT', 'resource_usage', 'project', 'project_id', 'id');
+
+-- +migrate Down
+DROP TABLE IF EXISTS "resource_usage";
+ALTER TABLE "organization" DROP COLUMN IF EXISTS max_build_minutes;
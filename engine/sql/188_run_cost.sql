@@ -0,0 +1,17 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS "run_cost" (
+    workflow_node_run_id BIGINT PRIMARY KEY,
+    workflow_run_id BIGINT NOT NULL,
+    project_id BIGINT NOT NULL,
+    workflow_id BIGINT NOT NULL,
+    application_id BIGINT NOT NULL DEFAULT 0,
+    group_id BIGINT NOT NULL DEFAULT 0,
+    compute_seconds BIGINT NOT NULL DEFAULT 0,
+    artifact_size BIGINT NOT NULL DEFAULT 0,
+    created TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT LOCALTIMESTAMP
+);
+SELECT create_index('run_cost', 'IDX_RUN_COST_CREATED', 'created');
+SELECT create_foreign_key_idx_cascade('FK_RUN_COST_PROJECT', 'run_cost', 'project', 'project_id', 'id');
+
+-- +migrate Down
+DROP TABLE IF EXISTS "run_cost";
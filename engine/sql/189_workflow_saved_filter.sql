@@ -0,0 +1,14 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS "workflow_saved_filter" (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL,
+    project_id BIGINT NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    filter JSONB
+);
+SELECT create_unique_index('workflow_saved_filter', 'IDX_WORKFLOW_SAVED_FILTER_USER_PROJECT_NAME', 'user_id,project_id,name');
+SELECT create_foreign_key_idx_cascade('FK_WORKFLOW_SAVED_FILTER_USER', 'workflow_saved_filter', 'user', 'user_id', 'id');
+SELECT create_foreign_key_idx_cascade('FK_WORKFLOW_SAVED_FILTER_PROJECT', 'workflow_saved_filter', 'project', 'project_id', 'id');
+
+-- +migrate Down
+DROP TABLE IF EXISTS "workflow_saved_filter";
@@ -0,0 +1,32 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS "dashboard" (
+    id BIGSERIAL PRIMARY KEY,
+    name VARCHAR(256) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    owner_id BIGINT NOT NULL,
+    widgets JSONB,
+    created TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT LOCALTIMESTAMP,
+    last_modified TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT LOCALTIMESTAMP
+);
+SELECT create_foreign_key_idx_cascade('FK_DASHBOARD_USER', 'dashboard', 'user', 'owner_id', 'id');
+
+CREATE TABLE IF NOT EXISTS "dashboard_user" (
+    dashboard_id BIGINT NOT NULL,
+    user_id BIGINT NOT NULL,
+    PRIMARY KEY (dashboard_id, user_id)
+);
+SELECT create_foreign_key_idx_cascade('FK_DASHBOARD_USER_DASHBOARD', 'dashboard_user', 'dashboard', 'dashboard_id', 'id');
+SELECT create_foreign_key_idx_cascade('FK_DASHBOARD_USER_USER', 'dashboard_user', 'user', 'user_id', 'id');
+
+CREATE TABLE IF NOT EXISTS "dashboard_group" (
+    dashboard_id BIGINT NOT NULL,
+    group_id BIGINT NOT NULL,
+    PRIMARY KEY (dashboard_id, group_id)
+);
+SELECT create_foreign_key_idx_cascade('FK_DASHBOARD_GROUP_DASHBOARD', 'dashboard_group', 'dashboard', 'dashboard_id', 'id');
+SELECT create_foreign_key_idx_cascade('FK_DASHBOARD_GROUP_GROUP', 'dashboard_group', 'group', 'group_id', 'id');
+
+-- +migrate Down
+DROP TABLE IF EXISTS "dashboard_group";
+DROP TABLE IF EXISTS "dashboard_user";
+DROP TABLE IF EXISTS "dashboard";
@@ -0,0 +1,21 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS "deployment" (
+    id BIGSERIAL PRIMARY KEY,
+    project_id BIGINT NOT NULL,
+    application_id BIGINT NOT NULL,
+    environment_id BIGINT NOT NULL,
+    workflow_id BIGINT NOT NULL,
+    workflow_run_id BIGINT NOT NULL,
+    workflow_node_run_id BIGINT NOT NULL,
+    status VARCHAR(50) NOT NULL,
+    vcs_hash VARCHAR(256) NOT NULL DEFAULT '',
+    started TIMESTAMP WITH TIME ZONE,
+    done TIMESTAMP WITH TIME ZONE NOT NULL,
+    lead_time FLOAT NOT NULL DEFAULT -1
+);
+SELECT create_unique_index('deployment', 'IDX_DEPLOYMENT_NODE_RUN', 'workflow_node_run_id');
+SELECT create_index('deployment', 'IDX_DEPLOYMENT_APPLICATION_ENVIRONMENT', 'application_id,environment_id,done');
+SELECT create_foreign_key_idx_cascade('FK_DEPLOYMENT_PROJECT', 'deployment', 'project', 'project_id', 'id');
+
+-- +migrate Down
+DROP TABLE IF EXISTS "deployment";
@@ -0,0 +1,17 @@
+-- +migrate Up
+ALTER TABLE "deployment" ADD COLUMN IF NOT EXISTS version VARCHAR(256) NOT NULL DEFAULT '';
+ALTER TABLE "deployment" ADD COLUMN IF NOT EXISTS vcs_branch VARCHAR(256) NOT NULL DEFAULT '';
+ALTER TABLE "deployment" ADD COLUMN IF NOT EXISTS workflow_name VARCHAR(256) NOT NULL DEFAULT '';
+ALTER TABLE "deployment" ADD COLUMN IF NOT EXISTS workflow_node_name VARCHAR(256) NOT NULL DEFAULT '';
+ALTER TABLE "deployment" ADD COLUMN IF NOT EXISTS num BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE "deployment" ADD COLUMN IF NOT EXISTS url TEXT NOT NULL DEFAULT '';
+SELECT create_index('deployment', 'IDX_DEPLOYMENT_ENVIRONMENT_STATUS', 'environment_id,status,done');
+
+-- +migrate Down
+DROP INDEX IF EXISTS IDX_DEPLOYMENT_ENVIRONMENT_STATUS;
+ALTER TABLE "deployment" DROP COLUMN IF EXISTS version;
+ALTER TABLE "deployment" DROP COLUMN IF EXISTS vcs_branch;
+ALTER TABLE "deployment" DROP COLUMN IF EXISTS workflow_name;
+ALTER TABLE "deployment" DROP COLUMN IF EXISTS workflow_node_name;
+ALTER TABLE "deployment" DROP COLUMN IF EXISTS num;
+ALTER TABLE "deployment" DROP COLUMN IF EXISTS url;
@@ -0,0 +1,28 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS "environment_freeze" (
+    id BIGSERIAL PRIMARY KEY,
+    project_id BIGINT NOT NULL,
+    environment_id BIGINT NOT NULL,
+    type VARCHAR(20) NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    from_date TIMESTAMP WITH TIME ZONE,
+    to_date TIMESTAMP WITH TIME ZONE,
+    cron VARCHAR(256) NOT NULL DEFAULT '',
+    timezone VARCHAR(256) NOT NULL DEFAULT '',
+    duration BIGINT NOT NULL DEFAULT 0,
+    author VARCHAR(256) NOT NULL DEFAULT '',
+    created TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT LOCALTIMESTAMP
+);
+SELECT create_foreign_key_idx_cascade('FK_ENVIRONMENT_FREEZE_PROJECT', 'environment_freeze', 'project', 'project_id', 'id');
+SELECT create_foreign_key_idx_cascade('FK_ENVIRONMENT_FREEZE_ENVIRONMENT', 'environment_freeze', 'environment', 'environment_id', 'id');
+
+CREATE TABLE IF NOT EXISTS "environment_freeze_override" (
+    workflow_node_run_id BIGINT PRIMARY KEY,
+    author VARCHAR(256) NOT NULL DEFAULT '',
+    created TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT LOCALTIMESTAMP
+);
+SELECT create_foreign_key_idx_cascade('FK_ENVIRONMENT_FREEZE_OVERRIDE_NODE_RUN', 'environment_freeze_override', 'workflow_node_run', 'workflow_node_run_id', 'id');
+
+-- +migrate Down
+DROP TABLE IF EXISTS "environment_freeze_override";
+DROP TABLE IF EXISTS "environment_freeze";
@@ -0,0 +1,18 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS "deployment_rollback" (
+    id BIGSERIAL PRIMARY KEY,
+    project_id BIGINT NOT NULL,
+    deployment_id BIGINT NOT NULL,
+    workflow_node_run_id BIGINT NOT NULL,
+    artifacts JSONB,
+    author VARCHAR(256) NOT NULL DEFAULT '',
+    created TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now()
+);
+SELECT create_unique_index('deployment_rollback', 'IDX_DEPLOYMENT_ROLLBACK_NODE_RUN', 'workflow_node_run_id');
+SELECT create_foreign_key_idx_cascade('FK_DEPLOYMENT_ROLLBACK_PROJECT', 'deployment_rollback', 'project', 'project_id', 'id');
+SELECT create_foreign_key_idx_cascade('FK_DEPLOYMENT_ROLLBACK_DEPLOYMENT', 'deployment_rollback', 'deployment', 'deployment_id', 'id');
+ALTER TABLE "deployment" ADD COLUMN IF NOT EXISTS rollback_of BIGINT NOT NULL DEFAULT 0;
+
+-- +migrate Down
+ALTER TABLE "deployment" DROP COLUMN IF EXISTS rollback_of;
+DROP TABLE IF EXISTS "deployment_rollback";
@@ -0,0 +1,7 @@
+-- +migrate Up
+ALTER TABLE "worker_model" ADD COLUMN IF NOT EXISTS parent_id BIGINT NOT NULL DEFAULT 0;
+SELECT create_index('worker_model', 'IDX_WORKER_MODEL_PARENT', 'parent_id');
+
+-- +migrate Down
+DROP INDEX IF EXISTS IDX_WORKER_MODEL_PARENT;
+ALTER TABLE "worker_model" DROP COLUMN IF EXISTS parent_id;
@@ -0,0 +1,10 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS "worker_model_usage" (
+    worker_model_id BIGINT PRIMARY KEY,
+    jobs BIGINT NOT NULL DEFAULT 0,
+    last_used TIMESTAMP WITH TIME ZONE NOT NULL
+);
+SELECT create_foreign_key_idx_cascade('FK_WORKER_MODEL_USAGE_MODEL', 'worker_model_usage', 'worker_model', 'worker_model_id', 'id');
+
+-- +migrate Down
+DROP TABLE IF EXISTS "worker_model_usage";
@@ -0,0 +1,9 @@
+-- +migrate Up
+ALTER TABLE "worker" ADD COLUMN IF NOT EXISTS pool VARCHAR(256) NOT NULL DEFAULT '';
+ALTER TABLE "worker" ADD COLUMN IF NOT EXISTS capabilities JSONB;
+ALTER TABLE "worker" ADD COLUMN IF NOT EXISTS draining BOOLEAN NOT NULL DEFAULT false;
+
+-- +migrate Down
+ALTER TABLE "worker" DROP COLUMN IF EXISTS pool;
+ALTER TABLE "worker" DROP COLUMN IF EXISTS capabilities;
+ALTER TABLE "worker" DROP COLUMN IF EXISTS draining;
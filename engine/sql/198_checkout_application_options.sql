@@ -0,0 +1,10 @@
+-- +migrate Up
+
+INSERT into action_parameter (action_id, name, description, type, value, advanced) values((select id from action where name = 'CheckoutApplication' and type = 'Builtin'), 'depth', 'Depth of the clone, 50 by default. Set 0 to clone the full history.', 'string', '50', true);
+INSERT into action_parameter (action_id, name, description, type, value, advanced) values((select id from action where name = 'CheckoutApplication' and type = 'Builtin'), 'submodules', 'Clone the submodules recursively, with the credentials of the application.', 'boolean', 'true', true);
+INSERT into action_parameter (action_id, name, description, type, value, advanced) values((select id from action where name = 'CheckoutApplication' and type = 'Builtin'), 'lfs', 'Fetch the Git LFS files, git-lfs must be installed on the worker.', 'boolean', 'false', true);
+INSERT into action_parameter (action_id, name, description, type, value, advanced) values((select id from action where name = 'CheckoutApplication' and type = 'Builtin'), 'sparse', 'Directories to checkout, separated by commas or new lines. Empty: checkout the whole repository.', 'text', '', true);
+
+-- +migrate Down
+
+DELETE from action_parameter where name in ('depth', 'submodules', 'lfs', 'sparse') and action_id = (select id from action where name = 'CheckoutApplication' and type = 'Builtin');
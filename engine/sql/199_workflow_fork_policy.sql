@@ -0,0 +1,20 @@
+-- +migrate Up
+ALTER TABLE workflow ADD COLUMN IF NOT EXISTS fork_policy JSONB;
+CREATE TABLE IF NOT EXISTS "workflow_fork_approval" (
+    id BIGSERIAL PRIMARY KEY,
+    workflow_id BIGINT NOT NULL,
+    repository VARCHAR(256) NOT NULL,
+    branch VARCHAR(256) NOT NULL,
+    author VARCHAR(256) NOT NULL DEFAULT '',
+    created TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT LOCALTIMESTAMP,
+    approved BOOLEAN NOT NULL DEFAULT false,
+    approver VARCHAR(256) NOT NULL DEFAULT '',
+    approval_date TIMESTAMP WITH TIME ZONE,
+    hook_event JSONB
+);
+SELECT create_unique_index('workflow_fork_approval', 'IDX_WORKFLOW_FORK_APPROVAL_WORKFLOW_REPOSITORY_BRANCH', 'workflow_id,repository,branch');
+SELECT create_foreign_key_idx_cascade('FK_WORKFLOW_FORK_APPROVAL_WORKFLOW', 'workflow_fork_approval', 'workflow', 'workflow_id', 'id');
+
+-- +migrate Down
+DROP TABLE IF EXISTS "workflow_fork_approval";
+ALTER TABLE workflow DROP COLUMN IF EXISTS fork_policy;
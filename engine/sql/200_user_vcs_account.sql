@@ -0,0 +1,13 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS "user_vcs_account" (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL,
+    vcs_server VARCHAR(256) NOT NULL,
+    username VARCHAR(256) NOT NULL,
+    created TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT LOCALTIMESTAMP
+);
+SELECT create_unique_index('user_vcs_account', 'IDX_USER_VCS_ACCOUNT_VCS_SERVER_USERNAME', 'vcs_server,username');
+SELECT create_foreign_key_idx_cascade('FK_USER_VCS_ACCOUNT_USER', 'user_vcs_account', 'user', 'user_id', 'id');
+
+-- +migrate Down
+DROP TABLE IF EXISTS "user_vcs_account";
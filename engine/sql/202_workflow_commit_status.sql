@@ -0,0 +1,5 @@
+-- +migrate Up
+ALTER TABLE workflow ADD COLUMN IF NOT EXISTS commit_status JSONB;
+
+-- +migrate Down
+ALTER TABLE workflow DROP COLUMN IF EXISTS commit_status;
@@ -218,3 +218,58 @@ func (b *bitbucketClient) CommitsBetweenRefs(ctx context.Context, repo, base, he
 	}
 	return commits, nil
 }
+
+func (b *bitbucketClient) ChangedFiles(ctx context.Context, repo, base, head string) ([]sdk.VCSChangedFile, error) {
+	project, slug, err := getRepo(repo)
+	if err != nil {
+		return nil, sdk.WithStack(err)
+	}
+
+	var stashChanges []Change
+
+	var stashChangesKey = cache.Key("vcs", "bitbucket", b.consumer.URL, repo, "compare/changes", "from@"+base, "to@"+head)
+
+	if !b.consumer.cache.Get(stashChangesKey, &stashChanges) {
+		response := ChangesResponse{}
+		path := fmt.Sprintf("/projects/%s/repos/%s/compare/changes", project, slug)
+		params := url.Values{}
+		params.Add("from", head)
+		params.Add("to", base)
+
+		for {
+			if response.NextPageStart != 0 {
+				params.Set("start", fmt.Sprintf("%d", response.NextPageStart))
+			}
+
+			if err := b.do(ctx, "GET", "core", path, params, nil, &response, nil); err != nil {
+				if sdk.ErrorIs(err, sdk.ErrNotFound) {
+					return nil, nil
+				}
+				return nil, sdk.WrapError(err, "Unable to get changes %s", path)
+			}
+
+			stashChanges = append(stashChanges, response.Values...)
+			if response.IsLastPage {
+				break
+			}
+		}
+		b.consumer.cache.SetWithTTL(stashChangesKey, stashChanges, 3*60*60) //3 hours
+	}
+
+	files := make([]sdk.VCSChangedFile, len(stashChanges))
+	for i, c := range stashChanges {
+		files[i] = sdk.VCSChangedFile{
+			Filename: c.Path.ToString,
+			Status:   sdk.VCSChangedFileModified,
+		}
+		switch c.Type {
+		case "ADD", "COPY":
+			files[i].Status = sdk.VCSChangedFileAdded
+		case "DELETE":
+			files[i].Status = sdk.VCSChangedFileRemoved
+		case "MOVE":
+			files[i].Status = sdk.VCSChangedFileRenamed
+		}
+	}
+	return files, nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net/url"
 	"strings"
 
 	"github.com/ovh/cds/sdk"
@@ -28,8 +29,32 @@ func (b *bitbucketClient) getHooksConfig(ctx context.Context, repo string) (Hook
 }
 
 func (b *bitbucketClient) GetHook(ctx context.Context, repo, url string) (sdk.VCSHook, error) {
+	// the hooks of the workflows are webhooks
+	webhooks, err := b.getWebHooks(ctx, repo)
+	if err != nil {
+		return sdk.VCSHook{}, err
+	}
+	for _, h := range webhooks {
+		if h.URL == url {
+			return sdk.VCSHook{
+				Disable:  !h.Active,
+				Events:   h.Events,
+				ID:       fmt.Sprintf("%d", h.ID),
+				Name:     h.Name,
+				URL:      h.URL,
+				Workflow: true,
+				// bitbucket masks the secret of the webhooks
+				Secret: h.Configuration["secret"],
+			}, nil
+		}
+	}
+
 	hcfg, err := b.getHooksConfig(ctx, repo)
 	if err != nil {
+		// the legacy hooks plugin may not be installed
+		if sdk.ErrorIs(err, sdk.ErrNotFound) {
+			return sdk.VCSHook{}, sdk.ErrHookNotFound
+		}
 		return sdk.VCSHook{}, err
 	}
 
@@ -52,6 +77,33 @@ func (b *bitbucketClient) GetHook(ctx context.Context, repo, url string) (sdk.VC
 	return sdk.VCSHook{}, sdk.ErrHookNotFound
 }
 
+func (b *bitbucketClient) getWebHooks(ctx context.Context, repo string) ([]WebHook, error) {
+	project, slug, err := getRepo(repo)
+	if err != nil {
+		return nil, sdk.WithStack(err)
+	}
+
+	var webhooks []WebHook
+	path := fmt.Sprintf("/projects/%s/repos/%s/webhooks", project, slug)
+	params := url.Values{}
+	nextPage := 0
+	for {
+		if nextPage != 0 {
+			params.Set("start", fmt.Sprintf("%d", nextPage))
+		}
+		var response WebHookResponse
+		if err := b.do(ctx, "GET", "core", path, params, nil, &response, nil); err != nil {
+			return nil, sdk.WrapError(err, "Unable to get webhooks")
+		}
+		webhooks = append(webhooks, response.Values...)
+		if response.IsLastPage {
+			break
+		}
+		nextPage = response.NextPageStart
+	}
+	return webhooks, nil
+}
+
 func (b *bitbucketClient) CreateHook(ctx context.Context, repo string, hook *sdk.VCSHook) error {
 	project, slug, err := getRepo(repo)
 	if err != nil {
@@ -102,11 +154,14 @@ func (b *bitbucketClient) CreateHook(ctx context.Context, repo string, hook *sdk
 	url := fmt.Sprintf("/projects/%s/repos/%s/webhooks", project, slug)
 	request := WebHook{
 		URL:           hook.URL,
-		Events:        []string{"repo:refs_changed"},
+		Events:        sdk.BitbucketWebHookEvents,
 		Active:        true,
 		Name:          repo,
 		Configuration: make(map[string]string),
 	}
+	if hook.Secret != "" {
+		request.Configuration["secret"] = hook.Secret
+	}
 
 	values, err := json.Marshal(&request)
 	if err != nil {
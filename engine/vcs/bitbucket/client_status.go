@@ -5,7 +5,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/url"
-	"strings"
 	"time"
 
 	"github.com/mitchellh/mapstructure"
@@ -89,9 +88,6 @@ func (b *bitbucketClient) ListStatuses(ctx context.Context, repo string, ref str
 
 	vcsStatuses := []sdk.VCSCommitStatus{}
 	for _, s := range ss {
-		if !strings.HasPrefix(s.Description, "CDS/") {
-			continue
-		}
 		vcsStatuses = append(vcsStatuses, sdk.VCSCommitStatus{
 			CreatedAt:  time.Unix(s.Timestamp/1000, 0),
 			Decription: s.Description,
@@ -131,6 +127,9 @@ func processWorkflowNodeRunEvent(event sdk.Event, uiURL string) (statusData, err
 		event.WorkflowName,
 		eventNR.NodeName,
 	)
+	if eventNR.StatusContext != "" {
+		data.key = eventNR.StatusContext
+	}
 	data.url = fmt.Sprintf("%s/project/%s/workflow/%s/run/%d",
 		uiURL,
 		event.ProjectKey,
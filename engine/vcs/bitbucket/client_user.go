@@ -19,3 +19,12 @@ func (b *bitbucketClient) findByEmail(ctx context.Context, email string) (*User,
 	}
 	return nil, fmt.Errorf("User not found")
 }
+
+// CurrentUser returns the user of the access token
+func (b *bitbucketClient) CurrentUser(ctx context.Context) (sdk.VCSAuthor, error) {
+	var user User
+	if err := b.do(ctx, "GET", "core", "username", nil, nil, &user, nil); err != nil {
+		return sdk.VCSAuthor{}, sdk.WrapError(err, "cannot get current user")
+	}
+	return sdk.VCSAuthor{Name: user.Username}, nil
+}
@@ -24,6 +24,13 @@ type WebHook struct {
 	URL           string            `json:"url"`
 }
 
+type WebHookResponse struct {
+	Values        []WebHook `json:"values"`
+	Size          int       `json:"size"`
+	NextPageStart int       `json:"nextPageStart"`
+	IsLastPage    bool      `json:"isLastPage"`
+}
+
 type Branch struct {
 	ID         string `json:"id"`
 	DisplayID  string `json:"displayId"`
@@ -64,6 +71,20 @@ type CommitsResponse struct {
 	IsLastPage    bool     `json:"isLastPage"`
 }
 
+type ChangesResponse struct {
+	Values        []Change `json:"values"`
+	Size          int      `json:"size"`
+	NextPageStart int      `json:"nextPageStart"`
+	IsLastPage    bool     `json:"isLastPage"`
+}
+
+type Change struct {
+	Type string `json:"type"`
+	Path struct {
+		ToString string `json:"toString"`
+	} `json:"path"`
+}
+
 type Commit struct {
 	Hash      string  `json:"id"`
 	Author    *Author `json:"author"`
@@ -0,0 +1,277 @@
+package github
+
+import (
+	"bytes"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	jwt "github.com/dgrijalva/jwt-go"
+
+	"github.com/ovh/cds/engine/vcs/ratelimit"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// App is a Github App installed on the organizations and the users of the repositories used by CDS.
+// The API calls on the repositories which the OAuth token can push to are authenticated with installation tokens instead
+// of the OAuth token, which gives a rate limit per installation and only the permissions granted to the App.
+// doc: https://developer.github.com/apps/building-github-apps/authenticating-with-github-apps/
+type App struct {
+	ID int64
+	// Permissions requested for the installation tokens, all the permissions of the App if empty
+	Permissions map[string]string
+
+	privateKey *rsa.PrivateKey
+	apiURL     string
+
+	mutex  sync.Mutex
+	tokens map[int64]installationToken
+	// installations of the App by full name of the repository, 0 if the App is not installed on the repository
+	installations map[string]cachedInstallation
+	// repositories which an OAuth token can push to, by hash of the token and full name of the repository
+	pushAccesses map[string]time.Time
+}
+
+type installationToken struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+type cachedInstallation struct {
+	ID        int64
+	ExpiresAt time.Time
+}
+
+const (
+	// installationTokenRenewal is the delay before the expiration of an installation token from which a new token is requested
+	installationTokenRenewal = 5 * time.Minute
+	// appCacheTTL is the duration the installations on the repositories and the push accesses of the OAuth tokens are kept
+	appCacheTTL = 10 * time.Minute
+)
+
+// NewApp returns a Github App from its ID and its PEM encoded private key
+func NewApp(id int64, privateKey []byte, permissions map[string]string) (*App, error) {
+	key, err := jwt.ParseRSAPrivateKeyFromPEM(privateKey)
+	if err != nil {
+		return nil, sdk.WrapError(err, "invalid private key for github app %d", id)
+	}
+	return &App{
+		ID:            id,
+		Permissions:   permissions,
+		privateKey:    key,
+		apiURL:        APIURL,
+		tokens:        map[int64]installationToken{},
+		installations: map[string]cachedInstallation{},
+		pushAccesses:  map[string]time.Time{},
+	}, nil
+}
+
+// jwt returns a token signed with the private key of the App, used to request the installation tokens
+func (a *App) jwt() (string, error) {
+	now := time.Now()
+	claims := jwt.StandardClaims{
+		// Github rejects the tokens issued in the future, let some room for clock drift
+		IssuedAt:  now.Add(-time.Minute).Unix(),
+		ExpiresAt: now.Add(9 * time.Minute).Unix(),
+		Issuer:    strconv.FormatInt(a.ID, 10),
+	}
+	s, err := jwt.NewWithClaims(jwt.SigningMethodRS256, claims).SignedString(a.privateKey)
+	if err != nil {
+		return "", sdk.WrapError(err, "cannot sign token for github app %d", a.ID)
+	}
+	return s, nil
+}
+
+// do calls the Github API as the App
+func (a *App) do(method, path string, body interface{}, out interface{}) (int, error) {
+	jwtToken, err := a.jwt()
+	if err != nil {
+		return 0, err
+	}
+	return a.doWithAuthorization(method, path, "Bearer "+jwtToken, fmt.Sprintf("%s-app-%d", budgetName, a.ID), body, out)
+}
+
+// doWithAuthorization calls the Github API with the given Authorization header, counted in the given rate limit budget
+// if any
+func (a *App) doWithAuthorization(method, path, authorization, budget string, body interface{}, out interface{}) (int, error) {
+	var reqBody []byte
+	if body != nil {
+		var err error
+		reqBody, err = json.Marshal(body)
+		if err != nil {
+			return 0, sdk.WithStack(err)
+		}
+	}
+
+	req, err := http.NewRequest(method, a.apiURL+path, bytes.NewReader(reqBody))
+	if err != nil {
+		return 0, sdk.WithStack(err)
+	}
+	req.Header.Set("User-Agent", fmt.Sprintf("CDS-gh_app_id=%d", a.ID))
+	req.Header.Set("Accept", "application/vnd.github.machine-man-preview+json")
+	req.Header.Set("Authorization", authorization)
+	req = ratelimit.WithBudget(req, budget)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	log.Debug("Github API>> App request URL %s", req.URL.String())
+
+	res, err := httpClient.Do(req)
+	if err != nil {
+		return 0, sdk.WrapError(err, "cannot do request %s %s", method, path)
+	}
+	defer res.Body.Close()
+
+	resBody, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return res.StatusCode, sdk.WithStack(err)
+	}
+	if res.StatusCode >= 400 {
+		return res.StatusCode, fmt.Errorf("github app> %s %s: %v", method, path, errorAPI(resBody))
+	}
+	if out != nil {
+		if err := json.Unmarshal(resBody, out); err != nil {
+			return res.StatusCode, sdk.WrapError(err, "cannot unmarshal github response")
+		}
+	}
+	return res.StatusCode, nil
+}
+
+// installationToken returns a token for the installation, kept until a few minutes before it expires
+func (a *App) installationToken(installationID int64) (string, error) {
+	a.mutex.Lock()
+	t, ok := a.tokens[installationID]
+	a.mutex.Unlock()
+	if ok && time.Now().Add(installationTokenRenewal).Before(t.ExpiresAt) {
+		return t.Token, nil
+	}
+
+	var body interface{}
+	if len(a.Permissions) > 0 {
+		body = map[string]interface{}{"permissions": a.Permissions}
+	}
+
+	if _, err := a.do(http.MethodPost, fmt.Sprintf("/app/installations/%d/access_tokens", installationID), body, &t); err != nil {
+		return "", err
+	}
+
+	a.mutex.Lock()
+	a.tokens[installationID] = t
+	a.mutex.Unlock()
+	return t.Token, nil
+}
+
+// installationID returns the installation of the App on the repository if the OAuth token can push to it, 0 if
+// the App is not installed on the repository or if the token cannot push to it. The installation is looked up first,
+// so that the push access of the token is only checked on the repositories of the installations.
+func (a *App) installationID(oauthToken, owner, repo string) (int64, error) {
+	fullname := owner + "/" + repo
+
+	a.mutex.Lock()
+	i, ok := a.installations[fullname]
+	a.mutex.Unlock()
+	if !ok || time.Now().After(i.ExpiresAt) {
+		var installation struct {
+			ID int64 `json:"id"`
+		}
+		status, err := a.do(http.MethodGet, fmt.Sprintf("/repos/%s/installation", fullname), nil, &installation)
+		if err != nil && status != http.StatusNotFound {
+			return 0, err
+		}
+
+		// an App installed on selected repositories only is not installed on the other repositories of the owner
+		i = cachedInstallation{ID: installation.ID, ExpiresAt: time.Now().Add(appCacheTTL)}
+		a.mutex.Lock()
+		a.installations[fullname] = i
+		a.mutex.Unlock()
+	}
+	if i.ID == 0 {
+		return 0, nil
+	}
+
+	push, err := a.canPush(oauthToken, owner, repo)
+	if err != nil || !push {
+		return 0, err
+	}
+	return i.ID, nil
+}
+
+// canPush returns true if the OAuth token can push to the repository. The accesses are kept for a while, a repository
+// which the token cannot push to is checked again on the next call.
+func (a *App) canPush(oauthToken, owner, repo string) (bool, error) {
+	h := sha256.Sum256([]byte(oauthToken + "/" + owner + "/" + repo))
+	k := hex.EncodeToString(h[:])
+
+	a.mutex.Lock()
+	expiresAt, ok := a.pushAccesses[k]
+	a.mutex.Unlock()
+	if ok && time.Now().Before(expiresAt) {
+		return true, nil
+	}
+
+	var r struct {
+		Permissions struct {
+			Push bool `json:"push"`
+		} `json:"permissions"`
+	}
+	status, err := a.doWithAuthorization(http.MethodGet, fmt.Sprintf("/repos/%s/%s", owner, repo), "token "+oauthToken, "", nil, &r)
+	if status == http.StatusNotFound {
+		return false, nil
+	}
+	if err != nil || !r.Permissions.Push {
+		return false, err
+	}
+
+	a.mutex.Lock()
+	a.pushAccesses[k] = time.Now().Add(appCacheTTL)
+	a.mutex.Unlock()
+	return true, nil
+}
+
+// authorization returns the value of the Authorization header for a call on the path, and the name of the rate limit
+// budget of the call if it is not the budget of its credential.
+// The calls on the repositories where the App is installed, and which the OAuth token can push to, use the
+// token of the installation, counted in the budget of the installation: its tokens are renewed every hour. The other
+// calls use the OAuth token.
+func (c *githubClient) authorization(path string) (string, string) {
+	oauth := fmt.Sprintf("token %s", c.OAuthToken)
+	if c.app == nil {
+		return oauth, ""
+	}
+
+	path = strings.TrimPrefix(path, APIURL)
+	if !strings.HasPrefix(path, "/repos/") {
+		return oauth, ""
+	}
+	s := strings.SplitN(strings.TrimPrefix(path, "/repos/"), "/", 3)
+	if len(s) < 2 || s[0] == "" || s[1] == "" {
+		return oauth, ""
+	}
+	owner, repo := s[0], strings.SplitN(s[1], "?", 2)[0]
+
+	installationID, err := c.app.installationID(c.OAuthToken, owner, repo)
+	if err != nil {
+		log.Warning("githubClient.authorization> cannot get installation of github app %d on %s/%s: %v", c.app.ID, owner, repo, err)
+		return oauth, ""
+	}
+	if installationID == 0 {
+		return oauth, ""
+	}
+
+	token, err := c.app.installationToken(installationID)
+	if err != nil {
+		log.Warning("githubClient.authorization> cannot get token of installation %d of github app %d: %v", installationID, c.app.ID, err)
+		return oauth, ""
+	}
+	return fmt.Sprintf("token %s", token), fmt.Sprintf("%s-installation-%d", budgetName, installationID)
+}
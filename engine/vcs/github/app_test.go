@@ -0,0 +1,120 @@
+package github
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	jwt "github.com/dgrijalva/jwt-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAppAuthorization(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	privateKey := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+
+	var tokenRequests, installationRequests, pushRequests int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// the permissions of the oauth token on the repositories
+		if r.Header.Get("Authorization") == "token oauth" {
+			pushRequests++
+			switch r.URL.Path {
+			case "/repos/ovh/cds", "/repos/ovh/venom", "/repos/ovh/selected", "/repos/fsamin/cds":
+				json.NewEncoder(w).Encode(map[string]interface{}{"permissions": map[string]bool{"push": true, "pull": true}})
+			case "/repos/ovh/public":
+				json.NewEncoder(w).Encode(map[string]interface{}{"permissions": map[string]bool{"push": false, "pull": true}})
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+			return
+		}
+
+		// the app is authenticated with a token signed with its private key
+		token, err := jwt.ParseWithClaims(strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer "), &jwt.StandardClaims{}, func(*jwt.Token) (interface{}, error) {
+			return &key.PublicKey, nil
+		})
+		if err != nil || token.Claims.(*jwt.StandardClaims).Issuer != "12" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		switch r.URL.Path {
+		case "/repos/ovh/cds/installation", "/repos/ovh/venom/installation", "/repos/ovh/public/installation":
+			json.NewEncoder(w).Encode(map[string]int64{"id": 42})
+		case "/repos/ovh/selected/installation", "/repos/fsamin/cds/installation":
+			installationRequests++
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"message":"Not Found"}`))
+		case "/app/installations/42/access_tokens":
+			tokenRequests++
+			var body struct {
+				Permissions map[string]string `json:"permissions"`
+			}
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			assert.Equal(t, "write", body.Permissions["statuses"])
+			json.NewEncoder(w).Encode(installationToken{
+				Token:     "installation-" + strings.Split(r.URL.Path, "/")[3],
+				ExpiresAt: time.Now().Add(time.Hour),
+			})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	app, err := NewApp(12, privateKey, map[string]string{"statuses": "write"})
+	assert.NoError(t, err)
+	app.apiURL = srv.URL
+	c := &githubClient{OAuthToken: "oauth", app: app}
+
+	// the calls on a repository where the app is installed use the token of the installation, which is reused, in the
+	// rate limit budget of the installation
+	_, budget := c.authorization(APIURL + "/repos/ovh/cds/pulls")
+	assert.Equal(t, "Github-installation-42", budget)
+	_, budget = c.authorization(APIURL + "/user/repos")
+	assert.Equal(t, "", budget)
+	assert.Equal(t, "token installation-42", authorizationHeader(c, APIURL+"/repos/ovh/cds/pulls"))
+	assert.Equal(t, "token installation-42", authorizationHeader(c, APIURL+"/repos/ovh/venom?page=2"))
+	assert.Equal(t, 1, tokenRequests)
+	assert.Equal(t, 2, pushRequests)
+
+	// the repositories of the installation which the oauth token cannot push to use the oauth token
+	assert.Equal(t, "token oauth", authorizationHeader(c, APIURL+"/repos/ovh/public/pulls"))
+	assert.Equal(t, "token oauth", authorizationHeader(c, APIURL+"/repos/ovh/private/pulls"))
+
+	// the repositories of an owner which are not selected in the installation use the oauth token, the push access
+	// of the token is not checked on the repositories without installation
+	pushRequests = 0
+	assert.Equal(t, "token oauth", authorizationHeader(c, APIURL+"/repos/ovh/selected/pulls"))
+	assert.Equal(t, "token oauth", authorizationHeader(c, APIURL+"/repos/fsamin/cds/pulls"))
+	assert.Equal(t, "token oauth", authorizationHeader(c, APIURL+"/repos/fsamin/cds/pulls"))
+	assert.Equal(t, 2, installationRequests)
+	assert.Equal(t, 0, pushRequests)
+	assert.Equal(t, "token oauth", authorizationHeader(c, APIURL+"/user/repos"))
+
+	// an expiring token is renewed, an expired installation is looked up again
+	app.tokens[42] = installationToken{Token: "old", ExpiresAt: time.Now().Add(time.Minute)}
+	app.installations["ovh/cds"] = cachedInstallation{ID: 1, ExpiresAt: time.Now().Add(-time.Second)}
+	assert.Equal(t, "token installation-42", authorizationHeader(c, APIURL+"/repos/ovh/cds/pulls"))
+	assert.Equal(t, 2, tokenRequests)
+
+	// without app the oauth token is always used
+	assert.Equal(t, "token oauth", authorizationHeader(&githubClient{OAuthToken: "oauth"}, APIURL+"/repos/ovh/cds/pulls"))
+
+	_, err = NewApp(12, []byte("not a key"), nil)
+	assert.Error(t, err)
+}
+
+// authorizationHeader returns the Authorization header of a call on the path
+func authorizationHeader(c *githubClient, path string) string {
+	authorization, _ := c.authorization(path)
+	return authorization
+}
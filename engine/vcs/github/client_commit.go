@@ -302,3 +302,124 @@ func (g *githubClient) CommitsBetweenRefs(ctx context.Context, repo, base, head
 
 	return commits, nil
 }
+
+func (g *githubClient) ChangedFiles(ctx context.Context, repo, base, head string) ([]sdk.VCSChangedFile, error) {
+	var files []sdk.VCSChangedFile
+	url := fmt.Sprintf("/repos/%s/compare/%s...%s", repo, base, head)
+	status, body, _, err := g.get(url)
+	if err != nil {
+		log.Warning("githubClient.ChangedFiles> Error %s", err)
+		return files, err
+	}
+	if status >= 400 {
+		return files, sdk.NewError(sdk.ErrRepoNotFound, errorAPI(body))
+	}
+
+	//Github may return 304 status because we are using conditional request with ETag based headers
+	if status == http.StatusNotModified {
+		//If repo isn't updated, lets get them from cache
+		g.Cache.Get(cache.Key("vcs", "github", "changedfiles", g.OAuthToken, url), &files)
+		return files, nil
+	}
+
+	var diff DiffCommits
+	if err := json.Unmarshal(body, &diff); err != nil {
+		log.Warning("githubClient.ChangedFiles> Unable to parse github diff: %s", err)
+		return files, err
+	}
+
+	files = make([]sdk.VCSChangedFile, len(diff.Files))
+	for i, f := range diff.Files {
+		files[i] = sdk.VCSChangedFile{
+			Filename: f.Filename,
+			Status:   f.Status,
+		}
+	}
+	// The compare api lists at most 300 files, the files are then listed commit by commit
+	if len(diff.Files) >= compareFilesLimit {
+		files, err = g.commitsChangedFiles(repo, base, head)
+		if err != nil {
+			return nil, err
+		}
+	}
+	//Put the body on cache for one hour and one minute
+	g.Cache.SetWithTTL(cache.Key("vcs", "github", "changedfiles", g.OAuthToken, url), &files, 61*60)
+
+	return files, nil
+}
+
+// compareFilesLimit is the maximum number of files listed by the compare api
+const compareFilesLimit = 300
+
+// commitsChangedFiles lists the files modified by each commit between base and head. The commits of the comparison
+// and the files of each commit are paginated.
+func (g *githubClient) commitsChangedFiles(repo, base, head string) ([]sdk.VCSChangedFile, error) {
+	var shas []string
+	nextPage := fmt.Sprintf("/repos/%s/compare/%s...%s?per_page=100", repo, base, head)
+	for nextPage != "" {
+		status, body, headers, err := g.get(nextPage, withoutETag)
+		if err != nil {
+			log.Warning("githubClient.commitsChangedFiles> Error %s", err)
+			return nil, err
+		}
+		if status >= 400 {
+			return nil, sdk.NewError(sdk.ErrRepoNotFound, errorAPI(body))
+		}
+		var diff DiffCommits
+		if err := json.Unmarshal(body, &diff); err != nil {
+			log.Warning("githubClient.commitsChangedFiles> Unable to parse github diff: %s", err)
+			return nil, err
+		}
+		for _, c := range diff.Commits {
+			shas = append(shas, c.Sha)
+		}
+		nextPage = getNextPage(headers)
+	}
+
+	var lists [][]sdk.VCSChangedFile
+	for _, sha := range shas {
+		var files []sdk.VCSChangedFile
+		nextPage := fmt.Sprintf("/repos/%s/commits/%s?per_page=100", repo, sha)
+		for nextPage != "" {
+			status, body, headers, err := g.get(nextPage, withoutETag)
+			if err != nil {
+				log.Warning("githubClient.commitsChangedFiles> Error %s", err)
+				return nil, err
+			}
+			if status >= 400 {
+				return nil, sdk.NewError(sdk.ErrRepoNotFound, errorAPI(body))
+			}
+			var commit struct {
+				Files []sdk.VCSChangedFile `json:"files"`
+			}
+			if err := json.Unmarshal(body, &commit); err != nil {
+				log.Warning("githubClient.commitsChangedFiles> Unable to parse github commit: %s", err)
+				return nil, err
+			}
+			files = append(files, commit.Files...)
+			nextPage = getNextPage(headers)
+		}
+		lists = append(lists, files)
+	}
+	return mergeChangedFiles(lists), nil
+}
+
+// mergeChangedFiles merges the files modified by successive commits, the status of a file is its last status except
+// for a file added then modified
+func mergeChangedFiles(lists [][]sdk.VCSChangedFile) []sdk.VCSChangedFile {
+	res := []sdk.VCSChangedFile{}
+	index := map[string]int{}
+	for _, files := range lists {
+		for _, f := range files {
+			if i, has := index[f.Filename]; has {
+				if !(res[i].Status == sdk.VCSChangedFileAdded && f.Status == sdk.VCSChangedFileModified) {
+					res[i].Status = f.Status
+				}
+				continue
+			}
+			index[f.Filename] = len(res)
+			res = append(res, f)
+		}
+	}
+	return res
+}
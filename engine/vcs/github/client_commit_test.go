@@ -5,6 +5,8 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/sdk"
 )
 
 func Test_filterCommits(t *testing.T) {
@@ -2191,3 +2193,16 @@ const data = `
   }
 ]
 `
+
+func Test_mergeChangedFiles(t *testing.T) {
+	files := mergeChangedFiles([][]sdk.VCSChangedFile{
+		{{Filename: "README.md", Status: sdk.VCSChangedFileAdded}, {Filename: "main.go", Status: sdk.VCSChangedFileModified}},
+		{{Filename: "README.md", Status: sdk.VCSChangedFileModified}, {Filename: "old.go", Status: sdk.VCSChangedFileRemoved}},
+		{{Filename: "main.go", Status: sdk.VCSChangedFileRemoved}},
+	})
+	assert.Equal(t, []sdk.VCSChangedFile{
+		{Filename: "README.md", Status: sdk.VCSChangedFileAdded},
+		{Filename: "main.go", Status: sdk.VCSChangedFileRemoved},
+		{Filename: "old.go", Status: sdk.VCSChangedFileRemoved},
+	}, files)
+}
@@ -24,10 +24,11 @@ func (g *githubClient) CreateHook(ctx context.Context, repo string, hook *sdk.VC
 	r := WebhookCreate{
 		Name:   "web",
 		Active: true,
-		Events: []string{"push"},
+		Events: sdk.GitHubWebHookEvents,
 		Config: WebHookConfig{
 			URL:         hook.URL,
 			ContentType: "json",
+			Secret:      hook.Secret,
 		},
 	}
 	b, err := json.Marshal(r)
@@ -57,9 +58,44 @@ func (g *githubClient) CreateHook(ctx context.Context, repo string, hook *sdk.VC
 	hook.ID = fmt.Sprintf("%d", r.ID)
 	return nil
 }
-func (g *githubClient) GetHook(ctx context.Context, repo, id string) (sdk.VCSHook, error) {
-	return sdk.VCSHook{}, fmt.Errorf("Not yet implemented")
+
+// GetHook returns the webhook of the repository calling the given url
+func (g *githubClient) GetHook(ctx context.Context, repo, url string) (sdk.VCSHook, error) {
+	var nextPage = "/repos/" + repo + "/hooks"
+	for nextPage != "" {
+		status, body, headers, err := g.get(nextPage, withoutETag)
+		if err != nil {
+			return sdk.VCSHook{}, sdk.WrapError(err, "github.GetHook")
+		}
+		if status >= 400 {
+			return sdk.VCSHook{}, sdk.NewError(sdk.ErrRepoNotFound, errorAPI(body))
+		}
+
+		var hooks []WebhookCreate
+		if err := json.Unmarshal(body, &hooks); err != nil {
+			return sdk.VCSHook{}, sdk.WrapError(err, "Cannot unmarshal response")
+		}
+		for _, h := range hooks {
+			if h.Config.URL != url {
+				continue
+			}
+			return sdk.VCSHook{
+				ID:          fmt.Sprintf("%d", h.ID),
+				Name:        h.Name,
+				Events:      h.Events,
+				URL:         h.Config.URL,
+				ContentType: h.Config.ContentType,
+				Disable:     !h.Active,
+				Workflow:    true,
+				// github masks the secret of the webhooks
+				Secret: h.Config.Secret,
+			}, nil
+		}
+		nextPage = getNextPage(headers)
+	}
+	return sdk.VCSHook{}, sdk.WithStack(sdk.ErrHookNotFound)
 }
+
 func (g *githubClient) UpdateHook(ctx context.Context, repo, id string, hook sdk.VCSHook) error {
 	return fmt.Errorf("Not yet implemented")
 }
@@ -2,18 +2,19 @@ package github
 
 import (
 	"encoding/json"
-	"time"
 
+	"github.com/ovh/cds/engine/vcs/ratelimit"
 	"github.com/ovh/cds/sdk"
 	"github.com/ovh/cds/sdk/log"
 )
 
-func isRateLimitReached() bool {
-	if RateLimitReset > 0 && RateLimitReset < int(time.Now().Unix()) {
-		log.Debug("RateLimitReset reached, it's ok to call github")
-		return false
+// isRateLimitReached returns true if the calls with the Authorization header would exceed the rate limit of their
+// credential, or of the named budget if any
+func isRateLimitReached(authorization, budget string) bool {
+	if budget != "" {
+		return ratelimit.Get(budget, budgetThreshold).Allow() != nil
 	}
-	return RateLimitRemaining < 100
+	return ratelimit.CredentialBudget(budgetName, budgetThreshold, authorization).Allow() != nil
 }
 
 // RateLimit Get your current rate limit status
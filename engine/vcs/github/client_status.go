@@ -7,7 +7,6 @@ import (
 	"fmt"
 	"io/ioutil"
 	"net/http"
-	"strings"
 
 	"github.com/mitchellh/mapstructure"
 
@@ -118,9 +117,6 @@ func (g *githubClient) ListStatuses(ctx context.Context, repo string, ref string
 
 	vcsStatuses := []sdk.VCSCommitStatus{}
 	for _, s := range ss {
-		if !strings.HasPrefix(s.Context, "CDS/") {
-			continue
-		}
 		vcsStatuses = append(vcsStatuses, sdk.VCSCommitStatus{
 			CreatedAt:  s.CreatedAt,
 			Decription: s.Context,
@@ -185,5 +181,8 @@ func processEventWorkflowNodeRun(event sdk.Event, githubURL string, disabledStat
 
 	data.context = sdk.VCSCommitStatusDescription(event.ProjectKey, event.WorkflowName, eventNR)
 	data.desc = eventNR.NodeName + ": " + eventNR.Status
+	if eventNR.StatusAggregate {
+		data.desc = event.WorkflowName + ": " + eventNR.Status
+	}
 	return data, nil
 }
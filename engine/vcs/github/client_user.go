@@ -38,3 +38,26 @@ func (g *githubClient) User(ctx context.Context, username string) (User, error)
 
 	return user, nil
 }
+
+// CurrentUser returns the user of the OAuth token
+// https://developer.github.com/v3/users/#get-the-authenticated-user
+func (g *githubClient) CurrentUser(ctx context.Context) (sdk.VCSAuthor, error) {
+	status, body, _, err := g.get("/user", withoutETag)
+	if err != nil {
+		log.Warning("githubClient.CurrentUser> Error %s", err)
+		return sdk.VCSAuthor{}, err
+	}
+	if status >= 400 {
+		return sdk.VCSAuthor{}, sdk.NewError(sdk.ErrUserNotFound, errorAPI(body))
+	}
+	var user User
+	if err := json.Unmarshal(body, &user); err != nil {
+		return sdk.VCSAuthor{}, sdk.WithStack(err)
+	}
+	return sdk.VCSAuthor{
+		Name:        user.Login,
+		DisplayName: user.Name,
+		Email:       user.Email,
+		Avatar:      user.AvatarURL,
+	}, nil
+}
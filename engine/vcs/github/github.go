@@ -17,6 +17,7 @@ type githubClient struct {
 	proxyURL            string
 	username            string
 	token               string
+	app                 *App
 }
 
 //GithubConsumer implements vcs.Server and it's used to instanciate a githubClient
@@ -31,10 +32,11 @@ type githubConsumer struct {
 	disableStatusDetail bool
 	username            string
 	token               string
+	app                 *App
 }
 
-//New creates a new GithubConsumer
-func New(ClientID, ClientSecret string, apiURL, uiURL, proxyURL, username, token string, store cache.Store, disableStatus, disableStatusDetail bool) sdk.VCSServer {
+//New creates a new GithubConsumer, app is optional
+func New(ClientID, ClientSecret string, apiURL, uiURL, proxyURL, username, token string, app *App, store cache.Store, disableStatus, disableStatusDetail bool) sdk.VCSServer {
 	return &githubConsumer{
 		ClientID:            ClientID,
 		ClientSecret:        ClientSecret,
@@ -46,5 +48,6 @@ func New(ClientID, ClientSecret string, apiURL, uiURL, proxyURL, username, token
 		disableStatusDetail: disableStatusDetail,
 		username:            username,
 		token:               token,
+		app:                 app,
 	}
 }
@@ -8,26 +8,34 @@ import (
 	"net/http"
 	"net/url"
 	"regexp"
-	"strconv"
 	"strings"
 	"time"
 
 	"github.com/ovh/cds/engine/api/cache"
+	"github.com/ovh/cds/engine/vcs/ratelimit"
 	"github.com/ovh/cds/sdk/cdsclient"
 	"github.com/ovh/cds/sdk/log"
 
 	"github.com/ovh/cds/sdk"
 )
 
+// the rate limit of Github is tracked per credential, the calls are refused when less than 100 calls remain
+const (
+	budgetName      = "Github"
+	budgetThreshold = 100
+)
+
 //Github http var
 var (
-	RateLimitLimit     int
-	RateLimitRemaining = 5000
-	RateLimitReset     int
-
-	httpClient = cdsclient.NewHTTPClient(time.Second*30, false)
+	httpClient = newHTTPClient()
 )
 
+func newHTTPClient() *http.Client {
+	c := cdsclient.NewHTTPClient(time.Second*30, false)
+	c.Transport = &ratelimit.Transport{Base: c.Transport, Name: budgetName, Threshold: budgetThreshold}
+	return c
+}
+
 func (g *githubConsumer) postForm(path string, data url.Values, headers map[string][]string) (int, []byte, error) {
 	body := strings.NewReader(data.Encode())
 
@@ -137,7 +145,9 @@ func (c *githubClient) post(path string, bodyType string, body io.Reader, opts *
 	if opts.asUser && c.token != "" {
 		req.SetBasicAuth(c.username, c.token)
 	} else {
-		req.Header.Add("Authorization", fmt.Sprintf("token %s", c.OAuthToken))
+		authorization, budget := c.authorization(path)
+		req.Header.Add("Authorization", authorization)
+		req = ratelimit.WithBudget(req, budget)
 	}
 
 	log.Debug("Github API>> Request URL %s", req.URL.String())
@@ -163,7 +173,9 @@ func (c *githubClient) patch(path string, opts *postOptions) (*http.Response, er
 	if opts.asUser && c.token != "" {
 		req.SetBasicAuth(c.username, c.token)
 	} else {
-		req.Header.Add("Authorization", fmt.Sprintf("token %s", c.OAuthToken))
+		authorization, budget := c.authorization(path)
+		req.Header.Add("Authorization", authorization)
+		req = ratelimit.WithBudget(req, budget)
 	}
 
 	log.Debug("Github API>> Request URL %s", req.URL.String())
@@ -190,7 +202,9 @@ func (c *githubClient) put(path string, bodyType string, body io.Reader, opts *p
 	if opts.asUser && c.token != "" {
 		req.SetBasicAuth(c.username, c.token)
 	} else {
-		req.Header.Add("Authorization", fmt.Sprintf("token %s", c.OAuthToken))
+		authorization, budget := c.authorization(path)
+		req.Header.Add("Authorization", authorization)
+		req = ratelimit.WithBudget(req, budget)
 	}
 
 	log.Debug("Github API>> Request URL %s", req.URL.String())
@@ -199,14 +213,15 @@ func (c *githubClient) put(path string, bodyType string, body io.Reader, opts *p
 }
 
 func (c *githubClient) get(path string, opts ...getArgFunc) (int, []byte, http.Header, error) {
-	if isRateLimitReached() {
-		return 0, nil, nil, ErrorRateLimit
-	}
-
 	if !strings.HasPrefix(path, APIURL) {
 		path = APIURL + path
 	}
 
+	authorization, budget := c.authorization(path)
+	if isRateLimitReached(authorization, budget) {
+		return 0, nil, nil, ErrorRateLimit
+	}
+
 	callURL, err := url.ParseRequestURI(path)
 	if err != nil {
 		return 0, nil, nil, err
@@ -219,7 +234,8 @@ func (c *githubClient) get(path string, opts ...getArgFunc) (int, []byte, http.H
 
 	req.Header.Set("User-Agent", "CDS-gh_client_id="+c.ClientID)
 	req.Header.Add("Accept", "application/json")
-	req.Header.Add("Authorization", fmt.Sprintf("token %s", c.OAuthToken))
+	req.Header.Add("Authorization", authorization)
+	req = ratelimit.WithBudget(req, budget)
 
 	if opts == nil {
 		withETag(c, req, path)
@@ -257,50 +273,34 @@ func (c *githubClient) get(path string, opts ...getArgFunc) (int, []byte, http.H
 
 	c.setETag(path, res.Header)
 
-	rateLimitLimit := res.Header.Get("X-RateLimit-Limit")
-	rateLimitRemaining := res.Header.Get("X-RateLimit-Remaining")
-	rateLimitReset := res.Header.Get("X-RateLimit-Reset")
-
-	if rateLimitLimit != "" && rateLimitRemaining != "" && rateLimitReset != "" {
-		RateLimitLimit, _ = strconv.Atoi(rateLimitLimit)
-		RateLimitRemaining, _ = strconv.Atoi(rateLimitRemaining)
-		RateLimitReset, _ = strconv.Atoi(rateLimitReset)
-	}
-
 	return res.StatusCode, resBody, res.Header, nil
 }
 
 func (c *githubClient) delete(path string) error {
-	if isRateLimitReached() {
-		return ErrorRateLimit
-	}
-
 	if !strings.HasPrefix(path, APIURL) {
 		path = APIURL + path
 	}
 
+	authorization, budget := c.authorization(path)
+	if isRateLimitReached(authorization, budget) {
+		return ErrorRateLimit
+	}
+
 	req, err := http.NewRequest(http.MethodDelete, path, nil)
 	if err != nil {
 		return err
 	}
 
 	req.Header.Set("User-Agent", "CDS-gh_client_id="+c.ClientID)
-	req.Header.Add("Authorization", fmt.Sprintf("token %s", c.OAuthToken))
+	req.Header.Add("Authorization", authorization)
+	req = ratelimit.WithBudget(req, budget)
 	log.Debug("Github API>> Request URL %s", req.URL.String())
 
 	res, err := httpClient.Do(req)
 	if err != nil {
 		return sdk.WrapError(err, "Cannot do delete request")
 	}
-
-	rateLimitLimit := res.Header.Get("X-RateLimit-Limit")
-	rateLimitRemaining := res.Header.Get("X-RateLimit-Remaining")
-	rateLimitReset := res.Header.Get("X-RateLimit-Reset")
-
-	if rateLimitLimit != "" && rateLimitRemaining != "" && rateLimitReset != "" {
-		RateLimitRemaining, _ = strconv.Atoi(rateLimitRemaining)
-		RateLimitReset, _ = strconv.Atoi(rateLimitReset)
-	}
+	defer res.Body.Close()
 
 	if res.StatusCode != 204 {
 		return fmt.Errorf("github>delete wrong status code %d on url %s", res.StatusCode, path)
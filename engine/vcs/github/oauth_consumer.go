@@ -117,6 +117,7 @@ func (g *githubConsumer) GetAuthorizedClient(ctx context.Context, accessToken, a
 			proxyURL:            g.proxyURL,
 			username:            g.username,
 			token:               g.token,
+			app:                 g.app,
 		}
 		instancesAuthorizedClient[accessToken] = c
 	}
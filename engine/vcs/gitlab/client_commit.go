@@ -103,3 +103,38 @@ func (c *gitlabClient) CommitsBetweenRefs(ctx context.Context, repo, base, head
 
 	return vcscommits, nil
 }
+
+func (c *gitlabClient) ChangedFiles(ctx context.Context, repo, base, head string) ([]sdk.VCSChangedFile, error) {
+	opt := &gitlab.CompareOptions{
+		From: &base,
+		To:   &head,
+	}
+
+	compare, _, err := c.client.Repositories.Compare(repo, opt)
+	if err != nil {
+		return nil, err
+	}
+
+	if compare == nil || compare.Diffs == nil {
+		return nil, nil
+	}
+
+	files := make([]sdk.VCSChangedFile, len(compare.Diffs))
+	for i, d := range compare.Diffs {
+		files[i] = sdk.VCSChangedFile{
+			Filename: d.NewPath,
+			Status:   sdk.VCSChangedFileModified,
+		}
+		switch {
+		case d.NewFile:
+			files[i].Status = sdk.VCSChangedFileAdded
+		case d.DeletedFile:
+			files[i].Filename = d.OldPath
+			files[i].Status = sdk.VCSChangedFileRemoved
+		case d.RenamedFile:
+			files[i].Status = sdk.VCSChangedFileRenamed
+		}
+	}
+
+	return files, nil
+}
@@ -13,8 +13,32 @@ import (
 	"github.com/ovh/cds/sdk/log"
 )
 
-func (c *gitlabClient) GetHook(ctx context.Context, repo, id string) (sdk.VCSHook, error) {
-	return sdk.VCSHook{}, fmt.Errorf("Not yet implemented")
+//GetHook returns the hook of the project calling the given url
+func (c *gitlabClient) GetHook(ctx context.Context, repo, hookURL string) (sdk.VCSHook, error) {
+	hooks, _, err := c.client.Projects.ListProjectHooks(repo, nil)
+	if err != nil {
+		return sdk.VCSHook{}, sdk.WrapError(err, "ListProjectHooks")
+	}
+	for _, h := range hooks {
+		if h.URL != hookURL {
+			continue
+		}
+		var events []string
+		if h.PushEvents {
+			events = append(events, "push")
+		}
+		if h.NoteEvents {
+			events = append(events, "note")
+		}
+		return sdk.VCSHook{
+			ID:          fmt.Sprintf("%d", h.ID),
+			Events:      events,
+			URL:         h.URL,
+			InsecureSSL: !h.EnableSSLVerification,
+			Workflow:    true,
+		}, nil
+	}
+	return sdk.VCSHook{}, sdk.WithStack(sdk.ErrHookNotFound)
 }
 func (c *gitlabClient) UpdateHook(ctx context.Context, repo, id string, hook sdk.VCSHook) error {
 	return fmt.Errorf("Not yet implemented")
@@ -41,8 +65,12 @@ func (c *gitlabClient) CreateHook(ctx context.Context, repo string, hook *sdk.VC
 		PushEvents:            &t,
 		MergeRequestsEvents:   &f,
 		TagPushEvents:         &f,
+		NoteEvents:            &t,
 		EnableSSLVerification: &f,
 	}
+	if hook.Secret != "" {
+		opt.Token = &hook.Secret
+	}
 
 	log.Debug("GitlabClient.CreateHook: %s %s\n", repo, *opt.URL)
 	ph, resp, err := c.client.Projects.AddProjectHook(repo, &opt)
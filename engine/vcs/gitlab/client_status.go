@@ -3,7 +3,6 @@ package gitlab
 import (
 	"context"
 	"fmt"
-	"strings"
 
 	"github.com/mitchellh/mapstructure"
 	"github.com/xanzy/go-gitlab"
@@ -19,6 +18,7 @@ type statusData struct {
 	desc         string
 	repoFullName string
 	hash         string
+	context      string
 }
 
 func getGitlabStateFromStatus(s string) gitlab.BuildState {
@@ -71,7 +71,11 @@ func (c *gitlabClient) SetStatus(ctx context.Context, event sdk.Event) error {
 		data.url = ""
 	}
 
+	// the statuses with a name customized by the workflow are identified by this name, the others share the CDS name
 	cds := "CDS"
+	if data.context != "" {
+		cds = data.context
+	}
 	opt := &gitlab.SetCommitStatusOptions{
 		Name:        &cds,
 		Context:     &cds,
@@ -96,9 +100,6 @@ func (c *gitlabClient) ListStatuses(ctx context.Context, repo string, ref string
 
 	vcsStatuses := []sdk.VCSCommitStatus{}
 	for _, s := range ss {
-		if !strings.HasPrefix(s.Description, "CDS/") {
-			continue
-		}
 		vcsStatuses = append(vcsStatuses, sdk.VCSCommitStatus{
 			CreatedAt:  *s.CreatedAt,
 			Decription: s.Description,
@@ -138,6 +139,7 @@ func processWorkflowNodeRunEvent(event sdk.Event, uiURL string) (statusData, err
 	)
 
 	data.desc = sdk.VCSCommitStatusDescription(event.ProjectKey, event.WorkflowName, eventNR)
+	data.context = eventNR.StatusContext
 	data.hash = eventNR.Hash
 	data.repoFullName = eventNR.RepositoryFullName
 	data.status = eventNR.Status
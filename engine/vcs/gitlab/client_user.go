@@ -0,0 +1,21 @@
+package gitlab
+
+import (
+	"context"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// CurrentUser returns the user of the access token
+func (c *gitlabClient) CurrentUser(ctx context.Context) (sdk.VCSAuthor, error) {
+	u, _, err := c.client.Users.CurrentUser()
+	if err != nil {
+		return sdk.VCSAuthor{}, sdk.WrapError(err, "cannot get current user")
+	}
+	return sdk.VCSAuthor{
+		Name:        u.Username,
+		DisplayName: u.Name,
+		Email:       u.Email,
+		Avatar:      u.AvatarURL,
+	}, nil
+}
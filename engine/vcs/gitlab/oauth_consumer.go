@@ -14,6 +14,7 @@ import (
 
 	"github.com/xanzy/go-gitlab"
 
+	"github.com/ovh/cds/engine/vcs/ratelimit"
 	"github.com/ovh/cds/sdk"
 	"github.com/ovh/cds/sdk/log"
 )
@@ -137,10 +138,11 @@ var instancesAuthorizedClient = map[string]*gitlabClient{}
 //GetAuthorized returns an authorized client
 func (g *gitlabConsumer) GetAuthorizedClient(ctx context.Context, accessToken, accessTokenSecret string) (sdk.VCSAuthorizedClient, error) {
 	c, ok := instancesAuthorizedClient[accessToken]
-	httpClient := &http.Client{
-		Timeout: 60 * time.Second,
-	}
 	if !ok {
+		httpClient := &http.Client{
+			Timeout:   60 * time.Second,
+			Transport: &ratelimit.Transport{Name: g.budgetName(), Threshold: 100, Cache: g.cache},
+		}
 		c = &gitlabClient{
 			client:              gitlab.NewOAuthClient(httpClient, accessToken),
 			uiURL:               g.uiURL,
@@ -152,3 +154,12 @@ func (g *gitlabConsumer) GetAuthorizedClient(ctx context.Context, accessToken, a
 	}
 	return c, nil
 }
+
+// budgetName returns the name of the rate limit budgets of the gitlab server, the budgets are counted per user
+func (g *gitlabConsumer) budgetName() string {
+	name := g.URL
+	if u, err := url.Parse(g.URL); err == nil && u.Host != "" {
+		name = u.Host
+	}
+	return "Gitlab-" + name
+}
@@ -0,0 +1,185 @@
+package ratelimit
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// Backoff bounds, the delay doubles on each rate limited response until a call succeeds
+const (
+	minBackoff = time.Second
+	maxBackoff = 5 * time.Minute
+)
+
+// Error is returned when a call is refused to keep the rate limit of a server
+type Error struct {
+	Name  string
+	Until time.Time
+}
+
+func (e Error) Error() string {
+	return fmt.Sprintf("%s rate limit reached until %s", e.Name, e.Until.Format(time.RFC3339))
+}
+
+// Budget tracks the rate limit of a VCS server from the headers of its responses
+type Budget struct {
+	Name string
+	// Threshold is the number of remaining calls under which the calls are refused until the reset of the rate limit
+	Threshold int
+
+	mutex        sync.Mutex
+	limit        int
+	remaining    int
+	reset        time.Time
+	backoff      time.Duration
+	backoffUntil time.Time
+}
+
+var (
+	budgets      = map[string]*Budget{}
+	budgetsMutex sync.Mutex
+)
+
+// Get returns the budget of a server, created on the first call
+func Get(name string, threshold int) *Budget {
+	budgetsMutex.Lock()
+	defer budgetsMutex.Unlock()
+	b, has := budgets[name]
+	if !has {
+		b = &Budget{Name: name, Threshold: threshold}
+		budgets[name] = b
+	}
+	return b
+}
+
+// CredentialBudget returns the budget of a credential on a server: the rate limits of the VCS servers are counted per
+// user, OAuth application or installation. The credential is identified by a hash of the Authorization header of its
+// calls.
+func CredentialBudget(name string, threshold int, authorization string) *Budget {
+	if authorization == "" {
+		return Get(name, threshold)
+	}
+	sum := sha1.Sum([]byte(authorization))
+	return Get(name+"-"+hex.EncodeToString(sum[:4]), threshold)
+}
+
+// Allow returns an error if a call would exceed the rate limit
+func (b *Budget) Allow() error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	now := time.Now()
+	if now.Before(b.backoffUntil) {
+		return Error{Name: b.Name, Until: b.backoffUntil}
+	}
+	if b.limit > 0 && b.remaining < b.Threshold && now.Before(b.reset) {
+		return Error{Name: b.Name, Until: b.reset}
+	}
+	return nil
+}
+
+// Update reads the rate limit headers sent by Github (X-RateLimit-*) and Gitlab (RateLimit-*)
+func (b *Budget) Update(h http.Header) {
+	for _, prefix := range []string{"X-RateLimit-", "RateLimit-"} {
+		limit, errL := strconv.Atoi(h.Get(prefix + "Limit"))
+		remaining, errR := strconv.Atoi(h.Get(prefix + "Remaining"))
+		reset, errT := strconv.ParseInt(h.Get(prefix+"Reset"), 10, 64)
+		if errL != nil || errR != nil || errT != nil {
+			continue
+		}
+		b.mutex.Lock()
+		b.limit, b.remaining, b.reset = limit, remaining, time.Unix(reset, 0)
+		b.mutex.Unlock()
+		return
+	}
+}
+
+// Backoff delays the next calls after a rate limited response, until the given date if any
+func (b *Budget) Backoff(until time.Time) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	switch {
+	case b.backoff == 0:
+		b.backoff = minBackoff
+	case b.backoff < maxBackoff:
+		b.backoff *= 2
+	}
+	if b.backoff > maxBackoff {
+		b.backoff = maxBackoff
+	}
+	if next := time.Now().Add(b.backoff); until.Before(next) {
+		until = next
+	}
+	b.backoffUntil = until
+}
+
+// Succeed resets the backoff after a call which was not rate limited
+func (b *Budget) Succeed() {
+	b.mutex.Lock()
+	b.backoff = 0
+	b.mutex.Unlock()
+}
+
+// Status returns the monitoring lines of the budget
+func (b *Budget) Status() []sdk.MonitoringStatusLine {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	statusRemaining := sdk.MonitoringStatusOK
+	switch {
+	case b.limit == 0:
+	case b.remaining < b.Threshold:
+		statusRemaining = sdk.MonitoringStatusAlert
+	case b.remaining < b.limit/5:
+		statusRemaining = sdk.MonitoringStatusWarn
+	}
+
+	var reset string
+	if !b.reset.IsZero() {
+		reset = b.reset.Format("15h04m05s")
+	}
+
+	statusBackoff := sdk.MonitoringStatusOK
+	var backoff string
+	if time.Now().Before(b.backoffUntil) {
+		statusBackoff = sdk.MonitoringStatusWarn
+		backoff = b.backoffUntil.Format("15h04m05s")
+	}
+
+	return []sdk.MonitoringStatusLine{
+		{Component: b.Name + "-RateLimitRemaining", Value: strconv.Itoa(b.remaining), Status: statusRemaining},
+		{Component: b.Name + "-RateLimitReset", Value: reset, Status: sdk.MonitoringStatusOK},
+		{Component: b.Name + "-RateLimit", Value: strconv.Itoa(b.limit), Status: sdk.MonitoringStatusOK},
+		{Component: b.Name + "-RateLimitBackoff", Value: backoff, Status: statusBackoff},
+	}
+}
+
+// Status returns the monitoring lines of all the budgets, the servers which did not send their rate limit yet are skipped
+func Status() []sdk.MonitoringStatusLine {
+	budgetsMutex.Lock()
+	names := make([]string, 0, len(budgets))
+	for name, b := range budgets {
+		b.mutex.Lock()
+		if b.limit > 0 || !b.backoffUntil.IsZero() {
+			names = append(names, name)
+		}
+		b.mutex.Unlock()
+	}
+	budgetsMutex.Unlock()
+	sort.Strings(names)
+
+	var lines []sdk.MonitoringStatusLine
+	for _, name := range names {
+		lines = append(lines, Get(name, 0).Status()...)
+	}
+	return lines
+}
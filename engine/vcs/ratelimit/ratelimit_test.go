@@ -0,0 +1,155 @@
+package ratelimit
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/engine/api/cache"
+	"github.com/ovh/cds/sdk"
+)
+
+func TestBudget(t *testing.T) {
+	b := Get("TestBudget", 10)
+	assert.NoError(t, b.Allow())
+
+	reset := time.Now().Add(time.Hour).Unix()
+	b.Update(http.Header{
+		"X-Ratelimit-Limit":     {"5000"},
+		"X-Ratelimit-Remaining": {"500"},
+		"X-Ratelimit-Reset":     {strconv.FormatInt(reset, 10)},
+	})
+	assert.NoError(t, b.Allow())
+	assert.Equal(t, sdk.MonitoringStatusWarn, b.Status()[0].Status)
+
+	// gitlab headers
+	b.Update(http.Header{
+		"Ratelimit-Limit":     {"600"},
+		"Ratelimit-Remaining": {"9"},
+		"Ratelimit-Reset":     {strconv.FormatInt(reset, 10)},
+	})
+	assert.Error(t, b.Allow())
+	assert.Equal(t, sdk.MonitoringStatusAlert, b.Status()[0].Status)
+
+	// the limit is reset
+	b.Update(http.Header{
+		"Ratelimit-Limit":     {"600"},
+		"Ratelimit-Remaining": {"9"},
+		"Ratelimit-Reset":     {strconv.FormatInt(time.Now().Add(-time.Second).Unix(), 10)},
+	})
+	assert.NoError(t, b.Allow())
+
+	b.Backoff(time.Time{})
+	assert.Equal(t, time.Second, b.backoff)
+	assert.Error(t, b.Allow())
+	b.Backoff(time.Time{})
+	assert.Equal(t, 2*time.Second, b.backoff)
+	assert.Equal(t, sdk.MonitoringStatusWarn, b.Status()[3].Status)
+	b.Succeed()
+	assert.Equal(t, time.Duration(0), b.backoff)
+
+	var found bool
+	for _, l := range Status() {
+		found = found || l.Component == "TestBudget-RateLimitRemaining"
+	}
+	assert.True(t, found)
+}
+
+func TestTransport(t *testing.T) {
+	var calls, notModified int
+	var rateLimited bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if rateLimited {
+			w.Header().Set("Retry-After", "60")
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.Header().Set("X-RateLimit-Limit", "5000")
+		w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(5000-calls))
+		w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10))
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			notModified++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Write([]byte("repositories of " + r.Header.Get("Authorization")))
+	}))
+	defer srv.Close()
+
+	c := &http.Client{Transport: &Transport{Name: "TestTransport", Threshold: 100, Cache: cache.NewLocalStore(60, 100)}}
+	b := CredentialBudget("TestTransport", 100, "foo")
+
+	get := func(token string) (int, string, error) {
+		req, _ := http.NewRequest(http.MethodGet, srv.URL+"/repos", nil)
+		req.Header.Set("Authorization", token)
+		res, err := c.Do(req)
+		if err != nil {
+			return 0, "", err
+		}
+		defer res.Body.Close()
+		body, _ := ioutil.ReadAll(res.Body)
+		return res.StatusCode, string(body), nil
+	}
+
+	status, body, err := get("foo")
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "repositories of foo", body)
+
+	// the second call is conditional and replaced by the cached response
+	status, body, err = get("foo")
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "repositories of foo", body)
+	assert.Equal(t, 1, notModified)
+	assert.Equal(t, 4998, b.remaining)
+
+	// the responses are cached per user
+	_, body, err = get("bar")
+	assert.NoError(t, err)
+	assert.Equal(t, "repositories of bar", body)
+	assert.Equal(t, 1, notModified)
+
+	// a rate limited response stops the next calls
+	rateLimited = true
+	status, _, err = get("foo")
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusForbidden, status)
+	_, _, err = get("foo")
+	assert.Error(t, err)
+	assert.Equal(t, 4, calls)
+
+	// the budgets of the other users are not affected
+	rateLimited = false
+	status, _, err = get("bar")
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, 5, calls)
+}
+
+func TestTransportWithBudget(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Limit", "5000")
+		w.Header().Set("X-RateLimit-Remaining", "4000")
+		w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10))
+	}))
+	defer srv.Close()
+
+	c := &http.Client{Transport: &Transport{Name: "TestTransportWithBudget", Threshold: 100}}
+	req, _ := http.NewRequest(http.MethodGet, srv.URL, nil)
+	req.Header.Set("Authorization", "token renewed")
+	res, err := c.Do(WithBudget(req, "TestTransportWithBudget-installation-42"))
+	assert.NoError(t, err)
+	res.Body.Close()
+
+	// the call is counted in the named budget, not in the budget of the token
+	assert.Equal(t, 4000, Get("TestTransportWithBudget-installation-42", 100).remaining)
+	assert.Equal(t, 0, CredentialBudget("TestTransportWithBudget", 100, "token renewed").remaining)
+}
@@ -0,0 +1,139 @@
+package ratelimit
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ovh/cds/engine/api/cache"
+)
+
+// etagTTL is the duration in seconds of the responses kept for the conditional requests
+const etagTTL = 60 * 60
+
+// Transport is an http.RoundTripper which refuses the calls exceeding the rate limit of the server, backs off on the
+// rate limited responses, and, if a cache is set, sends conditional requests for the GET requests already answered
+// with an ETag: the not modified responses do not count in the rate limit and are replaced by the cached response.
+// The calls are counted in the budget of their credential on the server named Name (see CredentialBudget), or in the
+// budget set on the request with WithBudget.
+type Transport struct {
+	Base      http.RoundTripper
+	Name      string
+	Threshold int
+	Cache     cache.Store
+}
+
+type cachedResponse struct {
+	ETag   string      `json:"etag"`
+	Header http.Header `json:"header"`
+	Body   []byte      `json:"body"`
+}
+
+type budgetContextKey struct{}
+
+// WithBudget returns the request counted in the named budget instead of the budget of its credential, for the
+// credentials renewed regularly which share the rate limit of a same installation
+func WithBudget(req *http.Request, name string) *http.Request {
+	if name == "" {
+		return req
+	}
+	return req.WithContext(context.WithValue(req.Context(), budgetContextKey{}, name))
+}
+
+// budget returns the budget of the request
+func (t *Transport) budget(req *http.Request) *Budget {
+	if name, ok := req.Context().Value(budgetContextKey{}).(string); ok {
+		return Get(name, t.Threshold)
+	}
+	return CredentialBudget(t.Name, t.Threshold, req.Header.Get("Authorization"))
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	budget := t.budget(req)
+	if err := budget.Allow(); err != nil {
+		return nil, err
+	}
+
+	// the conditional requests sent by the caller are left untouched
+	var key string
+	var cached cachedResponse
+	if t.Cache != nil && req.Method == http.MethodGet && req.Header.Get("If-None-Match") == "" {
+		// the responses depend on the user
+		sum := sha1.Sum([]byte(req.Header.Get("Authorization") + " " + req.URL.String()))
+		key = cache.Key("vcs", "etag", budget.Name, hex.EncodeToString(sum[:]))
+		if t.Cache.Get(key, &cached) && cached.ETag != "" {
+			req = req.Clone(req.Context())
+			req.Header.Set("If-None-Match", cached.ETag)
+		}
+	}
+
+	res, err := t.base().RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	budget.Update(res.Header)
+
+	if isRateLimited(res) {
+		var until time.Time
+		if s, err := strconv.Atoi(res.Header.Get("Retry-After")); err == nil {
+			until = time.Now().Add(time.Duration(s) * time.Second)
+		} else if reset, err := strconv.ParseInt(res.Header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+			until = time.Unix(reset, 0)
+		}
+		budget.Backoff(until)
+		return res, nil
+	}
+	budget.Succeed()
+
+	if key == "" {
+		return res, nil
+	}
+
+	switch {
+	case res.StatusCode == http.StatusNotModified && cached.ETag != "":
+		res.Body.Close()
+		for k, v := range cached.Header {
+			if res.Header.Get(k) == "" {
+				res.Header[k] = v
+			}
+		}
+		res.StatusCode = http.StatusOK
+		res.Status = http.StatusText(http.StatusOK)
+		res.Body = ioutil.NopCloser(bytes.NewReader(cached.Body))
+		res.ContentLength = int64(len(cached.Body))
+	case res.StatusCode == http.StatusOK && res.Header.Get("ETag") != "":
+		body, err := ioutil.ReadAll(res.Body)
+		res.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		t.Cache.SetWithTTL(key, cachedResponse{ETag: res.Header.Get("ETag"), Header: res.Header, Body: body}, etagTTL)
+		res.Body = ioutil.NopCloser(bytes.NewReader(body))
+	}
+	return res, nil
+}
+
+func (t *Transport) base() http.RoundTripper {
+	if t.Base == nil {
+		return http.DefaultTransport
+	}
+	return t.Base
+}
+
+// isRateLimited returns true for the responses refused because of the rate limit,
+// Github answers 403 with no remaining call, or with a Retry-After header for its abuse rate limit
+func isRateLimited(res *http.Response) bool {
+	switch res.StatusCode {
+	case http.StatusTooManyRequests:
+		return true
+	case http.StatusForbidden:
+		return res.Header.Get("X-RateLimit-Remaining") == "0" || res.Header.Get("Retry-After") != ""
+	}
+	return false
+}
@@ -3,10 +3,12 @@ package vcs
 import (
 	"fmt"
 	"strings"
+	"sync"
 
 	"github.com/ovh/cds/engine/api"
 	"github.com/ovh/cds/engine/api/cache"
 	"github.com/ovh/cds/engine/service"
+	"github.com/ovh/cds/engine/vcs/github"
 	"github.com/ovh/cds/sdk"
 	"github.com/ovh/cds/sdk/log"
 )
@@ -17,6 +19,9 @@ type Service struct {
 	Cfg    Configuration
 	Router *api.Router
 	Cache  cache.Store
+	// the github apps are kept to reuse their installation tokens
+	githubApps      map[string]*github.App
+	githubAppsMutex sync.Mutex
 }
 
 // Configuration is the vcs configuration structure
@@ -36,7 +41,7 @@ type Configuration struct {
 	Cache struct {
 		TTL   int `toml:"ttl" default:"60" json:"ttl"`
 		Redis struct {
-			Host     string `toml:"host" default:"localhost:6379" comment:"If your want to use a redis-sentinel based cluster, follow this syntax ! <clustername>@sentinel1:26379,sentinel2:26379sentinel3:26379" json:"host"`
+			Host     string `toml:"host" default:"localhost:6379" comment:"If your want to use a redis-sentinel based cluster, follow this syntax ! <clustername>@sentinel1:26379,sentinel2:26379sentinel3:26379\nIf your want to use a redis cluster, list its nodes: node1:7000,node2:7000,node3:7000" json:"host"`
 			Password string `toml:"password" json:"-"`
 		} `toml:"redis" json:"redis"`
 	} `toml:"cache" comment:"######################\n CDS VCS Cache Settings \n######################" json:"cache"`
@@ -64,6 +69,11 @@ type GithubServerConfiguration struct {
 	ProxyWebhook    string `toml:"proxyWebhook" default:"https://myproxy.com" commented:"true" comment:"If you want to have a reverse proxy url for your repository webhook, for example if you put https://myproxy.com it will generate a webhook URL like this https://myproxy.com/UUID_OF_YOUR_WEBHOOK" json:"proxy_webhook"`
 	Username        string `toml:"username" comment:"optional. Github username, used to add comment on Pull Request on failed build." json:"username"`
 	Token           string `toml:"token" comment:"optional, Github Token associated to username, used to add comment on Pull Request" json:"-"`
+	App             struct {
+		ID          int64             `toml:"id" default:"0" commented:"true" comment:"optional. Github App ID. The calls on the repositories of the organizations and users where the App is installed, which the OAuth token of the project can push to, are authenticated with installation tokens instead of the OAuth tokens" json:"id"`
+		PrivateKey  string            `toml:"privateKey" commented:"true" comment:"Github App private key (PEM format)" json:"-"`
+		Permissions map[string]string `toml:"permissions" commented:"true" comment:"optional. Permissions requested for the installation tokens, for example contents = \"read\" and statuses = \"write\". If not set, all the permissions of the App are requested" json:"permissions,omitempty"`
+	} `toml:"app" json:"app"`
 }
 
 func (s GithubServerConfiguration) check() error {
@@ -76,6 +86,9 @@ func (s GithubServerConfiguration) check() error {
 	if s.ProxyWebhook != "" && !strings.Contains(s.ProxyWebhook, "://") {
 		return fmt.Errorf("Github proxy webhook must have the HTTP scheme")
 	}
+	if s.App.ID != 0 && s.App.PrivateKey == "" {
+		return fmt.Errorf("Github App private key is mandatory")
+	}
 	return nil
 }
 
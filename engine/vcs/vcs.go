@@ -68,6 +68,28 @@ func (s *Service) CheckConfiguration(config interface{}) error {
 	return nil
 }
 
+// getGithubApp returns the Github App of a server, nil if no App is configured
+func (s *Service) getGithubApp(name string, cfg *GithubServerConfiguration) (*github.App, error) {
+	if cfg.App.ID == 0 {
+		return nil, nil
+	}
+
+	s.githubAppsMutex.Lock()
+	defer s.githubAppsMutex.Unlock()
+	if app, has := s.githubApps[name]; has {
+		return app, nil
+	}
+	app, err := github.NewApp(cfg.App.ID, []byte(cfg.App.PrivateKey), cfg.App.Permissions)
+	if err != nil {
+		return nil, err
+	}
+	if s.githubApps == nil {
+		s.githubApps = map[string]*github.App{}
+	}
+	s.githubApps[name] = app
+	return app, nil
+}
+
 func (s *Service) getConsumer(name string) (sdk.VCSServer, error) {
 	serverCfg, has := s.Cfg.Servers[name]
 	if !has {
@@ -75,6 +97,10 @@ func (s *Service) getConsumer(name string) (sdk.VCSServer, error) {
 	}
 
 	if serverCfg.Github != nil {
+		app, err := s.getGithubApp(name, serverCfg.Github)
+		if err != nil {
+			return nil, err
+		}
 		return github.New(
 			serverCfg.Github.ClientID,
 			serverCfg.Github.ClientSecret,
@@ -83,6 +109,7 @@ func (s *Service) getConsumer(name string) (sdk.VCSServer, error) {
 			serverCfg.Github.ProxyWebhook,
 			serverCfg.Github.Username,
 			serverCfg.Github.Token,
+			app,
 			s.Cache,
 			serverCfg.Github.Status.Disable,
 			!serverCfg.Github.Status.ShowDetail,
@@ -12,6 +12,7 @@ import (
 
 	"github.com/ovh/cds/engine/service"
 	"github.com/ovh/cds/engine/vcs/github"
+	"github.com/ovh/cds/engine/vcs/ratelimit"
 	"github.com/ovh/cds/sdk"
 	"github.com/ovh/cds/sdk/log"
 )
@@ -49,6 +50,10 @@ func (s *Service) getVCSServersHooksHandler() service.Handler {
 			WebhooksSupported bool   `json:"webhooks_supported"`
 			WebhooksDisabled  bool   `json:"webhooks_disabled"`
 			WebhooksIcon      string `json:"webhooks_icon"`
+			// WebhooksEvents are the events of the webhooks created by CDS
+			WebhooksEvents []string `json:"webhooks_events"`
+			// WebhooksSecretVisible is true if the repository manager tells if a webhook has a secret
+			WebhooksSecretVisible bool `json:"webhooks_secret_visible"`
 		}{}
 
 		switch {
@@ -56,14 +61,19 @@ func (s *Service) getVCSServersHooksHandler() service.Handler {
 			res.WebhooksSupported = true
 			res.WebhooksDisabled = cfg.Bitbucket.DisableWebHooks
 			res.WebhooksIcon = sdk.BitbucketIcon
+			res.WebhooksEvents = sdk.BitbucketWebHookEvents
+			res.WebhooksSecretVisible = true
 		case cfg.Github != nil:
 			res.WebhooksSupported = true
 			res.WebhooksDisabled = cfg.Github.DisableWebHooks
 			res.WebhooksIcon = sdk.GitHubIcon
+			res.WebhooksEvents = sdk.GitHubWebHookEvents
+			res.WebhooksSecretVisible = true
 		case cfg.Gitlab != nil:
 			res.WebhooksSupported = true
 			res.WebhooksDisabled = cfg.Gitlab.DisableWebHooks
 			res.WebhooksIcon = sdk.GitlabIcon
+			res.WebhooksEvents = sdk.GitlabWebHookEvents
 		}
 
 		return service.WriteJSON(w, res, http.StatusOK)
@@ -143,6 +153,35 @@ func (s *Service) postAuhorizeHandler() service.Handler {
 	}
 }
 
+// getCurrentUserHandler returns the account of the access token, it is used to link the account to a CDS user
+func (s *Service) getCurrentUserHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		name := muxVar(r, "name")
+
+		accessToken, accessTokenSecret, ok := getAccessTokens(ctx)
+		if !ok {
+			return sdk.WrapError(sdk.ErrUnauthorized, "VCS> getCurrentUserHandler> Unable to get access token headers")
+		}
+
+		consumer, err := s.getConsumer(name)
+		if err != nil {
+			return sdk.WrapError(err, "VCS server unavailable")
+		}
+
+		client, err := consumer.GetAuthorizedClient(ctx, accessToken, accessTokenSecret)
+		if err != nil {
+			return sdk.WrapError(err, "Unable to get authorized client")
+		}
+
+		u, err := client.CurrentUser(ctx)
+		if err != nil {
+			return sdk.WrapError(err, "Unable to get current user")
+		}
+
+		return service.WriteJSON(w, u, http.StatusOK)
+	}
+}
+
 func (s *Service) getReposHandler() service.Handler {
 	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
 		name := muxVar(r, "name")
@@ -358,6 +397,37 @@ func (s *Service) getCommitsBetweenRefsHandler() service.Handler {
 	}
 }
 
+func (s *Service) getChangedFilesHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		name := muxVar(r, "name")
+		owner := muxVar(r, "owner")
+		repo := muxVar(r, "repo")
+		base := r.URL.Query().Get("base")
+		head := r.URL.Query().Get("head")
+
+		accessToken, accessTokenSecret, ok := getAccessTokens(ctx)
+		if !ok {
+			return sdk.WrapError(sdk.ErrUnauthorized, "VCS> getChangedFilesHandler> Unable to get access token headers %s %s/%s", name, owner, repo)
+		}
+
+		consumer, err := s.getConsumer(name)
+		if err != nil {
+			return sdk.WrapError(err, "VCS server unavailable %s %s/%s", name, owner, repo)
+		}
+
+		client, err := consumer.GetAuthorizedClient(ctx, accessToken, accessTokenSecret)
+		if err != nil {
+			return sdk.WrapError(err, "Unable to get authorized client %s %s/%s", name, owner, repo)
+		}
+
+		files, err := client.ChangedFiles(ctx, fmt.Sprintf("%s/%s", owner, repo), base, head)
+		if err != nil {
+			return sdk.WrapError(err, "Unable to get changed files of %s/%s between %s and %s", owner, repo, base, head)
+		}
+		return service.WriteJSON(w, files, http.StatusOK)
+	}
+}
+
 func (s *Service) getCommitHandler() service.Handler {
 	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
 		name := muxVar(r, "name")
@@ -920,9 +990,7 @@ func (s *Service) getListForks() service.Handler {
 func (s *Service) Status() sdk.MonitoringStatus {
 	m := s.CommonMonitoring()
 
-	if s.Cfg.Servers["github"].URL != "" {
-		m.Lines = append(m.Lines, github.GetStatus()...)
-	}
+	m.Lines = append(m.Lines, ratelimit.Status()...)
 
 	return m
 }
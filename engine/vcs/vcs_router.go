@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/ovh/cds/engine/api"
+	"github.com/ovh/cds/engine/api/observability"
 	"github.com/ovh/cds/sdk/log"
 )
 
@@ -18,6 +19,7 @@ func (s *Service) initRouter(ctx context.Context) {
 
 	r.Handle("/mon/version", r.GET(api.VersionHandler, api.Auth(false)))
 	r.Handle("/mon/status", r.GET(s.statusHandler, api.Auth(false)))
+	r.Handle("/mon/metrics", r.GET(observability.StatsHandler, api.Auth(false)))
 
 	r.Handle("/vcs", r.GET(s.getAllVCSServersHandler))
 	r.Handle("/vcs/{name}", r.GET(s.getVCSServersHandler))
@@ -26,6 +28,8 @@ func (s *Service) initRouter(ctx context.Context) {
 
 	r.Handle("/vcs/{name}/authorize", r.GET(s.getAuthorizeHandler), r.POST(s.postAuhorizeHandler))
 
+	r.Handle("/vcs/{name}/user", r.GET(s.getCurrentUserHandler, api.EnableTracing()))
+
 	r.Handle("/vcs/{name}/repos", r.GET(s.getReposHandler, api.EnableTracing()))
 	r.Handle("/vcs/{name}/repos/{owner}/{repo}", r.GET(s.getRepoHandler, api.EnableTracing()))
 	r.Handle("/vcs/{name}/repos/{owner}/{repo}/branches", r.GET(s.getBranchesHandler, api.EnableTracing()))
@@ -34,6 +38,7 @@ func (s *Service) initRouter(ctx context.Context) {
 	r.Handle("/vcs/{name}/repos/{owner}/{repo}/tags", r.GET(s.getTagsHandler, api.EnableTracing()))
 	r.Handle("/vcs/{name}/repos/{owner}/{repo}/commits", r.GET(s.getCommitsBetweenRefsHandler, api.EnableTracing()))
 	r.Handle("/vcs/{name}/repos/{owner}/{repo}/commits/{commit}", r.GET(s.getCommitHandler, api.EnableTracing()))
+	r.Handle("/vcs/{name}/repos/{owner}/{repo}/changes", r.GET(s.getChangedFilesHandler, api.EnableTracing()))
 	r.Handle("/vcs/{name}/repos/{owner}/{repo}/commits/{commit}/statuses", r.GET(s.getCommitStatusHandler, api.EnableTracing()))
 	r.Handle("/vcs/{name}/repos/{owner}/{repo}/grant", r.POST(s.postRepoGrantHandler, api.EnableTracing()))
 	r.Handle("/vcs/{name}/repos/{owner}/{repo}/pullrequests", r.GET(s.getPullRequestsHandler, api.EnableTracing()), r.POST(s.postPullRequestsHandler, api.EnableTracing()))
@@ -46,4 +51,8 @@ func (s *Service) initRouter(ctx context.Context) {
 	r.Handle("/vcs/{name}/repos/{owner}/{repo}/forks", r.GET(s.getListForks, api.EnableTracing()))
 
 	r.Handle("/vcs/{name}/status", r.POST(s.postStatusHandler, api.EnableTracing()))
+
+	if err := r.InitMetrics("cds-vcs", s.Cfg.Name); err != nil {
+		log.Error("unable to init router metrics: %v", err)
+	}
 }
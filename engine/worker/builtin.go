@@ -7,6 +7,7 @@ import (
 
 	"github.com/golang/protobuf/ptypes/empty"
 	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/grpcplugin"
 	"github.com/ovh/cds/sdk/grpcplugin/actionplugin"
 	"github.com/ovh/cds/sdk/log"
 )
@@ -24,7 +25,16 @@ func init() {
 	mapBuiltinActions[sdk.CheckoutApplicationAction] = runCheckoutApplication
 	mapBuiltinActions[sdk.DeployApplicationAction] = runDeployApplication
 	mapBuiltinActions[sdk.CoverageAction] = runParseCoverageResultAction
+	mapBuiltinActions[sdk.CoverageUploadAction] = runCoverageUpload
 	mapBuiltinActions[sdk.ServeStaticFiles] = runServeStaticFiles
+	mapBuiltinActions[sdk.ArtifactPush] = runArtifactPush
+	mapBuiltinActions[sdk.PromoteAction] = runPromote
+	mapBuiltinActions[sdk.DeployHelmAction] = runDeployHelm
+	mapBuiltinActions[sdk.TerraformPlanAction] = runTerraformPlan
+	mapBuiltinActions[sdk.TerraformApplyAction] = runTerraformApply
+	mapBuiltinActions[sdk.SSHDeployAction] = runSSHDeploy
+	mapBuiltinActions[sdk.GenerateSBOMAction] = runGenerateSBOM
+	mapBuiltinActions[sdk.CheckCanaryMetricsAction] = runCheckCanaryMetrics
 }
 
 // BuiltInAction defines builtin action signature
@@ -117,13 +127,6 @@ func (w *currentWorker) runGRPCPlugin(ctx context.Context, a *sdk.Action, buildI
 			pluginFail(chanRes, sendLog, fmt.Sprintf("Unable to call grpc plugin... Aborting (%v)", err))
 			return
 		}
-		qPort := actionplugin.WorkerHTTPPortQuery{Port: w.exportPort}
-		if _, err := c.WorkerHTTPPort(ctx, &qPort); err != nil {
-			close(done)
-			pluginFail(chanRes, sendLog, fmt.Sprintf("Unable to set worker http port for grpc plugin... Aborting (%v)", err))
-			return
-		}
-
 		pluginSocket.Client = c
 		pluginClient := pluginSocket.Client
 		actionPluginClient, ok := pluginClient.(actionplugin.ActionPluginClient)
@@ -144,6 +147,22 @@ func (w *currentWorker) runGRPCPlugin(ctx context.Context, a *sdk.Action, buildI
 		}
 		log.Debug("plugin successfully initialized: %#v", manifest)
 
+		if err := grpcplugin.CheckCapabilities(manifest.Name, manifest.Capabilities); err != nil {
+			pluginFail(chanRes, sendLog, fmt.Sprintf("Unable to run plugin... Aborting (%v)", err))
+			actionPluginClientStop(ctx, actionPluginClient, stopLogs)
+			return
+		}
+
+		// Plugins which don't list their capabilities expect the worker http port
+		if len(manifest.Capabilities) == 0 || grpcplugin.HasCapability(manifest.Capabilities, grpcplugin.CapabilityWorkerHTTPPort) {
+			qPort := actionplugin.WorkerHTTPPortQuery{Port: w.exportPort}
+			if _, err := actionPluginClient.WorkerHTTPPort(ctx, &qPort); err != nil {
+				pluginFail(chanRes, sendLog, fmt.Sprintf("Unable to set worker http port for grpc plugin... Aborting (%v)", err))
+				actionPluginClientStop(ctx, actionPluginClient, stopLogs)
+				return
+			}
+		}
+
 		sendLog(fmt.Sprintf("# Plugin %s v%s is ready", manifest.Name, manifest.Version))
 		query := actionplugin.ActionQuery{
 			Options: sdk.ParametersMapMerge(sdk.ParametersToMap(params), sdk.ParametersToMap(a.Parameters), sdk.MapMergeOptions.ExcludeGitParams),
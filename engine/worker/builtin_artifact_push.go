@@ -0,0 +1,323 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// artifactManager is the Artifactory or Nexus server of the Artifact Manager integration
+type artifactManager struct {
+	platform string
+	url      string
+	username string
+	password string
+	client   *http.Client
+}
+
+type artifactChecksums struct {
+	md5    string
+	sha1   string
+	sha256 string
+}
+
+// artifactBuildInfo is the build-info document, in the format of the Artifactory build api
+type artifactBuildInfo struct {
+	Version     string                    `json:"version"`
+	Name        string                    `json:"name"`
+	Number      string                    `json:"number"`
+	Started     string                    `json:"started"`
+	URL         string                    `json:"url,omitempty"`
+	VCSRevision string                    `json:"vcsRevision,omitempty"`
+	VCSURL      string                    `json:"vcsUrl,omitempty"`
+	Agent       artifactBuildInfoAgent    `json:"agent"`
+	Modules     []artifactBuildInfoModule `json:"modules"`
+	Properties  map[string]string         `json:"properties,omitempty"`
+}
+
+type artifactBuildInfoAgent struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type artifactBuildInfoModule struct {
+	ID        string                      `json:"id"`
+	Artifacts []artifactBuildInfoArtifact `json:"artifacts"`
+}
+
+type artifactBuildInfoArtifact struct {
+	Type   string `json:"type,omitempty"`
+	Name   string `json:"name"`
+	Path   string `json:"path"`
+	MD5    string `json:"md5"`
+	SHA1   string `json:"sha1"`
+	SHA256 string `json:"sha256"`
+}
+
+func runArtifactPush(w *currentWorker) BuiltInAction {
+	return func(ctx context.Context, a *sdk.Action, buildID int64, params *[]sdk.Parameter, secrets []sdk.Variable, sendLog LoggerFunc) sdk.Result {
+		res := sdk.Result{Status: sdk.StatusSuccess.String()}
+
+		m, err := newArtifactManager(*params, secrets)
+		if err != nil {
+			res.Status = sdk.StatusFail.String()
+			res.Reason = err.Error()
+			sendLog(res.Reason)
+			return res
+		}
+
+		repository := strings.TrimSpace(sdk.ParameterValue(a.Parameters, "repository"))
+		if repository == "" {
+			res.Status = sdk.StatusFail.String()
+			res.Reason = fmt.Sprintf("repository parameter is empty. aborting")
+			sendLog(res.Reason)
+			return res
+		}
+		targetPath := strings.Trim(strings.TrimSpace(sdk.ParameterValue(a.Parameters, "targetPath")), "/")
+
+		filePath := strings.TrimSpace(sdk.ParameterValue(a.Parameters, "path"))
+		filesPath, err := filepath.Glob(filePath)
+		if err != nil {
+			res.Status = sdk.StatusFail.String()
+			res.Reason = fmt.Sprintf("cannot perform globbing of pattern '%s': %s", filePath, err)
+			sendLog(res.Reason)
+			return res
+		}
+		if len(filesPath) == 0 {
+			res.Status = sdk.StatusFail.String()
+			res.Reason = fmt.Sprintf("Pattern '%s' matched no file", filePath)
+			sendLog(res.Reason)
+			return res
+		}
+
+		bi := newArtifactBuildInfo(*params, sdk.ParameterValue(a.Parameters, "buildName"))
+		module := artifactBuildInfoModule{ID: sdk.ParameterValue(*params, "cds.application")}
+		if module.ID == "" {
+			module.ID = bi.Name
+		}
+
+		for _, p := range filesPath {
+			fi, err := os.Stat(p)
+			if err != nil {
+				res.Status = sdk.StatusFail.String()
+				res.Reason = fmt.Sprintf("Cannot stat file %s: %v", p, err)
+				sendLog(res.Reason)
+				return res
+			}
+			if fi.IsDir() {
+				continue
+			}
+
+			target := path.Join(targetPath, filepath.Base(p))
+			start := time.Now()
+			sum, err := m.push(ctx, repository, target, p, bi)
+			if err != nil {
+				res.Status = sdk.StatusFail.String()
+				res.Reason = fmt.Sprintf("Cannot push %s: %v", p, err)
+				sendLog(res.Reason)
+				return res
+			}
+			sendLog(fmt.Sprintf("File '%s' pushed in %.2fs to %s (sha256:%s)", filepath.Base(p), time.Since(start).Seconds(), m.fileURL(repository, target), sum.sha256))
+
+			module.Artifacts = append(module.Artifacts, artifactBuildInfoArtifact{
+				Type:   strings.TrimPrefix(filepath.Ext(p), "."),
+				Name:   filepath.Base(p),
+				Path:   target,
+				MD5:    sum.md5,
+				SHA1:   sum.sha1,
+				SHA256: sum.sha256,
+			})
+		}
+
+		if sdk.ParameterValue(a.Parameters, "buildInfo") == "false" {
+			return res
+		}
+
+		bi.Modules = []artifactBuildInfoModule{module}
+		if err := m.publishBuildInfo(ctx, repository, targetPath, bi); err != nil {
+			res.Status = sdk.StatusFail.String()
+			res.Reason = fmt.Sprintf("Cannot publish build-info: %v", err)
+			sendLog(res.Reason)
+			return res
+		}
+		sendLog(fmt.Sprintf("Build-info %s #%s published", bi.Name, bi.Number))
+
+		return res
+	}
+}
+
+// newArtifactManager returns the server of the integration of the pipeline context, its password is a secret of the job
+func newArtifactManager(params []sdk.Parameter, secrets []sdk.Variable) (*artifactManager, error) {
+	m := &artifactManager{
+		platform: sdk.ParameterValue(params, "cds.integration."+sdk.ArtifactManagerConfigPlatform),
+		url:      strings.TrimSuffix(sdk.ParameterValue(params, "cds.integration."+sdk.ArtifactManagerConfigURL), "/"),
+		username: sdk.ParameterValue(params, "cds.integration."+sdk.ArtifactManagerConfigUsername),
+		client:   &http.Client{Timeout: 30 * time.Minute},
+	}
+	if v := sdk.VariableFind(secrets, "cds.integration."+sdk.ArtifactManagerConfigPassword); v != nil {
+		m.password = v.Value
+	}
+
+	switch m.platform {
+	case sdk.ArtifactManagerPlatformArtifactory, sdk.ArtifactManagerPlatformNexus:
+	case "":
+		return nil, fmt.Errorf("there is no %s integration in the pipeline context", sdk.ArtifactManagerIntegrationModel)
+	default:
+		return nil, fmt.Errorf("unsupported platform %s, use %s or %s", m.platform, sdk.ArtifactManagerPlatformArtifactory, sdk.ArtifactManagerPlatformNexus)
+	}
+	if m.url == "" {
+		return nil, fmt.Errorf("the url of the %s integration is empty", sdk.ArtifactManagerIntegrationModel)
+	}
+	return m, nil
+}
+
+func newArtifactBuildInfo(params []sdk.Parameter, name string) artifactBuildInfo {
+	if name == "" {
+		name = sdk.ParameterValue(params, "cds.project") + "-" + sdk.ParameterValue(params, "cds.workflow")
+	}
+	bi := artifactBuildInfo{
+		Version:     "1.0.1",
+		Name:        name,
+		Number:      sdk.ParameterValue(params, "cds.version"),
+		Started:     time.Now().Format("2006-01-02T15:04:05.000-0700"),
+		URL:         sdk.ParameterValue(params, "cds.ui.pipeline.run"),
+		VCSRevision: sdk.ParameterValue(params, "git.hash"),
+		VCSURL:      sdk.ParameterValue(params, "git.url"),
+		Agent:       artifactBuildInfoAgent{Name: "cds", Version: sdk.VERSION},
+		Properties:  map[string]string{},
+	}
+	for _, k := range []string{"cds.project", "cds.workflow", "cds.application", "cds.pipeline", "cds.run.number", "git.branch", "git.tag"} {
+		if v := sdk.ParameterValue(params, k); v != "" {
+			bi.Properties[k] = v
+		}
+	}
+	return bi
+}
+
+// fileURL returns the url of a file in a repository
+func (m *artifactManager) fileURL(repository, target string) string {
+	if m.platform == sdk.ArtifactManagerPlatformNexus {
+		return m.url + "/repository/" + repository + "/" + target
+	}
+	return m.url + "/" + repository + "/" + target
+}
+
+// push uploads a file with its checksums. Artifactory gets them as headers and links the file to the build,
+// Nexus gets them as .md5, .sha1 and .sha256 files next to the file.
+func (m *artifactManager) push(ctx context.Context, repository, target, file string, bi artifactBuildInfo) (artifactChecksums, error) {
+	sum, err := fileChecksums(file)
+	if err != nil {
+		return sum, err
+	}
+
+	f, err := os.Open(file)
+	if err != nil {
+		return sum, sdk.WithStack(err)
+	}
+	defer f.Close()
+
+	u := m.fileURL(repository, target)
+	headers := map[string]string{}
+	if m.platform == sdk.ArtifactManagerPlatformArtifactory {
+		u += artifactoryMatrixParams(bi)
+		headers["X-Checksum-Md5"] = sum.md5
+		headers["X-Checksum-Sha1"] = sum.sha1
+		headers["X-Checksum-Sha256"] = sum.sha256
+	}
+	if err := m.put(ctx, u, f, headers); err != nil {
+		return sum, err
+	}
+
+	if m.platform == sdk.ArtifactManagerPlatformNexus {
+		for ext, s := range map[string]string{".md5": sum.md5, ".sha1": sum.sha1, ".sha256": sum.sha256} {
+			if err := m.put(ctx, m.fileURL(repository, target+ext), strings.NewReader(s), nil); err != nil {
+				return sum, err
+			}
+		}
+	}
+	return sum, nil
+}
+
+// publishBuildInfo sends the build-info to the Artifactory build api, or pushes it as build-info.json next to the files on Nexus
+func (m *artifactManager) publishBuildInfo(ctx context.Context, repository, targetPath string, bi artifactBuildInfo) error {
+	b, err := json.Marshal(bi)
+	if err != nil {
+		return sdk.WithStack(err)
+	}
+	if m.platform == sdk.ArtifactManagerPlatformNexus {
+		return m.put(ctx, m.fileURL(repository, path.Join(targetPath, "build-info.json")), bytes.NewReader(b), map[string]string{"Content-Type": "application/json"})
+	}
+	return m.put(ctx, m.url+"/api/build", bytes.NewReader(b), map[string]string{"Content-Type": "application/json"})
+}
+
+func (m *artifactManager) put(ctx context.Context, u string, body io.Reader, headers map[string]string) error {
+	req, err := http.NewRequest(http.MethodPut, u, body)
+	if err != nil {
+		return sdk.WithStack(err)
+	}
+	req = req.WithContext(ctx)
+	if m.username != "" || m.password != "" {
+		req.SetBasicAuth(m.username, m.password)
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	log.Debug("artifactManager> PUT %s", u)
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return sdk.WithStack(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		b, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("PUT %s returns %d: %s", u, resp.StatusCode, strings.TrimSpace(string(b)))
+	}
+	return nil
+}
+
+// artifactoryMatrixParams returns the properties linking a deployed file to its build, as Artifactory matrix parameters
+func artifactoryMatrixParams(bi artifactBuildInfo) string {
+	var s string
+	for _, p := range [][2]string{{"build.name", bi.Name}, {"build.number", bi.Number}, {"vcs.revision", bi.VCSRevision}} {
+		if p[1] != "" {
+			s += ";" + p[0] + "=" + url.PathEscape(p[1])
+		}
+	}
+	return s
+}
+
+func fileChecksums(file string) (artifactChecksums, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return artifactChecksums{}, sdk.WithStack(err)
+	}
+	defer f.Close()
+
+	hMD5, hSHA1, hSHA256 := md5.New(), sha1.New(), sha256.New()
+	if _, err := io.Copy(io.MultiWriter(hMD5, hSHA1, hSHA256), f); err != nil {
+		return artifactChecksums{}, sdk.WithStack(err)
+	}
+	return artifactChecksums{
+		md5:    hex.EncodeToString(hMD5.Sum(nil)),
+		sha1:   hex.EncodeToString(hSHA1.Sum(nil)),
+		sha256: hex.EncodeToString(hSHA256.Sum(nil)),
+	}, nil
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/sdk"
+)
+
+type artifactManagerMock struct {
+	mu       sync.Mutex
+	requests map[string]*http.Request
+	bodies   map[string]string
+}
+
+func (s *artifactManagerMock) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	b, _ := ioutil.ReadAll(r.Body)
+	s.mu.Lock()
+	s.requests[r.URL.EscapedPath()] = r
+	s.bodies[r.URL.EscapedPath()] = string(b)
+	s.mu.Unlock()
+	w.WriteHeader(http.StatusCreated)
+}
+
+func testArtifactPush(t *testing.T, platform string) (*artifactManagerMock, sdk.Result) {
+	mock := &artifactManagerMock{requests: map[string]*http.Request{}, bodies: map[string]string{}}
+	srv := httptest.NewServer(mock)
+	t.Cleanup(srv.Close)
+
+	dir, err := ioutil.TempDir("", "artifact-push")
+	assert.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "app.tar.gz"), []byte("my app"), 0644))
+
+	params := []sdk.Parameter{
+		{Name: "cds.integration.platform", Value: platform},
+		{Name: "cds.integration.url", Value: srv.URL + "/"},
+		{Name: "cds.integration.username", Value: "cds"},
+		{Name: "cds.version", Value: "42"},
+		{Name: "cds.application", Value: "my-app"},
+		{Name: "git.hash", Value: "abcdef"},
+	}
+	secrets := []sdk.Variable{{Name: "cds.integration.password", Value: "secret"}}
+	a := sdk.NewStepArtifactPush(map[string]string{
+		"path":       filepath.Join(dir, "*.tar.gz"),
+		"repository": "releases",
+		"targetPath": "/my-app/42/",
+		"buildInfo":  "true",
+		"buildName":  "my-build",
+	})
+
+	res := runArtifactPush(&currentWorker{})(context.Background(), &a, 1, &params, secrets, func(string) {})
+	return mock, res
+}
+
+func TestArtifactPushArtifactory(t *testing.T) {
+	mock, res := testArtifactPush(t, sdk.ArtifactManagerPlatformArtifactory)
+	assert.Equal(t, sdk.StatusSuccess.String(), res.Status, res.Reason)
+
+	r, ok := mock.requests["/releases/my-app/42/app.tar.gz;build.name=my-build;build.number=42;vcs.revision=abcdef"]
+	if assert.True(t, ok, "%v", mock.requests) {
+		assert.Equal(t, http.MethodPut, r.Method)
+		assert.NotEmpty(t, r.Header.Get("X-Checksum-Sha1"))
+		assert.NotEmpty(t, r.Header.Get("X-Checksum-Sha256"))
+		username, password, _ := r.BasicAuth()
+		assert.Equal(t, "cds", username)
+		assert.Equal(t, "secret", password)
+	}
+
+	var bi artifactBuildInfo
+	assert.NoError(t, json.Unmarshal([]byte(mock.bodies["/api/build"]), &bi))
+	assert.Equal(t, "my-build", bi.Name)
+	assert.Equal(t, "42", bi.Number)
+	assert.Equal(t, "abcdef", bi.VCSRevision)
+	if assert.Len(t, bi.Modules, 1) && assert.Len(t, bi.Modules[0].Artifacts, 1) {
+		assert.Equal(t, "my-app", bi.Modules[0].ID)
+		assert.Equal(t, "app.tar.gz", bi.Modules[0].Artifacts[0].Name)
+		assert.Equal(t, "my-app/42/app.tar.gz", bi.Modules[0].Artifacts[0].Path)
+		assert.Equal(t, mock.requests["/releases/my-app/42/app.tar.gz;build.name=my-build;build.number=42;vcs.revision=abcdef"].Header.Get("X-Checksum-Sha256"), bi.Modules[0].Artifacts[0].SHA256)
+	}
+}
+
+func TestArtifactPushNexus(t *testing.T) {
+	mock, res := testArtifactPush(t, sdk.ArtifactManagerPlatformNexus)
+	assert.Equal(t, sdk.StatusSuccess.String(), res.Status, res.Reason)
+
+	assert.Equal(t, "my app", mock.bodies["/repository/releases/my-app/42/app.tar.gz"])
+	assert.Len(t, mock.bodies["/repository/releases/my-app/42/app.tar.gz.md5"], 32)
+	assert.Len(t, mock.bodies["/repository/releases/my-app/42/app.tar.gz.sha1"], 40)
+	assert.Len(t, mock.bodies["/repository/releases/my-app/42/app.tar.gz.sha256"], 64)
+	assert.Contains(t, mock.bodies["/repository/releases/my-app/42/build-info.json"], `"name":"my-build"`)
+}
+
+func TestNewArtifactManager(t *testing.T) {
+	_, err := newArtifactManager(nil, nil)
+	assert.Error(t, err)
+
+	_, err = newArtifactManager([]sdk.Parameter{{Name: "cds.integration.platform", Value: "s3"}, {Name: "cds.integration.url", Value: "http://localhost"}}, nil)
+	assert.Error(t, err)
+
+	m, err := newArtifactManager([]sdk.Parameter{{Name: "cds.integration.platform", Value: "nexus"}, {Name: "cds.integration.url", Value: "http://localhost/"}}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "http://localhost/repository/releases/a/b.zip", m.fileURL("releases", "a/b.zip"))
+}
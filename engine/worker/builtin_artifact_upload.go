@@ -29,6 +29,9 @@ func runArtifactUpload(w *currentWorker) BuiltInAction {
 			return res
 		}
 
+		// the checksums of the artifacts are signed by the api with this key of the project
+		signingKey := strings.TrimSpace(sdk.ParameterValue(a.Parameters, "signingKey"))
+
 		// Global all files matching filePath
 		filesPath, err := filepath.Glob(path)
 		if err != nil {
@@ -64,12 +67,15 @@ func runArtifactUpload(w *currentWorker) BuiltInAction {
 			go func(path string) {
 				log.Debug("Uploading %s", path)
 				defer wg.Done()
-				throughTempURL, duration, err := w.client.QueueArtifactUpload(ctx, buildID, tag.Value, path)
+				throughTempURL, duration, err := w.client.QueueArtifactUpload(ctx, buildID, tag.Value, path, signingKey)
 				if err != nil {
 					chanError <- sdk.WrapError(err, "Error while uploading artifact %s", path)
 					wgErrors.Add(1)
 					return
 				}
+				if signingKey != "" {
+					sendLog(fmt.Sprintf("Checksums of file '%s' signed with key %s", filename, signingKey))
+				}
 				if throughTempURL {
 					sendLog(fmt.Sprintf("File '%s' uploaded in %.2fs to object store", filename, duration.Seconds()))
 				} else {
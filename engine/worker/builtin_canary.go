@@ -0,0 +1,355 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ovh/cds/sdk"
+)
+
+const canaryRollbackVar = "cds.build.canary.rollback"
+
+// canaryCheck is a threshold on the value of a query
+type canaryCheck struct {
+	query     string
+	operator  string
+	threshold float64
+}
+
+func (c canaryCheck) String() string {
+	return fmt.Sprintf("%s %s %v", c.query, c.operator, c.threshold)
+}
+
+// ok returns true if the value respects the threshold
+func (c canaryCheck) ok(v float64) bool {
+	switch c.operator {
+	case "<":
+		return v < c.threshold
+	case "<=":
+		return v <= c.threshold
+	case ">":
+		return v > c.threshold
+	default:
+		return v >= c.threshold
+	}
+}
+
+// parseCanaryChecks parses one check per line: <query> <operator> <threshold>. The query can contain spaces.
+func parseCanaryChecks(s string) ([]canaryCheck, error) {
+	var checks []canaryCheck
+	for _, line := range strings.Split(s, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) < 3 {
+			return nil, fmt.Errorf("invalid check %q, expected <query> <operator> <threshold>", line)
+		}
+		threshold, err := strconv.ParseFloat(fields[len(fields)-1], 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid threshold in check %q", line)
+		}
+		operator := fields[len(fields)-2]
+		if operator != "<" && operator != "<=" && operator != ">" && operator != ">=" {
+			return nil, fmt.Errorf("invalid operator %s in check %q, expected <, <=, > or >=", operator, line)
+		}
+		// keep the spaces of the query
+		query := strings.TrimSpace(line[:strings.LastIndex(line, operator)])
+		checks = append(checks, canaryCheck{query: query, operator: operator, threshold: threshold})
+	}
+	if len(checks) == 0 {
+		return nil, fmt.Errorf("checks parameter is empty")
+	}
+	return checks, nil
+}
+
+// metricsQuerier returns the current value of a query
+type metricsQuerier interface {
+	query(ctx context.Context, q string, at time.Time) (float64, error)
+}
+
+// newMetricsQuerier returns the metrics backend of the Monitoring integration of the pipeline context, its keys are
+// secrets of the job
+func newMetricsQuerier(params []sdk.Parameter, secrets []sdk.Variable) (metricsQuerier, error) {
+	platform := sdk.ParameterValue(params, "cds.integration."+sdk.MonitoringConfigPlatform)
+	u := strings.TrimSuffix(sdk.ParameterValue(params, "cds.integration."+sdk.MonitoringConfigURL), "/")
+	var apiKey, appKey string
+	if v := sdk.VariableFind(secrets, "cds.integration."+sdk.MonitoringConfigAPIKey); v != nil {
+		apiKey = v.Value
+	}
+	if v := sdk.VariableFind(secrets, "cds.integration."+sdk.MonitoringConfigApplicationKey); v != nil {
+		appKey = v.Value
+	}
+
+	if platform == "" {
+		return nil, fmt.Errorf("there is no %s integration in the pipeline context", sdk.MonitoringIntegrationModel)
+	}
+	if u == "" {
+		return nil, fmt.Errorf("the url of the %s integration is empty", sdk.MonitoringIntegrationModel)
+	}
+	client := &http.Client{Timeout: 30 * time.Second}
+	switch platform {
+	case sdk.MonitoringPlatformPrometheus:
+		return prometheusQuerier{url: u, token: apiKey, client: client}, nil
+	case sdk.MonitoringPlatformDatadog:
+		return datadogQuerier{url: u, apiKey: apiKey, appKey: appKey, client: client}, nil
+	default:
+		return nil, fmt.Errorf("unsupported platform %s, use %s or %s", platform, sdk.MonitoringPlatformPrometheus, sdk.MonitoringPlatformDatadog)
+	}
+}
+
+func getMetricsJSON(ctx context.Context, client *http.Client, req *http.Request, v interface{}) error {
+	resp, err := client.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("HTTP %d: %s", resp.StatusCode, string(body))
+	}
+	return json.Unmarshal(body, v)
+}
+
+type prometheusQuerier struct {
+	url    string
+	token  string
+	client *http.Client
+}
+
+func (p prometheusQuerier) query(ctx context.Context, q string, at time.Time) (float64, error) {
+	values := url.Values{}
+	values.Set("query", q)
+	values.Set("time", strconv.FormatInt(at.Unix(), 10))
+	req, err := http.NewRequest(http.MethodGet, p.url+"/api/v1/query?"+values.Encode(), nil)
+	if err != nil {
+		return 0, err
+	}
+	if p.token != "" {
+		req.Header.Set("Authorization", "Bearer "+p.token)
+	}
+
+	var res struct {
+		Status string `json:"status"`
+		Error  string `json:"error"`
+		Data   struct {
+			ResultType string          `json:"resultType"`
+			Result     json.RawMessage `json:"result"`
+		} `json:"data"`
+	}
+	if err := getMetricsJSON(ctx, p.client, req, &res); err != nil {
+		return 0, err
+	}
+	if res.Status != "success" {
+		return 0, fmt.Errorf("query failed: %s", res.Error)
+	}
+
+	// a scalar is a [time, "value"] pair, a vector a list of samples with such a value
+	var value []interface{}
+	switch res.Data.ResultType {
+	case "scalar":
+		if err := json.Unmarshal(res.Data.Result, &value); err != nil {
+			return 0, err
+		}
+	case "vector":
+		var samples []struct {
+			Value []interface{} `json:"value"`
+		}
+		if err := json.Unmarshal(res.Data.Result, &samples); err != nil {
+			return 0, err
+		}
+		if len(samples) == 0 {
+			return 0, fmt.Errorf("no data")
+		}
+		if len(samples) > 1 {
+			return 0, fmt.Errorf("the query returns %d series, aggregate them", len(samples))
+		}
+		value = samples[0].Value
+	default:
+		return 0, fmt.Errorf("unsupported result type %s", res.Data.ResultType)
+	}
+	if len(value) != 2 {
+		return 0, fmt.Errorf("invalid value %v", value)
+	}
+	s, ok := value[1].(string)
+	if !ok {
+		return 0, fmt.Errorf("invalid value %v", value[1])
+	}
+	return strconv.ParseFloat(s, 64)
+}
+
+type datadogQuerier struct {
+	url    string
+	apiKey string
+	appKey string
+	client *http.Client
+}
+
+func (d datadogQuerier) query(ctx context.Context, q string, at time.Time) (float64, error) {
+	values := url.Values{}
+	values.Set("query", q)
+	values.Set("from", strconv.FormatInt(at.Add(-5*time.Minute).Unix(), 10))
+	values.Set("to", strconv.FormatInt(at.Unix(), 10))
+	req, err := http.NewRequest(http.MethodGet, d.url+"/api/v1/query?"+values.Encode(), nil)
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("DD-API-KEY", d.apiKey)
+	req.Header.Set("DD-APPLICATION-KEY", d.appKey)
+
+	var res struct {
+		Status string `json:"status"`
+		Error  string `json:"error"`
+		Series []struct {
+			Pointlist [][]*float64 `json:"pointlist"`
+		} `json:"series"`
+	}
+	if err := getMetricsJSON(ctx, d.client, req, &res); err != nil {
+		return 0, err
+	}
+	if res.Status == "error" {
+		return 0, fmt.Errorf("query failed: %s", res.Error)
+	}
+	if len(res.Series) == 0 {
+		return 0, fmt.Errorf("no data")
+	}
+	if len(res.Series) > 1 {
+		return 0, fmt.Errorf("the query returns %d series, aggregate them", len(res.Series))
+	}
+	// the last point with a value
+	points := res.Series[0].Pointlist
+	for i := len(points) - 1; i >= 0; i-- {
+		if len(points[i]) == 2 && points[i][1] != nil {
+			return *points[i][1], nil
+		}
+	}
+	return 0, fmt.Errorf("no data")
+}
+
+// canaryAnalysis runs the checks at each interval during its duration
+type canaryAnalysis struct {
+	checks      []canaryCheck
+	duration    time.Duration
+	interval    time.Duration
+	maxFailures int
+	rollback    bool
+}
+
+func newCanaryAnalysis(params []sdk.Parameter) (*canaryAnalysis, error) {
+	checks, err := parseCanaryChecks(sdk.ParameterValue(params, "checks"))
+	if err != nil {
+		return nil, err
+	}
+	a := &canaryAnalysis{
+		checks:      checks,
+		duration:    10 * time.Minute,
+		interval:    time.Minute,
+		maxFailures: 1,
+		rollback:    sdk.ParameterValue(params, "rollback") == "true",
+	}
+	if s := strings.TrimSpace(sdk.ParameterValue(params, "duration")); s != "" {
+		if a.duration, err = time.ParseDuration(s); err != nil {
+			return nil, fmt.Errorf("invalid duration %s: %v", s, err)
+		}
+	}
+	if s := strings.TrimSpace(sdk.ParameterValue(params, "interval")); s != "" {
+		if a.interval, err = time.ParseDuration(s); err != nil || a.interval <= 0 {
+			return nil, fmt.Errorf("invalid interval %s", s)
+		}
+	}
+	if s := strings.TrimSpace(sdk.ParameterValue(params, "failures")); s != "" {
+		if a.maxFailures, err = strconv.Atoi(s); err != nil || a.maxFailures <= 0 {
+			return nil, fmt.Errorf("invalid failures %s", s)
+		}
+	}
+	return a, nil
+}
+
+// run returns nil if all the checks are respected until the end of the analysis. A query which cannot be run counts as
+// a failed check.
+func (a canaryAnalysis) run(ctx context.Context, q metricsQuerier, sendLog LoggerFunc) error {
+	failures := make([]int, len(a.checks))
+	end := time.Now().Add(a.duration)
+	for {
+		now := time.Now()
+		for i, c := range a.checks {
+			v, err := q.query(ctx, c.query, now)
+			switch {
+			case err != nil:
+				failures[i]++
+				sendLog(fmt.Sprintf("✗ %s: %v (%d/%d)", c, err, failures[i], a.maxFailures))
+			case !c.ok(v):
+				failures[i]++
+				sendLog(fmt.Sprintf("✗ %s: %v (%d/%d)", c, v, failures[i], a.maxFailures))
+			default:
+				sendLog(fmt.Sprintf("✓ %s: %v", c, v))
+			}
+			if failures[i] >= a.maxFailures {
+				return fmt.Errorf("the canary is rejected: %s failed %d times", c, failures[i])
+			}
+		}
+		if !now.Add(a.interval).Before(end) {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(a.interval):
+		}
+	}
+}
+
+func runCheckCanaryMetrics(w *currentWorker) BuiltInAction {
+	return func(ctx context.Context, a *sdk.Action, buildID int64, params *[]sdk.Parameter, secrets []sdk.Variable, sendLog LoggerFunc) sdk.Result {
+		res := sdk.Result{Status: sdk.StatusSuccess.String()}
+
+		analysis, err := newCanaryAnalysis(a.Parameters)
+		if err != nil {
+			res.Status = sdk.StatusFail.String()
+			res.Reason = err.Error()
+			sendLog(res.Reason)
+			return res
+		}
+		q, err := newMetricsQuerier(*params, secrets)
+		if err != nil {
+			res.Status = sdk.StatusFail.String()
+			res.Reason = err.Error()
+			sendLog(res.Reason)
+			return res
+		}
+
+		sendLog(fmt.Sprintf("Checking %d metrics every %s during %s", len(analysis.checks), analysis.interval, analysis.duration))
+		errAnalysis := analysis.run(ctx, q, sendLog)
+		if errAnalysis != nil && !analysis.rollback {
+			res.Status = sdk.StatusFail.String()
+			res.Reason = errAnalysis.Error()
+			sendLog(res.Reason)
+			return res
+		}
+		if errAnalysis != nil {
+			sendLog(fmt.Sprintf("%v, the rollback is requested", errAnalysis))
+		} else {
+			sendLog("The canary is accepted")
+		}
+
+		v := sdk.Variable{Name: canaryRollbackVar, Type: sdk.StringVariable, Value: strconv.FormatBool(errAnalysis != nil)}
+		if _, err := w.addVariableInPipelineBuild(v, params); err != nil {
+			res.Status = sdk.StatusFail.String()
+			res.Reason = fmt.Sprintf("Cannot export variable %s: %v", v.Name, err)
+			sendLog(res.Reason)
+			return res
+		}
+		return res
+	}
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func TestParseCanaryChecks(t *testing.T) {
+	checks, err := parseCanaryChecks(`
+# errors of the canary
+sum(rate(http_errors{track="canary"}[5m])) / sum(rate(http_requests{track="canary"}[5m])) < 0.01
+avg:trace.http.request.duration{version:canary} <= 250
+`)
+	assert.NoError(t, err)
+	assert.Equal(t, []canaryCheck{
+		{query: `sum(rate(http_errors{track="canary"}[5m])) / sum(rate(http_requests{track="canary"}[5m]))`, operator: "<", threshold: 0.01},
+		{query: "avg:trace.http.request.duration{version:canary}", operator: "<=", threshold: 250},
+	}, checks)
+	assert.True(t, checks[0].ok(0.005))
+	assert.False(t, checks[0].ok(0.01))
+	assert.True(t, checks[1].ok(250))
+
+	_, err = parseCanaryChecks("up == 1")
+	assert.Error(t, err)
+	_, err = parseCanaryChecks("up > one")
+	assert.Error(t, err)
+	_, err = parseCanaryChecks("up")
+	assert.Error(t, err)
+	_, err = parseCanaryChecks("\n# nothing\n")
+	assert.Error(t, err)
+}
+
+func TestPrometheusQuerier(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/query", r.URL.Path)
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		switch r.URL.Query().Get("query") {
+		case "errors":
+			w.Write([]byte(`{"status":"success","data":{"resultType":"vector","result":[{"metric":{},"value":[1578000000,"0.02"]}]}}`)) // nolint
+		case "scalar(1)":
+			w.Write([]byte(`{"status":"success","data":{"resultType":"scalar","result":[1578000000,"1"]}}`)) // nolint
+		default:
+			w.Write([]byte(`{"status":"success","data":{"resultType":"vector","result":[]}}`)) // nolint
+		}
+	}))
+	defer srv.Close()
+
+	q, err := newMetricsQuerier([]sdk.Parameter{
+		{Name: "cds.integration.platform", Value: sdk.MonitoringPlatformPrometheus},
+		{Name: "cds.integration.url", Value: srv.URL + "/"},
+	}, []sdk.Variable{{Name: "cds.integration.api key", Value: "token"}})
+	assert.NoError(t, err)
+
+	v, err := q.query(context.Background(), "errors", time.Now())
+	assert.NoError(t, err)
+	assert.Equal(t, 0.02, v)
+	v, err = q.query(context.Background(), "scalar(1)", time.Now())
+	assert.NoError(t, err)
+	assert.Equal(t, 1.0, v)
+	_, err = q.query(context.Background(), "unknown", time.Now())
+	assert.Error(t, err)
+}
+
+func TestDatadogQuerier(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "api", r.Header.Get("DD-API-KEY"))
+		assert.Equal(t, "app", r.Header.Get("DD-APPLICATION-KEY"))
+		assert.Equal(t, "avg:latency{version:canary}", r.URL.Query().Get("query"))
+		w.Write([]byte(`{"status":"ok","series":[{"pointlist":[[1578000000000,120.5],[1578000060000,130],[1578000120000,null]]}]}`)) // nolint
+	}))
+	defer srv.Close()
+
+	q, err := newMetricsQuerier([]sdk.Parameter{
+		{Name: "cds.integration.platform", Value: sdk.MonitoringPlatformDatadog},
+		{Name: "cds.integration.url", Value: srv.URL},
+	}, []sdk.Variable{
+		{Name: "cds.integration.api key", Value: "api"},
+		{Name: "cds.integration.application key", Value: "app"},
+	})
+	assert.NoError(t, err)
+
+	v, err := q.query(context.Background(), "avg:latency{version:canary}", time.Now())
+	assert.NoError(t, err)
+	assert.Equal(t, 130.0, v)
+
+	_, err = newMetricsQuerier(nil, nil)
+	assert.Error(t, err)
+}
+
+type fakeQuerier []float64
+
+func (f *fakeQuerier) query(ctx context.Context, q string, at time.Time) (float64, error) {
+	v := (*f)[0]
+	*f = (*f)[1:]
+	return v, nil
+}
+
+func TestCanaryAnalysis(t *testing.T) {
+	a, err := newCanaryAnalysis([]sdk.Parameter{
+		{Name: "checks", Value: "errors < 0.01"},
+		{Name: "duration", Value: "250ms"},
+		{Name: "interval", Value: "100ms"},
+		{Name: "failures", Value: "2"},
+	})
+	assert.NoError(t, err)
+	sendLog := func(string) {}
+
+	// a single failure is tolerated
+	q := fakeQuerier{0.001, 0.05, 0.001}
+	assert.NoError(t, a.run(context.Background(), &q, sendLog))
+
+	q = fakeQuerier{0.001, 0.05, 0.05}
+	assert.Error(t, a.run(context.Background(), &q, sendLog))
+
+	_, err = newCanaryAnalysis([]sdk.Parameter{{Name: "checks", Value: "errors < 0.01"}, {Name: "interval", Value: "0s"}})
+	assert.Error(t, err)
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"regexp"
+	"strconv"
+	"strings"
 
 	"github.com/ovh/cds/sdk"
 	"github.com/ovh/cds/sdk/vcs/git"
@@ -13,6 +15,10 @@ func runCheckoutApplication(w *currentWorker) BuiltInAction {
 	return func(ctx context.Context, a *sdk.Action, buildID int64, params *[]sdk.Parameter, secrets []sdk.Variable, sendLog LoggerFunc) sdk.Result {
 		// Load action param
 		directory := sdk.ParameterFind(&a.Parameters, "directory")
+		depth := sdk.ParameterValue(a.Parameters, "depth")
+		submodules := sdk.ParameterValue(a.Parameters, "submodules")
+		lfs := sdk.ParameterValue(a.Parameters, "lfs")
+		sparse := sdk.ParameterValue(a.Parameters, "sparse")
 
 		// Load build param
 		branch := sdk.ParameterFind(params, "git.branch")
@@ -32,10 +38,24 @@ func runCheckoutApplication(w *currentWorker) BuiltInAction {
 
 		//Prepare all options - clone options
 		var opts = &git.CloneOpts{
-			Recursive:               true,
 			NoStrictHostKeyChecking: true,
-			Depth: 50,
-			Tag:   tag,
+			Depth:                   50,
+			Tag:                     tag,
+			Submodules:              submodules != "false",
+			LFS:                     lfs == "true",
+			SparseCheckout:          sparseCheckoutPaths(sparse),
+		}
+		if depth != "" {
+			d, err := strconv.Atoi(depth)
+			if err != nil || d < 0 {
+				res := sdk.Result{
+					Status: sdk.StatusFail.String(),
+					Reason: fmt.Sprintf("Invalid depth %s, it must be a positive number", depth),
+				}
+				sendLog(res.Reason)
+				return res
+			}
+			opts.Depth = d
 		}
 		if branch != nil {
 			opts.Branch = branch.Value
@@ -60,6 +80,27 @@ func runCheckoutApplication(w *currentWorker) BuiltInAction {
 			dir = directory.Value
 		}
 
+		// the objects of big repositories are copied from the reference repository of the worker instead of being fetched
+		if w.gitCacheDir != "" {
+			ref, err := w.gitReference(gitURL, auth, sendLog)
+			if err != nil {
+				sendLog(fmt.Sprintf("Unable to update the reference repository, cloning without it: %v", err))
+			} else {
+				opts.Reference = ref
+			}
+		}
+
 		return gitClone(w, params, gitURL, dir, auth, opts, sendLog)
 	}
 }
+
+// sparseCheckoutPaths returns the directories to checkout, separated by commas or new lines
+func sparseCheckoutPaths(s string) []string {
+	var paths []string
+	for _, p := range strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == '\n' }) {
+		if p = strings.TrimSpace(p); p != "" {
+			paths = append(paths, p)
+		}
+	}
+	return paths
+}
@@ -1,10 +1,14 @@
 package main
 
 import (
+	"bufio"
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
+	"os"
 	"strconv"
+	"strings"
 
 	"github.com/sguiheux/go-coverage"
 
@@ -44,19 +48,7 @@ func runParseCoverageResultAction(w *currentWorker) BuiltInAction {
 			min_req = f
 		}
 
-		var parserMode coverage.CoverageMode
-		switch mode {
-		case string(coverage.COBERTURA):
-			parserMode = coverage.COBERTURA
-		case string(coverage.LCOV):
-			parserMode = coverage.LCOV
-		default:
-			res.Reason = fmt.Sprintf("Coverage parser: unknown format %s", mode)
-			sendLog(res.Reason)
-			return res
-		}
-		parser := coverage.New(p, parserMode)
-		report, errR := parser.Parse()
+		report, errR := parseCoverageReport(p, mode)
 		if errR != nil {
 			res.Reason = fmt.Sprintf("Coverage parser: unable to parse report: %v", errR)
 			sendLog(res.Reason)
@@ -99,3 +91,188 @@ func runParseCoverageResultAction(w *currentWorker) BuiltInAction {
 		return res
 	}
 }
+
+func runCoverageUpload(w *currentWorker) BuiltInAction {
+	return func(ctx context.Context, a *sdk.Action, buildID int64, params *[]sdk.Parameter, secrets []sdk.Variable, sendLog LoggerFunc) sdk.Result {
+		res := sdk.Result{Status: sdk.StatusSuccess.String()}
+
+		path := sdk.ParameterValue(a.Parameters, "path")
+		if path == "" {
+			res.Status = sdk.StatusFail.String()
+			res.Reason = "Coverage upload: path not provided"
+			sendLog(res.Reason)
+			return res
+		}
+
+		var minimum float64
+		if m := sdk.ParameterValue(a.Parameters, "minimum"); m != "" {
+			f, err := strconv.ParseFloat(m, 64)
+			if err != nil {
+				res.Status = sdk.StatusFail.String()
+				res.Reason = fmt.Sprintf("Coverage upload: wrong value for 'minimum': %v", err)
+				sendLog(res.Reason)
+				return res
+			}
+			minimum = f
+		}
+
+		format := sdk.ParameterValue(a.Parameters, "format")
+		if format == "" {
+			var err error
+			format, err = detectCoverageFormat(path)
+			if err != nil {
+				res.Status = sdk.StatusFail.String()
+				res.Reason = fmt.Sprintf("Coverage upload: %v", err)
+				sendLog(res.Reason)
+				return res
+			}
+		}
+
+		report, err := parseCoverageReport(path, format)
+		if err != nil {
+			res.Status = sdk.StatusFail.String()
+			res.Reason = fmt.Sprintf("Coverage upload: unable to parse %s report %s: %v", format, path, err)
+			sendLog(res.Reason)
+			return res
+		}
+
+		diff, err := w.client.QueueCoverageUpload(ctx, buildID, report)
+		if err != nil {
+			res.Status = sdk.StatusFail.String()
+			res.Reason = fmt.Sprintf("Coverage upload: failed to send coverage report: %v", err)
+			sendLog(res.Reason)
+			return res
+		}
+
+		msg := fmt.Sprintf("Coverage: %.2f%% of lines (%d/%d)", diff.Current.LinesPercent, diff.Current.CoveredLines, diff.Current.TotalLines)
+		if diff.DiffPrevious != nil {
+			msg += fmt.Sprintf(", %+.2f%% since previous run on %s", *diff.DiffPrevious, diff.Branch)
+		}
+		if diff.DiffDefaultBranch != nil {
+			msg += fmt.Sprintf(", %+.2f%% compared to default branch", *diff.DiffDefaultBranch)
+		}
+		sendLog(msg)
+
+		if minimum > 0 && diff.Current.LinesPercent < minimum {
+			res.Status = sdk.StatusFail.String()
+			res.Reason = fmt.Sprintf("Coverage: minimum coverage failed: %.2f%% < %.2f%%", diff.Current.LinesPercent, minimum)
+			sendLog(res.Reason)
+			return res
+		}
+
+		return res
+	}
+}
+
+// parseCoverageReport parses a coverage report file in one of the sdk.CoverageFormats
+func parseCoverageReport(path, format string) (coverage.Report, error) {
+	switch format {
+	case sdk.CoverageFormatCobertura:
+		return coverage.New(path, coverage.COBERTURA).Parse()
+	case sdk.CoverageFormatLCOV:
+		return coverage.New(path, coverage.LCOV).Parse()
+	case sdk.CoverageFormatGoCover:
+		f, err := os.Open(path)
+		if err != nil {
+			return coverage.Report{}, err
+		}
+		defer f.Close()
+		return parseGoCover(f)
+	}
+	return coverage.Report{}, fmt.Errorf("unknown format %s, expected one of: %s", format, strings.Join(sdk.CoverageFormats, ", "))
+}
+
+// detectCoverageFormat guesses the format of a coverage report file from its first line
+func detectCoverageFormat(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case line == "":
+			continue
+		case strings.HasPrefix(line, "mode:"):
+			return sdk.CoverageFormatGoCover, nil
+		case strings.HasPrefix(line, "<"):
+			return sdk.CoverageFormatCobertura, nil
+		case strings.HasPrefix(line, "TN:") || strings.HasPrefix(line, "SF:"):
+			return sdk.CoverageFormatLCOV, nil
+		}
+		break
+	}
+	if err := scanner.Err(); err != nil {
+		return "", err
+	}
+	return "", fmt.Errorf("unable to detect the format of %s, set the format parameter", path)
+}
+
+// parseGoCover parses a coverage profile generated by go test -coverprofile.
+// Go only reports statements: they are counted as lines. Blocks found several times, in merged profiles, are counted once.
+func parseGoCover(r io.Reader) (coverage.Report, error) {
+	var report coverage.Report
+
+	type block struct {
+		statements int
+		covered    bool
+	}
+	files := map[string]map[string]*block{}
+	var paths []string
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "mode:") {
+			continue
+		}
+		// name.go:line.column,line.column numberOfStatements count
+		i := strings.LastIndex(line, ":")
+		fields := strings.Fields(line[i+1:])
+		if i < 0 || len(fields) != 3 {
+			return report, fmt.Errorf("invalid line %q", line)
+		}
+		statements, err := strconv.Atoi(fields[1])
+		if err != nil {
+			return report, fmt.Errorf("invalid number of statements in line %q", line)
+		}
+		count, err := strconv.Atoi(fields[2])
+		if err != nil {
+			return report, fmt.Errorf("invalid count in line %q", line)
+		}
+
+		path := line[:i]
+		blocks, ok := files[path]
+		if !ok {
+			blocks = map[string]*block{}
+			files[path] = blocks
+			paths = append(paths, path)
+		}
+		b, ok := blocks[fields[0]]
+		if !ok {
+			b = &block{statements: statements}
+			blocks[fields[0]] = b
+		}
+		b.covered = b.covered || count > 0
+	}
+	if err := scanner.Err(); err != nil {
+		return report, err
+	}
+
+	for _, path := range paths {
+		f := coverage.FileReport{Path: path}
+		for _, b := range files[path] {
+			f.TotalLines += b.statements
+			if b.covered {
+				f.CoveredLines += b.statements
+			}
+		}
+		report.Files = append(report.Files, f)
+		report.TotalLines += f.TotalLines
+		report.CoveredLines += f.CoveredLines
+	}
+	return report, nil
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func TestParseGoCover(t *testing.T) {
+	profile := `mode: set
+github.com/ovh/cds/sdk/a.go:10.2,12.16 2 1
+github.com/ovh/cds/sdk/a.go:12.16,14.3 1 0
+github.com/ovh/cds/sdk/b.go:5.1,7.2 3 0
+github.com/ovh/cds/sdk/a.go:12.16,14.3 1 1
+`
+	report, err := parseGoCover(strings.NewReader(profile))
+	assert.NoError(t, err)
+	assert.Equal(t, 6, report.TotalLines)
+	assert.Equal(t, 3, report.CoveredLines)
+	assert.Len(t, report.Files, 2)
+	assert.Equal(t, "github.com/ovh/cds/sdk/a.go", report.Files[0].Path)
+	assert.Equal(t, 3, report.Files[0].CoveredLines)
+	assert.Equal(t, 0, report.Files[1].CoveredLines)
+
+	_, err = parseGoCover(strings.NewReader("mode: set\ngithub.com/ovh/cds/sdk/a.go:10.2,12.16 2\n"))
+	assert.Error(t, err)
+}
+
+func TestDetectCoverageFormat(t *testing.T) {
+	dir, err := ioutil.TempDir("", "coverage")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	for content, format := range map[string]string{
+		"mode: atomic\n":                            sdk.CoverageFormatGoCover,
+		"<?xml version=\"1.0\" ?>\n<coverage>\n":    sdk.CoverageFormatCobertura,
+		"\nTN:\nSF:/src/index.js\n":                 sdk.CoverageFormatLCOV,
+		"SF:/src/index.js\nDA:1,1\nend_of_record\n": sdk.CoverageFormatLCOV,
+	} {
+		path := filepath.Join(dir, "report")
+		assert.NoError(t, ioutil.WriteFile(path, []byte(content), 0644))
+		f, err := detectCoverageFormat(path)
+		assert.NoError(t, err)
+		assert.Equal(t, format, f)
+	}
+
+	path := filepath.Join(dir, "report")
+	assert.NoError(t, ioutil.WriteFile(path, []byte("coverage: 80%\n"), 0644))
+	_, err = detectCoverageFormat(path)
+	assert.Error(t, err)
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/golang/protobuf/ptypes/empty"
 
 	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/grpcplugin"
 	"github.com/ovh/cds/sdk/grpcplugin/integrationplugin"
 	"github.com/ovh/cds/sdk/log"
 )
@@ -111,6 +112,16 @@ func runDeployApplication(w *currentWorker) BuiltInAction {
 			return res
 		}
 
+		if err := grpcplugin.CheckCapabilities(manifest.Name, manifest.Capabilities); err != nil {
+			res := sdk.Result{
+				Reason: "Unable to run plugin... Aborting",
+				Status: sdk.StatusFail.String(),
+			}
+			integrationPluginClientStop(ctx, integrationPluginClient, done, stopLogs)
+			sendLog(err.Error())
+			return res
+		}
+
 		sendLog(fmt.Sprintf("# Plugin %s v%s is ready", manifest.Name, manifest.Version))
 
 		query := integrationplugin.DeployQuery{
@@ -0,0 +1,290 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// helmDeployment contains the options of a DeployHelm step
+type helmDeployment struct {
+	chart       string
+	releaseName string
+	namespace   string
+	repository  string
+	version     string
+	timeout     string
+	valuesFiles []string
+	set         []string
+	rollback    bool
+}
+
+// helmRelease is the status of a release, returned by helm status -o json
+type helmRelease struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+	Version   int    `json:"version"`
+	Info      struct {
+		Status      string `json:"status"`
+		Description string `json:"description"`
+	} `json:"info"`
+}
+
+// helmCmd runs the helm binary on the cluster of a kubeconfig file
+type helmCmd struct {
+	kubeconfig string
+	namespace  string
+	sendLog    LoggerFunc
+}
+
+func runDeployHelm(w *currentWorker) BuiltInAction {
+	return func(ctx context.Context, a *sdk.Action, buildID int64, params *[]sdk.Parameter, secrets []sdk.Variable, sendLog LoggerFunc) sdk.Result {
+		res := sdk.Result{Status: sdk.StatusSuccess.String()}
+
+		d := newHelmDeployment(a.Parameters)
+		if d.chart == "" {
+			res.Status = sdk.StatusFail.String()
+			res.Reason = "chart parameter is empty. aborting"
+			sendLog(res.Reason)
+			return res
+		}
+		if d.releaseName == "" {
+			res.Status = sdk.StatusFail.String()
+			res.Reason = "releaseName parameter is empty. aborting"
+			sendLog(res.Reason)
+			return res
+		}
+
+		kubeconfig, namespace, err := kubeconfigFromIntegration(*params, secrets)
+		if err != nil {
+			res.Status = sdk.StatusFail.String()
+			res.Reason = err.Error()
+			sendLog(res.Reason)
+			return res
+		}
+		if d.namespace == "" {
+			d.namespace = namespace
+		}
+
+		tmpDir, err := ioutil.TempDir(w.tmpDirectory(), "cds-helm")
+		if err != nil {
+			res.Status = sdk.StatusFail.String()
+			res.Reason = fmt.Sprintf("Cannot create temporary directory: %v", err)
+			sendLog(res.Reason)
+			return res
+		}
+		defer os.RemoveAll(tmpDir) // nolint
+
+		h := helmCmd{kubeconfig: filepath.Join(tmpDir, "kubeconfig"), namespace: d.namespace, sendLog: sendLog}
+		if err := ioutil.WriteFile(h.kubeconfig, []byte(kubeconfig), 0600); err != nil {
+			res.Status = sdk.StatusFail.String()
+			res.Reason = fmt.Sprintf("Cannot write kubeconfig: %v", err)
+			sendLog(res.Reason)
+			return res
+		}
+
+		// the values given in the step are applied after the values files
+		if values := sdk.ParameterValue(a.Parameters, "values"); strings.TrimSpace(values) != "" {
+			valuesFile := filepath.Join(tmpDir, "values.yaml")
+			if err := ioutil.WriteFile(valuesFile, []byte(values), 0600); err != nil {
+				res.Status = sdk.StatusFail.String()
+				res.Reason = fmt.Sprintf("Cannot write values: %v", err)
+				sendLog(res.Reason)
+				return res
+			}
+			d.valuesFiles = append(d.valuesFiles, valuesFile)
+		}
+
+		previous, _ := h.status(ctx, d.releaseName)
+
+		sendLog(fmt.Sprintf("Deploying chart %s as release %s in namespace %s", d.chart, d.releaseName, d.namespace))
+		if errDeploy := h.run(ctx, d.upgradeArgs()...); errDeploy != nil {
+			res.Status = sdk.StatusFail.String()
+			res.Reason = fmt.Sprintf("Deployment of release %s failed: %v", d.releaseName, errDeploy)
+			sendLog(res.Reason)
+
+			if !d.rollback {
+				return res
+			}
+			if err := h.rollbackOrUninstall(ctx, d, previous); err != nil {
+				sendLog(fmt.Sprintf("Cannot rollback release %s: %v", d.releaseName, err))
+				return res
+			}
+			var revision int
+			status := "uninstalled"
+			if release, err := h.status(ctx, d.releaseName); err == nil {
+				revision, status = release.Version, release.Info.Status
+			}
+			w.sendHelmSpawnInfo(ctx, buildID, sdk.MsgSpawnInfoHelmReleaseRolledBack, d.releaseName, revision, status)
+			return res
+		}
+
+		release, err := h.status(ctx, d.releaseName)
+		if err != nil {
+			res.Status = sdk.StatusFail.String()
+			res.Reason = fmt.Sprintf("Cannot get status of release %s: %v", d.releaseName, err)
+			sendLog(res.Reason)
+			return res
+		}
+		sendLog(fmt.Sprintf("Release %s deployed: revision %d, status %s", release.Name, release.Version, release.Info.Status))
+		w.sendHelmSpawnInfo(ctx, buildID, sdk.MsgSpawnInfoHelmReleaseDeployed, d.releaseName, d.namespace, release.Version, release.Info.Status)
+
+		return res
+	}
+}
+
+func newHelmDeployment(params []sdk.Parameter) helmDeployment {
+	d := helmDeployment{
+		chart:       strings.TrimSpace(sdk.ParameterValue(params, "chart")),
+		releaseName: strings.TrimSpace(sdk.ParameterValue(params, "releaseName")),
+		namespace:   strings.TrimSpace(sdk.ParameterValue(params, "namespace")),
+		repository:  strings.TrimSpace(sdk.ParameterValue(params, "repository")),
+		version:     strings.TrimSpace(sdk.ParameterValue(params, "version")),
+		timeout:     strings.TrimSpace(sdk.ParameterValue(params, "timeout")),
+		rollback:    sdk.ParameterValue(params, "rollback") != "false",
+	}
+	if d.timeout == "" {
+		d.timeout = "5m"
+	}
+	for _, f := range strings.Split(sdk.ParameterValue(params, "valuesFiles"), ",") {
+		if f = strings.TrimSpace(f); f != "" {
+			d.valuesFiles = append(d.valuesFiles, f)
+		}
+	}
+	for _, s := range strings.Split(sdk.ParameterValue(params, "set"), "\n") {
+		if s = strings.TrimSpace(s); s != "" {
+			d.set = append(d.set, s)
+		}
+	}
+	return d
+}
+
+// upgradeArgs returns the arguments of the helm command installing or upgrading the release
+func (d helmDeployment) upgradeArgs() []string {
+	args := []string{"upgrade", d.releaseName, d.chart, "--install", "--wait", "--timeout", d.timeout}
+	if d.repository != "" {
+		args = append(args, "--repo", d.repository)
+	}
+	if d.version != "" {
+		args = append(args, "--version", d.version)
+	}
+	for _, f := range d.valuesFiles {
+		args = append(args, "--values", f)
+	}
+	for _, s := range d.set {
+		args = append(args, "--set", s)
+	}
+	return args
+}
+
+// kubeconfigFromIntegration returns the kubeconfig and the default namespace of the Kubernetes integration of the pipeline context.
+// The kubeconfig is the one of the integration, or is built from the api url, the token and the certificate authority.
+func kubeconfigFromIntegration(params []sdk.Parameter, secrets []sdk.Variable) (string, string, error) {
+	namespace := sdk.ParameterValue(params, "cds.integration."+sdk.KubernetesConfigNamespace)
+	if namespace == "" {
+		namespace = "default"
+	}
+
+	if v := sdk.VariableFind(secrets, "cds.integration."+sdk.KubernetesConfigKubeconfig); v != nil && strings.TrimSpace(v.Value) != "" {
+		return v.Value, namespace, nil
+	}
+
+	apiURL := sdk.ParameterValue(params, "cds.integration."+sdk.KubernetesConfigAPIURL)
+	token := sdk.VariableFind(secrets, "cds.integration."+sdk.KubernetesConfigToken)
+	if apiURL == "" || token == nil || token.Value == "" {
+		return "", "", fmt.Errorf("there is no %s integration with a kubeconfig, or an api url and a token, in the pipeline context", sdk.KubernetesIntegrationModel)
+	}
+
+	cluster := map[string]interface{}{"server": apiURL}
+	if ca := strings.TrimSpace(sdk.ParameterValue(params, "cds.integration."+sdk.KubernetesConfigCA)); ca != "" {
+		cluster["certificate-authority-data"] = base64.StdEncoding.EncodeToString([]byte(ca + "\n"))
+	}
+	// json is valid yaml, and escapes the values
+	b, err := json.Marshal(map[string]interface{}{
+		"apiVersion":      "v1",
+		"kind":            "Config",
+		"clusters":        []interface{}{map[string]interface{}{"name": "cds", "cluster": cluster}},
+		"users":           []interface{}{map[string]interface{}{"name": "cds", "user": map[string]string{"token": token.Value}}},
+		"contexts":        []interface{}{map[string]interface{}{"name": "cds", "context": map[string]string{"cluster": "cds", "user": "cds", "namespace": namespace}}},
+		"current-context": "cds",
+	})
+	if err != nil {
+		return "", "", sdk.WithStack(err)
+	}
+	return string(b), namespace, nil
+}
+
+func (h helmCmd) command(ctx context.Context, args ...string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, "helm", append(args, "--namespace", h.namespace)...)
+	cmd.Env = append(os.Environ(), "KUBECONFIG="+h.kubeconfig)
+	return cmd
+}
+
+// run runs a helm command and sends its output in the logs of the step
+func (h helmCmd) run(ctx context.Context, args ...string) error {
+	log.Info("runDeployHelm> helm %s", strings.Join(args, " "))
+	cmd := h.command(ctx, args...)
+	r, wr := io.Pipe()
+	cmd.Stdout = wr
+	cmd.Stderr = wr
+
+	done := make(chan struct{})
+	go func() {
+		scanner := bufio.NewScanner(r)
+		for scanner.Scan() {
+			h.sendLog(scanner.Text())
+		}
+		close(done)
+	}()
+
+	err := cmd.Run()
+	wr.Close() // nolint
+	<-done
+	return err
+}
+
+func (h helmCmd) status(ctx context.Context, releaseName string) (*helmRelease, error) {
+	ctx, cancel := context.WithTimeout(ctx, time.Minute)
+	defer cancel()
+	out, err := h.command(ctx, "status", releaseName, "--output", "json").Output()
+	if err != nil {
+		return nil, err
+	}
+	var release helmRelease
+	if err := json.Unmarshal(out, &release); err != nil {
+		return nil, sdk.WithStack(err)
+	}
+	return &release, nil
+}
+
+// rollbackOrUninstall rollbacks the release to its previous revision, or uninstalls it if it was not installed before the deployment
+func (h helmCmd) rollbackOrUninstall(ctx context.Context, d helmDeployment, previous *helmRelease) error {
+	if previous == nil {
+		h.sendLog(fmt.Sprintf("Uninstalling release %s", d.releaseName))
+		return h.run(ctx, "uninstall", d.releaseName)
+	}
+	h.sendLog(fmt.Sprintf("Rolling back release %s to revision %d", d.releaseName, previous.Version))
+	return h.run(ctx, "rollback", d.releaseName, fmt.Sprintf("%d", previous.Version), "--wait", "--timeout", d.timeout)
+}
+
+func (w *currentWorker) sendHelmSpawnInfo(ctx context.Context, jobID int64, m *sdk.Message, args ...interface{}) {
+	info := sdk.SpawnInfo{
+		RemoteTime: time.Now(),
+		Message:    sdk.SpawnMsg{ID: m.ID, Args: args},
+	}
+	if err := w.client.QueueJobSendSpawnInfo(ctx, jobID, []sdk.SpawnInfo{info}); err != nil {
+		log.Warning("runDeployHelm> Cannot record spawn info for job %d: %v", jobID, err)
+	}
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func TestHelmDeploymentUpgradeArgs(t *testing.T) {
+	d := newHelmDeployment([]sdk.Parameter{
+		{Name: "chart", Value: "my-chart"},
+		{Name: "releaseName", Value: "my-app"},
+		{Name: "valuesFiles", Value: "values.yaml, values-prod.yaml,"},
+		{Name: "set", Value: "image.tag=1.2.3\n\nreplicas=2\n"},
+		{Name: "repository", Value: "https://charts.example.com"},
+		{Name: "version", Value: "0.1.0"},
+		{Name: "rollback", Value: "false"},
+	})
+	assert.False(t, d.rollback)
+	assert.Equal(t, []string{
+		"upgrade", "my-app", "my-chart", "--install", "--wait", "--timeout", "5m",
+		"--repo", "https://charts.example.com",
+		"--version", "0.1.0",
+		"--values", "values.yaml", "--values", "values-prod.yaml",
+		"--set", "image.tag=1.2.3", "--set", "replicas=2",
+	}, d.upgradeArgs())
+
+	d = newHelmDeployment([]sdk.Parameter{
+		{Name: "chart", Value: "./chart"},
+		{Name: "releaseName", Value: "my-app"},
+		{Name: "timeout", Value: "10m"},
+	})
+	assert.True(t, d.rollback)
+	assert.Equal(t, []string{"upgrade", "my-app", "./chart", "--install", "--wait", "--timeout", "10m"}, d.upgradeArgs())
+}
+
+func TestKubeconfigFromIntegration(t *testing.T) {
+	_, _, err := kubeconfigFromIntegration(nil, nil)
+	assert.Error(t, err)
+
+	kubeconfig, namespace, err := kubeconfigFromIntegration(
+		[]sdk.Parameter{{Name: "cds.integration.namespace", Value: "prod"}},
+		[]sdk.Variable{{Name: "cds.integration.kubeconfig", Value: "apiVersion: v1"}},
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, "apiVersion: v1", kubeconfig)
+	assert.Equal(t, "prod", namespace)
+
+	kubeconfig, namespace, err = kubeconfigFromIntegration(
+		[]sdk.Parameter{
+			{Name: "cds.integration.api url", Value: "https://k8s.example.com:6443"},
+			{Name: "cds.integration.certificate authority", Value: "-----BEGIN CERTIFICATE-----"},
+		},
+		[]sdk.Variable{{Name: "cds.integration.token", Value: "my-token"}},
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, "default", namespace)
+
+	var config struct {
+		Clusters []struct {
+			Cluster struct {
+				Server                   string `json:"server"`
+				CertificateAuthorityData string `json:"certificate-authority-data"`
+			} `json:"cluster"`
+		} `json:"clusters"`
+		Users []struct {
+			User struct {
+				Token string `json:"token"`
+			} `json:"user"`
+		} `json:"users"`
+		CurrentContext string `json:"current-context"`
+	}
+	assert.NoError(t, json.Unmarshal([]byte(kubeconfig), &config))
+	assert.Len(t, config.Clusters, 1)
+	assert.Equal(t, "https://k8s.example.com:6443", config.Clusters[0].Cluster.Server)
+	ca, err := base64.StdEncoding.DecodeString(config.Clusters[0].Cluster.CertificateAuthorityData)
+	assert.NoError(t, err)
+	assert.Equal(t, "-----BEGIN CERTIFICATE-----\n", string(ca))
+	assert.Len(t, config.Users, 1)
+	assert.Equal(t, "my-token", config.Users[0].User.Token)
+	assert.Equal(t, "cds", config.CurrentContext)
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func runPromote(w *currentWorker) BuiltInAction {
+	return func(ctx context.Context, a *sdk.Action, buildID int64, params *[]sdk.Parameter, secrets []sdk.Variable, sendLog LoggerFunc) sdk.Result {
+		pkey := sdk.ParameterValue(*params, "cds.project")
+		wName := sdk.ParameterValue(*params, "cds.workflow")
+		workflowNum := sdk.ParameterValue(*params, "cds.run.number")
+
+		if pkey == "" || wName == "" || workflowNum == "" {
+			res := sdk.Result{
+				Status: sdk.StatusFail.String(),
+				Reason: "cds.project, cds.workflow or cds.run.number variable not found.",
+			}
+			sendLog(res.Reason)
+			return res
+		}
+
+		wRunNumber, errI := strconv.ParseInt(workflowNum, 10, 64)
+		if errI != nil {
+			res := sdk.Result{
+				Status: sdk.StatusFail.String(),
+				Reason: fmt.Sprintf("Workflow number is not a number. Got %s: %s", workflowNum, errI),
+			}
+			sendLog(res.Reason)
+			return res
+		}
+
+		req := sdk.WorkflowNodeRunPromote{
+			Integration: sdk.ParameterValue(a.Parameters, "integration"),
+			Status:      sdk.ParameterValue(a.Parameters, "status"),
+			BuildName:   sdk.ParameterValue(a.Parameters, "buildName"),
+			BuildNumber: sdk.ParameterValue(a.Parameters, "buildNumber"),
+			Comment:     sdk.ParameterValue(a.Parameters, "comment"),
+			Copy:        sdk.ParameterValue(a.Parameters, "copy") == "true",
+		}
+		if req.Status == "" {
+			res := sdk.Result{
+				Status: sdk.StatusFail.String(),
+				Reason: "Promotion status is not set. Nothing to perform.",
+			}
+			sendLog(res.Reason)
+			return res
+		}
+
+		promotion, err := w.client.WorkflowNodeRunPromote(pkey, wName, wRunNumber, w.currentJob.wJob.WorkflowNodeRunID, req)
+		if err != nil {
+			res := sdk.Result{
+				Status: sdk.StatusFail.String(),
+				Reason: fmt.Sprintf("Cannot promote workflow node run: %s", err),
+			}
+			sendLog(res.Reason)
+			return res
+		}
+
+		sendLog(fmt.Sprintf("Build %s #%s promoted to %s with integration %s: %s -> %s", promotion.BuildName, promotion.BuildNumber, promotion.Status, promotion.Integration, promotion.SourceRepository, promotion.TargetRepository))
+		return sdk.Result{Status: sdk.StatusSuccess.String()}
+	}
+}
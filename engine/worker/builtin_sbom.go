@@ -0,0 +1,104 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os/exec"
+	"strings"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+func runGenerateSBOM(w *currentWorker) BuiltInAction {
+	return func(ctx context.Context, a *sdk.Action, buildID int64, params *[]sdk.Parameter, secrets []sdk.Variable, sendLog LoggerFunc) sdk.Result {
+		res := sdk.Result{Status: sdk.StatusSuccess.String()}
+
+		source := sdk.ParameterValue(a.Parameters, "source")
+		if source == "" {
+			source = "."
+		}
+		name := sdk.ParameterValue(a.Parameters, "name")
+		if name == "" {
+			name = source
+		}
+		format := sdk.ParameterValue(a.Parameters, "format")
+		if format == "" {
+			format = sdk.SBOMFormatSPDX
+		}
+		if !sdk.IsInArray(format, sdk.SBOMFormats) {
+			res.Status = sdk.StatusFail.String()
+			res.Reason = fmt.Sprintf("Invalid format %s, expected one of: %s", format, strings.Join(sdk.SBOMFormats, ", "))
+			sendLog(res.Reason)
+			return res
+		}
+
+		document, err := syftGenerate(ctx, source, format, sendLog)
+		if err != nil {
+			res.Status = sdk.StatusFail.String()
+			res.Reason = fmt.Sprintf("Cannot generate the SBOM of %s: %v", source, err)
+			sendLog(res.Reason)
+			return res
+		}
+
+		_, components, err := sdk.ParseSBOM(document)
+		if err != nil {
+			res.Status = sdk.StatusFail.String()
+			res.Reason = fmt.Sprintf("Cannot read the SBOM of %s: %v", source, err)
+			sendLog(res.Reason)
+			return res
+		}
+
+		if output := sdk.ParameterValue(a.Parameters, "output"); output != "" {
+			if err := ioutil.WriteFile(output, document, 0644); err != nil {
+				res.Status = sdk.StatusFail.String()
+				res.Reason = fmt.Sprintf("Cannot write the SBOM in %s: %v", output, err)
+				sendLog(res.Reason)
+				return res
+			}
+		}
+
+		if _, err := w.client.QueueSBOMUpload(ctx, buildID, name, document); err != nil {
+			res.Status = sdk.StatusFail.String()
+			res.Reason = fmt.Sprintf("Cannot attach the SBOM %s to the run: %v", name, err)
+			sendLog(res.Reason)
+			return res
+		}
+		sendLog(fmt.Sprintf("SBOM %s (%s) with %d components attached to the run", name, format, len(components)))
+
+		return res
+	}
+}
+
+// syftGenerate runs syft on a directory, an archive or an image and returns the SBOM document
+func syftGenerate(ctx context.Context, source, format string, sendLog LoggerFunc) ([]byte, error) {
+	args := []string{source, "-o", format, "-q"}
+	log.Info("syft> %s", strings.Join(args, " "))
+
+	cmd := exec.CommandContext(ctx, "syft", args...)
+	var stdout bytes.Buffer
+	r, wr := io.Pipe()
+	cmd.Stdout = &stdout
+	cmd.Stderr = wr
+
+	done := make(chan struct{})
+	go func() {
+		scanner := bufio.NewScanner(r)
+		for scanner.Scan() {
+			sendLog(scanner.Text())
+		}
+		close(done)
+	}()
+
+	err := cmd.Run()
+	wr.Close() // nolint
+	<-done
+	if err != nil {
+		return nil, err
+	}
+	return stdout.Bytes(), nil
+}
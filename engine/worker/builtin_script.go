@@ -19,6 +19,7 @@ import (
 func runScriptAction(w *currentWorker) BuiltInAction {
 	return func(ctx context.Context, a *sdk.Action, buildID int64, params *[]sdk.Parameter, secrets []sdk.Variable, sendLog LoggerFunc) sdk.Result {
 		chanRes := make(chan sdk.Result)
+		usage := w.currentJob.stepUsage
 
 		go func() {
 			res := sdk.Result{Status: sdk.StatusSuccess.String()}
@@ -62,7 +63,7 @@ func runScriptAction(w *currentWorker) BuiltInAction {
 			}
 
 			// Create a tmp file
-			tmpscript, errt := ioutil.TempFile(w.basedir, "cds-")
+			tmpscript, errt := ioutil.TempFile(w.tmpDirectory(), "cds-")
 			if errt != nil {
 				log.Warning("Cannot create tmp file: %s", errt)
 				res.Reason = fmt.Sprintf("cannot create temporary file, aborting\n")
@@ -138,13 +139,10 @@ func runScriptAction(w *currentWorker) BuiltInAction {
 			// worker export http port
 			cmd.Env = append(cmd.Env, fmt.Sprintf("%s=%d", WorkerServerPort, w.exportPort))
 
-			//DEPRECATED - BEGIN
-			// manage keys
-			if w.currentJob.pkey != "" && w.currentJob.gitsshPath != "" {
-				cmd.Env = append(cmd.Env, fmt.Sprintf("PKEY=%s", w.currentJob.pkey))
-				cmd.Env = append(cmd.Env, fmt.Sprintf("GIT_SSH=%s", w.currentJob.gitsshPath))
+			// the pgp keys installed by the step are imported in its own keyring
+			if w.currentJob.gnupgHome != "" {
+				cmd.Env = append(cmd.Env, fmt.Sprintf("GNUPGHOME=%s", w.currentJob.gnupgHome))
 			}
-			//DEPRECATED - END
 
 			//set up environment variables from pipeline build job parameters
 			for _, p := range *params {
@@ -241,7 +239,9 @@ func runScriptAction(w *currentWorker) BuiltInAction {
 
 			<-outchan
 			<-errchan
-			if err := cmd.Wait(); err != nil {
+			err = cmd.Wait()
+			usage.addProcess(cmd.ProcessState)
+			if err != nil {
 				res.Reason = fmt.Sprintf("%s\n", err)
 				sendLog(res.Reason)
 				res.Status = sdk.StatusFail.String()
@@ -0,0 +1,254 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// sshHost is a remote host of a SSHDeploy step
+type sshHost struct {
+	User string
+	Host string
+	Port string
+}
+
+func (h sshHost) String() string {
+	s := h.Host
+	if h.User != "" {
+		s = h.User + "@" + s
+	}
+	if h.Port != "" {
+		s += ":" + h.Port
+	}
+	return s
+}
+
+// target returns the destination of the ssh and rsync commands
+func (h sshHost) target() string {
+	if h.User != "" {
+		return h.User + "@" + h.Host
+	}
+	return h.Host
+}
+
+// sshDeployment runs the ssh and rsync commands of a SSHDeploy step
+type sshDeployment struct {
+	keyFile        string
+	knownHostsFile string
+	secrets        []sdk.Variable
+	sendLog        LoggerFunc
+}
+
+func runSSHDeploy(w *currentWorker) BuiltInAction {
+	return func(ctx context.Context, a *sdk.Action, buildID int64, params *[]sdk.Parameter, secrets []sdk.Variable, sendLog LoggerFunc) sdk.Result {
+		res := sdk.Result{Status: sdk.StatusSuccess.String()}
+
+		hosts, err := parseSSHHosts(sshDeployParameter(a.Parameters, "hosts"), sshDeployParameter(a.Parameters, "user"))
+		if err != nil {
+			res.Status = sdk.StatusFail.String()
+			res.Reason = err.Error()
+			sendLog(res.Reason)
+			return res
+		}
+		if len(hosts) == 0 {
+			res.Status = sdk.StatusFail.String()
+			res.Reason = "hosts parameter is empty. aborting"
+			sendLog(res.Reason)
+			return res
+		}
+
+		knownHosts := sshDeployParameter(a.Parameters, "knownHosts")
+		if knownHosts == "" {
+			res.Status = sdk.StatusFail.String()
+			res.Reason = "knownHosts parameter is empty: the keys of the hosts must be known. aborting"
+			sendLog(res.Reason)
+			return res
+		}
+
+		source := sshDeployParameter(a.Parameters, "source")
+		destination := sshDeployParameter(a.Parameters, "destination")
+		command := sshDeployParameter(a.Parameters, "command")
+		if source == "" && command == "" {
+			res.Status = sdk.StatusFail.String()
+			res.Reason = "source and command parameters are empty. Nothing to perform."
+			sendLog(res.Reason)
+			return res
+		}
+		if source != "" && destination == "" {
+			res.Status = sdk.StatusFail.String()
+			res.Reason = "destination parameter is empty. aborting"
+			sendLog(res.Reason)
+			return res
+		}
+
+		keyName := sdk.ParameterValue(a.Parameters, "privateKey")
+		key := sdk.VariableFind(secrets, "cds.key."+keyName+".priv")
+		if keyName == "" || key == nil {
+			res.Status = sdk.StatusFail.String()
+			res.Reason = fmt.Sprintf("SSH key %s not found. aborting", keyName)
+			sendLog(res.Reason)
+			return res
+		}
+
+		tmpDir, err := ioutil.TempDir(w.tmpDirectory(), "cds-ssh-deploy")
+		if err != nil {
+			res.Status = sdk.StatusFail.String()
+			res.Reason = fmt.Sprintf("Cannot create temporary directory: %v", err)
+			sendLog(res.Reason)
+			return res
+		}
+		defer os.RemoveAll(tmpDir) // nolint
+
+		d := sshDeployment{
+			keyFile:        filepath.Join(tmpDir, "id"),
+			knownHostsFile: filepath.Join(tmpDir, "known_hosts"),
+			secrets:        secrets,
+			sendLog:        sendLog,
+		}
+		if err := ioutil.WriteFile(d.keyFile, []byte(key.Value), 0600); err != nil {
+			res.Status = sdk.StatusFail.String()
+			res.Reason = fmt.Sprintf("Cannot write SSH key: %v", err)
+			sendLog(res.Reason)
+			return res
+		}
+		if err := ioutil.WriteFile(d.knownHostsFile, []byte(knownHosts+"\n"), 0600); err != nil {
+			res.Status = sdk.StatusFail.String()
+			res.Reason = fmt.Sprintf("Cannot write known hosts: %v", err)
+			sendLog(res.Reason)
+			return res
+		}
+
+		rsyncOpts := strings.Fields(sdk.ParameterValue(a.Parameters, "rsyncOpts"))
+		for _, h := range hosts {
+			if source != "" {
+				sendLog(fmt.Sprintf("Copying %s to %s:%s", source, h, destination))
+				if err := d.run(ctx, nil, "rsync", d.rsyncArgs(h, rsyncOpts, source, destination)...); err != nil {
+					res.Status = sdk.StatusFail.String()
+					res.Reason = fmt.Sprintf("Copy to %s failed: %v", h, err)
+					sendLog(res.Reason)
+					return res
+				}
+			}
+			if command != "" {
+				sendLog(fmt.Sprintf("Running commands on %s", h))
+				// the commands are given on stdin, they are not displayed in the processes of the worker
+				if err := d.run(ctx, strings.NewReader(command+"\n"), "ssh", append(d.sshArgs(h), h.target(), "sh -e -s")...); err != nil {
+					res.Status = sdk.StatusFail.String()
+					res.Reason = fmt.Sprintf("Commands failed on %s: %v", h, err)
+					sendLog(res.Reason)
+					return res
+				}
+			}
+		}
+
+		return res
+	}
+}
+
+// sshDeployParameter returns the value of a parameter, or nothing if its default value has not been interpolated
+func sshDeployParameter(params []sdk.Parameter, name string) string {
+	v := strings.TrimSpace(sdk.ParameterValue(params, name))
+	if strings.HasPrefix(v, "{{") {
+		return ""
+	}
+	return v
+}
+
+// parseSSHHosts parses a list of hosts, separated by a comma or a new line: [user@]host[:port]
+func parseSSHHosts(s, defaultUser string) ([]sshHost, error) {
+	var hosts []sshHost
+	for _, line := range strings.Split(s, "\n") {
+		for _, h := range strings.Split(line, ",") {
+			h = strings.TrimSpace(h)
+			if h == "" {
+				continue
+			}
+			host := sshHost{User: defaultUser, Host: h}
+			if i := strings.LastIndex(host.Host, "@"); i >= 0 {
+				host.User, host.Host = host.Host[:i], host.Host[i+1:]
+			}
+			if strings.Contains(host.Host, "]") || strings.Count(host.Host, ":") == 1 {
+				hostname, port, err := net.SplitHostPort(host.Host)
+				if err != nil {
+					return nil, fmt.Errorf("invalid host %s: %v", h, err)
+				}
+				host.Host, host.Port = hostname, port
+			}
+			if host.Host == "" {
+				return nil, fmt.Errorf("invalid host %s", h)
+			}
+			hosts = append(hosts, host)
+		}
+	}
+	return hosts, nil
+}
+
+// sshArgs returns the options of ssh: the key of the step, and only the known host keys
+func (d sshDeployment) sshArgs(h sshHost) []string {
+	args := []string{
+		"-i", d.keyFile,
+		"-o", "IdentitiesOnly=yes",
+		"-o", "BatchMode=yes",
+		"-o", "StrictHostKeyChecking=yes",
+		"-o", "UserKnownHostsFile=" + d.knownHostsFile,
+	}
+	if h.Port != "" {
+		args = append(args, "-p", h.Port)
+	}
+	return args
+}
+
+func (d sshDeployment) rsyncArgs(h sshHost, opts []string, source, destination string) []string {
+	args := append([]string{}, opts...)
+	args = append(args, "-e", "ssh "+strings.Join(d.sshArgs(h), " "), source, h.target()+":"+destination)
+	return args
+}
+
+// run runs a command and sends its output, without the secrets, in the logs of the step
+func (d sshDeployment) run(ctx context.Context, stdin io.Reader, name string, args ...string) error {
+	log.Info("runSSHDeploy> %s %s", name, strings.Join(args, " "))
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Stdin = stdin
+	r, wr := io.Pipe()
+	cmd.Stdout = wr
+	cmd.Stderr = wr
+
+	done := make(chan struct{})
+	go func() {
+		scanner := bufio.NewScanner(r)
+		for scanner.Scan() {
+			d.sendLog(maskSecrets(scanner.Text(), d.secrets))
+		}
+		close(done)
+	}()
+
+	err := cmd.Run()
+	wr.Close() // nolint
+	<-done
+	return err
+}
+
+// maskSecrets replaces the values of the secrets in a line of logs
+func maskSecrets(line string, secrets []sdk.Variable) string {
+	for _, s := range secrets {
+		for _, v := range strings.Split(s.Value, "\n") {
+			// short values would mask too many words
+			if v = strings.TrimSpace(v); len(v) < 6 {
+				continue
+			}
+			line = strings.Replace(line, v, sdk.PasswordPlaceholder, -1)
+		}
+	}
+	return line
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func TestParseSSHHosts(t *testing.T) {
+	hosts, err := parseSSHHosts("web1.example.com, admin@web2.example.com:2222\n\n10.0.0.3\n[2001:db8::1]:22", "deploy")
+	assert.NoError(t, err)
+	assert.Equal(t, []sshHost{
+		{User: "deploy", Host: "web1.example.com"},
+		{User: "admin", Host: "web2.example.com", Port: "2222"},
+		{User: "deploy", Host: "10.0.0.3"},
+		{User: "deploy", Host: "2001:db8::1", Port: "22"},
+	}, hosts)
+	assert.Equal(t, "admin@web2.example.com:2222", hosts[1].String())
+	assert.Equal(t, "admin@web2.example.com", hosts[1].target())
+
+	hosts, err = parseSSHHosts("web1.example.com", "")
+	assert.NoError(t, err)
+	assert.Equal(t, "web1.example.com", hosts[0].target())
+
+	_, err = parseSSHHosts("deploy@:22", "")
+	assert.Error(t, err)
+}
+
+func TestSSHDeploymentArgs(t *testing.T) {
+	d := sshDeployment{keyFile: "/tmp/id", knownHostsFile: "/tmp/known_hosts"}
+	h := sshHost{User: "deploy", Host: "web1.example.com", Port: "2222"}
+	assert.Equal(t, []string{
+		"-az", "--delete",
+		"-e", "ssh -i /tmp/id -o IdentitiesOnly=yes -o BatchMode=yes -o StrictHostKeyChecking=yes -o UserKnownHostsFile=/tmp/known_hosts -p 2222",
+		"./dist/", "deploy@web1.example.com:/var/www",
+	}, d.rsyncArgs(h, []string{"-az", "--delete"}, "./dist/", "/var/www"))
+}
+
+func TestMaskSecrets(t *testing.T) {
+	secrets := []sdk.Variable{
+		{Name: "cds.proj.password", Value: "my-password"},
+		{Name: "cds.app.short", Value: "abc"},
+		{Name: "cds.key.app-deploy.priv", Value: "-----BEGIN KEY-----\nc2VjcmV0LWtleQ==\n-----END KEY-----"},
+	}
+	assert.Equal(t, "login with **********, abc", maskSecrets("login with my-password, abc", secrets))
+	assert.Equal(t, "key: **********", maskSecrets("key: c2VjcmV0LWtleQ==", secrets))
+}
@@ -0,0 +1,375 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+const (
+	terraformArtifactTag       = "terraform"
+	terraformPlanSummaryVar    = "cds.build.terraform.plan.summary"
+	terraformPlanHasChangesVar = "cds.build.terraform.plan.changes"
+)
+
+// terraformCmd runs the terraform binary in the directory of a configuration
+type terraformCmd struct {
+	dir     string
+	env     []string
+	sendLog LoggerFunc
+}
+
+// terraformPlanSummary counts the resource changes of a plan
+type terraformPlanSummary struct {
+	Add     int
+	Change  int
+	Destroy int
+}
+
+func (s terraformPlanSummary) String() string {
+	return fmt.Sprintf("Plan: %d to add, %d to change, %d to destroy.", s.Add, s.Change, s.Destroy)
+}
+
+// HasChanges returns true if the plan changes at least one resource
+func (s terraformPlanSummary) HasChanges() bool {
+	return s.Add+s.Change+s.Destroy > 0
+}
+
+func runTerraformPlan(w *currentWorker) BuiltInAction {
+	return func(ctx context.Context, a *sdk.Action, buildID int64, params *[]sdk.Parameter, secrets []sdk.Variable, sendLog LoggerFunc) sdk.Result {
+		res := sdk.Result{Status: sdk.StatusSuccess.String()}
+
+		planName := terraformPlanName(a.Parameters)
+		tf, err := newTerraformCmd(a.Parameters, *params, secrets, sendLog)
+		if err != nil {
+			res.Status = sdk.StatusFail.String()
+			res.Reason = err.Error()
+			sendLog(res.Reason)
+			return res
+		}
+
+		if err := tf.init(ctx, *params, sdk.ParameterValue(a.Parameters, "workspace"), true); err != nil {
+			res.Status = sdk.StatusFail.String()
+			res.Reason = err.Error()
+			sendLog(res.Reason)
+			return res
+		}
+
+		if err := tf.run(ctx, terraformPlanArgs(a.Parameters, planName)...); err != nil {
+			res.Status = sdk.StatusFail.String()
+			res.Reason = fmt.Sprintf("terraform plan failed: %v", err)
+			sendLog(res.Reason)
+			return res
+		}
+
+		out, err := tf.output(ctx, "show", "-json", planName)
+		if err != nil {
+			res.Status = sdk.StatusFail.String()
+			res.Reason = fmt.Sprintf("Cannot read the plan %s: %v", planName, err)
+			sendLog(res.Reason)
+			return res
+		}
+		summary, err := parseTerraformPlanSummary(out)
+		if err != nil {
+			res.Status = sdk.StatusFail.String()
+			res.Reason = fmt.Sprintf("Cannot read the plan %s: %v", planName, err)
+			sendLog(res.Reason)
+			return res
+		}
+		sendLog(summary.String())
+
+		planPath := filepath.Join(tf.dir, planName)
+		if _, _, err := w.client.QueueArtifactUpload(ctx, buildID, terraformArtifactTag, planPath, ""); err != nil {
+			res.Status = sdk.StatusFail.String()
+			res.Reason = fmt.Sprintf("Cannot upload the plan %s: %v", planName, err)
+			sendLog(res.Reason)
+			return res
+		}
+		sendLog(fmt.Sprintf("Plan %s uploaded as an artifact of the run", planName))
+
+		// the summary is given to the children nodes, it is displayed before running manually the apply
+		for _, v := range []sdk.Variable{
+			{Name: terraformPlanSummaryVar, Type: sdk.StringVariable, Value: summary.String()},
+			{Name: terraformPlanHasChangesVar, Type: sdk.StringVariable, Value: strconv.FormatBool(summary.HasChanges())},
+		} {
+			if _, err := w.addVariableInPipelineBuild(v, params); err != nil {
+				res.Status = sdk.StatusFail.String()
+				res.Reason = fmt.Sprintf("Cannot export variable %s: %v", v.Name, err)
+				sendLog(res.Reason)
+				return res
+			}
+		}
+
+		info := sdk.SpawnInfo{
+			RemoteTime: time.Now(),
+			Message:    sdk.SpawnMsg{ID: sdk.MsgSpawnInfoTerraformPlan.ID, Args: []interface{}{planName, summary.Add, summary.Change, summary.Destroy}},
+		}
+		if err := w.client.QueueJobSendSpawnInfo(ctx, buildID, []sdk.SpawnInfo{info}); err != nil {
+			log.Warning("runTerraformPlan> Cannot record spawn info for job %d: %v", buildID, err)
+		}
+
+		return res
+	}
+}
+
+func runTerraformApply(w *currentWorker) BuiltInAction {
+	return func(ctx context.Context, a *sdk.Action, buildID int64, params *[]sdk.Parameter, secrets []sdk.Variable, sendLog LoggerFunc) sdk.Result {
+		res := sdk.Result{Status: sdk.StatusSuccess.String()}
+
+		if err := checkTerraformApproval(a.Parameters, *params); err != nil {
+			res.Status = sdk.StatusFail.String()
+			res.Reason = err.Error()
+			sendLog(res.Reason)
+			return res
+		}
+		for _, p := range *params {
+			if strings.HasSuffix(p.Name, ".build.terraform.plan.summary") {
+				sendLog(fmt.Sprintf("%s %s", p.Name, p.Value))
+			}
+		}
+
+		planName := terraformPlanName(a.Parameters)
+		tf, err := newTerraformCmd(a.Parameters, *params, secrets, sendLog)
+		if err != nil {
+			res.Status = sdk.StatusFail.String()
+			res.Reason = err.Error()
+			sendLog(res.Reason)
+			return res
+		}
+
+		if err := w.downloadTerraformPlan(*params, planName, filepath.Join(tf.dir, planName)); err != nil {
+			res.Status = sdk.StatusFail.String()
+			res.Reason = err.Error()
+			sendLog(res.Reason)
+			return res
+		}
+
+		if err := tf.init(ctx, *params, sdk.ParameterValue(a.Parameters, "workspace"), false); err != nil {
+			res.Status = sdk.StatusFail.String()
+			res.Reason = err.Error()
+			sendLog(res.Reason)
+			return res
+		}
+
+		if err := tf.run(ctx, "apply", "-input=false", "-no-color", planName); err != nil {
+			res.Status = sdk.StatusFail.String()
+			res.Reason = fmt.Sprintf("terraform apply failed: %v", err)
+			sendLog(res.Reason)
+			return res
+		}
+
+		return res
+	}
+}
+
+func terraformPlanName(params []sdk.Parameter) string {
+	if planName := strings.TrimSpace(sdk.ParameterValue(params, "planName")); planName != "" {
+		return planName
+	}
+	return "terraform.tfplan"
+}
+
+// terraformPlanArgs returns the arguments of the terraform plan command
+func terraformPlanArgs(params []sdk.Parameter, planName string) []string {
+	args := []string{"plan", "-input=false", "-no-color", "-out=" + planName}
+	for _, f := range strings.Split(sdk.ParameterValue(params, "varFiles"), ",") {
+		if f = strings.TrimSpace(f); f != "" {
+			args = append(args, "-var-file="+f)
+		}
+	}
+	for _, v := range strings.Split(sdk.ParameterValue(params, "vars"), "\n") {
+		if v = strings.TrimSpace(v); v != "" {
+			args = append(args, "-var", v)
+		}
+	}
+	return args
+}
+
+// terraformBackendConfigArgs returns the -backend-config arguments of terraform init from the Terraform integration
+func terraformBackendConfigArgs(params []sdk.Parameter) []string {
+	var args []string
+	for _, line := range strings.Split(sdk.ParameterValue(params, "cds.integration."+sdk.TerraformConfigBackendConfig), "\n") {
+		if line = strings.TrimSpace(line); line != "" && !strings.HasPrefix(line, "#") {
+			args = append(args, "-backend-config="+line)
+		}
+	}
+	return args
+}
+
+// terraformEnv returns the environment variables of the credentials of the Terraform integration
+func terraformEnv(secrets []sdk.Variable) ([]string, error) {
+	env := []string{"TF_IN_AUTOMATION=1", "TF_INPUT=0"}
+	v := sdk.VariableFind(secrets, "cds.integration."+sdk.TerraformConfigCredentials)
+	if v == nil {
+		return env, nil
+	}
+	for _, line := range strings.Split(v.Value, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if i := strings.Index(line, "="); i <= 0 {
+			return nil, fmt.Errorf("invalid credentials in the %s integration: a line is not KEY=value", sdk.TerraformIntegrationModel)
+		}
+		env = append(env, line)
+	}
+	return env, nil
+}
+
+// checkTerraformApproval checks that the pipeline has been run manually, unless the approval is disabled
+func checkTerraformApproval(actionParams, params []sdk.Parameter) error {
+	if sdk.ParameterValue(actionParams, "approval") == "false" {
+		return nil
+	}
+	if sdk.ParameterValue(params, "cds.manual") != "true" {
+		return fmt.Errorf("the plan must be approved before being applied: run this pipeline manually, or disable the approval of the step")
+	}
+	return nil
+}
+
+// parseTerraformPlanSummary counts the resource changes of the output of terraform show -json
+func parseTerraformPlanSummary(b []byte) (terraformPlanSummary, error) {
+	var plan struct {
+		ResourceChanges []struct {
+			Change struct {
+				Actions []string `json:"actions"`
+			} `json:"change"`
+		} `json:"resource_changes"`
+	}
+	var s terraformPlanSummary
+	if err := json.Unmarshal(b, &plan); err != nil {
+		return s, sdk.WithStack(err)
+	}
+	for _, rc := range plan.ResourceChanges {
+		for _, action := range rc.Change.Actions {
+			switch action {
+			case "create":
+				s.Add++
+			case "update":
+				s.Change++
+			case "delete":
+				s.Destroy++
+			}
+		}
+	}
+	return s, nil
+}
+
+func newTerraformCmd(actionParams, params []sdk.Parameter, secrets []sdk.Variable, sendLog LoggerFunc) (*terraformCmd, error) {
+	dir := strings.TrimSpace(sdk.ParameterValue(actionParams, "directory"))
+	if dir == "" {
+		dir = "."
+	}
+	if fi, err := os.Stat(dir); err != nil || !fi.IsDir() {
+		return nil, fmt.Errorf("directory %s not found", dir)
+	}
+	env, err := terraformEnv(secrets)
+	if err != nil {
+		return nil, err
+	}
+	return &terraformCmd{dir: dir, env: append(os.Environ(), env...), sendLog: sendLog}, nil
+}
+
+func (tf *terraformCmd) command(ctx context.Context, args ...string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, "terraform", args...)
+	cmd.Dir = tf.dir
+	cmd.Env = tf.env
+	return cmd
+}
+
+// init initializes the configuration and selects the workspace
+func (tf *terraformCmd) init(ctx context.Context, params []sdk.Parameter, workspace string, createWorkspace bool) error {
+	args := append([]string{"init", "-input=false", "-no-color"}, terraformBackendConfigArgs(params)...)
+	if err := tf.run(ctx, args...); err != nil {
+		return fmt.Errorf("terraform init failed: %v", err)
+	}
+	workspace = strings.TrimSpace(workspace)
+	if workspace == "" {
+		return nil
+	}
+	if err := tf.run(ctx, "workspace", "select", "-no-color", workspace); err != nil {
+		if !createWorkspace {
+			return fmt.Errorf("cannot select workspace %s: %v", workspace, err)
+		}
+		if err := tf.run(ctx, "workspace", "new", "-no-color", workspace); err != nil {
+			return fmt.Errorf("cannot create workspace %s: %v", workspace, err)
+		}
+	}
+	return nil
+}
+
+// run runs a terraform command and sends its output in the logs of the step
+func (tf *terraformCmd) run(ctx context.Context, args ...string) error {
+	log.Info("terraform> %s", strings.Join(args, " "))
+	cmd := tf.command(ctx, args...)
+	r, wr := io.Pipe()
+	cmd.Stdout = wr
+	cmd.Stderr = wr
+
+	done := make(chan struct{})
+	go func() {
+		scanner := bufio.NewScanner(r)
+		for scanner.Scan() {
+			tf.sendLog(scanner.Text())
+		}
+		close(done)
+	}()
+
+	err := cmd.Run()
+	wr.Close() // nolint
+	<-done
+	return err
+}
+
+func (tf *terraformCmd) output(ctx context.Context, args ...string) ([]byte, error) {
+	return tf.command(ctx, args...).Output()
+}
+
+// downloadTerraformPlan downloads the last plan file uploaded in the run
+func (w *currentWorker) downloadTerraformPlan(params []sdk.Parameter, planName, dest string) error {
+	project := sdk.ParameterValue(params, "cds.project")
+	workflow := sdk.ParameterValue(params, "cds.workflow")
+	number, err := strconv.ParseInt(sdk.ParameterValue(params, "cds.run.number"), 10, 64)
+	if err != nil {
+		return fmt.Errorf("cds.run.number variable is not valid. aborting")
+	}
+
+	artifacts, err := w.client.WorkflowRunArtifacts(project, workflow, number)
+	if err != nil {
+		return fmt.Errorf("Cannot list the artifacts of the run: %v", err)
+	}
+	var plan *sdk.WorkflowNodeRunArtifact
+	for i := range artifacts {
+		a := &artifacts[i]
+		if a.Name != planName || a.Tag != terraformArtifactTag {
+			continue
+		}
+		if plan == nil || a.Created.After(plan.Created) {
+			plan = a
+		}
+	}
+	if plan == nil {
+		return fmt.Errorf("plan %s not found in the artifacts of the run, it must be uploaded by the TerraformPlan action", planName)
+	}
+
+	f, err := os.OpenFile(dest, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return fmt.Errorf("Cannot create %s: %v", dest, err)
+	}
+	if err := w.client.WorkflowNodeRunArtifactDownload(project, workflow, *plan, f); err != nil {
+		f.Close() // nolint
+		return fmt.Errorf("Cannot download the plan %s: %v", planName, err)
+	}
+	return f.Close()
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func TestParseTerraformPlanSummary(t *testing.T) {
+	s, err := parseTerraformPlanSummary([]byte(`{
+  "format_version": "0.1",
+  "resource_changes": [
+    {"address": "aws_instance.a", "change": {"actions": ["create"]}},
+    {"address": "aws_instance.b", "change": {"actions": ["update"]}},
+    {"address": "aws_instance.c", "change": {"actions": ["delete", "create"]}},
+    {"address": "aws_instance.d", "change": {"actions": ["no-op"]}},
+    {"address": "aws_instance.e", "change": {"actions": ["delete"]}}
+  ]
+}`))
+	assert.NoError(t, err)
+	assert.Equal(t, terraformPlanSummary{Add: 2, Change: 1, Destroy: 2}, s)
+	assert.Equal(t, "Plan: 2 to add, 1 to change, 2 to destroy.", s.String())
+	assert.True(t, s.HasChanges())
+
+	s, err = parseTerraformPlanSummary([]byte(`{"format_version": "0.1"}`))
+	assert.NoError(t, err)
+	assert.False(t, s.HasChanges())
+
+	_, err = parseTerraformPlanSummary([]byte(`Plan: 1 to add`))
+	assert.Error(t, err)
+}
+
+func TestTerraformArgs(t *testing.T) {
+	assert.Equal(t, []string{
+		"plan", "-input=false", "-no-color", "-out=prod.tfplan",
+		"-var-file=common.tfvars", "-var-file=prod.tfvars",
+		"-var", "image_tag=1.2.3", "-var", "replicas=2",
+	}, terraformPlanArgs([]sdk.Parameter{
+		{Name: "varFiles", Value: "common.tfvars, prod.tfvars"},
+		{Name: "vars", Value: "image_tag=1.2.3\n\nreplicas=2\n"},
+	}, "prod.tfplan"))
+
+	assert.Equal(t, []string{"-backend-config=bucket=my-states", "-backend-config=region=eu-west-1"}, terraformBackendConfigArgs([]sdk.Parameter{
+		{Name: "cds.integration.backend config", Value: "# s3 backend\nbucket=my-states\nregion=eu-west-1\n"},
+	}))
+
+	env, err := terraformEnv([]sdk.Variable{{Name: "cds.integration.credentials", Value: "AWS_ACCESS_KEY_ID=foo\nAWS_SECRET_ACCESS_KEY=bar=\n"}})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"TF_IN_AUTOMATION=1", "TF_INPUT=0", "AWS_ACCESS_KEY_ID=foo", "AWS_SECRET_ACCESS_KEY=bar="}, env)
+
+	_, err = terraformEnv([]sdk.Variable{{Name: "cds.integration.credentials", Value: "my-secret"}})
+	assert.Error(t, err)
+}
+
+func TestCheckTerraformApproval(t *testing.T) {
+	assert.Error(t, checkTerraformApproval(nil, []sdk.Parameter{{Name: "cds.manual", Value: "false"}}))
+	assert.NoError(t, checkTerraformApproval(nil, []sdk.Parameter{{Name: "cds.manual", Value: "true"}}))
+	assert.NoError(t, checkTerraformApproval([]sdk.Parameter{{Name: "approval", Value: "false"}}, []sdk.Parameter{{Name: "cds.manual", Value: "false"}}))
+}
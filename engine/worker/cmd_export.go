@@ -38,6 +38,8 @@ You can use the build variable in :
 * another step of the current job with ` + "`{{.cds.build.varname}}`" + `
 * the next stages in same pipeline ` + "`{{.cds.build.varname}}`" + `
 * the next pipelines ` + "`{{.workflow.pipelineName.build.varname}}`" + ` with ` + "`pipelineName`" + ` the name of the pipeline in your worklow
+
+To pass values from a job to the next stages and pipelines, prefer ` + "`worker output set`" + `: outputs are scoped by job so that parallel jobs cannot override each other's values.
 	
 	`,
 	Run: exportCmd,
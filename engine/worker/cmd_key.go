@@ -79,6 +79,8 @@ export PKEY="/tmp/5/0/2569/655/bd925028e70aea34/cds.key.proj-mykey.priv";
 
 So that, you can use custom git commands the the previous installed SSH key.
 
+The keys are only available to the step which installs them: the SSH keys, the files written with ` + "`--file`" + ` and the PGP keyring of the step are removed at the end of the step.
+
 `,
 		Example: "worker key install proj-test",
 		Run:     keyInstallCmd(w),
@@ -244,6 +246,7 @@ func (wk *currentWorker) keyInstallHandler(w http.ResponseWriter, r *http.Reques
 				writeJSON(w, errSetup, errSetup.Status)
 				return
 			}
+			wk.installedKeyFile(fileName)
 		}
 
 		writeJSON(w, keyResponse{PKey: wk.currentJob.pkey, Type: sdk.KeyTypeSSH}, http.StatusOK)
@@ -266,8 +269,19 @@ func (wk *currentWorker) keyInstallHandler(w http.ResponseWriter, r *http.Reques
 				return
 			}
 		}
+		// the key is imported in the keyring of the step, removed at the end of the step
+		if err := os.MkdirAll(wk.currentJob.gnupgHome, 0700); err != nil {
+			errDir := sdk.Error{
+				Message: fmt.Sprintf("Cannot setup pgp keyring : %v", err),
+				Status:  http.StatusInternalServerError,
+			}
+			log.Error("%v", errDir)
+			writeJSON(w, errDir, errDir.Status)
+			return
+		}
+
 		content := []byte(key.Value)
-		tmpfile, errTmpFile := ioutil.TempFile("", key.Name)
+		tmpfile, errTmpFile := ioutil.TempFile(wk.tmpDirectory(), key.Name)
 		if errTmpFile != nil {
 			errFile := sdk.Error{
 				Message: fmt.Sprintf("Cannot setup pgp key %s : %v", key.Name, errTmpFile),
@@ -306,6 +320,7 @@ func (wk *currentWorker) keyInstallHandler(w http.ResponseWriter, r *http.Reques
 			gpgBin = "gpg2"
 		}
 		cmd := exec.Command(gpgBin, "--import", tmpfile.Name())
+		cmd.Env = append(os.Environ(), "GNUPGHOME="+wk.currentJob.gnupgHome)
 		var out bytes.Buffer
 		cmd.Stdout = &out
 		if err := cmd.Run(); err != nil {
@@ -27,14 +27,36 @@ func mainCommandRun(w *currentWorker) func(cmd *cobra.Command, args []string) {
 		var autoUpdate = FlagBool(cmd, flagAutoUpdate)
 		var singleUse = FlagBool(cmd, flagSingleUse)
 
+		var basedir = FlagString(cmd, flagBaseDir)
+		if basedir == "" {
+			basedir = os.TempDir()
+		}
+
 		log.Initialize(&log.Conf{})
 
+		// the binary is only downloaded when the CDS API runs another version than the installed one
+		installed := sdk.VERSION
 		if autoUpdate {
-			updateCmd(w)(cmd, args)
+			var err error
+			installed, err = w.upgrade(FlagString(cmd, flagAPI), FlagBool(cmd, flagInsecure), installed)
+			if err != nil {
+				sdk.Exit("%v\n", err)
+			}
 		}
 
-		for {
-			execWorker()
+		for i := 0; ; i++ {
+			// the worker exits after its job when the CDS API requires a new version, it is restarted with the new binary
+			if autoUpdate && i > 0 {
+				var err error
+				installed, err = w.upgrade(FlagString(cmd, flagAPI), FlagBool(cmd, flagInsecure), installed)
+				if err != nil {
+					log.Error("Unable to update worker binary: %v", err)
+				}
+			}
+			if drained := execWorker(basedir); drained {
+				log.Info("worker drained, it will be shutdown...")
+				break
+			}
 			if singleUse {
 				log.Info("single-use true, worker will be shutdown...")
 				break
@@ -46,7 +68,12 @@ func mainCommandRun(w *currentWorker) func(cmd *cobra.Command, args []string) {
 	}
 }
 
-func execWorker() {
+// exitCodeDrained is the exit code of a worker process drained by an administrator, it is not restarted
+const exitCodeDrained = 5
+
+// execWorker runs a worker process and returns true if it was drained.
+// The job directory left by a killed process is removed.
+func execWorker(basedir string) bool {
 	current, errExec := os.Executable()
 	if errExec != nil {
 		sdk.Exit("Error on getting current binary worker", errExec)
@@ -62,9 +89,15 @@ func execWorker() {
 
 	if err := cmd.Start(); err != nil {
 		log.Error("start err:%s", err)
+		return false
 	}
+	defer cleanupWorkspace(basedir, cmd.Process.Pid)
 
 	if err := cmd.Wait(); err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == exitCodeDrained {
+			return true
+		}
 		log.Error("wait err:%s", err)
 	}
+	return false
 }
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// maskedSecretName is the name displayed in the logs instead of the values masked with `worker mask`
+const maskedSecretName = "masked"
+
+func cmdMask(w *currentWorker) *cobra.Command {
+	c := &cobra.Command{
+		Use:   "mask",
+		Short: "worker mask <value>",
+		Long: `
+Inside a step, you can mask a secret generated during the job, a temporary token for example, in all the next logs of the job:
+
+	TOKEN=$(get-my-token)
+	worker mask "$TOKEN"
+
+The value can also be read from the standard input, so that it is not visible in the list of the processes:
+
+	get-my-token | worker mask
+
+The value is displayed as **` + maskedSecretName + `** in the logs. Values shorter than 6 characters cannot be masked.
+		`,
+		Run: maskCmd(w),
+	}
+	return c
+}
+
+func maskCmd(w *currentWorker) func(cmd *cobra.Command, args []string) {
+	return func(cmd *cobra.Command, args []string) {
+		port := workerServerPort()
+
+		var value string
+		switch len(args) {
+		case 0:
+			btes, err := ioutil.ReadAll(os.Stdin)
+			if err != nil {
+				sdk.Exit("cannot read value from standard input: %v\n", err)
+			}
+			value = strings.TrimRight(string(btes), "\r\n")
+		case 1:
+			value = args[0]
+		default:
+			sdk.Exit("Wrong usage: Example : worker mask <value>")
+		}
+
+		data, errMarshal := json.Marshal(sdk.Variable{Value: value})
+		if errMarshal != nil {
+			sdk.Exit("internal error (%s)\n", errMarshal)
+		}
+
+		req, errRequest := http.NewRequest("POST", fmt.Sprintf("http://127.0.0.1:%d/mask", port), bytes.NewReader(data))
+		if errRequest != nil {
+			sdk.Exit("cannot post worker mask (Request): %s\n", errRequest)
+		}
+
+		client := http.DefaultClient
+		client.Timeout = 5 * time.Second
+
+		resp, errDo := client.Do(req)
+		if errDo != nil {
+			sdk.Exit("command failed: %v\n", errDo)
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode >= 300 {
+			body, err := ioutil.ReadAll(resp.Body)
+			if err != nil {
+				sdk.Exit("mask failed: unable to read body %v\n", err)
+			}
+			cdsError := sdk.DecodeError(body)
+			sdk.Exit("mask failed: %v\n", cdsError)
+		}
+	}
+}
+
+func (wk *currentWorker) maskHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, r, sdk.ErrMethodNotAllowed)
+		return
+	}
+
+	data, errRead := ioutil.ReadAll(r.Body)
+	if errRead != nil {
+		writeError(w, r, sdk.NewError(sdk.ErrWrongRequest, errRead))
+		return
+	}
+	defer r.Body.Close()
+
+	var v sdk.Variable
+	if err := json.Unmarshal(data, &v); err != nil {
+		writeError(w, r, sdk.NewError(sdk.ErrWrongRequest, err))
+		return
+	}
+	if len(v.Value) < sdk.SecretMinLength {
+		writeError(w, r, sdk.NewErrorFrom(sdk.ErrWrongRequest, "cannot mask a value shorter than %d characters", sdk.SecretMinLength))
+		return
+	}
+
+	addLogSecret(sdk.Variable{Name: maskedSecretName, Type: sdk.SecretVariable, Value: v.Value})
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func cmdOutput(w *currentWorker) *cobra.Command {
+	cmdOutputRoot := &cobra.Command{
+		Use:   "output",
+		Short: "worker output",
+		Long:  "Inside a job, you can set outputs to pass values to the next stages and pipelines of the workflow",
+	}
+	cmdOutputRoot.AddCommand(cmdOutputSet(w))
+	return cmdOutputRoot
+}
+
+func cmdOutputSet(w *currentWorker) *cobra.Command {
+	c := &cobra.Command{
+		Use:   "set",
+		Short: "worker output set <key> <value>",
+		Long: `
+Inside a job, you can set an output of the job:
+
+	worker output set version 1.2.3
+
+The key can only contain letters, digits, '_' and '-'. Setting an output twice in the same job overrides its value.
+
+## Scope
+
+You can use the output of a job named ` + "`jobName`" + ` in:
+
+* the next steps of the current job with ` + "`{{.cds.outputs.jobName.key}}`" + `
+* the next stages of the pipeline with ` + "`{{.cds.outputs.jobName.key}}`" + `
+* the next pipelines with ` + "`{{.workflow.pipelineName.outputs.jobName.key}}`" + ` with ` + "`pipelineName`" + ` the name of the pipeline in your workflow
+
+The characters of the job name which are not letters, digits, '_' or '-' are replaced by '_'.
+		`,
+		Run: outputSetCmd(w),
+	}
+	return c
+}
+
+func outputSetCmd(w *currentWorker) func(cmd *cobra.Command, args []string) {
+	return func(cmd *cobra.Command, args []string) {
+		port := workerServerPort()
+
+		if len(args) != 2 {
+			sdk.Exit("Wrong usage: Example : worker output set <key> <value>")
+		}
+
+		data, errMarshal := json.Marshal(sdk.WorkflowRunJobOutput{Key: args[0], Value: args[1]})
+		if errMarshal != nil {
+			sdk.Exit("internal error (%s)\n", errMarshal)
+		}
+
+		req, errRequest := http.NewRequest("POST", fmt.Sprintf("http://127.0.0.1:%d/output", port), bytes.NewReader(data))
+		if errRequest != nil {
+			sdk.Exit("cannot post worker output (Request): %s\n", errRequest)
+		}
+
+		client := http.DefaultClient
+		client.Timeout = 5 * time.Minute
+
+		resp, errDo := client.Do(req)
+		if errDo != nil {
+			sdk.Exit("command failed: %v\n", errDo)
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode >= 300 {
+			body, err := ioutil.ReadAll(resp.Body)
+			if err != nil {
+				sdk.Exit("output failed: unable to read body %v\n", err)
+			}
+			cdsError := sdk.DecodeError(body)
+			sdk.Exit("output failed: %v\n", cdsError)
+		}
+	}
+}
+
+func (wk *currentWorker) outputHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, r, sdk.ErrMethodNotAllowed)
+		return
+	}
+
+	data, errRead := ioutil.ReadAll(r.Body)
+	if errRead != nil {
+		writeError(w, r, sdk.NewError(sdk.ErrWrongRequest, errRead))
+		return
+	}
+	defer r.Body.Close()
+
+	var output sdk.WorkflowRunJobOutput
+	if err := json.Unmarshal(data, &output); err != nil {
+		writeError(w, r, sdk.NewError(sdk.ErrWrongRequest, err))
+		return
+	}
+	if err := output.IsValid(); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	res, err := wk.client.QueueJobOutputSet(ctx, wk.currentJob.wJob.ID, output)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	// The output is also available in the next steps of the current job
+	name := sdk.WorkflowRunJobOutputParameterName(sdk.ParameterValue(wk.currentJob.params, "cds.job"), output.Key)
+	var found bool
+	for i := range wk.currentJob.buildVariables {
+		if wk.currentJob.buildVariables[i].Name == name {
+			wk.currentJob.buildVariables[i].Value = output.Value
+			found = true
+			break
+		}
+	}
+	if !found {
+		wk.currentJob.buildVariables = append(wk.currentJob.buildVariables, sdk.Variable{
+			Name:  name,
+			Type:  sdk.StringVariable,
+			Value: output.Value,
+		})
+	}
+
+	writeJSON(w, res, http.StatusOK)
+}
@@ -0,0 +1,249 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mitchellh/mapstructure"
+	"github.com/spf13/cobra"
+
+	"github.com/ovh/cds/sdk"
+)
+
+type workerRunResult struct {
+	Type string            `json:"type"`
+	Data map[string]string `json:"data"`
+}
+
+func cmdResult(w *currentWorker) *cobra.Command {
+	cmdResultRoot := &cobra.Command{
+		Use:   "result",
+		Short: "worker result",
+		Long:  "Inside a job, you can add results to the current workflow run and list them",
+	}
+	cmdResultRoot.AddCommand(cmdResultAdd(w), cmdResultList(w))
+	return cmdResultRoot
+}
+
+func cmdResultAdd(w *currentWorker) *cobra.Command {
+	c := &cobra.Command{
+		Use:   "add",
+		Short: "worker result add <type> key=value key=value",
+		Long: `
+Inside a job, you can add a result to the current workflow run. A result has a type and metadata depending on its type:
+
+	# worker result add <type> <key>=<value> <key>=<value>
+	worker result add docker image=registry.example.com/my-app tag={{.cds.version}} digest=sha256:...
+	worker result add deployment environment=production application=my-app version={{.cds.version}} url=https://my-app.example.com
+
+Available types are: artifact, docker, tests, coverage and deployment. Artifacts, tests and coverage results are also added automatically by the upload of artifacts and reports.
+		`,
+		Run: resultAddCmd(w),
+	}
+	return c
+}
+
+var cmdResultListTypes []string
+
+func cmdResultList(w *currentWorker) *cobra.Command {
+	c := &cobra.Command{
+		Use:   "list",
+		Short: "worker result list [--type=<type>]",
+		Long: `
+Inside a job, you can list the results of the current workflow run:
+
+	worker result list --type docker --type deployment
+		`,
+		Run: resultListCmd(w),
+	}
+	c.Flags().StringSliceVar(&cmdResultListTypes, "type", nil, "Type of the results to list. Optional, default: all")
+	return c
+}
+
+func workerServerPort() int {
+	portS := os.Getenv(WorkerServerPort)
+	if portS == "" {
+		sdk.Exit("%s not found, are you running inside a CDS worker job?\n", WorkerServerPort)
+	}
+
+	port, errPort := strconv.Atoi(portS)
+	if errPort != nil {
+		sdk.Exit("cannot parse '%s' as a port number", portS)
+	}
+	return port
+}
+
+func resultAddCmd(w *currentWorker) func(cmd *cobra.Command, args []string) {
+	return func(cmd *cobra.Command, args []string) {
+		port := workerServerPort()
+
+		if len(args) < 2 {
+			sdk.Exit("Wrong usage: Example : worker result add <type> <key>=<value>")
+		}
+
+		result := workerRunResult{Type: args[0], Data: map[string]string{}}
+		for _, s := range args[1:] {
+			t := strings.SplitN(s, "=", 2)
+			if len(t) != 2 {
+				sdk.Exit("Wrong usage: Example : worker result add <type> <key>=<value>")
+			}
+			result.Data[t[0]] = t[1]
+		}
+
+		data, errMarshal := json.Marshal(result)
+		if errMarshal != nil {
+			sdk.Exit("internal error (%s)\n", errMarshal)
+		}
+
+		req, errRequest := http.NewRequest("POST", fmt.Sprintf("http://127.0.0.1:%d/result", port), bytes.NewReader(data))
+		if errRequest != nil {
+			sdk.Exit("cannot post worker result (Request): %s\n", errRequest)
+		}
+
+		client := http.DefaultClient
+		client.Timeout = 5 * time.Minute
+
+		resp, errDo := client.Do(req)
+		if errDo != nil {
+			sdk.Exit("command failed: %v\n", errDo)
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode >= 300 {
+			body, err := ioutil.ReadAll(resp.Body)
+			if err != nil {
+				sdk.Exit("result failed: unable to read body %v\n", err)
+			}
+			cdsError := sdk.DecodeError(body)
+			sdk.Exit("result failed: %v\n", cdsError)
+		}
+	}
+}
+
+func resultListCmd(w *currentWorker) func(cmd *cobra.Command, args []string) {
+	return func(cmd *cobra.Command, args []string) {
+		port := workerServerPort()
+
+		params := url.Values{}
+		for _, t := range cmdResultListTypes {
+			params.Add("type", t)
+		}
+
+		resp, errDo := http.DefaultClient.Get(fmt.Sprintf("http://127.0.0.1:%d/result?%s", port, params.Encode()))
+		if errDo != nil {
+			sdk.Exit("command failed: %v\n", errDo)
+		}
+		defer resp.Body.Close()
+
+		body, err := ioutil.ReadAll(resp.Body)
+		if err != nil {
+			sdk.Exit("result failed: unable to read body %v\n", err)
+		}
+		if resp.StatusCode >= 300 {
+			cdsError := sdk.DecodeError(body)
+			sdk.Exit("result failed: %v\n", cdsError)
+		}
+		fmt.Println(string(body))
+	}
+}
+
+func (wk *currentWorker) resultHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+		wk.addResultHandler(w, r)
+	case http.MethodGet:
+		wk.listResultsHandler(w, r)
+	default:
+		writeError(w, r, sdk.ErrMethodNotAllowed)
+	}
+}
+
+func (wk *currentWorker) addResultHandler(w http.ResponseWriter, r *http.Request) {
+	data, errRead := ioutil.ReadAll(r.Body)
+	if errRead != nil {
+		writeError(w, r, sdk.NewError(sdk.ErrWrongRequest, errRead))
+		return
+	}
+	defer r.Body.Close()
+
+	var reqArgs workerRunResult
+	if err := json.Unmarshal(data, &reqArgs); err != nil {
+		writeError(w, r, sdk.NewError(sdk.ErrWrongRequest, err))
+		return
+	}
+
+	var metadata interface{}
+	switch reqArgs.Type {
+	case sdk.WorkflowRunResultTypeArtifact:
+		metadata = &sdk.WorkflowRunResultArtifact{}
+	case sdk.WorkflowRunResultTypeDocker:
+		metadata = &sdk.WorkflowRunResultDocker{}
+	case sdk.WorkflowRunResultTypeTests:
+		metadata = &sdk.WorkflowRunResultTests{}
+	case sdk.WorkflowRunResultTypeCoverage:
+		metadata = &sdk.WorkflowRunResultCoverage{}
+	case sdk.WorkflowRunResultTypeDeployment:
+		metadata = &sdk.WorkflowRunResultDeployment{}
+	default:
+		writeError(w, r, sdk.NewError(sdk.ErrWrongRequest, fmt.Errorf("invalid result type %s, available types are: %s", reqArgs.Type, strings.Join(sdk.WorkflowRunResultTypes, ", "))))
+		return
+	}
+
+	// Values are given as strings on the command line, numbers are converted by the decoder
+	decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		TagName:          "json",
+		WeaklyTypedInput: true,
+		ErrorUnused:      true,
+		Result:           metadata,
+	})
+	if err != nil {
+		writeError(w, r, sdk.WithStack(err))
+		return
+	}
+	if err := decoder.Decode(reqArgs.Data); err != nil {
+		writeError(w, r, sdk.NewError(sdk.ErrWrongRequest, fmt.Errorf("invalid %s result: %v", reqArgs.Type, err)))
+		return
+	}
+
+	result, err := sdk.NewWorkflowRunResult(reqArgs.Type, metadata)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	res, err := wk.client.QueueWorkflowRunResultAdd(ctx, wk.currentJob.wJob.ID, result)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	writeJSON(w, res, http.StatusOK)
+}
+
+func (wk *currentWorker) listResultsHandler(w http.ResponseWriter, r *http.Request) {
+	projectKey := sdk.ParameterValue(wk.currentJob.params, "cds.project")
+	workflowName := sdk.ParameterValue(wk.currentJob.params, "cds.workflow")
+	buildNumberString := sdk.ParameterValue(wk.currentJob.params, "cds.run.number")
+	number, errN := strconv.ParseInt(buildNumberString, 10, 64)
+	if errN != nil {
+		writeError(w, r, sdk.NewError(sdk.ErrWrongRequest, fmt.Errorf("Cannot parse '%s' as run number: %s", buildNumberString, errN)))
+		return
+	}
+
+	results, err := wk.client.WorkflowRunResultsList(projectKey, workflowName, number, r.URL.Query()["type"]...)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	writeJSON(w, results, http.StatusOK)
+}
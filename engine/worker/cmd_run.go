@@ -9,9 +9,11 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/golang/protobuf/ptypes/empty"
 	"github.com/google/gops/agent"
 	"github.com/spf13/cobra"
 
+	"github.com/ovh/cds/engine/api/grpc"
 	"github.com/ovh/cds/sdk"
 	"github.com/ovh/cds/sdk/log"
 )
@@ -75,11 +77,14 @@ func runCmd(w *currentWorker) func(cmd *cobra.Command, args []string) {
 		}()
 
 		ttl := FlagInt(cmd, flagTTL)
-		time.AfterFunc(time.Duration(ttl)*time.Minute, func() {
-			if w.nbActionsDone == 0 {
-				cancel()
-			}
-		})
+		// the workers of a pool wait for jobs until they are drained
+		if w.pool == "" {
+			time.AfterFunc(time.Duration(ttl)*time.Minute, func() {
+				if w.nbActionsDone == 0 {
+					cancel()
+				}
+			})
+		}
 
 		//Register
 		t0 := time.Now()
@@ -108,6 +113,7 @@ func runCmd(w *currentWorker) func(cmd *cobra.Command, args []string) {
 		// start queue polling
 		wjobs := make(chan sdk.WorkflowNodeJobRun, 50)
 		errs := make(chan error, 1)
+		drain := make(chan struct{})
 
 		//Definition of the function which must be called to stop the worker
 		var endFunc = func() {
@@ -120,6 +126,11 @@ func runCmd(w *currentWorker) func(cmd *cobra.Command, args []string) {
 			cancel()
 			stopHTTPServer()
 
+			if w.drained {
+				log.Info("Exiting drained worker")
+				os.Exit(exitCodeDrained)
+			}
+
 			if FlagBool(cmd, flagForceExit) {
 				log.Info("Exiting worker with force_exit true")
 				return
@@ -183,12 +194,23 @@ func runCmd(w *currentWorker) func(cmd *cobra.Command, args []string) {
 		// Register (heartbeat loop)
 		go func() {
 			var nbErrors int
+			var drained bool
 			for {
 				select {
 				case <-ctx.Done():
 					return
 				case <-refreshTick.C:
-					if err := w.client.WorkerRefresh(ctx); err != nil {
+					err := w.heartbeat(ctx)
+					// the heartbeat goes on until the end of the current job of a drained worker
+					if sdk.ErrorIs(err, sdk.ErrWorkerDraining) {
+						if !drained {
+							log.Info("The worker is drained, it will exit once its job is done")
+							drained = true
+							close(drain)
+						}
+						err = nil
+					}
+					if err != nil {
 						log.Error("Heartbeat failed: %v", err)
 						nbErrors++
 						if nbErrors == 5 {
@@ -290,8 +312,17 @@ func runCmd(w *currentWorker) func(cmd *cobra.Command, args []string) {
 				// Unregister from engine
 				log.Info("Job is done. Unregistering...")
 				cancel()
+			case <-drain:
+				// the jobs are run in this loop, the drained worker exits between two jobs
+				log.Info("Worker drained, unregistering...")
+				w.drained = true
+				cancel()
 			case <-updateTick.C:
-				w.doUpdate()
+				// the jobs are run in this loop, the worker checks its version between two jobs
+				if w.doUpdate() {
+					log.Info("A new worker version is required, unregistering...")
+					cancel()
+				}
 			}
 		}
 	}
@@ -340,16 +371,29 @@ func (w *currentWorker) processBookedWJob(ctx context.Context, wjobs chan<- sdk.
 	return nil
 }
 
-func (w *currentWorker) doUpdate() {
-	if w.autoUpdate {
-		version, err := w.client.Version()
-		if err != nil {
-			log.Error("Error while getting version from CDS API: %s", err)
-		}
-		if version.Version != sdk.VERSION {
-			sdk.Exit("Exiting this CDS Worker process - auto updating worker")
+// heartbeat refreshes the last beat of the worker, through grpc if it is available
+func (w *currentWorker) heartbeat(ctx context.Context) error {
+	if w.grpc.conn != nil {
+		_, err := grpc.NewWorkflowQueueClient(w.grpc.conn).Heartbeat(ctx, new(empty.Empty))
+		if err == nil {
+			return nil
 		}
+		log.Error("heartbeat> Unable to refresh worker through grpc: %v", err)
+	}
+	return w.client.WorkerRefresh(ctx)
+}
+
+// doUpdate returns true if the auto-updated worker must exit to be restarted with the version required by the CDS API
+func (w *currentWorker) doUpdate() bool {
+	if !w.autoUpdate {
+		return false
+	}
+	version, err := w.client.Version()
+	if err != nil {
+		log.Error("Error while getting version from CDS API: %s", err)
+		return false
 	}
+	return version.Version != sdk.VERSION
 }
 
 func (w *currentWorker) doRegister() error {
@@ -365,6 +409,7 @@ func (w *currentWorker) doRegister() error {
 			Token:        w.token,
 			HatcheryName: w.hatchery.name,
 			ModelID:      w.model.ID,
+			Pool:         w.pool,
 		}
 		if err := w.register(form); err != nil {
 			log.Error("Cannot register: %s", err)
@@ -40,8 +40,13 @@ func (w *currentWorker) serve(c context.Context) (int, error) {
 	r.HandleFunc("/download", w.downloadHandler)
 	r.HandleFunc("/exit", w.exitHandler)
 	r.HandleFunc("/key/{key}/install", w.keyInstallHandler)
+	r.HandleFunc("/mask", w.maskHandler)
+	r.HandleFunc("/output", w.outputHandler)
+	r.HandleFunc("/result", w.resultHandler)
 	r.HandleFunc("/services/{type}", w.serviceHandler)
 	r.HandleFunc("/tag", w.tagHandler)
+	r.HandleFunc("/tmpfile/{name}/pull", w.tmpFilePullHandler)
+	r.HandleFunc("/tmpfile/{name}/push", w.tmpFilePushHandler)
 	r.HandleFunc("/tmpl", w.tmplHandler)
 	r.HandleFunc("/upload", w.uploadHandler)
 	r.HandleFunc("/checksecret", w.checkSecretHandler)
@@ -0,0 +1,240 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/spf13/cobra"
+
+	"github.com/ovh/cds/sdk"
+)
+
+type workerTmpFile struct {
+	Files            []string `json:"files"`
+	WorkingDirectory string   `json:"working_directory"`
+}
+
+func cmdTmpFile(w *currentWorker) *cobra.Command {
+	cmdTmpFileRoot := &cobra.Command{
+		Use:   "tmpfile",
+		Short: "worker tmpfile",
+		Long: `
+Inside a job, you can push temporary files to pass them to the next jobs of the same workflow run, without uploading them as artifacts:
+
+	# in a job of the first stage
+	go build -o bin/my-app
+	worker tmpfile push my-app bin/
+
+	# in a job of the next stage, or of the next pipelines
+	worker tmpfile pull my-app
+	./bin/my-app --version
+
+Temporary files are stored by the CDN service and deleted with the workflow run.
+`,
+	}
+	cmdTmpFileRoot.AddCommand(cmdTmpFilePush(w), cmdTmpFilePull(w))
+	return cmdTmpFileRoot
+}
+
+func cmdTmpFilePush(w *currentWorker) *cobra.Command {
+	c := &cobra.Command{
+		Use:   "push",
+		Short: "worker tmpfile push <name> {{.cds.workspace}}/pathToUpload",
+		Long: `
+Inside a job, you can push files and directories as a temporary file of the workflow run:
+
+	worker tmpfile push <name> dir/file
+
+Pushing a temporary file with the same name in the same workflow run replaces it.
+		`,
+		Example: "worker tmpfile push my-app {{.cds.workspace}}/bin",
+		Run:     tmpFilePushCmd(w),
+	}
+	return c
+}
+
+func tmpFilePushCmd(w *currentWorker) func(cmd *cobra.Command, args []string) {
+	return func(cmd *cobra.Command, args []string) {
+		port := workerServerPort()
+
+		if len(args) < 2 {
+			sdk.Exit("Wrong usage: Example : worker tmpfile push <name> filea fileb filec")
+		}
+		if err := (sdk.WorkflowRunTmpFile{Name: args[0]}).IsValid(); err != nil {
+			sdk.Exit("%v\n", err)
+		}
+
+		cwd, err := os.Getwd()
+		if err != nil {
+			sdk.Exit("cannot find working directory: %s\n", err)
+		}
+
+		f := workerTmpFile{WorkingDirectory: cwd}
+		for _, arg := range args[1:] {
+			absPath, err := filepath.Abs(arg)
+			if err != nil {
+				sdk.Exit("cannot have absolute path for (%s): %s\n", arg, err)
+			}
+			f.Files = append(f.Files, absPath)
+		}
+
+		data, errMarshal := json.Marshal(f)
+		if errMarshal != nil {
+			sdk.Exit("internal error (%s)\n", errMarshal)
+		}
+
+		fmt.Printf("Worker tmpfile push in progress... (name: %s)\n", args[0])
+		req, errRequest := http.NewRequest("POST", fmt.Sprintf("http://127.0.0.1:%d/tmpfile/%s/push", port, args[0]), bytes.NewReader(data))
+		if errRequest != nil {
+			sdk.Exit("cannot post worker tmpfile push (Request): %s\n", errRequest)
+		}
+
+		client := http.DefaultClient
+		client.Timeout = 30 * time.Minute
+
+		resp, errDo := client.Do(req)
+		if errDo != nil {
+			sdk.Exit("command failed: %v\n", errDo)
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode >= 300 {
+			body, err := ioutil.ReadAll(resp.Body)
+			if err != nil {
+				sdk.Exit("tmpfile push failed: unable to read body %v\n", err)
+			}
+			cdsError := sdk.DecodeError(body)
+			sdk.Exit("tmpfile push failed: %v\n", cdsError)
+		}
+
+		fmt.Printf("Worker tmpfile push with success (name: %s)\n", args[0])
+	}
+}
+
+func cmdTmpFilePull(w *currentWorker) *cobra.Command {
+	c := &cobra.Command{
+		Use:   "pull",
+		Short: "worker tmpfile pull <name>",
+		Long: `
+Inside a job, you can pull a temporary file pushed by a previous job of the workflow run:
+
+	worker tmpfile pull <name>
+
+If a previous job pushed a temporary file with:
+
+	worker tmpfile push my-app bin/
+
+The command:
+
+	worker tmpfile pull my-app
+
+will create the directory bin/ with its content in the current directory.
+		`,
+		Run: tmpFilePullCmd(w),
+	}
+	return c
+}
+
+func tmpFilePullCmd(w *currentWorker) func(cmd *cobra.Command, args []string) {
+	return func(cmd *cobra.Command, args []string) {
+		port := workerServerPort()
+
+		if len(args) != 1 {
+			sdk.Exit("Wrong usage: Example : worker tmpfile pull <name>")
+		}
+		if err := (sdk.WorkflowRunTmpFile{Name: args[0]}).IsValid(); err != nil {
+			sdk.Exit("%v\n", err)
+		}
+
+		cwd, err := os.Getwd()
+		if err != nil {
+			sdk.Exit("cannot find working directory: %s\n", err)
+		}
+
+		fmt.Printf("Worker tmpfile pull in progress... (name: %s)\n", args[0])
+		client := http.DefaultClient
+		client.Timeout = 10 * time.Minute
+
+		resp, errDo := client.Get(fmt.Sprintf("http://127.0.0.1:%d/tmpfile/%s/pull?path=%s", port, args[0], url.QueryEscape(cwd)))
+		if errDo != nil {
+			sdk.Exit("command failed: %v\n", errDo)
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode >= 300 {
+			body, err := ioutil.ReadAll(resp.Body)
+			if err != nil {
+				sdk.Exit("tmpfile pull failed: unable to read body %v\n", err)
+			}
+			cdsError := sdk.DecodeError(body)
+			sdk.Exit("tmpfile pull failed: %v\n", cdsError)
+		}
+
+		fmt.Printf("Worker tmpfile pull with success (name: %s)\n", args[0])
+	}
+}
+
+func (wk *currentWorker) tmpFilePushHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, r, sdk.ErrMethodNotAllowed)
+		return
+	}
+
+	data, errRead := ioutil.ReadAll(r.Body)
+	if errRead != nil {
+		writeError(w, r, sdk.NewError(sdk.ErrWrongRequest, errRead))
+		return
+	}
+	defer r.Body.Close()
+
+	var f workerTmpFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		writeError(w, r, sdk.NewError(sdk.ErrWrongRequest, err))
+		return
+	}
+
+	content, err := sdk.CreateTarFromPaths(f.WorkingDirectory, f.Files, nil)
+	if err != nil {
+		writeError(w, r, sdk.NewError(sdk.ErrWrongRequest, fmt.Errorf("cannot tar: %v", err)))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
+	defer cancel()
+	res, err := wk.client.QueueTmpFileUpload(ctx, wk.currentJob.wJob.ID, mux.Vars(r)["name"], content)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	writeJSON(w, res, http.StatusOK)
+}
+
+func (wk *currentWorker) tmpFilePullHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, r, sdk.ErrMethodNotAllowed)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+	content, err := wk.client.QueueTmpFileDownload(ctx, wk.currentJob.wJob.ID, mux.Vars(r)["name"])
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	defer content.Close()
+
+	if err := sdk.Untar(r.FormValue("path"), content); err != nil {
+		writeError(w, r, sdk.NewError(sdk.ErrWrongRequest, fmt.Errorf("unable to extract temporary file: %v", err)))
+		return
+	}
+}
@@ -1,6 +1,9 @@
 package main
 
 import (
+	"crypto"
+	_ "crypto/sha512"
+	"encoding/hex"
 	"fmt"
 	"net/http"
 	"time"
@@ -18,9 +21,9 @@ func cmdUpdate(w *currentWorker) *cobra.Command {
 		Short: "worker update [flags]",
 		Long: `Update worker from CDS API or from CDS Release
 
-Update from Github:
+Update from Github, with the SHA512 checksum of the binary given on the release page:
 
-		worker update --from-github
+		worker update --from-github --checksum <sha512>
 
 Update from your CDS API:
 
@@ -29,6 +32,7 @@ Update from your CDS API:
 		Run: updateCmd(w),
 	}
 	c.Flags().Bool(flagFromGithub, false, "Update binary from latest github release")
+	c.Flags().String(flagChecksum, "", "SHA512 checksum of the binary of the latest github release")
 	c.Flags().String(flagAPI, "", "URL of CDS API")
 	c.Flags().Bool(flagInsecure, false, `(SSL) This option explicitly allows curl to perform "insecure" SSL connections and transfers.`)
 	return c
@@ -37,46 +41,87 @@ Update from your CDS API:
 func updateCmd(w *currentWorker) func(cmd *cobra.Command, args []string) {
 	return func(cmd *cobra.Command, args []string) {
 		fmt.Println(sdk.VersionString())
-		var urlBinary string
-		if !FlagBool(cmd, "from-github") {
-			w.apiEndpoint = FlagString(cmd, flagAPI)
-			if w.apiEndpoint == "" {
-				sdk.Exit("--api not provided, aborting update.")
-			}
-			w.client = cdsclient.NewWorker(w.apiEndpoint, "download", cdsclient.NewHTTPClient(time.Second*360, FlagBool(cmd, flagInsecure)))
-			urlBinary = w.client.DownloadURLFromAPI("worker", sdk.GOOS, sdk.GOARCH)
-			fmt.Printf("Updating worker binary from CDS API on %s...\n", urlBinary)
-		} else {
-			// no need to have apiEndpoint here
-			w.client = cdsclient.NewWorker("", "download", nil)
-
-			var errGH error
-			urlBinary, errGH = w.client.DownloadURLFromGithub(sdk.GetArtifactFilename("worker", sdk.GOOS, sdk.GOARCH))
-			if errGH != nil {
-				sdk.Exit("Error while getting URL from Github: %s", errGH)
-			}
-			fmt.Printf("Updating worker binary from Github on %s...\n", urlBinary)
+		if err := w.update(FlagString(cmd, flagAPI), FlagBool(cmd, flagFromGithub), FlagString(cmd, flagChecksum), FlagBool(cmd, flagInsecure)); err != nil {
+			sdk.Exit("%v\n", err)
 		}
+		fmt.Println("Update worker done.")
+	}
+}
 
-		resp, err := http.Get(urlBinary)
+// upgrade installs the binary of the version of the CDS API if it is not the installed version. It returns the
+// version installed after the upgrade.
+func (w *currentWorker) upgrade(apiEndpoint string, insecure bool, installed string) (string, error) {
+	client := cdsclient.NewWorker(apiEndpoint, "download", cdsclient.NewHTTPClient(time.Second*30, insecure))
+	version, err := client.Version()
+	if err != nil {
+		return installed, fmt.Errorf("Error while getting version from CDS API: %v", err)
+	}
+	if version.Version == installed {
+		return installed, nil
+	}
+	if err := w.update(apiEndpoint, false, "", insecure); err != nil {
+		return installed, err
+	}
+	return version.Version, nil
+}
+
+// update replaces the worker binary by the one of the CDS API or of the latest Github release. The binary downloaded
+// from the CDS API is checked against the checksum requested separately to the API, the binary of the Github release
+// against the given checksum. Without checksum the binary is not installed.
+func (w *currentWorker) update(apiEndpoint string, fromGithub bool, checksum string, insecure bool) error {
+	var urlBinary string
+	if !fromGithub {
+		w.apiEndpoint = apiEndpoint
+		if w.apiEndpoint == "" {
+			return fmt.Errorf("--api not provided, aborting update")
+		}
+		w.client = cdsclient.NewWorker(w.apiEndpoint, "download", cdsclient.NewHTTPClient(time.Second*360, insecure))
+		var err error
+		checksum, err = w.client.DownloadChecksumFromAPI("worker", sdk.GOOS, sdk.GOARCH)
 		if err != nil {
-			sdk.Exit("Error while getting binary from CDS API: %s\n", err)
+			return fmt.Errorf("Error while getting checksum of the binary from CDS API: %v", err)
 		}
-		defer resp.Body.Close()
-
-		if contentType := getContentType(resp); contentType != "application/octet-stream" {
-			sdk.Exit("Invalid Binary (Content-Type: %s). Please try again or download it manually from %s\n", contentType, sdk.URLGithubReleases)
+		urlBinary = w.client.DownloadURLFromAPI("worker", sdk.GOOS, sdk.GOARCH)
+		fmt.Printf("Updating worker binary from CDS API on %s...\n", urlBinary)
+	} else {
+		if checksum == "" {
+			return fmt.Errorf("--checksum not provided, aborting update")
 		}
+		// no need to have apiEndpoint here
+		w.client = cdsclient.NewWorker("", "download", nil)
 
-		if resp.StatusCode != 200 {
-			sdk.Exit("Error http code: %d, url called: %s\n", resp.StatusCode, urlBinary)
+		var errGH error
+		urlBinary, errGH = w.client.DownloadURLFromGithub(sdk.GetArtifactFilename("worker", sdk.GOOS, sdk.GOARCH))
+		if errGH != nil {
+			return fmt.Errorf("Error while getting URL from Github: %s", errGH)
 		}
+		fmt.Printf("Updating worker binary from Github on %s...\n", urlBinary)
+	}
 
-		if err := update.Apply(resp.Body, update.Options{}); err != nil {
-			sdk.Exit("Error while getting updating worker from CDS API: %s\n", err)
-		}
-		fmt.Println("Update worker done.")
+	sum, err := hex.DecodeString(checksum)
+	if err != nil {
+		return fmt.Errorf("Invalid checksum %s: %v", checksum, err)
+	}
+
+	resp, err := cdsclient.NewHTTPClient(time.Second*360, insecure).Get(urlBinary)
+	if err != nil {
+		return fmt.Errorf("Error while getting binary from CDS API: %s", err)
+	}
+	defer resp.Body.Close()
+
+	if contentType := getContentType(resp); contentType != "application/octet-stream" {
+		return fmt.Errorf("Invalid Binary (Content-Type: %s). Please try again or download it manually from %s", contentType, sdk.URLGithubReleases)
+	}
+
+	if resp.StatusCode != 200 {
+		return fmt.Errorf("Error http code: %d, url called: %s", resp.StatusCode, urlBinary)
+	}
+
+	opts := update.Options{Hash: crypto.SHA512, Checksum: sum}
+	if err := update.Apply(resp.Body, opts); err != nil {
+		return fmt.Errorf("Error while getting updating worker from CDS API: %s", err)
 	}
+	return nil
 }
 
 func getContentType(resp *http.Response) string {
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ovh/cds/sdk/log"
+	"github.com/ovh/cds/sdk/vcs/git"
+)
+
+const (
+	// gitReferenceLockTimeout is the maximum time to wait for another worker updating the same reference
+	gitReferenceLockTimeout = 10 * time.Minute
+	// gitReferenceLockStale is the age after which the lock of a reference is considered as left by a killed worker
+	gitReferenceLockStale = 30 * time.Minute
+)
+
+// gitReference updates the reference repository of an url in the git cache directory of the worker, and returns its path.
+// The cache directory can be shared by the workers of a host.
+func (w *currentWorker) gitReference(gitURL string, auth *git.AuthOpts, sendLog LoggerFunc) (string, error) {
+	sum := sha1.Sum([]byte(gitURL))
+	dir := filepath.Join(w.gitCacheDir, hex.EncodeToString(sum[:])+".git")
+
+	if err := os.MkdirAll(w.gitCacheDir, 0755); err != nil {
+		return "", err
+	}
+
+	unlock, err := lockGitReference(dir + ".lock")
+	if err != nil {
+		return "", err
+	}
+	defer unlock()
+
+	// a new reference is seeded from the mirror of the repository, the repository manager only sends the latest changes
+	if _, err := os.Stat(filepath.Join(dir, "HEAD")); os.IsNotExist(err) {
+		if err := w.seedGitReference(gitURL, dir); err != nil {
+			sendLog(fmt.Sprintf("No mirror available for the reference repository: %v", err))
+		} else {
+			sendLog(fmt.Sprintf("Reference repository %s created from the mirror of the repository", dir))
+		}
+	}
+
+	sendLog(fmt.Sprintf("Updating the reference repository %s", dir))
+	stdErr := new(bytes.Buffer)
+	if err := git.UpdateReference(gitURL, dir, auth, &git.OutputOpts{Stderr: stdErr}); err != nil {
+		if stdErr.Len() > 0 {
+			sendLog(stdErr.String())
+		}
+		return "", err
+	}
+	return dir, nil
+}
+
+// seedGitReference creates a reference repository from the bundle of the mirror maintained by the repositories service
+func (w *currentWorker) seedGitReference(gitURL, dir string) error {
+	if w.currentJob.wJob == nil {
+		return fmt.Errorf("no job")
+	}
+
+	body, err := w.client.QueueJobMirrorDownload(context.Background(), w.currentJob.wJob.ID, gitURL)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	// the bundle is written in the cache directory, it does not count in the disk quota of the job
+	bundle := dir + ".bundle"
+	f, err := os.Create(bundle)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(bundle) // nolint
+	if _, err := io.Copy(f, body); err != nil {
+		f.Close() // nolint
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	stdErr := new(bytes.Buffer)
+	if err := git.FetchBundle(bundle, dir, &git.OutputOpts{Stderr: stdErr}); err != nil {
+		return fmt.Errorf("%v: %s", err, stdErr.String())
+	}
+	return nil
+}
+
+// lockGitReference waits for the lock of a reference repository, it returns the func releasing the lock
+func lockGitReference(lock string) (func(), error) {
+	timeout := time.Now().Add(gitReferenceLockTimeout)
+	for {
+		err := os.Mkdir(lock, 0755)
+		if err == nil {
+			return func() {
+				if err := os.Remove(lock); err != nil {
+					log.Warning("lockGitReference> unable to remove lock %s: %v", lock, err)
+				}
+			}, nil
+		}
+		if !os.IsExist(err) {
+			return nil, err
+		}
+		if fi, err := os.Stat(lock); err == nil && time.Since(fi.ModTime()) > gitReferenceLockStale {
+			log.Warning("lockGitReference> removing stale lock %s", lock)
+			_ = os.Remove(lock)
+			continue
+		}
+		if time.Now().After(timeout) {
+			return nil, fmt.Errorf("timeout while waiting for lock %s", lock)
+		}
+		time.Sleep(time.Second)
+	}
+}
@@ -22,8 +22,10 @@ const (
 	flagSingleUse           = "single-use"
 	flagAutoUpdate          = "auto-update"
 	flagFromGithub          = "from-github"
+	flagChecksum            = "checksum"
 	flagForceExit           = "force-exit"
 	flagBaseDir             = "basedir"
+	flagPluginsCacheDir     = "plugins-cache-dir"
 	flagTTL                 = "ttl"
 	flagBookedWorkflowJobID = "booked-workflow-job-id"
 	flagBookedJobID         = "booked-job-id"
@@ -35,12 +37,16 @@ const (
 	flagGraylogExtraKey     = "graylog-extra-key"
 	flagGraylogExtraValue   = "graylog-extra-value"
 	flagLogLevel            = "log-level"
+	flagLogFormat           = "log-format"
 	flagAPI                 = "api"
 	flagInsecure            = "insecure"
 	flagToken               = "token"
 	flagName                = "name"
 	flagModel               = "model"
 	flagHatcheryName        = "hatchery-name"
+	flagPool                = "pool"
+	flagDiskQuota           = "disk-quota"
+	flagGitCacheDir         = "git-cache-dir"
 )
 
 func initFlagsRun(cmd *cobra.Command) {
@@ -50,6 +56,7 @@ func initFlagsRun(cmd *cobra.Command) {
 	flags.Bool(flagFromGithub, false, "Update binary from latest github release")
 	flags.Bool(flagForceExit, false, "If single_use=true, force exit. This is useful if it's spawned by an Hatchery (default: worker wait 30min for being killed by hatchery)")
 	flags.String(flagBaseDir, "", "This directory (default TMPDIR os environment var) will contains worker working directory and temporary files")
+	flags.String(flagPluginsCacheDir, "", "This directory (default basedir) will contains the binaries of the plugins, it can be shared by the workers of a host")
 	flags.Int(flagTTL, 30, "Worker time to live (minutes)")
 	flags.Int64(flagBookedWorkflowJobID, 0, "Booked Workflow job id")
 	flags.Int64(flagBookedJobID, 0, "Booked job id")
@@ -61,12 +68,16 @@ func initFlagsRun(cmd *cobra.Command) {
 	flags.String(flagGraylogExtraKey, "", "Ex: --graylog-extra-key=xxxx-yyyy")
 	flags.String(flagGraylogExtraValue, "", "Ex: --graylog-extra-value=xxxx-yyyy")
 	flags.String(flagLogLevel, "notice", "Log Level: debug, info, notice, warning, critical")
+	flags.String(flagLogFormat, "text", "Log format: text or json")
 	flags.String(flagAPI, "", "URL of CDS API")
 	flags.Bool(flagInsecure, false, `(SSL) This option explicitly allows curl to perform "insecure" SSL connections and transfers.`)
 	flags.String(flagToken, "", "CDS Token")
 	flags.String(flagName, "", "Name of worker")
 	flags.Int(flagModel, 0, "Model of worker")
 	flags.String(flagHatcheryName, "", "Hatchery Name spawing worker")
+	flags.String(flagPool, "", "Pool of long-lived workers registered by themselves, the worker does not exit when it is idle")
+	flags.String(flagGitCacheDir, "", "This directory will contains the reference repositories used by CheckoutApplication to speed up the clones of big repositories, it can be shared by the workers of a host. Empty means no reference repository")
+	flags.Int(flagDiskQuota, 0, "Disk quota of each job (MB), the job fails when its workspace, keys and temporary files exceed it. 0 means no quota")
 }
 
 // FlagBool replaces viper.GetBool
@@ -147,6 +158,7 @@ func initFlags(cmd *cobra.Command, w *currentWorker) {
 
 	log.Initialize(&log.Conf{
 		Level:                  FlagString(cmd, flagLogLevel),
+		Format:                 FlagString(cmd, flagLogFormat),
 		GraylogProtocol:        FlagString(cmd, flagGraylogProtocol),
 		GraylogHost:            FlagString(cmd, flagGraylogHost),
 		GraylogPort:            FlagString(cmd, flagGraylogPort),
@@ -186,12 +198,19 @@ func initFlags(cmd *cobra.Command, w *currentWorker) {
 	if w.basedir == "" {
 		w.basedir = os.TempDir()
 	}
+	w.pluginsCacheDir = FlagString(cmd, flagPluginsCacheDir)
+	if w.pluginsCacheDir == "" {
+		w.pluginsCacheDir = w.basedir
+	}
+	w.diskQuota = int64(FlagInt(cmd, flagDiskQuota)) << 20
+	w.gitCacheDir = FlagString(cmd, flagGitCacheDir)
 	w.bookedWJobID = FlagInt64(cmd, flagBookedWorkflowJobID)
 
 	w.client = cdsclient.NewWorker(w.apiEndpoint, w.status.Name, cdsclient.NewHTTPClient(time.Second*360, FlagBool(cmd, flagInsecure)))
 
 	w.autoUpdate = FlagBool(cmd, flagAutoUpdate)
 	w.singleUse = FlagBool(cmd, flagSingleUse)
+	w.pool = FlagString(cmd, flagPool)
 	w.grpc.address = FlagString(cmd, flagGRPCAPI)
 	w.grpc.insecure = FlagBool(cmd, flagGRPCInsecure)
 }
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCleanupStepKeys(t *testing.T) {
+	dir, err := ioutil.TempDir("", "keys")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	defer func(d string) { keysDirectory = d }(keysDirectory)
+	keysDirectory = filepath.Join(dir, "keys")
+	assert.NoError(t, os.MkdirAll(keysDirectory, 0700))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(keysDirectory, "cds.key.proj-mykey.priv"), []byte("private"), 0600))
+
+	w := &currentWorker{}
+	w.currentJob.gnupgHome = filepath.Join(dir, "gnupg")
+	assert.NoError(t, os.MkdirAll(w.currentJob.gnupgHome, 0700))
+	w.currentJob.pkey = filepath.Join(keysDirectory, "cds.key.proj-mykey.priv")
+
+	file := filepath.Join(dir, "id_rsa")
+	assert.NoError(t, ioutil.WriteFile(file, []byte("private"), 0600))
+	w.installedKeyFile(file)
+
+	w.cleanupStepKeys()
+
+	files, err := ioutil.ReadDir(keysDirectory)
+	assert.NoError(t, err)
+	assert.Len(t, files, 0)
+	_, err = os.Stat(w.currentJob.gnupgHome)
+	assert.True(t, os.IsNotExist(err))
+	_, err = os.Stat(file)
+	assert.True(t, os.IsNotExist(err))
+	assert.Equal(t, "", w.currentJob.pkey)
+	assert.Len(t, w.currentJob.keyFiles, 0)
+}
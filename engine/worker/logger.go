@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/golang/protobuf/ptypes"
@@ -15,14 +16,33 @@ import (
 	"github.com/ovh/cds/sdk/log"
 )
 
-var logsecrets []sdk.Variable
+var (
+	logsecrets      []sdk.Variable
+	logsecretsMutex sync.RWMutex
+)
+
+// setLogSecrets sets the secrets masked in the logs of the current job
+func setLogSecrets(secrets []sdk.Variable) {
+	logsecretsMutex.Lock()
+	defer logsecretsMutex.Unlock()
+	logsecrets = append([]sdk.Variable(nil), secrets...)
+}
+
+// addLogSecret masks a secret generated during the job in the next logs of the job
+func addLogSecret(secret sdk.Variable) {
+	logsecretsMutex.Lock()
+	defer logsecretsMutex.Unlock()
+	logsecrets = append(logsecrets, secret)
+}
+
+func maskLogSecrets(value string) string {
+	logsecretsMutex.RLock()
+	defer logsecretsMutex.RUnlock()
+	return sdk.MaskSecrets(value, logsecrets)
+}
 
 func (wk *currentWorker) sendLog(buildID int64, value string, stepOrder int, final bool) error {
-	for i := range logsecrets {
-		if len(logsecrets[i].Value) >= sdk.SecretMinLength {
-			value = strings.Replace(value, logsecrets[i].Value, "**"+logsecrets[i].Name+"**", -1)
-		}
-	}
+	value = maskLogSecrets(value)
 
 	l := sdk.NewLog(buildID, value, wk.currentJob.wJob.WorkflowNodeRunID, stepOrder)
 	if final {
@@ -35,35 +55,39 @@ func (wk *currentWorker) sendLog(buildID int64, value string, stepOrder int, fin
 }
 
 func (wk *currentWorker) logProcessor(ctx context.Context) error {
-	if wk.grpc.conn != nil {
-		if err := wk.grpcLogger(ctx, wk.logger.logChan); err != nil {
-			log.Error("GPPC logger : %s", err)
-		} else {
-			return nil
+	ticker := time.NewTicker(250 * time.Millisecond)
+	defer func() {
+		ticker.Stop()
+	}()
+
+	wk.logger.llist = list.New()
+	wk.logger.stream = newLogStream()
+	for {
+		// Stop reading the logs while too many of them are not acknowledged by the API,
+		// the steps are then blocked when writing their logs once the channel is full
+		logChan := wk.logger.logChan
+		if wk.logger.stream.full() {
+			logChan = nil
 		}
-	} else {
-		ticker := time.NewTicker(250 * time.Millisecond)
-		defer func() {
-			ticker.Stop()
-		}()
-
-		wk.logger.llist = list.New()
-		for {
-			select {
-			case l := <-wk.logger.logChan:
-				wk.logger.llist.PushBack(l)
-			case <-ctx.Done():
-				return ctx.Err()
-			case <-ticker.C:
-				wk.sendHTTPLog()
+
+		select {
+		case l := <-logChan:
+			wk.logger.llist.PushBack(l)
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			logs := wk.batchLogs()
+			if wk.grpc.conn != nil && !wk.logger.stream.disabled {
+				wk.streamLogs(ctx, logs)
+			} else {
+				wk.sendHTTPLog(logs)
 			}
 		}
 	}
-
-	return nil
 }
 
-func (wk *currentWorker) sendHTTPLog() {
+// batchLogs merges the waiting log lines in one log by step
+func (wk *currentWorker) batchLogs() []*sdk.Log {
 	var logs []*sdk.Log
 	var currentStepLog *sdk.Log
 	// While list is not empty
@@ -109,11 +133,10 @@ func (wk *currentWorker) sendHTTPLog() {
 	if currentStepLog != nil {
 		logs = append(logs, currentStepLog)
 	}
+	return logs
+}
 
-	if len(logs) == 0 {
-		return
-	}
-
+func (wk *currentWorker) sendHTTPLog(logs []*sdk.Log) {
 	for _, l := range logs {
 		log.Debug("LOG: %v", l.Val)
 		// Buffer log list is empty, sending batch to API
@@ -132,42 +155,31 @@ func (wk *currentWorker) sendHTTPLog() {
 	}
 }
 
-func (wk *currentWorker) grpcLogger(ctx context.Context, inputChan chan sdk.Log) error {
-	log.Info("Logging through grpc")
-
-	streamWorkflow, err := grpc.NewWorkflowQueueClient(wk.grpc.conn).SendLog(ctx)
-	if err != nil {
-		return err
+// streamLogs sends the logs through the grpc stream, which is opened again if it has been closed.
+// If it cannot be opened after several attempts, the logs which are not acknowledged are sent over http.
+func (wk *currentWorker) streamLogs(ctx context.Context, logs []*sdk.Log) {
+	s := wk.logger.stream
+	for _, l := range logs {
+		log.Debug("LOG: %v", l.Val)
+		s.send(s.add(l))
 	}
 
-	for {
-		l, ok := <-inputChan
-		if ok {
-
-			log.Debug("LOG: %v", l.Val)
-			var errSend error
-
-			errSend = streamWorkflow.Send(&l)
-
-			if errSend != nil {
-				log.Error("grpcLogger> Error sending message : %s", errSend)
-				//Close all
-				streamWorkflow.CloseSend()
-				wk.grpc.conn.Close()
-				wk.grpc.conn = nil
-				//Reinject log
-				inputChan <- l
-				return nil
-			}
-		} else {
-			return streamWorkflow.CloseSend()
+	if s.opened() || time.Now().Before(s.retryAt) {
+		return
+	}
+	if err := s.open(ctx, grpc.NewWorkflowQueueClient(wk.grpc.conn)); err != nil {
+		log.Error("streamLogs> Unable to open log stream (attempt %d): %v", s.failures, err)
+		if s.failures >= logStreamMaxFailures {
+			log.Error("streamLogs> Sending logs over http")
+			s.disabled = true
+			wk.sendHTTPLog(s.flush())
 		}
 	}
 }
 
 func (wk *currentWorker) drainLogsAndCloseLogger(c context.Context) error {
 	var i int
-	for (len(wk.logger.logChan) > 0 || (wk.logger.llist != nil && wk.logger.llist.Len() > 0)) && i < 60 {
+	for (len(wk.logger.logChan) > 0 || (wk.logger.llist != nil && wk.logger.llist.Len() > 0) || (wk.logger.stream != nil && wk.logger.stream.len() > 0)) && i < 60 {
 		log.Debug("Draining logs...")
 		i++
 		time.Sleep(1 * time.Second)
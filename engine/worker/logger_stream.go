@@ -0,0 +1,189 @@
+package main
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/ovh/cds/engine/api/grpc"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+const (
+	// logStreamMaxPendingSize is the size of the logs not acknowledged by the API above which
+	// the worker stops reading the logs of the steps
+	logStreamMaxPendingSize = 10 * 1024 * 1024
+	// logStreamMaxFailures is the number of consecutive failures of the stream after which
+	// the logs are sent over http
+	logStreamMaxFailures   = 10
+	logStreamMaxRetryDelay = 30 * time.Second
+)
+
+type logStepKey struct {
+	jobID, stepOrder int64
+}
+
+// logStream sends the logs of the steps through a grpc stream. The chunks of logs are kept until the API
+// acknowledges them, and sent again from their offset when the stream is opened again, so that no logs
+// are lost when the API restarts.
+type logStream struct {
+	mutex       sync.Mutex
+	client      grpc.WorkflowQueue_StreamLogClient
+	cancel      context.CancelFunc
+	offsets     map[logStepKey]int64
+	pending     []*sdk.LogChunk
+	pendingSize int
+	failures    int
+	retryAt     time.Time
+	disabled    bool
+}
+
+func newLogStream() *logStream {
+	return &logStream{offsets: map[logStepKey]int64{}}
+}
+
+// add gives its offset in the logs of its step to a log and keeps it until it is acknowledged
+func (s *logStream) add(l *sdk.Log) *sdk.LogChunk {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	k := logStepKey{jobID: l.PipelineBuildJobID, stepOrder: l.StepOrder}
+	c := &sdk.LogChunk{
+		JobID:     l.PipelineBuildJobID,
+		NodeRunID: l.PipelineBuildID,
+		StepOrder: l.StepOrder,
+		Offset:    s.offsets[k],
+		Val:       l.Val,
+		Done:      l.Done,
+	}
+	s.offsets[k] += int64(len(l.Val))
+	s.pending = append(s.pending, c)
+	s.pendingSize += len(c.Val)
+	return c
+}
+
+// send sends a chunk if the stream is opened, the stream is closed if the chunk cannot be sent
+func (s *logStream) send(c *sdk.LogChunk) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.client == nil {
+		return
+	}
+	if err := s.client.Send(c); err != nil {
+		log.Error("logStream> Unable to send logs: %v", err)
+		s.close()
+	}
+}
+
+// open opens the stream and sends again the chunks which are not acknowledged
+func (s *logStream) open(ctx context.Context, client grpc.WorkflowQueueClient) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	streamCtx, cancel := context.WithCancel(ctx)
+	stream, err := client.StreamLog(streamCtx)
+	if err != nil {
+		cancel()
+		s.retry()
+		return err
+	}
+	s.client, s.cancel = stream, cancel
+
+	for _, c := range s.pending {
+		if err := stream.Send(c); err != nil {
+			s.close()
+			return err
+		}
+	}
+
+	go s.receiveAcks(stream)
+	return nil
+}
+
+func (s *logStream) receiveAcks(stream grpc.WorkflowQueue_StreamLogClient) {
+	for {
+		a, err := stream.Recv()
+		if err != nil {
+			s.mutex.Lock()
+			if s.client == stream {
+				log.Error("logStream> Unable to receive acknowledgments: %v", err)
+				s.close()
+			}
+			s.mutex.Unlock()
+			return
+		}
+		s.ack(a)
+	}
+}
+
+// ack removes the chunks of a step persisted by the API
+func (s *logStream) ack(a *sdk.LogAck) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.failures = 0
+	pending := s.pending[:0]
+	for _, c := range s.pending {
+		if c.JobID == a.JobID && c.StepOrder == a.StepOrder && c.Offset+int64(len(c.Val)) <= a.Offset {
+			s.pendingSize -= len(c.Val)
+			continue
+		}
+		pending = append(pending, c)
+	}
+	s.pending = pending
+}
+
+// close closes the stream, it will be opened again after a delay growing with the number of failures
+func (s *logStream) close() {
+	if s.client != nil {
+		_ = s.client.CloseSend()
+		s.cancel()
+		s.client = nil
+	}
+	s.retry()
+}
+
+func (s *logStream) retry() {
+	s.failures++
+	delay := time.Duration(s.failures) * time.Second
+	if delay > logStreamMaxRetryDelay {
+		delay = logStreamMaxRetryDelay
+	}
+	s.retryAt = time.Now().Add(delay)
+}
+
+// flush returns the chunks which are not acknowledged as logs, and forgets them
+func (s *logStream) flush() []*sdk.Log {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	logs := make([]*sdk.Log, 0, len(s.pending))
+	for _, c := range s.pending {
+		l := sdk.NewLog(c.JobID, c.Val, c.NodeRunID, int(c.StepOrder))
+		l.Done = c.Done
+		logs = append(logs, l)
+	}
+	s.pending = nil
+	s.pendingSize = 0
+	return logs
+}
+
+func (s *logStream) opened() bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.client != nil
+}
+
+func (s *logStream) full() bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.pendingSize >= logStreamMaxPendingSize
+}
+
+func (s *logStream) len() int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return len(s.pending)
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func TestLogStream(t *testing.T) {
+	s := newLogStream()
+
+	c1 := s.add(sdk.NewLog(1, "line 1\n", 10, 0))
+	c2 := s.add(sdk.NewLog(1, "line 2\n", 10, 0))
+	c3 := s.add(sdk.NewLog(1, "other step\n", 10, 1))
+	assert.Equal(t, int64(0), c1.Offset)
+	assert.Equal(t, int64(7), c2.Offset)
+	assert.Equal(t, int64(0), c3.Offset)
+	assert.Equal(t, 3, s.len())
+
+	// Only the chunks of the step persisted until the offset are acknowledged
+	s.ack(&sdk.LogAck{JobID: 1, StepOrder: 0, Offset: 7})
+	assert.Equal(t, 2, s.len())
+	assert.Equal(t, len("line 2\n")+len("other step\n"), s.pendingSize)
+
+	logs := s.flush()
+	assert.Len(t, logs, 2)
+	assert.Equal(t, "line 2\n", logs[0].Val)
+	assert.Equal(t, "other step\n", logs[1].Val)
+	assert.Equal(t, 0, s.len())
+	assert.False(t, s.full())
+
+	// Offsets keep growing after a flush
+	assert.Equal(t, int64(14), s.add(sdk.NewLog(1, "line 3\n", 10, 0)).Offset)
+}
@@ -0,0 +1,22 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func TestMaskLogSecrets(t *testing.T) {
+	secrets := []sdk.Variable{{Name: "cds.proj.password", Value: "my-password"}}
+	setLogSecrets(secrets)
+	defer setLogSecrets(nil)
+
+	addLogSecret(sdk.Variable{Name: maskedSecretName, Value: "my-temporary-token"})
+	assert.Len(t, secrets, 1, "the secrets of the job must not be modified")
+	assert.Equal(t, "curl -u **cds.proj.password** -H 'Token: **masked**'", maskLogSecrets("curl -u my-password -H 'Token: my-temporary-token'"))
+
+	setLogSecrets(nil)
+	assert.Equal(t, "my-temporary-token", maskLogSecrets("my-temporary-token"))
+}
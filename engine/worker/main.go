@@ -12,6 +12,8 @@ import (
 type currentWorker struct {
 	autoUpdate    bool
 	singleUse     bool
+	pool          string
+	drained       bool
 	apiEndpoint   string
 	token         string
 	id            string
@@ -20,10 +22,13 @@ type currentWorker struct {
 	bookedWJobID  int64
 	nbActionsDone int
 	basedir       string
+	diskQuota     int64
+	gitCacheDir   string
 	manualExit    bool
 	logger        struct {
 		logChan chan sdk.Log
 		llist   *list.List
+		stream  *logStream
 	}
 	exportPort int32
 	hatchery   struct {
@@ -39,16 +44,20 @@ type currentWorker struct {
 		currentStep      int
 		buildVariables   []sdk.Variable
 		pkey             string
-		gitsshPath       string
+		keyFiles         []string
+		gnupgHome        string
 		params           []sdk.Parameter
 		secrets          []sdk.Variable
 		workingDirectory string
+		tmpDirectory     string
+		stepUsage        *stepUsage
 	}
 	status struct {
 		Name   string `json:"name"`
 		Status string `json:"status"`
 	}
-	client cdsclient.Interface
+	client          cdsclient.Interface
+	pluginsCacheDir string
 }
 
 func main() {
@@ -63,6 +72,10 @@ func main() {
 	cmd.AddCommand(cmdTmpl(w))
 	cmd.AddCommand(cmdCheckSecret(w))
 	cmd.AddCommand(cmdTag(w))
+	cmd.AddCommand(cmdResult(w))
+	cmd.AddCommand(cmdOutput(w))
+	cmd.AddCommand(cmdTmpFile(w))
+	cmd.AddCommand(cmdMask(w))
 	cmd.AddCommand(cmdRun(w))
 	cmd.AddCommand(cmdUpdate(w))
 	cmd.AddCommand(cmdExit(w))
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// pluginBinaryPath returns the path of a plugin binary in the cache of the worker. The binaries are cached by
+// checksum, so that a new binary uploaded for a plugin is downloaded again.
+func pluginBinaryPath(cacheDir string, b *sdk.GRPCPluginBinary) string {
+	if b.SHA256sum == "" {
+		return path.Join(cacheDir, b.Name)
+	}
+	return path.Join(cacheDir, b.SHA256sum, b.Name)
+}
+
+// checkPluginBinary verifies the sha256 checksum of a plugin binary. The binaries uploaded without checksum
+// are not checked.
+func checkPluginBinary(file string, b *sdk.GRPCPluginBinary) error {
+	if b.SHA256sum == "" {
+		return nil
+	}
+	sum, err := sdk.FileSHA256sum(file)
+	if err != nil {
+		return err
+	}
+	if sum != b.SHA256sum {
+		return fmt.Errorf("checksum of plugin binary %s does not match: expected %s, got %s", b.Name, b.SHA256sum, sum)
+	}
+	return nil
+}
+
+// downloadPluginBinary downloads the binary of a plugin in the cache of the worker if it's not already there and
+// returns its path. The binary is downloaded only when the plugin is run.
+func downloadPluginBinary(w *currentWorker, pluginName string, b *sdk.GRPCPluginBinary) (string, error) {
+	file := pluginBinaryPath(w.pluginsCacheDir, b)
+	if _, err := os.Stat(file); err == nil {
+		if err := checkPluginBinary(file, b); err == nil {
+			log.Debug("plugin binary is in cache %s", file)
+			return file, nil
+		}
+		log.Warning("downloadPluginBinary> plugin binary %s in cache is corrupted, downloading it again", file)
+		_ = os.Remove(file)
+	}
+
+	if err := os.MkdirAll(path.Dir(file), os.FileMode(0755)); err != nil {
+		return "", sdk.WithStack(err)
+	}
+
+	// The binary is downloaded in a temporary file then renamed, so that the workers sharing the cache never use
+	// a binary partially downloaded
+	log.Debug("Downloading the plugin %s", b.Name)
+	tmp, err := ioutil.TempFile(path.Dir(file), b.Name+".")
+	if err != nil {
+		return "", sdk.WithStack(err)
+	}
+	defer os.Remove(tmp.Name()) // nolint
+
+	if err := w.client.PluginGetBinary(pluginName, b.OS, b.Arch, tmp); err != nil {
+		_ = tmp.Close()
+		return "", sdk.WrapError(err, "unable to download plugin %s", pluginName)
+	}
+	if err := tmp.Close(); err != nil {
+		return "", sdk.WithStack(err)
+	}
+	perm := os.FileMode(b.Perm)
+	if perm == 0 {
+		perm = os.FileMode(0755)
+	}
+	if err := os.Chmod(tmp.Name(), perm); err != nil {
+		return "", sdk.WithStack(err)
+	}
+	if err := checkPluginBinary(tmp.Name(), b); err != nil {
+		return "", err
+	}
+	if err := os.Rename(tmp.Name(), file); err != nil {
+		return "", sdk.WithStack(err)
+	}
+
+	log.Info("plugin successfully downloaded: %s", file)
+	return file, nil
+}
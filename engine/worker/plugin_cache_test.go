@@ -0,0 +1,42 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/grpcplugin"
+)
+
+func TestCheckPluginBinary(t *testing.T) {
+	dir, err := ioutil.TempDir("", "plugins")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	file := path.Join(dir, "my-plugin")
+	assert.NoError(t, ioutil.WriteFile(file, []byte("my plugin"), os.FileMode(0755)))
+	sum, err := sdk.FileSHA256sum(file)
+	assert.NoError(t, err)
+
+	b := sdk.GRPCPluginBinary{Name: "my-plugin", SHA256sum: sum}
+	assert.Equal(t, path.Join(dir, sum, "my-plugin"), pluginBinaryPath(dir, &b))
+	assert.NoError(t, checkPluginBinary(file, &b))
+
+	b.SHA256sum = "0123456789"
+	assert.Error(t, checkPluginBinary(file, &b))
+
+	// Binaries uploaded without checksum are not checked
+	b.SHA256sum = ""
+	assert.Equal(t, file, pluginBinaryPath(dir, &b))
+	assert.NoError(t, checkPluginBinary(file, &b))
+}
+
+func TestCheckPluginCapabilities(t *testing.T) {
+	assert.NoError(t, grpcplugin.CheckCapabilities("my-plugin", nil))
+	assert.NoError(t, grpcplugin.CheckCapabilities("my-plugin", []string{grpcplugin.CapabilityWorkerHTTPPort}))
+	assert.Error(t, grpcplugin.CheckCapabilities("my-plugin", []string{grpcplugin.CapabilityLogs, "teleport"}))
+}
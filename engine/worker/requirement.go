@@ -5,7 +5,6 @@ import (
 	"net"
 	"os"
 	"os/exec"
-	"path"
 	"strconv"
 	"strings"
 	"time"
@@ -82,32 +81,11 @@ func checkPluginRequirement(w *currentWorker, r sdk.Requirement) (bool, error) {
 	var currentOS = strings.ToLower(sdk.GOOS)
 	var currentARCH = strings.ToLower(sdk.GOARCH)
 
-	binary, err := w.client.PluginGetBinaryInfos(r.Name, currentOS, currentARCH)
-	if err != nil {
+	// The plugin is downloaded when the step using it is run, only check that it's available for this os and arch
+	if _, err := w.client.PluginGetBinaryInfos(r.Name, currentOS, currentARCH); err != nil {
 		return false, err
 	}
 
-	// then try to download the plugin
-	pluginBinary := path.Join(w.basedir, binary.Name)
-	if _, err := os.Stat(pluginBinary); os.IsNotExist(err) {
-		log.Debug("Downloading the plugin %s", binary.Name)
-		//If the file doesn't exist. Download it.
-		fi, err := os.OpenFile(pluginBinary, os.O_CREATE|os.O_RDWR, os.FileMode(binary.Perm))
-		if err != nil {
-			return false, err
-		}
-
-		log.Debug("Get the binary plugin %s", r.Name)
-		if err := w.client.PluginGetBinary(r.Name, currentOS, currentARCH, fi); err != nil {
-			_ = fi.Close()
-			return false, err
-		}
-		//It's downloaded. Close the file
-		_ = fi.Close()
-	} else {
-		log.Debug("plugin binary is in cache %s", pluginBinary)
-	}
-
 	return true, nil
 }
 
@@ -220,7 +198,7 @@ func checkOSArchRequirement(w *currentWorker, r sdk.Requirement) (bool, error) {
 
 // checkPluginDeployment returns true if current job:
 //  - is not linked to a deployment integration
-//  - is linked to a deployement integration, plugin well downloaded (in this func) and
+//  - is linked to a deployement integration available for the os and arch of the worker and
 //    requirements on the plugins are OK too
 func checkPluginDeployment(w *currentWorker, job sdk.WorkflowNodeJobRun) (bool, error) {
 	var currentOS = strings.ToLower(sdk.GOOS)
@@ -256,27 +234,6 @@ func checkPluginDeployment(w *currentWorker, job sdk.WorkflowNodeJobRun) (bool,
 		}
 	}
 
-	// then try to download the plugin
-	integrationPluginBinary := path.Join(w.basedir, binary.Name)
-	if _, err := os.Stat(integrationPluginBinary); os.IsNotExist(err) {
-		log.Debug("Downloading the plugin %s", binary.PluginName)
-		//If the file doesn't exist. Download it.
-		fi, err := os.OpenFile(integrationPluginBinary, os.O_CREATE|os.O_RDWR, os.FileMode(binary.Perm))
-		if err != nil {
-			return false, err
-		}
-
-		if err := w.client.PluginGetBinary(binary.PluginName, currentOS, currentARCH, fi); err != nil {
-			_ = fi.Close()
-			return false, err
-		}
-		//It's downloaded. Close the file
-		_ = fi.Close()
-	} else {
-		log.Debug("plugin binary is in cache")
-	}
-
-	log.Info("plugin successfully downloaded: %#v", binary.Name)
-
+	// the plugin is downloaded when the deployment step is run
 	return true, nil
 }
@@ -7,14 +7,17 @@ import (
 	"os"
 	"os/user"
 	"path"
+	"runtime/debug"
 	"strings"
 	"time"
 
+	"github.com/golang/protobuf/ptypes"
+
+	"github.com/ovh/cds/engine/api/grpc"
 	"github.com/ovh/cds/sdk"
 	"github.com/ovh/cds/sdk/cdsclient"
 	"github.com/ovh/cds/sdk/interpolate"
 	"github.com/ovh/cds/sdk/log"
-	"github.com/ovh/cds/sdk/vcs"
 )
 
 func processJobParameter(params *[]sdk.Parameter, secrets []sdk.Variable) {
@@ -255,7 +258,13 @@ func (w *currentWorker) runSteps(ctx context.Context, steps []sdk.Action, a *sdk
 			}
 			_ = w.sendLog(buildID, fmt.Sprintf("Starting step \"%s\"\n", childName), w.currentJob.currentStep, false)
 
+			usage := w.startStepUsage()
 			r = w.startAction(ctx, &child, buildID, params, secrets, w.currentJob.currentStep, childName)
+			stepUsage := w.stopStepUsage(usage)
+			// the keys installed by a step of the job are not kept for the next steps
+			if stepOrder == -1 {
+				w.cleanupStepKeys()
+			}
 			if r.Status != sdk.StatusSuccess.String() && !child.Optional {
 				criticalStepFailed = true
 			}
@@ -266,8 +275,8 @@ func (w *currentWorker) runSteps(ctx context.Context, steps []sdk.Action, a *sdk
 				_ = w.sendLog(buildID, fmt.Sprintf("End of step \"%s\" [%s]", childName, r.Status), w.currentJob.currentStep, true)
 			}
 
-			// Update step status
-			if err := w.updateStepStatus(ctx, buildID, w.currentJob.currentStep, r.Status); err != nil {
+			// Update step status with the resources used by the step
+			if err := w.updateStepStatusWithUsage(ctx, buildID, w.currentJob.currentStep, r.Status, stepUsage); err != nil {
 				log.Warning("Cannot update step (%d) status (%s) for build %d: %s", w.currentJob.currentStep, sdk.StatusDisabled.String(), buildID, err)
 			}
 		} else if criticalStepFailed && !child.AlwaysExecuted { // Update status of steps which are never built
@@ -288,11 +297,33 @@ func (w *currentWorker) runSteps(ctx context.Context, steps []sdk.Action, a *sdk
 }
 
 func (w *currentWorker) updateStepStatus(ctx context.Context, buildID int64, stepOrder int, status string) error {
+	return w.updateStepStatusWithUsage(ctx, buildID, stepOrder, status, nil)
+}
+
+func (w *currentWorker) updateStepStatusWithUsage(ctx context.Context, buildID int64, stepOrder int, status string, usage *sdk.StepResourceUsage) error {
 	step := sdk.StepStatus{
 		StepOrder: stepOrder,
 		Status:    status,
 		Start:     time.Now(),
 		Done:      time.Now(),
+		Usage:     usage,
+	}
+
+	// Try to send the step status through grpc, the usage is only sent over http
+	if w.grpc.conn != nil && usage == nil {
+		start, _ := ptypes.TimestampProto(step.Start)
+		done, _ := ptypes.TimestampProto(step.Done)
+		_, err := grpc.NewWorkflowQueueClient(w.grpc.conn).SendStepStatus(ctx, &sdk.StepStatusUpdate{
+			JobID:     buildID,
+			StepOrder: int64(stepOrder),
+			Status:    status,
+			Start:     start,
+			Done:      done,
+		})
+		if err == nil {
+			return nil
+		}
+		log.Error("updateStepStatus> Unable to send step status through grpc: %v", err)
 	}
 
 	path := fmt.Sprintf("/queue/workflows/%d/step", buildID)
@@ -335,11 +366,6 @@ func setupBuildDirectory(wd string) error {
 	return os.Setenv("HOME", wd)
 }
 
-// remove the buildDirectory created by setupBuildDirectory
-func teardownBuildDirectory(wd string) error {
-	return os.RemoveAll(wd)
-}
-
 func workingDirectory(basedir string, jobInfo *sdk.WorkflowNodeJobRunData, suffixes ...string) string {
 	var encodedName = base64.RawStdEncoding.EncodeToString([]byte(jobInfo.NodeJobRun.Job.Job.Action.Name))
 	paths := append([]string{basedir, encodedName}, suffixes...)
@@ -352,14 +378,30 @@ func workingDirectory(basedir string, jobInfo *sdk.WorkflowNodeJobRunData, suffi
 	return dir
 }
 
-func (w *currentWorker) processJob(ctx context.Context, jobInfo *sdk.WorkflowNodeJobRunData) sdk.Result {
+func (w *currentWorker) processJob(ctx context.Context, jobInfo *sdk.WorkflowNodeJobRunData) (res sdk.Result) {
 	t0 := time.Now()
 	ctx, cancel := context.WithTimeout(ctx, 6*time.Hour)
 
 	defer func() { log.Info("processJob> Process Job Done (%s)", sdk.Round(time.Since(t0), time.Second).String()) }()
+	// a panic fails the job, the job directory is removed before
+	defer func() {
+		if r := recover(); r != nil {
+			log.Error("processJob> panic: %v\n%s", r, debug.Stack())
+			res = sdk.Result{
+				Status: sdk.StatusFail.String(),
+				Reason: fmt.Sprintf("Error: worker internal error: %v", r),
+			}
+		}
+	}()
 	defer cancel()
 	defer w.drainLogsAndCloseLogger(ctx)
 
+	// The job directory contains the working directory, the keys and the temporary files of the job,
+	// it is removed at the end of the job, or by the main worker process if this one is killed
+	jobDir := workingDirectory(w.basedir, jobInfo)
+	w.registerJobDirectory(jobDir)
+	defer w.cleanupJobDirectory(jobDir)
+
 	// Setup working directory
 	wd := workingDirectory(w.basedir, jobInfo, "run")
 
@@ -372,6 +414,16 @@ func (w *currentWorker) processJob(ctx context.Context, jobInfo *sdk.WorkflowNod
 	}
 	w.currentJob.workingDirectory = wd
 
+	tmpDir := workingDirectory(w.basedir, jobInfo, "tmp")
+	if err := os.MkdirAll(tmpDir, 0755); err != nil {
+		return sdk.Result{
+			Status: sdk.StatusFail.String(),
+			Reason: fmt.Sprintf("Error: cannot setup temporary directory: %s", err),
+		}
+	}
+	w.currentJob.tmpDirectory = tmpDir
+	defer func() { w.currentJob.tmpDirectory = "" }()
+
 	//Add working directory as job parameter
 	jobInfo.NodeJobRun.Parameters = append(jobInfo.NodeJobRun.Parameters, sdk.Parameter{
 		Name:  "cds.workspace",
@@ -407,42 +459,36 @@ func (w *currentWorker) processJob(ctx context.Context, jobInfo *sdk.WorkflowNod
 		jobInfo.NodeJobRun.Parameters = append(jobInfo.NodeJobRun.Parameters, p)
 	}
 
-	// Setup user ssh keys
+	// Setup the directory of the user ssh keys, the keys are only written when a step installs them
+	// and are removed at the end of the step
 	keysDirectory = workingDirectory(w.basedir, jobInfo, "keys")
 	log.Debug("processJob> Setup user ssh keys - mkdir %s", keysDirectory)
-	if err := os.MkdirAll(keysDirectory, 0755); err != nil {
+	if err := os.MkdirAll(keysDirectory, 0700); err != nil {
 		log.Debug("processJob> call os.MkdirAll error:%s", err)
 		return sdk.Result{
 			Status: sdk.StatusFail.String(),
 			Reason: fmt.Sprintf("Error: cannot setup workingDirectory (%s)", err),
 		}
 	}
+	w.currentJob.gnupgHome = workingDirectory(w.basedir, jobInfo, "gnupg")
+	defer func() { w.currentJob.gnupgHome = "" }()
 
-	// DEPRECATED - BEGIN
-	if err := w.setupSSHKey(jobInfo.Secrets, keysDirectory); err != nil {
-		log.Debug("processJob> call w.setupSSHKey error:%s", err)
+	// Wait for the services of the job before running the steps
+	if err := w.waitServices(ctx, jobInfo.NodeJobRun.ID, jobInfo.NodeJobRun.Job.Action.Requirements); err != nil {
 		return sdk.Result{
 			Status: sdk.StatusFail.String(),
-			Reason: fmt.Sprintf("Error: cannot setup ssh key (%s)", err),
+			Reason: fmt.Sprintf("Error: %s", err),
 		}
 	}
-	// DEPRECATED - END
 
-	// The right way to go is :
-	if err := vcs.SetupSSHKey(jobInfo.Secrets, keysDirectory, nil); err != nil {
-		log.Debug("processJob> call vcs.SetupSSHKey error:%s", err)
-		return sdk.Result{
-			Status: sdk.StatusFail.String(),
-			Reason: fmt.Sprintf("Error: cannot setup vcs ssh key (%s)", err),
-		}
+	setLogSecrets(jobInfo.Secrets)
+	ctx, stopDiskQuota := w.watchDiskQuota(ctx, jobInfo.NodeJobRun.ID, jobDir)
+	res = w.startAction(ctx, &jobInfo.NodeJobRun.Job.Action, jobInfo.NodeJobRun.ID, &jobInfo.NodeJobRun.Parameters, jobInfo.Secrets, -1, "")
+	if reason := stopDiskQuota(); reason != "" {
+		res.Status = sdk.StatusFail.String()
+		res.Reason = reason
 	}
+	setLogSecrets(nil)
 
-	logsecrets = jobInfo.Secrets
-	res := w.startAction(ctx, &jobInfo.NodeJobRun.Job.Action, jobInfo.NodeJobRun.ID, &jobInfo.NodeJobRun.Parameters, logsecrets, -1, "")
-	logsecrets = nil
-
-	if err := teardownBuildDirectory(wd); err != nil {
-		log.Error("Cannot remove build directory: %s", err)
-	}
 	return res
 }
@@ -101,8 +101,13 @@ func startGRPCPlugin(ctx context.Context, pluginName string, w *currentWorker, p
 	}
 	envs = append(envs, opts.envs...)
 
+	binaryPath, err := downloadPluginBinary(w, pluginName, binary)
+	if err != nil {
+		return nil, sdk.WrapError(err, "plugin:%s unable to download plugin binary file... Aborting", pluginName)
+	}
+
 	log.Info("Starting GRPC Plugin %s in dir %s", binary.Name, dir)
-	fileContent, err := ioutil.ReadFile(path.Join(w.basedir, binary.GetName()))
+	fileContent, err := ioutil.ReadFile(binaryPath)
 	if err != nil {
 		return nil, sdk.WrapError(err, "plugin:%s unable to get plugin binary file... Aborting", pluginName)
 	}
@@ -124,9 +129,13 @@ func startGRPCPlugin(ctx context.Context, pluginName string, w *currentWorker, p
 
 	cmd := binary.Cmd
 	if _, err := exec.LookPath(cmd); err != nil {
-		cmd = path.Join(w.basedir, cmd)
+		cmd = path.Join(w.basedir, binary.Cmd)
 		if _, err := exec.LookPath(cmd); err != nil {
-			return nil, sdk.WrapError(err, "plugin:%s unable to start GRPC plugin, binary command not found.", pluginName)
+			// The binary is not an archive, it is run from the cache
+			cmd = path.Join(path.Dir(binaryPath), binary.Cmd)
+			if _, err := exec.LookPath(cmd); err != nil {
+				return nil, sdk.WrapError(err, "plugin:%s unable to start GRPC plugin, binary command not found.", pluginName)
+			}
 		}
 	}
 	args := append(binary.Entrypoints, binary.Args...)
@@ -0,0 +1,173 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// Options of a service requirement defining its readiness check
+// example: postgres:9.5.3 POSTGRES_PASSWORD=pg CDS_SERVICE_READY_TCP=5432 CDS_SERVICE_READY_TIMEOUT=60
+const (
+	serviceReadyTCP     = "CDS_SERVICE_READY_TCP"
+	serviceReadyHTTP    = "CDS_SERVICE_READY_HTTP"
+	serviceReadyCmd     = "CDS_SERVICE_READY_CMD"
+	serviceReadyTimeout = "CDS_SERVICE_READY_TIMEOUT"
+
+	defaultServiceReadyTimeout = 60 * time.Second
+	serviceReadyInterval       = 2 * time.Second
+)
+
+type serviceReadiness struct {
+	service string
+	tcp     string
+	http    string
+	cmd     string
+	timeout time.Duration
+}
+
+// serviceReadinessCheck parses the readiness check of a service requirement,
+// it returns nil if the service has no check
+func serviceReadinessCheck(r sdk.Requirement) (*serviceReadiness, error) {
+	s := &serviceReadiness{service: r.Name, timeout: defaultServiceReadyTimeout}
+	opts := sdk.SplitServiceRequirementValue(r.Value)
+	if len(opts) > 0 {
+		// the first one is the image
+		opts = opts[1:]
+	}
+	for _, opt := range opts {
+		kv := strings.SplitN(opt, "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		switch kv[0] {
+		case serviceReadyTCP:
+			s.tcp = net.JoinHostPort(r.Name, kv[1])
+		case serviceReadyHTTP:
+			// the url can be relative to the service, example: :8080/health
+			s.http = kv[1]
+			if !strings.HasPrefix(s.http, "http://") && !strings.HasPrefix(s.http, "https://") {
+				s.http = "http://" + r.Name + s.http
+			}
+		case serviceReadyCmd:
+			s.cmd = kv[1]
+		case serviceReadyTimeout:
+			t, err := strconv.Atoi(kv[1])
+			if err != nil || t <= 0 {
+				return nil, fmt.Errorf("invalid %s %s for service %s", serviceReadyTimeout, kv[1], r.Name)
+			}
+			s.timeout = time.Duration(t) * time.Second
+		}
+	}
+	if s.tcp == "" && s.http == "" && s.cmd == "" {
+		return nil, nil
+	}
+	return s, nil
+}
+
+// ready runs once the checks of the service
+func (s *serviceReadiness) ready(ctx context.Context) error {
+	if s.tcp != "" {
+		conn, err := net.DialTimeout("tcp", s.tcp, serviceReadyInterval)
+		if err != nil {
+			return err
+		}
+		conn.Close()
+	}
+	if s.http != "" {
+		req, err := http.NewRequest(http.MethodGet, s.http, nil)
+		if err != nil {
+			return err
+		}
+		ctxHTTP, cancel := context.WithTimeout(ctx, 5*time.Second)
+		defer cancel()
+		resp, err := http.DefaultClient.Do(req.WithContext(ctxHTTP))
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return fmt.Errorf("%s returns %d", s.http, resp.StatusCode)
+		}
+	}
+	if s.cmd != "" {
+		if out, err := exec.CommandContext(ctx, "sh", "-c", s.cmd).CombinedOutput(); err != nil {
+			return fmt.Errorf("%s: %v %s", s.cmd, err, strings.TrimSpace(string(out)))
+		}
+	}
+	return nil
+}
+
+// wait runs the checks of the service until they succeed or the timeout is reached
+func (s *serviceReadiness) wait(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(serviceReadyInterval)
+	defer ticker.Stop()
+	for {
+		err := s.ready(ctx)
+		if err == nil {
+			return nil
+		}
+		log.Debug("service %s is not ready: %v", s.service, err)
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("service %s is not ready after %s: %v", s.service, s.timeout, err)
+		case <-ticker.C:
+		}
+	}
+}
+
+// waitServices waits for the readiness checks of the services of the job,
+// and reports the wait duration in the spawn infos of the job
+func (w *currentWorker) waitServices(ctx context.Context, jobID int64, requirements []sdk.Requirement) error {
+	var checks []*serviceReadiness
+	for _, r := range requirements {
+		if r.Type != sdk.ServiceRequirement {
+			continue
+		}
+		s, err := serviceReadinessCheck(r)
+		if err != nil {
+			return err
+		}
+		if s != nil {
+			checks = append(checks, s)
+		}
+	}
+	if len(checks) == 0 {
+		return nil
+	}
+
+	start := time.Now()
+	var errWait error
+	names := make([]string, len(checks))
+	for i, s := range checks {
+		names[i] = s.service
+		if err := s.wait(ctx); err != nil {
+			errWait = err
+			break
+		}
+	}
+	duration := sdk.Round(time.Since(start), time.Second).String()
+
+	info := sdk.SpawnInfo{RemoteTime: time.Now()}
+	if errWait != nil {
+		info.Message = sdk.SpawnMsg{ID: sdk.MsgSpawnInfoWorkerServicesNotReady.ID, Args: []interface{}{w.status.Name, errWait.Error()}}
+	} else {
+		info.Message = sdk.SpawnMsg{ID: sdk.MsgSpawnInfoWorkerServicesReady.ID, Args: []interface{}{w.status.Name, strings.Join(names, ", "), duration}}
+	}
+	log.Info("waitServices> services %s checked in %s", strings.Join(names, ", "), duration)
+	if err := w.client.QueueJobSendSpawnInfo(ctx, jobID, []sdk.SpawnInfo{info}); err != nil {
+		log.Warning("waitServices> Cannot record spawn info for job %d: %v", jobID, err)
+	}
+	return errWait
+}
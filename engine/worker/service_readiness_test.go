@@ -0,0 +1,41 @@
+package main
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func TestServiceReadinessCheck(t *testing.T) {
+	s, err := serviceReadinessCheck(sdk.Requirement{Name: "pg", Type: sdk.ServiceRequirement, Value: "postgres:9.5.3 POSTGRES_PASSWORD=pg"})
+	assert.NoError(t, err)
+	assert.Nil(t, s)
+
+	s, err = serviceReadinessCheck(sdk.Requirement{Name: "pg", Type: sdk.ServiceRequirement,
+		Value: `postgres:9.5.3 CDS_SERVICE_READY_TCP=5432 CDS_SERVICE_READY_HTTP=:8080/health CDS_SERVICE_READY_CMD="pg_isready -h pg" CDS_SERVICE_READY_TIMEOUT=30`})
+	assert.NoError(t, err)
+	assert.Equal(t, "pg:5432", s.tcp)
+	assert.Equal(t, "http://pg:8080/health", s.http)
+	assert.Equal(t, "pg_isready -h pg", s.cmd)
+	assert.Equal(t, 30*time.Second, s.timeout)
+
+	_, err = serviceReadinessCheck(sdk.Requirement{Name: "pg", Type: sdk.ServiceRequirement, Value: "postgres:9.5.3 CDS_SERVICE_READY_TCP=5432 CDS_SERVICE_READY_TIMEOUT=foo"})
+	assert.Error(t, err)
+}
+
+func TestServiceReadinessWait(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer l.Close()
+
+	s := &serviceReadiness{service: "local", tcp: l.Addr().String(), cmd: "true", timeout: time.Second}
+	assert.NoError(t, s.wait(context.Background()))
+
+	s.cmd = "false"
+	assert.Error(t, s.wait(context.Background()))
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// stepUsageSamplingInterval is the interval between two samplings of the working directory size
+var stepUsageSamplingInterval = 30 * time.Second
+
+// stepUsage records the resources consumed by the processes of the current step
+type stepUsage struct {
+	mutex sync.Mutex
+	usage sdk.StepResourceUsage
+	dir   string
+	stop  chan struct{}
+	done  chan struct{}
+}
+
+// startStepUsage starts recording the resources used by the current step.
+// It returns nil if a parent step is already recording, its usage includes its children.
+func (w *currentWorker) startStepUsage() *stepUsage {
+	if w.currentJob.stepUsage != nil {
+		return nil
+	}
+	u := &stepUsage{
+		dir:  w.currentJob.workingDirectory,
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+	w.currentJob.stepUsage = u
+
+	go func() {
+		defer close(u.done)
+		ticker := time.NewTicker(stepUsageSamplingInterval)
+		defer ticker.Stop()
+		for {
+			u.sampleDisk()
+			select {
+			case <-u.stop:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	return u
+}
+
+// stopStepUsage stops the recording started by startStepUsage and returns the usage of the step
+func (w *currentWorker) stopStepUsage(u *stepUsage) *sdk.StepResourceUsage {
+	if u == nil {
+		return nil
+	}
+	close(u.stop)
+	<-u.done
+	u.sampleDisk()
+	w.currentJob.stepUsage = nil
+
+	u.mutex.Lock()
+	defer u.mutex.Unlock()
+	usage := u.usage
+	return &usage
+}
+
+// addProcess adds the CPU time and the memory of an exited process to the usage of the step
+func (u *stepUsage) addProcess(state *os.ProcessState) {
+	if u == nil || state == nil {
+		return
+	}
+	u.mutex.Lock()
+	defer u.mutex.Unlock()
+	u.usage.CPUTime += (state.UserTime() + state.SystemTime()).Seconds()
+	if rss := maxRSS(state); rss > u.usage.MaxMemory {
+		u.usage.MaxMemory = rss
+	}
+}
+
+func (u *stepUsage) sampleDisk() {
+	if u.dir == "" {
+		return
+	}
+	size, err := dirSize(u.dir)
+	if err != nil {
+		log.Warning("stepUsage> unable to compute the size of %s: %v", u.dir, err)
+		return
+	}
+	u.mutex.Lock()
+	defer u.mutex.Unlock()
+	if size > u.usage.MaxDisk {
+		u.usage.MaxDisk = size
+	}
+}
+
+// dirSize returns the size of the regular files in a directory, files removed during the walk are ignored
+func dirSize(dir string) (int64, error) {
+	var size int64
+	err := filepath.Walk(dir, func(_ string, info os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if info.Mode().IsRegular() {
+			size += info.Size()
+		}
+		return nil
+	})
+	return size, err
+}
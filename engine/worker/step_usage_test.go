@@ -0,0 +1,42 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStepUsage(t *testing.T) {
+	dir, err := ioutil.TempDir("", "step-usage")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	assert.NoError(t, os.MkdirAll(filepath.Join(dir, "sub"), 0755))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "a"), make([]byte, 100), 0644))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "sub", "b"), make([]byte, 50), 0644))
+
+	w := &currentWorker{}
+	w.currentJob.workingDirectory = dir
+
+	u := w.startStepUsage()
+	assert.NotNil(t, u)
+	// a nested step does not record twice
+	assert.Nil(t, w.startStepUsage())
+
+	cmd := exec.Command("go", "version")
+	assert.NoError(t, cmd.Run())
+	u.addProcess(cmd.ProcessState)
+	u.addProcess(nil)
+
+	usage := w.stopStepUsage(u)
+	assert.NotNil(t, usage)
+	assert.Nil(t, w.currentJob.stepUsage)
+	assert.Equal(t, int64(150), usage.MaxDisk)
+	assert.True(t, usage.CPUTime >= 0)
+	assert.True(t, usage.MaxMemory > 0)
+
+	assert.Nil(t, w.stopStepUsage(nil))
+}
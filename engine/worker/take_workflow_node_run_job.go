@@ -13,12 +13,22 @@ import (
 	"github.com/ovh/cds/sdk"
 	"github.com/ovh/cds/sdk/cdsclient"
 	"github.com/ovh/cds/sdk/log"
+	"github.com/ovh/cds/sdk/tracingutils"
 )
 
 // takeWorkflowJob try to take a job.
 // If Take is not possible (as Job already booked for example)
 // it will return true (-> can work on another job), false, otherwise
 func (w *currentWorker) takeWorkflowJob(ctx context.Context, job sdk.WorkflowNodeJobRun) (bool, error) {
+	// Propagate the trace of the workflow run on the calls to the API
+	if parent, ok := tracingutils.ParseTraceParent(job.Header[tracingutils.TraceParentHeader]); ok {
+		ctx = tracingutils.SpanContextToContext(ctx, parent)
+	}
+	// Correlate the logs of the worker with the logs of the API
+	fields := job.LogFields()
+	fields[log.FieldWorker] = w.status.Name
+	ctx = log.ContextWithFields(ctx, fields)
+
 	ctxQueueTakeJob, cancelQueueTakeJob := context.WithTimeout(ctx, 20*time.Second)
 	defer cancelQueueTakeJob()
 	info, err := w.client.QueueTakeJob(ctxQueueTakeJob, job, w.bookedWJobID == job.ID)
@@ -32,15 +42,15 @@ func (w *currentWorker) takeWorkflowJob(ctx context.Context, job sdk.WorkflowNod
 	if w.bookedWJobID == job.ID {
 		t = ", this was my booked job"
 	}
-	log.Info("takeWorkflowJob> Job %d taken%s", job.ID, t)
+	log.InfoCtx(ctx, "takeWorkflowJob> Job %d taken%s", job.ID, t)
 
 	w.nbActionsDone++
 	// Set build variables
 	w.currentJob.wJob = &info.NodeJobRun
 	w.currentJob.secrets = info.Secrets
 	// Reset build variables
-	w.currentJob.gitsshPath = ""
 	w.currentJob.pkey = ""
+	w.currentJob.keyFiles = nil
 	w.currentJob.buildVariables = nil
 
 	start := time.Now()
@@ -64,11 +74,11 @@ func (w *currentWorker) takeWorkflowJob(ctx context.Context, job sdk.WorkflowNod
 				code, err := w.client.(cdsclient.Raw).GetJSON(ctxGetJSON, fmt.Sprintf("/queue/workflows/%d/infos", jobID), j)
 				if err != nil {
 					if code == http.StatusNotFound {
-						log.Info("takeWorkflowJob> Unable to load workflow job - Not Found (Request) %d: %v", jobID, err)
+						log.InfoCtx(ctx, "takeWorkflowJob> Unable to load workflow job - Not Found (Request) %d: %v", jobID, err)
 						cancel()
 						return
 					}
-					log.Error("takeWorkflowJob> Unable to load workflow job (Request) %d: %v", jobID, err)
+					log.ErrorCtx(ctx, "takeWorkflowJob> Unable to load workflow job (Request) %d: %v", jobID, err)
 
 					// If we got a "connection refused", retry 5 times
 					if strings.Contains(err.Error(), "connection refused") {
@@ -83,7 +93,7 @@ func (w *currentWorker) takeWorkflowJob(ctx context.Context, job sdk.WorkflowNod
 				}
 
 				if j.Status != sdk.StatusBuilding.String() {
-					log.Info("takeWorkflowJob> The job is not more in Building Status. Current Status: %s - Cancelling context - err: %v", j.Status, err)
+					log.InfoCtx(ctx, "takeWorkflowJob> The job is not more in Building Status. Current Status: %s - Cancelling context - err: %v", j.Status, err)
 					cancel()
 					return
 				}
@@ -112,23 +122,23 @@ func (w *currentWorker) takeWorkflowJob(ctx context.Context, job sdk.WorkflowNod
 		if err == nil {
 			return false, nil
 		}
-		log.Error("Unable to send result through grpc: %v", err)
+		log.ErrorCtx(ctx, "Unable to send result through grpc: %v", err)
 	}
 
 	var lasterr error
 	for try := 1; try <= 10; try++ {
-		log.Info("takeWorkflowJob> Sending build result...")
+		log.InfoCtx(ctx, "takeWorkflowJob> Sending build result...")
 		ctxSendResult, cancelSendResult := context.WithTimeout(ctx, 5*time.Second)
 		lasterr = w.client.QueueSendResult(ctxSendResult, job.ID, res)
 		if lasterr == nil {
-			log.Info("takeWorkflowJob> Send build result OK")
+			log.InfoCtx(ctx, "takeWorkflowJob> Send build result OK")
 			cancelSendResult()
 			return false, nil
 		}
 		cancelSendResult()
-		log.Warning("takeWorkflowJob> Cannot send build result: HTTP %v - try: %d - new try in 15s", lasterr, try)
+		log.WarningCtx(ctx, "takeWorkflowJob> Cannot send build result: HTTP %v - try: %d - new try in 15s", lasterr, try)
 		time.Sleep(15 * time.Second)
 	}
-	log.Error("takeWorkflowJob> Could not send built result 10 times, giving up. job: %d", job.ID)
+	log.ErrorCtx(ctx, "takeWorkflowJob> Could not send built result 10 times, giving up. job: %d", job.ID)
 	return false, lasterr
 }
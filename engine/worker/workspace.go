@@ -0,0 +1,124 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ovh/cds/sdk/log"
+)
+
+// diskQuotaInterval is the interval between two checks of the size of the job directory
+var diskQuotaInterval = 10 * time.Second
+
+// workspaceRegistry is the file where a worker process writes the directory of its job,
+// so that the main worker process removes it when the process is killed
+func workspaceRegistry(basedir string, pid int) string {
+	return filepath.Join(basedir, fmt.Sprintf(".cds-worker-%d.workspace", pid))
+}
+
+// registerJobDirectory records the directory of the current job, it is removed by cleanupJobDirectory
+func (w *currentWorker) registerJobDirectory(dir string) {
+	if err := ioutil.WriteFile(workspaceRegistry(w.basedir, os.Getpid()), []byte(dir), 0600); err != nil {
+		log.Warning("registerJobDirectory> unable to register %s: %v", dir, err)
+	}
+}
+
+// cleanupJobDirectory removes the directory of the job with its workspace, its keys and its temporary files
+func (w *currentWorker) cleanupJobDirectory(dir string) {
+	// leave the directory before removing it
+	_ = os.Chdir(w.basedir)
+	if err := os.RemoveAll(dir); err != nil {
+		log.Error("cleanupJobDirectory> cannot remove job directory %s: %v", dir, err)
+		return
+	}
+	if err := os.Remove(workspaceRegistry(w.basedir, os.Getpid())); err != nil && !os.IsNotExist(err) {
+		log.Warning("cleanupJobDirectory> %v", err)
+	}
+}
+
+// cleanupWorkspace removes the job directory left by a worker process which exited without cleaning it
+func cleanupWorkspace(basedir string, pid int) {
+	registry := workspaceRegistry(basedir, pid)
+	btes, err := ioutil.ReadFile(registry)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Warning("cleanupWorkspace> %v", err)
+		}
+		return
+	}
+	// never remove a directory outside of the basedir
+	dir := filepath.Clean(string(btes))
+	if strings.HasPrefix(dir, filepath.Clean(basedir)+string(filepath.Separator)) {
+		log.Info("cleanupWorkspace> removing job directory %s left by worker process %d", dir, pid)
+		if err := os.RemoveAll(dir); err != nil {
+			log.Error("cleanupWorkspace> cannot remove %s: %v", dir, err)
+			return
+		}
+	}
+	_ = os.Remove(registry)
+}
+
+// tmpDirectory returns the directory of the temporary files of the current job
+func (w *currentWorker) tmpDirectory() string {
+	if w.currentJob.tmpDirectory != "" {
+		return w.currentJob.tmpDirectory
+	}
+	return w.basedir
+}
+
+// watchDiskQuota cancels the returned context when the size of the job directory exceeds the disk quota of the worker.
+// The returned func stops the watch and returns the reason of the failure if the quota was exceeded.
+func (w *currentWorker) watchDiskQuota(ctx context.Context, buildID int64, dir string) (context.Context, func() string) {
+	if w.diskQuota <= 0 {
+		return ctx, func() string { return "" }
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	var mutex sync.Mutex
+	var reason string
+	stop := make(chan struct{})
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(diskQuotaInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			size, err := dirSize(dir)
+			if err != nil {
+				log.Warning("watchDiskQuota> unable to compute the size of %s: %v", dir, err)
+				continue
+			}
+			if size > w.diskQuota {
+				mutex.Lock()
+				reason = fmt.Sprintf("Disk quota exceeded: the job uses %d MB, the limit is %d MB", size>>20, w.diskQuota>>20)
+				mutex.Unlock()
+				_ = w.sendLog(buildID, reason+"\n", w.currentJob.currentStep, false)
+				cancel()
+				return
+			}
+		}
+	}()
+
+	return ctx, func() string {
+		close(stop)
+		<-done
+		cancel()
+		mutex.Lock()
+		defer mutex.Unlock()
+		return reason
+	}
+}
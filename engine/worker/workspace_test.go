@@ -0,0 +1,66 @@
+package main
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func TestCleanupWorkspace(t *testing.T) {
+	basedir, err := ioutil.TempDir("", "workspace")
+	assert.NoError(t, err)
+	defer os.RemoveAll(basedir)
+
+	jobDir := filepath.Join(basedir, "job")
+	assert.NoError(t, os.MkdirAll(filepath.Join(jobDir, "keys"), 0755))
+	assert.NoError(t, ioutil.WriteFile(workspaceRegistry(basedir, 42), []byte(jobDir), 0600))
+
+	cleanupWorkspace(basedir, 42)
+	_, err = os.Stat(jobDir)
+	assert.True(t, os.IsNotExist(err))
+	_, err = os.Stat(workspaceRegistry(basedir, 42))
+	assert.True(t, os.IsNotExist(err))
+
+	// a directory outside of the basedir is never removed
+	outside, err := ioutil.TempDir("", "outside")
+	assert.NoError(t, err)
+	defer os.RemoveAll(outside)
+	assert.NoError(t, ioutil.WriteFile(workspaceRegistry(basedir, 43), []byte(outside), 0600))
+	cleanupWorkspace(basedir, 43)
+	_, err = os.Stat(outside)
+	assert.NoError(t, err)
+}
+
+func TestWatchDiskQuota(t *testing.T) {
+	dir, err := ioutil.TempDir("", "workspace")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	defer func(d time.Duration) { diskQuotaInterval = d }(diskQuotaInterval)
+	diskQuotaInterval = 10 * time.Millisecond
+
+	w := &currentWorker{diskQuota: 1 << 20}
+	w.currentJob.wJob = &sdk.WorkflowNodeJobRun{}
+	w.logger.logChan = make(chan sdk.Log, 10)
+
+	ctx, stop := w.watchDiskQuota(context.Background(), 1, dir)
+	time.Sleep(50 * time.Millisecond)
+	assert.NoError(t, ctx.Err())
+	assert.Equal(t, "", stop())
+
+	ctx, stop = w.watchDiskQuota(context.Background(), 1, dir)
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "big"), make([]byte, 2<<20), 0644))
+	select {
+	case <-ctx.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("the context was not canceled")
+	}
+	assert.Equal(t, "Disk quota exceeded: the job uses 2 MB, the limit is 1 MB", stop())
+}
@@ -6,19 +6,21 @@ import (
 
 // Action is the base element of CDS pipeline
 type Action struct {
-	ID             int64         `json:"id" yaml:"-"`
-	Name           string        `json:"name" cli:"name,key"`
-	StepName       string        `json:"step_name,omitempty" yaml:"step_name,omitempty" cli:"step_name"`
-	Type           string        `json:"type" yaml:"-" cli:"type"`
-	Description    string        `json:"description" yaml:"desc,omitempty"`
-	Requirements   []Requirement `json:"requirements"`
-	Parameters     []Parameter   `json:"parameters"`
-	Actions        []Action      `json:"actions" yaml:"actions,omitempty"`
-	Enabled        bool          `json:"enabled" yaml:"-"`
-	Deprecated     bool          `json:"deprecated" yaml:"-"`
-	Optional       bool          `json:"optional" yaml:"-"`
-	AlwaysExecuted bool          `json:"always_executed" yaml:"-"`
-	LastModified   int64         `json:"last_modified" cli:"modified"`
+	ID                int64         `json:"id" yaml:"-"`
+	Name              string        `json:"name" cli:"name,key"`
+	Version           string        `json:"version,omitempty" yaml:"-" cli:"version"`
+	StepName          string        `json:"step_name,omitempty" yaml:"step_name,omitempty" cli:"step_name"`
+	Type              string        `json:"type" yaml:"-" cli:"type"`
+	Description       string        `json:"description" yaml:"desc,omitempty"`
+	Requirements      []Requirement `json:"requirements"`
+	Parameters        []Parameter   `json:"parameters"`
+	Actions           []Action      `json:"actions" yaml:"actions,omitempty"`
+	Enabled           bool          `json:"enabled" yaml:"-"`
+	Deprecated        bool          `json:"deprecated" yaml:"-"`
+	Optional          bool          `json:"optional" yaml:"-"`
+	AlwaysExecuted    bool          `json:"always_executed" yaml:"-"`
+	VersionConstraint string        `json:"version_constraint,omitempty" yaml:"-"`
+	LastModified      int64         `json:"last_modified" cli:"modified"`
 }
 
 // ActionSummary is the light representation of an action for CDS event
@@ -57,11 +59,19 @@ const (
 	ScriptAction              = "Script"
 	JUnitAction               = "JUnit"
 	CoverageAction            = "Coverage"
+	CoverageUploadAction      = "CoverageUpload"
 	GitCloneAction            = "GitClone"
 	GitTagAction              = "GitTag"
 	ReleaseAction             = "Release"
 	CheckoutApplicationAction = "CheckoutApplication"
 	DeployApplicationAction   = "DeployApplication"
+	PromoteAction             = "Promote"
+	DeployHelmAction          = "DeployHelm"
+	TerraformPlanAction       = "TerraformPlan"
+	TerraformApplyAction      = "TerraformApply"
+	CheckCanaryMetricsAction  = "CheckCanaryMetrics"
+	SSHDeployAction           = "SSHDeploy"
+	GenerateSBOMAction        = "GenerateSBOM"
 
 	DefaultGitCloneParameterTagValue = "{{.git.tag}}"
 )
@@ -87,6 +87,76 @@ func NewStepRelease(v map[string]string) Action {
 	return newAction
 }
 
+// NewStepPromote returns an action (basically used as a step of a job) of Promote type
+func NewStepPromote(v map[string]string) Action {
+	newAction := Action{
+		Name:       PromoteAction,
+		Type:       BuiltinAction,
+		Parameters: ParametersFromMap(v),
+	}
+	return newAction
+}
+
+// NewStepDeployHelm returns an action (basically used as a step of a job) of DeployHelm type
+func NewStepDeployHelm(v map[string]string) Action {
+	newAction := Action{
+		Name:       DeployHelmAction,
+		Type:       BuiltinAction,
+		Parameters: ParametersFromMap(v),
+	}
+	return newAction
+}
+
+// NewStepTerraformPlan returns an action (basically used as a step of a job) of TerraformPlan type
+func NewStepTerraformPlan(v map[string]string) Action {
+	newAction := Action{
+		Name:       TerraformPlanAction,
+		Type:       BuiltinAction,
+		Parameters: ParametersFromMap(v),
+	}
+	return newAction
+}
+
+// NewStepTerraformApply returns an action (basically used as a step of a job) of TerraformApply type
+func NewStepTerraformApply(v map[string]string) Action {
+	newAction := Action{
+		Name:       TerraformApplyAction,
+		Type:       BuiltinAction,
+		Parameters: ParametersFromMap(v),
+	}
+	return newAction
+}
+
+// NewStepSSHDeploy returns an action (basically used as a step of a job) of SSHDeploy type
+func NewStepSSHDeploy(v map[string]string) Action {
+	newAction := Action{
+		Name:       SSHDeployAction,
+		Type:       BuiltinAction,
+		Parameters: ParametersFromMap(v),
+	}
+	return newAction
+}
+
+// NewStepGenerateSBOM returns an action (basically used as a step of a job) of GenerateSBOM type
+func NewStepGenerateSBOM(v map[string]string) Action {
+	newAction := Action{
+		Name:       GenerateSBOMAction,
+		Type:       BuiltinAction,
+		Parameters: ParametersFromMap(v),
+	}
+	return newAction
+}
+
+// NewStepCheckCanaryMetrics returns an action (basically used as a step of a job) of CheckCanaryMetrics type
+func NewStepCheckCanaryMetrics(v map[string]string) Action {
+	newAction := Action{
+		Name:       CheckCanaryMetricsAction,
+		Type:       BuiltinAction,
+		Parameters: ParametersFromMap(v),
+	}
+	return newAction
+}
+
 // NewStepArtifactUpload returns an action (basically used as a step of a job) of artifact upload type
 func NewStepArtifactUpload(i interface{}) Action {
 	newAction := Action{
@@ -119,6 +189,16 @@ func NewStepServeStaticFiles(v map[string]string) Action {
 	return newAction
 }
 
+// NewStepArtifactPush returns an action (basically used as a step of a job) of artifact push type
+func NewStepArtifactPush(v map[string]string) Action {
+	newAction := Action{
+		Name:       ArtifactPush,
+		Type:       BuiltinAction,
+		Parameters: ParametersFromMap(v),
+	}
+	return newAction
+}
+
 // NewCoverage returns an action of coverage type
 func NewCoverage(v map[string]string) Action {
 	newAction := Action{
@@ -129,6 +209,16 @@ func NewCoverage(v map[string]string) Action {
 	return newAction
 }
 
+// NewStepCoverageUpload returns an action (basically used as a step of a job) of CoverageUpload type
+func NewStepCoverageUpload(v map[string]string) Action {
+	newAction := Action{
+		Name:       CoverageUploadAction,
+		Type:       BuiltinAction,
+		Parameters: ParametersFromMap(v),
+	}
+	return newAction
+}
+
 // NewStepArtifactDownload returns an action (basically used as a step of a job) of artifact download type
 func NewStepArtifactDownload(v map[string]string) Action {
 	newAction := Action{
@@ -155,6 +245,16 @@ func NewCheckoutApplication(s string) Action {
 	return newAction
 }
 
+// NewStepCheckoutApplication returns an action (basically used as a step of a job) of checkout application type with its options
+func NewStepCheckoutApplication(v map[string]string) Action {
+	newAction := Action{
+		Name:       CheckoutApplicationAction,
+		Type:       BuiltinAction,
+		Parameters: ParametersFromMap(v),
+	}
+	return newAction
+}
+
 // NewDeployApplication returns an action (basically used as a step of a job) of deploy application type
 func NewDeployApplication(s string) Action {
 	newAction := Action{
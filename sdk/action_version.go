@@ -0,0 +1,100 @@
+package sdk
+
+import (
+	"strings"
+
+	"github.com/blang/semver"
+)
+
+// ActionVersionSeparator separates the name of an action from the version constraint in a step (ie. "myAction@v1")
+const ActionVersionSeparator = "@"
+
+// ParseActionReference splits a reference to an action (ie. "myAction@v1.2") in its name and its version constraint
+func ParseActionReference(ref string) (name, constraint string) {
+	i := strings.LastIndex(ref, ActionVersionSeparator)
+	if i < 0 {
+		return ref, ""
+	}
+	return ref[:i], ref[i+1:]
+}
+
+// ActionReference returns the reference to an action with its version constraint
+func ActionReference(name, constraint string) string {
+	if constraint == "" {
+		return name
+	}
+	return name + ActionVersionSeparator + constraint
+}
+
+// IsValidActionVersion checks that the version of an action is a semver version, a leading "v" and a missing
+// minor or patch number are allowed (ie. "v1", "v1.2.3").
+func IsValidActionVersion(version string) error {
+	if version == "" {
+		return nil
+	}
+	if _, err := semver.ParseTolerant(version); err != nil {
+		return NewErrorFrom(ErrWrongRequest, "invalid action version %s: %v", version, err)
+	}
+	return nil
+}
+
+// ActionVersionRange returns the semver range of a version constraint. A partial version selects all the versions
+// with the same major or minor number ("v1" is "1.x", "v1.2" is "1.2.x"), a full version only selects itself and
+// any other expression is a semver range (ie. ">=1.2.0 <2.0.0").
+func ActionVersionRange(constraint string) (semver.Range, error) {
+	expr := strings.TrimPrefix(strings.TrimSpace(constraint), "v")
+	if !strings.ContainsAny(expr, "<>=! |x*X") {
+		switch strings.Count(expr, ".") {
+		case 0, 1:
+			expr += ".x"
+		}
+	}
+	r, err := semver.ParseRange(expr)
+	if err != nil {
+		return nil, NewErrorFrom(ErrWrongRequest, "invalid action version constraint %s: %v", constraint, err)
+	}
+	return r, nil
+}
+
+// LatestActionVersion returns the highest version of an action matching the constraint. Without constraint,
+// the highest version is returned; an action without version is lower than any versioned one.
+func LatestActionVersion(actions []Action, constraint string) (*Action, error) {
+	var r semver.Range
+	if constraint != "" {
+		var err error
+		r, err = ActionVersionRange(constraint)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var latest *Action
+	var latestVersion semver.Version
+	for i := range actions {
+		a := &actions[i]
+		var v semver.Version
+		if a.Version != "" {
+			var err error
+			v, err = semver.ParseTolerant(a.Version)
+			if err != nil {
+				continue
+			}
+		} else if r != nil {
+			continue
+		}
+		if r != nil && !r(v) {
+			continue
+		}
+		if latest == nil || v.GT(latestVersion) {
+			latest = a
+			latestVersion = v
+		}
+	}
+	if latest == nil {
+		if constraint == "" {
+			return nil, ErrNoAction
+		}
+		return nil, NewErrorFrom(ErrNoAction, "no version of the action matches %s", constraint)
+	}
+	return latest, nil
+}
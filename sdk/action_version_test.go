@@ -0,0 +1,64 @@
+package sdk
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseActionReference(t *testing.T) {
+	name, constraint := ParseActionReference("myAction@v1.2")
+	assert.Equal(t, "myAction", name)
+	assert.Equal(t, "v1.2", constraint)
+
+	name, constraint = ParseActionReference("myAction")
+	assert.Equal(t, "myAction", name)
+	assert.Equal(t, "", constraint)
+
+	assert.Equal(t, "myAction@>=1.0.0 <2.0.0", ActionReference("myAction", ">=1.0.0 <2.0.0"))
+	assert.Equal(t, "myAction", ActionReference("myAction", ""))
+}
+
+func TestLatestActionVersion(t *testing.T) {
+	actions := []Action{
+		{ID: 1, Name: "myAction"},
+		{ID: 2, Name: "myAction", Version: "v1"},
+		{ID: 3, Name: "myAction", Version: "v1.2.3"},
+		{ID: 4, Name: "myAction", Version: "v1.10.0"},
+		{ID: 5, Name: "myAction", Version: "v2.0.0"},
+	}
+
+	tests := []struct {
+		constraint string
+		id         int64
+	}{
+		{"", 5},
+		{"v1", 4},
+		{"v1.2", 3},
+		{"v1.2.3", 3},
+		{"1.0.0", 2},
+		{">=1.0.0 <1.5.0", 3},
+		{"v2", 5},
+	}
+	for _, tt := range tests {
+		a, err := LatestActionVersion(actions, tt.constraint)
+		assert.NoError(t, err, tt.constraint)
+		if assert.NotNil(t, a, tt.constraint) {
+			assert.Equal(t, tt.id, a.ID, tt.constraint)
+		}
+	}
+
+	_, err := LatestActionVersion(actions, "v3")
+	assert.True(t, ErrorIs(err, ErrNoAction))
+
+	_, err = LatestActionVersion(actions, "not a version")
+	assert.True(t, ErrorIs(err, ErrWrongRequest))
+
+	// Without versions, the action is returned only without constraint
+	a, err := LatestActionVersion(actions[:1], "")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), a.ID)
+
+	assert.NoError(t, IsValidActionVersion("v1.2"))
+	assert.Error(t, IsValidActionVersion("latest"))
+}
@@ -5,6 +5,7 @@ const (
 	ArtifactUpload   = "Artifact Upload"
 	ArtifactDownload = "Artifact Download"
 	ServeStaticFiles = "Serve Static Files"
+	ArtifactPush     = "Artifact Push"
 )
 
 // ArtifactsStore represents
@@ -11,6 +11,7 @@ const (
 	AuditAdd    = "add"
 	AuditUpdate = "update"
 	AuditDelete = "delete"
+	AuditDeny   = "deny"
 )
 
 // AuditCommon contains basic stuff for audits.
@@ -48,3 +49,29 @@ type AuditWorkflowTemplateInstance struct {
 	AuditCommon
 	WorkflowTemplateInstanceID int64 `json:"workflow_template_instance_id" db:"workflow_template_instance_id"`
 }
+
+// AuditLog is an entry of the audit trail, recording who changed an entity of CDS.
+type AuditLog struct {
+	ID         int64     `json:"id" db:"id"`
+	Created    time.Time `json:"created" db:"created"`
+	Actor      string    `json:"actor" db:"actor"`
+	ActorEmail string    `json:"actor_email,omitempty" db:"actor_email"`
+	EventType  string    `json:"event_type" db:"event_type"`
+	EntityType string    `json:"entity_type" db:"entity_type"`
+	EntityName string    `json:"entity_name" db:"entity_name"`
+	Action     string    `json:"action" db:"action"`
+	ProjectKey string    `json:"project_key,omitempty" db:"project_key"`
+	Data       string    `json:"data,omitempty" db:"data"`
+}
+
+// AuditLogFilter filters the audit logs.
+type AuditLogFilter struct {
+	Actor      string    `json:"actor,omitempty"`
+	EntityType string    `json:"entity_type,omitempty"`
+	EntityName string    `json:"entity_name,omitempty"`
+	ProjectKey string    `json:"project_key,omitempty"`
+	Since      time.Time `json:"since,omitempty"`
+	Until      time.Time `json:"until,omitempty"`
+	Offset     int       `json:"offset,omitempty"`
+	Limit      int       `json:"limit,omitempty"`
+}
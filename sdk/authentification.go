@@ -8,6 +8,7 @@ type Auth struct {
 	HashedTokenVerify string `json:"hashedTokenVerify"`
 	EmailVerified     bool   `json:"emailVerified"`
 	DateReset         int64  `json:"dateReset"`
+	MFA               MFA    `json:"mfa"`
 }
 
 // UserToken for user persistent session
@@ -64,10 +64,18 @@ func (j ExecutedJob) ToSummary() ExecutedJobSummary {
 
 // StepStatus Represent a step and his status
 type StepStatus struct {
-	StepOrder int       `json:"step_order" db:"-"`
-	Status    string    `json:"status" db:"-"`
-	Start     time.Time `json:"start" db:"-"`
-	Done      time.Time `json:"done" db:"-"`
+	StepOrder int                `json:"step_order" db:"-"`
+	Status    string             `json:"status" db:"-"`
+	Start     time.Time          `json:"start" db:"-"`
+	Done      time.Time          `json:"done" db:"-"`
+	Usage     *StepResourceUsage `json:"usage,omitempty" db:"-"`
+}
+
+// StepResourceUsage is the resources consumed by the processes of a step, sampled by the worker
+type StepResourceUsage struct {
+	CPUTime   float64 `json:"cpu_time"`   // user and system time, in seconds
+	MaxMemory int64   `json:"max_memory"` // highest resident memory of a process, in bytes
+	MaxDisk   int64   `json:"max_disk"`   // highest size of the working directory, in bytes
 }
 
 // StepStatusSummary Represent a step and his status for CDS event
@@ -0,0 +1,68 @@
+package sdk
+
+import "time"
+
+// Types of the items stored by the CDN service
+const (
+	CDNItemTypeLog      = "log"
+	CDNItemTypeArtifact = "artifact"
+	CDNItemTypeTmpFile  = "tmpfile"
+)
+
+// Status of an item in a storage unit of the CDN service
+const (
+	CDNItemUnitStatusSynced    = "synced"
+	CDNItemUnitStatusMissing   = "missing"
+	CDNItemUnitStatusCorrupted = "corrupted"
+)
+
+// CDNItem is a log or an artifact stored by the CDN service. Items are addressed by the sha256 of their content
+// and replicated in all the storage units of the service.
+type CDNItem struct {
+	Hash         string        `json:"hash" cli:"hash,key"`
+	Type         string        `json:"type" cli:"type"`
+	Size         int64         `json:"size" cli:"size"`
+	Created      time.Time     `json:"created" cli:"created"`
+	LastModified time.Time     `json:"last_modified" cli:"last_modified"`
+	Units        []CDNItemUnit `json:"units" cli:"-"`
+}
+
+// Unit returns the state of the item in a storage unit
+func (i *CDNItem) Unit(name string) *CDNItemUnit {
+	for j := range i.Units {
+		if i.Units[j].Unit == name {
+			return &i.Units[j]
+		}
+	}
+	return nil
+}
+
+// Synced returns true if the item is synced in all its storage units
+func (i CDNItem) Synced() bool {
+	for _, u := range i.Units {
+		if u.Status != CDNItemUnitStatusSynced {
+			return false
+		}
+	}
+	return true
+}
+
+// CDNItemUnit is the state of an item in a storage unit of the CDN service
+type CDNItemUnit struct {
+	Unit      string    `json:"unit" cli:"unit,key"`
+	Status    string    `json:"status" cli:"status"`
+	LastCheck time.Time `json:"last_check" cli:"last_check"`
+	LastError string    `json:"last_error,omitempty" cli:"last_error"`
+}
+
+// CDNUnit is a storage unit of the CDN service
+type CDNUnit struct {
+	Name        string `json:"name" cli:"name,key"`
+	Buffer      bool   `json:"buffer" cli:"buffer"`
+	Status      string `json:"status" cli:"status"`
+	Value       string `json:"value" cli:"value"`
+	NbItems     int    `json:"nb_items" cli:"nb_items"`
+	NbSynced    int    `json:"nb_synced" cli:"nb_synced"`
+	NbMissing   int    `json:"nb_missing" cli:"nb_missing"`
+	NbCorrupted int    `json:"nb_corrupted" cli:"nb_corrupted"`
+}
@@ -22,6 +22,14 @@ func (c *client) AdminDatabaseMigrationsList() ([]sdk.DatabaseMigrationStatus, e
 	return dlist, nil
 }
 
+func (c *client) AdminDatabaseMigrationStatus() (*sdk.DatabaseSchemaStatus, error) {
+	var s sdk.DatabaseSchemaStatus
+	if _, err := c.GetJSON(context.Background(), "/admin/database/migration/status", &s); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
 func (c *client) AdminDatabaseMigrationUnlock(id string) error {
 	_, _, _, err := c.Request(context.Background(), "POST", "/admin/database/migration/unlock/"+url.QueryEscape(id), nil)
 	return err
@@ -100,3 +108,15 @@ func (c *client) ServiceCallDELETE(stype string, query string) error {
 	_, _, _, err := c.Request(context.Background(), "DELETE", "/admin/services/call?type="+stype+"&query="+url.QueryEscape(query), nil)
 	return err
 }
+
+func (c *client) AdminSecretsRotate(projectKey string) (map[string]int, error) {
+	path := "/admin/secrets/rotate"
+	if projectKey != "" {
+		path += "?project=" + url.QueryEscape(projectKey)
+	}
+	res := map[string]int{}
+	if _, err := c.PostJSON(context.Background(), path, nil, &res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
@@ -0,0 +1,58 @@
+package cdsclient
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func auditLogFilterQuery(f sdk.AuditLogFilter) string {
+	v := url.Values{}
+	if f.Actor != "" {
+		v.Set("actor", f.Actor)
+	}
+	if f.EntityType != "" {
+		v.Set("entity", f.EntityType)
+	}
+	if f.EntityName != "" {
+		v.Set("name", f.EntityName)
+	}
+	if f.ProjectKey != "" {
+		v.Set("project", f.ProjectKey)
+	}
+	if !f.Since.IsZero() {
+		v.Set("since", f.Since.Format(time.RFC3339))
+	}
+	if !f.Until.IsZero() {
+		v.Set("until", f.Until.Format(time.RFC3339))
+	}
+	if f.Offset > 0 {
+		v.Set("offset", strconv.Itoa(f.Offset))
+	}
+	if f.Limit > 0 {
+		v.Set("limit", strconv.Itoa(f.Limit))
+	}
+	if len(v) == 0 {
+		return ""
+	}
+	return "?" + v.Encode()
+}
+
+func (c *client) AdminAudits(filter sdk.AuditLogFilter) ([]sdk.AuditLog, error) {
+	as := []sdk.AuditLog{}
+	if _, err := c.GetJSON(context.Background(), "/admin/audits"+auditLogFilterQuery(filter), &as); err != nil {
+		return nil, err
+	}
+	return as, nil
+}
+
+func (c *client) ProjectAudits(projectKey string, filter sdk.AuditLogFilter) ([]sdk.AuditLog, error) {
+	as := []sdk.AuditLog{}
+	if _, err := c.GetJSON(context.Background(), "/project/"+projectKey+"/audits"+auditLogFilterQuery(filter), &as); err != nil {
+		return nil, err
+	}
+	return as, nil
+}
@@ -0,0 +1,55 @@
+package cdsclient
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func costQuery(by, from, to string, csv bool) string {
+	q := url.Values{}
+	if by != "" {
+		q.Set("by", by)
+	}
+	if from != "" {
+		q.Set("from", from)
+	}
+	if to != "" {
+		q.Set("to", to)
+	}
+	if csv {
+		q.Set("format", "csv")
+	}
+	if len(q) == 0 {
+		return ""
+	}
+	return "?" + q.Encode()
+}
+
+func (c *client) ProjectCost(projectKey, by, from, to string) (*sdk.CostReport, error) {
+	var r sdk.CostReport
+	if _, err := c.GetJSON(context.Background(), "/project/"+projectKey+"/cost"+costQuery(by, from, to, false), &r); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+func (c *client) ProjectCostCSV(projectKey, by, from, to string) ([]byte, error) {
+	btes, _, _, err := c.Request(context.Background(), http.MethodGet, "/project/"+projectKey+"/cost"+costQuery(by, from, to, true), nil)
+	return btes, err
+}
+
+func (c *client) AdminCost(by, from, to string) (*sdk.CostReport, error) {
+	var r sdk.CostReport
+	if _, err := c.GetJSON(context.Background(), "/admin/cost"+costQuery(by, from, to, false), &r); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+func (c *client) AdminCostCSV(by, from, to string) ([]byte, error) {
+	btes, _, _, err := c.Request(context.Background(), http.MethodGet, "/admin/cost"+costQuery(by, from, to, true), nil)
+	return btes, err
+}
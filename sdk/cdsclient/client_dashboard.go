@@ -0,0 +1,47 @@
+package cdsclient
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func (c *client) DashboardList() ([]sdk.Dashboard, error) {
+	ds := []sdk.Dashboard{}
+	if _, err := c.GetJSON(context.Background(), "/dashboard", &ds); err != nil {
+		return nil, err
+	}
+	return ds, nil
+}
+
+func (c *client) DashboardGet(id int64) (*sdk.Dashboard, error) {
+	var d sdk.Dashboard
+	if _, err := c.GetJSON(context.Background(), fmt.Sprintf("/dashboard/%d", id), &d); err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+func (c *client) DashboardCreate(d *sdk.Dashboard) error {
+	_, err := c.PostJSON(context.Background(), "/dashboard", d, d)
+	return err
+}
+
+func (c *client) DashboardUpdate(d *sdk.Dashboard) error {
+	_, err := c.PutJSON(context.Background(), fmt.Sprintf("/dashboard/%d", d.ID), d, d)
+	return err
+}
+
+func (c *client) DashboardDelete(id int64) error {
+	_, err := c.DeleteJSON(context.Background(), fmt.Sprintf("/dashboard/%d", id), nil)
+	return err
+}
+
+func (c *client) DashboardData(id int64) ([]sdk.DashboardWidgetData, error) {
+	data := []sdk.DashboardWidgetData{}
+	if _, err := c.GetJSON(context.Background(), fmt.Sprintf("/dashboard/%d/data", id), &data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
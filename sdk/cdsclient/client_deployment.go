@@ -0,0 +1,69 @@
+package cdsclient
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func (c *client) ProjectDORAMetrics(projectKey, application, environment, from, to string) ([]sdk.DORAMetrics, error) {
+	q := url.Values{}
+	for k, v := range map[string]string{"application": application, "environment": environment, "from": from, "to": to} {
+		if v != "" {
+			q.Set(k, v)
+		}
+	}
+	path := "/project/" + projectKey + "/dora"
+	if len(q) > 0 {
+		path += "?" + q.Encode()
+	}
+	var metrics []sdk.DORAMetrics
+	if _, err := c.GetJSON(context.Background(), path, &metrics); err != nil {
+		return nil, err
+	}
+	return metrics, nil
+}
+
+func (c *client) ProjectDeployments(projectKey, application, environment string, limit int) ([]sdk.Deployment, error) {
+	q := url.Values{}
+	if application != "" {
+		q.Set("application", application)
+	}
+	if environment != "" {
+		q.Set("environment", environment)
+	}
+	if limit > 0 {
+		q.Set("limit", strconv.Itoa(limit))
+	}
+	path := "/project/" + projectKey + "/deployments"
+	if len(q) > 0 {
+		path += "?" + q.Encode()
+	}
+	var ds []sdk.Deployment
+	if _, err := c.GetJSON(context.Background(), path, &ds); err != nil {
+		return nil, err
+	}
+	return ds, nil
+}
+
+func (c *client) ProjectCurrentDeployments(projectKey, environment string) ([]sdk.Deployment, error) {
+	path := "/project/" + projectKey + "/deployments/current"
+	if environment != "" {
+		path += "?environment=" + url.QueryEscape(environment)
+	}
+	var ds []sdk.Deployment
+	if _, err := c.GetJSON(context.Background(), path, &ds); err != nil {
+		return nil, err
+	}
+	return ds, nil
+}
+
+func (c *client) ProjectDeploymentRollback(projectKey string, req sdk.DeploymentRollbackRequest) (*sdk.DeploymentRollback, error) {
+	var rb sdk.DeploymentRollback
+	if _, err := c.PostJSON(context.Background(), "/project/"+projectKey+"/deployments/rollback", req, &rb); err != nil {
+		return nil, err
+	}
+	return &rb, nil
+}
@@ -23,6 +23,17 @@ func (c *client) DownloadURLFromAPI(name, os, arch string) string {
 	return fmt.Sprintf("%s/download/%s/%s/%s", c.APIURL(), name, os, arch)
 }
 
+func (c *client) DownloadChecksumFromAPI(name, os, arch string) (string, error) {
+	var res sdk.DownloadChecksum
+	if _, err := c.GetJSON(context.Background(), fmt.Sprintf("/download/%s/%s/%s/checksum", name, os, arch), &res); err != nil {
+		return "", err
+	}
+	if res.SHA512 == "" {
+		return "", fmt.Errorf("no checksum given by the CDS API for %s %s %s", name, os, arch)
+	}
+	return res.SHA512, nil
+}
+
 func (c *client) DownloadURLFromGithub(filename string) (string, error) {
 	var httpClient = &http.Client{Timeout: 10 * time.Second}
 
@@ -0,0 +1,32 @@
+package cdsclient
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func (c *client) EnvironmentFreezeList(projectKey string, envName string) ([]sdk.EnvironmentFreeze, error) {
+	var fs []sdk.EnvironmentFreeze
+	if _, err := c.GetJSON(context.Background(), "/project/"+projectKey+"/environment/"+url.QueryEscape(envName)+"/freeze", &fs); err != nil {
+		return nil, err
+	}
+	return fs, nil
+}
+
+func (c *client) EnvironmentFreezeCreate(projectKey string, envName string, f *sdk.EnvironmentFreeze) error {
+	_, err := c.PostJSON(context.Background(), "/project/"+projectKey+"/environment/"+url.QueryEscape(envName)+"/freeze", f, f)
+	return err
+}
+
+func (c *client) EnvironmentFreezeDelete(projectKey string, envName string, id int64) error {
+	_, _, _, err := c.Request(context.Background(), "DELETE", fmt.Sprintf("/project/%s/environment/%s/freeze/%d", projectKey, url.QueryEscape(envName), id), nil)
+	return err
+}
+
+func (c *client) WorkflowNodeRunFreezeOverride(projectKey string, workflowName string, runNumber int64, nodeRunID int64) error {
+	_, err := c.PostJSON(context.Background(), fmt.Sprintf("/project/%s/workflows/%s/runs/%d/nodes/%d/freeze/override", projectKey, workflowName, runNumber, nodeRunID), nil, nil)
+	return err
+}
@@ -0,0 +1,27 @@
+package cdsclient
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func (c *client) AdminEventOutbox(status string) ([]sdk.EventOutboxEntry, error) {
+	entries := []sdk.EventOutboxEntry{}
+	if _, err := c.GetJSON(context.Background(), "/admin/events/outbox?status="+url.QueryEscape(status), &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func (c *client) AdminEventOutboxRetry(id int64) error {
+	_, _, _, err := c.Request(context.Background(), "POST", fmt.Sprintf("/admin/events/outbox/%d/retry", id), nil)
+	return err
+}
+
+func (c *client) AdminEventOutboxDelete(id int64) error {
+	_, _, _, err := c.Request(context.Background(), "DELETE", fmt.Sprintf("/admin/events/outbox/%d", id), nil)
+	return err
+}
@@ -0,0 +1,57 @@
+package cdsclient
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// EventsListen listens to the events stream of the API and sends the received events in the channel
+// until the context is done. The stream is opened again if it is closed by the API, resuming from
+// the last received event.
+func (c *client) EventsListen(ctx context.Context, events chan<- sdk.Event) {
+	chanSSEvt := make(chan SSEvent)
+	var lastEventID atomic.Value
+	lastEventID.Store("")
+	sdk.GoRoutine(ctx, "EventsListen", func(ctx context.Context) {
+		for ctx.Err() == nil {
+			resume := func(req *http.Request) {
+				if id := lastEventID.Load().(string); id != "" {
+					req.Header.Set("Last-Event-ID", id)
+				}
+			}
+			if err := c.RequestSSEGet(ctx, "/events", chanSSEvt, resume); err != nil && c.config.Verbose {
+				log.Println("EventsListen", err)
+			}
+			time.Sleep(1 * time.Second)
+		}
+	})
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case evt := <-chanSSEvt:
+			if evt.ID != "" {
+				lastEventID.Store(evt.ID)
+			}
+			content, _ := ioutil.ReadAll(evt.Data)
+			var e sdk.Event
+			// ACK messages and keep alives are not events
+			if err := json.Unmarshal(content, &e); err != nil || e.EventType == "" {
+				continue
+			}
+			select {
+			case events <- e:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package cdsclient
 
 import (
+	"context"
 	"fmt"
 	"strconv"
 	"time"
@@ -25,3 +26,12 @@ func (c *client) PollVCSEvents(uuid string, workflowID int64, vcsServer string,
 
 	return events, interval, nil
 }
+
+func (c *client) HookRepair(uuid string) (sdk.RepositoryWebHookReport, error) {
+	var report sdk.RepositoryWebHookReport
+	url := fmt.Sprintf("/hook/%s/repair", uuid)
+	if _, err := c.PostJSON(context.Background(), url, nil, &report); err != nil {
+		return report, err
+	}
+	return report, nil
+}
@@ -0,0 +1,15 @@
+package cdsclient
+
+import (
+	"context"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func (c *client) AdminLDAPGroupSyncStatus() (sdk.LDAPGroupSyncStatus, error) {
+	var s sdk.LDAPGroupSyncStatus
+	if _, err := c.GetJSON(context.Background(), "/admin/ldap/groups/sync", &s); err != nil {
+		return s, err
+	}
+	return s, nil
+}
@@ -3,9 +3,18 @@ package cdsclient
 import (
 	"context"
 	"fmt"
+
+	"github.com/ovh/cds/sdk"
 )
 
 func (c *client) Maintenance(enable bool) error {
 	_, err := c.PostJSON(context.Background(), fmt.Sprintf("/admin/maintenance?enable=%v", enable), nil, nil)
 	return err
 }
+
+// MaintenanceStatus returns the maintenance mode and the progress of the queue drain
+func (c *client) MaintenanceStatus() (sdk.MaintenanceStatus, error) {
+	var s sdk.MaintenanceStatus
+	_, err := c.GetJSON(context.Background(), "/admin/maintenance", &s)
+	return s, err
+}
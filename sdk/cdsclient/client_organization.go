@@ -0,0 +1,68 @@
+package cdsclient
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func (c *client) OrganizationList() ([]sdk.Organization, error) {
+	orgs := []sdk.Organization{}
+	if _, err := c.GetJSON(context.Background(), "/admin/organization", &orgs); err != nil {
+		return nil, err
+	}
+	return orgs, nil
+}
+
+func (c *client) OrganizationGet(name string) (*sdk.Organization, error) {
+	var o sdk.Organization
+	if _, err := c.GetJSON(context.Background(), "/organization/"+url.QueryEscape(name), &o); err != nil {
+		return nil, err
+	}
+	return &o, nil
+}
+
+func (c *client) OrganizationCreate(o *sdk.Organization) error {
+	_, err := c.PostJSON(context.Background(), "/admin/organization", o, o)
+	return err
+}
+
+func (c *client) OrganizationUpdate(name string, o *sdk.Organization) error {
+	_, err := c.PutJSON(context.Background(), "/organization/"+url.QueryEscape(name), o, o)
+	return err
+}
+
+func (c *client) OrganizationDelete(name string) error {
+	_, err := c.DeleteJSON(context.Background(), "/organization/"+url.QueryEscape(name), nil)
+	return err
+}
+
+func (c *client) OrganizationAdminAdd(name, username string) error {
+	_, err := c.PostJSON(context.Background(), "/organization/"+url.QueryEscape(name)+"/admin/"+url.QueryEscape(username), nil, nil)
+	return err
+}
+
+func (c *client) OrganizationAdminRemove(name, username string) error {
+	_, _, _, err := c.Request(context.Background(), http.MethodDelete, "/organization/"+url.QueryEscape(name)+"/admin/"+url.QueryEscape(username), nil)
+	return err
+}
+
+func (c *client) OrganizationProjectAdd(name, projectKey string) error {
+	_, err := c.PostJSON(context.Background(), "/organization/"+url.QueryEscape(name)+"/project/"+projectKey, nil, nil)
+	return err
+}
+
+func (c *client) OrganizationProjectRemove(name, projectKey string) error {
+	_, _, _, err := c.Request(context.Background(), http.MethodDelete, "/organization/"+url.QueryEscape(name)+"/project/"+projectKey, nil)
+	return err
+}
+
+func (c *client) OrganizationUsage(name, month string) (*sdk.OrganizationUsageReport, error) {
+	var r sdk.OrganizationUsageReport
+	if _, err := c.GetJSON(context.Background(), "/organization/"+url.QueryEscape(name)+"/usage?month="+url.QueryEscape(month), &r); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
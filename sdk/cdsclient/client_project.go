@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"net/http"
 	"net/url"
 
 	"github.com/ovh/cds/engine/api/permission"
@@ -33,6 +34,59 @@ func (c *client) ProjectDelete(key string) error {
 	return err
 }
 
+func (c *client) ProjectArchive(key string) (*sdk.Project, error) {
+	p := &sdk.Project{}
+	if _, err := c.PostJSON(context.Background(), "/project/"+key+"/archival", nil, p); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (c *client) ProjectRestore(key string) (*sdk.Project, error) {
+	p := &sdk.Project{}
+	if _, err := c.DeleteJSON(context.Background(), "/project/"+key+"/archival", p); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (c *client) ProjectTrash(key string) ([]sdk.TrashItem, error) {
+	items := []sdk.TrashItem{}
+	if _, err := c.GetJSON(context.Background(), "/project/"+key+"/trash", &items); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func (c *client) ProjectUsage(key, month string) (*sdk.ResourceUsage, error) {
+	var r sdk.ResourceUsage
+	if _, err := c.GetJSON(context.Background(), "/project/"+key+"/usage?month="+url.QueryEscape(month), &r); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+func (c *client) ProjectDashboard(key string) (*sdk.ProjectDashboard, error) {
+	var d sdk.ProjectDashboard
+	if _, err := c.GetJSON(context.Background(), "/project/"+key+"/dashboard", &d); err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+func (c *client) ProjectTrashRestore(key, itemType, name string) ([]sdk.TrashItem, error) {
+	items := []sdk.TrashItem{}
+	if _, err := c.PostJSON(context.Background(), fmt.Sprintf("/project/%s/trash/%s/%s/restore", key, itemType, name), nil, &items); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func (c *client) ProjectTrashPurge(key, itemType, name string) error {
+	_, err := c.DeleteJSON(context.Background(), fmt.Sprintf("/project/%s/trash/%s/%s", key, itemType, name), nil)
+	return err
+}
+
 func (c *client) ProjectGroupAdd(key, groupName string, permission int, onlyProject bool) error {
 	gp := sdk.GroupPermission{
 		Group:      sdk.Group{Name: groupName},
@@ -88,3 +142,50 @@ func (c *client) ProjectGroupsImport(projectKey string, content io.Reader, forma
 
 	return proj, nil
 }
+
+func (c *client) ProjectSBOMComponentRuns(projectKey string, name, version string) ([]sdk.WorkflowRunSBOMComponent, error) {
+	params := url.Values{}
+	params.Set("name", name)
+	if version != "" {
+		params.Set("version", version)
+	}
+	path := fmt.Sprintf("/project/%s/sbom/components?%s", projectKey, params.Encode())
+	runs := []sdk.WorkflowRunSBOMComponent{}
+	if _, err := c.GetJSON(context.Background(), path, &runs); err != nil {
+		return nil, err
+	}
+	return runs, nil
+}
+
+// ProjectArchiveExport returns the tar archive of the project, a full archive contains the secrets encrypted
+// with the passphrase
+func (c *client) ProjectArchiveExport(projectKey string, full bool, passphrase string) ([]byte, error) {
+	path := fmt.Sprintf("/project/%s/archive?full=%t", projectKey, full)
+	btes, _, _, err := c.Request(context.Background(), "GET", path, nil, func(r *http.Request) {
+		if passphrase != "" {
+			r.Header.Set(sdk.ArchivePassphraseHeader, passphrase)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return btes, nil
+}
+
+// ProjectArchiveImport creates the project of the tar archive
+func (c *client) ProjectArchiveImport(content io.Reader, passphrase string) ([]string, error) {
+	btes, _, _, err := c.Request(context.Background(), "POST", "/project/archive", content, func(r *http.Request) {
+		r.Header.Set("Content-Type", "application/tar")
+		if passphrase != "" {
+			r.Header.Set(sdk.ArchivePassphraseHeader, passphrase)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	messages := []string{}
+	if err := json.Unmarshal(btes, &messages); err != nil {
+		return nil, err
+	}
+	return messages, nil
+}
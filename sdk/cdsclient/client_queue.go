@@ -18,6 +18,8 @@ import (
 	"strings"
 	"time"
 
+	"github.com/sguiheux/go-coverage"
+
 	"github.com/ovh/cds/sdk"
 )
 
@@ -251,13 +253,27 @@ func (c *client) QueueJobIncAttempts(ctx context.Context, jobID int64) ([]int64,
 	return spawnAttempts, err
 }
 
-// QueueJobBook books a job for a Hatchery
+// QueueJobWorkerLost replaces in queue a job whose worker was lost by the hatchery
+func (c *client) QueueJobWorkerLost(ctx context.Context, jobID int64, form sdk.WorkerLostForm) error {
+	path := fmt.Sprintf("/queue/workflows/%d/lost", jobID)
+	_, err := c.PostJSON(ctx, path, form, nil)
+	return err
+}
+
+// QueueJobBook reserves a job for a Hatchery, the reservation must be confirmed before spawning a worker
 func (c *client) QueueJobBook(ctx context.Context, id int64) error {
 	path := fmt.Sprintf("/queue/workflows/%d/book", id)
 	_, err := c.PostJSON(ctx, path, nil, nil)
 	return err
 }
 
+// QueueJobConfirmBook confirms the reservation of a job by a Hatchery
+func (c *client) QueueJobConfirmBook(ctx context.Context, id int64) error {
+	path := fmt.Sprintf("/queue/workflows/%d/book/confirm", id)
+	_, err := c.PostJSON(ctx, path, nil, nil)
+	return err
+}
+
 // QueueJobRelease release a job for a worker
 func (c *client) QueueJobRelease(id int64) error {
 	path := fmt.Sprintf("/queue/workflows/%d/book", id)
@@ -281,15 +297,15 @@ func (c *client) QueueSendResult(ctx context.Context, id int64, res sdk.Result)
 	return err
 }
 
-func (c *client) QueueArtifactUpload(ctx context.Context, id int64, tag, filePath string) (bool, time.Duration, error) {
+func (c *client) QueueArtifactUpload(ctx context.Context, id int64, tag, filePath, signingKey string) (bool, time.Duration, error) {
 	t0 := time.Now()
 	store := new(sdk.ArtifactsStore)
 	_, _ = c.GetJSON(ctx, "/artifact/store", store)
 	if store.TemporaryURLSupported {
-		err := c.queueIndirectArtifactUpload(ctx, id, tag, filePath)
+		err := c.queueIndirectArtifactUpload(ctx, id, tag, filePath, signingKey)
 		return true, time.Since(t0), err
 	}
-	err := c.queueDirectArtifactUpload(id, tag, filePath)
+	err := c.queueDirectArtifactUpload(id, tag, filePath, signingKey)
 	return false, time.Since(t0), err
 }
 
@@ -352,7 +368,7 @@ func (c *client) queueIndirectArtifactTempURLPost(url string, content []byte) er
 	return globalErr
 }
 
-func (c *client) queueIndirectArtifactUpload(ctx context.Context, id int64, tag, filePath string) error {
+func (c *client) queueIndirectArtifactUpload(ctx context.Context, id int64, tag, filePath, signingKey string) error {
 	f, errop := os.Open(filePath)
 	if errop != nil {
 		return errop
@@ -370,6 +386,11 @@ func (c *client) queueIndirectArtifactUpload(ctx context.Context, id int64, tag,
 		return err512
 	}
 
+	sha256sum, err256 := sdk.FileSHA256sum(filePath)
+	if err256 != nil {
+		return err256
+	}
+
 	md5sum, errmd5 := sdk.FileMd5sum(filePath)
 	if errmd5 != nil {
 		return errmd5
@@ -379,14 +400,16 @@ func (c *client) queueIndirectArtifactUpload(ctx context.Context, id int64, tag,
 
 	ref := base64.RawURLEncoding.EncodeToString([]byte(tag))
 	art := sdk.WorkflowNodeRunArtifact{
-		Name:      name,
-		Tag:       tag,
-		Ref:       ref,
-		Size:      stat.Size(),
-		Perm:      uint32(stat.Mode().Perm()),
-		MD5sum:    md5sum,
-		SHA512sum: sha512sum,
-		Created:   time.Now(),
+		Name:       name,
+		Tag:        tag,
+		Ref:        ref,
+		Size:       stat.Size(),
+		Perm:       uint32(stat.Mode().Perm()),
+		MD5sum:     md5sum,
+		SHA512sum:  sha512sum,
+		SHA256sum:  sha256sum,
+		SigningKey: signingKey,
+		Created:    time.Now(),
 	}
 
 	if err := c.queueIndirectArtifactTempURL(ctx, id, &art); err != nil {
@@ -434,7 +457,7 @@ func (c *client) queueIndirectArtifactUpload(ctx context.Context, id int64, tag,
 	return callbackErr
 }
 
-func (c *client) queueDirectArtifactUpload(id int64, tag, filePath string) error {
+func (c *client) queueDirectArtifactUpload(id int64, tag, filePath, signingKey string) error {
 	f, errop := os.Open(filePath)
 	if errop != nil {
 		return errop
@@ -451,6 +474,11 @@ func (c *client) queueDirectArtifactUpload(id int64, tag, filePath string) error
 		return err512
 	}
 
+	sha256sum, err256 := sdk.FileSHA256sum(filePath)
+	if err256 != nil {
+		return err256
+	}
+
 	md5sum, errmd5 := sdk.FileMd5sum(filePath)
 	if errmd5 != nil {
 		return errmd5
@@ -478,6 +506,10 @@ func (c *client) queueDirectArtifactUpload(id int64, tag, filePath string) error
 	writer.WriteField("perm", strconv.FormatUint(uint64(stat.Mode().Perm()), 10))
 	writer.WriteField("md5sum", md5sum)
 	writer.WriteField("sha512sum", sha512sum)
+	writer.WriteField("sha256sum", sha256sum)
+	if signingKey != "" {
+		writer.WriteField("signing_key", signingKey)
+	}
 
 	if errclose := writer.Close(); errclose != nil {
 		return errclose
@@ -506,6 +538,86 @@ func (c *client) QueueJobTag(ctx context.Context, jobID int64, tags []sdk.Workfl
 	return err
 }
 
+func (c *client) QueueSBOMUpload(ctx context.Context, jobID int64, name string, document []byte) (*sdk.WorkflowRunSBOM, error) {
+	path := fmt.Sprintf("/queue/workflows/%d/sbom?name=%s", jobID, url.QueryEscape(name))
+	var sbom sdk.WorkflowRunSBOM
+	if _, err := c.PostJSON(ctx, path, json.RawMessage(document), &sbom); err != nil {
+		return nil, err
+	}
+	return &sbom, nil
+}
+
+func (c *client) QueueWorkflowRunResultAdd(ctx context.Context, jobID int64, result sdk.WorkflowRunResult) (*sdk.WorkflowRunResult, error) {
+	path := fmt.Sprintf("/queue/workflows/%d/run/results", jobID)
+	var res sdk.WorkflowRunResult
+	if _, err := c.PostJSON(ctx, path, result, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+func (c *client) QueueJobOutputSet(ctx context.Context, jobID int64, output sdk.WorkflowRunJobOutput) (*sdk.WorkflowRunJobOutput, error) {
+	path := fmt.Sprintf("/queue/workflows/%d/output", jobID)
+	var res sdk.WorkflowRunJobOutput
+	if _, err := c.PostJSON(ctx, path, output, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+func (c *client) QueueTmpFileUpload(ctx context.Context, jobID int64, name string, content io.Reader) (*sdk.WorkflowRunTmpFile, error) {
+	path := fmt.Sprintf("/queue/workflows/%d/tmpfile/%s", jobID, url.PathEscape(name))
+	mods := []RequestModifier{
+		func(r *http.Request) {
+			r.Header.Set("Content-Type", "application/octet-stream")
+		},
+	}
+	btes, _, _, err := c.Request(ctx, "POST", path, content, mods...)
+	if err != nil {
+		return nil, err
+	}
+	var f sdk.WorkflowRunTmpFile
+	if err := json.Unmarshal(btes, &f); err != nil {
+		return nil, err
+	}
+	return &f, nil
+}
+
+func (c *client) QueueTmpFileDownload(ctx context.Context, jobID int64, name string) (io.ReadCloser, error) {
+	path := fmt.Sprintf("/queue/workflows/%d/tmpfile/%s", jobID, url.PathEscape(name))
+	body, _, code, err := c.Stream(ctx, "GET", path, nil, true)
+	if err != nil {
+		return nil, err
+	}
+	if code >= 400 {
+		defer body.Close()
+		return nil, decodeStreamError(body, code)
+	}
+	return body, nil
+}
+
+func (c *client) QueueJobMirrorDownload(ctx context.Context, jobID int64, gitURL string) (io.ReadCloser, error) {
+	path := fmt.Sprintf("/queue/workflows/%d/mirror?url=%s", jobID, url.QueryEscape(gitURL))
+	body, _, code, err := c.Stream(ctx, "GET", path, nil, true)
+	if err != nil {
+		return nil, err
+	}
+	if code >= 400 {
+		defer body.Close()
+		return nil, decodeStreamError(body, code)
+	}
+	return body, nil
+}
+
+func (c *client) QueueCoverageUpload(ctx context.Context, jobID int64, report coverage.Report) (*sdk.WorkflowNodeRunCoverageDiff, error) {
+	path := fmt.Sprintf("/queue/workflows/%d/coverage", jobID)
+	var diff sdk.WorkflowNodeRunCoverageDiff
+	if _, err := c.PostJSON(ctx, path, report, &diff); err != nil {
+		return nil, err
+	}
+	return &diff, nil
+}
+
 func (c *client) QueueServiceLogs(ctx context.Context, logs []sdk.ServiceLog) error {
 	status, err := c.PostJSON(ctx, "/queue/workflows/log/service", logs, nil)
 	if status >= 400 {
@@ -0,0 +1,37 @@
+package cdsclient
+
+import (
+	"context"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func (c *client) AdminQueueQuotas() (sdk.QueueQuotas, error) {
+	var q sdk.QueueQuotas
+	if _, err := c.GetJSON(context.Background(), "/admin/queue/quotas", &q); err != nil {
+		return q, err
+	}
+	return q, nil
+}
+
+func (c *client) AdminProjectQueueQuotaSet(projectKey string, maxConcurrentJobs int) error {
+	q := sdk.ProjectQueueQuota{MaxConcurrentJobs: maxConcurrentJobs}
+	_, err := c.PutJSON(context.Background(), "/project/"+projectKey+"/queue/quota", q, nil)
+	return err
+}
+
+func (c *client) AdminProjectQueueQuotaDelete(projectKey string) error {
+	_, err := c.DeleteJSON(context.Background(), "/project/"+projectKey+"/queue/quota", nil)
+	return err
+}
+
+func (c *client) AdminGroupQueueWeightSet(groupName string, weight int) error {
+	w := sdk.GroupQueueWeight{Weight: weight}
+	_, err := c.PutJSON(context.Background(), "/group/"+groupName+"/queue/weight", w, nil)
+	return err
+}
+
+func (c *client) AdminGroupQueueWeightDelete(groupName string) error {
+	_, err := c.DeleteJSON(context.Background(), "/group/"+groupName+"/queue/weight", nil)
+	return err
+}
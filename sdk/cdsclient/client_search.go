@@ -0,0 +1,21 @@
+package cdsclient
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func (c *client) Search(query string, offset, limit int) (*sdk.SearchResults, error) {
+	params := url.Values{}
+	params.Set("q", query)
+	params.Set("offset", strconv.Itoa(offset))
+	params.Set("limit", strconv.Itoa(limit))
+	var res sdk.SearchResults
+	if _, err := c.GetJSON(context.Background(), "/search?"+params.Encode(), &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
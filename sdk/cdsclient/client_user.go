@@ -5,17 +5,21 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"time"
 
 	"github.com/ovh/cds/sdk"
 )
 
 func (c *client) UserLogin(username, password string) (bool, string, error) {
-	r := struct {
-		Username string `json:"username"`
-		Password string `json:"password"`
-	}{
+	return c.UserLoginWithMFA(username, password, nil)
+}
+
+// UserLoginWithMFA logs in with the second factor of an account with two-factor authentication enabled
+func (c *client) UserLoginWithMFA(username, password string, mfa *sdk.MFAVerification) (bool, string, error) {
+	r := sdk.UserLoginRequest{
 		Username: username,
 		Password: password,
+		MFA:      mfa,
 	}
 
 	response := struct {
@@ -125,6 +129,89 @@ func (c *client) FindToken(tokenValue string) (sdk.Token, error) {
 	return token, nil
 }
 
+// UserAccessTokenCreate creates a personal access token, it returns the token and its JWT value
+func (c *client) UserAccessTokenCreate(description string, scopes []string, expiration time.Duration, restrictions sdk.AccessTokenRestrictions) (*sdk.AccessToken, string, error) {
+	req := sdk.AccessTokenRequest{
+		Description:           description,
+		Scopes:                scopes,
+		ExpirationDelaySecond: expiration.Seconds(),
+		Restrictions:          restrictions,
+	}
+	var token sdk.AccessToken
+	_, header, _, err := c.RequestJSON(context.Background(), http.MethodPost, "/user/accesstoken", req, &token)
+	if err != nil {
+		return nil, "", err
+	}
+	return &token, header.Get("X-CDS-JWT"), nil
+}
+
+// UserAccessTokenList lists the access tokens of the current user
+func (c *client) UserAccessTokenList() ([]sdk.AccessToken, error) {
+	tokens := []sdk.AccessToken{}
+	if _, err := c.GetJSON(context.Background(), "/user/accesstoken", &tokens); err != nil {
+		return nil, err
+	}
+	return tokens, nil
+}
+
+// UserAccessTokenRestrict updates the restrictions of an access token of the current user
+func (c *client) UserAccessTokenRestrict(id string, restrictions sdk.AccessTokenRestrictions) (*sdk.AccessToken, error) {
+	var token sdk.AccessToken
+	if _, err := c.PutJSON(context.Background(), "/user/accesstoken/"+url.QueryEscape(id), restrictions, &token); err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// UserAccessTokenRevoke revokes an access token of the current user
+func (c *client) UserAccessTokenRevoke(id string) error {
+	_, err := c.DeleteJSON(context.Background(), "/user/accesstoken/"+url.QueryEscape(id), nil)
+	return err
+}
+
+// UserVCSAccountList lists the accounts on the repositories managers linked to a user
+func (c *client) UserVCSAccountList(username string) ([]sdk.UserVCSAccount, error) {
+	as := []sdk.UserVCSAccount{}
+	if _, err := c.GetJSON(context.Background(), "/user/"+url.QueryEscape(username)+"/vcs", &as); err != nil {
+		return nil, err
+	}
+	return as, nil
+}
+
+// UserVCSAccountAuthorize starts the OAuth flow of a repositories manager to link an account to a user, it returns the
+// URL where the account authorizes CDS and the request token of the flow
+func (c *client) UserVCSAccountAuthorize(username, vcsServer string) (map[string]string, error) {
+	res := map[string]string{}
+	if _, err := c.PostJSON(context.Background(), fmt.Sprintf("/user/%s/vcs/%s/authorize", url.QueryEscape(username), url.QueryEscape(vcsServer)), nil, &res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// UserVCSAccountAuthorizeCallback ends the OAuth flow of the repositories managers which give a verifier instead of
+// calling back CDS
+func (c *client) UserVCSAccountAuthorizeCallback(username, vcsServer, requestToken, verifier string) (*sdk.UserVCSAccount, error) {
+	body := map[string]string{"request_token": requestToken, "verifier": verifier}
+	var a sdk.UserVCSAccount
+	if _, err := c.PostJSON(context.Background(), fmt.Sprintf("/user/%s/vcs/%s/authorize/callback", url.QueryEscape(username), url.QueryEscape(vcsServer)), body, &a); err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+// UserVCSAccountUnlink unlinks an account on a repositories manager from a user
+func (c *client) UserVCSAccountUnlink(username string, id int64) error {
+	_, err := c.DeleteJSON(context.Background(), fmt.Sprintf("/user/%s/vcs/%d", url.QueryEscape(username), id), nil)
+	return err
+}
+
+// UserMFAVerify checks a second factor of the current user, the session can then run sensitive operations for some
+// minutes
+func (c *client) UserMFAVerify(v sdk.MFAVerification) error {
+	_, err := c.PostJSON(context.Background(), "/user/mfa/verify", v, nil)
+	return err
+}
+
 // UpdateFavorite Update favorites (add or delete) return updated workflow or project
 func (c *client) UpdateFavorite(params sdk.FavoriteParams) (interface{}, error) {
 	switch params.Type {
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"net/url"
 	"time"
 
 	"github.com/ovh/cds/sdk"
@@ -81,3 +82,31 @@ func (c *client) WorkerSetStatus(ctx context.Context, status sdk.Status) error {
 
 	return nil
 }
+
+func (c *client) AdminWorkerPools(pool string) ([]sdk.Worker, error) {
+	uri := "/admin/worker/pool"
+	if pool != "" {
+		uri += "?pool=" + url.QueryEscape(pool)
+	}
+	ws := []sdk.Worker{}
+	if _, err := c.GetJSON(context.Background(), uri, &ws); err != nil {
+		return nil, err
+	}
+	return ws, nil
+}
+
+func (c *client) AdminWorkerPoolDrain(pool string, workers ...string) ([]sdk.Worker, error) {
+	q := url.Values{}
+	for _, w := range workers {
+		q.Add("worker", w)
+	}
+	uri := fmt.Sprintf("/admin/worker/pool/%s/drain", url.PathEscape(pool))
+	if len(q) > 0 {
+		uri += "?" + q.Encode()
+	}
+	ws := []sdk.Worker{}
+	if _, err := c.PostJSON(context.Background(), uri, nil, &ws); err != nil {
+		return nil, err
+	}
+	return ws, nil
+}
@@ -155,6 +155,13 @@ func (c *client) WorkerModel(name string) (sdk.Model, error) {
 	return model, err
 }
 
+func (c *client) WorkerModelResolved(name string) (sdk.Model, error) {
+	uri := "/worker/model?resolved=true&name=" + url.QueryEscape(name)
+	var model sdk.Model
+	_, err := c.GetJSON(context.Background(), uri, &model)
+	return model, err
+}
+
 func (c *client) WorkerModelDelete(name string) error {
 	wm, err := c.WorkerModel(name)
 	if err != nil {
@@ -165,3 +172,15 @@ func (c *client) WorkerModelDelete(name string) error {
 	_, errDelete := c.DeleteJSON(context.Background(), uri, nil)
 	return errDelete
 }
+
+func (c *client) AdminUnusedWorkerModels(days int) ([]sdk.Model, error) {
+	uri := "/admin/worker/model/unused"
+	if days > 0 {
+		uri += fmt.Sprintf("?days=%d", days)
+	}
+	var models []sdk.Model
+	if _, err := c.GetJSON(context.Background(), uri, &models); err != nil {
+		return nil, err
+	}
+	return models, nil
+}
@@ -9,6 +9,7 @@ import (
 	"log"
 	"net/http"
 	"net/url"
+	"strconv"
 	"time"
 
 	"github.com/ovh/cds/sdk"
@@ -127,6 +128,188 @@ func (c *client) WorkflowRunArtifacts(projectKey string, workflowName string, nu
 	return arts, nil
 }
 
+func (c *client) WorkflowRunResultsList(projectKey string, workflowName string, number int64, types ...string) ([]sdk.WorkflowRunResult, error) {
+	path := fmt.Sprintf("/project/%s/workflows/%s/runs/%d/results", projectKey, workflowName, number)
+	if len(types) > 0 {
+		params := url.Values{}
+		for _, t := range types {
+			params.Add("type", t)
+		}
+		path += "?" + params.Encode()
+	}
+	results := []sdk.WorkflowRunResult{}
+	if _, err := c.GetJSON(context.Background(), path, &results); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+func (c *client) WorkflowRunJobOutputsList(projectKey string, workflowName string, number int64) ([]sdk.WorkflowRunJobOutput, error) {
+	path := fmt.Sprintf("/project/%s/workflows/%s/runs/%d/outputs", projectKey, workflowName, number)
+	outputs := []sdk.WorkflowRunJobOutput{}
+	if _, err := c.GetJSON(context.Background(), path, &outputs); err != nil {
+		return nil, err
+	}
+	return outputs, nil
+}
+
+func (c *client) WorkflowTestCaseHistory(projectKey string, workflowName string, nodeName, testSuite, testName, branch string) ([]sdk.WorkflowRunTestCase, error) {
+	params := url.Values{}
+	params.Set("node", nodeName)
+	params.Set("suite", testSuite)
+	params.Set("name", testName)
+	if branch != "" {
+		params.Set("branch", branch)
+	}
+	path := fmt.Sprintf("/project/%s/workflows/%s/tests/history?%s", projectKey, workflowName, params.Encode())
+	history := []sdk.WorkflowRunTestCase{}
+	if _, err := c.GetJSON(context.Background(), path, &history); err != nil {
+		return nil, err
+	}
+	return history, nil
+}
+
+func (c *client) WorkflowFlakyTests(projectKey string, workflowName string, nodeName, branch string) ([]sdk.WorkflowTestCaseFlakiness, error) {
+	params := url.Values{}
+	if nodeName != "" {
+		params.Set("node", nodeName)
+	}
+	if branch != "" {
+		params.Set("branch", branch)
+	}
+	path := fmt.Sprintf("/project/%s/workflows/%s/tests/flaky?%s", projectKey, workflowName, params.Encode())
+	flakiness := []sdk.WorkflowTestCaseFlakiness{}
+	if _, err := c.GetJSON(context.Background(), path, &flakiness); err != nil {
+		return nil, err
+	}
+	return flakiness, nil
+}
+
+func (c *client) WorkflowCoverageTrend(projectKey string, workflowName string, applicationName, branch string) ([]sdk.WorkflowCoverageTrend, error) {
+	params := url.Values{}
+	if applicationName != "" {
+		params.Set("application", applicationName)
+	}
+	if branch != "" {
+		params.Set("branch", branch)
+	}
+	path := fmt.Sprintf("/project/%s/workflows/%s/coverage?%s", projectKey, workflowName, params.Encode())
+	trend := []sdk.WorkflowCoverageTrend{}
+	if _, err := c.GetJSON(context.Background(), path, &trend); err != nil {
+		return nil, err
+	}
+	return trend, nil
+}
+
+func (c *client) WorkflowRunCoverage(projectKey string, workflowName string, number int64) ([]sdk.WorkflowNodeRunCoverageDiff, error) {
+	path := fmt.Sprintf("/project/%s/workflows/%s/runs/%d/coverage", projectKey, workflowName, number)
+	diffs := []sdk.WorkflowNodeRunCoverageDiff{}
+	if _, err := c.GetJSON(context.Background(), path, &diffs); err != nil {
+		return nil, err
+	}
+	return diffs, nil
+}
+
+func (c *client) WorkflowRunsCompare(projectKey string, workflowName string, from, to int64) (*sdk.WorkflowRunComparison, error) {
+	path := fmt.Sprintf("/project/%s/workflows/%s/runs/compare?from=%d&to=%d", projectKey, workflowName, from, to)
+	var comparison sdk.WorkflowRunComparison
+	if _, err := c.GetJSON(context.Background(), path, &comparison); err != nil {
+		return nil, err
+	}
+	return &comparison, nil
+}
+
+func (c *client) WorkflowDurationsAnalytics(projectKey string, workflowName string, kind, pipelineName, interval string, days int) ([]sdk.WorkflowDurationStats, error) {
+	params := url.Values{}
+	if kind != "" {
+		params.Set("kind", kind)
+	}
+	if pipelineName != "" {
+		params.Set("pipeline", pipelineName)
+	}
+	if interval != "" {
+		params.Set("interval", interval)
+	}
+	if days > 0 {
+		params.Set("days", strconv.Itoa(days))
+	}
+	path := fmt.Sprintf("/project/%s/workflows/%s/analytics/durations?%s", projectKey, workflowName, params.Encode())
+	stats := []sdk.WorkflowDurationStats{}
+	if _, err := c.GetJSON(context.Background(), path, &stats); err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+func (c *client) WorkflowSlowestSteps(projectKey string, workflowName string, pipelineName string, days int) ([]sdk.WorkflowSlowStep, error) {
+	params := url.Values{}
+	if pipelineName != "" {
+		params.Set("pipeline", pipelineName)
+	}
+	if days > 0 {
+		params.Set("days", strconv.Itoa(days))
+	}
+	path := fmt.Sprintf("/project/%s/workflows/%s/analytics/steps?%s", projectKey, workflowName, params.Encode())
+	steps := []sdk.WorkflowSlowStep{}
+	if _, err := c.GetJSON(context.Background(), path, &steps); err != nil {
+		return nil, err
+	}
+	return steps, nil
+}
+
+func (c *client) WorkflowBadgeGet(projectKey string, workflowName string) (*sdk.WorkflowBadge, error) {
+	path := fmt.Sprintf("/project/%s/workflows/%s/badge", projectKey, workflowName)
+	var badge sdk.WorkflowBadge
+	if _, err := c.GetJSON(context.Background(), path, &badge); err != nil {
+		return nil, err
+	}
+	return &badge, nil
+}
+
+func (c *client) WorkflowBadgeEnable(projectKey string, workflowName string, protected bool) (*sdk.WorkflowBadge, error) {
+	path := fmt.Sprintf("/project/%s/workflows/%s/badge", projectKey, workflowName)
+	req := map[string]bool{"protected": protected}
+	var badge sdk.WorkflowBadge
+	if _, err := c.PostJSON(context.Background(), path, req, &badge); err != nil {
+		return nil, err
+	}
+	return &badge, nil
+}
+
+func (c *client) WorkflowBadgeDisable(projectKey string, workflowName string) error {
+	path := fmt.Sprintf("/project/%s/workflows/%s/badge", projectKey, workflowName)
+	_, err := c.DeleteJSON(context.Background(), path, nil)
+	return err
+}
+
+func (c *client) WorkflowRunSBOMs(projectKey string, workflowName string, number int64) ([]sdk.WorkflowRunSBOM, error) {
+	url := fmt.Sprintf("/project/%s/workflows/%s/runs/%d/sbom", projectKey, workflowName, number)
+	sboms := []sdk.WorkflowRunSBOM{}
+	if _, err := c.GetJSON(context.Background(), url, &sboms); err != nil {
+		return nil, err
+	}
+	return sboms, nil
+}
+
+func (c *client) WorkflowRunSBOMDownload(projectKey string, workflowName string, number int64, sbomID int64, w io.Writer) error {
+	url := fmt.Sprintf("/project/%s/workflows/%s/runs/%d/sbom/%d", projectKey, workflowName, number, sbomID)
+	btes, _, _, err := c.Request(context.Background(), "GET", url, nil)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(btes)
+	return err
+}
+
+func (c *client) WorkflowNodeRunChangedFiles(projectKey string, workflowName string, number int64, nodeRunID int64) ([]sdk.VCSChangedFile, error) {
+	url := fmt.Sprintf("/project/%s/workflows/%s/runs/%d/nodes/%d/changes", projectKey, workflowName, number, nodeRunID)
+	files := []sdk.VCSChangedFile{}
+	if _, err := c.GetJSON(context.Background(), url, &files); err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
 func (c *client) WorkflowNodeRun(projectKey string, workflowName string, number int64, nodeRunID int64) (*sdk.WorkflowNodeRun, error) {
 	url := fmt.Sprintf("/project/%s/workflows/%s/runs/%d/nodes/%d", projectKey, workflowName, number, nodeRunID)
 	run := sdk.WorkflowNodeRun{}
@@ -167,6 +350,54 @@ func (c *client) WorkflowNodeRunJobStep(projectKey string, workflowName string,
 	return &buildState, nil
 }
 
+func (c *client) WorkflowNodeRunJobStepLogDownload(projectKey string, workflowName string, number int64, nodeRunID, job int64, step int, offset int64, w io.Writer) (int64, error) {
+	url := fmt.Sprintf("/project/%s/workflows/%s/runs/%d/nodes/%d/job/%d/step/%d/log/download", projectKey, workflowName, number, nodeRunID, job, step)
+	var mods []RequestModifier
+	if offset > 0 {
+		mods = append(mods, SetHeader("Range", fmt.Sprintf("bytes=%d-", offset)))
+	}
+	reader, _, code, err := c.Stream(context.Background(), "GET", url, nil, true, mods...)
+	if err != nil {
+		return 0, err
+	}
+	defer reader.Close()
+
+	// There is nothing after the offset
+	if code == http.StatusRequestedRangeNotSatisfiable {
+		return 0, nil
+	}
+	if code >= 400 {
+		return 0, decodeStreamError(reader, code)
+	}
+	return io.Copy(w, reader)
+}
+
+func (c *client) WorkflowRunLogsDownload(projectKey string, workflowName string, number int64, w io.Writer) error {
+	url := fmt.Sprintf("/project/%s/workflows/%s/runs/%d/logs/download", projectKey, workflowName, number)
+	reader, _, code, err := c.Stream(context.Background(), "GET", url, nil, true)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	if code >= 400 {
+		return decodeStreamError(reader, code)
+	}
+	_, err = io.Copy(w, reader)
+	return err
+}
+
+func decodeStreamError(body io.Reader, code int) error {
+	btes, err := ioutil.ReadAll(body)
+	if err != nil {
+		return err
+	}
+	if err := sdk.DecodeError(btes); err != nil {
+		return err
+	}
+	return fmt.Errorf("HTTP %d", code)
+}
+
 func (c *client) WorkflowNodeRunArtifactDownload(projectKey string, workflowName string, a sdk.WorkflowNodeRunArtifact, w io.Writer) error {
 	var url = fmt.Sprintf("/project/%s/workflows/%s/artifact/%d", projectKey, workflowName, a.ID)
 	var reader io.ReadCloser
@@ -186,6 +417,24 @@ func (c *client) WorkflowNodeRunArtifactDownload(projectKey string, workflowName
 	return err
 }
 
+func (c *client) WorkflowNodeRunArtifactVerify(projectKey string, workflowName string, artifactID int64) (*sdk.WorkflowNodeRunArtifactVerification, error) {
+	url := fmt.Sprintf("/project/%s/workflows/%s/artifact/%d/verify", projectKey, workflowName, artifactID)
+	var v sdk.WorkflowNodeRunArtifactVerification
+	if _, err := c.GetJSON(context.Background(), url, &v); err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
+
+func (c *client) WorkflowNodeRunArtifactProvenance(projectKey string, workflowName string, artifactID int64) (*sdk.DSSEEnvelope, error) {
+	url := fmt.Sprintf("/project/%s/workflows/%s/artifact/%d/provenance", projectKey, workflowName, artifactID)
+	var env sdk.DSSEEnvelope
+	if _, err := c.GetJSON(context.Background(), url, &env); err != nil {
+		return nil, err
+	}
+	return &env, nil
+}
+
 func (c *client) WorkflowNodeRunRelease(projectKey string, workflowName string, runNumber int64, nodeRunID int64, release sdk.WorkflowNodeRunRelease) error {
 	url := fmt.Sprintf("/project/%s/workflows/%s/runs/%d/nodes/%d/release", projectKey, workflowName, runNumber, nodeRunID)
 	code, err := c.PostJSON(context.Background(), url, release, nil)
@@ -198,7 +447,16 @@ func (c *client) WorkflowNodeRunRelease(projectKey string, workflowName string,
 	return nil
 }
 
-func (c *client) WorkflowRunFromHook(projectKey string, workflowName string, hook sdk.WorkflowNodeRunHookEvent) (*sdk.WorkflowRun, error) {
+func (c *client) WorkflowNodeRunPromote(projectKey string, workflowName string, runNumber int64, nodeRunID int64, promote sdk.WorkflowNodeRunPromote) (*sdk.WorkflowNodeRunPromote, error) {
+	url := fmt.Sprintf("/project/%s/workflows/%s/runs/%d/nodes/%d/promote", projectKey, workflowName, runNumber, nodeRunID)
+	var res sdk.WorkflowNodeRunPromote
+	if _, err := c.PostJSON(context.Background(), url, promote, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+func (c *client) WorkflowRunFromHook(ctx context.Context, projectKey string, workflowName string, hook sdk.WorkflowNodeRunHookEvent) (*sdk.WorkflowRun, error) {
 	if c.config.Verbose {
 		log.Println("Payload: ", hook.Payload)
 	}
@@ -206,7 +464,7 @@ func (c *client) WorkflowRunFromHook(projectKey string, workflowName string, hoo
 	url := fmt.Sprintf("/project/%s/workflows/%s/runs", projectKey, workflowName)
 	content := sdk.WorkflowRunPostHandlerOption{Hook: &hook}
 	run := &sdk.WorkflowRun{}
-	code, err := c.PostJSON(context.Background(), url, &content, run)
+	code, err := c.PostJSON(ctx, url, &content, run)
 	if err != nil {
 		return nil, err
 	}
@@ -241,6 +499,15 @@ func (c *client) WorkflowRunFromManual(projectKey string, workflowName string, m
 	return run, nil
 }
 
+func (c *client) WorkflowRunPriority(projectKey string, workflowName string, number int64, priority int) (*sdk.WorkflowRun, error) {
+	url := fmt.Sprintf("/project/%s/workflows/%s/runs/%d/priority", projectKey, workflowName, number)
+	run := &sdk.WorkflowRun{}
+	if _, err := c.PutJSON(context.Background(), url, sdk.WorkflowRunPriority{Priority: priority}, run); err != nil {
+		return nil, err
+	}
+	return run, nil
+}
+
 func (c *client) WorkflowStop(projectKey string, workflowName string, number int64) (*sdk.WorkflowRun, error) {
 	url := fmt.Sprintf("/project/%s/workflows/%s/runs/%d/stop", projectKey, workflowName, number)
 
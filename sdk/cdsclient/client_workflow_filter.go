@@ -0,0 +1,55 @@
+package cdsclient
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func (c *client) WorkflowListFiltered(projectKey string, f sdk.WorkflowFilter) ([]sdk.Workflow, error) {
+	return c.workflowListQuery(projectKey, f.Values())
+}
+
+func (c *client) WorkflowListBySavedFilter(projectKey string, name string) ([]sdk.Workflow, error) {
+	return c.workflowListQuery(projectKey, url.Values{"filter": []string{name}})
+}
+
+func (c *client) workflowListQuery(projectKey string, q url.Values) ([]sdk.Workflow, error) {
+	path := fmt.Sprintf("/project/%s/workflows", projectKey)
+	if len(q) > 0 {
+		path += "?" + q.Encode()
+	}
+	w := []sdk.Workflow{}
+	if _, err := c.GetJSON(context.Background(), path, &w); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+func (c *client) WorkflowSavedFilterList(projectKey string) ([]sdk.WorkflowSavedFilter, error) {
+	fs := []sdk.WorkflowSavedFilter{}
+	if _, err := c.GetJSON(context.Background(), fmt.Sprintf("/project/%s/workflow/filters", projectKey), &fs); err != nil {
+		return nil, err
+	}
+	return fs, nil
+}
+
+func (c *client) WorkflowSavedFilterCreate(projectKey string, f *sdk.WorkflowSavedFilter) error {
+	_, err := c.PostJSON(context.Background(), fmt.Sprintf("/project/%s/workflow/filters", projectKey), f, f)
+	return err
+}
+
+func (c *client) WorkflowSavedFilterDelete(projectKey string, name string) error {
+	_, err := c.DeleteJSON(context.Background(), fmt.Sprintf("/project/%s/workflow/filters/%s", projectKey, url.PathEscape(name)), nil)
+	return err
+}
+
+func (c *client) WorkflowLabels(projectKey string, req sdk.WorkflowLabelsRequest) ([]sdk.IDName, error) {
+	res := []sdk.IDName{}
+	if _, err := c.PostJSON(context.Background(), fmt.Sprintf("/project/%s/workflow/labels", projectKey), req, &res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
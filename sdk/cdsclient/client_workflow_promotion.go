@@ -0,0 +1,62 @@
+package cdsclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// WorkflowPromote promotes the workflow to a remote CDS instance
+func (c *client) WorkflowPromote(projectKey string, workflowName string, req sdk.WorkflowPromotionRequest) (*sdk.WorkflowPromotion, error) {
+	path := fmt.Sprintf("/project/%s/workflows/%s/promote", projectKey, workflowName)
+	var p sdk.WorkflowPromotion
+	if _, err := c.PostJSON(context.Background(), path, req, &p); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// WorkflowPromotionReceive imports a pulled workflow promoted from another CDS instance
+func (c *client) WorkflowPromotionReceive(projectKey string, tarContent io.Reader, passphrase, source, comment string) (*sdk.WorkflowPromotion, error) {
+	params := url.Values{}
+	params.Set("source", source)
+	params.Set("comment", comment)
+	path := fmt.Sprintf("/project/%s/promotions?%s", projectKey, params.Encode())
+	btes, _, _, err := c.Request(context.Background(), "POST", path, tarContent, func(r *http.Request) {
+		r.Header.Set("Content-Type", "application/tar")
+		r.Header.Set(sdk.ArchivePassphraseHeader, passphrase)
+	})
+	if err != nil {
+		return nil, err
+	}
+	var p sdk.WorkflowPromotion
+	if err := json.Unmarshal(btes, &p); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// WorkflowPromotionList returns the promotions of the workflow
+func (c *client) WorkflowPromotionList(projectKey string, workflowName string) ([]sdk.WorkflowPromotion, error) {
+	path := fmt.Sprintf("/project/%s/workflows/%s/promotions", projectKey, workflowName)
+	ps := []sdk.WorkflowPromotion{}
+	if _, err := c.GetJSON(context.Background(), path, &ps); err != nil {
+		return nil, err
+	}
+	return ps, nil
+}
+
+// WorkflowPromotionGet returns a promotion of the workflow with its diff
+func (c *client) WorkflowPromotionGet(projectKey string, workflowName string, id int64) (*sdk.WorkflowPromotion, error) {
+	path := fmt.Sprintf("/project/%s/workflows/%s/promotions/%d", projectKey, workflowName, id)
+	var p sdk.WorkflowPromotion
+	if _, err := c.GetJSON(context.Background(), path, &p); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
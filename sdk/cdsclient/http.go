@@ -228,7 +228,9 @@ func (c *client) Stream(ctx context.Context, method string, path string, body io
 				basedHash := base64.StdEncoding.EncodeToString([]byte(c.config.Hash))
 				req.Header.Set(AuthHeader, basedHash)
 			}
-			if c.config.User != "" && c.config.Token != "" {
+			if c.config.AccessToken != "" {
+				req.Header.Set("Authorization", "Bearer "+c.config.AccessToken)
+			} else if c.config.User != "" && c.config.Token != "" {
 				req.Header.Add(SessionTokenHeader, c.config.Token)
 				req.SetBasicAuth(c.config.User, c.config.Token)
 			}
@@ -330,7 +332,9 @@ func (c *client) UploadMultiPart(method string, path string, body *bytes.Buffer,
 			basedHash := base64.StdEncoding.EncodeToString([]byte(c.config.Hash))
 			req.Header.Set(AuthHeader, basedHash)
 		}
-		if !c.isProvider && c.config.User != "" && c.config.Token != "" {
+		if !c.isProvider && c.config.AccessToken != "" {
+			req.Header.Set("Authorization", "Bearer "+c.config.AccessToken)
+		} else if !c.isProvider && c.config.User != "" && c.config.Token != "" {
 			req.Header.Add(SessionTokenHeader, c.config.Token)
 			req.SetBasicAuth(c.config.User, c.config.Token)
 		}
@@ -14,11 +14,13 @@ import (
 const (
 	sseEvent = "event"
 	sseData  = "data"
+	sseID    = "id"
 )
 
 //SSEvent is a go representation of an http server-sent event
 type SSEvent struct {
 	URI  string
+	ID   string
 	Type string
 	Data io.Reader
 }
@@ -79,6 +81,7 @@ func (c *client) RequestSSEGet(ctx context.Context, path string, evCh chan<- SSE
 	delim := []byte{':', ' '}
 
 	var currEvent *SSEvent
+	var lastID string
 	var EOF bool
 
 	for !EOF {
@@ -107,8 +110,10 @@ func (c *client) RequestSSEGet(ctx context.Context, path string, evCh chan<- SSE
 			continue
 		}
 
-		currEvent = &SSEvent{URI: uri}
+		currEvent = &SSEvent{URI: uri, ID: lastID}
 		switch string(spl[0]) {
+		case sseID:
+			lastID = string(bytes.TrimSpace(spl[1]))
 		case sseEvent:
 			currEvent.Type = string(bytes.TrimSpace(spl[1]))
 		case sseData:
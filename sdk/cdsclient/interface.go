@@ -7,6 +7,8 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/sguiheux/go-coverage"
+
 	"github.com/ovh/cds/sdk"
 )
 
@@ -34,9 +36,26 @@ type AdminService interface {
 	AdminDatabaseMigrationDelete(id string) error
 	AdminDatabaseMigrationUnlock(id string) error
 	AdminDatabaseMigrationsList() ([]sdk.DatabaseMigrationStatus, error)
+	AdminDatabaseMigrationStatus() (*sdk.DatabaseSchemaStatus, error)
 	AdminCDSMigrationList() ([]sdk.Migration, error)
 	AdminCDSMigrationCancel(id int64) error
 	AdminCDSMigrationReset(id int64) error
+	AdminSecretsRotate(projectKey string) (map[string]int, error)
+	AdminAudits(filter sdk.AuditLogFilter) ([]sdk.AuditLog, error)
+	AdminQueueQuotas() (sdk.QueueQuotas, error)
+	AdminProjectQueueQuotaSet(projectKey string, maxConcurrentJobs int) error
+	AdminProjectQueueQuotaDelete(projectKey string) error
+	AdminGroupQueueWeightSet(groupName string, weight int) error
+	AdminGroupQueueWeightDelete(groupName string) error
+	AdminLDAPGroupSyncStatus() (sdk.LDAPGroupSyncStatus, error)
+	AdminEventOutbox(status string) ([]sdk.EventOutboxEntry, error)
+	AdminEventOutboxRetry(id int64) error
+	AdminEventOutboxDelete(id int64) error
+	AdminCost(by, from, to string) (*sdk.CostReport, error)
+	AdminCostCSV(by, from, to string) ([]byte, error)
+	AdminUnusedWorkerModels(days int) ([]sdk.Model, error)
+	AdminWorkerPools(pool string) ([]sdk.Worker, error)
+	AdminWorkerPoolDrain(pool string, workers ...string) ([]sdk.Worker, error)
 	Services() ([]sdk.Service, error)
 	ServicesByName(name string) (*sdk.Service, error)
 	ServiceDelete(name string) error
@@ -112,6 +131,7 @@ type EnvironmentClient interface {
 	EnvironmentImport(projectKey string, content io.Reader, format string, force bool) ([]string, error)
 	EnvironmentVariableClient
 	EnvironmentKeysClient
+	EnvironmentFreezeClient
 }
 
 // EnvironmentKeysClient exposes environment keys related functions
@@ -121,6 +141,14 @@ type EnvironmentKeysClient interface {
 	EnvironmentKeysDelete(projectKey string, envName string, keyEnvName string) error
 }
 
+// EnvironmentFreezeClient exposes environment freezes related functions
+type EnvironmentFreezeClient interface {
+	EnvironmentFreezeList(projectKey string, envName string) ([]sdk.EnvironmentFreeze, error)
+	EnvironmentFreezeCreate(projectKey string, envName string, f *sdk.EnvironmentFreeze) error
+	EnvironmentFreezeDelete(projectKey string, envName string, id int64) error
+	WorkflowNodeRunFreezeOverride(projectKey string, workflowName string, runNumber int64, nodeRunID int64) error
+}
+
 // EnvironmentVariableClient exposes environment variables related functions
 type EnvironmentVariableClient interface {
 	EnvironmentVariablesList(key string, envName string) ([]sdk.Variable, error)
@@ -134,6 +162,7 @@ type EnvironmentVariableClient interface {
 type DownloadClient interface {
 	Download() ([]sdk.Download, error)
 	DownloadURLFromAPI(name, os, arch string) string
+	DownloadChecksumFromAPI(name, os, arch string) (string, error)
 	DownloadURLFromGithub(filename string) (string, error)
 }
 
@@ -185,12 +214,50 @@ type PipelineClient interface {
 // MaintenanceClient manage maintenance mode on CDS
 type MaintenanceClient interface {
 	Maintenance(enable bool) error
+	MaintenanceStatus() (sdk.MaintenanceStatus, error)
+}
+
+// DashboardClient exposes dashboards related functions
+type DashboardClient interface {
+	DashboardList() ([]sdk.Dashboard, error)
+	DashboardGet(id int64) (*sdk.Dashboard, error)
+	DashboardCreate(d *sdk.Dashboard) error
+	DashboardUpdate(d *sdk.Dashboard) error
+	DashboardDelete(id int64) error
+	DashboardData(id int64) ([]sdk.DashboardWidgetData, error)
+}
+
+// OrganizationClient exposes organizations related functions
+type OrganizationClient interface {
+	OrganizationList() ([]sdk.Organization, error)
+	OrganizationGet(name string) (*sdk.Organization, error)
+	OrganizationCreate(o *sdk.Organization) error
+	OrganizationUpdate(name string, o *sdk.Organization) error
+	OrganizationDelete(name string) error
+	OrganizationAdminAdd(name, username string) error
+	OrganizationAdminRemove(name, username string) error
+	OrganizationProjectAdd(name, projectKey string) error
+	OrganizationProjectRemove(name, projectKey string) error
+	OrganizationUsage(name, month string) (*sdk.OrganizationUsageReport, error)
 }
 
 // ProjectClient exposes project related functions
 type ProjectClient interface {
 	ProjectCreate(proj *sdk.Project, groupName string) error
 	ProjectDelete(projectKey string) error
+	ProjectArchive(projectKey string) (*sdk.Project, error)
+	ProjectRestore(projectKey string) (*sdk.Project, error)
+	ProjectTrash(projectKey string) ([]sdk.TrashItem, error)
+	ProjectUsage(projectKey, month string) (*sdk.ResourceUsage, error)
+	ProjectDashboard(projectKey string) (*sdk.ProjectDashboard, error)
+	ProjectCost(projectKey, by, from, to string) (*sdk.CostReport, error)
+	ProjectCostCSV(projectKey, by, from, to string) ([]byte, error)
+	ProjectDORAMetrics(projectKey, application, environment, from, to string) ([]sdk.DORAMetrics, error)
+	ProjectDeployments(projectKey, application, environment string, limit int) ([]sdk.Deployment, error)
+	ProjectCurrentDeployments(projectKey, environment string) ([]sdk.Deployment, error)
+	ProjectDeploymentRollback(projectKey string, req sdk.DeploymentRollbackRequest) (*sdk.DeploymentRollback, error)
+	ProjectTrashRestore(projectKey, itemType, name string) ([]sdk.TrashItem, error)
+	ProjectTrashPurge(projectKey, itemType, name string) error
 	ProjectGroupAdd(projectKey, groupName string, permission int, projectOnly bool) error
 	ProjectGroupDelete(projectKey, groupName string) error
 	ProjectGet(projectKey string, opts ...RequestModifier) (*sdk.Project, error)
@@ -202,6 +269,10 @@ type ProjectClient interface {
 	ProjectIntegrationGet(projectKey string, integrationName string, clearPassword bool) (sdk.ProjectIntegration, error)
 	ProjectIntegrationList(projectKey string) ([]sdk.ProjectIntegration, error)
 	ProjectIntegrationDelete(projectKey string, integrationName string) error
+	ProjectAudits(projectKey string, filter sdk.AuditLogFilter) ([]sdk.AuditLog, error)
+	ProjectSBOMComponentRuns(projectKey string, name, version string) ([]sdk.WorkflowRunSBOMComponent, error)
+	ProjectArchiveExport(projectKey string, full bool, passphrase string) ([]byte, error)
+	ProjectArchiveImport(content io.Reader, passphrase string) ([]string, error)
 }
 
 // ProjectKeysClient exposes project keys related functions
@@ -228,14 +299,23 @@ type QueueClient interface {
 	QueuePolling(ctx context.Context, jobs chan<- sdk.WorkflowNodeJobRun, errs chan<- error, delay time.Duration, graceTime int, modelType string, ratioService *int, exceptWfJobID *int64) error
 	QueueTakeJob(ctx context.Context, job sdk.WorkflowNodeJobRun, isBooked bool) (*sdk.WorkflowNodeJobRunData, error)
 	QueueJobBook(ctx context.Context, id int64) error
+	QueueJobConfirmBook(ctx context.Context, id int64) error
 	QueueJobRelease(id int64) error
 	QueueJobInfo(id int64) (*sdk.WorkflowNodeJobRun, error)
 	QueueJobSendSpawnInfo(ctx context.Context, id int64, in []sdk.SpawnInfo) error
 	QueueSendResult(ctx context.Context, id int64, res sdk.Result) error
-	QueueArtifactUpload(ctx context.Context, id int64, tag, filePath string) (bool, time.Duration, error)
+	QueueArtifactUpload(ctx context.Context, id int64, tag, filePath, signingKey string) (bool, time.Duration, error)
 	QueueStaticFilesUpload(ctx context.Context, nodeJobRunID int64, name, entrypoint, staticKey string, tarContent io.Reader) (string, bool, time.Duration, error)
 	QueueJobTag(ctx context.Context, jobID int64, tags []sdk.WorkflowRunTag) error
+	QueueSBOMUpload(ctx context.Context, jobID int64, name string, document []byte) (*sdk.WorkflowRunSBOM, error)
+	QueueWorkflowRunResultAdd(ctx context.Context, jobID int64, result sdk.WorkflowRunResult) (*sdk.WorkflowRunResult, error)
+	QueueJobOutputSet(ctx context.Context, jobID int64, output sdk.WorkflowRunJobOutput) (*sdk.WorkflowRunJobOutput, error)
+	QueueTmpFileUpload(ctx context.Context, jobID int64, name string, content io.Reader) (*sdk.WorkflowRunTmpFile, error)
+	QueueTmpFileDownload(ctx context.Context, jobID int64, name string) (io.ReadCloser, error)
+	QueueJobMirrorDownload(ctx context.Context, jobID int64, gitURL string) (io.ReadCloser, error)
+	QueueCoverageUpload(ctx context.Context, jobID int64, report coverage.Report) (*sdk.WorkflowNodeRunCoverageDiff, error)
 	QueueJobIncAttempts(ctx context.Context, jobID int64) ([]int64, error)
+	QueueJobWorkerLost(ctx context.Context, jobID int64, form sdk.WorkerLostForm) error
 	QueueServiceLogs(ctx context.Context, logs []sdk.ServiceLog) error
 }
 
@@ -246,10 +326,20 @@ type UserClient interface {
 	UserGet(username string) (*sdk.User, error)
 	UserGetGroups(username string) (map[string][]sdk.Group, error)
 	UserLogin(username, password string) (bool, string, error)
+	UserLoginWithMFA(username, password string, mfa *sdk.MFAVerification) (bool, string, error)
+	UserMFAVerify(v sdk.MFAVerification) error
 	UserReset(username, email, callback string) error
 	UserSignup(username, fullname, email, callback string) error
 	ListAllTokens() ([]sdk.Token, error)
 	FindToken(token string) (sdk.Token, error)
+	UserAccessTokenCreate(description string, scopes []string, expiration time.Duration, restrictions sdk.AccessTokenRestrictions) (*sdk.AccessToken, string, error)
+	UserAccessTokenList() ([]sdk.AccessToken, error)
+	UserAccessTokenRestrict(id string, restrictions sdk.AccessTokenRestrictions) (*sdk.AccessToken, error)
+	UserAccessTokenRevoke(id string) error
+	UserVCSAccountList(username string) ([]sdk.UserVCSAccount, error)
+	UserVCSAccountAuthorize(username, vcsServer string) (map[string]string, error)
+	UserVCSAccountAuthorizeCallback(username, vcsServer, requestToken, verifier string) (*sdk.UserVCSAccount, error)
+	UserVCSAccountUnlink(username string, id int64) error
 	UpdateFavorite(params sdk.FavoriteParams) (interface{}, error)
 }
 
@@ -262,6 +352,7 @@ type WorkerClient interface {
 	WorkerModelAdd(name, modelType, patternName string, dockerModel *sdk.ModelDocker, vmModel *sdk.ModelVirtualMachine, groupID int64) (sdk.Model, error)
 	WorkerModelUpdate(ID int64, name string, modelType string, dockerModel *sdk.ModelDocker, vmModel *sdk.ModelVirtualMachine, groupID int64) (sdk.Model, error)
 	WorkerModel(name string) (sdk.Model, error)
+	WorkerModelResolved(name string) (sdk.Model, error)
 	WorkerModelDelete(name string) error
 	WorkerModelSpawnError(id int64, info sdk.SpawnErrorForm) error
 	WorkerModelsEnabled() ([]sdk.Model, error)
@@ -275,11 +366,18 @@ type WorkerClient interface {
 // HookClient exposes functions used for hooks services
 type HookClient interface {
 	PollVCSEvents(uuid string, workflowID int64, vcsServer string, timestamp int64) (events sdk.RepositoryEvents, interval time.Duration, err error)
+	HookRepair(uuid string) (sdk.RepositoryWebHookReport, error)
 }
 
 // WorkflowClient exposes workflows functions
 type WorkflowClient interface {
 	WorkflowList(projectKey string) ([]sdk.Workflow, error)
+	WorkflowListFiltered(projectKey string, f sdk.WorkflowFilter) ([]sdk.Workflow, error)
+	WorkflowListBySavedFilter(projectKey string, name string) ([]sdk.Workflow, error)
+	WorkflowSavedFilterList(projectKey string) ([]sdk.WorkflowSavedFilter, error)
+	WorkflowSavedFilterCreate(projectKey string, f *sdk.WorkflowSavedFilter) error
+	WorkflowSavedFilterDelete(projectKey string, name string) error
+	WorkflowLabels(projectKey string, req sdk.WorkflowLabelsRequest) ([]sdk.IDName, error)
 	WorkflowGet(projectKey, name string) (*sdk.Workflow, error)
 	WorkflowUpdate(projectKey, name string, wf *sdk.Workflow) error
 	WorkflowDelete(projectKey string, workflowName string) error
@@ -290,16 +388,41 @@ type WorkflowClient interface {
 	WorkflowRunSearch(projectKey string, offset, limit int64, filter ...Filter) ([]sdk.WorkflowRun, error)
 	WorkflowRunList(projectKey string, workflowName string, offset, limit int64) ([]sdk.WorkflowRun, error)
 	WorkflowRunArtifacts(projectKey string, name string, number int64) ([]sdk.WorkflowNodeRunArtifact, error)
-	WorkflowRunFromHook(projectKey string, workflowName string, hook sdk.WorkflowNodeRunHookEvent) (*sdk.WorkflowRun, error)
+	WorkflowRunResultsList(projectKey string, name string, number int64, types ...string) ([]sdk.WorkflowRunResult, error)
+	WorkflowRunJobOutputsList(projectKey string, name string, number int64) ([]sdk.WorkflowRunJobOutput, error)
+	WorkflowRunSBOMs(projectKey string, name string, number int64) ([]sdk.WorkflowRunSBOM, error)
+	WorkflowTestCaseHistory(projectKey string, name string, nodeName, testSuite, testName, branch string) ([]sdk.WorkflowRunTestCase, error)
+	WorkflowFlakyTests(projectKey string, name string, nodeName, branch string) ([]sdk.WorkflowTestCaseFlakiness, error)
+	WorkflowCoverageTrend(projectKey string, name string, applicationName, branch string) ([]sdk.WorkflowCoverageTrend, error)
+	WorkflowRunCoverage(projectKey string, name string, number int64) ([]sdk.WorkflowNodeRunCoverageDiff, error)
+	WorkflowRunsCompare(projectKey string, name string, from, to int64) (*sdk.WorkflowRunComparison, error)
+	WorkflowDurationsAnalytics(projectKey string, name string, kind, pipelineName, interval string, days int) ([]sdk.WorkflowDurationStats, error)
+	WorkflowSlowestSteps(projectKey string, name string, pipelineName string, days int) ([]sdk.WorkflowSlowStep, error)
+	WorkflowBadgeGet(projectKey string, name string) (*sdk.WorkflowBadge, error)
+	WorkflowBadgeEnable(projectKey string, name string, protected bool) (*sdk.WorkflowBadge, error)
+	WorkflowBadgeDisable(projectKey string, name string) error
+	WorkflowPromote(projectKey string, name string, req sdk.WorkflowPromotionRequest) (*sdk.WorkflowPromotion, error)
+	WorkflowPromotionReceive(projectKey string, tarContent io.Reader, passphrase, source, comment string) (*sdk.WorkflowPromotion, error)
+	WorkflowPromotionList(projectKey string, name string) ([]sdk.WorkflowPromotion, error)
+	WorkflowPromotionGet(projectKey string, name string, id int64) (*sdk.WorkflowPromotion, error)
+	WorkflowRunSBOMDownload(projectKey string, name string, number int64, sbomID int64, w io.Writer) error
+	WorkflowRunFromHook(ctx context.Context, projectKey string, workflowName string, hook sdk.WorkflowNodeRunHookEvent) (*sdk.WorkflowRun, error)
 	WorkflowRunFromManual(projectKey string, workflowName string, manual sdk.WorkflowNodeRunManual, number, fromNodeID int64) (*sdk.WorkflowRun, error)
 	WorkflowRunNumberGet(projectKey string, workflowName string) (*sdk.WorkflowRunNumber, error)
 	WorkflowRunNumberSet(projectKey string, workflowName string, number int64) error
 	WorkflowStop(projectKey string, workflowName string, number int64) (*sdk.WorkflowRun, error)
+	WorkflowRunPriority(projectKey string, workflowName string, number int64, priority int) (*sdk.WorkflowRun, error)
 	WorkflowNodeStop(projectKey string, workflowName string, number, fromNodeID int64) (*sdk.WorkflowNodeRun, error)
 	WorkflowNodeRun(projectKey string, name string, number int64, nodeRunID int64) (*sdk.WorkflowNodeRun, error)
+	WorkflowNodeRunChangedFiles(projectKey string, name string, number int64, nodeRunID int64) ([]sdk.VCSChangedFile, error)
 	WorkflowNodeRunArtifactDownload(projectKey string, name string, a sdk.WorkflowNodeRunArtifact, w io.Writer) error
+	WorkflowNodeRunArtifactVerify(projectKey string, name string, artifactID int64) (*sdk.WorkflowNodeRunArtifactVerification, error)
+	WorkflowNodeRunArtifactProvenance(projectKey string, name string, artifactID int64) (*sdk.DSSEEnvelope, error)
 	WorkflowNodeRunJobStep(projectKey string, workflowName string, number int64, nodeRunID, job int64, step int) (*sdk.BuildState, error)
+	WorkflowNodeRunJobStepLogDownload(projectKey string, workflowName string, number int64, nodeRunID, job int64, step int, offset int64, w io.Writer) (int64, error)
+	WorkflowRunLogsDownload(projectKey string, workflowName string, number int64, w io.Writer) error
 	WorkflowNodeRunRelease(projectKey string, workflowName string, runNumber int64, nodeRunID int64, release sdk.WorkflowNodeRunRelease) error
+	WorkflowNodeRunPromote(projectKey string, workflowName string, runNumber int64, nodeRunID int64, promote sdk.WorkflowNodeRunPromote) (*sdk.WorkflowNodeRunPromote, error)
 	WorkflowAllHooksList() ([]sdk.WorkflowNodeHook, error)
 	WorkflowCachePush(projectKey, ref string, tarContent io.Reader) error
 	WorkflowCachePull(projectKey, ref string) (io.Reader, error)
@@ -334,17 +457,21 @@ type Interface interface {
 	ConfigUser() (map[string]string, error)
 	DownloadClient
 	EnvironmentClient
+	EventsListen(ctx context.Context, events chan<- sdk.Event)
 	ExportImportInterface
 	GroupClient
 	GRPCPluginsClient
 	HatcheryClient
 	BroadcastClient
+	DashboardClient
 	MaintenanceClient
+	OrganizationClient
 	PipelineClient
 	IntegrationClient
 	ProjectClient
 	QueueClient
 	Navbar() ([]sdk.NavbarProjectData, error)
+	Search(query string, offset, limit int) (*sdk.SearchResults, error)
 	Requirements() ([]sdk.Requirement, error)
 	RepositoriesManagerInterface
 	GetService() *sdk.Service
@@ -5,6 +5,7 @@ import (
 	"bytes"
 	"context"
 	"crypto/md5"
+	"crypto/sha256"
 	"crypto/sha512"
 	"encoding/hex"
 	"encoding/json"
@@ -96,6 +97,23 @@ func FileMd5sum(filePath string) (string, error) {
 	return sum, nil
 }
 
+// FileSHA256sum returns the sha256sum of a file
+func FileSHA256sum(filePath string) (string, error) {
+	file, errop := os.Open(filePath)
+	if errop != nil {
+		return "", fmt.Errorf("error opening file for computing sha256: %v", errop)
+	}
+	defer file.Close()
+
+	reader := bufio.NewReader(file)
+	hash := sha256.New()
+	if _, err := io.Copy(hash, reader); err != nil {
+		return "", fmt.Errorf("error computing sha256: %v", err)
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
 // FileSHA512sum returns the sha512sum of a file
 func FileSHA512sum(filePath string) (string, error) {
 	file, errop := os.Open(filePath)
@@ -0,0 +1,66 @@
+package sdk
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"time"
+)
+
+// Aggregations of the cost reports
+const (
+	CostByProject     = "project"
+	CostByApplication = "application"
+	CostByGroup       = "group"
+	CostByWorkflow    = "workflow"
+	CostByRun         = "run"
+)
+
+// CostAggregations lists the valid aggregations of the cost reports
+var CostAggregations = []string{CostByProject, CostByApplication, CostByGroup, CostByWorkflow, CostByRun}
+
+// CostReportLine is the compute time of the workers and the size of the artifacts of the runs charged to a project,
+// an application, a group, a workflow or a run
+type CostReportLine struct {
+	Key            string `json:"key" db:"key" cli:"key,key"`
+	Runs           int64  `json:"runs" db:"runs" cli:"runs"`
+	ComputeSeconds int64  `json:"compute_seconds" db:"compute_seconds" cli:"-"`
+	ComputeMinutes int64  `json:"compute_minutes" db:"-" cli:"compute_minutes"`
+	ArtifactSize   int64  `json:"artifact_size" db:"artifact_size" cli:"artifact_size"`
+}
+
+// CostReport aggregates the costs of the runs started between two dates
+type CostReport struct {
+	By    string           `json:"by"`
+	From  time.Time        `json:"from"`
+	To    time.Time        `json:"to"`
+	Lines []CostReportLine `json:"lines"`
+}
+
+// ComputeMinutes rounds up the compute time of each line to the minute
+func (r *CostReport) ComputeMinutes() {
+	for i := range r.Lines {
+		r.Lines[i].ComputeMinutes = (r.Lines[i].ComputeSeconds + 59) / 60
+	}
+}
+
+// WriteCSV writes the lines of the report as CSV with a header line
+func (r CostReport) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{r.By, "runs", "compute_seconds", "compute_minutes", "artifact_size"}); err != nil {
+		return WithStack(err)
+	}
+	for _, l := range r.Lines {
+		if err := cw.Write([]string{
+			l.Key,
+			strconv.FormatInt(l.Runs, 10),
+			strconv.FormatInt(l.ComputeSeconds, 10),
+			strconv.FormatInt(l.ComputeMinutes, 10),
+			strconv.FormatInt(l.ArtifactSize, 10),
+		}); err != nil {
+			return WithStack(err)
+		}
+	}
+	cw.Flush()
+	return WithStack(cw.Error())
+}
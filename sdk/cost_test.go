@@ -0,0 +1,26 @@
+package sdk
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCostReportWriteCSV(t *testing.T) {
+	r := CostReport{
+		By: CostByApplication,
+		Lines: []CostReportLine{
+			{Key: "PROJ/my-app", Runs: 3, ComputeSeconds: 125, ArtifactSize: 2048},
+			{Key: "PROJ/other, app", Runs: 1, ComputeSeconds: 60},
+		},
+	}
+	r.ComputeMinutes()
+
+	var buf bytes.Buffer
+	assert.NoError(t, r.WriteCSV(&buf))
+	assert.Equal(t, `application,runs,compute_seconds,compute_minutes,artifact_size
+PROJ/my-app,3,125,3,2048
+"PROJ/other, app",1,60,1,0
+`, buf.String())
+}
@@ -0,0 +1,139 @@
+package sdk
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Types of the widgets of a dashboard
+const (
+	DashboardWidgetRunHistory          = "run_history"
+	DashboardWidgetQueueDepth          = "queue_depth"
+	DashboardWidgetDeploymentFrequency = "deployment_frequency"
+)
+
+// DashboardWidgetTypes lists the types of the widgets of a dashboard
+var DashboardWidgetTypes = []string{DashboardWidgetRunHistory, DashboardWidgetQueueDepth, DashboardWidgetDeploymentFrequency}
+
+// DashboardWidgetDefaultDays is the period of the widgets showing a history, in days, when it is not set
+const DashboardWidgetDefaultDays = 30
+
+// Dashboard is a set of widgets defined by a user, it can be shared with other users and groups
+type Dashboard struct {
+	ID           int64            `json:"id" db:"id" cli:"id,key"`
+	Name         string           `json:"name" db:"name" cli:"name"`
+	Description  string           `json:"description" db:"description" cli:"description"`
+	OwnerID      int64            `json:"-" db:"owner_id" cli:"-"`
+	Owner        string           `json:"owner" db:"-" cli:"owner"`
+	Widgets      DashboardWidgets `json:"widgets" db:"widgets" cli:"-"`
+	SharedUsers  []string         `json:"shared_users" db:"-" cli:"-"`
+	SharedGroups []string         `json:"shared_groups" db:"-" cli:"-"`
+	Created      time.Time        `json:"created" db:"created" cli:"created"`
+	LastModified time.Time        `json:"last_modified" db:"last_modified" cli:"-"`
+}
+
+// IsValid returns an error if the name of the dashboard is empty or if one of its widgets is invalid
+func (d Dashboard) IsValid() error {
+	if d.Name == "" {
+		return NewErrorFrom(ErrWrongRequest, "invalid dashboard name")
+	}
+	for i, w := range d.Widgets {
+		if err := w.IsValid(); err != nil {
+			return NewErrorFrom(ErrWrongRequest, "invalid widget %d: %v", i, Cause(err))
+		}
+	}
+	return nil
+}
+
+// CanRead returns true if the user owns the dashboard or if it is shared with the user or one of the user's groups
+func (d Dashboard) CanRead(u *User) bool {
+	if u.Admin || d.OwnerID == u.ID || IsInArray(u.Username, d.SharedUsers) {
+		return true
+	}
+	for _, g := range u.Groups {
+		if IsInArray(g.Name, d.SharedGroups) {
+			return true
+		}
+	}
+	return false
+}
+
+// DashboardWidget is a widget of a dashboard with its position on a grid. The run history widget shows the runs of a
+// workflow by status and by day, the queue depth widget the jobs of a project waiting and building, and the
+// deployment frequency widget the successful runs of a pipeline of a workflow by day.
+type DashboardWidget struct {
+	Type         string `json:"type"`
+	Title        string `json:"title,omitempty"`
+	ProjectKey   string `json:"project_key"`
+	WorkflowName string `json:"workflow_name,omitempty"`
+	PipelineName string `json:"pipeline_name,omitempty"`
+	Days         int    `json:"days,omitempty"`
+	X            int    `json:"x"`
+	Y            int    `json:"y"`
+	Width        int    `json:"width"`
+	Height       int    `json:"height"`
+}
+
+// IsValid returns an error if the type of the widget is unknown or if it misses the data it shows
+func (w DashboardWidget) IsValid() error {
+	if !IsInArray(w.Type, DashboardWidgetTypes) {
+		return errors.Errorf("unknown type %s", w.Type)
+	}
+	if w.ProjectKey == "" {
+		return errors.New("missing project key")
+	}
+	if w.Type != DashboardWidgetQueueDepth && w.WorkflowName == "" {
+		return errors.New("missing workflow name")
+	}
+	if w.Type == DashboardWidgetDeploymentFrequency && w.PipelineName == "" {
+		return errors.New("missing pipeline name")
+	}
+	if w.Days < 0 || w.Days > 365 {
+		return errors.Errorf("invalid days %d, should be between 1 and 365", w.Days)
+	}
+	return nil
+}
+
+// Since returns the beginning of the history shown by the widget
+func (w DashboardWidget) Since(now time.Time) time.Time {
+	days := w.Days
+	if days == 0 {
+		days = DashboardWidgetDefaultDays
+	}
+	return now.AddDate(0, 0, -days)
+}
+
+// DashboardWidgets are the widgets of a dashboard
+type DashboardWidgets []DashboardWidget
+
+// Value returns driver.Value from dashboard widgets.
+func (w DashboardWidgets) Value() (driver.Value, error) {
+	j, err := json.Marshal(w)
+	return j, WrapError(err, "cannot marshal DashboardWidgets")
+}
+
+// Scan dashboard widgets.
+func (w *DashboardWidgets) Scan(src interface{}) error {
+	source, ok := src.([]byte)
+	if !ok {
+		return WithStack(errors.New("type assertion .([]byte) failed"))
+	}
+	return WrapError(json.Unmarshal(source, w), "cannot unmarshal DashboardWidgets")
+}
+
+// DashboardWidgetData is the data shown by a widget, or the reason why it cannot be shown
+type DashboardWidgetData struct {
+	Type   string               `json:"type"`
+	Points []DashboardDataPoint `json:"points"`
+	Error  string               `json:"error,omitempty"`
+}
+
+// DashboardDataPoint is a value of a widget at a date for a label, like the number of failed runs on a day
+type DashboardDataPoint struct {
+	Date  time.Time `json:"date"`
+	Label string    `json:"label"`
+	Value float64   `json:"value"`
+}
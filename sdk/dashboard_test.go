@@ -0,0 +1,40 @@
+package sdk
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDashboardIsValid(t *testing.T) {
+	d := Dashboard{
+		Name: "my dashboard",
+		Widgets: DashboardWidgets{
+			{Type: DashboardWidgetRunHistory, ProjectKey: "PROJ", WorkflowName: "build"},
+			{Type: DashboardWidgetQueueDepth, ProjectKey: "PROJ"},
+			{Type: DashboardWidgetDeploymentFrequency, ProjectKey: "PROJ", WorkflowName: "deploy", PipelineName: "deploy-prod", Days: 90},
+		},
+	}
+	assert.NoError(t, d.IsValid())
+
+	d.Widgets = append(d.Widgets, DashboardWidget{Type: DashboardWidgetDeploymentFrequency, ProjectKey: "PROJ", WorkflowName: "deploy"})
+	assert.Error(t, d.IsValid())
+	assert.Error(t, Dashboard{}.IsValid())
+	assert.Error(t, DashboardWidget{Type: "pie", ProjectKey: "PROJ"}.IsValid())
+	assert.Error(t, DashboardWidget{Type: DashboardWidgetQueueDepth, ProjectKey: "PROJ", Days: 400}.IsValid())
+
+	now := time.Date(2019, 3, 31, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, time.Date(2019, 3, 1, 0, 0, 0, 0, time.UTC), d.Widgets[0].Since(now))
+	assert.Equal(t, time.Date(2018, 12, 31, 0, 0, 0, 0, time.UTC), d.Widgets[2].Since(now))
+}
+
+func TestDashboardCanRead(t *testing.T) {
+	d := Dashboard{OwnerID: 1, SharedUsers: []string{"bob"}, SharedGroups: []string{"team-a"}}
+
+	assert.True(t, d.CanRead(&User{ID: 1, Username: "alice"}))
+	assert.True(t, d.CanRead(&User{ID: 2, Username: "bob"}))
+	assert.True(t, d.CanRead(&User{ID: 3, Username: "carol", Groups: []Group{{Name: "team-a"}}}))
+	assert.True(t, d.CanRead(&User{ID: 4, Username: "admin", Admin: true}))
+	assert.False(t, d.CanRead(&User{ID: 5, Username: "dave", Groups: []Group{{Name: "team-b"}}}))
+}
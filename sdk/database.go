@@ -10,3 +10,15 @@ type DatabaseMigrationStatus struct {
 	Migrated  bool       `json:"migrated" db:"-" cli:"migrated"`
 	AppliedAt *time.Time `json:"applied_at" db:"applied_at" cli:"applied_at"`
 }
+
+// DatabaseSchemaStatus represents the compatibility between the database schema and the API
+type DatabaseSchemaStatus struct {
+	SchemaVersion        int        `json:"schema_version" cli:"schema_version"`
+	RequiredVersion      int        `json:"required_version" cli:"required_version"`
+	MinCompatibleVersion int        `json:"min_compatible_version" cli:"min_compatible_version"`
+	Compatible           bool       `json:"compatible" cli:"compatible"`
+	Locked               bool       `json:"locked" cli:"locked"`
+	LockedBy             string     `json:"locked_by,omitempty" cli:"locked_by"`
+	LockedSince          *time.Time `json:"locked_since,omitempty" cli:"locked_since"`
+	OnlineMigrations     int        `json:"online_migrations" cli:"online_migrations"`
+}
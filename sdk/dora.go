@@ -0,0 +1,240 @@
+package sdk
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Deployment is a run of a workflow node with an application and an environment. The lead time is the time between
+// the oldest commit of the run and its end, in seconds, or -1 when the commits of the run are unknown.
+type Deployment struct {
+	ID                int64     `json:"id" db:"id" cli:"-"`
+	ProjectID         int64     `json:"project_id" db:"project_id" cli:"-"`
+	ApplicationID     int64     `json:"application_id" db:"application_id" cli:"-"`
+	ApplicationName   string    `json:"application_name" db:"-" cli:"application,key"`
+	EnvironmentID     int64     `json:"environment_id" db:"environment_id" cli:"-"`
+	EnvironmentName   string    `json:"environment_name" db:"-" cli:"environment,key"`
+	Version           string    `json:"version" db:"version" cli:"version"`
+	Status            string    `json:"status" db:"status" cli:"status"`
+	VCSBranch         string    `json:"vcs_branch" db:"vcs_branch" cli:"branch"`
+	VCSHash           string    `json:"vcs_hash" db:"vcs_hash" cli:"commit"`
+	WorkflowID        int64     `json:"workflow_id" db:"workflow_id" cli:"-"`
+	WorkflowName      string    `json:"workflow_name" db:"workflow_name" cli:"workflow"`
+	WorkflowNodeName  string    `json:"workflow_node_name" db:"workflow_node_name" cli:"-"`
+	WorkflowRunID     int64     `json:"workflow_run_id" db:"workflow_run_id" cli:"-"`
+	Number            int64     `json:"num" db:"num" cli:"num"`
+	WorkflowNodeRunID int64     `json:"workflow_node_run_id" db:"workflow_node_run_id" cli:"-"`
+	URL               string    `json:"url" db:"url" cli:"url"`
+	Start             time.Time `json:"start" db:"started" cli:"-"`
+	Done              time.Time `json:"done" db:"done" cli:"done"`
+	LeadTime          float64   `json:"lead_time" db:"lead_time" cli:"-"`
+	RollbackOf        int64     `json:"rollback_of,omitempty" db:"rollback_of" cli:"rollback_of"`
+}
+
+// NewDeployment returns the deployment of a terminated node run
+func NewDeployment(nr WorkflowNodeRun, projectID, applicationID, environmentID int64) Deployment {
+	d := Deployment{
+		ProjectID:         projectID,
+		ApplicationID:     applicationID,
+		EnvironmentID:     environmentID,
+		WorkflowID:        nr.WorkflowID,
+		WorkflowNodeName:  nr.WorkflowNodeName,
+		WorkflowRunID:     nr.WorkflowRunID,
+		Number:            nr.Number,
+		WorkflowNodeRunID: nr.ID,
+		Version:           DeploymentVersion(nr),
+		Status:            nr.Status,
+		VCSBranch:         nr.VCSBranch,
+		VCSHash:           nr.VCSHash,
+		Start:             nr.Start,
+		Done:              nr.Done,
+		LeadTime:          -1,
+	}
+	var oldest time.Time
+	for _, c := range nr.Commits {
+		if t := c.Time(); !t.IsZero() && (oldest.IsZero() || t.Before(oldest)) {
+			oldest = t
+		}
+	}
+	if !oldest.IsZero() && !oldest.After(nr.Done) {
+		d.LeadTime = nr.Done.Sub(oldest).Seconds()
+	}
+	return d
+}
+
+// DeploymentVersion returns the version deployed by a node run: its release version, its git tag, its semver or its
+// run number
+func DeploymentVersion(nr WorkflowNodeRun) string {
+	if v := ParameterValue(nr.BuildParameters, "cds.release.version"); v != "" {
+		return v
+	}
+	if nr.VCSTag != "" {
+		return nr.VCSTag
+	}
+	if v := ParameterValue(nr.BuildParameters, "cds.semver"); v != "" {
+		return v
+	}
+	return fmt.Sprintf("%d", nr.Number)
+}
+
+// DeploymentArtifact is an artifact of the workflow run of a deployment, identified by its digest
+type DeploymentArtifact struct {
+	Name      string `json:"name" cli:"name,key"`
+	Tag       string `json:"tag" cli:"tag"`
+	SHA512sum string `json:"sha512sum" cli:"sha512sum"`
+}
+
+// DeploymentArtifacts returns the artifacts of the last run of each node of a workflow run
+func DeploymentArtifacts(wr WorkflowRun) []DeploymentArtifact {
+	as := []DeploymentArtifact{}
+	for _, nrs := range wr.WorkflowNodeRuns {
+		if len(nrs) == 0 {
+			continue
+		}
+		for _, a := range nrs[0].Artifacts {
+			as = append(as, DeploymentArtifact{Name: a.Name, Tag: a.Tag, SHA512sum: a.SHA512sum})
+		}
+	}
+	sort.Slice(as, func(i, j int) bool {
+		if as[i].Tag != as[j].Tag {
+			return as[i].Tag < as[j].Tag
+		}
+		return as[i].Name < as[j].Name
+	})
+	return as
+}
+
+// DeploymentRollbackRequest selects the deployment to redeploy: by its id, or the last successful deployment of a
+// version of an application on an environment. Without version, the last successful deployment of the version before
+// the current one is selected.
+type DeploymentRollbackRequest struct {
+	DeploymentID    int64  `json:"deployment_id,omitempty"`
+	ApplicationName string `json:"application_name,omitempty"`
+	EnvironmentName string `json:"environment_name,omitempty"`
+	Version         string `json:"version,omitempty"`
+}
+
+// DeploymentRollback is the redeployment of a previous successful deployment: its workflow node is run again in its
+// workflow run, with the artifacts of the run, without rebuilding them
+type DeploymentRollback struct {
+	ID                int64                `json:"id" cli:"id,key"`
+	ProjectID         int64                `json:"project_id" cli:"-"`
+	DeploymentID      int64                `json:"deployment_id" cli:"deployment_id"`
+	ApplicationName   string               `json:"application_name" cli:"application"`
+	EnvironmentName   string               `json:"environment_name" cli:"environment"`
+	Version           string               `json:"version" cli:"version"`
+	WorkflowName      string               `json:"workflow_name" cli:"workflow"`
+	Number            int64                `json:"num" cli:"num"`
+	SubNumber         int64                `json:"subnum" cli:"subnum"`
+	WorkflowNodeRunID int64                `json:"workflow_node_run_id" cli:"-"`
+	Artifacts         []DeploymentArtifact `json:"artifacts" cli:"-"`
+	Author            string               `json:"author" cli:"author"`
+	Created           time.Time            `json:"created" cli:"-"`
+}
+
+// RollbackTarget returns the deployment to redeploy among the deployments of an application on an environment, the
+// most recent first: the last successful deployment of the given version, or without version of the version before
+// the current one
+func RollbackTarget(deployments []Deployment, version string) (*Deployment, error) {
+	var current string
+	for i := range deployments {
+		d := &deployments[i]
+		if d.Status != StatusSuccess.String() {
+			continue
+		}
+		if version != "" {
+			if d.Version == version {
+				return d, nil
+			}
+			continue
+		}
+		if current == "" {
+			current = d.Version
+			continue
+		}
+		if d.Version != current {
+			return d, nil
+		}
+	}
+	if version != "" {
+		return nil, NewErrorFrom(ErrNotFound, "no successful deployment of version %s", version)
+	}
+	return nil, NewErrorFrom(ErrNotFound, "no successful deployment of a previous version")
+}
+
+// DORAMetrics are the four key metrics of the deployments of an application on an environment over a period:
+// the successful deployments per day, the median lead time for changes of the successful deployments, the ratio of
+// failed deployments and the mean time to restore, between a failed deployment and the next successful one. The
+// durations are in seconds, -1 when they cannot be computed.
+type DORAMetrics struct {
+	ApplicationName     string    `json:"application_name" cli:"application,key"`
+	EnvironmentName     string    `json:"environment_name" cli:"environment,key"`
+	From                time.Time `json:"from" cli:"-"`
+	To                  time.Time `json:"to" cli:"-"`
+	Deployments         int64     `json:"deployments" cli:"deployments"`
+	Failures            int64     `json:"failures" cli:"failures"`
+	DeploymentFrequency float64   `json:"deployment_frequency" cli:"deployment_frequency"`
+	LeadTime            float64   `json:"lead_time" cli:"lead_time"`
+	ChangeFailureRate   float64   `json:"change_failure_rate" cli:"change_failure_rate"`
+	TimeToRestore       float64   `json:"time_to_restore" cli:"time_to_restore"`
+}
+
+// ComputeDORAMetrics computes the metrics of the deployments of an application on an environment, ended between the
+// from and to dates. Only the successful and failed deployments are counted.
+func ComputeDORAMetrics(deployments []Deployment, from, to time.Time) DORAMetrics {
+	m := DORAMetrics{From: from, To: to, LeadTime: -1, ChangeFailureRate: -1, TimeToRestore: -1}
+
+	ds := make([]Deployment, 0, len(deployments))
+	for _, d := range deployments {
+		if (d.Status == StatusSuccess.String() || d.Status == StatusFail.String()) && !d.Done.Before(from) && d.Done.Before(to) {
+			ds = append(ds, d)
+		}
+	}
+	sort.Slice(ds, func(i, j int) bool { return ds[i].Done.Before(ds[j].Done) })
+
+	var leadTimes []float64
+	var restores []float64
+	var failedAt time.Time
+	for _, d := range ds {
+		m.Deployments++
+		if d.Status == StatusFail.String() {
+			m.Failures++
+			if failedAt.IsZero() {
+				failedAt = d.Done
+			}
+			continue
+		}
+		if d.LeadTime >= 0 {
+			leadTimes = append(leadTimes, d.LeadTime)
+		}
+		if !failedAt.IsZero() {
+			restores = append(restores, d.Done.Sub(failedAt).Seconds())
+			failedAt = time.Time{}
+		}
+	}
+
+	if days := to.Sub(from).Hours() / 24; days > 0 {
+		m.DeploymentFrequency = float64(m.Deployments-m.Failures) / days
+	}
+	if m.Deployments > 0 {
+		m.ChangeFailureRate = float64(m.Failures) / float64(m.Deployments)
+	}
+	if len(leadTimes) > 0 {
+		sort.Float64s(leadTimes)
+		n := len(leadTimes)
+		if n%2 == 1 {
+			m.LeadTime = leadTimes[n/2]
+		} else {
+			m.LeadTime = (leadTimes[n/2-1] + leadTimes[n/2]) / 2
+		}
+	}
+	if len(restores) > 0 {
+		var sum float64
+		for _, r := range restores {
+			sum += r
+		}
+		m.TimeToRestore = sum / float64(len(restores))
+	}
+	return m
+}
@@ -0,0 +1,103 @@
+package sdk
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestComputeDORAMetrics(t *testing.T) {
+	from := time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 10)
+	at := func(h int) time.Time { return from.Add(time.Duration(h) * time.Hour) }
+
+	m := ComputeDORAMetrics(nil, from, to)
+	assert.Equal(t, int64(0), m.Deployments)
+	assert.Equal(t, 0.0, m.DeploymentFrequency)
+	assert.Equal(t, -1.0, m.LeadTime)
+	assert.Equal(t, -1.0, m.ChangeFailureRate)
+	assert.Equal(t, -1.0, m.TimeToRestore)
+
+	m = ComputeDORAMetrics([]Deployment{
+		{Status: StatusSuccess.String(), Done: at(1), LeadTime: 100},
+		{Status: StatusFail.String(), Done: at(10), LeadTime: 50},
+		{Status: StatusFail.String(), Done: at(11), LeadTime: 50},
+		{Status: StatusSuccess.String(), Done: at(12), LeadTime: 300},
+		{Status: StatusSuccess.String(), Done: at(20), LeadTime: -1},
+		{Status: StatusStopped.String(), Done: at(21)},
+		{Status: StatusSuccess.String(), Done: at(-1), LeadTime: 1000},
+	}, from, to)
+	assert.Equal(t, int64(5), m.Deployments)
+	assert.Equal(t, int64(2), m.Failures)
+	assert.Equal(t, 0.3, m.DeploymentFrequency)
+	assert.Equal(t, 200.0, m.LeadTime)
+	assert.Equal(t, 0.4, m.ChangeFailureRate)
+	assert.Equal(t, 7200.0, m.TimeToRestore)
+}
+
+func TestNewDeployment(t *testing.T) {
+	done := time.Unix(1500000000, 0)
+	nr := WorkflowNodeRun{
+		ID:      1,
+		Status:  StatusSuccess.String(),
+		Done:    done,
+		Commits: []VCSCommit{{Timestamp: 1499999000}, {Timestamp: 1499998000 * 1000}, {}},
+	}
+	d := NewDeployment(nr, 1, 2, 3)
+	assert.Equal(t, int64(2), d.ApplicationID)
+	assert.Equal(t, int64(3), d.EnvironmentID)
+	assert.Equal(t, 2000.0, d.LeadTime)
+
+	nr.Commits = nil
+	assert.Equal(t, -1.0, NewDeployment(nr, 1, 2, 3).LeadTime)
+}
+
+func TestDeploymentVersion(t *testing.T) {
+	nr := WorkflowNodeRun{Number: 12}
+	assert.Equal(t, "12", DeploymentVersion(nr))
+	nr.BuildParameters = []Parameter{{Name: "cds.semver", Value: "1.2.0+12"}}
+	assert.Equal(t, "1.2.0+12", DeploymentVersion(nr))
+	nr.VCSTag = "v1.2.0"
+	assert.Equal(t, "v1.2.0", DeploymentVersion(nr))
+	nr.BuildParameters = append(nr.BuildParameters, Parameter{Name: "cds.release.version", Value: "1.2.0"})
+	assert.Equal(t, "1.2.0", DeploymentVersion(nr))
+}
+
+func TestRollbackTarget(t *testing.T) {
+	ds := []Deployment{
+		{ID: 5, Version: "1.3.0", Status: StatusFail.String()},
+		{ID: 4, Version: "1.2.0", Status: StatusSuccess.String()},
+		{ID: 3, Version: "1.2.0", Status: StatusSuccess.String()},
+		{ID: 2, Version: "1.1.0", Status: StatusSuccess.String()},
+		{ID: 1, Version: "1.0.0", Status: StatusSuccess.String()},
+	}
+	d, err := RollbackTarget(ds, "")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), d.ID)
+
+	d, err = RollbackTarget(ds, "1.0.0")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), d.ID)
+
+	_, err = RollbackTarget(ds, "1.3.0")
+	assert.True(t, ErrorIs(err, ErrNotFound))
+	_, err = RollbackTarget(ds[:3], "")
+	assert.True(t, ErrorIs(err, ErrNotFound))
+}
+
+func TestDeploymentArtifacts(t *testing.T) {
+	wr := WorkflowRun{WorkflowNodeRuns: map[int64][]WorkflowNodeRun{
+		1: {
+			{SubNumber: 1, Artifacts: []WorkflowNodeRunArtifact{{Name: "app.tar.gz", Tag: "1.2.0", SHA512sum: "new"}}},
+			{SubNumber: 0, Artifacts: []WorkflowNodeRunArtifact{{Name: "app.tar.gz", Tag: "1.2.0", SHA512sum: "old"}}},
+		},
+		2: {
+			{Artifacts: []WorkflowNodeRunArtifact{{Name: "chart.tgz", Tag: "1.2.0", SHA512sum: "chart"}}},
+		},
+	}}
+	assert.Equal(t, []DeploymentArtifact{
+		{Name: "app.tar.gz", Tag: "1.2.0", SHA512sum: "new"},
+		{Name: "chart.tgz", Tag: "1.2.0", SHA512sum: "chart"},
+	}, DeploymentArtifacts(wr))
+}
@@ -1,10 +1,15 @@
 package sdk
 
 import (
+	"crypto/sha512"
+	"encoding/hex"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"net/http"
 	"os"
 	"path"
+	"path/filepath"
 )
 
 // URLGithubIssues contains a link to CDS Issues
@@ -13,6 +18,12 @@ const URLGithubIssues = "https://github.com/ovh/cds/issues"
 // URLGithubReleases contains a link to CDS Official Releases
 const URLGithubReleases = "https://github.com/ovh/cds/releases"
 
+// DownloadChecksum is the hexadecimal SHA512 checksum of a binary served by the API. It is requested separately from
+// the binary, so that a binary altered between the API and the client is detected.
+type DownloadChecksum struct {
+	SHA512 string `json:"sha512"`
+}
+
 // Download contains a association name of binary / arch-os available
 type Download struct {
 	Name    string   `json:"name"`
@@ -159,3 +170,56 @@ func CheckContentTypeBinary(resp *http.Response) error {
 	}
 	return nil
 }
+
+// FileSHA512 returns the hexadecimal SHA512 checksum of a file
+func FileSHA512(filename string) (string, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return "", WithStack(err)
+	}
+	defer f.Close()
+	h := sha512.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", WithStack(err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// DownloadVerifiedBinary downloads a binary in the target file. The binary is checked against the given checksum
+// before replacing the target file, it is never replaced by a corrupted or an unchecked binary.
+func DownloadVerifiedBinary(httpClient *http.Client, url, checksum, target string) error {
+	if checksum == "" {
+		return fmt.Errorf("cannot download %s: no checksum to check the binary", url)
+	}
+	resp, err := httpClient.Get(url)
+	if err != nil {
+		return WrapError(err, "cannot download %s", url)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("cannot download %s: HTTP %d", url, resp.StatusCode)
+	}
+	if err := CheckContentTypeBinary(resp); err != nil {
+		return err
+	}
+
+	tmp, err := ioutil.TempFile(filepath.Dir(target), "."+filepath.Base(target))
+	if err != nil {
+		return WithStack(err)
+	}
+	defer os.Remove(tmp.Name()) // nolint
+
+	h := sha512.New()
+	_, err = io.Copy(io.MultiWriter(tmp, h), resp.Body)
+	tmp.Close() // nolint
+	if err != nil {
+		return WrapError(err, "cannot download %s", url)
+	}
+	if sum := hex.EncodeToString(h.Sum(nil)); sum != checksum {
+		return fmt.Errorf("binary downloaded from %s has a wrong checksum. Expected: %s, got: %s", url, checksum, sum)
+	}
+	if err := os.Chmod(tmp.Name(), 0755); err != nil {
+		return WithStack(err)
+	}
+	return WithStack(os.Rename(tmp.Name(), target))
+}
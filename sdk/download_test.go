@@ -0,0 +1,53 @@
+package sdk
+
+import (
+	"crypto/sha512"
+	"encoding/hex"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDownloadVerifiedBinary(t *testing.T) {
+	binary := []byte("#!/bin/sh\necho worker\n")
+	sum := sha512.Sum512(binary)
+	checksum := hex.EncodeToString(sum[:])
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/octet-stream")
+		w.Write(binary) // nolint
+	}))
+	defer srv.Close()
+
+	dir, err := ioutil.TempDir("", "download")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir) // nolint
+	target := filepath.Join(dir, "worker")
+
+	assert.NoError(t, DownloadVerifiedBinary(srv.Client(), srv.URL, checksum, target))
+	content, err := ioutil.ReadFile(target)
+	assert.NoError(t, err)
+	assert.Equal(t, binary, content)
+	fi, err := os.Stat(target)
+	assert.NoError(t, err)
+	assert.Equal(t, os.FileMode(0755), fi.Mode().Perm())
+	s, err := FileSHA512(target)
+	assert.NoError(t, err)
+	assert.Equal(t, checksum, s)
+
+	// the target is not replaced by a corrupted or an unchecked binary
+	assert.NoError(t, ioutil.WriteFile(target, []byte("previous"), 0755))
+	assert.Error(t, DownloadVerifiedBinary(srv.Client(), srv.URL, checksum[1:]+"0", target))
+	assert.Error(t, DownloadVerifiedBinary(srv.Client(), srv.URL, "", target))
+	content, err = ioutil.ReadFile(target)
+	assert.NoError(t, err)
+	assert.Equal(t, "previous", string(content))
+	files, err := ioutil.ReadDir(dir)
+	assert.NoError(t, err)
+	assert.Len(t, files, 1)
+}
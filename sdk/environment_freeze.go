@@ -0,0 +1,109 @@
+package sdk
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gorhill/cronexpr"
+)
+
+// Types of environment freeze
+const (
+	EnvironmentFreezeLock   = "lock"
+	EnvironmentFreezeWindow = "window"
+	EnvironmentFreezeCron   = "cron"
+)
+
+// EnvironmentFreeze blocks the jobs of the deployment nodes targeting an environment. A lock blocks them until it is
+// removed, a window between two dates and a cron freeze during the given duration, in minutes, from each time matching
+// its cron expression.
+type EnvironmentFreeze struct {
+	ID              int64      `json:"id" db:"id" cli:"id,key"`
+	ProjectID       int64      `json:"project_id" db:"project_id" cli:"-"`
+	EnvironmentID   int64      `json:"environment_id" db:"environment_id" cli:"-"`
+	EnvironmentName string     `json:"environment_name" db:"-" cli:"environment"`
+	Type            string     `json:"type" db:"type" cli:"type"`
+	Reason          string     `json:"reason" db:"reason" cli:"reason"`
+	From            *time.Time `json:"from,omitempty" db:"from_date" cli:"from"`
+	To              *time.Time `json:"to,omitempty" db:"to_date" cli:"to"`
+	Cron            string     `json:"cron,omitempty" db:"cron" cli:"cron"`
+	Timezone        string     `json:"timezone,omitempty" db:"timezone" cli:"-"`
+	Duration        int64      `json:"duration,omitempty" db:"duration" cli:"duration"`
+	Author          string     `json:"author" db:"author" cli:"author"`
+	Created         time.Time  `json:"created" db:"created" cli:"-"`
+}
+
+// IsValid returns an error if the freeze has not the fields required by its type
+func (f EnvironmentFreeze) IsValid() error {
+	switch f.Type {
+	case EnvironmentFreezeLock:
+	case EnvironmentFreezeWindow:
+		if f.From == nil || f.To == nil || !f.From.Before(*f.To) {
+			return NewErrorFrom(ErrWrongRequest, "a freeze window needs a from date before its to date")
+		}
+	case EnvironmentFreezeCron:
+		if _, err := cronexpr.Parse(f.Cron); err != nil {
+			return NewErrorFrom(ErrWrongRequest, "invalid cron expression %s: %v", f.Cron, err)
+		}
+		if f.Duration <= 0 {
+			return NewErrorFrom(ErrWrongRequest, "a cron freeze needs a duration")
+		}
+		if _, err := time.LoadLocation(f.Timezone); err != nil {
+			return NewErrorFrom(ErrWrongRequest, "invalid timezone %s", f.Timezone)
+		}
+	default:
+		return NewErrorFrom(ErrWrongRequest, "invalid freeze type %s, expected %s, %s or %s", f.Type, EnvironmentFreezeLock, EnvironmentFreezeWindow, EnvironmentFreezeCron)
+	}
+	return nil
+}
+
+// ActiveUntil returns whether the freeze blocks the deployments at the given time, and its end. The end of a lock is
+// zero.
+func (f EnvironmentFreeze) ActiveUntil(now time.Time) (bool, time.Time) {
+	switch f.Type {
+	case EnvironmentFreezeLock:
+		return true, time.Time{}
+	case EnvironmentFreezeWindow:
+		if f.From != nil && f.To != nil && !now.Before(*f.From) && now.Before(*f.To) {
+			return true, *f.To
+		}
+	case EnvironmentFreezeCron:
+		expr, err := cronexpr.Parse(f.Cron)
+		if err != nil || f.Duration <= 0 {
+			return false, time.Time{}
+		}
+		loc, err := time.LoadLocation(f.Timezone)
+		if err != nil {
+			return false, time.Time{}
+		}
+		duration := time.Duration(f.Duration) * time.Minute
+		// the freeze is active if it started during the last duration
+		if start := expr.Next(now.In(loc).Add(-duration)); !start.IsZero() && !start.After(now) {
+			return true, start.Add(duration)
+		}
+	}
+	return false, time.Time{}
+}
+
+// Message returns the explanation of the freeze shown on the blocked jobs
+func (f EnvironmentFreeze) Message(now time.Time) string {
+	_, until := f.ActiveUntil(now)
+	s := fmt.Sprintf("environment %s is frozen by %s", f.EnvironmentName, f.Author)
+	if !until.IsZero() {
+		s += fmt.Sprintf(" until %s", until.UTC().Format(time.RFC3339))
+	}
+	if f.Reason != "" {
+		s += ": " + f.Reason
+	}
+	return s
+}
+
+// ActiveEnvironmentFreeze returns the first freeze blocking the deployments at the given time, if any
+func ActiveEnvironmentFreeze(freezes []EnvironmentFreeze, now time.Time) *EnvironmentFreeze {
+	for i := range freezes {
+		if active, _ := freezes[i].ActiveUntil(now); active {
+			return &freezes[i]
+		}
+	}
+	return nil
+}
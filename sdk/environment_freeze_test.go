@@ -0,0 +1,64 @@
+package sdk
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEnvironmentFreezeIsValid(t *testing.T) {
+	from := time.Date(2019, 12, 20, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 15)
+	assert.NoError(t, EnvironmentFreeze{Type: EnvironmentFreezeLock}.IsValid())
+	assert.NoError(t, EnvironmentFreeze{Type: EnvironmentFreezeWindow, From: &from, To: &to}.IsValid())
+	assert.Error(t, EnvironmentFreeze{Type: EnvironmentFreezeWindow, From: &to, To: &from}.IsValid())
+	assert.NoError(t, EnvironmentFreeze{Type: EnvironmentFreezeCron, Cron: "0 18 * * 5", Duration: 60 * 63, Timezone: "Europe/Paris"}.IsValid())
+	assert.Error(t, EnvironmentFreeze{Type: EnvironmentFreezeCron, Cron: "0 18 * * 5"}.IsValid())
+	assert.Error(t, EnvironmentFreeze{Type: EnvironmentFreezeCron, Cron: "every friday", Duration: 60}.IsValid())
+	assert.Error(t, EnvironmentFreeze{Type: "unknown"}.IsValid())
+}
+
+func TestEnvironmentFreezeActiveUntil(t *testing.T) {
+	from := time.Date(2019, 12, 20, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 15)
+
+	active, until := EnvironmentFreeze{Type: EnvironmentFreezeLock}.ActiveUntil(from)
+	assert.True(t, active)
+	assert.True(t, until.IsZero())
+
+	window := EnvironmentFreeze{Type: EnvironmentFreezeWindow, From: &from, To: &to}
+	active, until = window.ActiveUntil(from.Add(time.Hour))
+	assert.True(t, active)
+	assert.Equal(t, to, until)
+	active, _ = window.ActiveUntil(to)
+	assert.False(t, active)
+
+	// from friday 18:00 to monday 9:00
+	weekend := EnvironmentFreeze{Type: EnvironmentFreezeCron, Cron: "0 18 * * 5", Duration: 60 * 63}
+	friday := time.Date(2019, 12, 20, 18, 0, 0, 0, time.UTC)
+	active, _ = weekend.ActiveUntil(friday.Add(-time.Minute))
+	assert.False(t, active)
+	active, until = weekend.ActiveUntil(friday)
+	assert.True(t, active)
+	assert.Equal(t, friday.Add(63*time.Hour), until)
+	active, _ = weekend.ActiveUntil(friday.Add(62 * time.Hour))
+	assert.True(t, active)
+	active, _ = weekend.ActiveUntil(friday.Add(63 * time.Hour))
+	assert.False(t, active)
+
+	fs := []EnvironmentFreeze{window, weekend}
+	assert.Nil(t, ActiveEnvironmentFreeze(fs, time.Date(2020, 1, 7, 12, 0, 0, 0, time.UTC)))
+	if f := ActiveEnvironmentFreeze(fs, friday.Add(time.Hour)); assert.NotNil(t, f) {
+		assert.Equal(t, EnvironmentFreezeWindow, f.Type)
+	}
+}
+
+func TestEnvironmentFreezeMessage(t *testing.T) {
+	from := time.Date(2019, 12, 20, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 15)
+	f := EnvironmentFreeze{Type: EnvironmentFreezeWindow, EnvironmentName: "production", Author: "alice", Reason: "end of year", From: &from, To: &to}
+	assert.Equal(t, "environment production is frozen by alice until 2020-01-04T00:00:00Z: end of year", f.Message(from))
+	f = EnvironmentFreeze{Type: EnvironmentFreezeLock, EnvironmentName: "production", Author: "alice"}
+	assert.Equal(t, "environment production is frozen by alice", f.Message(from))
+}
@@ -177,6 +177,16 @@ var (
 	ErrGroupNotFoundInProject                 = Error{ID: 160, Status: http.StatusBadRequest}
 	ErrGroupNotFoundInWorkflow                = Error{ID: 161, Status: http.StatusBadRequest}
 	ErrWorkflowPermInsufficient               = Error{ID: 162, Status: http.StatusBadRequest}
+	ErrQueueQuotaExceeded                     = Error{ID: 163, Status: http.StatusForbidden}
+	ErrMFARequired                            = Error{ID: 164, Status: http.StatusUnauthorized}
+	ErrMFAInvalid                             = Error{ID: 165, Status: http.StatusUnauthorized}
+	ErrMFAEnrollmentRequired                  = Error{ID: 166, Status: http.StatusForbidden}
+	ErrMaintenance                            = Error{ID: 167, Status: http.StatusLocked}
+	ErrProjectArchived                        = Error{ID: 168, Status: http.StatusForbidden}
+	ErrOrganizationQuotaExceeded              = Error{ID: 169, Status: http.StatusTooManyRequests}
+	ErrEnvironmentFrozen                      = Error{ID: 170, Status: http.StatusLocked}
+	ErrWorkerDraining                         = Error{ID: 171, Status: http.StatusConflict}
+	ErrForkApprovalRequired                   = Error{ID: 172, Status: http.StatusForbidden}
 )
 
 var errorsAmericanEnglish = map[int]string{
@@ -336,6 +346,16 @@ var errorsAmericanEnglish = map[int]string{
 	ErrGroupNotFoundInProject.ID:                 "Cannot add this permission group on your workflow because this group is not already in the project's permissions",
 	ErrGroupNotFoundInWorkflow.ID:                "Cannot add this permission group on your workflow node because this group is not already your workflow's permissions",
 	ErrWorkflowPermInsufficient.ID:               "Cannot add this permission group on your workflow because you can't have less rights than rights in your project when you are in RWX",
+	ErrQueueQuotaExceeded.ID:                     "The project has reached its quota of jobs building at the same time",
+	ErrMFARequired.ID:                            "A second authentication factor is required",
+	ErrMFAInvalid.ID:                             "Invalid second authentication factor",
+	ErrMFAEnrollmentRequired.ID:                  "You have to enable two-factor authentication, it is required by one of your groups",
+	ErrMaintenance.ID:                            "CDS is in maintenance, no new run can be started",
+	ErrProjectArchived.ID:                        "The project is archived, it is read-only until it is restored by an administrator",
+	ErrOrganizationQuotaExceeded.ID:              "The quota of the organization of the project is exceeded",
+	ErrEnvironmentFrozen.ID:                      "The environment is frozen",
+	ErrWorkerDraining.ID:                         "The worker is draining, it does not take any new job",
+	ErrForkApprovalRequired.ID:                   "The runs of the pull requests from this fork are waiting for the approval of a maintainer",
 }
 
 var errorsFrench = map[int]string{
@@ -495,6 +515,16 @@ var errorsFrench = map[int]string{
 	ErrGroupNotFoundInProject.ID:                 "Impossible d'ajouter ce groupe dans vos permissions de workflow car ce groupe n'est pas présent dans les permissions de votre projet",
 	ErrGroupNotFoundInWorkflow.ID:                "Impossible d'ajouter ce groupe dans vos permissions de noeud du workflow car ce groupe n'est pas présent dans les permissions de votre workflow",
 	ErrWorkflowPermInsufficient.ID:               "Impossible d'ajouter ce groupe dans vos permissions du workflow car ce groupe a des droits inférieurs (< RWX) à celui du workflow",
+	ErrQueueQuotaExceeded.ID:                     "Le projet a atteint son quota de jobs en cours d'exécution simultanément",
+	ErrMFARequired.ID:                            "Un second facteur d'authentification est requis",
+	ErrMFAInvalid.ID:                             "Second facteur d'authentification invalide",
+	ErrMFAEnrollmentRequired.ID:                  "Vous devez activer l'authentification à deux facteurs, elle est requise par l'un de vos groupes",
+	ErrMaintenance.ID:                            "CDS est en maintenance, aucune nouvelle exécution ne peut être lancée",
+	ErrProjectArchived.ID:                        "Le projet est archivé, il est en lecture seule jusqu'à sa restauration par un administrateur",
+	ErrOrganizationQuotaExceeded.ID:              "Le quota de l'organisation du projet est dépassé",
+	ErrEnvironmentFrozen.ID:                      "L'environnement est gelé",
+	ErrWorkerDraining.ID:                         "Le worker est en cours d'arrêt, il ne prend plus de nouveau job",
+	ErrForkApprovalRequired.ID:                   "Les exécutions des pull requests de ce fork attendent l'approbation d'un mainteneur",
 }
 
 var errorsLanguages = []map[int]string{
@@ -1,6 +1,9 @@
 package sdk
 
 import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
 	"time"
 )
 
@@ -79,6 +82,10 @@ type EventRunWorkflowNode struct {
 	StagesSummary         []StageSummary            `json:"stages_summary"`
 	HookUUID              string                    `json:"hook_uuid"`
 	HookLog               string                    `json:"log,omitempty"`
+	// StatusContext is the name of the commit status if customized by the workflow
+	StatusContext string `json:"status_context,omitempty"`
+	// StatusAggregate is true if a single commit status is sent for the run
+	StatusAggregate bool `json:"status_aggregate,omitempty"`
 }
 
 // EventRunWorkflowOutgoingHook contains event data for a workflow outgoing hook run
@@ -137,3 +144,42 @@ type EventNotif struct {
 	Subject    string   `json:"subject,omitempty"`
 	Body       string   `json:"body,omitempty"`
 }
+
+// Status of the events of the outbox
+const (
+	EventOutboxStatusPending   = "pending"
+	EventOutboxStatusDelivered = "delivered"
+	EventOutboxStatusDead      = "dead"
+)
+
+// EventOutboxEntry is an event saved in database in the transaction of the change it reports, it is sent to
+// the event brokers until they acknowledge it or its max number of attempts is reached
+type EventOutboxEntry struct {
+	ID          int64     `json:"id" db:"id" cli:"id,key"`
+	Created     time.Time `json:"created" db:"created" cli:"created"`
+	EventType   string    `json:"type_event" db:"event_type" cli:"type"`
+	ProjectKey  string    `json:"project_key" db:"project_key" cli:"project"`
+	Event       Event     `json:"event" db:"event" cli:"-"`
+	Status      string    `json:"status" db:"status" cli:"status"`
+	Attempts    int       `json:"attempts" db:"attempts" cli:"attempts"`
+	NextAttempt time.Time `json:"next_attempt" db:"next_attempt" cli:"next_attempt"`
+	LastError   string    `json:"last_error" db:"last_error" cli:"last_error"`
+}
+
+// Value returns driver.Value from event.
+func (e Event) Value() (driver.Value, error) {
+	j, err := json.Marshal(e)
+	return j, WrapError(err, "cannot marshal Event")
+}
+
+// Scan event.
+func (e *Event) Scan(src interface{}) error {
+	if src == nil {
+		return nil
+	}
+	source, ok := src.([]byte)
+	if !ok {
+		return WithStack(fmt.Errorf("type assertion .([]byte) failed (%T)", src))
+	}
+	return WrapError(json.Unmarshal(source, e), "cannot unmarshal Event")
+}
@@ -0,0 +1,14 @@
+package sdk
+
+// EventAccessTokenDeny represents the event when a request made with an access token is denied by its restrictions
+type EventAccessTokenDeny struct {
+	AccessToken AccessTokenViolation
+}
+
+// AccessTokenViolation describes a request denied by the restrictions of an access token
+type AccessTokenViolation struct {
+	ID         string
+	Name       string
+	Reason     string
+	RemoteAddr string
+}
@@ -0,0 +1,17 @@
+package sdk
+
+// EventWorkerModelAdd represents the event when adding a worker model
+type EventWorkerModelAdd struct {
+	Model Model
+}
+
+// EventWorkerModelUpdate represents the event when updating a worker model
+type EventWorkerModelUpdate struct {
+	OldModel Model
+	NewModel Model
+}
+
+// EventWorkerModelDelete represents the event when deleting a worker model
+type EventWorkerModelDelete struct {
+	Model Model
+}
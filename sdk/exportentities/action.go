@@ -14,6 +14,7 @@ import (
 type Action struct {
 	Version      string                    `json:"version,omitempty" yaml:"version,omitempty"`
 	Name         string                    `json:"name,omitempty" yaml:"name,omitempty"`
+	Release      string                    `json:"release,omitempty" yaml:"release,omitempty"`
 	Description  string                    `json:"description,omitempty" yaml:"description,omitempty"`
 	Enabled      *bool                     `json:"enabled,omitempty" yaml:"enabled,omitempty"`
 	Parameters   map[string]ParameterValue `json:"parameters,omitempty" yaml:"parameters,omitempty"`
@@ -33,6 +34,7 @@ const (
 func NewAction(act sdk.Action) (a Action) {
 	a.Name = act.Name
 	a.Version = ActionVersion1
+	a.Release = act.Version
 	a.Description = act.Description
 	a.Parameters = make(map[string]ParameterValue, len(act.Parameters))
 	for k, v := range act.Parameters {
@@ -136,6 +138,10 @@ func newSteps(a sdk.Action) []Step {
 				if tag != nil {
 					artifactUploadArgs["tag"] = tag.Value
 				}
+				signingKey := sdk.ParameterFind(&act.Parameters, "signingKey")
+				if signingKey != nil && signingKey.Value != "" {
+					artifactUploadArgs["signingKey"] = signingKey.Value
+				}
 				s["artifactUpload"] = artifactUploadArgs
 			case sdk.ServeStaticFiles:
 				serveStaticFilesArgs := map[string]string{}
@@ -156,6 +162,23 @@ func newSteps(a sdk.Action) []Step {
 					serveStaticFilesArgs["static-key"] = staticKey.Value
 				}
 				s["serveStaticFiles"] = serveStaticFilesArgs
+			case sdk.ArtifactPush:
+				artifactPushArgs := map[string]string{}
+				for _, name := range []string{"path", "repository", "targetPath"} {
+					p := sdk.ParameterFind(&act.Parameters, name)
+					if p != nil {
+						artifactPushArgs[name] = p.Value
+					}
+				}
+				buildInfo := sdk.ParameterFind(&act.Parameters, "buildInfo")
+				if buildInfo != nil && buildInfo.Value == "false" {
+					artifactPushArgs["buildInfo"] = buildInfo.Value
+				}
+				buildName := sdk.ParameterFind(&act.Parameters, "buildName")
+				if buildName != nil && buildName.Value != "" {
+					artifactPushArgs["buildName"] = buildName.Value
+				}
+				s["artifactPush"] = artifactPushArgs
 			case sdk.GitCloneAction:
 				gitCloneArgs := map[string]string{}
 				branch := sdk.ParameterFind(&act.Parameters, "branch")
@@ -245,14 +268,156 @@ func newSteps(a sdk.Action) []Step {
 					releaseArgs["title"] = title.Value
 				}
 				s["release"] = releaseArgs
+			case sdk.PromoteAction:
+				promoteArgs := map[string]string{}
+				for _, name := range []string{"status", "comment", "integration"} {
+					p := sdk.ParameterFind(&act.Parameters, name)
+					if p != nil && p.Value != "" {
+						promoteArgs[name] = p.Value
+					}
+				}
+				cp := sdk.ParameterFind(&act.Parameters, "copy")
+				if cp != nil && cp.Value == "true" {
+					promoteArgs["copy"] = cp.Value
+				}
+				buildName := sdk.ParameterFind(&act.Parameters, "buildName")
+				if buildName != nil && buildName.Value != "" && buildName.Value != "{{.cds.project}}-{{.cds.workflow}}" {
+					promoteArgs["buildName"] = buildName.Value
+				}
+				buildNumber := sdk.ParameterFind(&act.Parameters, "buildNumber")
+				if buildNumber != nil && buildNumber.Value != "" && buildNumber.Value != "{{.cds.version}}" {
+					promoteArgs["buildNumber"] = buildNumber.Value
+				}
+				s["promote"] = promoteArgs
+			case sdk.DeployHelmAction:
+				deployHelmArgs := map[string]string{}
+				for _, name := range []string{"chart", "namespace", "values", "valuesFiles", "set", "repository", "version"} {
+					p := sdk.ParameterFind(&act.Parameters, name)
+					if p != nil && p.Value != "" {
+						deployHelmArgs[name] = p.Value
+					}
+				}
+				releaseName := sdk.ParameterFind(&act.Parameters, "releaseName")
+				if releaseName != nil && releaseName.Value != "" && releaseName.Value != "{{.cds.application}}" {
+					deployHelmArgs["releaseName"] = releaseName.Value
+				}
+				timeout := sdk.ParameterFind(&act.Parameters, "timeout")
+				if timeout != nil && timeout.Value != "" && timeout.Value != "5m" {
+					deployHelmArgs["timeout"] = timeout.Value
+				}
+				rollback := sdk.ParameterFind(&act.Parameters, "rollback")
+				if rollback != nil && rollback.Value == "false" {
+					deployHelmArgs["rollback"] = rollback.Value
+				}
+				s["deployHelm"] = deployHelmArgs
+			case sdk.TerraformPlanAction, sdk.TerraformApplyAction:
+				terraformArgs := map[string]string{}
+				for _, name := range []string{"vars", "varFiles", "workspace"} {
+					p := sdk.ParameterFind(&act.Parameters, name)
+					if p != nil && p.Value != "" {
+						terraformArgs[name] = p.Value
+					}
+				}
+				directory := sdk.ParameterFind(&act.Parameters, "directory")
+				if directory != nil && directory.Value != "" && directory.Value != "." {
+					terraformArgs["directory"] = directory.Value
+				}
+				planName := sdk.ParameterFind(&act.Parameters, "planName")
+				if planName != nil && planName.Value != "" && planName.Value != "terraform.tfplan" {
+					terraformArgs["planName"] = planName.Value
+				}
+				approval := sdk.ParameterFind(&act.Parameters, "approval")
+				if approval != nil && approval.Value == "false" {
+					terraformArgs["approval"] = approval.Value
+				}
+				if act.Name == sdk.TerraformPlanAction {
+					s["terraformPlan"] = terraformArgs
+				} else {
+					s["terraformApply"] = terraformArgs
+				}
+			case sdk.SSHDeployAction:
+				sshDeployArgs := map[string]string{}
+				for _, name := range []string{"privateKey", "source", "destination", "command", "user"} {
+					p := sdk.ParameterFind(&act.Parameters, name)
+					if p != nil && p.Value != "" {
+						sshDeployArgs[name] = p.Value
+					}
+				}
+				hosts := sdk.ParameterFind(&act.Parameters, "hosts")
+				if hosts != nil && hosts.Value != "" && hosts.Value != "{{.cds.env.hosts}}" {
+					sshDeployArgs["hosts"] = hosts.Value
+				}
+				knownHosts := sdk.ParameterFind(&act.Parameters, "knownHosts")
+				if knownHosts != nil && knownHosts.Value != "" && knownHosts.Value != "{{.cds.env.known_hosts}}" {
+					sshDeployArgs["knownHosts"] = knownHosts.Value
+				}
+				rsyncOpts := sdk.ParameterFind(&act.Parameters, "rsyncOpts")
+				if rsyncOpts != nil && rsyncOpts.Value != "" && rsyncOpts.Value != "-az" {
+					sshDeployArgs["rsyncOpts"] = rsyncOpts.Value
+				}
+				s["sshDeploy"] = sshDeployArgs
+			case sdk.CoverageUploadAction:
+				coverageUploadArgs := map[string]string{}
+				for _, name := range []string{"path", "format", "minimum"} {
+					p := sdk.ParameterFind(&act.Parameters, name)
+					if p != nil && p.Value != "" {
+						coverageUploadArgs[name] = p.Value
+					}
+				}
+				s["coverageUpload"] = coverageUploadArgs
+			case sdk.GenerateSBOMAction:
+				generateSBOMArgs := map[string]string{}
+				for _, name := range []string{"name", "output"} {
+					p := sdk.ParameterFind(&act.Parameters, name)
+					if p != nil && p.Value != "" {
+						generateSBOMArgs[name] = p.Value
+					}
+				}
+				source := sdk.ParameterFind(&act.Parameters, "source")
+				if source != nil && source.Value != "" && source.Value != "." {
+					generateSBOMArgs["source"] = source.Value
+				}
+				format := sdk.ParameterFind(&act.Parameters, "format")
+				if format != nil && format.Value != "" && format.Value != sdk.SBOMFormatSPDX {
+					generateSBOMArgs["format"] = format.Value
+				}
+				s["generateSBOM"] = generateSBOMArgs
+			case sdk.CheckCanaryMetricsAction:
+				checkCanaryArgs := map[string]string{}
+				checks := sdk.ParameterFind(&act.Parameters, "checks")
+				if checks != nil && checks.Value != "" {
+					checkCanaryArgs["checks"] = checks.Value
+				}
+				defaults := map[string]string{"duration": "10m", "interval": "1m", "failures": "1", "rollback": "false"}
+				for _, name := range []string{"duration", "interval", "failures", "rollback"} {
+					p := sdk.ParameterFind(&act.Parameters, name)
+					if p != nil && p.Value != "" && p.Value != defaults[name] {
+						checkCanaryArgs[name] = p.Value
+					}
+				}
+				s["checkCanaryMetrics"] = checkCanaryArgs
 			case sdk.JUnitAction:
 				path := sdk.ParameterFind(&act.Parameters, "path")
 				if path != nil {
 					s["jUnitReport"] = path.Value
 				}
 			case sdk.CheckoutApplicationAction:
+				// the options are exported with the directory when they are not the default ones
+				checkoutArgs := map[string]string{}
+				defaults := map[string]string{"depth": "50", "submodules": "true", "lfs": "false", "sparse": ""}
+				for _, name := range []string{"depth", "submodules", "lfs", "sparse"} {
+					p := sdk.ParameterFind(&act.Parameters, name)
+					if p != nil && p.Value != "" && p.Value != defaults[name] {
+						checkoutArgs[name] = p.Value
+					}
+				}
 				directory := sdk.ParameterFind(&act.Parameters, "directory")
-				if directory != nil {
+				if len(checkoutArgs) > 0 {
+					if directory != nil {
+						checkoutArgs["directory"] = directory.Value
+					}
+					s["checkout"] = checkoutArgs
+				} else if directory != nil {
 					s["checkout"] = directory.Value
 				}
 			case sdk.DeployApplicationAction:
@@ -265,7 +430,7 @@ func newSteps(a sdk.Action) []Step {
 					args[p.Name] = p.Value
 				}
 			}
-			s[act.Name] = args
+			s[sdk.ActionReference(act.Name, act.VersionConstraint)] = args
 		}
 		res[i] = s
 	}
@@ -348,10 +513,13 @@ func (s Step) AsAction() (*sdk.Action, bool, error) {
 		return nil, true, sdk.WrapError(err, "AsAction.decode.Malformatted Step")
 	}
 
-	a, err := sdk.NewStepDefault(actionName, argss)
+	// The version of the action can be given after its name (ie. "myAction@v1")
+	name, constraint := sdk.ParseActionReference(actionName)
+	a, err := sdk.NewStepDefault(name, argss)
 	if err != nil {
 		return nil, true, err
 	}
+	a.VersionConstraint = constraint
 
 	a.Enabled, err = s.IsFlagged("enabled")
 	a.StepName, err = s.Name()
@@ -546,6 +714,49 @@ func (s Step) AsServeStaticFiles() (*sdk.Action, bool, error) {
 	return &a, true, nil
 }
 
+//AsArtifactPush returns the step a sdk.Action
+func (s Step) AsArtifactPush() (*sdk.Action, bool, error) {
+	if !s.IsValid() {
+		return nil, false, fmt.Errorf("AsArtifactPush.Malformatted Step")
+	}
+
+	bI, ok := s["artifactPush"]
+	if !ok {
+		return nil, false, nil
+	}
+
+	if reflect.ValueOf(bI).Kind() != reflect.Map {
+		return nil, false, nil
+	}
+
+	var argss map[string]string
+	if err := mapstructure.Decode(bI, &argss); err != nil {
+		return nil, true, sdk.WrapError(err, "AsArtifactPush.Malformatted Step")
+	}
+
+	a := sdk.NewStepArtifactPush(argss)
+
+	var err error
+	a.StepName, err = s.Name()
+	if err != nil {
+		return nil, true, err
+	}
+	a.Enabled, err = s.IsFlagged("enabled")
+	if err != nil {
+		return nil, true, err
+	}
+	a.Optional, err = s.IsFlagged("optional")
+	if err != nil {
+		return nil, true, err
+	}
+	a.AlwaysExecuted, err = s.IsFlagged("always_executed")
+	if err != nil {
+		return nil, true, err
+	}
+
+	return &a, true, nil
+}
+
 //AsArtifactDownload returns the step a sdk.Action
 func (s Step) AsArtifactDownload() (*sdk.Action, bool, error) {
 	if !s.IsValid() {
@@ -594,11 +805,25 @@ func (s Step) AsCheckoutApplication() (*sdk.Action, bool, error) {
 		return nil, false, nil
 	}
 
-	bS, ok := bI.(string)
-	if !ok {
-		return nil, true, fmt.Errorf("Malformatted Step : checkout must be a string")
+	var a sdk.Action
+	if reflect.ValueOf(bI).Kind() == reflect.Map {
+		var argsi map[string]interface{}
+		if err := mapstructure.Decode(bI, &argsi); err != nil {
+			return nil, true, sdk.WrapError(err, "Malformatted Step : checkout must be a string or a map of options")
+		}
+		// the options can be written as numbers or booleans
+		argss := make(map[string]string, len(argsi))
+		for k, v := range argsi {
+			argss[k] = fmt.Sprintf("%v", v)
+		}
+		a = sdk.NewStepCheckoutApplication(argss)
+	} else {
+		bS, ok := bI.(string)
+		if !ok {
+			return nil, true, fmt.Errorf("Malformatted Step : checkout must be a string or a map of options")
+		}
+		a = sdk.NewCheckoutApplication(bS)
 	}
-	a := sdk.NewCheckoutApplication(bS)
 
 	var err error
 	a.StepName, err = s.Name()
@@ -724,6 +949,7 @@ func (s Step) Name() (string, error) {
 func (act *Action) Action() (*sdk.Action, error) {
 	a := new(sdk.Action)
 	a.Name = act.Name
+	a.Version = act.Release
 	a.Type = sdk.DefaultAction
 	a.Description = act.Description
 
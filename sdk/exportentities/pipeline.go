@@ -447,6 +447,11 @@ func computeStep(s Step) (a *sdk.Action, e error) {
 		return
 	}
 
+	a, ok, e = s.AsArtifactPush()
+	if ok {
+		return
+	}
+
 	a, ok, e = s.AsJUnitReport()
 	if ok {
 		return
@@ -576,6 +576,39 @@ steps:
 	assert.Len(t, p.Stages[0].Jobs[0].Action.Actions[2].Parameters, 1)
 }
 
+func Test_ImportPipelineWithArtifactPush(t *testing.T) {
+	in := `name: publish
+steps:
+- artifactPush:
+    path: dist/*.tar.gz
+    repository: releases
+    targetPath: '{{.cds.application}}/{{.cds.version}}'
+    buildInfo: "false"
+`
+
+	payload := &Pipeline{}
+	test.NoError(t, yaml.Unmarshal([]byte(in), payload))
+
+	p, err := payload.Pipeline()
+	test.NoError(t, err)
+
+	assert.Len(t, p.Stages[0].Jobs[0].Action.Actions, 1)
+	a := p.Stages[0].Jobs[0].Action.Actions[0]
+	assert.Equal(t, sdk.ArtifactPush, a.Name)
+	assert.Equal(t, sdk.BuiltinAction, a.Type)
+	assert.Equal(t, "releases", sdk.ParameterValue(a.Parameters, "repository"))
+	assert.Equal(t, "{{.cds.application}}/{{.cds.version}}", sdk.ParameterValue(a.Parameters, "targetPath"))
+	assert.Equal(t, "false", sdk.ParameterValue(a.Parameters, "buildInfo"))
+
+	steps := newSteps(p.Stages[0].Jobs[0].Action)
+	assert.Equal(t, map[string]string{
+		"path":       "dist/*.tar.gz",
+		"repository": "releases",
+		"targetPath": "{{.cds.application}}/{{.cds.version}}",
+		"buildInfo":  "false",
+	}, steps[0]["artifactPush"])
+}
+
 func Test_ImportPipelineWithCheckout(t *testing.T) {
 	in := `name: build-all-images
 steps:
@@ -593,6 +626,61 @@ steps:
 	assert.Len(t, p.Stages[0].Jobs[0].Action.Actions[0].Parameters, 1)
 }
 
+func Test_ImportPipelineWithCheckoutOptions(t *testing.T) {
+	in := `name: build-monorepo
+steps:
+- checkout:
+    directory: '{{.cds.workspace}}'
+    depth: 1
+    lfs: true
+    sparse: engine,sdk
+`
+
+	payload := &Pipeline{}
+	test.NoError(t, yaml.Unmarshal([]byte(in), payload))
+
+	p, err := payload.Pipeline()
+	test.NoError(t, err)
+
+	a := p.Stages[0].Jobs[0].Action.Actions[0]
+	assert.Equal(t, sdk.CheckoutApplicationAction, a.Name)
+	assert.Equal(t, "1", sdk.ParameterValue(a.Parameters, "depth"))
+	assert.Equal(t, "true", sdk.ParameterValue(a.Parameters, "lfs"))
+	assert.Equal(t, "engine,sdk", sdk.ParameterValue(a.Parameters, "sparse"))
+
+	// the default options are not exported
+	a.Parameters = append(a.Parameters, sdk.Parameter{Name: "submodules", Value: "true"})
+	steps := newSteps(p.Stages[0].Jobs[0].Action)
+	assert.Equal(t, map[string]string{
+		"directory": "{{.cds.workspace}}",
+		"depth":     "1",
+		"lfs":       "true",
+		"sparse":    "engine,sdk",
+	}, steps[0]["checkout"])
+}
+
+func Test_ImportPipelineWithActionVersion(t *testing.T) {
+	in := `name: build-all-images
+steps:
+- myAction@v1.2:
+    param: value
+`
+
+	payload := &Pipeline{}
+	test.NoError(t, yaml.Unmarshal([]byte(in), payload))
+
+	p, err := payload.Pipeline()
+	test.NoError(t, err)
+
+	step := p.Stages[0].Jobs[0].Action.Actions[0]
+	assert.Equal(t, "myAction", step.Name)
+	assert.Equal(t, "v1.2", step.VersionConstraint)
+
+	// The version constraint is kept on export
+	exported := NewPipeline(*p)
+	assert.Contains(t, exported.Steps[0], "myAction@v1.2")
+}
+
 func Test_IsFlagged(t *testing.T) {
 	testc := []struct {
 		flag     string
@@ -0,0 +1,34 @@
+package exportentities
+
+// Project is the export of a project with its settings, it is the project.yml file of a project archive
+type Project struct {
+	Version      string                        `json:"version" yaml:"version"`
+	Key          string                        `json:"key" yaml:"key"`
+	Name         string                        `json:"name" yaml:"name"`
+	Description  string                        `json:"description,omitempty" yaml:"description,omitempty"`
+	Variables    map[string]VariableValue      `json:"variables,omitempty" yaml:"variables,omitempty"`
+	Keys         map[string]KeyValue           `json:"keys,omitempty" yaml:"keys,omitempty"`
+	Permissions  map[string]int                `json:"permissions,omitempty" yaml:"permissions,omitempty"`
+	Integrations map[string]ProjectIntegration `json:"integrations,omitempty" yaml:"integrations,omitempty"`
+	Metadata     map[string]string             `json:"metadata,omitempty" yaml:"metadata,omitempty"`
+}
+
+// ProjectIntegration is the export of an integration of a project
+type ProjectIntegration struct {
+	Model  string                   `json:"model" yaml:"model"`
+	Config map[string]VariableValue `json:"config,omitempty" yaml:"config,omitempty"`
+}
+
+// There are the supported versions of the project archives
+const (
+	ProjectVersion1 = "v1.0"
+)
+
+// Files of a project archive
+const (
+	ProjectArchiveFile            = "project.yml"
+	ProjectArchiveEnvironmentsDir = "environments"
+	ProjectArchiveApplicationsDir = "applications"
+	ProjectArchivePipelinesDir    = "pipelines"
+	ProjectArchiveWorkflowsDir    = "workflows"
+)
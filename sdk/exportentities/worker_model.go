@@ -20,8 +20,11 @@ type WorkerModel struct {
 	PreCmd        string            `json:"pre_cmd,omitempty" yaml:"pre_cmd,omitempty"`
 	Cmd           string            `json:"cmd,omitempty" yaml:"cmd,omitempty"`
 	PostCmd       string            `json:"post_cmd,omitempty" yaml:"post_cmd,omitempty"`
+	Preemptible   bool              `json:"preemptible,omitempty" yaml:"preemptible,omitempty"`
+	Registry      string            `json:"registry,omitempty" yaml:"registry,omitempty"`
 	Restricted    bool              `json:"restricted,omitempty" yaml:"restricted,omitempty"`
 	IsDeprecated  bool              `json:"is_deprecated,omitempty" yaml:"is_deprecated,omitempty"`
+	Parent        string            `json:"parent,omitempty" yaml:"parent,omitempty"`
 }
 
 // NewWorkerModel creates an exportentities WorkerModel from a struct sdk.Model
@@ -37,6 +40,7 @@ func NewWorkerModel(wm sdk.Model) WorkerModel {
 		Description:   wm.Description,
 		Restricted:    wm.Restricted,
 		Image:         wm.Image,
+		Parent:        wm.Parent,
 	}
 
 	switch wm.Type {
@@ -45,12 +49,14 @@ func NewWorkerModel(wm sdk.Model) WorkerModel {
 		model.Image = wm.ModelDocker.Image
 		model.Cmd = wm.ModelDocker.Cmd
 		model.Envs = wm.ModelDocker.Envs
-	case sdk.VSphere, sdk.Openstack:
+		model.Registry = wm.ModelDocker.Registry
+	case sdk.VSphere, sdk.Openstack, sdk.AWS:
 		model.Flavor = wm.ModelVirtualMachine.Flavor
 		model.Image = wm.ModelVirtualMachine.Image
 		model.PreCmd = wm.ModelVirtualMachine.PreCmd
 		model.Cmd = wm.ModelVirtualMachine.Cmd
 		model.PostCmd = wm.ModelVirtualMachine.PostCmd
+		model.Preemptible = wm.ModelVirtualMachine.Preemptible
 	}
 
 	return model
@@ -72,23 +78,26 @@ func (wm WorkerModel) GetWorkerModel() (sdk.Model, error) {
 		Provision:     wm.Provision,
 		Description:   wm.Description,
 		Restricted:    wm.Restricted,
+		Parent:        wm.Parent,
 	}
 
 	switch wm.Type {
 	case sdk.Docker:
 		model.ModelDocker = sdk.ModelDocker{
-			Shell: wm.Shell,
-			Image: wm.Image,
-			Cmd:   wm.Cmd,
-			Envs:  wm.Envs,
+			Shell:    wm.Shell,
+			Image:    wm.Image,
+			Cmd:      wm.Cmd,
+			Envs:     wm.Envs,
+			Registry: wm.Registry,
 		}
-	case sdk.VSphere, sdk.Openstack:
+	case sdk.VSphere, sdk.Openstack, sdk.AWS:
 		model.ModelVirtualMachine = sdk.ModelVirtualMachine{
-			Image:   wm.Image,
-			Flavor:  wm.Flavor,
-			Cmd:     wm.Cmd,
-			PostCmd: wm.PostCmd,
-			PreCmd:  wm.PreCmd,
+			Image:       wm.Image,
+			Flavor:      wm.Flavor,
+			Cmd:         wm.Cmd,
+			PostCmd:     wm.PostCmd,
+			PreCmd:      wm.PreCmd,
+			Preemptible: wm.Preemptible,
 		}
 	}
 
@@ -105,6 +114,14 @@ func (wm *WorkerModel) IsValid() error {
 		return sdk.NewErrorFrom(sdk.ErrWrongRequest, "Error: group is not provided")
 	}
 
+	// the image and the commands of a model extending a base model can be inherited, they are checked by the API
+	if wm.Parent != "" {
+		switch wm.Type {
+		case sdk.Docker, sdk.Openstack, sdk.AWS, sdk.VSphere:
+			return nil
+		}
+	}
+
 	switch wm.Type {
 	case sdk.Docker:
 		if wm.Image == "" {
@@ -130,6 +147,18 @@ func (wm *WorkerModel) IsValid() error {
 				return sdk.NewErrorFrom(sdk.ErrWrongRequest, "Error: Openstack command not provided")
 			}
 		}
+	case sdk.AWS:
+		if wm.Image == "" {
+			return sdk.NewErrorFrom(sdk.ErrWrongRequest, "Error: AWS AMI not provided")
+		}
+		if wm.Flavor == "" {
+			return sdk.NewErrorFrom(sdk.ErrWrongRequest, "Error: AWS instance type not provided")
+		}
+		if wm.PatternName == "" {
+			if wm.Cmd == "" {
+				return sdk.NewErrorFrom(sdk.ErrWrongRequest, "Error: AWS command not provided")
+			}
+		}
 	case sdk.VSphere:
 		if wm.Image == "" {
 			return sdk.NewErrorFrom(sdk.ErrWrongRequest, "Error: VSphere image not provided")
@@ -48,6 +48,9 @@ type Workflow struct {
 	Metadata               map[string]string              `json:"metadata,omitempty" yaml:"metadata,omitempty"`
 	PurgeTags              []string                       `json:"purge_tags,omitempty" yaml:"purge_tags,omitempty"`
 	HistoryLength          *int64                         `json:"history_length,omitempty" yaml:"history_length,omitempty"`
+	ForkPolicy             *sdk.WorkflowForkPolicy        `json:"fork_policy,omitempty" yaml:"fork_policy,omitempty"`
+	AutoCancel             bool                           `json:"auto_cancel,omitempty" yaml:"auto_cancel,omitempty"`
+	CommitStatus           *sdk.WorkflowCommitStatus      `json:"commit_status,omitempty" yaml:"commit_status,omitempty"`
 	Notifications          []NotificationEntry            `json:"notify,omitempty" yaml:"notify,omitempty"`               // This is used when the workflow have only one pipeline
 	MapNotifications       map[string][]NotificationEntry `json:"notifications,omitempty" yaml:"notifications,omitempty"` // This is used when the workflow have more than one pipeline
 }
@@ -148,6 +151,8 @@ func (w WorkflowPulled) Tar(writer io.Writer) error {
 type NodeEntry struct {
 	ID                     int64                       `json:"-" yaml:"-"`
 	DependsOn              []string                    `json:"depends_on,omitempty" yaml:"depends_on,omitempty"`
+	JoinStrategy           string                      `json:"join_strategy,omitempty" yaml:"join_strategy,omitempty"`
+	JoinMin                int                         `json:"join_min,omitempty" yaml:"join_min,omitempty"`
 	Conditions             *sdk.WorkflowNodeConditions `json:"conditions,omitempty" yaml:"conditions,omitempty"`
 	When                   []string                    `json:"when,omitempty" yaml:"when,omitempty"` //This is used only for manual and success condition
 	PipelineName           string                      `json:"pipeline,omitempty" yaml:"pipeline,omitempty"`
@@ -191,6 +196,7 @@ func craftNodeEntry(w sdk.Workflow, n sdk.Node) (NodeEntry, error) {
 			for _, t := range node.Triggers {
 				if t.ChildNode.Name == n.Name {
 					if node.Type == sdk.NodeTypeJoin && !joinAsNode(node) {
+						entry.setJoinStrategy(node.JoinStrategy)
 						for _, jp := range node.JoinContext {
 							parentNode := w.WorkflowData.NodeByRef(jp.ParentName)
 							if parentNode == nil {
@@ -208,6 +214,7 @@ func craftNodeEntry(w sdk.Workflow, n sdk.Node) (NodeEntry, error) {
 		for _, jc := range n.JoinContext {
 			ancestors = append(ancestors, jc.ParentName)
 		}
+		entry.setJoinStrategy(n.JoinStrategy)
 	}
 
 	sort.Strings(ancestors)
@@ -233,10 +240,11 @@ func craftNodeEntry(w sdk.Workflow, n sdk.Node) (NodeEntry, error) {
 			}
 		}
 
-		if len(conditions) > 0 || n.Context.Conditions.LuaScript != "" {
+		if len(conditions) > 0 || n.Context.Conditions.LuaScript != "" || n.Context.Conditions.Priority != nil {
 			entry.Conditions = &sdk.WorkflowNodeConditions{
 				PlainConditions: conditions,
 				LuaScript:       n.Context.Conditions.LuaScript,
+				Priority:        n.Context.Conditions.Priority,
 			}
 		}
 
@@ -372,6 +380,16 @@ func NewWorkflow(w sdk.Workflow, opts ...WorkflowOptions) (Workflow, error) {
 
 	exportedWorkflow.PurgeTags = w.PurgeTags
 
+	if !w.ForkPolicy.IsDefault() || len(w.ForkPolicy.AllowedSecrets) > 0 {
+		forkPolicy := w.ForkPolicy
+		exportedWorkflow.ForkPolicy = &forkPolicy
+	}
+	exportedWorkflow.AutoCancel = w.AutoCancel
+	if !w.CommitStatus.IsDefault() {
+		commitStatus := w.CommitStatus
+		exportedWorkflow.CommitStatus = &commitStatus
+	}
+
 	nodes := w.WorkflowData.Array()
 
 	if len(nodes) == 1 {
@@ -385,7 +403,7 @@ func NewWorkflow(w sdk.Workflow, opts ...WorkflowOptions) (Workflow, error) {
 		exportedWorkflow.EnvironmentName = entry.EnvironmentName
 		exportedWorkflow.ProjectIntegrationName = entry.ProjectIntegrationName
 		exportedWorkflow.DependsOn = entry.DependsOn
-		if entry.Conditions != nil && (len(entry.Conditions.PlainConditions) > 0 || entry.Conditions.LuaScript != "") {
+		if entry.Conditions != nil && (len(entry.Conditions.PlainConditions) > 0 || entry.Conditions.LuaScript != "" || entry.Conditions.Priority != nil) {
 			exportedWorkflow.When = entry.When
 			exportedWorkflow.Conditions = entry.Conditions
 		}
@@ -578,6 +596,13 @@ func (w Workflow) GetWorkflow() (*sdk.Workflow, error) {
 		return nil, sdk.WrapError(err, "Unable to check dependencies")
 	}
 	wf.PurgeTags = w.PurgeTags
+	if w.ForkPolicy != nil {
+		wf.ForkPolicy = *w.ForkPolicy
+	}
+	wf.AutoCancel = w.AutoCancel
+	if w.CommitStatus != nil {
+		wf.CommitStatus = *w.CommitStatus
+	}
 	if len(w.Metadata) > 0 {
 		wf.Metadata = make(map[string]string, len(w.Metadata))
 		for k, v := range w.Metadata {
@@ -645,6 +670,24 @@ func (w Workflow) GetWorkflow() (*sdk.Workflow, error) {
 	return wf, nil
 }
 
+func (e *NodeEntry) setJoinStrategy(s *sdk.NodeJoinStrategy) {
+	if s.GetType() == sdk.JoinStrategyAllSuccess {
+		return
+	}
+	e.JoinStrategy = s.Type
+	e.JoinMin = s.Min
+}
+
+func (e *NodeEntry) joinStrategy() *sdk.NodeJoinStrategy {
+	if e.JoinStrategy == "" {
+		return nil
+	}
+	return &sdk.NodeJoinStrategy{
+		Type: e.JoinStrategy,
+		Min:  e.JoinMin,
+	}
+}
+
 func (e *NodeEntry) getNode(name string, w *sdk.Workflow) (*sdk.Node, error) {
 	node := &sdk.Node{
 		Name: name,
@@ -668,6 +711,7 @@ func (e *NodeEntry) getNode(name string, w *sdk.Workflow) (*sdk.Node, error) {
 		for _, parent := range e.DependsOn {
 			node.JoinContext = append(node.JoinContext, sdk.NodeJoin{ParentName: parent})
 		}
+		node.JoinStrategy = e.joinStrategy()
 	}
 
 	if len(e.Permissions) > 0 {
@@ -873,9 +917,10 @@ func (e *NodeEntry) processNodeAncestors(name string, w *sdk.Workflow) (bool, er
 			})
 		}
 		join = &sdk.Node{
-			JoinContext: joinContext,
-			Type:        sdk.NodeTypeJoin,
-			Ref:         fmt.Sprintf("fakeRef%d", e.ID),
+			JoinContext:  joinContext,
+			JoinStrategy: e.joinStrategy(),
+			Type:         sdk.NodeTypeJoin,
+			Ref:          fmt.Sprintf("fakeRef%d", e.ID),
 		}
 		appendJoin = true
 	}
@@ -14,21 +14,21 @@ import (
 
 func TestWorkflow_checkDependencies(t *testing.T) {
 	type fields struct {
-		Name                string
-		Description         string
-		Version             string
-		Workflow            map[string]NodeEntry
-		Hooks               map[string][]HookEntry
-		DependsOn           []string
-		Conditions          *sdk.WorkflowNodeConditions
-		When                []string
-		PipelineName        string
-		ApplicationName     string
-		EnvironmentName     string
+		Name                   string
+		Description            string
+		Version                string
+		Workflow               map[string]NodeEntry
+		Hooks                  map[string][]HookEntry
+		DependsOn              []string
+		Conditions             *sdk.WorkflowNodeConditions
+		When                   []string
+		PipelineName           string
+		ApplicationName        string
+		EnvironmentName        string
 		ProjectIntegrationName string
-		PipelineHooks       []HookEntry
-		Permissions         map[string]int
-		HistoryLength       int64
+		PipelineHooks          []HookEntry
+		Permissions            map[string]int
+		HistoryLength          int64
 	}
 	tests := []struct {
 		name    string
@@ -93,20 +93,20 @@ func TestWorkflow_checkDependencies(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			w := Workflow{
-				Name:                tt.fields.Name,
-				Description:         tt.fields.Description,
-				Version:             tt.fields.Version,
-				Workflow:            tt.fields.Workflow,
-				Hooks:               tt.fields.Hooks,
-				DependsOn:           tt.fields.DependsOn,
-				Conditions:          tt.fields.Conditions,
-				When:                tt.fields.When,
-				PipelineName:        tt.fields.PipelineName,
-				ApplicationName:     tt.fields.ApplicationName,
-				EnvironmentName:     tt.fields.EnvironmentName,
+				Name:                   tt.fields.Name,
+				Description:            tt.fields.Description,
+				Version:                tt.fields.Version,
+				Workflow:               tt.fields.Workflow,
+				Hooks:                  tt.fields.Hooks,
+				DependsOn:              tt.fields.DependsOn,
+				Conditions:             tt.fields.Conditions,
+				When:                   tt.fields.When,
+				PipelineName:           tt.fields.PipelineName,
+				ApplicationName:        tt.fields.ApplicationName,
+				EnvironmentName:        tt.fields.EnvironmentName,
 				ProjectIntegrationName: tt.fields.ProjectIntegrationName,
-				PipelineHooks:       tt.fields.PipelineHooks,
-				Permissions:         tt.fields.Permissions,
+				PipelineHooks:          tt.fields.PipelineHooks,
+				Permissions:            tt.fields.Permissions,
 			}
 			if err := w.checkDependencies(); (err != nil) != tt.wantErr {
 				t.Errorf("Workflow.checkDependencies() error = %v, wantErr %v", err, tt.wantErr)
@@ -117,19 +117,19 @@ func TestWorkflow_checkDependencies(t *testing.T) {
 
 func TestWorkflow_checkValidity(t *testing.T) {
 	type fields struct {
-		Name                string
-		Version             string
-		Workflow            map[string]NodeEntry
-		Hooks               map[string][]HookEntry
-		DependsOn           []string
-		Conditions          *sdk.WorkflowNodeConditions
-		When                []string
-		PipelineName        string
-		ApplicationName     string
-		EnvironmentName     string
+		Name                   string
+		Version                string
+		Workflow               map[string]NodeEntry
+		Hooks                  map[string][]HookEntry
+		DependsOn              []string
+		Conditions             *sdk.WorkflowNodeConditions
+		When                   []string
+		PipelineName           string
+		ApplicationName        string
+		EnvironmentName        string
 		ProjectIntegrationName string
-		PipelineHooks       []HookEntry
-		Permissions         map[string]int
+		PipelineHooks          []HookEntry
+		Permissions            map[string]int
 	}
 	tests := []struct {
 		name    string
@@ -178,19 +178,19 @@ func TestWorkflow_checkValidity(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			w := Workflow{
-				Name:                tt.fields.Name,
-				Version:             tt.fields.Version,
-				Workflow:            tt.fields.Workflow,
-				Hooks:               tt.fields.Hooks,
-				DependsOn:           tt.fields.DependsOn,
-				Conditions:          tt.fields.Conditions,
-				When:                tt.fields.When,
-				PipelineName:        tt.fields.PipelineName,
-				ApplicationName:     tt.fields.ApplicationName,
-				EnvironmentName:     tt.fields.EnvironmentName,
+				Name:                   tt.fields.Name,
+				Version:                tt.fields.Version,
+				Workflow:               tt.fields.Workflow,
+				Hooks:                  tt.fields.Hooks,
+				DependsOn:              tt.fields.DependsOn,
+				Conditions:             tt.fields.Conditions,
+				When:                   tt.fields.When,
+				PipelineName:           tt.fields.PipelineName,
+				ApplicationName:        tt.fields.ApplicationName,
+				EnvironmentName:        tt.fields.EnvironmentName,
 				ProjectIntegrationName: tt.fields.ProjectIntegrationName,
-				PipelineHooks:       tt.fields.PipelineHooks,
-				Permissions:         tt.fields.Permissions,
+				PipelineHooks:          tt.fields.PipelineHooks,
+				Permissions:            tt.fields.Permissions,
 			}
 			if err := w.checkValidity(); (err != nil) != tt.wantErr {
 				t.Errorf("Workflow.checkValidity() error = %v, wantErr %v", err, tt.wantErr)
@@ -201,21 +201,21 @@ func TestWorkflow_checkValidity(t *testing.T) {
 
 func TestWorkflow_GetWorkflow(t *testing.T) {
 	type fields struct {
-		Name                string
-		Description         string
-		Version             string
-		Workflow            map[string]NodeEntry
-		Hooks               map[string][]HookEntry
-		DependsOn           []string
-		Conditions          *sdk.WorkflowNodeConditions
-		When                []string
-		PipelineName        string
-		ApplicationName     string
-		EnvironmentName     string
+		Name                   string
+		Description            string
+		Version                string
+		Workflow               map[string]NodeEntry
+		Hooks                  map[string][]HookEntry
+		DependsOn              []string
+		Conditions             *sdk.WorkflowNodeConditions
+		When                   []string
+		PipelineName           string
+		ApplicationName        string
+		EnvironmentName        string
 		ProjectIntegrationName string
-		PipelineHooks       []HookEntry
-		Permissions         map[string]int
-		HistoryLength       int64
+		PipelineHooks          []HookEntry
+		Permissions            map[string]int
+		HistoryLength          int64
 	}
 	tsts := []struct {
 		name    string
@@ -605,7 +605,7 @@ func TestWorkflow_GetWorkflow(t *testing.T) {
 		{
 			name: "Complex workflow with integration should not raise an error",
 			fields: fields{
-				PipelineName:        "pipeline",
+				PipelineName:           "pipeline",
 				ProjectIntegrationName: "integration",
 			},
 			wantErr: false,
@@ -617,7 +617,7 @@ func TestWorkflow_GetWorkflow(t *testing.T) {
 						Ref:  "pipeline",
 						Type: "pipeline",
 						Context: &sdk.NodeContext{
-							PipelineName:        "pipeline",
+							PipelineName:           "pipeline",
 							ProjectIntegrationName: "integration",
 						},
 					},
@@ -677,21 +677,21 @@ func TestWorkflow_GetWorkflow(t *testing.T) {
 	for _, tt := range tsts {
 		t.Run(tt.name, func(t *testing.T) {
 			w := Workflow{
-				Name:                tt.fields.Name,
-				Description:         tt.fields.Description,
-				Version:             tt.fields.Version,
-				Workflow:            tt.fields.Workflow,
-				Hooks:               tt.fields.Hooks,
-				DependsOn:           tt.fields.DependsOn,
-				Conditions:          tt.fields.Conditions,
-				When:                tt.fields.When,
-				PipelineName:        tt.fields.PipelineName,
-				ApplicationName:     tt.fields.ApplicationName,
-				EnvironmentName:     tt.fields.EnvironmentName,
+				Name:                   tt.fields.Name,
+				Description:            tt.fields.Description,
+				Version:                tt.fields.Version,
+				Workflow:               tt.fields.Workflow,
+				Hooks:                  tt.fields.Hooks,
+				DependsOn:              tt.fields.DependsOn,
+				Conditions:             tt.fields.Conditions,
+				When:                   tt.fields.When,
+				PipelineName:           tt.fields.PipelineName,
+				ApplicationName:        tt.fields.ApplicationName,
+				EnvironmentName:        tt.fields.EnvironmentName,
 				ProjectIntegrationName: tt.fields.ProjectIntegrationName,
-				PipelineHooks:       tt.fields.PipelineHooks,
-				Permissions:         tt.fields.Permissions,
-				HistoryLength:       &tt.fields.HistoryLength,
+				PipelineHooks:          tt.fields.PipelineHooks,
+				Permissions:            tt.fields.Permissions,
+				HistoryLength:          &tt.fields.HistoryLength,
 			}
 			got, err := w.GetWorkflow()
 			if (err != nil) != tt.wantErr {
@@ -990,6 +990,27 @@ workflow:
     depends_on:
     - 1_start
     pipeline: test
+`,
+		},
+		{
+			name: "node with a priority",
+			yaml: `name: priority
+version: v1.0
+workflow:
+  build:
+    pipeline: build
+  deploy:
+    depends_on:
+    - build
+    conditions:
+      check:
+      - variable: git.branch
+        operator: eq
+        value: master
+      priority: 1
+    when:
+    - success
+    pipeline: deploy
 `,
 		},
 		{
@@ -1034,6 +1055,59 @@ workflow:
     - aa_2
     when:
     - manual
+`,
+		}, {
+			name: "Join with strategy",
+			yaml: `name: joins
+version: v1.0
+workflow:
+  aa:
+    pipeline: aa
+  aa_2:
+    depends_on:
+    - aa
+    pipeline: aa
+  aa_3:
+    depends_on:
+    - aa
+    pipeline: aa
+  aa_4:
+    depends_on:
+    - aa_2
+    - aa_3
+    join_strategy: at_least
+    join_min: 1
+    pipeline: aa
+`,
+		}, {
+			name: "fork policy",
+			yaml: `name: fork-policy
+version: v1.0
+pipeline: build
+fork_policy:
+  secrets: restricted
+  allowed_secrets:
+  - cds.app.sonar_token
+  approval: none
+`,
+		},
+		{
+			name: "auto cancel",
+			yaml: `name: auto-cancel
+version: v1.0
+pipeline: build
+auto_cancel: true
+`,
+		},
+		{
+			name: "commit status",
+			yaml: `name: commit-status
+version: v1.0
+pipeline: build
+commit_status:
+  context: ci/{{.cds.workflow}}/{{.cds.node}}
+  nodes:
+    build: ci/build
 `,
 		},
 	}
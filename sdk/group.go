@@ -1,5 +1,7 @@
 package sdk
 
+import "time"
+
 // SharedInfraGroupName is the name of the builtin group used to share infrastructure between projects
 const SharedInfraGroupName = "shared.infra"
 
@@ -38,3 +40,30 @@ func GroupsToIDs(gs []Group) []int64 {
 	}
 	return ids
 }
+
+// Changes applied by the LDAP groups synchronization
+const (
+	LDAPGroupSyncCreate       = "create"
+	LDAPGroupSyncLink         = "link"
+	LDAPGroupSyncRestore      = "restore"
+	LDAPGroupSyncDelete       = "delete"
+	LDAPGroupSyncAddMember    = "add_member"
+	LDAPGroupSyncRemoveMember = "remove_member"
+)
+
+// LDAPGroupSyncChange is a change on a CDS group computed by the LDAP groups synchronization
+type LDAPGroupSyncChange struct {
+	Action string `json:"action" cli:"action"`
+	Group  string `json:"group" cli:"group"`
+	User   string `json:"user,omitempty" cli:"user"`
+}
+
+// LDAPGroupSyncStatus is the status of the last LDAP groups synchronization
+type LDAPGroupSyncStatus struct {
+	Enabled bool                  `json:"enabled"`
+	DryRun  bool                  `json:"dry_run"`
+	Start   time.Time             `json:"start"`
+	End     time.Time             `json:"end"`
+	Error   string                `json:"error,omitempty"`
+	Changes []LDAPGroupSyncChange `json:"changes"`
+}
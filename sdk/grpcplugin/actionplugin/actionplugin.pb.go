@@ -28,6 +28,7 @@ type ActionPluginManifest struct {
 	Version              string   `protobuf:"bytes,2,opt,name=version,proto3" json:"version,omitempty"`
 	Description          string   `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
 	Author               string   `protobuf:"bytes,4,opt,name=author,proto3" json:"author,omitempty"`
+	Capabilities         []string `protobuf:"bytes,5,rep,name=capabilities,proto3" json:"capabilities,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -86,6 +87,13 @@ func (m *ActionPluginManifest) GetAuthor() string {
 	return ""
 }
 
+func (m *ActionPluginManifest) GetCapabilities() []string {
+	if m != nil {
+		return m.Capabilities
+	}
+	return nil
+}
+
 type ActionQuery struct {
 	Options              map[string]string `protobuf:"bytes,1,rep,name=options,proto3" json:"options,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
 	JobID                int64             `protobuf:"varint,2,opt,name=jobID,proto3" json:"jobID,omitempty"`
@@ -230,34 +238,35 @@ func init() {
 func init() { proto.RegisterFile("actionplugin.proto", fileDescriptor_8761e3c72e0ffc53) }
 
 var fileDescriptor_8761e3c72e0ffc53 = []byte{
-	// 425 bytes of a gzipped FileDescriptorProto
+	// 440 bytes of a gzipped FileDescriptorProto
 	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x74, 0x53, 0x4d, 0x6f, 0xd3, 0x40,
-	0x10, 0x8d, 0x63, 0xb7, 0x85, 0x49, 0x84, 0x60, 0xa8, 0x2a, 0x63, 0x2e, 0x61, 0x0f, 0x50, 0x2e,
-	0x5b, 0xa9, 0x5c, 0xaa, 0x1e, 0x50, 0xa9, 0xa8, 0x54, 0x24, 0x2a, 0x8c, 0xa9, 0x84, 0xc4, 0xcd,
-	0xb1, 0x37, 0x8e, 0x89, 0xe3, 0xb5, 0xf6, 0x23, 0x92, 0x2f, 0xfc, 0x97, 0xfc, 0x53, 0xe4, 0x5d,
-	0xbb, 0xb2, 0xa5, 0xf8, 0x36, 0x6f, 0xe6, 0xcd, 0xec, 0xbc, 0x79, 0x5a, 0xc0, 0x38, 0x51, 0x39,
-	0x2f, 0xab, 0x42, 0x67, 0x79, 0x49, 0x2b, 0xc1, 0x15, 0xc7, 0x79, 0x3f, 0x17, 0xbc, 0xcd, 0x38,
-	0xcf, 0x0a, 0x76, 0x61, 0x6a, 0x4b, 0xbd, 0xba, 0x60, 0xdb, 0x4a, 0xd5, 0x96, 0x4a, 0xfe, 0xc1,
-	0xe9, 0x17, 0x43, 0x0e, 0x0d, 0xf9, 0x21, 0x2e, 0xf3, 0x15, 0x93, 0x0a, 0x11, 0xbc, 0x32, 0xde,
-	0x32, 0xdf, 0x59, 0x38, 0xe7, 0xcf, 0x23, 0x13, 0xa3, 0x0f, 0x27, 0x3b, 0x26, 0x64, 0xce, 0x4b,
-	0x7f, 0x6a, 0xd2, 0x1d, 0xc4, 0x05, 0xcc, 0x52, 0x26, 0x13, 0x91, 0x57, 0xcd, 0x28, 0xdf, 0x35,
-	0xd5, 0x7e, 0x0a, 0xcf, 0xe0, 0x38, 0xd6, 0x6a, 0xcd, 0x85, 0xef, 0x99, 0x62, 0x8b, 0xc8, 0xde,
-	0x81, 0x99, 0x5d, 0xe0, 0xa7, 0x66, 0xa2, 0xc6, 0x1b, 0x38, 0xe1, 0xa6, 0x43, 0xfa, 0xce, 0xc2,
-	0x3d, 0x9f, 0x5d, 0xbe, 0xa7, 0x03, 0x81, 0x3d, 0x2e, 0xfd, 0x61, 0x89, 0x77, 0xa5, 0x12, 0x75,
-	0xd4, 0xb5, 0xe1, 0x29, 0x1c, 0xfd, 0xe5, 0xcb, 0x6f, 0x5f, 0xcd, 0x8e, 0x6e, 0x64, 0x41, 0x70,
-	0x0d, 0xf3, 0x3e, 0x1d, 0x5f, 0x82, 0xbb, 0x61, 0x75, 0x2b, 0xaf, 0x09, 0x9b, 0xbe, 0x5d, 0x5c,
-	0x68, 0xd6, 0x6a, 0xb3, 0xe0, 0x7a, 0x7a, 0xe5, 0x90, 0x1b, 0x98, 0xdb, 0x67, 0x23, 0x26, 0x75,
-	0xa1, 0x1a, 0x2d, 0x52, 0xc5, 0x4a, 0xcb, 0xb6, 0xbd, 0x45, 0xcd, 0x7d, 0x52, 0xa6, 0xe2, 0xbc,
-	0x90, 0xdd, 0x7d, 0x5a, 0x48, 0x3e, 0xc2, 0xeb, 0xdf, 0x5c, 0x6c, 0x98, 0xb8, 0x7f, 0x7c, 0x0c,
-	0x43, 0x2e, 0x94, 0x15, 0x8b, 0xe0, 0x55, 0x5c, 0x28, 0x33, 0xe6, 0x28, 0x32, 0xf1, 0xe5, 0x7e,
-	0xda, 0xbd, 0x66, 0x1d, 0xc1, 0x7b, 0x78, 0xf6, 0xe4, 0xca, 0x19, 0xb5, 0x5e, 0xd2, 0xce, 0x4b,
-	0x7a, 0xd7, 0x78, 0x19, 0x90, 0x43, 0x47, 0x1a, 0x3a, 0x4a, 0x26, 0xf8, 0x19, 0xdc, 0x48, 0x97,
-	0xf8, 0x66, 0xf4, 0xa2, 0x41, 0x70, 0xa8, 0x64, 0x55, 0x93, 0x09, 0x3e, 0xc0, 0x8b, 0xa1, 0x0a,
-	0x7c, 0x37, 0xe4, 0x1f, 0xd0, 0x18, 0x8c, 0xac, 0x4c, 0x26, 0x78, 0x05, 0xde, 0x2f, 0xc5, 0xab,
-	0x51, 0x51, 0xa3, 0x9d, 0xb7, 0xdf, 0xe1, 0x43, 0xc2, 0xb7, 0x94, 0xef, 0xd6, 0x34, 0x49, 0x25,
-	0x95, 0xe9, 0x86, 0x66, 0xa2, 0x4a, 0xda, 0x2d, 0xfa, 0x2b, 0xdd, 0xbe, 0xea, 0xdf, 0x22, 0x6c,
-	0x06, 0x85, 0xce, 0x9f, 0xc1, 0xff, 0x58, 0x1e, 0x9b, 0xf9, 0x9f, 0xfe, 0x07, 0x00, 0x00, 0xff,
-	0xff, 0x00, 0x7f, 0x06, 0xee, 0x4a, 0x03, 0x00, 0x00,
+	0x10, 0x8d, 0xe3, 0xa4, 0xa5, 0x13, 0x0b, 0xc1, 0x50, 0x55, 0xc6, 0x5c, 0xc2, 0x1e, 0x20, 0x5c,
+	0xb6, 0x52, 0xb9, 0x54, 0x3d, 0xa0, 0x52, 0x51, 0xa9, 0x48, 0x54, 0x18, 0x53, 0x09, 0x89, 0xdb,
+	0xc6, 0xd9, 0xa6, 0x4b, 0x1c, 0xaf, 0xb5, 0x1f, 0x91, 0xf2, 0x73, 0xc2, 0x2f, 0x45, 0xde, 0x75,
+	0x90, 0x2d, 0xc5, 0xb7, 0x7d, 0x33, 0x6f, 0xc6, 0xf3, 0xde, 0x93, 0x01, 0x59, 0x6e, 0x84, 0x2c,
+	0xab, 0xc2, 0x2e, 0x45, 0x49, 0x2b, 0x25, 0x8d, 0xc4, 0xa8, 0x5d, 0x4b, 0xde, 0x2c, 0xa5, 0x5c,
+	0x16, 0xfc, 0xdc, 0xf5, 0xe6, 0xf6, 0xf1, 0x9c, 0xaf, 0x2b, 0xb3, 0xf5, 0x54, 0xf2, 0x37, 0x80,
+	0xd3, 0xcf, 0x8e, 0x9d, 0x3a, 0xf6, 0x3d, 0x2b, 0xc5, 0x23, 0xd7, 0x06, 0x11, 0x46, 0x25, 0x5b,
+	0xf3, 0x38, 0x98, 0x06, 0xb3, 0x93, 0xcc, 0xbd, 0x31, 0x86, 0xe3, 0x0d, 0x57, 0x5a, 0xc8, 0x32,
+	0x1e, 0xba, 0xf2, 0x1e, 0xe2, 0x14, 0x26, 0x0b, 0xae, 0x73, 0x25, 0xaa, 0x7a, 0x55, 0x1c, 0xba,
+	0x6e, 0xbb, 0x84, 0x67, 0x70, 0xc4, 0xac, 0x79, 0x92, 0x2a, 0x1e, 0xb9, 0x66, 0x83, 0x90, 0x40,
+	0x94, 0xb3, 0x8a, 0xcd, 0x45, 0x21, 0x8c, 0xe0, 0x3a, 0x1e, 0x4f, 0xc3, 0xd9, 0x49, 0xd6, 0xa9,
+	0x91, 0x5d, 0x00, 0x13, 0x7f, 0xe4, 0x0f, 0xcb, 0xd5, 0x16, 0xaf, 0xe1, 0x58, 0xba, 0xad, 0x3a,
+	0x0e, 0xa6, 0xe1, 0x6c, 0x72, 0xf1, 0x8e, 0x76, 0x5c, 0x68, 0x71, 0xe9, 0x77, 0x4f, 0xbc, 0x2d,
+	0x8d, 0xda, 0x66, 0xfb, 0x31, 0x3c, 0x85, 0xf1, 0x1f, 0x39, 0xff, 0xfa, 0xc5, 0xe9, 0x08, 0x33,
+	0x0f, 0x92, 0x2b, 0x88, 0xda, 0x74, 0x7c, 0x01, 0xe1, 0x8a, 0x6f, 0x1b, 0x0b, 0xea, 0x67, 0x3d,
+	0xb7, 0x61, 0x85, 0xe5, 0x8d, 0x7e, 0x0f, 0xae, 0x86, 0x97, 0x01, 0xb9, 0x86, 0xc8, 0x7f, 0x36,
+	0xe3, 0xda, 0x16, 0xa6, 0xd6, 0xab, 0x0d, 0x33, 0x56, 0x37, 0xe3, 0x0d, 0xaa, 0x3d, 0x5c, 0x70,
+	0xc3, 0x44, 0xa1, 0xf7, 0x1e, 0x36, 0x90, 0x7c, 0x80, 0x57, 0xbf, 0xa4, 0x5a, 0x71, 0x75, 0xf7,
+	0xf0, 0x90, 0xa6, 0x52, 0x19, 0x2f, 0x16, 0x61, 0x54, 0x49, 0x65, 0xdc, 0x9a, 0x71, 0xe6, 0xde,
+	0x17, 0xbb, 0x21, 0x44, 0xed, 0xd4, 0xf0, 0x0e, 0x9e, 0xfd, 0x4f, 0xee, 0x8c, 0xfa, 0xc0, 0xe9,
+	0x3e, 0x70, 0x7a, 0x5b, 0x07, 0x9e, 0x90, 0x43, 0x26, 0x75, 0x53, 0x27, 0x03, 0xfc, 0x04, 0x61,
+	0x66, 0x4b, 0x7c, 0xdd, 0xeb, 0x68, 0x92, 0x1c, 0x6a, 0x79, 0xd5, 0x64, 0x80, 0xf7, 0xf0, 0xbc,
+	0xab, 0x02, 0xdf, 0x76, 0xf9, 0x07, 0x34, 0x26, 0x3d, 0x27, 0x93, 0x01, 0x5e, 0xc2, 0xe8, 0xa7,
+	0x91, 0x55, 0xaf, 0xa8, 0xde, 0xc9, 0x9b, 0x6f, 0xf0, 0x3e, 0x97, 0x6b, 0x2a, 0x37, 0x4f, 0x34,
+	0x5f, 0x68, 0xaa, 0x17, 0x2b, 0xba, 0x54, 0x55, 0xde, 0x5c, 0xd1, 0x3e, 0xe9, 0xe6, 0x65, 0xdb,
+	0x8b, 0xb4, 0x5e, 0x94, 0x06, 0xbf, 0x3b, 0x3f, 0xd1, 0xfc, 0xc8, 0xed, 0xff, 0xf8, 0x6f, 0x00,
+	0x83, 0x28, 0x92, 0x1d, 0x6f, 0x03, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
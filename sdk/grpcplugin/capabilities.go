@@ -0,0 +1,45 @@
+package grpcplugin
+
+import (
+	"fmt"
+	"strings"
+)
+
+// These are the capabilities a worker can offer to the plugins. A plugin lists in its manifest the capabilities
+// it needs, the worker refuses to run a plugin needing a capability it doesn't know.
+const (
+	// CapabilityWorkerHTTPPort means that the worker gives the port of its HTTP API to the plugin before running it
+	CapabilityWorkerHTTPPort = "worker-http-port"
+	// CapabilityJobID means that the ID of the job is given to the plugin when it runs
+	CapabilityJobID = "job-id"
+	// CapabilityLogs means that the worker sends the outputs of the plugin in the logs of the step
+	CapabilityLogs = "logs"
+)
+
+// WorkerCapabilities are the capabilities offered by this version of the worker
+var WorkerCapabilities = []string{CapabilityWorkerHTTPPort, CapabilityJobID, CapabilityLogs}
+
+// CheckCapabilities returns an error if some of the capabilities needed by a plugin are not offered by the worker.
+// The plugins written before the negotiation of the capabilities don't list any and are always accepted.
+func CheckCapabilities(pluginName string, capabilities []string) error {
+	var missing []string
+	for _, c := range capabilities {
+		if !HasCapability(WorkerCapabilities, c) {
+			missing = append(missing, c)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("plugin %s needs capabilities not supported by this worker: %s", pluginName, strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// HasCapability returns true if the capability is in the list
+func HasCapability(capabilities []string, capability string) bool {
+	for _, c := range capabilities {
+		if c == capability {
+			return true
+		}
+	}
+	return false
+}
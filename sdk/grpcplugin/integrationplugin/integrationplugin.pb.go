@@ -29,6 +29,7 @@ type IntegrationPluginManifest struct {
 	Version              string   `protobuf:"bytes,2,opt,name=version,proto3" json:"version,omitempty"`
 	Description          string   `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
 	Author               string   `protobuf:"bytes,4,opt,name=author,proto3" json:"author,omitempty"`
+	Capabilities         []string `protobuf:"bytes,5,rep,name=capabilities,proto3" json:"capabilities,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -87,6 +88,13 @@ func (m *IntegrationPluginManifest) GetAuthor() string {
 	return ""
 }
 
+func (m *IntegrationPluginManifest) GetCapabilities() []string {
+	if m != nil {
+		return m.Capabilities
+	}
+	return nil
+}
+
 type DeployQuery struct {
 	Options              map[string]string `protobuf:"bytes,1,rep,name=options,proto3" json:"options,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
 	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
@@ -223,33 +231,35 @@ func init() {
 func init() { proto.RegisterFile("integrationplugin.proto", fileDescriptor_ad20155c873eed76) }
 
 var fileDescriptor_ad20155c873eed76 = []byte{
-	// 415 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x53, 0x4f, 0x8b, 0xda, 0x40,
-	0x1c, 0x35, 0xd1, 0x6a, 0xfb, 0x53, 0x4a, 0x1d, 0x8a, 0x4d, 0x53, 0x68, 0x25, 0xed, 0x41, 0xa8,
-	0x8c, 0x60, 0x2f, 0xe2, 0xa9, 0x88, 0x1e, 0xa4, 0x94, 0xda, 0x78, 0x28, 0xb4, 0xa7, 0x98, 0x8c,
-	0x31, 0x18, 0x33, 0x61, 0x66, 0x22, 0xe4, 0xdc, 0x2f, 0xb0, 0xdf, 0x6a, 0xbf, 0xd6, 0x92, 0x99,
-	0xc9, 0x6e, 0x20, 0x9b, 0x65, 0x6f, 0xf9, 0xfd, 0x79, 0xef, 0x37, 0xef, 0x3d, 0x02, 0xef, 0xa2,
-	0x44, 0x90, 0x90, 0x79, 0x22, 0xa2, 0x49, 0x1a, 0x67, 0x61, 0x94, 0xe0, 0x94, 0x51, 0x41, 0xd1,
-	0xb0, 0x36, 0xb0, 0x3f, 0x84, 0x94, 0x86, 0x31, 0x99, 0xc9, 0x85, 0x43, 0x76, 0x9c, 0x91, 0x4b,
-	0x2a, 0x72, 0xb5, 0xef, 0xfc, 0x37, 0xe0, 0xfd, 0xf6, 0x01, 0xb2, 0x93, 0x90, 0x9f, 0x5e, 0x12,
-	0x1d, 0x09, 0x17, 0x08, 0x41, 0x27, 0xf1, 0x2e, 0xc4, 0x32, 0xc6, 0xc6, 0xe4, 0x95, 0x2b, 0xbf,
-	0x91, 0x05, 0xbd, 0x2b, 0x61, 0x3c, 0xa2, 0x89, 0x65, 0xca, 0x76, 0x59, 0xa2, 0x31, 0xf4, 0x03,
-	0xc2, 0x7d, 0x16, 0xa5, 0x05, 0x95, 0xd5, 0x96, 0xd3, 0x6a, 0x0b, 0x8d, 0xa0, 0xeb, 0x65, 0xe2,
-	0x44, 0x99, 0xd5, 0x91, 0x43, 0x5d, 0x39, 0x37, 0x06, 0xf4, 0xd7, 0x24, 0x8d, 0x69, 0xfe, 0x3b,
-	0x23, 0x2c, 0x47, 0x1b, 0xe8, 0x51, 0x89, 0xe0, 0x96, 0x31, 0x6e, 0x4f, 0xfa, 0xf3, 0xaf, 0xb8,
-	0x2e, 0xb8, 0x02, 0xc0, 0xbf, 0xd4, 0xf6, 0x26, 0x11, 0x2c, 0x77, 0x4b, 0xac, 0xbd, 0x84, 0x41,
-	0x75, 0x80, 0xde, 0x40, 0xfb, 0x4c, 0x72, 0xad, 0xa6, 0xf8, 0x44, 0x6f, 0xe1, 0xc5, 0xd5, 0x8b,
-	0x33, 0xa2, 0xa5, 0xa8, 0x62, 0x69, 0x2e, 0x0c, 0xe7, 0x3b, 0x0c, 0xd4, 0x01, 0x97, 0xf0, 0x2c,
-	0x16, 0xc5, 0xd3, 0xb9, 0xf0, 0x44, 0xc6, 0x35, 0x5c, 0x57, 0x85, 0x1d, 0x01, 0x11, 0x5e, 0x14,
-	0xf3, 0xd2, 0x0e, 0x5d, 0x3a, 0x9f, 0x61, 0xa8, 0x18, 0xf6, 0x72, 0x53, 0x29, 0x7b, 0x0d, 0xe6,
-	0x76, 0xad, 0x29, 0xcc, 0xed, 0x7a, 0x7e, 0x6b, 0xc2, 0xb0, 0xe6, 0x3f, 0x72, 0xe1, 0xe5, 0x7d,
-	0x06, 0x23, 0xac, 0xf2, 0xc3, 0x65, 0x7e, 0x78, 0x53, 0xe4, 0x67, 0x4f, 0x1f, 0xb1, 0xa4, 0x31,
-	0x49, 0xa7, 0x85, 0x7e, 0x40, 0x57, 0x3d, 0x07, 0x7d, 0x7c, 0xda, 0x4c, 0xfb, 0x53, 0xe3, 0x5c,
-	0x79, 0xe1, 0xb4, 0xd0, 0x9f, 0xd2, 0x1d, 0xa5, 0x0d, 0x7d, 0x69, 0x84, 0x54, 0xc4, 0x3f, 0x87,
-	0x78, 0x01, 0x9d, 0xbd, 0xa0, 0x69, 0xa3, 0xea, 0x86, 0xbe, 0xd3, 0x5a, 0xfd, 0x83, 0xa9, 0x4f,
-	0x2f, 0x98, 0x5e, 0x4f, 0xd8, 0x0f, 0x38, 0xe6, 0xc1, 0x19, 0x87, 0x2c, 0xf5, 0xf5, 0x99, 0xda,
-	0xe1, 0xd5, 0xa8, 0x66, 0xd6, 0xae, 0xa0, 0xdc, 0x19, 0x7f, 0xeb, 0xff, 0xd0, 0xa1, 0x2b, 0xcf,
-	0x7d, 0xbb, 0x0b, 0x00, 0x00, 0xff, 0xff, 0x04, 0xbd, 0x83, 0x33, 0x78, 0x03, 0x00, 0x00,
+	// 433 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x53, 0xdd, 0x8a, 0xd3, 0x40,
+	0x18, 0x6d, 0xd2, 0x6e, 0xd7, 0xfd, 0x5a, 0xc4, 0x0e, 0x52, 0x63, 0x05, 0x2d, 0xa3, 0x17, 0x05,
+	0x97, 0x59, 0x58, 0x6f, 0x96, 0xbd, 0x92, 0xa5, 0xbd, 0x28, 0x22, 0xd6, 0xec, 0x85, 0xa0, 0x57,
+	0xd3, 0x74, 0x36, 0x3b, 0x34, 0xcd, 0x0c, 0x33, 0x93, 0x42, 0xde, 0xc2, 0xb7, 0xf0, 0x51, 0x7c,
+	0x2d, 0xc9, 0xcc, 0x44, 0x23, 0x31, 0xb2, 0x77, 0xf3, 0xfd, 0x9c, 0xf3, 0xe5, 0x9c, 0x43, 0xe0,
+	0x19, 0xcf, 0x0d, 0x4b, 0x15, 0x35, 0x5c, 0xe4, 0x32, 0x2b, 0x52, 0x9e, 0x13, 0xa9, 0x84, 0x11,
+	0x68, 0xd2, 0x1a, 0xcc, 0x5e, 0xa4, 0x42, 0xa4, 0x19, 0xbb, 0xb0, 0x0b, 0xdb, 0xe2, 0xee, 0x82,
+	0x1d, 0xa4, 0x29, 0xdd, 0x3e, 0xfe, 0x11, 0xc0, 0xf3, 0xf5, 0x1f, 0xc8, 0xc6, 0x42, 0x3e, 0xd2,
+	0x9c, 0xdf, 0x31, 0x6d, 0x10, 0x82, 0x41, 0x4e, 0x0f, 0x2c, 0x0a, 0xe6, 0xc1, 0xe2, 0x2c, 0xb6,
+	0x6f, 0x14, 0xc1, 0xe9, 0x91, 0x29, 0xcd, 0x45, 0x1e, 0x85, 0xb6, 0x5d, 0x97, 0x68, 0x0e, 0xa3,
+	0x1d, 0xd3, 0x89, 0xe2, 0xb2, 0xa2, 0x8a, 0xfa, 0x76, 0xda, 0x6c, 0xa1, 0x29, 0x0c, 0x69, 0x61,
+	0xee, 0x85, 0x8a, 0x06, 0x76, 0xe8, 0x2b, 0x84, 0x61, 0x9c, 0x50, 0x49, 0xb7, 0x3c, 0xe3, 0x86,
+	0x33, 0x1d, 0x9d, 0xcc, 0xfb, 0x8b, 0xb3, 0xf8, 0xaf, 0x1e, 0xfe, 0x1e, 0xc0, 0x68, 0xc9, 0x64,
+	0x26, 0xca, 0xcf, 0x05, 0x53, 0x25, 0x5a, 0xc1, 0xa9, 0xb0, 0xac, 0x3a, 0x0a, 0xe6, 0xfd, 0xc5,
+	0xe8, 0xf2, 0x2d, 0x69, 0x9b, 0xd2, 0x00, 0x90, 0x4f, 0x6e, 0x7b, 0x95, 0x1b, 0x55, 0xc6, 0x35,
+	0x76, 0x76, 0x0d, 0xe3, 0xe6, 0x00, 0x3d, 0x81, 0xfe, 0x9e, 0x95, 0x5e, 0x71, 0xf5, 0x44, 0x4f,
+	0xe1, 0xe4, 0x48, 0xb3, 0x82, 0x79, 0xb9, 0xae, 0xb8, 0x0e, 0xaf, 0x02, 0xfc, 0x1e, 0xc6, 0xee,
+	0x40, 0xcc, 0x74, 0x91, 0x99, 0x4a, 0x9e, 0x36, 0xd4, 0x14, 0xda, 0xc3, 0x7d, 0x55, 0x59, 0xb6,
+	0x63, 0x86, 0xf2, 0x4c, 0xd7, 0x96, 0xf9, 0x12, 0xbf, 0x86, 0x89, 0x63, 0xb8, 0xb5, 0x9b, 0x4e,
+	0xd9, 0x63, 0x08, 0xd7, 0x4b, 0x4f, 0x11, 0xae, 0x97, 0x97, 0x3f, 0x43, 0x98, 0xb4, 0x32, 0x42,
+	0x31, 0x3c, 0xfa, 0x9d, 0xd3, 0x94, 0xb8, 0x8c, 0x49, 0x9d, 0x31, 0x59, 0x55, 0x19, 0xcf, 0xce,
+	0xff, 0x61, 0x49, 0x67, 0xda, 0xb8, 0x87, 0x3e, 0xc0, 0xd0, 0x7d, 0x0e, 0x7a, 0xf9, 0x7f, 0x33,
+	0x67, 0xaf, 0x3a, 0xe7, 0xce, 0x0b, 0xdc, 0x43, 0x5f, 0x60, 0xdc, 0xd4, 0x86, 0xde, 0x74, 0x42,
+	0x1a, 0xe2, 0x1f, 0x42, 0x7c, 0x05, 0x83, 0x5b, 0x23, 0x64, 0xa7, 0xea, 0x8e, 0x3e, 0xee, 0xdd,
+	0x7c, 0x83, 0xf3, 0x44, 0x1c, 0x88, 0x38, 0xde, 0x93, 0x64, 0xa7, 0x89, 0xde, 0xed, 0x49, 0xaa,
+	0x64, 0xe2, 0xcf, 0xb4, 0x0e, 0xdf, 0x4c, 0x5b, 0x66, 0x6d, 0x2a, 0xca, 0x4d, 0xf0, 0xb5, 0xfd,
+	0x9f, 0x6d, 0x87, 0xf6, 0xdc, 0xbb, 0x5f, 0x03, 0x00, 0xfa, 0x6f, 0xa5, 0x31, 0x9c, 0x03, 0x00,
+	0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -0,0 +1,126 @@
+package hatchery
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// AutoscalingConfiguration is the configuration of the control loop computing the number of workers to provision for each model
+type AutoscalingConfiguration struct {
+	Enabled            bool `toml:"enabled" default:"false" comment:"Compute the number of workers to provision for each model from the queue pressure, instead of the provision value of the model" json:"enabled"`
+	MinProvision       int  `toml:"minProvision" default:"0" comment:"Minimum number of workers provisioned for each model" json:"minProvision"`
+	MaxProvision       int  `toml:"maxProvision" default:"5" comment:"Maximum number of workers provisioned for each model" json:"maxProvision"`
+	Step               int  `toml:"step" default:"1" comment:"Number of workers added or removed at each scaling" json:"step"`
+	ScaleUpWaitTime    int  `toml:"scaleUpWaitTime" default:"60" comment:"Scale up if a job waits for a model since more than this value (seconds)" json:"scaleUpWaitTime"`
+	ScaleUpPendingJobs int  `toml:"scaleUpPendingJobs" default:"3" comment:"Scale up if at least this number of jobs wait for a model" json:"scaleUpPendingJobs"`
+	CooldownUp         int  `toml:"cooldownUp" default:"60" comment:"Minimum delay between two scale up of a model (seconds)" json:"cooldownUp"`
+	CooldownDown       int  `toml:"cooldownDown" default:"300" comment:"Minimum delay after the last scaling before a scale down of a model (seconds)" json:"cooldownDown"`
+	Window             int  `toml:"window" default:"60" comment:"A job not received from the queue since this value (seconds) is no more pending" json:"window"`
+}
+
+type pendingJob struct {
+	modelID  int64
+	queued   time.Time
+	lastSeen time.Time
+}
+
+// autoscaler tracks the jobs waiting for each model and computes the provisioning target of the models
+type autoscaler struct {
+	mutex       sync.Mutex
+	cfg         AutoscalingConfiguration
+	jobs        map[int64]pendingJob
+	targets     map[int64]int
+	lastScaling map[int64]time.Time
+}
+
+func newAutoscaler(cfg AutoscalingConfiguration) *autoscaler {
+	if cfg.Step < 1 {
+		cfg.Step = 1
+	}
+	if cfg.MaxProvision < cfg.MinProvision {
+		cfg.MaxProvision = cfg.MinProvision
+	}
+	return &autoscaler{
+		cfg:         cfg,
+		jobs:        map[int64]pendingJob{},
+		targets:     map[int64]int{},
+		lastScaling: map[int64]time.Time{},
+	}
+}
+
+// observe records that a job waiting in the queue can run with the model
+func (a *autoscaler) observe(j sdk.WorkflowNodeJobRun, m sdk.Model, now time.Time) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	a.jobs[j.ID] = pendingJob{modelID: m.ID, queued: j.Queued, lastSeen: now}
+}
+
+// target returns the number of workers to provision for the model
+func (a *autoscaler) target(m sdk.Model) int {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	if t, ok := a.targets[m.ID]; ok {
+		return t
+	}
+	return a.clamp(int(m.Provision))
+}
+
+func (a *autoscaler) clamp(n int) int {
+	if n < a.cfg.MinProvision {
+		return a.cfg.MinProvision
+	}
+	if n > a.cfg.MaxProvision {
+		return a.cfg.MaxProvision
+	}
+	return n
+}
+
+// compute updates the provisioning target of each model from the pending jobs
+func (a *autoscaler) compute(models []sdk.Model, now time.Time) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	window := time.Duration(a.cfg.Window) * time.Second
+	pending := map[int64]int{}
+	oldest := map[int64]time.Time{}
+	for id, j := range a.jobs {
+		if now.Sub(j.lastSeen) > window {
+			delete(a.jobs, id)
+			continue
+		}
+		pending[j.modelID]++
+		if o, ok := oldest[j.modelID]; !ok || j.queued.Before(o) {
+			oldest[j.modelID] = j.queued
+		}
+	}
+
+	for _, m := range models {
+		current, ok := a.targets[m.ID]
+		if !ok {
+			current = a.clamp(int(m.Provision))
+		}
+		next := current
+		last := a.lastScaling[m.ID]
+
+		waiting := pending[m.ID] > 0 && now.Sub(oldest[m.ID]) >= time.Duration(a.cfg.ScaleUpWaitTime)*time.Second
+		switch {
+		case pending[m.ID] >= a.cfg.ScaleUpPendingJobs || waiting:
+			if now.Sub(last) >= time.Duration(a.cfg.CooldownUp)*time.Second {
+				next = a.clamp(current + a.cfg.Step)
+			}
+		case pending[m.ID] == 0:
+			if now.Sub(last) >= time.Duration(a.cfg.CooldownDown)*time.Second {
+				next = a.clamp(current - a.cfg.Step)
+			}
+		}
+
+		if next != current {
+			log.Info("autoscaling> provisioning of model %s: %d -> %d (%d pending jobs)", m.Name, current, next, pending[m.ID])
+			a.lastScaling[m.ID] = now
+		}
+		a.targets[m.ID] = next
+	}
+}
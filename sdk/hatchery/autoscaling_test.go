@@ -0,0 +1,57 @@
+package hatchery
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func TestAutoscalerCompute(t *testing.T) {
+	a := newAutoscaler(AutoscalingConfiguration{
+		MinProvision:       1,
+		MaxProvision:       3,
+		Step:               1,
+		ScaleUpWaitTime:    60,
+		ScaleUpPendingJobs: 2,
+		CooldownUp:         60,
+		CooldownDown:       300,
+		Window:             60,
+	})
+	m := sdk.Model{ID: 1, Name: "model", Provision: 0}
+	models := []sdk.Model{m}
+	now := time.Now()
+
+	// The provision of the model is bounded
+	assert.Equal(t, 1, a.target(m))
+
+	// Two pending jobs: scale up
+	a.observe(sdk.WorkflowNodeJobRun{ID: 1, Queued: now}, m, now)
+	a.observe(sdk.WorkflowNodeJobRun{ID: 2, Queued: now}, m, now)
+	a.compute(models, now)
+	assert.Equal(t, 2, a.target(m))
+
+	// Still two pending jobs, but in the cooldown
+	a.compute(models, now.Add(30*time.Second))
+	assert.Equal(t, 2, a.target(m))
+
+	// A single job waiting for too long: scale up to the max
+	a.observe(sdk.WorkflowNodeJobRun{ID: 2, Queued: now}, m, now.Add(60*time.Second))
+	a.compute(models, now.Add(70*time.Second))
+	assert.Equal(t, 3, a.target(m))
+	a.observe(sdk.WorkflowNodeJobRun{ID: 2, Queued: now}, m, now.Add(130*time.Second))
+	a.compute(models, now.Add(140*time.Second))
+	assert.Equal(t, 3, a.target(m))
+
+	// No more pending jobs: scale down after the cooldown, down to the min
+	a.compute(models, now.Add(300*time.Second))
+	assert.Equal(t, 3, a.target(m))
+	a.compute(models, now.Add(400*time.Second))
+	assert.Equal(t, 2, a.target(m))
+	a.compute(models, now.Add(800*time.Second))
+	assert.Equal(t, 1, a.target(m))
+	a.compute(models, now.Add(1200*time.Second))
+	assert.Equal(t, 1, a.target(m))
+}
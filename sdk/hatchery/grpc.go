@@ -0,0 +1,76 @@
+package hatchery
+
+import (
+	"context"
+	"crypto/tls"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/status"
+
+	cdsgrpc "github.com/ovh/cds/engine/api/grpc"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// grpcConn is the connection to the grpc api of CDS, nil if it is not configured
+var grpcConn *grpc.ClientConn
+
+type grpcCreds struct {
+	h Interface
+}
+
+// GetRequestMetadata authenticates the hatchery with the hash it got when it registered
+func (c *grpcCreds) GetRequestMetadata(context.Context, ...string) (map[string]string, error) {
+	srv := c.h.Service()
+	if srv == nil {
+		return nil, sdk.WithStack(sdk.ErrForbidden)
+	}
+	return map[string]string{"hash": srv.Hash}, nil
+}
+
+// RequireTransportSecurity indicates whether the credentials requires transport security.
+func (c *grpcCreds) RequireTransportSecurity() bool {
+	return !c.h.Configuration().API.GRPC.Insecure
+}
+
+func initGRPCConn(h Interface) {
+	address := h.Configuration().API.GRPC.URL
+	if address == "" {
+		return
+	}
+	// grpc dials a host:port target
+	address = strings.TrimPrefix(strings.TrimPrefix(address, "http://"), "https://")
+
+	opts := []grpc.DialOption{grpc.WithPerRPCCredentials(&grpcCreds{h: h})}
+	if h.Configuration().API.GRPC.Insecure {
+		opts = append(opts, grpc.WithInsecure())
+	} else {
+		opts = append(opts, grpc.WithTransportCredentials(credentials.NewTLS(&tls.Config{})))
+	}
+
+	var err error
+	grpcConn, err = grpc.Dial(address, opts...)
+	if err != nil {
+		log.Error("hatchery> Unable to connect to GRPC API %s: %v", address, err)
+		grpcConn = nil
+	}
+}
+
+// bookJob books the job through grpc if it is available, the grpc booking is confirmed in the same call.
+// The job is booked through the REST api when the grpc api can't be reached.
+func bookJob(ctx context.Context, h Interface, jobID int64) (bool, error) {
+	if grpcConn != nil {
+		_, err := cdsgrpc.NewWorkflowQueueClient(grpcConn).BookJob(ctx, &sdk.JobBookRequest{JobID: jobID})
+		if err == nil {
+			return true, nil
+		}
+		if status.Code(err) != codes.Unavailable {
+			return false, err
+		}
+		log.Error("hatchery> bookJob> Unable to book job %d through grpc: %v", jobID, err)
+	}
+	return false, h.CDSClient().QueueJobBook(ctx, jobID)
+}
@@ -74,6 +74,9 @@ func Create(ctx context.Context, h Interface) error {
 		return fmt.Errorf("Create> Init error: %v", err)
 	}
 
+	// the jobs are booked through grpc when the grpc api is configured
+	initGRPCConn(h)
+
 	// Call WorkerModel Enabled first
 	var errwm error
 	models, errwm = h.WorkerModelsEnabled()
@@ -91,6 +94,11 @@ func Create(ctx context.Context, h Interface) error {
 		tickerGetModels.Stop()
 	}()
 
+	var scaler *autoscaler
+	if h.Configuration().Provision.Autoscaling.Enabled {
+		scaler = newAutoscaler(h.Configuration().Provision.Autoscaling)
+	}
+
 	wjobs := make(chan sdk.WorkflowNodeJobRun, h.Configuration().Provision.MaxConcurrentProvisioning)
 	errs := make(chan error, 1)
 
@@ -182,7 +190,12 @@ func Create(ctx context.Context, h Interface) error {
 			currentCtx, currentCancel := context.WithTimeout(ctx, 10*time.Minute)
 			currentCtx = WithTags(currentCtx, h)
 			if val, has := j.Header.Get(tracingutils.SampledHeader); has && val == "1" {
-				currentCtx, _ = observability.New(currentCtx, h.ServiceName(), "hatchery.JobReceive", trace.AlwaysSample(), trace.SpanKindServer)
+				// continue the trace of the workflow run if the job comes with its span context
+				if parent, ok := tracingutils.ParseTraceParent(j.Header[tracingutils.TraceParentHeader]); ok {
+					currentCtx, _ = observability.NewWithRemoteParent(currentCtx, "hatchery.JobReceive", parent, trace.AlwaysSample(), trace.SpanKindServer)
+				} else {
+					currentCtx, _ = observability.New(currentCtx, h.ServiceName(), "hatchery.JobReceive", trace.AlwaysSample(), trace.SpanKindServer)
+				}
 
 				r, _ := j.Header.Get(sdk.WorkflowRunHeader)
 				w, _ := j.Header.Get(sdk.WorkflowHeader)
@@ -243,13 +256,6 @@ func Create(ctx context.Context, h Interface) error {
 				continue
 			}
 
-			//Check if hatchery if able to start a new worker
-			if !checkCapacities(ctx, h) {
-				log.Info("hatchery %s is not able to provision new worker", h.Service().Name)
-				endTrace("no capacities")
-				continue
-			}
-
 			workerRequest := workerStarterRequest{
 				ctx:               currentCtx,
 				cancel:            endTrace,
@@ -262,15 +268,30 @@ func Create(ctx context.Context, h Interface) error {
 				workflowNodeRunID: j.WorkflowNodeRunID,
 			}
 
-			// Check at least one worker model can match
-			var chosenModel *sdk.Model
-			for i := range models {
-				if canRunJob(h, workerRequest, models[i]) {
-					chosenModel = &models[i]
-					break
+			//Check if hatchery if able to start a new worker
+			if !checkCapacities(ctx, h) {
+				log.Info("hatchery %s is not able to provision new worker", h.Service().Name)
+				// Hint the users that a worker of a job with a lower priority could be stopped
+				if j.Priority >= sdk.WorkflowRunPriorityHigh {
+					log.Warning("hatchery %s is full, %s priority job %d is waiting", h.Service().Name, sdk.WorkflowRunPriorityName(j.Priority), j.ID)
+					SendSpawnInfo(currentCtx, h, j.ID, sdk.SpawnMsg{
+						ID:   sdk.MsgSpawnInfoHatcheryFullForPriority.ID,
+						Args: []interface{}{h.Service().Name, sdk.WorkflowRunPriorityName(j.Priority)},
+					})
 				}
+				// The job waits because of the capacities: it is the pressure used by the autoscaling
+				if scaler != nil {
+					if m := chooseModel(h, workerRequest, models); m != nil {
+						scaler.observe(j, *m, time.Now())
+					}
+				}
+				endTrace("no capacities")
+				continue
 			}
 
+			// Check at least one worker model can match
+			chosenModel := chooseModel(h, workerRequest, models)
+
 			// No model has been found, let's send a failing result
 			if chosenModel == nil {
 				workerStartResultChan <- workerStarterResult{
@@ -285,13 +306,19 @@ func Create(ctx context.Context, h Interface) error {
 
 			//We got a model, let's start a worker
 			workerRequest.model = *chosenModel
+			if scaler != nil {
+				scaler.observe(j, *chosenModel, time.Now())
+			}
 
 			//Ask to start
 			log.Debug("hatchery> Request a worker for job %d (%.3f seconds elapsed)", j.ID, time.Since(t0).Seconds())
 			workersStartChan <- workerRequest
 
 		case <-tickerProvision.C:
-			provisioning(h, models)
+			if scaler != nil {
+				scaler.compute(models, time.Now())
+			}
+			provisioning(h, models, scaler)
 
 		case <-tickerRegister.C:
 			if err := workerRegister(ctx, h, workersStartChan); err != nil {
@@ -319,6 +346,16 @@ func CheckRequirement(r sdk.Requirement) (bool, error) {
 	}
 }
 
+// chooseModel returns the first model able to run the job, nil if there is none
+func chooseModel(h Interface, j workerStarterRequest, models []sdk.Model) *sdk.Model {
+	for i := range models {
+		if canRunJob(h, j, models[i]) {
+			return &models[i]
+		}
+	}
+	return nil
+}
+
 func canRunJob(h Interface, j workerStarterRequest, model sdk.Model) bool {
 	if model.Type != h.ModelType() {
 		log.Debug("canRunJob> model %s type:%s current hatchery modelType: %s", model.Name, model.Type, h.ModelType())
@@ -431,6 +468,17 @@ func SendSpawnInfo(ctx context.Context, h Interface, jobID int64, spawnMsg sdk.S
 	cancel()
 }
 
+// WorkerLost asks the API to replace in queue the job of a worker whose instance was preempted,
+// the run is not failed
+func WorkerLost(ctx context.Context, h Interface, jobID int64, workerName, reason string) {
+	log.Info("hatchery> worker %s lost (%s), replacing job %d in queue", workerName, reason, jobID)
+	ctxc, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	if err := h.CDSClient().QueueJobWorkerLost(ctxc, jobID, sdk.WorkerLostForm{WorkerName: workerName, Reason: reason}); err != nil {
+		log.Warning("hatchery> cannot replace job %d of lost worker %s in queue: %s", jobID, workerName, err)
+	}
+}
+
 func logTime(h Interface, name string, then time.Time) {
 	d := time.Since(then)
 	if d > time.Duration(h.Configuration().LogOptions.SpawnOptions.ThresholdCritical)*time.Second {
@@ -59,7 +59,7 @@ func checkCapacities(ctx context.Context, h Interface) bool {
 	return true
 }
 
-func provisioning(h Interface, models []sdk.Model) {
+func provisioning(h Interface, models []sdk.Model, scaler *autoscaler) {
 	if h.Configuration().Provision.Disabled {
 		log.Debug("provisioning> disabled on this hatchery")
 		return
@@ -75,7 +75,11 @@ func provisioning(h Interface, models []sdk.Model) {
 		}
 		if models[k].Type == h.ModelType() {
 			existing := h.WorkersStartedByModel(&models[k])
-			for i := existing; i < int(models[k].Provision); i++ {
+			target := int(models[k].Provision)
+			if scaler != nil {
+				target = scaler.target(models[k])
+			}
+			for i := existing; i < target; i++ {
 				go func(m sdk.Model) {
 					if name, errSpawn := h.SpawnWorker(context.Background(), SpawnArguments{Model: m, JobID: 0, Requirements: nil, LogInfo: "spawn for provision"}); errSpawn != nil {
 						log.Warning("provisioning> cannot spawn worker %s with model %s for provisioning: %s", name, m.Name, errSpawn)
@@ -143,7 +143,8 @@ func spawnWorkerForJob(h Interface, j workerStarterRequest) (bool, error) {
 
 	_, next := observability.Span(ctx, "hatchery.QueueJobBook")
 	ctxt, cancel := context.WithTimeout(ctx, 10*time.Second)
-	if err := h.CDSClient().QueueJobBook(ctxt, j.id); err != nil {
+	confirmed, err := bookJob(ctxt, h, j.id)
+	if err != nil {
 		next()
 		// perhaps already booked by another hatchery
 		log.Info("hatchery> spawnWorkerForJob> %d - cannot book job %d %s: %s", j.timestamp, j.id, j.model.Name, err)
@@ -154,6 +155,26 @@ func spawnWorkerForJob(h Interface, j workerStarterRequest) (bool, error) {
 	cancel()
 	log.Debug("hatchery> spawnWorkerForJob> %d - send book job %d %s by hatchery %d", j.timestamp, j.id, j.model.Name, h.ID())
 
+	// the job is reserved, the hatchery checks it can still spawn the worker before confirming the booking
+	if !h.CanSpawn(&j.model, j.id, j.requirements) {
+		log.Info("hatchery> spawnWorkerForJob> %d - cannot spawn model %s for job %d anymore, releasing it", j.timestamp, j.model.Name, j.id)
+		releaseJob(h, j.id)
+		return false, nil
+	}
+	if !confirmed {
+		_, next = observability.Span(ctx, "hatchery.QueueJobConfirmBook")
+		ctxt, cancel = context.WithTimeout(ctx, 10*time.Second)
+		if err := h.CDSClient().QueueJobConfirmBook(ctxt, j.id); err != nil {
+			next()
+			// the reservation expired, another hatchery may have booked the job
+			log.Info("hatchery> spawnWorkerForJob> %d - cannot confirm booking of job %d %s: %s", j.timestamp, j.id, j.model.Name, err)
+			cancel()
+			return false, nil
+		}
+		next()
+		cancel()
+	}
+
 	start := time.Now()
 	SendSpawnInfo(ctx, h, j.id, sdk.SpawnMsg{
 		ID:   sdk.MsgSpawnInfoHatcheryStarts.ID,
@@ -172,9 +193,12 @@ func spawnWorkerForJob(h Interface, j workerStarterRequest) (bool, error) {
 		})
 		log.Error("hatchery %s cannot spawn worker %s for job %d: %v", h.Service().Name, j.model.Name, j.id, errSpawn)
 		next()
+		releaseJob(h, j.id)
 		return false, nil
 	}
 
+	stats.Record(WithTags(ctx, h), h.Metrics().SpawnDuration.M(float64(time.Since(start))/float64(time.Millisecond)))
+
 	SendSpawnInfo(ctx, h, j.id, sdk.SpawnMsg{
 		ID: sdk.MsgSpawnInfoHatcheryStartsSuccessfully.ID,
 		Args: []interface{}{
@@ -192,3 +216,10 @@ func spawnWorkerForJob(h Interface, j workerStarterRequest) (bool, error) {
 	}
 	return true, nil // ok for this job
 }
+
+// releaseJob releases the booking of a job the hatchery will not spawn a worker for, so that another hatchery can book it
+func releaseJob(h Interface, jobID int64) {
+	if err := h.CDSClient().QueueJobRelease(jobID); err != nil {
+		log.Warning("hatchery> cannot release job %d: %v", jobID, err)
+	}
+}
@@ -1,5 +1,7 @@
 package sdk
 
+import "time"
+
 // These are constants about hooks
 const (
 	WebHookModelName              = "WebHook"
@@ -9,6 +11,7 @@ const (
 	KafkaHookModelName            = "Kafka hook"
 	RabbitMQHookModelName         = "RabbitMQ hook"
 	WorkflowModelName             = "Workflow"
+	UpstreamWorkflowModelName     = "Upstream Workflow"
 	HookConfigProject             = "project"
 	HookConfigWorkflow            = "workflow"
 	HookConfigTargetProject       = "target_project"
@@ -20,6 +23,9 @@ const (
 	HookConfigIcon                = "hookIcon"
 	WebHookModelConfigMethod      = "method"
 	RepositoryWebHookModelMethod  = "method"
+	RepositoryWebHookIncludePaths = "include_paths"
+	RepositoryWebHookExcludePaths = "exclude_paths"
+	RepositoryWebHookSecret       = "webHookSecret"
 	SchedulerModelCron            = "cron"
 	SchedulerModelTimezone        = "timezone"
 	Payload                       = "payload"
@@ -31,6 +37,10 @@ const (
 	RabbitMQHookModelExchangeType = "exchange_type"
 	RabbitMQHookModelExchangeName = "exchange_name"
 	RabbitMQHookModelConsumerTag  = "consumer_tag"
+	UpstreamWorkflowProject       = "upstream_project"
+	UpstreamWorkflowWorkflow      = "upstream_workflow"
+	UpstreamWorkflowNode          = "upstream_node"
+	UpstreamWorkflowBranch        = "upstream_branch"
 )
 
 // Here are the default hooks
@@ -43,6 +53,7 @@ var (
 		&KafkaHookModel,
 		&RabbitMQHookModel,
 		&WorkflowModel,
+		&UpstreamWorkflowModel,
 	}
 
 	BuiltinOutgoingHookModels = []*WorkflowHookModel{
@@ -142,6 +153,16 @@ var (
 				Configurable: false,
 				Type:         HookConfigTypeString,
 			},
+			RepositoryWebHookIncludePaths: {
+				Value:        "",
+				Configurable: true,
+				Type:         HookConfigTypeString,
+			},
+			RepositoryWebHookExcludePaths: {
+				Value:        "",
+				Configurable: true,
+				Type:         HookConfigTypeString,
+			},
 		},
 	}
 
@@ -193,6 +214,33 @@ var (
 		Icon:       "sitemap",
 	}
 
+	UpstreamWorkflowModel = WorkflowHookModel{
+		Author:     "CDS",
+		Type:       WorkflowHookModelBuiltin,
+		Identifier: "github.com/ovh/cds/hook/builtin/upstreamworkflow",
+		Name:       UpstreamWorkflowModelName,
+		Icon:       "sitemap",
+		DefaultConfig: WorkflowNodeHookConfig{
+			UpstreamWorkflowProject: {
+				Configurable: true,
+				Type:         HookConfigTypeProject,
+			},
+			UpstreamWorkflowWorkflow: {
+				Configurable: true,
+				Type:         HookConfigTypeWorkflow,
+			},
+			UpstreamWorkflowNode: {
+				Configurable: true,
+				Type:         HookConfigTypeString,
+			},
+			UpstreamWorkflowBranch: {
+				Value:        "**",
+				Configurable: true,
+				Type:         HookConfigTypeString,
+			},
+		},
+	}
+
 	OutgoingWebHookModel = WorkflowHookModel{
 		Author:     "CDS",
 		Type:       WorkflowHookModelBuiltin,
@@ -258,7 +306,29 @@ func GetDefaultHookModel(modelName string) WorkflowHookModel {
 		return GitPollerModel
 	case WorkflowModelName:
 		return WorkflowModel
+	case UpstreamWorkflowModelName:
+		return UpstreamWorkflowModel
 	}
 
 	return WebHookModel
 }
+
+// These are the states of a repository webhook checked on its VCS server
+const (
+	RepositoryWebHookStatusOK       = "ok"
+	RepositoryWebHookStatusRepaired = "repaired"
+	RepositoryWebHookStatusBroken   = "broken"
+)
+
+// RepositoryWebHookReport is the result of the verification of a repository webhook on its VCS server
+type RepositoryWebHookReport struct {
+	UUID       string    `json:"uuid"`
+	Project    string    `json:"project"`
+	Workflow   string    `json:"workflow"`
+	VCSServer  string    `json:"vcs_server"`
+	Repository string    `json:"repository"`
+	WebHookID  string    `json:"webhook_id"`
+	Status     string    `json:"status"`
+	Message    string    `json:"message,omitempty"`
+	Date       time.Time `json:"date"`
+}
@@ -26,6 +26,7 @@ type TaskExecution struct {
 	RabbitMQ            *RabbitMQTaskExecution  `json:"rabbitmq,omitempty" cli:"-"`
 	ScheduledTask       *ScheduledTaskExecution `json:"scheduled_task,omitempty" cli:"-"`
 	Status              string                  `json:"status" cli:"status"`
+	TraceParent         string                  `json:"traceparent,omitempty" cli:"-"`
 }
 
 // WebHookExecution contains specific data for a webhook execution
@@ -2,8 +2,110 @@ package sdk
 
 // This is the buitin integration model
 const (
-	KafkaIntegrationModel    = "Kafka"
-	RabbitMQIntegrationModel = "RabbitMQ"
+	KafkaIntegrationModel           = "Kafka"
+	RabbitMQIntegrationModel        = "RabbitMQ"
+	VaultIntegrationModel           = "Vault"
+	AWSSecretsIntegrationModel      = "AWS Secrets"
+	DockerRegistryIntegrationModel  = "Docker Registry"
+	ArtifactManagerIntegrationModel = "Artifact Manager"
+	ArtifactoryIntegrationModel     = "Artifactory"
+	KubernetesIntegrationModel      = "Kubernetes"
+	TerraformIntegrationModel       = "Terraform"
+	MonitoringIntegrationModel      = "Monitoring"
+	OAuth2IntegrationModel          = "OAuth2 Client Credentials"
+)
+
+// These are the configuration keys of the Vault integration
+const (
+	VaultConfigURL        = "url"
+	VaultConfigAuthMethod = "auth method"
+	VaultConfigToken      = "token"
+	VaultConfigRoleID     = "role id"
+	VaultConfigSecretID   = "secret id"
+	VaultConfigMount      = "mount"
+	VaultConfigPaths      = "paths"
+
+	VaultAuthMethodToken   = "token"
+	VaultAuthMethodAppRole = "approle"
+)
+
+// These are the configuration keys of the AWS Secrets integration
+const (
+	AWSSecretsConfigRegion          = "region"
+	AWSSecretsConfigAccessKeyID     = "access key id"
+	AWSSecretsConfigSecretAccessKey = "secret access key"
+)
+
+// These are the configuration keys of the Docker Registry integration, given to the jobs as cds.integration.url,
+// cds.integration.username, cds.integration.password and cds.integration.mirror
+const (
+	DockerRegistryConfigURL      = "url"
+	DockerRegistryConfigUsername = "username"
+	DockerRegistryConfigPassword = "password"
+	DockerRegistryConfigMirror   = "mirror"
+)
+
+// DockerRegistryEnvironmentVariablePrefix is the prefix of the environment variables overriding the configuration
+// of the Docker Registry integration of a pipeline context, ie. the variable registry.url of an environment
+// overrides the url of the registry
+const DockerRegistryEnvironmentVariablePrefix = "registry."
+
+// DockerRegistryConfigKeys are the configuration keys of the Docker Registry integration
+var DockerRegistryConfigKeys = []string{DockerRegistryConfigURL, DockerRegistryConfigUsername, DockerRegistryConfigPassword, DockerRegistryConfigMirror}
+
+// These are the configuration keys of the Artifact Manager integration, used by the Artifact Push action
+const (
+	ArtifactManagerConfigPlatform = "platform"
+	ArtifactManagerConfigURL      = "url"
+	ArtifactManagerConfigUsername = "username"
+	ArtifactManagerConfigPassword = "password"
+
+	ArtifactManagerPlatformArtifactory = "artifactory"
+	ArtifactManagerPlatformNexus       = "nexus"
+)
+
+// These are the configuration keys of the Artifactory integration, used to promote the builds
+const (
+	ArtifactoryConfigURL                = "url"
+	ArtifactoryConfigToken              = "token"
+	ArtifactoryConfigRepositoryMappings = "repository mappings"
+)
+
+// These are the configuration keys of the Kubernetes integration, used by the DeployHelm action.
+// The cluster is given by a kubeconfig, or by its api url with a token and a certificate authority.
+const (
+	KubernetesConfigKubeconfig = "kubeconfig"
+	KubernetesConfigAPIURL     = "api url"
+	KubernetesConfigToken      = "token"
+	KubernetesConfigCA         = "certificate authority"
+	KubernetesConfigNamespace  = "namespace"
+)
+
+// These are the configuration keys of the Terraform integration, used by the TerraformPlan and TerraformApply actions
+const (
+	TerraformConfigBackendConfig = "backend config"
+	TerraformConfigCredentials   = "credentials"
+)
+
+// These are the configuration keys of the OAuth2 Client Credentials integration, used to give an access token to the jobs
+const (
+	OAuth2ConfigTokenURL     = "token url"
+	OAuth2ConfigClientID     = "client id"
+	OAuth2ConfigClientSecret = "client secret"
+	OAuth2ConfigScopes       = "scopes"
+	OAuth2ConfigAudience     = "audience"
+)
+
+// These are the configuration keys of the Monitoring integration, used by the CheckCanaryMetrics action to query
+// the metrics of Prometheus or of Datadog
+const (
+	MonitoringConfigPlatform       = "platform"
+	MonitoringConfigURL            = "url"
+	MonitoringConfigAPIKey         = "api key"
+	MonitoringConfigApplicationKey = "application key"
+
+	MonitoringPlatformPrometheus = "prometheus"
+	MonitoringPlatformDatadog    = "datadog"
 )
 
 // Here are the default plateform models
@@ -11,6 +113,15 @@ var (
 	BuiltinIntegrationModels = []*IntegrationModel{
 		&KafkaIntegration,
 		&RabbitMQIntegration,
+		&VaultIntegration,
+		&AWSSecretsIntegration,
+		&DockerRegistryIntegration,
+		&ArtifactManagerIntegration,
+		&ArtifactoryIntegration,
+		&KubernetesIntegration,
+		&TerraformIntegration,
+		&OAuth2Integration,
+		&MonitoringIntegration,
 	}
 	// KafkaIntegration represent a kafka integration
 	KafkaIntegration = IntegrationModel{
@@ -52,6 +163,238 @@ var (
 		Disabled: false,
 		Hook:     true,
 	}
+	// VaultIntegration represent a HashiCorp Vault integration, used to resolve project secrets at job start
+	VaultIntegration = IntegrationModel{
+		Name:       VaultIntegrationModel,
+		Author:     "CDS",
+		Identifier: "github.com/ovh/cds/integration/builtin/vault",
+		Icon:       "",
+		DefaultConfig: IntegrationConfig{
+			VaultConfigURL: IntegrationConfigValue{
+				Type: IntegrationConfigTypeString,
+			},
+			VaultConfigAuthMethod: IntegrationConfigValue{
+				Type:        IntegrationConfigTypeString,
+				Value:       VaultAuthMethodToken,
+				Description: "token or approle",
+			},
+			VaultConfigToken: IntegrationConfigValue{
+				Type: IntegrationConfigTypePassword,
+			},
+			VaultConfigRoleID: IntegrationConfigValue{
+				Type: IntegrationConfigTypeString,
+			},
+			VaultConfigSecretID: IntegrationConfigValue{
+				Type: IntegrationConfigTypePassword,
+			},
+			VaultConfigMount: IntegrationConfigValue{
+				Type:  IntegrationConfigTypeString,
+				Value: "secret",
+			},
+			VaultConfigPaths: IntegrationConfigValue{
+				Type:        IntegrationConfigTypeText,
+				Description: "KV v2 paths to load, one per line",
+			},
+		},
+		Disabled: false,
+	}
+	// AWSSecretsIntegration represent an AWS Secrets Manager and SSM Parameter Store integration,
+	// used to resolve secret variables referencing an arn at job start
+	AWSSecretsIntegration = IntegrationModel{
+		Name:       AWSSecretsIntegrationModel,
+		Author:     "CDS",
+		Identifier: "github.com/ovh/cds/integration/builtin/aws-secrets",
+		Icon:       "",
+		DefaultConfig: IntegrationConfig{
+			AWSSecretsConfigRegion: IntegrationConfigValue{
+				Type:        IntegrationConfigTypeString,
+				Description: "default region, used for ssm: references",
+			},
+			AWSSecretsConfigAccessKeyID: IntegrationConfigValue{
+				Type: IntegrationConfigTypeString,
+			},
+			AWSSecretsConfigSecretAccessKey: IntegrationConfigValue{
+				Type: IntegrationConfigTypePassword,
+			},
+		},
+		Disabled: false,
+	}
+	// DockerRegistryIntegration represent an OCI registry, used by the image build actions to push images and by the
+	// hatcheries to pull the images of the worker models
+	DockerRegistryIntegration = IntegrationModel{
+		Name:       DockerRegistryIntegrationModel,
+		Author:     "CDS",
+		Identifier: "github.com/ovh/cds/integration/builtin/docker-registry",
+		Icon:       "",
+		DefaultConfig: IntegrationConfig{
+			DockerRegistryConfigURL: IntegrationConfigValue{
+				Type:        IntegrationConfigTypeString,
+				Description: "registry host, example: registry.example.com or registry.example.com/my-namespace",
+			},
+			DockerRegistryConfigUsername: IntegrationConfigValue{
+				Type: IntegrationConfigTypeString,
+			},
+			DockerRegistryConfigPassword: IntegrationConfigValue{
+				Type: IntegrationConfigTypePassword,
+			},
+			DockerRegistryConfigMirror: IntegrationConfigValue{
+				Type:        IntegrationConfigTypeString,
+				Description: "pull-through cache used to pull the base images, example: mirror.example.com (OPTIONAL)",
+			},
+		},
+		Disabled: false,
+	}
+	// ArtifactManagerIntegration represent an Artifactory or a Nexus server, used by the Artifact Push action
+	ArtifactManagerIntegration = IntegrationModel{
+		Name:       ArtifactManagerIntegrationModel,
+		Author:     "CDS",
+		Identifier: "github.com/ovh/cds/integration/builtin/artifact-manager",
+		Icon:       "",
+		DefaultConfig: IntegrationConfig{
+			ArtifactManagerConfigPlatform: IntegrationConfigValue{
+				Type:        IntegrationConfigTypeString,
+				Value:       ArtifactManagerPlatformArtifactory,
+				Description: "artifactory or nexus",
+			},
+			ArtifactManagerConfigURL: IntegrationConfigValue{
+				Type:        IntegrationConfigTypeString,
+				Description: "example: https://artifactory.example.com/artifactory or https://nexus.example.com",
+			},
+			ArtifactManagerConfigUsername: IntegrationConfigValue{
+				Type: IntegrationConfigTypeString,
+			},
+			ArtifactManagerConfigPassword: IntegrationConfigValue{
+				Type:        IntegrationConfigTypePassword,
+				Description: "password or api token",
+			},
+		},
+		Disabled: false,
+	}
+	// ArtifactoryIntegration represent an Artifactory server, used to promote the build-info of the runs
+	ArtifactoryIntegration = IntegrationModel{
+		Name:       ArtifactoryIntegrationModel,
+		Author:     "CDS",
+		Identifier: "github.com/ovh/cds/integration/builtin/artifactory",
+		Icon:       "",
+		DefaultConfig: IntegrationConfig{
+			ArtifactoryConfigURL: IntegrationConfigValue{
+				Type:        IntegrationConfigTypeString,
+				Description: "example: https://artifactory.example.com/artifactory",
+			},
+			ArtifactoryConfigToken: IntegrationConfigValue{
+				Type: IntegrationConfigTypePassword,
+			},
+			ArtifactoryConfigRepositoryMappings: IntegrationConfigValue{
+				Type:        IntegrationConfigTypeText,
+				Description: "one promotion status per line: <status>: <source repository> -> <target repository>",
+			},
+		},
+		Disabled: false,
+	}
+	// KubernetesIntegration represent a Kubernetes cluster, used by the DeployHelm action
+	KubernetesIntegration = IntegrationModel{
+		Name:       KubernetesIntegrationModel,
+		Author:     "CDS",
+		Identifier: "github.com/ovh/cds/integration/builtin/kubernetes",
+		Icon:       "",
+		DefaultConfig: IntegrationConfig{
+			KubernetesConfigKubeconfig: IntegrationConfigValue{
+				Type:        IntegrationConfigTypePassword,
+				Description: "content of a kubeconfig file, or empty to use the api url and the token",
+			},
+			KubernetesConfigAPIURL: IntegrationConfigValue{
+				Type:        IntegrationConfigTypeString,
+				Description: "example: https://kubernetes.example.com:6443",
+			},
+			KubernetesConfigToken: IntegrationConfigValue{
+				Type:        IntegrationConfigTypePassword,
+				Description: "token of a service account",
+			},
+			KubernetesConfigCA: IntegrationConfigValue{
+				Type:        IntegrationConfigTypeText,
+				Description: "PEM certificate authority of the api server",
+			},
+			KubernetesConfigNamespace: IntegrationConfigValue{
+				Type:  IntegrationConfigTypeString,
+				Value: "default",
+			},
+		},
+		Disabled: false,
+	}
+	// TerraformIntegration represent the backend and the provider credentials of terraform
+	TerraformIntegration = IntegrationModel{
+		Name:       TerraformIntegrationModel,
+		Author:     "CDS",
+		Identifier: "github.com/ovh/cds/integration/builtin/terraform",
+		Icon:       "",
+		DefaultConfig: IntegrationConfig{
+			TerraformConfigBackendConfig: IntegrationConfigValue{
+				Type:        IntegrationConfigTypeText,
+				Description: "backend configuration, one key=value per line. Example: bucket=my-terraform-states",
+			},
+			TerraformConfigCredentials: IntegrationConfigValue{
+				Type:        IntegrationConfigTypePassword,
+				Description: "environment variables of the backend and the providers, one KEY=value per line. Example: AWS_SECRET_ACCESS_KEY=...",
+			},
+		},
+		Disabled: false,
+	}
+	// OAuth2Integration represent an OAuth2 authorization server, the API gets an access token with the
+	// client credentials grant when a job starts
+	OAuth2Integration = IntegrationModel{
+		Name:       OAuth2IntegrationModel,
+		Author:     "CDS",
+		Identifier: "github.com/ovh/cds/integration/builtin/oauth2",
+		Icon:       "",
+		DefaultConfig: IntegrationConfig{
+			OAuth2ConfigTokenURL: IntegrationConfigValue{
+				Type:        IntegrationConfigTypeString,
+				Description: "example: https://auth.example.com/oauth2/token",
+			},
+			OAuth2ConfigClientID: IntegrationConfigValue{
+				Type: IntegrationConfigTypeString,
+			},
+			OAuth2ConfigClientSecret: IntegrationConfigValue{
+				Type: IntegrationConfigTypePassword,
+			},
+			OAuth2ConfigScopes: IntegrationConfigValue{
+				Type:        IntegrationConfigTypeString,
+				Description: "scopes to request, separated by spaces (OPTIONAL)",
+			},
+			OAuth2ConfigAudience: IntegrationConfigValue{
+				Type:        IntegrationConfigTypeString,
+				Description: "audience to request, needed by some authorization servers (OPTIONAL)",
+			},
+		},
+		Disabled: false,
+	}
+	// MonitoringIntegration represent a metrics backend, used by the CheckCanaryMetrics action
+	MonitoringIntegration = IntegrationModel{
+		Name:       MonitoringIntegrationModel,
+		Author:     "CDS",
+		Identifier: "github.com/ovh/cds/integration/builtin/monitoring",
+		Icon:       "",
+		DefaultConfig: IntegrationConfig{
+			MonitoringConfigPlatform: IntegrationConfigValue{
+				Type:        IntegrationConfigTypeString,
+				Description: "prometheus or datadog",
+				Value:       MonitoringPlatformPrometheus,
+			},
+			MonitoringConfigURL: IntegrationConfigValue{
+				Type:        IntegrationConfigTypeString,
+				Description: "example: https://prometheus.example.com or https://api.datadoghq.com",
+			},
+			MonitoringConfigAPIKey: IntegrationConfigValue{
+				Type:        IntegrationConfigTypePassword,
+				Description: "bearer token of prometheus, or api key of datadog",
+			},
+			MonitoringConfigApplicationKey: IntegrationConfigValue{
+				Type:        IntegrationConfigTypePassword,
+				Description: "application key of datadog",
+			},
+		},
+		Disabled: false,
+	}
 )
 
 // IntegrationConfig represent the configuration of a plateform
@@ -129,6 +472,7 @@ type IntegrationModel struct {
 	Deployment              bool                         `json:"deployment" db:"deployment" yaml:"deployment" cli:"deployment_supported"`
 	Compute                 bool                         `json:"compute" db:"compute" yaml:"compute" cli:"compute_supported"`
 	Public                  bool                         `json:"public,omitempty" db:"public" yaml:"public,omitempty"`
+	OrganizationID          int64                        `json:"organization_id,omitempty" db:"organization_id" yaml:"-"`
 }
 
 //IsBuiltin checks is the model is builtin or not
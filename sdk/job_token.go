@@ -0,0 +1,43 @@
+package sdk
+
+import jwt "github.com/dgrijalva/jwt-go"
+
+// JobTokenVariable is the secret variable giving its OIDC token to a job, available as CDS_JOB_TOKEN
+const JobTokenVariable = "cds.job.token"
+
+// JobTokenClaims are the claims of the OIDC token of a job. The subject is
+// project:<key>:workflow:<name>:node:<name>:ref:<ref> so that the cloud providers can restrict their trust.
+type JobTokenClaims struct {
+	Project  string `json:"project"`
+	Workflow string `json:"workflow"`
+	Run      int64  `json:"run"`
+	Node     string `json:"node"`
+	Job      string `json:"job"`
+	Ref      string `json:"ref,omitempty"`
+	jwt.StandardClaims
+}
+
+// JSONWebKey is a public key of a JSON Web Key Set, see RFC 7517
+type JSONWebKey struct {
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+// JSONWebKeySet is the set of keys used to verify the OIDC tokens of the jobs
+type JSONWebKeySet struct {
+	Keys []JSONWebKey `json:"keys"`
+}
+
+// OpenIDConfiguration is the OpenID Connect discovery document of the issuer of the job tokens
+type OpenIDConfiguration struct {
+	Issuer                           string   `json:"issuer"`
+	JWKSURI                          string   `json:"jwks_uri"`
+	ResponseTypesSupported           []string `json:"response_types_supported"`
+	SubjectTypesSupported            []string `json:"subject_types_supported"`
+	IDTokenSigningAlgValuesSupported []string `json:"id_token_signing_alg_values_supported"`
+	ClaimsSupported                  []string `json:"claims_supported"`
+}
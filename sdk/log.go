@@ -1,6 +1,8 @@
 package sdk
 
 import (
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/golang/protobuf/ptypes"
@@ -21,3 +23,25 @@ func NewLog(pbJobID int64, value string, pipelineBuildID int64, stepOrder int) *
 
 	return l
 }
+
+// WorkflowRunStepLogFilename returns the name of the file of the logs of a step of a workflow run job
+func WorkflowRunStepLogFilename(wr WorkflowRun, nr WorkflowNodeRun, stageName string, job WorkflowNodeJobRun, stepOrder int) string {
+	return fmt.Sprintf("%s-step.%d.log", workflowRunJobLogPrefix(wr, nr, stageName, job), stepOrder)
+}
+
+// WorkflowRunServiceLogFilename returns the name of the file of the logs of a service of a workflow run job
+func WorkflowRunServiceLogFilename(wr WorkflowRun, nr WorkflowNodeRun, stageName string, job WorkflowNodeJobRun, serviceName string) string {
+	return fmt.Sprintf("%s-service.%s.log", workflowRunJobLogPrefix(wr, nr, stageName, job), strings.Replace(serviceName, " ", "", -1))
+}
+
+func workflowRunJobLogPrefix(wr WorkflowRun, nr WorkflowNodeRun, stageName string, job WorkflowNodeJobRun) string {
+	return fmt.Sprintf("%s-%d.%d-pipeline.%s-stage.%s-job.%s-status.%s",
+		wr.Workflow.Name,
+		wr.Number,
+		nr.SubNumber,
+		nr.WorkflowNodeName,
+		strings.Replace(stageName, " ", "", -1),
+		strings.Replace(job.Job.Action.Name, " ", "", -1),
+		job.Status,
+	)
+}
@@ -0,0 +1,106 @@
+package log
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Fields are the structured data attached to a log entry
+type Fields map[string]interface{}
+
+// Names of the fields used to correlate the logs of the services
+const (
+	FieldRequestID         = "request_id"
+	FieldProjectKey        = "project_key"
+	FieldWorkflow          = "workflow"
+	FieldWorkflowRunID     = "workflow_run_id"
+	FieldWorkflowRunNumber = "workflow_run_number"
+	FieldWorkflowNodeRunID = "workflow_node_run_id"
+	FieldJobID             = "job_id"
+	FieldWorker            = "worker"
+)
+
+type contextKey struct{}
+
+// ContextWithFields returns a copy of ctx carrying the given fields, in addition to the fields already in ctx
+func ContextWithFields(ctx context.Context, fields Fields) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	merged := Fields{}
+	for k, v := range FieldsFromContext(ctx) {
+		merged[k] = v
+	}
+	for k, v := range fields {
+		merged[k] = v
+	}
+	return context.WithValue(ctx, contextKey{}, merged)
+}
+
+// FieldsFromContext returns the fields carried by ctx
+func FieldsFromContext(ctx context.Context) Fields {
+	if ctx == nil {
+		return nil
+	}
+	f, _ := ctx.Value(contextKey{}).(Fields)
+	return f
+}
+
+// DebugCtx prints debug log with the fields of the context
+func DebugCtx(ctx context.Context, format string, values ...interface{}) {
+	if logger != nil {
+		logger.Logf("[DEBUG]    "+format+formatFields(ctx), values...)
+	} else {
+		log.WithFields(log.Fields(FieldsFromContext(ctx))).Debugf(format, values...)
+	}
+}
+
+// InfoCtx prints information log with the fields of the context
+func InfoCtx(ctx context.Context, format string, values ...interface{}) {
+	if logger != nil {
+		logger.Logf("[INFO]    "+format+formatFields(ctx), values...)
+	} else {
+		log.WithFields(log.Fields(FieldsFromContext(ctx))).Infof(format, values...)
+	}
+}
+
+// WarningCtx prints warnings for user with the fields of the context
+func WarningCtx(ctx context.Context, format string, values ...interface{}) {
+	if logger != nil {
+		logger.Logf("[WARN]    "+format+formatFields(ctx), values...)
+	} else {
+		log.WithFields(log.Fields(FieldsFromContext(ctx))).Warnf(format, values...)
+	}
+}
+
+// ErrorCtx prints error informations with the fields of the context
+func ErrorCtx(ctx context.Context, format string, values ...interface{}) {
+	if logger != nil {
+		logger.Logf("[ERROR]    "+format+formatFields(ctx), values...)
+	} else {
+		log.WithFields(log.Fields(FieldsFromContext(ctx))).Errorf(format, values...)
+	}
+}
+
+// formatFields returns the fields of the context as " key=value" for the loggers which are not structured.
+// Percent signs are escaped as the result is appended to a format string.
+func formatFields(ctx context.Context) string {
+	fields := FieldsFromContext(ctx)
+	if len(fields) == 0 {
+		return ""
+	}
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var b strings.Builder
+	for _, k := range keys {
+		fmt.Fprintf(&b, " %s=%v", k, fields[k])
+	}
+	return strings.Replace(b.String(), "%", "%%", -1)
+}
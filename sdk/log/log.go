@@ -7,6 +7,7 @@ import (
 	"io/ioutil"
 	"os"
 	"strings"
+	"time"
 
 	loghook "github.com/ovh/cds/sdk/log/hook"
 	log "github.com/sirupsen/logrus"
@@ -15,6 +16,7 @@ import (
 // Conf contains log configuration
 type Conf struct {
 	Level                  string
+	Format                 string
 	GraylogHost            string
 	GraylogPort            string
 	GraylogProtocol        string
@@ -58,7 +60,12 @@ func Initialize(conf *Conf) {
 	default:
 		log.SetLevel(log.InfoLevel)
 	}
-	log.SetFormatter(&CDSFormatter{})
+	switch conf.Format {
+	case "json":
+		log.SetFormatter(&log.JSONFormatter{TimestampFormat: time.RFC3339Nano})
+	default:
+		log.SetFormatter(&CDSFormatter{})
+	}
 
 	if conf.GraylogHost != "" && conf.GraylogPort != "" {
 		graylogcfg := &loghook.Config{
@@ -6,11 +6,14 @@ import (
 
 	"github.com/yuin/gluare"
 	lua "github.com/yuin/gopher-lua"
+
+	"github.com/ovh/cds/sdk"
 )
 
 // Check is a type which helps to call a lua script with variables to check something.
 // The lua script must return true/false
 // re.find , re.gsub, re.match, re.gmatch are available. These functions have the same API as Lua pattern match. gluare uses the Go regexp package, so you can use regular expressions that are supported in the Go regexp package.
+// semver_satisfies(version, range) and glob_match(patterns, value) are also available.
 
 type Check struct {
 	state                    *lua.LState
@@ -58,6 +61,9 @@ func NewCheck() (*Check, error) {
 		return nil, err
 	}
 
+	state.SetGlobal("semver_satisfies", state.NewFunction(semverSatisfies))
+	state.SetGlobal("glob_match", state.NewFunction(globMatch))
+
 	c := &Check{
 		state: state,
 	}
@@ -65,6 +71,21 @@ func NewCheck() (*Check, error) {
 	return c, nil
 }
 
+func semverSatisfies(L *lua.LState) int {
+	ok, err := sdk.SemverMatch(L.CheckString(1), L.CheckString(2))
+	if err != nil {
+		L.RaiseError("%v", err)
+		return 0
+	}
+	L.Push(lua.LBool(ok))
+	return 1
+}
+
+func globMatch(L *lua.LState) int {
+	L.Push(lua.LBool(sdk.GlobMatch(L.CheckString(1), L.CheckString(2))))
+	return 1
+}
+
 func (c *Check) exceptionHandler(L *lua.LState) int {
 	c.IsError = true
 	return 0
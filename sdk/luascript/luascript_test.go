@@ -74,3 +74,14 @@ func TestLuaCheckRegularExpression(t *testing.T) {
 	assert.False(t, l.Result)
 
 }
+
+func TestLuaCheckSemverAndGlob(t *testing.T) {
+	l, err := NewCheck()
+	test.NoError(t, err)
+	l.SetVariables(map[string]string{
+		"git.tag": "v2.3.1",
+	})
+	test.NoError(t, l.Perform("return semver_satisfies(git_tag, \">=2.0.0 <3.0.0\") and glob_match(\"v2.*\", git_tag)"))
+	assert.False(t, l.IsError)
+	assert.True(t, l.Result)
+}
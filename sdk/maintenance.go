@@ -3,3 +3,12 @@ package sdk
 const (
 	MaintenanceQueueName string = "cds_maintenance"
 )
+
+// MaintenanceStatus is the state of the maintenance mode. When enabled, no new run can be started and the hatcheries
+// stop booking jobs, the queue is drained once the building jobs are over.
+type MaintenanceStatus struct {
+	Enabled      bool  `json:"enabled" cli:"enabled"`
+	BuildingJobs int64 `json:"building_jobs" cli:"building_jobs"`
+	WaitingJobs  int64 `json:"waiting_jobs" cli:"waiting_jobs"`
+	Drained      bool  `json:"drained" cli:"drained"`
+}
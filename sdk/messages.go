@@ -47,6 +47,7 @@ var (
 	MsgEnvironmentKeyCreated               = &Message{"MsgEnvironmentKeyCreated", trad{FR: "La clé %s %s a été créée sur l'environnement %s", EN: "%s key %s created on environment %s"}, nil}
 	MsgJobNotValidActionNotFound           = &Message{"MsgJobNotValidActionNotFound", trad{FR: "Erreur de validation du Job %s : L'action %s à l'étape %d n'a pas été trouvée", EN: "Job %s validation Failure: Unknown action %s on step #%d"}, nil}
 	MsgJobNotValidInvalidActionParameter   = &Message{"MsgJobNotValidInvalidActionParameter", trad{FR: "Erreur de validation du Job %s : Le paramètre %s de l'étape %d - %s est invalide", EN: "Job %s validation Failure: Invalid parameter %s on step #%d %s"}, nil}
+	MsgJobActionDeprecated                 = &Message{"MsgJobActionDeprecated", trad{FR: "Attention le Job %s utilise l'action dépréciée %s à l'étape %d", EN: "Pay attention job %s uses the deprecated action %s on step #%d"}, nil}
 	MsgPipelineGroupUpdated                = &Message{"MsgPipelineGroupUpdated", trad{FR: "Les permissions du groupe %s sur le pipeline %s on été mises à jour", EN: "Permission for group %s on pipeline %s has been updated"}, nil}
 	MsgPipelineGroupAdded                  = &Message{"MsgPipelineGroupAdded", trad{FR: "Les permissions du groupe %s sur le pipeline %s on été ajoutées", EN: "Permission for group %s on pipeline %s has been added"}, nil}
 	MsgPipelineGroupDeleted                = &Message{"MsgPipelineGroupDeleted", trad{FR: "Les permissions du groupe %s sur le pipeline %s on été supprimées", EN: "Permission for group %s on pipeline %s has been deleted"}, nil}
@@ -80,10 +81,22 @@ var (
 	MsgWorkflowNodeMutexRelease            = &Message{"MsgWorkflowNodeMutexRelease", trad{FR: "Lancement du pipeline %s", EN: "Triggering pipeline %s"}, nil}
 	MsgWorkflowImportedUpdated             = &Message{"MsgWorkflowImportedUpdated", trad{FR: "Le workflow %s a été mis à jour", EN: "Workflow %s has been updated"}, nil}
 	MsgWorkflowImportedInserted            = &Message{"MsgWorkflowImportedInserted", trad{FR: "Le workflow %s a été créé", EN: "Workflow %s has been created"}, nil}
+	MsgSpawnInfoHatcheryFullForPriority    = &Message{"MsgSpawnInfoHatcheryFullForPriority", trad{FR: "La Hatchery %s a atteint son nombre maximum de workers, ce job de priorité %s attend la fin d'un worker. Arrêter un run de priorité inférieure libérerait un worker", EN: "Hatchery %s reached its maximum number of workers, this %s priority job waits for a worker to end. Stopping a run of lower priority would free a worker"}, nil}
+	MsgSpawnInfoWorkerLost                 = &Message{"MsgSpawnInfoWorkerLost", trad{FR: "Le worker %s a été perdu (instance préemptée), le job a été replacé dans la file d'attente", EN: "Worker %s was lost (preempted instance), the job has been replaced in the queue"}, nil}
+	MsgSpawnInfoWorkerServicesReady        = &Message{"MsgSpawnInfoWorkerServicesReady", trad{FR: "Le worker %s a attendu les services %s pendant %s", EN: "Worker %s waited for services %s during %s"}, nil}
+	MsgSpawnInfoWorkerServicesNotReady     = &Message{"MsgSpawnInfoWorkerServicesNotReady", trad{FR: "Le worker %s n'a pas pu démarrer le job: %s", EN: "Worker %s cannot start the job: %s"}, nil}
+	MsgSpawnInfoHelmReleaseDeployed        = &Message{"MsgSpawnInfoHelmReleaseDeployed", trad{FR: "La release helm %s a été déployée dans le namespace %s: révision %d, statut %s", EN: "Helm release %s has been deployed in namespace %s: revision %d, status %s"}, nil}
+	MsgSpawnInfoHelmReleaseRolledBack      = &Message{"MsgSpawnInfoHelmReleaseRolledBack", trad{FR: "⚠ Le déploiement de la release helm %s a échoué, elle a été restaurée: révision %d, statut %s", EN: "⚠ Deployment of helm release %s failed, it has been rolled back: revision %d, status %s"}, nil}
+	MsgSpawnInfoTerraformPlan              = &Message{"MsgSpawnInfoTerraformPlan", trad{FR: "Plan terraform %s: %d à ajouter, %d à modifier, %d à supprimer", EN: "Terraform plan %s: %d to add, %d to change, %d to destroy"}, nil}
 	MsgSpawnInfoHatcheryCannotStartJob     = &Message{"MsgSpawnInfoHatcheryCannotStart", trad{FR: "Aucune hatchery n'a pu démarrer de worker respectant vos pré-requis de job, merci de les vérifier.", EN: "No hatchery can spawn a worker corresponding your job's requirements. Please check your job's requirements."}, nil}
 	MsgWorkflowRunBranchDeleted            = &Message{"MsgWorkflowRunBranchDeleted", trad{FR: "La branche %s  a été supprimée", EN: "Branch %s has been deleted"}, nil}
 	MsgWorkflowTemplateImportedInserted    = &Message{"MsgWorkflowTemplateImportedInserted", trad{FR: "Le template de workflow %s/%s a été créé", EN: "Workflow template %s/%s has been created"}, nil}
 	MsgWorkflowTemplateImportedUpdated     = &Message{"MsgWorkflowTemplateImportedUpdated", trad{FR: "Le template de workflow %s/%s a été mis à jour", EN: "Workflow template %s/%s has been updated"}, nil}
+	MsgSpawnInfoEnvironmentFrozen          = &Message{"MsgSpawnInfoEnvironmentFrozen", trad{FR: "⚠ Job bloqué: %s. Il démarrera à la fin du gel ou si un administrateur le débloque", EN: "⚠ Job blocked: %s. It will start at the end of the freeze or when an administrator overrides it"}, nil}
+	MsgSpawnInfoEnvironmentUnfrozen        = &Message{"MsgSpawnInfoEnvironmentUnfrozen", trad{FR: "Le gel de l'environnement %s a été levé pour ce job par %s", EN: "The freeze of environment %s has been overridden for this job by %s"}, nil}
+	MsgWorkflowRunNoChangedFileMatch       = &Message{"MsgWorkflowRunNoChangedFileMatch", trad{FR: "Aucun fichier modifié ne correspond aux filtres de chemins du hook %s", EN: "No changed file matches the path filters of hook %s"}, nil}
+	MsgWorkflowRunChangedFilesUnknown      = &Message{"MsgWorkflowRunChangedFilesUnknown", trad{FR: "Les fichiers modifiés n'ont pas pu être récupérés, les filtres de chemins du hook %s ne peuvent pas être vérifiés", EN: "The changed files could not be resolved, the path filters of hook %s cannot be checked"}, nil}
+	MsgWorkflowRunSuperseded               = &Message{"MsgWorkflowRunSuperseded", trad{FR: "Le run a été annulé, il est remplacé par le run %d du commit %s", EN: "The run has been cancelled, it is superseded by run %d of commit %s"}, nil}
 )
 
 // Messages contains all sdk Messages
@@ -115,6 +128,7 @@ var Messages = map[string]*Message{
 	MsgEnvironmentKeyCreated.ID:               MsgEnvironmentKeyCreated,
 	MsgJobNotValidActionNotFound.ID:           MsgJobNotValidActionNotFound,
 	MsgJobNotValidInvalidActionParameter.ID:   MsgJobNotValidInvalidActionParameter,
+	MsgJobActionDeprecated.ID:                 MsgJobActionDeprecated,
 	MsgPipelineGroupUpdated.ID:                MsgPipelineGroupUpdated,
 	MsgPipelineGroupAdded.ID:                  MsgPipelineGroupAdded,
 	MsgPipelineGroupDeleted.ID:                MsgPipelineGroupDeleted,
@@ -148,8 +162,20 @@ var Messages = map[string]*Message{
 	MsgWorkflowNodeMutex.ID:                   MsgWorkflowNodeMutex,
 	MsgWorkflowNodeMutexRelease.ID:            MsgWorkflowNodeMutexRelease,
 	MsgSpawnInfoHatcheryCannotStartJob.ID:     MsgSpawnInfoHatcheryCannotStartJob,
+	MsgSpawnInfoHatcheryFullForPriority.ID:    MsgSpawnInfoHatcheryFullForPriority,
+	MsgSpawnInfoWorkerLost.ID:                 MsgSpawnInfoWorkerLost,
+	MsgSpawnInfoWorkerServicesReady.ID:        MsgSpawnInfoWorkerServicesReady,
+	MsgSpawnInfoWorkerServicesNotReady.ID:     MsgSpawnInfoWorkerServicesNotReady,
+	MsgSpawnInfoHelmReleaseDeployed.ID:        MsgSpawnInfoHelmReleaseDeployed,
+	MsgSpawnInfoHelmReleaseRolledBack.ID:      MsgSpawnInfoHelmReleaseRolledBack,
+	MsgSpawnInfoTerraformPlan.ID:              MsgSpawnInfoTerraformPlan,
 	MsgWorkflowRunBranchDeleted.ID:            MsgWorkflowRunBranchDeleted,
 	MsgSpawnInfoDeprecatedModel.ID:            MsgSpawnInfoDeprecatedModel,
+	MsgWorkflowRunNoChangedFileMatch.ID:       MsgWorkflowRunNoChangedFileMatch,
+	MsgWorkflowRunChangedFilesUnknown.ID:      MsgWorkflowRunChangedFilesUnknown,
+	MsgWorkflowRunSuperseded.ID:               MsgWorkflowRunSuperseded,
+	MsgSpawnInfoEnvironmentFrozen.ID:          MsgSpawnInfoEnvironmentFrozen,
+	MsgSpawnInfoEnvironmentUnfrozen.ID:        MsgSpawnInfoEnvironmentUnfrozen,
 }
 
 //Message represent a struc format translated messages
@@ -0,0 +1,125 @@
+package sdk
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"time"
+)
+
+// MFAHeader gives the second factor of a step-up verification on the sensitive operations. Its value is a TOTP code,
+// a recovery code or a base64url encoded MFAVerification.
+const MFAHeader = "X-Cds-Mfa"
+
+// MFAStepUpTTL is the duration in seconds of a step-up verification for a session
+const MFAStepUpTTL = 300
+
+// MFA is the second factor configuration of a local account, stored with its authentication data
+type MFA struct {
+	TOTPSecret          string               `json:"totpSecret,omitempty"`
+	TOTPEnabled         bool                 `json:"totpEnabled,omitempty"`
+	TOTPLastCounter     int64                `json:"totpLastCounter,omitempty"`
+	RecoveryCodes       []string             `json:"recoveryCodes,omitempty"`
+	WebAuthnCredentials []WebAuthnCredential `json:"webAuthnCredentials,omitempty"`
+}
+
+// Enabled returns true if the account has at least one second factor
+func (m MFA) Enabled() bool {
+	return m.TOTPEnabled || len(m.WebAuthnCredentials) > 0
+}
+
+// WebAuthnCredential is a security key registered by a user
+type WebAuthnCredential struct {
+	ID        string    `json:"id" cli:"id,key"`
+	Name      string    `json:"name" cli:"name"`
+	PublicKey []byte    `json:"public_key,omitempty" cli:"-"`
+	SignCount uint32    `json:"sign_count" cli:"-"`
+	Created   time.Time `json:"created" cli:"created"`
+}
+
+// MFAVerification is a second factor given at login or on a step-up verification
+type MFAVerification struct {
+	TOTP         string             `json:"totp,omitempty"`
+	RecoveryCode string             `json:"recovery_code,omitempty"`
+	WebAuthn     *WebAuthnAssertion `json:"webauthn,omitempty"`
+}
+
+// IsEmpty returns true if no second factor is given
+func (v MFAVerification) IsEmpty() bool {
+	return v.TOTP == "" && v.RecoveryCode == "" && v.WebAuthn == nil
+}
+
+// ParseMFAHeader reads the value of the MFA header
+func ParseMFAHeader(h string) (MFAVerification, error) {
+	var v MFAVerification
+	h = strings.TrimSpace(h)
+	switch {
+	case h == "":
+	case strings.Trim(h, "0123456789") == "":
+		v.TOTP = h
+	case strings.Count(h, "-") == 1:
+		v.RecoveryCode = h
+	default:
+		btes, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(h, "="))
+		if err != nil {
+			return v, NewErrorFrom(ErrWrongRequest, "invalid %s header", MFAHeader)
+		}
+		if err := json.Unmarshal(btes, &v); err != nil {
+			return v, NewErrorFrom(ErrWrongRequest, "invalid %s header", MFAHeader)
+		}
+	}
+	return v, nil
+}
+
+// WebAuthnAssertion is the response of a security key to navigator.credentials.get(), all fields are base64url encoded
+type WebAuthnAssertion struct {
+	CredentialID      string `json:"credential_id"`
+	ClientDataJSON    string `json:"client_data_json"`
+	AuthenticatorData string `json:"authenticator_data"`
+	Signature         string `json:"signature"`
+}
+
+// WebAuthnRegistration is the response of a security key to navigator.credentials.create(), all fields are base64url
+// encoded. The public key is the DER encoded key returned by AuthenticatorAttestationResponse.getPublicKey().
+type WebAuthnRegistration struct {
+	Name              string `json:"name"`
+	CredentialID      string `json:"credential_id"`
+	ClientDataJSON    string `json:"client_data_json"`
+	AuthenticatorData string `json:"authenticator_data"`
+	PublicKey         string `json:"public_key"`
+}
+
+// WebAuthnChallenge gives the options of navigator.credentials.create() and navigator.credentials.get()
+type WebAuthnChallenge struct {
+	Challenge     string   `json:"challenge"`
+	RPID          string   `json:"rp_id"`
+	UserID        string   `json:"user_id"`
+	UserName      string   `json:"user_name"`
+	CredentialIDs []string `json:"credential_ids"`
+	Timeout       int64    `json:"timeout"`
+}
+
+// TOTPEnrollment is returned when a user starts the enrollment of an authenticator application
+type TOTPEnrollment struct {
+	Secret string `json:"secret"`
+	URL    string `json:"url"`
+}
+
+// MFARecoveryCodes are the single use codes that replace a second factor, they are displayed once
+type MFARecoveryCodes struct {
+	Codes []string `json:"codes"`
+}
+
+// MFAStatus is the second factor configuration of a user
+type MFAStatus struct {
+	TOTP                bool                 `json:"totp"`
+	WebAuthnCredentials []WebAuthnCredential `json:"webauthn_credentials"`
+	RecoveryCodes       int                  `json:"recovery_codes"`
+	Required            bool                 `json:"required"`
+}
+
+// MFAPolicy is the second factor policy of a group, when required all the members have to enable two-factor
+// authentication
+type MFAPolicy struct {
+	Required bool `json:"required"`
+}
@@ -0,0 +1,29 @@
+package sdk
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseMFAHeader(t *testing.T) {
+	v, err := ParseMFAHeader("")
+	assert.NoError(t, err)
+	assert.True(t, v.IsEmpty())
+
+	v, err = ParseMFAHeader(" 123456 ")
+	assert.NoError(t, err)
+	assert.Equal(t, MFAVerification{TOTP: "123456"}, v)
+
+	v, err = ParseMFAHeader("abcde-fghij")
+	assert.NoError(t, err)
+	assert.Equal(t, MFAVerification{RecoveryCode: "abcde-fghij"}, v)
+
+	v, err = ParseMFAHeader(base64.URLEncoding.EncodeToString([]byte(`{"webauthn": {"credential_id": "abc"}}`)))
+	assert.NoError(t, err)
+	assert.Equal(t, "abc", v.WebAuthn.CredentialID)
+
+	_, err = ParseMFAHeader("not valid")
+	assert.Error(t, err)
+}
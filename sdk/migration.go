@@ -16,6 +16,16 @@ const (
 	MigrationStatusCanceled string = "CANCELED"
 )
 
+// Phases of an online migration, run in this order
+const (
+	// MigrationPhasePre is the phase of the pre hook, which usually enables the dual writes
+	MigrationPhasePre string = "PRE"
+	// MigrationPhaseExec is the phase of the migration itself, which usually backfills the existing data
+	MigrationPhaseExec string = "EXEC"
+	// MigrationPhasePost is the phase of the post hook, which usually switches the reads to the new data
+	MigrationPhasePost string = "POST"
+)
+
 // Migration represent a CDS migration
 type Migration struct {
 	ID        int64     `json:"id" db:"id" cli:"id"`
@@ -30,6 +40,12 @@ type Migration struct {
 	Major     uint64    `json:"major" db:"major" cli:"major"`
 	Minor     uint64    `json:"minor" db:"minor" cli:"minor"`
 	Patch     uint64    `json:"patch" db:"patch" cli:"patch"`
+	Phase     string    `json:"phase" db:"phase" cli:"phase"`
+	Cursor    int64     `json:"cursor" db:"batch_cursor" cli:"-"`
 
+	// PreFunc and PostFunc are run before and after ExecFunc, each phase is run
+	// once even if the API is restarted in the middle of the migration
+	PreFunc  func(ctx context.Context) error `json:"-" db:"-" cli:"-" yaml:"-"`
 	ExecFunc func(ctx context.Context) error `json:"-" db:"-" cli:"-" yaml:"-"`
+	PostFunc func(ctx context.Context) error `json:"-" db:"-" cli:"-" yaml:"-"`
 }
@@ -0,0 +1,28 @@
+package sdk
+
+import "time"
+
+// Organization groups projects of a same tenant. Its administrators manage all its projects and its quotas are shared
+// by all its projects, a zero quota is unlimited.
+type Organization struct {
+	ID                int64     `json:"id" db:"id" cli:"-"`
+	Name              string    `json:"name" db:"name" cli:"name,key"`
+	Description       string    `json:"description" db:"description" cli:"description"`
+	MaxConcurrentJobs int       `json:"max_concurrent_jobs" db:"max_concurrent_jobs" cli:"max_concurrent_jobs"`
+	MaxStorage        int64     `json:"max_storage" db:"max_storage" cli:"max_storage"`
+	MaxBuildMinutes   int       `json:"max_build_minutes" db:"max_build_minutes" cli:"max_build_minutes"`
+	Created           time.Time `json:"created" db:"created" cli:"created"`
+	Admins            []string  `json:"admins,omitempty" db:"-" cli:"admins"`
+	Projects          []string  `json:"projects,omitempty" db:"-" cli:"projects"`
+}
+
+// IsValid returns an error if the organization is not valid
+func (o Organization) IsValid() error {
+	if !NamePatternRegex.MatchString(o.Name) {
+		return NewErrorFrom(ErrInvalidName, "invalid organization name, it should match %s", NamePattern)
+	}
+	if o.MaxConcurrentJobs < 0 || o.MaxStorage < 0 || o.MaxBuildMinutes < 0 {
+		return NewErrorFrom(ErrWrongRequest, "organization quotas cannot be negative")
+	}
+	return nil
+}
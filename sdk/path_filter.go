@@ -0,0 +1,105 @@
+package sdk
+
+import (
+	"regexp"
+	"strings"
+)
+
+// PathFilters returns the list of path patterns from a comma separated string
+func PathFilters(s string) []string {
+	var res []string
+	for _, p := range strings.Split(s, ",") {
+		p = strings.TrimSpace(p)
+		if p != "" {
+			res = append(res, p)
+		}
+	}
+	return res
+}
+
+// JoinChangedFiles returns the value of the git.changed_files variable, one file per line as file names can
+// contain commas
+func JoinChangedFiles(files []string) string {
+	return strings.Join(files, "\n")
+}
+
+// SplitChangedFiles returns the list of files of the git.changed_files variable
+func SplitChangedFiles(s string) []string {
+	res := []string{}
+	for _, f := range strings.Split(s, "\n") {
+		if f != "" {
+			res = append(res, f)
+		}
+	}
+	return res
+}
+
+// PathMatch checks if the path matches the glob pattern.
+// It supports '*' (any sequence of characters except '/'), '**' (any sequence of characters
+// including '/') and '?' (any single character except '/').
+func PathMatch(pattern, path string) bool {
+	r, err := globToRegexp(pattern)
+	if err != nil {
+		return false
+	}
+	return r.MatchString(strings.TrimPrefix(path, "/"))
+}
+
+func globToRegexp(pattern string) (*regexp.Regexp, error) {
+	pattern = strings.TrimPrefix(pattern, "/")
+	var b strings.Builder
+	b.WriteString("^")
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+		switch c {
+		case '*':
+			if i+1 < len(pattern) && pattern[i+1] == '*' {
+				i++
+				// "**/" matches zero or more directories
+				if i+1 < len(pattern) && pattern[i+1] == '/' {
+					i++
+					b.WriteString("(.*/)?")
+				} else {
+					b.WriteString(".*")
+				}
+			} else {
+				b.WriteString("[^/]*")
+			}
+		case '?':
+			b.WriteString("[^/]")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	b.WriteString("$")
+	return regexp.Compile(b.String())
+}
+
+// FilterPaths returns the paths matching at least one of the includes patterns and none of the excludes patterns.
+// If there is no includes pattern, all the paths are included.
+func FilterPaths(paths, includes, excludes []string) []string {
+	var res []string
+	for _, p := range paths {
+		included := len(includes) == 0
+		for _, i := range includes {
+			if PathMatch(i, p) {
+				included = true
+				break
+			}
+		}
+		if !included {
+			continue
+		}
+		var excluded bool
+		for _, e := range excludes {
+			if PathMatch(e, p) {
+				excluded = true
+				break
+			}
+		}
+		if !excluded {
+			res = append(res, p)
+		}
+	}
+	return res
+}
@@ -0,0 +1,45 @@
+package sdk
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPathMatch(t *testing.T) {
+	tests := []struct {
+		pattern string
+		path    string
+		match   bool
+	}{
+		{"services/api/**", "services/api/main.go", true},
+		{"services/api/**", "services/api/handlers/user.go", true},
+		{"services/api/**", "services/hooks/main.go", false},
+		{"*.md", "README.md", true},
+		{"*.md", "docs/README.md", false},
+		{"**/*.md", "docs/README.md", true},
+		{"**/*.md", "README.md", true},
+		{"docs/?.txt", "docs/a.txt", true},
+		{"docs/?.txt", "docs/ab.txt", false},
+		{"/go.mod", "go.mod", true},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.match, PathMatch(tt.pattern, tt.path), "pattern %s on path %s", tt.pattern, tt.path)
+	}
+}
+
+func TestFilterPaths(t *testing.T) {
+	paths := []string{"services/api/main.go", "services/api/README.md", "services/hooks/main.go", "README.md"}
+
+	assert.Equal(t, paths, FilterPaths(paths, nil, nil))
+	assert.Equal(t, []string{"services/api/main.go", "services/api/README.md"}, FilterPaths(paths, PathFilters("services/api/**"), nil))
+	assert.Equal(t, []string{"services/api/main.go"}, FilterPaths(paths, PathFilters("services/api/**"), PathFilters("**/*.md")))
+	assert.Equal(t, []string{"services/api/main.go", "services/hooks/main.go"}, FilterPaths(paths, nil, PathFilters(" **/*.md , ")))
+	assert.Empty(t, FilterPaths(paths, PathFilters("ui/**"), nil))
+}
+
+func TestChangedFiles(t *testing.T) {
+	files := []string{"README.md", "docs/a,b.md"}
+	assert.Equal(t, files, SplitChangedFiles(JoinChangedFiles(files)))
+	assert.Equal(t, []string{}, SplitChangedFiles(""))
+}
@@ -39,6 +39,7 @@ type GRPCPluginBinary struct {
 	Perm             uint32          `json:"perm,omitempty" yaml:"-"`
 	MD5sum           string          `json:"md5sum,omitempty" yaml:"-"`
 	SHA512sum        string          `json:"sha512sum,omitempty" yaml:"-"`
+	SHA256sum        string          `json:"sha256sum,omitempty" yaml:"sha256sum,omitempty"`
 	TempURL          string          `json:"temp_url,omitempty" yaml:"-"`
 	TempURLSecretKey string          `json:"-" yaml:"-"`
 	Entrypoints      []string        `json:"entrypoints,omitempty" yaml:"entrypoints"`
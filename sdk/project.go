@@ -34,6 +34,8 @@ type Project struct {
 	Integrations     []ProjectIntegration `json:"integrations" yaml:"integrations" db:"-" cli:"-"`
 	Features         map[string]bool      `json:"features" yaml:"features" db:"-" cli:"-"`
 	Favorite         bool                 `json:"favorite" yaml:"favorite" db:"-" cli:"favorite"`
+	Archived         bool                 `json:"archived" yaml:"-" db:"archived" cli:"archived"`
+	OrganizationID   int64                `json:"organization_id,omitempty" yaml:"-" db:"organization_id" cli:"-"`
 }
 
 // IsValid returns error if the project is not valid
@@ -0,0 +1,77 @@
+package sdk
+
+import "time"
+
+// ProjectDashboard summarizes the health of the workflows of a project
+type ProjectDashboard struct {
+	ProjectKey             string                      `json:"project_key"`
+	Workflows              []WorkflowHealth            `json:"workflows"`
+	SuccessRate7Days       float64                     `json:"success_rate_7_days"`
+	SuccessRate30Days      float64                     `json:"success_rate_30_days"`
+	Queue                  ProjectQueueHealth          `json:"queue"`
+	AsCodePullRequests     []ProjectAsCodePullRequest  `json:"ascode_pull_requests"`
+	FailingOnDefaultBranch []WorkflowDefaultBranchFail `json:"failing_on_default_branch"`
+}
+
+// WorkflowHealth is the status of the last run of a workflow and its success rates. The success rates are the
+// ratio of the successful runs among the successful and failed runs, -1 without such runs.
+type WorkflowHealth struct {
+	Name              string     `json:"name" cli:"name,key"`
+	LastRunNumber     int64      `json:"last_run_number,omitempty" cli:"last_run"`
+	LastRunStatus     string     `json:"last_run_status,omitempty" cli:"status"`
+	LastRunDate       *time.Time `json:"last_run_date,omitempty" cli:"-"`
+	Runs7Days         int64      `json:"runs_7_days" cli:"-"`
+	Success7Days      int64      `json:"success_7_days" cli:"-"`
+	SuccessRate7Days  float64    `json:"success_rate_7_days" cli:"success_rate_7_days"`
+	Runs30Days        int64      `json:"runs_30_days" cli:"-"`
+	Success30Days     int64      `json:"success_30_days" cli:"-"`
+	SuccessRate30Days float64    `json:"success_rate_30_days" cli:"success_rate_30_days"`
+}
+
+// ComputeSuccessRates computes the success rates of the workflows and of the project
+func (d *ProjectDashboard) ComputeSuccessRates() {
+	rate := func(success, runs int64) float64 {
+		if runs == 0 {
+			return -1
+		}
+		return float64(success) / float64(runs)
+	}
+	var runs7, success7, runs30, success30 int64
+	for i := range d.Workflows {
+		w := &d.Workflows[i]
+		w.SuccessRate7Days = rate(w.Success7Days, w.Runs7Days)
+		w.SuccessRate30Days = rate(w.Success30Days, w.Runs30Days)
+		runs7, success7 = runs7+w.Runs7Days, success7+w.Success7Days
+		runs30, success30 = runs30+w.Runs30Days, success30+w.Success30Days
+	}
+	d.SuccessRate7Days = rate(success7, runs7)
+	d.SuccessRate30Days = rate(success30, runs30)
+}
+
+// ProjectQueueHealth are the jobs of a project waiting in the queue and the time the jobs of the last 7 days waited
+// before starting, in seconds
+type ProjectQueueHealth struct {
+	Waiting          int64   `json:"waiting"`
+	OldestWaiting    float64 `json:"oldest_waiting"`
+	Jobs7Days        int64   `json:"jobs_7_days"`
+	AverageWait7Days float64 `json:"average_wait_7_days"`
+	P95Wait7Days     float64 `json:"p95_wait_7_days"`
+}
+
+// ProjectAsCodePullRequest is a pull request opened from CDS on the repository of an as code workflow
+type ProjectAsCodePullRequest struct {
+	WorkflowName   string    `json:"workflow_name" cli:"workflow"`
+	PullRequestID  int64     `json:"pullrequest_id" cli:"id"`
+	PullRequestURL string    `json:"pullrequest_url" cli:"url"`
+	Username       string    `json:"username" cli:"username"`
+	CreationDate   time.Time `json:"creation_date" cli:"created"`
+}
+
+// WorkflowDefaultBranchFail is a workflow whose last run on the default branch of its repository failed
+type WorkflowDefaultBranchFail struct {
+	WorkflowName string    `json:"workflow_name" cli:"workflow,key"`
+	Repository   string    `json:"repository" cli:"repository"`
+	Branch       string    `json:"branch" cli:"branch"`
+	RunNumber    int64     `json:"run_number" cli:"run"`
+	RunDate      time.Time `json:"run_date" cli:"date"`
+}
@@ -0,0 +1,26 @@
+package sdk
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProjectDashboardComputeSuccessRates(t *testing.T) {
+	d := ProjectDashboard{
+		Workflows: []WorkflowHealth{
+			{Name: "build", Runs7Days: 4, Success7Days: 3, Runs30Days: 10, Success30Days: 5},
+			{Name: "deploy", Runs30Days: 6, Success30Days: 6},
+			{Name: "never-run"},
+		},
+	}
+	d.ComputeSuccessRates()
+
+	assert.Equal(t, 0.75, d.Workflows[0].SuccessRate7Days)
+	assert.Equal(t, 0.5, d.Workflows[0].SuccessRate30Days)
+	assert.Equal(t, -1.0, d.Workflows[1].SuccessRate7Days)
+	assert.Equal(t, 1.0, d.Workflows[1].SuccessRate30Days)
+	assert.Equal(t, -1.0, d.Workflows[2].SuccessRate30Days)
+	assert.Equal(t, 0.75, d.SuccessRate7Days)
+	assert.Equal(t, 11.0/16, d.SuccessRate30Days)
+}
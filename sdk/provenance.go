@@ -0,0 +1,96 @@
+package sdk
+
+import (
+	"fmt"
+	"time"
+)
+
+// Provenance of the artifacts, as in-toto statements with a SLSA provenance predicate
+// wrapped in DSSE envelopes.
+const (
+	InTotoStatementType         = "https://in-toto.io/Statement/v0.1"
+	InTotoPayloadType           = "application/vnd.in-toto+json"
+	SLSAProvenancePredicateType = "https://slsa.dev/provenance/v0.2"
+	CDSBuildType                = "https://github.com/ovh/cds/workflow@v1"
+)
+
+// InTotoStatement is an in-toto attestation about artifacts
+type InTotoStatement struct {
+	Type          string          `json:"_type"`
+	Subject       []InTotoSubject `json:"subject"`
+	PredicateType string          `json:"predicateType"`
+	Predicate     SLSAProvenance  `json:"predicate"`
+}
+
+// InTotoSubject is an artifact identified by its digests
+type InTotoSubject struct {
+	Name   string            `json:"name"`
+	Digest map[string]string `json:"digest"`
+}
+
+// SLSAProvenance describes how an artifact was built
+type SLSAProvenance struct {
+	Builder    SLSABuilder    `json:"builder"`
+	BuildType  string         `json:"buildType"`
+	Invocation SLSAInvocation `json:"invocation"`
+	Metadata   SLSAMetadata   `json:"metadata"`
+	Materials  []SLSAMaterial `json:"materials,omitempty"`
+}
+
+// SLSABuilder identifies the CDS instance which built an artifact
+type SLSABuilder struct {
+	ID string `json:"id"`
+}
+
+// SLSAInvocation describes the run which built an artifact
+type SLSAInvocation struct {
+	ConfigSource SLSAConfigSource  `json:"configSource"`
+	Parameters   map[string]string `json:"parameters,omitempty"`
+	Environment  map[string]string `json:"environment,omitempty"`
+}
+
+// SLSAConfigSource is the source of the run
+type SLSAConfigSource struct {
+	URI        string            `json:"uri,omitempty"`
+	Digest     map[string]string `json:"digest,omitempty"`
+	EntryPoint string            `json:"entryPoint"`
+}
+
+// SLSAMetadata gives the identifier and the dates of the run
+type SLSAMetadata struct {
+	BuildInvocationID string     `json:"buildInvocationId"`
+	BuildStartedOn    *time.Time `json:"buildStartedOn,omitempty"`
+	BuildFinishedOn   *time.Time `json:"buildFinishedOn,omitempty"`
+	Reproducible      bool       `json:"reproducible"`
+}
+
+// SLSAMaterial is a source used by the run
+type SLSAMaterial struct {
+	URI    string            `json:"uri"`
+	Digest map[string]string `json:"digest,omitempty"`
+}
+
+// DSSEEnvelope is a signed payload, see https://github.com/secure-systems-lab/dsse
+type DSSEEnvelope struct {
+	PayloadType string          `json:"payloadType"`
+	Payload     string          `json:"payload"`
+	Signatures  []DSSESignature `json:"signatures"`
+}
+
+// DSSESignature is a signature of a DSSE envelope
+type DSSESignature struct {
+	KeyID string `json:"keyid"`
+	Sig   string `json:"sig"`
+}
+
+// DSSEPreAuthEncoding returns the data signed for a payload of a DSSE envelope
+func DSSEPreAuthEncoding(payloadType string, payload []byte) []byte {
+	return []byte(fmt.Sprintf("DSSEv1 %d %s %d %s", len(payloadType), payloadType, len(payload), payload))
+}
+
+// ProvenanceKey is the public key of the CDS instance used to sign the provenance of the artifacts
+type ProvenanceKey struct {
+	KeyID     string `json:"keyid" cli:"keyid"`
+	Algorithm string `json:"algorithm" cli:"algorithm"`
+	PublicKey string `json:"public_key" cli:"public_key"`
+}
@@ -0,0 +1,212 @@
+// Code generated by protoc-gen-go.
+// source: queue.proto
+// DO NOT EDIT!
+
+package sdk
+
+import proto "github.com/golang/protobuf/proto"
+import fmt "fmt"
+import math "math"
+import google_protobuf "github.com/golang/protobuf/ptypes/timestamp"
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// JobBookRequest is sent by an hatchery to book a job of the queue
+// Generate *.pb.go files with:
+// 	protoc --go_out=plugins=grpc:. ./queue.proto
+type JobBookRequest struct {
+	JobID int64 `protobuf:"varint,1,opt,name=jobID" json:"jobID,omitempty"`
+}
+
+func (m *JobBookRequest) Reset()                    { *m = JobBookRequest{} }
+func (m *JobBookRequest) String() string            { return proto.CompactTextString(m) }
+func (*JobBookRequest) ProtoMessage()               {}
+func (*JobBookRequest) Descriptor() ([]byte, []int) { return fileDescriptor2, []int{0} }
+
+func (m *JobBookRequest) GetJobID() int64 {
+	if m != nil {
+		return m.JobID
+	}
+	return 0
+}
+
+// StepStatusUpdate is sent by a worker when the status of a step of its job changes
+// Generate *.pb.go files with:
+// 	protoc --go_out=plugins=grpc:. ./queue.proto
+type StepStatusUpdate struct {
+	JobID     int64                      `protobuf:"varint,1,opt,name=jobID" json:"jobID,omitempty"`
+	StepOrder int64                      `protobuf:"varint,2,opt,name=stepOrder" json:"stepOrder,omitempty"`
+	Status    string                     `protobuf:"bytes,3,opt,name=status" json:"status,omitempty"`
+	Start     *google_protobuf.Timestamp `protobuf:"bytes,4,opt,name=start" json:"start,omitempty"`
+	Done      *google_protobuf.Timestamp `protobuf:"bytes,5,opt,name=done" json:"done,omitempty"`
+}
+
+func (m *StepStatusUpdate) Reset()                    { *m = StepStatusUpdate{} }
+func (m *StepStatusUpdate) String() string            { return proto.CompactTextString(m) }
+func (*StepStatusUpdate) ProtoMessage()               {}
+func (*StepStatusUpdate) Descriptor() ([]byte, []int) { return fileDescriptor2, []int{1} }
+
+func (m *StepStatusUpdate) GetJobID() int64 {
+	if m != nil {
+		return m.JobID
+	}
+	return 0
+}
+
+func (m *StepStatusUpdate) GetStepOrder() int64 {
+	if m != nil {
+		return m.StepOrder
+	}
+	return 0
+}
+
+func (m *StepStatusUpdate) GetStatus() string {
+	if m != nil {
+		return m.Status
+	}
+	return ""
+}
+
+func (m *StepStatusUpdate) GetStart() *google_protobuf.Timestamp {
+	if m != nil {
+		return m.Start
+	}
+	return nil
+}
+
+func (m *StepStatusUpdate) GetDone() *google_protobuf.Timestamp {
+	if m != nil {
+		return m.Done
+	}
+	return nil
+}
+
+// LogChunk is a chunk of the logs of a step streamed by a worker. The offset is the position of the chunk
+// in the logs of the step, it allows the API to ignore the chunks sent again when the stream is resumed.
+// Generate *.pb.go files with:
+// 	protoc --go_out=plugins=grpc:. ./queue.proto
+type LogChunk struct {
+	JobID     int64                      `protobuf:"varint,1,opt,name=jobID" json:"jobID,omitempty"`
+	NodeRunID int64                      `protobuf:"varint,2,opt,name=nodeRunID" json:"nodeRunID,omitempty"`
+	StepOrder int64                      `protobuf:"varint,3,opt,name=stepOrder" json:"stepOrder,omitempty"`
+	Offset    int64                      `protobuf:"varint,4,opt,name=offset" json:"offset,omitempty"`
+	Val       string                     `protobuf:"bytes,5,opt,name=val" json:"val,omitempty"`
+	Done      *google_protobuf.Timestamp `protobuf:"bytes,6,opt,name=done" json:"done,omitempty"`
+}
+
+func (m *LogChunk) Reset()                    { *m = LogChunk{} }
+func (m *LogChunk) String() string            { return proto.CompactTextString(m) }
+func (*LogChunk) ProtoMessage()               {}
+func (*LogChunk) Descriptor() ([]byte, []int) { return fileDescriptor2, []int{2} }
+
+func (m *LogChunk) GetJobID() int64 {
+	if m != nil {
+		return m.JobID
+	}
+	return 0
+}
+
+func (m *LogChunk) GetNodeRunID() int64 {
+	if m != nil {
+		return m.NodeRunID
+	}
+	return 0
+}
+
+func (m *LogChunk) GetStepOrder() int64 {
+	if m != nil {
+		return m.StepOrder
+	}
+	return 0
+}
+
+func (m *LogChunk) GetOffset() int64 {
+	if m != nil {
+		return m.Offset
+	}
+	return 0
+}
+
+func (m *LogChunk) GetVal() string {
+	if m != nil {
+		return m.Val
+	}
+	return ""
+}
+
+func (m *LogChunk) GetDone() *google_protobuf.Timestamp {
+	if m != nil {
+		return m.Done
+	}
+	return nil
+}
+
+// LogAck acknowledges the logs of a step persisted by the API until the offset
+// Generate *.pb.go files with:
+// 	protoc --go_out=plugins=grpc:. ./queue.proto
+type LogAck struct {
+	JobID     int64 `protobuf:"varint,1,opt,name=jobID" json:"jobID,omitempty"`
+	StepOrder int64 `protobuf:"varint,2,opt,name=stepOrder" json:"stepOrder,omitempty"`
+	Offset    int64 `protobuf:"varint,3,opt,name=offset" json:"offset,omitempty"`
+}
+
+func (m *LogAck) Reset()                    { *m = LogAck{} }
+func (m *LogAck) String() string            { return proto.CompactTextString(m) }
+func (*LogAck) ProtoMessage()               {}
+func (*LogAck) Descriptor() ([]byte, []int) { return fileDescriptor2, []int{3} }
+
+func (m *LogAck) GetJobID() int64 {
+	if m != nil {
+		return m.JobID
+	}
+	return 0
+}
+
+func (m *LogAck) GetStepOrder() int64 {
+	if m != nil {
+		return m.StepOrder
+	}
+	return 0
+}
+
+func (m *LogAck) GetOffset() int64 {
+	if m != nil {
+		return m.Offset
+	}
+	return 0
+}
+
+func init() {
+	proto.RegisterType((*JobBookRequest)(nil), "github.com.ovh.cds.sdk.JobBookRequest")
+	proto.RegisterType((*StepStatusUpdate)(nil), "github.com.ovh.cds.sdk.StepStatusUpdate")
+	proto.RegisterType((*LogChunk)(nil), "github.com.ovh.cds.sdk.LogChunk")
+	proto.RegisterType((*LogAck)(nil), "github.com.ovh.cds.sdk.LogAck")
+}
+
+func init() { proto.RegisterFile("queue.proto", fileDescriptor2) }
+
+var fileDescriptor2 = []byte{
+	// 302 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x90, 0x51, 0x4b, 0x02, 0x41,
+	0x14, 0x85, 0x99, 0xc6, 0x5d, 0x72, 0x84, 0x90, 0x21, 0x64, 0x91, 0x20, 0xf1, 0x21, 0x7c, 0x1a,
+	0xa3, 0x7e, 0x41, 0xe6, 0x8b, 0x21, 0x04, 0xa3, 0xbd, 0xf4, 0xb6, 0xeb, 0x5c, 0x57, 0x5b, 0xdd,
+	0xbb, 0xee, 0xdc, 0xf1, 0xa7, 0xf5, 0xdc, 0x4f, 0x8b, 0x66, 0xd5, 0x0a, 0x8c, 0xa2, 0xb7, 0x39,
+	0x77, 0xce, 0x99, 0xf9, 0xce, 0x15, 0x8d, 0x8d, 0x03, 0x07, 0xaa, 0x28, 0x91, 0x50, 0xb6, 0xd2,
+	0x25, 0x2d, 0x5c, 0xa2, 0x66, 0xb8, 0x56, 0xb8, 0x5d, 0xa8, 0x99, 0xb1, 0xca, 0x9a, 0xac, 0x7d,
+	0x99, 0x22, 0xa6, 0x2b, 0xe8, 0x7b, 0x57, 0xe2, 0xe6, 0x7d, 0x5a, 0xae, 0xc1, 0x52, 0xbc, 0x2e,
+	0xaa, 0x60, 0xf7, 0x4a, 0x9c, 0x3d, 0x60, 0x32, 0x40, 0xcc, 0x34, 0x6c, 0x1c, 0x58, 0x92, 0xe7,
+	0x22, 0x78, 0xc1, 0x64, 0x34, 0x8c, 0x58, 0x87, 0xf5, 0xb8, 0xae, 0x44, 0xf7, 0x8d, 0x89, 0xe6,
+	0x84, 0xa0, 0x98, 0x50, 0x4c, 0xce, 0x3e, 0x15, 0x26, 0x26, 0x38, 0x6e, 0x95, 0x17, 0xa2, 0x6e,
+	0x09, 0x8a, 0xc7, 0xd2, 0x40, 0x19, 0x9d, 0xf8, 0x9b, 0xcf, 0x81, 0x6c, 0x89, 0xd0, 0xfa, 0x37,
+	0x22, 0xde, 0x61, 0xbd, 0xba, 0xde, 0x29, 0x79, 0x2d, 0x02, 0x4b, 0x71, 0x49, 0x51, 0xad, 0xc3,
+	0x7a, 0x8d, 0x9b, 0xb6, 0xaa, 0xc8, 0xd5, 0x9e, 0x5c, 0x4d, 0xf7, 0xe4, 0xba, 0x32, 0x4a, 0x25,
+	0x6a, 0x06, 0x73, 0x88, 0x82, 0x5f, 0x03, 0xde, 0xd7, 0x7d, 0x65, 0xe2, 0x74, 0x8c, 0xe9, 0xfd,
+	0xc2, 0xe5, 0xd9, 0xcf, 0xe8, 0x39, 0x1a, 0xd0, 0x2e, 0x1f, 0x0d, 0xf7, 0xe8, 0x87, 0xc1, 0xf7,
+	0x62, 0xfc, 0x48, 0x31, 0x9c, 0xcf, 0x2d, 0x54, 0x0d, 0xb8, 0xde, 0x29, 0xd9, 0x14, 0x7c, 0x1b,
+	0xaf, 0x3c, 0x65, 0x5d, 0x7f, 0x1c, 0x0f, 0xe0, 0xe1, 0x1f, 0xc1, 0xa7, 0x22, 0x1c, 0x63, 0x7a,
+	0x37, 0xcb, 0xfe, 0xbb, 0xf0, 0x1d, 0x17, 0xff, 0xca, 0x35, 0x08, 0x9e, 0xb9, 0x35, 0x59, 0x12,
+	0xfa, 0x6f, 0x6f, 0xdf, 0x07, 0x00, 0x2a, 0x8f, 0xbe, 0x8c, 0x4f, 0x02, 0x00, 0x00,
+}
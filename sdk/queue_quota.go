@@ -0,0 +1,22 @@
+package sdk
+
+// ProjectQueueQuota limits the number of jobs of a project building at the same time.
+type ProjectQueueQuota struct {
+	ProjectID         int64  `json:"project_id" db:"project_id"`
+	ProjectKey        string `json:"project_key" db:"project_key"`
+	MaxConcurrentJobs int    `json:"max_concurrent_jobs" db:"max_concurrent_jobs"`
+}
+
+// GroupQueueWeight is the share of the workers given to the jobs of a group, relatively to the other groups.
+type GroupQueueWeight struct {
+	GroupID   int64  `json:"group_id" db:"group_id"`
+	GroupName string `json:"group_name" db:"group_name"`
+	Weight    int    `json:"weight" db:"weight"`
+}
+
+// QueueQuotas contains the scheduling settings of the job queue.
+type QueueQuotas struct {
+	DefaultProjectQuota int                 `json:"default_project_quota"`
+	Projects            []ProjectQueueQuota `json:"projects"`
+	Groups              []GroupQueueWeight  `json:"groups"`
+}
@@ -59,6 +59,31 @@ type VCSCommit struct {
 	URL       string    `json:"url"`
 }
 
+// Time returns the date of the commit, the repositories managers give it in seconds or in milliseconds
+func (c VCSCommit) Time() time.Time {
+	if c.Timestamp <= 0 {
+		return time.Time{}
+	}
+	if c.Timestamp > 1e12 {
+		return time.Unix(0, c.Timestamp*int64(time.Millisecond))
+	}
+	return time.Unix(c.Timestamp, 0)
+}
+
+//VCSChangedFile represents a file modified between two refs
+type VCSChangedFile struct {
+	Filename string `json:"filename"`
+	Status   string `json:"status"`
+}
+
+// These are the status of a VCSChangedFile
+const (
+	VCSChangedFileAdded    = "added"
+	VCSChangedFileModified = "modified"
+	VCSChangedFileRemoved  = "removed"
+	VCSChangedFileRenamed  = "renamed"
+)
+
 //VCSRemote represents remotes known by the repositories manager
 type VCSRemote struct {
 	Name string `json:"name"`
@@ -134,6 +159,8 @@ type VCSHook struct {
 	Disable     bool     `json:"disable"`
 	InsecureSSL bool     `json:"insecure_ssl"`
 	Workflow    bool     `json:"workflow"`
+	// Secret signs the payloads sent by the repository manager
+	Secret string `json:"secret,omitempty"`
 }
 
 // VCSCommitStatus represents a status on a VCS repository
@@ -1,13 +1,16 @@
 package sdk
 
 import (
+	"crypto/sha1"
 	"encoding/base64"
+	"encoding/hex"
 	"time"
 )
 
 // Operation is the main business object use in repositories service
 type Operation struct {
 	UUID               string                   `json:"uuid"`
+	ProjectKey         string                   `json:"project_key,omitempty"`
 	VCSServer          string                   `json:"vcs_server,omitempty"`
 	RepoFullName       string                   `json:"repo_fullname,omitempty"`
 	URL                string                   `json:"url"`
@@ -25,6 +28,7 @@ type Operation struct {
 type OperationSetup struct {
 	Checkout OperationCheckout `json:"checkout,omitempty"`
 	Push     OperationPush     `json:"push,omitempty"`
+	Mirror   bool              `json:"mirror,omitempty"`
 }
 
 // OperationRepositoryInfo represents global information about the repository
@@ -76,3 +80,10 @@ type OperationRepo struct {
 func (r OperationRepo) ID() string {
 	return base64.StdEncoding.EncodeToString([]byte(r.URL))
 }
+
+// MirrorID returns the identifier of the mirror of a repository url in repositories service. The mirrors are not
+// shared between the projects nor the vcs servers, as they are fetched with the credentials of the project.
+func MirrorID(projectKey, vcsServer, url string) string {
+	sum := sha1.Sum([]byte(projectKey + "/" + vcsServer + "/" + url))
+	return hex.EncodeToString(sum[:])
+}
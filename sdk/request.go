@@ -47,11 +47,16 @@ var (
 	ResponseWorkflowIDHeader = "X-Api-Workflow-Id"
 	// WorkflowAsCodeHeader is used as HTTP header
 	WorkflowAsCodeHeader = "X-Api-Workflow-As-Code"
+	// RequestIDHeader is used as HTTP header to correlate the logs of the services handling a request
+	RequestIDHeader = "X-Request-Id"
 
 	// ResponseTemplateGroupNameHeader is used as HTTP header
 	ResponseTemplateGroupNameHeader = "X-Api-Template-Group-Name"
 	// ResponseTemplateSlugHeader is used as HTTP header
 	ResponseTemplateSlugHeader = "X-Api-Template-Slug"
+
+	// ArchivePassphraseHeader is used as HTTP header to give the passphrase encrypting the secrets of a project archive
+	ArchivePassphraseHeader = "X-Cds-Archive-Passphrase"
 )
 
 // InitEndpoint force sdk package request to given endpoint
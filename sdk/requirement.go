@@ -1,5 +1,7 @@
 package sdk
 
+import "strings"
+
 const (
 	//BinaryRequirement refers to the need to a specific binary on host running the action
 	BinaryRequirement = "binary"
@@ -101,6 +103,33 @@ type Requirement struct {
 	Value string `json:"value" yaml:"value"`
 }
 
+// SplitServiceRequirementValue splits the value of a service requirement: the image followed by
+// the options, separated by spaces. A quoted option can contain spaces, example:
+// postgres:9.5.3 POSTGRES_PASSWORD=pg CDS_SERVICE_READY_CMD="pg_isready -h pg"
+// As strings.Split, it returns at least one element, the image.
+func SplitServiceRequirementValue(value string) []string {
+	var res []string
+	var current strings.Builder
+	var quoted bool
+	for _, c := range value {
+		switch {
+		case c == '"':
+			quoted = !quoted
+		case c == ' ' && !quoted:
+			if current.Len() > 0 {
+				res = append(res, current.String())
+				current.Reset()
+			}
+		default:
+			current.WriteRune(c)
+		}
+	}
+	if current.Len() > 0 || len(res) == 0 {
+		res = append(res, current.String())
+	}
+	return res
+}
+
 // AddRequirement append a requirement in a requirement array
 func AddRequirement(array *RequirementList, id int64, name string, requirementType string, value string) {
 	requirements := append(*array, Requirement{
@@ -0,0 +1,14 @@
+package sdk
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitServiceRequirementValue(t *testing.T) {
+	assert.Equal(t, []string{""}, SplitServiceRequirementValue(""))
+	assert.Equal(t, []string{"redis"}, SplitServiceRequirementValue("redis"))
+	assert.Equal(t, []string{"postgres:9.5.3", "POSTGRES_PASSWORD=pg", "CDS_SERVICE_READY_CMD=pg_isready -h pg"},
+		SplitServiceRequirementValue(`postgres:9.5.3  POSTGRES_PASSWORD=pg CDS_SERVICE_READY_CMD="pg_isready -h pg"`))
+}
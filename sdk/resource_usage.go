@@ -0,0 +1,51 @@
+package sdk
+
+import "time"
+
+// UsageMonthFormat is the layout of the months of the resource usage reports
+const UsageMonthFormat = "2006-01"
+
+// UsageMonth returns the month of the resource usage counted at the given time
+func UsageMonth(t time.Time) string {
+	return t.UTC().Format(UsageMonthFormat)
+}
+
+// ParseUsageMonth checks a month of a resource usage report, the current month is returned for an empty string
+func ParseUsageMonth(s string) (string, error) {
+	if s == "" {
+		return UsageMonth(time.Now()), nil
+	}
+	if _, err := time.Parse(UsageMonthFormat, s); err != nil {
+		return "", NewErrorFrom(ErrWrongRequest, "invalid month %s, expected format is YYYY-MM", s)
+	}
+	return s, nil
+}
+
+// ResourceUsage is the build time and the size of the artifacts uploaded by the jobs of a project during a month
+type ResourceUsage struct {
+	ProjectID    int64  `json:"-" db:"project_id" cli:"-"`
+	ProjectKey   string `json:"project_key" db:"project_key" cli:"project,key"`
+	Month        string `json:"month" db:"month" cli:"month"`
+	BuildSeconds int64  `json:"build_seconds" db:"build_seconds" cli:"-"`
+	BuildMinutes int64  `json:"build_minutes" db:"-" cli:"build_minutes"`
+	ArtifactSize int64  `json:"artifact_size" db:"artifact_size" cli:"artifact_size"`
+}
+
+// ComputeBuildMinutes rounds up the build time of the usage to the minute
+func (r *ResourceUsage) ComputeBuildMinutes() {
+	r.BuildMinutes = (r.BuildSeconds + 59) / 60
+}
+
+// OrganizationUsageReport is the usage of the projects of an organization during a month, with the quotas of the
+// organization. The storage is the size of all the artifacts currently kept, in bytes.
+type OrganizationUsageReport struct {
+	Organization      string          `json:"organization" cli:"organization"`
+	Month             string          `json:"month" cli:"month"`
+	BuildMinutes      int64           `json:"build_minutes" cli:"build_minutes"`
+	MaxBuildMinutes   int             `json:"max_build_minutes" cli:"max_build_minutes"`
+	Storage           int64           `json:"storage" cli:"storage"`
+	MaxStorage        int64           `json:"max_storage" cli:"max_storage"`
+	BuildingJobs      int64           `json:"building_jobs" cli:"building_jobs"`
+	MaxConcurrentJobs int             `json:"max_concurrent_jobs" cli:"max_concurrent_jobs"`
+	Projects          []ResourceUsage `json:"projects" cli:"-"`
+}
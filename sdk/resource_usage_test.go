@@ -0,0 +1,31 @@
+package sdk
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseUsageMonth(t *testing.T) {
+	m, err := ParseUsageMonth("2019-03")
+	assert.NoError(t, err)
+	assert.Equal(t, "2019-03", m)
+
+	m, err = ParseUsageMonth("")
+	assert.NoError(t, err)
+	assert.Equal(t, UsageMonth(time.Now()), m)
+
+	_, err = ParseUsageMonth("03/2019")
+	assert.True(t, ErrorIs(err, ErrWrongRequest))
+}
+
+func TestResourceUsageBuildMinutes(t *testing.T) {
+	r := ResourceUsage{BuildSeconds: 61}
+	r.ComputeBuildMinutes()
+	assert.Equal(t, int64(2), r.BuildMinutes)
+
+	r = ResourceUsage{BuildSeconds: 120}
+	r.ComputeBuildMinutes()
+	assert.Equal(t, int64(2), r.BuildMinutes)
+}
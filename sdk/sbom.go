@@ -0,0 +1,139 @@
+package sdk
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+)
+
+// SBOM formats
+const (
+	SBOMFormatSPDX      = "spdx-json"
+	SBOMFormatCycloneDX = "cyclonedx-json"
+)
+
+// SBOMFormats list the formats of SBOM documents handled by CDS
+var SBOMFormats = []string{SBOMFormatSPDX, SBOMFormatCycloneDX}
+
+// WorkflowRunSBOM is a software bill of materials attached to a workflow run by a job
+type WorkflowRunSBOM struct {
+	ID                int64           `json:"id" db:"id" cli:"id"`
+	ProjectID         int64           `json:"project_id" db:"project_id"`
+	WorkflowID        int64           `json:"workflow_id" db:"workflow_id"`
+	WorkflowRunID     int64           `json:"workflow_run_id" db:"workflow_run_id"`
+	WorkflowNodeRunID int64           `json:"workflow_node_run_id" db:"workflow_node_run_id"`
+	Name              string          `json:"name" db:"name" cli:"name"`
+	Format            string          `json:"format" db:"format" cli:"format"`
+	Created           time.Time       `json:"created" db:"created" cli:"created"`
+	Components        []SBOMComponent `json:"components,omitempty" db:"-"`
+	Document          json.RawMessage `json:"document,omitempty" db:"-"`
+}
+
+// SBOMComponent is a package listed in a SBOM document
+type SBOMComponent struct {
+	Name    string `json:"name" db:"name" cli:"name"`
+	Version string `json:"version" db:"version" cli:"version"`
+	Type    string `json:"type,omitempty" db:"type" cli:"type"`
+	PURL    string `json:"purl,omitempty" db:"purl" cli:"purl"`
+}
+
+// WorkflowRunSBOMComponent is a component found in the SBOM of a workflow run
+type WorkflowRunSBOMComponent struct {
+	WorkflowName      string    `json:"workflow_name" cli:"workflow"`
+	WorkflowRunID     int64     `json:"workflow_run_id"`
+	WorkflowRunNumber int64     `json:"workflow_run_number" cli:"run"`
+	SBOMID            int64     `json:"sbom_id"`
+	SBOMName          string    `json:"sbom_name" cli:"sbom"`
+	Created           time.Time `json:"created" cli:"created"`
+	SBOMComponent
+}
+
+type spdxDocument struct {
+	SPDXVersion string `json:"spdxVersion"`
+	Packages    []struct {
+		Name         string `json:"name"`
+		VersionInfo  string `json:"versionInfo"`
+		ExternalRefs []struct {
+			ReferenceType    string `json:"referenceType"`
+			ReferenceLocator string `json:"referenceLocator"`
+		} `json:"externalRefs"`
+	} `json:"packages"`
+}
+
+type cycloneDXComponent struct {
+	Name       string               `json:"name"`
+	Version    string               `json:"version"`
+	Type       string               `json:"type"`
+	PURL       string               `json:"purl"`
+	Components []cycloneDXComponent `json:"components"`
+}
+
+type cycloneDXDocument struct {
+	BOMFormat  string               `json:"bomFormat"`
+	Components []cycloneDXComponent `json:"components"`
+}
+
+// ParseSBOM detects the format of a SPDX or CycloneDX JSON document and returns its components
+func ParseSBOM(data []byte) (string, []SBOMComponent, error) {
+	var header struct {
+		SPDXVersion string `json:"spdxVersion"`
+		BOMFormat   string `json:"bomFormat"`
+	}
+	if err := json.Unmarshal(data, &header); err != nil {
+		return "", nil, NewErrorFrom(ErrWrongRequest, "invalid SBOM document: %v", err)
+	}
+
+	switch {
+	case header.SPDXVersion != "":
+		var doc spdxDocument
+		if err := json.Unmarshal(data, &doc); err != nil {
+			return "", nil, NewErrorFrom(ErrWrongRequest, "invalid SPDX document: %v", err)
+		}
+		components := make([]SBOMComponent, 0, len(doc.Packages))
+		for _, p := range doc.Packages {
+			c := SBOMComponent{Name: p.Name, Version: p.VersionInfo}
+			for _, ref := range p.ExternalRefs {
+				if ref.ReferenceType == "purl" {
+					c.PURL = ref.ReferenceLocator
+					c.Type = purlType(ref.ReferenceLocator)
+					break
+				}
+			}
+			components = append(components, c)
+		}
+		return SBOMFormatSPDX, components, nil
+	case strings.EqualFold(header.BOMFormat, "CycloneDX"):
+		var doc cycloneDXDocument
+		if err := json.Unmarshal(data, &doc); err != nil {
+			return "", nil, NewErrorFrom(ErrWrongRequest, "invalid CycloneDX document: %v", err)
+		}
+		components := []SBOMComponent{}
+		var walk func(cs []cycloneDXComponent)
+		walk = func(cs []cycloneDXComponent) {
+			for _, c := range cs {
+				t := purlType(c.PURL)
+				if t == "" {
+					t = c.Type
+				}
+				components = append(components, SBOMComponent{Name: c.Name, Version: c.Version, Type: t, PURL: c.PURL})
+				walk(c.Components)
+			}
+		}
+		walk(doc.Components)
+		return SBOMFormatCycloneDX, components, nil
+	}
+
+	return "", nil, NewErrorFrom(ErrWrongRequest, "unsupported SBOM document: only SPDX and CycloneDX JSON documents are supported")
+}
+
+// purlType returns the type of a package url, ie. npm for pkg:npm/lodash@4.17.21
+func purlType(purl string) string {
+	if !strings.HasPrefix(purl, "pkg:") {
+		return ""
+	}
+	t := strings.TrimPrefix(purl, "pkg:")
+	if i := strings.Index(t, "/"); i > 0 {
+		return t[:i]
+	}
+	return ""
+}
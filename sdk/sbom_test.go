@@ -0,0 +1,49 @@
+package sdk
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSBOM(t *testing.T) {
+	spdx := `{
+  "spdxVersion": "SPDX-2.2",
+  "packages": [
+    {"name": "my-app"},
+    {"name": "lodash", "versionInfo": "4.17.21", "externalRefs": [
+      {"referenceCategory": "SECURITY", "referenceType": "cpe23Type", "referenceLocator": "cpe:2.3:a:lodash:lodash:4.17.21:*:*:*:*:*:*:*"},
+      {"referenceCategory": "PACKAGE_MANAGER", "referenceType": "purl", "referenceLocator": "pkg:npm/lodash@4.17.21"}
+    ]}
+  ]
+}`
+	format, components, err := ParseSBOM([]byte(spdx))
+	assert.NoError(t, err)
+	assert.Equal(t, SBOMFormatSPDX, format)
+	assert.Equal(t, []SBOMComponent{
+		{Name: "my-app"},
+		{Name: "lodash", Version: "4.17.21", Type: "npm", PURL: "pkg:npm/lodash@4.17.21"},
+	}, components)
+
+	cyclonedx := `{
+  "bomFormat": "CycloneDX",
+  "specVersion": "1.4",
+  "components": [
+    {"type": "library", "name": "log4j-core", "version": "2.14.1", "purl": "pkg:maven/org.apache.logging.log4j/log4j-core@2.14.1", "components": [
+      {"type": "library", "name": "log4j-api", "version": "2.14.1"}
+    ]}
+  ]
+}`
+	format, components, err = ParseSBOM([]byte(cyclonedx))
+	assert.NoError(t, err)
+	assert.Equal(t, SBOMFormatCycloneDX, format)
+	assert.Equal(t, []SBOMComponent{
+		{Name: "log4j-core", Version: "2.14.1", Type: "maven", PURL: "pkg:maven/org.apache.logging.log4j/log4j-core@2.14.1"},
+		{Name: "log4j-api", Version: "2.14.1", Type: "library"},
+	}, components)
+
+	_, _, err = ParseSBOM([]byte(`{"name": "foo"}`))
+	assert.Error(t, err)
+	_, _, err = ParseSBOM([]byte(`<bom/>`))
+	assert.Error(t, err)
+}
@@ -0,0 +1,109 @@
+package sdk
+
+import (
+	"strings"
+	"time"
+)
+
+// Kinds of the search results
+const (
+	SearchKindProject     = "project"
+	SearchKindWorkflow    = "workflow"
+	SearchKindApplication = "application"
+	SearchKindRun         = "run"
+)
+
+// SearchKinds lists the kinds of the search results, in the order they are returned
+var SearchKinds = []string{SearchKindProject, SearchKindWorkflow, SearchKindApplication, SearchKindRun}
+
+// SearchQuery is a parsed search query, made of free text and of typed filters like "kind:workflow repo:ovh/cds"
+type SearchQuery struct {
+	Text       string   `json:"text,omitempty"`
+	Kinds      []string `json:"kinds,omitempty"`
+	Project    string   `json:"project,omitempty"`
+	Repository string   `json:"repository,omitempty"`
+	Branch     string   `json:"branch,omitempty"`
+	Status     string   `json:"status,omitempty"`
+}
+
+// ParseSearchQuery parses a search query. The filters are kind, project, repo, branch and status,
+// the other words are the free text.
+func ParseSearchQuery(q string) (SearchQuery, error) {
+	var res SearchQuery
+	var text []string
+	for _, w := range strings.Fields(q) {
+		i := strings.Index(w, ":")
+		if i <= 0 {
+			text = append(text, w)
+			continue
+		}
+		key, value := strings.ToLower(w[:i]), w[i+1:]
+		switch key {
+		case "kind":
+			for _, k := range strings.Split(strings.ToLower(value), ",") {
+				if !IsInArray(k, SearchKinds) {
+					return res, NewErrorFrom(ErrWrongRequest, "invalid kind %s", k)
+				}
+				if !IsInArray(k, res.Kinds) {
+					res.Kinds = append(res.Kinds, k)
+				}
+			}
+		case "project":
+			res.Project = value
+		case "repo":
+			res.Repository = value
+		case "branch":
+			res.Branch = value
+		case "status":
+			res.Status = value
+		default:
+			text = append(text, w)
+		}
+	}
+	res.Text = strings.Join(text, " ")
+	return res, nil
+}
+
+// HasKind returns true if the query searches for the given kind of results
+func (q SearchQuery) HasKind(kind string) bool {
+	if len(q.Kinds) > 0 && !IsInArray(kind, q.Kinds) {
+		return false
+	}
+	switch kind {
+	case SearchKindProject:
+		return q.Repository == "" && q.Branch == "" && q.Status == ""
+	case SearchKindWorkflow, SearchKindApplication:
+		return q.Branch == "" && q.Status == ""
+	}
+	return true
+}
+
+// SearchResult is an entity found by a search
+type SearchResult struct {
+	Kind         string    `json:"kind" cli:"kind"`
+	ProjectKey   string    `json:"project_key" cli:"project"`
+	Name         string    `json:"name" cli:"name"`
+	Description  string    `json:"description,omitempty"`
+	WorkflowName string    `json:"workflow_name,omitempty"`
+	RunNumber    int64     `json:"run_number,omitempty" cli:"run"`
+	Status       string    `json:"status,omitempty" cli:"status"`
+	Repository   string    `json:"repository,omitempty" cli:"repository"`
+	Branch       string    `json:"branch,omitempty" cli:"branch"`
+	LastModified time.Time `json:"last_modified,omitempty"`
+}
+
+// SearchResults is a page of search results
+type SearchResults struct {
+	Results []SearchResult `json:"results"`
+	Offset  int            `json:"offset"`
+	Limit   int            `json:"limit"`
+	HasMore bool           `json:"has_more"`
+}
+
+// SearchRunsRequest is sent to the elasticsearch service to search workflow runs.
+// Nil project keys match all the projects.
+type SearchRunsRequest struct {
+	Query       SearchQuery `json:"query"`
+	ProjectKeys []string    `json:"project_keys"`
+	Size        int         `json:"size"`
+}
@@ -0,0 +1,28 @@
+package sdk
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSearchQuery(t *testing.T) {
+	q, err := ParseSearchQuery("kind:workflow,Application repo:ovh/cds  my app http://foo")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{SearchKindWorkflow, SearchKindApplication}, q.Kinds)
+	assert.Equal(t, "ovh/cds", q.Repository)
+	assert.Equal(t, "my app http://foo", q.Text)
+	assert.True(t, q.HasKind(SearchKindWorkflow))
+	assert.False(t, q.HasKind(SearchKindProject))
+	assert.False(t, q.HasKind(SearchKindRun))
+
+	q, err = ParseSearchQuery("branch:master status:Fail")
+	assert.NoError(t, err)
+	assert.Equal(t, "master", q.Branch)
+	assert.Equal(t, "Fail", q.Status)
+	assert.True(t, q.HasKind(SearchKindRun))
+	assert.False(t, q.HasKind(SearchKindWorkflow))
+
+	_, err = ParseSearchQuery("kind:pipeline")
+	assert.Error(t, err)
+}
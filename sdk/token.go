@@ -1,9 +1,14 @@
 package sdk
 
 import (
+	"database/sql/driver"
+	"encoding/json"
+	"net"
+	"strings"
 	"time"
 
 	jwt "github.com/dgrijalva/jwt-go"
+	"github.com/pkg/errors"
 )
 
 const (
@@ -11,12 +16,131 @@ const (
 	AccessTokenStatusDisabled = "disabled"
 )
 
+// Scopes of the personal access tokens
+const (
+	AccessTokenScopeReadProject      = "read:project"
+	AccessTokenScopeRunWorkflow      = "run:workflow"
+	AccessTokenScopeAdminWorkerModel = "admin:worker-model"
+)
+
+// AccessTokenScopesAvailable is the list of all the scopes that can be given to a personal access token
+var AccessTokenScopesAvailable = []string{
+	AccessTokenScopeReadProject,
+	AccessTokenScopeRunWorkflow,
+	AccessTokenScopeAdminWorkerModel,
+}
+
+// AccessTokenScopes is the list of scopes of an access token, a token without scope is not restricted
+type AccessTokenScopes []string
+
+// IsValid returns an error if a scope is unknown.
+func (s AccessTokenScopes) IsValid() error {
+	for _, scope := range s {
+		if !IsInArray(scope, AccessTokenScopesAvailable) {
+			return NewErrorFrom(ErrWrongRequest, "invalid scope %s, available scopes are %s", scope, strings.Join(AccessTokenScopesAvailable, ", "))
+		}
+	}
+	return nil
+}
+
+// Allows returns true if the scopes contain the given scope or if there is no scope.
+func (s AccessTokenScopes) Allows(scope string) bool {
+	if len(s) == 0 {
+		return true
+	}
+	return IsInArray(scope, s)
+}
+
+// Value returns driver.Value from access token scopes.
+func (s AccessTokenScopes) Value() (driver.Value, error) {
+	j, err := json.Marshal(s)
+	return j, WrapError(err, "cannot marshal AccessTokenScopes")
+}
+
+// Scan access token scopes.
+func (s *AccessTokenScopes) Scan(src interface{}) error {
+	if src == nil {
+		return nil
+	}
+	source, ok := src.([]byte)
+	if !ok {
+		return WithStack(errors.New("type assertion .([]byte) failed"))
+	}
+	return WrapError(json.Unmarshal(source, s), "cannot unmarshal AccessTokenScopes")
+}
+
+// AccessTokenDefaultSessionTTL is the inactivity in minutes closing a session when the restrictions of a token
+// limit its number of sessions without giving a session ttl
+const AccessTokenDefaultSessionTTL = 60
+
+// AccessTokenRestrictions restrict the use of an access token. A session is opened the first time the token is
+// used from a client address, and closed after SessionTTL minutes without request from this address.
+type AccessTokenRestrictions struct {
+	AllowedCIDRs []string `json:"allowed_cidrs,omitempty"`
+	SessionTTL   int64    `json:"session_ttl,omitempty"`
+	MaxSessions  int      `json:"max_sessions,omitempty"`
+}
+
+// IsValid returns an error if a CIDR or a limit is invalid.
+func (r AccessTokenRestrictions) IsValid() error {
+	for _, c := range r.AllowedCIDRs {
+		if _, _, err := net.ParseCIDR(c); err != nil {
+			return NewErrorFrom(ErrWrongRequest, "invalid CIDR %s", c)
+		}
+	}
+	if r.SessionTTL < 0 || r.MaxSessions < 0 {
+		return NewErrorFrom(ErrWrongRequest, "session ttl and max sessions cannot be negative")
+	}
+	return nil
+}
+
+// AllowsIP returns true if the ip is in one of the allowed CIDRs or if there is no allowed CIDR.
+func (r AccessTokenRestrictions) AllowsIP(ip net.IP) bool {
+	if len(r.AllowedCIDRs) == 0 {
+		return true
+	}
+	for _, c := range r.AllowedCIDRs {
+		if _, n, err := net.ParseCIDR(c); err == nil && ip != nil && n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// SessionDuration returns the inactivity closing a session.
+func (r AccessTokenRestrictions) SessionDuration() time.Duration {
+	if r.SessionTTL > 0 {
+		return time.Duration(r.SessionTTL) * time.Minute
+	}
+	return AccessTokenDefaultSessionTTL * time.Minute
+}
+
+// Value returns driver.Value from access token restrictions.
+func (r AccessTokenRestrictions) Value() (driver.Value, error) {
+	j, err := json.Marshal(r)
+	return j, WrapError(err, "cannot marshal AccessTokenRestrictions")
+}
+
+// Scan access token restrictions.
+func (r *AccessTokenRestrictions) Scan(src interface{}) error {
+	if src == nil {
+		return nil
+	}
+	source, ok := src.([]byte)
+	if !ok {
+		return WithStack(errors.New("type assertion .([]byte) failed"))
+	}
+	return WrapError(json.Unmarshal(source, r), "cannot unmarshal AccessTokenRestrictions")
+}
+
 // AccessTokenRequest a the type used by clients to ask a new access_token
 type AccessTokenRequest struct {
-	GroupsIDs             []int64 `json:"scope"`
-	Description           string  `json:"description"`
-	Origin                string  `json:"origin"`
-	ExpirationDelaySecond float64 `json:"expiration_delay_second"`
+	GroupsIDs             []int64                 `json:"scope"`
+	Description           string                  `json:"description"`
+	Origin                string                  `json:"origin"`
+	ExpirationDelaySecond float64                 `json:"expiration_delay_second"`
+	Scopes                []string                `json:"scopes,omitempty"`
+	Restrictions          AccessTokenRestrictions `json:"restrictions"`
 }
 
 // GrantedUser is a user granted from a JWT token. It can be a service, a worker, a hatchery or a user
@@ -39,15 +163,18 @@ func (g *GrantedUser) IsRealUser() bool {
 
 // AccessToken is either a Personnal Access Token or a Group Access Token
 type AccessToken struct {
-	ID          string     `json:"id" cli:"-" db:"id"`
-	Description string     `json:"description" cli:"description,key" db:"description"`
-	UserID      int64      `json:"user_id,omitempty" db:"user_id"`
-	User        User       `json:"user" db:"-"`
-	ExpireAt    *time.Time `json:"expired_at,omitempty" cli:"expired_at" db:"expired_at"`
-	Created     time.Time  `json:"created" cli:"created" db:"created"`
-	Status      string     `json:"status" cli:"status" db:"status"`
-	Origin      string     `json:"-" cli:"-" db:"origin"`
-	Groups      []Group    `json:"groups" cli:"-" db:"-"`
+	ID           string                  `json:"id" cli:"-" db:"id"`
+	Description  string                  `json:"description" cli:"description,key" db:"description"`
+	UserID       int64                   `json:"user_id,omitempty" db:"user_id"`
+	User         User                    `json:"user" db:"-"`
+	ExpireAt     *time.Time              `json:"expired_at,omitempty" cli:"expired_at" db:"expired_at"`
+	Created      time.Time               `json:"created" cli:"created" db:"created"`
+	Status       string                  `json:"status" cli:"status" db:"status"`
+	Origin       string                  `json:"-" cli:"-" db:"origin"`
+	Groups       []Group                 `json:"groups" cli:"-" db:"-"`
+	Scopes       AccessTokenScopes       `json:"scopes,omitempty" cli:"scopes" db:"scopes"`
+	LastUsed     *time.Time              `json:"last_used,omitempty" cli:"last_used" db:"last_used"`
+	Restrictions AccessTokenRestrictions `json:"restrictions" cli:"-" db:"restrictions"`
 }
 
 // Token describes tokens used by worker to access the API
@@ -0,0 +1,57 @@
+package sdk
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccessTokenScopes(t *testing.T) {
+	var unrestricted AccessTokenScopes
+	assert.True(t, unrestricted.Allows(AccessTokenScopeAdminWorkerModel))
+	assert.True(t, unrestricted.Allows(""))
+
+	scopes := AccessTokenScopes{AccessTokenScopeReadProject, AccessTokenScopeRunWorkflow}
+	assert.NoError(t, scopes.IsValid())
+	assert.True(t, scopes.Allows(AccessTokenScopeRunWorkflow))
+	assert.False(t, scopes.Allows(AccessTokenScopeAdminWorkerModel))
+	assert.False(t, scopes.Allows(""))
+
+	assert.Error(t, AccessTokenScopes{"write:everything"}.IsValid())
+
+	v, err := scopes.Value()
+	assert.NoError(t, err)
+	var scanned AccessTokenScopes
+	assert.NoError(t, scanned.Scan(v))
+	assert.Equal(t, scopes, scanned)
+
+	var null AccessTokenScopes
+	assert.NoError(t, null.Scan(nil))
+	assert.Nil(t, null)
+}
+
+func TestAccessTokenRestrictions(t *testing.T) {
+	var none AccessTokenRestrictions
+	assert.NoError(t, none.IsValid())
+	assert.True(t, none.AllowsIP(net.ParseIP("203.0.113.10")))
+	assert.Equal(t, AccessTokenDefaultSessionTTL*time.Minute, none.SessionDuration())
+
+	r := AccessTokenRestrictions{AllowedCIDRs: []string{"10.0.0.0/8", "2001:db8::/32"}, SessionTTL: 5, MaxSessions: 1}
+	assert.NoError(t, r.IsValid())
+	assert.True(t, r.AllowsIP(net.ParseIP("10.1.2.3")))
+	assert.True(t, r.AllowsIP(net.ParseIP("2001:db8::1")))
+	assert.False(t, r.AllowsIP(net.ParseIP("203.0.113.10")))
+	assert.False(t, r.AllowsIP(nil))
+	assert.Equal(t, 5*time.Minute, r.SessionDuration())
+
+	assert.Error(t, AccessTokenRestrictions{AllowedCIDRs: []string{"10.0.0.1"}}.IsValid())
+	assert.Error(t, AccessTokenRestrictions{MaxSessions: -1}.IsValid())
+
+	v, err := r.Value()
+	assert.NoError(t, err)
+	var scanned AccessTokenRestrictions
+	assert.NoError(t, scanned.Scan(v))
+	assert.Equal(t, r, scanned)
+}
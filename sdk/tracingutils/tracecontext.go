@@ -0,0 +1,69 @@
+package tracingutils
+
+import (
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"go.opencensus.io/plugin/ochttp/propagation/b3"
+	"go.opencensus.io/trace"
+)
+
+// TraceParentHeader is the W3C Trace Context header, understood by OpenTelemetry
+const TraceParentHeader = "traceparent"
+
+// FormatTraceParent returns the value of the traceparent header for the span context
+func FormatTraceParent(sc trace.SpanContext) string {
+	flags := "00"
+	if sc.IsSampled() {
+		flags = "01"
+	}
+	return fmt.Sprintf("00-%s-%s-%s", hex.EncodeToString(sc.TraceID[:]), hex.EncodeToString(sc.SpanID[:]), flags)
+}
+
+// ParseTraceParent parses the value of the traceparent header: version-traceid-spanid-flags
+func ParseTraceParent(s string) (trace.SpanContext, bool) {
+	parts := strings.Split(strings.TrimSpace(s), "-")
+	if len(parts) < 4 || len(parts[0]) != 2 || parts[0] == "ff" || len(parts[1]) != 32 || len(parts[2]) != 16 || len(parts[3]) != 2 {
+		return trace.SpanContext{}, false
+	}
+	// Version 00 has exactly 4 fields, future versions may add fields
+	if parts[0] == "00" && len(parts) != 4 {
+		return trace.SpanContext{}, false
+	}
+
+	var sc trace.SpanContext
+	if _, err := hex.Decode(sc.TraceID[:], []byte(parts[1])); err != nil || sc.TraceID == (trace.TraceID{}) {
+		return trace.SpanContext{}, false
+	}
+	if _, err := hex.Decode(sc.SpanID[:], []byte(parts[2])); err != nil || sc.SpanID == (trace.SpanID{}) {
+		return trace.SpanContext{}, false
+	}
+	flags, err := hex.DecodeString(parts[3])
+	if err != nil {
+		return trace.SpanContext{}, false
+	}
+	sc.TraceOptions = trace.TraceOptions(flags[0] & 1)
+	return sc, true
+}
+
+// HTTPFormat propagates the span context with both the W3C Trace Context and the B3 headers.
+// The traceparent header takes precedence when reading a request.
+type HTTPFormat struct {
+	b3 b3.HTTPFormat
+}
+
+// SpanContextFromRequest extracts a span context from incoming requests
+func (f *HTTPFormat) SpanContextFromRequest(req *http.Request) (trace.SpanContext, bool) {
+	if sc, ok := ParseTraceParent(req.Header.Get(TraceParentHeader)); ok {
+		return sc, true
+	}
+	return f.b3.SpanContextFromRequest(req)
+}
+
+// SpanContextToRequest modifies the given request to include the traceparent and B3 headers
+func (f *HTTPFormat) SpanContextToRequest(sc trace.SpanContext, req *http.Request) {
+	req.Header.Set(TraceParentHeader, FormatTraceParent(sc))
+	f.b3.SpanContextToRequest(sc, req)
+}
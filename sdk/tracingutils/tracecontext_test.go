@@ -0,0 +1,41 @@
+package tracingutils
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTraceParent(t *testing.T) {
+	sc, ok := ParseTraceParent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	assert.True(t, ok)
+	assert.True(t, sc.IsSampled())
+	assert.Equal(t, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", FormatTraceParent(sc))
+
+	for _, s := range []string{
+		"",
+		"00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01",
+		"ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra",
+		"00-zzf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+	} {
+		_, ok := ParseTraceParent(s)
+		assert.False(t, ok, s)
+	}
+}
+
+func TestHTTPFormat(t *testing.T) {
+	sc, _ := ParseTraceParent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	req, _ := http.NewRequest(http.MethodGet, "http://localhost", nil)
+	DefaultFormat.SpanContextToRequest(sc, req)
+	assert.Equal(t, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", req.Header.Get(TraceParentHeader))
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", req.Header.Get(TraceIDHeader))
+
+	// B3 headers are still understood
+	req.Header.Del(TraceParentHeader)
+	got, ok := DefaultFormat.SpanContextFromRequest(req)
+	assert.True(t, ok)
+	assert.Equal(t, sc, got)
+}
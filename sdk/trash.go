@@ -0,0 +1,21 @@
+package sdk
+
+import "time"
+
+// Types of the items of the trash of a project
+const (
+	TrashItemWorkflow = "workflow"
+	TrashItemPipeline = "pipeline"
+)
+
+// TrashItem is a workflow or a pipeline deleted from a project, it can be restored until it is purged
+type TrashItem struct {
+	ID        int64     `json:"id" db:"id" cli:"-"`
+	ProjectID int64     `json:"-" db:"project_id" cli:"-"`
+	Type      string    `json:"type" db:"type" cli:"type,key"`
+	ItemID    int64     `json:"item_id" db:"item_id" cli:"-"`
+	Name      string    `json:"name" db:"name" cli:"name,key"`
+	DeletedBy string    `json:"deleted_by" db:"deleted_by" cli:"deleted_by"`
+	Deleted   time.Time `json:"deleted" db:"deleted" cli:"deleted"`
+	Purge     time.Time `json:"purge" db:"-" cli:"purge"`
+}
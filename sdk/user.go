@@ -13,6 +13,7 @@ type User struct {
 	Favorites   []Favorite      `json:"favorites" yaml:"favorites"`
 	Permissions UserPermissions `json:"permissions,omitempty" yaml:"-" cli:"-"`
 	GroupAdmin  bool            `json:"-" yaml:"-" cli:"group_admin"`
+	Deactivated bool            `json:"deactivated,omitempty" yaml:"-" cli:"-"`
 }
 
 // Favorite represent the favorites workflow or project of the user
@@ -47,15 +48,17 @@ type UserAPIRequest struct {
 
 // UserLoginRequest login request
 type UserLoginRequest struct {
-	Username string `json:"username"`
-	Password string `json:"password"`
+	Username string           `json:"username"`
+	Password string           `json:"password"`
+	MFA      *MFAVerification `json:"mfa,omitempty"`
 }
 
 // UserAPIResponse  response from rest API
 type UserAPIResponse struct {
-	User     User   `json:"user"`
-	Password string `json:"password,omitempty"`
-	Token    string `json:"token,omitempty"`
+	User                  User   `json:"user"`
+	Password              string `json:"password,omitempty"`
+	Token                 string `json:"token,omitempty"`
+	MFAEnrollmentRequired bool   `json:"mfa_enrollment_required,omitempty"`
 }
 
 // UserEmailPattern  pattern for user email address
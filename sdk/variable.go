@@ -1,6 +1,9 @@
 package sdk
 
-import "time"
+import (
+	"strings"
+	"time"
+)
 
 // Variable represent a variable for a project or pipeline
 type Variable struct {
@@ -26,6 +29,17 @@ const (
 	SecretMinLength = 6
 )
 
+// MaskSecrets replaces the values of the secrets found in a string by the names of the secrets. Values shorter
+// than SecretMinLength are not masked as they would mask too many words.
+func MaskSecrets(value string, secrets []Variable) string {
+	for i := range secrets {
+		if len(secrets[i].Value) >= SecretMinLength {
+			value = strings.Replace(value, secrets[i].Value, "**"+secrets[i].Name+"**", -1)
+		}
+	}
+	return value
+}
+
 // Different type of Variable
 const (
 	SecretVariable     = "password"
@@ -0,0 +1,16 @@
+package sdk
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMaskSecrets(t *testing.T) {
+	secrets := []Variable{
+		{Name: "cds.app.password", Value: "my-password"},
+		{Name: "masked", Value: "short"},
+	}
+	assert.Equal(t, "login with **cds.app.password** and short", MaskSecrets("login with my-password and short", secrets))
+	assert.Equal(t, "nothing to mask", MaskSecrets("nothing to mask", nil))
+}
@@ -41,6 +41,7 @@ type VCSAuthorizedClient interface {
 	Commits(ctx context.Context, repo, branch, since, until string) ([]VCSCommit, error)
 	Commit(ctx context.Context, repo, hash string) (VCSCommit, error)
 	CommitsBetweenRefs(ctx context.Context, repo, base, head string) ([]VCSCommit, error)
+	ChangedFiles(ctx context.Context, repo, base, head string) ([]VCSChangedFile, error)
 
 	// PullRequests
 	PullRequest(context.Context, string, int) (VCSPullRequest, error)
@@ -74,6 +75,9 @@ type VCSAuthorizedClient interface {
 
 	// Permissions
 	GrantReadPermission(ctx context.Context, repo string) error
+
+	// Users
+	CurrentUser(ctx context.Context) (VCSAuthor, error)
 }
 
 // GetDefaultBranch return the default branch
@@ -86,8 +90,11 @@ func GetDefaultBranch(branches []VCSBranch) VCSBranch {
 	return VCSBranch{}
 }
 
-// VCSCommitStatusDescription return a node formated status description
+// VCSCommitStatusDescription return a node formated status description, or the name customized by the workflow
 func VCSCommitStatusDescription(projKey, workflowName string, evt EventRunWorkflowNode) string {
+	if evt.StatusContext != "" {
+		return evt.StatusContext
+	}
 	key := fmt.Sprintf("%s-%s-%s",
 		projKey,
 		workflowName,
@@ -2,6 +2,7 @@ package git
 
 import (
 	"fmt"
+	"net/url"
 	"strings"
 	"time"
 
@@ -19,6 +20,14 @@ type CloneOpts struct {
 	Quiet                   bool
 	CheckoutCommit          string
 	NoStrictHostKeyChecking bool
+	// Submodules updates the submodules recursively after the checkout, with the credentials of the clone
+	Submodules bool
+	// LFS fetches the Git LFS files after the checkout
+	LFS bool
+	// SparseCheckout contains the directories to checkout, the whole repository is checked out if empty
+	SparseCheckout []string
+	// Reference is a local repository whose objects are copied in the clone instead of being fetched
+	Reference string
 }
 
 // Clone make a git clone
@@ -28,7 +37,7 @@ func Clone(repo string, path string, auth *AuthOpts, opts *CloneOpts, output *Ou
 		if opts != nil && opts.CheckoutCommit != "" {
 			defer LogFunc("Checkout commit %s", opts.CheckoutCommit)
 		}
-		defer LogFunc("Git clone %s (%v s)", path, int(time.Since(t1).Seconds()))
+		defer func() { LogFunc("Git clone %s (%v s)", path, int(time.Since(t1).Seconds())) }()
 	}
 
 	var commands []cmd
@@ -73,6 +82,14 @@ func prepareGitCloneCommands(repo string, path string, opts *CloneOpts) (string,
 		if opts.Recursive {
 			gitcmd.args = append(gitcmd.args, "--recursive")
 		}
+
+		if opts.Reference != "" {
+			gitcmd.args = append(gitcmd.args, "--reference-if-able", opts.Reference, "--dissociate")
+		}
+
+		if len(opts.SparseCheckout) > 0 {
+			gitcmd.args = append(gitcmd.args, "--no-checkout")
+		}
 	}
 
 	userLogCommand := "Executing: git " + strings.Join(gitcmd.args, " ") + " ...  "
@@ -84,22 +101,77 @@ func prepareGitCloneCommands(repo string, path string, opts *CloneOpts) (string,
 
 	allCmd = append(allCmd, gitcmd)
 
-	if opts != nil && opts.CheckoutCommit != "" && opts.Tag == "" {
+	if opts == nil {
+		return userLogCommand, cmds(allCmd)
+	}
+
+	//Locate the next commands to the right directory
+	dir := path
+	if dir == "" {
+		t := strings.Split(repo, "/")
+		dir = strings.TrimSuffix(t[len(t)-1], ".git")
+	}
+
+	if len(opts.SparseCheckout) > 0 {
+		sparseCmd := cmd{
+			dir:  dir,
+			cmd:  "git",
+			args: append([]string{"sparse-checkout", "set"}, opts.SparseCheckout...),
+		}
+		userLogCommand += "\n\rExecuting: git " + strings.Join(sparseCmd.args, " ")
+		allCmd = append(allCmd, sparseCmd)
+	}
+
+	// the files of a sparse clone are checked out by the reset
+	var resetTarget string
+	if opts.CheckoutCommit != "" && opts.Tag == "" {
+		resetTarget = opts.CheckoutCommit
+	} else if len(opts.SparseCheckout) > 0 {
+		resetTarget = "HEAD"
+	}
+	if resetTarget != "" {
 		resetCmd := cmd{
+			dir:  dir,
 			cmd:  "git",
-			args: []string{"reset", "--hard", opts.CheckoutCommit},
+			args: []string{"reset", "--hard", resetTarget},
 		}
 		userLogCommand += "\n\rExecuting: git " + strings.Join(resetCmd.args, " ")
-		//Locate the git reset cmd to the right directory
-		if path == "" {
-			t := strings.Split(repo, "/")
-			resetCmd.dir = strings.TrimSuffix(t[len(t)-1], ".git")
-		} else {
-			resetCmd.dir = path
+		allCmd = append(allCmd, resetCmd)
+	}
+
+	if opts.Submodules {
+		submoduleCmd := cmd{
+			dir:  dir,
+			cmd:  "git",
+			args: []string{"submodule", "update", "--init", "--recursive"},
 		}
+		userLogCommand += "\n\rExecuting: git " + strings.Join(submoduleCmd.args, " ")
+		// the submodules hosted on the same server are fetched with the credentials of the clone
+		submoduleCmd.args = append(submoduleCredentials(repo), submoduleCmd.args...)
+		allCmd = append(allCmd, submoduleCmd)
+	}
 
-		allCmd = append(allCmd, resetCmd)
+	if opts.LFS {
+		lfsCmds := []cmd{
+			{dir: dir, cmd: "git", args: []string{"lfs", "install", "--local"}},
+			{dir: dir, cmd: "git", args: []string{"lfs", "pull"}},
+		}
+		for _, c := range lfsCmds {
+			userLogCommand += "\n\rExecuting: git " + strings.Join(c.args, " ")
+		}
+		allCmd = append(allCmd, lfsCmds...)
 	}
 
 	return userLogCommand, cmds(allCmd)
 }
+
+// submoduleCredentials returns the git options which add the credentials of an https repository url
+// to the urls of the same server
+func submoduleCredentials(repo string) []string {
+	u, err := url.Parse(repo)
+	if err != nil || u.User == nil || u.Scheme != "https" {
+		return nil
+	}
+	withCredentials := u.Scheme + "://" + u.User.String() + "@" + u.Host + "/"
+	return []string{"-c", "url." + withCredentials + ".insteadOf=" + u.Scheme + "://" + u.Host + "/"}
+}
@@ -0,0 +1,60 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// UpdateReference creates or updates a bare repository with the branches and the tags of a repository,
+// it is used as a reference by the clones of the repository. The url of the repository is not stored
+// in the reference, so that its credentials are not kept.
+func UpdateReference(repo string, path string, auth *AuthOpts, output *OutputOpts) error {
+	if verbose {
+		t1 := time.Now()
+		defer func() { LogFunc("Git update reference %s (%v s)", path, int(time.Since(t1).Seconds())) }()
+	}
+
+	repoURL, err := getRepoURL(repo, auth)
+	if err != nil {
+		return err
+	}
+
+	_, errStat := os.Stat(filepath.Join(path, "HEAD"))
+	return runGitCommands(repo, prepareGitReferenceCommands(repoURL, path, errStat == nil), auth, output)
+}
+
+// CreateBundle writes in a file a bundle of all the references of a repository
+func CreateBundle(path string, bundle string, output *OutputOpts) error {
+	return runGitCommandRaw(prepareGitBundleCommands(path, bundle), output)
+}
+
+// FetchBundle creates or updates a reference repository from a bundle created by CreateBundle
+func FetchBundle(bundle string, path string, output *OutputOpts) error {
+	_, errStat := os.Stat(filepath.Join(path, "HEAD"))
+	return runGitCommandRaw(prepareGitReferenceCommands(bundle, path, errStat == nil), output)
+}
+
+func prepareGitBundleCommands(path string, bundle string) cmds {
+	return cmds{{
+		dir:  path,
+		cmd:  "git",
+		args: []string{"bundle", "create", bundle, "--all"},
+	}}
+}
+
+func prepareGitReferenceCommands(repo string, path string, exists bool) cmds {
+	allCmd := []cmd{}
+	if !exists {
+		allCmd = append(allCmd, cmd{
+			cmd:  "git",
+			args: []string{"init", "--quiet", "--bare", path},
+		})
+	}
+	allCmd = append(allCmd, cmd{
+		dir:  path,
+		cmd:  "git",
+		args: []string{"fetch", "--quiet", "--prune", repo, "+refs/heads/*:refs/heads/*", "+refs/tags/*:refs/tags/*"},
+	})
+	return cmds(allCmd)
+}
@@ -32,14 +32,15 @@ func CleanSSHKeys(path string, key *sdk.Variable) error {
 		if errO != nil {
 			return sdk.WrapError(errO, "CleanSSHKeys> Cannot open path %s", path)
 		}
+		defer dirRead.Close() // nolint
 		dirFiles, errR := dirRead.Readdir(0)
 		if errR != nil {
 			return sdk.WrapError(errR, "CleanSSHKeys> Cannot read path %s", path)
 		}
 
 		for i := range dirFiles {
-			if err := os.RemoveAll(path + dirFiles[i].Name()); err != nil {
-				return sdk.WrapError(err, "Cannot remote file %s", path+dirFiles[i].Name())
+			if err := os.RemoveAll(filepath.Join(path, dirFiles[i].Name())); err != nil {
+				return sdk.WrapError(err, "Cannot remove file %s", filepath.Join(path, dirFiles[i].Name()))
 			}
 		}
 		return nil
@@ -19,6 +19,9 @@ type Worker struct {
 	JobType       string    `json:"job_type" cli:"-"`    // sdk.JobType...
 	Status        Status    `json:"status" cli:"status"` // Waiting, Building, Disabled, Unknown
 	Uptodate      bool      `json:"up_to_date" cli:"-"`
+	Pool          string    `json:"pool,omitempty" cli:"pool"`
+	Capabilities  []string  `json:"capabilities,omitempty" cli:"-"`
+	Draining      bool      `json:"draining" cli:"draining"`
 }
 
 // WorkerRegistrationForm represents the arguments needed to register a worker
@@ -31,6 +34,7 @@ type WorkerRegistrationForm struct {
 	Version            string
 	OS                 string
 	Arch               string
+	Pool               string
 }
 
 // WorkerTakeForm contains booked JobID if exists
@@ -48,6 +52,7 @@ const (
 	HostProcess = "host"
 	Openstack   = "openstack"
 	VSphere     = "vsphere"
+	AWS         = "aws"
 )
 
 // WorkerModelValidate returns if given strings are valid worker model type.
@@ -67,6 +72,7 @@ var (
 		string(HostProcess),
 		string(Openstack),
 		string(VSphere),
+		string(AWS),
 	}
 )
 
@@ -90,6 +96,12 @@ type SpawnErrorForm struct {
 	Logs  []byte
 }
 
+// WorkerLostForm is sent by a hatchery when the instance of a worker was preempted
+type WorkerLostForm struct {
+	WorkerName string `json:"worker_name"`
+	Reason     string `json:"reason"`
+}
+
 // Model represents a worker model (ex: Go 1.5.1 Docker Images)
 // with specified capabilities (ex: go, golint and go2xunit binaries)
 //easyjson:json
@@ -115,6 +127,7 @@ type Model struct {
 	Provision              int64               `json:"provision" db:"provision" cli:"provision"`
 	GroupID                int64               `json:"group_id" db:"group_id" cli:"-"`
 	Group                  Group               `json:"group" db:"-" cli:"-"`
+	OrganizationID         int64               `json:"organization_id" db:"organization_id" cli:"-"`
 	NbSpawnErr             int64               `json:"nb_spawn_err" db:"nb_spawn_err" cli:"nb_spawn_err"`
 	LastSpawnErr           string              `json:"last_spawn_err" db:"-" cli:"-"`
 	LastSpawnErrLogs       *string             `json:"last_spawn_err_log" db:"-" cli:"-"`
@@ -122,6 +135,67 @@ type Model struct {
 	IsDeprecated           bool                `json:"is_deprecated" db:"is_deprecated" cli:"deprecated"`
 	IsOfficial             bool                `json:"is_official" db:"-" cli:"official"`
 	PatternName            string              `json:"pattern_name,omitempty" db:"-" cli:"-"`
+	ParentID               int64               `json:"parent_id,omitempty" db:"parent_id" cli:"-"`
+	Parent                 string              `json:"parent,omitempty" db:"-" cli:"parent"`
+	NbJobs                 int64               `json:"nb_jobs" db:"-" cli:"nb_jobs"`
+	LastUsed               *time.Time          `json:"last_used,omitempty" db:"-" cli:"last_used"`
+}
+
+// Inherit returns the worker model with the fields it does not override taken from the base model it extends. The
+// pre-command of a virtual machine model runs after the one of its base, and the environment variables of a docker
+// model are added to the ones of its base.
+func (m Model) Inherit(base *Model) Model {
+	if base == nil {
+		return m
+	}
+	switch m.Type {
+	case Docker:
+		d, b := &m.ModelDocker, base.ModelDocker
+		if d.Image == "" {
+			d.Image = b.Image
+		}
+		if d.Memory == 0 {
+			d.Memory = b.Memory
+		}
+		if d.Shell == "" {
+			d.Shell = b.Shell
+		}
+		if d.Cmd == "" {
+			d.Cmd = b.Cmd
+		}
+		if d.Registry == "" {
+			d.Registry = b.Registry
+		}
+		envs := make(map[string]string, len(b.Envs)+len(d.Envs))
+		for k, v := range b.Envs {
+			envs[k] = v
+		}
+		for k, v := range d.Envs {
+			envs[k] = v
+		}
+		d.Envs = envs
+	default:
+		vm, b := &m.ModelVirtualMachine, base.ModelVirtualMachine
+		if vm.Image == "" {
+			vm.Image = b.Image
+		}
+		if vm.Flavor == "" {
+			vm.Flavor = b.Flavor
+		}
+		switch {
+		case vm.PreCmd == "":
+			vm.PreCmd = b.PreCmd
+		case b.PreCmd != "":
+			vm.PreCmd = b.PreCmd + "\n" + vm.PreCmd
+		}
+		if vm.Cmd == "" {
+			vm.Cmd = b.Cmd
+		}
+		if vm.PostCmd == "" {
+			vm.PostCmd = b.PostCmd
+		}
+	}
+	return m
 }
 
 // ModelVirtualMachine for openstack or vsphere
@@ -131,6 +205,8 @@ type ModelVirtualMachine struct {
 	PreCmd  string `json:"pre_cmd,omitempty"`
 	Cmd     string `json:"cmd,omitempty"`
 	PostCmd string `json:"post_cmd,omitempty"`
+	// Preemptible allows the hatchery to spawn the workers on spot/preemptible instances
+	Preemptible bool `json:"preemptible,omitempty"`
 }
 
 // ModelDocker for swarm, marathon and kubernetes
@@ -140,6 +216,17 @@ type ModelDocker struct {
 	Envs   map[string]string `json:"envs,omitempty"`
 	Shell  string            `json:"shell,omitempty"`
 	Cmd    string            `json:"cmd,omitempty"`
+	// Registry is the name of a public configuration of the Docker Registry integration, used to pull the image
+	Registry string `json:"registry,omitempty"`
+	// RegistryAuth is resolved by the API from the registry and only given to the hatcheries
+	RegistryAuth *ModelDockerRegistryAuth `json:"registry_auth,omitempty"`
+}
+
+// ModelDockerRegistryAuth contains the credentials used by a hatchery to pull the image of a worker model
+type ModelDockerRegistryAuth struct {
+	URL      string `json:"url"`
+	Username string `json:"username"`
+	Password string `json:"password"`
 }
 
 // ModelPattern represent patterns for users and admin when creating a worker model
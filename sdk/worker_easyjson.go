@@ -104,6 +104,8 @@ func easyjson82a45abeDecodeGithubComOvhCdsSdk(in *jlexer.Lexer, out *Model) {
 			out.GroupID = int64(in.Int64())
 		case "group":
 			easyjson82a45abeDecodeGithubComOvhCdsSdk4(in, &out.Group)
+		case "organization_id":
+			out.OrganizationID = int64(in.Int64())
 		case "nb_spawn_err":
 			out.NbSpawnErr = int64(in.Int64())
 		case "last_spawn_err":
@@ -136,6 +138,24 @@ func easyjson82a45abeDecodeGithubComOvhCdsSdk(in *jlexer.Lexer, out *Model) {
 			out.IsOfficial = bool(in.Bool())
 		case "pattern_name":
 			out.PatternName = string(in.String())
+		case "parent_id":
+			out.ParentID = int64(in.Int64())
+		case "parent":
+			out.Parent = string(in.String())
+		case "nb_jobs":
+			out.NbJobs = int64(in.Int64())
+		case "last_used":
+			if in.IsNull() {
+				in.Skip()
+				out.LastUsed = nil
+			} else {
+				if out.LastUsed == nil {
+					out.LastUsed = new(time.Time)
+				}
+				if data := in.Raw(); in.Ok() {
+					in.AddError((*out.LastUsed).UnmarshalJSON(data))
+				}
+			}
 		default:
 			in.SkipRecursive()
 		}
@@ -371,6 +391,16 @@ func easyjson82a45abeEncodeGithubComOvhCdsSdk(out *jwriter.Writer, in Model) {
 		}
 		easyjson82a45abeEncodeGithubComOvhCdsSdk4(out, in.Group)
 	}
+	{
+		const prefix string = ",\"organization_id\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Int64(int64(in.OrganizationID))
+	}
 	{
 		const prefix string = ",\"nb_spawn_err\":"
 		if first {
@@ -449,6 +479,46 @@ func easyjson82a45abeEncodeGithubComOvhCdsSdk(out *jwriter.Writer, in Model) {
 		}
 		out.String(string(in.PatternName))
 	}
+	if in.ParentID != 0 {
+		const prefix string = ",\"parent_id\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Int64(int64(in.ParentID))
+	}
+	if in.Parent != "" {
+		const prefix string = ",\"parent\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Parent))
+	}
+	{
+		const prefix string = ",\"nb_jobs\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Int64(int64(in.NbJobs))
+	}
+	if in.LastUsed != nil {
+		const prefix string = ",\"last_used\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Raw((*in.LastUsed).MarshalJSON())
+	}
 	out.RawByte('}')
 }
 
@@ -1168,6 +1238,18 @@ func easyjson82a45abeDecodeGithubComOvhCdsSdk2(in *jlexer.Lexer, out *ModelDocke
 			out.Shell = string(in.String())
 		case "cmd":
 			out.Cmd = string(in.String())
+		case "registry":
+			out.Registry = string(in.String())
+		case "registry_auth":
+			if in.IsNull() {
+				in.Skip()
+				out.RegistryAuth = nil
+			} else {
+				if out.RegistryAuth == nil {
+					out.RegistryAuth = new(ModelDockerRegistryAuth)
+				}
+				easyjson82a45abeDecodeGithubComOvhCdsSdk7(in, &*out.RegistryAuth)
+			}
 		default:
 			in.SkipRecursive()
 		}
@@ -1246,6 +1328,97 @@ func easyjson82a45abeEncodeGithubComOvhCdsSdk2(out *jwriter.Writer, in ModelDock
 		}
 		out.String(string(in.Cmd))
 	}
+	if in.Registry != "" {
+		const prefix string = ",\"registry\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Registry))
+	}
+	if in.RegistryAuth != nil {
+		const prefix string = ",\"registry_auth\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		easyjson82a45abeEncodeGithubComOvhCdsSdk7(out, *in.RegistryAuth)
+	}
+	out.RawByte('}')
+}
+func easyjson82a45abeDecodeGithubComOvhCdsSdk7(in *jlexer.Lexer, out *ModelDockerRegistryAuth) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeString()
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "url":
+			out.URL = string(in.String())
+		case "username":
+			out.Username = string(in.String())
+		case "password":
+			out.Password = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson82a45abeEncodeGithubComOvhCdsSdk7(out *jwriter.Writer, in ModelDockerRegistryAuth) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"url\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.URL))
+	}
+	{
+		const prefix string = ",\"username\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Username))
+	}
+	{
+		const prefix string = ",\"password\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Password))
+	}
 	out.RawByte('}')
 }
 func easyjson82a45abeDecodeGithubComOvhCdsSdk1(in *jlexer.Lexer, out *ModelVirtualMachine) {
@@ -1277,6 +1450,8 @@ func easyjson82a45abeDecodeGithubComOvhCdsSdk1(in *jlexer.Lexer, out *ModelVirtu
 			out.Cmd = string(in.String())
 		case "post_cmd":
 			out.PostCmd = string(in.String())
+		case "preemptible":
+			out.Preemptible = bool(in.Bool())
 		default:
 			in.SkipRecursive()
 		}
@@ -1341,5 +1516,15 @@ func easyjson82a45abeEncodeGithubComOvhCdsSdk1(out *jwriter.Writer, in ModelVirt
 		}
 		out.String(string(in.PostCmd))
 	}
+	if in.Preemptible {
+		const prefix string = ",\"preemptible\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Bool(bool(in.Preemptible))
+	}
 	out.RawByte('}')
 }
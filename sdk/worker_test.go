@@ -0,0 +1,50 @@
+package sdk
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestModelInherit(t *testing.T) {
+	base := Model{
+		Type: Docker,
+		ModelDocker: ModelDocker{
+			Image:  "golden/base:1",
+			Memory: 2048,
+			Shell:  "sh -c",
+			Cmd:    "worker",
+			Envs:   map[string]string{"CDS_TTL": "{{.TTL}}", "HTTP_PROXY": "proxy:3128"},
+		},
+	}
+	m := Model{
+		Type:        Docker,
+		ModelDocker: ModelDocker{Image: "golden/go:1.12", Envs: map[string]string{"HTTP_PROXY": "", "GOPATH": "/go"}},
+	}
+	resolved := m.Inherit(&base)
+	assert.Equal(t, ModelDocker{
+		Image:  "golden/go:1.12",
+		Memory: 2048,
+		Shell:  "sh -c",
+		Cmd:    "worker",
+		Envs:   map[string]string{"CDS_TTL": "{{.TTL}}", "HTTP_PROXY": "", "GOPATH": "/go"},
+	}, resolved.ModelDocker)
+	assert.Len(t, base.ModelDocker.Envs, 2, "the envs of the base must not be modified")
+	assert.Equal(t, m, m.Inherit(nil))
+
+	base = Model{
+		Type:                Openstack,
+		ModelVirtualMachine: ModelVirtualMachine{Image: "Debian 9", Flavor: "b2-7", PreCmd: "apt-get update", Cmd: "./worker"},
+	}
+	m = Model{
+		Type:                Openstack,
+		ModelVirtualMachine: ModelVirtualMachine{PreCmd: "apt-get install -y golang", PostCmd: "shutdown -h now"},
+	}
+	assert.Equal(t, ModelVirtualMachine{
+		Image:   "Debian 9",
+		Flavor:  "b2-7",
+		PreCmd:  "apt-get update\napt-get install -y golang",
+		Cmd:     "./worker",
+		PostCmd: "shutdown -h now",
+	}, m.Inherit(&base).ModelVirtualMachine)
+}
@@ -37,6 +37,9 @@ type Workflow struct {
 	Usage                   *Usage                       `json:"usage,omitempty" db:"-" cli:"-"`
 	HistoryLength           int64                        `json:"history_length" db:"history_length" cli:"-"`
 	PurgeTags               []string                     `json:"purge_tags,omitempty" db:"-" cli:"-"`
+	ForkPolicy              WorkflowForkPolicy           `json:"fork_policy" db:"-" cli:"-"`
+	AutoCancel              bool                         `json:"auto_cancel,omitempty" db:"-" cli:"-"`
+	CommitStatus            WorkflowCommitStatus         `json:"commit_status" db:"-" cli:"-"`
 	Notifications           []WorkflowNotification       `json:"notifications,omitempty" db:"-" cli:"-"`
 	FromRepository          string                       `json:"from_repository,omitempty" db:"from_repository" cli:"from"`
 	DerivedFromWorkflowID   int64                        `json:"derived_from_workflow_id,omitempty" db:"derived_from_workflow_id" cli:"-"`
@@ -1483,6 +1486,8 @@ type WorkflowNodeOutgoingHookTrigger struct {
 type WorkflowNodeConditions struct {
 	PlainConditions []WorkflowNodeCondition `json:"plain,omitempty" yaml:"check,omitempty"`
 	LuaScript       string                  `json:"lua_script,omitempty" yaml:"script,omitempty"`
+	// Priority is the priority of the run when the node is triggered, from low (-1) to high (1)
+	Priority *int `json:"priority,omitempty" yaml:"priority,omitempty"`
 }
 
 //WorkflowNodeCondition represents a condition to trigger ot not a pipeline in a workflow. Operator can be =, !=, regex
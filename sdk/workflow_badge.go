@@ -0,0 +1,21 @@
+package sdk
+
+import "time"
+
+// Types of the badges of a workflow
+const (
+	BadgeTypeStatus   = "status"
+	BadgeTypeCoverage = "coverage"
+	BadgeTypeVersion  = "version"
+)
+
+// BadgeTypes lists the types of the badges of a workflow
+var BadgeTypes = []string{BadgeTypeStatus, BadgeTypeCoverage, BadgeTypeVersion}
+
+// WorkflowBadge enables the public badges of a workflow. When the token is set, it is required to get the badges.
+type WorkflowBadge struct {
+	WorkflowID int64             `json:"workflow_id" db:"workflow_id"`
+	Token      string            `json:"token,omitempty" db:"token"`
+	Created    time.Time         `json:"created" db:"created"`
+	URLs       map[string]string `json:"urls,omitempty" db:"-"`
+}
@@ -0,0 +1,93 @@
+package sdk
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// CommentCommandPrefix starts the commands written in the comments of the pull requests
+const CommentCommandPrefix = "/cds"
+
+// The commands allowed in the comments of the pull requests
+const (
+	CommentCommandRun   = "run"
+	CommentCommandRetry = "retry"
+)
+
+// Payload values set by the hooks on the comment events
+const (
+	PayloadCommentCommand = "cds.comment.command"
+	PayloadCommentAuthor  = "cds.comment.author"
+	PayloadCommentNode    = "cds.comment.node"
+	PayloadPullRequestID  = "git.pr.id"
+)
+
+// CommentCommand is a command read in a comment of a pull request.
+// "/cds run key=value..." starts a run of the workflow with the values added to the payload,
+// "/cds retry [node]" restarts the failed node, or the given node, of the last run of the branch.
+type CommentCommand struct {
+	Name    string
+	Node    string
+	Payload map[string]string
+}
+
+// ParseCommentCommand reads the first line of a comment starting with /cds.
+// It returns nil if the comment does not contain any command.
+func ParseCommentCommand(comment string) (*CommentCommand, error) {
+	for _, line := range strings.Split(comment, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 || fields[0] != CommentCommandPrefix {
+			continue
+		}
+		if len(fields) == 1 {
+			return nil, fmt.Errorf("missing command after %s", CommentCommandPrefix)
+		}
+
+		c := CommentCommand{Name: fields[1], Payload: map[string]string{}}
+		args := fields[2:]
+		switch c.Name {
+		case CommentCommandRun:
+			for _, a := range args {
+				kv := strings.SplitN(a, "=", 2)
+				if len(kv) != 2 || kv[0] == "" {
+					return nil, fmt.Errorf("invalid argument %s, expected key=value", a)
+				}
+				// the git and cds values are computed by CDS, they can not be overridden by a comment
+				if strings.HasPrefix(kv[0], "git.") || strings.HasPrefix(kv[0], "cds.") {
+					return nil, fmt.Errorf("the value %s can not be set by a comment", kv[0])
+				}
+				c.Payload[kv[0]] = kv[1]
+			}
+		case CommentCommandRetry:
+			if len(args) > 1 {
+				return nil, fmt.Errorf("retry expects at most one node name")
+			}
+			if len(args) == 1 {
+				c.Node = args[0]
+			}
+		default:
+			return nil, fmt.Errorf("unknown command %s", c.Name)
+		}
+		return &c, nil
+	}
+	return nil, nil
+}
+
+// UserVCSAccount links the account of a user on a repositories manager to its CDS user.
+// The commands written in the comments of the pull requests are run with the permissions of the linked user.
+type UserVCSAccount struct {
+	ID        int64     `json:"id" db:"id" cli:"id,key"`
+	UserID    int64     `json:"user_id" db:"user_id" cli:"-"`
+	VCSServer string    `json:"vcs_server" db:"vcs_server" cli:"vcs_server"`
+	Username  string    `json:"username" db:"username" cli:"username"`
+	Created   time.Time `json:"created" db:"created" cli:"created"`
+}
+
+// IsValid checks the fields of the account
+func (a UserVCSAccount) IsValid() error {
+	if a.VCSServer == "" || a.Username == "" {
+		return NewErrorFrom(ErrWrongRequest, "the repositories manager and the username are required")
+	}
+	return nil
+}
@@ -0,0 +1,37 @@
+package sdk
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCommentCommand(t *testing.T) {
+	c, err := ParseCommentCommand("Looks good to me")
+	assert.NoError(t, err)
+	assert.Nil(t, c)
+
+	c, err = ParseCommentCommand("Fixed the tests\n/cds run env=staging debug=true\nthanks")
+	assert.NoError(t, err)
+	assert.Equal(t, &CommentCommand{Name: CommentCommandRun, Payload: map[string]string{"env": "staging", "debug": "true"}}, c)
+
+	c, err = ParseCommentCommand("/cds retry")
+	assert.NoError(t, err)
+	assert.Equal(t, &CommentCommand{Name: CommentCommandRetry, Payload: map[string]string{}}, c)
+
+	c, err = ParseCommentCommand("/cds retry deploy")
+	assert.NoError(t, err)
+	assert.Equal(t, "deploy", c.Node)
+
+	_, err = ParseCommentCommand("/cds delete")
+	assert.Error(t, err)
+
+	_, err = ParseCommentCommand("/cds run git.branch=master")
+	assert.Error(t, err, "git values can not be overridden")
+
+	_, err = ParseCommentCommand("/cds run env")
+	assert.Error(t, err)
+
+	_, err = ParseCommentCommand("/cds")
+	assert.Error(t, err)
+}
@@ -0,0 +1,62 @@
+package sdk
+
+import (
+	"fmt"
+	"strings"
+)
+
+// WorkflowCommitStatus customizes the statuses sent on the commits by the runs of a workflow.
+// By default each node sends a status named CDS/<project>-<workflow>-<node>.
+type WorkflowCommitStatus struct {
+	// Context is the name of the statuses, it can use {{.cds.project}}, {{.cds.workflow}} and {{.cds.node}}
+	Context string `json:"context,omitempty" yaml:"context,omitempty"`
+	// Nodes overrides the name of the statuses of some nodes
+	Nodes map[string]string `json:"nodes,omitempty" yaml:"nodes,omitempty"`
+	// Aggregate sends a single status for the whole run, with the status of the run
+	Aggregate bool `json:"aggregate,omitempty" yaml:"aggregate,omitempty"`
+}
+
+// IsValid checks the names of the statuses
+func (s WorkflowCommitStatus) IsValid() error {
+	if s.Aggregate && len(s.Nodes) > 0 {
+		return NewErrorFrom(ErrWrongRequest, "the names of the nodes statuses can not be set with an aggregated commit status")
+	}
+	if s.Aggregate && strings.Contains(s.Context, "{{.cds.node}}") {
+		return NewErrorFrom(ErrWrongRequest, "the name of an aggregated commit status can not contain the node name")
+	}
+	for node, context := range s.Nodes {
+		if context == "" {
+			return NewErrorFrom(ErrWrongRequest, "invalid commit status name for node %s", node)
+		}
+	}
+	return nil
+}
+
+// IsDefault returns true if the statuses are not customized
+func (s WorkflowCommitStatus) IsDefault() bool {
+	return s.Context == "" && len(s.Nodes) == 0 && !s.Aggregate
+}
+
+// ContextName returns the name of the status sent by a node, or by the run if the status is aggregated
+func (s WorkflowCommitStatus) ContextName(projKey, workflowName, nodeName string) string {
+	if name, ok := s.Nodes[nodeName]; ok && !s.Aggregate {
+		return name
+	}
+	if s.Context == "" {
+		if s.Aggregate {
+			return fmt.Sprintf("CDS/%s-%s", projKey, workflowName)
+		}
+		return ""
+	}
+	return strings.NewReplacer(
+		"{{.cds.project}}", projKey,
+		"{{.cds.workflow}}", workflowName,
+		"{{.cds.node}}", nodeName,
+	).Replace(s.Context)
+}
+
+// Apply sets the name of the commit status on the event of a node run
+func (s WorkflowCommitStatus) Apply(projKey, workflowName string, e *EventRunWorkflowNode) {
+	e.StatusContext = s.ContextName(projKey, workflowName, e.NodeName)
+	e.StatusAggregate = s.Aggregate
+}
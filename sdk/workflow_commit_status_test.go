@@ -0,0 +1,38 @@
+package sdk
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorkflowCommitStatus(t *testing.T) {
+	var s WorkflowCommitStatus
+	assert.NoError(t, s.IsValid())
+	assert.True(t, s.IsDefault())
+
+	e := EventRunWorkflowNode{NodeName: "build"}
+	s.Apply("KEY", "wf", &e)
+	assert.Equal(t, "CDS/KEY-wf-build", VCSCommitStatusDescription("KEY", "wf", e))
+
+	s = WorkflowCommitStatus{Context: "ci/{{.cds.workflow}}/{{.cds.node}}", Nodes: map[string]string{"deploy": "deployment"}}
+	assert.NoError(t, s.IsValid())
+	assert.Equal(t, "ci/wf/build", s.ContextName("KEY", "wf", "build"))
+	assert.Equal(t, "deployment", s.ContextName("KEY", "wf", "deploy"))
+
+	s = WorkflowCommitStatus{Aggregate: true}
+	assert.NoError(t, s.IsValid())
+	assert.Equal(t, "CDS/KEY-wf", s.ContextName("KEY", "wf", "build"))
+	s.Apply("KEY", "wf", &e)
+	assert.True(t, e.StatusAggregate)
+	assert.Equal(t, "CDS/KEY-wf", VCSCommitStatusDescription("KEY", "wf", e))
+
+	s = WorkflowCommitStatus{Aggregate: true, Context: "ci/{{.cds.node}}"}
+	assert.Error(t, s.IsValid(), "an aggregated status is not sent by a node")
+
+	s = WorkflowCommitStatus{Aggregate: true, Nodes: map[string]string{"deploy": "deployment"}}
+	assert.Error(t, s.IsValid())
+
+	s = WorkflowCommitStatus{Nodes: map[string]string{"deploy": ""}}
+	assert.Error(t, s.IsValid())
+}
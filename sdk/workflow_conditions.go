@@ -5,6 +5,8 @@ import (
 	"regexp"
 	"strings"
 
+	"github.com/blang/semver"
+
 	"github.com/ovh/cds/sdk/interpolate"
 )
 
@@ -17,6 +19,8 @@ const (
 	WorkflowConditionsOperatorGreaterThan        = "gt"
 	WorkflowConditionsOperatorGreaterOrEqualThan = "ge"
 	WorkflowConditionsOperatorRegex              = "regex"
+	WorkflowConditionsOperatorSemver             = "semver"
+	WorkflowConditionsOperatorGlob               = "glob"
 )
 
 // WorkflowData conditions operator
@@ -29,6 +33,8 @@ var (
 		WorkflowConditionsOperatorGreaterThan:        ">",
 		WorkflowConditionsOperatorGreaterOrEqualThan: ">=",
 		WorkflowConditionsOperatorRegex:              "match",
+		WorkflowConditionsOperatorSemver:             "satisfies semver range",
+		WorkflowConditionsOperatorGlob:               "match glob",
 	}
 )
 
@@ -76,8 +82,42 @@ func WorkflowCheckConditions(conditions []WorkflowNodeCondition, params []Parame
 				return false, fmt.Errorf("Unable to match string with regex %s (%v)", cond.Value, err)
 			}
 			conditionsOK = conditionsOK && match
+
+		case WorkflowConditionsOperatorSemver:
+			match, err := SemverMatch(mapParams[cond.Variable], cond.Value)
+			if err != nil {
+				return false, err
+			}
+			conditionsOK = conditionsOK && match
+
+		case WorkflowConditionsOperatorGlob:
+			conditionsOK = conditionsOK && GlobMatch(cond.Value, mapParams[cond.Variable])
 		}
 	}
 
 	return conditionsOK, nil
 }
+
+// SemverMatch checks if the version satisfies the range (ie. ">=2.0.0 <3.0.0 || >=4.0.0").
+// A version which is not a valid semver never matches, a leading "v" is allowed.
+func SemverMatch(version, rangeExpr string) (bool, error) {
+	r, err := semver.ParseRange(rangeExpr)
+	if err != nil {
+		return false, fmt.Errorf("Unable to parse semver range %s (%v)", rangeExpr, err)
+	}
+	v, err := semver.ParseTolerant(version)
+	if err != nil {
+		return false, nil
+	}
+	return r(v), nil
+}
+
+// GlobMatch checks if the value matches one of the comma separated glob patterns (ie. "v2.*,release/*")
+func GlobMatch(patterns, value string) bool {
+	for _, p := range PathFilters(patterns) {
+		if PathMatch(p, value) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,35 @@
+package sdk
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorkflowCheckConditionsSemverAndGlob(t *testing.T) {
+	params := []Parameter{
+		{Name: "cds.semver", Type: StringParameter, Value: "2.3.1"},
+		{Name: "git.tag", Type: StringParameter, Value: "v2.3.1"},
+	}
+
+	tests := []struct {
+		condition WorkflowNodeCondition
+		ok        bool
+	}{
+		{WorkflowNodeCondition{Variable: "cds.semver", Operator: WorkflowConditionsOperatorSemver, Value: ">=2.0.0"}, true},
+		{WorkflowNodeCondition{Variable: "cds.semver", Operator: WorkflowConditionsOperatorSemver, Value: ">=2.0.0 <2.3.0"}, false},
+		{WorkflowNodeCondition{Variable: "cds.semver", Operator: WorkflowConditionsOperatorSemver, Value: "<2.0.0 || >=2.3.0"}, true},
+		{WorkflowNodeCondition{Variable: "git.tag", Operator: WorkflowConditionsOperatorSemver, Value: "2.x"}, true},
+		{WorkflowNodeCondition{Variable: "git.branch", Operator: WorkflowConditionsOperatorSemver, Value: ">=1.0.0"}, false},
+		{WorkflowNodeCondition{Variable: "git.tag", Operator: WorkflowConditionsOperatorGlob, Value: "v2.*"}, true},
+		{WorkflowNodeCondition{Variable: "git.tag", Operator: WorkflowConditionsOperatorGlob, Value: "v1.*,v3.*"}, false},
+	}
+	for _, tt := range tests {
+		ok, err := WorkflowCheckConditions([]WorkflowNodeCondition{tt.condition}, params)
+		assert.NoError(t, err)
+		assert.Equal(t, tt.ok, ok, "%s %s %s", tt.condition.Variable, tt.condition.Operator, tt.condition.Value)
+	}
+
+	_, err := WorkflowCheckConditions([]WorkflowNodeCondition{{Variable: "cds.semver", Operator: WorkflowConditionsOperatorSemver, Value: "foo"}}, params)
+	assert.Error(t, err)
+}
@@ -0,0 +1,109 @@
+package sdk
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"net/url"
+	"strconv"
+
+	"github.com/pkg/errors"
+)
+
+// WorkflowFilter filters the workflows of a project, a workflow must match all the criteria that are set:
+// all the labels, the status of its last run, the repository of its as code files or of one of its applications,
+// the template it was generated from as group/slug and being a favorite of the user
+type WorkflowFilter struct {
+	Labels     []string `json:"labels,omitempty"`
+	Status     string   `json:"status,omitempty"`
+	Repository string   `json:"repository,omitempty"`
+	Template   string   `json:"template,omitempty"`
+	Favorite   bool     `json:"favorite,omitempty"`
+}
+
+// WorkflowFilterFromValues returns the filter given in the query parameters of a request
+func WorkflowFilterFromValues(q url.Values) WorkflowFilter {
+	favorite, _ := strconv.ParseBool(q.Get("favorite"))
+	return WorkflowFilter{
+		Labels:     q["label"],
+		Status:     q.Get("status"),
+		Repository: q.Get("repository"),
+		Template:   q.Get("template"),
+		Favorite:   favorite,
+	}
+}
+
+// Values returns the query parameters of the filter
+func (f WorkflowFilter) Values() url.Values {
+	q := url.Values{}
+	for _, l := range f.Labels {
+		q.Add("label", l)
+	}
+	if f.Status != "" {
+		q.Set("status", f.Status)
+	}
+	if f.Repository != "" {
+		q.Set("repository", f.Repository)
+	}
+	if f.Template != "" {
+		q.Set("template", f.Template)
+	}
+	if f.Favorite {
+		q.Set("favorite", "true")
+	}
+	return q
+}
+
+// IsEmpty returns true if the filter has no criteria
+func (f WorkflowFilter) IsEmpty() bool {
+	return len(f.Labels) == 0 && f.Status == "" && f.Repository == "" && f.Template == "" && !f.Favorite
+}
+
+// IsValid returns an error if the status of the filter is unknown
+func (f WorkflowFilter) IsValid() error {
+	if f.Status != "" && StatusFromString(f.Status) == StatusUnknown {
+		return NewErrorFrom(ErrWrongRequest, "invalid status %s", f.Status)
+	}
+	return nil
+}
+
+// Value returns driver.Value from workflow filter.
+func (f WorkflowFilter) Value() (driver.Value, error) {
+	j, err := json.Marshal(f)
+	return j, WrapError(err, "cannot marshal WorkflowFilter")
+}
+
+// Scan workflow filter.
+func (f *WorkflowFilter) Scan(src interface{}) error {
+	source, ok := src.([]byte)
+	if !ok {
+		return WithStack(errors.New("type assertion .([]byte) failed"))
+	}
+	return WrapError(json.Unmarshal(source, f), "cannot unmarshal WorkflowFilter")
+}
+
+// WorkflowSavedFilter is a filter on the workflows of a project saved by a user
+type WorkflowSavedFilter struct {
+	ID        int64          `json:"id" db:"id" cli:"-"`
+	UserID    int64          `json:"-" db:"user_id" cli:"-"`
+	ProjectID int64          `json:"-" db:"project_id" cli:"-"`
+	Name      string         `json:"name" db:"name" cli:"name,key"`
+	Filter    WorkflowFilter `json:"filter" db:"filter" cli:"-"`
+}
+
+// IsValid returns an error if the name or the filter are invalid
+func (f WorkflowSavedFilter) IsValid() error {
+	if !NamePatternRegex.MatchString(f.Name) {
+		return NewErrorFrom(ErrInvalidName, "invalid filter name, should match %s", NamePattern)
+	}
+	if f.Filter.IsEmpty() {
+		return NewErrorFrom(ErrWrongRequest, "filter should not be empty")
+	}
+	return f.Filter.IsValid()
+}
+
+// WorkflowLabelsRequest adds and removes labels on several workflows of a project, the missing labels are created
+type WorkflowLabelsRequest struct {
+	Workflows []string `json:"workflows"`
+	Add       []string `json:"add,omitempty"`
+	Remove    []string `json:"remove,omitempty"`
+}
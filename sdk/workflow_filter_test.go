@@ -0,0 +1,25 @@
+package sdk
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorkflowFilterValues(t *testing.T) {
+	f := WorkflowFilter{
+		Labels:   []string{"team-a", "backend"},
+		Status:   StatusFail.String(),
+		Template: "shared.infra/go-build",
+		Favorite: true,
+	}
+	assert.Equal(t, "favorite=true&label=team-a&label=backend&status=Fail&template=shared.infra%2Fgo-build", f.Values().Encode())
+	assert.Equal(t, f, WorkflowFilterFromValues(f.Values()))
+	assert.False(t, f.IsEmpty())
+	assert.NoError(t, f.IsValid())
+
+	assert.True(t, WorkflowFilterFromValues(nil).IsEmpty())
+	assert.Error(t, WorkflowFilter{Status: "Done"}.IsValid())
+	assert.Error(t, WorkflowSavedFilter{Name: "my filter", Filter: f}.IsValid())
+	assert.Error(t, WorkflowSavedFilter{Name: "empty"}.IsValid())
+}
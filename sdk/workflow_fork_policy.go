@@ -0,0 +1,154 @@
+package sdk
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// The secrets given to the runs of the pull requests from forks
+const (
+	ForkSecretsNone       = "none"
+	ForkSecretsRestricted = "restricted"
+	ForkSecretsAll        = "all"
+)
+
+// The approvals required for the runs of the pull requests from forks
+const (
+	ForkApprovalFirstRun = "first-run"
+	ForkApprovalNone     = "none"
+)
+
+// Payload values set by the hooks on the pull request events
+const (
+	PayloadPullRequestFork           = "git.pr.fork"
+	PayloadPullRequestBaseRepository = "git.pr.base.repository"
+)
+
+// WorkflowForkPolicy is the policy applied to the runs triggered by the pull requests from forks.
+// By default the secrets are withheld and the first run of each branch of a fork must be approved by a maintainer.
+type WorkflowForkPolicy struct {
+	Secrets        string   `json:"secrets,omitempty" yaml:"secrets,omitempty"`
+	AllowedSecrets []string `json:"allowed_secrets,omitempty" yaml:"allowed_secrets,omitempty"`
+	Approval       string   `json:"approval,omitempty" yaml:"approval,omitempty"`
+}
+
+// IsValid checks the values of the policy
+func (p WorkflowForkPolicy) IsValid() error {
+	switch p.Secrets {
+	case "", ForkSecretsNone, ForkSecretsAll:
+		if len(p.AllowedSecrets) > 0 {
+			return NewErrorFrom(ErrWrongRequest, "allowed secrets can only be set with the %s fork secrets policy", ForkSecretsRestricted)
+		}
+	case ForkSecretsRestricted:
+	default:
+		return NewErrorFrom(ErrWrongRequest, "invalid fork secrets policy %s", p.Secrets)
+	}
+	switch p.Approval {
+	case "", ForkApprovalFirstRun, ForkApprovalNone:
+	default:
+		return NewErrorFrom(ErrWrongRequest, "invalid fork approval policy %s", p.Approval)
+	}
+	return nil
+}
+
+// IsDefault returns true if the policy is the default one
+func (p WorkflowForkPolicy) IsDefault() bool {
+	return (p.Secrets == "" || p.Secrets == ForkSecretsNone) && (p.Approval == "" || p.Approval == ForkApprovalFirstRun)
+}
+
+// RequireApproval returns true if the first run of a fork must be approved
+func (p WorkflowForkPolicy) RequireApproval() bool {
+	return p.Approval != ForkApprovalNone
+}
+
+// FilterSecrets returns the secrets given to a run of a pull request from a fork
+func (p WorkflowForkPolicy) FilterSecrets(secrets []Variable) []Variable {
+	if p.Secrets == ForkSecretsAll {
+		return secrets
+	}
+	res := []Variable{}
+	for _, s := range secrets {
+		if p.AllowSecret(s.Name) {
+			res = append(res, s)
+		}
+	}
+	return res
+}
+
+// AllowSecret returns true if the secret is given to a run of a pull request from a fork
+func (p WorkflowForkPolicy) AllowSecret(name string) bool {
+	switch p.Secrets {
+	case ForkSecretsAll:
+		return true
+	case ForkSecretsRestricted:
+		return IsInArray(name, p.AllowedSecrets)
+	default:
+		return false
+	}
+}
+
+// AllowSecretPrefix returns true if at least one secret with the given prefix is given to a run of a pull request
+// from a fork
+func (p WorkflowForkPolicy) AllowSecretPrefix(prefix string) bool {
+	switch p.Secrets {
+	case ForkSecretsAll:
+		return true
+	case ForkSecretsRestricted:
+		for _, s := range p.AllowedSecrets {
+			if strings.HasPrefix(s, prefix) {
+				return true
+			}
+		}
+		return false
+	default:
+		return false
+	}
+}
+
+// IsForkPullRequest returns true if the payload of a hook event is a pull request from a fork
+func IsForkPullRequest(payload map[string]string) bool {
+	return payload[PayloadPullRequestFork] == "true"
+}
+
+// IsPullRequest returns true if the payload of a hook event is a pull request
+func IsPullRequest(payload map[string]string) bool {
+	return payload[PayloadPullRequestBaseRepository] != "" || payload[PayloadPullRequestID] != ""
+}
+
+// WorkflowForkApproval is the approval of the runs of a branch of a fork
+type WorkflowForkApproval struct {
+	ID           int64                    `json:"id" db:"id" cli:"id,key"`
+	WorkflowID   int64                    `json:"workflow_id" db:"workflow_id" cli:"-"`
+	Repository   string                   `json:"repository" db:"repository" cli:"repository"`
+	Branch       string                   `json:"branch" db:"branch" cli:"branch"`
+	Author       string                   `json:"author" db:"author" cli:"author"`
+	Created      time.Time                `json:"created" db:"created" cli:"created"`
+	Approved     bool                     `json:"approved" db:"approved" cli:"approved"`
+	Approver     string                   `json:"approver,omitempty" db:"approver" cli:"approver"`
+	ApprovalDate *time.Time               `json:"approval_date,omitempty" db:"approval_date" cli:"-"`
+	HookEvent    WorkflowNodeRunHookEvent `json:"hook_event" db:"hook_event" cli:"-"`
+}
+
+// String returns the fork and the branch of an approval
+func (a WorkflowForkApproval) String() string {
+	return fmt.Sprintf("%s:%s", a.Repository, a.Branch)
+}
+
+// Value returns driver.Value from workflow node run hook event.
+func (e WorkflowNodeRunHookEvent) Value() (driver.Value, error) {
+	j, err := json.Marshal(e)
+	return j, WrapError(err, "cannot marshal WorkflowNodeRunHookEvent")
+}
+
+// Scan workflow node run hook event.
+func (e *WorkflowNodeRunHookEvent) Scan(src interface{}) error {
+	source, ok := src.([]byte)
+	if !ok {
+		return WithStack(errors.New("type assertion .([]byte) failed"))
+	}
+	return WrapError(json.Unmarshal(source, e), "cannot unmarshal WorkflowNodeRunHookEvent")
+}
@@ -0,0 +1,37 @@
+package sdk
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorkflowForkPolicy(t *testing.T) {
+	var p WorkflowForkPolicy
+	assert.NoError(t, p.IsValid())
+	assert.True(t, p.IsDefault())
+	assert.True(t, p.RequireApproval())
+
+	p = WorkflowForkPolicy{Secrets: ForkSecretsNone, AllowedSecrets: []string{"cds.app.token"}}
+	assert.Error(t, p.IsValid(), "allowed secrets are only used by the restricted policy")
+
+	p = WorkflowForkPolicy{Secrets: "some"}
+	assert.Error(t, p.IsValid())
+
+	p = WorkflowForkPolicy{Secrets: ForkSecretsRestricted, AllowedSecrets: []string{"cds.app.token"}, Approval: ForkApprovalNone}
+	assert.NoError(t, p.IsValid())
+	assert.False(t, p.IsDefault())
+	assert.False(t, p.RequireApproval())
+	assert.Equal(t, []Variable{{Name: "cds.app.token"}}, p.FilterSecrets([]Variable{{Name: "cds.app.token"}, {Name: "cds.proj.key"}}))
+	assert.True(t, p.AllowSecretPrefix("cds.app."))
+	assert.False(t, p.AllowSecretPrefix("cds.vault.prod."))
+	assert.False(t, WorkflowForkPolicy{}.AllowSecretPrefix("cds.app."))
+	assert.True(t, WorkflowForkPolicy{Secrets: ForkSecretsAll}.AllowSecretPrefix("cds.vault.prod."))
+
+	assert.True(t, IsForkPullRequest(map[string]string{PayloadPullRequestFork: "true"}))
+	assert.False(t, IsForkPullRequest(map[string]string{"git.branch": "master"}))
+
+	assert.True(t, IsPullRequest(map[string]string{PayloadPullRequestBaseRepository: "ovh/cds"}))
+	assert.True(t, IsPullRequest(map[string]string{PayloadPullRequestID: "42"}))
+	assert.False(t, IsPullRequest(map[string]string{"git.branch": "master"}))
+}
@@ -7,6 +7,13 @@ const (
 	BitbucketIcon = "Bitbucket"
 )
 
+// Those are the events of the repository webhooks created by CDS on each repository manager
+var (
+	GitlabWebHookEvents    = []string{"push", "note"}
+	GitHubWebHookEvents    = []string{"push", "issue_comment"}
+	BitbucketWebHookEvents = []string{"repo:refs_changed", "pr:comment:added"}
+)
+
 // FilterHooksConfig filter all hooks configuration and remove some configuration key
 func (w *Workflow) FilterHooksConfig(s ...string) {
 	if w.Root == nil {
@@ -224,8 +231,30 @@ const (
 	HookConfigTypeWorkflow = "workflow"
 	// HookConfigTypeHook type hook
 	HookConfigTypeHook = "hook"
+	// HookConfigTypeSecret type secret, the value is encrypted
+	HookConfigTypeSecret = "secret"
 )
 
+// MaskHooksSecrets replaces the secrets of the repository webhooks of the workflow by a placeholder
+func (w *Workflow) MaskHooksSecrets() {
+	mask := func(cfg WorkflowNodeHookConfig) {
+		if v, ok := cfg[RepositoryWebHookSecret]; ok && v.Value != "" {
+			v.Value = PasswordPlaceholder
+			cfg[RepositoryWebHookSecret] = v
+		}
+	}
+	for _, h := range w.GetHooks() {
+		mask(h.Config)
+	}
+	if w.WorkflowData != nil {
+		for _, n := range w.WorkflowData.Array() {
+			for _, h := range n.Hooks {
+				mask(h.Config)
+			}
+		}
+	}
+}
+
 //WorkflowHookModel represents a hook which can be used in workflows.
 type WorkflowHookModel struct {
 	ID            int64                  `json:"id" db:"id" cli:"-"`
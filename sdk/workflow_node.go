@@ -26,6 +26,7 @@ type Node struct {
 	Context             *NodeContext      `json:"context" db:"-"`
 	OutGoingHookContext *NodeOutGoingHook `json:"outgoing_hook" db:"-"`
 	JoinContext         []NodeJoin        `json:"parents" db:"-"`
+	JoinStrategy        *NodeJoinStrategy `json:"join_strategy,omitempty" db:"-"`
 	Hooks               []NodeHook        `json:"hooks" db:"-"`
 	Groups              []GroupPermission `json:"groups,omitempty" db:"-"`
 }
@@ -148,6 +149,42 @@ type NodeJoin struct {
 	ParentID   int64  `json:"parent_id,omitempty" db:"parent_id"`
 }
 
+// These are the semantics available to trigger a join node
+const (
+	JoinStrategyAllSuccess  = "all_success"
+	JoinStrategyAnySuccess  = "any_success"
+	JoinStrategyAtLeast     = "at_least"
+	JoinStrategyAllFinished = "all_finished"
+)
+
+// NodeJoinStrategy represents the condition on the parents runs to trigger a join node
+type NodeJoinStrategy struct {
+	Type string `json:"type"`
+	Min  int    `json:"min,omitempty"`
+}
+
+// GetType returns the join strategy type, all_success by default
+func (s *NodeJoinStrategy) GetType() string {
+	if s == nil || s.Type == "" {
+		return JoinStrategyAllSuccess
+	}
+	return s.Type
+}
+
+// IsValid checks the join strategy for the given number of parents
+func (s *NodeJoinStrategy) IsValid(nbParents int) error {
+	switch s.GetType() {
+	case JoinStrategyAllSuccess, JoinStrategyAnySuccess, JoinStrategyAllFinished:
+		return nil
+	case JoinStrategyAtLeast:
+		if s.Min < 1 || s.Min > nbParents {
+			return NewErrorFrom(ErrWorkflowInvalid, "join strategy %s expects a minimum between 1 and %d", s.Type, nbParents)
+		}
+		return nil
+	}
+	return NewErrorFrom(ErrWorkflowInvalid, "unknown join strategy %s", s.Type)
+}
+
 func (n *Node) nodeByRef(ref string) *Node {
 	if n.Ref == ref {
 		return n
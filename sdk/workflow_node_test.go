@@ -137,3 +137,12 @@ func TestAncestorsSimple(t *testing.T) {
 
 	}
 }
+
+func TestNodeJoinStrategyIsValid(t *testing.T) {
+	var s *NodeJoinStrategy
+	assert.Equal(t, JoinStrategyAllSuccess, s.GetType())
+	assert.NoError(t, s.IsValid(2))
+	assert.NoError(t, (&NodeJoinStrategy{Type: JoinStrategyAtLeast, Min: 2}).IsValid(2))
+	assert.Error(t, (&NodeJoinStrategy{Type: JoinStrategyAtLeast, Min: 3}).IsValid(2))
+	assert.Error(t, (&NodeJoinStrategy{Type: "foo"}).IsValid(2))
+}
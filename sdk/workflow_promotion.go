@@ -0,0 +1,24 @@
+package sdk
+
+import "time"
+
+// WorkflowPromotion is the promotion of a workflow from another CDS instance, it is recorded on the target
+// instance with the diff of the workflow and its dependencies
+type WorkflowPromotion struct {
+	ID           int64     `json:"id" db:"id" cli:"id,key"`
+	ProjectKey   string    `json:"project_key" db:"project_key" cli:"-"`
+	WorkflowName string    `json:"workflow_name" db:"workflow_name" cli:"workflow"`
+	Source       string    `json:"source" db:"source" cli:"source"`
+	Username     string    `json:"username" db:"username" cli:"username"`
+	Comment      string    `json:"comment" db:"comment" cli:"comment"`
+	Created      time.Time `json:"created" db:"created" cli:"created"`
+	Diff         string    `json:"diff,omitempty" db:"diff" cli:"-"`
+	Messages     []string  `json:"messages,omitempty" db:"-" cli:"-"`
+}
+
+// WorkflowPromotionRequest is the request to promote a workflow to a remote CDS instance
+type WorkflowPromotionRequest struct {
+	Remote  string `json:"remote"`
+	Project string `json:"project,omitempty"`
+	Comment string `json:"comment,omitempty"`
+}
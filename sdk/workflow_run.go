@@ -11,6 +11,8 @@ import (
 
 	"github.com/ovh/venom"
 	"github.com/sguiheux/go-coverage"
+
+	"github.com/ovh/cds/sdk/log"
 )
 
 var (
@@ -54,6 +56,34 @@ type WorkflowRun struct {
 	ToDelete         bool                             `json:"to_delete" db:"to_delete" cli:"-"`
 	JoinTriggersRun  map[int64]WorkflowNodeTriggerRun `json:"join_triggers_run,omitempty" db:"-"`
 	Header           WorkflowRunHeaders               `json:"header,omitempty" db:"-"`
+	Priority         int                              `json:"priority" db:"priority"`
+}
+
+// Priorities of the workflow runs, the jobs of the runs with the highest priority are started first
+const (
+	WorkflowRunPriorityLow    = -1
+	WorkflowRunPriorityNormal = 0
+	WorkflowRunPriorityHigh   = 1
+	WorkflowRunPriorityUrgent = 2
+)
+
+// WorkflowRunPriorityValidate returns true if the priority is one of the priority levels
+func WorkflowRunPriorityValidate(p int) bool {
+	return p >= WorkflowRunPriorityLow && p <= WorkflowRunPriorityUrgent
+}
+
+// WorkflowRunPriorityName returns the name of a priority level
+func WorkflowRunPriorityName(p int) string {
+	switch {
+	case p <= WorkflowRunPriorityLow:
+		return "low"
+	case p == WorkflowRunPriorityNormal:
+		return "normal"
+	case p == WorkflowRunPriorityHigh:
+		return "high"
+	default:
+		return "urgent"
+	}
 }
 
 // WorkflowNodeRunRelease represents the request struct use by release builtin action for workflow
@@ -64,12 +94,31 @@ type WorkflowNodeRunRelease struct {
 	Artifacts      []string `json:"artifacts,omitempty"`
 }
 
+// WorkflowNodeRunPromote represents the request struct use by promote builtin action to promote the build-info
+// of a workflow node run in Artifactory. The API returns it with the repositories of the promotion.
+type WorkflowNodeRunPromote struct {
+	Integration      string `json:"integration,omitempty"`
+	Status           string `json:"status"`
+	BuildName        string `json:"build_name,omitempty"`
+	BuildNumber      string `json:"build_number,omitempty"`
+	Comment          string `json:"comment,omitempty"`
+	Copy             bool   `json:"copy,omitempty"`
+	SourceRepository string `json:"source_repository,omitempty"`
+	TargetRepository string `json:"target_repository,omitempty"`
+}
+
 // WorkflowRunPostHandlerOption contains the body content for launch a workflow
 type WorkflowRunPostHandlerOption struct {
 	Hook        *WorkflowNodeRunHookEvent `json:"hook,omitempty"`
 	Manual      *WorkflowNodeRunManual    `json:"manual,omitempty"`
 	Number      *int64                    `json:"number,omitempty"`
 	FromNodeIDs []int64                   `json:"from_nodes,omitempty"`
+	Priority    *int                      `json:"priority,omitempty"`
+}
+
+// WorkflowRunPriority is the body to change the priority of a workflow run
+type WorkflowRunPriority struct {
+	Priority int `json:"priority"`
 }
 
 //WorkflowRunNumber contains a workflow run number
@@ -228,6 +277,7 @@ type WorkflowNodeRun struct {
 	Coverage               WorkflowNodeRunCoverage              `json:"coverage,omitempty"`
 	VulnerabilitiesReport  WorkflowNodeRunVulnerabilityReport   `json:"vulnerabilities_report,omitempty"`
 	Tests                  *venom.Tests                         `json:"tests,omitempty"`
+	FlakyTests             []WorkflowTestCaseFlakiness          `json:"flaky_tests,omitempty"`
 	Commits                []VCSCommit                          `json:"commits,omitempty"`
 	TriggersRun            map[int64]WorkflowNodeTriggerRun     `json:"triggers_run,omitempty"`
 	VCSRepository          string                               `json:"vcs_repository"`
@@ -285,6 +335,10 @@ type WorkflowNodeRunCoverage struct {
 	Branch            string                        `json:"branch" db:"branch"`
 	Report            coverage.Report               `json:"report" db:"-"`
 	Trend             WorkflowNodeRunCoverageTrends `json:"trend" db:"-"`
+	LinesPercent      float64                       `json:"lines_percent" db:"lines_percent"`
+	FunctionsPercent  float64                       `json:"functions_percent" db:"functions_percent"`
+	BranchesPercent   float64                       `json:"branches_percent" db:"branches_percent"`
+	Created           time.Time                     `json:"created" db:"created"`
 }
 
 // WorkflowNodeRunCoverageTrends represents code coverage trend with current branch and default branch
@@ -321,6 +375,9 @@ type WorkflowNodeRunArtifact struct {
 	Perm              uint32    `json:"perm,omitempty" db:"perm"`
 	MD5sum            string    `json:"md5sum,omitempty" db:"md5sum" cli:"-"`
 	SHA512sum         string    `json:"sha512sum,omitempty" db:"sha512sum" cli:"sha512sum"`
+	SHA256sum         string    `json:"sha256sum,omitempty" db:"sha256sum" cli:"sha256sum"`
+	SigningKey        string    `json:"signing_key,omitempty" db:"signing_key" cli:"signing_key"`
+	Signature         string    `json:"signature,omitempty" db:"signature" cli:"-"`
 	ObjectPath        string    `json:"object_path,omitempty" db:"object_path"`
 	Created           time.Time `json:"created,omitempty" db:"created"`
 	TempURL           string    `json:"temp_url,omitempty" db:"-"`
@@ -335,7 +392,9 @@ func (w WorkflowNodeRunArtifact) Equal(c WorkflowNodeRunArtifact) bool {
 			w.DownloadHash == c.DownloadHash &&
 			w.Tag == c.Tag &&
 			w.TempURL == c.TempURL &&
-			w.SHA512sum == c.SHA512sum
+			w.SHA512sum == c.SHA512sum &&
+			w.SHA256sum == c.SHA256sum &&
+			w.SigningKey == c.SigningKey
 	}
 	return w.WorkflowID == c.WorkflowID &&
 		w.WorkflowNodeRunID == c.WorkflowNodeRunID &&
@@ -369,6 +428,25 @@ type WorkflowNodeJobRun struct {
 	IntegrationPluginBinaries []GRPCPluginBinary `json:"integration_plugin_binaries,omitempty"`
 	Header                    WorkflowRunHeaders `json:"header,omitempty"`
 	ContainsService           bool               `json:"contains_service,omitempty"`
+	Priority                  int                `json:"priority,omitempty"`
+}
+
+// LogFields returns the fields used to correlate the logs about the job between the services
+func (njr *WorkflowNodeJobRun) LogFields() log.Fields {
+	f := log.Fields{
+		log.FieldJobID:             njr.ID,
+		log.FieldWorkflowNodeRunID: njr.WorkflowNodeRunID,
+	}
+	if v, ok := njr.Header.Get(ProjectKeyHeader); ok {
+		f[log.FieldProjectKey] = v
+	}
+	if v, ok := njr.Header.Get(WorkflowHeader); ok {
+		f[log.FieldWorkflow] = v
+	}
+	if v, ok := njr.Header.Get(WorkflowRunHeader); ok {
+		f[log.FieldWorkflowRunNumber] = v
+	}
+	return f
 }
 
 // /!\ DONT FORGET TO REGENERATE EASYJSON FILES /!\
@@ -436,6 +514,24 @@ type WorkflowNodeRunManual struct {
 	User               User        `json:"user" db:"-"`
 }
 
+// Attestation returns the statement of the checksums of the artifact, signed with its signing key
+func (w WorkflowNodeRunArtifact) Attestation() []byte {
+	return []byte(fmt.Sprintf("name: %s\ntag: %s\nsize: %d\nsha256sum: %s\nsha512sum: %s\n", w.Name, w.Tag, w.Size, w.SHA256sum, w.SHA512sum))
+}
+
+// WorkflowNodeRunArtifactVerification is the verification of the signature of an artifact
+type WorkflowNodeRunArtifactVerification struct {
+	ID         int64  `json:"id" cli:"-"`
+	Name       string `json:"name" cli:"name,key"`
+	SHA256sum  string `json:"sha256sum,omitempty" cli:"sha256sum"`
+	SHA512sum  string `json:"sha512sum,omitempty" cli:"sha512sum"`
+	Signed     bool   `json:"signed" cli:"signed"`
+	SigningKey string `json:"signing_key,omitempty" cli:"signing_key"`
+	KeyID      string `json:"key_id,omitempty" cli:"key_id"`
+	Verified   bool   `json:"verified" cli:"verified"`
+	Error      string `json:"error,omitempty" cli:"error"`
+}
+
 //GetName returns the name the artifact
 func (w *WorkflowNodeRunArtifact) GetName() string {
 	return w.Name
@@ -501,7 +597,10 @@ func (q WorkflowQueue) Sort() {
 		n[j.ProjectID] = nb
 	}
 
-	sort.Slice(q, func(i, j int) bool {
+	sort.SliceStable(q, func(i, j int) bool {
+		if q[i].Priority != q[j].Priority {
+			return q[i].Priority > q[j].Priority
+		}
 		p1 := n[q[i].ProjectID]
 		p2 := n[q[j].ProjectID]
 		return p1 < p2
@@ -0,0 +1,308 @@
+package sdk
+
+import (
+	"sort"
+	"time"
+
+	"github.com/fsamin/go-dump"
+)
+
+// Changes of an artifact between two runs
+const (
+	WorkflowRunComparisonAdded     = "added"
+	WorkflowRunComparisonRemoved   = "removed"
+	WorkflowRunComparisonChanged   = "changed"
+	WorkflowRunComparisonUnchanged = "unchanged"
+)
+
+// WorkflowRunComparison lists the differences between two runs of a workflow
+type WorkflowRunComparison struct {
+	From      WorkflowRunComparisonRun        `json:"from"`
+	To        WorkflowRunComparisonRun        `json:"to"`
+	Payload   []WorkflowRunComparisonValue    `json:"payload"`
+	Commits   []WorkflowRunComparisonCommits  `json:"commits"`
+	Nodes     []WorkflowRunComparisonNode     `json:"nodes"`
+	Artifacts []WorkflowRunComparisonArtifact `json:"artifacts"`
+}
+
+// WorkflowRunComparisonRun is a run compared with another one
+type WorkflowRunComparisonRun struct {
+	Number   int64     `json:"num"`
+	Status   string    `json:"status"`
+	Start    time.Time `json:"start"`
+	Duration float64   `json:"duration"`
+}
+
+// WorkflowRunComparisonValue is a value of the payload which differs between two runs
+type WorkflowRunComparisonValue struct {
+	Key  string `json:"key"`
+	From string `json:"from,omitempty"`
+	To   string `json:"to,omitempty"`
+}
+
+// WorkflowRunComparisonCommits is the range of commits of a repository between two runs
+type WorkflowRunComparisonCommits struct {
+	Repository string `json:"repository"`
+	FromBranch string `json:"from_branch,omitempty"`
+	FromHash   string `json:"from_hash,omitempty"`
+	ToBranch   string `json:"to_branch,omitempty"`
+	ToHash     string `json:"to_hash,omitempty"`
+}
+
+// WorkflowRunComparisonNode compares the runs of a node of the workflow. Durations are in seconds.
+type WorkflowRunComparisonNode struct {
+	Name         string                     `json:"name"`
+	FromStatus   string                     `json:"from_status,omitempty"`
+	ToStatus     string                     `json:"to_status,omitempty"`
+	FromDuration float64                    `json:"from_duration"`
+	ToDuration   float64                    `json:"to_duration"`
+	DurationDiff float64                    `json:"duration_diff"`
+	Jobs         []WorkflowRunComparisonJob `json:"jobs,omitempty"`
+}
+
+// WorkflowRunComparisonJob compares the runs of a job of a node. Durations are in seconds.
+type WorkflowRunComparisonJob struct {
+	Name         string  `json:"name"`
+	FromStatus   string  `json:"from_status,omitempty"`
+	ToStatus     string  `json:"to_status,omitempty"`
+	FromDuration float64 `json:"from_duration"`
+	ToDuration   float64 `json:"to_duration"`
+	DurationDiff float64 `json:"duration_diff"`
+}
+
+// WorkflowRunComparisonArtifact compares the checksums of an artifact uploaded by a node in two runs
+type WorkflowRunComparisonArtifact struct {
+	NodeName string `json:"node_name"`
+	Name     string `json:"name"`
+	Change   string `json:"change"`
+	FromHash string `json:"from_sha256sum,omitempty"`
+	ToHash   string `json:"to_sha256sum,omitempty"`
+}
+
+// CompareWorkflowRuns returns the differences between two runs of a workflow.
+// Only the last subnumber of each node run is compared.
+func CompareWorkflowRuns(from, to WorkflowRun) WorkflowRunComparison {
+	c := WorkflowRunComparison{
+		From:      newWorkflowRunComparisonRun(from),
+		To:        newWorkflowRunComparisonRun(to),
+		Payload:   []WorkflowRunComparisonValue{},
+		Commits:   []WorkflowRunComparisonCommits{},
+		Nodes:     []WorkflowRunComparisonNode{},
+		Artifacts: []WorkflowRunComparisonArtifact{},
+	}
+
+	fromNodes, toNodes := lastNodeRunsByName(from), lastNodeRunsByName(to)
+	names := nodeRunNames(fromNodes, toNodes)
+
+	c.Payload = comparePayloads(rootNodeRunPayload(from), rootNodeRunPayload(to))
+
+	repositories := map[string]*WorkflowRunComparisonCommits{}
+	var repositoryNames []string
+	for _, name := range names {
+		fromNode, toNode := fromNodes[name], toNodes[name]
+		for _, nr := range []*WorkflowNodeRun{fromNode, toNode} {
+			if nr == nil || nr.VCSRepository == "" {
+				continue
+			}
+			if _, ok := repositories[nr.VCSRepository]; !ok {
+				repositories[nr.VCSRepository] = &WorkflowRunComparisonCommits{Repository: nr.VCSRepository}
+				repositoryNames = append(repositoryNames, nr.VCSRepository)
+			}
+			commits := repositories[nr.VCSRepository]
+			if nr == fromNode && commits.FromHash == "" {
+				commits.FromBranch, commits.FromHash = nr.VCSBranch, nr.VCSHash
+			}
+			if nr == toNode && commits.ToHash == "" {
+				commits.ToBranch, commits.ToHash = nr.VCSBranch, nr.VCSHash
+			}
+		}
+
+		c.Nodes = append(c.Nodes, compareNodeRuns(name, fromNode, toNode))
+		c.Artifacts = append(c.Artifacts, compareArtifacts(name, fromNode, toNode)...)
+	}
+	sort.Strings(repositoryNames)
+	for _, r := range repositoryNames {
+		c.Commits = append(c.Commits, *repositories[r])
+	}
+
+	return c
+}
+
+func newWorkflowRunComparisonRun(r WorkflowRun) WorkflowRunComparisonRun {
+	return WorkflowRunComparisonRun{
+		Number:   r.Number,
+		Status:   r.Status,
+		Start:    r.Start,
+		Duration: comparisonDuration(r.Start, r.LastModified),
+	}
+}
+
+func comparisonDuration(start, done time.Time) float64 {
+	if start.IsZero() || done.IsZero() || done.Before(start) {
+		return 0
+	}
+	return done.Sub(start).Seconds()
+}
+
+func lastNodeRunsByName(r WorkflowRun) map[string]*WorkflowNodeRun {
+	res := map[string]*WorkflowNodeRun{}
+	for _, nrs := range r.WorkflowNodeRuns {
+		for i := range nrs {
+			nr := &nrs[i]
+			if last, ok := res[nr.WorkflowNodeName]; !ok || nr.SubNumber > last.SubNumber {
+				res[nr.WorkflowNodeName] = nr
+			}
+		}
+	}
+	return res
+}
+
+func nodeRunNames(nodes ...map[string]*WorkflowNodeRun) []string {
+	set := map[string]struct{}{}
+	for _, m := range nodes {
+		for name := range m {
+			set[name] = struct{}{}
+		}
+	}
+	names := make([]string, 0, len(set))
+	for name := range set {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func rootNodeRunPayload(r WorkflowRun) interface{} {
+	if r.Workflow.WorkflowData == nil {
+		return nil
+	}
+	if rootRun := r.RootRun(); rootRun != nil {
+		return rootRun.Payload
+	}
+	return nil
+}
+
+func comparePayloads(from, to interface{}) []WorkflowRunComparisonValue {
+	fromValues, toValues := payloadToStringMap(from), payloadToStringMap(to)
+	keys := map[string]struct{}{}
+	for k := range fromValues {
+		keys[k] = struct{}{}
+	}
+	for k := range toValues {
+		keys[k] = struct{}{}
+	}
+
+	res := []WorkflowRunComparisonValue{}
+	for k := range keys {
+		if fromValues[k] != toValues[k] {
+			res = append(res, WorkflowRunComparisonValue{Key: k, From: fromValues[k], To: toValues[k]})
+		}
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Key < res[j].Key })
+	return res
+}
+
+func payloadToStringMap(payload interface{}) map[string]string {
+	if payload == nil {
+		return map[string]string{}
+	}
+	dumper := dump.NewDefaultEncoder(nil)
+	dumper.ExtraFields.DetailedMap = false
+	dumper.ExtraFields.DetailedStruct = false
+	dumper.ExtraFields.Len = false
+	dumper.ExtraFields.Type = false
+	m, err := dumper.ToStringMap(payload)
+	if err != nil {
+		return map[string]string{}
+	}
+	return m
+}
+
+func compareNodeRuns(name string, from, to *WorkflowNodeRun) WorkflowRunComparisonNode {
+	n := WorkflowRunComparisonNode{Name: name}
+	fromJobs, toJobs := map[string]WorkflowNodeJobRun{}, map[string]WorkflowNodeJobRun{}
+	var jobNames []string
+	for _, nr := range []*WorkflowNodeRun{from, to} {
+		if nr == nil {
+			continue
+		}
+		jobs := toJobs
+		if nr == from {
+			n.FromStatus, n.FromDuration = nr.Status, comparisonDuration(nr.Start, nr.Done)
+			jobs = fromJobs
+		} else {
+			n.ToStatus, n.ToDuration = nr.Status, comparisonDuration(nr.Start, nr.Done)
+		}
+		for _, s := range nr.Stages {
+			for _, j := range s.RunJobs {
+				jobName := s.Name + "/" + j.Job.Action.Name
+				if _, ok := fromJobs[jobName]; !ok {
+					if _, ok := toJobs[jobName]; !ok {
+						jobNames = append(jobNames, jobName)
+					}
+				}
+				jobs[jobName] = j
+			}
+		}
+	}
+	n.DurationDiff = n.ToDuration - n.FromDuration
+
+	for _, jobName := range jobNames {
+		j := WorkflowRunComparisonJob{Name: jobName}
+		if fj, ok := fromJobs[jobName]; ok {
+			j.FromStatus, j.FromDuration = fj.Status, comparisonDuration(fj.Start, fj.Done)
+		}
+		if tj, ok := toJobs[jobName]; ok {
+			j.ToStatus, j.ToDuration = tj.Status, comparisonDuration(tj.Start, tj.Done)
+		}
+		j.DurationDiff = j.ToDuration - j.FromDuration
+		n.Jobs = append(n.Jobs, j)
+	}
+	return n
+}
+
+func compareArtifacts(name string, from, to *WorkflowNodeRun) []WorkflowRunComparisonArtifact {
+	fromArtifacts, toArtifacts := map[string]string{}, map[string]string{}
+	var artifactNames []string
+	for _, nr := range []*WorkflowNodeRun{from, to} {
+		if nr == nil {
+			continue
+		}
+		artifacts := toArtifacts
+		if nr == from {
+			artifacts = fromArtifacts
+		}
+		for _, a := range nr.Artifacts {
+			_, inFrom := fromArtifacts[a.Name]
+			_, inTo := toArtifacts[a.Name]
+			if !inFrom && !inTo {
+				artifactNames = append(artifactNames, a.Name)
+			}
+			sum := a.SHA256sum
+			if sum == "" {
+				sum = a.MD5sum
+			}
+			artifacts[a.Name] = sum
+		}
+	}
+	sort.Strings(artifactNames)
+
+	var res []WorkflowRunComparisonArtifact
+	for _, a := range artifactNames {
+		fromHash, inFrom := fromArtifacts[a]
+		toHash, inTo := toArtifacts[a]
+		c := WorkflowRunComparisonArtifact{NodeName: name, Name: a, FromHash: fromHash, ToHash: toHash}
+		switch {
+		case !inFrom:
+			c.Change = WorkflowRunComparisonAdded
+		case !inTo:
+			c.Change = WorkflowRunComparisonRemoved
+		case fromHash != toHash:
+			c.Change = WorkflowRunComparisonChanged
+		default:
+			c.Change = WorkflowRunComparisonUnchanged
+		}
+		res = append(res, c)
+	}
+	return res
+}
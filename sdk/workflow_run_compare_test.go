@@ -0,0 +1,75 @@
+package sdk
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompareWorkflowRuns(t *testing.T) {
+	start := time.Date(2019, 1, 1, 10, 0, 0, 0, time.UTC)
+	wf := Workflow{WorkflowData: &WorkflowData{Node: Node{ID: 1, Name: "build"}}}
+
+	newRun := func(number int64, payload map[string]string, hash string, jobDuration time.Duration, artifactSum string) WorkflowRun {
+		return WorkflowRun{
+			Number:       number,
+			Status:       StatusSuccess.String(),
+			Start:        start,
+			LastModified: start.Add(jobDuration),
+			Workflow:     wf,
+			WorkflowNodeRuns: map[int64][]WorkflowNodeRun{
+				1: {{
+					WorkflowNodeName: "build",
+					Status:           StatusSuccess.String(),
+					Start:            start,
+					Done:             start.Add(jobDuration),
+					Payload:          payload,
+					VCSRepository:    "ovh/cds",
+					VCSBranch:        "master",
+					VCSHash:          hash,
+					Stages: []Stage{{
+						Name: "Build",
+						RunJobs: []WorkflowNodeJobRun{{
+							Status: StatusSuccess.String(),
+							Start:  start,
+							Done:   start.Add(jobDuration),
+							Job:    ExecutedJob{Job: Job{Action: Action{Name: "Compile"}}},
+						}},
+					}},
+					Artifacts: []WorkflowNodeRunArtifact{{Name: "cds.tar.gz", SHA256sum: artifactSum}},
+				}},
+			},
+		}
+	}
+
+	from := newRun(1, map[string]string{"git.branch": "master", "env": "prod"}, "abc", time.Minute, "111")
+	to := newRun(2, map[string]string{"git.branch": "master", "env": "preprod"}, "def", 3*time.Minute, "222")
+	to.WorkflowNodeRuns[1][0].Artifacts = append(to.WorkflowNodeRuns[1][0].Artifacts, WorkflowNodeRunArtifact{Name: "cds.sbom", SHA256sum: "333"})
+
+	c := CompareWorkflowRuns(from, to)
+	assert.Equal(t, int64(1), c.From.Number)
+	assert.Equal(t, 180.0, c.To.Duration)
+
+	assert.Equal(t, []WorkflowRunComparisonValue{{Key: "env", From: "prod", To: "preprod"}}, c.Payload)
+	assert.Equal(t, []WorkflowRunComparisonCommits{{Repository: "ovh/cds", FromBranch: "master", FromHash: "abc", ToBranch: "master", ToHash: "def"}}, c.Commits)
+
+	if assert.Len(t, c.Nodes, 1) {
+		assert.Equal(t, 120.0, c.Nodes[0].DurationDiff)
+		assert.Equal(t, []WorkflowRunComparisonJob{{
+			Name:         "Build/Compile",
+			FromStatus:   StatusSuccess.String(),
+			ToStatus:     StatusSuccess.String(),
+			FromDuration: 60,
+			ToDuration:   180,
+			DurationDiff: 120,
+		}}, c.Nodes[0].Jobs)
+	}
+
+	if assert.Len(t, c.Artifacts, 2) {
+		assert.Equal(t, "cds.sbom", c.Artifacts[0].Name)
+		assert.Equal(t, WorkflowRunComparisonAdded, c.Artifacts[0].Change)
+		assert.Equal(t, "cds.tar.gz", c.Artifacts[1].Name)
+		assert.Equal(t, WorkflowRunComparisonChanged, c.Artifacts[1].Change)
+	}
+}
@@ -0,0 +1,100 @@
+package sdk
+
+import (
+	"math"
+	"time"
+
+	"github.com/sguiheux/go-coverage"
+)
+
+// Formats of the coverage reports
+const (
+	CoverageFormatCobertura = "cobertura"
+	CoverageFormatLCOV      = "lcov"
+	CoverageFormatGoCover   = "gocover"
+)
+
+// CoverageFormats lists the formats of the coverage reports
+var CoverageFormats = []string{CoverageFormatCobertura, CoverageFormatLCOV, CoverageFormatGoCover}
+
+// CoverageSummary is the coverage percentages of a report
+type CoverageSummary struct {
+	TotalLines       int     `json:"total_lines"`
+	CoveredLines     int     `json:"covered_lines"`
+	LinesPercent     float64 `json:"lines_percent"`
+	FunctionsPercent float64 `json:"functions_percent"`
+	BranchesPercent  float64 `json:"branches_percent"`
+}
+
+// NewCoverageSummary returns the coverage percentages of a report
+func NewCoverageSummary(r coverage.Report) CoverageSummary {
+	return CoverageSummary{
+		TotalLines:       r.TotalLines,
+		CoveredLines:     r.CoveredLines,
+		LinesPercent:     CoveragePercent(r.CoveredLines, r.TotalLines),
+		FunctionsPercent: CoveragePercent(r.CoveredFunctions, r.TotalFunctions),
+		BranchesPercent:  CoveragePercent(r.CoveredBranches, r.TotalBranches),
+	}
+}
+
+// CoveragePercent returns the percentage of covered items, rounded to two decimals
+func CoveragePercent(covered, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return math.Round(float64(covered)/float64(total)*10000) / 100
+}
+
+// ComputePercents sets the coverage percentages of the report
+func (c *WorkflowNodeRunCoverage) ComputePercents() {
+	s := NewCoverageSummary(c.Report)
+	c.LinesPercent, c.FunctionsPercent, c.BranchesPercent = s.LinesPercent, s.FunctionsPercent, s.BranchesPercent
+}
+
+// WorkflowCoverageTrend is the coverage of an application for a run of a workflow
+type WorkflowCoverageTrend struct {
+	RunNumber         int64     `json:"run_number" cli:"run"`
+	WorkflowNodeRunID int64     `json:"workflow_node_run_id"`
+	ApplicationName   string    `json:"application_name" cli:"application"`
+	Branch            string    `json:"branch" cli:"branch"`
+	LinesPercent      float64   `json:"lines_percent" cli:"lines"`
+	FunctionsPercent  float64   `json:"functions_percent" cli:"functions"`
+	BranchesPercent   float64   `json:"branches_percent" cli:"branches"`
+	Created           time.Time `json:"created" cli:"created"`
+}
+
+// WorkflowNodeRunCoverageDiff compares the coverage of a node run with the previous run on the same branch
+// and the latest run on the default branch of the repository
+type WorkflowNodeRunCoverageDiff struct {
+	WorkflowNodeRunID int64            `json:"workflow_node_run_id"`
+	WorkflowNodeName  string           `json:"workflow_node_name" cli:"node"`
+	ApplicationID     int64            `json:"application_id"`
+	Branch            string           `json:"branch" cli:"branch"`
+	Current           CoverageSummary  `json:"current"`
+	Previous          *CoverageSummary `json:"previous,omitempty"`
+	DefaultBranch     *CoverageSummary `json:"default_branch,omitempty"`
+	// Differences of lines coverage, in percentage points
+	DiffPrevious      *float64 `json:"diff_previous,omitempty"`
+	DiffDefaultBranch *float64 `json:"diff_default_branch,omitempty"`
+}
+
+// NewWorkflowNodeRunCoverageDiff computes the coverage differences of a node run from its trend
+func NewWorkflowNodeRunCoverageDiff(c WorkflowNodeRunCoverage) WorkflowNodeRunCoverageDiff {
+	d := WorkflowNodeRunCoverageDiff{
+		WorkflowNodeRunID: c.WorkflowNodeRunID,
+		ApplicationID:     c.ApplicationID,
+		Branch:            c.Branch,
+		Current:           NewCoverageSummary(c.Report),
+	}
+	if c.Trend.CurrentBranch.TotalLines > 0 {
+		previous := NewCoverageSummary(c.Trend.CurrentBranch)
+		diff := math.Round((d.Current.LinesPercent-previous.LinesPercent)*100) / 100
+		d.Previous, d.DiffPrevious = &previous, &diff
+	}
+	if c.Trend.DefaultBranch.TotalLines > 0 {
+		defaultBranch := NewCoverageSummary(c.Trend.DefaultBranch)
+		diff := math.Round((d.Current.LinesPercent-defaultBranch.LinesPercent)*100) / 100
+		d.DefaultBranch, d.DiffDefaultBranch = &defaultBranch, &diff
+	}
+	return d
+}
@@ -0,0 +1,36 @@
+package sdk
+
+import (
+	"testing"
+
+	"github.com/sguiheux/go-coverage"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewWorkflowNodeRunCoverageDiff(t *testing.T) {
+	assert.Equal(t, 66.67, CoveragePercent(2, 3))
+	assert.Equal(t, 0.0, CoveragePercent(0, 0))
+
+	c := WorkflowNodeRunCoverage{
+		WorkflowNodeRunID: 3,
+		Branch:            "feat/login",
+		Report:            coverage.Report{TotalLines: 200, CoveredLines: 150, TotalFunctions: 10, CoveredFunctions: 5},
+		Trend: WorkflowNodeRunCoverageTrends{
+			CurrentBranch: coverage.Report{TotalLines: 200, CoveredLines: 140},
+		},
+	}
+	c.ComputePercents()
+	assert.Equal(t, 75.0, c.LinesPercent)
+	assert.Equal(t, 50.0, c.FunctionsPercent)
+
+	d := NewWorkflowNodeRunCoverageDiff(c)
+	assert.Equal(t, 75.0, d.Current.LinesPercent)
+	assert.Equal(t, 70.0, d.Previous.LinesPercent)
+	assert.Equal(t, 5.0, *d.DiffPrevious)
+	assert.Nil(t, d.DefaultBranch)
+	assert.Nil(t, d.DiffDefaultBranch)
+
+	c.Trend.DefaultBranch = coverage.Report{TotalLines: 300, CoveredLines: 240}
+	d = NewWorkflowNodeRunCoverageDiff(c)
+	assert.Equal(t, -5.0, *d.DiffDefaultBranch)
+}
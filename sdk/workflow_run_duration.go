@@ -0,0 +1,130 @@
+package sdk
+
+import (
+	"time"
+)
+
+// Kinds of the durations stored for a node run
+const (
+	DurationKindPipeline = "pipeline"
+	DurationKindStage    = "stage"
+	DurationKindStep     = "step"
+)
+
+// Periods used to aggregate the durations of a workflow
+const (
+	DurationIntervalDay   = "day"
+	DurationIntervalWeek  = "week"
+	DurationIntervalMonth = "month"
+)
+
+// DurationIntervals lists the periods used to aggregate the durations of a workflow
+var DurationIntervals = []string{DurationIntervalDay, DurationIntervalWeek, DurationIntervalMonth}
+
+// WorkflowRunDuration is the duration of a pipeline, a stage or a step in a node run. Durations are in seconds.
+type WorkflowRunDuration struct {
+	ID                int64     `json:"id" db:"id"`
+	WorkflowID        int64     `json:"workflow_id" db:"workflow_id"`
+	WorkflowRunID     int64     `json:"workflow_run_id" db:"workflow_run_id"`
+	WorkflowNodeRunID int64     `json:"workflow_node_run_id" db:"workflow_node_run_id"`
+	WorkflowNodeName  string    `json:"workflow_node_name" db:"workflow_node_name"`
+	PipelineName      string    `json:"pipeline_name" db:"pipeline_name"`
+	Number            int64     `json:"num" db:"num"`
+	Kind              string    `json:"kind" db:"kind"`
+	StageName         string    `json:"stage_name,omitempty" db:"stage_name"`
+	JobName           string    `json:"job_name,omitempty" db:"job_name"`
+	StepOrder         int       `json:"step_order" db:"step_order"`
+	StepName          string    `json:"step_name,omitempty" db:"step_name"`
+	Status            string    `json:"status" db:"status"`
+	Duration          float64   `json:"duration" db:"duration"`
+	Created           time.Time `json:"created" db:"created"`
+}
+
+// WorkflowDurationStats are the percentiles of the durations of a pipeline, or of one of its stages, over a period
+type WorkflowDurationStats struct {
+	Period       time.Time `json:"period" cli:"period"`
+	PipelineName string    `json:"pipeline_name" cli:"pipeline"`
+	StageName    string    `json:"stage_name,omitempty" cli:"stage"`
+	Runs         int64     `json:"runs" cli:"runs"`
+	P50          float64   `json:"p50" cli:"p50"`
+	P95          float64   `json:"p95" cli:"p95"`
+}
+
+// WorkflowSlowStep is a step of a workflow with its average and 95th percentile durations
+type WorkflowSlowStep struct {
+	PipelineName string  `json:"pipeline_name" cli:"pipeline"`
+	StageName    string  `json:"stage_name" cli:"stage"`
+	JobName      string  `json:"job_name" cli:"job"`
+	StepOrder    int     `json:"step_order" cli:"order"`
+	StepName     string  `json:"step_name" cli:"step"`
+	Runs         int64   `json:"runs" cli:"runs"`
+	Average      float64 `json:"average" cli:"average"`
+	P95          float64 `json:"p95" cli:"p95"`
+}
+
+// NewWorkflowRunDurations returns the durations of a terminated node run, of its stages and of the steps of its jobs.
+// Stages, jobs and steps which did not run are ignored.
+func NewWorkflowRunDurations(nodeRun WorkflowNodeRun, pipelineName string) []WorkflowRunDuration {
+	newDuration := func(kind, status string, start, done time.Time) WorkflowRunDuration {
+		return WorkflowRunDuration{
+			WorkflowID:        nodeRun.WorkflowID,
+			WorkflowRunID:     nodeRun.WorkflowRunID,
+			WorkflowNodeRunID: nodeRun.ID,
+			WorkflowNodeName:  nodeRun.WorkflowNodeName,
+			PipelineName:      pipelineName,
+			Number:            nodeRun.Number,
+			Kind:              kind,
+			Status:            status,
+			Duration:          done.Sub(start).Seconds(),
+		}
+	}
+
+	var res []WorkflowRunDuration
+	if hasRun(nodeRun.Start, nodeRun.Done) {
+		res = append(res, newDuration(DurationKindPipeline, nodeRun.Status, nodeRun.Start, nodeRun.Done))
+	}
+
+	for _, s := range nodeRun.Stages {
+		var stageStart, stageDone time.Time
+		for _, j := range s.RunJobs {
+			if !hasRun(j.Start, j.Done) {
+				continue
+			}
+			if stageStart.IsZero() || j.Start.Before(stageStart) {
+				stageStart = j.Start
+			}
+			if j.Done.After(stageDone) {
+				stageDone = j.Done
+			}
+		}
+		if stageStart.IsZero() {
+			continue
+		}
+		d := newDuration(DurationKindStage, s.Status.String(), stageStart, stageDone)
+		d.StageName = s.Name
+		res = append(res, d)
+
+		for _, j := range s.RunJobs {
+			for _, st := range j.Job.StepStatus {
+				if !hasRun(st.Start, st.Done) {
+					continue
+				}
+				d := newDuration(DurationKindStep, st.Status, st.Start, st.Done)
+				d.StageName, d.JobName, d.StepOrder = s.Name, j.Job.Action.Name, st.StepOrder
+				if st.StepOrder >= 0 && st.StepOrder < len(j.Job.Action.Actions) {
+					step := j.Job.Action.Actions[st.StepOrder]
+					d.StepName = step.StepName
+					if d.StepName == "" {
+						d.StepName = step.Name
+					}
+				}
+				res = append(res, d)
+			}
+		}
+	}
+	return res
+}
+
+func hasRun(start, done time.Time) bool {
+	return !start.IsZero() && !done.IsZero() && !done.Before(start)
+}
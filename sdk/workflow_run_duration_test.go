@@ -0,0 +1,63 @@
+package sdk
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewWorkflowRunDurations(t *testing.T) {
+	start := time.Date(2019, 1, 1, 10, 0, 0, 0, time.UTC)
+	nr := WorkflowNodeRun{
+		ID:               3,
+		WorkflowID:       1,
+		WorkflowRunID:    2,
+		WorkflowNodeName: "build",
+		Number:           12,
+		Status:           StatusSuccess.String(),
+		Start:            start,
+		Done:             start.Add(5 * time.Minute),
+		Stages: []Stage{
+			{
+				Name:   "Build",
+				Status: StatusSuccess,
+				RunJobs: []WorkflowNodeJobRun{
+					{
+						Start: start.Add(10 * time.Second),
+						Done:  start.Add(2 * time.Minute),
+						Job: ExecutedJob{
+							Job: Job{Action: Action{Name: "Compile", Actions: []Action{{Name: "Script", StepName: "go build"}, {Name: "Artifact Upload"}}}},
+							StepStatus: []StepStatus{
+								{StepOrder: 0, Status: StatusSuccess.String(), Start: start.Add(10 * time.Second), Done: start.Add(70 * time.Second)},
+								{StepOrder: 1, Status: StatusSuccess.String(), Start: start.Add(70 * time.Second), Done: start.Add(2 * time.Minute)},
+							},
+						},
+					},
+					{Start: start.Add(20 * time.Second), Done: start.Add(3 * time.Minute)},
+				},
+			},
+			{Name: "Deploy", Status: StatusSkipped},
+		},
+	}
+
+	durations := NewWorkflowRunDurations(nr, "build-pip")
+	if !assert.Len(t, durations, 4) {
+		return
+	}
+	assert.Equal(t, DurationKindPipeline, durations[0].Kind)
+	assert.Equal(t, 300.0, durations[0].Duration)
+	assert.Equal(t, "build-pip", durations[0].PipelineName)
+	assert.Equal(t, int64(3), durations[0].WorkflowNodeRunID)
+
+	assert.Equal(t, DurationKindStage, durations[1].Kind)
+	assert.Equal(t, "Build", durations[1].StageName)
+	assert.Equal(t, 170.0, durations[1].Duration)
+
+	assert.Equal(t, DurationKindStep, durations[2].Kind)
+	assert.Equal(t, "Compile", durations[2].JobName)
+	assert.Equal(t, "go build", durations[2].StepName)
+	assert.Equal(t, 60.0, durations[2].Duration)
+	assert.Equal(t, "Artifact Upload", durations[3].StepName)
+	assert.Equal(t, 1, durations[3].StepOrder)
+}
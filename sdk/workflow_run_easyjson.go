@@ -2690,6 +2690,16 @@ func easyjsonD7860c2dDecodeGithubComOvhCdsSdk13(in *jlexer.Lexer, out *StepStatu
 			if data := in.Raw(); in.Ok() {
 				in.AddError((out.Done).UnmarshalJSON(data))
 			}
+		case "usage":
+			if in.IsNull() {
+				in.Skip()
+				out.Usage = nil
+			} else {
+				if out.Usage == nil {
+					out.Usage = new(StepResourceUsage)
+				}
+				easyjsonD7860c2dDecodeGithubComOvhCdsSdk16(in, &*out.Usage)
+			}
 		default:
 			in.SkipRecursive()
 		}
@@ -2744,5 +2754,86 @@ func easyjsonD7860c2dEncodeGithubComOvhCdsSdk13(out *jwriter.Writer, in StepStat
 		}
 		out.Raw((in.Done).MarshalJSON())
 	}
+	if in.Usage != nil {
+		const prefix string = ",\"usage\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		easyjsonD7860c2dEncodeGithubComOvhCdsSdk16(out, *in.Usage)
+	}
+	out.RawByte('}')
+}
+func easyjsonD7860c2dDecodeGithubComOvhCdsSdk16(in *jlexer.Lexer, out *StepResourceUsage) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeString()
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "cpu_time":
+			out.CPUTime = float64(in.Float64())
+		case "max_memory":
+			out.MaxMemory = int64(in.Int64())
+		case "max_disk":
+			out.MaxDisk = int64(in.Int64())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonD7860c2dEncodeGithubComOvhCdsSdk16(out *jwriter.Writer, in StepResourceUsage) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"cpu_time\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Float64(float64(in.CPUTime))
+	}
+	{
+		const prefix string = ",\"max_memory\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Int64(int64(in.MaxMemory))
+	}
+	{
+		const prefix string = ",\"max_disk\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Int64(int64(in.MaxDisk))
+	}
 	out.RawByte('}')
 }
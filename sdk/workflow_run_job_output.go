@@ -0,0 +1,47 @@
+package sdk
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+)
+
+var (
+	workflowRunJobOutputKeyRegexp  = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+	workflowRunJobOutputNameRegexp = regexp.MustCompile(`[^a-zA-Z0-9_-]`)
+)
+
+// WorkflowRunJobOutput is a key-value result set by a job with the command `worker output set`.
+// Outputs are available in the next stages of the pipeline with {{.cds.outputs.<job>.<key>}}
+// and in the next pipelines of the workflow with {{.workflow.<pipeline>.outputs.<job>.<key>}}.
+type WorkflowRunJobOutput struct {
+	ID                int64     `json:"id" db:"id"`
+	WorkflowRunID     int64     `json:"workflow_run_id" db:"workflow_run_id"`
+	WorkflowNodeRunID int64     `json:"workflow_node_run_id" db:"workflow_node_run_id"`
+	WorkflowRunJobID  int64     `json:"workflow_run_job_id" db:"workflow_run_job_id"`
+	WorkflowNodeName  string    `json:"workflow_node_name" db:"workflow_node_name" cli:"node"`
+	SubNumber         int64     `json:"sub_num" db:"sub_num"`
+	JobName           string    `json:"job_name" db:"job_name" cli:"job"`
+	Key               string    `json:"key" db:"key" cli:"key"`
+	Value             string    `json:"value" db:"value" cli:"value"`
+	Created           time.Time `json:"created" db:"created"`
+}
+
+// IsValid checks the key of the output, dots are not allowed as they would be read as nested variables
+func (o WorkflowRunJobOutput) IsValid() error {
+	if !workflowRunJobOutputKeyRegexp.MatchString(o.Key) {
+		return NewErrorFrom(ErrWrongRequest, "invalid output key %q, only letters, digits, '_' and '-' are allowed", o.Key)
+	}
+	return nil
+}
+
+// ParameterName returns the name of the build parameter of the output
+func (o WorkflowRunJobOutput) ParameterName() string {
+	return WorkflowRunJobOutputParameterName(o.JobName, o.Key)
+}
+
+// WorkflowRunJobOutputParameterName returns the name of the build parameter of an output of a job,
+// the characters of the name of the job which cannot be used in a variable name are replaced by '_'
+func WorkflowRunJobOutputParameterName(jobName, key string) string {
+	return fmt.Sprintf("cds.outputs.%s.%s", workflowRunJobOutputNameRegexp.ReplaceAllString(jobName, "_"), key)
+}
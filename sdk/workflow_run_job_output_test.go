@@ -0,0 +1,18 @@
+package sdk
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorkflowRunJobOutput(t *testing.T) {
+	o := WorkflowRunJobOutput{JobName: "Build my app", Key: "version"}
+	assert.NoError(t, o.IsValid())
+	assert.Equal(t, "cds.outputs.Build_my_app.version", o.ParameterName())
+
+	for _, k := range []string{"", "my.version", "my version", "{{.version}}"} {
+		o.Key = k
+		assert.Error(t, o.IsValid(), "key %q should be invalid", k)
+	}
+}
@@ -0,0 +1,208 @@
+package sdk
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Types of the results of a workflow run
+const (
+	WorkflowRunResultTypeArtifact   = "artifact"
+	WorkflowRunResultTypeDocker     = "docker"
+	WorkflowRunResultTypeTests      = "tests"
+	WorkflowRunResultTypeCoverage   = "coverage"
+	WorkflowRunResultTypeDeployment = "deployment"
+)
+
+// WorkflowRunResultTypes lists the types of the results of a workflow run
+var WorkflowRunResultTypes = []string{
+	WorkflowRunResultTypeArtifact,
+	WorkflowRunResultTypeDocker,
+	WorkflowRunResultTypeTests,
+	WorkflowRunResultTypeCoverage,
+	WorkflowRunResultTypeDeployment,
+}
+
+// WorkflowRunResult is something produced by a job of a workflow run, with metadata depending on its type
+type WorkflowRunResult struct {
+	ID                int64           `json:"id" db:"id" cli:"id"`
+	WorkflowRunID     int64           `json:"workflow_run_id" db:"workflow_run_id"`
+	WorkflowNodeRunID int64           `json:"workflow_node_run_id" db:"workflow_node_run_id"`
+	WorkflowRunJobID  int64           `json:"workflow_run_job_id" db:"workflow_run_job_id"`
+	WorkflowNodeName  string          `json:"workflow_node_name" db:"workflow_node_name" cli:"node"`
+	SubNumber         int64           `json:"sub_num" db:"sub_num"`
+	Type              string          `json:"type" db:"type" cli:"type"`
+	Name              string          `json:"name" db:"name" cli:"name"`
+	Created           time.Time       `json:"created" db:"created" cli:"created"`
+	Data              json.RawMessage `json:"data" db:"-"`
+}
+
+// WorkflowRunResultArtifact is the metadata of an artifact uploaded by a job
+type WorkflowRunResultArtifact struct {
+	Name       string `json:"name"`
+	Tag        string `json:"tag,omitempty"`
+	ArtifactID int64  `json:"artifact_id,omitempty"`
+	Size       int64  `json:"size,omitempty"`
+	Perm       uint32 `json:"perm,omitempty"`
+	MD5sum     string `json:"md5sum,omitempty"`
+	SHA256sum  string `json:"sha256sum,omitempty"`
+	SHA512sum  string `json:"sha512sum,omitempty"`
+}
+
+// WorkflowRunResultDocker is the metadata of a docker image pushed by a job
+type WorkflowRunResultDocker struct {
+	Image  string `json:"image"`
+	Tag    string `json:"tag,omitempty"`
+	Digest string `json:"digest,omitempty"`
+}
+
+// WorkflowRunResultTests is the summary of the test reports of a job
+type WorkflowRunResultTests struct {
+	Name         string `json:"name"`
+	Total        int    `json:"total"`
+	TotalOK      int    `json:"ok"`
+	TotalKO      int    `json:"ko"`
+	TotalSkipped int    `json:"skipped"`
+}
+
+// WorkflowRunResultCoverage is the summary of the coverage report of a job
+type WorkflowRunResultCoverage struct {
+	Name             string `json:"name"`
+	TotalLines       int    `json:"total_lines"`
+	CoveredLines     int    `json:"covered_lines"`
+	TotalFunctions   int    `json:"total_functions,omitempty"`
+	CoveredFunctions int    `json:"covered_functions,omitempty"`
+	TotalBranches    int    `json:"total_branches,omitempty"`
+	CoveredBranches  int    `json:"covered_branches,omitempty"`
+}
+
+// WorkflowRunResultDeployment is a deployment performed by a job
+type WorkflowRunResultDeployment struct {
+	Environment string `json:"environment"`
+	Application string `json:"application,omitempty"`
+	Version     string `json:"version,omitempty"`
+	URL         string `json:"url,omitempty"`
+	Integration string `json:"integration,omitempty"`
+}
+
+// NewWorkflowRunResult returns a result of the given type with its metadata
+func NewWorkflowRunResult(resultType string, data interface{}) (WorkflowRunResult, error) {
+	r := WorkflowRunResult{Type: resultType}
+	btes, err := json.Marshal(data)
+	if err != nil {
+		return r, WithStack(err)
+	}
+	r.Data = btes
+	return r, r.IsValid()
+}
+
+// IsValid checks the type and the metadata of the result, and computes its name
+func (r *WorkflowRunResult) IsValid() error {
+	if len(r.Data) == 0 {
+		return NewErrorFrom(ErrWrongRequest, "missing data of the %s result", r.Type)
+	}
+
+	switch r.Type {
+	case WorkflowRunResultTypeArtifact:
+		var a WorkflowRunResultArtifact
+		if err := r.decode(&a); err != nil {
+			return err
+		}
+		if a.Name == "" {
+			return NewErrorFrom(ErrWrongRequest, "invalid artifact result: name is mandatory")
+		}
+		r.Name = a.Name
+	case WorkflowRunResultTypeDocker:
+		var d WorkflowRunResultDocker
+		if err := r.decode(&d); err != nil {
+			return err
+		}
+		if d.Image == "" {
+			return NewErrorFrom(ErrWrongRequest, "invalid docker result: image is mandatory")
+		}
+		r.Name = d.Image
+		if d.Tag != "" {
+			r.Name += ":" + d.Tag
+		}
+	case WorkflowRunResultTypeTests:
+		var t WorkflowRunResultTests
+		if err := r.decode(&t); err != nil {
+			return err
+		}
+		r.Name = t.Name
+	case WorkflowRunResultTypeCoverage:
+		var c WorkflowRunResultCoverage
+		if err := r.decode(&c); err != nil {
+			return err
+		}
+		r.Name = c.Name
+	case WorkflowRunResultTypeDeployment:
+		var d WorkflowRunResultDeployment
+		if err := r.decode(&d); err != nil {
+			return err
+		}
+		if d.Environment == "" {
+			return NewErrorFrom(ErrWrongRequest, "invalid deployment result: environment is mandatory")
+		}
+		r.Name = d.Environment
+		if d.Application != "" {
+			r.Name = d.Application + "/" + d.Environment
+		}
+	default:
+		return NewErrorFrom(ErrWrongRequest, "invalid result type %s", r.Type)
+	}
+	return nil
+}
+
+func (r WorkflowRunResult) decode(v interface{}) error {
+	if err := json.Unmarshal(r.Data, v); err != nil {
+		return NewErrorFrom(ErrWrongRequest, "invalid data of the %s result: %v", r.Type, err)
+	}
+	return nil
+}
+
+// GetArtifact returns the metadata of an artifact result
+func (r WorkflowRunResult) GetArtifact() (WorkflowRunResultArtifact, error) {
+	var a WorkflowRunResultArtifact
+	if r.Type != WorkflowRunResultTypeArtifact {
+		return a, fmt.Errorf("result %d is not an artifact", r.ID)
+	}
+	return a, r.decode(&a)
+}
+
+// GetDocker returns the metadata of a docker result
+func (r WorkflowRunResult) GetDocker() (WorkflowRunResultDocker, error) {
+	var d WorkflowRunResultDocker
+	if r.Type != WorkflowRunResultTypeDocker {
+		return d, fmt.Errorf("result %d is not a docker image", r.ID)
+	}
+	return d, r.decode(&d)
+}
+
+// GetTests returns the metadata of a tests result
+func (r WorkflowRunResult) GetTests() (WorkflowRunResultTests, error) {
+	var t WorkflowRunResultTests
+	if r.Type != WorkflowRunResultTypeTests {
+		return t, fmt.Errorf("result %d is not a tests report", r.ID)
+	}
+	return t, r.decode(&t)
+}
+
+// GetCoverage returns the metadata of a coverage result
+func (r WorkflowRunResult) GetCoverage() (WorkflowRunResultCoverage, error) {
+	var c WorkflowRunResultCoverage
+	if r.Type != WorkflowRunResultTypeCoverage {
+		return c, fmt.Errorf("result %d is not a coverage report", r.ID)
+	}
+	return c, r.decode(&c)
+}
+
+// GetDeployment returns the metadata of a deployment result
+func (r WorkflowRunResult) GetDeployment() (WorkflowRunResultDeployment, error) {
+	var d WorkflowRunResultDeployment
+	if r.Type != WorkflowRunResultTypeDeployment {
+		return d, fmt.Errorf("result %d is not a deployment", r.ID)
+	}
+	return d, r.decode(&d)
+}
@@ -0,0 +1,35 @@
+package sdk
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorkflowRunResultIsValid(t *testing.T) {
+	r, err := NewWorkflowRunResult(WorkflowRunResultTypeDocker, WorkflowRunResultDocker{Image: "registry.example.com/my-app", Tag: "1.2.0", Digest: "sha256:abc"})
+	assert.NoError(t, err)
+	assert.Equal(t, "registry.example.com/my-app:1.2.0", r.Name)
+	d, err := r.GetDocker()
+	assert.NoError(t, err)
+	assert.Equal(t, "sha256:abc", d.Digest)
+	_, err = r.GetArtifact()
+	assert.Error(t, err)
+
+	r, err = NewWorkflowRunResult(WorkflowRunResultTypeDeployment, WorkflowRunResultDeployment{Environment: "production", Application: "my-app", Version: "1.2.0"})
+	assert.NoError(t, err)
+	assert.Equal(t, "my-app/production", r.Name)
+
+	_, err = NewWorkflowRunResult(WorkflowRunResultTypeDeployment, WorkflowRunResultDeployment{Application: "my-app"})
+	assert.Error(t, err)
+
+	_, err = NewWorkflowRunResult(WorkflowRunResultTypeArtifact, WorkflowRunResultArtifact{})
+	assert.Error(t, err)
+
+	_, err = NewWorkflowRunResult("helm", map[string]string{"chart": "my-app"})
+	assert.Error(t, err)
+
+	r = WorkflowRunResult{Type: WorkflowRunResultTypeTests, Data: json.RawMessage(`{"name": 42}`)}
+	assert.Error(t, r.IsValid())
+}
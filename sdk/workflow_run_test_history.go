@@ -0,0 +1,107 @@
+package sdk
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/ovh/venom"
+)
+
+// Status of a test case
+const (
+	TestCaseStatusSuccess = "success"
+	TestCaseStatusFailure = "failure"
+	TestCaseStatusSkipped = "skipped"
+)
+
+// FlakinessWindow is the number of the last executions of a test case used to compute its flakiness
+const FlakinessWindow = 20
+
+// WorkflowRunTestCase is the result of a test case in a node run
+type WorkflowRunTestCase struct {
+	ID                int64     `json:"id" db:"id"`
+	WorkflowID        int64     `json:"workflow_id" db:"workflow_id"`
+	WorkflowRunID     int64     `json:"workflow_run_id" db:"workflow_run_id"`
+	WorkflowNodeRunID int64     `json:"workflow_node_run_id" db:"workflow_node_run_id"`
+	WorkflowNodeName  string    `json:"workflow_node_name" db:"workflow_node_name" cli:"node"`
+	Number            int64     `json:"num" db:"num" cli:"num"`
+	SubNumber         int64     `json:"sub_num" db:"sub_num"`
+	VCSBranch         string    `json:"vcs_branch,omitempty" db:"vcs_branch" cli:"branch"`
+	TestSuite         string    `json:"test_suite" db:"test_suite" cli:"suite"`
+	Classname         string    `json:"classname,omitempty" db:"classname"`
+	Name              string    `json:"name" db:"name" cli:"name"`
+	Status            string    `json:"status" db:"status" cli:"status"`
+	Duration          float64   `json:"duration" db:"duration" cli:"duration"`
+	Created           time.Time `json:"created" db:"created" cli:"created"`
+}
+
+// WorkflowTestCaseFlakiness is the flakiness score of a test case, computed on its last executions
+type WorkflowTestCaseFlakiness struct {
+	WorkflowNodeName string  `json:"workflow_node_name" cli:"node"`
+	TestSuite        string  `json:"test_suite" cli:"suite"`
+	Name             string  `json:"name" cli:"name"`
+	Executions       int     `json:"executions" cli:"executions"`
+	Failures         int     `json:"failures" cli:"failures"`
+	Score            float64 `json:"score" cli:"score"`
+}
+
+// TestCaseStatus returns the status of a venom test case
+func TestCaseStatus(tc venom.TestCase) string {
+	switch {
+	case len(tc.Errors) > 0 || len(tc.Failures) > 0:
+		return TestCaseStatusFailure
+	case len(tc.Skipped) > 0:
+		return TestCaseStatusSkipped
+	}
+	return TestCaseStatusSuccess
+}
+
+// NewWorkflowRunTestCases returns the test cases of a tests report of a node run
+func NewWorkflowRunTestCases(nodeRun WorkflowNodeRun, tests venom.Tests) []WorkflowRunTestCase {
+	var res []WorkflowRunTestCase
+	for _, ts := range tests.TestSuites {
+		for _, tc := range ts.TestCases {
+			duration, _ := strconv.ParseFloat(tc.Time, 64)
+			res = append(res, WorkflowRunTestCase{
+				WorkflowID:        nodeRun.WorkflowID,
+				WorkflowRunID:     nodeRun.WorkflowRunID,
+				WorkflowNodeRunID: nodeRun.ID,
+				WorkflowNodeName:  nodeRun.WorkflowNodeName,
+				Number:            nodeRun.Number,
+				SubNumber:         nodeRun.SubNumber,
+				VCSBranch:         nodeRun.VCSBranch,
+				TestSuite:         ts.Name,
+				Classname:         tc.Classname,
+				Name:              tc.Name,
+				Status:            TestCaseStatus(tc),
+				Duration:          duration,
+			})
+		}
+	}
+	return res
+}
+
+// ComputeFlakiness returns the flakiness of a test case from the statuses of its executions, ordered from the most recent.
+// The score is the rate of status changes between two consecutive executions: 0 for a stable test,
+// 1 for a test that alternates between success and failure at each execution. Skipped executions are ignored.
+func ComputeFlakiness(statuses []string) (executions, failures int, score float64) {
+	var last string
+	var flips int
+	for _, s := range statuses {
+		if s == TestCaseStatusSkipped {
+			continue
+		}
+		executions++
+		if s == TestCaseStatusFailure {
+			failures++
+		}
+		if last != "" && s != last {
+			flips++
+		}
+		last = s
+	}
+	if executions < 2 {
+		return executions, failures, 0
+	}
+	return executions, failures, float64(flips) / float64(executions-1)
+}
@@ -0,0 +1,48 @@
+package sdk
+
+import (
+	"testing"
+
+	"github.com/ovh/venom"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestComputeFlakiness(t *testing.T) {
+	executions, failures, score := ComputeFlakiness([]string{TestCaseStatusSuccess, TestCaseStatusSuccess, TestCaseStatusSuccess})
+	assert.Equal(t, 3, executions)
+	assert.Equal(t, 0, failures)
+	assert.Equal(t, 0.0, score)
+
+	executions, failures, score = ComputeFlakiness([]string{TestCaseStatusFailure, TestCaseStatusSkipped, TestCaseStatusSuccess, TestCaseStatusFailure, TestCaseStatusSuccess})
+	assert.Equal(t, 4, executions)
+	assert.Equal(t, 2, failures)
+	assert.Equal(t, 1.0, score)
+
+	_, _, score = ComputeFlakiness([]string{TestCaseStatusFailure, TestCaseStatusFailure, TestCaseStatusSuccess, TestCaseStatusSuccess, TestCaseStatusSuccess})
+	assert.Equal(t, 0.25, score)
+
+	_, _, score = ComputeFlakiness([]string{TestCaseStatusFailure})
+	assert.Equal(t, 0.0, score)
+}
+
+func TestNewWorkflowRunTestCases(t *testing.T) {
+	nodeRun := WorkflowNodeRun{ID: 3, WorkflowID: 1, WorkflowRunID: 2, WorkflowNodeName: "build", Number: 12, VCSBranch: "master"}
+	tests := venom.Tests{TestSuites: []venom.TestSuite{{
+		Name: "api",
+		TestCases: []venom.TestCase{
+			{Name: "TestA", Time: "0.5"},
+			{Name: "TestB", Failures: []venom.Failure{{Value: "expected 1"}}},
+			{Name: "TestC", Skipped: []venom.Skipped{{Value: "not on CI"}}},
+		},
+	}}}
+
+	cases := NewWorkflowRunTestCases(nodeRun, tests)
+	assert.Len(t, cases, 3)
+	assert.Equal(t, TestCaseStatusSuccess, cases[0].Status)
+	assert.Equal(t, 0.5, cases[0].Duration)
+	assert.Equal(t, TestCaseStatusFailure, cases[1].Status)
+	assert.Equal(t, TestCaseStatusSkipped, cases[2].Status)
+	assert.Equal(t, "api", cases[2].TestSuite)
+	assert.Equal(t, "master", cases[2].VCSBranch)
+	assert.Equal(t, int64(3), cases[2].WorkflowNodeRunID)
+}
@@ -0,0 +1,23 @@
+package sdk
+
+import "time"
+
+// WorkflowRunTmpFile is a temporary file pushed by a job with the command `worker tmpfile push` to be pulled
+// by the next jobs of the same workflow run. Its content is stored by the CDN service and deleted with the run.
+type WorkflowRunTmpFile struct {
+	ID               int64     `json:"id" db:"id"`
+	WorkflowRunID    int64     `json:"workflow_run_id" db:"workflow_run_id"`
+	WorkflowRunJobID int64     `json:"workflow_run_job_id" db:"workflow_run_job_id"`
+	Name             string    `json:"name" db:"name" cli:"name"`
+	Hash             string    `json:"hash" db:"hash" cli:"hash"`
+	Size             int64     `json:"size" db:"size" cli:"size"`
+	Created          time.Time `json:"created" db:"created" cli:"created"`
+}
+
+// IsValid checks the name of the temporary file
+func (f WorkflowRunTmpFile) IsValid() error {
+	if !NamePatternRegex.MatchString(f.Name) {
+		return NewErrorFrom(ErrInvalidName, "invalid temporary file name %q", f.Name)
+	}
+	return nil
+}
@@ -149,3 +149,19 @@ func TestWorkflowNode_AncestorsAfterJoin(t *testing.T) {
 	assert.Equal(t, 1, len(ids))
 	assert.Equal(t, int64(4), ids[0])
 }
+
+func TestWorkflow_MaskHooksSecrets(t *testing.T) {
+	w := Workflow{
+		WorkflowData: &WorkflowData{Node: Node{Hooks: []NodeHook{
+			{Ref: "AAA", Config: WorkflowNodeHookConfig{
+				RepositoryWebHookSecret: {Value: "encrypted", Type: HookConfigTypeSecret},
+				"repoFullName":          {Value: "ovh/cds"},
+			}},
+		}}},
+	}
+	w.MaskHooksSecrets()
+
+	cfg := w.WorkflowData.Node.Hooks[0].Config
+	assert.Equal(t, PasswordPlaceholder, cfg[RepositoryWebHookSecret].Value)
+	assert.Equal(t, "ovh/cds", cfg["repoFullName"].Value)
+}